@@ -27,6 +27,7 @@ require (
 	github.com/joyt/godate v0.0.0-20150226210126-7151572574a7 // indirect
 	github.com/json-iterator/go v1.1.12
 	github.com/juju/ratelimit v1.0.1
+	github.com/klauspost/compress v1.15.0
 	github.com/mitchellh/mapstructure v1.5.0
 	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826
 	github.com/mssola/user_agent v0.5.3
@@ -56,6 +57,7 @@ require (
 	go.opentelemetry.io/otel v1.7.0
 	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.7.0
 	go.opentelemetry.io/otel/sdk v1.7.0
+	go.starlark.net v0.0.0-20220328144851-d1966c6b9fcd
 	golang.org/x/text v0.3.7
 	gopkg.in/mgo.v2 v2.0.0-20190816093944-a6b53ec6cb22
 	gopkg.in/yaml.v2 v2.4.0
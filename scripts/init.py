@@ -454,6 +454,34 @@ eventServer:
       fileOwner: "root"
       # 下发主机身份文件权限值
       filePrivilege: 644
+  # 审计日志流式推送相关配置，将审计日志准实时地推送到外部的安全审计系统(SIEM)
+  auditStream:
+    # 是否开启审计日志推送功能，true为开启，false为关闭
+    startUp: false
+    # 推送的目标类型，可选值为kafka和http，kafka时推送到kafka.auditStream配置的topic中，http时推送到http.url配置的https收集端
+    sink: http
+    # https收集端相关配置，审计日志以json数组的形式通过POST请求推送
+    http:
+      # 收集端的地址
+      url:
+      # 收集端的鉴权token，会以Authorization: Bearer <token>的形式放在请求头中
+      token:
+      # 请求的超时时间，单位为秒
+      timeoutSeconds: 10
+    # 每批推送的审计日志的最大数量，范围为[1, 1000]
+    batchSize: 200
+    # 没有新的审计日志时，下一次查询的等待时间，单位为秒
+    intervalSeconds: 3
+    # 只推送此时间之前产生的审计日志，避免事务提交较晚的审计日志被跳过，单位为秒
+    delaySeconds: 5
+    # 每批审计日志推送失败后的最大重试次数，重试都失败时会在下一次查询时继续推送，不会丢失
+    maxRetry: 5
+    # 推送的审计日志的过滤条件，为空时推送所有的审计日志
+    filter:
+      # 需要推送的审计日志的操作类型，如create、update、delete
+      actions:
+      # 需要推送的审计日志的资源类型，如host、business
+      resourceTypes:
 
 # 直接调用gse服务相关配置
 gse:
@@ -503,6 +531,55 @@ tls:
   caFile:
   # 用于解密根据RFC1423加密的证书密钥的PEM块
   password:
+
+# cmdb服务间及调用第三方(如iam)请求的熔断和隔离配置, 避免单个下游服务变慢时耗尽调用方的协程, 导致故障在服务间扩散
+apiMachinery:
+  guard:
+    # 是否开启熔断和隔离, bool值, 默认为false
+    enabled: true
+    circuitBreaker:
+      # 对下游服务的某个实例连续失败多少次后熔断该实例, 网络错误和502、503、504状态码计为失败, 默认为10
+      failureThreshold: 10
+      # 熔断持续时间, 单位为秒, 超过该时间后放行一个探测请求, 成功则恢复, 默认为10
+      openSeconds: 10
+    bulkhead:
+      # 对每个下游服务的最大并发请求数, 不配置或为0时不限制
+      maxConcurrency: 1000
+      # 并发请求数达到上限时等待的最长时间, 单位为毫秒, 超时后请求失败, 默认为3000
+      acquireTimeoutMilliseconds: 3000
+
+# 文件存储配置, 用于保存导出任务等生成的文件, 使其可以被所有web_server实例共享, 不配置时保存在web_server本地的/tmp/blob目录
+# 生成的文件不会被自动清理, 使用s3或bkrepo时请为export/目录配置生命周期规则, 保留时间与taskServer.fileRetentionHours一致
+#blobStore:
+#  # 存储类型, 可选值为local(本地或挂载的共享目录)、s3(兼容s3协议的对象存储, 如minio、cos)和bkrepo(蓝鲸制品库), 默认为local
+#  type: local
+#  local:
+#    # 文件存储的根目录
+#    path: /data/cmdb/blob
+#  s3:
+#    # 对象存储的地址, 如http://minio.example.com:9000, 不配置时使用aws s3对应region的地址
+#    endpoint:
+#    region:
+#    bucket:
+#    accessKey:
+#    secretKey:
+#    # 是否使用path style的url访问bucket, minio需要设置为true
+#    pathStyle: false
+#  bkrepo:
+#    # 蓝鲸制品库网关地址, 如http://bkrepo.example.com, 文件保存在该项目的generic类型仓库中
+#    endpoint:
+#    project:
+#    repo:
+#    username:
+#    password:
+#    # 请求超时时间, 单位为秒, 默认为300
+#    timeoutSeconds:
+
+# 故障注入配置, 用于在测试环境中对mongodb、redis和服务间调用注入延迟和错误以进行容灾演练, 请勿在正式环境开启
+# 开启后可以通过admin_server的/update/system/fault_inject_rules接口设置注入规则
+#faultInject:
+#  # 是否开启故障注入, bool值, 默认为false
+#  enabled: false
     '''
 
     template = FileTemplate(common_file_template_str)
@@ -0,0 +1,44 @@
+#!/bin/bash
+# generate the python and java client bindings of the watch and core read apis from the openapi spec,
+# and ship them with the cursor helpers and the examples to ${OUTPUT_DIR}/sdk.
+# the openapi-generator-cli in the PATH is used if exists, otherwise the docker image is used.
+set -e
+
+GENERATOR_VERSION=${GENERATOR_VERSION:-v7.4.0}
+PROJECT_PATH=$(cd $(dirname $0)/..; pwd)
+SPEC=${PROJECT_PATH}/docs/apidoc/openapi/cmdb.yaml
+SDK_SOURCE=${PROJECT_PATH}/src/sdk
+OUTPUT_DIR=${1:-${OUTPUT_DIR:-${PROJECT_PATH}/src/bin/build/sdk}}
+SDK_DIR=${OUTPUT_DIR}/sdk
+SDK_VERSION=$(sed -n 's/^  version: *//p' ${SPEC})
+
+generate() {
+    local lang=$1 out=$2 props=$3
+    if command -v openapi-generator-cli > /dev/null 2>&1; then
+        openapi-generator-cli generate -i ${SPEC} -g ${lang} -o ${out} --additional-properties=${props}
+    else
+        docker run --rm -u $(id -u):$(id -g) -v ${PROJECT_PATH}:/project -v ${SDK_DIR}:/sdk \
+            openapitools/openapi-generator-cli:${GENERATOR_VERSION} generate \
+            -i /project/docs/apidoc/openapi/cmdb.yaml -g ${lang} -o ${out/#${SDK_DIR}//sdk} \
+            --additional-properties=${props}
+    fi
+}
+
+rm -rf ${SDK_DIR} && mkdir -p ${SDK_DIR}
+
+echo "generating python sdk..."
+generate python ${SDK_DIR}/python \
+    "packageName=bkcmdb_client,projectName=bkcmdb-client,packageVersion=${SDK_VERSION}"
+cp -R ${SDK_SOURCE}/python/bkcmdb_watch ${SDK_DIR}/python/
+cp -R ${SDK_SOURCE}/python/examples ${SDK_DIR}/python/
+
+echo "generating java sdk..."
+generate java ${SDK_DIR}/java \
+    "library=native,groupId=com.tencent.bk,artifactId=bkcmdb-client,artifactVersion=${SDK_VERSION},\
+invokerPackage=com.tencent.bk.cmdb.client,apiPackage=com.tencent.bk.cmdb.client.api,\
+modelPackage=com.tencent.bk.cmdb.client.model"
+cp -R ${SDK_SOURCE}/java/src ${SDK_DIR}/java/
+cp -R ${SDK_SOURCE}/java/examples ${SDK_DIR}/java/
+
+cp ${SPEC} ${SDK_SOURCE}/README.md ${SDK_DIR}/
+echo "sdk is generated to ${SDK_DIR}"
@@ -0,0 +1,38 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extensions
+
+import (
+	"context"
+	"net/http"
+
+	"configcenter/src/ac/meta"
+	"configcenter/src/common/util"
+)
+
+// AuthorizeConfigAdmin authorize if the user is the platform admin, which is used to gate the diagnostic abilities
+// of the normal apis, e.g. the explain mode of the searches.
+func (am *AuthManager) AuthorizeConfigAdmin(ctx context.Context, header http.Header) error {
+	if !am.Enabled() {
+		return nil
+	}
+
+	resource := meta.ResourceAttribute{
+		Basic: meta.Basic{
+			Type:   meta.ConfigAdmin,
+			Action: meta.Update,
+		},
+		SupplierAccount: util.GetOwnerID(header),
+	}
+	return am.batchAuthorize(ctx, header, resource)
+}
@@ -95,6 +95,34 @@ func (am *AuthManager) batchAuthorize(ctx context.Context, header http.Header, r
 	return nil
 }
 
+// listUnauthorizedResourceIDs authorize the resources in batch, and returns the instance ids of the resources that
+// the user is not authorized to.
+func (am *AuthManager) listUnauthorizedResourceIDs(ctx context.Context, header http.Header,
+	resources ...meta.ResourceAttribute) ([]int64, error) {
+
+	commonInfo, err := parser.ParseCommonInfo(&header)
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed, parse user info from header failed, err: %+v", err)
+	}
+	decisions, err := am.Authorizer.AuthorizeBatch(ctx, header, commonInfo.User, resources...)
+	if err != nil {
+		return nil, fmt.Errorf("authorize failed, err: %+v", err)
+	}
+
+	if len(decisions) != len(resources) {
+		return nil, fmt.Errorf("authorize failed, got %d decisions for %d resources", len(decisions), len(resources))
+	}
+
+	unauthorized := make([]int64, 0)
+	for index, decision := range decisions {
+		if !decision.Authorized {
+			unauthorized = append(unauthorized, resources[index].InstanceID)
+		}
+	}
+
+	return unauthorized, nil
+}
+
 // Enabled TODO
 func (am *AuthManager) Enabled() bool {
 	return auth.EnableAuthorize()
@@ -300,6 +300,38 @@ func (am *AuthManager) AuthorizeByHostsIDs(ctx context.Context, header http.Head
 	return am.AuthorizeByHosts(ctx, header, action, hosts...)
 }
 
+// ListUnauthorizedHostIDs returns the ids of the hosts that the user is not authorized to do the action on, the ids
+// of the hosts that do not exist are ignored.
+func (am *AuthManager) ListUnauthorizedHostIDs(ctx context.Context, header http.Header, action meta.Action,
+	hostIDs ...int64) ([]int64, error) {
+
+	rid := util.ExtractRequestIDFromContext(ctx)
+
+	if !am.Enabled() || len(hostIDs) == 0 {
+		return make([]int64, 0), nil
+	}
+
+	if am.SkipReadAuthorization && (action == meta.Find || action == meta.FindMany) {
+		blog.V(4).Infof("skip authorization for reading, hosts: %+v, rid: %s", hostIDs, rid)
+		return make([]int64, 0), nil
+	}
+
+	hosts, err := am.collectHostByHostIDs(ctx, header, hostIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("get hosts by id failed, err: %+v, rid: %s", err, rid)
+	}
+	if len(hosts) == 0 {
+		return make([]int64, 0), nil
+	}
+
+	resources, err := am.MakeResourcesByHosts(ctx, header, action, hosts...)
+	if err != nil {
+		return nil, fmt.Errorf("make host resources failed, err: %+v", err)
+	}
+
+	return am.listUnauthorizedResourceIDs(ctx, header, resources...)
+}
+
 // AuthorizeCreateHost TODO
 func (am *AuthManager) AuthorizeCreateHost(ctx context.Context, header http.Header, bizID int64) error {
 	if !am.Enabled() {
@@ -268,6 +268,39 @@ func (am *AuthManager) AuthorizeByInstanceID(ctx context.Context, header http.He
 	return am.AuthorizeByInstances(ctx, header, action, instances...)
 }
 
+// ListUnauthorizedInstanceIDs returns the ids of the common model instances that the user is not authorized to do
+// the action on, the ids of the instances that do not exist are ignored.
+func (am *AuthManager) ListUnauthorizedInstanceIDs(ctx context.Context, header http.Header, action meta.Action,
+	objID string, ids ...int64) ([]int64, error) {
+
+	rid := util.ExtractRequestIDFromContext(ctx)
+
+	if !am.Enabled() || len(ids) == 0 {
+		return make([]int64, 0), nil
+	}
+
+	if am.SkipReadAuthorization && (action == meta.Find || action == meta.FindMany) {
+		blog.V(4).Infof("skip authorization for reading, model: %s, ids: %+v, rid: %s", objID, ids, rid)
+		return make([]int64, 0), nil
+	}
+
+	instances, err := am.collectInstancesByRawIDs(ctx, header, objID, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("collect instance of model: %s by id %+v failed, err: %+v", objID, ids, err)
+	}
+	if len(instances) == 0 {
+		return make([]int64, 0), nil
+	}
+
+	resources, err := am.MakeResourcesByInstances(ctx, header, action, instances...)
+	if err != nil {
+		blog.Errorf("make resource by instances failed, err: %+v, rid: %s", err, rid)
+		return nil, fmt.Errorf("make resource by instances failed, err: %+v", err)
+	}
+
+	return am.listUnauthorizedResourceIDs(ctx, header, resources...)
+}
+
 // AuthorizeByInstances TODO
 func (am *AuthManager) AuthorizeByInstances(ctx context.Context, header http.Header, action meta.Action, instances ...InstanceSimplify) error {
 	rid := util.ExtractRequestIDFromContext(ctx)
@@ -68,6 +68,8 @@ func NewIAM(cfg AuthConfig, reg prometheus.Registerer) (*IAM, error) {
 			Mocked: false,
 		},
 		MetricOpts: util.MetricOption{Register: reg},
+		// protect the caller from the slow iam, which shares the guard config with the cmdb services
+		Guard: flowctrl.NewGuardSet(util.NewGuardConfigFromConfig("apiMachinery.guard"), reg).Get("iam"),
 	}
 
 	header := http.Header{}
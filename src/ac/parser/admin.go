@@ -48,6 +48,34 @@ var ConfigAdminConfigs = []AuthConfig{
 		HTTPMethod:     http.MethodPut,
 		ResourceType:   meta.ConfigAdmin,
 		ResourceAction: meta.Update,
+	}, {
+		Name:           "findReadOnlyMode",
+		Description:    "查询只读维护模式",
+		Pattern:        "/api/v3/admin/find/system/readonly_mode",
+		HTTPMethod:     http.MethodGet,
+		ResourceType:   meta.ConfigAdmin,
+		ResourceAction: meta.Find,
+	}, {
+		Name:           "updateReadOnlyMode",
+		Description:    "设置只读维护模式",
+		Pattern:        "/api/v3/admin/update/system/readonly_mode",
+		HTTPMethod:     http.MethodPut,
+		ResourceType:   meta.ConfigAdmin,
+		ResourceAction: meta.Update,
+	}, {
+		Name:           "findFaultInjectRules",
+		Description:    "查询故障注入规则",
+		Pattern:        "/api/v3/admin/find/system/fault_inject_rules",
+		HTTPMethod:     http.MethodGet,
+		ResourceType:   meta.ConfigAdmin,
+		ResourceAction: meta.Find,
+	}, {
+		Name:           "updateFaultInjectRules",
+		Description:    "设置故障注入规则",
+		Pattern:        "/api/v3/admin/update/system/fault_inject_rules",
+		HTTPMethod:     http.MethodPut,
+		ResourceType:   meta.ConfigAdmin,
+		ResourceAction: meta.Update,
 	},
 }
 
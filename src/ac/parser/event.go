@@ -37,7 +37,10 @@ func (ps *parseStream) eventRelated() *parseStream {
 }
 
 var (
-	watchResourceRegexp = regexp.MustCompile(`^/api/v3/event/watch/resource/\S+/?$`)
+	watchResourceRegexp    = regexp.MustCompile(`^/api/v3/event/watch/resource/\S+/?$`)
+	listChangesRegexp      = regexp.MustCompile(`^/api/v3/event/watch/changes/resource/[^\s/]+/?$`)
+	watchNamedCursorRegexp = regexp.MustCompile(
+		`^/api/v3/event/watch/named_cursor/[^\s/]+/resource/[^\s/]+/name/[^\s/]+/?$`)
 )
 
 func (ps *parseStream) watch() *parseStream {
@@ -47,49 +50,45 @@ func (ps *parseStream) watch() *parseStream {
 
 	// watch resource.
 	if ps.hitRegexp(watchResourceRegexp, http.MethodPost) {
-		resource := ps.RequestCtx.Elements[5]
-		if len(resource) == 0 {
-			ps.err = fmt.Errorf("watch event resource, but got empty resource: %s", ps.RequestCtx.Elements[5])
+		authResource, err := ps.watchResourceAttribute(ps.RequestCtx.Elements[5], watchSubResourcePath)
+		if err != nil {
+			ps.err = err
 			return ps
 		}
 
-		if resource == string(watch.HostIdentifier) {
-			// redirect host identity resource to host resource in iam.
-			resource = string(watch.Host)
-		}
-
-		if resource == string(watch.BizSetRelation) {
-			// redirect biz set relation resource to biz set resource in iam.
-			resource = string(watch.BizSet)
-		}
+		ps.Attribute.Resources = append(ps.Attribute.Resources, authResource)
+		return ps
+	}
 
-		authResource := meta.ResourceAttribute{
-			Basic: meta.Basic{
-				Type:   meta.EventWatch,
-				Action: meta.Action(resource),
-			},
+	// list the changes of the resource, which is authorized as watching the resource, the sub resource is set in
+	// the body directly instead of the filter.
+	if ps.hitRegexp(listChangesRegexp, http.MethodPost) {
+		authResource, err := ps.watchResourceAttribute(ps.RequestCtx.Elements[6], common.BKSubResourceField)
+		if err != nil {
+			ps.err = err
+			return ps
 		}
 
-		if resource == string(watch.ObjectBase) || resource == string(watch.MainlineInstance) ||
-			resource == string(watch.InstAsst) {
+		ps.Attribute.Resources = append(ps.Attribute.Resources, authResource)
+		return ps
+	}
 
-			body, err := ps.RequestCtx.getRequestBody()
-			if err != nil {
-				ps.err = err
-				return ps
-			}
+	// operate the named cursor of the resource, which is authorized as watching the resource, only the events
+	// watching operation uses the sub resource filter in the body.
+	if ps.hitRegexp(watchNamedCursorRegexp, ps.RequestCtx.Method) {
+		if len(ps.RequestCtx.Elements) != 10 {
+			ps.err = fmt.Errorf("operate named cursor, but got invalid url: %s", ps.RequestCtx.URI)
+			return ps
+		}
 
-			// use sub resource(corresponding to the bk_obj_id of the object) for authorization if it is set
-			// if sub resource is not set, verify authorization of the resource(which means all sub resources)
-			subResource := gjson.GetBytes(body, "bk_filter."+common.BKSubResourceField)
-			if subResource.Exists() {
-				model, err := ps.getOneModel(mapstr.MapStr{common.BKObjIDField: subResource.String()})
-				if err != nil {
-					ps.err = err
-					return ps
-				}
-				authResource.InstanceID = model.ID
-			}
+		subResourcePath := ""
+		if ps.RequestCtx.Elements[5] == "events" {
+			subResourcePath = watchSubResourcePath
+		}
+		authResource, err := ps.watchResourceAttribute(ps.RequestCtx.Elements[7], subResourcePath)
+		if err != nil {
+			ps.err = err
+			return ps
 		}
 
 		ps.Attribute.Resources = append(ps.Attribute.Resources, authResource)
@@ -99,6 +98,58 @@ func (ps *parseStream) watch() *parseStream {
 	return ps
 }
 
+// watchSubResourcePath is the path of the sub resource in the body of watching the resource.
+const watchSubResourcePath = "bk_filter." + common.BKSubResourceField
+
+// watchResourceAttribute returns the auth attribute of watching the resource, the sub resource in the body's
+// subResourcePath is used for authorization if subResourcePath is set.
+func (ps *parseStream) watchResourceAttribute(resource string, subResourcePath string) (meta.ResourceAttribute,
+	error) {
+
+	if len(resource) == 0 {
+		return meta.ResourceAttribute{}, fmt.Errorf("watch event resource, but got empty resource: %s", resource)
+	}
+
+	if resource == string(watch.HostIdentifier) {
+		// redirect host identity resource to host resource in iam.
+		resource = string(watch.Host)
+	}
+
+	if resource == string(watch.BizSetRelation) {
+		// redirect biz set relation resource to biz set resource in iam.
+		resource = string(watch.BizSet)
+	}
+
+	authResource := meta.ResourceAttribute{
+		Basic: meta.Basic{
+			Type:   meta.EventWatch,
+			Action: meta.Action(resource),
+		},
+	}
+
+	if len(subResourcePath) > 0 && (resource == string(watch.ObjectBase) ||
+		resource == string(watch.MainlineInstance) || resource == string(watch.InstAsst)) {
+
+		body, err := ps.RequestCtx.getRequestBody()
+		if err != nil {
+			return meta.ResourceAttribute{}, err
+		}
+
+		// use sub resource(corresponding to the bk_obj_id of the object) for authorization if it is set
+		// if sub resource is not set, verify authorization of the resource(which means all sub resources)
+		subResource := gjson.GetBytes(body, subResourcePath)
+		if subResource.Exists() {
+			model, err := ps.getOneModel(mapstr.MapStr{common.BKObjIDField: subResource.String()})
+			if err != nil {
+				return meta.ResourceAttribute{}, err
+			}
+			authResource.InstanceID = model.ID
+		}
+	}
+
+	return authResource, nil
+}
+
 const (
 	syncHostIdentifierPattern = "/api/v3/event/sync/host_identifier"
 	pushHostIdentifierPattern = "/api/v3/event/push/host_identifier"
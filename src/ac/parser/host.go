@@ -305,6 +305,7 @@ const (
 	addHostsByExcelPattern                = "/api/v3/hosts/excel/add"
 	addHostsToResourcePoolPattern         = "/api/v3/hosts/add/resource"
 	moveHostToBusinessModulePattern       = "/api/v3/hosts/modules"
+	batchMoveHostToBizModulePattern       = "/api/v3/hosts/modules/batch"
 	moveResPoolHostToBizIdleModulePattern = "/api/v3/hosts/modules/resource/idle"
 	moveHostsToBizFaultModulePattern      = "/api/v3/hosts/modules/fault"
 	moveHostsFromModuleToResPoolPattern   = "/api/v3/hosts/modules/resource"
@@ -325,6 +326,7 @@ const (
 	// used in sync framework.
 	// moveHostToBusinessOrModulePattern = "/api/v3/hosts/sync/new/host"
 	findHostsWithConditionPattern  = "/api/v3/hosts/search"
+	smartSearchHostsPattern        = "/api/v3/hosts/smart_search"
 	findBizHostsWithoutAppPattern  = "/api/v3/hosts/list_hosts_without_app"
 	findResourcePoolHostsPattern   = "/api/v3/hosts/list_resource_pool_hosts"
 	findHostsDetailsPattern        = "/api/v3/hosts/search/asstdetail"
@@ -334,6 +336,7 @@ const (
 
 	findHostRelationWithObjInstPattern = "/api/v3/findmany/hosts/relation/with_topo"
 	listHostDetailAndTopologyPattern   = "/api/v3/findmany/hosts/detail_topo"
+	findHostsByIDsPattern              = "/api/v3/findmany/hosts/by_ids"
 
 	findHostsServiceTemplatesPattern = "/api/v3/findmany/hosts/service_template"
 
@@ -362,11 +365,23 @@ var (
 	findHostsBySetTemplatesRegex     = regexp.MustCompile(`^/api/v3/findmany/hosts/by_set_templates/biz/\d+$`)
 	findHostModuleRelationsRegex     = regexp.MustCompile(`^/api/v3/findmany/module_relation/bk_biz_id/[0-9]+/?$`)
 	findHostsByTopoRegex             = regexp.MustCompile(`^/api/v3/findmany/hosts/by_topo/biz/\d+$`)
+	selectHostsRegex                 = regexp.MustCompile(`^/api/v3/findmany/hosts/select/biz/\d+$`)
 
 	// find host by biz set regex, authorize by biz set access permission, **only for ui**
 	findHostsByBizSetPattern = regexp.MustCompile(`^/api/v3/findmany/hosts/biz_set/[0-9]+/?$`)
 
 	findHostsTotalTopo = regexp.MustCompile(`^/api/v3/findmany/hosts/total_mainline_topo/biz/\d+$`)
+
+	// host recycle policy regex, the business id is the last element of the url
+	createHostRecyclePolicyRegex = regexp.MustCompile(`^/api/v3/create/host/recycle_policy/biz/\d+$`)
+	updateHostRecyclePolicyRegex = regexp.MustCompile(`^/api/v3/update/host/recycle_policy/\d+/biz/\d+$`)
+	deleteHostRecyclePolicyRegex = regexp.MustCompile(`^/api/v3/delete/host/recycle_policy/\d+/biz/\d+$`)
+	runHostRecyclePolicyRegex    = regexp.MustCompile(`^/api/v3/update/host/recycle_policy/\d+/run/biz/\d+$`)
+	findHostRecyclePolicyRegex   = regexp.MustCompile(`^/api/v3/findmany/host/recycle_policy/biz/\d+$`)
+	findHostRecycleReportRegex   = regexp.MustCompile(`^/api/v3/findmany/host/recycle_report/biz/\d+$`)
+	reviewHostRecycleReportRegex = regexp.MustCompile(`^/api/v3/update/host/recycle_report/\d+/review/biz/\d+$`)
+
+	probeHostsRegex = regexp.MustCompile(`^/api/v3/update/hosts/probe/biz/\d+$`)
 )
 
 func (ps *parseStream) host() *parseStream {
@@ -491,6 +506,92 @@ func (ps *parseStream) host() *parseStream {
 		return ps
 	}
 
+	// select hosts randomly with the spread constraints in the business.
+	// the host recycle policies move the hosts of the business, so they are authorized as updating the hosts
+	if ps.hitRegexp(createHostRecyclePolicyRegex, http.MethodPost) ||
+		ps.hitRegexp(updateHostRecyclePolicyRegex, http.MethodPut) ||
+		ps.hitRegexp(deleteHostRecyclePolicyRegex, http.MethodDelete) ||
+		ps.hitRegexp(runHostRecyclePolicyRegex, http.MethodPost) ||
+		ps.hitRegexp(reviewHostRecycleReportRegex, http.MethodPut) {
+
+		bizIDStr := ps.RequestCtx.Elements[len(ps.RequestCtx.Elements)-1]
+		bizID, err := strconv.ParseInt(bizIDStr, 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("operate host recycle policy, but got invalid business id: %s", bizIDStr)
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:   meta.HostInstance,
+					Action: meta.Update,
+				},
+			},
+		}
+		return ps
+	}
+
+	if ps.hitRegexp(findHostRecyclePolicyRegex, http.MethodPost) ||
+		ps.hitRegexp(findHostRecycleReportRegex, http.MethodPost) {
+
+		bizID, err := strconv.ParseInt(ps.RequestCtx.Elements[6], 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("find host recycle policy, but got invalid business id: %s", ps.RequestCtx.Elements[6])
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:   meta.HostInstance,
+					Action: meta.FindMany,
+				},
+			},
+		}
+		return ps
+	}
+
+	if ps.hitRegexp(probeHostsRegex, http.MethodPost) {
+		bizID, err := strconv.ParseInt(ps.RequestCtx.Elements[6], 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("probe hosts, but got invalid business id: %s", ps.RequestCtx.Elements[6])
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:   meta.HostInstance,
+					Action: meta.Update,
+				},
+			},
+		}
+		return ps
+	}
+
+	if ps.hitRegexp(selectHostsRegex, http.MethodPost) {
+		bizID, err := strconv.ParseInt(ps.RequestCtx.Elements[6], 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("select hosts, but got invalid business id: %s", ps.RequestCtx.Elements[6])
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:   meta.HostInstance,
+					Action: meta.FindMany,
+				},
+			},
+		}
+		return ps
+	}
+
 	if ps.hitRegexp(findHostsTotalTopo, http.MethodPost) {
 		bizID, err := strconv.ParseInt(ps.RequestCtx.Elements[6], 10, 64)
 		if err != nil {
@@ -651,7 +752,8 @@ func (ps *parseStream) host() *parseStream {
 	}
 
 	// find hosts with condition operation.
-	if ps.hitPattern(findHostsWithConditionPattern, http.MethodPost) {
+	if ps.hitPattern(findHostsWithConditionPattern, http.MethodPost) ||
+		ps.hitPattern(smartSearchHostsPattern, http.MethodPost) {
 		bizID, err := ps.parseBusinessID()
 		if err != nil {
 			ps.err = err
@@ -763,6 +865,19 @@ func (ps *parseStream) host() *parseStream {
 	}
 
 	// list host's detail and it's topology info
+	// find hosts by ids, the hosts that the user is not authorized to view are filtered by host server.
+	if ps.hitPattern(findHostsByIDsPattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.HostInstance,
+					Action: meta.SkipAction,
+				},
+			},
+		}
+		return ps
+	}
+
 	if ps.hitPattern(listHostDetailAndTopologyPattern, http.MethodPost) {
 		ps.Attribute.Resources = []meta.ResourceAttribute{
 			{
@@ -1048,8 +1163,10 @@ func (ps *parseStream) hostTransfer() *parseStream {
 		return ps
 	}
 
-	// move hosts to business module operation, transfer host in the same business.
-	if ps.hitPattern(moveHostToBusinessModulePattern, http.MethodPost) {
+	// move hosts to business module operation, transfer host in the same business, the batch transfer moves each
+	// host to its own modules in the same business.
+	if ps.hitPattern(moveHostToBusinessModulePattern, http.MethodPost) ||
+		ps.hitPattern(batchMoveHostToBizModulePattern, http.MethodPost) {
 		bizID, err := ps.parseBusinessID()
 		if err != nil {
 			ps.err = err
@@ -1475,7 +1592,8 @@ func (ps *parseStream) hostFavorite() *parseStream {
 }
 
 var (
-	findIdentifierAPIRegexp = regexp.MustCompile(`^/api/v3/identifier/[^\s/]+/search/?$`)
+	findIdentifierAPIRegexp      = regexp.MustCompile(`^/api/v3/identifier/[^\s/]+/search/?$`)
+	snapshotHostIdentifierRegexp = regexp.MustCompile(`^/api/v3/identifier/host/snapshot/biz/[0-9]+/?$`)
 )
 
 func (ps *parseStream) findObjectIdentifier() *parseStream {
@@ -1493,6 +1611,25 @@ func (ps *parseStream) findObjectIdentifier() *parseStream {
 		}
 		return ps
 	}
+
+	// the host identifier snapshot of the business is authorized as finding the hosts of the business
+	if ps.hitRegexp(snapshotHostIdentifierRegexp, http.MethodPost) {
+		bizID, err := strconv.ParseInt(ps.RequestCtx.Elements[6], 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("snapshot host identifier, but got invalid business id: %s", ps.RequestCtx.Elements[6])
+			return ps
+		}
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:   meta.HostInstance,
+					Action: meta.FindMany,
+				},
+			},
+		}
+		return ps
+	}
 	return ps
 }
 
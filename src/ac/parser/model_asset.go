@@ -0,0 +1,66 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"net/http"
+	"regexp"
+
+	"configcenter/src/ac/meta"
+)
+
+var (
+	addModelAssetVersionLatestRegexp = regexp.MustCompile(`^/api/v3/create/model/asset/[0-9]+/version/?$`)
+	deleteModelAssetLatestRegexp     = regexp.MustCompile(`^/api/v3/delete/model/asset/[0-9]+/?$`)
+)
+
+// ModelAssetAuthConfigs the model assets are shared by all the models, so they are managed by the config admin
+var ModelAssetAuthConfigs = []AuthConfig{
+	{
+		Name:           "createModelAssetLatest",
+		Description:    "创建模型资源",
+		Pattern:        "/api/v3/create/model/asset",
+		HTTPMethod:     http.MethodPost,
+		ResourceType:   meta.ConfigAdmin,
+		ResourceAction: meta.Update,
+	}, {
+		Name:           "addModelAssetVersionLatest",
+		Description:    "新增模型资源版本",
+		Regex:          addModelAssetVersionLatestRegexp,
+		HTTPMethod:     http.MethodPost,
+		ResourceType:   meta.ConfigAdmin,
+		ResourceAction: meta.Update,
+	}, {
+		Name:           "searchModelAssetLatest",
+		Description:    "查询模型资源",
+		Pattern:        "/api/v3/findmany/model/asset",
+		HTTPMethod:     http.MethodPost,
+		ResourceType:   meta.ConfigAdmin,
+		ResourceAction: meta.SkipAction,
+	}, {
+		Name:           "deleteModelAssetLatest",
+		Description:    "删除模型资源",
+		Regex:          deleteModelAssetLatestRegexp,
+		HTTPMethod:     http.MethodDelete,
+		ResourceType:   meta.ConfigAdmin,
+		ResourceAction: meta.Update,
+	},
+}
+
+func (ps *parseStream) modelAssetLatest() *parseStream {
+	if ps.shouldReturn() {
+		return ps
+	}
+
+	return ParseStreamWithFramework(ps, ModelAssetAuthConfigs)
+}
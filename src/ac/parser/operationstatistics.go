@@ -26,6 +26,13 @@ import (
  http.MethodPost,  "/update/operation/chart"
  http.MethodGet,  "/search/operation/chart"
  http.MethodPost,  "/search/operation/chart/data"
+ http.MethodPost,  "/create/operation/report_subscription"
+ http.MethodPut,  "/update/operation/report_subscription/{id}"
+ http.MethodDelete,  "/delete/operation/report_subscription/{id}"
+ http.MethodPost,  "/findmany/operation/report_subscription"
+ http.MethodPost,  "/create/operation/report_subscription/{id}/send"
+ http.MethodPost,  "/find/operation/host_placement/single_domain_module"
+ http.MethodPost,  "/find/operation/change_digest"
 */
 var OperationStatisticAuthConfigs = []AuthConfig{
 	{
@@ -82,6 +89,69 @@ var OperationStatisticAuthConfigs = []AuthConfig{
 		ResourceType:   meta.OperationStatistic,
 		ResourceAction: meta.Update,
 	},
+	{
+		Name:           "CreateReportSubscriptionRegex",
+		Description:    "创建报表订阅",
+		Regex:          regexp.MustCompile(`^/api/v3/create/operation/report_subscription/?$`),
+		HTTPMethod:     http.MethodPost,
+		BizIDGetter:    nil,
+		ResourceType:   meta.OperationStatistic,
+		ResourceAction: meta.Update,
+	},
+	{
+		Name:           "UpdateReportSubscriptionRegex",
+		Description:    "更新报表订阅",
+		Regex:          regexp.MustCompile(`^/api/v3/update/operation/report_subscription/([0-9]+)/?$`),
+		HTTPMethod:     http.MethodPut,
+		BizIDGetter:    nil,
+		ResourceType:   meta.OperationStatistic,
+		ResourceAction: meta.Update,
+	},
+	{
+		Name:           "DeleteReportSubscriptionRegex",
+		Description:    "删除报表订阅",
+		Regex:          regexp.MustCompile(`^/api/v3/delete/operation/report_subscription/([0-9]+)/?$`),
+		HTTPMethod:     http.MethodDelete,
+		BizIDGetter:    nil,
+		ResourceType:   meta.OperationStatistic,
+		ResourceAction: meta.Update,
+	},
+	{
+		Name:           "SearchReportSubscriptionRegex",
+		Description:    "查看报表订阅",
+		Regex:          regexp.MustCompile(`^/api/v3/findmany/operation/report_subscription/?$`),
+		HTTPMethod:     http.MethodPost,
+		BizIDGetter:    nil,
+		ResourceType:   meta.OperationStatistic,
+		ResourceAction: meta.Find,
+	},
+	{
+		Name:           "SendReportSubscriptionRegex",
+		Description:    "立即发送订阅报表",
+		Regex:          regexp.MustCompile(`^/api/v3/create/operation/report_subscription/([0-9]+)/send/?$`),
+		HTTPMethod:     http.MethodPost,
+		BizIDGetter:    nil,
+		ResourceType:   meta.OperationStatistic,
+		ResourceAction: meta.Update,
+	},
+	{
+		Name:           "FindSingleDomainModuleRegex",
+		Description:    "查询主机均位于单一故障域的模块",
+		Regex:          regexp.MustCompile(`^/api/v3/find/operation/host_placement/single_domain_module/?$`),
+		HTTPMethod:     http.MethodPost,
+		BizIDGetter:    nil,
+		ResourceType:   meta.OperationStatistic,
+		ResourceAction: meta.Find,
+	},
+	{
+		Name:           "FindChangeDigestRegex",
+		Description:    "查询业务变更摘要",
+		Regex:          regexp.MustCompile(`^/api/v3/find/operation/change_digest/?$`),
+		HTTPMethod:     http.MethodPost,
+		BizIDGetter:    nil,
+		ResourceType:   meta.OperationStatistic,
+		ResourceAction: meta.Find,
+	},
 }
 
 // OperationStatistic TODO
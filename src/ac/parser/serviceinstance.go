@@ -140,6 +140,69 @@ var ServiceInstanceAuthConfigs = []AuthConfig{
 		BizIndex:       6,
 		ResourceType:   meta.ProcessServiceInstance,
 		ResourceAction: meta.UpdateMany,
+	}, {
+		Name:           "renderServiceInstanceNames",
+		Description:    "按命名模板重新生成某业务下的服务实例名称",
+		Regex:          regexp.MustCompile(`^/api/v3/updatemany/proc/service_instance/name/render/biz/([0-9]+)/?$`),
+		HTTPMethod:     http.MethodPut,
+		BizIDGetter:    BizIDFromURLGetter,
+		BizIndex:       8,
+		ResourceType:   meta.ProcessServiceInstance,
+		ResourceAction: meta.UpdateMany,
+	}, {
+		Name:           "saveServiceInstanceNameTemplate",
+		Description:    "设置某业务的服务实例命名模板",
+		Regex:          regexp.MustCompile(`^/api/v3/update/proc/service_instance/name_template/biz/([0-9]+)/?$`),
+		HTTPMethod:     http.MethodPut,
+		BizIDGetter:    BizIDFromURLGetter,
+		BizIndex:       7,
+		ResourceType:   meta.ProcessServiceInstance,
+		ResourceAction: meta.UpdateMany,
+	}, {
+		Name:           "findServiceInstanceNameTemplate",
+		Description:    "查询某业务的服务实例命名模板",
+		Regex:          regexp.MustCompile(`^/api/v3/find/proc/service_instance/name_template/biz/([0-9]+)/?$`),
+		HTTPMethod:     http.MethodPost,
+		BizIDGetter:    BizIDFromURLGetter,
+		BizIndex:       7,
+		ResourceType:   meta.ProcessServiceInstance,
+		ResourceAction: meta.SkipAction,
+	}, {
+		Name:           "deleteServiceInstanceNameTemplate",
+		Description:    "删除某业务的服务实例命名模板",
+		Regex:          regexp.MustCompile(`^/api/v3/delete/proc/service_instance/name_template/biz/([0-9]+)/?$`),
+		HTTPMethod:     http.MethodDelete,
+		BizIDGetter:    BizIDFromURLGetter,
+		BizIndex:       7,
+		ResourceType:   meta.ProcessServiceInstance,
+		ResourceAction: meta.UpdateMany,
+	}, {
+		Name:           "saveTemplateVariable",
+		Description:    "设置某业务下模块或集群绑定的模板变量",
+		Regex:          regexp.MustCompile(`^/api/v3/update/proc/template_variable/biz/([0-9]+)/?$`),
+		HTTPMethod:     http.MethodPut,
+		BizIDGetter:    BizIDFromURLGetter,
+		BizIndex:       6,
+		ResourceType:   meta.ProcessServiceInstance,
+		ResourceAction: meta.UpdateMany,
+	}, {
+		Name:           "listTemplateVariable",
+		Description:    "查询某业务下模块或集群绑定的模板变量",
+		Regex:          regexp.MustCompile(`^/api/v3/findmany/proc/template_variable/biz/([0-9]+)/?$`),
+		HTTPMethod:     http.MethodPost,
+		BizIDGetter:    BizIDFromURLGetter,
+		BizIndex:       6,
+		ResourceType:   meta.ProcessServiceInstance,
+		ResourceAction: meta.SkipAction,
+	}, {
+		Name:           "deleteTemplateVariable",
+		Description:    "删除某业务下模块或集群绑定的模板变量",
+		Regex:          regexp.MustCompile(`^/api/v3/delete/proc/template_variable/biz/([0-9]+)/?$`),
+		HTTPMethod:     http.MethodDelete,
+		BizIDGetter:    BizIDFromURLGetter,
+		BizIndex:       6,
+		ResourceType:   meta.ProcessServiceInstance,
+		ResourceAction: meta.UpdateMany,
 	}, {
 		Name:        "updateServiceTemplateHostApplyEnableStatus",
 		Description: "更新服务模板主机自动应用状态",
@@ -183,6 +246,22 @@ var ServiceInstanceAuthConfigs = []AuthConfig{
 		BizIDGetter:    DefaultBizIDGetter,
 		ResourceType:   meta.ProcessServiceInstance,
 		ResourceAction: meta.Delete,
+	}, {
+		Name:           "previewBatchDeleteServiceInstancePattern",
+		Description:    "预览批量删除服务实例涉及的服务实例、进程及模板",
+		Pattern:        "/api/v3/find/proc/service_instance/batch_delete/preview",
+		HTTPMethod:     http.MethodPost,
+		BizIDGetter:    DefaultBizIDGetter,
+		ResourceType:   meta.ProcessServiceInstance,
+		ResourceAction: meta.Find,
+	}, {
+		Name:           "batchDeleteServiceInstancePattern",
+		Description:    "按ID或过滤条件批量删除服务实例",
+		Pattern:        "/api/v3/deletemany/proc/service_instance/batch",
+		HTTPMethod:     http.MethodDelete,
+		BizIDGetter:    DefaultBizIDGetter,
+		ResourceType:   meta.ProcessServiceInstance,
+		ResourceAction: meta.Delete,
 	}, {
 		Name:           "diffServiceInstanceWithTemplatePattern",
 		Description:    "对比服务实例与模板差异涉及到的进程列表",
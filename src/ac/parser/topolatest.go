@@ -23,6 +23,7 @@ import (
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
 
 	"github.com/tidwall/gjson"
 )
@@ -47,7 +48,10 @@ func (ps *parseStream) topologyLatest() *parseStream {
 		objectAttributeGroupLatest().
 		objectAttributeLatest().
 		mainlineLatest().
-		setTemplate()
+		setTemplate().
+		changeBundleLatest().
+		bizRoleLatest().
+		modelAssetLatest()
 
 	return ps
 }
@@ -304,6 +308,7 @@ func (ps *parseStream) associationTypeLatest() *parseStream {
 
 const (
 	findObjectAssociationLatestPattern                    = "/api/v3/find/objectassociation"
+	findObjectAsstMappingViolationLatestPattern           = "/api/v3/find/objectassociation/mapping_violation"
 	createObjectAssociationLatestPattern                  = "/api/v3/create/objectassociation"
 	findObjectAssociationWithAssociationKindLatestPattern = "/api/v3/find/topoassociationtype"
 )
@@ -337,6 +342,19 @@ func (ps *parseStream) objectAssociationLatest() *parseStream {
 		return ps
 	}
 
+	// scan the instance associations that violate the object association's mapping
+	if ps.hitPattern(findObjectAsstMappingViolationLatestPattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.ModelAssociation,
+					Action: meta.FindMany,
+				},
+			},
+		}
+		return ps
+	}
+
 	// create object association operation
 	if ps.hitPattern(createObjectAssociationLatestPattern, http.MethodPost) {
 		val, err := ps.RequestCtx.getValueFromBody(common.BKObjIDField)
@@ -562,6 +580,8 @@ var (
 
 	searchInstanceAssociationsRegexp = regexp.MustCompile(`^/api/v3/search/instance_associations/object/[^\s/]+/?$`)
 	countInstanceAssociationsRegexp  = regexp.MustCompile(`^/api/v3/count/instance_associations/object/[^\s/]+/?$`)
+
+	findInstAsstEdgesRegexp = regexp.MustCompile(`^/api/v3/(findmany|count)/instassociation/edge/object/[^\s/]+/?$`)
 )
 
 func (ps *parseStream) objectInstanceAssociationLatest() *parseStream {
@@ -611,6 +631,42 @@ func (ps *parseStream) objectInstanceAssociationLatest() *parseStream {
 		return ps
 	}
 
+	// search or count the instance association edges operation.
+	if ps.hitRegexp(findInstAsstEdgesRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) != 7 {
+			ps.err = errors.New("find instance association edges, got invalid url")
+			return ps
+		}
+
+		bizID, err := ps.RequestCtx.getBizIDFromBody()
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		model, err := ps.getOneModel(mapstr.MapStr{common.BKObjIDField: ps.RequestCtx.Elements[6]})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		instanceType, err := ps.getInstanceTypeByObject(model.ObjectID, model.ID)
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:   instanceType,
+					Action: meta.FindMany,
+				},
+			},
+		}
+		return ps
+	}
+
 	// search instance associations operation.
 	if ps.hitRegexp(searchInstanceAssociationsRegexp, http.MethodPost) {
 		if len(ps.RequestCtx.Elements) != 6 {
@@ -1146,9 +1202,15 @@ const (
 )
 
 var (
-	createObjectInstanceLatestRegexp             = regexp.MustCompile(`^/api/v3/create/instance/object/[^\s/]+/?$`)
+	createObjectInstanceLatestRegexp         = regexp.MustCompile(`^/api/v3/create/instance/object/[^\s/]+/?$`)
+	createOrUpdateObjectInstanceLatestRegexp = regexp.MustCompile(
+		`^/api/v3/create_or_update/instance/object/[^\s/]+/?$`)
 	createObjectManyInstanceByImportLatestRegexp = regexp.MustCompile(
 		`^/api/v3/create/instance/object/[^\s/]+/by_import/?$`)
+	createObjectInstanceImportTaskLatestRegexp = regexp.MustCompile(
+		`^/api/v3/create/instance/object/[^\s/]+/by_import/task/?$`)
+	createObjectInstanceExportTaskLatestRegexp = regexp.MustCompile(
+		`^/api/v3/create/instance/object/[^\s/]+/export/task/?$`)
 	createObjectManyInstanceLatestRegexp      = regexp.MustCompile(`^/api/v3/createmany/instance/object/[^\s/]+/?$`)
 	findObjectInstanceAssociationLatestRegexp = regexp.MustCompile(`^/api/v3/find/instassociation/object/[^\s/]+/?$`)
 	updateObjectInstanceLatestRegexp          = regexp.MustCompile(
@@ -1157,17 +1219,33 @@ var (
 	deleteObjectInstanceBatchLatestRegexp = regexp.MustCompile(`^/api/v3/deletemany/instance/object/[^\s/]+/?$`)
 	deleteObjectInstanceLatestRegexp      = regexp.MustCompile(
 		`^/api/v3/delete/instance/object/[^\s/]+/inst/[0-9]+/?$`)
+	mergeObjectInstanceLatestRegexp        = regexp.MustCompile(`^/api/v3/update/instance/object/[^\s/]+/merge/?$`)
+	previewMergeObjectInstanceLatestRegexp = regexp.MustCompile(
+		`^/api/v3/find/instance/object/[^\s/]+/merge/preview/?$`)
 	// TODO remove it
 	findObjectInstanceSubTopologyLatestRegexp = regexp.MustCompile(
 		`^/api/v3/find/insttopo/object/[^\s/]+/inst/[0-9]+/?$`)
 	findObjectInstanceTopologyLatestRegexp = regexp.MustCompile(
 		`^/api/v3/find/instassttopo/object/[^\s/]+/inst/[0-9]+/?$`)
+	findObjectInstanceGraphLatestRegexp = regexp.MustCompile(
+		`^/api/v3/find/instassttopo/graph/object/[^\s/]+/inst/[0-9]+/?$`)
+	findResourceActionsLatestRegexp   = regexp.MustCompile(`^/api/v3/find/resource/[^\s/]+/[0-9]+/actions/?$`)
+	executeResourceActionLatestRegexp = regexp.MustCompile(
+		`^/api/v3/update/resource/[^\s/]+/[0-9]+/actions/[^\s/]+/?$`)
 	findObjectInstancesLatestRegexp       = regexp.MustCompile(`^/api/v3/find/instance/object/[^\s/]+/?$`)
 	findObjectInstancesUniqueFieldsRegexp = regexp.MustCompile(
 		`^/api/v3/find/instance/object/[^\s/]+/unique_fields/by/unique/[0-9]+/?$`)
 
-	searchObjectInstancesRegexp = regexp.MustCompile(`^/api/v3/search/instances/object/[^\s/]+/?$`)
-	countObjectInstancesRegexp  = regexp.MustCompile(`^/api/v3/count/instances/object/[^\s/]+/?$`)
+	searchObjectInstancesRegexp    = regexp.MustCompile(`^/api/v3/search/instances/object/[^\s/]+/?$`)
+	countObjectInstancesRegexp     = regexp.MustCompile(`^/api/v3/count/instances/object/[^\s/]+/?$`)
+	findObjectInstancesByIDsRegexp = regexp.MustCompile(`^/api/v3/findmany/instance/object/[^\s/]+/by_ids/?$`)
+
+	findObjectInstImportConflictRegexp = regexp.MustCompile(
+		`^/api/v3/findmany/instance/object/[^\s/]+/import_conflict/?$`)
+
+	bindExternalIDRegexp    = regexp.MustCompile(`^/api/v3/update/external_id/object/[^\s/]+/inst/[0-9]+/?$`)
+	unbindExternalIDRegexp  = regexp.MustCompile(`^/api/v3/delete/external_id/object/[^\s/]+/inst/[0-9]+/?$`)
+	searchExternalIDsRegexp = regexp.MustCompile(`^/api/v3/findmany/external_id/object/[^\s/]+/?$`)
 )
 
 func (ps *parseStream) objectInstanceLatest() *parseStream {
@@ -1175,8 +1253,9 @@ func (ps *parseStream) objectInstanceLatest() *parseStream {
 		return ps
 	}
 
-	// create instance operation
-	if ps.hitRegexp(createObjectInstanceLatestRegexp, http.MethodPost) {
+	// create instance operation, the update permission of create or update instance is authorized by topo server
+	if ps.hitRegexp(createObjectInstanceLatestRegexp, http.MethodPost) ||
+		ps.hitRegexp(createOrUpdateObjectInstanceLatestRegexp, http.MethodPost) {
 		if len(ps.RequestCtx.Elements) != 6 {
 			ps.err = errors.New("create instance, but got invalid url")
 			return ps
@@ -1251,6 +1330,74 @@ func (ps *parseStream) objectInstanceLatest() *parseStream {
 		return ps
 	}
 
+	// create instance import job, same as the import
+	if ps.hitRegexp(createObjectInstanceImportTaskLatestRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) != 8 {
+			ps.err = errors.New("create instance import task, but got invalid url")
+			return ps
+		}
+
+		objID := ps.RequestCtx.Elements[5]
+		model, err := ps.getOneModel(mapstr.MapStr{common.BKObjIDField: objID})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		instanceType, err := ps.getInstanceTypeByObject(model.ObjectID, model.ID)
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		bizID, err := ps.RequestCtx.getBizIDFromBody()
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:   instanceType,
+					Action: meta.Create,
+				},
+			},
+		}
+
+		return ps
+	}
+
+	// create instance export job, same as finding the instances
+	if ps.hitRegexp(createObjectInstanceExportTaskLatestRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) != 8 {
+			ps.err = errors.New("create instance export task, but got invalid url")
+			return ps
+		}
+
+		objID := ps.RequestCtx.Elements[5]
+		model, err := ps.getOneModel(mapstr.MapStr{common.BKObjIDField: objID})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		instanceType, err := ps.getInstanceTypeByObject(model.ObjectID, model.ID)
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   instanceType,
+					Action: meta.SkipAction,
+				},
+			},
+		}
+		return ps
+	}
+
 	if ps.hitRegexp(createObjectManyInstanceLatestRegexp, http.MethodPost) {
 		if len(ps.RequestCtx.Elements) != 6 {
 			ps.err = errors.New("create instance, but got invalid url")
@@ -1521,6 +1668,69 @@ func (ps *parseStream) objectInstanceLatest() *parseStream {
 		return ps
 	}
 
+	// merge instance operation, the source instance is deleted and the target instance is updated.
+	isMergeInst := ps.hitRegexp(mergeObjectInstanceLatestRegexp, http.MethodPut)
+	if isMergeInst || ps.hitRegexp(previewMergeObjectInstanceLatestRegexp, http.MethodPost) {
+		if (isMergeInst && len(ps.RequestCtx.Elements) != 7) || (!isMergeInst && len(ps.RequestCtx.Elements) != 8) {
+			ps.err = errors.New("merge object instance, but got invalid url")
+			return ps
+		}
+
+		objID := ps.RequestCtx.Elements[5]
+		model, err := ps.getOneModel(mapstr.MapStr{common.BKObjIDField: objID})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		instanceType, err := ps.getInstanceTypeByObject(model.ObjectID, model.ID)
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		bizID, err := ps.RequestCtx.getBizIDFromBody()
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		sourceID, err := ps.RequestCtx.getValueFromBody("source_id")
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		targetID, err := ps.RequestCtx.getValueFromBody("target_id")
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		sourceAction, targetAction := meta.Find, meta.Find
+		if isMergeInst {
+			sourceAction, targetAction = meta.Delete, meta.Update
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:       instanceType,
+					Action:     sourceAction,
+					InstanceID: sourceID.Int(),
+				},
+			},
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:       instanceType,
+					Action:     targetAction,
+					InstanceID: targetID.Int(),
+				},
+			},
+		}
+		return ps
+	}
+
 	// find object instance sub topology operation
 	if ps.hitRegexp(findObjectInstanceSubTopologyLatestRegexp, http.MethodPost) {
 		if len(ps.RequestCtx.Elements) != 8 {
@@ -1570,6 +1780,58 @@ func (ps *parseStream) objectInstanceLatest() *parseStream {
 		return ps
 	}
 
+	// find object instance neighborhood graph operation.
+	if ps.hitRegexp(findObjectInstanceGraphLatestRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) != 9 {
+			ps.err = errors.New("find object instance graph, but got invalid url")
+			return ps
+		}
+
+		bizID, err := ps.RequestCtx.getBizIDFromBody()
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:   meta.ModelInstanceTopology,
+					Action: meta.Find,
+				},
+			},
+		}
+		return ps
+	}
+
+	// find or execute resource custom actions operation, the permission required by the action is configured
+	// along with the action, so it is authorized by topo server when the action is executed.
+	if ps.hitRegexp(findResourceActionsLatestRegexp, http.MethodPost) ||
+		ps.hitRegexp(executeResourceActionLatestRegexp, http.MethodPost) {
+
+		model, err := ps.getOneModel(mapstr.MapStr{common.BKObjIDField: ps.RequestCtx.Elements[4]})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		instanceType, err := ps.getInstanceTypeByObject(model.ObjectID, model.ID)
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   instanceType,
+					Action: meta.SkipAction,
+				},
+			},
+		}
+		return ps
+	}
+
 	// find object's instance list operation
 	if ps.hitRegexp(findObjectInstancesLatestRegexp, http.MethodPost) {
 		if len(ps.RequestCtx.Elements) != 6 {
@@ -1670,10 +1932,11 @@ func (ps *parseStream) objectInstanceLatest() *parseStream {
 		return ps
 	}
 
-	// find object's instances' unique fields operation
-	if ps.hitRegexp(findObjectInstancesUniqueFieldsRegexp, http.MethodPost) {
-		if len(ps.RequestCtx.Elements) != 10 {
-			ps.err = errors.New("find object's instances' unique fields, but got invalid url")
+	// find object instances by ids operation, the instances that the user is not authorized to view are filtered by
+	// topo server.
+	if ps.hitRegexp(findObjectInstancesByIDsRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) != 7 {
+			ps.err = errors.New("find object instances by ids, got invalid url")
 			return ps
 		}
 
@@ -1700,15 +1963,17 @@ func (ps *parseStream) objectInstanceLatest() *parseStream {
 		return ps
 	}
 
-	// find object instances' names to get set/module name for host advanced filter
-	if ps.hitPattern(findObjectInstancesNamesPattern, http.MethodPost) {
-		val, err := ps.RequestCtx.getValueFromBody(common.BKObjIDField)
-		if err != nil {
-			ps.err = err
+	// bind or unbind the external ids of the instance, the update permission of the instance is authorized by the
+	// topo server, which handles the hosts and the mainline instances. search the external ids is like searching
+	// the instances, which skips the authorization.
+	if ps.hitRegexp(bindExternalIDRegexp, http.MethodPut) || ps.hitRegexp(unbindExternalIDRegexp, http.MethodDelete) ||
+		ps.hitRegexp(searchExternalIDsRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) < 6 {
+			ps.err = errors.New("operate external ids, but got invalid url")
 			return ps
 		}
-		objID := val.Value()
 
+		objID := ps.RequestCtx.Elements[5]
 		model, err := ps.getOneModel(mapstr.MapStr{common.BKObjIDField: objID})
 		if err != nil {
 			ps.err = err
@@ -1731,38 +1996,121 @@ func (ps *parseStream) objectInstanceLatest() *parseStream {
 		return ps
 	}
 
-	return ps
-}
-
-const (
-	createObjectLatestPattern            = "/api/v3/create/object"
-	createManyObjectLatestPattern        = "/api/v3/createmany/object/by_import"
-	createObjectBatchLatestPattern       = "/api/v3/createmany/object"
-	findObjectsLatestPattern             = "/api/v3/find/object"
-	findObjectBatchLatestPattern         = "/api/v3/findmany/object"
-	findObjectWithTotalInfoLatestPattern = "/api/v3/findmany/object/total/info"
-	findObjectTopologyLatestPattern      = "/api/v3/find/objecttopology"
-)
-
-var (
-	deleteObjectLatestRegexp = regexp.MustCompile(`^/api/v3/delete/object/[0-9]+/?$`)
-	updateObjectLatestRegexp = regexp.MustCompile(`^/api/v3/update/object/[0-9]+/?$`)
-
-	// TODO remove it
-	// 获取模型拓扑图及位置信息-Web
-	findObjectTopologyGraphicLatestRegexp = regexp.MustCompile(`^/api/v3/find/objecttopo/scope_type/[^\s/]+/scope_id/[^\s/]+/?$`)
-	// 设置模型拓扑图及位置信息-Web
-	updateObjectTopologyGraphicLatestRegexp = regexp.MustCompile(`^/api/v3/update/objecttopo/scope_type/[^\s/]+/scope_id/[^\s/]+/?$`)
-)
-
-func (ps *parseStream) objectLatest() *parseStream {
-	if ps.shouldReturn() {
+	// find the import conflicts of the object's instances, which is reviewed by the platform admins since the
+	// conflicts are caused by the ingestion paths of the platform.
+	if ps.hitRegexp(findObjectInstImportConflictRegexp, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.ConfigAdmin,
+					Action: meta.Update,
+				},
+			},
+		}
 		return ps
 	}
 
-	// create common object operation.
-	if ps.hitPattern(createObjectLatestPattern, http.MethodPost) {
-		val, err := ps.RequestCtx.getValueFromBody(common.BKClassificationIDField)
+	// find object's instances' unique fields operation
+	if ps.hitRegexp(findObjectInstancesUniqueFieldsRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) != 10 {
+			ps.err = errors.New("find object's instances' unique fields, but got invalid url")
+			return ps
+		}
+
+		objID := ps.RequestCtx.Elements[5]
+		model, err := ps.getOneModel(mapstr.MapStr{common.BKObjIDField: objID})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		instanceType, err := ps.getInstanceTypeByObject(model.ObjectID, model.ID)
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   instanceType,
+					Action: meta.SkipAction,
+				},
+			},
+		}
+		return ps
+	}
+
+	// find object instances' names to get set/module name for host advanced filter
+	if ps.hitPattern(findObjectInstancesNamesPattern, http.MethodPost) {
+		val, err := ps.RequestCtx.getValueFromBody(common.BKObjIDField)
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		objID := val.Value()
+
+		model, err := ps.getOneModel(mapstr.MapStr{common.BKObjIDField: objID})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+		instanceType, err := ps.getInstanceTypeByObject(model.ObjectID, model.ID)
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   instanceType,
+					Action: meta.SkipAction,
+				},
+			},
+		}
+		return ps
+	}
+
+	return ps
+}
+
+const (
+	createObjectLatestPattern            = "/api/v3/create/object"
+	createManyObjectLatestPattern        = "/api/v3/createmany/object/by_import"
+	createObjectBatchLatestPattern       = "/api/v3/createmany/object"
+	findObjectsLatestPattern             = "/api/v3/find/object"
+	findObjectBatchLatestPattern         = "/api/v3/findmany/object"
+	findObjectWithTotalInfoLatestPattern = "/api/v3/findmany/object/total/info"
+	findObjectTopologyLatestPattern      = "/api/v3/find/objecttopology"
+	exportModelBundleLatestPattern       = "/api/v3/findmany/object/bundle/export"
+	previewInstallModelBundlePattern     = "/api/v3/find/object/bundle/install/preview"
+	installModelBundleLatestPattern      = "/api/v3/createmany/object/by_bundle"
+)
+
+var (
+	deleteObjectLatestRegexp        = regexp.MustCompile(`^/api/v3/delete/object/[0-9]+/?$`)
+	updateObjectLatestRegexp        = regexp.MustCompile(`^/api/v3/update/object/[0-9]+/?$`)
+	deleteObjectCascadeLatestRegexp = regexp.MustCompile(`^/api/v3/delete/object/[0-9]+/cascade/?$`)
+	previewDeleteObjectLatestRegexp = regexp.MustCompile(`^/api/v3/find/object/[0-9]+/delete/preview/?$`)
+
+	// 更新模型的生命周期
+	updateObjectLifecycleLatestRegexp = regexp.MustCompile(`^/api/v3/update/object/[0-9]+/lifecycle/?$`)
+
+	// TODO remove it
+	// 获取模型拓扑图及位置信息-Web
+	findObjectTopologyGraphicLatestRegexp = regexp.MustCompile(`^/api/v3/find/objecttopo/scope_type/[^\s/]+/scope_id/[^\s/]+/?$`)
+	// 设置模型拓扑图及位置信息-Web
+	updateObjectTopologyGraphicLatestRegexp = regexp.MustCompile(`^/api/v3/update/objecttopo/scope_type/[^\s/]+/scope_id/[^\s/]+/?$`)
+)
+
+func (ps *parseStream) objectLatest() *parseStream {
+	if ps.shouldReturn() {
+		return ps
+	}
+
+	// create common object operation.
+	if ps.hitPattern(createObjectLatestPattern, http.MethodPost) {
+		val, err := ps.RequestCtx.getValueFromBody(common.BKClassificationIDField)
 		if err != nil {
 			ps.err = err
 			return ps
@@ -1869,9 +2217,44 @@ func (ps *parseStream) objectLatest() *parseStream {
 		return ps
 	}
 
-	// update object operation.
-	if ps.hitRegexp(updateObjectLatestRegexp, http.MethodPut) {
-		if len(ps.RequestCtx.Elements) != 5 {
+	// delete object with cascade policy operation, preview the deletion needs the same permission.
+	if ps.hitRegexp(deleteObjectCascadeLatestRegexp, http.MethodDelete) ||
+		ps.hitRegexp(previewDeleteObjectLatestRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) < 6 {
+			ps.err = errors.New("delete object with policy, but got invalid url")
+			return ps
+		}
+
+		id, err := strconv.ParseInt(ps.RequestCtx.Elements[4], 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("delete object with policy, but got invalid object's id %s",
+				ps.RequestCtx.Elements[4])
+			return ps
+		}
+
+		bizID, err := ps.RequestCtx.getBizIDFromBody()
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:       meta.Model,
+					Action:     meta.Delete,
+					InstanceID: id,
+				},
+			},
+		}
+		return ps
+	}
+
+	// update object operation, update the object's lifecycle needs the same permission.
+	if ps.hitRegexp(updateObjectLatestRegexp, http.MethodPut) ||
+		ps.hitRegexp(updateObjectLifecycleLatestRegexp, http.MethodPut) {
+		if len(ps.RequestCtx.Elements) < 5 {
 			ps.err = errors.New("update object, but got invalid url")
 			return ps
 		}
@@ -1954,6 +2337,45 @@ func (ps *parseStream) objectLatest() *parseStream {
 		return ps
 	}
 
+	// export models as a model bundle, which reads the models like finding them with total info.
+	if ps.hitPattern(exportModelBundleLatestPattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.Model,
+					Action: meta.FindMany,
+				},
+			},
+		}
+		return ps
+	}
+
+	// preview the installation of a model bundle, which only reads the existing models.
+	if ps.hitPattern(previewInstallModelBundlePattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.Model,
+					Action: meta.SkipAction,
+				},
+			},
+		}
+		return ps
+	}
+
+	// install a model bundle, which creates the models in it like the yaml import.
+	if ps.hitPattern(installModelBundleLatestPattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.Model,
+					Action: meta.Create,
+				},
+			},
+		}
+		return ps
+	}
+
 	// find object's topology operation.
 	if ps.hitPattern(findObjectTopologyLatestPattern, http.MethodPost) {
 		bizID, err := ps.RequestCtx.getBizIDFromBody()
@@ -2368,14 +2790,31 @@ const (
 	createObjectAttributeLatestPattern   = "/api/v3/create/objectattr"
 	findObjectAttributeLatestPattern     = "/api/v3/find/objectattr"
 	findHostObjectAttributeLatestPattern = "/api/v3/find/objectattr/host"
+	lockObjectAttributeLatestPattern     = "/api/v3/update/objectattr/lock"
+	setObjectAttrOwnerLatestPattern      = "/api/v3/update/objectattr/owner"
 )
 
 var (
 	deleteObjectAttributeLatestRegexp      = regexp.MustCompile(`^/api/v3/delete/objectattr/[0-9]+/?$`)
+	deleteObjectAttrCascadeLatestRegexp    = regexp.MustCompile(`^/api/v3/delete/objectattr/[0-9]+/cascade/?$`)
+	previewDeleteObjectAttrLatestRegexp    = regexp.MustCompile(`^/api/v3/find/objectattr/[0-9]+/delete/preview/?$`)
 	updateObjectAttributeLatestRegexp      = regexp.MustCompile(`^/api/v3/update/objectattr/[0-9]+/?$`)
 	updateObjectAttributeIndexLatestRegexp = regexp.MustCompile(`^/api/v3/update/objectattr/index/[^\s/]+/[0-9]+/?$`)
 	createBizCustomFieldLatestRegexp       = regexp.MustCompile(`^/api/v3/create/objectattr/biz/[0-9]+/?$`)
 	updateBizCustomFieldLatestRegexp       = regexp.MustCompile(`^/api/v3/update/objectattr/biz/[0-9]+/id/[0-9]+/?$`)
+	updateBizFieldLayoutLatestRegexp       = regexp.MustCompile(`^/api/v3/update/objectattr/biz/[0-9]+/layout/[^\s/]+/?$`)
+	deleteBizFieldLayoutLatestRegexp       = regexp.MustCompile(`^/api/v3/delete/objectattr/biz/[0-9]+/layout/[^\s/]+/?$`)
+	findBizFieldLayoutLatestRegexp         = regexp.MustCompile(`^/api/v3/find/objectattr/biz/[0-9]+/layout/[^\s/]+/?$`)
+	updateAttrGroupDelegationLatestRegexp  = regexp.MustCompile(
+		`^/api/v3/update/objectattr/group/delegation/object/[^\s/]+/?$`)
+	deleteAttrGroupDelegationLatestRegexp = regexp.MustCompile(
+		`^/api/v3/delete/objectattr/group/delegation/object/[^\s/]+/?$`)
+	findAttrGroupDelegationLatestRegexp = regexp.MustCompile(
+		`^/api/v3/findmany/objectattr/group/delegation/object/[^\s/]+/?$`)
+	findAttributeEditSchemaLatestRegexp  = regexp.MustCompile(`^/api/v3/find/objectattr/edit_schema/object/[^\s/]+/?$`)
+	findAttributeUsageLatestRegexp       = regexp.MustCompile(`^/api/v3/find/objectattr/usage/object/[^\s/]+/?$`)
+	findEnumCascadeOptionsLatestRegexp   = regexp.MustCompile(`^/api/v3/find/objectattr/enum_cascade/object/[^\s/]+/?$`)
+	updateAttributeLifecycleLatestRegexp = regexp.MustCompile(`^/api/v3/update/objectattr/[0-9]+/lifecycle/?$`)
 )
 
 func (ps *parseStream) objectAttributeLatest() *parseStream {
@@ -2420,9 +2859,12 @@ func (ps *parseStream) objectAttributeLatest() *parseStream {
 		return ps
 	}
 
-	// delete object's attribute operation.
-	if ps.hitRegexp(deleteObjectAttributeLatestRegexp, http.MethodDelete) {
-		if len(ps.RequestCtx.Elements) != 5 {
+	// delete object's attribute operation, or delete it with cascade policy, preview the deletion needs the same
+	// permission.
+	isCascade := ps.hitRegexp(deleteObjectAttrCascadeLatestRegexp, http.MethodDelete) ||
+		ps.hitRegexp(previewDeleteObjectAttrLatestRegexp, http.MethodPost)
+	if isCascade || ps.hitRegexp(deleteObjectAttributeLatestRegexp, http.MethodDelete) {
+		if (!isCascade && len(ps.RequestCtx.Elements) != 5) || (isCascade && len(ps.RequestCtx.Elements) < 6) {
 			ps.err = errors.New("delete object attribute, but got invalid url")
 			return ps
 		}
@@ -2465,9 +2907,10 @@ func (ps *parseStream) objectAttributeLatest() *parseStream {
 		return ps
 	}
 
-	// update object attribute operation
-	if ps.hitRegexp(updateObjectAttributeLatestRegexp, http.MethodPut) {
-		if len(ps.RequestCtx.Elements) != 5 {
+	// update object attribute operation, update the attribute's lifecycle needs the same permission.
+	if ps.hitRegexp(updateObjectAttributeLatestRegexp, http.MethodPut) ||
+		ps.hitRegexp(updateAttributeLifecycleLatestRegexp, http.MethodPut) {
+		if len(ps.RequestCtx.Elements) < 5 {
 			ps.err = errors.New("update object attribute, but got invalid url")
 			return ps
 		}
@@ -2510,6 +2953,61 @@ func (ps *parseStream) objectAttributeLatest() *parseStream {
 		return ps
 	}
 
+	// lock object attributes or set their owner operation, the attributes are locked or owned by the platform, so the
+	// business custom fields are authorized as the global attributes too.
+	if ps.hitPattern(lockObjectAttributeLatestPattern, http.MethodPut) ||
+		ps.hitPattern(setObjectAttrOwnerLatestPattern, http.MethodPut) {
+		val, err := ps.RequestCtx.getValueFromBody("ids")
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		attrIDs := make([]int64, 0)
+		for _, id := range val.Array() {
+			attrIDs = append(attrIDs, id.Int())
+		}
+
+		if len(attrIDs) == 0 {
+			ps.err = errors.New("lock object attribute or set its owner, but got empty attribute ids")
+			return ps
+		}
+
+		attrs, err := ps.getModelAttribute(mapstr.MapStr{common.BKFieldID: mapstr.MapStr{common.BKDBIN: attrIDs}})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		objIDs := make([]string, 0)
+		for _, attr := range attrs {
+			objIDs = append(objIDs, attr.ObjectID)
+		}
+
+		models, err := ps.searchModels(mapstr.MapStr{common.BKObjIDField: mapstr.MapStr{common.BKDBIN: objIDs}})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		modelIDs := make(map[string]int64, len(models))
+		for _, model := range models {
+			modelIDs[model.ObjectID] = model.ID
+		}
+
+		for _, attr := range attrs {
+			ps.Attribute.Resources = append(ps.Attribute.Resources, meta.ResourceAttribute{
+				Basic: meta.Basic{
+					Type:       meta.ModelAttribute,
+					Action:     meta.Update,
+					InstanceID: attr.ID,
+				},
+				Layers: []meta.Item{{Type: meta.Model, InstanceID: modelIDs[attr.ObjectID]}},
+			})
+		}
+		return ps
+	}
+
 	// update object attribute index operation
 	if ps.hitRegexp(updateObjectAttributeIndexLatestRegexp, http.MethodPost) {
 		if len(ps.RequestCtx.Elements) != 7 {
@@ -2694,12 +3192,124 @@ func (ps *parseStream) objectAttributeLatest() *parseStream {
 		return ps
 	}
 
+	// save or delete the business's field layout of the model, which is managed like the business custom fields
+	isDeleteLayout := ps.hitRegexp(deleteBizFieldLayoutLatestRegexp, http.MethodDelete)
+	if isDeleteLayout || ps.hitRegexp(updateBizFieldLayoutLatestRegexp, http.MethodPut) {
+		if len(ps.RequestCtx.Elements) != 8 {
+			ps.err = errors.New("save business field layout, but got invalid url")
+			return ps
+		}
+
+		bizID, err := strconv.ParseInt(ps.RequestCtx.Elements[5], 10, 64)
+		if err != nil || bizID <= 0 {
+			ps.err = fmt.Errorf("save business field layout, but got invalid business id %s",
+				ps.RequestCtx.Elements[5])
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:   meta.ModelAttribute,
+					Action: meta.Update,
+				},
+			},
+		}
+		return ps
+	}
+
+	// find the business's field layout of the model
+	if ps.hitRegexp(findBizFieldLayoutLatestRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) != 8 {
+			ps.err = errors.New("find business field layout, but got invalid url")
+			return ps
+		}
+
+		bizID, err := strconv.ParseInt(ps.RequestCtx.Elements[5], 10, 64)
+		if err != nil || bizID <= 0 {
+			ps.err = fmt.Errorf("find business field layout, but got invalid business id %s",
+				ps.RequestCtx.Elements[5])
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				BusinessID: bizID,
+				Basic: meta.Basic{
+					Type:   meta.ModelAttribute,
+					Action: meta.FindMany,
+				},
+			},
+		}
+		return ps
+	}
+
+	// delegate the edit right of the model's attribute group to a role or delete the delegation, which is
+	// authorized as the model's update operation since only the model owners can delegate it.
+	isDeleteDelegation := ps.hitRegexp(deleteAttrGroupDelegationLatestRegexp, http.MethodDelete)
+	if isDeleteDelegation || ps.hitRegexp(updateAttrGroupDelegationLatestRegexp, http.MethodPut) {
+		if len(ps.RequestCtx.Elements) != 8 {
+			ps.err = errors.New("save attribute group delegation, but got invalid url")
+			return ps
+		}
+
+		model, err := ps.getOneModel(mapstr.MapStr{common.BKObjIDField: ps.RequestCtx.Elements[7]})
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:       meta.Model,
+					Action:     meta.Update,
+					InstanceID: model.ID,
+				},
+			},
+		}
+		return ps
+	}
+
+	// find the attribute group delegations, the attributes' edit schema or the cascade enum options of the model, they
+	// are readable by all the users so that the ui can render the instance forms.
+	if ps.hitRegexp(findAttrGroupDelegationLatestRegexp, http.MethodPost) ||
+		ps.hitRegexp(findAttributeEditSchemaLatestRegexp, http.MethodPost) ||
+		ps.hitRegexp(findEnumCascadeOptionsLatestRegexp, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.ModelAttribute,
+					Action: meta.SkipAction,
+				},
+			},
+		}
+		return ps
+	}
+
+	// find the attribute usages of the model, which is the analytics of the api traffic, so only the platform admins
+	// can find it.
+	if ps.hitRegexp(findAttributeUsageLatestRegexp, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.ConfigAdmin,
+					Action: meta.Update,
+				},
+			},
+		}
+		return ps
+	}
+
 	return ps
 }
 
 const (
 	createMainlineObjectLatestPattern   = "/api/v3/create/topomodelmainline"
 	findMainlineObjectTopoLatestPattern = "/api/v3/find/topomodelmainline"
+
+	findTopoLimitUsageLatestPattern = "/api/v3/find/topo/limit/usage"
 )
 
 var (
@@ -2759,6 +3369,19 @@ func (ps *parseStream) mainlineLatest() *parseStream {
 	}
 
 	// get mainline object operation
+	// find the usage of the business topology limits, which are managed in the platform setting.
+	if ps.hitPattern(findTopoLimitUsageLatestPattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.ConfigAdmin,
+					Action: meta.Find,
+				},
+			},
+		}
+		return ps
+	}
+
 	if ps.hitPattern(findMainlineObjectTopoLatestPattern, http.MethodPost) {
 		bizID, err := ps.RequestCtx.getBizIDFromBody()
 		if err != nil {
@@ -2914,3 +3537,120 @@ func (ps *parseStream) mainlineLatest() *parseStream {
 
 	return ps
 }
+
+var executeChangeBundleLatestRegexp = regexp.MustCompile(`^/api/v3/update/topo/change_bundle/biz/[0-9]+/?$`)
+
+func (ps *parseStream) changeBundleLatest() *parseStream {
+	if ps.shouldReturn() {
+		return ps
+	}
+
+	// execute change bundle operation, requires the permissions of all the changes in the bundle.
+	if ps.hitRegexp(executeChangeBundleLatestRegexp, http.MethodPost) {
+		if len(ps.RequestCtx.Elements) != 7 {
+			ps.err = errors.New("execute change bundle, but got invalid url")
+			return ps
+		}
+
+		bizID, err := strconv.ParseInt(ps.RequestCtx.Elements[6], 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("execute change bundle, but got invalid business id %s", ps.RequestCtx.Elements[6])
+			return ps
+		}
+
+		types, err := ps.RequestCtx.getValueFromBody("changes.#.type")
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		changeTypes := make(map[metadata.ChangeBundleChangeType]struct{})
+		types.ForEach(func(key, value gjson.Result) bool {
+			changeTypes[metadata.ChangeBundleChangeType(value.String())] = struct{}{}
+			return true
+		})
+
+		permissions := make([]meta.Basic, 0)
+		if _, exists := changeTypes[metadata.ChangeBundleCreateSet]; exists {
+			permissions = append(permissions, meta.Basic{Type: meta.ModelSet, Action: meta.Create})
+		}
+		if _, exists := changeTypes[metadata.ChangeBundleCreateModule]; exists {
+			permissions = append(permissions, meta.Basic{Type: meta.ModelModule, Action: meta.Create})
+		}
+		if _, exists := changeTypes[metadata.ChangeBundleTransferHost]; exists {
+			// same as transferring hosts in the business, which may create or delete the service instances
+			permissions = append(permissions,
+				meta.Basic{Type: meta.ProcessServiceInstance, Action: meta.Create},
+				meta.Basic{Type: meta.ProcessServiceInstance, Action: meta.Update},
+				meta.Basic{Type: meta.ProcessServiceInstance, Action: meta.Delete})
+		} else if _, exists := changeTypes[metadata.ChangeBundleCreateServiceInstance]; exists {
+			permissions = append(permissions, meta.Basic{Type: meta.ProcessServiceInstance, Action: meta.Create})
+		}
+
+		for _, permission := range permissions {
+			ps.Attribute.Resources = append(ps.Attribute.Resources, meta.ResourceAttribute{
+				BusinessID: bizID,
+				Basic:      permission,
+			})
+		}
+		return ps
+	}
+
+	return ps
+}
+
+var (
+	saveBizRoleLatestRegexp            = regexp.MustCompile(`^/api/v3/update/topo/biz_role/biz/[0-9]+/?$`)
+	deleteBizRoleLatestRegexp          = regexp.MustCompile(`^/api/v3/delete/topo/biz_role/biz/[0-9]+/?$`)
+	findBizRoleLatestRegexp            = regexp.MustCompile(`^/api/v3/findmany/topo/biz_role/biz/[0-9]+/?$`)
+	resolveBizRoleReceiverLatestRegexp = regexp.MustCompile(`^/api/v3/find/topo/biz_role/receivers/biz/[0-9]+/?$`)
+)
+
+func (ps *parseStream) bizRoleLatest() *parseStream {
+	if ps.shouldReturn() {
+		return ps
+	}
+
+	// assign the business role, same as updating the business.
+	isDelete := ps.hitRegexp(deleteBizRoleLatestRegexp, http.MethodDelete)
+	if isDelete || ps.hitRegexp(saveBizRoleLatestRegexp, http.MethodPut) {
+		if len(ps.RequestCtx.Elements) != 7 {
+			ps.err = errors.New("save business role, but got invalid url")
+			return ps
+		}
+
+		bizID, err := strconv.ParseInt(ps.RequestCtx.Elements[6], 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("save business role, but got invalid business id %s", ps.RequestCtx.Elements[6])
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:       meta.Business,
+					Action:     meta.Update,
+					InstanceID: bizID,
+				},
+			},
+		}
+		return ps
+	}
+
+	// find the business role assignments or the receivers, it is used by the notification systems, so skip it.
+	if ps.hitRegexp(findBizRoleLatestRegexp, http.MethodPost) ||
+		ps.hitRegexp(resolveBizRoleReceiverLatestRegexp, http.MethodPost) {
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.Business,
+					Action: meta.SkipAction,
+				},
+			},
+		}
+		return ps
+	}
+
+	return ps
+}
@@ -1330,6 +1330,8 @@ var (
 	searchAuditList   = `/api/v3/findmany/audit_list`
 	searchAuditDetail = `/api/v3/find/audit`
 	searchInstAudit   = `/api/v3/find/inst_audit`
+
+	searchAuditChangeSet = `/api/v3/find/audit/change_set`
 )
 
 func (ps *parseStream) audit() *parseStream {
@@ -1373,6 +1375,18 @@ func (ps *parseStream) audit() *parseStream {
 		return ps
 	}
 
+	if ps.hitPattern(searchAuditChangeSet, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.AuditLog,
+					Action: meta.Find,
+				},
+			},
+		}
+		return ps
+	}
+
 	if ps.hitPattern(searchInstAudit, http.MethodPost) {
 		query := new(metadata.InstAuditQueryInput)
 		body, err := ps.RequestCtx.getRequestBody()
@@ -1453,7 +1467,10 @@ func (ps *parseStream) audit() *parseStream {
 }
 
 var (
-	fullTextSearchPattern = "/api/v3/find/full_text"
+	fullTextSearchPattern       = "/api/v3/find/full_text"
+	startFullTextReindexPattern = "/api/v3/create/full_text/reindex"
+	findFullTextReindexPattern  = "/api/v3/find/full_text/reindex"
+	cancelFullTextReindexRegexp = regexp.MustCompile(`^/api/v3/update/full_text/reindex/[^\s/]+/cancel/?$`)
 )
 
 func (ps *parseStream) fullTextSearch() *parseStream {
@@ -1472,6 +1489,32 @@ func (ps *parseStream) fullTextSearch() *parseStream {
 		return ps
 	}
 
+	// rebuilding the fulltext indexes affects the whole platform, so only the platform admins can do it.
+	if ps.hitPattern(startFullTextReindexPattern, http.MethodPost) ||
+		ps.hitRegexp(cancelFullTextReindexRegexp, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.ConfigAdmin,
+					Action: meta.Update,
+				},
+			},
+		}
+		return ps
+	}
+
+	if ps.hitPattern(findFullTextReindexPattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.ConfigAdmin,
+					Action: meta.Find,
+				},
+			},
+		}
+		return ps
+	}
+
 	return ps
 }
 
@@ -1480,11 +1523,15 @@ const (
 	createCloudAreaPattern        = "/api/v3/create/cloudarea"
 	createManyCloudAreaPattern    = "/api/v3/createmany/cloudarea"
 	findCloudAreaHostCountPattern = "/api/v3/findmany/cloudarea/hostcount"
+	findCloudAreaUsagePattern     = "/api/v3/findmany/cloudarea/usage"
+	mergeCloudAreaPattern         = "/api/v3/updatemany/cloudarea/merge"
 )
 
 var (
 	updateCloudAreaRegexp = regexp.MustCompile(`^/api/v3/update/cloudarea/[0-9]+/?$`)
 	deleteCloudAreaRegexp = regexp.MustCompile(`^/api/v3/delete/cloudarea/[0-9]+/?$`)
+	// relocate all the hosts of the cloud area to the target cloud area
+	relocateCloudAreaHostsRegexp = regexp.MustCompile(`^/api/v3/update/cloudarea/[0-9]+/relocate/?$`)
 )
 
 func (ps *parseStream) cloudArea() *parseStream {
@@ -1578,5 +1625,87 @@ func (ps *parseStream) cloudArea() *parseStream {
 		return ps
 	}
 
+	if ps.hitPattern(findCloudAreaUsagePattern, http.MethodPost) {
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:   meta.CloudAreaInstance,
+					Action: meta.SkipAction,
+				},
+			},
+		}
+		return ps
+	}
+
+	// relocate hosts, authorize if user has update permission to both the source and the target cloud area
+	if ps.hitRegexp(relocateCloudAreaHostsRegexp, http.MethodPut) {
+		id, err := strconv.ParseInt(ps.RequestCtx.Elements[4], 10, 64)
+		if err != nil {
+			ps.err = fmt.Errorf("parse cloud id %s failed", ps.RequestCtx.Elements[4])
+			return ps
+		}
+
+		targetIDVal, err := ps.RequestCtx.getValueFromBody("target_bk_cloud_id")
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:       meta.CloudAreaInstance,
+					Action:     meta.Update,
+					InstanceID: id,
+				},
+			},
+			{
+				Basic: meta.Basic{
+					Type:       meta.CloudAreaInstance,
+					Action:     meta.Update,
+					InstanceID: targetIDVal.Int(),
+				},
+			},
+		}
+		return ps
+	}
+
+	// merge cloud areas, authorize if user has delete permission to the source cloud areas and update permission to
+	// the target cloud area
+	if ps.hitPattern(mergeCloudAreaPattern, http.MethodPost) {
+		sourceIDsVal, err := ps.RequestCtx.getValueFromBody("source_bk_cloud_ids")
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		targetIDVal, err := ps.RequestCtx.getValueFromBody("target_bk_cloud_id")
+		if err != nil {
+			ps.err = err
+			return ps
+		}
+
+		ps.Attribute.Resources = []meta.ResourceAttribute{
+			{
+				Basic: meta.Basic{
+					Type:       meta.CloudAreaInstance,
+					Action:     meta.Update,
+					InstanceID: targetIDVal.Int(),
+				},
+			},
+		}
+
+		for _, sourceIDVal := range sourceIDsVal.Array() {
+			ps.Attribute.Resources = append(ps.Attribute.Resources, meta.ResourceAttribute{
+				Basic: meta.Basic{
+					Type:       meta.CloudAreaInstance,
+					Action:     meta.Delete,
+					InstanceID: sourceIDVal.Int(),
+				},
+			})
+		}
+		return ps
+	}
+
 	return ps
 }
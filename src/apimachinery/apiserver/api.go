@@ -323,6 +323,94 @@ func (a *apiServer) AddInstByImport(ctx context.Context, h http.Header, ownerID,
 	return resp, nil
 }
 
+// CreateInstImportTask create the instance import job, returns the task id
+func (a *apiServer) CreateInstImportTask(ctx context.Context, h http.Header, objID string, params mapstr.MapStr) (
+	string, error) {
+
+	resp := new(metadata.InstTaskResult)
+	err := a.client.Post().
+		WithContext(ctx).
+		Body(params).
+		SubResourcef("/create/instance/object/%s/by_import/task", objID).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return "", err
+	}
+
+	if ccErr := resp.CCError(); ccErr != nil {
+		return "", ccErr
+	}
+	return resp.Data.TaskID, nil
+}
+
+// CreateInstExportTask create the instance export job, returns the task id
+func (a *apiServer) CreateInstExportTask(ctx context.Context, h http.Header, objID string,
+	params *metadata.InstExportTaskData) (string, error) {
+
+	resp := new(metadata.InstTaskResult)
+	err := a.client.Post().
+		WithContext(ctx).
+		Body(params).
+		SubResourcef("/create/instance/object/%s/export/task", objID).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return "", err
+	}
+
+	if ccErr := resp.CCError(); ccErr != nil {
+		return "", ccErr
+	}
+	return resp.Data.TaskID, nil
+}
+
+// GetTaskDetail get the task detail
+func (a *apiServer) GetTaskDetail(ctx context.Context, h http.Header, taskID string) (*metadata.APITaskDetail,
+	error) {
+
+	resp := new(metadata.TaskDetailResponse)
+	err := a.client.Post().
+		WithContext(ctx).
+		SubResourcef("/task/findone/detail/%s", taskID).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ccErr := resp.CCError(); ccErr != nil {
+		return nil, ccErr
+	}
+	return &resp.Data.Info, nil
+}
+
+// ListTaskFile list the file chunks generated by the finished task in order
+func (a *apiServer) ListTaskFile(ctx context.Context, h http.Header, taskID string) ([]metadata.APITaskFile, error) {
+	resp := new(metadata.ListAPITaskFileResponse)
+	err := a.client.Post().
+		WithContext(ctx).
+		SubResourcef("/task/findmany/file/%s", taskID).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ccErr := resp.CCError(); ccErr != nil {
+		return nil, ccErr
+	}
+	return resp.Data, nil
+}
+
 // AddObjectBatch TODO
 func (a *apiServer) AddObjectBatch(ctx context.Context, h http.Header, params mapstr.MapStr) (resp *metadata.Response, err error) {
 	resp = new(metadata.Response)
@@ -635,3 +723,92 @@ func (a *apiServer) SearchCloudArea(ctx context.Context, h http.Header, params m
 
 	return &resp.Data, nil
 }
+
+// CreateModelAsset create the model asset whose content is put into the blob store
+func (a *apiServer) CreateModelAsset(ctx context.Context, h http.Header, opt *metadata.CreateModelAssetOption) (
+	*metadata.ModelAsset, error) {
+
+	resp := new(metadata.ModelAssetResp)
+	err := a.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef("/create/model/asset").
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ccErr := resp.CCError(); ccErr != nil {
+		return nil, ccErr
+	}
+	return resp.Data, nil
+}
+
+// AddModelAssetVersion add a new version to the model asset
+func (a *apiServer) AddModelAssetVersion(ctx context.Context, h http.Header, id int64,
+	opt *metadata.AddModelAssetVersionOption) (*metadata.AddModelAssetVersionResult, error) {
+
+	resp := new(metadata.AddModelAssetVersionResp)
+	err := a.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef("/create/model/asset/%d/version", id).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ccErr := resp.CCError(); ccErr != nil {
+		return nil, ccErr
+	}
+	return resp.Data, nil
+}
+
+// SearchModelAsset search the model assets
+func (a *apiServer) SearchModelAsset(ctx context.Context, h http.Header, opt *metadata.SearchModelAssetOption) (
+	*metadata.SearchModelAssetResult, error) {
+
+	resp := new(metadata.SearchModelAssetResp)
+	err := a.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef("/findmany/model/asset").
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ccErr := resp.CCError(); ccErr != nil {
+		return nil, ccErr
+	}
+	return resp.Data, nil
+}
+
+// DeleteModelAsset delete the model asset, returns the deleted asset
+func (a *apiServer) DeleteModelAsset(ctx context.Context, h http.Header, id int64) (*metadata.ModelAsset, error) {
+	resp := new(metadata.ModelAssetResp)
+	err := a.client.Delete().
+		WithContext(ctx).
+		SubResourcef("/delete/model/asset/%d", id).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ccErr := resp.CCError(); ccErr != nil {
+		return nil, ccErr
+	}
+	return resp.Data, nil
+}
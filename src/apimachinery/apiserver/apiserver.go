@@ -52,6 +52,11 @@ type ApiServerClientInterface interface {
 	AddInst(ctx context.Context, h http.Header, ownerID, objID string, params mapstr.MapStr) (resp *metadata.ResponseDataMapStr, err error)
 	AddInstByImport(ctx context.Context, h http.Header, ownerID, objID string, params mapstr.MapStr) (
 		*metadata.ResponseDataMapStr, error)
+	CreateInstImportTask(ctx context.Context, h http.Header, objID string, params mapstr.MapStr) (string, error)
+	CreateInstExportTask(ctx context.Context, h http.Header, objID string,
+		params *metadata.InstExportTaskData) (string, error)
+	GetTaskDetail(ctx context.Context, h http.Header, taskID string) (*metadata.APITaskDetail, error)
+	ListTaskFile(ctx context.Context, h http.Header, taskID string) ([]metadata.APITaskFile, error)
 	AddObjectBatch(ctx context.Context, h http.Header, params mapstr.MapStr) (resp *metadata.Response, err error)
 	SearchAssociationInst(ctx context.Context, h http.Header, request *metadata.SearchAssociationInstRequest) (resp *metadata.SearchAssociationInstResult, err error)
 	ImportAssociation(ctx context.Context, h http.Header, objID string, input *metadata.RequestImportAssociation) (resp *metadata.ResponeImportAssociation, err error)
@@ -93,6 +98,14 @@ type ApiServerClientInterface interface {
 
 	SearchCloudArea(ctx context.Context, h http.Header, params metadata.CloudAreaSearchParam) (
 		*metadata.SearchDataResult, error)
+
+	CreateModelAsset(ctx context.Context, h http.Header, opt *metadata.CreateModelAssetOption) (*metadata.ModelAsset,
+		error)
+	AddModelAssetVersion(ctx context.Context, h http.Header, id int64, opt *metadata.AddModelAssetVersionOption) (
+		*metadata.AddModelAssetVersionResult, error)
+	SearchModelAsset(ctx context.Context, h http.Header, opt *metadata.SearchModelAssetOption) (
+		*metadata.SearchModelAssetResult, error)
+	DeleteModelAsset(ctx context.Context, h http.Header, id int64) (*metadata.ModelAsset, error)
 }
 
 // NewApiServerClientInterface TODO
@@ -24,6 +24,8 @@ import (
 // Interface TODO
 type Interface interface {
 	WatchEvent(ctx context.Context, h http.Header, opts *watch.WatchEventOptions) (*string, errors.CCErrorCoder)
+	ListChanges(ctx context.Context, h http.Header, opts *watch.ListChangesOptions) (*watch.ListChangesResult,
+		errors.CCErrorCoder)
 }
 
 // NewCacheClient TODO
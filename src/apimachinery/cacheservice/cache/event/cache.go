@@ -18,6 +18,7 @@ import (
 
 	"configcenter/src/common"
 	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
 	"configcenter/src/common/watch"
 )
 
@@ -42,3 +43,30 @@ func (e *eventCache) WatchEvent(ctx context.Context, h http.Header, opts *watch.
 	}
 	return &resp.Data, nil
 }
+
+// ListChanges list the ids of the resources changed since the token
+func (e *eventCache) ListChanges(ctx context.Context, h http.Header, opts *watch.ListChangesOptions) (
+	*watch.ListChangesResult, errors.CCErrorCoder) {
+
+	resp := &struct {
+		metadata.BaseResp `json:",inline"`
+		Data              *watch.ListChangesResult `json:"data"`
+	}{}
+
+	err := e.client.Post().
+		WithContext(ctx).
+		Body(opts).
+		SubResourcef("/watch/cache/changes").
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.New(common.CCErrCommHTTPDoRequestFailed, err.Error())
+	}
+
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
@@ -28,6 +28,7 @@ import (
 	"configcenter/src/apimachinery/taskserver"
 	"configcenter/src/apimachinery/toposerver"
 	"configcenter/src/apimachinery/util"
+	"configcenter/src/common/types"
 )
 
 // ClientSetInterface TODO
@@ -57,7 +58,13 @@ func NewApiMachinery(c *util.APIMachineryConfig, discover discovery.DiscoveryInt
 	}
 
 	flowcontrol := flowctrl.NewRateLimiter(c.QPS, c.Burst)
-	return NewClientSet(client, discover, flowcontrol), nil
+	return &ClientSet{
+		version:  "v3",
+		client:   client,
+		discover: discover,
+		throttle: flowcontrol,
+		guards:   flowctrl.NewGuardSet(c.GuardConfig, c.MetricRegister),
+	}, nil
 }
 
 // NewClientSet TODO
@@ -88,6 +95,9 @@ type ClientSet struct {
 	discover discovery.DiscoveryInterface
 	throttle flowctrl.RateLimiter
 	Mock     util.MockInfo
+
+	// guards the circuit breakers and bulkheads of the destination services
+	guards *flowctrl.GuardSet
 }
 
 // HostServer TODO
@@ -96,6 +106,7 @@ func (cs *ClientSet) HostServer() hostserver.HostServerClientInterface {
 		Client:   cs.client,
 		Discover: cs.discover.HostServer(),
 		Throttle: cs.throttle,
+		Guard:    cs.guards.Get(types.CC_MODULE_HOST),
 		Mock:     cs.Mock,
 	}
 	cs.Mock.SetMockData = false
@@ -108,6 +119,7 @@ func (cs *ClientSet) TopoServer() toposerver.TopoServerClientInterface {
 		Client:   cs.client,
 		Discover: cs.discover.TopoServer(),
 		Throttle: cs.throttle,
+		Guard:    cs.guards.Get(types.CC_MODULE_TOPO),
 		Mock:     cs.Mock,
 	}
 	cs.Mock.SetMockData = false
@@ -120,6 +132,7 @@ func (cs *ClientSet) ProcServer() procserver.ProcServerClientInterface {
 		Client:   cs.client,
 		Discover: cs.discover.ProcServer(),
 		Throttle: cs.throttle,
+		Guard:    cs.guards.Get(types.CC_MODULE_PROC),
 	}
 	cs.Mock.SetMockData = false
 	return procserver.NewProcServerClientInterface(c, cs.version)
@@ -131,6 +144,7 @@ func (cs *ClientSet) AdminServer() adminserver.AdminServerClientInterface {
 		Client:   cs.client,
 		Discover: cs.discover.MigrateServer(),
 		Throttle: cs.throttle,
+		Guard:    cs.guards.Get(types.CC_MODULE_MIGRATE),
 		Mock:     cs.Mock,
 	}
 	cs.Mock.SetMockData = false
@@ -143,6 +157,7 @@ func (cs *ClientSet) ApiServer() apiserver.ApiServerClientInterface {
 		Client:   cs.client,
 		Discover: cs.discover.ApiServer(),
 		Throttle: cs.throttle,
+		Guard:    cs.guards.Get(types.CC_MODULE_APISERVER),
 	}
 	return apiserver.NewApiServerClientInterface(c, cs.version)
 }
@@ -153,6 +168,7 @@ func (cs *ClientSet) EventServer() eventserver.EventServerClientInterface {
 		Client:   cs.client,
 		Discover: cs.discover.EventServer(),
 		Throttle: cs.throttle,
+		Guard:    cs.guards.Get(types.CC_MODULE_EVENTSERVER),
 		Mock:     cs.Mock,
 	}
 	cs.Mock.SetMockData = false
@@ -174,6 +190,7 @@ func (cs *ClientSet) CoreService() coreservice.CoreServiceClientInterface {
 		Client:   cs.client,
 		Discover: cs.discover.CoreService(),
 		Throttle: cs.throttle,
+		Guard:    cs.guards.Get(types.CC_MODULE_CORESERVICE),
 		Mock:     cs.Mock,
 	}
 	return coreservice.NewCoreServiceClient(c, cs.version)
@@ -185,6 +202,7 @@ func (cs *ClientSet) TaskServer() taskserver.TaskServerClientInterface {
 		Client:   cs.client,
 		Discover: cs.discover.TaskServer(),
 		Throttle: cs.throttle,
+		Guard:    cs.guards.Get(types.CC_MODULE_TASK),
 		Mock:     cs.Mock,
 	}
 	return taskserver.NewProcServerClientInterface(c, cs.version)
@@ -196,6 +214,7 @@ func (cs *ClientSet) CloudServer() cloudserver.CloudServerClientInterface {
 		Client:   cs.client,
 		Discover: cs.discover.CloudServer(),
 		Throttle: cs.throttle,
+		Guard:    cs.guards.Get(types.CC_MODULE_CLOUD),
 		Mock:     cs.Mock,
 	}
 	return cloudserver.NewCloudServerClientInterface(c, cs.version)
@@ -207,6 +226,7 @@ func (cs *ClientSet) AuthServer() authserver.AuthServerClientInterface {
 		Client:   cs.client,
 		Discover: cs.discover.AuthServer(),
 		Throttle: cs.throttle,
+		Guard:    cs.guards.Get(types.CC_MODULE_AUTH),
 		Mock:     cs.Mock,
 	}
 	return authserver.NewAuthServerClientInterface(c, cs.version)
@@ -218,6 +238,7 @@ func (cs *ClientSet) CacheService() cacheservice.CacheServiceClientInterface {
 		Client:   cs.client,
 		Discover: cs.discover.CacheService(),
 		Throttle: cs.throttle,
+		Guard:    cs.guards.Get(types.CC_MODULE_CACHESERVICE),
 		Mock:     cs.Mock,
 	}
 	return cacheservice.NewCacheServiceClient(c, cs.version)
@@ -489,6 +489,35 @@ func (asst *association) CountInstanceAssociations(ctx context.Context, header h
 	return &resp.Data, nil
 }
 
+// ScanAsstMappingViolation scans the instance associations that violate the association mapping.
+func (asst *association) ScanAsstMappingViolation(ctx context.Context, h http.Header,
+	opt *metadata.ScanAsstMappingViolationOption) (*metadata.AsstMappingViolationResult, errors.CCErrorCoder) {
+
+	resp := &struct {
+		metadata.BaseResp `json:",inline"`
+		Data              *metadata.AsstMappingViolationResult `json:"data"`
+	}{}
+	subPath := "/find/instanceassociation/mapping_violation"
+
+	err := asst.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
 // DeleteInstAssociation api of delete object instances associationS
 func (asst *association) DeleteInstAssociation(ctx context.Context, h http.Header,
 	input *metadata.InstAsstDeleteOption) (*metadata.DeletedCount, error) {
@@ -18,6 +18,7 @@ import (
 	"net/http"
 
 	"configcenter/src/apimachinery/rest"
+	"configcenter/src/common/errors"
 	"configcenter/src/common/metadata"
 )
 
@@ -70,6 +71,10 @@ type AssociationClientInterface interface {
 	// CountInstanceAssociations counts model instance associations num.
 	CountInstanceAssociations(ctx context.Context, header http.Header, objID string, input *metadata.Condition) (
 		*metadata.CountResponseContent, error)
+
+	// ScanAsstMappingViolation scans the instance associations that violate the association mapping.
+	ScanAsstMappingViolation(ctx context.Context, h http.Header, opt *metadata.ScanAsstMappingViolationOption) (
+		*metadata.AsstMappingViolationResult, errors.CCErrorCoder)
 }
 
 // NewAssociationClientInterface TODO
@@ -70,3 +70,25 @@ func (inst *auditlog) SearchAuditLog(ctx context.Context, h http.Header, param m
 
 	return resp.Data, nil
 }
+
+// RepointAuditLog api of moving the audit logs of a resource to another resource of the same type
+func (inst *auditlog) RepointAuditLog(ctx context.Context, h http.Header,
+	opt *metadata.RepointAuditLogOption) errors.CCErrorCoder {
+
+	resp := new(metadata.BaseResp)
+	subPath := "/update/auditlog/repoint"
+
+	err := inst.client.Put().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+
+	return resp.CCError()
+}
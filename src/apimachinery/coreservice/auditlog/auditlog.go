@@ -27,6 +27,7 @@ type AuditClientInterface interface {
 	SaveAuditLog(ctx context.Context, h http.Header, logs ...metadata.AuditLog) errors.CCErrorCoder
 	SearchAuditLog(ctx context.Context, h http.Header, param metadata.QueryCondition) (*metadata.AuditQueryResult,
 		errors.CCErrorCoder)
+	RepointAuditLog(ctx context.Context, h http.Header, opt *metadata.RepointAuditLogOption) errors.CCErrorCoder
 }
 
 // NewAuditClientInterface TODO
@@ -125,6 +125,26 @@ func (h *host) DeleteHostFromSystem(ctx context.Context, header http.Header,
 	return resp.CCError()
 }
 
+// MergeHost move the source host's relations to the target host and delete the source host
+func (h *host) MergeHost(ctx context.Context, header http.Header, input *metadata.MergeHostOption) errors.CCErrorCoder {
+	resp := new(metadata.BaseResp)
+	subPath := "/update/host/merge"
+
+	err := h.client.Put().
+		WithContext(ctx).
+		Body(input).
+		SubResourcef(subPath).
+		WithHeaders(header).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+
+	return resp.CCError()
+}
+
 // GetHostModuleRelation get host module relation
 func (h *host) GetHostModuleRelation(ctx context.Context, header http.Header,
 	input *metadata.HostModuleRelationRequest) (*metadata.HostConfigData, error) {
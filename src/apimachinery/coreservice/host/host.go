@@ -33,6 +33,7 @@ type HostClientInterface interface {
 	RemoveFromModule(ctx context.Context, header http.Header, input *metadata.RemoveHostsFromModuleOption) (
 		resp *metadata.OperaterException, err error)
 	DeleteHostFromSystem(ctx context.Context, header http.Header, input *metadata.DeleteHostRequest) errors.CCErrorCoder
+	MergeHost(ctx context.Context, header http.Header, input *metadata.MergeHostOption) errors.CCErrorCoder
 
 	GetHostModuleRelation(ctx context.Context, header http.Header, input *metadata.HostModuleRelationRequest) (
 		*metadata.HostConfigData, error)
@@ -102,6 +103,21 @@ type HostClientInterface interface {
 
 	TransferHostResourceDirectory(ctx context.Context, header http.Header,
 		option *metadata.TransferHostResourceDirectory) errors.CCErrorCoder
+
+	// host recycle policy interfaces.
+	CreateHostRecyclePolicy(ctx context.Context, header http.Header, data *metadata.HostRecyclePolicy) (
+		*metadata.HostRecyclePolicy, errors.CCErrorCoder)
+	UpdateHostRecyclePolicy(ctx context.Context, header http.Header, id int64,
+		data map[string]interface{}) errors.CCErrorCoder
+	DeleteHostRecyclePolicy(ctx context.Context, header http.Header, id int64) errors.CCErrorCoder
+	SearchHostRecyclePolicy(ctx context.Context, header http.Header, data *metadata.QueryCondition) (
+		*metadata.MultipleHostRecyclePolicy, errors.CCErrorCoder)
+	CreateHostRecycleReport(ctx context.Context, header http.Header, data *metadata.HostRecycleReport) (
+		*metadata.HostRecycleReport, errors.CCErrorCoder)
+	UpdateHostRecycleReport(ctx context.Context, header http.Header, id int64,
+		data map[string]interface{}) errors.CCErrorCoder
+	SearchHostRecycleReport(ctx context.Context, header http.Header, data *metadata.QueryCondition) (
+		*metadata.MultipleHostRecycleReport, errors.CCErrorCoder)
 }
 
 // NewHostClientInterface TODO
@@ -0,0 +1,179 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"context"
+	"net/http"
+
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+)
+
+// CreateHostRecyclePolicy create a host recycle policy
+func (h *host) CreateHostRecyclePolicy(ctx context.Context, header http.Header, data *metadata.HostRecyclePolicy) (
+	*metadata.HostRecyclePolicy, errors.CCErrorCoder) {
+
+	resp := new(metadata.HostRecyclePolicyResult)
+	err := h.client.Post().
+		WithContext(ctx).
+		Body(data).
+		SubResourcef("/create/host_recycle_policy").
+		WithHeaders(header).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
+// UpdateHostRecyclePolicy update the host recycle policy
+func (h *host) UpdateHostRecyclePolicy(ctx context.Context, header http.Header, id int64,
+	data map[string]interface{}) errors.CCErrorCoder {
+
+	resp := new(metadata.BaseResp)
+	err := h.client.Put().
+		WithContext(ctx).
+		Body(data).
+		SubResourcef("/update/host_recycle_policy/%d", id).
+		WithHeaders(header).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteHostRecyclePolicy delete the host recycle policy
+func (h *host) DeleteHostRecyclePolicy(ctx context.Context, header http.Header, id int64) errors.CCErrorCoder {
+	resp := new(metadata.BaseResp)
+	err := h.client.Delete().
+		WithContext(ctx).
+		SubResourcef("/delete/host_recycle_policy/%d", id).
+		WithHeaders(header).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SearchHostRecyclePolicy search the host recycle policies
+func (h *host) SearchHostRecyclePolicy(ctx context.Context, header http.Header, data *metadata.QueryCondition) (
+	*metadata.MultipleHostRecyclePolicy, errors.CCErrorCoder) {
+
+	resp := new(metadata.MultipleHostRecyclePolicyResult)
+	err := h.client.Post().
+		WithContext(ctx).
+		Body(data).
+		SubResourcef("/findmany/host_recycle_policy").
+		WithHeaders(header).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
+// CreateHostRecycleReport create a host recycle report
+func (h *host) CreateHostRecycleReport(ctx context.Context, header http.Header, data *metadata.HostRecycleReport) (
+	*metadata.HostRecycleReport, errors.CCErrorCoder) {
+
+	resp := new(metadata.HostRecycleReportResult)
+	err := h.client.Post().
+		WithContext(ctx).
+		Body(data).
+		SubResourcef("/create/host_recycle_report").
+		WithHeaders(header).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
+// UpdateHostRecycleReport update the review result of the host recycle report
+func (h *host) UpdateHostRecycleReport(ctx context.Context, header http.Header, id int64,
+	data map[string]interface{}) errors.CCErrorCoder {
+
+	resp := new(metadata.BaseResp)
+	err := h.client.Put().
+		WithContext(ctx).
+		Body(data).
+		SubResourcef("/update/host_recycle_report/%d", id).
+		WithHeaders(header).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SearchHostRecycleReport search the host recycle reports
+func (h *host) SearchHostRecycleReport(ctx context.Context, header http.Header, data *metadata.QueryCondition) (
+	*metadata.MultipleHostRecycleReport, errors.CCErrorCoder) {
+
+	resp := new(metadata.MultipleHostRecycleReportResult)
+	err := h.client.Post().
+		WithContext(ctx).
+		Body(data).
+		SubResourcef("/findmany/host_recycle_report").
+		WithHeaders(header).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
@@ -48,6 +48,32 @@ func (inst *instance) CreateInstance(ctx context.Context, h http.Header, objID s
 	return &resp.Data, nil
 }
 
+// CreateOrUpdateInstance create the instance, or update the instance with the same natural key
+func (inst *instance) CreateOrUpdateInstance(ctx context.Context, h http.Header, objID string,
+	input *metadata.CreateOrUpdateModelInstance) (*metadata.CreateOrUpdateInstResult, errors.CCErrorCoder) {
+
+	resp := new(metadata.CreateOrUpdateInstResp)
+	subPath := "/create_or_update/model/%s/instance"
+
+	err := inst.client.Post().
+		WithContext(ctx).
+		Body(input).
+		SubResourcef(subPath, objID).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
 // CreateManyInstance batch create instances
 func (inst *instance) CreateManyInstance(ctx context.Context, h http.Header, objID string,
 	input *metadata.CreateManyModelInstance) (*metadata.CreateManyDataResult, errors.CCErrorCoder) {
@@ -258,3 +284,99 @@ func (inst *instance) GetInstanceObjectMapping(ctx context.Context, header http.
 
 	return resp.Data, nil
 }
+
+// ReadImportConflict read the recorded conflicts of the concurrent imports, the newest conflicts are returned first
+func (inst *instance) ReadImportConflict(ctx context.Context, h http.Header, opt *metadata.SearchImportConflictOption) (
+	*metadata.ImportConflictResult, error) {
+
+	resp := new(metadata.ImportConflictResp)
+	subPath := "/read/instance/import/conflict"
+
+	err := inst.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err = resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// BindExternalIDs bind the external ids to the instance
+func (inst *instance) BindExternalIDs(ctx context.Context, h http.Header, objID string,
+	opt *metadata.BindExternalIDOption) error {
+
+	resp := new(metadata.BaseResp)
+	subPath := "/update/external_id/object/%s"
+
+	err := inst.client.Put().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath, objID).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+
+	return resp.CCError()
+}
+
+// UnbindExternalIDs unbind the external ids of the sources from the instance
+func (inst *instance) UnbindExternalIDs(ctx context.Context, h http.Header, objID string,
+	opt *metadata.UnbindExternalIDOption) error {
+
+	resp := new(metadata.BaseResp)
+	subPath := "/delete/external_id/object/%s"
+
+	err := inst.client.Delete().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath, objID).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+
+	return resp.CCError()
+}
+
+// SearchExternalIDs search the external ids of the instances, or look up the instances by the external ids
+func (inst *instance) SearchExternalIDs(ctx context.Context, h http.Header, objID string,
+	opt *metadata.SearchExternalIDOption) ([]metadata.ExternalID, errors.CCErrorCoder) {
+
+	resp := new(metadata.ExternalIDsResult)
+	subPath := "/findmany/external_id/object/%s"
+
+	err := inst.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath, objID).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
@@ -32,6 +32,9 @@ type InstanceClientInterface interface {
 		resp *metadata.SetOptionResult, err error)
 	UpdateInstance(ctx context.Context, h http.Header, objID string, input *metadata.UpdateOption) (
 		*metadata.UpdatedCount, error)
+	// CreateOrUpdateInstance create the instance, or update the instance with the same natural key
+	CreateOrUpdateInstance(ctx context.Context, h http.Header, objID string,
+		input *metadata.CreateOrUpdateModelInstance) (*metadata.CreateOrUpdateInstResult, errors.CCErrorCoder)
 	ReadInstance(ctx context.Context, h http.Header, objID string, input *metadata.QueryCondition) (
 		*metadata.InstDataInfo, error)
 	DeleteInstance(ctx context.Context, h http.Header, objID string, input *metadata.DeleteOption) (
@@ -47,6 +50,16 @@ type InstanceClientInterface interface {
 		*metadata.CountResponseContent, error)
 	GetInstanceObjectMapping(ctx context.Context, h http.Header, ids []int64) ([]metadata.ObjectMapping,
 		errors.CCErrorCoder)
+	// ReadImportConflict read the recorded conflicts of the concurrent imports
+	ReadImportConflict(ctx context.Context, h http.Header, opt *metadata.SearchImportConflictOption) (
+		*metadata.ImportConflictResult, error)
+	// BindExternalIDs bind the external ids to the instance
+	BindExternalIDs(ctx context.Context, h http.Header, objID string, opt *metadata.BindExternalIDOption) error
+	// UnbindExternalIDs unbind the external ids of the sources from the instance
+	UnbindExternalIDs(ctx context.Context, h http.Header, objID string, opt *metadata.UnbindExternalIDOption) error
+	// SearchExternalIDs search the external ids of the instances, or look up the instances by the external ids
+	SearchExternalIDs(ctx context.Context, h http.Header, objID string, opt *metadata.SearchExternalIDOption) (
+		[]metadata.ExternalID, errors.CCErrorCoder)
 }
 
 // NewInstanceClientInterface TODO
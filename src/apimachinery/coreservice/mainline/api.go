@@ -77,3 +77,30 @@ func (m *mainline) SearchMainlineInstanceTopo(ctx context.Context, header http.H
 
 	return &ret.Data, nil
 }
+
+// CountTopoChildNodes count the most child nodes of the mainline object under one parent node of each business
+func (m *mainline) CountTopoChildNodes(ctx context.Context, header http.Header, objID string,
+	opt *metadata.CountTopoChildNodesOption) ([]metadata.TopoChildNodeCount, errors.CCErrorCoder) {
+
+	rid := util.GetHTTPCCRequestID(header)
+	ret := new(metadata.TopoChildNodeCountResp)
+	subPath := "/read/mainline/child_count/%s"
+
+	err := m.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath, objID).
+		WithHeaders(header).
+		Do().
+		Into(ret)
+
+	if err != nil {
+		blog.Errorf("count topo child nodes failed, http failed, err: %v, rid: %s", err, rid)
+		return nil, errors.CCHttpError
+	}
+	if ret.CCError() != nil {
+		return nil, ret.CCError()
+	}
+
+	return ret.Data, nil
+}
@@ -26,6 +26,8 @@ import (
 type MainlineClientInterface interface {
 	SearchMainlineModelTopo(ctx context.Context, h http.Header, withDetail bool) (*metadata.TopoModelNode, errors.CCErrorCoder)
 	SearchMainlineInstanceTopo(ctx context.Context, h http.Header, bkBizID int64, withDetail bool) (resp *metadata.TopoInstanceNode, err errors.CCErrorCoder)
+	CountTopoChildNodes(ctx context.Context, h http.Header, objID string, opt *metadata.CountTopoChildNodesOption) (
+		[]metadata.TopoChildNodeCount, errors.CCErrorCoder)
 }
 
 // NewMainlineClientInterface TODO
@@ -0,0 +1,95 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"net/http"
+
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+)
+
+// SaveAttrGroupDelegation create or replace the delegation of the model's attribute group to the role
+func (m *model) SaveAttrGroupDelegation(ctx context.Context, h http.Header, delegation *metadata.AttrGroupDelegation) (
+	*metadata.AttrGroupDelegation, error) {
+
+	resp := new(metadata.AttrGroupDelegationResult)
+	subPath := "/update/model/group/delegation"
+
+	err := m.client.Put().
+		WithContext(ctx).
+		Body(delegation).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err = resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// ReadAttrGroupDelegation read the attribute group delegations of the model
+func (m *model) ReadAttrGroupDelegation(ctx context.Context, h http.Header,
+	opt *metadata.ListAttrGroupDelegationOption) ([]metadata.AttrGroupDelegation, error) {
+
+	resp := new(metadata.AttrGroupDelegationsResult)
+	subPath := "/read/model/group/delegation"
+
+	err := m.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err = resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// DeleteAttrGroupDelegation delete the delegation of the model's attribute group to the role
+func (m *model) DeleteAttrGroupDelegation(ctx context.Context, h http.Header,
+	opt *metadata.AttrGroupDelegationKey) error {
+
+	resp := new(metadata.BaseResp)
+	subPath := "/delete/model/group/delegation"
+
+	err := m.client.Delete().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+
+	return resp.CCError()
+}
@@ -0,0 +1,93 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"net/http"
+
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+)
+
+// SaveBizFieldLayout create or replace the business's field layout of the model
+func (m *model) SaveBizFieldLayout(ctx context.Context, h http.Header, layout *metadata.BizFieldLayout) (
+	*metadata.BizFieldLayout, error) {
+
+	resp := new(metadata.BizFieldLayoutResp)
+	subPath := "/update/model/biz_field_layout"
+
+	err := m.client.Put().
+		WithContext(ctx).
+		Body(layout).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err = resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// ReadBizFieldLayout read the business's field layout of the model, returns nil if it is not set
+func (m *model) ReadBizFieldLayout(ctx context.Context, h http.Header, opt *metadata.BizFieldLayoutOption) (
+	*metadata.BizFieldLayout, error) {
+
+	resp := new(metadata.BizFieldLayoutResp)
+	subPath := "/read/model/biz_field_layout"
+
+	err := m.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err = resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// DeleteBizFieldLayout delete the business's field layout of the model
+func (m *model) DeleteBizFieldLayout(ctx context.Context, h http.Header, opt *metadata.BizFieldLayoutOption) error {
+	resp := new(metadata.BaseResp)
+	subPath := "/delete/model/biz_field_layout"
+
+	err := m.client.Delete().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+
+	return resp.CCError()
+}
@@ -97,6 +97,29 @@ type ModelClientInterface interface {
 		*metadata.QueryUniqueResult, error)
 
 	CreateModelTables(ctx context.Context, h http.Header, input *metadata.CreateModelTable) (err error)
+
+	SaveBizFieldLayout(ctx context.Context, h http.Header, layout *metadata.BizFieldLayout) (
+		*metadata.BizFieldLayout, error)
+	ReadBizFieldLayout(ctx context.Context, h http.Header, opt *metadata.BizFieldLayoutOption) (
+		*metadata.BizFieldLayout, error)
+	DeleteBizFieldLayout(ctx context.Context, h http.Header, opt *metadata.BizFieldLayoutOption) error
+
+	SaveAttrGroupDelegation(ctx context.Context, h http.Header, delegation *metadata.AttrGroupDelegation) (
+		*metadata.AttrGroupDelegation, error)
+	ReadAttrGroupDelegation(ctx context.Context, h http.Header, opt *metadata.ListAttrGroupDelegationOption) (
+		[]metadata.AttrGroupDelegation, error)
+	DeleteAttrGroupDelegation(ctx context.Context, h http.Header, opt *metadata.AttrGroupDelegationKey) error
+
+	CreateModelAsset(ctx context.Context, h http.Header, opt *metadata.CreateModelAssetOption) (*metadata.ModelAsset,
+		error)
+	AddModelAssetVersion(ctx context.Context, h http.Header, id int64, opt *metadata.AddModelAssetVersionOption) (
+		*metadata.AddModelAssetVersionResult, error)
+	ReadModelAsset(ctx context.Context, h http.Header, opt *metadata.SearchModelAssetOption) (
+		*metadata.SearchModelAssetResult, error)
+	DeleteModelAsset(ctx context.Context, h http.Header, id int64) (*metadata.ModelAsset, error)
+
+	ReadAttributeUsage(ctx context.Context, h http.Header, objID string, opt *metadata.SearchAttributeUsageOption) (
+		*metadata.AttributeUsageResult, error)
 }
 
 // NewModelClientInterface TODO
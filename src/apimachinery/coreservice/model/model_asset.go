@@ -0,0 +1,122 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"net/http"
+
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+)
+
+// CreateModelAsset create the model asset with its first version
+func (m *model) CreateModelAsset(ctx context.Context, h http.Header, opt *metadata.CreateModelAssetOption) (
+	*metadata.ModelAsset, error) {
+
+	resp := new(metadata.ModelAssetResp)
+	subPath := "/create/model/asset"
+
+	err := m.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err = resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// AddModelAssetVersion add a new version to the model asset
+func (m *model) AddModelAssetVersion(ctx context.Context, h http.Header, id int64,
+	opt *metadata.AddModelAssetVersionOption) (*metadata.AddModelAssetVersionResult, error) {
+
+	resp := new(metadata.AddModelAssetVersionResp)
+	subPath := "/create/model/asset/%d/version"
+
+	err := m.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath, id).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err = resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// ReadModelAsset search the model assets
+func (m *model) ReadModelAsset(ctx context.Context, h http.Header, opt *metadata.SearchModelAssetOption) (
+	*metadata.SearchModelAssetResult, error) {
+
+	resp := new(metadata.SearchModelAssetResp)
+	subPath := "/read/model/asset"
+
+	err := m.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err = resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// DeleteModelAsset delete the model asset, returns the deleted asset
+func (m *model) DeleteModelAsset(ctx context.Context, h http.Header, id int64) (*metadata.ModelAsset, error) {
+	resp := new(metadata.ModelAssetResp)
+	subPath := "/delete/model/asset/%d"
+
+	err := m.client.Delete().
+		WithContext(ctx).
+		SubResourcef(subPath, id).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+
+	if err = resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
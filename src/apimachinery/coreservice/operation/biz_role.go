@@ -0,0 +1,111 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operation
+
+import (
+	"context"
+	"net/http"
+
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+)
+
+// SaveBizRoleAssignment create or replace the users assigned to the business role of the topo node
+func (s *operation) SaveBizRoleAssignment(ctx context.Context, h http.Header,
+	assignment *metadata.BizRoleAssignment) (*metadata.BizRoleAssignment, errors.CCErrorCoder) {
+
+	resp := new(metadata.BizRoleAssignmentResult)
+	err := s.client.Put().
+		WithContext(ctx).
+		Body(assignment).
+		SubResourcef("/update/biz_role").
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// SearchBizRoleAssignment search the business role assignments of the business
+func (s *operation) SearchBizRoleAssignment(ctx context.Context, h http.Header,
+	opt *metadata.ListBizRoleAssignmentOption) ([]metadata.BizRoleAssignment, errors.CCErrorCoder) {
+
+	resp := new(metadata.BizRoleAssignmentsResult)
+	err := s.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef("/findmany/biz_role").
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// DeleteBizRoleAssignment delete the assignment of the business role of the topo node
+func (s *operation) DeleteBizRoleAssignment(ctx context.Context, h http.Header,
+	opt *metadata.DeleteBizRoleAssignmentOption) errors.CCErrorCoder {
+
+	resp := new(metadata.BaseResp)
+	err := s.client.Delete().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef("/delete/biz_role").
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+
+	return resp.CCError()
+}
+
+// ResolveBizRoleReceivers resolve the users of the business roles that should be notified for the module or the
+// business
+func (s *operation) ResolveBizRoleReceivers(ctx context.Context, h http.Header,
+	opt *metadata.ResolveBizRoleReceiverOption) ([]metadata.BizRoleReceiver, errors.CCErrorCoder) {
+
+	resp := new(metadata.BizRoleReceiversResult)
+	err := s.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef("/find/biz_role/receivers").
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
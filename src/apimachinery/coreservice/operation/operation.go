@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"configcenter/src/apimachinery/rest"
+	"configcenter/src/common/errors"
 	"configcenter/src/common/metadata"
 )
 
@@ -21,6 +22,21 @@ type OperationClientInterface interface {
 	UpdateChartPosition(ctx context.Context, h http.Header, data interface{}) (resp *metadata.Response, err error)
 	SearchChartCommon(ctx context.Context, h http.Header, data interface{}) (resp *metadata.SearchChartCommon, err error)
 	TimerFreshData(ctx context.Context, h http.Header, data interface{}) (resp *metadata.BoolResponse, err error)
+
+	CreateReportSubscription(ctx context.Context, h http.Header, data *metadata.ReportSubscription) (
+		*metadata.ReportSubscription, errors.CCErrorCoder)
+	UpdateReportSubscription(ctx context.Context, h http.Header, id int64, data map[string]interface{}) errors.CCErrorCoder
+	DeleteReportSubscription(ctx context.Context, h http.Header, id int64) errors.CCErrorCoder
+	SearchReportSubscription(ctx context.Context, h http.Header, input *metadata.QueryCondition) (
+		*metadata.MultipleReportSubscription, errors.CCErrorCoder)
+
+	SaveBizRoleAssignment(ctx context.Context, h http.Header, assignment *metadata.BizRoleAssignment) (
+		*metadata.BizRoleAssignment, errors.CCErrorCoder)
+	SearchBizRoleAssignment(ctx context.Context, h http.Header, opt *metadata.ListBizRoleAssignmentOption) (
+		[]metadata.BizRoleAssignment, errors.CCErrorCoder)
+	DeleteBizRoleAssignment(ctx context.Context, h http.Header, opt *metadata.DeleteBizRoleAssignmentOption) errors.CCErrorCoder
+	ResolveBizRoleReceivers(ctx context.Context, h http.Header, opt *metadata.ResolveBizRoleReceiverOption) (
+		[]metadata.BizRoleReceiver, errors.CCErrorCoder)
 }
 
 // NewOperationClientInterface TODO
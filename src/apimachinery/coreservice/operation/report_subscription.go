@@ -0,0 +1,110 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operation
+
+import (
+	"context"
+	"net/http"
+
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+)
+
+// CreateReportSubscription create a report subscription
+func (s *operation) CreateReportSubscription(ctx context.Context, h http.Header,
+	data *metadata.ReportSubscription) (*metadata.ReportSubscription, errors.CCErrorCoder) {
+
+	resp := new(metadata.ReportSubscriptionResult)
+	err := s.client.Post().
+		WithContext(ctx).
+		Body(data).
+		SubResourcef("/create/report_subscription").
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
+// UpdateReportSubscription update the report subscription
+func (s *operation) UpdateReportSubscription(ctx context.Context, h http.Header, id int64,
+	data map[string]interface{}) errors.CCErrorCoder {
+
+	resp := new(metadata.BaseResp)
+	err := s.client.Put().
+		WithContext(ctx).
+		Body(data).
+		SubResourcef("/update/report_subscription/%d", id).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteReportSubscription delete the report subscription
+func (s *operation) DeleteReportSubscription(ctx context.Context, h http.Header, id int64) errors.CCErrorCoder {
+	resp := new(metadata.BaseResp)
+	err := s.client.Delete().
+		WithContext(ctx).
+		SubResourcef("/delete/report_subscription/%d", id).
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SearchReportSubscription search the report subscriptions
+func (s *operation) SearchReportSubscription(ctx context.Context, h http.Header, input *metadata.QueryCondition) (
+	*metadata.MultipleReportSubscription, errors.CCErrorCoder) {
+
+	resp := new(metadata.MultipleReportSubscriptionResult)
+	err := s.client.Post().
+		WithContext(ctx).
+		Body(input).
+		SubResourcef("/findmany/report_subscription").
+		WithHeaders(h).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
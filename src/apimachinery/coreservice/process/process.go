@@ -108,6 +108,19 @@ type ProcessInterface interface {
 	ConstructServiceInstanceName(ctx context.Context, h http.Header,
 		params *metadata.SrvInstNameParams) errors.CCErrorCoder
 	ReconstructServiceInstanceName(ctx context.Context, h http.Header, instanceID int64) errors.CCErrorCoder
+	RenderServiceInstanceNames(ctx context.Context, h http.Header, opt *metadata.RenderSrvInstNameOption) (uint64,
+		errors.CCErrorCoder)
+	SaveSrvInstNameTemplate(ctx context.Context, h http.Header, template *metadata.SrvInstNameTemplate) (
+		*metadata.SrvInstNameTemplate, errors.CCErrorCoder)
+	ReadSrvInstNameTemplate(ctx context.Context, h http.Header, opt *metadata.SrvInstNameTemplateOption) (
+		*metadata.SrvInstNameTemplate, errors.CCErrorCoder)
+	DeleteSrvInstNameTemplate(ctx context.Context, h http.Header, opt *metadata.SrvInstNameTemplateOption) errors.CCErrorCoder
+
+	SaveTemplateVariable(ctx context.Context, h http.Header, variable *metadata.TemplateVariable) (
+		*metadata.TemplateVariable, errors.CCErrorCoder)
+	ListTemplateVariable(ctx context.Context, h http.Header, opt *metadata.TemplateVariableOption) (
+		[]metadata.TemplateVariable, errors.CCErrorCoder)
+	DeleteTemplateVariable(ctx context.Context, h http.Header, opt *metadata.TemplateVariableOption) errors.CCErrorCoder
 
 	// UpdateServiceTemplateAttribute TODO
 	// service template attribute
@@ -0,0 +1,127 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"context"
+	"net/http"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+)
+
+// RenderServiceInstanceNames render the names of the service instances again with the business's naming template,
+// returns the number of the rendered service instances
+func (p *process) RenderServiceInstanceNames(ctx context.Context, h http.Header,
+	opt *metadata.RenderSrvInstNameOption) (uint64, errors.CCErrorCoder) {
+
+	ret := new(metadata.CountResponse)
+	subPath := "/updatemany/process/service_instance_name/render"
+
+	err := p.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(ret)
+
+	if err != nil {
+		blog.Errorf("render service instance names failed, http request failed, err: %v", err)
+		return 0, errors.CCHttpError
+	}
+	if ret.CCError() != nil {
+		return 0, ret.CCError()
+	}
+
+	return ret.Data.Count, nil
+}
+
+// SaveSrvInstNameTemplate create or replace the business's service instance naming template
+func (p *process) SaveSrvInstNameTemplate(ctx context.Context, h http.Header,
+	template *metadata.SrvInstNameTemplate) (*metadata.SrvInstNameTemplate, errors.CCErrorCoder) {
+
+	ret := new(metadata.SrvInstNameTemplateResp)
+	subPath := "/update/process/service_instance_name_template"
+
+	err := p.client.Put().
+		WithContext(ctx).
+		Body(template).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(ret)
+
+	if err != nil {
+		blog.Errorf("save service instance name template failed, http request failed, err: %v", err)
+		return nil, errors.CCHttpError
+	}
+	if ret.CCError() != nil {
+		return nil, ret.CCError()
+	}
+
+	return ret.Data, nil
+}
+
+// ReadSrvInstNameTemplate read the business's service instance naming template, returns nil if it is not set
+func (p *process) ReadSrvInstNameTemplate(ctx context.Context, h http.Header,
+	opt *metadata.SrvInstNameTemplateOption) (*metadata.SrvInstNameTemplate, errors.CCErrorCoder) {
+
+	ret := new(metadata.SrvInstNameTemplateResp)
+	subPath := "/find/process/service_instance_name_template"
+
+	err := p.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(ret)
+
+	if err != nil {
+		blog.Errorf("read service instance name template failed, http request failed, err: %v", err)
+		return nil, errors.CCHttpError
+	}
+	if ret.CCError() != nil {
+		return nil, ret.CCError()
+	}
+
+	return ret.Data, nil
+}
+
+// DeleteSrvInstNameTemplate delete the business's service instance naming template
+func (p *process) DeleteSrvInstNameTemplate(ctx context.Context, h http.Header,
+	opt *metadata.SrvInstNameTemplateOption) errors.CCErrorCoder {
+
+	ret := new(metadata.BaseResp)
+	subPath := "/delete/process/service_instance_name_template"
+
+	err := p.client.Delete().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(ret)
+
+	if err != nil {
+		blog.Errorf("delete service instance name template failed, http request failed, err: %v", err)
+		return errors.CCHttpError
+	}
+	if ret.CCError() != nil {
+		return ret.CCError()
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"context"
+	"net/http"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+)
+
+// SaveTemplateVariable create or replace the template variables bound on a module or a set
+func (p *process) SaveTemplateVariable(ctx context.Context, h http.Header,
+	variable *metadata.TemplateVariable) (*metadata.TemplateVariable, errors.CCErrorCoder) {
+
+	ret := new(metadata.TemplateVariableResp)
+	subPath := "/update/process/template_variable"
+
+	err := p.client.Put().
+		WithContext(ctx).
+		Body(variable).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(ret)
+
+	if err != nil {
+		blog.Errorf("save template variable failed, http request failed, err: %v", err)
+		return nil, errors.CCHttpError
+	}
+	if ret.CCError() != nil {
+		return nil, ret.CCError()
+	}
+
+	return ret.Data, nil
+}
+
+// ListTemplateVariable list the template variables bound on the modules or sets
+func (p *process) ListTemplateVariable(ctx context.Context, h http.Header,
+	opt *metadata.TemplateVariableOption) ([]metadata.TemplateVariable, errors.CCErrorCoder) {
+
+	ret := new(metadata.TemplateVariablesResp)
+	subPath := "/findmany/process/template_variable"
+
+	err := p.client.Post().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(ret)
+
+	if err != nil {
+		blog.Errorf("list template variables failed, http request failed, err: %v", err)
+		return nil, errors.CCHttpError
+	}
+	if ret.CCError() != nil {
+		return nil, ret.CCError()
+	}
+
+	return ret.Data, nil
+}
+
+// DeleteTemplateVariable delete the template variables bound on the modules or sets
+func (p *process) DeleteTemplateVariable(ctx context.Context, h http.Header,
+	opt *metadata.TemplateVariableOption) errors.CCErrorCoder {
+
+	ret := new(metadata.BaseResp)
+	subPath := "/delete/process/template_variable"
+
+	err := p.client.Delete().
+		WithContext(ctx).
+		Body(opt).
+		SubResourcef(subPath).
+		WithHeaders(h).
+		Do().
+		Into(ret)
+
+	if err != nil {
+		blog.Errorf("delete template variables failed, http request failed, err: %v", err)
+		return errors.CCHttpError
+	}
+	if ret.CCError() != nil {
+		return ret.CCError()
+	}
+
+	return nil
+}
@@ -0,0 +1,301 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowctrl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"configcenter/src/common/blog"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// the states of the circuit breaker of a destination host.
+const (
+	// CircuitClosed the requests are sent to the host normally.
+	CircuitClosed = iota
+	// CircuitOpen the host keeps failing, the requests to it are rejected until the open duration elapses.
+	CircuitOpen
+	// CircuitHalfOpen a probe request is sent to the host to check whether it is recovered.
+	CircuitHalfOpen
+)
+
+const (
+	// DefaultFailureThreshold the default number of consecutive failures to open the circuit of a host.
+	DefaultFailureThreshold = 10
+	// DefaultOpenDuration the default duration the circuit keeps open.
+	DefaultOpenDuration = 10 * time.Second
+	// DefaultAcquireTimeout the default max time to wait for a free slot of the bulkhead.
+	DefaultAcquireTimeout = 3 * time.Second
+)
+
+var (
+	// ErrCircuitOpen the circuits of all the hosts of the destination service are open.
+	ErrCircuitOpen = errors.New("circuit breaker is open, the destination service is unavailable")
+	// ErrBulkheadFull the concurrent requests to the destination service reach the limit.
+	ErrBulkheadFull = errors.New("too many concurrent requests to the destination service")
+)
+
+// GuardConfig is the circuit breaker and bulkhead config of the requests to the destination services.
+type GuardConfig struct {
+	Enabled bool
+	// FailureThreshold the number of consecutive failures to open the circuit of a host.
+	FailureThreshold int
+	// OpenDuration how long the circuit keeps open before a probe request is allowed.
+	OpenDuration time.Duration
+	// MaxConcurrency the max concurrent requests to a destination service, unlimited if not set.
+	MaxConcurrency int
+	// AcquireTimeout the max time to wait for a free slot of the bulkhead.
+	AcquireTimeout time.Duration
+}
+
+// GuardSet holds the guards of the destination services, which share the same config and metrics.
+type GuardSet struct {
+	conf    GuardConfig
+	metrics *guardMetrics
+	lock    sync.Mutex
+	guards  map[string]*Guard
+}
+
+// NewGuardSet new a guard set, the metrics are not collected if the register is nil.
+func NewGuardSet(conf GuardConfig, register prometheus.Registerer) *GuardSet {
+	if conf.FailureThreshold <= 0 {
+		conf.FailureThreshold = DefaultFailureThreshold
+	}
+	if conf.OpenDuration <= 0 {
+		conf.OpenDuration = DefaultOpenDuration
+	}
+	if conf.AcquireTimeout <= 0 {
+		conf.AcquireTimeout = DefaultAcquireTimeout
+	}
+
+	set := &GuardSet{
+		conf:   conf,
+		guards: make(map[string]*Guard),
+	}
+	if conf.Enabled && register != nil {
+		set.metrics = newGuardMetrics(register)
+	}
+	return set
+}
+
+// Get returns the guard of the destination service, nil if the guard is not enabled.
+func (s *GuardSet) Get(name string) *Guard {
+	if s == nil || !s.conf.Enabled {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if guard, exists := s.guards[name]; exists {
+		return guard
+	}
+
+	guard := &Guard{
+		name:     name,
+		conf:     s.conf,
+		metrics:  s.metrics,
+		circuits: make(map[string]*circuit),
+	}
+	if s.conf.MaxConcurrency > 0 {
+		guard.slots = make(chan struct{}, s.conf.MaxConcurrency)
+	}
+	s.guards[name] = guard
+	return guard
+}
+
+// Guard protects the caller from a slow or failing destination service. The bulkhead limits the concurrent requests
+// to the service so that it can not exhaust the caller's goroutines, and the circuit breaker of each host rejects
+// the requests to the host fast after it keeps failing. All the methods are no-op on a nil guard.
+type Guard struct {
+	name     string
+	conf     GuardConfig
+	slots    chan struct{}
+	metrics  *guardMetrics
+	lock     sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state    int
+	failures int
+	// changedAt the time when the circuit is opened or a probe request is allowed.
+	changedAt time.Time
+}
+
+// Acquire takes a slot of the bulkhead, waits until a slot is released or the acquire timeout. The returned release
+// func must be called after the request is done.
+func (g *Guard) Acquire(ctx context.Context) (func(), error) {
+	if g == nil || g.slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case g.slots <- struct{}{}:
+	default:
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		timer := time.NewTimer(g.conf.AcquireTimeout)
+		defer timer.Stop()
+
+		select {
+		case g.slots <- struct{}{}:
+		case <-timer.C:
+			g.metrics.reject(g.name, "bulkhead_full")
+			return nil, ErrBulkheadFull
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	g.metrics.inflight(g.name, 1)
+	return func() {
+		<-g.slots
+		g.metrics.inflight(g.name, -1)
+	}, nil
+}
+
+// Allow checks whether the request can be sent to the host. When the open duration of the circuit elapses, only one
+// probe request is allowed in each open duration until the host is recovered.
+func (g *Guard) Allow(host string) bool {
+	if g == nil {
+		return true
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	c, exists := g.circuits[host]
+	if !exists || c.state == CircuitClosed {
+		return true
+	}
+
+	if time.Since(c.changedAt) < g.conf.OpenDuration {
+		g.metrics.reject(g.name, "circuit_open")
+		return false
+	}
+
+	c.state = CircuitHalfOpen
+	c.changedAt = time.Now()
+	g.metrics.setState(g.name, host, CircuitHalfOpen)
+	return true
+}
+
+// Report records the result of the request to the host, the circuit of the host is opened after consecutive failures
+// reach the threshold or the probe request fails, and is closed after a request succeeds.
+func (g *Guard) Report(host string, success bool) {
+	if g == nil {
+		return
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	c, exists := g.circuits[host]
+	if success {
+		if !exists {
+			return
+		}
+		if c.state != CircuitClosed {
+			blog.Infof("circuit of %s host %s is closed", g.name, host)
+			g.metrics.setState(g.name, host, CircuitClosed)
+		}
+		delete(g.circuits, host)
+		return
+	}
+
+	if !exists {
+		c = &circuit{state: CircuitClosed}
+		g.circuits[host] = c
+	}
+
+	c.failures++
+	if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= g.conf.FailureThreshold) {
+		blog.Errorf("circuit of %s host %s is opened after %d consecutive failures", g.name, host, c.failures)
+		c.state = CircuitOpen
+		c.changedAt = time.Now()
+		g.metrics.setState(g.name, host, CircuitOpen)
+	}
+}
+
+type guardMetrics struct {
+	circuitState *prometheus.GaugeVec
+	rejected     *prometheus.CounterVec
+	inflightReq  *prometheus.GaugeVec
+}
+
+func newGuardMetrics(register prometheus.Registerer) *guardMetrics {
+	m := &guardMetrics{
+		circuitState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cmdb_apimachinery_circuit_breaker_state",
+			Help: "circuit breaker state of the destination host, 0: closed, 1: open, 2: half open.",
+		}, []string{"destination", "host"}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cmdb_apimachinery_guard_rejected_total",
+			Help: "total number of the requests rejected by the circuit breaker or the bulkhead.",
+		}, []string{"destination", "reason"}),
+		inflightReq: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cmdb_apimachinery_bulkhead_inflight_requests",
+			Help: "number of the inflight requests to the destination service.",
+		}, []string{"destination"}),
+	}
+
+	if err := register.Register(m.circuitState); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			m.circuitState = are.ExistingCollector.(*prometheus.GaugeVec)
+		} else {
+			panic(err)
+		}
+	}
+	if err := register.Register(m.rejected); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			m.rejected = are.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			panic(err)
+		}
+	}
+	if err := register.Register(m.inflightReq); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			m.inflightReq = are.ExistingCollector.(*prometheus.GaugeVec)
+		} else {
+			panic(err)
+		}
+	}
+	return m
+}
+
+func (m *guardMetrics) setState(destination, host string, state int) {
+	if m == nil {
+		return
+	}
+	m.circuitState.WithLabelValues(destination, host).Set(float64(state))
+}
+
+func (m *guardMetrics) reject(destination, reason string) {
+	if m == nil {
+		return
+	}
+	m.rejected.WithLabelValues(destination, reason).Inc()
+}
+
+func (m *guardMetrics) inflight(destination string, delta float64) {
+	if m == nil {
+		return
+	}
+	m.inflightReq.WithLabelValues(destination).Add(delta)
+}
@@ -0,0 +1,85 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flowctrl
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGuardCircuit(t *testing.T) {
+	set := NewGuardSet(GuardConfig{Enabled: true, FailureThreshold: 2, OpenDuration: 50 * time.Millisecond}, nil)
+	guard := set.Get("demo")
+	host := "http://127.0.0.1:80"
+
+	guard.Report(host, false)
+	if !guard.Allow(host) {
+		t.Fatalf("circuit should be closed before the failures reach the threshold")
+	}
+
+	guard.Report(host, false)
+	if guard.Allow(host) {
+		t.Fatalf("circuit should be open after the failures reach the threshold")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !guard.Allow(host) {
+		t.Fatalf("probe request should be allowed after the open duration")
+	}
+	if guard.Allow(host) {
+		t.Fatalf("only one probe request should be allowed")
+	}
+
+	guard.Report(host, true)
+	if !guard.Allow(host) {
+		t.Fatalf("circuit should be closed after the probe request succeeds")
+	}
+}
+
+func TestGuardBulkhead(t *testing.T) {
+	set := NewGuardSet(GuardConfig{Enabled: true, MaxConcurrency: 1, AcquireTimeout: 10 * time.Millisecond}, nil)
+	guard := set.Get("demo")
+
+	release, err := guard.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire failed, err: %v", err)
+	}
+
+	if _, err := guard.Acquire(context.Background()); err != ErrBulkheadFull {
+		t.Fatalf("acquire should fail when the bulkhead is full, err: %v", err)
+	}
+
+	release()
+	if _, err := guard.Acquire(context.Background()); err != nil {
+		t.Fatalf("acquire failed after the slot is released, err: %v", err)
+	}
+}
+
+func TestDisabledGuard(t *testing.T) {
+	guard := NewGuardSet(GuardConfig{}, nil).Get("demo")
+	if guard != nil {
+		t.Fatalf("guard should be nil when it is disabled")
+	}
+
+	release, err := guard.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire of nil guard failed, err: %v", err)
+	}
+	release()
+
+	guard.Report("host", false)
+	if !guard.Allow("host") {
+		t.Fatalf("nil guard should allow all the requests")
+	}
+}
@@ -30,9 +30,11 @@ import (
 	"syscall"
 	"time"
 
+	"configcenter/src/apimachinery/flowctrl"
 	"configcenter/src/apimachinery/util"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/faultinject"
 	"configcenter/src/common/json"
 	"configcenter/src/common/metadata"
 	commonUtil "configcenter/src/common/util"
@@ -303,8 +305,19 @@ func (r *Request) Do() *Result {
 		return result
 	}
 
+	// limit the concurrent requests to the destination service, so that a slow service can not exhaust the goroutines
+	release, err := r.capability.Guard.Acquire(r.ctx)
+	if err != nil {
+		blog.Errorf("[apimachinery] %s %s rejected by bulkhead, err: %v, rid: %s", string(r.verb), r.subPath, err, rid)
+		result.Err = err
+		result.Rid = rid
+		return result
+	}
+	defer release()
+
 	maxRetryCycle := 3
 	var retries int
+	var circuitOpen bool
 	for try := 0; try < maxRetryCycle; try++ {
 		for index, host := range hosts {
 			retries = try + index
@@ -333,8 +346,19 @@ func (r *Request) Do() *Result {
 				r.tryThrottle(url)
 			}
 
+			// skip the host whose circuit is open, the circuit must be reported after the request is sent.
+			if !r.capability.Guard.Allow(host) {
+				circuitOpen = true
+				continue
+			}
+
 			start := time.Now()
-			resp, err := client.Do(req)
+			var resp *http.Response
+			err = faultinject.Inject(r.ctx, faultinject.HTTP, string(r.verb)+" "+req.URL.Path)
+			if err == nil {
+				resp, err = client.Do(req)
+			}
+			r.reportCircuit(host, resp, err)
 			if err != nil {
 				// "Connection reset by peer" is a special err which in most scenario is a a transient error.
 				// Which means that we can retry it. And so does the GET operation.
@@ -403,10 +427,40 @@ func (r *Request) Do() *Result {
 
 	}
 
+	if circuitOpen {
+		blog.Errorf("[apimachinery] %s %s rejected by circuit breaker, rid: %s", string(r.verb), r.subPath, rid)
+		result.Err = flowctrl.ErrCircuitOpen
+		result.Rid = rid
+		return result
+	}
+
 	result.Err = errors.New("unexpected error")
 	return result
 }
 
+// reportCircuit reports the request result to the circuit breaker of the host, the transport errors and the
+// unavailable status of the gateway count as failures. The request canceled by the caller is not counted.
+func (r *Request) reportCircuit(host string, resp *http.Response, err error) {
+	if r.capability.Guard == nil {
+		return
+	}
+
+	if err != nil {
+		if r.ctx != nil && r.ctx.Err() == context.Canceled {
+			return
+		}
+		r.capability.Guard.Report(host, false)
+		return
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		r.capability.Guard.Report(host, false)
+	default:
+		r.capability.Guard.Report(host, true)
+	}
+}
+
 const maxLatency = 100 * time.Millisecond
 
 func (r *Request) tryThrottle(url string) {
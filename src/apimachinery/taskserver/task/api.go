@@ -45,6 +45,13 @@ type TaskClientInterface interface {
 
 	ListSyncStatusHistory(ctx context.Context, header http.Header, option *metadata.QueryCondition) (
 		*metadata.ListAPITaskSyncStatusResult, errors.CCErrorCoder)
+
+	// ResumeTask resume the failed task from its last checkpoint, the finished sub tasks are not executed again
+	ResumeTask(ctx context.Context, header http.Header, taskID string) errors.CCErrorCoder
+
+	// ListTaskFile list the file chunks generated by the finished task in order
+	ListTaskFile(ctx context.Context, header http.Header, taskID string) ([]metadata.APITaskFile,
+		errors.CCErrorCoder)
 }
 
 // NewTaskClientInterface TODO
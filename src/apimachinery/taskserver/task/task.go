@@ -229,3 +229,51 @@ func (t *task) ListSyncStatusHistory(ctx context.Context, header http.Header,
 
 	return resp.Data, nil
 }
+
+// ResumeTask resume the failed task from its last checkpoint
+func (t *task) ResumeTask(ctx context.Context, header http.Header, taskID string) errors.CCErrorCoder {
+	resp := new(metadata.BaseResp)
+	subPath := "/task/resume/id/%s"
+
+	err := t.client.Put().
+		WithContext(ctx).
+		Body(nil).
+		SubResourcef(subPath, taskID).
+		WithHeaders(header).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListTaskFile list the file chunks generated by the task in order
+func (t *task) ListTaskFile(ctx context.Context, header http.Header, taskID string) ([]metadata.APITaskFile,
+	errors.CCErrorCoder) {
+
+	resp := new(metadata.ListAPITaskFileResponse)
+	subPath := "/task/findmany/file/%s"
+
+	err := t.client.Post().
+		WithContext(ctx).
+		Body(nil).
+		SubResourcef(subPath, taskID).
+		WithHeaders(header).
+		Do().
+		Into(resp)
+
+	if err != nil {
+		return nil, errors.CCHttpError
+	}
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
@@ -25,16 +25,18 @@ import (
 // NewClient create a new http client
 func NewClient(c *TLSClientConfig, conf ...ExtraClientConfig) (*http.Client, error) {
 	tlsConf := new(tls.Config)
-	if c != nil && len(c.CAFile) != 0 && len(c.CertFile) != 0 && len(c.KeyFile) != 0 {
-		var err error
-		tlsConf, err = ssl.ClientTLSConfVerity(c.CAFile, c.CertFile, c.KeyFile, c.Password)
+	if c != nil {
+		tlsConf.InsecureSkipVerify = c.InsecureSkipVerify
+	}
+
+	// the client certificate is reloaded when it is rotated, so that the mutual tls connections to the other
+	// services keep working without restarting.
+	if source := c.CertSource(); source != nil {
+		reloader, err := ssl.GetCertReloader(source, c.ReloadInterval)
 		if err != nil {
 			return nil, err
 		}
-	}
-
-	if c != nil {
-		tlsConf.InsecureSkipVerify = c.InsecureSkipVerify
+		tlsConf = reloader.ClientTLSConfig(c.InsecureSkipVerify)
 	}
 
 	// set api request timeout to 25s, so that we can stop the long request like searching all hosts
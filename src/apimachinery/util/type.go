@@ -32,6 +32,11 @@ type APIMachineryConfig struct {
 	// request's burst value
 	Burst     int64
 	TLSConfig *TLSClientConfig
+
+	// GuardConfig the circuit breaker and bulkhead config of the requests to each destination service
+	GuardConfig flowctrl.GuardConfig
+	// MetricRegister the prometheus register of the circuit breaker and bulkhead metrics
+	MetricRegister prometheus.Registerer
 }
 
 // Capability TODO
@@ -41,6 +46,8 @@ type Capability struct {
 	Throttle   flowctrl.RateLimiter
 	Mock       MockInfo
 	MetricOpts MetricOption
+	// Guard the circuit breaker and bulkhead of the destination service, disabled if not set.
+	Guard *flowctrl.Guard
 	// the max tolerance api request latency time, if exceeded this time, then
 	// this request will be logged and warned.
 	ToleranceLatencyTime time.Duration
@@ -73,6 +80,29 @@ type TLSClientConfig struct {
 	CAFile string
 	// the password to decrypt the certificate
 	Password string
+	// CertService the cert service to load the certificates from instead of the files
+	CertService *ssl.CertServiceConfig
+	// ClientAuthMode how the server authenticates the client certificates, strict/permissive/none
+	ClientAuthMode ssl.ClientAuthMode
+	// ReloadInterval the interval to check whether the certificates are rotated
+	ReloadInterval time.Duration
+}
+
+// CertSource returns where the certificates are loaded from, nil if the certificates are not configured.
+func (c *TLSClientConfig) CertSource() ssl.CertSource {
+	if c == nil {
+		return nil
+	}
+
+	if c.CertService != nil && len(c.CertService.URL) != 0 {
+		return &ssl.CertServiceSource{Config: *c.CertService, Passwd: c.Password}
+	}
+
+	if len(c.CertFile) == 0 || len(c.KeyFile) == 0 {
+		return nil
+	}
+
+	return &ssl.FileSource{CAFile: c.CAFile, CertFile: c.CertFile, KeyFile: c.KeyFile, Passwd: c.Password}
 }
 
 // NewTLSClientConfigFromConfig new config about tls client config
@@ -107,9 +137,57 @@ func NewTLSClientConfigFromConfig(prefix string) (TLSClientConfig, error) {
 		tlsConfig.Password = val
 	}
 
+	clientAuthModeKey := fmt.Sprintf("%s.clientAuthMode", prefix)
+	if val, err := cc.String(clientAuthModeKey); err == nil && len(val) != 0 {
+		tlsConfig.ClientAuthMode = ssl.ClientAuthMode(val)
+		if err := tlsConfig.ClientAuthMode.Validate(); err != nil {
+			return tlsConfig, err
+		}
+	}
+
+	reloadIntervalKey := fmt.Sprintf("%s.reloadIntervalSeconds", prefix)
+	if val, err := cc.Int(reloadIntervalKey); err == nil && val > 0 {
+		tlsConfig.ReloadInterval = time.Duration(val) * time.Second
+	}
+
+	certServiceURLKey := fmt.Sprintf("%s.certService.url", prefix)
+	if val, err := cc.String(certServiceURLKey); err == nil && len(val) != 0 {
+		tlsConfig.CertService = &ssl.CertServiceConfig{URL: val}
+		if token, err := cc.String(fmt.Sprintf("%s.certService.token", prefix)); err == nil {
+			tlsConfig.CertService.Token = token
+		}
+	}
+
 	return tlsConfig, nil
 }
 
+// NewGuardConfigFromConfig new the circuit breaker and bulkhead config of the requests to the destination services
+func NewGuardConfigFromConfig(prefix string) flowctrl.GuardConfig {
+	conf := flowctrl.GuardConfig{}
+
+	if val, err := cc.Bool(fmt.Sprintf("%s.enabled", prefix)); err == nil {
+		conf.Enabled = val
+	}
+
+	if val, err := cc.Int(fmt.Sprintf("%s.circuitBreaker.failureThreshold", prefix)); err == nil && val > 0 {
+		conf.FailureThreshold = val
+	}
+
+	if val, err := cc.Int(fmt.Sprintf("%s.circuitBreaker.openSeconds", prefix)); err == nil && val > 0 {
+		conf.OpenDuration = time.Duration(val) * time.Second
+	}
+
+	if val, err := cc.Int(fmt.Sprintf("%s.bulkhead.maxConcurrency", prefix)); err == nil && val > 0 {
+		conf.MaxConcurrency = val
+	}
+
+	if val, err := cc.Int(fmt.Sprintf("%s.bulkhead.acquireTimeoutMilliseconds", prefix)); err == nil && val > 0 {
+		conf.AcquireTimeout = time.Duration(val) * time.Millisecond
+	}
+
+	return conf
+}
+
 // ExtraClientConfig extra http client configuration
 type ExtraClientConfig struct {
 	// ResponseHeaderTimeout the amount of time to wait for a server's response headers
@@ -127,12 +205,12 @@ func GetClientTLSConfig(prefix string) (*tls.Config, error) {
 		return nil, err
 	}
 
-	if len(config.CAFile) != 0 && len(config.CertFile) != 0 && len(config.KeyFile) != 0 {
-		tlsConf, err = ssl.ClientTLSConfVerity(config.CAFile, config.CertFile, config.KeyFile, config.Password)
+	if source := config.CertSource(); source != nil {
+		reloader, err := ssl.GetCertReloader(source, config.ReloadInterval)
 		if err != nil {
 			return nil, err
 		}
-		tlsConf.InsecureSkipVerify = config.InsecureSkipVerify
+		tlsConf = reloader.ClientTLSConfig(config.InsecureSkipVerify)
 	}
 
 	return tlsConf, nil
@@ -78,7 +78,19 @@ func Run(ctx context.Context, cancel context.CancelFunc, op *options.ServerOptio
 		return fmt.Errorf("new proxy client failed, err: %v", err)
 	}
 
-	svc.SetConfig(engine, client, engine.Discovery(), engine.CoreAPI, cache, limiter)
+	bodyLimit, err := service.NewBodyLimitFromConfig("apiServer.bodyLimit")
+	if err != nil {
+		blog.Errorf("get apiServer.bodyLimit config failed, err: %v", err)
+		return err
+	}
+
+	queryCache, err := service.NewQueryCacheFromConfig("apiServer.queryCache")
+	if err != nil {
+		blog.Errorf("get apiServer.queryCache config failed, err: %v", err)
+		return err
+	}
+
+	svc.SetConfig(engine, client, engine.Discovery(), engine.CoreAPI, cache, limiter, bodyLimit, queryCache)
 
 	ctnr := restful.NewContainer()
 	ctnr.Router(restful.CurlyRouter{})
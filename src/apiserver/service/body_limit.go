@@ -0,0 +1,133 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"configcenter/src/common"
+	cc "configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+// DefaultBodyLimitMB is the default max request body size in MB of the apis.
+const DefaultBodyLimitMB = 100
+
+// BodyLimit is the max request body size in bytes of the api groups, the size of a request body is checked before
+// it is proxied to the backend server, so that an oversize request can not exhaust the memory of the servers.
+type BodyLimit struct {
+	// Default the max body size of the apis not in the groups
+	Default int64
+	// Groups the max body size of the api groups, the key is the request type of the apis, e.g. topo, host.
+	Groups map[RequestType]int64
+}
+
+// NewBodyLimitFromConfig new the body limit from the config, the sizes are configured in MB.
+func NewBodyLimitFromConfig(prefix string) (*BodyLimit, error) {
+	limit := &BodyLimit{
+		Default: DefaultBodyLimitMB << 20,
+		Groups:  make(map[RequestType]int64),
+	}
+
+	defaultKey := prefix + ".default"
+	if cc.IsExist(defaultKey) {
+		size, err := cc.Int64(defaultKey)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("%s is invalid, it must be a positive integer, err: %v", defaultKey, err)
+		}
+		limit.Default = size << 20
+	}
+
+	groups := []RequestType{TopoType, HostType, ProcType, EventType, DataCollectType, OperationType, TaskType,
+		AdminType, CloudType, CacheType}
+	for _, group := range groups {
+		key := fmt.Sprintf("%s.groups.%s", prefix, group)
+		if !cc.IsExist(key) {
+			continue
+		}
+
+		size, err := cc.Int64(key)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("%s is invalid, it must be a positive integer, err: %v", key, err)
+		}
+		limit.Groups[group] = size << 20
+	}
+
+	return limit, nil
+}
+
+// Get returns the max body size of the api group
+func (l *BodyLimit) Get(kind RequestType) int64 {
+	if size, exists := l.Groups[kind]; exists {
+		return size
+	}
+	return l.Default
+}
+
+// BodyLimitFilter limits the request body size before any other filter, so that the oversize request is rejected
+// before its body is read into memory, e.g. by the auth filter which peeks the body to parse the resources.
+func (s *service) BodyLimitFilter() func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
+	return func(req *restful.Request, resp *restful.Response, fchain *restful.FilterChain) {
+		if s.bodyLimit == nil {
+			fchain.ProcessFilter(req, resp)
+			return
+		}
+
+		// the api group is matched with a clone of the request, because the matching rewrites the request url,
+		// the unknown apis are limited by the default size.
+		probe := restful.NewRequest(req.Request.Clone(req.Request.Context()))
+		kind, _ := URLPath(req.Request.RequestURI).FilterChain(probe)
+		if !s.limitRequestBody(kind, req, resp) {
+			return
+		}
+		fchain.ProcessFilter(req, resp)
+	}
+}
+
+// limitRequestBody limits the request body size of the api group, returns false if the body is known to exceed
+// the limit, otherwise the body is wrapped to fail the reading once it exceeds the limit, which is used for the
+// chunked request whose size is unknown in advance.
+func (s *service) limitRequestBody(kind RequestType, req *restful.Request, resp *restful.Response) bool {
+	if s.bodyLimit == nil {
+		return true
+	}
+
+	limit := s.bodyLimit.Get(kind)
+	if req.Request.ContentLength > limit {
+		rid := util.GetHTTPCCRequestID(req.Request.Header)
+		blog.Errorf("request %s body size %d exceeds limit %d, rid: %s", req.Request.RequestURI,
+			req.Request.ContentLength, limit, rid)
+
+		errMsg := s.engine.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(req.Request.Header)).
+			CCErrorf(common.CCErrCommRequestBodyTooLarge, limit)
+		rsp := metadata.BaseResp{
+			Code:   common.CCErrCommRequestBodyTooLarge,
+			ErrMsg: errMsg.Error(),
+			Result: false,
+		}
+		if err := resp.WriteHeaderAndJson(http.StatusRequestEntityTooLarge, rsp, restful.MIME_JSON); err != nil {
+			blog.Errorf("response request[url: %s] failed, err: %v, rid: %s", req.Request.RequestURI, err, rid)
+		}
+		return false
+	}
+
+	if req.Request.Body != nil {
+		req.Request.Body = http.MaxBytesReader(resp.ResponseWriter, req.Request.Body, limit)
+	}
+	return true
+}
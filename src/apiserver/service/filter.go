@@ -64,6 +64,11 @@ const (
 func (s *service) URLFilterChan(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
 	rid := util.GetHTTPCCRequestID(req.Request.Header)
 
+	// the cached apis are matched by the api path before it is rewritten to the backend server's path
+	if s.queryCache.Match(req.Request) {
+		req.SetAttribute(queryCacheAttribute, true)
+	}
+
 	var kind RequestType
 	var err error
 	kind, err = URLPath(req.Request.RequestURI).FilterChain(req)
@@ -80,6 +85,11 @@ func (s *service) URLFilterChan(req *restful.Request, resp *restful.Response, ch
 		return
 	}
 
+	// the admin apis are not restricted by the read-only mode so that the repair operations can still be done
+	if kind != AdminType && !s.engine.CheckReadOnly(req, resp) {
+		return
+	}
+
 	defer func() {
 		if err != nil {
 			blog.Errorf("proxy request url[%s] failed, err: %v, rid: %s", req.Request.RequestURI, err, rid)
@@ -54,28 +54,21 @@ const maxToleranceLatencyTime = 10 * time.Second
 // Do TODO
 func (s *service) Do(req *restful.Request, resp *restful.Response) {
 
+	if cached, _ := req.Attribute(queryCacheAttribute).(bool); cached {
+		s.doWithQueryCache(req, resp)
+		return
+	}
+
 	rid := util.GetHTTPCCRequestID(req.Request.Header)
 	start := time.Now()
 	url := req.Request.URL.Scheme + "://" + req.Request.URL.Host + req.Request.RequestURI
-	proxyReq, err := http.NewRequestWithContext(req.Request.Context(), req.Request.Method, url, req.Request.Body)
+	proxyReq, err := newProxyRequest(req, req.Request.Body)
 	if err != nil {
 		blog.Errorf("new proxy request[%s] failed, err: %v, rid: %s", url, err, rid)
-		if err := resp.WriteError(http.StatusInternalServerError, &metadata.RespError{
-			Msg:     fmt.Errorf("proxy request failed, %s", err.Error()),
-			ErrCode: common.CCErrProxyRequestFailed,
-			Data:    nil,
-		}); err != nil {
-			blog.Errorf("response request[url: %s] failed, err: %v, rid: %s, rid: %s", req.Request.RequestURI, err, rid)
-		}
+		s.writeProxyError(req, resp, err)
 		return
 	}
 
-	for k, v := range req.Request.Header {
-		if len(v) > 0 {
-			proxyReq.Header.Set(k, v[0])
-		}
-	}
-
 	response, err := s.client.Do(proxyReq)
 	if err != nil {
 		if time.Since(start) >= maxToleranceLatencyTime {
@@ -141,3 +134,31 @@ func (s *service) Do(req *restful.Request, resp *restful.Response) {
 	)
 	return
 }
+
+// newProxyRequest new the request that proxies the request to the backend server with the body
+func newProxyRequest(req *restful.Request, body io.Reader) (*http.Request, error) {
+	url := req.Request.URL.Scheme + "://" + req.Request.URL.Host + req.Request.RequestURI
+	proxyReq, err := http.NewRequestWithContext(req.Request.Context(), req.Request.Method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range req.Request.Header {
+		if len(v) > 0 {
+			proxyReq.Header.Set(k, v[0])
+		}
+	}
+	return proxyReq, nil
+}
+
+// writeProxyError response the error that the request is failed to be proxied
+func (s *service) writeProxyError(req *restful.Request, resp *restful.Response, err error) {
+	if err := resp.WriteError(http.StatusInternalServerError, &metadata.RespError{
+		Msg:     fmt.Errorf("proxy request failed, %s", err.Error()),
+		ErrCode: common.CCErrProxyRequestFailed,
+		Data:    nil,
+	}); err != nil {
+		blog.Errorf("response request[url: %s] failed, err: %v, rid: %s", req.Request.RequestURI, err,
+			util.GetHTTPCCRequestID(req.Request.Header))
+	}
+}
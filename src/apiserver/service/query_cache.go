@@ -0,0 +1,298 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/common"
+	cc "configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/storage/dal/redis"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+// DefaultQueryCacheTTLSeconds is the default ttl in seconds of the cached query results.
+const DefaultQueryCacheTTLSeconds = 5
+
+// queryCacheAttribute is the request attribute that marks the request's response is cached.
+const queryCacheAttribute = "query_cache"
+
+// QueryCache is an opt-in read cache of the expensive query apis, e.g. the dashboards issue the same heavy queries
+// every few seconds. the successful responses of the apis that match the configured paths are cached for a short ttl,
+// keyed by the hash of the request's user, url and normalized body which contains the filter and the page.
+// the same requests that miss the cache at the same time are merged into one backend request, so that the backend is
+// not flooded when a hot cache expires.
+type QueryCache struct {
+	TTL time.Duration
+	// Paths the regular expressions of the url paths of the apis that are cached
+	Paths []*regexp.Regexp
+	calls *queryCallGroup
+}
+
+// NewQueryCacheFromConfig new the query cache from the config, returns nil if no api is configured to be cached.
+func NewQueryCacheFromConfig(prefix string) (*QueryCache, error) {
+	pathsKey := prefix + ".paths"
+	if !cc.IsExist(pathsKey) {
+		return nil, nil
+	}
+
+	paths, err := cc.StringSlice(pathsKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s is invalid, err: %v", pathsKey, err)
+	}
+
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	cache := &QueryCache{
+		TTL:   DefaultQueryCacheTTLSeconds * time.Second,
+		Paths: make([]*regexp.Regexp, 0, len(paths)),
+		calls: &queryCallGroup{calls: make(map[string]*queryCall)},
+	}
+
+	for _, path := range paths {
+		pathRegexp, err := regexp.Compile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s path %s is invalid, err: %v", pathsKey, path, err)
+		}
+		cache.Paths = append(cache.Paths, pathRegexp)
+	}
+
+	ttlKey := prefix + ".ttlSeconds"
+	if cc.IsExist(ttlKey) {
+		ttl, err := cc.Int(ttlKey)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("%s is invalid, it must be a positive integer, err: %v", ttlKey, err)
+		}
+		cache.TTL = time.Duration(ttl) * time.Second
+	}
+
+	return cache, nil
+}
+
+// Match check if the response of the request can be cached, only the query requests are cached.
+func (c *QueryCache) Match(req *http.Request) bool {
+	if c == nil {
+		return false
+	}
+
+	if req.Method != http.MethodGet && req.Method != http.MethodPost {
+		return false
+	}
+
+	for _, path := range c.Paths {
+		if path.MatchString(req.URL.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryCacheEntry is the cached response of a query request.
+type queryCacheEntry struct {
+	StatusCode int               `json:"status_code"`
+	Header     map[string]string `json:"header"`
+	Body       []byte            `json:"body"`
+}
+
+// doWithQueryCache proxy the query request and cache its response, the cache is not read if the request has the
+// bypass header, but the fresh response is still cached for the subsequent requests.
+func (s *service) doWithQueryCache(req *restful.Request, resp *restful.Response) {
+	rid := util.GetHTTPCCRequestID(req.Request.Header)
+
+	body, err := ioutil.ReadAll(req.Request.Body)
+	if err != nil {
+		blog.Errorf("read request[url: %s] body failed, err: %v, rid: %s", req.Request.RequestURI, err, rid)
+		s.writeProxyError(req, resp, err)
+		return
+	}
+
+	key := queryCacheKey(req.Request, body)
+	if req.Request.Header.Get(common.BKHTTPQueryCacheBypass) != "true" {
+		entry, hit := s.getQueryCache(key, rid)
+		if hit {
+			blog.V(4).Infof("query cache hit, url: %s, rid: %s", req.Request.RequestURI, rid)
+			writeQueryCacheEntry(resp, entry, rid)
+			return
+		}
+	}
+
+	entry, err := s.queryCache.calls.do(key, func() (*queryCacheEntry, error) {
+		return s.doQueryRequest(req, body, key)
+	})
+	if err != nil {
+		blog.Errorf("do query request[url: %s] failed, err: %v, rid: %s", req.Request.RequestURI, err, rid)
+		s.writeProxyError(req, resp, err)
+		return
+	}
+
+	writeQueryCacheEntry(resp, entry, rid)
+}
+
+// doQueryRequest proxy the query request to the backend, the successful response is cached.
+func (s *service) doQueryRequest(req *restful.Request, body []byte, key string) (*queryCacheEntry, error) {
+	rid := util.GetHTTPCCRequestID(req.Request.Header)
+
+	proxyReq, err := newProxyRequest(req, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.client.Do(proxyReq)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	respBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &queryCacheEntry{StatusCode: response.StatusCode, Header: make(map[string]string), Body: respBody}
+	for k, v := range response.Header {
+		if len(v) > 0 {
+			entry.Header[k] = v[0]
+		}
+	}
+
+	// only the successful response is cached, the failed one is returned to the requests waiting for it only
+	if response.StatusCode != http.StatusOK {
+		return entry, nil
+	}
+
+	baseResp := new(metadata.BaseResp)
+	if err := json.Unmarshal(respBody, baseResp); err != nil || !baseResp.Result {
+		return entry, nil
+	}
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		blog.Errorf("marshal query cache entry failed, err: %v, rid: %s", err, rid)
+		return entry, nil
+	}
+
+	if err := s.cache.Set(req.Request.Context(), key, value, s.queryCache.TTL).Err(); err != nil {
+		blog.Errorf("set query cache %s failed, err: %v, rid: %s", key, err, rid)
+	}
+	return entry, nil
+}
+
+// getQueryCache get the cached response, the cache is treated as missed if it is failed to be read.
+func (s *service) getQueryCache(key, rid string) (*queryCacheEntry, bool) {
+	value, err := s.cache.Get(context.Background(), key).Result()
+	if err != nil {
+		if !redis.IsNilErr(err) {
+			blog.Errorf("get query cache %s failed, err: %v, rid: %s", key, err, rid)
+		}
+		return nil, false
+	}
+
+	entry := new(queryCacheEntry)
+	if err := json.Unmarshal([]byte(value), entry); err != nil {
+		blog.Errorf("unmarshal query cache %s failed, err: %v, rid: %s", key, err, rid)
+		return nil, false
+	}
+	return entry, true
+}
+
+func writeQueryCacheEntry(resp *restful.Response, entry *queryCacheEntry, rid string) {
+	for k, v := range entry.Header {
+		resp.Header().Set(k, v)
+	}
+	resp.ResponseWriter.WriteHeader(entry.StatusCode)
+
+	if _, err := resp.Write(entry.Body); err != nil {
+		blog.Errorf("write query response failed, err: %v, rid: %s", err, rid)
+	}
+}
+
+// queryCacheKey returns the cache key of the query request, the json body is normalized so that the same filters
+// in different key orders share the cache.
+func queryCacheKey(req *http.Request, body []byte) string {
+	normalized := body
+	var data interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err == nil {
+		if marshaled, err := json.Marshal(data); err == nil {
+			normalized = marshaled
+		}
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(strings.Join([]string{
+		util.GetOwnerID(req.Header),
+		util.GetUser(req.Header),
+		util.GetLanguage(req.Header),
+		req.Method,
+		req.URL.RequestURI(),
+	}, "\n")))
+	hash.Write([]byte("\n"))
+	hash.Write(normalized)
+
+	return common.ApiQueryCachePrefix + hex.EncodeToString(hash.Sum(nil))
+}
+
+// queryCall is an in-flight backend request of the query cache.
+type queryCall struct {
+	wg    sync.WaitGroup
+	entry *queryCacheEntry
+	err   error
+}
+
+// queryCallGroup merges the same query requests that are in flight, the later requests wait for the result of the
+// first one instead of requesting the backend again.
+type queryCallGroup struct {
+	lock  sync.Mutex
+	calls map[string]*queryCall
+}
+
+func (g *queryCallGroup) do(key string, fn func() (*queryCacheEntry, error)) (*queryCacheEntry, error) {
+	g.lock.Lock()
+	if call, exists := g.calls[key]; exists {
+		g.lock.Unlock()
+		call.wg.Wait()
+		return call.entry, call.err
+	}
+
+	call := new(queryCall)
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.lock.Unlock()
+
+	call.entry, call.err = fn()
+	call.wg.Done()
+
+	g.lock.Lock()
+	delete(g.calls, key)
+	g.lock.Unlock()
+
+	return call.entry, call.err
+}
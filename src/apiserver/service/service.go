@@ -34,7 +34,8 @@ import (
 type Service interface {
 	WebServices() []*restful.WebService
 	SetConfig(engine *backbone.Engine, httpClient HTTPClient, discovery discovery.DiscoveryInterface,
-		clientSet apimachinery.ClientSetInterface, cache redis.Client, limiter *Limiter)
+		clientSet apimachinery.ClientSetInterface, cache redis.Client, limiter *Limiter, bodyLimit *BodyLimit,
+		queryCache *QueryCache)
 }
 
 // NewService create a new service instance
@@ -50,19 +51,24 @@ type service struct {
 	authorizer ac.AuthorizeInterface
 	cache      redis.Client
 	limiter    *Limiter
+	bodyLimit  *BodyLimit
+	queryCache *QueryCache
 	// noPermissionRequestTotal is the total number of request without permission
 	noPermissionRequestTotal *prometheus.CounterVec
 }
 
 // SetConfig set config
 func (s *service) SetConfig(engine *backbone.Engine, httpClient HTTPClient, discovery discovery.DiscoveryInterface,
-	clientSet apimachinery.ClientSetInterface, cache redis.Client, limiter *Limiter) {
+	clientSet apimachinery.ClientSetInterface, cache redis.Client, limiter *Limiter, bodyLimit *BodyLimit,
+	queryCache *QueryCache) {
 	s.engine = engine
 	s.client = httpClient
 	s.discovery = discovery
 	s.clientSet = clientSet
 	s.cache = cache
 	s.limiter = limiter
+	s.bodyLimit = bodyLimit
+	s.queryCache = queryCache
 	s.authorizer = iam.NewAuthorizer(clientSet)
 }
 
@@ -74,6 +80,7 @@ func (s *service) WebServices() []*restful.WebService {
 
 	ws := &restful.WebService{}
 	ws.Path(rootPath)
+	ws.Filter(s.BodyLimitFilter())
 	ws.Filter(s.JwtFilter())
 	ws.Filter(s.engine.Metric().RestfulMiddleWare)
 	ws.Filter(rdapi.AllGlobalFilter(getErrFun))
@@ -275,7 +275,7 @@ func (u *URLPath) WithEvent(req *restful.Request) (isHit bool) {
 	return false
 }
 
-const verbs = "create|createmany|update|updatemany|delete|deletemany|find|findmany"
+const verbs = "create|createmany|create_or_update|update|updatemany|delete|deletemany|find|findmany"
 
 var procUrlRegexp = regexp.MustCompile(fmt.Sprintf("^/api/v3/(%s)/proc/.*$", verbs))
 
@@ -0,0 +1,57 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditlog
+
+import (
+	"configcenter/src/apimachinery/coreservice"
+	"configcenter/src/common/metadata"
+)
+
+// ChangeBundleAuditLog is audit log handler for the change bundle executions.
+type ChangeBundleAuditLog struct {
+	audit
+}
+
+// NewChangeBundleAuditLog creates a new ChangeBundleAuditLog object.
+func NewChangeBundleAuditLog(clientSet coreservice.CoreServiceClientInterface) *ChangeBundleAuditLog {
+	return &ChangeBundleAuditLog{audit: audit{clientSet: clientSet}}
+}
+
+// GenerateAuditLog generates one audit log for the whole change bundle, the changes, their results and the
+// compensation plan are recorded as the current data. the bundle is named by the request id.
+func (l *ChangeBundleAuditLog) GenerateAuditLog(param *generateAuditCommonParameter, bizID int64,
+	opt *metadata.ChangeBundleOption, result *metadata.ChangeBundleResult, execErr error) metadata.AuditLog {
+
+	content := map[string]interface{}{
+		"mode":          opt.Mode,
+		"changes":       opt.Changes,
+		"results":       result.Changes,
+		"compensations": result.Compensations,
+	}
+	if execErr != nil {
+		content["error"] = execErr.Error()
+	}
+
+	return metadata.AuditLog{
+		AuditType:    metadata.BusinessResourceType,
+		ResourceType: metadata.ChangeBundleRes,
+		Action:       param.action,
+		BusinessID:   bizID,
+		ResourceID:   bizID,
+		OperateFrom:  param.operateFrom,
+		ResourceName: param.kit.Rid,
+		OperationDetail: &metadata.BasicOpDetail{
+			Details: param.NewBasicContent(content),
+		},
+	}
+}
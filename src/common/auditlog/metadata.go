@@ -49,7 +49,7 @@ func (a *generateAuditCommonParameter) WithUpdateFields(updateFields map[string]
 func (a *generateAuditCommonParameter) NewBasicContent(data map[string]interface{}) *metadata.BasicContent {
 	var basicDetail *metadata.BasicContent
 	switch a.action {
-	case metadata.AuditCreate:
+	case metadata.AuditCreate, metadata.AuditExecute:
 		basicDetail = &metadata.BasicContent{
 			CurData: data,
 		}
@@ -0,0 +1,67 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditlog
+
+import (
+	"configcenter/src/apimachinery/coreservice"
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// ResourceActionAuditLog is audit log handler for the custom resource action executions.
+type ResourceActionAuditLog struct {
+	audit
+}
+
+// NewResourceActionAuditLog creates a new ResourceActionAuditLog object.
+func NewResourceActionAuditLog(clientSet coreservice.CoreServiceClientInterface) *ResourceActionAuditLog {
+	return &ResourceActionAuditLog{audit: audit{clientSet: clientSet}}
+}
+
+// GenerateAuditLog generates an audit log for executing the resource action on the instance, the action and its
+// parameters and result are recorded as the current data.
+func (l *ResourceActionAuditLog) GenerateAuditLog(param *generateAuditCommonParameter, inst mapstr.MapStr,
+	action *metadata.ResourceAction, params mapstr.MapStr, result *metadata.ResourceActionResult) metadata.AuditLog {
+
+	content := map[string]interface{}{
+		"action_id":   action.ID,
+		"action_name": action.Name,
+		"params":      params,
+	}
+	if result != nil {
+		content["status_code"] = result.StatusCode
+		content["response"] = result.Response
+	}
+
+	objID := action.ObjID
+	bizID, _ := inst.Int64(common.BKAppIDField)
+	instID, _ := inst.Int64(common.GetInstIDField(objID))
+
+	return metadata.AuditLog{
+		AuditType:    metadata.GetAuditTypeByObjID(objID, false),
+		ResourceType: metadata.GetResourceTypeByObjID(objID, false),
+		Action:       param.action,
+		BusinessID:   bizID,
+		ResourceID:   instID,
+		OperateFrom:  param.operateFrom,
+		ResourceName: util.GetStrByInterface(inst[common.GetInstNameField(objID)]),
+		OperationDetail: &metadata.InstanceOpDetail{
+			BasicOpDetail: metadata.BasicOpDetail{
+				Details: param.NewBasicContent(content),
+			},
+			ModelID: objID,
+		},
+	}
+}
@@ -196,6 +196,15 @@ func NewBackbone(ctx context.Context, input *BackboneParameter) (*Engine, error)
 		return nil, fmt.Errorf("handle notice failed, err: %v", err)
 	}
 
+	engine.readOnly = &readOnlyWatcher{client: client.Client()}
+	go engine.readOnly.run(ctx)
+
+	if enabled, _ := cc.Bool("faultInject.enabled"); enabled {
+		blog.Warnf("fault injection is enabled, the faults may be injected by the rules in %s",
+			types.CC_SERVFAULTINJECT_PATH)
+		go syncFaultInjectRules(ctx, client.Client())
+	}
+
 	if err := monitor.InitMonitor(); err != nil {
 		return nil, fmt.Errorf("init monitor failed, err: %v", err)
 	}
@@ -214,9 +223,11 @@ func NewBackbone(ctx context.Context, input *BackboneParameter) (*Engine, error)
 		return nil, err
 	}
 	engine.apiMachineryConfig = &util.APIMachineryConfig{
-		QPS:       1000,
-		Burst:     2000,
-		TLSConfig: tlsConf,
+		QPS:            1000,
+		Burst:          2000,
+		TLSConfig:      tlsConf,
+		GuardConfig:    util.NewGuardConfigFromConfig("apiMachinery.guard"),
+		MetricRegister: metricService.Registry(),
 	}
 
 	machinery, err := newApiMachinery(serviceDiscovery, engine.apiMachineryConfig)
@@ -281,6 +292,9 @@ type Engine struct {
 	discovery              discovery.DiscoveryInterface
 	metric                 *metrics.Service
 
+	// readOnly keeps the read-only maintenance mode synced from zk
+	readOnly *readOnlyWatcher
+
 	sync.Mutex
 
 	RegisterPath string
@@ -397,8 +411,5 @@ func getTLSConf() (*util.TLSClientConfig, error) {
 }
 
 func isTLS(config *util.TLSClientConfig) bool {
-	if config == nil || len(config.CertFile) == 0 || len(config.KeyFile) == 0 {
-		return false
-	}
-	return true
+	return config.CertSource() != nil
 }
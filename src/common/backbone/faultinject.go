@@ -0,0 +1,62 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backbone
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/faultinject"
+	"configcenter/src/common/types"
+	"configcenter/src/common/zkclient"
+)
+
+// syncFaultInjectRules keeps the fault injection rules synced from zk, which are set by the admin server.
+func syncFaultInjectRules(ctx context.Context, client *zkclient.ZkClient) {
+	ticker := time.NewTicker(readOnlySyncInterval)
+	defer ticker.Stop()
+
+	var previous string
+	for {
+		data, err := client.Get(types.CC_SERVFAULTINJECT_PATH)
+		switch {
+		case err != nil && err != zkclient.ErrNoNode:
+			blog.Errorf("get fault injection rules from %s failed, err: %v", types.CC_SERVFAULTINJECT_PATH, err)
+		case data != previous:
+			if err := setFaultInjectRules(data); err != nil {
+				blog.Errorf("set fault injection rules %s failed, err: %v", data, err)
+				break
+			}
+			blog.Warnf("fault injection rules are changed to %s", data)
+			previous = data
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func setFaultInjectRules(data string) error {
+	rules := new(faultinject.Rules)
+	if len(data) != 0 {
+		if err := json.Unmarshal([]byte(data), rules); err != nil {
+			return err
+		}
+	}
+	return faultinject.SetRules(rules.Rules)
+}
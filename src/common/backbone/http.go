@@ -76,10 +76,13 @@ func ListenAndServe(c Server, svcDisc ServiceRegisterInterface, cancel context.C
 		return nil
 	}
 
-	tlsC, err := ssl.ServerTLSVerifyClient(c.TLS.CAFile,
-		c.TLS.CertFile,
-		c.TLS.KeyFile,
-		c.TLS.Password)
+	// the server certificate and the ca to verify the clients are reloaded when they are rotated.
+	reloader, err := ssl.GetCertReloader(c.TLS.CertSource(), c.TLS.ReloadInterval)
+	if err != nil {
+		return fmt.Errorf("load tls certificates failed. err: %v", err)
+	}
+
+	tlsC, err := reloader.ServerTLSConfig(c.TLS.ClientAuthMode)
 	if err != nil {
 		return fmt.Errorf("generate tls config failed. err: %v", err)
 	}
@@ -0,0 +1,204 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backbone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/types"
+	"configcenter/src/common/util"
+	"configcenter/src/common/zkclient"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/tidwall/gjson"
+)
+
+// readOnlySyncInterval the interval to sync the read-only mode from zk
+const readOnlySyncInterval = 5 * time.Second
+
+// readOnlyWatcher keeps the read-only mode synced from zk, which is set by the admin server.
+type readOnlyWatcher struct {
+	client *zkclient.ZkClient
+	lock   sync.RWMutex
+	mode   metadata.ReadOnlyMode
+}
+
+func (w *readOnlyWatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(readOnlySyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.sync(); err != nil {
+			blog.Errorf("sync read-only mode from %s failed, err: %v", types.CC_SERVREADONLY_PATH, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync the read-only mode from zk, the mode is disabled if it is not set. the previous mode is kept if it failed to
+// be read, so that a zk failure does not turn off the read-only mode during the maintenance.
+func (w *readOnlyWatcher) sync() error {
+	mode := metadata.ReadOnlyMode{}
+	data, err := w.client.Get(types.CC_SERVREADONLY_PATH)
+	if err != nil && err != zkclient.ErrNoNode {
+		return err
+	}
+
+	if err == nil && len(data) != 0 {
+		if err := json.Unmarshal([]byte(data), &mode); err != nil {
+			return err
+		}
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if !reflect.DeepEqual(mode, w.mode) {
+		blog.Infof("read-only mode is changed to enabled: %t, biz ids: %v, reason: %s, operator: %s", mode.Enabled,
+			mode.BizIDs, mode.Reason, mode.Operator)
+	}
+	w.mode = mode
+	return nil
+}
+
+func (w *readOnlyWatcher) get() metadata.ReadOnlyMode {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.mode
+}
+
+// ReadOnlyMode returns the current read-only maintenance mode
+func (e *Engine) ReadOnlyMode() metadata.ReadOnlyMode {
+	if e.readOnly == nil {
+		return metadata.ReadOnlyMode{}
+	}
+	return e.readOnly.get()
+}
+
+// ReadOnlyFilter rejects the mutating requests when the service is in the read-only maintenance mode
+func (e *Engine) ReadOnlyFilter() restful.FilterFunction {
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		if !e.CheckReadOnly(req, resp) {
+			return
+		}
+		chain.ProcessFilter(req, resp)
+	}
+}
+
+// CheckReadOnly checks whether the request is allowed in the read-only maintenance mode, the error is written to the
+// response and false is returned if the request modifies the data of cmdb or a read-only business.
+func (e *Engine) CheckReadOnly(req *restful.Request, resp *restful.Response) bool {
+	mode := e.ReadOnlyMode()
+	if !mode.Enabled && len(mode.BizIDs) == 0 {
+		return true
+	}
+
+	if !isMutatingRequest(req.Request) {
+		return true
+	}
+
+	rsp := metadata.BaseResp{Result: false}
+	defErr := e.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(req.Request.Header))
+	if mode.Enabled {
+		rsp.Code = common.CCErrCommReadOnlyMode
+		rsp.ErrMsg = defErr.CCErrorf(common.CCErrCommReadOnlyMode, mode.Reason).Error()
+	} else {
+		bizID := getRequestBizID(req)
+		if bizID <= 0 || !mode.IsBizReadOnly(bizID) {
+			return true
+		}
+		rsp.Code = common.CCErrCommBizReadOnlyMode
+		rsp.ErrMsg = defErr.CCErrorf(common.CCErrCommBizReadOnlyMode, bizID, mode.Reason).Error()
+	}
+
+	rid := util.GetHTTPCCRequestID(req.Request.Header)
+	blog.Warnf("reject request %s %s in read-only mode, user: %s, rid: %s", req.Request.Method,
+		req.Request.RequestURI, util.GetUser(req.Request.Header), rid)
+	if err := resp.WriteHeaderAndJson(http.StatusOK, rsp, restful.MIME_JSON); err != nil {
+		blog.Errorf("response request[url: %s] failed, err: %v, rid: %s", req.Request.RequestURI, err, rid)
+	}
+	return false
+}
+
+// the action verbs in the api path, the first verb in the path decides whether a POST request modifies the data.
+var (
+	readActionVerbs = []string{"find", "findone", "search", "list", "count", "get", "read", "query", "check",
+		"preview", "export", "watch", "fetch", "statistic", "statistics", "verify", "authorize", "smart_search"}
+	writeActionVerbs = []string{"create", "update", "delete", "add", "remove", "transfer", "move", "bind", "unbind",
+		"sync", "import", "clone", "upsert", "save", "modify", "change", "register"}
+)
+
+// isMutatingRequest checks whether the request modifies the data, the POST request is regarded as mutating unless
+// the first action verb in its path reads the data, e.g. /findmany/xxx, /hosts/search, /hosts/list_hosts.
+func isMutatingRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	case http.MethodPost:
+	default:
+		return true
+	}
+
+	for _, segment := range strings.Split(strings.ToLower(req.URL.Path), "/") {
+		if matchActionVerb(segment, writeActionVerbs) {
+			return true
+		}
+		if matchActionVerb(segment, readActionVerbs) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchActionVerb checks whether the path segment is one of the verbs, like find, findmany or find_xxx
+func matchActionVerb(segment string, verbs []string) bool {
+	for _, verb := range verbs {
+		if segment == verb || segment == verb+"many" || strings.HasPrefix(segment, verb+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// bizIDPathParams the path parameter names of the business id
+var bizIDPathParams = []string{common.BKAppIDField, "appid", "app_id", "biz_id", "bizID"}
+
+// getRequestBizID get the business id of the request from the path parameters or the body, returns 0 if not found.
+func getRequestBizID(req *restful.Request) int64 {
+	for _, name := range bizIDPathParams {
+		if value := req.PathParameter(name); len(value) != 0 {
+			if bizID, err := util.GetInt64ByInterface(value); err == nil {
+				return bizID
+			}
+		}
+	}
+
+	body, err := util.PeekRequest(req.Request)
+	if err != nil || len(body) == 0 {
+		return 0
+	}
+	return gjson.GetBytes(body, common.BKAppIDField).Int()
+}
@@ -0,0 +1,48 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backbone
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestIsMutatingRequest(t *testing.T) {
+	cases := []struct {
+		method   string
+		path     string
+		mutating bool
+	}{
+		{http.MethodGet, "/topo/v3/topo/inst/0/2", false},
+		{http.MethodPost, "/topo/v3/findmany/object", false},
+		{http.MethodPost, "/host/v3/hosts/search", false},
+		{http.MethodPost, "/host/v3/hosts/app/2/list_hosts", false},
+		{http.MethodPost, "/event/v3/watch/resource/host", false},
+		{http.MethodPost, "/topo/v3/create/object", true},
+		{http.MethodPost, "/topo/v3/createmany/object", true},
+		{http.MethodPost, "/topo/v3/create/instance/object/find", true},
+		{http.MethodPost, "/host/v3/hosts/modules", true},
+		{http.MethodPut, "/topo/v3/find/object", true},
+		{http.MethodDelete, "/topo/v3/delete/object/1", true},
+	}
+
+	for _, c := range cases {
+		req, err := http.NewRequest(c.method, "http://127.0.0.1"+c.path, nil)
+		if err != nil {
+			t.Fatalf("new request failed, err: %v", err)
+		}
+		if mutating := isMutatingRequest(req); mutating != c.mutating {
+			t.Errorf("%s %s mutating should be %t, but got %t", c.method, c.path, c.mutating, mutating)
+		}
+	}
+}
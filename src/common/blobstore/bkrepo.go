@@ -0,0 +1,139 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// bkRepoDefaultTimeout the default timeout of a request to bkrepo
+	bkRepoDefaultTimeout = 5 * time.Minute
+	// bkRepoOverwriteHeader the header to overwrite the existing file when uploading
+	bkRepoOverwriteHeader = "X-BKREPO-OVERWRITE"
+)
+
+// bkRepoStore stores the files in the generic repository of bkrepo, the files are accessed by the generic api
+// {endpoint}/generic/{project}/{repo}/{key} with the basic auth.
+type bkRepoStore struct {
+	conf   BkRepoConfig
+	client *http.Client
+}
+
+func newBkRepoStore(conf BkRepoConfig) *bkRepoStore {
+	if conf.Timeout <= 0 {
+		conf.Timeout = bkRepoDefaultTimeout
+	}
+	conf.Endpoint = strings.TrimRight(conf.Endpoint, "/")
+
+	return &bkRepoStore{
+		conf:   conf,
+		client: &http.Client{Timeout: conf.Timeout},
+	}
+}
+
+func (s *bkRepoStore) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(cleaned, "/")
+	for index := range segments {
+		segments[index] = url.PathEscape(segments[index])
+	}
+	fileURL := fmt.Sprintf("%s/generic/%s/%s/%s", s.conf.Endpoint, url.PathEscape(s.conf.Project),
+		url.PathEscape(s.conf.Repo), strings.Join(segments, "/"))
+
+	req, err := http.NewRequestWithContext(ctx, method, fileURL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(s.conf.Username, s.conf.Password)
+	return req, nil
+}
+
+func (s *bkRepoStore) do(req *http.Request) (*http.Response, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("bkrepo %s %s failed, status: %s, response: %s", req.Method, req.URL.Path, resp.Status,
+		body)
+}
+
+// Put uploads the file to bkrepo, the existing file is overwritten.
+func (s *bkRepoStore) Put(ctx context.Context, key string, data io.Reader, size int64) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	if size >= 0 {
+		req.ContentLength = size
+	}
+	req.Header.Set(bkRepoOverwriteHeader, "true")
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Get opens the file of the key
+func (s *bkRepoStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete removes the file of the key
+func (s *bkRepoStore) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		if err == ErrNotFound {
+			return nil
+		}
+		return err
+	}
+	return resp.Body.Close()
+}
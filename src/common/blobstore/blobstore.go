@@ -0,0 +1,159 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package blobstore provides the storage of the files generated by cmdb, e.g. the exported excel files, which can be
+// shared by all the service instances instead of being kept on the local disk of one instance.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	cc "configcenter/src/common/backbone/configcenter"
+)
+
+// the types of the blob store backends.
+const (
+	// TypeLocal stores the files in the local filesystem, which is only shared by the instances on the same host or
+	// with the same mounted directory.
+	TypeLocal = "local"
+	// TypeS3 stores the files in the s3 compatible object storage, e.g. aws s3, minio, tencent cloud cos.
+	TypeS3 = "s3"
+	// TypeBkRepo stores the files in the generic repository of the blueking repository(bkrepo).
+	TypeBkRepo = "bkrepo"
+)
+
+// ErrNotFound the file of the key does not exist in the blob store.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores the files by the key, the key is a slash separated path like "export/inst/xxx.xlsx".
+type BlobStore interface {
+	// Put saves the file of the key, the file is replaced if it exists. size is the length of the data, -1 if
+	// it is unknown.
+	Put(ctx context.Context, key string, data io.Reader, size int64) error
+	// Get returns the file of the key, returns ErrNotFound if it does not exist. The caller must close the file.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the file of the key, it is not an error if the file does not exist.
+	Delete(ctx context.Context, key string) error
+}
+
+// Config is the config of the blob store.
+type Config struct {
+	// Type the type of the backend, local/s3/bkrepo
+	Type   string
+	Local  LocalConfig
+	S3     S3Config
+	BkRepo BkRepoConfig
+}
+
+// LocalConfig is the config of the local filesystem backend.
+type LocalConfig struct {
+	// Path the root directory to store the files
+	Path string
+}
+
+// S3Config is the config of the s3 compatible backend.
+type S3Config struct {
+	// Endpoint the address of the object storage, use the aws s3 endpoint of the region if not set
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle use the path style url instead of the virtual hosted style, which is required by minio
+	PathStyle bool
+}
+
+// BkRepoConfig is the config of the bkrepo backend.
+type BkRepoConfig struct {
+	// Endpoint the address of the bkrepo gateway, e.g. http://bkrepo.example.com
+	Endpoint string
+	Project  string
+	Repo     string
+	Username string
+	Password string
+	// Timeout the timeout of a request to bkrepo
+	Timeout time.Duration
+}
+
+// ParseConfig parse the blob store config with the prefix, the local backend is used if the type is not set.
+func ParseConfig(prefix string) (*Config, error) {
+	conf := &Config{Type: TypeLocal}
+	if val, err := cc.String(prefix + ".type"); err == nil && len(val) != 0 {
+		conf.Type = val
+	}
+
+	switch conf.Type {
+	case TypeLocal:
+		conf.Local.Path, _ = cc.String(prefix + ".local.path")
+		if len(conf.Local.Path) == 0 {
+			return nil, fmt.Errorf("%s.local.path is not set", prefix)
+		}
+
+	case TypeS3:
+		conf.S3.Endpoint, _ = cc.String(prefix + ".s3.endpoint")
+		conf.S3.Region, _ = cc.String(prefix + ".s3.region")
+		conf.S3.Bucket, _ = cc.String(prefix + ".s3.bucket")
+		conf.S3.AccessKey, _ = cc.String(prefix + ".s3.accessKey")
+		conf.S3.SecretKey, _ = cc.String(prefix + ".s3.secretKey")
+		conf.S3.PathStyle, _ = cc.Bool(prefix + ".s3.pathStyle")
+		if len(conf.S3.Bucket) == 0 || len(conf.S3.Region) == 0 {
+			return nil, fmt.Errorf("%s.s3.bucket and %s.s3.region must be set", prefix, prefix)
+		}
+
+	case TypeBkRepo:
+		conf.BkRepo.Endpoint, _ = cc.String(prefix + ".bkrepo.endpoint")
+		conf.BkRepo.Project, _ = cc.String(prefix + ".bkrepo.project")
+		conf.BkRepo.Repo, _ = cc.String(prefix + ".bkrepo.repo")
+		conf.BkRepo.Username, _ = cc.String(prefix + ".bkrepo.username")
+		conf.BkRepo.Password, _ = cc.String(prefix + ".bkrepo.password")
+		if val, err := cc.Int(prefix + ".bkrepo.timeoutSeconds"); err == nil && val > 0 {
+			conf.BkRepo.Timeout = time.Duration(val) * time.Second
+		}
+		if len(conf.BkRepo.Endpoint) == 0 || len(conf.BkRepo.Project) == 0 || len(conf.BkRepo.Repo) == 0 {
+			return nil, fmt.Errorf("%s.bkrepo.endpoint, project and repo must be set", prefix)
+		}
+
+	default:
+		return nil, fmt.Errorf("%s.type %s is invalid, must be one of local, s3 and bkrepo", prefix, conf.Type)
+	}
+
+	return conf, nil
+}
+
+// New new the blob store of the configured backend.
+func New(conf *Config) (BlobStore, error) {
+	switch conf.Type {
+	case TypeLocal:
+		return newLocalStore(conf.Local)
+	case TypeS3:
+		return newS3Store(conf.S3)
+	case TypeBkRepo:
+		return newBkRepoStore(conf.BkRepo), nil
+	default:
+		return nil, fmt.Errorf("blob store type %s is invalid", conf.Type)
+	}
+}
+
+// cleanKey validates the key and returns its cleaned form, the key can not be empty or escape the root of the store.
+func cleanKey(key string) (string, error) {
+	cleaned := strings.TrimPrefix(path.Clean("/"+key), "/")
+	if len(cleaned) == 0 || cleaned != strings.TrimPrefix(key, "/") {
+		return "", fmt.Errorf("blob key %s is invalid", key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,120 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blobstore
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCleanKey(t *testing.T) {
+	valid := map[string]string{"export/a.xlsx": "export/a.xlsx", "/export/a.xlsx": "export/a.xlsx"}
+	for key, expected := range valid {
+		cleaned, err := cleanKey(key)
+		if err != nil || cleaned != expected {
+			t.Errorf("clean key %s failed, cleaned: %s, err: %v", key, cleaned, err)
+		}
+	}
+
+	for _, key := range []string{"", "/", "../a", "export/../../a", "export//a"} {
+		if _, err := cleanKey(key); err == nil {
+			t.Errorf("key %s should be invalid", key)
+		}
+	}
+}
+
+func testBlobStore(t *testing.T, store BlobStore) {
+	ctx := context.Background()
+	key := "export/inst/task.xlsx"
+
+	if _, err := store.Get(ctx, key); err != ErrNotFound {
+		t.Fatalf("get not existing file should return ErrNotFound, err: %v", err)
+	}
+
+	content := "exported data"
+	if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content))); err != nil {
+		t.Fatalf("put file failed, err: %v", err)
+	}
+
+	reader, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get file failed, err: %v", err)
+	}
+	data, err := ioutil.ReadAll(reader)
+	_ = reader.Close()
+	if err != nil || string(data) != content {
+		t.Fatalf("get file content %s is not expected, err: %v", data, err)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("delete file failed, err: %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("delete not existing file failed, err: %v", err)
+	}
+	if _, err := store.Get(ctx, key); err != ErrNotFound {
+		t.Fatalf("get deleted file should return ErrNotFound, err: %v", err)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	store, err := New(&Config{Type: TypeLocal, Local: LocalConfig{Path: t.TempDir()}})
+	if err != nil {
+		t.Fatalf("new local store failed, err: %v", err)
+	}
+	testBlobStore(t, store)
+}
+
+func TestBkRepoStore(t *testing.T) {
+	var lock sync.Mutex
+	files := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pwd, ok := r.BasicAuth(); !ok || user != "cmdb" || pwd != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			files[r.URL.Path], _ = ioutil.ReadAll(r.Body)
+		case http.MethodGet:
+			data, exists := files[r.URL.Path]
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(data)
+		case http.MethodDelete:
+			if _, exists := files[r.URL.Path]; !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(files, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	conf := BkRepoConfig{Endpoint: server.URL, Project: "bkcc", Repo: "files", Username: "cmdb", Password: "secret"}
+	store, err := New(&Config{Type: TypeBkRepo, BkRepo: conf})
+	if err != nil {
+		t.Fatalf("new bkrepo store failed, err: %v", err)
+	}
+	testBlobStore(t, store)
+}
@@ -0,0 +1,95 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"configcenter/src/common"
+)
+
+type localStore struct {
+	root string
+}
+
+func newLocalStore(conf LocalConfig) (*localStore, error) {
+	if err := os.MkdirAll(conf.Path, os.ModeDir|os.ModePerm); err != nil {
+		return nil, fmt.Errorf("make blob store dir %s failed, err: %v", conf.Path, err)
+	}
+	return &localStore{root: conf.Path}, nil
+}
+
+func (s *localStore) filePath(key string) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+// Put writes the file to a temporary file and renames it, so that the readers never see a partial file.
+func (s *localStore) Put(_ context.Context, key string, data io.Reader, _ int64) error {
+	name, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), os.ModeDir|os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := common.AtomicFileNew(name, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, data); err != nil {
+		_ = file.Abort()
+		return err
+	}
+	return file.Close()
+}
+
+// Get opens the file of the key
+func (s *localStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	name, err := s.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// Delete removes the file of the key
+func (s *localStore) Delete(_ context.Context, key string) error {
+	name, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+type s3Store struct {
+	bucket   string
+	client   *s3.S3
+	uploader *s3manager.Uploader
+}
+
+func newS3Store(conf S3Config) (*s3Store, error) {
+	awsConf := &aws.Config{
+		Region:           aws.String(conf.Region),
+		S3ForcePathStyle: aws.Bool(conf.PathStyle),
+	}
+	if len(conf.Endpoint) != 0 {
+		awsConf.Endpoint = aws.String(conf.Endpoint)
+	}
+	if len(conf.AccessKey) != 0 {
+		awsConf.Credentials = credentials.NewStaticCredentials(conf.AccessKey, conf.SecretKey, "")
+	}
+
+	sess, err := session.NewSession(awsConf)
+	if err != nil {
+		return nil, fmt.Errorf("new s3 session failed, err: %v", err)
+	}
+
+	client := s3.New(sess)
+	return &s3Store{
+		bucket:   conf.Bucket,
+		client:   client,
+		uploader: s3manager.NewUploaderWithClient(client),
+	}, nil
+}
+
+// Put uploads the file by the s3 upload manager, which uploads the large file in parts.
+func (s *s3Store) Put(ctx context.Context, key string, data io.Reader, _ int64) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(cleaned),
+		Body:   data,
+	})
+	return err
+}
+
+// Get opens the file of the key
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(cleaned),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+// Delete removes the file of the key, s3 does not return error if the file does not exist.
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(cleaned),
+	})
+	return err
+}
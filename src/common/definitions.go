@@ -164,6 +164,9 @@ const (
 	// BKDBAND the db operator
 	BKDBAND = "$and"
 
+	// BKDBNOR the db operator
+	BKDBNOR = "$nor"
+
 	// BKDBLIKE the db operator
 	BKDBLIKE = "$regex"
 
@@ -235,6 +238,15 @@ const (
 	// Querying by data type is useful when dealing with highly unstructured data where data types are not predictable.
 	BKDBType = "$type"
 
+	// BKDBExpr allows the use of aggregation expressions within the query language.
+	BKDBExpr = "$expr"
+
+	// BKDBCond evaluates a boolean expression to return one of the two specified return expressions.
+	BKDBCond = "$cond"
+
+	// BKDBIsArray determines if the operand is an array.
+	BKDBIsArray = "$isArray"
+
 	// BKDBSort the db operator
 	BKDBSort = "$sort"
 
@@ -281,9 +293,18 @@ const (
 	// BKHostInnerIPField the host innerip field
 	BKHostInnerIPField = "bk_host_innerip"
 
+	// BKHostLifecycleStateField the host lifecycle state field
+	BKHostLifecycleStateField = "bk_lifecycle_state"
+
 	// BKHostCloudRegionField the host cloud region field
 	BKHostCloudRegionField = "bk_cloud_region"
 
+	// BKHostCloudZoneField the host cloud availability zone field
+	BKHostCloudZoneField = "bk_cloud_zone"
+
+	// BKHostRackField the host rack field
+	BKHostRackField = "bk_rack"
+
 	// BKHostOuterIPField the host outerip field
 	BKHostOuterIPField = "bk_host_outerip"
 
@@ -338,6 +359,24 @@ const (
 	// BKOSNameField the os name field
 	BKOSNameField = "bk_os_name"
 
+	// BKOSFamilyField the os family field derived from the host snapshot, e.g. rhel, debian
+	BKOSFamilyField = "bk_os_family"
+
+	// BKOSKernelVersionField the os kernel version field derived from the host snapshot
+	BKOSKernelVersionField = "bk_os_kernel_version"
+
+	// BKVirtualizationTypeField the virtualization type field derived from the host snapshot, e.g. kvm, physical
+	BKVirtualizationTypeField = "bk_virtualization_type"
+
+	// BKContainerRuntimeField the container runtime field derived from the host snapshot, empty if there's none
+	BKContainerRuntimeField = "bk_container_runtime"
+
+	// BKProbeStatusField the latest connectivity probe status of the host, reachable/unreachable/unknown
+	BKProbeStatusField = "bk_probe_status"
+
+	// BKProbeTimeField the time of the latest connectivity probe of the host
+	BKProbeTimeField = "bk_probe_time"
+
 	// BKHttpGet the http get
 	BKHttpGet = "GET"
 
@@ -464,6 +503,9 @@ const (
 	// BKResourceNameField the audit resource name field
 	BKResourceNameField = "resource_name"
 
+	// BKChangeSetIDField the audit change set id field
+	BKChangeSetIDField = "change_set_id"
+
 	// BKLabelField the audit resource name field
 	BKLabelField = "label"
 
@@ -657,6 +699,13 @@ const (
 	// BKProcInstNum TODO
 	BKProcInstNum = "proc_num"
 
+	// BKProcCommandsField the structured start/stop/reload command templates of the process
+	BKProcCommandsField = "bk_proc_commands"
+	// BKProcEnvsField the environment variables of the process
+	BKProcEnvsField = "bk_proc_envs"
+	// BKConfigTemplatesField the config template references of the process or service instance
+	BKConfigTemplatesField = "bk_config_templates"
+
 	// BKInstKeyField the inst key field for metric discover
 	BKInstKeyField = "bk_inst_key"
 
@@ -702,6 +751,8 @@ const (
 	BKStartAtTimeField = "start_at_time"
 	// BKSubResourceField TODO
 	BKSubResourceField = "bk_sub_resource"
+	// BKChangedFieldsField the fields changed by the update event
+	BKChangedFieldsField = "bk_changed_fields"
 
 	// BKBizSetIDField TODO
 	BKBizSetIDField = "bk_biz_set_id"
@@ -867,6 +918,9 @@ const (
 	// FieldTypeEnum the enum field type
 	FieldTypeEnum string = "enum"
 
+	// FieldTypeEnumCascade the cascade enum field type, whose options depend on the value of its parent attribute
+	FieldTypeEnumCascade string = "enumcascade"
+
 	// FieldTypeDate the date field type
 	FieldTypeDate string = "date"
 
@@ -1051,6 +1105,9 @@ const (
 const (
 	EventCacheEventIDKey = BKCacheKeyV3Prefix + "event:inst_id"
 	RedisSnapKeyPrefix   = BKCacheKeyV3Prefix + "snapshot:"
+	// RedisAgentLastSeenKey the hash of the hosts' last snapshot report time, the field is the host id and the
+	// value is the unix timestamp, which is used to tell how long the host's agent has been offline.
+	RedisAgentLastSeenKey = BKCacheKeyV3Prefix + "host:agent_last_seen"
 )
 const (
 	// RedisSentinelMode redis mode is sentinel
@@ -1062,8 +1119,13 @@ const (
 // api cache keys
 const (
 	ApiCacheLimiterRulePrefix = BKCacheKeyV3Prefix + "api:limiter_rule:"
+	// ApiQueryCachePrefix the prefix of the cached query api responses
+	ApiQueryCachePrefix = BKCacheKeyV3Prefix + "api:query_cache:"
 )
 
+// AttributeUsageKeyPrefix the prefix of the redis hash that stores the sampled usages of a model's attributes
+const AttributeUsageKeyPrefix = BKCacheKeyV3Prefix + "attr_usage:"
+
 const (
 	// BKHTTPHeaderUser current request http request header fields name for login user
 	BKHTTPHeaderUser = "BK_User"
@@ -1091,10 +1153,24 @@ const (
 	BKHTTPSecretsProject = "BK-Secrets-Project"
 	// BKHTTPSecretsEnv TODO
 	BKHTTPSecretsEnv = "BK-Secrets-Env"
+	// BKHTTPProbeToken the token to access the external host prober
+	BKHTTPProbeToken = "BK-Probe-Token"
 	// BKHTTPReadReference  query db use secondary node
 	BKHTTPReadReference = "Cc_Read_Preference"
 	// BKHTTPRequestFromWeb represents if request is from web server
 	BKHTTPRequestFromWeb = "Cc_Request_From_Web"
+	// BKHTTPQueryCacheBypass represents the query api response should not be read from the cache if it is "true"
+	BKHTTPQueryCacheBypass = "Cc_Query_Cache_Bypass"
+	// BKHTTPImportSource the ingestion path of the request, e.g. cloud_sync, it is used to resolve the conflicts when
+	// the different ingestion paths update the same instance attributes within a short window
+	BKHTTPImportSource = "Cc_Import_Source"
+	// BKHTTPImportConflictPolicy the conflict policy of the import request
+	BKHTTPImportConflictPolicy = "Cc_Import_Conflict_Policy"
+	// BKHTTPImportDataTime the unix time when the imported data is collected, default is the request time
+	BKHTTPImportDataTime = "Cc_Import_Data_Time"
+	// BKHTTPChangeSetID the change set id of the request, the audit logs of the requests with the same change set id
+	// are grouped together, the transaction id is used if it is not set
+	BKHTTPChangeSetID = "Cc_Change_Set_Id"
 )
 
 // ReadPreferenceMode TODO
@@ -1134,6 +1210,8 @@ const (
 const (
 	TransactionIdHeader      = "cc_transaction_id_string"
 	TransactionTimeoutHeader = "cc_transaction_timeout"
+	// TransactionSuppressEventHeader marks the changes of the transaction to be excluded from the watch events.
+	TransactionSuppressEventHeader = "cc_transaction_suppress_event"
 
 	// mongodb default transaction timeout is 1 minute.
 	TransactionDefaultTimeout = 2 * time.Minute
@@ -1142,6 +1220,8 @@ const (
 const (
 	// DefaultAppLifeCycleNormal  biz life cycle normal
 	DefaultAppLifeCycleNormal = "2"
+	// DefaultAppLifeCycleStopped biz life cycle stopped, the business is out of service
+	DefaultAppLifeCycleStopped = "3"
 )
 
 // Host OS type enumeration value
@@ -1467,6 +1547,14 @@ const (
 	// SyncServiceTemplateHostApplyTaskFlag  service template dimension host auto-apply async task flag.
 	SyncServiceTemplateHostApplyTaskFlag = "service_template_host_apply_sync"
 
+	// ModelDeleteCascadeTaskFlag model or model attribute deletion with cascade policy async task flag.
+	ModelDeleteCascadeTaskFlag = "model_delete_cascade"
+
+	// InstImportTaskFlag instance excel import job async task flag, each sub task imports a chunk of the rows.
+	InstImportTaskFlag = "inst_import"
+	// InstExportTaskFlag instance excel export job async task flag, each sub task exports a chunk of the instances.
+	InstExportTaskFlag = "inst_export"
+
 	// BKHostState TODO
 	BKHostState = "bk_state"
 )
@@ -230,6 +230,27 @@ const (
 	// CCIllegalRegularExpression the regular expression's type assertion failed
 	CCIllegalRegularExpression = 1199090
 
+	// CCErrCommRequestBodyTooLarge the request body exceeds the size limit, one argument: the limit in bytes
+	CCErrCommRequestBodyTooLarge = 1199091
+
+	// CCErrCommReadOnlyMode cmdb is in the read-only maintenance mode, one argument: the reason
+	CCErrCommReadOnlyMode = 1199092
+
+	// CCErrCommBizReadOnlyMode the business is in the read-only maintenance mode, two arguments: the business id and
+	// the reason
+	CCErrCommBizReadOnlyMode = 1199093
+
+	// CCErrCommFaultInjectDisabled the fault injection is not enabled by faultInject.enabled config
+	CCErrCommFaultInjectDisabled = 1199094
+
+	// CCErrCommPageLimitExceedMax the page limit exceeds the max limit of the endpoint, three arguments: the limit,
+	// the endpoint and the max limit
+	CCErrCommPageLimitExceedMax = 1199095
+
+	// CCErrCommTemplateVariableNotBound the template variables referenced by the template are not bound on the
+	// instance, three arguments: the variable names, the object id and the instance id
+	CCErrCommTemplateVariableNotBound = 1199096
+
 	// too many requests
 	CCErrTooManyRequestErr = 1199997
 
@@ -495,6 +516,31 @@ const (
 	CCErrUpdateModuleAttributesFail                   = 1101117
 	CCErrorBizSetNameDuplicated                       = 1101118
 
+	// CCErrTopoObjectAttrHasDataForbiddenToDelete the model attribute has instance data, can not be deleted.
+	CCErrTopoObjectAttrHasDataForbiddenToDelete = 1101119
+	// CCErrTopoDeleteBlockedByPolicy the deletion is rejected by the cascade policy.
+	CCErrTopoDeleteBlockedByPolicy = 1101120
+	// CCErrTopoInstMergeConflict the merged instances have conflicting attribute values which are not resolved.
+	CCErrTopoInstMergeConflict = 1101121
+	// CCErrTopoResourceActionExecuteFailed executing the custom resource action failed.
+	CCErrTopoResourceActionExecuteFailed = 1101122
+	// CCErrTopoChangeBundleExecuteFailed executing a change of the change bundle failed.
+	CCErrTopoChangeBundleExecuteFailed = 1101123
+	// CCErrTopoChangeBundleCompensateFailed the change bundle failed, and some executed changes are not compensated.
+	CCErrTopoChangeBundleCompensateFailed = 1101124
+	// CCErrTopoAttributeLocked the model attribute is locked by the platform, it can not be changed by the business.
+	CCErrTopoAttributeLocked = 1101125
+	// CCErrTopoModelBundleNotInstallable the model bundle conflicts with the existing models or lacks dependencies.
+	CCErrTopoModelBundleNotInstallable = 1101126
+	// CCErrTopoChildNodeOverLimit the number of the child nodes under the parent node exceeds the limit.
+	CCErrTopoChildNodeOverLimit = 1101127
+	// CCErrTopoFullTextReindexRunning the fulltext index is being rebuilt by another reindex task.
+	CCErrTopoFullTextReindexRunning = 1101128
+	// CCErrTopoFullTextReindexTaskNotFound the fulltext reindex task does not exist or is not running.
+	CCErrTopoFullTextReindexTaskNotFound = 1101129
+	// CCErrTopoFullTextReindexFailed starting or canceling the fulltext reindex task failed.
+	CCErrTopoFullTextReindexFailed = 1101130
+
 	// object controller 1102XXX
 
 	// CCErrObjectPropertyGroupInsertFailed failed to save the property group
@@ -541,6 +587,12 @@ const (
 	CCErrEventGetTaskStatusTimeout       = 1103009
 	CCErrEventPushHostIdentifierFailed   = 1103010
 	CCErrEventSyncHostIdentifierDisabled = 1103011
+	// CCErrEventNamedCursorOwned 命名游标%s正在被消费者%s使用
+	CCErrEventNamedCursorOwned = 1103012
+	// CCErrEventNamedCursorLeaseLost 消费者%s未持有命名游标%s的租约
+	CCErrEventNamedCursorLeaseLost = 1103013
+	// CCErrEventNamedCursorNotExist 命名游标%s不存在
+	CCErrEventNamedCursorNotExist = 1103014
 
 	// host 1104XXX
 	CCErrHostModuleRelationAddFailed = 1104000
@@ -721,6 +773,24 @@ const (
 	CCErrHostFindManyCloudAreaAddHostCountFieldFail           = 1110065
 	CCErrDeleteDefaultCloudAreaFail                           = 1110066
 	CCErrHostFindManyCloudAreaAddSyncTaskIDsFieldFail         = 1110067
+	// CCErrHostDynamicGroupCompositionCycle 动态分组%s存在循环引用
+	CCErrHostDynamicGroupCompositionCycle = 1110068
+	// CCErrHostDynamicGroupCompositionTooDeep 动态分组的组合层级超过上限%d
+	CCErrHostDynamicGroupCompositionTooDeep = 1110069
+	// CCErrHostDynamicGroupReferenced 动态分组被动态分组%s引用，不能删除
+	CCErrHostDynamicGroupReferenced = 1110070
+	// CCErrHostCloudAreaHasHosts 云区域%d下存在%d台主机，请先迁移主机
+	CCErrHostCloudAreaHasHosts = 1110071
+	// CCErrHostCloudAreaUsedBySyncTask 云区域%d被云同步任务%v使用
+	CCErrHostCloudAreaUsedBySyncTask = 1110072
+	// CCErrHostRecycleReportNotPending 主机回收报告%d不是待审批状态
+	CCErrHostRecycleReportNotPending = 1110073
+	// CCErrHostDynamicGroupDuplicateQuery 动态分组的查询条件与已存在的动态分组%s相同
+	CCErrHostDynamicGroupDuplicateQuery = 1110074
+	// CCErrHostProbeMethodNotSupported 主机连通性探测方式%s未启用
+	CCErrHostProbeMethodNotSupported = 1110075
+	// CCErrHostProbeFailed 主机连通性探测失败: %s
+	CCErrHostProbeFailed = 1110076
 
 	// web 1111XXX
 	CCErrWebFileNoFound                 = 1111001
@@ -835,6 +905,30 @@ const (
 	// CCERrrCoreServiceSupersetUniqueRuleExist 所选字段组合和已有规则重复，请勿创建冗余规则
 	CCERrrCoreServiceSupersetUniqueRuleExist = 1113052
 	CCERrrCoreServiceConcurrent              = 1113053
+	// CCErrCoreServiceHostLifecycleTransitionNotAllowed 主机生命周期状态不允许从%s变更为%s
+	CCErrCoreServiceHostLifecycleTransitionNotAllowed = 1113054
+	// CCErrCoreServiceHostLifecycleRequiredAttr 主机生命周期状态变更为%s时字段%s必填
+	CCErrCoreServiceHostLifecycleRequiredAttr = 1113055
+	// CCErrCoreServiceTemplateAttrLocked 字段%s已被平台锁定，不允许在模板中配置
+	CCErrCoreServiceTemplateAttrLocked = 1113056
+	// CCErrCoreServiceAttrGroupDelegated 字段%s所在分组已委派给角色%s，当前用户无权编辑
+	CCErrCoreServiceAttrGroupDelegated = 1113057
+	// CCErrCoreServiceSchemaNotWritable 模型或字段%s处于%s阶段，不允许写入
+	CCErrCoreServiceSchemaNotWritable = 1113058
+	// CCErrCoreServiceSrvInstNameDuplicated 服务实例名称 %s 在模块中已存在
+	CCErrCoreServiceSrvInstNameDuplicated = 1113059
+	// CCErrCoreServiceAttrOwnedBySource 字段%s由外部来源%s维护，不允许修改
+	CCErrCoreServiceAttrOwnedBySource = 1113060
+	// CCErrCoreServiceModelAssetReferred 模型资源%s正在被%d个模型或分类引用，不允许删除
+	CCErrCoreServiceModelAssetReferred = 1113061
+	// CCErrCoreServiceEnumCascadeValueInvalid 字段%s的值%s不是字段%s取值为%s时的可选项
+	CCErrCoreServiceEnumCascadeValueInvalid = 1113062
+	// CCErrCoreServiceEnumCascadeParentReferred 字段%s是级联枚举字段%s的上级字段，不允许删除
+	CCErrCoreServiceEnumCascadeParentReferred = 1113063
+	// CCErrCoreServiceExternalIDConflict 来源%s的外部ID%s已关联到实例%d
+	CCErrCoreServiceExternalIDConflict = 1113064
+	// CCErrCoreServiceMergeHostNotInSameBiz 合并的主机%d和%d不在同一个业务下
+	CCErrCoreServiceMergeHostNotInSameBiz = 1113065
 
 	// CCErrCoreServiceResourceDirectoryNotExistErr 资源池目录不存在
 	CCErrCoreServiceResourceDirectoryNotExistErr = 1113033
@@ -868,6 +962,8 @@ const (
 	CCErrOperationUpdateChartFail         = 1116006
 	CCErrOperationGetChartDataFail        = 1116007
 	CCErrOperationUpdateChartPositionFail = 1116008
+	// CCErrOperationSendReportFail failed to send the subscribed report, err: %s
+	CCErrOperationSendReportFail = 1116009
 
 	// task_server 1117xxx
 	// CCErrTaskNotFound task not found
@@ -882,6 +978,12 @@ const (
 	CCErrTaskUnLockedTaskFail     = 1117006
 	CCErrTaskListTaskFail         = 1117007
 	CCErrTaskCreateConflict       = 1117008
+	// CCErrTaskResumeNotFailed only the failed task can be resumed, task %s status is %s
+	CCErrTaskResumeNotFailed = 1117009
+	// CCErrTaskFileNotReady the file of task %s is not ready, task status is %s
+	CCErrTaskFileNotReady = 1117010
+	// CCErrTaskFileExpired the file of task %s is expired
+	CCErrTaskFileExpired = 1117011
 
 	// cloud_server 1118xxx
 	// CCErrCloudVendorNotSupport cloud vendor not support
@@ -49,7 +49,7 @@ func (cli *ccErrorHelper) Error(language string, errCode int) error {
 
 // Errorf returns an error that adapt to the error interface which accepts arguments
 func (cli *ccErrorHelper) Errorf(language string, ErrorCode int, args ...interface{}) error {
-	return &ccError{code: ErrorCode, callback: func() string {
+	return &ccError{code: ErrorCode, args: args, callback: func() string {
 		return cli.errorStrf(language, ErrorCode, args...)
 	}}
 }
@@ -63,7 +63,7 @@ func (cli *ccErrorHelper) CCError(language string, errCode int) CCErrorCoder {
 
 // CCErrorf returns an error that adapt to the error interface which accepts arguments
 func (cli *ccErrorHelper) CCErrorf(language string, ErrorCode int, args ...interface{}) CCErrorCoder {
-	return &ccError{code: ErrorCode, callback: func() string {
+	return &ccError{code: ErrorCode, args: args, callback: func() string {
 		return cli.errorStrf(language, ErrorCode, args...)
 	}}
 }
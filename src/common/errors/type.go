@@ -33,4 +33,8 @@ type CCError interface {
 type ccError struct {
 	code     int
 	callback func() string
+	// args the arguments to format the error message
+	args []interface{}
+	// cause the error that causes this error, which can be got by errors.Unwrap
+	cause error
 }
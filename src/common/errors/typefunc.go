@@ -21,3 +21,25 @@ func (cli *ccError) Error() string {
 func (cli *ccError) GetCode() int {
 	return cli.code
 }
+
+// GetArgs returns the arguments to format the error message
+func (cli *ccError) GetArgs() []interface{} {
+	return cli.args
+}
+
+// Unwrap returns the error that causes this error
+func (cli *ccError) Unwrap() error {
+	return cli.cause
+}
+
+// Is reports whether the error matches the target, the target matches if it is a cc error with the same code, or
+// the class of the error's code.
+func (cli *ccError) Is(target error) bool {
+	switch t := target.(type) {
+	case Class:
+		return GetClass(cli.code) == t
+	case CCErrorCoder:
+		return t.GetCode() == cli.code
+	}
+	return false
+}
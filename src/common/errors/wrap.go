@@ -0,0 +1,227 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errors
+
+import (
+	stderrors "errors"
+	"sync"
+
+	"configcenter/src/common"
+)
+
+// Class is the class of the error codes, the scene servers can match the errors by their classes instead of
+// comparing the codes or the messages one by one, e.g. errors.Is(err, errors.ClassNotFound).
+type Class string
+
+// Error implementation of error interface, so that the class can be used as the target of errors.Is
+func (c Class) Error() string {
+	return string(c)
+}
+
+const (
+	// ClassUnknown the error code is not classified
+	ClassUnknown Class = "unknown"
+	// ClassInvalidParam the request parameters are invalid
+	ClassInvalidParam Class = "invalid_param"
+	// ClassNotFound the requested resource is not found
+	ClassNotFound Class = "not_found"
+	// ClassConflict the request conflicts with the existing resources, e.g. duplicated
+	ClassConflict Class = "conflict"
+	// ClassForbidden the operation is forbidden on the resource
+	ClassForbidden Class = "forbidden"
+	// ClassNoPermission the user has no permission to do the operation
+	ClassNoPermission Class = "no_permission"
+	// ClassLimitExceeded the request exceeds the limit
+	ClassLimitExceeded Class = "limit_exceeded"
+	// ClassDependency the dependent service or storage failed
+	ClassDependency Class = "dependency"
+	// ClassInternal the server failed internally
+	ClassInternal Class = "internal"
+)
+
+var (
+	classLock sync.RWMutex
+	// codeClasses the classes of the error codes
+	codeClasses = map[int]Class{
+		common.CCErrCommJSONUnmarshalFailed:      ClassInvalidParam,
+		common.CCErrCommHTTPInputInvalid:         ClassInvalidParam,
+		common.CCErrCommHTTPReadBodyFailed:       ClassInvalidParam,
+		common.CCErrCommHTTPBodyEmpty:            ClassInvalidParam,
+		common.CCErrCommParamsInvalid:            ClassInvalidParam,
+		common.CCErrCommParamsNeedString:         ClassInvalidParam,
+		common.CCErrCommParamsLostField:          ClassInvalidParam,
+		common.CCErrCommParamsNeedInt:            ClassInvalidParam,
+		common.CCErrCommParamsNeedSet:            ClassInvalidParam,
+		common.CCErrCommParamsIsInvalid:          ClassInvalidParam,
+		common.CCErrCommParamsNeedTimeZone:       ClassInvalidParam,
+		common.CCErrCommParamsNeedBool:           ClassInvalidParam,
+		common.CCErrCommFieldNotValid:            ClassInvalidParam,
+		common.CCErrCommParamsShouldBeString:     ClassInvalidParam,
+		common.CCErrCommParamsShouldBeEnum:       ClassInvalidParam,
+		common.CCErrCommParamsNeedFloat:          ClassInvalidParam,
+		common.CCErrCommUnexpectedParameterField: ClassInvalidParam,
+		common.CCErrCommParamsValueInvalidError:  ClassInvalidParam,
+		common.CCErrCommUnexpectedFieldType:      ClassInvalidParam,
+
+		common.CCErrCommNotFound:                ClassNotFound,
+		common.CCErrCommInstFieldNotFound:       ClassNotFound,
+		common.CCErrCommTopoModuleNotFoundError: ClassNotFound,
+		common.CCErrCommBizNotFoundError:        ClassNotFound,
+
+		common.CCErrCommDuplicateItem:     ClassConflict,
+		common.CCErrCommOPInProgressErr:   ClassConflict,
+		common.CCErrCommGetMultipleObject: ClassConflict,
+
+		common.CCErrCommOperateBuiltInItemForbidden:                     ClassForbidden,
+		common.CCErrCommRemoveRecordHasChildrenForbidden:                ClassForbidden,
+		common.CCErrCommRemoveReferencedRecordForbidden:                 ClassForbidden,
+		common.CCErrCommForbiddenOperateMainlineInstanceWithCommonAPI:   ClassForbidden,
+		common.CCErrCommModifyFieldForbidden:                            ClassForbidden,
+		common.CCErrCommForbiddenOperateInnerModelInstanceWithCommonAPI: ClassForbidden,
+
+		common.CCErrCommAuthNotHavePermission: ClassNoPermission,
+		common.CCErrCommNotAuthItem:           ClassNoPermission,
+
+		common.CCErrCommOverLimit:           ClassLimitExceeded,
+		common.CCErrCommXXExceedLimit:       ClassLimitExceeded,
+		common.CCErrCommPageLimitIsExceeded: ClassLimitExceeded,
+		common.CCErrCommValExceedMaxFailed:  ClassLimitExceeded,
+		common.CCErrCommRequestBodyTooLarge: ClassLimitExceeded,
+
+		common.CCErrCommHTTPDoRequestFailed:     ClassDependency,
+		common.CCErrCommDBSelectFailed:          ClassDependency,
+		common.CCErrCommDBInsertFailed:          ClassDependency,
+		common.CCErrCommDBUpdateFailed:          ClassDependency,
+		common.CCErrCommDBDeleteFailed:          ClassDependency,
+		common.CCErrCommRedisOPErr:              ClassDependency,
+		common.CCErrCommStartTransactionFailed:  ClassDependency,
+		common.CCErrCommCommitTransactionFailed: ClassDependency,
+		common.CCErrCommAbortTransactionFailed:  ClassDependency,
+
+		common.CCErrCommInternalServerError:      ClassInternal,
+		common.CCErrorUnknownOrUnrecognizedError: ClassInternal,
+	}
+)
+
+// RegisterClass registers the class of the error codes, the codes of the scene servers can be classified by it.
+func RegisterClass(class Class, codes ...int) {
+	classLock.Lock()
+	defer classLock.Unlock()
+
+	for _, code := range codes {
+		codeClasses[code] = class
+	}
+}
+
+// GetClass returns the class of the error code
+func GetClass(code int) Class {
+	classLock.RLock()
+	defer classLock.RUnlock()
+
+	if class, exists := codeClasses[code]; exists {
+		return class
+	}
+	return ClassUnknown
+}
+
+// Wrap returns a copy of the cc error with the cause, so that the cause can be matched by errors.Is and errors.As
+func Wrap(cause error, ccErr CCErrorCoder) CCErrorCoder {
+	if ccErr == nil {
+		return nil
+	}
+
+	wrapped := &ccError{code: ccErr.GetCode(), callback: ccErr.Error, cause: cause}
+	if e, ok := ccErr.(*ccError); ok {
+		wrapped.callback = e.callback
+		wrapped.args = e.args
+	}
+	return wrapped
+}
+
+// AsCCError returns the first cc error in the error's chain
+func AsCCError(err error) (CCErrorCoder, bool) {
+	var ccErr CCErrorCoder
+	if !stderrors.As(err, &ccErr) {
+		return nil, false
+	}
+	return ccErr, true
+}
+
+// GetCode returns the code of the first cc error in the error's chain
+func GetCode(err error) (int, bool) {
+	ccErr, ok := AsCCError(err)
+	if !ok {
+		return 0, false
+	}
+	return ccErr.GetCode(), true
+}
+
+// IsCode reports whether any cc error in the error's chain has the code
+func IsCode(err error, code int) bool {
+	return stderrors.Is(err, &ccError{code: code})
+}
+
+// Detail is the machine-readable detail of the error, which is returned to the client with the error message
+type Detail struct {
+	Code  int           `json:"code"`
+	Class Class         `json:"class"`
+	Args  []interface{} `json:"args,omitempty"`
+	// Message the error message, which is only set for the causes, the message of the error itself is returned
+	// as the bk_error_msg
+	Message string  `json:"message,omitempty"`
+	Cause   *Detail `json:"cause,omitempty"`
+}
+
+// GetDetail returns the detail of the error and its causes, returns nil if the error is not a cc error.
+func GetDetail(err error) *Detail {
+	ccErr, ok := err.(CCErrorCoder)
+	if !ok {
+		return nil
+	}
+
+	detail := &Detail{Code: ccErr.GetCode(), Class: GetClass(ccErr.GetCode())}
+	if e, ok := ccErr.(*ccError); ok {
+		detail.Args = e.args
+	}
+
+	cause := stderrors.Unwrap(err)
+	if cause == nil {
+		return detail
+	}
+
+	if causeDetail := GetDetail(cause); causeDetail != nil {
+		causeDetail.Message = cause.Error()
+		detail.Cause = causeDetail
+		return detail
+	}
+
+	// the cause is not a cc error, only its message is kept.
+	detail.Cause = &Detail{Code: common.CCErrorUnknownOrUnrecognizedError, Class: ClassUnknown,
+		Message: cause.Error()}
+	return detail
+}
+
+// NewFromDetail creates the error with the message and the detail got from the response of the other service, so
+// that the error can be matched by its code, its class and its causes as it is in the service.
+func NewFromDetail(code int, msg string, detail *Detail) CCErrorCoder {
+	err := &ccError{code: code, callback: func() string { return msg }}
+	if detail == nil {
+		return err
+	}
+
+	err.args = detail.Args
+	if detail.Cause != nil {
+		err.cause = NewFromDetail(detail.Cause.Code, detail.Cause.Message, detail.Cause)
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"configcenter/src/common"
+)
+
+func TestWrap(t *testing.T) {
+	dbErr := stderrors.New("connection refused")
+	cause := Wrap(dbErr, New(common.CCErrCommDBSelectFailed, "select failed"))
+	err := Wrap(cause, New(common.CCErrCommNotFound, "not found"))
+
+	if !stderrors.Is(err, dbErr) {
+		t.Errorf("wrapped error should match the root cause")
+	}
+
+	if !stderrors.Is(err, ClassNotFound) || !stderrors.Is(err, ClassDependency) {
+		t.Errorf("wrapped error should match the classes of itself and its cause")
+	}
+
+	if stderrors.Is(err, ClassConflict) {
+		t.Errorf("wrapped error should not match the class of neither itself nor its cause")
+	}
+
+	if !IsCode(fmt.Errorf("find host: %w", err), common.CCErrCommDBSelectFailed) {
+		t.Errorf("wrapped error should match the code of its cause")
+	}
+
+	if code, ok := GetCode(fmt.Errorf("find host: %w", err)); !ok || code != common.CCErrCommNotFound {
+		t.Errorf("get code of wrapped error got %d, want %d", code, common.CCErrCommNotFound)
+	}
+}
+
+func TestDetail(t *testing.T) {
+	cause := Wrap(stderrors.New("connection refused"), New(common.CCErrCommDBSelectFailed, "select failed"))
+	err := Wrap(cause, &ccError{code: common.CCErrCommParamsInvalid, args: []interface{}{"bk_host_id"},
+		callback: func() string { return "bk_host_id is invalid" }})
+
+	data, jsErr := json.Marshal(GetDetail(err))
+	if jsErr != nil {
+		t.Fatalf("marshal error detail failed, err: %v", jsErr)
+	}
+
+	detail := new(Detail)
+	if jsErr := json.Unmarshal(data, detail); jsErr != nil {
+		t.Fatalf("unmarshal error detail failed, err: %v", jsErr)
+	}
+
+	received := NewFromDetail(common.CCErrCommParamsInvalid, "bk_host_id is invalid", detail)
+	if !stderrors.Is(received, ClassInvalidParam) || !IsCode(received, common.CCErrCommDBSelectFailed) {
+		t.Errorf("received error should match the class and the cause as the original error")
+	}
+
+	if args := received.(*ccError).GetArgs(); len(args) != 1 || args[0] != "bk_host_id" {
+		t.Errorf("received error args got %v, want [bk_host_id]", args)
+	}
+
+	if received.Error() != err.Error() {
+		t.Errorf("received error message got %s, want %s", received.Error(), err.Error())
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package faultinject injects latency and errors into the mongo, redis and inter-service calls by rules, it is used
+// to run the resilience tests of the workflows in the staging environment. the rules are set by the admin server and
+// only take effect in the services with faultInject.enabled configured, otherwise Inject does nothing.
+package faultinject
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/util"
+)
+
+// Target is the kind of the call that the fault is injected into
+type Target string
+
+const (
+	// Mongo the mongodb operations, the resource is "<collection>.<operation>", e.g. cc_HostBase.find
+	Mongo Target = "mongo"
+	// Redis the redis commands, the resource is "<command>:<key>", e.g. get:cc:v3:lock
+	Redis Target = "redis"
+	// HTTP the inter-service calls by apimachinery, the resource is "<method> <url path>",
+	// e.g. POST /api/v3/update/model/instance/object/host
+	HTTP Target = "http"
+)
+
+const (
+	// MaxRuleCount the max number of the fault injection rules
+	MaxRuleCount = 50
+	// MaxDelay the max latency that can be injected by a rule
+	MaxDelay = time.Minute
+)
+
+// Rule is a fault injection rule, the latency is injected before the call and then the error is returned instead of
+// doing the call if the error is set.
+type Rule struct {
+	// Name the unique name of the rule
+	Name   string `json:"name"`
+	Target Target `json:"target"`
+	// Services the services to inject the fault, e.g. coreservice, all the services are matched if it is empty.
+	Services []string `json:"services"`
+	// Resource the regular expression to match the resource of the call, all the calls are matched if it is empty.
+	Resource string `json:"resource"`
+	// DelayMilliseconds the latency injected before the call
+	DelayMilliseconds int64 `json:"delay_ms"`
+	// Error the message of the error returned instead of doing the call
+	Error string `json:"error"`
+	// Percent the percentage of the matched calls to inject the fault, ranges in (0, 100].
+	Percent float64 `json:"percent"`
+	// ExpireAt the rule is ignored after this time, so that a forgotten rule does not break the environment.
+	ExpireAt time.Time `json:"expire_at"`
+}
+
+// Rules is the fault injection rules stored in zk
+type Rules struct {
+	Rules      []Rule    `json:"rules"`
+	Operator   string    `json:"operator"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// Validate validates the fault injection rules
+func (r *Rules) Validate() errors.RawErrorInfo {
+	if len(r.Rules) > MaxRuleCount {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommXXExceedLimit, Args: []interface{}{"rules", MaxRuleCount}}
+	}
+
+	names := make(map[string]struct{}, len(r.Rules))
+	for _, rule := range r.Rules {
+		if len(rule.Name) == 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"name"}}
+		}
+		if _, exists := names[rule.Name]; exists {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommDuplicateItem, Args: []interface{}{rule.Name}}
+		}
+		names[rule.Name] = struct{}{}
+
+		switch rule.Target {
+		case Mongo, Redis, HTTP:
+		default:
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"target"}}
+		}
+
+		if _, err := regexp.Compile(rule.Resource); err != nil {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"resource"}}
+		}
+
+		if rule.DelayMilliseconds < 0 || time.Duration(rule.DelayMilliseconds)*time.Millisecond > MaxDelay {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"delay_ms"}}
+		}
+
+		if rule.DelayMilliseconds == 0 && len(rule.Error) == 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"delay_ms or error"}}
+		}
+
+		if rule.Percent <= 0 || rule.Percent > 100 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"percent"}}
+		}
+
+		if rule.ExpireAt.IsZero() {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"expire_at"}}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// InjectedError is the error returned by a fault injection rule
+type InjectedError struct {
+	Rule    string
+	Message string
+}
+
+// Error returns the message of the injected error
+func (e *InjectedError) Error() string {
+	return fmt.Sprintf("fault injected by rule %s: %s", e.Rule, e.Message)
+}
+
+// IsInjectedError returns whether the error is returned by a fault injection rule
+func IsInjectedError(err error) bool {
+	_, ok := err.(*InjectedError)
+	return ok
+}
+
+type compiledRule struct {
+	Rule
+	resource *regexp.Regexp
+}
+
+// rules is the compiled rules of the current service, it is nil if no rule takes effect.
+var rules atomic.Value
+
+// SetRules sets the fault injection rules, the rules of other services are dropped.
+func SetRules(all []Rule) error {
+	service := common.GetIdentification()
+	compiled := make([]compiledRule, 0)
+	for _, rule := range all {
+		if len(rule.Services) != 0 && !util.InStrArr(rule.Services, service) {
+			continue
+		}
+
+		re, err := regexp.Compile(rule.Resource)
+		if err != nil {
+			return fmt.Errorf("compile rule %s resource %s failed, err: %v", rule.Name, rule.Resource, err)
+		}
+		compiled = append(compiled, compiledRule{Rule: rule, resource: re})
+	}
+
+	rules.Store(compiled)
+	return nil
+}
+
+// Inject injects the fault of the first matched rule into the call, the injected error is returned if the rule has
+// an error, and the call should return it directly.
+func Inject(ctx context.Context, target Target, resource string) error {
+	compiled, _ := rules.Load().([]compiledRule)
+	if len(compiled) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, rule := range compiled {
+		if rule.Target != target || now.After(rule.ExpireAt) || !rule.resource.MatchString(resource) {
+			continue
+		}
+
+		if rand.Float64()*100 >= rule.Percent {
+			return nil
+		}
+
+		if rule.DelayMilliseconds > 0 {
+			if err := sleep(ctx, time.Duration(rule.DelayMilliseconds)*time.Millisecond); err != nil {
+				return err
+			}
+		}
+
+		if len(rule.Error) == 0 {
+			return nil
+		}
+		return &InjectedError{Rule: rule.Name, Message: rule.Error}
+	}
+
+	return nil
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if ctx == nil {
+		time.Sleep(delay)
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
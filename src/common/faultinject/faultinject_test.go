@@ -0,0 +1,95 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package faultinject
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"configcenter/src/common"
+)
+
+func TestInject(t *testing.T) {
+	expireAt := time.Now().Add(time.Hour)
+	err := SetRules([]Rule{
+		{Name: "other", Target: Mongo, Services: []string{"other"}, Error: "other", Percent: 100,
+			ExpireAt: expireAt},
+		{Name: "expired", Target: Mongo, Error: "expired", Percent: 100, ExpireAt: time.Now().Add(-time.Hour)},
+		{Name: "host", Target: Mongo, Resource: `^cc_HostBase\.`, Error: "mongo down", Percent: 100,
+			ExpireAt: expireAt},
+		{Name: "delay", Target: HTTP, DelayMilliseconds: 20, Percent: 100, ExpireAt: expireAt},
+	})
+	if err != nil {
+		t.Fatalf("set rules failed, err: %v", err)
+	}
+	defer SetRules(nil)
+
+	if err := Inject(context.Background(), Mongo, "cc_ApplicationBase.find"); err != nil {
+		t.Errorf("unexpected error for unmatched resource: %v", err)
+	}
+
+	err = Inject(context.Background(), Mongo, "cc_HostBase.find")
+	if !IsInjectedError(err) || err.(*InjectedError).Rule != "host" {
+		t.Errorf("expect error of rule host, but got %v", err)
+	}
+
+	if err := Inject(context.Background(), Redis, "get:cc_HostBase."); err != nil {
+		t.Errorf("unexpected error for unmatched target: %v", err)
+	}
+
+	start := time.Now()
+	if err := Inject(context.Background(), HTTP, "POST /api/v3/findmany/hosts"); err != nil {
+		t.Errorf("unexpected error for delay rule: %v", err)
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Errorf("expect the delay to be injected")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Inject(ctx, HTTP, "GET /"); err != context.Canceled {
+		t.Errorf("expect the delay to be canceled, but got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	expireAt := time.Now().Add(time.Hour)
+	cases := []struct {
+		rule    Rule
+		errCode int
+	}{
+		{Rule{Name: "a", Target: Redis, Error: "e", Percent: 50, ExpireAt: expireAt}, 0},
+		{Rule{Name: "a", Target: "kafka", Error: "e", Percent: 50, ExpireAt: expireAt}, common.CCErrCommParamsInvalid},
+		{Rule{Name: "a", Target: Redis, Resource: "(", Error: "e", Percent: 50, ExpireAt: expireAt},
+			common.CCErrCommParamsInvalid},
+		{Rule{Name: "a", Target: Redis, Percent: 50, ExpireAt: expireAt}, common.CCErrCommParamsNeedSet},
+		{Rule{Name: "a", Target: Redis, Error: "e", Percent: 0, ExpireAt: expireAt}, common.CCErrCommParamsInvalid},
+		{Rule{Name: "a", Target: Redis, Error: "e", Percent: 50}, common.CCErrCommParamsNeedSet},
+		{Rule{Name: "a", Target: Redis, DelayMilliseconds: 120000, Percent: 50, ExpireAt: expireAt},
+			common.CCErrCommParamsInvalid},
+	}
+
+	for index, c := range cases {
+		rules := Rules{Rules: []Rule{c.rule}}
+		if rawErr := rules.Validate(); rawErr.ErrCode != c.errCode {
+			t.Errorf("case %d expect error code %d, but got %d", index, c.errCode, rawErr.ErrCode)
+		}
+	}
+
+	rule := Rule{Name: "a", Target: Redis, Error: "e", Percent: 50, ExpireAt: expireAt}
+	rules := Rules{Rules: []Rule{rule, rule}}
+	if rawErr := rules.Validate(); rawErr.ErrCode != common.CCErrCommDuplicateItem {
+		t.Errorf("expect duplicate error, but got %d", rawErr.ErrCode)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"configcenter/src/ac"
 	"configcenter/src/common"
@@ -70,6 +71,24 @@ func (c *Contexts) DecodeInto(to interface{}) error {
 	return nil
 }
 
+// DecodeStreamInto decodes the request body without reading the whole body into memory first, it is used by the
+// large batch apis. The number of elements of the array or map fields in limits is validated while decoding, so
+// the request exceeding the limits is rejected before it is fully read.
+func (c *Contexts) DecodeStreamInto(to interface{}, limits map[string]int) error {
+	err := json.DecodeStream(c.Request.Request.Body, to, limits)
+	if err == nil {
+		return nil
+	}
+
+	if limitErr, ok := err.(*json.ExceedLimitError); ok {
+		blog.ErrorfDepthf(1, "rid: %s, decode request body failed, err: %v", c.Kit.Rid, err)
+		return c.Kit.CCError.CCErrorf(common.CCErrCommXXExceedLimit, limitErr.Field, limitErr.Limit)
+	}
+
+	blog.ErrorfDepthf(1, "rid: %s, stream decode request body failed, err: %v", c.Kit.Rid, err)
+	return c.Kit.CCError.Error(common.CCErrCommJSONUnmarshalFailed)
+}
+
 // RespEntity TODO
 func (c *Contexts) RespEntity(data interface{}) {
 	if c.respStatusCode != 0 {
@@ -265,11 +284,13 @@ func (c *Contexts) RespWithError(err error, errCode int, format string, args ...
 
 	var code int
 	var errMsg string
+	var detail *errors.Detail
 	if err != nil {
-		t, yes := err.(errors.CCErrorCoder)
+		t, yes := errors.AsCCError(err)
 		if yes {
 			code = t.GetCode()
 			errMsg = t.Error()
+			detail = errors.GetDetail(t)
 		} else {
 			if errCode > 0 {
 				code = errCode
@@ -293,6 +314,7 @@ func (c *Contexts) RespWithError(err error, errCode int, format string, args ...
 			Result: false,
 			ErrMsg: errMsg,
 			Code:   code,
+			Detail: detail,
 		},
 		Data: nil,
 	}
@@ -307,11 +329,13 @@ func (c *Contexts) RespAutoError(err error) {
 	blog.ErrorfDepthf(1, "rid: %s, err: %v", c.Kit.Rid, err)
 	var code int
 	var errMsg string
+	var detail *errors.Detail
 	if err != nil {
-		t, yes := err.(errors.CCErrorCoder)
+		t, yes := errors.AsCCError(err)
 		if yes {
 			code = t.GetCode()
 			errMsg = t.Error()
+			detail = errors.GetDetail(t)
 		} else {
 			code = common.CCErrorUnknownOrUnrecognizedError
 			errMsg = err.Error()
@@ -328,6 +352,7 @@ func (c *Contexts) RespAutoError(err error) {
 			Result: false,
 			ErrMsg: errMsg,
 			Code:   code,
+			Detail: detail,
 		},
 		Data: nil,
 	}
@@ -458,6 +483,20 @@ func (c *Contexts) SetReadPreference(mode common.ReadPreferenceMode) {
 	c.Kit.Ctx, c.Kit.Header = util.SetReadPreference(c.Kit.Ctx, c.Kit.Header, mode)
 }
 
+// SetWarnings 在响应头中设置告警信息，用于提示调用方正在使用即将下线的功能，多条告警合并为一个响应头，
+// 因为apiserver只会转发每个响应头的第一个值。
+func (c *Contexts) SetWarnings(warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	values := make([]string, len(warnings))
+	for idx, warning := range warnings {
+		values[idx] = fmt.Sprintf("299 - %s", strconv.Quote(warning))
+	}
+	c.resp.Header().Set("Warning", strings.Join(values, ", "))
+}
+
 // NewKit 产生一个新的kit， 一般用于在创建新的协程的时候，这个时候会对header 做处理，删除不必要的http header。
 func (kit *Kit) NewKit() *Kit {
 	newHeader := util.CCHeader(kit.Header)
@@ -135,6 +135,8 @@ func (r *RestUtility) wrapperAction(action Action) func(req *restful.Request, re
 			// we got a request with transaction info, which is only useful for coreservice.
 			ctx = context.WithValue(ctx, common.TransactionIdHeader, txnID)
 			ctx = context.WithValue(ctx, common.TransactionTimeoutHeader, header.Get(common.TransactionTimeoutHeader))
+			ctx = context.WithValue(ctx, common.TransactionSuppressEventHeader,
+				header.Get(common.TransactionSuppressEventHeader))
 		}
 		if mode := util.GetHTTPReadPreference(header); mode != common.NilMode {
 			ctx = util.SetDBReadPreference(ctx, mode)
@@ -29,7 +29,18 @@ func init() {
 
 //  新加和修改后的索引,索引名字一定要用对应的前缀，CCLogicUniqueIdxNamePrefix|common.CCLogicIndexNamePrefix
 
-var commAuditLogIndexes = []types.Index{}
+var commAuditLogIndexes = []types.Index{
+	{
+		Name: common.CCLogicIndexNamePrefix + "changeSetID",
+		Keys: bson.D{
+			{common.BKChangeSetIDField, 1},
+		},
+		Background: true,
+		PartialFilterExpression: map[string]interface{}{
+			common.BKChangeSetIDField: map[string]string{common.BKDBType: "string"},
+		},
+	},
+}
 
 // deprecated 未规范化前的索引，只允许删除不允许新加和修改，
 var deprecatedAuditLogIndexes = []types.Index{
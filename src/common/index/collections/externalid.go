@@ -0,0 +1,51 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package collections
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/storage/dal/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func init() {
+	registerIndexes(common.BKTableNameExternalID, commExternalIDIndexes)
+}
+
+//  新加和修改后的索引,索引名字一定要用对应的前缀，CCLogicUniqueIdxNamePrefix|common.CCLogicIndexNamePrefix
+
+var commExternalIDIndexes = []types.Index{
+	{
+		Name: common.CCLogicUniqueIdxNamePrefix + "objID_source_externalID_ownerID",
+		Keys: bson.D{
+			{common.BKObjIDField, 1},
+			{"source", 1},
+			{"external_id", 1},
+			{common.BKOwnerIDField, 1},
+		},
+		Unique:     true,
+		Background: true,
+	},
+	{
+		Name: common.CCLogicUniqueIdxNamePrefix + "objID_instID_source_ownerID",
+		Keys: bson.D{
+			{common.BKObjIDField, 1},
+			{common.BKInstIDField, 1},
+			{"source", 1},
+			{common.BKOwnerIDField, 1},
+		},
+		Unique:     true,
+		Background: true,
+	},
+}
@@ -39,7 +39,8 @@ func InstanceAssociationIndexes() []types.Index {
 // CCFieldTypeToDBType TODO
 func CCFieldTypeToDBType(typ string) string {
 	switch typ {
-	case common.FieldTypeSingleChar, common.FieldTypeEnum, common.FieldTypeDate, common.FieldTypeList:
+	case common.FieldTypeSingleChar, common.FieldTypeEnum, common.FieldTypeDate, common.FieldTypeList,
+		common.FieldTypeEnumCascade:
 		return "string"
 	case common.FieldTypeInt, common.FieldTypeFloat:
 		return "number"
@@ -0,0 +1,213 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// streamBufferSize is the buffer size of the stream decoder.
+const streamBufferSize = 4096
+
+// ExceedLimitError is returned by DecodeStream when the number of elements of a field exceeds its limit.
+type ExceedLimitError struct {
+	Field string
+	Limit int
+}
+
+// Error returns the error message
+func (e *ExceedLimitError) Error() string {
+	return fmt.Sprintf("the number of %s exceeds limit %d", e.Field, e.Limit)
+}
+
+// DecodeStream decodes the json data from the reader into v without reading the whole data into memory first.
+// If v points to a struct, the elements of its array or map fields whose json name is in limits are counted while
+// decoding, and the decoding stops with ExceedLimitError as soon as the limit is exceeded, so that an oversize
+// request is rejected before the rest of it is read. An empty reader leaves v unchanged.
+func DecodeStream(r io.Reader, v interface{}, limits map[string]int) error {
+	iter := jsoniter.Parse(iteratorJson, r, streamBufferSize)
+	if iter.WhatIsNext() == jsoniter.InvalidValue && iter.Error == io.EOF {
+		return nil
+	}
+
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Kind() == reflect.Ptr {
+		if value.Elem().IsNil() {
+			value.Elem().Set(reflect.New(value.Elem().Type().Elem()))
+		}
+		value = value.Elem()
+	}
+
+	if len(limits) == 0 || value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct ||
+		iter.WhatIsNext() != jsoniter.ObjectValue {
+		iter.ReadVal(v)
+		return iterError(iter)
+	}
+
+	value = value.Elem()
+	fields := structFields(value.Type())
+	var limitErr error
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, key string) bool {
+		index, exists := fields[key]
+		if !exists {
+			for name, idx := range fields {
+				if strings.EqualFold(name, key) {
+					key, index, exists = name, idx, true
+					break
+				}
+			}
+		}
+
+		if !exists {
+			iter.Skip()
+			return iter.Error == nil
+		}
+
+		field := value.FieldByIndex(index)
+		limit, limited := limits[key]
+		if !limited {
+			iter.ReadVal(field.Addr().Interface())
+			return iter.Error == nil
+		}
+
+		limitErr = readLimitedField(iter, key, field, limit)
+		return limitErr == nil && iter.Error == nil
+	})
+
+	if limitErr != nil {
+		return limitErr
+	}
+	return iterError(iter)
+}
+
+// readLimitedField decodes the array or map field element by element, and stops once the limit is exceeded.
+func readLimitedField(iter *jsoniter.Iterator, name string, field reflect.Value, limit int) error {
+	if iter.WhatIsNext() == jsoniter.NilValue {
+		iter.Skip()
+		return nil
+	}
+
+	count := 0
+	var err error
+	switch field.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(field.Type(), 0, 0)
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			if count++; count > limit {
+				err = &ExceedLimitError{Field: name, Limit: limit}
+				return false
+			}
+
+			elem := reflect.New(field.Type().Elem())
+			iter.ReadVal(elem.Interface())
+			slice = reflect.Append(slice, elem.Elem())
+			return iter.Error == nil
+		})
+		field.Set(slice)
+
+	case reflect.Map:
+		m := reflect.MakeMap(field.Type())
+		iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+			if count++; count > limit {
+				err = &ExceedLimitError{Field: name, Limit: limit}
+				return false
+			}
+
+			mapKey, keyErr := parseMapKey(key, field.Type().Key())
+			if keyErr != nil {
+				iter.ReportError("decode "+name, keyErr.Error())
+				return false
+			}
+
+			elem := reflect.New(field.Type().Elem())
+			iter.ReadVal(elem.Interface())
+			m.SetMapIndex(mapKey, elem.Elem())
+			return iter.Error == nil
+		})
+		field.Set(m)
+
+	default:
+		iter.ReadVal(field.Addr().Interface())
+	}
+
+	return err
+}
+
+func parseMapKey(key string, typ reflect.Type) (reflect.Value, error) {
+	value := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		value.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, typ.Bits())
+		if err != nil {
+			return value, err
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(key, 10, typ.Bits())
+		if err != nil {
+			return value, err
+		}
+		value.SetUint(u)
+	default:
+		return value, fmt.Errorf("unsupported map key type %s", typ)
+	}
+	return value, nil
+}
+
+// structFields returns the field index of the struct by the field's json name, including the embedded fields.
+func structFields(typ reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedName, index := range structFields(field.Type) {
+				if _, exists := fields[embedName]; !exists {
+					fields[embedName] = append([]int{i}, index...)
+				}
+			}
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = []int{i}
+	}
+	return fields
+}
+
+// iterError returns the error of the iterator, the data is truncated if it ends before the value is fully decoded.
+func iterError(iter *jsoniter.Iterator) error {
+	if iter.Error == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return iter.Error
+}
@@ -47,12 +47,17 @@ const (
 type SearchAssociationTypeRequest struct {
 	BasePage  `json:"page"`
 	Condition map[string]interface{} `json:"condition"`
+	// WithConstraints returns the mapping constraints of the model associations using the association kinds if set.
+	WithConstraints bool `json:"with_constraints"`
 }
 
 // SearchAssociationType struct for search association type
 type SearchAssociationType struct {
 	Count int                `json:"count"`
 	Info  []*AssociationKind `json:"info"`
+	// Constraints the mapping constraints of the model associations using the association kind, keyed by the
+	// association kind id, only returned if they are required.
+	Constraints map[string][]AssociationKindConstraint `json:"constraints,omitempty"`
 }
 
 // SearchAssociationTypeResult TODO
@@ -0,0 +1,90 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+const (
+	// AsstMappingViolationDefaultLimit the default number of the violations returned by one scan.
+	AsstMappingViolationDefaultLimit = 100
+	// AsstMappingViolationMaxLimit the max number of the violations returned by one scan.
+	AsstMappingViolationMaxLimit = 500
+)
+
+// the sides of the instance that violates the association mapping.
+const (
+	// AsstMappingViolationSrc the source instance is associated with more than one destination instance.
+	AsstMappingViolationSrc = "src"
+	// AsstMappingViolationDest the destination instance is associated with more than one source instance.
+	AsstMappingViolationDest = "dest"
+)
+
+// ScanAsstMappingViolationOption is the option to scan the existing instance associations of the model association
+// that violate its mapping, which may be created before the mapping is enforced.
+type ScanAsstMappingViolationOption struct {
+	ObjectAsstID string `json:"bk_obj_asst_id"`
+	Limit        int    `json:"limit"`
+}
+
+// Validate validate the scan association mapping violation option, and set the default limit if it is not set.
+func (o *ScanAsstMappingViolationOption) Validate() errors.RawErrorInfo {
+	if len(o.ObjectAsstID) == 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsNeedSet,
+			Args:    []interface{}{common.AssociationObjAsstIDField},
+		}
+	}
+
+	if o.Limit == 0 {
+		o.Limit = AsstMappingViolationDefaultLimit
+	}
+
+	if o.Limit < 0 || o.Limit > AsstMappingViolationMaxLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"limit", AsstMappingViolationMaxLimit},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// AsstMappingViolation is an instance that is associated with more instances than the association mapping allows.
+type AsstMappingViolation struct {
+	// Side which side of the association the instance is on, src or dest.
+	Side   string `json:"side"`
+	InstID int64  `json:"bk_inst_id"`
+	// AsstIDs the ids of the instance associations of the instance on this side.
+	AsstIDs []int64 `json:"asst_ids"`
+}
+
+// AsstMappingViolationResult is the result of scanning the association mapping violations, n:n association never
+// has violations. HasMore is set if there are more violations than the limit.
+type AsstMappingViolationResult struct {
+	ObjectAsstID string                 `json:"bk_obj_asst_id"`
+	Mapping      AssociationMapping     `json:"mapping"`
+	HasMore      bool                   `json:"has_more"`
+	Violations   []AsstMappingViolation `json:"violations"`
+}
+
+// AssociationKindConstraint is the mapping constraint of a model association using the association kind, the
+// instance associations of the model association are rejected on creation if they violate the mapping.
+type AssociationKindConstraint struct {
+	ObjectAsstID string             `json:"bk_obj_asst_id"`
+	ObjectID     string             `json:"bk_obj_id"`
+	AsstObjID    string             `json:"bk_asst_obj_id"`
+	Mapping      AssociationMapping `json:"mapping"`
+}
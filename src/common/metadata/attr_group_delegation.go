@@ -0,0 +1,153 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+const (
+	// AttrGroupDelegationMaxUsers the max number of the users of a delegated role.
+	AttrGroupDelegationMaxUsers = 100
+	// AttrGroupDelegationMaxRoleLength the max length of the delegated role's name.
+	AttrGroupDelegationMaxRoleLength = 64
+)
+
+// AttrGroupDelegation delegates the edit right of an attribute group of the model to a role, e.g. the network team
+// edits the vlan attributes of the hosts. once the group is delegated, its attributes can only be changed by the
+// users of the delegated roles.
+type AttrGroupDelegation struct {
+	ObjID   string `json:"bk_obj_id" bson:"bk_obj_id"`
+	GroupID string `json:"bk_group_id" bson:"bk_group_id"`
+	// Role the name of the delegated role, e.g. "network".
+	Role  string   `json:"role" bson:"role"`
+	Users []string `json:"users" bson:"users"`
+
+	Modifier        string    `json:"modifier" bson:"modifier"`
+	LastTime        time.Time `json:"last_time" bson:"last_time"`
+	SupplierAccount string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+}
+
+// Validate validate the attribute group delegation
+func (d *AttrGroupDelegation) Validate() errors.RawErrorInfo {
+	if rawErr := d.AttrGroupDelegationKey().Validate(); rawErr.ErrCode != 0 {
+		return rawErr
+	}
+
+	if len(d.Users) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"users"}}
+	}
+
+	if len(d.Users) > AttrGroupDelegationMaxUsers {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"users", AttrGroupDelegationMaxUsers},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// AttrGroupDelegationKey returns the key of the delegation.
+func (d *AttrGroupDelegation) AttrGroupDelegationKey() *AttrGroupDelegationKey {
+	return &AttrGroupDelegationKey{ObjID: d.ObjID, GroupID: d.GroupID, Role: d.Role}
+}
+
+// AttrGroupDelegationKey is the key of an attribute group delegation, which is also the option to delete it.
+type AttrGroupDelegationKey struct {
+	ObjID   string `json:"bk_obj_id"`
+	GroupID string `json:"bk_group_id"`
+	Role    string `json:"role"`
+}
+
+// Validate validate the attribute group delegation key
+func (k *AttrGroupDelegationKey) Validate() errors.RawErrorInfo {
+	if len(k.ObjID) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKObjIDField}}
+	}
+
+	if len(k.GroupID) == 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsNeedSet,
+			Args:    []interface{}{common.BKPropertyGroupIDField},
+		}
+	}
+
+	if len(k.Role) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"role"}}
+	}
+
+	if len(k.Role) > AttrGroupDelegationMaxRoleLength {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"role", AttrGroupDelegationMaxRoleLength},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// ListAttrGroupDelegationOption is the option to list the attribute group delegations of the model.
+type ListAttrGroupDelegationOption struct {
+	ObjID string `json:"bk_obj_id"`
+	// GroupIDs the attribute groups, list the delegations of all the groups if not set.
+	GroupIDs []string `json:"bk_group_ids,omitempty"`
+}
+
+// Validate validate the list attribute group delegation option
+func (o *ListAttrGroupDelegationOption) Validate() errors.RawErrorInfo {
+	if len(o.ObjID) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKObjIDField}}
+	}
+
+	if len(o.GroupIDs) > common.BKMaxInstanceLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"bk_group_ids", common.BKMaxInstanceLimit},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// AttributeEditSchemaOption is the option to find the attributes' edit schema of the model.
+type AttributeEditSchemaOption struct {
+	// BizID the business whose custom attributes are returned too, only the model's attributes are returned if not set.
+	BizID int64 `json:"bk_biz_id"`
+}
+
+// AttributeEditSchema is the attribute with the current user's edit right, which is used by the ui to render the
+// editable fields of the instance form.
+type AttributeEditSchema struct {
+	Attribute `json:",inline"`
+	// DelegatedRoles the roles that the attribute's group is delegated to, the attribute is editable by all the
+	// users who have the instance's edit permission if it is empty.
+	DelegatedRoles []string `json:"delegated_roles"`
+	// UserEditable whether the current user can edit the attribute regarding the delegations.
+	UserEditable bool `json:"user_editable"`
+}
+
+// AttrGroupDelegationResult is the response of an attribute group delegation.
+type AttrGroupDelegationResult struct {
+	BaseResp `json:",inline"`
+	Data     *AttrGroupDelegation `json:"data"`
+}
+
+// AttrGroupDelegationsResult is the response of the attribute group delegations.
+type AttrGroupDelegationsResult struct {
+	BaseResp `json:",inline"`
+	Data     []AttrGroupDelegation `json:"data"`
+}
@@ -77,6 +77,8 @@ const (
 	AttributeFieldCreateTime = "create_time"
 	// AttributeFieldLastTime TODO
 	AttributeFieldLastTime = "last_time"
+	// AttributeFieldIsLocked whether the attribute is locked by the platform against the business's changes
+	AttributeFieldIsLocked = "bk_islocked"
 )
 
 // Attribute attribute metadata definition
@@ -99,12 +101,21 @@ type Attribute struct {
 	IsOnly            bool        `field:"isonly" json:"isonly" bson:"isonly" mapstructure:"isonly"`
 	IsSystem          bool        `field:"bk_issystem" json:"bk_issystem" bson:"bk_issystem" mapstructure:"bk_issystem"`
 	IsAPI             bool        `field:"bk_isapi" json:"bk_isapi" bson:"bk_isapi" mapstructure:"bk_isapi"`
+	IsLocked          bool        `field:"bk_islocked" json:"bk_islocked" bson:"bk_islocked" mapstructure:"bk_islocked"`
+	OwnerSource       string      `field:"bk_owner_source" json:"bk_owner_source" bson:"bk_owner_source" mapstructure:"bk_owner_source"`
+	OwnerPolicy       string      `field:"bk_owner_policy" json:"bk_owner_policy" bson:"bk_owner_policy" mapstructure:"bk_owner_policy"`
 	PropertyType      string      `field:"bk_property_type" json:"bk_property_type" bson:"bk_property_type" mapstructure:"bk_property_type"`
 	Option            interface{} `field:"option" json:"option" bson:"option" mapstructure:"option"`
 	Description       string      `field:"description" json:"description" bson:"description" mapstructure:"description"`
 	Creator           string      `field:"creator" json:"creator" bson:"creator" mapstructure:"creator"`
 	CreateTime        *Time       `json:"create_time" bson:"create_time" mapstructure:"create_time"`
 	LastTime          *Time       `json:"last_time" bson:"last_time" mapstructure:"last_time"`
+
+	// Lifecycle the lifecycle of the attribute, the attribute is active if it is not set
+	Lifecycle *SchemaLifecycle `json:"bk_lifecycle,omitempty" bson:"bk_lifecycle,omitempty"`
+
+	// I18n the display texts of the attribute in the languages, the key is the language like "en".
+	I18n map[string]AttributeI18n `json:"bk_i18n,omitempty" bson:"bk_i18n,omitempty" mapstructure:"bk_i18n"`
 }
 
 // AttributeGroup attribute metadata definition
@@ -161,6 +172,8 @@ func (attribute *Attribute) Validate(ctx context.Context, data interface{}, key
 		rawError = attribute.validFloat(ctx, data, key)
 	case common.FieldTypeEnum:
 		rawError = attribute.validEnum(ctx, data, key)
+	case common.FieldTypeEnumCascade:
+		rawError = attribute.validEnumCascade(ctx, data, key)
 	case common.FieldTypeDate:
 		rawError = attribute.validDate(ctx, data, key)
 	case common.FieldTypeTime:
@@ -1145,6 +1158,19 @@ func (attribute Attribute) PrettyValue(ctx context.Context, val interface{}) (st
 			}
 		}
 		return "", fmt.Errorf("invalid value for %s, value: %s", fieldType, valStr)
+	case common.FieldTypeEnumCascade:
+		valStr, ok := val.(string)
+		if !ok {
+			return "", fmt.Errorf("invalid value type for %s, value: %+v", fieldType, val)
+		}
+		option, err := ParseEnumCascadeOption(attribute.Option)
+		if err != nil {
+			return "", fmt.Errorf("parse options for enum cascade type failed, err: %+v", err)
+		}
+		if enumVal, exists := option.getEnumVal(valStr); exists {
+			return enumVal.Name, nil
+		}
+		return "", fmt.Errorf("invalid value for %s, value: %s", fieldType, valStr)
 	case common.FieldTypeDate:
 		valStr, ok := val.(string)
 		if ok == false {
@@ -0,0 +1,179 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/util"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// EnumCascadeOption is the option of the cascade enum attribute, the options of the attribute depend on the value of
+// its parent attribute, e.g. the options of the city depend on the province.
+type EnumCascadeOption struct {
+	// Parent the property id of the parent attribute, which is an enum or cascade enum attribute of the same model.
+	Parent string `json:"parent" bson:"parent"`
+	// Options the enum options of each value of the parent attribute.
+	Options map[string]EnumOption `json:"options" bson:"options"`
+}
+
+// ParseEnumCascadeOption convert the attribute option to EnumCascadeOption, the option can be the json string, the
+// map decoded from the request or the bson document read from db.
+func ParseEnumCascadeOption(val interface{}) (*EnumCascadeOption, error) {
+	option := new(EnumCascadeOption)
+	switch opt := val.(type) {
+	case nil:
+		return nil, fmt.Errorf("enum cascade option is not set")
+	case EnumCascadeOption:
+		option = &opt
+	case *EnumCascadeOption:
+		option = opt
+	case string:
+		if err := json.Unmarshal([]byte(opt), option); err != nil {
+			return nil, err
+		}
+	default:
+		raw, err := bson.Marshal(opt)
+		if err != nil {
+			return nil, fmt.Errorf("unknown enum cascade option type: %#v, err: %v", val, err)
+		}
+		if err := bson.Unmarshal(raw, option); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(option.Parent) == 0 {
+		return nil, fmt.Errorf("enum cascade option parent is not set")
+	}
+	return option, nil
+}
+
+// GetOptions returns the enum options when the parent attribute's value is parentValue.
+func (opt *EnumCascadeOption) GetOptions(parentValue string) EnumOption {
+	options, exists := opt.Options[parentValue]
+	if !exists {
+		return make(EnumOption, 0)
+	}
+	return options
+}
+
+// Contains checks if the value is one of the options when the parent attribute's value is parentValue.
+func (opt *EnumCascadeOption) Contains(parentValue, value string) bool {
+	for _, option := range opt.Options[parentValue] {
+		if option.ID == value {
+			return true
+		}
+	}
+	return false
+}
+
+// getEnumVal returns the enum option of the value regardless of the parent attribute's value.
+func (opt *EnumCascadeOption) getEnumVal(value string) (EnumVal, bool) {
+	for _, options := range opt.Options {
+		for _, option := range options {
+			if option.ID == value {
+				return option, true
+			}
+		}
+	}
+	return EnumVal{}, false
+}
+
+// validEnumCascade valid object attribute that is cascade enum type, the value is only checked to be one of the
+// options of any parent value here, it is checked with the parent value by ValidateEnumCascade.
+func (attribute *Attribute) validEnumCascade(ctx context.Context, val interface{}, key string) errors.RawErrorInfo {
+	rid := util.ExtractRequestIDFromContext(ctx)
+	if val == nil || val == "" {
+		if attribute.IsRequired {
+			blog.Errorf("params %s can not be null, rid: %s", key, rid)
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{key}}
+		}
+		return errors.RawErrorInfo{}
+	}
+
+	valStr, ok := val.(string)
+	if !ok {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{key}}
+	}
+
+	option, err := ParseEnumCascadeOption(attribute.Option)
+	if err != nil {
+		blog.Warnf("parse enum cascade option failed, option: %#v, err: %v, rid: %s", attribute.Option, err, rid)
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{key}}
+	}
+
+	if _, exists := option.getEnumVal(valStr); !exists {
+		blog.Errorf("params %s not valid, enum cascade value: %s, rid: %s", key, valStr, rid)
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{key}}
+	}
+	return errors.RawErrorInfo{}
+}
+
+// ValidateEnumCascade validate the cascade enum attribute's value in the instance data with the value of its parent
+// attribute in it, the data should be the whole instance so that the unchanged parent value is checked too.
+func (attribute *Attribute) ValidateEnumCascade(ctx context.Context, data mapstr.MapStr) errors.RawErrorInfo {
+	val := data[attribute.PropertyID]
+	if val == nil || val == "" {
+		return errors.RawErrorInfo{}
+	}
+
+	option, err := ParseEnumCascadeOption(attribute.Option)
+	if err != nil {
+		blog.Warnf("parse enum cascade option failed, option: %#v, err: %v, rid: %s", attribute.Option, err,
+			util.ExtractRequestIDFromContext(ctx))
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{attribute.PropertyID}}
+	}
+
+	valStr := util.GetStrByInterface(val)
+	parentVal := util.GetStrByInterface(data[option.Parent])
+	if !option.Contains(parentVal, valStr) {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCoreServiceEnumCascadeValueInvalid,
+			Args:    []interface{}{attribute.PropertyID, valStr, option.Parent, parentVal},
+		}
+	}
+	return errors.RawErrorInfo{}
+}
+
+// EnumCascadeOptionsOption is the option to find the options of the cascade enum attribute for a parent value.
+type EnumCascadeOptionsOption struct {
+	// PropertyID the property id of the cascade enum attribute.
+	PropertyID string `json:"bk_property_id"`
+	// ParentValue the value of the parent attribute, the dependent dropdown is empty if the parent is not chosen.
+	ParentValue string `json:"parent_value"`
+}
+
+// Validate validate the EnumCascadeOptionsOption
+func (o *EnumCascadeOptionsOption) Validate() errors.RawErrorInfo {
+	if len(o.PropertyID) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKPropertyIDField}}
+	}
+	return errors.RawErrorInfo{}
+}
+
+// EnumCascadeOptions is the options of the cascade enum attribute for a parent value, which is used by the ui to
+// render the dependent dropdown.
+type EnumCascadeOptions struct {
+	PropertyID  string     `json:"bk_property_id"`
+	Parent      string     `json:"parent"`
+	ParentValue string     `json:"parent_value"`
+	Options     EnumOption `json:"options"`
+}
@@ -0,0 +1,141 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/util"
+)
+
+const (
+	// AttributeFieldI18n the field of the attribute's display texts in the languages.
+	AttributeFieldI18n = "bk_i18n"
+	// AttributeI18nMaxLanguages the max number of the languages of an attribute's display texts.
+	AttributeI18nMaxLanguages = 10
+)
+
+// attributeLanguagePattern the language of the display texts, which is the same as the request language header,
+// such as "en" and "cn".
+var attributeLanguagePattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]{2,4})?$`)
+
+// AttributeI18n is the display texts of an attribute in a language, the texts that are not set fall back to the
+// attribute's own texts.
+type AttributeI18n struct {
+	PropertyName string `json:"bk_property_name,omitempty" bson:"bk_property_name,omitempty" mapstructure:"bk_property_name"`
+	Placeholder  string `json:"placeholder,omitempty" bson:"placeholder,omitempty" mapstructure:"placeholder"`
+	// OptionNames the labels of the enum options, the key is the id of the option.
+	OptionNames map[string]string `json:"option_names,omitempty" bson:"option_names,omitempty" mapstructure:"option_names"`
+}
+
+// ValidateAttributeI18n validate the display texts of the attribute in the languages
+func ValidateAttributeI18n(i18n map[string]AttributeI18n) errors.RawErrorInfo {
+	if len(i18n) > AttributeI18nMaxLanguages {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{AttributeFieldI18n, AttributeI18nMaxLanguages},
+		}
+	}
+
+	for language, texts := range i18n {
+		if !attributeLanguagePattern.MatchString(language) {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{AttributeFieldI18n}}
+		}
+
+		if utf8.RuneCountInString(texts.PropertyName) > common.AttributeNameMaxLength {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommValExceedMaxFailed,
+				Args: []interface{}{fmt.Sprintf("%s.%s.%s", AttributeFieldI18n, language,
+					AttributeFieldPropertyName), common.AttributeNameMaxLength},
+			}
+		}
+
+		if utf8.RuneCountInString(texts.Placeholder) > common.AttributePlaceHolderMaxLength {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommValExceedMaxFailed,
+				Args: []interface{}{fmt.Sprintf("%s.%s.%s", AttributeFieldI18n, language,
+					AttributeFieldPlaceHolder), common.AttributePlaceHolderMaxLength},
+			}
+		}
+
+		for id, name := range texts.OptionNames {
+			if len(id) == 0 || len(name) == 0 || utf8.RuneCountInString(name) > common.AttributeOptionValueMaxLength {
+				return errors.RawErrorInfo{
+					ErrCode: common.CCErrCommParamsInvalid,
+					Args:    []interface{}{fmt.Sprintf("%s.%s.option_names", AttributeFieldI18n, language)},
+				}
+			}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// Localize replace the display name, placeholder and enum option labels of the attribute with the texts of the
+// language, the language such as "en-us" falls back to "en" if it has no texts of its own.
+func (attribute *Attribute) Localize(ctx context.Context, language string) {
+	texts, exists := attribute.lookupI18n(language)
+	if !exists {
+		return
+	}
+
+	if len(texts.PropertyName) > 0 {
+		attribute.PropertyName = texts.PropertyName
+	}
+
+	if len(texts.Placeholder) > 0 {
+		attribute.Placeholder = texts.Placeholder
+	}
+
+	if len(texts.OptionNames) == 0 || attribute.PropertyType != common.FieldTypeEnum {
+		return
+	}
+
+	options, err := ParseEnumOption(ctx, attribute.Option)
+	if err != nil {
+		blog.Errorf("parse attribute %s enum option failed, err: %v, rid: %s", attribute.PropertyID, err,
+			util.ExtractRequestIDFromContext(ctx))
+		return
+	}
+
+	for index := range options {
+		if name, exists := texts.OptionNames[options[index].ID]; exists {
+			options[index].Name = name
+		}
+	}
+	attribute.Option = options
+}
+
+func (attribute *Attribute) lookupI18n(language string) (AttributeI18n, bool) {
+	if len(attribute.I18n) == 0 || len(language) == 0 {
+		return AttributeI18n{}, false
+	}
+
+	language = strings.ToLower(language)
+	if texts, exists := attribute.I18n[language]; exists {
+		return texts, true
+	}
+
+	if index := strings.Index(language, "-"); index > 0 {
+		texts, exists := attribute.I18n[language[:index]]
+		return texts, exists
+	}
+	return AttributeI18n{}, false
+}
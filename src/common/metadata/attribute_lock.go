@@ -0,0 +1,42 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// LockAttributeOption is the option to lock or unlock the model attributes, the locked attributes, no matter built-in
+// or custom, can not be updated or deleted in the business scope, and can not be configured in the set or service
+// templates, only the platform can change them.
+type LockAttributeOption struct {
+	IDs    []int64 `json:"ids"`
+	Locked bool    `json:"locked"`
+}
+
+// Validate validate the lock attribute option
+func (o *LockAttributeOption) Validate() errors.RawErrorInfo {
+	if len(o.IDs) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"ids"}}
+	}
+
+	if len(o.IDs) > common.BKMaxInstanceLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"ids", common.BKMaxInstanceLimit},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
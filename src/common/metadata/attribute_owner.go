@@ -0,0 +1,90 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"regexp"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+const (
+	// AttributeFieldOwnerSource the external source which owns the attribute's data, e.g. the cloud sync or the
+	// monitoring agent, the attribute is owned by nobody if it is not set.
+	AttributeFieldOwnerSource = "bk_owner_source"
+	// AttributeFieldOwnerPolicy how the writes of the owned attribute from the other actors are handled.
+	AttributeFieldOwnerPolicy = "bk_owner_policy"
+)
+
+const (
+	// AttributeOwnerPolicyReject the writes from the other actors are rejected.
+	AttributeOwnerPolicyReject = "reject"
+	// AttributeOwnerPolicyFlag the writes from the other actors are accepted but flagged in the log and metrics.
+	AttributeOwnerPolicyFlag = "flag"
+)
+
+var attributeOwnerSourceRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-.]{1,64}$`)
+
+// SetAttributeOwnerOption is the option to set the external source which owns the data of the model attributes,
+// the owned attributes can only be written by the source, which is matched with the app code or the user of the
+// request, so that the manual edits and the automated data do not clobber each other.
+type SetAttributeOwnerOption struct {
+	IDs []int64 `json:"ids"`
+	// Source the owner source of the attributes, clear the owner of the attributes if it is empty.
+	Source string `json:"source"`
+	// Policy the policy of the writes from the other actors, default is reject.
+	Policy string `json:"policy"`
+}
+
+// Validate validate the set attribute owner option, and set the default policy if it is not set.
+func (o *SetAttributeOwnerOption) Validate() errors.RawErrorInfo {
+	if len(o.IDs) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"ids"}}
+	}
+
+	if len(o.IDs) > common.BKMaxInstanceLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"ids", common.BKMaxInstanceLimit},
+		}
+	}
+
+	if len(o.Source) == 0 {
+		o.Policy = ""
+		return errors.RawErrorInfo{}
+	}
+
+	if !attributeOwnerSourceRegexp.MatchString(o.Source) {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"source"}}
+	}
+
+	switch o.Policy {
+	case "":
+		o.Policy = AttributeOwnerPolicyReject
+	case AttributeOwnerPolicyReject, AttributeOwnerPolicyFlag:
+	default:
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"policy"}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// IsOwnedBy check if the attribute's data can be written by the actor identified by the app code or the user,
+// the attribute which is not owned by any source can be written by everyone.
+func (attribute *Attribute) IsOwnedBy(appCode, user string) bool {
+	if len(attribute.OwnerSource) == 0 {
+		return true
+	}
+	return attribute.OwnerSource == appCode || attribute.OwnerSource == user
+}
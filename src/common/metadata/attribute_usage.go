@@ -0,0 +1,89 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+const (
+	// AttributeUsageDefaultSamplePercent the default percent of the requests sampled to track the attribute usage.
+	AttributeUsageDefaultSamplePercent = 10
+	// AttributeUsageMaxUnusedDays the max days of the unused attributes filter.
+	AttributeUsageMaxUnusedDays = 3650
+)
+
+// AttributeUsageConfig is the config of the attribute usage tracking.
+type AttributeUsageConfig struct {
+	// SamplePercent the percent of the requests sampled to track the attribute usage, 0 disables the tracking.
+	SamplePercent int
+}
+
+// SearchAttributeUsageOption is the option to search the attribute usages of the model.
+type SearchAttributeUsageOption struct {
+	// UnusedDays only returns the attributes that are neither read nor written in the recent days if it is set.
+	UnusedDays int `json:"unused_days"`
+}
+
+// Validate validate the search attribute usage option
+func (o *SearchAttributeUsageOption) Validate() errors.RawErrorInfo {
+	if o.UnusedDays < 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"unused_days"}}
+	}
+
+	if o.UnusedDays > AttributeUsageMaxUnusedDays {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"unused_days", AttributeUsageMaxUnusedDays},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// AttributeUsage is the read and write frequency of a model attribute, the counts are estimated from the sampled
+// requests, and the last used times are the last time the attribute is used in the sampled requests.
+type AttributeUsage struct {
+	PropertyID    string     `json:"bk_property_id"`
+	PropertyName  string     `json:"bk_property_name"`
+	IsPre         bool       `json:"ispre"`
+	ReadCount     int64      `json:"read_count"`
+	WriteCount    int64      `json:"write_count"`
+	LastReadTime  *time.Time `json:"last_read_time"`
+	LastWriteTime *time.Time `json:"last_write_time"`
+}
+
+// LastUsedTime returns the last time the attribute is read or written, returns nil if it is never used.
+func (u *AttributeUsage) LastUsedTime() *time.Time {
+	if u.LastReadTime == nil || u.LastWriteTime != nil && u.LastWriteTime.After(*u.LastReadTime) {
+		return u.LastWriteTime
+	}
+	return u.LastReadTime
+}
+
+// AttributeUsageResult is the attribute usages of the model.
+type AttributeUsageResult struct {
+	ObjectID string `json:"bk_obj_id"`
+	// SamplePercent the percent of the sampled requests, the usages are not tracked if it is 0.
+	SamplePercent int              `json:"sample_percent"`
+	Attributes    []AttributeUsage `json:"attributes"`
+}
+
+// AttributeUsageResp is the response of the attribute usages of the model.
+type AttributeUsageResp struct {
+	BaseResp `json:",inline"`
+	Data     *AttributeUsageResult `json:"data"`
+}
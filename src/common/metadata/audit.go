@@ -91,6 +91,8 @@ type AuditQueryCondition struct {
 	FuzzyQuery bool `json:"fuzzy_query"`
 	// Condition is used for new way to search audit log by user or resource_name
 	Condition []querybuilder.AtomRule `json:"condition"`
+	// ChangeSetID filters audit logs produced in the same change set, such as a host transfer or a template sync
+	ChangeSetID string `json:"change_set_id"`
 }
 
 // Validate is a AuditQueryCondition validator to validate user resource_name condition whether exist at the same time
@@ -201,6 +203,9 @@ type AuditLog struct {
 	AppCode string `json:"code,omitempty" bson:"code,omitempty"`
 	// RequestID is the request id of the request
 	RequestID string `json:"rid,omitempty" bson:"rid,omitempty"`
+	// ChangeSetID groups the audit logs produced by the same transaction or the same bundle of changes, such as a
+	// host transfer or a template sync, so that all the effects of the change can be found as one unit.
+	ChangeSetID string `json:"change_set_id,omitempty" bson:"change_set_id,omitempty"`
 }
 
 type bsonAuditLog struct {
@@ -218,6 +223,7 @@ type bsonAuditLog struct {
 	ResourceName    string          `json:"resource_name" bson:"resource_name"`
 	AppCode         string          `json:"code" bson:"code"`
 	RequestID       string          `json:"rid" bson:"rid"`
+	ChangeSetID     string          `json:"change_set_id" bson:"change_set_id,omitempty"`
 }
 
 type jsonAuditLog struct {
@@ -235,6 +241,7 @@ type jsonAuditLog struct {
 	ResourceName    string          `json:"resource_name" bson:"resource_name"`
 	AppCode         string          `json:"code" bson:"code"`
 	RequestID       string          `json:"rid" bson:"rid"`
+	ChangeSetID     string          `json:"change_set_id" bson:"change_set_id,omitempty"`
 }
 
 // DetailFactory TODO
@@ -261,6 +268,7 @@ func (auditLog *AuditLog) UnmarshalJSON(data []byte) error {
 	auditLog.ResourceName = audit.ResourceName
 	auditLog.AppCode = audit.AppCode
 	auditLog.RequestID = audit.RequestID
+	auditLog.ChangeSetID = audit.ChangeSetID
 
 	if audit.OperationDetail == nil {
 		return nil
@@ -330,6 +338,7 @@ func (auditLog *AuditLog) UnmarshalBSON(data []byte) error {
 	auditLog.ResourceName = audit.ResourceName
 	auditLog.AppCode = audit.AppCode
 	auditLog.RequestID = audit.RequestID
+	auditLog.ChangeSetID = audit.ChangeSetID
 
 	if audit.OperationDetail == nil {
 		return nil
@@ -396,6 +405,7 @@ func (auditLog AuditLog) MarshalBSON() ([]byte, error) {
 	audit.ResourceName = auditLog.ResourceName
 	audit.AppCode = auditLog.AppCode
 	audit.RequestID = auditLog.RequestID
+	audit.ChangeSetID = auditLog.ChangeSetID
 	var err error
 	switch val := auditLog.OperationDetail.(type) {
 	default:
@@ -411,6 +421,11 @@ func (auditLog AuditLog) MarshalBSON() ([]byte, error) {
 type BasicOpDetail struct {
 	// Details contains all the details information about a user's operation
 	Details *BasicContent `json:"details" bson:"details"`
+	// DetailLevel the granularity of the details, empty means the full details.
+	DetailLevel AuditDetailLevel `json:"detail_level,omitempty" bson:"detail_level,omitempty"`
+	// CompressedDetails the gzip compressed json of the details, it is set instead of the details when they are too
+	// large, and is decompressed when the audit log is searched.
+	CompressedDetails []byte `json:"-" bson:"compressed_details,omitempty"`
 }
 
 // WithName TODO
@@ -632,6 +647,8 @@ const (
 	HostApplyRes ResourceType = "host_apply"
 	// CustomFieldRes TODO
 	CustomFieldRes ResourceType = "custom_field"
+	// ChangeBundleRes the bundle of related changes in a business that is executed as a whole
+	ChangeBundleRes ResourceType = "change_bundle"
 
 	// ModelRes TODO
 	// model related operation type
@@ -729,6 +746,8 @@ const (
 	// AuditResume TODO
 	// resume using an object
 	AuditResume ActionType = "resume"
+	// AuditExecute execute a custom action on a resource
+	AuditExecute ActionType = "execute"
 )
 
 // GetAuditTypeByObjID TODO
@@ -859,6 +878,14 @@ var auditDict = []resourceTypeInfo{
 			actionInfoMap[AuditAssignHost],
 			actionInfoMap[AuditUnassignHost],
 			actionInfoMap[AuditTransferHostModule],
+			actionInfoMap[AuditExecute],
+		},
+	},
+	{
+		ID:   ChangeBundleRes,
+		Name: "变更包",
+		Operations: []actionTypeInfo{
+			actionInfoMap[AuditExecute],
 		},
 	},
 	{
@@ -896,6 +923,7 @@ var auditDict = []resourceTypeInfo{
 			actionInfoMap[AuditCreate],
 			actionInfoMap[AuditUpdate],
 			actionInfoMap[AuditDelete],
+			actionInfoMap[AuditExecute],
 		},
 	},
 	{
@@ -1003,6 +1031,7 @@ var actionInfoMap = map[ActionType]actionTypeInfo{
 	AuditRecover:            {ID: AuditRecover, Name: "恢复"},
 	AuditPause:              {ID: AuditPause, Name: "停用"},
 	AuditResume:             {ID: AuditResume, Name: "启用"},
+	AuditExecute:            {ID: AuditExecute, Name: "执行操作"},
 }
 
 type resourceTypeInfo struct {
@@ -1086,6 +1115,14 @@ var auditEnDict = []resourceTypeInfo{
 			actionInfoEnMap[AuditAssignHost],
 			actionInfoEnMap[AuditUnassignHost],
 			actionInfoEnMap[AuditTransferHostModule],
+			actionInfoEnMap[AuditExecute],
+		},
+	},
+	{
+		ID:   ChangeBundleRes,
+		Name: "Change Bundle",
+		Operations: []actionTypeInfo{
+			actionInfoEnMap[AuditExecute],
 		},
 	},
 	{
@@ -1123,6 +1160,7 @@ var auditEnDict = []resourceTypeInfo{
 			actionInfoEnMap[AuditCreate],
 			actionInfoEnMap[AuditUpdate],
 			actionInfoEnMap[AuditDelete],
+			actionInfoEnMap[AuditExecute],
 		},
 	},
 	{
@@ -1229,4 +1267,5 @@ var actionInfoEnMap = map[ActionType]actionTypeInfo{
 	AuditRecover:            {ID: AuditRecover, Name: "Recover"},
 	AuditPause:              {ID: AuditPause, Name: "Pause"},
 	AuditResume:             {ID: AuditResume, Name: "Resume"},
+	AuditExecute:            {ID: AuditExecute, Name: "Execute action"},
 }
@@ -0,0 +1,80 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"net/http"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/util"
+)
+
+// GetChangeSetID get the change set id of the request, which is the change set id in the header, or the transaction
+// id if the request runs in a transaction. returns empty if the request belongs to no change set.
+func GetChangeSetID(header http.Header) string {
+	if id := header.Get(common.BKHTTPChangeSetID); len(id) > 0 {
+		return id
+	}
+	return header.Get(common.TransactionIdHeader)
+}
+
+// KeepChangeSet set the change set id of the request into the header, a new one is generated if the request belongs
+// to no change set. it is used before the changes of the request are executed in several transactions or out of the
+// transaction, so that all of them are grouped into the same change set.
+func KeepChangeSet(header http.Header) string {
+	id := GetChangeSetID(header)
+	if len(id) == 0 {
+		id = util.GenerateRID()
+	}
+	header.Set(common.BKHTTPChangeSetID, id)
+	return id
+}
+
+// AuditChangeSetQueryInput is the input to find the audit logs of a change set.
+type AuditChangeSetQueryInput struct {
+	ChangeSetID string   `json:"change_set_id"`
+	Page        BasePage `json:"page"`
+}
+
+// Validate validates the audit change set query input
+func (input *AuditChangeSetQueryInput) Validate() errors.RawErrorInfo {
+	if len(input.ChangeSetID) == 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsNeedSet,
+			Args:    []interface{}{common.BKChangeSetIDField},
+		}
+	}
+
+	if input.Page.Limit <= 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsInvalid,
+			Args:    []interface{}{"limit"},
+		}
+	}
+
+	if input.Page.Limit > common.BKAuditLogPageLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommPageLimitIsExceeded,
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// AuditChangeSet is the audit logs of a change set in the order they are produced.
+type AuditChangeSet struct {
+	ChangeSetID string     `json:"change_set_id"`
+	Count       int64      `json:"count"`
+	Info        []AuditLog `json:"info"`
+}
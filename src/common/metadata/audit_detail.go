@@ -0,0 +1,260 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+)
+
+// AuditDetailLevel is the granularity of the details stored in the audit log.
+type AuditDetailLevel string
+
+const (
+	// AuditDetailFull stores the full documents before and after the operation.
+	AuditDetailFull AuditDetailLevel = "full"
+	// AuditDetailChangedFields stores only the fields changed by the operation in the documents.
+	AuditDetailChangedFields AuditDetailLevel = "changed_fields"
+	// AuditDetailMetadataOnly stores only the metadata of the operation, such as the resource and the operator.
+	AuditDetailMetadataOnly AuditDetailLevel = "metadata_only"
+)
+
+// Validate validate the audit detail level.
+func (l AuditDetailLevel) Validate() error {
+	switch l {
+	case AuditDetailFull, AuditDetailChangedFields, AuditDetailMetadataOnly:
+		return nil
+	}
+	return fmt.Errorf("audit detail level %s is invalid", l)
+}
+
+// AuditDetailConfig defines how the details of the audit logs are stored.
+type AuditDetailConfig struct {
+	// DefaultLevel the detail level of the resources whose level is not configured.
+	DefaultLevel AuditDetailLevel `yaml:"defaultLevel"`
+	// Levels the detail levels of the resources.
+	Levels map[ResourceType]AuditDetailLevel `yaml:"levels"`
+	// AsyncDiffFieldCount the changed fields of the documents whose field count exceeds it are computed
+	// asynchronously after the audit log is saved, 0 means always computing them synchronously.
+	AsyncDiffFieldCount int `yaml:"asyncDiffFieldCount"`
+	// CompressSize the details whose json size exceeds it are compressed, 0 means never compressing them.
+	CompressSize int `yaml:"compressSize"`
+	// AsyncWrite the config of saving the audit logs asynchronously in batches.
+	AsyncWrite AuditAsyncWriteConfig `yaml:"asyncWrite"`
+}
+
+// AuditAsyncWriteConfig defines how the audit logs are saved asynchronously in batches, the audit logs saved in a
+// transaction or of the sync actions are always saved synchronously.
+type AuditAsyncWriteConfig struct {
+	// Enabled whether the audit logs are saved asynchronously, they are saved synchronously if it is not enabled.
+	Enabled bool `yaml:"enabled"`
+	// QueueSize the max number of the audit logs waiting to be saved, the audit logs are saved synchronously when
+	// the queue is full.
+	QueueSize int `yaml:"queueSize"`
+	// BatchSize the max number of the audit logs saved in one batch.
+	BatchSize int `yaml:"batchSize"`
+	// FlushIntervalMS the max milliseconds that the audit logs wait in the queue before they are saved.
+	FlushIntervalMS int `yaml:"flushIntervalMS"`
+	// SyncActions the actions whose audit logs are always saved synchronously, defaults to delete.
+	SyncActions []ActionType `yaml:"syncActions"`
+}
+
+const (
+	// AuditAsyncWriteDefaultQueueSize the default queue size of the audit logs saved asynchronously.
+	AuditAsyncWriteDefaultQueueSize = 10000
+	// AuditAsyncWriteDefaultBatchSize the default batch size of the audit logs saved asynchronously.
+	AuditAsyncWriteDefaultBatchSize = 200
+	// AuditAsyncWriteDefaultFlushIntervalMS the default flush interval of the audit logs saved asynchronously.
+	AuditAsyncWriteDefaultFlushIntervalMS = 500
+)
+
+// DefaultAuditDetailConfig is the audit detail config used when it is not configured, which keeps the full details.
+func DefaultAuditDetailConfig() *AuditDetailConfig {
+	return &AuditDetailConfig{DefaultLevel: AuditDetailFull}
+}
+
+// Validate validate the audit detail config.
+func (c *AuditDetailConfig) Validate() error {
+	if len(c.DefaultLevel) == 0 {
+		c.DefaultLevel = AuditDetailFull
+	}
+
+	if err := c.DefaultLevel.Validate(); err != nil {
+		return err
+	}
+
+	for resource, level := range c.Levels {
+		if err := level.Validate(); err != nil {
+			return fmt.Errorf("resource %s %v", resource, err)
+		}
+	}
+
+	if c.AsyncDiffFieldCount < 0 {
+		return fmt.Errorf("audit async diff field count %d is invalid", c.AsyncDiffFieldCount)
+	}
+
+	if c.CompressSize < 0 {
+		return fmt.Errorf("audit compress size %d is invalid", c.CompressSize)
+	}
+
+	if err := c.AsyncWrite.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validate the audit async write config, and set the default values of the ones that are not set.
+func (c *AuditAsyncWriteConfig) Validate() error {
+	if c.QueueSize < 0 || c.BatchSize < 0 || c.FlushIntervalMS < 0 {
+		return fmt.Errorf("audit async write queue size %d, batch size %d or flush interval %d is invalid",
+			c.QueueSize, c.BatchSize, c.FlushIntervalMS)
+	}
+
+	if c.QueueSize == 0 {
+		c.QueueSize = AuditAsyncWriteDefaultQueueSize
+	}
+
+	if c.BatchSize == 0 {
+		c.BatchSize = AuditAsyncWriteDefaultBatchSize
+	}
+
+	if c.FlushIntervalMS == 0 {
+		c.FlushIntervalMS = AuditAsyncWriteDefaultFlushIntervalMS
+	}
+
+	if c.SyncActions == nil {
+		c.SyncActions = []ActionType{AuditDelete}
+	}
+
+	return nil
+}
+
+// IsSyncAction returns if the audit logs of the action are always saved synchronously.
+func (c *AuditAsyncWriteConfig) IsSyncAction(action ActionType) bool {
+	for _, syncAction := range c.SyncActions {
+		if syncAction == action {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLevel returns the detail level of the resource.
+func (c *AuditDetailConfig) GetLevel(resource ResourceType) AuditDetailLevel {
+	if level, exists := c.Levels[resource]; exists {
+		return level
+	}
+	return c.DefaultLevel
+}
+
+// GetBasicOpDetail returns the basic operation detail, it is promoted to the operation details that embed it, so
+// that the details of them can be processed in the same way.
+func (op *BasicOpDetail) GetBasicOpDetail() *BasicOpDetail {
+	return op
+}
+
+// BasicOpDetailGetter is the operation detail that embeds the basic operation detail.
+type BasicOpDetailGetter interface {
+	GetBasicOpDetail() *BasicOpDetail
+}
+
+// Compress compresses the details into CompressedDetails if their json size is not less than the size.
+func (op *BasicOpDetail) Compress(size int) error {
+	if op.Details == nil || size <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(op.Details)
+	if err != nil {
+		return err
+	}
+
+	if len(data) < size {
+		return nil
+	}
+
+	buf := new(bytes.Buffer)
+	writer := gzip.NewWriter(buf)
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	op.CompressedDetails = buf.Bytes()
+	op.Details = nil
+	return nil
+}
+
+// Decompress decompresses the CompressedDetails back into the details.
+func (op *BasicOpDetail) Decompress() error {
+	if len(op.CompressedDetails) == 0 {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(op.CompressedDetails))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	details := new(BasicContent)
+	if err := json.Unmarshal(data, details); err != nil {
+		return err
+	}
+
+	op.Details = details
+	op.CompressedDetails = nil
+	return nil
+}
+
+// KeepChangedFields removes the fields that are not changed by the operation from the previous and current data,
+// the data of the creation and deletion operations are all changed, so they are kept.
+func (c *BasicContent) KeepChangedFields() {
+	if c == nil || c.PreData == nil || c.CurData == nil {
+		return
+	}
+
+	preData := make(map[string]interface{})
+	curData := make(map[string]interface{})
+	for field, preValue := range c.PreData {
+		curValue, exists := c.CurData[field]
+		if exists && reflect.DeepEqual(preValue, curValue) {
+			continue
+		}
+		preData[field] = preValue
+		if exists {
+			curData[field] = curValue
+		}
+	}
+
+	for field, curValue := range c.CurData {
+		if _, exists := c.PreData[field]; !exists {
+			curData[field] = curValue
+		}
+	}
+
+	c.PreData = preData
+	c.CurData = curData
+}
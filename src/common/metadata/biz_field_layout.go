@@ -0,0 +1,141 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// BizFieldLayoutMaxFields the max number of the fields in a business field layout.
+const BizFieldLayoutMaxFields = 1000
+
+// BizFieldLayout is a business's override of the visibility, order and group of a model's attributes in the host
+// and instance forms, the global model is not changed.
+type BizFieldLayout struct {
+	BizID int64  `json:"bk_biz_id" bson:"bk_biz_id"`
+	ObjID string `json:"bk_obj_id" bson:"bk_obj_id"`
+	// Fields the attributes that are overridden, in the display order. the attributes that are not in the fields
+	// are displayed after them in the model's order.
+	Fields []BizFieldLayoutField `json:"fields" bson:"fields"`
+
+	Modifier        string    `json:"modifier" bson:"modifier"`
+	LastTime        time.Time `json:"last_time" bson:"last_time"`
+	SupplierAccount string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+}
+
+// BizFieldLayoutField is the override of an attribute in the business field layout.
+type BizFieldLayoutField struct {
+	PropertyID string `json:"bk_property_id" bson:"bk_property_id"`
+	// Hidden the attribute is hidden in the forms of the business, and is not exported by default.
+	Hidden bool `json:"hidden" bson:"hidden"`
+	// PropertyGroup the attribute group that the attribute is displayed in, use the model's group if not set.
+	PropertyGroup string `json:"bk_property_group,omitempty" bson:"bk_property_group"`
+}
+
+// Validate validate the business field layout
+func (l *BizFieldLayout) Validate() errors.RawErrorInfo {
+	if l.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if len(l.ObjID) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKObjIDField}}
+	}
+
+	if len(l.Fields) > BizFieldLayoutMaxFields {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"fields", BizFieldLayoutMaxFields},
+		}
+	}
+
+	propertyIDs := make(map[string]struct{}, len(l.Fields))
+	for _, field := range l.Fields {
+		if len(field.PropertyID) == 0 {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsNeedSet,
+				Args:    []interface{}{common.BKPropertyIDField},
+			}
+		}
+
+		if _, exists := propertyIDs[field.PropertyID]; exists {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommDuplicateItem, Args: []interface{}{field.PropertyID}}
+		}
+		propertyIDs[field.PropertyID] = struct{}{}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// Apply returns the visible attributes of the business field layout in the display order, the attributes' groups
+// are overridden by the layout, and the attributes' indexes are reset to their display order.
+func (l *BizFieldLayout) Apply(attrs []Attribute) []Attribute {
+	if l == nil || len(l.Fields) == 0 {
+		return attrs
+	}
+
+	attrMap := make(map[string]Attribute, len(attrs))
+	for _, attr := range attrs {
+		attrMap[attr.PropertyID] = attr
+	}
+
+	result := make([]Attribute, 0, len(attrs))
+	overridden := make(map[string]struct{}, len(l.Fields))
+	for _, field := range l.Fields {
+		overridden[field.PropertyID] = struct{}{}
+
+		attr, exists := attrMap[field.PropertyID]
+		if !exists || field.Hidden {
+			continue
+		}
+
+		if len(field.PropertyGroup) != 0 {
+			attr.PropertyGroup = field.PropertyGroup
+		}
+		result = append(result, attr)
+	}
+
+	for _, attr := range attrs {
+		if _, exists := overridden[attr.PropertyID]; !exists {
+			result = append(result, attr)
+		}
+	}
+
+	for index := range result {
+		result[index].PropertyIndex = int64(index)
+	}
+
+	return result
+}
+
+// BizFieldLayoutResult is the business field layout with the attributes applied by it.
+type BizFieldLayoutResult struct {
+	Layout BizFieldLayout `json:"layout"`
+	// Attributes the visible attributes of the model in the display order of the business.
+	Attributes []Attribute `json:"attributes"`
+}
+
+// BizFieldLayoutResp is the response of a business field layout.
+type BizFieldLayoutResp struct {
+	BaseResp `json:",inline"`
+	Data     *BizFieldLayout `json:"data"`
+}
+
+// BizFieldLayoutOption is the option to find or delete a business field layout.
+type BizFieldLayoutOption struct {
+	BizID int64  `json:"bk_biz_id"`
+	ObjID string `json:"bk_obj_id"`
+}
@@ -0,0 +1,256 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// BizRole is a responsibility of the users in a business, it is used to route the notifications of the business.
+type BizRole string
+
+const (
+	// BizRoleOwner the owner of the business or the topo node
+	BizRoleOwner BizRole = "owner"
+	// BizRoleOps the operators of the business or the topo node
+	BizRoleOps BizRole = "ops"
+	// BizRoleDev the developers of the business or the topo node
+	BizRoleDev BizRole = "dev"
+	// BizRoleSecurity the security officers of the business or the topo node
+	BizRoleSecurity BizRole = "security"
+)
+
+// BizRoles all the business roles
+var BizRoles = []BizRole{BizRoleOwner, BizRoleOps, BizRoleDev, BizRoleSecurity}
+
+// Validate validate the business role
+func (r BizRole) Validate() errors.RawErrorInfo {
+	for _, role := range BizRoles {
+		if r == role {
+			return errors.RawErrorInfo{}
+		}
+	}
+	return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"role"}}
+}
+
+// BizRoleReceiverPrefix is the prefix of the receiver that refers to the users of a business role, e.g. "role:ops"
+// refers to the operators of the business.
+const BizRoleReceiverPrefix = "role:"
+
+// BizRoleMaxUsers the max number of the users assigned to a business role of a topo node.
+const BizRoleMaxUsers = 100
+
+// BizRoleDefaultAttributes are the user type attributes of the topo nodes that are used as the role's users when
+// the role is not assigned to the node, e.g. the operators of a module are its operator and bak operator.
+var BizRoleDefaultAttributes = map[BizRole]map[string][]string{
+	BizRoleOwner: {
+		common.BKInnerObjIDApp: {common.BKProductPMField},
+	},
+	BizRoleOps: {
+		common.BKInnerObjIDModule: {common.BKOperatorField, common.BKBakOperatorField},
+		common.BKInnerObjIDApp:    {common.BKMaintainersField},
+	},
+	BizRoleDev: {
+		common.BKInnerObjIDApp: {common.BKDeveloperField},
+	},
+}
+
+// BizRoleAssignment is the users that are assigned to a business role of a topo node in the business, the nodes
+// inherit the assignments of their parents, e.g. a module's operators are its set's operators if not assigned.
+type BizRoleAssignment struct {
+	BizID int64 `json:"bk_biz_id" bson:"bk_biz_id"`
+	// ObjID the object of the topo node, only business, set and module are supported.
+	ObjID  string   `json:"bk_obj_id" bson:"bk_obj_id"`
+	InstID int64    `json:"bk_inst_id" bson:"bk_inst_id"`
+	Role   BizRole  `json:"role" bson:"role"`
+	Users  []string `json:"users" bson:"users"`
+
+	Modifier        string    `json:"modifier" bson:"modifier"`
+	LastTime        time.Time `json:"last_time" bson:"last_time"`
+	SupplierAccount string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+}
+
+// Validate validate the business role assignment
+func (a *BizRoleAssignment) Validate() errors.RawErrorInfo {
+	if rawErr := a.BizRoleNode().Validate(); rawErr.ErrCode != 0 {
+		return rawErr
+	}
+
+	if rawErr := a.Role.Validate(); rawErr.ErrCode != 0 {
+		return rawErr
+	}
+
+	if len(a.Users) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"users"}}
+	}
+
+	if len(a.Users) > BizRoleMaxUsers {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"users", BizRoleMaxUsers},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// BizRoleNode returns the topo node that the role is assigned to.
+func (a *BizRoleAssignment) BizRoleNode() *BizRoleNode {
+	return &BizRoleNode{BizID: a.BizID, ObjID: a.ObjID, InstID: a.InstID}
+}
+
+// BizRoleNode is a topo node in the business that the roles can be assigned to.
+type BizRoleNode struct {
+	BizID  int64  `json:"bk_biz_id"`
+	ObjID  string `json:"bk_obj_id"`
+	InstID int64  `json:"bk_inst_id"`
+}
+
+// Validate validate the business role node
+func (n *BizRoleNode) Validate() errors.RawErrorInfo {
+	if n.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	switch n.ObjID {
+	case common.BKInnerObjIDApp:
+		if n.InstID != n.BizID {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKInstIDField}}
+		}
+	case common.BKInnerObjIDSet, common.BKInnerObjIDModule:
+		if n.InstID <= 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKInstIDField}}
+		}
+	default:
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKObjIDField}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// DeleteBizRoleAssignmentOption is the option to delete the assignment of a business role of a topo node.
+type DeleteBizRoleAssignmentOption struct {
+	BizRoleNode `json:",inline"`
+	Role        BizRole `json:"role"`
+}
+
+// Validate validate the delete business role assignment option
+func (o *DeleteBizRoleAssignmentOption) Validate() errors.RawErrorInfo {
+	if rawErr := o.BizRoleNode.Validate(); rawErr.ErrCode != 0 {
+		return rawErr
+	}
+
+	return o.Role.Validate()
+}
+
+// ListBizRoleAssignmentOption is the option to list the business role assignments of a business.
+type ListBizRoleAssignmentOption struct {
+	BizID int64 `json:"bk_biz_id"`
+	// ObjID the object of the topo nodes, list the assignments of all the nodes if not set.
+	ObjID   string    `json:"bk_obj_id,omitempty"`
+	InstIDs []int64   `json:"bk_inst_ids,omitempty"`
+	Roles   []BizRole `json:"roles,omitempty"`
+}
+
+// Validate validate the list business role assignment option
+func (o *ListBizRoleAssignmentOption) Validate() errors.RawErrorInfo {
+	if o.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if len(o.InstIDs) > 0 && len(o.ObjID) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKObjIDField}}
+	}
+
+	if len(o.InstIDs) > common.BKMaxInstanceLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"bk_inst_ids", common.BKMaxInstanceLimit},
+		}
+	}
+
+	for _, role := range o.Roles {
+		if rawErr := role.Validate(); rawErr.ErrCode != 0 {
+			return rawErr
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// ResolveBizRoleReceiverOption is the option to resolve the users that should be notified for a business or a
+// module in the business.
+type ResolveBizRoleReceiverOption struct {
+	BizID int64 `json:"bk_biz_id"`
+	// ModuleID the module that the notification is about, the notification is about the business if not set.
+	ModuleID int64 `json:"bk_module_id,omitempty"`
+	// Roles the roles that should be notified, all the roles are resolved if not set.
+	Roles []BizRole `json:"roles,omitempty"`
+}
+
+// Validate validate the resolve business role receiver option, and set the default roles.
+func (o *ResolveBizRoleReceiverOption) Validate() errors.RawErrorInfo {
+	if o.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if o.ModuleID < 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKModuleIDField}}
+	}
+
+	if len(o.Roles) == 0 {
+		o.Roles = BizRoles
+		return errors.RawErrorInfo{}
+	}
+
+	for _, role := range o.Roles {
+		if rawErr := role.Validate(); rawErr.ErrCode != 0 {
+			return rawErr
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// BizRoleReceiver is the users of a business role that should be notified.
+type BizRoleReceiver struct {
+	Role  BizRole  `json:"role"`
+	Users []string `json:"users"`
+	// ObjID and InstID are the topo node that the users are resolved from, they are not set if no users are found.
+	ObjID  string `json:"bk_obj_id,omitempty"`
+	InstID int64  `json:"bk_inst_id,omitempty"`
+	// FromAttribute whether the users are resolved from the default attributes of the topo node instead of the
+	// role assignments.
+	FromAttribute bool `json:"from_attribute"`
+}
+
+// BizRoleAssignmentResult is the response of a business role assignment.
+type BizRoleAssignmentResult struct {
+	BaseResp `json:",inline"`
+	Data     *BizRoleAssignment `json:"data"`
+}
+
+// BizRoleAssignmentsResult is the response of the business role assignments.
+type BizRoleAssignmentsResult struct {
+	BaseResp `json:",inline"`
+	Data     []BizRoleAssignment `json:"data"`
+}
+
+// BizRoleReceiversResult is the response of the resolved business role receivers.
+type BizRoleReceiversResult struct {
+	BaseResp `json:",inline"`
+	Data     []BizRoleReceiver `json:"data"`
+}
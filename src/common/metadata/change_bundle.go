@@ -0,0 +1,257 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+)
+
+// ChangeBundleChangeType is the type of a change in the change bundle.
+type ChangeBundleChangeType string
+
+const (
+	// ChangeBundleCreateSet create a set, the data is the set's attributes
+	ChangeBundleCreateSet ChangeBundleChangeType = "create_set"
+	// ChangeBundleCreateModule create a module, the data is the module's attributes with the bk_set_id
+	ChangeBundleCreateModule ChangeBundleChangeType = "create_module"
+	// ChangeBundleTransferHost transfer hosts to modules, the data is the same as HostsModuleRelation
+	ChangeBundleTransferHost ChangeBundleChangeType = "transfer_host"
+	// ChangeBundleCreateServiceInstance create a service instance, the data is ChangeBundleServiceInstance
+	ChangeBundleCreateServiceInstance ChangeBundleChangeType = "create_service_instance"
+)
+
+// ChangeBundleMode is the way that the changes in the change bundle are executed.
+type ChangeBundleMode string
+
+const (
+	// ChangeBundleTransactionMode execute all changes in one transaction, none of them takes effect if any one fails.
+	ChangeBundleTransactionMode ChangeBundleMode = "transaction"
+	// ChangeBundleCompensationMode execute the changes one by one without transaction, the executed changes are
+	// compensated in the reverse order if any one fails. it is used when the bundle can not be done in one
+	// transaction, e.g. the bundle is too large to be finished within the transaction's time limit.
+	ChangeBundleCompensationMode ChangeBundleMode = "compensation"
+)
+
+const (
+	// ChangeBundleMaxChanges the max number of the changes in a change bundle
+	ChangeBundleMaxChanges = 100
+	// ChangeBundleRefPrefix is the prefix of the value that refers to the id of the resource created by a previous
+	// change in the bundle, e.g. "$ref:my_set" is the bk_set_id of the set created by the change whose ref is my_set
+	ChangeBundleRefPrefix = "$ref:"
+)
+
+// ChangeBundleOption is the option to execute a bundle of related changes in a business.
+type ChangeBundleOption struct {
+	// Mode the execute mode of the bundle, default is transaction
+	Mode    ChangeBundleMode     `json:"mode"`
+	Changes []ChangeBundleChange `json:"changes"`
+}
+
+// ChangeBundleChange is a change in the change bundle.
+type ChangeBundleChange struct {
+	Type ChangeBundleChangeType `json:"type"`
+	// Ref the reference name of the change, the following changes can refer to the id of the resource created by
+	// this change with the ChangeBundleRefPrefix.
+	Ref  string        `json:"ref,omitempty"`
+	Data mapstr.MapStr `json:"data"`
+}
+
+// ChangeBundleServiceInstance is the data of the ChangeBundleCreateServiceInstance change, the service instance
+// is created with the service template of the module.
+type ChangeBundleServiceInstance struct {
+	ModuleID int64  `json:"bk_module_id"`
+	HostID   int64  `json:"bk_host_id"`
+	Name     string `json:"name"`
+}
+
+// Validate validate the change bundle option, and set the default mode.
+func (o *ChangeBundleOption) Validate() errors.RawErrorInfo {
+	switch o.Mode {
+	case "":
+		o.Mode = ChangeBundleTransactionMode
+	case ChangeBundleTransactionMode, ChangeBundleCompensationMode:
+	default:
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"mode"}}
+	}
+
+	if len(o.Changes) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"changes"}}
+	}
+
+	if len(o.Changes) > ChangeBundleMaxChanges {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"changes", ChangeBundleMaxChanges},
+		}
+	}
+
+	refs := make(map[string]struct{})
+	for index, change := range o.Changes {
+		switch change.Type {
+		case ChangeBundleCreateSet, ChangeBundleCreateModule, ChangeBundleTransferHost,
+			ChangeBundleCreateServiceInstance:
+		default:
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{fmt.Sprintf("changes[%d].type", index)},
+			}
+		}
+
+		if len(change.Data) == 0 {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsNeedSet,
+				Args:    []interface{}{fmt.Sprintf("changes[%d].data", index)},
+			}
+		}
+
+		// only the changes referring to the previous changes are allowed, so that the changes can be executed in order
+		for _, ref := range change.referredRefs() {
+			if _, exists := refs[ref]; !exists {
+				return errors.RawErrorInfo{
+					ErrCode: common.CCErrCommParamsInvalid,
+					Args:    []interface{}{fmt.Sprintf("changes[%d].data", index)},
+				}
+			}
+		}
+
+		if len(change.Ref) == 0 {
+			continue
+		}
+
+		if change.Type == ChangeBundleTransferHost {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{fmt.Sprintf("changes[%d].ref", index)},
+			}
+		}
+
+		if _, exists := refs[change.Ref]; exists {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommDuplicateItem, Args: []interface{}{change.Ref}}
+		}
+		refs[change.Ref] = struct{}{}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// referredRefs returns the refs of the previous changes that the change's data refers to.
+func (c *ChangeBundleChange) referredRefs() []string {
+	refs := make([]string, 0)
+	for _, value := range c.Data {
+		switch val := value.(type) {
+		case string:
+			if strings.HasPrefix(val, ChangeBundleRefPrefix) {
+				refs = append(refs, strings.TrimPrefix(val, ChangeBundleRefPrefix))
+			}
+		case []interface{}:
+			for _, item := range val {
+				str, ok := item.(string)
+				if ok && strings.HasPrefix(str, ChangeBundleRefPrefix) {
+					refs = append(refs, strings.TrimPrefix(str, ChangeBundleRefPrefix))
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// ResolveRefs returns the change's data with the refs replaced by the ids of the resources created by the previous
+// changes, the change's own data is not modified.
+func (c *ChangeBundleChange) ResolveRefs(ids map[string]int64) (mapstr.MapStr, error) {
+	resolve := func(value string) (interface{}, error) {
+		if !strings.HasPrefix(value, ChangeBundleRefPrefix) {
+			return value, nil
+		}
+		id, exists := ids[strings.TrimPrefix(value, ChangeBundleRefPrefix)]
+		if !exists {
+			return nil, fmt.Errorf("reference %s is not found", value)
+		}
+		return id, nil
+	}
+
+	data := make(mapstr.MapStr, len(c.Data))
+	for key, value := range c.Data {
+		switch val := value.(type) {
+		case string:
+			resolved, err := resolve(val)
+			if err != nil {
+				return nil, err
+			}
+			data[key] = resolved
+		case []interface{}:
+			items := make([]interface{}, len(val))
+			for index, item := range val {
+				str, ok := item.(string)
+				if !ok {
+					items[index] = item
+					continue
+				}
+				resolved, err := resolve(str)
+				if err != nil {
+					return nil, err
+				}
+				items[index] = resolved
+			}
+			data[key] = items
+		default:
+			data[key] = value
+		}
+	}
+	return data, nil
+}
+
+// ChangeBundleCompensation is the step to compensate an executed change of the change bundle.
+type ChangeBundleCompensation struct {
+	// ChangeIndex the index of the compensated change in the bundle
+	ChangeIndex int `json:"change_index"`
+	// Action the compensation action, e.g. delete the created set
+	Action string        `json:"action"`
+	Data   mapstr.MapStr `json:"data"`
+	// Executed whether the compensation is executed successfully
+	Executed bool `json:"executed"`
+	// Error the error message if the compensation is failed
+	Error string `json:"error,omitempty"`
+}
+
+const (
+	// ChangeBundleDeleteSet the compensation action that deletes the created set
+	ChangeBundleDeleteSet = "delete_set"
+	// ChangeBundleDeleteModule the compensation action that deletes the created module
+	ChangeBundleDeleteModule = "delete_module"
+	// ChangeBundleRestoreHostRelation the compensation action that transfers the hosts back to the original modules
+	ChangeBundleRestoreHostRelation = "restore_host_relation"
+	// ChangeBundleDeleteServiceInstance the compensation action that deletes the created service instance
+	ChangeBundleDeleteServiceInstance = "delete_service_instance"
+)
+
+// ChangeBundleChangeResult is the result of an executed change in the change bundle.
+type ChangeBundleChangeResult struct {
+	Index int                    `json:"index"`
+	Type  ChangeBundleChangeType `json:"type"`
+	Ref   string                 `json:"ref,omitempty"`
+	// ID the id of the resource created by the change
+	ID int64 `json:"id,omitempty"`
+}
+
+// ChangeBundleResult is the result of the change bundle.
+type ChangeBundleResult struct {
+	Mode    ChangeBundleMode           `json:"mode"`
+	Changes []ChangeBundleChangeResult `json:"changes"`
+	// Compensations the compensation plan of the executed changes in the reverse order
+	Compensations []ChangeBundleCompensation `json:"compensations"`
+}
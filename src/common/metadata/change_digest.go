@@ -0,0 +1,104 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// ChangeDigestPeriod is the period that the change digest of a business summarizes.
+type ChangeDigestPeriod string
+
+const (
+	// ChangeDigestDaily summarize the changes in the last day.
+	ChangeDigestDaily ChangeDigestPeriod = "daily"
+	// ChangeDigestWeekly summarize the changes in the last week.
+	ChangeDigestWeekly ChangeDigestPeriod = "weekly"
+)
+
+// Duration returns the duration of the period, returns 0 if the period is invalid.
+func (p ChangeDigestPeriod) Duration() time.Duration {
+	switch p {
+	case ChangeDigestDaily:
+		return 24 * time.Hour
+	case ChangeDigestWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+const (
+	// ChangeDigestTopChangerCount the number of the top changers in the change digest.
+	ChangeDigestTopChangerCount = 10
+)
+
+// ChangeDigestOption is the option to generate the change digest of a business.
+type ChangeDigestOption struct {
+	BizID  int64              `json:"bk_biz_id"`
+	Period ChangeDigestPeriod `json:"period"`
+	// EndTime the end of the period, use the current time if not set.
+	EndTime *time.Time `json:"end_time,omitempty"`
+}
+
+// Validate validate the change digest option.
+func (o *ChangeDigestOption) Validate() errors.RawErrorInfo {
+	if o.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if o.Period.Duration() == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"period"}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// ChangeDigest is the summary of the changes in a business during a period, which is generated from the audit logs
+// and the template sync histories.
+type ChangeDigest struct {
+	BizID     int64              `json:"bk_biz_id"`
+	Period    ChangeDigestPeriod `json:"period"`
+	StartTime time.Time          `json:"start_time"`
+	EndTime   time.Time          `json:"end_time"`
+	// HostAdded the number of the hosts created in or assigned to the business.
+	HostAdded int64 `json:"host_added"`
+	// HostRemoved the number of the hosts deleted from or unassigned from the business.
+	HostRemoved int64 `json:"host_removed"`
+	// TopoChanges the number of the changes of the business's topology, grouped by the resource and the action.
+	TopoChanges []ChangeDigestTopoChange `json:"topo_changes"`
+	// SetTemplateSyncs the number of the sets synchronized with their set templates.
+	SetTemplateSyncs int64 `json:"set_template_syncs"`
+	// ServiceTemplateSyncs the number of the modules synchronized with their service templates.
+	ServiceTemplateSyncs int64 `json:"service_template_syncs"`
+	// TopChangers the users who made the most changes, sorted by the number of the changes.
+	TopChangers []ChangeDigestChanger `json:"top_changers"`
+	// Truncated is true if the business has too many changes in the period, and the top changers are counted from
+	// part of them.
+	Truncated bool `json:"truncated"`
+}
+
+// ChangeDigestTopoChange is the number of a kind of topology changes.
+type ChangeDigestTopoChange struct {
+	ResourceType ResourceType `json:"resource_type"`
+	Action       ActionType   `json:"action"`
+	Count        int64        `json:"count"`
+}
+
+// ChangeDigestChanger is the number of the changes made by a user.
+type ChangeDigestChanger struct {
+	User  string `json:"user"`
+	Count int64  `json:"count"`
+}
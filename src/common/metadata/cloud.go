@@ -315,6 +315,8 @@ type Instance struct {
 	PublicIp      string `json:"bk_host_outerip" bson:"bk_host_outerip"`
 	InstanceState string `json:"bk_cloud_host_status" bson:"bk_cloud_host_status"`
 	VpcId         string `json:"bk_vpc_id" bson:"bk_vpc_id"`
+	Region        string `json:"bk_cloud_region" bson:"bk_cloud_region"`
+	Zone          string `json:"bk_cloud_zone" bson:"bk_cloud_zone"`
 }
 
 // CloudHostResource 云主机同步时的资源数据
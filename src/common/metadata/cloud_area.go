@@ -0,0 +1,127 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/util"
+)
+
+const (
+	// CloudAreaUsageMaxCount the max number of the cloud areas to get the usages at once
+	CloudAreaUsageMaxCount = 50
+	// CloudAreaUsageDefaultHostLimit the default number of the hosts returned in each cloud area's usage
+	CloudAreaUsageDefaultHostLimit = 10
+	// CloudAreaUsageMaxHostLimit the max number of the hosts returned in each cloud area's usage
+	CloudAreaUsageMaxHostLimit = 100
+	// CloudAreaMergeMaxCount the max number of the cloud areas to be merged at once
+	CloudAreaMergeMaxCount = 20
+)
+
+// CloudAreaUsageOption is the option to get the resources bound to the cloud areas.
+type CloudAreaUsageOption struct {
+	CloudIDs []int64 `json:"bk_cloud_ids"`
+	// HostLimit the max number of the hosts returned in each cloud area's usage, the host count is not limited.
+	HostLimit int `json:"host_limit"`
+}
+
+// Validate validate the cloud area usage option, and set the default host limit.
+func (o *CloudAreaUsageOption) Validate() errors.RawErrorInfo {
+	if len(o.CloudIDs) == 0 || len(o.CloudIDs) > CloudAreaUsageMaxCount {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrArrayLengthWrong,
+			Args:    []interface{}{"bk_cloud_ids", CloudAreaUsageMaxCount},
+		}
+	}
+	o.CloudIDs = util.IntArrayUnique(o.CloudIDs)
+
+	if o.HostLimit == 0 {
+		o.HostLimit = CloudAreaUsageDefaultHostLimit
+	}
+
+	if o.HostLimit < 0 || o.HostLimit > CloudAreaUsageMaxHostLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"host_limit", CloudAreaUsageMaxHostLimit},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// CloudAreaUsage is the resources bound to a cloud area, a cloud area can be deleted only if it is not used.
+type CloudAreaUsage struct {
+	CloudID   int64 `json:"bk_cloud_id"`
+	HostCount int64 `json:"host_count"`
+	// Hosts the hosts in the cloud area with their ids and inner ips, at most host limit hosts are returned.
+	Hosts []mapstr.MapStr `json:"hosts"`
+	// SyncTaskIDs the ids of the cloud sync tasks which sync hosts into the cloud area.
+	SyncTaskIDs []int64 `json:"sync_task_ids"`
+}
+
+// IsUsed returns if the cloud area has resources bound to it.
+func (u *CloudAreaUsage) IsUsed() bool {
+	return u.HostCount > 0 || len(u.SyncTaskIDs) > 0
+}
+
+// RelocateCloudAreaHostsOption is the option to relocate all the hosts of the cloud area to the target cloud area.
+type RelocateCloudAreaHostsOption struct {
+	TargetCloudID int64 `json:"target_bk_cloud_id"`
+}
+
+// RelocateCloudAreaHostsResult is the result of the cloud area hosts relocation.
+type RelocateCloudAreaHostsResult struct {
+	RelocatedCount int `json:"relocated_count"`
+}
+
+// MergeCloudAreaOption is the option to merge the source cloud areas into the target cloud area, the hosts of the
+// source cloud areas are relocated to the target, then the sources are deleted.
+type MergeCloudAreaOption struct {
+	SourceCloudIDs []int64 `json:"source_bk_cloud_ids"`
+	TargetCloudID  int64   `json:"target_bk_cloud_id"`
+}
+
+// Validate validate the merge cloud area option.
+func (o *MergeCloudAreaOption) Validate() errors.RawErrorInfo {
+	if len(o.SourceCloudIDs) == 0 || len(o.SourceCloudIDs) > CloudAreaMergeMaxCount {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrArrayLengthWrong,
+			Args:    []interface{}{"source_bk_cloud_ids", CloudAreaMergeMaxCount},
+		}
+	}
+	o.SourceCloudIDs = util.IntArrayUnique(o.SourceCloudIDs)
+
+	if o.TargetCloudID < 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"target_bk_cloud_id"}}
+	}
+
+	for _, cloudID := range o.SourceCloudIDs {
+		// the default cloud area can not be deleted, so it can not be merged into others
+		if cloudID <= 0 || cloudID == o.TargetCloudID {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{"source_bk_cloud_ids"},
+			}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// MergeCloudAreaResult is the result of the cloud areas merge.
+type MergeCloudAreaResult struct {
+	RelocatedCount  int     `json:"relocated_count"`
+	DeletedCloudIDs []int64 `json:"deleted_bk_cloud_ids"`
+}
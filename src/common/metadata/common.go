@@ -473,6 +473,8 @@ func (f *CommonSearchFilter) Validate() (string, error) {
 	option := &querybuilder.RuleOption{
 		NeedSameSliceElementType: true,
 		MaxSliceElementsCount:    querybuilder.DefaultMaxSliceElementsCount,
+		MaxValueBytes:            querybuilder.DefaultMaxValueBytes,
+		MaxStringLength:          querybuilder.DefaultMaxStringLength,
 		MaxConditionOrRulesCount: querybuilder.DefaultMaxConditionOrRulesCount,
 	}
 
@@ -540,6 +542,8 @@ func (f *CommonCountFilter) Validate() (string, error) {
 	option := &querybuilder.RuleOption{
 		NeedSameSliceElementType: true,
 		MaxSliceElementsCount:    querybuilder.DefaultMaxSliceElementsCount,
+		MaxValueBytes:            querybuilder.DefaultMaxValueBytes,
+		MaxStringLength:          querybuilder.DefaultMaxStringLength,
 		MaxConditionOrRulesCount: querybuilder.DefaultMaxConditionOrRulesCount,
 	}
 
@@ -0,0 +1,131 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// ConfigTemplateRefMaxCount the max number of the config template references of a process or service instance.
+	ConfigTemplateRefMaxCount = 20
+	// ConfigTemplateIDMaxLength the max length of the config template id.
+	ConfigTemplateIDMaxLength = 128
+	// ConfigTemplateVersionMaxLength the max length of the config template version.
+	ConfigTemplateVersionMaxLength = 64
+)
+
+// ConfigTemplateRef is a reference to the config template managed by the external config system, which is used by
+// the config distribution workflows to render and distribute the config files.
+type ConfigTemplateRef struct {
+	TemplateID string `field:"template_id" json:"template_id" bson:"template_id" mapstructure:"template_id"`
+	Version    string `field:"version" json:"version" bson:"version" mapstructure:"version"`
+}
+
+// ConfigTemplateRefs the config template references, each config template can be referenced only once.
+type ConfigTemplateRefs []ConfigTemplateRef
+
+// Validate validate the config template references
+func (r ConfigTemplateRefs) Validate() error {
+	if len(r) > ConfigTemplateRefMaxCount {
+		return fmt.Errorf("config template count %d exceeds max count %d", len(r), ConfigTemplateRefMaxCount)
+	}
+
+	templateIDs := make(map[string]struct{})
+	for idx := range r {
+		r[idx].TemplateID = strings.TrimSpace(r[idx].TemplateID)
+		r[idx].Version = strings.TrimSpace(r[idx].Version)
+
+		if len(r[idx].TemplateID) == 0 {
+			return errors.New("config template id is not set")
+		}
+		if utf8.RuneCountInString(r[idx].TemplateID) > ConfigTemplateIDMaxLength {
+			return fmt.Errorf("config template id %s exceeds max length %d", r[idx].TemplateID,
+				ConfigTemplateIDMaxLength)
+		}
+
+		if len(r[idx].Version) == 0 {
+			return fmt.Errorf("config template %s version is not set", r[idx].TemplateID)
+		}
+		if utf8.RuneCountInString(r[idx].Version) > ConfigTemplateVersionMaxLength {
+			return fmt.Errorf("config template %s version exceeds max length %d", r[idx].TemplateID,
+				ConfigTemplateVersionMaxLength)
+		}
+
+		if _, exists := templateIDs[r[idx].TemplateID]; exists {
+			return fmt.Errorf("config template %s is duplicated", r[idx].TemplateID)
+		}
+		templateIDs[r[idx].TemplateID] = struct{}{}
+	}
+
+	return nil
+}
+
+// Equal check if the config template references are the same, the nil references equals to the empty ones.
+func (r ConfigTemplateRefs) Equal(other ConfigTemplateRefs) bool {
+	if len(r) != len(other) {
+		return false
+	}
+
+	for idx := range r {
+		if r[idx] != other[idx] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PropertyConfigTemplates is the config template references of the process template, which are synced to the process
+// instances like the other process template fields.
+type PropertyConfigTemplates struct {
+	Value *ConfigTemplateRefs `field:"value" json:"value" bson:"value"`
+
+	// AsDefaultValue records whether the value is used by all the process instances of the process template.
+	AsDefaultValue *bool `field:"as_default_value" json:"as_default_value" bson:"as_default_value"`
+}
+
+// Validate validate the config template references of the process template
+func (p *PropertyConfigTemplates) Validate() error {
+	if p.Value == nil {
+		return nil
+	}
+	return p.Value.Validate()
+}
+
+// parseConfigTemplateRefs parse the config template references from the raw value and validate them
+func parseConfigTemplateRefs(value interface{}) (ConfigTemplateRefs, error) {
+	refs := make(ConfigTemplateRefs, 0)
+	if value == nil {
+		return refs, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &refs); err != nil {
+		return nil, err
+	}
+
+	if err := refs.Validate(); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
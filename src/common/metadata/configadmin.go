@@ -132,6 +132,10 @@ func (s GlobalModule) Validate() error {
 type AdminBackendCfg struct {
 	MaxBizTopoLevel int64  `json:"max_biz_topo_level"`
 	SnapshotBizName string `json:"snapshot_biz_name"`
+	// MaxTopoChildNodes the max number of the child nodes under one parent node in the business topology, keyed by
+	// the object id of the child nodes, e.g. {"set": 500} limits each business or custom level node to 500 sets.
+	// the levels not in it are unlimited.
+	MaxTopoChildNodes map[string]int64 `json:"max_topo_child_nodes,omitempty"`
 }
 
 // Validate validate the fields of BackendCfg.
@@ -144,6 +148,16 @@ func (b AdminBackendCfg) Validate() error {
 	if b.MaxBizTopoLevel < minBizTopoLevel || b.MaxBizTopoLevel > maxBizTopoLevel {
 		return fmt.Errorf("max biz topo level value must in range [%d-%d]", minBizTopoLevel, maxBizTopoLevel)
 	}
+
+	for objID, limit := range b.MaxTopoChildNodes {
+		if objID == common.BKInnerObjIDApp || strings.TrimSpace(objID) == "" {
+			return fmt.Errorf("max topo child nodes object id %s is invalid", objID)
+		}
+
+		if limit <= 0 || limit > maxTopoChildNodes {
+			return fmt.Errorf("max topo child nodes of %s must in range [1-%d]", objID, maxTopoChildNodes)
+		}
+	}
 	return nil
 }
 
@@ -192,6 +206,8 @@ func (c *PlatformSettingConfig) Validate() error {
 const (
 	maxBizTopoLevel = 10
 	minBizTopoLevel = 3
+
+	maxTopoChildNodes = 100000
 )
 
 // InitAdminConfig factory configuration.
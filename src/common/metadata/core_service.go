@@ -96,6 +96,35 @@ type CreateModelInstance struct {
 	Data mapstr.MapStr `json:"data"`
 }
 
+// CreateOrUpdateModelInstance is the option to create the instance, or update the instance if the one with the same
+// natural key exists, the natural key is the keys of the model's unique rule.
+type CreateOrUpdateModelInstance struct {
+	// UniqueID the id of the unique rule used as the natural key, the first unique rule whose keys are all set in
+	// the data is used if it is not set.
+	UniqueID uint64        `json:"bk_unique_id"`
+	Data     mapstr.MapStr `json:"data"`
+	// ExternalID the id of the instance in the source system, the instance bound to it is updated if exists, and
+	// the created or updated instance is bound to it.
+	ExternalID *ExternalIDRef `json:"external_id,omitempty"`
+}
+
+// CreateOrUpdateInstResult is the result of creating or updating the instance by the natural key.
+type CreateOrUpdateInstResult struct {
+	ID uint64 `json:"id"`
+	// Created the instance is created.
+	Created bool `json:"created"`
+	// Updated the instance exists and its values are changed.
+	Updated bool `json:"updated"`
+	// PreData the instance before it is updated, it is set only when the instance exists.
+	PreData mapstr.MapStr `json:"pre_data,omitempty"`
+}
+
+// CreateOrUpdateInstResp is the response of creating or updating the instance by the natural key.
+type CreateOrUpdateInstResp struct {
+	BaseResp `json:",inline"`
+	Data     CreateOrUpdateInstResult `json:"data"`
+}
+
 // CreateManyModelInstance TODO
 type CreateManyModelInstance struct {
 	Datas []mapstr.MapStr `json:"datas"`
@@ -307,6 +336,9 @@ type HostModuleRelationRequest struct {
 	ModuleIDArr   []int64  `json:"bk_module_ids" bson:"bk_module_ids" field:"bk_module_ids" mapstructure:"bk_module_ids"`
 	Page          BasePage `json:"page" bson:"page" field:"page" mapstructure:"page"`
 	Fields        []string `json:"field" bson:"field"  field:"field" mapstructure:"field"`
+	// AfterHostID only gets the relations of the hosts whose id is greater than it if it is set, which is used to
+	// iterate the relations by the host id.
+	AfterHostID int64 `json:"after_host_id,omitempty" bson:"after_host_id" field:"after_host_id" mapstructure:"after_host_id"`
 }
 
 // Empty empty struct
@@ -19,6 +19,7 @@ import (
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/querybuilder"
 	"configcenter/src/common/util"
 
 	"github.com/google/uuid"
@@ -76,6 +77,24 @@ var (
 	}
 )
 
+// set operators of the dynamic group composition.
+const (
+	// DynamicGroupCompositionUnion the members of any of the composed groups.
+	DynamicGroupCompositionUnion = "union"
+
+	// DynamicGroupCompositionIntersection the members of all the composed groups.
+	DynamicGroupCompositionIntersection = "intersection"
+
+	// DynamicGroupCompositionDifference the members of the first composed group excluding the other groups'.
+	DynamicGroupCompositionDifference = "difference"
+
+	// DynamicGroupCompositionMaxDepth the max depth of the nested dynamic group compositions.
+	DynamicGroupCompositionMaxDepth = 3
+
+	// DynamicGroupCompositionMaxGroups the max number of the groups composed by a dynamic group.
+	DynamicGroupCompositionMaxGroups = 10
+)
+
 // Validatefunc is func callback for validating.
 type Validatefunc func(objectID string) ([]Attribute, error)
 
@@ -171,7 +190,7 @@ const (
 func getAttributeType(attributeType string) (string, error) {
 	switch attributeType {
 	case common.FieldTypeSingleChar, common.FieldTypeLongChar, common.FieldTypeEnum, common.FieldTypeDate, common.FieldTypeTime,
-		common.FieldTypeTimeZone, common.FieldTypeUser, common.FieldTypeList:
+		common.FieldTypeTimeZone, common.FieldTypeUser, common.FieldTypeList, common.FieldTypeEnumCascade:
 		return stringType, nil
 	case common.FieldTypeInt, common.FieldTypeFloat, common.FieldTypeOrganization:
 		return numericType, nil
@@ -230,10 +249,52 @@ func (c *DynamicGroupInfoCondition) Validate(validatefunc Validatefunc) error {
 	return nil
 }
 
+// DynamicGroupComposition is the other dynamic groups of the same business and object composed by set operation,
+// so that the common base groups like "prod hosts" can be reused by many derived groups.
+type DynamicGroupComposition struct {
+	// Operator is the set operation of the composed groups, union/intersection/difference.
+	Operator string `json:"operator" bson:"operator"`
+
+	// GroupIDs is the composed dynamic group ids, the order matters for the difference operation.
+	GroupIDs []string `json:"group_ids" bson:"group_ids"`
+}
+
+// Validate validates dynamic group composition format, the composed groups are validated when the group is saved
+// or executed since they may be changed afterwards.
+func (c *DynamicGroupComposition) Validate() error {
+	switch c.Operator {
+	case DynamicGroupCompositionUnion, DynamicGroupCompositionIntersection, DynamicGroupCompositionDifference:
+	default:
+		return fmt.Errorf("not support composition operator, %s", c.Operator)
+	}
+
+	if len(c.GroupIDs) == 0 {
+		return errors.New("empty composition group_ids")
+	}
+
+	if len(c.GroupIDs) > DynamicGroupCompositionMaxGroups {
+		return fmt.Errorf("composition group_ids exceeds max count %d", DynamicGroupCompositionMaxGroups)
+	}
+
+	if len(util.StrArrayUnique(c.GroupIDs)) != len(c.GroupIDs) {
+		return errors.New("duplicate composition group_ids")
+	}
+
+	for _, id := range c.GroupIDs {
+		if len(id) == 0 {
+			return errors.New("empty composition group id")
+		}
+	}
+	return nil
+}
+
 // DynamicGroupInfo is info field in DynamicGroup struct.
 type DynamicGroupInfo struct {
 	// Condition is dynamic group index conditions set.
 	Condition []DynamicGroupInfoCondition `json:"condition" bson:"condition"`
+
+	// Composition is the composed dynamic groups, the members of the composition are filtered by the conditions.
+	Composition *DynamicGroupComposition `json:"composition,omitempty" bson:"composition,omitempty"`
 }
 
 // Validate validates dynamic group info format, it's OK if conditions empty in this level.
@@ -243,6 +304,12 @@ func (c *DynamicGroupInfo) Validate(objectID string, validatefunc Validatefunc)
 		return fmt.Errorf("not support dynamic group type, %s", objectID)
 	}
 
+	if c.Composition != nil {
+		if err := c.Composition.Validate(); err != nil {
+			return err
+		}
+	}
+
 	for _, cond := range c.Condition {
 		if _, isSupport = types[cond.ObjID]; !isSupport {
 			return fmt.Errorf("not support condition type[%s] for %s dynamic group", cond.ObjID, objectID)
@@ -255,6 +322,55 @@ func (c *DynamicGroupInfo) Validate(objectID string, validatefunc Validatefunc)
 	return nil
 }
 
+// Hash returns the canonical hash of the dynamic group info, the infos that differ only in the order of the
+// conditions, the order of the $in/$nin values or the time zone of the time conditions have the same hash, so that
+// the identical saved queries can be found.
+func (c *DynamicGroupInfo) Hash() (string, error) {
+	rules := make([]querybuilder.Rule, 0)
+	for _, cond := range c.Condition {
+		for _, item := range cond.Condition {
+			rules = append(rules, querybuilder.AtomRule{
+				Field:    cond.ObjID + "." + item.Field,
+				Operator: querybuilder.Operator(item.Operator),
+				Value:    item.Value,
+			})
+		}
+
+		if cond.TimeCondition == nil {
+			continue
+		}
+
+		for _, item := range cond.TimeCondition.Rules {
+			timeRange := make(map[string]interface{})
+			if item.Start != nil {
+				timeRange["start"] = item.Start.UTC()
+			}
+			if item.End != nil {
+				timeRange["end"] = item.End.UTC()
+			}
+			rules = append(rules, querybuilder.AtomRule{
+				Field:    cond.ObjID + "." + item.Field,
+				Operator: querybuilder.Operator(cond.TimeCondition.Operator),
+				Value:    timeRange,
+			})
+		}
+	}
+
+	if c.Composition != nil {
+		field := "composition." + c.Composition.Operator
+		groupIDs := c.Composition.GroupIDs
+		// the first group of the difference is the one that the others are subtracted from, the others are a set.
+		if c.Composition.Operator == DynamicGroupCompositionDifference && len(groupIDs) > 0 {
+			field += "." + groupIDs[0]
+			groupIDs = groupIDs[1:]
+		}
+
+		rules = append(rules, querybuilder.AtomRule{Field: field, Operator: querybuilder.OperatorIn, Value: groupIDs})
+	}
+
+	return querybuilder.RuleHash(querybuilder.CombinedRule{Condition: querybuilder.ConditionAnd, Rules: rules})
+}
+
 // DynamicGroup is dynamic grouping of conditions for host/set data searching.
 type DynamicGroup struct {
 	// AppID is application id which dynamic group belongs to.
@@ -301,8 +417,8 @@ func (g *DynamicGroup) Validate(validatefunc Validatefunc) error {
 	}
 
 	// check conditions format.
-	if len(g.Info.Condition) == 0 {
-		// it's not OK if conditions empty in this level.
+	if len(g.Info.Condition) == 0 && g.Info.Composition == nil {
+		// it's not OK if conditions empty in this level, unless the group is composed by other groups.
 		return errors.New("empty info.condition")
 	}
 	return g.Info.Validate(g.ObjID, validatefunc)
@@ -0,0 +1,195 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+const (
+	// ExternalIDMaxPerInst the max number of the sources an instance can be bound to at once.
+	ExternalIDMaxPerInst = 20
+	// ExternalIDMaxLength the max length of an external id.
+	ExternalIDMaxLength = 256
+	// ExternalIDSearchMaxLimit the max number of the instances or external ids to search at once.
+	ExternalIDSearchMaxLimit = 500
+	// BKExternalIDField the column of the external id in the import excel, the source of which is specified by the
+	// import option.
+	BKExternalIDField = "bk_external_id"
+)
+
+// externalIDSourceRegexp the source system name is used as the namespace of the external ids, e.g. "cmdb_v2".
+var externalIDSourceRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-.]{1,64}$`)
+
+// ExternalID is the id of a host or instance in an external source system, an external id is unique in its source,
+// and an instance has at most one external id of each source.
+type ExternalID struct {
+	ObjID           string    `json:"bk_obj_id" bson:"bk_obj_id"`
+	InstID          int64     `json:"bk_inst_id" bson:"bk_inst_id"`
+	Source          string    `json:"source" bson:"source"`
+	ID              string    `json:"external_id" bson:"external_id"`
+	SupplierAccount string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	Creator         string    `json:"creator" bson:"creator"`
+	CreateTime      time.Time `json:"create_time" bson:"create_time"`
+	LastTime        time.Time `json:"last_time" bson:"last_time"`
+}
+
+// ExternalIDRef refers to an instance by its id in the source system.
+type ExternalIDRef struct {
+	Source string `json:"source"`
+	ID     string `json:"external_id"`
+}
+
+// Validate validate the source and the id of the external id
+func (r ExternalIDRef) Validate() errors.RawErrorInfo {
+	if !externalIDSourceRegexp.MatchString(r.Source) {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"source"}}
+	}
+
+	if len(r.ID) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"external_id"}}
+	}
+
+	if utf8.RuneCountInString(r.ID) > ExternalIDMaxLength {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"external_id", ExternalIDMaxLength},
+		}
+	}
+	return errors.RawErrorInfo{}
+}
+
+// String returns the external id in the form of "source:id"
+func (r ExternalIDRef) String() string {
+	return fmt.Sprintf("%s:%s", r.Source, r.ID)
+}
+
+// BindExternalIDOption is the option to bind the external ids to an instance, the existing external id of the same
+// source is replaced.
+type BindExternalIDOption struct {
+	InstID      int64           `json:"bk_inst_id"`
+	ExternalIDs []ExternalIDRef `json:"external_ids"`
+}
+
+// Validate validate the bind external id option
+func (o *BindExternalIDOption) Validate() errors.RawErrorInfo {
+	if o.InstID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKInstIDField}}
+	}
+
+	if len(o.ExternalIDs) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"external_ids"}}
+	}
+
+	if len(o.ExternalIDs) > ExternalIDMaxPerInst {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"external_ids", ExternalIDMaxPerInst},
+		}
+	}
+
+	sources := make(map[string]struct{})
+	for _, ref := range o.ExternalIDs {
+		if rawErr := ref.Validate(); rawErr.ErrCode != 0 {
+			return rawErr
+		}
+
+		if _, exists := sources[ref.Source]; exists {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommDuplicateItem, Args: []interface{}{ref.Source}}
+		}
+		sources[ref.Source] = struct{}{}
+	}
+	return errors.RawErrorInfo{}
+}
+
+// UnbindExternalIDOption is the option to unbind the external ids of the sources from an instance.
+type UnbindExternalIDOption struct {
+	InstID  int64    `json:"bk_inst_id"`
+	Sources []string `json:"sources"`
+}
+
+// Validate validate the unbind external id option
+func (o *UnbindExternalIDOption) Validate() errors.RawErrorInfo {
+	if o.InstID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKInstIDField}}
+	}
+
+	if len(o.Sources) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"sources"}}
+	}
+
+	if len(o.Sources) > ExternalIDMaxPerInst {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"sources", ExternalIDMaxPerInst},
+		}
+	}
+
+	for _, source := range o.Sources {
+		if !externalIDSourceRegexp.MatchString(source) {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"sources"}}
+		}
+	}
+	return errors.RawErrorInfo{}
+}
+
+// SearchExternalIDOption is the option to search the external ids, by the instances, or by the external ids of a
+// source to look up the instances they refer to.
+type SearchExternalIDOption struct {
+	InstIDs     []int64  `json:"bk_inst_ids"`
+	Source      string   `json:"source"`
+	ExternalIDs []string `json:"external_ids"`
+}
+
+// Validate validate the search external id option
+func (o *SearchExternalIDOption) Validate() errors.RawErrorInfo {
+	if len(o.InstIDs) == 0 && len(o.ExternalIDs) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"bk_inst_ids"}}
+	}
+
+	if len(o.InstIDs) > ExternalIDSearchMaxLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"bk_inst_ids", ExternalIDSearchMaxLimit},
+		}
+	}
+
+	if len(o.ExternalIDs) > ExternalIDSearchMaxLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"external_ids", ExternalIDSearchMaxLimit},
+		}
+	}
+
+	// the external ids are unique only in their source, so the source is required to look up by them
+	if len(o.ExternalIDs) > 0 && len(o.Source) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"source"}}
+	}
+
+	if len(o.Source) > 0 && !externalIDSourceRegexp.MatchString(o.Source) {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"source"}}
+	}
+	return errors.RawErrorInfo{}
+}
+
+// ExternalIDsResult is the response of searching the external ids.
+type ExternalIDsResult struct {
+	BaseResp `json:",inline"`
+	Data     []ExternalID `json:"data"`
+}
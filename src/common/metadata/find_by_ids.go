@@ -0,0 +1,109 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/util"
+)
+
+// FindByIDsMaxLimit the max number of the ids to be found by the bulk read api at a time.
+const FindByIDsMaxLimit = 500
+
+// FindByIDsOption is the option to find the hosts or instances by an id list, so that the sync tools need not to
+// search with the $in condition and diff the result locally.
+type FindByIDsOption struct {
+	IDs    []int64  `json:"ids"`
+	Fields []string `json:"fields"`
+}
+
+// Validate validate the find by ids option, the duplicate ids are removed with the request order kept.
+func (o *FindByIDsOption) Validate() errors.RawErrorInfo {
+	if len(o.IDs) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"ids"}}
+	}
+
+	if len(o.IDs) > FindByIDsMaxLimit {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommXXExceedLimit, Args: []interface{}{"ids", FindByIDsMaxLimit}}
+	}
+
+	ids := make([]int64, 0, len(o.IDs))
+	idMap := make(map[int64]struct{}, len(o.IDs))
+	for _, id := range o.IDs {
+		if id <= 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"ids"}}
+		}
+		if _, exists := idMap[id]; exists {
+			continue
+		}
+		idMap[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	o.IDs = ids
+
+	return errors.RawErrorInfo{}
+}
+
+// FindByIDsResult is the result of finding the hosts or instances by an id list.
+type FindByIDsResult struct {
+	// Info the found data in the order of the requested ids.
+	Info []mapstr.MapStr `json:"info"`
+	// NotFound the requested ids whose data do not exist.
+	NotFound []int64 `json:"not_found"`
+	// NoPermission the requested ids whose data exist but the user is not authorized to view.
+	NoPermission []int64 `json:"no_permission"`
+}
+
+// NewFindByIDsResult arranges the found data in the order of the requested ids, the data that the user is not
+// authorized to view are dropped and reported in the no permission ids.
+func NewFindByIDsResult(ids []int64, idField string, data []mapstr.MapStr, noPermission []int64) (*FindByIDsResult,
+	error) {
+
+	dataMap := make(map[int64]mapstr.MapStr, len(data))
+	for _, item := range data {
+		id, err := util.GetInt64ByInterface(item[idField])
+		if err != nil {
+			return nil, fmt.Errorf("parse %s from data %v failed, err: %v", idField, item, err)
+		}
+		dataMap[id] = item
+	}
+
+	noPermissionMap := make(map[int64]struct{}, len(noPermission))
+	for _, id := range noPermission {
+		noPermissionMap[id] = struct{}{}
+	}
+
+	result := &FindByIDsResult{
+		Info:         make([]mapstr.MapStr, 0, len(data)),
+		NotFound:     make([]int64, 0),
+		NoPermission: make([]int64, 0),
+	}
+	for _, id := range ids {
+		item, exists := dataMap[id]
+		if !exists {
+			result.NotFound = append(result.NotFound, id)
+			continue
+		}
+		if _, denied := noPermissionMap[id]; denied {
+			result.NoPermission = append(result.NoPermission, id)
+			continue
+		}
+		result.Info = append(result.Info, item)
+	}
+
+	return result, nil
+}
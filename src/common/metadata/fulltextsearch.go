@@ -14,8 +14,11 @@ package metadata
 
 import (
 	"fmt"
+	"regexp"
+	"time"
 
 	"configcenter/src/common"
+	"configcenter/src/common/errors"
 	ccjson "configcenter/src/common/json"
 )
 
@@ -168,3 +171,121 @@ func (idx *ESIndex) Metadata() string {
 	}
 	return meta
 }
+
+// FullTextIndexNames all the bk-cmdb elastic index names, which are the alias names of the real indexes.
+var FullTextIndexNames = []string{IndexNameBizSet, IndexNameBiz, IndexNameSet, IndexNameModule, IndexNameHost,
+	IndexNameModel, IndexNameObjectInstance}
+
+// fulltext reindex task status.
+const (
+	// FullTextReindexStatusRunning the reindex task is running.
+	FullTextReindexStatusRunning = "running"
+
+	// FullTextReindexStatusSucceed the new index is built and the alias is switched to it.
+	FullTextReindexStatusSucceed = "succeed"
+
+	// FullTextReindexStatusFailed the reindex task failed, the alias is not switched.
+	FullTextReindexStatusFailed = "failed"
+
+	// FullTextReindexStatusCanceled the reindex task is canceled, the alias is not switched.
+	FullTextReindexStatusCanceled = "canceled"
+)
+
+// fulltext reindex task stages, in the order of execution.
+const (
+	// FullTextReindexStageSnapshot copy all the documents of the source index to the new index.
+	FullTextReindexStageSnapshot = "snapshot"
+
+	// FullTextReindexStageCatchUp replay the documents changed by the sync stream during the snapshot.
+	FullTextReindexStageCatchUp = "catch_up"
+
+	// FullTextReindexStageVerify compare the document counts of the source index and the new index.
+	FullTextReindexStageVerify = "verify"
+
+	// FullTextReindexStageSwitch atomically switch the alias from the source index to the new index.
+	FullTextReindexStageSwitch = "switch"
+
+	// FullTextReindexStageFinish replay the documents written to the source index just before the switch.
+	FullTextReindexStageFinish = "finish"
+)
+
+// fullTextIndexVersionRegexp the version of the new index, which is the postfix of the real index name.
+var fullTextIndexVersionRegexp = regexp.MustCompile(`^[a-z0-9_\-]{1,32}$`)
+
+// FullTextReindexOption is the option to rebuild an elastic index without downtime.
+type FullTextReindexOption struct {
+	// Index the bk-cmdb index name, which is the alias of the index to be rebuilt.
+	Index string `json:"index"`
+
+	// Version the version of the new index, the new index is named as {index}_{version}.
+	// NOTE: set the same version to the monstache plugin, otherwise it would alias the old index again on restart.
+	Version string `json:"version"`
+
+	// Metadata the settings and mappings of the new index, copy the ones of the source index if not set.
+	Metadata *ESIndexMetadata `json:"metadata"`
+}
+
+// Validate validate the fulltext reindex option.
+func (o *FullTextReindexOption) Validate() errors.RawErrorInfo {
+	if len(o.Index) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"index"}}
+	}
+
+	isValidIndex := false
+	for _, name := range FullTextIndexNames {
+		if o.Index == name {
+			isValidIndex = true
+			break
+		}
+	}
+	if !isValidIndex {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"index"}}
+	}
+
+	if !fullTextIndexVersionRegexp.MatchString(o.Version) {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"version"}}
+	}
+
+	if o.Metadata != nil && (len(o.Metadata.Settings.Shards) == 0 || len(o.Metadata.Settings.Replicas) == 0 ||
+		len(o.Metadata.Mappings.Properties) == 0) {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"metadata"}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// FullTextReindexTask is the progress of a fulltext reindex task.
+type FullTextReindexTask struct {
+	// TaskID the id of the reindex task, which is the name of the new index.
+	TaskID string `json:"task_id"`
+
+	// Index the bk-cmdb index name, which is the alias to be switched.
+	Index string `json:"index"`
+
+	// SourceIndex the real index that the alias points to before the reindex.
+	SourceIndex string `json:"source_index"`
+
+	// TargetIndex the new real index.
+	TargetIndex string `json:"target_index"`
+
+	Status string `json:"status"`
+	Stage  string `json:"stage"`
+
+	// Total the number of the documents to be copied in the current stage.
+	Total int64 `json:"total"`
+
+	// Copied the number of the documents copied in the current stage.
+	Copied int64 `json:"copied"`
+
+	// CatchUpRounds the number of the catch up rounds executed until the document counts are the same.
+	CatchUpRounds int `json:"catch_up_rounds"`
+
+	// SourceCount and TargetCount are the document counts of the two indexes compared in the verify stage.
+	SourceCount int64 `json:"source_count"`
+	TargetCount int64 `json:"target_count"`
+
+	// Message the reason of the failure.
+	Message    string    `json:"message"`
+	CreateTime time.Time `json:"create_time"`
+	LastTime   time.Time `json:"last_time"`
+}
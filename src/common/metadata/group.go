@@ -33,6 +33,8 @@ const (
 	GroupFieldIsDefault = "bk_isdefault"
 	// GroupFieldIsPre TODO
 	GroupFieldIsPre = "ispre"
+	// GroupFieldDisplayRules the conditional visibility rules of the group
+	GroupFieldDisplayRules = "display_rules"
 )
 
 // PropertyGroupObjectAtt uset to update or delete the property group object attribute
@@ -60,6 +62,8 @@ type Group struct {
 	IsDefault  bool   `field:"bk_isdefault" json:"bk_isdefault" bson:"bk_isdefault"`
 	IsPre      bool   `field:"ispre" json:"ispre" bson:"ispre"`
 	IsCollapse bool   `field:"is_collapse" json:"is_collapse" bson:"is_collapse"`
+	// DisplayRules the conditional visibility rules of the group, the group is always shown if not set.
+	DisplayRules GroupDisplayRules `field:"display_rules" json:"display_rules,omitempty" bson:"display_rules,omitempty"`
 }
 
 // Parse load the data from mapstr group into group instance
@@ -0,0 +1,101 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"reflect"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// the operators of the attribute group display rule, which compare the attribute's value with the rule's value.
+const (
+	// GroupDisplayRuleEqual the attribute's value equals to the rule's value.
+	GroupDisplayRuleEqual = "equal"
+	// GroupDisplayRuleNotEqual the attribute's value does not equal to the rule's value.
+	GroupDisplayRuleNotEqual = "not_equal"
+	// GroupDisplayRuleIn the attribute's value is one of the rule's values.
+	GroupDisplayRuleIn = "in"
+	// GroupDisplayRuleNotIn the attribute's value is none of the rule's values.
+	GroupDisplayRuleNotIn = "not_in"
+)
+
+// GroupDisplayRuleMaxCount the max number of the display rules of an attribute group.
+const GroupDisplayRuleMaxCount = 10
+
+// GroupDisplayRule is a conditional visibility rule of the attribute group, e.g. show the "virtual machine" group
+// when the host's "host type" is "vm". a group with display rules is shown only when all its rules are matched by
+// the instance, so that the forms can adapt to the instance without hard-coded frontend logic.
+type GroupDisplayRule struct {
+	// PropertyID the id of the attribute whose value decides the visibility, it must be an attribute of the same
+	// model which does not belong to the group itself.
+	PropertyID string      `json:"bk_property_id" bson:"bk_property_id"`
+	Operator   string      `json:"operator" bson:"operator"`
+	Value      interface{} `json:"value" bson:"value"`
+}
+
+// GroupDisplayRules is the display rules of an attribute group.
+type GroupDisplayRules []GroupDisplayRule
+
+// Validate validate the display rules, the referenced attributes are validated by the caller since they are stored
+// separately from the group.
+func (rules GroupDisplayRules) Validate() errors.RawErrorInfo {
+	if len(rules) > GroupDisplayRuleMaxCount {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{GroupFieldDisplayRules, GroupDisplayRuleMaxCount},
+		}
+	}
+
+	for index, rule := range rules {
+		field := fmt.Sprintf("%s[%d]", GroupFieldDisplayRules, index)
+		if len(rule.PropertyID) == 0 {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsNeedSet,
+				Args:    []interface{}{field + "." + common.BKPropertyIDField},
+			}
+		}
+
+		switch rule.Operator {
+		case GroupDisplayRuleEqual, GroupDisplayRuleNotEqual:
+			if rule.Value == nil {
+				return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{field + ".value"}}
+			}
+		case GroupDisplayRuleIn, GroupDisplayRuleNotIn:
+			value := reflect.ValueOf(rule.Value)
+			if rule.Value == nil || value.Kind() != reflect.Slice || value.Len() == 0 {
+				return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{field + ".value"}}
+			}
+		default:
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{field + ".operator"}}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// PropertyIDs returns the ids of the attributes referenced by the display rules.
+func (rules GroupDisplayRules) PropertyIDs() []string {
+	ids := make([]string, 0, len(rules))
+	exists := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		if _, ok := exists[rule.PropertyID]; ok {
+			continue
+		}
+		exists[rule.PropertyID] = struct{}{}
+		ids = append(ids, rule.PropertyID)
+	}
+	return ids
+}
@@ -0,0 +1,175 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+)
+
+// HostLifecycleState is a state of the host lifecycle.
+type HostLifecycleState struct {
+	ID   string `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+}
+
+// HostLifecycleTransition is an allowed change of the host lifecycle state.
+type HostLifecycleTransition struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+	// RequiredAttributes the host attributes that must have values when the host changes to the target state,
+	// e.g. the operator must be set when the host is in service.
+	RequiredAttributes []string `json:"required_attributes" yaml:"required_attributes"`
+}
+
+// HostLifecycleConfig is the state machine of the host lifecycle state field bk_lifecycle_state, the state changes
+// are emitted as the host update events whose bk_changed_fields contains bk_lifecycle_state.
+type HostLifecycleConfig struct {
+	// States the states of the host lifecycle, the first one is the initial state of the created hosts.
+	States      []HostLifecycleState      `json:"states" yaml:"states"`
+	Transitions []HostLifecycleTransition `json:"transitions" yaml:"transitions"`
+}
+
+// DefaultHostLifecycleConfig is the host lifecycle state machine used when it is not configured.
+func DefaultHostLifecycleConfig() *HostLifecycleConfig {
+	return &HostLifecycleConfig{
+		States: []HostLifecycleState{
+			{ID: "planned", Name: "规划中"},
+			{ID: "in_service", Name: "服务中"},
+			{ID: "maintenance", Name: "维护中"},
+			{ID: "retiring", Name: "退役中"},
+			{ID: "retired", Name: "已退役"},
+		},
+		Transitions: []HostLifecycleTransition{
+			{From: "planned", To: "in_service", RequiredAttributes: []string{common.BKOperatorField}},
+			{From: "planned", To: "retired"},
+			{From: "in_service", To: "maintenance"},
+			{From: "maintenance", To: "in_service"},
+			{From: "in_service", To: "retiring"},
+			{From: "maintenance", To: "retiring"},
+			{From: "retiring", To: "retired"},
+		},
+	}
+}
+
+// Validate validate the host lifecycle config.
+func (c *HostLifecycleConfig) Validate() error {
+	if len(c.States) == 0 {
+		return fmt.Errorf("host lifecycle states are not set")
+	}
+
+	states := make(map[string]struct{}, len(c.States))
+	for _, state := range c.States {
+		if len(state.ID) == 0 {
+			return fmt.Errorf("host lifecycle state id is not set")
+		}
+
+		if _, exists := states[state.ID]; exists {
+			return fmt.Errorf("host lifecycle state %s is duplicated", state.ID)
+		}
+		states[state.ID] = struct{}{}
+	}
+
+	transitions := make(map[string]struct{}, len(c.Transitions))
+	for _, transition := range c.Transitions {
+		if _, exists := states[transition.From]; !exists {
+			return fmt.Errorf("host lifecycle transition from state %s is not defined", transition.From)
+		}
+
+		if _, exists := states[transition.To]; !exists {
+			return fmt.Errorf("host lifecycle transition to state %s is not defined", transition.To)
+		}
+
+		if transition.From == transition.To {
+			return fmt.Errorf("host lifecycle transition from state %s to itself is not needed", transition.From)
+		}
+
+		key := transition.From + "->" + transition.To
+		if _, exists := transitions[key]; exists {
+			return fmt.Errorf("host lifecycle transition %s is duplicated", key)
+		}
+		transitions[key] = struct{}{}
+	}
+
+	return nil
+}
+
+// InitialState returns the initial state of the created hosts.
+func (c *HostLifecycleConfig) InitialState() string {
+	return c.States[0].ID
+}
+
+// ValidateState checks if the state is defined in the host lifecycle.
+func (c *HostLifecycleConfig) ValidateState(state string) errors.RawErrorInfo {
+	for _, s := range c.States {
+		if s.ID == state {
+			return errors.RawErrorInfo{}
+		}
+	}
+
+	return errors.RawErrorInfo{
+		ErrCode: common.CCErrCommParamsInvalid,
+		Args:    []interface{}{common.BKHostLifecycleStateField},
+	}
+}
+
+// ValidateTransition checks if the host is allowed to change from the state to the other, the host data is the
+// host's attributes after the change, which must contain the transition's required attributes.
+func (c *HostLifecycleConfig) ValidateTransition(from, to string, host mapstr.MapStr) errors.RawErrorInfo {
+	if rawErr := c.ValidateState(to); rawErr.ErrCode != 0 {
+		return rawErr
+	}
+
+	// the hosts created before the lifecycle is introduced have no state, they can be set to any state
+	if from == to || len(from) == 0 {
+		return errors.RawErrorInfo{}
+	}
+
+	for _, transition := range c.Transitions {
+		if transition.From != from || transition.To != to {
+			continue
+		}
+
+		for _, attr := range transition.RequiredAttributes {
+			if isHostLifecycleAttrEmpty(host[attr]) {
+				return errors.RawErrorInfo{
+					ErrCode: common.CCErrCoreServiceHostLifecycleRequiredAttr,
+					Args:    []interface{}{to, attr},
+				}
+			}
+		}
+		return errors.RawErrorInfo{}
+	}
+
+	return errors.RawErrorInfo{
+		ErrCode: common.CCErrCoreServiceHostLifecycleTransitionNotAllowed,
+		Args:    []interface{}{from, to},
+	}
+}
+
+func isHostLifecycleAttrEmpty(value interface{}) bool {
+	switch val := value.(type) {
+	case nil:
+		return true
+	case string:
+		return len(strings.TrimSpace(val)) == 0
+	case []interface{}:
+		return len(val) == 0
+	default:
+		return false
+	}
+}
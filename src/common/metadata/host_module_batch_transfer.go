@@ -0,0 +1,134 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+const (
+	// HostModuleBatchTransferMaxHosts the max number of the hosts transferred by the batch transfer at a time.
+	HostModuleBatchTransferMaxHosts = 2000
+	// HostModuleBatchTransferDefaultChunkSize the default number of the hosts transferred in one transaction.
+	HostModuleBatchTransferDefaultChunkSize = 100
+	// HostModuleBatchTransferMaxChunkSize the max number of the hosts transferred in one transaction, the larger
+	// transaction holds the locks longer and is more likely to be aborted.
+	HostModuleBatchTransferMaxChunkSize = 500
+)
+
+// HostModuleBatchTransferOption is the option to transfer the hosts in a business to different modules in one
+// call, each host has its own target modules and transfer policy.
+type HostModuleBatchTransferOption struct {
+	BizID     int64                `json:"bk_biz_id"`
+	Transfers []HostModuleTransfer `json:"transfers"`
+	// ChunkSize the number of the hosts transferred in one transaction, the hosts in the failed chunk are not
+	// transferred, while the other chunks are not affected.
+	ChunkSize int `json:"chunk_size"`
+}
+
+// HostModuleTransfer is the target modules and the transfer policy of a host.
+type HostModuleTransfer struct {
+	HostID    int64   `json:"bk_host_id"`
+	ModuleIDs []int64 `json:"bk_module_ids"`
+	// IsIncrement add the host to the target modules and keep its current modules, otherwise the host is removed
+	// from its current modules.
+	IsIncrement bool `json:"is_increment"`
+	// DisableAutoCreateSvcInst disable auto create service instance when transfer to a module with process in
+	// template.
+	DisableAutoCreateSvcInst bool `json:"disable_auto_create"`
+}
+
+// Validate validate the host module batch transfer option, and set the default chunk size if it is not set.
+func (o *HostModuleBatchTransferOption) Validate() errors.RawErrorInfo {
+	if o.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if len(o.Transfers) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"transfers"}}
+	}
+
+	if len(o.Transfers) > HostModuleBatchTransferMaxHosts {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"transfers", HostModuleBatchTransferMaxHosts},
+		}
+	}
+
+	if o.ChunkSize == 0 {
+		o.ChunkSize = HostModuleBatchTransferDefaultChunkSize
+	}
+
+	if o.ChunkSize < 0 || o.ChunkSize > HostModuleBatchTransferMaxChunkSize {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"chunk_size", HostModuleBatchTransferMaxChunkSize},
+		}
+	}
+
+	hostIDMap := make(map[int64]struct{}, len(o.Transfers))
+	for idx, transfer := range o.Transfers {
+		if transfer.HostID <= 0 {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{fmt.Sprintf("transfers[%d].%s", idx, common.BKHostIDField)},
+			}
+		}
+
+		// a host can only be transferred once, otherwise the result depends on the order of the chunks.
+		if _, exists := hostIDMap[transfer.HostID]; exists {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommDuplicateItem,
+				Args:    []interface{}{fmt.Sprintf("transfers[%d].%s", idx, common.BKHostIDField)},
+			}
+		}
+		hostIDMap[transfer.HostID] = struct{}{}
+
+		if len(transfer.ModuleIDs) == 0 {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsNeedSet,
+				Args:    []interface{}{fmt.Sprintf("transfers[%d].bk_module_ids", idx)},
+			}
+		}
+
+		for _, moduleID := range transfer.ModuleIDs {
+			if moduleID <= 0 {
+				return errors.RawErrorInfo{
+					ErrCode: common.CCErrCommParamsInvalid,
+					Args:    []interface{}{fmt.Sprintf("transfers[%d].bk_module_ids", idx)},
+				}
+			}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// HostModuleTransferResult is the transfer result of a host.
+type HostModuleTransferResult struct {
+	HostID  int64  `json:"bk_host_id"`
+	Success bool   `json:"success"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// HostModuleBatchTransferResult is the result of the host module batch transfer, the results are in the order of
+// the transfers.
+type HostModuleBatchTransferResult struct {
+	SuccessCount int                        `json:"success_count"`
+	FailedCount  int                        `json:"failed_count"`
+	Results      []HostModuleTransferResult `json:"results"`
+}
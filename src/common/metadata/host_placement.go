@@ -0,0 +1,102 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// the fault domain levels of the host placement, from the largest to the smallest.
+const (
+	// HostPlacementRegion the region which the host resides in.
+	HostPlacementRegion = "region"
+	// HostPlacementZone the availability zone which the host resides in.
+	HostPlacementZone = "zone"
+	// HostPlacementRack the rack which the host resides in.
+	HostPlacementRack = "rack"
+)
+
+// hostPlacementFields the host fields of the fault domain levels.
+var hostPlacementFields = map[string]string{
+	HostPlacementRegion: common.BKHostCloudRegionField,
+	HostPlacementZone:   common.BKHostCloudZoneField,
+	HostPlacementRack:   common.BKHostRackField,
+}
+
+// GetHostPlacementField get the host field of the fault domain level.
+func GetHostPlacementField(level string) (string, bool) {
+	field, exists := hostPlacementFields[level]
+	return field, exists
+}
+
+// SingleDomainModuleDefaultMinHostCount the default min host count of the modules to be checked, a module with only
+// one host resides in a single fault domain inevitably.
+const SingleDomainModuleDefaultMinHostCount = 2
+
+// FindSingleDomainModuleOption is the option to find the modules whose hosts all reside in a single fault domain,
+// which are at risk when the fault domain fails.
+type FindSingleDomainModuleOption struct {
+	BizID int64 `json:"bk_biz_id"`
+	// Level the fault domain level, region/zone/rack, default is zone.
+	Level string `json:"level"`
+	// MinHostCount only the modules which have at least this number of hosts are checked.
+	MinHostCount int      `json:"min_host_count"`
+	Page         BasePage `json:"page"`
+}
+
+// Validate validate the find single domain module option, and set the default level and min host count.
+func (o *FindSingleDomainModuleOption) Validate() errors.RawErrorInfo {
+	if o.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if o.Level == "" {
+		o.Level = HostPlacementZone
+	}
+
+	if _, exists := GetHostPlacementField(o.Level); !exists {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"level"}}
+	}
+
+	if o.MinHostCount == 0 {
+		o.MinHostCount = SingleDomainModuleDefaultMinHostCount
+	}
+
+	if o.MinHostCount < 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"min_host_count"}}
+	}
+
+	if err := o.Page.ValidateLimit(common.BKMaxPageSize); err != nil {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"page.limit"}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// SingleDomainModule is a module whose hosts all reside in a single fault domain.
+type SingleDomainModule struct {
+	ModuleID   int64  `json:"bk_module_id"`
+	ModuleName string `json:"bk_module_name"`
+	SetID      int64  `json:"bk_set_id"`
+	HostCount  int    `json:"host_count"`
+	// Domain the fault domain which all the hosts of the module reside in.
+	Domain string `json:"domain"`
+}
+
+// FindSingleDomainModuleResult is the result of the single domain modules, sorted by the module id. the modules
+// which have hosts without the placement of the level are not included since their fault domains are unknown.
+type FindSingleDomainModuleResult struct {
+	Count int                  `json:"count"`
+	Info  []SingleDomainModule `json:"info"`
+}
@@ -0,0 +1,128 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// the methods of the host connectivity probe.
+const (
+	// HostProbeMethodPing probes the host by icmp ping, which is done by the external prober.
+	HostProbeMethodPing = "ping"
+	// HostProbeMethodPort probes the host by connecting to the tcp ports, which is done by the external prober.
+	HostProbeMethodPort = "port"
+	// HostProbeMethodAgent probes the host by the gse agent status.
+	HostProbeMethodAgent = "agent"
+)
+
+// the host probe status, which is saved as the host's bk_probe_status field.
+const (
+	// HostProbeReachable the host is reachable by the probe.
+	HostProbeReachable = "reachable"
+	// HostProbeUnreachable the host is not reachable by the probe.
+	HostProbeUnreachable = "unreachable"
+	// HostProbeUnknown the prober can not tell if the host is reachable, e.g. the host has no inner ip.
+	HostProbeUnknown = "unknown"
+)
+
+const (
+	// HostProbeMaxHosts the max number of the hosts probed in one request.
+	HostProbeMaxHosts = 200
+	// HostProbeMaxPorts the max number of the ports probed for each host.
+	HostProbeMaxPorts = 10
+)
+
+// HostProbeOption is the option to probe the connectivity of the hosts in the business, the latest probe results are
+// saved as the host status fields.
+type HostProbeOption struct {
+	HostIDs []int64 `json:"bk_host_ids"`
+	// Method the probe method, ping/port/agent.
+	Method string `json:"method"`
+	// Ports the tcp ports to be connected, only used by the port method.
+	Ports []int `json:"ports"`
+}
+
+// Validate validate the host probe option.
+func (o *HostProbeOption) Validate() errors.RawErrorInfo {
+	if len(o.HostIDs) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"bk_host_ids"}}
+	}
+
+	if len(o.HostIDs) > HostProbeMaxHosts {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"bk_host_ids", HostProbeMaxHosts},
+		}
+	}
+
+	switch o.Method {
+	case HostProbeMethodPing, HostProbeMethodAgent:
+		if len(o.Ports) != 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"ports"}}
+		}
+
+	case HostProbeMethodPort:
+		if len(o.Ports) == 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"ports"}}
+		}
+
+		if len(o.Ports) > HostProbeMaxPorts {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommXXExceedLimit,
+				Args:    []interface{}{"ports", HostProbeMaxPorts},
+			}
+		}
+
+		for _, port := range o.Ports {
+			if port <= 0 || port > 65535 {
+				return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"ports"}}
+			}
+		}
+
+	default:
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"method"}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// HostProbeTarget is a host to be probed.
+type HostProbeTarget struct {
+	HostID  int64  `json:"bk_host_id"`
+	CloudID int64  `json:"bk_cloud_id"`
+	InnerIP string `json:"bk_host_innerip"`
+}
+
+// HostProbeRequest is the request sent to the prober.
+type HostProbeRequest struct {
+	Method  string            `json:"method"`
+	Ports   []int             `json:"ports,omitempty"`
+	Targets []HostProbeTarget `json:"targets"`
+}
+
+// HostProbeResult is the probe result of a host.
+type HostProbeResult struct {
+	HostID int64 `json:"bk_host_id"`
+	// Status the probe status, reachable/unreachable/unknown.
+	Status string `json:"status"`
+	// Message the detail of the probe result given by the prober, e.g. the unreachable ports.
+	Message string `json:"message,omitempty"`
+}
+
+// HostProbeResponse is the response of the external prober.
+type HostProbeResponse struct {
+	BaseResp `json:",inline"`
+	Data     []HostProbeResult `json:"data"`
+}
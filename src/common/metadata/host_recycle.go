@@ -0,0 +1,261 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+
+	"github.com/robfig/cron"
+)
+
+// HostRecycleAction is the action taken on the hosts that meet the criteria of a host recycle policy.
+type HostRecycleAction string
+
+const (
+	// HostRecycleActionRecycle move the hosts into the recycle module of the business directly.
+	HostRecycleActionRecycle HostRecycleAction = "recycle"
+	// HostRecycleActionReview raise a review report, the hosts are recycled after the report is approved.
+	HostRecycleActionReview HostRecycleAction = "review"
+)
+
+// HostRecycleReportStatus is the status of a host recycle report.
+type HostRecycleReportStatus string
+
+const (
+	// HostRecycleReportRecycled the hosts in the report have been moved into the recycle module.
+	HostRecycleReportRecycled HostRecycleReportStatus = "recycled"
+	// HostRecycleReportPending the report is waiting for review.
+	HostRecycleReportPending HostRecycleReportStatus = "pending"
+	// HostRecycleReportRejected the report is rejected, the hosts are kept where they are.
+	HostRecycleReportRejected HostRecycleReportStatus = "rejected"
+	// HostRecycleReportFailed the hosts in the report failed to be moved into the recycle module.
+	HostRecycleReportFailed HostRecycleReportStatus = "failed"
+)
+
+const (
+	// HostRecycleMaxHostsPerRun the max number of hosts that a policy run recycles, the rest hosts are recycled by
+	// the next runs, which prevents a wrong policy from emptying the business at once.
+	HostRecycleMaxHostsPerRun = 500
+	// HostRecycleMaxOptOutTags the max number of the opt-out tags of a host recycle policy.
+	HostRecycleMaxOptOutTags = 20
+)
+
+// HostRecycleCriteria is the criteria of the hosts to be recycled, a host is matched only if it meets all the
+// criteria that are set.
+type HostRecycleCriteria struct {
+	// AgentOfflineDays the host is matched if its agent has not reported the snapshot for at least the days.
+	AgentOfflineDays int `json:"agent_offline_days,omitempty" bson:"agent_offline_days"`
+	// BizOutOfService the host is matched if the business is out of service(停运).
+	BizOutOfService bool `json:"biz_out_of_service,omitempty" bson:"biz_out_of_service"`
+	// NoServiceInstance the host is matched if it has no service instance in the business.
+	NoServiceInstance bool `json:"no_service_instance,omitempty" bson:"no_service_instance"`
+}
+
+// IsEmpty check if none of the criteria is set.
+func (c HostRecycleCriteria) IsEmpty() bool {
+	return c.AgentOfflineDays == 0 && !c.BizOutOfService && !c.NoServiceInstance
+}
+
+// HostRecyclePolicy is a policy that recycles the hosts of a business which meet the criteria periodically.
+type HostRecyclePolicy struct {
+	ID       int64               `json:"id" bson:"id"`
+	Name     string              `json:"name" bson:"name"`
+	BizID    int64               `json:"bk_biz_id" bson:"bk_biz_id"`
+	Criteria HostRecycleCriteria `json:"criteria" bson:"criteria"`
+	Action   HostRecycleAction   `json:"action" bson:"action"`
+	// Schedule the standard cron spec of the policy's run time, such as "0 2 * * *".
+	Schedule string `json:"schedule" bson:"schedule"`
+	// OptOutTags the hosts whose comment contains any of the tags are never recycled by the policy.
+	OptOutTags []string `json:"opt_out_tags" bson:"opt_out_tags"`
+	Enabled    bool     `json:"enabled" bson:"enabled"`
+	// LastRunTime the last time that the policy is run by the scheduler.
+	LastRunTime *time.Time `json:"last_run_time,omitempty" bson:"last_run_time"`
+
+	Creator         string    `json:"creator" bson:"creator"`
+	Modifier        string    `json:"modifier" bson:"modifier"`
+	CreateTime      time.Time `json:"create_time" bson:"create_time"`
+	LastTime        time.Time `json:"last_time" bson:"last_time"`
+	SupplierAccount string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+}
+
+// Validate validate the host recycle policy
+func (p *HostRecyclePolicy) Validate() errors.RawErrorInfo {
+	if len(p.Name) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKFieldName}}
+	}
+
+	if p.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if p.Criteria.AgentOfflineDays < 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsInvalid,
+			Args:    []interface{}{"criteria.agent_offline_days"},
+		}
+	}
+
+	if p.Criteria.IsEmpty() {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"criteria"}}
+	}
+
+	switch p.Action {
+	case HostRecycleActionRecycle, HostRecycleActionReview:
+	default:
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"action"}}
+	}
+
+	if _, err := cron.ParseStandard(p.Schedule); err != nil {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"schedule"}}
+	}
+
+	if len(p.OptOutTags) > HostRecycleMaxOptOutTags {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"opt_out_tags", HostRecycleMaxOptOutTags},
+		}
+	}
+
+	for _, tag := range p.OptOutTags {
+		if len(tag) == 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"opt_out_tags"}}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// IsDue check if the policy should be run at the time
+func (p *HostRecyclePolicy) IsDue(now time.Time) bool {
+	if !p.Enabled {
+		return false
+	}
+
+	schedule, err := cron.ParseStandard(p.Schedule)
+	if err != nil {
+		return false
+	}
+
+	last := p.CreateTime
+	if p.LastRunTime != nil {
+		last = *p.LastRunTime
+	}
+
+	return !schedule.Next(last).After(now)
+}
+
+// HostRecycleHost is a host matched by the host recycle policy.
+type HostRecycleHost struct {
+	HostID  int64  `json:"bk_host_id" bson:"bk_host_id"`
+	InnerIP string `json:"bk_host_innerip" bson:"bk_host_innerip"`
+	// AgentOfflineDays the days that the host's agent has been offline.
+	AgentOfflineDays int `json:"agent_offline_days" bson:"agent_offline_days"`
+}
+
+// HostRecycleReport is the report of a host recycle policy's run.
+type HostRecycleReport struct {
+	ID         int64                   `json:"id" bson:"id"`
+	BizID      int64                   `json:"bk_biz_id" bson:"bk_biz_id"`
+	PolicyID   int64                   `json:"policy_id" bson:"policy_id"`
+	PolicyName string                  `json:"policy_name" bson:"policy_name"`
+	Action     HostRecycleAction       `json:"action" bson:"action"`
+	Status     HostRecycleReportStatus `json:"status" bson:"status"`
+	Hosts      []HostRecycleHost       `json:"hosts" bson:"hosts"`
+	// MatchedCount the number of all the matched hosts, which may be more than the hosts in the report if the
+	// report is truncated by the max hosts per run.
+	MatchedCount int  `json:"matched_count" bson:"matched_count"`
+	Truncated    bool `json:"truncated" bson:"truncated"`
+	// Reason the reason of the failure or the review comment.
+	Reason   string `json:"reason,omitempty" bson:"reason"`
+	Reviewer string `json:"reviewer,omitempty" bson:"reviewer"`
+
+	Creator         string    `json:"creator" bson:"creator"`
+	Modifier        string    `json:"modifier" bson:"modifier"`
+	CreateTime      time.Time `json:"create_time" bson:"create_time"`
+	LastTime        time.Time `json:"last_time" bson:"last_time"`
+	SupplierAccount string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+}
+
+// HostIDs returns the ids of the hosts in the report.
+func (r *HostRecycleReport) HostIDs() []int64 {
+	hostIDs := make([]int64, len(r.Hosts))
+	for index, host := range r.Hosts {
+		hostIDs[index] = host.HostID
+	}
+	return hostIDs
+}
+
+// MultipleHostRecyclePolicy is the host recycle policies search result.
+type MultipleHostRecyclePolicy struct {
+	Count int64               `json:"count"`
+	Info  []HostRecyclePolicy `json:"info"`
+}
+
+// HostRecyclePolicyResult is the response of a host recycle policy.
+type HostRecyclePolicyResult struct {
+	BaseResp `json:",inline"`
+	Data     HostRecyclePolicy `json:"data"`
+}
+
+// MultipleHostRecyclePolicyResult is the response of the host recycle policies search result.
+type MultipleHostRecyclePolicyResult struct {
+	BaseResp `json:",inline"`
+	Data     MultipleHostRecyclePolicy `json:"data"`
+}
+
+// MultipleHostRecycleReport is the host recycle reports search result.
+type MultipleHostRecycleReport struct {
+	Count int64               `json:"count"`
+	Info  []HostRecycleReport `json:"info"`
+}
+
+// HostRecycleReportResult is the response of a host recycle report.
+type HostRecycleReportResult struct {
+	BaseResp `json:",inline"`
+	Data     HostRecycleReport `json:"data"`
+}
+
+// MultipleHostRecycleReportResult is the response of the host recycle reports search result.
+type MultipleHostRecycleReportResult struct {
+	BaseResp `json:",inline"`
+	Data     MultipleHostRecycleReport `json:"data"`
+}
+
+// ListHostRecyclePolicyOption is the option to list the host recycle policies of a business.
+type ListHostRecyclePolicyOption struct {
+	Page BasePage `json:"page"`
+}
+
+// ListHostRecycleReportOption is the option to list the host recycle reports of a business.
+type ListHostRecycleReportOption struct {
+	// PolicyID list the reports of the policy, list the reports of all the policies if not set.
+	PolicyID int64 `json:"policy_id"`
+	// Status list the reports of the status, list the reports of all the status if not set.
+	Status HostRecycleReportStatus `json:"status"`
+	Page   BasePage                `json:"page"`
+}
+
+// RunHostRecyclePolicyOption is the option to run a host recycle policy immediately.
+type RunHostRecyclePolicyOption struct {
+	// DryRun only returns the matched hosts, the hosts are not recycled and the report is not saved.
+	DryRun bool `json:"dry_run"`
+}
+
+// ReviewHostRecycleReportOption is the option to review a pending host recycle report.
+type ReviewHostRecycleReportOption struct {
+	Approve bool   `json:"approve"`
+	Reason  string `json:"reason"`
+}
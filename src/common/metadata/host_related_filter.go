@@ -0,0 +1,80 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"configcenter/src/common"
+	"configcenter/src/common/querybuilder"
+)
+
+// the resources related to the hosts that can be used to filter the hosts.
+const (
+	// HostRelatedServiceTemplate filters the hosts in the modules created by the service templates.
+	HostRelatedServiceTemplate = "service_template"
+	// HostRelatedSetTemplate filters the hosts in the sets created by the set templates.
+	HostRelatedSetTemplate = "set_template"
+	// HostRelatedInstance filters the hosts associated to the instances of a model.
+	HostRelatedInstance = "instance"
+)
+
+const (
+	// HostRelatedFilterMaxCount the max number of the related filters in one host query.
+	HostRelatedFilterMaxCount = 5
+	// HostRelatedFilterMaxMatches the max number of the resources matched by each stage of a related filter, the
+	// matched ids are used as the condition of the next stage, so the number is limited to keep the queries cheap.
+	HostRelatedFilterMaxMatches = 10000
+)
+
+// HostRelatedFilter is the condition over a resource related to the hosts, e.g. hosts whose module's service
+// template matches the filter, or hosts associated to the instances of a model matching the filter.
+type HostRelatedFilter struct {
+	// Resource the related resource, service_template/set_template/instance.
+	Resource string `json:"resource"`
+	// ObjectID the model of the associated instances, only used by the instance resource.
+	ObjectID string                    `json:"bk_obj_id"`
+	Filter   *querybuilder.QueryFilter `json:"filter"`
+}
+
+// Validate validates the host related filter.
+func (f HostRelatedFilter) Validate() (string, error) {
+	switch f.Resource {
+	case HostRelatedServiceTemplate, HostRelatedSetTemplate:
+		if len(f.ObjectID) != 0 {
+			return common.BKObjIDField, fmt.Errorf("bk_obj_id can only be set with %s resource", HostRelatedInstance)
+		}
+	case HostRelatedInstance:
+		if len(f.ObjectID) == 0 {
+			return common.BKObjIDField, fmt.Errorf("bk_obj_id is not set")
+		}
+		if f.ObjectID == common.BKInnerObjIDHost {
+			return common.BKObjIDField, fmt.Errorf("host can not be associated to itself")
+		}
+	default:
+		return "resource", fmt.Errorf("unsupported related resource: %s", f.Resource)
+	}
+
+	if f.Filter == nil || f.Filter.Rule == nil {
+		return "filter", fmt.Errorf("filter is not set")
+	}
+
+	if key, err := f.Filter.Validate(&querybuilder.RuleOption{NeedSameSliceElementType: true}); err != nil {
+		return fmt.Sprintf("filter.%s", key), err
+	}
+
+	if f.Filter.GetDeep() > querybuilder.MaxDeep {
+		return "filter.rules", fmt.Errorf("exceed max query condition deepth: %d", querybuilder.MaxDeep)
+	}
+	return "", nil
+}
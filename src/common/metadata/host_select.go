@@ -0,0 +1,120 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/querybuilder"
+)
+
+const (
+	// HostSelectMaxCount the max number of the hosts selected at a time.
+	HostSelectMaxCount = 500
+	// HostSelectMaxCandidates the max number of the hosts matching the filter to select from, the filter should be
+	// narrowed if there are more candidates.
+	HostSelectMaxCandidates = 20000
+)
+
+// HostSelectOption is the option to select hosts randomly from the business with the spread constraints, so that
+// the deployment tools can pick the canary hosts across the failure domains.
+type HostSelectOption struct {
+	// SetIDs and ModuleIDs limit the topology scope of the candidate hosts, the whole business if not set.
+	SetIDs             []int64                   `json:"bk_set_ids"`
+	ModuleIDs          []int64                   `json:"bk_module_ids"`
+	HostPropertyFilter *querybuilder.QueryFilter `json:"host_property_filter"`
+	// Count the number of the hosts to select.
+	Count  int              `json:"count"`
+	Spread HostSelectSpread `json:"spread"`
+	Fields []string         `json:"fields"`
+	// Seed the random seed, the same candidates are selected the same way with the same seed, a random seed is
+	// used if it is not set, which is returned in the result for reproducing the selection.
+	Seed int64 `json:"seed"`
+}
+
+// HostSelectSpread is the spread constraints of the host selection, zero means no limit. a host in multiple modules
+// counts against each of its modules and their sets.
+type HostSelectSpread struct {
+	MaxPerModule    int `json:"max_per_module"`
+	MaxPerSet       int `json:"max_per_set"`
+	MaxPerCloudArea int `json:"max_per_cloud_area"`
+}
+
+// Validate validate the host select option.
+func (o *HostSelectOption) Validate() errors.RawErrorInfo {
+	if o.Count <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"count"}}
+	}
+
+	if o.Count > HostSelectMaxCount {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"count", HostSelectMaxCount},
+		}
+	}
+
+	if o.Spread.MaxPerModule < 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"spread.max_per_module"}}
+	}
+
+	if o.Spread.MaxPerSet < 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"spread.max_per_set"}}
+	}
+
+	if o.Spread.MaxPerCloudArea < 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsInvalid,
+			Args:    []interface{}{"spread.max_per_cloud_area"},
+		}
+	}
+
+	if o.HostPropertyFilter != nil {
+		key, err := o.HostPropertyFilter.Validate(&querybuilder.RuleOption{NeedSameSliceElementType: true})
+		if err != nil {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{fmt.Sprintf("host_property_filter.%s", key)},
+			}
+		}
+
+		if o.HostPropertyFilter.GetDeep() > querybuilder.MaxDeep {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommXXExceedLimit,
+				Args:    []interface{}{"host_property_filter.rules", querybuilder.MaxDeep},
+			}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// HostSelectItem is a selected host with its topology in the selection scope.
+type HostSelectItem struct {
+	Host      mapstr.MapStr `json:"host"`
+	SetIDs    []int64       `json:"bk_set_ids"`
+	ModuleIDs []int64       `json:"bk_module_ids"`
+}
+
+// HostSelectResult is the result of the host selection.
+type HostSelectResult struct {
+	// Satisfied whether the requested count of hosts are selected, it is false if there are not enough candidates
+	// or the spread constraints are too strict.
+	Satisfied bool `json:"satisfied"`
+	// Candidates the number of the hosts matching the filter.
+	Candidates int              `json:"candidates"`
+	Seed       int64            `json:"seed"`
+	Info       []HostSelectItem `json:"info"`
+}
@@ -0,0 +1,115 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+)
+
+// the match sources of the host smart search, in the order of relevance.
+const (
+	// HostSmartSearchExact the host field equals to the query.
+	HostSmartSearchExact = "exact"
+	// HostSmartSearchPrefix the host field begins with the query.
+	HostSmartSearchPrefix = "prefix"
+	// HostSmartSearchFulltext the host field contains the query case-insensitively.
+	HostSmartSearchFulltext = "fulltext"
+)
+
+// the query types of the host smart search, which decides the host fields to be matched.
+const (
+	// HostSmartSearchQueryIP the query is an ip address or a fragment of it.
+	HostSmartSearchQueryIP = "ip"
+	// HostSmartSearchQueryNumber the query is a number, which may be the host id.
+	HostSmartSearchQueryNumber = "number"
+	// HostSmartSearchQueryText the query is a text, e.g. the host name or the asset id.
+	HostSmartSearchQueryText = "text"
+)
+
+const (
+	// HostSmartSearchDefaultLimit the default number of the hosts returned by the host smart search.
+	HostSmartSearchDefaultLimit = 20
+	// HostSmartSearchMaxLimit the max number of the hosts returned by the host smart search.
+	HostSmartSearchMaxLimit = 100
+	// HostSmartSearchMaxQueryLength the max length of the host smart search query.
+	HostSmartSearchMaxQueryLength = 128
+	// HostSmartSearchMinFulltextLength the min length of the query to do the fulltext match, the shorter query
+	// matches too many hosts to be relevant.
+	HostSmartSearchMinFulltextLength = 3
+)
+
+// HostSmartSearchOption is the option of the host smart search, which interprets a single query string instead of
+// the structured filters for the quick lookup of the hosts.
+type HostSmartSearchOption struct {
+	// BizID the business to search in, search all the hosts if not set.
+	BizID int64 `json:"bk_biz_id"`
+	// Query the ip, asset id, host name or a fragment of them.
+	Query  string   `json:"query"`
+	Fields []string `json:"fields"`
+	Limit  int      `json:"limit"`
+}
+
+// Validate validate the host smart search option, and set the default limit if it is not set.
+func (o *HostSmartSearchOption) Validate() errors.RawErrorInfo {
+	o.Query = strings.TrimSpace(o.Query)
+	if len(o.Query) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"query"}}
+	}
+
+	if utf8.RuneCountInString(o.Query) > HostSmartSearchMaxQueryLength {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"query", HostSmartSearchMaxQueryLength},
+		}
+	}
+
+	if o.BizID < 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if o.Limit == 0 {
+		o.Limit = HostSmartSearchDefaultLimit
+	}
+
+	if o.Limit < 0 || o.Limit > HostSmartSearchMaxLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"limit", HostSmartSearchMaxLimit},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// HostSmartSearchHit is a host matched by the host smart search with its relevance.
+type HostSmartSearchHit struct {
+	// Score the relevance score of the host, the higher the more relevant.
+	Score int `json:"score"`
+	// Source the match source of the host, exact/prefix/fulltext.
+	Source string `json:"source"`
+	// MatchedField the host field matched by the query.
+	MatchedField string        `json:"matched_field"`
+	Host         mapstr.MapStr `json:"host"`
+}
+
+// HostSmartSearchResult is the result of the host smart search, the hits are sorted by the score.
+type HostSmartSearchResult struct {
+	// QueryType how the query is interpreted, ip/number/text.
+	QueryType string               `json:"query_type"`
+	Hits      []HostSmartSearchHit `json:"hits"`
+}
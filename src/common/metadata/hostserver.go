@@ -411,8 +411,12 @@ type ListHostsParameter struct {
 	ModuleIDs          []int64                   `json:"bk_module_ids"`
 	ModuleCond         []ConditionItem           `json:"module_cond"`
 	HostPropertyFilter *querybuilder.QueryFilter `json:"host_property_filter"`
-	Fields             []string                  `json:"fields"`
-	Page               BasePage                  `json:"page"`
+	// RelatedFilters the conditions over the resources related to the hosts, they are compiled into staged queries.
+	RelatedFilters []HostRelatedFilter `json:"related_filters"`
+	Fields         []string            `json:"fields"`
+	Page           BasePage            `json:"page"`
+	// Explain returns the search explanation with the hosts, it is only allowed for the platform administrators.
+	Explain bool `json:"explain"`
 }
 
 // Validate TODO
@@ -438,6 +442,16 @@ func (option ListHostsParameter) Validate() (string, error) {
 		return "bk_module_ids", fmt.Errorf("exceed max length: 500")
 	}
 
+	if len(option.RelatedFilters) > HostRelatedFilterMaxCount {
+		return "related_filters", fmt.Errorf("exceed max length: %d", HostRelatedFilterMaxCount)
+	}
+
+	for idx, filter := range option.RelatedFilters {
+		if key, err := filter.Validate(); err != nil {
+			return fmt.Sprintf("related_filters[%d].%s", idx, key), err
+		}
+	}
+
 	return "", nil
 }
 
@@ -446,6 +460,8 @@ type ListHostsWithNoBizParameter struct {
 	HostPropertyFilter *querybuilder.QueryFilter `json:"host_property_filter"`
 	Fields             []string                  `json:"fields"`
 	Page               BasePage                  `json:"page"`
+	// Explain returns the search explanation with the hosts, it is only allowed for the platform administrators.
+	Explain bool `json:"explain"`
 }
 
 // Validate TODO
@@ -588,6 +604,8 @@ type ListHosts struct {
 	HostPropertyFilter *querybuilder.QueryFilter `json:"host_property_filter"`
 	Fields             []string                  `json:"fields"`
 	Page               BasePage                  `json:"page"`
+	// Explain returns the search explanation, the hosts are always searched in the db instead of the cache.
+	Explain bool `json:"explain,omitempty"`
 }
 
 // Validate whether ListHosts is valid
@@ -656,6 +674,8 @@ type ListHostResp struct {
 type ListHostResult struct {
 	Count int                      `json:"count"`
 	Info  []map[string]interface{} `json:"info"`
+	// Explain the search explanation, it is only returned in the explain mode.
+	Explain *SearchExplain `json:"explain,omitempty"`
 }
 
 // HostTopoResult TODO
@@ -0,0 +1,198 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// the conflict policies of the imports, which decide the attribute value when two ingestion paths update the same
+// instance attribute within the conflict window.
+const (
+	// ImportConflictSourcePriority the value of the ingestion path with the higher configured priority wins, the
+	// later import wins if the priorities are the same.
+	ImportConflictSourcePriority = "source_priority"
+	// ImportConflictNewestWins the value collected later wins, which is decided by the import data time.
+	ImportConflictNewestWins = "newest_wins"
+	// ImportConflictFieldMerge the attributes are merged by field, the attributes that are not written by the other
+	// ingestion path within the window are updated, and the others are kept.
+	ImportConflictFieldMerge = "field_merge"
+)
+
+// the import sources of the built-in ingestion paths.
+const (
+	// ImportSourceCloudSync the hosts synchronized from the cloud accounts
+	ImportSourceCloudSync = "cloud_sync"
+	// ImportSourceHostSnapshot the hosts reported by the host snapshot of the agents
+	ImportSourceHostSnapshot = "host_snapshot"
+	// ImportSourceExcel the instances imported from the excel
+	ImportSourceExcel = "excel_import"
+)
+
+// ImportConflictDefaultWindow the default window in which the writes of the different ingestion paths are conflicted
+const ImportConflictDefaultWindow = 5 * time.Minute
+
+// ImportConflictConfig is the config of the conflict resolution of the concurrent imports.
+type ImportConflictConfig struct {
+	// Window the writes of the different ingestion paths within it are conflicted
+	Window time.Duration
+	// SourcePriority the priorities of the ingestion paths used by the source priority policy, default is 0
+	SourcePriority map[string]int
+}
+
+// IsValidImportConflictPolicy check if the import conflict policy is valid
+func IsValidImportConflictPolicy(policy string) bool {
+	switch policy {
+	case ImportConflictSourcePriority, ImportConflictNewestWins, ImportConflictFieldMerge:
+		return true
+	}
+	return false
+}
+
+// ImportContext is the ingestion path and the conflict policy of an import request, which is passed by the header
+// so that it is kept through the scene servers to the core service.
+type ImportContext struct {
+	Source   string
+	Policy   string
+	DataTime time.Time
+}
+
+// SetImportContext set the import context into the header, the data time is set only if it is not zero.
+func SetImportContext(header http.Header, ctx *ImportContext) {
+	header.Set(common.BKHTTPImportSource, ctx.Source)
+	header.Set(common.BKHTTPImportConflictPolicy, ctx.Policy)
+	if !ctx.DataTime.IsZero() {
+		header.Set(common.BKHTTPImportDataTime, strconv.FormatInt(ctx.DataTime.Unix(), 10))
+	}
+}
+
+// GetImportContext get the import context from the header, returns nil if the request is not an import, the policy
+// is default to source priority, and the data time is default to now.
+func GetImportContext(header http.Header) (*ImportContext, errors.RawErrorInfo) {
+	source := header.Get(common.BKHTTPImportSource)
+	if len(source) == 0 {
+		return nil, errors.RawErrorInfo{}
+	}
+
+	ctx := &ImportContext{
+		Source:   source,
+		Policy:   header.Get(common.BKHTTPImportConflictPolicy),
+		DataTime: time.Now(),
+	}
+
+	if len(ctx.Policy) == 0 {
+		ctx.Policy = ImportConflictSourcePriority
+	}
+
+	if !IsValidImportConflictPolicy(ctx.Policy) {
+		return nil, errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsInvalid,
+			Args:    []interface{}{common.BKHTTPImportConflictPolicy},
+		}
+	}
+
+	if dataTime := header.Get(common.BKHTTPImportDataTime); len(dataTime) != 0 {
+		unix, err := strconv.ParseInt(dataTime, 10, 64)
+		if err != nil || unix <= 0 {
+			return nil, errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{common.BKHTTPImportDataTime},
+			}
+		}
+		ctx.DataTime = time.Unix(unix, 0)
+	}
+
+	return ctx, errors.RawErrorInfo{}
+}
+
+// ImportFieldWrite is the last import write of an instance attribute.
+type ImportFieldWrite struct {
+	Source    string    `json:"source" bson:"source"`
+	DataTime  time.Time `json:"data_time" bson:"data_time"`
+	WriteTime time.Time `json:"write_time" bson:"write_time"`
+}
+
+// ImportWriteRecord is the last import writes of the instance's attributes, which is used to find out the conflicts
+// of the later imports.
+type ImportWriteRecord struct {
+	ObjectID string                      `json:"bk_obj_id" bson:"bk_obj_id"`
+	InstID   int64                       `json:"bk_inst_id" bson:"bk_inst_id"`
+	Fields   map[string]ImportFieldWrite `json:"fields" bson:"fields"`
+	OwnerID  string                      `json:"bk_supplier_account" bson:"bk_supplier_account"`
+}
+
+// ImportConflict is a conflict of the concurrent imports recorded for review, it records the value of the import
+// and whether it is applied or dropped by the conflict policy.
+type ImportConflict struct {
+	ID         int64       `json:"id" bson:"id"`
+	ObjectID   string      `json:"bk_obj_id" bson:"bk_obj_id"`
+	InstID     int64       `json:"bk_inst_id" bson:"bk_inst_id"`
+	PropertyID string      `json:"bk_property_id" bson:"bk_property_id"`
+	Policy     string      `json:"policy" bson:"policy"`
+	Source     string      `json:"source" bson:"source"`
+	DataTime   time.Time   `json:"data_time" bson:"data_time"`
+	Value      interface{} `json:"value" bson:"value"`
+	// ConflictSource the ingestion path that wrote the attribute within the conflict window
+	ConflictSource   string    `json:"conflict_source" bson:"conflict_source"`
+	ConflictDataTime time.Time `json:"conflict_data_time" bson:"conflict_data_time"`
+	// Applied whether the value of the import is applied
+	Applied    bool      `json:"applied" bson:"applied"`
+	Operator   string    `json:"operator" bson:"operator"`
+	OwnerID    string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+	CreateTime time.Time `json:"create_time" bson:"create_time"`
+}
+
+// SearchImportConflictOption is the option to search the conflicts of the concurrent imports of a model.
+type SearchImportConflictOption struct {
+	ObjectID string   `json:"bk_obj_id"`
+	InstIDs  []int64  `json:"bk_inst_ids"`
+	Source   string   `json:"source"`
+	Page     BasePage `json:"page"`
+}
+
+// Validate validate the search import conflict option
+func (o *SearchImportConflictOption) Validate() errors.RawErrorInfo {
+	if len(o.ObjectID) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKObjIDField}}
+	}
+
+	if len(o.InstIDs) > common.BKMaxInstanceLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"bk_inst_ids", common.BKMaxInstanceLimit},
+		}
+	}
+
+	if err := o.Page.ValidateLimit(common.BKMaxPageSize); err != nil {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"page.limit"}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// ImportConflictResult is the result of the import conflicts search, the newest conflicts are returned first.
+type ImportConflictResult struct {
+	Count int64            `json:"count"`
+	Info  []ImportConflict `json:"info"`
+}
+
+// ImportConflictResp is the response of the import conflicts search
+type ImportConflictResp struct {
+	BaseResp `json:",inline"`
+	Data     *ImportConflictResult `json:"data"`
+}
@@ -122,6 +122,14 @@ type HostIdentProcess struct {
 	PortEnable bool `field:"bk_enable_port" json:"bk_enable_port" bson:"bk_enable_port"`
 	// BindInfo 进程绑定信息
 	BindInfo []ProcBindInfo `field:"bind_info" json:"bind_info" bson:"bind_info"`
+	// Commands 进程结构化的启动/停止/重载命令模板
+	Commands *ProcCommands `field:"bk_proc_commands" json:"bk_proc_commands" bson:"bk_proc_commands"`
+	// Envs 进程环境变量
+	Envs ProcEnvs `field:"bk_proc_envs" json:"bk_proc_envs" bson:"bk_proc_envs"`
+	// ConfigTemplates 进程的配置模板引用
+	ConfigTemplates ConfigTemplateRefs `field:"bk_config_templates" json:"bk_config_templates" bson:"bk_config_templates"`
+	// SvcInstConfigTemplates 进程所属服务实例的配置模板引用
+	SvcInstConfigTemplates ConfigTemplateRefs `json:"service_instance_config_templates" bson:"-"`
 }
 
 // HostIdentModule HostIdentifier module define
@@ -177,6 +185,63 @@ type SearchHostIdentifierData struct {
 	Info  []HostIdentifier `json:"info"`
 }
 
+const (
+	// HostIdentifierSnapshotDefaultLimit the default number of the host identifiers in a page of the snapshot
+	HostIdentifierSnapshotDefaultLimit = 100
+	// HostIdentifierSnapshotMaxLimit the max number of the host identifiers in a page of the snapshot
+	HostIdentifierSnapshotMaxLimit = 500
+)
+
+// HostIdentifierSnapshotOption is the option to get the identifiers of all the hosts under the business, sets or
+// modules page by page. The hosts are returned in the order of their ids, and the next page is requested with the
+// cursor returned by the previous page, so the pages are consistent even if the hosts are transferred in the meantime.
+type HostIdentifierSnapshotOption struct {
+	// SetIDs the sets to get the host identifiers of, all the hosts of the business if both SetIDs and ModuleIDs are
+	// not set, the hosts must be in both the sets and the modules if they are all set.
+	SetIDs    []int64 `json:"bk_set_ids"`
+	ModuleIDs []int64 `json:"bk_module_ids"`
+	// Cursor the host id of the last host identifier of the previous page, 0 means the first page.
+	Cursor int64 `json:"cursor"`
+	Limit  int   `json:"limit"`
+}
+
+// Validate validates the host identifier snapshot option, and sets the default limit if it is not set.
+func (o *HostIdentifierSnapshotOption) Validate() errors.RawErrorInfo {
+	if len(o.SetIDs) > common.BKMaxPageSize {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommXXExceedLimit,
+			Args: []interface{}{"bk_set_ids", common.BKMaxPageSize}}
+	}
+
+	if len(o.ModuleIDs) > common.BKMaxPageSize {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommXXExceedLimit,
+			Args: []interface{}{"bk_module_ids", common.BKMaxPageSize}}
+	}
+
+	if o.Cursor < 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"cursor"}}
+	}
+
+	if o.Limit == 0 {
+		o.Limit = HostIdentifierSnapshotDefaultLimit
+	}
+
+	if o.Limit < 0 || o.Limit > HostIdentifierSnapshotMaxLimit {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommXXExceedLimit,
+			Args: []interface{}{"limit", HostIdentifierSnapshotMaxLimit}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// HostIdentifierSnapshotResult is a page of the host identifier snapshot
+type HostIdentifierSnapshotResult struct {
+	// Cursor the cursor to get the next page, which is the host id of the last host identifier in Info.
+	Cursor int64 `json:"cursor"`
+	// HasMore whether there may be more host identifiers after the cursor.
+	HasMore bool             `json:"has_more"`
+	Info    []HostIdentifier `json:"info"`
+}
+
 // SearchInstsNamesOption search instances names option
 type SearchInstsNamesOption struct {
 	ObjID string `json:"bk_obj_id"`
@@ -245,6 +310,9 @@ type CreateManyCommInst struct {
 	Details []mapstr.MapStr `json:"details"`
 }
 
+// CreateManyCommInstMaxLimit the max number of the instances created at once
+const CreateManyCommInstMaxLimit = 200
+
 // CreateManyCommInstResult result of creating multiple instances
 type CreateManyCommInstResult struct {
 	BaseResp `json:",inline"`
@@ -271,6 +339,105 @@ type InstBatchInfo struct {
 	// map[rownumber]map[property_id][date]
 	BatchInfo map[int64]mapstr.MapStr `json:"BatchInfo"`
 	InputType string                  `json:"input_type"`
+	// UpsertKeys the attributes that are used to match the existing instance of the row without instance id, the
+	// matched instance is updated, otherwise a new instance is created.
+	UpsertKeys []string `json:"upsert_keys,omitempty"`
+	// AssociationColumns the columns that refer to the associated instances, the instance's associations of the
+	// column's association are refreshed to the referred instances.
+	AssociationColumns []ImportAssociationColumn `json:"association_columns,omitempty"`
+	// DryRun only reports what will be done for each row, nothing is changed.
+	DryRun bool `json:"dry_run,omitempty"`
+	// ExternalIDSource the source system of the external ids in the bk_external_id column, the row is matched to
+	// the instance bound to its external id before the upsert keys, and the imported instance is bound to it.
+	ExternalIDSource string `json:"external_id_source,omitempty"`
+}
+
+// InstBatchMaxUpsertKeys the max number of the upsert keys of the instance import
+const InstBatchMaxUpsertKeys = 5
+
+// Validate validate the upsert keys and the association columns of the instance import
+func (b *InstBatchInfo) Validate() errors.RawErrorInfo {
+	if len(b.UpsertKeys) > InstBatchMaxUpsertKeys {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"upsert_keys", InstBatchMaxUpsertKeys},
+		}
+	}
+
+	keys := make(map[string]struct{}, len(b.UpsertKeys))
+	for _, key := range b.UpsertKeys {
+		if len(key) == 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"upsert_keys"}}
+		}
+
+		if _, exists := keys[key]; exists {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommDuplicateItem, Args: []interface{}{key}}
+		}
+		keys[key] = struct{}{}
+	}
+
+	columns := make(map[string]struct{}, len(b.AssociationColumns))
+	for _, column := range b.AssociationColumns {
+		if len(column.Column) == 0 {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsNeedSet,
+				Args:    []interface{}{"association_columns.column"},
+			}
+		}
+
+		if len(column.ObjAsstID) == 0 {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsNeedSet,
+				Args:    []interface{}{"association_columns." + common.AssociationObjAsstIDField},
+			}
+		}
+
+		if _, exists := columns[column.Column]; exists {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommDuplicateItem, Args: []interface{}{column.Column}}
+		}
+		columns[column.Column] = struct{}{}
+	}
+
+	if len(b.ExternalIDSource) > 0 && !externalIDSourceRegexp.MatchString(b.ExternalIDSource) {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"external_id_source"}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// ImportAssociationColumn is a column of the instance import that refers to the associated instances.
+type ImportAssociationColumn struct {
+	// Column the property id header of the column, the cell is the key values of the associated instances
+	// separated by comma.
+	Column string `json:"column"`
+	// ObjAsstID the model association of the associated instances.
+	ObjAsstID string `json:"bk_obj_asst_id"`
+	// Key the attribute of the associated model that the cell refers to, default is the instance name.
+	Key string `json:"key,omitempty"`
+}
+
+// ImportRowAction is the action that is done for a row of the instance import.
+type ImportRowAction string
+
+const (
+	// ImportRowCreate a new instance is created by the row
+	ImportRowCreate ImportRowAction = "create"
+	// ImportRowUpdate the existing instance is updated by the row
+	ImportRowUpdate ImportRowAction = "update"
+	// ImportRowError the row is failed to be imported
+	ImportRowError ImportRowAction = "error"
+)
+
+// ImportRowResult is the result of a row of the instance import.
+type ImportRowResult struct {
+	Line   int64           `json:"line"`
+	Action ImportRowAction `json:"action"`
+	InstID int64           `json:"bk_inst_id,omitempty"`
+	// AssociationCreated the number of the associations created by the association columns of the row
+	AssociationCreated int `json:"association_created"`
+	// AssociationDeleted the number of the associations deleted by the association columns of the row
+	AssociationDeleted int    `json:"association_deleted"`
+	Error              string `json:"error,omitempty"`
 }
 
 // GetInstID get inst id by objid
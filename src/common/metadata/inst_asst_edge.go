@@ -0,0 +1,134 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/querybuilder"
+)
+
+// InstAsstEdgeMaxEndpointInsts the max number of the instances matched by the endpoint filter of the instance
+// association edge search, the filter must be narrowed if more instances are matched.
+const InstAsstEdgeMaxEndpointInsts = 10000
+
+// instAsstEdgeSortFields the fields of the instance association edge that can be sorted by.
+var instAsstEdgeSortFields = map[string]struct{}{
+	common.BKFieldID:         {},
+	common.BKInstIDField:     {},
+	common.BKAsstInstIDField: {},
+}
+
+// InstAsstEdgeFilter filters the instance associations of a model association, the source and target instances of
+// the edges can be filtered by their attributes, e.g. the linux hosts related to a vip.
+type InstAsstEdgeFilter struct {
+	BizID     int64  `json:"bk_biz_id"`
+	ObjAsstID string `json:"bk_obj_asst_id"`
+	// AsstFilter filters the edges by their own fields.
+	AsstFilter *querybuilder.QueryFilter `json:"asst_filter"`
+	// SrcFilter filters the edges by the attributes of the source instances.
+	SrcFilter *querybuilder.QueryFilter `json:"src_filter"`
+	// DstFilter filters the edges by the attributes of the target instances.
+	DstFilter *querybuilder.QueryFilter `json:"dst_filter"`
+}
+
+// Validate validate the instance association edge filter
+func (f *InstAsstEdgeFilter) Validate() errors.RawErrorInfo {
+	if len(f.ObjAsstID) == 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsNeedSet,
+			Args:    []interface{}{common.AssociationObjAsstIDField},
+		}
+	}
+
+	option := &querybuilder.RuleOption{
+		NeedSameSliceElementType: true,
+		MaxSliceElementsCount:    querybuilder.DefaultMaxSliceElementsCount,
+		MaxValueBytes:            querybuilder.DefaultMaxValueBytes,
+		MaxStringLength:          querybuilder.DefaultMaxStringLength,
+		MaxConditionOrRulesCount: querybuilder.DefaultMaxConditionOrRulesCount,
+	}
+
+	filters := map[string]*querybuilder.QueryFilter{
+		"asst_filter": f.AsstFilter,
+		"src_filter":  f.SrcFilter,
+		"dst_filter":  f.DstFilter,
+	}
+	for name, filter := range filters {
+		if filter == nil {
+			continue
+		}
+
+		if key, err := filter.Validate(option); err != nil {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{fmt.Sprintf("%s.%s", name, key)},
+			}
+		}
+
+		if filter.GetDeep() > querybuilder.MaxDeep {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{name + ".rules"},
+			}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// InstAsstEdgeSearchOption is the option to search the instance associations of a model association by page.
+type InstAsstEdgeSearchOption struct {
+	InstAsstEdgeFilter `json:",inline"`
+	Fields             []string `json:"fields"`
+	// Page the page of the edges, sort by the id if the sort is not set, the sort can be the id, bk_inst_id and
+	// bk_asst_inst_id of the edges, prefixed with "-" for the descending order. the id is always appended to the
+	// sort so that the pages are stable for the high-degree instances.
+	Page BasePage `json:"page"`
+}
+
+// Validate validate the instance association edge search option, and set the default sort
+func (o *InstAsstEdgeSearchOption) Validate() errors.RawErrorInfo {
+	if rawErr := o.InstAsstEdgeFilter.Validate(); rawErr.ErrCode != 0 {
+		return rawErr
+	}
+
+	if err := o.Page.ValidateLimit(common.BKMaxInstanceLimit); err != nil {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"page.limit"}}
+	}
+
+	if len(o.Page.Sort) == 0 {
+		o.Page.Sort = common.BKFieldID
+		return errors.RawErrorInfo{}
+	}
+
+	hasID := false
+	for _, item := range strings.Split(o.Page.Sort, ",") {
+		field := strings.TrimLeft(strings.Split(strings.TrimSpace(item), ":")[0], "+-")
+		if _, ok := instAsstEdgeSortFields[field]; !ok {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"page.sort"}}
+		}
+		if field == common.BKFieldID {
+			hasID = true
+		}
+	}
+
+	if !hasID {
+		o.Page.Sort += "," + common.BKFieldID
+	}
+
+	return errors.RawErrorInfo{}
+}
@@ -0,0 +1,144 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+const (
+	// InstGraphDefaultDepth the default depth of the instance graph
+	InstGraphDefaultDepth = 1
+	// InstGraphMaxDepth the max depth of the instance graph
+	InstGraphMaxDepth = 3
+	// InstGraphDefaultNeighborLimit the default count of neighbors returned for each node
+	InstGraphDefaultNeighborLimit = 50
+	// InstGraphMaxNeighborLimit the max count of neighbors returned for each node
+	InstGraphMaxNeighborLimit = 200
+	// InstGraphMaxNodes the max count of nodes in the instance graph, the nodes beyond it are not expanded
+	InstGraphMaxNodes = 1000
+)
+
+// InstGraphOption is the option to search the neighborhood graph of an instance.
+type InstGraphOption struct {
+	// Depth the max association hops from the root instance, default is 1.
+	Depth int `json:"depth"`
+	// NeighborLimit the max count of neighbors returned for each node, default is 50.
+	NeighborLimit int `json:"neighbor_limit"`
+	// NeighborStart the offset of the root instance's neighbors, used to page through a high-degree node.
+	NeighborStart int `json:"neighbor_start"`
+	// ObjIDs only return the neighbors of these models if set.
+	ObjIDs []string `json:"bk_obj_ids"`
+	// BizID the business id of the instance, used for authorization.
+	BizID int64 `json:"bk_biz_id"`
+}
+
+// Validate validate the instance graph option, and set the default values.
+func (o *InstGraphOption) Validate() errors.RawErrorInfo {
+	if o.Depth == 0 {
+		o.Depth = InstGraphDefaultDepth
+	}
+
+	if o.Depth < 0 || o.Depth > InstGraphMaxDepth {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommValExceedMaxFailed,
+			Args:    []interface{}{"depth", InstGraphMaxDepth},
+		}
+	}
+
+	if o.NeighborLimit == 0 {
+		o.NeighborLimit = InstGraphDefaultNeighborLimit
+	}
+
+	if o.NeighborLimit < 0 || o.NeighborLimit > InstGraphMaxNeighborLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommValExceedMaxFailed,
+			Args:    []interface{}{"neighbor_limit", InstGraphMaxNeighborLimit},
+		}
+	}
+
+	if o.NeighborStart < 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsInvalid,
+			Args:    []interface{}{"neighbor_start"},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// InstGraph is the neighborhood graph of an instance, used by the topology graph of the ui.
+type InstGraph struct {
+	Nodes  []*InstGraphNode `json:"nodes"`
+	Edges  []InstGraphEdge  `json:"edges"`
+	Groups []InstGraphGroup `json:"groups"`
+	Layout InstGraphLayout  `json:"layout"`
+}
+
+// InstGraphNode is an instance in the graph.
+type InstGraphNode struct {
+	// ID the unique id of the node in the graph, it is composed of the model id and the instance id.
+	ID       string `json:"id"`
+	ObjID    string `json:"bk_obj_id"`
+	InstID   int64  `json:"bk_inst_id"`
+	InstName string `json:"bk_inst_name"`
+	// Group the group that the node belongs to, nodes of the same model are in the same group.
+	Group string `json:"group"`
+	// Level the association hops from the root instance, it is a layout hint for the hierarchical layout.
+	Level int `json:"level"`
+	// Expanded whether the node's neighbors are returned, the not expanded node can be expanded by searching
+	// the graph with it as the root.
+	Expanded bool `json:"expanded"`
+	// Degree the total count of the node's associations, only set when the node is expanded.
+	Degree uint64 `json:"degree"`
+	// NextStart the neighbor_start to get the rest neighbors of the node, 0 means there is no more neighbors.
+	NextStart int `json:"next_start"`
+}
+
+// InstGraphEdge is an instance association in the graph.
+type InstGraphEdge struct {
+	// ID the instance association id.
+	ID int64 `json:"id"`
+	// Source the id of the association's source node.
+	Source string `json:"source"`
+	// Target the id of the association's target node.
+	Target            string `json:"target"`
+	ObjectAsstID      string `json:"bk_obj_asst_id"`
+	AssociationKindID string `json:"bk_asst_id"`
+}
+
+// InstGraphGroup is the model of a group of nodes in the graph.
+type InstGraphGroup struct {
+	ID      string `json:"id"`
+	ObjName string `json:"bk_obj_name"`
+	ObjIcon string `json:"bk_obj_icon"`
+	Count   int    `json:"count"`
+}
+
+// InstGraphLayout is the layout hints of the graph.
+type InstGraphLayout struct {
+	// Root the id of the root node, it should be placed at the center of the graph.
+	Root string `json:"root"`
+	// MaxLevel the max level of the nodes.
+	MaxLevel int `json:"max_level"`
+	// Truncated whether some nodes are not expanded because the graph exceeds the max nodes.
+	Truncated bool `json:"truncated"`
+}
+
+// InstGraphNodeID returns the id of the instance's node in the graph.
+func InstGraphNodeID(objID string, instID int64) string {
+	return objID + ":" + strconv.FormatInt(instID, 10)
+}
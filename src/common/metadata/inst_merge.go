@@ -0,0 +1,138 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+)
+
+// MergeInstOption is the option to merge a duplicated instance into another instance of the same model.
+type MergeInstOption struct {
+	// SourceID the duplicated instance, it will be deleted and archived after merged.
+	SourceID int64 `json:"source_id"`
+	// TargetID the surviving instance.
+	TargetID int64 `json:"target_id"`
+	// Data the values chosen for the conflicting attributes, key is the attribute's bk_property_id.
+	Data mapstr.MapStr `json:"data"`
+	// BizID the business id of the instances, used for authorization.
+	BizID int64 `json:"bk_biz_id"`
+}
+
+// Validate validate the merge instance option
+func (m *MergeInstOption) Validate() errors.RawErrorInfo {
+	if m.SourceID <= 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsInvalid,
+			Args:    []interface{}{"source_id"},
+		}
+	}
+
+	if m.TargetID <= 0 || m.TargetID == m.SourceID {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsInvalid,
+			Args:    []interface{}{"target_id"},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// MergeInstConflict is an attribute that both instances have different values.
+type MergeInstConflict struct {
+	PropertyID  string      `json:"bk_property_id"`
+	SourceValue interface{} `json:"source_value"`
+	TargetValue interface{} `json:"target_value"`
+}
+
+// MergeInstPreview is the preview result of merging two instances.
+type MergeInstPreview struct {
+	// Conflicts the attributes that need to choose a value to keep.
+	Conflicts []MergeInstConflict `json:"conflicts"`
+	// Data the attribute values that will be updated to the target instance, includes the values that only the
+	// source instance has and the values chosen for the conflicting attributes.
+	Data mapstr.MapStr `json:"data"`
+	// AssociationCount the count of the source instance's associations which will be re-pointed to the target.
+	AssociationCount int `json:"association_count"`
+}
+
+// MergeHostOption is the option to merge the relations of a duplicated host into another host.
+type MergeHostOption struct {
+	// SourceID the duplicated host, it is deleted after its relations are moved to the target host.
+	SourceID int64 `json:"source_id"`
+	// TargetID the surviving host.
+	TargetID int64 `json:"target_id"`
+}
+
+// Validate validate the merge host option
+func (m *MergeHostOption) Validate() errors.RawErrorInfo {
+	if m.SourceID <= 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsInvalid,
+			Args:    []interface{}{"source_id"},
+		}
+	}
+
+	if m.TargetID <= 0 || m.TargetID == m.SourceID {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsInvalid,
+			Args:    []interface{}{"target_id"},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// RepointAuditLogOption is the option to move the audit logs of a resource to another resource of the same type,
+// so that the history of a merged instance is kept by the surviving instance.
+type RepointAuditLogOption struct {
+	ResourceType ResourceType `json:"resource_type"`
+	// ObjID the model of the instances, required when the resource type is model instance.
+	ObjID    string `json:"bk_obj_id"`
+	SourceID int64  `json:"source_id"`
+	TargetID int64  `json:"target_id"`
+}
+
+// Validate validate the repoint audit log option
+func (r *RepointAuditLogOption) Validate() errors.RawErrorInfo {
+	if len(r.ResourceType) == 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsNeedSet,
+			Args:    []interface{}{common.BKResourceTypeField},
+		}
+	}
+
+	if r.ResourceType == ModelInstanceRes && len(r.ObjID) == 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsNeedSet,
+			Args:    []interface{}{common.BKObjIDField},
+		}
+	}
+
+	if r.SourceID <= 0 {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsInvalid,
+			Args:    []interface{}{"source_id"},
+		}
+	}
+
+	if r.TargetID <= 0 || r.TargetID == r.SourceID {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommParamsInvalid,
+			Args:    []interface{}{"target_id"},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
@@ -0,0 +1,160 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// fullWidthOffset the offset between the full-width form and the ascii form of the printable characters.
+	fullWidthOffset = 0xFEE0
+	// ideographicSpace the full-width form of the space.
+	ideographicSpace = '　'
+)
+
+// InstNameCollation is the policy to compare the instance names in the uniqueness checks, the names that are equal
+// under the policy are considered duplicated even if they are not exactly the same. the exact comparison is used if
+// none of the options is set.
+type InstNameCollation struct {
+	// CaseInsensitive compare the names case-insensitively, e.g. "Web" equals to "web".
+	CaseInsensitive bool `json:"case_insensitive"`
+	// TrimSpace ignore the leading and trailing white spaces of the names.
+	TrimSpace bool `json:"trim_space"`
+	// WidthInsensitive compare the full-width characters as their half-width forms, e.g. "ｗｅｂ１" equals to "web1".
+	WidthInsensitive bool `json:"width_insensitive"`
+}
+
+// IsExact returns if the names are compared exactly, which is already guaranteed by the unique indexes.
+func (c *InstNameCollation) IsExact() bool {
+	return c == nil || (!c.CaseInsensitive && !c.TrimSpace && !c.WidthInsensitive)
+}
+
+// Normalize returns the normalized form of the name, the names are equal under the policy if and only if their
+// normalized forms are the same.
+func (c *InstNameCollation) Normalize(name string) string {
+	if c.IsExact() {
+		return name
+	}
+
+	if c.TrimSpace {
+		name = strings.TrimFunc(name, isCollationSpace)
+	}
+
+	runes := []rune(name)
+	for idx, r := range runes {
+		if c.WidthInsensitive {
+			r = foldWidth(r)
+		}
+		if c.CaseInsensitive {
+			r = unicode.ToLower(r)
+		}
+		runes[idx] = r
+	}
+	return string(runes)
+}
+
+// MatchPattern returns the regular expression that matches the names which are equal to the name under the policy,
+// it is used to find the duplicated names in db since the stored names are not normalized.
+func (c *InstNameCollation) MatchPattern(name string) string {
+	pattern := new(strings.Builder)
+	pattern.WriteString("^")
+	if c.TrimSpace {
+		pattern.WriteString(collationSpacePattern)
+	}
+
+	for _, r := range []rune(c.Normalize(name)) {
+		variants := c.variants(r)
+		if len(variants) == 1 {
+			pattern.WriteString(quoteRune(r))
+			continue
+		}
+
+		pattern.WriteString("[")
+		for _, variant := range variants {
+			pattern.WriteString(quoteRune(variant))
+		}
+		pattern.WriteString("]")
+	}
+
+	if c.TrimSpace {
+		pattern.WriteString(collationSpacePattern)
+	}
+	pattern.WriteString("$")
+	return pattern.String()
+}
+
+// variants returns all the characters whose normalized form is the normalized character r.
+func (c *InstNameCollation) variants(r rune) []rune {
+	forms := []rune{r}
+	if c.CaseInsensitive {
+		if upper := unicode.ToUpper(r); upper != r {
+			forms = append(forms, upper)
+		}
+	}
+
+	if !c.WidthInsensitive {
+		return forms
+	}
+
+	variants := make([]rune, 0, 2*len(forms))
+	for _, form := range forms {
+		variants = append(variants, form)
+		if full, ok := toFullWidth(form); ok {
+			variants = append(variants, full)
+		}
+	}
+	return variants
+}
+
+// collationSpacePattern matches the white spaces that are trimmed by the policy.
+const collationSpacePattern = `[\t\n\f\r \x{3000}]*`
+
+func isCollationSpace(r rune) bool {
+	switch r {
+	case '\t', '\n', '\f', '\r', ' ', ideographicSpace:
+		return true
+	}
+	return false
+}
+
+// foldWidth converts the full-width form of the printable ascii character to its half-width form.
+func foldWidth(r rune) rune {
+	if r == ideographicSpace {
+		return ' '
+	}
+	if r >= '!'+fullWidthOffset && r <= '~'+fullWidthOffset {
+		return r - fullWidthOffset
+	}
+	return r
+}
+
+// toFullWidth converts the printable ascii character to its full-width form.
+func toFullWidth(r rune) (rune, bool) {
+	if r == ' ' {
+		return ideographicSpace, true
+	}
+	if r >= '!' && r <= '~' {
+		return r + fullWidthOffset, true
+	}
+	return r, false
+}
+
+// quoteRune escapes the character that has special meanings in the regular expression.
+func quoteRune(r rune) string {
+	if r < unicode.MaxASCII && !unicode.IsLetter(r) && !unicode.IsDigit(r) && unicode.IsPrint(r) && r != ' ' {
+		return `\` + string(r)
+	}
+	return string(r)
+}
@@ -0,0 +1,86 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// InstTaskChunkSize the max number of the rows or instances that a sub task of the instance import or export job
+// handles, the job can be resumed from the chunk that is failed.
+const InstTaskChunkSize = 500
+
+// InstImportTaskData is the sub task data of the instance import job, each sub task imports a chunk of the rows.
+type InstImportTaskData struct {
+	ObjID         string `json:"bk_obj_id"`
+	InstBatchInfo `json:",inline"`
+}
+
+// Validate validate the instance import task data
+func (d *InstImportTaskData) Validate() errors.RawErrorInfo {
+	if len(d.ObjID) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKObjIDField}}
+	}
+
+	if len(d.BatchInfo) > InstTaskChunkSize {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"BatchInfo", InstTaskChunkSize},
+		}
+	}
+
+	return d.InstBatchInfo.Validate()
+}
+
+// InstExportTaskData is the sub task data of the instance export job, each sub task exports a chunk of the instances,
+// the exported instances are saved as the task's file, which is built into the excel file when it is downloaded.
+type InstExportTaskData struct {
+	ObjID   string  `json:"bk_obj_id"`
+	BizID   int64   `json:"bk_biz_id"`
+	InstIDs []int64 `json:"bk_inst_ids"`
+
+	// CustomFields, AssociationCond and ObjectUniqueID are the options to build the excel file, they are the same
+	// as the options of the instance export.
+	CustomFields    []string         `json:"export_custom_fields,omitempty"`
+	AssociationCond map[string]int64 `json:"association_condition,omitempty"`
+	ObjectUniqueID  int64            `json:"object_unique_id,omitempty"`
+}
+
+// Validate validate the instance export task data, the max instances is the limit of the job or a chunk of the job.
+func (d *InstExportTaskData) Validate(maxInsts int) errors.RawErrorInfo {
+	if len(d.ObjID) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKObjIDField}}
+	}
+
+	if len(d.InstIDs) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"bk_inst_ids"}}
+	}
+
+	if len(d.InstIDs) > maxInsts {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"bk_inst_ids", maxInsts},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// InstTaskResult is the response of creating the instance import or export job.
+type InstTaskResult struct {
+	BaseResp `json:",inline"`
+	Data     struct {
+		TaskID string `json:"task_id"`
+	} `json:"data"`
+}
@@ -0,0 +1,267 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// the types of the model assets.
+const (
+	// ModelAssetTypeIcon the icon of the models and classifications, which replaces the built-in icons of the ui.
+	ModelAssetTypeIcon = "icon"
+	// ModelAssetTypeMetadata the custom metadata of the models, e.g. the branding description in json.
+	ModelAssetTypeMetadata = "metadata"
+)
+
+const (
+	// ModelAssetRefPrefix the prefix of the model asset reference, the bk_obj_icon of the model and the
+	// bk_classification_icon of the classification can be "asset:<id>" to use the model asset as the icon.
+	ModelAssetRefPrefix = "asset:"
+	// ModelAssetBlobKeyPrefix the prefix of the blob store keys of the model assets.
+	ModelAssetBlobKeyPrefix = "model/asset/"
+	// ModelAssetMaxSize the max size of a model asset version's content in bytes.
+	ModelAssetMaxSize = 1 << 20
+	// ModelAssetMaxVersions the max number of the versions kept for a model asset, the oldest versions are
+	// removed when a new version is added.
+	ModelAssetMaxVersions = 10
+	// ModelAssetMaxNameLength the max length of the model asset's name.
+	ModelAssetMaxNameLength = 128
+)
+
+var modelAssetContentTypes = map[string][]string{
+	ModelAssetTypeIcon:     {"image/svg+xml", "image/png", "image/jpeg", "image/gif"},
+	ModelAssetTypeMetadata: {"application/json", "text/plain"},
+}
+
+// ModelAssetRef returns the reference of the model asset, which is used as the icon of the models.
+func ModelAssetRef(id int64) string {
+	return ModelAssetRefPrefix + strconv.FormatInt(id, 10)
+}
+
+// ParseModelAssetRef parses the model asset id from the icon, returns false if the icon is not a model asset.
+func ParseModelAssetRef(ref string) (int64, bool) {
+	if !strings.HasPrefix(ref, ModelAssetRefPrefix) {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(ref, ModelAssetRefPrefix), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// ModelAsset is an icon or a custom metadata of the models stored in the blob store, the content of the asset is
+// versioned so that the models can be rebranded without losing the history.
+type ModelAsset struct {
+	ID   int64  `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+	Type string `json:"type" bson:"type"`
+	// Version the latest version of the asset, which is served when the asset is referred.
+	Version  int64               `json:"version" bson:"version"`
+	Versions []ModelAssetVersion `json:"versions" bson:"versions"`
+	// RefCount the number of the models and classifications referring to the asset, which is counted when the
+	// asset is searched and is not stored.
+	RefCount        int64     `json:"ref_count" bson:"-"`
+	Creator         string    `json:"creator" bson:"creator"`
+	CreateTime      time.Time `json:"create_time" bson:"create_time"`
+	Modifier        string    `json:"modifier" bson:"modifier"`
+	LastTime        time.Time `json:"last_time" bson:"last_time"`
+	SupplierAccount string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+}
+
+// GetVersion returns the version of the model asset, the latest version is returned if the version is 0.
+func (a *ModelAsset) GetVersion(version int64) (*ModelAssetVersion, bool) {
+	if version == 0 {
+		version = a.Version
+	}
+
+	for index := range a.Versions {
+		if a.Versions[index].Version == version {
+			return &a.Versions[index], true
+		}
+	}
+	return nil, false
+}
+
+// BlobKeys returns the blob store keys of all the versions of the model asset.
+func (a *ModelAsset) BlobKeys() []string {
+	keys := make([]string, 0, len(a.Versions))
+	for _, version := range a.Versions {
+		keys = append(keys, version.Key)
+	}
+	return keys
+}
+
+// ModelAssetContent is the content of a model asset version stored in the blob store.
+type ModelAssetContent struct {
+	// Key the blob store key of the content.
+	Key         string `json:"key" bson:"key"`
+	ContentType string `json:"content_type" bson:"content_type"`
+	Size        int64  `json:"size" bson:"size"`
+	SHA256      string `json:"sha256" bson:"sha256"`
+}
+
+// Validate validate the model asset content of the asset type
+func (c *ModelAssetContent) Validate(assetType string) errors.RawErrorInfo {
+	if !strings.HasPrefix(c.Key, ModelAssetBlobKeyPrefix) || len(c.Key) == len(ModelAssetBlobKeyPrefix) {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"key"}}
+	}
+
+	if c.Size <= 0 || c.Size > ModelAssetMaxSize {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommXXExceedLimit, Args: []interface{}{"size", ModelAssetMaxSize}}
+	}
+
+	if len(c.SHA256) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"sha256"}}
+	}
+
+	if !IsModelAssetContentTypeAllowed(assetType, c.ContentType) {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"content_type"}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// IsModelAssetContentTypeAllowed checks if the content type can be uploaded as the model asset of the type.
+func IsModelAssetContentTypeAllowed(assetType, contentType string) bool {
+	// the parameters of the content type like the charset are ignored
+	contentType = strings.TrimSpace(strings.Split(contentType, ";")[0])
+	for _, allowed := range modelAssetContentTypes[assetType] {
+		if contentType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// ModelAssetVersion is a version of the model asset.
+type ModelAssetVersion struct {
+	Version           int64 `json:"version" bson:"version"`
+	ModelAssetContent `json:",inline" bson:",inline"`
+	Creator           string    `json:"creator" bson:"creator"`
+	CreateTime        time.Time `json:"create_time" bson:"create_time"`
+}
+
+// CreateModelAssetOption is the option to create a model asset with its first version.
+type CreateModelAssetOption struct {
+	Name    string            `json:"name"`
+	Type    string            `json:"type"`
+	Content ModelAssetContent `json:"content"`
+}
+
+// Validate validate the create model asset option
+func (o *CreateModelAssetOption) Validate() errors.RawErrorInfo {
+	o.Name = strings.TrimSpace(o.Name)
+	if len(o.Name) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKFieldName}}
+	}
+
+	if utf8.RuneCountInString(o.Name) > ModelAssetMaxNameLength {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{common.BKFieldName, ModelAssetMaxNameLength},
+		}
+	}
+
+	if _, exists := modelAssetContentTypes[o.Type]; !exists {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"type"}}
+	}
+
+	return o.Content.Validate(o.Type)
+}
+
+// AddModelAssetVersionOption is the option to add a new version to the model asset, which becomes the latest one.
+type AddModelAssetVersionOption struct {
+	Content ModelAssetContent `json:"content"`
+}
+
+// AddModelAssetVersionResult is the result of adding a model asset version.
+type AddModelAssetVersionResult struct {
+	Asset *ModelAsset `json:"asset"`
+	// RemovedKeys the blob store keys of the oldest versions removed for exceeding the max versions, the caller
+	// should delete them from the blob store.
+	RemovedKeys []string `json:"removed_keys"`
+}
+
+// SearchModelAssetOption is the option to search the model assets.
+type SearchModelAssetOption struct {
+	IDs  []int64 `json:"ids"`
+	Type string  `json:"type"`
+	// Name the name of the assets to search, which is matched fuzzily.
+	Name string   `json:"name"`
+	Page BasePage `json:"page"`
+}
+
+// Validate validate the search model asset option, and set the default sort
+func (o *SearchModelAssetOption) Validate() errors.RawErrorInfo {
+	if len(o.IDs) > common.BKMaxInstanceLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"ids", common.BKMaxInstanceLimit},
+		}
+	}
+
+	if len(o.Type) > 0 {
+		if _, exists := modelAssetContentTypes[o.Type]; !exists {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"type"}}
+		}
+	}
+
+	if err := o.Page.ValidateLimit(common.BKMaxInstanceLimit); err != nil {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"page.limit"}}
+	}
+
+	if len(o.Page.Sort) == 0 {
+		o.Page.Sort = common.BKFieldID
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// SearchModelAssetResult is the result of searching the model assets.
+type SearchModelAssetResult struct {
+	Count int64        `json:"count"`
+	Info  []ModelAsset `json:"info"`
+}
+
+// ModelAssetResp is the response of a model asset.
+type ModelAssetResp struct {
+	BaseResp `json:",inline"`
+	Data     *ModelAsset `json:"data"`
+}
+
+// AddModelAssetVersionResp is the response of adding a model asset version.
+type AddModelAssetVersionResp struct {
+	BaseResp `json:",inline"`
+	Data     *AddModelAssetVersionResult `json:"data"`
+}
+
+// SearchModelAssetResp is the response of searching the model assets.
+type SearchModelAssetResp struct {
+	BaseResp `json:",inline"`
+	Data     *SearchModelAssetResult `json:"data"`
+}
+
+// ModelAssetBlobKey returns a new blob store key for the model asset content.
+func ModelAssetBlobKey(assetType, uniqueID string) string {
+	return fmt.Sprintf("%s%s/%s", ModelAssetBlobKeyPrefix, assetType, uniqueID)
+}
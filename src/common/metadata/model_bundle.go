@@ -0,0 +1,206 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"regexp"
+	"sort"
+	"unicode/utf8"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+const (
+	// ModelBundleMaxObjects the max number of the models in a model bundle
+	ModelBundleMaxObjects = 100
+	// ModelBundleMaxNameLength the max length of the model bundle's name
+	ModelBundleMaxNameLength = 128
+)
+
+// modelBundleVersionRegexp the version of the model bundle is in the form of major.minor.patch, e.g. 1.0.2
+var modelBundleVersionRegexp = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+// ModelBundleHeader is the name and version of a model bundle.
+type ModelBundleHeader struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+// Validate validate the name and version of the model bundle.
+func (h *ModelBundleHeader) Validate() errors.RawErrorInfo {
+	if len(h.Name) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"name"}}
+	}
+
+	if utf8.RuneCountInString(h.Name) > ModelBundleMaxNameLength {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"name", ModelBundleMaxNameLength},
+		}
+	}
+
+	if !modelBundleVersionRegexp.MatchString(h.Version) {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"version"}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// ExportModelBundleOption is the option to export the models as a model bundle.
+type ExportModelBundleOption struct {
+	ModelBundleHeader `json:",inline"`
+	ObjectIDs         []int64 `json:"object_id"`
+	// ExcludedAsstIDs the ids of the model associations that are not exported with the models.
+	ExcludedAsstIDs []int64 `json:"excluded_asst_id"`
+}
+
+// Validate validate the export model bundle option.
+func (o *ExportModelBundleOption) Validate() errors.RawErrorInfo {
+	if err := o.ModelBundleHeader.Validate(); err.ErrCode != 0 {
+		return err
+	}
+
+	if len(o.ObjectIDs) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"object_id"}}
+	}
+
+	if len(o.ObjectIDs) > ModelBundleMaxObjects {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"object_id", ModelBundleMaxObjects},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// ModelBundle is a package of related models with their attributes, unique rules, associations and association
+// kinds, which is exported from one deployment and installed into another, e.g. a standard database CI pack.
+type ModelBundle struct {
+	ModelBundleHeader `json:",inline"`
+	// CreateTime the unix timestamp when the bundle is exported.
+	CreateTime int64 `json:"create_time"`
+	// Dependencies the models and association kinds that the bundle refers to but does not contain, they must
+	// exist in the deployment that the bundle is installed into.
+	Dependencies ModelBundleDependencies `json:"dependencies"`
+	Objects      []YamlObject            `json:"objects"`
+	AsstKinds    []AssociationKind       `json:"asst_kinds"`
+}
+
+// ModelBundleDependencies is the models and association kinds that a model bundle depends on.
+type ModelBundleDependencies struct {
+	ObjectIDs   []string `json:"bk_obj_id"`
+	AsstKindIDs []string `json:"bk_asst_id"`
+}
+
+// Validate validate the model bundle and its models.
+func (b *ModelBundle) Validate() errors.RawErrorInfo {
+	if err := b.ModelBundleHeader.Validate(); err.ErrCode != 0 {
+		return err
+	}
+
+	if len(b.Objects) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"objects"}}
+	}
+
+	if len(b.Objects) > ModelBundleMaxObjects {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"objects", ModelBundleMaxObjects},
+		}
+	}
+
+	objIDs := make(map[string]struct{})
+	for index := range b.Objects {
+		if err := b.Objects[index].Validate(); err.ErrCode != 0 {
+			return err
+		}
+
+		if _, exists := objIDs[b.Objects[index].ObjectID]; exists {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommDuplicateItem, Args: []interface{}{
+				b.Objects[index].ObjectID}}
+		}
+		objIDs[b.Objects[index].ObjectID] = struct{}{}
+	}
+
+	for _, kind := range b.AsstKinds {
+		if len(kind.AssociationKindID) == 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet,
+				Args: []interface{}{common.AssociationKindIDField}}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// CollectDependencies collects the models and association kinds that are referred by the associations of the
+// bundle's models but are not contained in the bundle, the declared dependencies are not trusted since the bundle
+// may be edited by hand.
+func (b *ModelBundle) CollectDependencies() ModelBundleDependencies {
+	objIDs := make(map[string]struct{})
+	for _, obj := range b.Objects {
+		objIDs[obj.ObjectID] = struct{}{}
+	}
+
+	kindIDs := make(map[string]struct{})
+	for _, kind := range b.AsstKinds {
+		kindIDs[kind.AssociationKindID] = struct{}{}
+	}
+
+	depObjIDs := make(map[string]struct{})
+	depKindIDs := make(map[string]struct{})
+	for _, obj := range b.Objects {
+		for _, asst := range obj.ObjectAsst {
+			for _, objID := range []string{asst.ObjectID, asst.AsstObjID} {
+				if _, exists := objIDs[objID]; !exists && len(objID) > 0 {
+					depObjIDs[objID] = struct{}{}
+				}
+			}
+
+			if _, exists := kindIDs[asst.AsstKindID]; !exists && len(asst.AsstKindID) > 0 {
+				depKindIDs[asst.AsstKindID] = struct{}{}
+			}
+		}
+	}
+
+	return ModelBundleDependencies{ObjectIDs: sortedKeys(depObjIDs), AsstKindIDs: sortedKeys(depKindIDs)}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ModelBundleCheckResult is the result of checking whether a model bundle can be installed.
+type ModelBundleCheckResult struct {
+	ModelBundleHeader `json:",inline"`
+	Installable       bool `json:"installable"`
+	// ConflictObjectIDs the models in the bundle that already exist.
+	ConflictObjectIDs []string `json:"conflict_bk_obj_id"`
+	// MissingObjectIDs the models that the bundle depends on but do not exist.
+	MissingObjectIDs []string `json:"missing_bk_obj_id"`
+	// MissingAsstKindIDs the association kinds that the bundle depends on but do not exist.
+	MissingAsstKindIDs []string `json:"missing_bk_asst_id"`
+}
+
+// ModelBundleInstallResult is the result of installing a model bundle.
+type ModelBundleInstallResult struct {
+	ModelBundleHeader `json:",inline"`
+	Objects           []Object `json:"objects"`
+}
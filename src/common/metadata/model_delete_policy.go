@@ -0,0 +1,105 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// DeletePolicy defines how the related data is handled when a model or a model attribute is deleted.
+type DeletePolicy string
+
+const (
+	// DeletePolicyBlock reject the deletion if the model still has instances or the attribute still has data.
+	DeletePolicyBlock DeletePolicy = "block"
+	// DeletePolicyArchive delete all the instances of the model in background, the deleted instances are archived
+	// as usual, then delete the model itself. only used for model deletion.
+	DeletePolicyArchive DeletePolicy = "archive"
+	// DeletePolicyDropData delete the attribute and its data in the instances in background.
+	// only used for model attribute deletion.
+	DeletePolicyDropData DeletePolicy = "drop_data"
+)
+
+// ModelDeletePolicies the policies that can be used to delete a model.
+var ModelDeletePolicies = []DeletePolicy{DeletePolicyBlock, DeletePolicyArchive}
+
+// AttributeDeletePolicies the policies that can be used to delete a model attribute.
+var AttributeDeletePolicies = []DeletePolicy{DeletePolicyBlock, DeletePolicyDropData}
+
+// DeleteWithPolicyOption is the option to preview or execute a deletion with a cascade policy.
+type DeleteWithPolicyOption struct {
+	// Policy the cascade policy, default is block.
+	Policy DeletePolicy `json:"policy"`
+	// BizID the model's business id, 0 means public model.
+	BizID int64 `json:"bk_biz_id"`
+}
+
+// Validate validate the policy with the policies that are supported by the resource.
+func (d *DeleteWithPolicyOption) Validate(supported []DeletePolicy) errors.RawErrorInfo {
+	if len(d.Policy) == 0 {
+		d.Policy = DeletePolicyBlock
+	}
+
+	for _, policy := range supported {
+		if policy == d.Policy {
+			return errors.RawErrorInfo{}
+		}
+	}
+
+	return errors.RawErrorInfo{
+		ErrCode: common.CCErrCommParamsInvalid,
+		Args:    []interface{}{"policy"},
+	}
+}
+
+// DeletePreview is the preview result of a deletion with a cascade policy.
+type DeletePreview struct {
+	// Policy the policy the preview is computed with.
+	Policy DeletePolicy `json:"policy"`
+	// ObjID the model the deletion related to.
+	ObjID string `json:"bk_obj_id"`
+	// PropertyID the attribute to be deleted, only set for model attribute deletion.
+	PropertyID string `json:"bk_property_id,omitempty"`
+	// InstanceCount the count of instances of the model, or the instances which has data of the attribute.
+	InstanceCount int64 `json:"instance_count"`
+	// AssociationCount the count of model associations related to the model.
+	AssociationCount int64 `json:"association_count"`
+	// Blocked whether the deletion will be rejected with this policy.
+	Blocked bool `json:"blocked"`
+	// Reason the reason why the deletion is rejected.
+	Reason string `json:"reason,omitempty"`
+	// Async whether the deletion will be executed as a background task.
+	Async bool `json:"async"`
+	// Policies all the policies that can be used to delete this resource.
+	Policies []DeletePolicy `json:"policies"`
+}
+
+// DeleteWithPolicyResult is the result of a deletion with a cascade policy.
+type DeleteWithPolicyResult struct {
+	Policy DeletePolicy `json:"policy"`
+	// TaskID the background task's id which can be used to track the deletion, empty when the deletion is
+	// executed synchronously.
+	TaskID string `json:"task_id,omitempty"`
+}
+
+// ModelDeleteCascadeTask is the sub task data of the background model/attribute deletion task.
+type ModelDeleteCascadeTask struct {
+	Policy DeletePolicy `json:"policy"`
+	ObjID  string       `json:"bk_obj_id"`
+	// ModelID the id of the model to be deleted.
+	ModelID int64 `json:"model_id,omitempty"`
+	// AttrID the id of the attribute to be deleted.
+	AttrID int64 `json:"attr_id,omitempty"`
+	BizID  int64 `json:"bk_biz_id"`
+}
@@ -73,6 +73,9 @@ type Object struct {
 	Modifier    string `field:"modifier" json:"modifier" bson:"modifier" mapstructure:"modifier"`
 	CreateTime  *Time  `field:"create_time" json:"create_time" bson:"create_time" mapstructure:"create_time"`
 	LastTime    *Time  `field:"last_time" json:"last_time" bson:"last_time" mapstructure:"last_time"`
+
+	// Lifecycle the lifecycle of the model, the model is active if it is not set
+	Lifecycle *SchemaLifecycle `json:"bk_lifecycle,omitempty" bson:"bk_lifecycle,omitempty"`
 }
 
 // GetDefaultInstPropertyName get default inst
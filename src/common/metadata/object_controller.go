@@ -86,6 +86,8 @@ type UpdateGroupCondition struct {
 		IsCollapse *bool   `field:"is_collapse" json:"is_collapse,omitempty"`
 		Name       *string `field:"bk_group_name" json:"bk_group_name,omitempty"`
 		Index      *int64  `field:"bk_group_index" json:"bk_group_index,omitempty"`
+		// DisplayRules the display rules to replace the group's, an empty array removes them.
+		DisplayRules *GroupDisplayRules `field:"display_rules" json:"display_rules,omitempty"`
 	} `json:"data"`
 }
 
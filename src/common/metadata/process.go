@@ -350,19 +350,44 @@ func (o *UpdateServiceInstanceOption) Validate() (rawError cErr.RawErrorInfo) {
 			}
 		}
 
-		// so far, only allow to update service instance name
-		if len(inst.Update) == 0 || len(inst.Update) > 1 {
+		// so far, only allow to update service instance name and config template references
+		if len(inst.Update) == 0 {
 			return cErr.RawErrorInfo{
 				ErrCode: common.CCErrCommParamsInvalid,
-				Args:    []interface{}{"can only update service instance name"},
+				Args:    []interface{}{"can only update service instance name or config templates"},
 			}
 		}
 
-		instName, ok := inst.Update[common.BKFieldName].(string)
+		for field := range inst.Update {
+			if field != common.BKFieldName && field != common.BKConfigTemplatesField {
+				return cErr.RawErrorInfo{
+					ErrCode: common.CCErrCommParamsInvalid,
+					Args:    []interface{}{"can only update service instance name or config templates"},
+				}
+			}
+		}
+
+		if configTemplates, exists := inst.Update[common.BKConfigTemplatesField]; exists {
+			refs, err := parseConfigTemplateRefs(configTemplates)
+			if err != nil {
+				return cErr.RawErrorInfo{
+					ErrCode: common.CCErrCommParamsInvalid,
+					Args:    []interface{}{common.BKConfigTemplatesField},
+				}
+			}
+			inst.Update[common.BKConfigTemplatesField] = refs
+		}
+
+		name, exists := inst.Update[common.BKFieldName]
+		if !exists {
+			continue
+		}
+
+		instName, ok := name.(string)
 		if !ok {
 			return cErr.RawErrorInfo{
 				ErrCode: common.CCErrCommParamsInvalid,
-				Args:    []interface{}{"can only update service instance name"},
+				Args:    []interface{}{"service instance name must be a string"},
 			}
 		}
 		if len(instName) == 0 {
@@ -982,6 +1007,13 @@ type Process struct {
 	StartParamRegex   *string        `field:"bk_start_param_regex" json:"bk_start_param_regex" bson:"bk_start_param_regex" structs:"bk_start_param_regex" mapstructure:"bk_start_param_regex"`
 	ServiceInstanceID int64          `field:"service_instance_id" json:"service_instance_id" bson:"service_instance_id" mapstructure:"service_instance_id"`
 	BindInfo          []ProcBindInfo `field:"bind_info" json:"bind_info" bson:"bind_info" structs:"bind_info" mapstructure:"bind_info"`
+
+	// Commands and Envs are the structured control data of the process consumed by the deployment tooling.
+	Commands *ProcCommands `field:"bk_proc_commands" json:"bk_proc_commands" bson:"bk_proc_commands" structs:"bk_proc_commands" mapstructure:"bk_proc_commands"`
+	Envs     ProcEnvs      `field:"bk_proc_envs" json:"bk_proc_envs" bson:"bk_proc_envs" structs:"bk_proc_envs" mapstructure:"bk_proc_envs"`
+
+	// ConfigTemplates the config template references of the process, which are synced from the process template.
+	ConfigTemplates ConfigTemplateRefs `field:"bk_config_templates" json:"bk_config_templates" bson:"bk_config_templates" structs:"bk_config_templates" mapstructure:"bk_config_templates"`
 }
 
 // Map TODO
@@ -1015,6 +1047,9 @@ func (p *Process) Map() map[string]interface{} {
 		common.CreateTimeField:          p.CreateTime,
 		common.LastTimeField:            p.LastTime,
 		common.BKServiceInstanceIDField: p.ServiceInstanceID,
+		common.BKProcCommandsField:      p.Commands,
+		common.BKProcEnvsField:          p.Envs,
+		common.BKConfigTemplatesField:   p.ConfigTemplates,
 	}
 
 	return procMap
@@ -1219,6 +1254,9 @@ func (pt *ProcessTemplate) NewProcess(bizID, svcInstID int64, supplierAccount st
 	processInstance.Description = property.Description.Value
 	processInstance.StartParamRegex = property.StartParamRegex.Value
 	processInstance.ServiceInstanceID = svcInstID
+	if property.ConfigTemplates.Value != nil {
+		processInstance.ConfigTemplates = *property.ConfigTemplates.Value
+	}
 
 	var err error
 	processInstance.BindInfo, err = property.BindInfo.NewProcBindInfo(host)
@@ -1270,6 +1308,7 @@ func GetAllProcessPropertyFields() []string {
 	fields = append(fields, "bk_gateway_port")
 	fields = append(fields, "bk_gateway_protocol")
 	fields = append(fields, "bk_gateway_city")
+	fields = append(fields, common.BKConfigTemplatesField)
 
 	return fields
 }
@@ -1504,6 +1543,17 @@ func (pt *ProcessTemplate) ExtractChangeInfo(i *Process, host map[string]interfa
 		}
 	}
 
+	if IsAsDefaultValue(t.ConfigTemplates.AsDefaultValue) {
+		var configTemplates ConfigTemplateRefs
+		if t.ConfigTemplates.Value != nil {
+			configTemplates = *t.ConfigTemplates.Value
+		}
+		if !configTemplates.Equal(i.ConfigTemplates) {
+			process[common.BKConfigTemplatesField] = configTemplates
+			changed = true
+		}
+	}
+
 	bindInfo, bindInfoChanged, bindInfoIsNamePortChanged, err := t.BindInfo.ExtractChangeInfoBindInfo(i, host)
 	if err != nil {
 		return nil, false, err
@@ -1590,8 +1640,13 @@ func (pt *ProcessTemplate) ExtractEditableFields() []string {
 	if IsAsDefaultValue(property.StartCmd.AsDefaultValue) == false {
 		editableFields = append(editableFields, "start_cmd")
 	}
+	if IsAsDefaultValue(property.ConfigTemplates.AsDefaultValue) == false {
+		editableFields = append(editableFields, common.BKConfigTemplatesField)
+	}
 	//
 	editableFields = append(editableFields, common.BKProcBindInfo)
+	// the structured control data is not managed by the process template, so it is always editable
+	editableFields = append(editableFields, common.BKProcCommandsField, common.BKProcEnvsField)
 
 	return editableFields
 }
@@ -1691,6 +1746,19 @@ func (pt *ProcessTemplate) ExtractInstanceUpdateData(input *Process, host map[st
 			data["start_cmd"] = *input.StartCmd
 		}
 	}
+	if IsAsDefaultValue(property.ConfigTemplates.AsDefaultValue) == false {
+		if input.ConfigTemplates != nil {
+			data[common.BKConfigTemplatesField] = input.ConfigTemplates
+		}
+	}
+
+	// the structured control data is not managed by the process template, so it is updated as the instance's own
+	if input.Commands != nil {
+		data[common.BKProcCommandsField] = input.Commands
+	}
+	if input.Envs != nil {
+		data[common.BKProcEnvsField] = input.Envs
+	}
 
 	// bind info 每次都是全量更新
 	var err error
@@ -1731,6 +1799,8 @@ type ProcessProperty struct {
 	// GatewayCity     PropertyString   `field:"bk_gateway_city" json:"bk_gateway_city" bson:"bk_gateway_city"`
 
 	BindInfo ProcPropertyBindInfo `field:"bind_info" json:"bind_info" bson:"bind_info" structs:"bind_info" mapstructure:"bind_info"`
+
+	ConfigTemplates PropertyConfigTemplates `field:"bk_config_templates" json:"bk_config_templates" bson:"bk_config_templates"`
 }
 
 // Validate TODO
@@ -2034,6 +2104,10 @@ type ServiceInstance struct {
 	// the module that this service belongs to.
 	ModuleID int64 `field:"bk_module_id" json:"bk_module_id" bson:"bk_module_id"`
 
+	// ConfigTemplates the config template references of the service instance, which is used by the config
+	// distribution workflows together with the config templates of its processes.
+	ConfigTemplates ConfigTemplateRefs `field:"bk_config_templates" json:"bk_config_templates" bson:"bk_config_templates"`
+
 	Creator         string    `field:"creator" json:"creator" bson:"creator"`
 	Modifier        string    `field:"modifier" json:"modifier" bson:"modifier"`
 	CreateTime      time.Time `field:"create_time" json:"create_time" bson:"create_time"`
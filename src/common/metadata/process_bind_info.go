@@ -150,8 +150,11 @@ func (pbi *ProcPropertyBindInfo) Validate() (string, error) {
 		}
 		port := (*PropertyPortValue)(property.Std.Port.Value)
 
-		if err := port.Validate(); err != nil {
-			return fmt.Sprintf("%s[%d].%s", common.BKProcBindInfo, idx, common.BKPort), err
+		// the port with template variables is validated after the variables are resolved at sync time
+		if port == nil || !HasTemplateVariable(string(*port)) {
+			if err := port.Validate(); err != nil {
+				return fmt.Sprintf("%s[%d].%s", common.BKProcBindInfo, idx, common.BKPort), err
+			}
 		}
 		if err := property.Std.Protocol.Value.Validate(); err != nil {
 			return fmt.Sprintf("%s[%d].%s", common.BKProcBindInfo, idx, common.BKProtocol), err
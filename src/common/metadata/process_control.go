@@ -0,0 +1,147 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/util"
+)
+
+// the placeholders of the process command template, which are rendered by the deployment tooling with the value of
+// the process field of the same name.
+var procCommandPlaceholders = []string{
+	common.BKProcessNameField,
+	common.BKFuncName,
+	common.BKWorkPath,
+	common.BKProcPidFile,
+	common.BKUser,
+}
+
+var (
+	procCommandPlaceholderRegexp = regexp.MustCompile(`\{([^{}]*)\}`)
+	procEnvNameRegexp            = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+const (
+	// ProcCommandMaxLength the max length of the process command template.
+	ProcCommandMaxLength = 2000
+	// ProcCommandMaxTimeout the max seconds to wait for the process command to finish.
+	ProcCommandMaxTimeout = 10000
+	// ProcEnvMaxCount the max number of the process environment variables.
+	ProcEnvMaxCount = 100
+	// ProcEnvNameMaxLength the max length of the process environment variable name.
+	ProcEnvNameMaxLength = 128
+	// ProcEnvValueMaxLength the max length of the process environment variable value.
+	ProcEnvValueMaxLength = 2000
+)
+
+// ProcCommand is a structured process command, which is consumed by the deployment tooling instead of parsing the
+// free-text command fields.
+type ProcCommand struct {
+	// Template the command line, placeholders like {work_path} are rendered with the process field of the same name.
+	Template string `json:"template" bson:"template"`
+	// Timeout the seconds to wait for the command to finish, the process's timeout is used if it is not set.
+	Timeout int64 `json:"timeout" bson:"timeout"`
+}
+
+// Validate validate the process command, field is the field path of the command used in the error.
+func (c *ProcCommand) Validate(field string) errors.RawErrorInfo {
+	if c == nil {
+		return errors.RawErrorInfo{}
+	}
+
+	c.Template = strings.TrimSpace(c.Template)
+	if len(c.Template) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{field + ".template"}}
+	}
+
+	if utf8.RuneCountInString(c.Template) > ProcCommandMaxLength {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{field + ".template", ProcCommandMaxLength},
+		}
+	}
+
+	for _, match := range procCommandPlaceholderRegexp.FindAllStringSubmatch(c.Template, -1) {
+		if !util.InStrArr(procCommandPlaceholders, match[1]) {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{field + ".template"}}
+		}
+	}
+
+	if c.Timeout < 0 || c.Timeout > ProcCommandMaxTimeout {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{field + ".timeout"}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// ProcCommands the structured start/stop/reload command templates of the process, the command is not supported by
+// the process if it is not set.
+type ProcCommands struct {
+	Start  *ProcCommand `json:"start,omitempty" bson:"start,omitempty"`
+	Stop   *ProcCommand `json:"stop,omitempty" bson:"stop,omitempty"`
+	Reload *ProcCommand `json:"reload,omitempty" bson:"reload,omitempty"`
+}
+
+// Validate validate the process commands
+func (c *ProcCommands) Validate() errors.RawErrorInfo {
+	if c == nil {
+		return errors.RawErrorInfo{}
+	}
+
+	if rawErr := c.Start.Validate(common.BKProcCommandsField + ".start"); rawErr.ErrCode != 0 {
+		return rawErr
+	}
+
+	if rawErr := c.Stop.Validate(common.BKProcCommandsField + ".stop"); rawErr.ErrCode != 0 {
+		return rawErr
+	}
+
+	return c.Reload.Validate(common.BKProcCommandsField + ".reload")
+}
+
+// ProcEnvs the environment variables of the process, key is the variable name.
+type ProcEnvs map[string]string
+
+// Validate validate the process environment variables
+func (e ProcEnvs) Validate() errors.RawErrorInfo {
+	if len(e) > ProcEnvMaxCount {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{common.BKProcEnvsField, ProcEnvMaxCount},
+		}
+	}
+
+	for name, value := range e {
+		if len(name) > ProcEnvNameMaxLength || !procEnvNameRegexp.MatchString(name) {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{common.BKProcEnvsField + "." + name},
+			}
+		}
+
+		if utf8.RuneCountInString(value) > ProcEnvValueMaxLength {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommXXExceedLimit,
+				Args:    []interface{}{common.BKProcEnvsField + "." + name, ProcEnvValueMaxLength},
+			}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
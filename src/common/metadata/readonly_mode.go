@@ -0,0 +1,101 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+const (
+	// ReadOnlyBizMaxCount the max number of the businesses that can be set to read-only at the same time
+	ReadOnlyBizMaxCount = 1000
+	// ReadOnlyReasonMaxLength the max length of the reason of the read-only mode
+	ReadOnlyReasonMaxLength = 256
+)
+
+// ReadOnlyMode is the read-only maintenance mode of cmdb, the mutating requests are rejected by the services when it
+// is enabled for all the businesses or the business of the request, which is used during the migrations and the
+// incident response. the admin server is not restricted so that the repair operations can still be done.
+type ReadOnlyMode struct {
+	// Enabled all the businesses are read-only
+	Enabled bool `json:"enabled"`
+	// BizIDs the businesses that are read-only when the global mode is not enabled
+	BizIDs []int64 `json:"bk_biz_ids"`
+	// Reason why the data can not be modified, which is returned to the users
+	Reason     string    `json:"reason"`
+	Operator   string    `json:"operator"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// Validate validate the read-only mode, and remove the duplicate businesses.
+func (m *ReadOnlyMode) Validate() errors.RawErrorInfo {
+	m.Reason = strings.TrimSpace(m.Reason)
+	if (m.Enabled || len(m.BizIDs) > 0) && len(m.Reason) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"reason"}}
+	}
+
+	if utf8.RuneCountInString(m.Reason) > ReadOnlyReasonMaxLength {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"reason", ReadOnlyReasonMaxLength},
+		}
+	}
+
+	if len(m.BizIDs) > ReadOnlyBizMaxCount {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"bk_biz_ids", ReadOnlyBizMaxCount},
+		}
+	}
+
+	bizIDs := make([]int64, 0, len(m.BizIDs))
+	bizIDMap := make(map[int64]struct{}, len(m.BizIDs))
+	for _, bizID := range m.BizIDs {
+		if bizID <= 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"bk_biz_ids"}}
+		}
+		if _, exists := bizIDMap[bizID]; exists {
+			continue
+		}
+		bizIDMap[bizID] = struct{}{}
+		bizIDs = append(bizIDs, bizID)
+	}
+	m.BizIDs = bizIDs
+
+	return errors.RawErrorInfo{}
+}
+
+// IsBizReadOnly checks whether the business is read-only, which is also read-only when the global mode is enabled.
+func (m *ReadOnlyMode) IsBizReadOnly(bizID int64) bool {
+	if m.Enabled {
+		return true
+	}
+
+	for _, id := range m.BizIDs {
+		if id == bizID {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadOnlyModeResult is the result of the read-only mode
+type ReadOnlyModeResult struct {
+	BaseResp `json:",inline"`
+	Data     ReadOnlyMode `json:"data"`
+}
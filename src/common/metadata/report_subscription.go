@@ -0,0 +1,230 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"strings"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+
+	"github.com/robfig/cron"
+)
+
+// ReportType is the type of the report that can be subscribed.
+type ReportType string
+
+const (
+	// ReportTypeDynamicGroup the members of a dynamic group.
+	ReportTypeDynamicGroup ReportType = "dynamic_group"
+	// ReportTypeIdleHost the hosts in the idle set of a business.
+	ReportTypeIdleHost ReportType = "idle_host"
+	// ReportTypeChangeDigest the summary of the changes in a business.
+	ReportTypeChangeDigest ReportType = "change_digest"
+	// ReportTypeDrift the host attributes in a business that drift from the host apply rules of their modules.
+	ReportTypeDrift ReportType = "drift"
+)
+
+// ReportFormat is the file format of the report attached to the mail.
+type ReportFormat string
+
+const (
+	// ReportFormatCSV the report is attached as a csv file, it is the default format.
+	ReportFormatCSV ReportFormat = "csv"
+	// ReportFormatExcel the report is attached as an excel file.
+	ReportFormatExcel ReportFormat = "excel"
+)
+
+// ReportChannel is the channel that the report is delivered by, it is the msg_type of the blueking cmsi component.
+type ReportChannel string
+
+const (
+	// ReportChannelMail deliver the report by email, the report is attached as a csv or excel file.
+	ReportChannelMail ReportChannel = "mail"
+	// ReportChannelWeixin deliver the report by weixin, the report content is sent as text.
+	ReportChannelWeixin ReportChannel = "weixin"
+	// ReportChannelRtx deliver the report by rtx, the report content is sent as text.
+	ReportChannelRtx ReportChannel = "rtx"
+)
+
+const (
+	// ReportSubscriptionMaxReceivers the max number of receivers of a report subscription.
+	ReportSubscriptionMaxReceivers = 100
+)
+
+// ReportSubscription is a user's subscription of a periodic report.
+type ReportSubscription struct {
+	ID    int64  `json:"id" bson:"id"`
+	Name  string `json:"name" bson:"name"`
+	BizID int64  `json:"bk_biz_id" bson:"bk_biz_id"`
+	// ReportType the type of the report.
+	ReportType ReportType `json:"report_type" bson:"report_type"`
+	// DynamicGroupID the id of the dynamic group, only used by the dynamic group report.
+	DynamicGroupID string `json:"dynamic_group_id,omitempty" bson:"dynamic_group_id"`
+	// DigestPeriod the period that the change digest summarizes, only used by the change digest report.
+	DigestPeriod ChangeDigestPeriod `json:"digest_period,omitempty" bson:"digest_period"`
+	// Fields the fields of the report's columns, use the visible fields of the business's export profile, or the
+	// default fields of the report type if the business has no export profile of the model.
+	Fields []string `json:"fields" bson:"fields"`
+	// Format the file format of the report attached to the mail, csv is used if not set.
+	Format ReportFormat `json:"format,omitempty" bson:"format"`
+	// Schedule the standard cron spec of the report's delivery time, such as "0 9 * * 1".
+	Schedule string        `json:"schedule" bson:"schedule"`
+	Channel  ReportChannel `json:"channel" bson:"channel"`
+	// Receivers the usernames of the receivers, or the business roles like "role:ops" that are resolved to the users
+	// of the roles in the business when the report is delivered.
+	Receivers []string `json:"receivers" bson:"receivers"`
+	Enabled   bool     `json:"enabled" bson:"enabled"`
+	// LastSendTime the last time that the report is delivered.
+	LastSendTime *time.Time `json:"last_send_time,omitempty" bson:"last_send_time"`
+
+	Creator         string    `json:"creator" bson:"creator"`
+	Modifier        string    `json:"modifier" bson:"modifier"`
+	CreateTime      time.Time `json:"create_time" bson:"create_time"`
+	LastTime        time.Time `json:"last_time" bson:"last_time"`
+	SupplierAccount string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+}
+
+// Validate validate the report subscription
+func (r *ReportSubscription) Validate() errors.RawErrorInfo {
+	if len(r.Name) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKFieldName}}
+	}
+
+	if r.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	switch r.ReportType {
+	case ReportTypeDynamicGroup:
+		if len(r.DynamicGroupID) == 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"dynamic_group_id"}}
+		}
+	case ReportTypeIdleHost, ReportTypeDrift:
+	case ReportTypeChangeDigest:
+		if r.DigestPeriod.Duration() == 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"digest_period"}}
+		}
+	default:
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"report_type"}}
+	}
+
+	if _, err := cron.ParseStandard(r.Schedule); err != nil {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"schedule"}}
+	}
+
+	switch r.Channel {
+	case ReportChannelMail, ReportChannelWeixin, ReportChannelRtx:
+	default:
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"channel"}}
+	}
+
+	switch r.Format {
+	case "", ReportFormatCSV, ReportFormatExcel:
+	default:
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"format"}}
+	}
+
+	if len(r.Receivers) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"receivers"}}
+	}
+
+	if len(r.Receivers) > ReportSubscriptionMaxReceivers {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"receivers", ReportSubscriptionMaxReceivers},
+		}
+	}
+
+	for _, receiver := range r.Receivers {
+		if !strings.HasPrefix(receiver, BizRoleReceiverPrefix) {
+			continue
+		}
+
+		role := BizRole(strings.TrimPrefix(receiver, BizRoleReceiverPrefix))
+		if rawErr := role.Validate(); rawErr.ErrCode != 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"receivers"}}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// IsDue check if the report should be delivered at the time
+func (r *ReportSubscription) IsDue(now time.Time) bool {
+	if !r.Enabled {
+		return false
+	}
+
+	schedule, err := cron.ParseStandard(r.Schedule)
+	if err != nil {
+		return false
+	}
+
+	last := r.CreateTime
+	if r.LastSendTime != nil {
+		last = *r.LastSendTime
+	}
+
+	return !schedule.Next(last).After(now)
+}
+
+// MultipleReportSubscription is the report subscriptions search result.
+type MultipleReportSubscription struct {
+	Count int64                `json:"count"`
+	Info  []ReportSubscription `json:"info"`
+}
+
+// ReportSubscriptionResult is the response of a report subscription.
+type ReportSubscriptionResult struct {
+	BaseResp `json:",inline"`
+	Data     ReportSubscription `json:"data"`
+}
+
+// MultipleReportSubscriptionResult is the response of the report subscriptions search result.
+type MultipleReportSubscriptionResult struct {
+	BaseResp `json:",inline"`
+	Data     MultipleReportSubscription `json:"data"`
+}
+
+// ListReportSubscriptionOption is the option to list the report subscriptions of a business.
+type ListReportSubscriptionOption struct {
+	BizID int64    `json:"bk_biz_id"`
+	Page  BasePage `json:"page"`
+}
+
+// CmsiAttachment is the attachment of a mail sent by the blueking cmsi component.
+type CmsiAttachment struct {
+	Filename string `json:"filename"`
+	// Content the base64 encoded content of the attachment
+	Content string `json:"content"`
+}
+
+// CmsiMail is the mail sent by the blueking cmsi component.
+type CmsiMail struct {
+	// Receiver the receivers' usernames joined by comma
+	Receiver    string           `json:"receiver__username"`
+	Title       string           `json:"title"`
+	Content     string           `json:"content"`
+	Attachments []CmsiAttachment `json:"attachments,omitempty"`
+}
+
+// CmsiMsg is the message sent by the blueking cmsi component.
+type CmsiMsg struct {
+	MsgType ReportChannel `json:"msg_type"`
+	// Receiver the receivers' usernames joined by comma
+	Receiver string `json:"receiver__username"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+}
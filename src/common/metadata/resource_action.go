@@ -0,0 +1,211 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/util"
+)
+
+// ResourceActionParamType is the value type of a resource action's parameter.
+type ResourceActionParamType string
+
+const (
+	// ResourceActionParamString the parameter value is a string
+	ResourceActionParamString ResourceActionParamType = "string"
+	// ResourceActionParamInt the parameter value is an integer
+	ResourceActionParamInt ResourceActionParamType = "int"
+	// ResourceActionParamBool the parameter value is a boolean
+	ResourceActionParamBool ResourceActionParamType = "bool"
+	// ResourceActionParamEnum the parameter value is one of the options
+	ResourceActionParamEnum ResourceActionParamType = "enum"
+)
+
+const (
+	// ResourceActionPermissionFind the action requires the find permission of the instance
+	ResourceActionPermissionFind = "find"
+	// ResourceActionPermissionUpdate the action requires the update permission of the instance
+	ResourceActionPermissionUpdate = "update"
+	// ResourceActionDefaultTimeout the default timeout seconds of calling the external system
+	ResourceActionDefaultTimeout = 10
+	// ResourceActionMaxTimeout the max timeout seconds of calling the external system
+	ResourceActionMaxTimeout = 30
+)
+
+// ResourceActionParam is the schema of a resource action's parameter.
+type ResourceActionParam struct {
+	ID       string                  `json:"id" yaml:"id"`
+	Name     string                  `json:"name" yaml:"name"`
+	Type     ResourceActionParamType `json:"type" yaml:"type"`
+	Required bool                    `json:"required" yaml:"required"`
+	// Options the available values of the enum type parameter.
+	Options []string `json:"options,omitempty" yaml:"options"`
+}
+
+// ResourceAction is a custom action on the instances of a model, it is registered by config, and executed by
+// calling the external system, e.g. reboot a host by calling the job system.
+type ResourceAction struct {
+	ID          string                `json:"id" yaml:"id"`
+	Name        string                `json:"name" yaml:"name"`
+	ObjID       string                `json:"bk_obj_id" yaml:"bk_obj_id"`
+	Description string                `json:"description" yaml:"description"`
+	Params      []ResourceActionParam `json:"params" yaml:"params"`
+	// Permission the permission of the instance that is required to execute the action, find or update,
+	// default is update.
+	Permission string `json:"permission" yaml:"permission"`
+	// URL the address of the external system that executes the action.
+	URL string `json:"-" yaml:"url"`
+	// Method the http method to call the external system, default is POST.
+	Method string `json:"-" yaml:"method"`
+	// Timeout the timeout seconds to call the external system.
+	Timeout int `json:"-" yaml:"timeout"`
+}
+
+// Validate validate the resource action config, and set the default values.
+func (r *ResourceAction) Validate() error {
+	if len(r.ID) == 0 || len(r.Name) == 0 || len(r.ObjID) == 0 {
+		return fmt.Errorf("resource action id, name and bk_obj_id must be set")
+	}
+
+	if len(r.URL) == 0 {
+		return fmt.Errorf("resource action %s url is not set", r.ID)
+	}
+
+	switch r.Permission {
+	case "":
+		r.Permission = ResourceActionPermissionUpdate
+	case ResourceActionPermissionFind, ResourceActionPermissionUpdate:
+	default:
+		return fmt.Errorf("resource action %s permission %s is invalid", r.ID, r.Permission)
+	}
+
+	r.Method = strings.ToUpper(r.Method)
+	switch r.Method {
+	case "":
+		r.Method = http.MethodPost
+	case http.MethodPost, http.MethodPut:
+	default:
+		return fmt.Errorf("resource action %s method %s is not supported", r.ID, r.Method)
+	}
+
+	if r.Timeout == 0 {
+		r.Timeout = ResourceActionDefaultTimeout
+	}
+	if r.Timeout < 0 || r.Timeout > ResourceActionMaxTimeout {
+		return fmt.Errorf("resource action %s timeout %d exceeds %d", r.ID, r.Timeout, ResourceActionMaxTimeout)
+	}
+
+	paramIDs := make(map[string]struct{})
+	for _, param := range r.Params {
+		if len(param.ID) == 0 {
+			return fmt.Errorf("resource action %s has param without id", r.ID)
+		}
+		if _, exists := paramIDs[param.ID]; exists {
+			return fmt.Errorf("resource action %s param %s is duplicated", r.ID, param.ID)
+		}
+		paramIDs[param.ID] = struct{}{}
+
+		switch param.Type {
+		case ResourceActionParamString, ResourceActionParamInt, ResourceActionParamBool:
+		case ResourceActionParamEnum:
+			if len(param.Options) == 0 {
+				return fmt.Errorf("resource action %s enum param %s has no options", r.ID, param.ID)
+			}
+		default:
+			return fmt.Errorf("resource action %s param %s type %s is invalid", r.ID, param.ID, param.Type)
+		}
+	}
+
+	return nil
+}
+
+// ValidateParams validate the parameters of executing the action with the parameter schemas
+func (r *ResourceAction) ValidateParams(params mapstr.MapStr) errors.RawErrorInfo {
+	paramMap := make(map[string]ResourceActionParam)
+	for _, param := range r.Params {
+		paramMap[param.ID] = param
+
+		if _, exists := params[param.ID]; !exists && param.Required {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsNeedSet,
+				Args:    []interface{}{param.ID},
+			}
+		}
+	}
+
+	for key, value := range params {
+		param, exists := paramMap[key]
+		if !exists {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsIsInvalid,
+				Args:    []interface{}{key},
+			}
+		}
+
+		valid := false
+		switch param.Type {
+		case ResourceActionParamString:
+			_, valid = value.(string)
+		case ResourceActionParamInt:
+			_, err := util.GetInt64ByInterface(value)
+			valid = err == nil
+		case ResourceActionParamBool:
+			_, valid = value.(bool)
+		case ResourceActionParamEnum:
+			valid = util.InStrArr(param.Options, util.GetStrByInterface(value))
+		}
+
+		if !valid {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{key},
+			}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// ResourceActionConfig is the config file content that registers the resource actions.
+type ResourceActionConfig struct {
+	Actions []ResourceAction `yaml:"actions"`
+}
+
+// ExecuteResourceActionOption is the option to execute a resource action on an instance.
+type ExecuteResourceActionOption struct {
+	Params mapstr.MapStr `json:"params"`
+}
+
+// ResourceActionRequest is the request body sent to the external system that executes the action.
+type ResourceActionRequest struct {
+	ActionID string        `json:"action_id"`
+	ObjID    string        `json:"bk_obj_id"`
+	InstID   int64         `json:"bk_inst_id"`
+	InstName string        `json:"bk_inst_name"`
+	Operator string        `json:"operator"`
+	Params   mapstr.MapStr `json:"params"`
+}
+
+// ResourceActionResult is the result of executing a resource action.
+type ResourceActionResult struct {
+	// StatusCode the http status code returned by the external system.
+	StatusCode int `json:"status_code"`
+	// Response the response body returned by the external system.
+	Response string `json:"response"`
+}
@@ -26,6 +26,8 @@ type BaseResp struct {
 	Code        int            `json:"bk_error_code" mapstructure:"bk_error_code"`
 	ErrMsg      string         `json:"bk_error_msg" mapstructure:"bk_error_msg"`
 	Permissions *IamPermission `json:"permission" mapstructure:"permission"`
+	// Detail the machine-readable detail of the error, including its class, arguments and causes
+	Detail *errors.Detail `json:"bk_error_detail,omitempty" mapstructure:"bk_error_detail"`
 }
 
 // CCError 根据response返回的信息产生错误
@@ -33,7 +35,7 @@ func (br *BaseResp) CCError() errors.CCErrorCoder {
 	if br.Result {
 		return nil
 	}
-	return errors.New(br.Code, br.ErrMsg)
+	return errors.NewFromDetail(br.Code, br.ErrMsg, br.Detail)
 }
 
 // Error 用于错误处理
@@ -42,7 +44,7 @@ func (br *BaseResp) Error() error {
 	if br.Result {
 		return nil
 	}
-	return errors.New(br.Code, br.ErrMsg)
+	return errors.NewFromDetail(br.Code, br.ErrMsg, br.Detail)
 }
 
 // ToString TODO
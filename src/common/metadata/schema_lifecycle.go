@@ -0,0 +1,167 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// the lifecycle states of the models and attributes, which retire the schema gracefully.
+const (
+	// SchemaStateActive the model or attribute is used normally.
+	SchemaStateActive = "active"
+	// SchemaStateDeprecated the model or attribute can still be used, but the responses carry the warnings.
+	SchemaStateDeprecated = "deprecated"
+	// SchemaStateReadOnly the instances of the model or the attribute's values can be read but not written.
+	SchemaStateReadOnly = "read_only"
+	// SchemaStateHidden the model or attribute is read only, and is not listed in the model or attribute searches.
+	SchemaStateHidden = "hidden"
+)
+
+const (
+	// SchemaFieldLifecycle the field of the model's and attribute's lifecycle.
+	SchemaFieldLifecycle = "bk_lifecycle"
+	// SchemaLifecycleMaxSchedule the max number of the scheduled transitions of a lifecycle.
+	SchemaLifecycleMaxSchedule = 10
+	// SchemaLifecycleMaxMessageLength the max length of the lifecycle message.
+	SchemaLifecycleMaxMessageLength = 256
+)
+
+// IsSchemaState returns whether the state is a valid lifecycle state.
+func IsSchemaState(state string) bool {
+	switch state {
+	case SchemaStateActive, SchemaStateDeprecated, SchemaStateReadOnly, SchemaStateHidden:
+		return true
+	}
+	return false
+}
+
+// SchemaLifecycle is the lifecycle of a model or an attribute, the state changes to the scheduled state once the
+// scheduled time arrives, so that the users can be warned ahead of the retirement.
+type SchemaLifecycle struct {
+	State string `json:"state" bson:"state"`
+	// Message tells the users what to do with the retirement, e.g. use another attribute instead.
+	Message  string             `json:"message" bson:"message"`
+	Schedule []SchemaTransition `json:"schedule" bson:"schedule"`
+}
+
+// SchemaTransition is a scheduled transition of the lifecycle.
+type SchemaTransition struct {
+	State string `json:"state" bson:"state"`
+	Time  Time   `json:"time" bson:"time"`
+}
+
+// Validate validate the schema lifecycle, the scheduled transitions must be in time order, and the times are
+// converted to utc since the time is marshaled without the time zone.
+func (l *SchemaLifecycle) Validate() errors.RawErrorInfo {
+	if !IsSchemaState(l.State) {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"state"}}
+	}
+
+	if len(l.Message) > SchemaLifecycleMaxMessageLength {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"message", SchemaLifecycleMaxMessageLength},
+		}
+	}
+
+	if len(l.Schedule) > SchemaLifecycleMaxSchedule {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"schedule", SchemaLifecycleMaxSchedule},
+		}
+	}
+
+	for index, transition := range l.Schedule {
+		if !IsSchemaState(transition.State) {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{fmt.Sprintf("schedule[%d].state", index)},
+			}
+		}
+
+		if transition.Time.IsZero() || index > 0 && !transition.Time.After(l.Schedule[index-1].Time.Time) {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommParamsInvalid,
+				Args:    []interface{}{fmt.Sprintf("schedule[%d].time", index)},
+			}
+		}
+		l.Schedule[index].Time.Time = transition.Time.UTC()
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// EffectiveState returns the state of the lifecycle at the time, which is the state of the last arrived scheduled
+// transition, or the state of the lifecycle if none arrives. a nil lifecycle is always active.
+func (l *SchemaLifecycle) EffectiveState(now time.Time) string {
+	if l == nil {
+		return SchemaStateActive
+	}
+
+	state := l.State
+	for _, transition := range l.Schedule {
+		if transition.Time.After(now) {
+			break
+		}
+		state = transition.State
+	}
+
+	if len(state) == 0 {
+		return SchemaStateActive
+	}
+	return state
+}
+
+// NextTransition returns the next scheduled transition after the time, returns nil if there is none.
+func (l *SchemaLifecycle) NextTransition(now time.Time) *SchemaTransition {
+	if l == nil {
+		return nil
+	}
+
+	for index := range l.Schedule {
+		if l.Schedule[index].Time.After(now) {
+			return &l.Schedule[index]
+		}
+	}
+	return nil
+}
+
+// IsSchemaWritable returns whether the instances of the model or the attribute's values in the state can be written.
+func IsSchemaWritable(state string) bool {
+	return state != SchemaStateReadOnly && state != SchemaStateHidden
+}
+
+// Warning returns the warning of the model or attribute named by the subject if it is not active at the time, e.g.
+// `attribute "bk_os_bit" is deprecated, use bk_cpu_architecture instead, changes to read_only at 2026-01-01`, returns
+// empty if no warning is needed.
+func (l *SchemaLifecycle) Warning(subject string, now time.Time) string {
+	state := l.EffectiveState(now)
+	next := l.NextTransition(now)
+	if state == SchemaStateActive && next == nil {
+		return ""
+	}
+
+	warning := fmt.Sprintf("%s is %s", subject, state)
+	if len(l.Message) > 0 {
+		warning += ", " + l.Message
+	}
+	if next != nil {
+		warning += fmt.Sprintf(", changes to %s at %s", next.State, next.Time.UTC().Format(time.RFC3339))
+	}
+	return warning
+}
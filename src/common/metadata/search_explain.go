@@ -0,0 +1,48 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"time"
+
+	"configcenter/src/storage/dal/types"
+)
+
+// SearchExplain is the explanation of a search in the explain mode, which is used to diagnose the slow search
+// without reproducing it in the mongo shell.
+type SearchExplain struct {
+	Collection string `json:"collection"`
+	// Filter the mongo filter generated from the search condition.
+	Filter map[string]interface{} `json:"filter"`
+	Sort   string                 `json:"sort"`
+	// Plan the execution plan chosen by mongodb and its execution stats.
+	Plan *types.ExplainResult `json:"plan"`
+	// Timings the time costs of the search steps in order.
+	Timings []SearchExplainTiming `json:"timings"`
+}
+
+// SearchExplainTiming is the time cost of a search step.
+type SearchExplainTiming struct {
+	Step       string `json:"step"`
+	CostMillis int64  `json:"cost_millis"`
+}
+
+// AddTiming add the time cost of the search step which starts at the start time, it does nothing if the explain is
+// nil, so that the search steps can be timed whether it is in the explain mode or not.
+func (e *SearchExplain) AddTiming(step string, start time.Time) {
+	if e == nil {
+		return
+	}
+
+	e.Timings = append(e.Timings, SearchExplainTiming{Step: step, CostMillis: time.Since(start).Milliseconds()})
+}
@@ -0,0 +1,128 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+const (
+	// ServiceInstanceBatchDeleteMaxCount the max number of the service instances deleted by the batch deletion.
+	ServiceInstanceBatchDeleteMaxCount = 10000
+	// ServiceInstanceBatchDeleteDefaultChunkSize the default number of the service instances deleted in one
+	// transaction.
+	ServiceInstanceBatchDeleteDefaultChunkSize = 100
+)
+
+// ServiceInstanceBatchDeleteOption is the option to delete the service instances in a business by their ids or by
+// a filter, the processes of the service instances are deleted with them.
+type ServiceInstanceBatchDeleteOption struct {
+	BizID int64 `json:"bk_biz_id"`
+	// ServiceInstanceIDs the ids of the service instances to delete, it can not be used with Filter.
+	ServiceInstanceIDs []int64                           `json:"service_instance_ids"`
+	Filter             *ServiceInstanceBatchDeleteFilter `json:"filter"`
+	// ChunkSize the number of the service instances deleted in one transaction, the service instances in the failed
+	// chunk are not deleted, while the other chunks are not affected.
+	ChunkSize int `json:"chunk_size"`
+}
+
+// ServiceInstanceBatchDeleteFilter is the filter of the service instances to delete, the conditions are combined
+// with and, at least one of them must be set.
+type ServiceInstanceBatchDeleteFilter struct {
+	ModuleIDs         []int64 `json:"bk_module_ids"`
+	HostIDs           []int64 `json:"bk_host_ids"`
+	ServiceTemplateID int64   `json:"service_template_id"`
+}
+
+// Validate validate the service instance batch delete option, and set the default chunk size if it is not set.
+func (o *ServiceInstanceBatchDeleteOption) Validate() errors.RawErrorInfo {
+	if o.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if len(o.ServiceInstanceIDs) == 0 && o.Filter == nil {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet,
+			Args: []interface{}{"service_instance_ids or filter"}}
+	}
+
+	if len(o.ServiceInstanceIDs) > 0 && o.Filter != nil {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid,
+			Args: []interface{}{"service_instance_ids and filter can not be set at the same time"}}
+	}
+
+	if len(o.ServiceInstanceIDs) > ServiceInstanceBatchDeleteMaxCount {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommXXExceedLimit,
+			Args: []interface{}{"service_instance_ids", ServiceInstanceBatchDeleteMaxCount}}
+	}
+
+	for _, id := range o.ServiceInstanceIDs {
+		if id <= 0 {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid,
+				Args: []interface{}{"service_instance_ids"}}
+		}
+	}
+
+	if o.Filter != nil && len(o.Filter.ModuleIDs) == 0 && len(o.Filter.HostIDs) == 0 &&
+		o.Filter.ServiceTemplateID == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet,
+			Args: []interface{}{"filter.bk_module_ids, filter.bk_host_ids or filter.service_template_id"}}
+	}
+
+	if o.ChunkSize == 0 {
+		o.ChunkSize = ServiceInstanceBatchDeleteDefaultChunkSize
+	}
+
+	if o.ChunkSize < 0 || o.ChunkSize > common.BKMaxDeletePageSize {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommXXExceedLimit,
+			Args: []interface{}{"chunk_size", common.BKMaxDeletePageSize}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// ServiceInstanceBatchDeletePreview is what will be deleted by the service instance batch deletion.
+type ServiceInstanceBatchDeletePreview struct {
+	ServiceInstanceIDs []int64 `json:"service_instance_ids"`
+	ProcessIDs         []int64 `json:"bk_process_ids"`
+	// ServiceTemplates the service templates that the service instances are created by, the service instances not
+	// created by a service template are counted in the one with id 0.
+	ServiceTemplates []ServiceTemplateDeleteBinding `json:"service_templates"`
+}
+
+// ServiceTemplateDeleteBinding is the service instances and processes to be deleted that are bound to a service
+// template.
+type ServiceTemplateDeleteBinding struct {
+	ServiceTemplateID    int64 `json:"service_template_id"`
+	ServiceInstanceCount int   `json:"service_instance_count"`
+	// ProcessTemplateIDs the process templates that the processes to be deleted are created by.
+	ProcessTemplateIDs []int64 `json:"process_template_ids"`
+	ProcessCount       int     `json:"process_count"`
+}
+
+// ServiceInstanceDeleteResult is the deletion result of a service instance.
+type ServiceInstanceDeleteResult struct {
+	ServiceInstanceID int64  `json:"service_instance_id"`
+	Success           bool   `json:"success"`
+	Code              int    `json:"code"`
+	Message           string `json:"message"`
+}
+
+// ServiceInstanceBatchDeleteResult is the result of the service instance batch deletion, the results are in the
+// order of the service instance ids in the preview.
+type ServiceInstanceBatchDeleteResult struct {
+	Preview      ServiceInstanceBatchDeletePreview `json:"preview"`
+	SuccessCount int                               `json:"success_count"`
+	FailedCount  int                               `json:"failed_count"`
+	Results      []ServiceInstanceDeleteResult     `json:"results"`
+}
@@ -0,0 +1,156 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// the placeholders of the service instance naming template.
+const (
+	// SrvInstNamePlaceholderHostIP the inner ip of the service instance's host.
+	SrvInstNamePlaceholderHostIP = "{host_ip}"
+	// SrvInstNamePlaceholderModuleName the name of the service instance's module.
+	SrvInstNamePlaceholderModuleName = "{module_name}"
+	// SrvInstNamePlaceholderProcessName the name of the service instance's first process.
+	SrvInstNamePlaceholderProcessName = "{process_name}"
+	// SrvInstNamePlaceholderPort the port of the service instance's first process.
+	SrvInstNamePlaceholderPort = "{port}"
+	// SrvInstNamePlaceholderIndex the smallest positive number that makes the name unique in the module.
+	SrvInstNamePlaceholderIndex = "{index}"
+)
+
+const (
+	// SrvInstNameTemplateMaxLength the max length of the service instance naming template.
+	SrvInstNameTemplateMaxLength = 256
+	// SrvInstNameMaxIndex the max index of the service instance name in a module.
+	SrvInstNameMaxIndex = 100000
+	// SrvInstNameRenderMaxCount the max number of the service instances whose names are rendered at a time.
+	SrvInstNameRenderMaxCount = 500
+)
+
+var srvInstNamePlaceholderRegexp = regexp.MustCompile(`\{[^{}]*\}`)
+
+// SrvInstNameTemplate is a business's naming template of the service instances, which replaces the default name
+// `host ip + first process name + first process port` of the service instances in the business.
+type SrvInstNameTemplate struct {
+	BizID int64 `json:"bk_biz_id" bson:"bk_biz_id"`
+	// Template the name template with placeholders, e.g. `{module_name}-{host_ip}-{index}`.
+	Template string `json:"template" bson:"template"`
+
+	Modifier        string    `json:"modifier" bson:"modifier"`
+	LastTime        time.Time `json:"last_time" bson:"last_time"`
+	SupplierAccount string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+}
+
+// Validate validate the service instance naming template
+func (t *SrvInstNameTemplate) Validate() errors.RawErrorInfo {
+	if t.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	t.Template = strings.TrimSpace(t.Template)
+	if len(t.Template) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"template"}}
+	}
+
+	if utf8.RuneCountInString(t.Template) > SrvInstNameTemplateMaxLength {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"template", SrvInstNameTemplateMaxLength},
+		}
+	}
+
+	for _, placeholder := range srvInstNamePlaceholderRegexp.FindAllString(t.Template, -1) {
+		switch placeholder {
+		case SrvInstNamePlaceholderHostIP, SrvInstNamePlaceholderModuleName, SrvInstNamePlaceholderProcessName,
+			SrvInstNamePlaceholderPort, SrvInstNamePlaceholderIndex:
+		default:
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{placeholder}}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// HasIndex returns if the template has the index placeholder, the name without index can not be changed to avoid
+// the duplication in the module.
+func (t *SrvInstNameTemplate) HasIndex() bool {
+	return strings.Contains(t.Template, SrvInstNamePlaceholderIndex)
+}
+
+// SrvInstNameValues is the values of the placeholders of the service instance naming template.
+type SrvInstNameValues struct {
+	HostIP      string
+	ModuleName  string
+	ProcessName string
+	Port        string
+}
+
+// Render renders the service instance name with the values and the index, the placeholders without value are
+// replaced with empty strings.
+func (t *SrvInstNameTemplate) Render(values SrvInstNameValues, index int) string {
+	replacer := strings.NewReplacer(
+		SrvInstNamePlaceholderHostIP, values.HostIP,
+		SrvInstNamePlaceholderModuleName, values.ModuleName,
+		SrvInstNamePlaceholderProcessName, values.ProcessName,
+		SrvInstNamePlaceholderPort, values.Port,
+		SrvInstNamePlaceholderIndex, strconv.Itoa(index),
+	)
+	return replacer.Replace(t.Template)
+}
+
+// SrvInstNameTemplateOption is the option to find or delete the business's service instance naming template.
+type SrvInstNameTemplateOption struct {
+	BizID int64 `json:"bk_biz_id"`
+}
+
+// SrvInstNameTemplateResp is the response of the service instance naming template.
+type SrvInstNameTemplateResp struct {
+	BaseResp `json:",inline"`
+	Data     *SrvInstNameTemplate `json:"data"`
+}
+
+// RenderSrvInstNameOption is the option to render the names of the service instances again with the business's
+// current naming template, or with the default naming rule if the business has no naming template.
+type RenderSrvInstNameOption struct {
+	BizID              int64   `json:"bk_biz_id"`
+	ServiceInstanceIDs []int64 `json:"service_instance_ids"`
+}
+
+// Validate validate the render service instance name option
+func (o *RenderSrvInstNameOption) Validate() errors.RawErrorInfo {
+	if o.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if len(o.ServiceInstanceIDs) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"service_instance_ids"}}
+	}
+
+	if len(o.ServiceInstanceIDs) > SrvInstNameRenderMaxCount {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"service_instance_ids", SrvInstNameRenderMaxCount},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
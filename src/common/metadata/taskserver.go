@@ -28,6 +28,24 @@ type CreateTaskRequest struct {
 	InstID int64 `json:"bk_inst_id"`
 
 	Data []interface{} `json:"data"`
+
+	// Checkpoints the progress that each sub task of the data represents, e.g. the row range of an import job, it is
+	// optional, but must be of the same length as the data if set.
+	Checkpoints []APITaskCheckpoint `json:"checkpoints,omitempty"`
+}
+
+// APITaskCheckpoint is the progress of a job that is split into sub tasks, the job can be resumed after the last
+// checkpoint when it is failed, because the sub tasks before it are finished and are not executed again.
+type APITaskCheckpoint struct {
+	// SubTaskID the sub task that the checkpoint belongs to
+	SubTaskID string `json:"sub_task_id,omitempty" bson:"sub_task_id,omitempty"`
+	// ChunkID the index of the chunk of the job that the sub task handles
+	ChunkID int64 `json:"chunk_id" bson:"chunk_id"`
+	// StartRow and EndRow are the range of the rows in the chunk, the end row is included
+	StartRow int64 `json:"start_row" bson:"start_row"`
+	EndRow   int64 `json:"end_row" bson:"end_row"`
+	// LastTime the time that the checkpoint is reached
+	LastTime *time.Time `json:"last_time,omitempty" bson:"last_time,omitempty"`
 }
 
 // APITaskDetail task info detail
@@ -46,6 +64,8 @@ type APITaskDetail struct {
 	Status APITaskStatus `json:"status,omitempty" bson:"status"`
 	// Detail 子任务详情列表
 	Detail []APISubTaskDetail `json:"detail,omitempty" bson:"detail"`
+	// Checkpoint 最后一个执行成功的子任务对应的进度，任务失败后可以从该进度之后恢复执行
+	Checkpoint *APITaskCheckpoint `json:"checkpoint,omitempty" bson:"checkpoint,omitempty"`
 
 	// CreateTime 任务创建时间
 	CreateTime time.Time `json:"create_time,omitempty" bson:"create_time"`
@@ -59,6 +79,26 @@ type APISubTaskDetail struct {
 	Data      interface{}   `json:"data,omitempty" bson:"data"`
 	Status    APITaskStatus `json:"status,omitempty" bson:"status"`
 	Response  *Response     `json:"response,omitempty" bson:"response"`
+	// Checkpoint the progress that the sub task represents
+	Checkpoint *APITaskCheckpoint `json:"checkpoint,omitempty" bson:"checkpoint,omitempty"`
+}
+
+// APITaskFile is the data generated by a sub task of the task whose result is a file, e.g. the instances exported
+// by an export job. the file of the task is made up of its sub tasks' data in order, and it is kept until it
+// expires.
+type APITaskFile struct {
+	TaskID    string `json:"task_id" bson:"task_id"`
+	SubTaskID string `json:"sub_task_id" bson:"sub_task_id"`
+	// ChunkID the index of the sub task in the task
+	ChunkID  int64       `json:"chunk_id" bson:"chunk_id"`
+	Data     interface{} `json:"data" bson:"data"`
+	ExpireAt time.Time   `json:"expire_at" bson:"expire_at"`
+}
+
+// ListAPITaskFileResponse list api task file chunks response
+type ListAPITaskFileResponse struct {
+	BaseResp
+	Data []APITaskFile `json:"data"`
 }
 
 // APITaskSyncStatus api task sync status
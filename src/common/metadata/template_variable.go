@@ -0,0 +1,284 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/json"
+)
+
+const (
+	// TemplateVariableMaxCount the max number of the template variables bound on a module or a set.
+	TemplateVariableMaxCount = 50
+	// TemplateVariableValueMaxLength the max length of the template variable's value.
+	TemplateVariableValueMaxLength = 256
+	// TemplateVariableListMaxCount the max number of the instances whose template variables are listed at a time.
+	TemplateVariableListMaxCount = 500
+)
+
+var (
+	// templateVariableRegexp matches the template variable placeholders like `${port}` in the template values.
+	templateVariableRegexp = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]{0,63})\}`)
+	// templateVariableNameRegexp matches the valid template variable names.
+	templateVariableNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,63}$`)
+)
+
+// TemplateVariable is the template variables bound on a module or a set. The `${name}` placeholders in the string
+// values of its service template's process templates and attributes, or its set template's attributes, are replaced
+// with the bound values when the module or set is synchronized with its template, so that one template can serve
+// many similar instances which differ only in these values, e.g. the port offsets or the environment names.
+type TemplateVariable struct {
+	BizID int64 `json:"bk_biz_id" bson:"bk_biz_id"`
+	// ObjID the object id of the instance which the variables are bound on, can only be module or set.
+	ObjID     string            `json:"bk_obj_id" bson:"bk_obj_id"`
+	InstID    int64             `json:"bk_inst_id" bson:"bk_inst_id"`
+	Variables map[string]string `json:"variables" bson:"variables"`
+
+	Modifier        string    `json:"modifier" bson:"modifier"`
+	LastTime        time.Time `json:"last_time" bson:"last_time"`
+	SupplierAccount string    `json:"bk_supplier_account" bson:"bk_supplier_account"`
+}
+
+// Validate validate the template variables
+func (t *TemplateVariable) Validate() errors.RawErrorInfo {
+	if t.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if rawErr := validateTemplateVariableObjID(t.ObjID); rawErr.ErrCode != 0 {
+		return rawErr
+	}
+
+	if t.InstID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKInstIDField}}
+	}
+
+	if len(t.Variables) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"variables"}}
+	}
+
+	if len(t.Variables) > TemplateVariableMaxCount {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"variables", TemplateVariableMaxCount},
+		}
+	}
+
+	for name, value := range t.Variables {
+		if !templateVariableNameRegexp.MatchString(name) {
+			return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{name}}
+		}
+
+		if utf8.RuneCountInString(value) > TemplateVariableValueMaxLength {
+			return errors.RawErrorInfo{
+				ErrCode: common.CCErrCommXXExceedLimit,
+				Args:    []interface{}{name, TemplateVariableValueMaxLength},
+			}
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+func validateTemplateVariableObjID(objID string) errors.RawErrorInfo {
+	switch objID {
+	case common.BKInnerObjIDModule, common.BKInnerObjIDSet:
+		return errors.RawErrorInfo{}
+	case "":
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{common.BKObjIDField}}
+	default:
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKObjIDField}}
+	}
+}
+
+// TemplateVariableOption is the option to list or delete the template variables bound on the modules or sets.
+type TemplateVariableOption struct {
+	BizID   int64   `json:"bk_biz_id"`
+	ObjID   string  `json:"bk_obj_id"`
+	InstIDs []int64 `json:"bk_inst_ids"`
+}
+
+// Validate validate the template variable option
+func (o *TemplateVariableOption) Validate() errors.RawErrorInfo {
+	if o.BizID <= 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{common.BKAppIDField}}
+	}
+
+	if rawErr := validateTemplateVariableObjID(o.ObjID); rawErr.ErrCode != 0 {
+		return rawErr
+	}
+
+	if len(o.InstIDs) == 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"bk_inst_ids"}}
+	}
+
+	if len(o.InstIDs) > TemplateVariableListMaxCount {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"bk_inst_ids", TemplateVariableListMaxCount},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// TemplateVariableResp is the response of saving the template variables.
+type TemplateVariableResp struct {
+	BaseResp `json:",inline"`
+	Data     *TemplateVariable `json:"data"`
+}
+
+// TemplateVariablesResp is the response of listing the template variables.
+type TemplateVariablesResp struct {
+	BaseResp `json:",inline"`
+	Data     []TemplateVariable `json:"data"`
+}
+
+// HasTemplateVariable returns if the value contains any template variable placeholder.
+func HasTemplateVariable(value string) bool {
+	return templateVariableRegexp.MatchString(value)
+}
+
+// ResolveTemplateVariables replaces the template variable placeholders in the string values of the value, including
+// the ones nested in the maps and slices, with the bound variables. It returns the resolved value and the sorted
+// names of the referenced variables that are not bound, the placeholders of which are kept as they are.
+func ResolveTemplateVariables(value interface{}, variables map[string]string) (interface{}, []string) {
+	missing := make(map[string]struct{})
+	resolved := resolveTemplateVariables(value, variables, missing)
+
+	if len(missing) == 0 {
+		return resolved, nil
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return resolved, names
+}
+
+func resolveTemplateVariables(value interface{}, variables map[string]string,
+	missing map[string]struct{}) interface{} {
+
+	switch val := value.(type) {
+	case string:
+		return templateVariableRegexp.ReplaceAllStringFunc(val, func(placeholder string) string {
+			name := templateVariableRegexp.FindStringSubmatch(placeholder)[1]
+			bound, exists := variables[name]
+			if !exists {
+				missing[name] = struct{}{}
+				return placeholder
+			}
+			return bound
+		})
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			resolved[key] = resolveTemplateVariables(item, variables, missing)
+		}
+		return resolved
+	case []interface{}:
+		resolved := make([]interface{}, len(val))
+		for idx, item := range val {
+			resolved[idx] = resolveTemplateVariables(item, variables, missing)
+		}
+		return resolved
+	default:
+		return value
+	}
+}
+
+// ResolveVariables returns a copy of the process template whose properties' template variable placeholders are
+// replaced with the bound variables, the resolved properties are validated again since the placeholders skip the
+// format validation. If some referenced variables are not bound, the process template itself and the names of
+// these variables are returned.
+func (pt *ProcessTemplate) ResolveVariables(variables map[string]string) (*ProcessTemplate, []string, error) {
+	if pt.Property == nil {
+		return pt, nil, nil
+	}
+
+	raw, err := json.Marshal(pt.Property)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// most process templates have no variables, skip the resolving for them
+	if !bytes.Contains(raw, []byte("${")) {
+		return pt, nil, nil
+	}
+
+	var property interface{}
+	if err := json.Unmarshal(raw, &property); err != nil {
+		return nil, nil, err
+	}
+
+	resolved, missing := ResolveTemplateVariables(property, variables)
+	if len(missing) > 0 {
+		return pt, missing, nil
+	}
+
+	if raw, err = json.Marshal(resolved); err != nil {
+		return nil, nil, err
+	}
+
+	template := *pt
+	template.Property = new(ProcessProperty)
+	if err := json.Unmarshal(raw, template.Property); err != nil {
+		return nil, nil, err
+	}
+
+	if field, err := template.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("resolved process template %d field %s is invalid, err: %v", pt.ID, field, err)
+	}
+
+	return &template, nil, nil
+}
+
+// ResolveProcessTemplates resolves the template variables of the process templates, returns the resolved copies and
+// the sorted names of the referenced variables that are not bound by any of them.
+func ResolveProcessTemplates(templates []ProcessTemplate, variables map[string]string) ([]ProcessTemplate, []string,
+	error) {
+
+	resolved := make([]ProcessTemplate, len(templates))
+	missingMap := make(map[string]struct{})
+	for idx := range templates {
+		template, missing, err := templates[idx].ResolveVariables(variables)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, name := range missing {
+			missingMap[name] = struct{}{}
+		}
+		resolved[idx] = *template
+	}
+
+	if len(missingMap) == 0 {
+		return resolved, nil, nil
+	}
+
+	missing := make([]string, 0, len(missingMap))
+	for name := range missingMap {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	return resolved, missing, nil
+}
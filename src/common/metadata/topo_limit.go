@@ -0,0 +1,88 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/errors"
+)
+
+// TopoLimitUsageDefaultRatio the default usage ratio of the limits that the businesses are reported as approaching.
+const TopoLimitUsageDefaultRatio = 0.8
+
+// TopoLimitUsageOption is the option to find the businesses approaching the topology limits.
+type TopoLimitUsageOption struct {
+	// BizIDs the businesses to check, all the businesses are checked if not set.
+	BizIDs []int64 `json:"bk_biz_ids"`
+	// Ratio the usage ratio of the limit to report the business, in range (0, 1], default is 0.8.
+	Ratio float64 `json:"ratio"`
+}
+
+// Validate validate the topology limit usage option, and set the default ratio
+func (o *TopoLimitUsageOption) Validate() errors.RawErrorInfo {
+	if len(o.BizIDs) > common.BKMaxInstanceLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommXXExceedLimit,
+			Args:    []interface{}{"bk_biz_ids", common.BKMaxInstanceLimit},
+		}
+	}
+
+	if o.Ratio == 0 {
+		o.Ratio = TopoLimitUsageDefaultRatio
+	}
+
+	if o.Ratio < 0 || o.Ratio > 1 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"ratio"}}
+	}
+
+	return errors.RawErrorInfo{}
+}
+
+// TopoLimitUsageResult is the usage of the business topology limits.
+type TopoLimitUsageResult struct {
+	// Level the current number of the mainline levels, which is shared by all the businesses.
+	Level    int64 `json:"level"`
+	MaxLevel int64 `json:"max_level"`
+	// Usages the child node usages of the businesses reaching the usage ratio, sorted by the usage in descending order.
+	Usages []TopoChildNodeUsage `json:"usages"`
+}
+
+// TopoChildNodeUsage is the max number of the child nodes of the object under one parent node in the business.
+type TopoChildNodeUsage struct {
+	BizID int64  `json:"bk_biz_id"`
+	ObjID string `json:"bk_obj_id"`
+	// ParentID the parent node that has the most child nodes.
+	ParentID int64 `json:"bk_parent_id"`
+	Count    int64 `json:"count"`
+	Limit    int64 `json:"limit"`
+}
+
+// CountTopoChildNodesOption is the option to count the most child nodes under one parent node of each business.
+type CountTopoChildNodesOption struct {
+	BizIDs []int64 `json:"bk_biz_ids"`
+	// MinCount only the businesses whose max child node count reaches it are returned.
+	MinCount int64 `json:"min_count"`
+}
+
+// TopoChildNodeCount is the max number of the child nodes under one parent node in the business.
+type TopoChildNodeCount struct {
+	BizID    int64 `json:"bk_biz_id" bson:"bk_biz_id"`
+	ParentID int64 `json:"bk_parent_id" bson:"bk_parent_id"`
+	Count    int64 `json:"count" bson:"count"`
+}
+
+// TopoChildNodeCountResp is the response of counting the topology child nodes.
+type TopoChildNodeCountResp struct {
+	BaseResp `json:",inline"`
+	Data     []TopoChildNodeCount `json:"data"`
+}
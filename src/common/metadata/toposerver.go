@@ -119,6 +119,8 @@ func (option *PreviewBusinessSetRequest) Validate(allowNoLimit bool) ccErr.RawEr
 	op := &querybuilder.RuleOption{
 		NeedSameSliceElementType: true,
 		MaxSliceElementsCount:    querybuilder.DefaultMaxSliceElementsCount,
+		MaxValueBytes:            querybuilder.DefaultMaxValueBytes,
+		MaxStringLength:          querybuilder.DefaultMaxStringLength,
 		MaxConditionOrRulesCount: querybuilder.DefaultMaxConditionOrRulesCount,
 	}
 
@@ -188,6 +190,8 @@ func (option *QueryBusinessSetRequest) Validate(allowNoLimit bool) ccErr.RawErro
 	op := &querybuilder.RuleOption{
 		NeedSameSliceElementType: true,
 		MaxSliceElementsCount:    querybuilder.DefaultMaxSliceElementsCount,
+		MaxValueBytes:            querybuilder.DefaultMaxValueBytes,
+		MaxStringLength:          querybuilder.DefaultMaxStringLength,
 		MaxConditionOrRulesCount: querybuilder.DefaultMaxConditionOrRulesCount,
 	}
 
@@ -389,6 +393,8 @@ func (scope *BizSetScope) Validate() (*BizSetScopeParamsInfo, error) {
 	option := &querybuilder.RuleOption{
 		NeedSameSliceElementType: true,
 		MaxSliceElementsCount:    querybuilder.DefaultMaxSliceElementsCount,
+		MaxValueBytes:            querybuilder.DefaultMaxValueBytes,
+		MaxStringLength:          querybuilder.DefaultMaxStringLength,
 		MaxConditionOrRulesCount: querybuilder.DefaultMaxConditionOrRulesCount,
 	}
 
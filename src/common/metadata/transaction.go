@@ -20,12 +20,17 @@ type TxnOption struct {
 	// min value: 5 * time.Second
 	// default: 5min
 	Timeout time.Duration
+
+	// SuppressEvent marks the changes of the transaction to be excluded from the watch events, it is used by the
+	// repair or migration jobs so that the downstream systems do not react to their mass backfill operations.
+	SuppressEvent bool
 }
 
 // TxnCapable TODO
 type TxnCapable struct {
-	Timeout   time.Duration `json:"timeout"`
-	SessionID string        `json:"session_id"`
+	Timeout       time.Duration `json:"timeout"`
+	SessionID     string        `json:"session_id"`
+	SuppressEvent bool          `json:"suppress_event"`
 }
 
 // AbortTransactionResult abort transaction result
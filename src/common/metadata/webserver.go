@@ -149,6 +149,8 @@ type ExcelAssociation struct {
 	Operate      ExcelAssociationOperate `json:"operate"`
 	SrcPrimary   string                  `json:"src_primary_key"`
 	DstPrimary   string                  `json:"dst_primary_key"`
+	// AsstKindID the association kind of the row, it is optional and must be the kind of the association if set
+	AsstKindID string `json:"bk_asst_id,omitempty"`
 }
 
 // ObjectAsstIDStatisticsInfo TODO
@@ -61,6 +61,8 @@ type HealthMeta struct {
 	IsHealthy bool `json:"healthy"`
 	// messages which describes the health status
 	Message string `json:"message"`
+	// Degraded if some of the items are unavailable but the module keeps serving with the degraded features
+	Degraded bool `json:"degraded,omitempty"`
 
 	Items []HealthItem `json:"items"`
 }
@@ -73,6 +75,9 @@ type HealthItem struct {
 	IsHealthy bool `json:"healthy"`
 	// messages which describes the health status
 	Message string `json:"message"`
+	// Degraded if this item is unavailable but the module falls back to the degraded paths, the item is still
+	// regarded as healthy so that the module is not removed from the service
+	Degraded bool `json:"degraded,omitempty"`
 }
 
 // MetricMeta define the MetricMeta that shows the named metric
@@ -138,3 +143,14 @@ func NewHealthItem(name string, err error) HealthItem {
 	}
 	return mongoHealthy
 }
+
+// NewDegradedHealthItem build the HealthItem of the item that the module can work without, the item is degraded
+// instead of unhealthy if err is not nil
+func NewDegradedHealthItem(name string, err error) HealthItem {
+	item := HealthItem{Name: name, IsHealthy: true}
+	if err != nil {
+		item.Degraded = true
+		item.Message = "degraded: " + err.Error()
+	}
+	return item
+}
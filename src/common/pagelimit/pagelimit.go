@@ -0,0 +1,100 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package pagelimit validates the page of the list apis in one place. each endpoint has a default limit used when
+// the limit is not set and a max limit, they are configured by pageLimit in the common config, and no endpoint can
+// exceed the global hard limit, so that the unbounded list requests are rejected consistently.
+package pagelimit
+
+import (
+	"fmt"
+
+	"configcenter/src/common"
+	cc "configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+)
+
+// Policy is the page limit policy of an endpoint
+type Policy struct {
+	// DefaultLimit the limit used when the page limit is not set
+	DefaultLimit int
+	// MaxLimit the max page limit, it is never greater than the hard limit
+	MaxLimit int
+}
+
+// HardLimit returns the global hard limit of the page size, which is pageLimit.hardLimit or common.BKMaxPageSize
+func HardLimit() int {
+	if limit, err := cc.Int("pageLimit.hardLimit"); err == nil && limit > 0 {
+		return limit
+	}
+	return common.BKMaxPageSize
+}
+
+// GetPolicy returns the page limit policy of the endpoint. the policy configured by pageLimit.endpoints.<endpoint>
+// overrides the global pageLimit.defaultLimit and pageLimit.maxLimit, the max limit is capped by the hard limit and
+// the default limit is capped by the max limit.
+func GetPolicy(endpoint string) Policy {
+	hardLimit := HardLimit()
+	policy := Policy{DefaultLimit: common.BKDefaultLimit, MaxLimit: hardLimit}
+
+	for _, prefix := range []string{"pageLimit", fmt.Sprintf("pageLimit.endpoints.%s", endpoint)} {
+		if limit, err := cc.Int(prefix + ".defaultLimit"); err == nil && limit > 0 {
+			policy.DefaultLimit = limit
+		}
+		if limit, err := cc.Int(prefix + ".maxLimit"); err == nil && limit > 0 {
+			policy.MaxLimit = limit
+		}
+	}
+
+	if policy.MaxLimit > hardLimit {
+		policy.MaxLimit = hardLimit
+	}
+	if policy.DefaultLimit > policy.MaxLimit {
+		policy.DefaultLimit = policy.MaxLimit
+	}
+	return policy
+}
+
+// Validate validates the page of the endpoint by its policy, the default limit is set to the page if the limit is
+// not set.
+func Validate(endpoint string, page *metadata.BasePage) errors.RawErrorInfo {
+	return GetPolicy(endpoint).Validate(endpoint, page)
+}
+
+// Validate validates the page of the endpoint by the policy
+func (p Policy) Validate(endpoint string, page *metadata.BasePage) errors.RawErrorInfo {
+	if page == nil {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsNeedSet, Args: []interface{}{"page"}}
+	}
+
+	if page.Start < 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"page.start"}}
+	}
+
+	if page.Limit < 0 {
+		return errors.RawErrorInfo{ErrCode: common.CCErrCommParamsInvalid, Args: []interface{}{"page.limit"}}
+	}
+
+	if page.Limit == 0 {
+		page.Limit = p.DefaultLimit
+	}
+
+	if page.Limit > p.MaxLimit {
+		return errors.RawErrorInfo{
+			ErrCode: common.CCErrCommPageLimitExceedMax,
+			Args:    []interface{}{page.Limit, endpoint, p.MaxLimit},
+		}
+	}
+
+	return errors.RawErrorInfo{}
+}
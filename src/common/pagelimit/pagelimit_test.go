@@ -0,0 +1,59 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pagelimit
+
+import (
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	policy := Policy{DefaultLimit: 20, MaxLimit: 500}
+
+	page := &metadata.BasePage{}
+	if rawErr := policy.Validate("list_hosts", page); rawErr.ErrCode != 0 {
+		t.Fatalf("validate empty page failed, err: %v", rawErr)
+	}
+	if page.Limit != 20 {
+		t.Errorf("default limit is not set, limit: %d", page.Limit)
+	}
+
+	page = &metadata.BasePage{Start: 10, Limit: 500}
+	if rawErr := policy.Validate("list_hosts", page); rawErr.ErrCode != 0 {
+		t.Errorf("validate max limit page failed, err: %v", rawErr)
+	}
+
+	invalids := map[int]*metadata.BasePage{
+		common.CCErrCommPageLimitExceedMax: {Limit: 501},
+		common.CCErrCommParamsInvalid:      {Start: -1, Limit: 10},
+		common.CCErrCommParamsNeedSet:      nil,
+	}
+	for code, page := range invalids {
+		if rawErr := policy.Validate("list_hosts", page); rawErr.ErrCode != code {
+			t.Errorf("page %+v error code %d is not %d", page, rawErr.ErrCode, code)
+		}
+	}
+
+	if rawErr := policy.Validate("list_hosts", &metadata.BasePage{Limit: common.BKNoLimit}); rawErr.ErrCode == 0 {
+		t.Errorf("no limit page should be rejected")
+	}
+}
+
+func TestGetPolicyDefault(t *testing.T) {
+	policy := GetPolicy("list_hosts")
+	if policy.DefaultLimit != common.BKDefaultLimit || policy.MaxLimit != common.BKMaxPageSize {
+		t.Errorf("policy %+v is not the default one", policy)
+	}
+}
@@ -0,0 +1,297 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"fmt"
+)
+
+// Builder builds the query filter fluently instead of assembling the rules by hand, e.g.
+//
+//	NewBuilder().Field("bk_host_innerip").Equal("10.0.0.1").
+//		Or(NewBuilder().Field("bk_os_type").Equal("1"), NewBuilder().Field("bk_cloud_id").In([]int64{0, 1}))
+//
+// the rules added to a builder are combined by its condition, the builders added by And, Or and Not are nested as a
+// group. Build returns the validated query filter, or the first error of the rules.
+type Builder struct {
+	condition Condition
+	rules     []Rule
+}
+
+// NewBuilder create a builder whose rules are combined by AND
+func NewBuilder() *Builder {
+	return &Builder{condition: ConditionAnd, rules: make([]Rule, 0)}
+}
+
+// NewOrBuilder create a builder whose rules are combined by OR
+func NewOrBuilder() *Builder {
+	return &Builder{condition: ConditionOr, rules: make([]Rule, 0)}
+}
+
+// Field starts an atom rule of the field, the rule is added to the builder once its operator is set
+func (b *Builder) Field(field string) *FieldBuilder {
+	return &FieldBuilder{builder: b, field: field}
+}
+
+// Rule adds a rule to the builder, which is used for the rules that are not built by the builder
+func (b *Builder) Rule(rule Rule) *Builder {
+	if rule != nil {
+		b.rules = append(b.rules, rule)
+	}
+	return b
+}
+
+// And adds the rules of the builders as a group combined by AND
+func (b *Builder) And(builders ...*Builder) *Builder {
+	return b.group(ConditionAnd, builders)
+}
+
+// Or adds the rules of the builders as a group combined by OR
+func (b *Builder) Or(builders ...*Builder) *Builder {
+	return b.group(ConditionOr, builders)
+}
+
+// Not adds the rules of the builders as a group which matches the records that match none of them
+func (b *Builder) Not(builders ...*Builder) *Builder {
+	return b.group(ConditionNot, builders)
+}
+
+func (b *Builder) group(condition Condition, builders []*Builder) *Builder {
+	rules := make([]Rule, 0, len(builders))
+	for _, builder := range builders {
+		if builder == nil || len(builder.rules) == 0 {
+			continue
+		}
+		rules = append(rules, builder.rule())
+	}
+
+	switch {
+	case len(rules) == 0:
+	case len(rules) == 1 && condition != ConditionNot:
+		// a group of one rule is the rule itself, which saves a level of the depth
+		b.rules = append(b.rules, rules[0])
+	default:
+		b.rules = append(b.rules, CombinedRule{Condition: condition, Rules: rules})
+	}
+	return b
+}
+
+// rule returns the rule of the builder when it is nested in another builder
+func (b *Builder) rule() Rule {
+	if len(b.rules) == 1 {
+		return b.rules[0]
+	}
+	return CombinedRule{Condition: b.condition, Rules: b.rules}
+}
+
+// Build returns the query filter of the builder, the rules are validated without the limits of the elements count,
+// which is used for the filters built by the server itself.
+func (b *Builder) Build() (*QueryFilter, error) {
+	return b.BuildWithOption(&RuleOption{NeedSameSliceElementType: true})
+}
+
+// BuildWithOption returns the query filter of the builder, the rules are validated with the option.
+func (b *Builder) BuildWithOption(option *RuleOption) (*QueryFilter, error) {
+	filter := &QueryFilter{Rule: CombinedRule{Condition: b.condition, Rules: b.rules}}
+	if key, err := filter.Validate(option); err != nil {
+		return nil, fmt.Errorf("invalid key: %s, err: %v", key, err)
+	}
+
+	if filter.GetDeep() > MaxDeep {
+		return nil, fmt.Errorf("exceed max query condition deepth: %d", MaxDeep)
+	}
+	return filter, nil
+}
+
+// FieldBuilder builds an atom rule of the field, the operator methods add the rule to the builder and return it.
+type FieldBuilder struct {
+	builder *Builder
+	field   string
+}
+
+// Op adds the atom rule with the operator and value
+func (f *FieldBuilder) Op(operator Operator, value interface{}) *Builder {
+	f.builder.rules = append(f.builder.rules, AtomRule{Field: f.field, Operator: operator, Value: value})
+	return f.builder
+}
+
+// Equal adds the equal rule
+func (f *FieldBuilder) Equal(value interface{}) *Builder {
+	return f.Op(OperatorEqual, value)
+}
+
+// NotEqual adds the not equal rule
+func (f *FieldBuilder) NotEqual(value interface{}) *Builder {
+	return f.Op(OperatorNotEqual, value)
+}
+
+// In adds the in rule, the value must be an array
+func (f *FieldBuilder) In(value interface{}) *Builder {
+	return f.Op(OperatorIn, value)
+}
+
+// NotIn adds the not in rule, the value must be an array
+func (f *FieldBuilder) NotIn(value interface{}) *Builder {
+	return f.Op(OperatorNotIn, value)
+}
+
+// Less adds the less rule
+func (f *FieldBuilder) Less(value interface{}) *Builder {
+	return f.Op(OperatorLess, value)
+}
+
+// LessOrEqual adds the less or equal rule
+func (f *FieldBuilder) LessOrEqual(value interface{}) *Builder {
+	return f.Op(OperatorLessOrEqual, value)
+}
+
+// Greater adds the greater rule
+func (f *FieldBuilder) Greater(value interface{}) *Builder {
+	return f.Op(OperatorGreater, value)
+}
+
+// GreaterOrEqual adds the greater or equal rule
+func (f *FieldBuilder) GreaterOrEqual(value interface{}) *Builder {
+	return f.Op(OperatorGreaterOrEqual, value)
+}
+
+// DatetimeLess adds the datetime less rule, the value is a date string like "2006-01-02"
+func (f *FieldBuilder) DatetimeLess(value string) *Builder {
+	return f.Op(OperatorDatetimeLess, value)
+}
+
+// DatetimeLessOrEqual adds the datetime less or equal rule, the value is a date string like "2006-01-02"
+func (f *FieldBuilder) DatetimeLessOrEqual(value string) *Builder {
+	return f.Op(OperatorDatetimeLessOrEqual, value)
+}
+
+// DatetimeGreater adds the datetime greater rule, the value is a date string like "2006-01-02"
+func (f *FieldBuilder) DatetimeGreater(value string) *Builder {
+	return f.Op(OperatorDatetimeGreater, value)
+}
+
+// DatetimeGreaterOrEqual adds the datetime greater or equal rule, the value is a date string like "2006-01-02"
+func (f *FieldBuilder) DatetimeGreaterOrEqual(value string) *Builder {
+	return f.Op(OperatorDatetimeGreaterOrEqual, value)
+}
+
+// InCIDR adds the rule that the ip field is in the ipv4 cidr, e.g. "10.0.0.0/8"
+func (f *FieldBuilder) InCIDR(cidr string) *Builder {
+	return f.Op(OperatorInCIDR, cidr)
+}
+
+// Size adds the rule that the array field has exactly n elements
+func (f *FieldBuilder) Size(n int) *Builder {
+	return f.Op(OperatorSize, n)
+}
+
+// SizeGreaterOrEqual adds the rule that the array field has at least n elements
+func (f *FieldBuilder) SizeGreaterOrEqual(n int) *Builder {
+	return f.Op(OperatorSizeGreaterOrEqual, n)
+}
+
+// SizeLessOrEqual adds the rule that the array field has at most n elements
+func (f *FieldBuilder) SizeLessOrEqual(n int) *Builder {
+	return f.Op(OperatorSizeLessOrEqual, n)
+}
+
+// CompareField adds the rule that compares the field with another field of the same record by the operator, e.g.
+// Field("cpu_used").CompareField(OperatorGreater, "cpu_limit")
+func (f *FieldBuilder) CompareField(operator Operator, other string) *Builder {
+	return f.Op(OperatorFieldCompare, FieldCompareValue{Field: other, Operator: operator})
+}
+
+// WithinDays adds the rule that the time field is within the last n days
+func (f *FieldBuilder) WithinDays(days int) *Builder {
+	return f.Op(OperatorWithinDays, days)
+}
+
+// WithinHours adds the rule that the time field is within the last n hours
+func (f *FieldBuilder) WithinHours(hours int) *Builder {
+	return f.Op(OperatorWithinHours, hours)
+}
+
+// OlderThan adds the rule that the time field is older than n days
+func (f *FieldBuilder) OlderThan(days int) *Builder {
+	return f.Op(OperatorOlderThan, days)
+}
+
+// BeginsWith adds the begins with rule, the value is used as a regular expression
+func (f *FieldBuilder) BeginsWith(value string) *Builder {
+	return f.Op(OperatorBeginsWith, value)
+}
+
+// NotBeginsWith adds the not begins with rule, the value is used as a regular expression
+func (f *FieldBuilder) NotBeginsWith(value string) *Builder {
+	return f.Op(OperatorNotBeginsWith, value)
+}
+
+// Contains adds the contains rule, the value is used as a regular expression
+func (f *FieldBuilder) Contains(value string) *Builder {
+	return f.Op(OperatorContains, value)
+}
+
+// NotContains adds the not contains rule, the value is used as a regular expression
+func (f *FieldBuilder) NotContains(value string) *Builder {
+	return f.Op(OperatorNotContains, value)
+}
+
+// EndsWith adds the ends with rule, the value is used as a regular expression
+func (f *FieldBuilder) EndsWith(value string) *Builder {
+	return f.Op(OperatorsEndsWith, value)
+}
+
+// NotEndsWith adds the not ends with rule, the value is used as a regular expression
+func (f *FieldBuilder) NotEndsWith(value string) *Builder {
+	return f.Op(OperatorNotEndsWith, value)
+}
+
+// Regex adds the regex rule, the regular expression is validated when the filter is built
+func (f *FieldBuilder) Regex(value string) *Builder {
+	return f.Op(OperatorRegex, value)
+}
+
+// IRegex adds the case-insensitive regex rule
+func (f *FieldBuilder) IRegex(value string) *Builder {
+	return f.Op(OperatorIRegex, value)
+}
+
+// IsEmpty adds the is empty rule of the array field
+func (f *FieldBuilder) IsEmpty() *Builder {
+	return f.Op(OperatorIsEmpty, nil)
+}
+
+// IsNotEmpty adds the is not empty rule of the array field
+func (f *FieldBuilder) IsNotEmpty() *Builder {
+	return f.Op(OperatorIsNotEmpty, nil)
+}
+
+// IsNull adds the is null rule
+func (f *FieldBuilder) IsNull() *Builder {
+	return f.Op(OperatorIsNull, nil)
+}
+
+// IsNotNull adds the is not null rule
+func (f *FieldBuilder) IsNotNull() *Builder {
+	return f.Op(OperatorIsNotNull, nil)
+}
+
+// Exist adds the exist rule
+func (f *FieldBuilder) Exist() *Builder {
+	return f.Op(OperatorExist, nil)
+}
+
+// NotExist adds the not exist rule
+func (f *FieldBuilder) NotExist() *Builder {
+	return f.Op(OperatorNotExist, nil)
+}
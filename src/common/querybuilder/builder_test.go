@@ -0,0 +1,313 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	filter, err := querybuilder.NewBuilder().Field("bk_host_innerip").Equal("10.0.0.1").
+		Or(querybuilder.NewBuilder().Field("bk_os_type").Equal("1"),
+			querybuilder.NewBuilder().Field("bk_cloud_id").In([]int64{0, 1})).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, filter.GetDeep())
+
+	mgoFilter, errKey, err := filter.ToMgo()
+	assert.Nil(t, err)
+	assert.Empty(t, errKey)
+	expected := map[string]interface{}{
+		common.BKDBAND: []map[string]interface{}{
+			{"bk_host_innerip": map[string]interface{}{common.BKDBEQ: "10.0.0.1"}},
+			{common.BKDBOR: []map[string]interface{}{
+				{"bk_os_type": map[string]interface{}{common.BKDBEQ: "1"}},
+				{"bk_cloud_id": map[string]interface{}{common.BKDBIN: []int64{0, 1}}},
+			}},
+		},
+	}
+	assert.Equal(t, expected, mgoFilter)
+}
+
+func TestBuilderNestedSingleRule(t *testing.T) {
+	or := querybuilder.NewOrBuilder().Field("a").Equal(1).Field("b").Equal(2)
+	filter, err := querybuilder.NewBuilder().And(or).Field("c").NotIn([]int64{3}).Build()
+	assert.Nil(t, err)
+	// the group of a single builder is added without a new level
+	assert.Equal(t, 3, filter.GetDeep())
+}
+
+func TestBuilderInvalid(t *testing.T) {
+	_, err := querybuilder.NewBuilder().Build()
+	assert.NotNil(t, err)
+
+	_, err = querybuilder.NewBuilder().Field("a").In(1).Build()
+	assert.NotNil(t, err)
+
+	_, err = querybuilder.NewBuilder().Field("").Equal(1).Build()
+	assert.NotNil(t, err)
+
+	option := &querybuilder.RuleOption{MaxSliceElementsCount: 1}
+	_, err = querybuilder.NewBuilder().Field("a").In([]int64{1, 2}).BuildWithOption(option)
+	assert.NotNil(t, err)
+}
+
+func TestBuilderFieldOperators(t *testing.T) {
+	option := &querybuilder.RuleOption{
+		FieldOperators: map[string][]querybuilder.Operator{
+			"bk_host_innerip": {querybuilder.OperatorEqual, querybuilder.OperatorIn, querybuilder.OperatorBeginsWith},
+		},
+	}
+
+	_, err := querybuilder.NewBuilder().Field("bk_host_innerip").BeginsWith("10.0.").Field("bk_host_name").
+		Contains("web").BuildWithOption(option)
+	assert.Nil(t, err)
+
+	_, err = querybuilder.NewBuilder().Field("bk_cloud_id").Equal(0).
+		Or(querybuilder.NewBuilder().Field("bk_host_innerip").Contains("0.1")).BuildWithOption(option)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "rules[1].operator")
+	assert.Contains(t, err.Error(), "operator contains is not allowed on field bk_host_innerip")
+}
+
+func TestBuilderRegex(t *testing.T) {
+	filter, err := querybuilder.NewBuilder().Field("bk_host_name").IRegex("^web-[0-9]+$").Build()
+	assert.Nil(t, err)
+
+	mgoFilter, _, err := filter.ToMgo()
+	assert.Nil(t, err)
+	expected := map[string]interface{}{
+		common.BKDBAND: []map[string]interface{}{
+			{"bk_host_name": map[string]interface{}{common.BKDBLIKE: "^web-[0-9]+$", common.BKDBOPTIONS: "i"}},
+		},
+	}
+	assert.Equal(t, expected, mgoFilter)
+
+	where, args, _, err := filter.ToSQL(&querybuilder.SQLOption{Dialect: querybuilder.SQLDialectPostgreSQL})
+	assert.Nil(t, err)
+	assert.Equal(t, `("bk_host_name" ~* $1)`, where)
+	assert.Equal(t, []interface{}{"^web-[0-9]+$"}, args)
+
+	invalidPatterns := []string{"", "(a+)+$", "(x*y?)*", "(\\d{1,3}\\.){2,}", "a(", strings.Repeat("a", 257)}
+	for _, pattern := range invalidPatterns {
+		_, err = querybuilder.NewBuilder().Field("bk_host_name").Regex(pattern).Build()
+		assert.NotNil(t, err, pattern)
+	}
+
+	_, err = querybuilder.NewBuilder().Field("bk_host_name").Regex("^(web|db)-\\d+\\.idc[0-9]?$").Build()
+	assert.Nil(t, err)
+}
+
+func TestBuilderNot(t *testing.T) {
+	filter, err := querybuilder.NewBuilder().Field("a").Equal(1).
+		Not(querybuilder.NewBuilder().Field("b").Contains("x")).
+		Build()
+	assert.Nil(t, err)
+	// the not group of a single builder is still nested
+	assert.Equal(t, 3, filter.GetDeep())
+
+	mgoFilter, _, err := filter.ToMgo()
+	assert.Nil(t, err)
+	expected := map[string]interface{}{
+		common.BKDBAND: []map[string]interface{}{
+			{"a": map[string]interface{}{common.BKDBEQ: 1}},
+			{common.BKDBNOR: []map[string]interface{}{
+				{"b": map[string]interface{}{common.BKDBLIKE: "x", common.BKDBOPTIONS: "i"}},
+			}},
+		},
+	}
+	assert.Equal(t, expected, mgoFilter)
+
+	matcher := func(r querybuilder.AtomRule) bool { return r.Field == "a" }
+	assert.True(t, filter.Match(matcher))
+	assert.False(t, filter.Match(func(r querybuilder.AtomRule) bool { return true }))
+}
+
+func TestNegate(t *testing.T) {
+	a := querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 1}
+	b := querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorEqual, Value: 2}
+
+	not := querybuilder.Negate(a)
+	assert.Equal(t, querybuilder.CombinedRule{Condition: querybuilder.ConditionNot, Rules: []querybuilder.Rule{a}}, not)
+	assert.Equal(t, a, querybuilder.Negate(not))
+
+	or := querybuilder.CombinedRule{Condition: querybuilder.ConditionOr, Rules: []querybuilder.Rule{a, b}}
+	nor := querybuilder.CombinedRule{Condition: querybuilder.ConditionNot, Rules: []querybuilder.Rule{a, b}}
+	assert.Equal(t, nor, querybuilder.Negate(or))
+	assert.Equal(t, or, querybuilder.Negate(nor))
+
+	and := querybuilder.CombinedRule{Condition: querybuilder.ConditionAnd, Rules: []querybuilder.Rule{a, b}}
+	assert.Equal(t, querybuilder.CombinedRule{Condition: querybuilder.ConditionNot,
+		Rules: []querybuilder.Rule{and}}, querybuilder.Negate(and))
+}
+
+func TestBuilderRelativeTime(t *testing.T) {
+	before := time.Now()
+	filter, err := querybuilder.NewBuilder().Field("last_time").WithinDays(7).Field("create_time").OlderThan(30).
+		Build()
+	assert.Nil(t, err)
+
+	// the time boundary is resolved when the filter is converted
+	mgo, key, err := filter.ToMgo()
+	assert.Nil(t, err, key)
+	after := time.Now()
+
+	and := mgo[common.BKDBAND].([]map[string]interface{})
+	within := and[0]["last_time"].(map[string]interface{})[common.BKDBGTE].(time.Time)
+	assert.False(t, within.Before(before.AddDate(0, 0, -7)))
+	assert.False(t, within.After(after.AddDate(0, 0, -7)))
+
+	older := and[1]["create_time"].(map[string]interface{})[common.BKDBLT].(time.Time)
+	assert.False(t, older.Before(before.AddDate(0, 0, -30)))
+	assert.False(t, older.After(after.AddDate(0, 0, -30)))
+
+	rule := querybuilder.AtomRule{Field: "last_time", Operator: querybuilder.OperatorWithinHours, Value: 12}
+	assert.Equal(t, `{"range":{"last_time":{"from":"now-12h","include_lower":true,"include_upper":true,"to":null}}}`,
+		esSource(t, rule))
+
+	// the value should be a positive integer in the range of the max relative days
+	option := &querybuilder.RuleOption{MaxRelativeDays: 30}
+	invalid := []querybuilder.AtomRule{
+		{Field: "last_time", Operator: querybuilder.OperatorWithinDays, Value: 0},
+		{Field: "last_time", Operator: querybuilder.OperatorWithinDays, Value: 1.5},
+		{Field: "last_time", Operator: querybuilder.OperatorWithinDays, Value: "7"},
+		{Field: "last_time", Operator: querybuilder.OperatorOlderThan, Value: 31},
+		{Field: "last_time", Operator: querybuilder.OperatorWithinHours, Value: 721},
+	}
+	for _, rule := range invalid {
+		key, err := rule.Validate(option)
+		assert.NotNil(t, err, "rule: %+v", rule)
+		assert.Equal(t, "value", key)
+	}
+
+	valid := querybuilder.AtomRule{Field: "last_time", Operator: querybuilder.OperatorWithinHours, Value: 720}
+	_, err = valid.Validate(option)
+	assert.Nil(t, err)
+}
+
+func TestBuilderInCIDR(t *testing.T) {
+	testCases := []struct {
+		cidr    string
+		pattern string
+	}{
+		{cidr: "10.0.0.0/8", pattern: `(^|,)10\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}(,|$)`},
+		{cidr: "10.0.0.0/14", pattern: `(^|,)10\.(0|1|2|3)\.[0-9]{1,3}\.[0-9]{1,3}(,|$)`},
+		// the host bits of the ip are masked
+		{cidr: "172.16.5.130/30", pattern: `(^|,)172\.16\.5\.(128|129|130|131)(,|$)`},
+		{cidr: "192.168.1.1/32", pattern: `(^|,)192\.168\.1\.1(,|$)`},
+		{cidr: "0.0.0.0/0", pattern: `(^|,)[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}(,|$)`},
+	}
+
+	for _, testCase := range testCases {
+		filter, err := querybuilder.NewBuilder().Field("bk_host_innerip").InCIDR(testCase.cidr).Build()
+		assert.Nil(t, err, testCase.cidr)
+
+		mgo, key, err := filter.ToMgo()
+		assert.Nil(t, err, key)
+		and := mgo[common.BKDBAND].([]map[string]interface{})
+		assert.Equal(t, testCase.pattern, and[0]["bk_host_innerip"].(map[string]interface{})[common.BKDBLIKE],
+			testCase.cidr)
+	}
+
+	for _, cidr := range []string{"", "10.0.0.0", "10.0.0.0/33", "10.0.0.256/8", "fe80::/10"} {
+		_, err := querybuilder.NewBuilder().Field("bk_host_innerip").InCIDR(cidr).Build()
+		assert.NotNil(t, err, cidr)
+	}
+}
+
+func TestBuilderSize(t *testing.T) {
+	filter, err := querybuilder.NewBuilder().Field("bk_host_outerip").Size(2).Build()
+	assert.Nil(t, err)
+	mgo, key, err := filter.ToMgo()
+	assert.Nil(t, err, key)
+	and := mgo[common.BKDBAND].([]map[string]interface{})
+	assert.Equal(t, map[string]interface{}{common.BKDBSize: int64(2)}, and[0]["bk_host_outerip"])
+
+	// the size range is compared by $expr, and the non-array field does not match
+	filter, err = querybuilder.NewBuilder().Field("tags").SizeGreaterOrEqual(1).Build()
+	assert.Nil(t, err)
+	mgo, key, err = filter.ToMgo()
+	assert.Nil(t, err, key)
+	and = mgo[common.BKDBAND].([]map[string]interface{})
+	expected := map[string]interface{}{
+		common.BKDBCond: []interface{}{
+			map[string]interface{}{common.BKDBIsArray: "$tags"},
+			map[string]interface{}{common.BKDBGTE: []interface{}{map[string]interface{}{common.BKDBSize: "$tags"},
+				int64(1)}},
+			false,
+		},
+	}
+	assert.Equal(t, expected, and[0][common.BKDBExpr])
+
+	for _, value := range []interface{}{-1, 1.5, "2", nil, int64(1) << 40} {
+		rule := querybuilder.AtomRule{Field: "tags", Operator: querybuilder.OperatorSizeLessOrEqual, Value: value}
+		_, err := rule.Validate(&querybuilder.RuleOption{})
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestBuilderCompareField(t *testing.T) {
+	filter, err := querybuilder.NewBuilder().Field("cpu_used").CompareField(querybuilder.OperatorGreater, "cpu_limit").
+		Build()
+	assert.Nil(t, err)
+	mgo, key, err := filter.ToMgo()
+	assert.Nil(t, err, key)
+	and := mgo[common.BKDBAND].([]map[string]interface{})
+	expected := map[string]interface{}{
+		common.BKDBAND: []interface{}{
+			map[string]interface{}{common.BKDBGT: []interface{}{"$cpu_used", nil}},
+			map[string]interface{}{common.BKDBGT: []interface{}{"$cpu_limit", nil}},
+			map[string]interface{}{common.BKDBGT: []interface{}{"$cpu_used", "$cpu_limit"}},
+		},
+	}
+	assert.Equal(t, expected, and[0][common.BKDBExpr])
+	assert.Equal(t, []string{"cpu_used", "cpu_limit"}, filter.GetField())
+
+	option := &querybuilder.RuleOption{RuleFields: map[string]string{
+		"cpu_used":  common.FieldTypeInt,
+		"cpu_limit": common.FieldTypeFloat,
+		"name":      common.FieldTypeSingleChar,
+		"online":    common.FieldTypeBool,
+	}}
+	_, err = querybuilder.NewBuilder().Field("cpu_used").CompareField(querybuilder.OperatorLess, "cpu_limit").
+		BuildWithOption(option)
+	assert.Nil(t, err)
+
+	invalidValues := []interface{}{
+		// the compared field is not in the rule fields
+		querybuilder.FieldCompareValue{Field: "mem_used", Operator: querybuilder.OperatorLess},
+		// the field types are not comparable
+		querybuilder.FieldCompareValue{Field: "name", Operator: querybuilder.OperatorEqual},
+		querybuilder.FieldCompareValue{Field: "cpu_used", Operator: querybuilder.OperatorEqual},
+		querybuilder.FieldCompareValue{Field: "cpu_limit", Operator: querybuilder.OperatorIn},
+		map[string]interface{}{"field": "cpu_limit"},
+		"cpu_limit",
+	}
+	for _, value := range invalidValues {
+		rule := querybuilder.AtomRule{Field: "cpu_used", Operator: querybuilder.OperatorFieldCompare, Value: value}
+		_, err := rule.Validate(option)
+		assert.NotNil(t, err, value)
+	}
+
+	rule := querybuilder.AtomRule{Field: "online", Operator: querybuilder.OperatorFieldCompare,
+		Value: querybuilder.FieldCompareValue{Field: "online", Operator: querybuilder.OperatorEqual}}
+	_, err = rule.Validate(option)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,99 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// cidrOctetPattern matches any octet of the ipv4 address.
+const cidrOctetPattern = "[0-9]{1,3}"
+
+// validateCIDRType validates the value of the in_cidr operator, which should be an ipv4 cidr string.
+func validateCIDRType(value interface{}) error {
+	if err := validateNotEmptyStringType(value); err != nil {
+		return err
+	}
+
+	if _, err := parseIPv4CIDR(value.(string)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseIPv4CIDR parses the ipv4 cidr string, the host bits of the ip are masked.
+func parseIPv4CIDR(cidr string) (*net.IPNet, error) {
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cidr: %s", cidr)
+	}
+
+	if ip.To4() == nil {
+		return nil, fmt.Errorf("only ipv4 cidr is supported, cidr: %s", cidr)
+	}
+	return ipNet, nil
+}
+
+// cidrIPPattern generates the regular expression that matches the ipv4 addresses in the cidr, the octets covered by
+// the prefix are matched literally so that the expression stays short, and the octet split by the prefix is matched
+// with the alternation of its values, e.g. 10.0.0.0/14 is converted to 10\.(0|1|2|3)\.[0-9]{1,3}\.[0-9]{1,3}
+func cidrIPPattern(cidr string) (string, error) {
+	ipNet, err := parseIPv4CIDR(cidr)
+	if err != nil {
+		return "", err
+	}
+
+	ip := ipNet.IP.To4()
+	ones, _ := ipNet.Mask.Size()
+	octets := make([]string, net.IPv4len)
+	for idx := range octets {
+		prefixBits := ones - idx*8
+		switch {
+		case prefixBits >= 8:
+			octets[idx] = strconv.Itoa(int(ip[idx]))
+		case prefixBits <= 0:
+			octets[idx] = cidrOctetPattern
+		default:
+			values := make([]string, 1<<(8-prefixBits))
+			for offset := range values {
+				values[offset] = strconv.Itoa(int(ip[idx]) + offset)
+			}
+			octets[idx] = "(" + strings.Join(values, "|") + ")"
+		}
+	}
+
+	return strings.Join(octets, `\.`), nil
+}
+
+// cidrRegex returns the regular expression of the in_cidr operator, the field may be an ip array or the ips joined
+// with comma, so the ip is matched as the whole value or one of the comma separated values.
+func (r AtomRule) cidrRegex() (string, error) {
+	pattern, err := cidrIPPattern(r.Value.(string))
+	if err != nil {
+		return "", err
+	}
+	return "(^|,)" + pattern + "(,|$)", nil
+}
+
+// cidrLuceneRegex returns the lucene regular expression of the in_cidr operator, which always matches the entire
+// value and does not support the anchors.
+func (r AtomRule) cidrLuceneRegex() (string, error) {
+	pattern, err := cidrIPPattern(r.Value.(string))
+	if err != nil {
+		return "", err
+	}
+	return "(.*,)?" + pattern + "(,.*)?", nil
+}
@@ -0,0 +1,238 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultCompiledCacheTTL the default time that a compiled rule is kept in the cache.
+	DefaultCompiledCacheTTL = 10 * time.Minute
+	// DefaultCompiledCacheSize the default max number of the compiled rules in the cache.
+	DefaultCompiledCacheSize = 1000
+)
+
+// CompiledCacheOption is the option of the compiled rule cache.
+type CompiledCacheOption struct {
+	// Name the name of the cache, which is used as the label of the metrics.
+	Name string
+	// TTL the time that a compiled rule is kept in the cache after it is compiled.
+	TTL time.Duration
+	// MaxSize the max number of the compiled rules in the cache, the one expires first is evicted when it is full.
+	MaxSize int
+}
+
+// CompiledRule is a validated rule with its pre-built mongo condition.
+type CompiledRule struct {
+	Rule Rule
+	// relative the rule contains the relative time operators, whose mongo condition depends on the current time,
+	// so it is built every time instead of being cached.
+	relative  bool
+	mgoFilter map[string]interface{}
+	expireAt  time.Time
+}
+
+// MgoFilter returns the mongo condition of the compiled rule, the top level of the condition is copied so that the
+// caller can add other conditions to it, the nested conditions are shared and must not be changed.
+func (c *CompiledRule) MgoFilter() (map[string]interface{}, string, error) {
+	if c.relative {
+		return c.Rule.ToMgo()
+	}
+
+	filter := make(map[string]interface{}, len(c.mgoFilter)+2)
+	for key, value := range c.mgoFilter {
+		filter[key] = value
+	}
+	return filter, "", nil
+}
+
+// CompiledCache caches the compiled rules by the canonical hash of the rule tree and the validate option, so that
+// the same rules evaluated repeatedly are parsed and validated only once. It is safe for concurrent use.
+type CompiledCache struct {
+	opt     CompiledCacheOption
+	metrics *compiledCacheMetrics
+	lock    sync.RWMutex
+	rules   map[string]*CompiledRule
+}
+
+// NewCompiledCache new a compiled rule cache, the metrics are not collected if the register is nil.
+func NewCompiledCache(opt CompiledCacheOption, register prometheus.Registerer) *CompiledCache {
+	if opt.TTL <= 0 {
+		opt.TTL = DefaultCompiledCacheTTL
+	}
+	if opt.MaxSize <= 0 {
+		opt.MaxSize = DefaultCompiledCacheSize
+	}
+
+	c := &CompiledCache{
+		opt:   opt,
+		rules: make(map[string]*CompiledRule),
+	}
+	if register != nil {
+		c.metrics = newCompiledCacheMetrics(register)
+	}
+	return c
+}
+
+// Compile returns the compiled rule of the query filter, the query filter is validated with the option and
+// converted to the mongo condition if it is not cached. The validation error is not cached.
+func (c *CompiledCache) Compile(qf *QueryFilter, option *RuleOption) (*CompiledRule, string, error) {
+	if qf == nil || qf.Rule == nil {
+		return nil, "", fmt.Errorf("query filter is empty")
+	}
+
+	hash, err := compiledRuleHash(qf.Rule, option)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	c.lock.RLock()
+	compiled, exists := c.rules[hash]
+	c.lock.RUnlock()
+
+	if exists && now.Before(compiled.expireAt) {
+		c.collect("hit")
+		return compiled, "", nil
+	}
+	c.collect("miss")
+
+	if option != nil {
+		if key, err := qf.Validate(option); err != nil {
+			return nil, key, err
+		}
+	}
+
+	mgoFilter, key, err := qf.Rule.ToMgo()
+	if err != nil {
+		return nil, key, err
+	}
+
+	compiled = &CompiledRule{
+		Rule:      qf.Rule,
+		relative:  hasRelativeTimeRule(qf.Rule),
+		mgoFilter: mgoFilter,
+		expireAt:  now.Add(c.opt.TTL),
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, exists := c.rules[hash]; !exists && len(c.rules) >= c.opt.MaxSize {
+		c.evict(now)
+	}
+	c.rules[hash] = compiled
+	return compiled, "", nil
+}
+
+// evict removes the expired rules, or the rule expires first if none is expired, it must be called with the lock.
+func (c *CompiledCache) evict(now time.Time) {
+	var oldestHash string
+	var oldest time.Time
+	expired := 0
+	for hash, compiled := range c.rules {
+		if !now.Before(compiled.expireAt) {
+			delete(c.rules, hash)
+			expired++
+			continue
+		}
+
+		if len(oldestHash) == 0 || compiled.expireAt.Before(oldest) {
+			oldestHash, oldest = hash, compiled.expireAt
+		}
+	}
+
+	if expired > 0 {
+		c.collectEviction("expired", expired)
+		return
+	}
+
+	delete(c.rules, oldestHash)
+	c.collectEviction("capacity", 1)
+}
+
+// Len returns the number of the compiled rules in the cache, including the expired ones not evicted yet.
+func (c *CompiledCache) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return len(c.rules)
+}
+
+func (c *CompiledCache) collect(result string) {
+	if c.metrics != nil {
+		c.metrics.requests.WithLabelValues(c.opt.Name, result).Inc()
+	}
+}
+
+func (c *CompiledCache) collectEviction(reason string, count int) {
+	if c.metrics != nil {
+		c.metrics.evictions.WithLabelValues(c.opt.Name, reason).Add(float64(count))
+	}
+}
+
+// hasRelativeTimeRule checks if the rule tree contains the relative time operators.
+func hasRelativeTimeRule(rule Rule) bool {
+	switch r := rule.(type) {
+	case AtomRule:
+		switch r.Operator {
+		case OperatorWithinDays, OperatorWithinHours, OperatorOlderThan:
+			return true
+		}
+	case CombinedRule:
+		for _, subRule := range r.Rules {
+			if hasRelativeTimeRule(subRule) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type compiledCacheMetrics struct {
+	requests  *prometheus.CounterVec
+	evictions *prometheus.CounterVec
+}
+
+func newCompiledCacheMetrics(register prometheus.Registerer) *compiledCacheMetrics {
+	m := &compiledCacheMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cmdb_querybuilder_compiled_cache_requests_total",
+			Help: "total number of the compiled rule cache requests, the result is hit or miss.",
+		}, []string{"cache", "result"}),
+		evictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cmdb_querybuilder_compiled_cache_evictions_total",
+			Help: "total number of the compiled rules evicted from the cache, the reason is expired or capacity.",
+		}, []string{"cache", "reason"}),
+	}
+
+	if err := register.Register(m.requests); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			m.requests = are.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			panic(err)
+		}
+	}
+	if err := register.Register(m.evictions); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			m.evictions = are.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			panic(err)
+		}
+	}
+	return m
+}
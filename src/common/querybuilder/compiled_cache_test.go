@@ -0,0 +1,105 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"testing"
+	"time"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCompiledFilter(rules ...querybuilder.Rule) *querybuilder.QueryFilter {
+	return &querybuilder.QueryFilter{
+		Rule: querybuilder.CombinedRule{Condition: querybuilder.ConditionAnd, Rules: rules},
+	}
+}
+
+func TestCompiledCacheHit(t *testing.T) {
+	cache := querybuilder.NewCompiledCache(querybuilder.CompiledCacheOption{Name: "test"}, prometheus.NewRegistry())
+
+	a := querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 1}
+	b := querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorIn, Value: []string{"x", "y"}}
+
+	first, _, err := cache.Compile(newCompiledFilter(a, b), nil)
+	require.NoError(t, err)
+
+	// the sub rules in different orders share the same compiled rule
+	second, _, err := cache.Compile(newCompiledFilter(b, a), nil)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, cache.Len())
+
+	// the rules validated with different options are compiled separately
+	option := &querybuilder.RuleOption{MaxSliceElementsCount: 1}
+	_, _, err = cache.Compile(newCompiledFilter(a, b), option)
+	assert.Error(t, err)
+	assert.Equal(t, 1, cache.Len())
+
+	// the caller can add conditions to the mongo condition without changing the cached one
+	cond, _, err := first.MgoFilter()
+	require.NoError(t, err)
+	cond["c"] = 1
+	cond, _, err = second.MgoFilter()
+	require.NoError(t, err)
+	assert.NotContains(t, cond, "c")
+	assert.Contains(t, cond, "$and")
+}
+
+func TestCompiledCacheEviction(t *testing.T) {
+	cache := querybuilder.NewCompiledCache(querybuilder.CompiledCacheOption{TTL: 50 * time.Millisecond, MaxSize: 2},
+		nil)
+
+	for value := 0; value < 3; value++ {
+		rule := querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: value}
+		_, _, err := cache.Compile(newCompiledFilter(rule), nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, cache.Len())
+
+	rule := querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 2}
+	first, _, err := cache.Compile(newCompiledFilter(rule), nil)
+	require.NoError(t, err)
+
+	time.Sleep(60 * time.Millisecond)
+	second, _, err := cache.Compile(newCompiledFilter(rule), nil)
+	require.NoError(t, err)
+	assert.NotSame(t, first, second)
+
+	// the expired rules are evicted when the cache is full
+	other := querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorEqual, Value: 1}
+	_, _, err = cache.Compile(newCompiledFilter(other), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestCompiledCacheRelativeTime(t *testing.T) {
+	cache := querybuilder.NewCompiledCache(querybuilder.CompiledCacheOption{}, nil)
+
+	rule := querybuilder.AtomRule{Field: "create_time", Operator: querybuilder.OperatorWithinHours, Value: 1}
+	compiled, _, err := cache.Compile(newCompiledFilter(rule), nil)
+	require.NoError(t, err)
+
+	first, _, err := compiled.MgoFilter()
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	second, _, err := compiled.MgoFilter()
+	require.NoError(t, err)
+
+	// the boundary of the relative time is resolved every time the mongo condition is returned
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,155 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"reflect"
+	"strings"
+)
+
+// the base costs of the operators on an indexed field, which are decided by the operator selectivity, the less
+// documents the operator can locate by the index, the cheaper it is.
+const (
+	// costLookup the cost of an exact lookup, e.g. equal, or each element of in.
+	costLookup float64 = 1
+	// costRange the cost of a range scan on the index, e.g. less, greater or begins_with.
+	costRange float64 = 5
+	// costExistence the cost of checking whether the field is set, which usually matches most of the documents.
+	costExistence float64 = 10
+	// costNegation the cost of a negative operator, which matches most of the documents and can hardly use the index.
+	costNegation float64 = 50
+	// costScan the cost of the operator which has to match the value of each document, e.g. contains or regex.
+	costScan float64 = 100
+)
+
+const (
+	// DefaultUnindexedCostFactor the default factor that the cost of a rule on an unindexed field is multiplied by.
+	DefaultUnindexedCostFactor float64 = 10
+	// andResidualCostRatio the ratio of the cost of the AND rules other than the cheapest one, since they are only
+	// evaluated on the documents located by the cheapest one.
+	andResidualCostRatio float64 = 0.1
+)
+
+// CostOption is the option to estimate the cost of the rule.
+type CostOption struct {
+	// IndexedFields the fields that have indexes, all the fields are regarded as unindexed if not set.
+	IndexedFields []string
+
+	// UnindexedFactor the factor that the cost of a rule on an unindexed field is multiplied by,
+	// DefaultUnindexedCostFactor is used if not set.
+	UnindexedFactor float64
+}
+
+// isIndexed returns if the field has an index.
+func (o *CostOption) isIndexed(field string) bool {
+	if o == nil {
+		return false
+	}
+
+	for _, indexed := range o.IndexedFields {
+		if indexed == field {
+			return true
+		}
+	}
+	return false
+}
+
+// unindexedFactor returns the factor of the unindexed field.
+func (o *CostOption) unindexedFactor() float64 {
+	if o == nil || o.UnindexedFactor <= 0 {
+		return DefaultUnindexedCostFactor
+	}
+	return o.UnindexedFactor
+}
+
+// Estimate returns the cost score of the atom rule, which is the base cost of the operator multiplied by the
+// unindexed factor if the field has no index, the cost of in/not_in grows with the size of the array.
+func (r AtomRule) Estimate(opt *CostOption) float64 {
+	var cost float64
+	switch r.Operator {
+	case OperatorEqual:
+		cost = costLookup
+	case OperatorIn:
+		cost = costLookup * float64(r.arraySize())
+	case OperatorLess, OperatorLessOrEqual, OperatorGreater, OperatorGreaterOrEqual, OperatorDatetimeLess,
+		OperatorDatetimeLessOrEqual, OperatorDatetimeGreater, OperatorDatetimeGreaterOrEqual, OperatorWithinDays,
+		OperatorWithinHours, OperatorOlderThan, OperatorBeginsWith:
+		cost = costRange
+	case OperatorRegex:
+		// the regex anchored at the beginning is matched as a prefix by the index.
+		cost = costScan
+		if value, ok := r.Value.(string); ok && strings.HasPrefix(value, "^") {
+			cost = costRange
+		}
+	case OperatorIsEmpty, OperatorIsNotEmpty, OperatorIsNull, OperatorIsNotNull, OperatorExist, OperatorNotExist:
+		cost = costExistence
+	case OperatorNotEqual, OperatorNotBeginsWith:
+		cost = costNegation
+	case OperatorNotIn:
+		cost = costNegation + costLookup*float64(r.arraySize())
+	default:
+		// contains, ends_with, iregex, in_cidr, the size operators, field_compare and the negations, or the custom
+		// operators.
+		cost = costScan
+	}
+
+	if !opt.isIndexed(r.Field) {
+		cost *= opt.unindexedFactor()
+	}
+	return cost
+}
+
+// arraySize returns the element count of the in/not_in value, which is at least 1.
+func (r AtomRule) arraySize() int {
+	if r.Value == nil {
+		return 1
+	}
+
+	v := reflect.ValueOf(r.Value)
+	if v.Kind() != reflect.Array && v.Kind() != reflect.Slice || v.Len() == 0 {
+		return 1
+	}
+	return v.Len()
+}
+
+// Estimate returns the cost score of the combined rules. For AND condition, the cheapest rule locates the documents
+// and the other rules are evaluated on them, for OR condition, all the rules are evaluated, and for NOT condition,
+// the rules are evaluated as unindexed since the negation can not use the index.
+func (r CombinedRule) Estimate(opt *CostOption) float64 {
+	if len(r.Rules) == 0 {
+		return 0
+	}
+
+	costs := make([]float64, 0, len(r.Rules))
+	var sum float64
+	for _, rule := range r.Rules {
+		cost := rule.Estimate(opt)
+		costs = append(costs, cost)
+		sum += cost
+	}
+
+	switch r.Condition {
+	case ConditionAnd:
+		min := costs[0]
+		for _, cost := range costs[1:] {
+			if cost < min {
+				min = cost
+			}
+		}
+		return min + (sum-min)*andResidualCostRatio
+	case ConditionNot:
+		return sum * opt.unindexedFactor()
+	default:
+		return sum
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"testing"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomRuleEstimate(t *testing.T) {
+	opt := &querybuilder.CostOption{IndexedFields: []string{"a"}}
+
+	testCases := []struct {
+		rule querybuilder.AtomRule
+		cost float64
+	}{
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 1},
+			cost: 1,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorEqual, Value: 1},
+			cost: 10,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorIn, Value: []int64{1, 2, 3}},
+			cost: 3,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorIn, Value: []int64{}},
+			cost: 1,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorNotIn, Value: []string{"x", "y"}},
+			cost: 52,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorGreater, Value: 1},
+			cost: 5,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorBeginsWith, Value: "x"},
+			cost: 5,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorRegex, Value: "^x"},
+			cost: 5,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorRegex, Value: "x$"},
+			cost: 100,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorExist, Value: true},
+			cost: 10,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorNotEqual, Value: 1},
+			cost: 50,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorContains, Value: "x"},
+			cost: 1000,
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.cost, testCase.rule.Estimate(opt), "operator: %s", testCase.rule.Operator)
+	}
+}
+
+func TestCombinedRuleEstimate(t *testing.T) {
+	opt := &querybuilder.CostOption{IndexedFields: []string{"a"}, UnindexedFactor: 20}
+
+	equal := querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 1}
+	contains := querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorContains, Value: "x"}
+
+	and := querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd,
+		Rules:     []querybuilder.Rule{contains, equal},
+	}
+	assert.InDelta(t, 201, and.Estimate(opt), 1e-9)
+
+	or := querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionOr,
+		Rules:     []querybuilder.Rule{contains, equal},
+	}
+	assert.Equal(t, float64(2001), or.Estimate(opt))
+
+	not := querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionNot,
+		Rules:     []querybuilder.Rule{equal},
+	}
+	assert.Equal(t, float64(20), not.Estimate(opt))
+
+	filter := &querybuilder.QueryFilter{Rule: and}
+	assert.InDelta(t, 201, filter.Estimate(opt), 1e-9)
+	assert.Equal(t, float64(0), new(querybuilder.QueryFilter).Estimate(opt))
+
+	// all the fields are regarded as unindexed without the option.
+	assert.Equal(t, float64(10), equal.Estimate(nil))
+}
@@ -0,0 +1,175 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// CustomOperator is the domain operator registered by the modules embedding the query filter, e.g. a version_gte
+// operator comparing the semantic versions. The rule with a custom operator is validated by ValidateValue and
+// converted to the mongo filter by ToMgo, the in memory match, sql and es conversions are supported only if the
+// operator implements the corresponding CustomOperatorMatcher, CustomOperatorSQLConverter and
+// CustomOperatorESConverter interfaces.
+type CustomOperator interface {
+	// ValidateValue validates the rule value of the operator.
+	ValidateValue(value interface{}, option *RuleOption) error
+	// ToMgo generates the mongo filter of the rule field with the validated value, the filter is used as it is, so it
+	// should contain the field itself, e.g. {field: {"$gte": value}}.
+	ToMgo(field string, value interface{}) (map[string]interface{}, error)
+}
+
+// CustomOperatorMatcher is implemented by the custom operator supporting to match the document in memory.
+type CustomOperatorMatcher interface {
+	// MatchDoc checks if the field values of the document matches the rule value, the values are the ones found on
+	// the dotted field path, which is empty if the field does not exist.
+	MatchDoc(values []interface{}, value interface{}) (bool, error)
+}
+
+// CustomOperatorSQLConverter is implemented by the custom operator supporting to convert to the sql where clause.
+type CustomOperatorSQLConverter interface {
+	// ToSQL generates the sql clause of the column with the placeholder returned by the placeholder function, and
+	// the args of the placeholders.
+	ToSQL(column string, value interface{}, placeholder func() string) (string, []interface{}, error)
+}
+
+// CustomOperatorESConverter is implemented by the custom operator supporting to convert to the elasticsearch query.
+type CustomOperatorESConverter interface {
+	// ToES generates the elasticsearch query of the rule field.
+	ToES(field string, value interface{}) (elastic.Query, error)
+}
+
+// customOperators the registered custom operators, operator -> implementation.
+var customOperators = struct {
+	sync.RWMutex
+	operators map[Operator]CustomOperator
+}{operators: make(map[Operator]CustomOperator)}
+
+// RegisterOperator registers a custom operator, so that the rules can use it like the builtin operators. It's
+// supposed to be called when the module initializes, the builtin operators and the registered ones can not be
+// overridden.
+func RegisterOperator(name Operator, op CustomOperator) error {
+	if len(name) == 0 {
+		return errors.New("operator name is empty")
+	}
+
+	if op == nil {
+		return fmt.Errorf("operator %s implementation is nil", name)
+	}
+
+	if SupportOperators[name] {
+		return fmt.Errorf("operator %s is a builtin operator", name)
+	}
+
+	customOperators.Lock()
+	defer customOperators.Unlock()
+
+	if _, exists := customOperators.operators[name]; exists {
+		return fmt.Errorf("operator %s is already registered", name)
+	}
+
+	customOperators.operators[name] = op
+	return nil
+}
+
+// customOperator returns the registered custom operator.
+func customOperator(name Operator) (CustomOperator, bool) {
+	customOperators.RLock()
+	defer customOperators.RUnlock()
+
+	op, exists := customOperators.operators[name]
+	return op, exists
+}
+
+// validateCustomValue validates the value of the rule with a custom operator.
+func (r AtomRule) validateCustomValue(option *RuleOption) error {
+	op, exists := customOperator(r.Operator)
+	if !exists {
+		return fmt.Errorf("unsupported operator: %s", r.Operator)
+	}
+	return op.ValidateValue(r.Value, option)
+}
+
+// customMgo generates the mongo filter of the rule with a custom operator.
+func (r AtomRule) customMgo() (map[string]interface{}, error) {
+	op, exists := customOperator(r.Operator)
+	if !exists {
+		return nil, fmt.Errorf("unsupported operator: %s", r.Operator)
+	}
+
+	filter, err := op.ToMgo(r.Field, r.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filter) == 0 {
+		return nil, fmt.Errorf("operator %s generates empty filter", r.Operator)
+	}
+	return filter, nil
+}
+
+// matchCustom matches the document field values with the rule of a custom operator.
+func (r AtomRule) matchCustom(values []interface{}) (bool, error) {
+	op, exists := customOperator(r.Operator)
+	if !exists {
+		return false, fmt.Errorf("unsupported operator: %s", r.Operator)
+	}
+
+	matcher, ok := op.(CustomOperatorMatcher)
+	if !ok {
+		return false, fmt.Errorf("operator %s does not support matching in memory", r.Operator)
+	}
+	return matcher.MatchDoc(values, r.Value)
+}
+
+// customToSQL generates the sql clause of the rule with a custom operator.
+func (r AtomRule) customToSQL(opt *SQLOption, column string) (string, []interface{}, string, error) {
+	op, exists := customOperator(r.Operator)
+	if !exists {
+		return "", nil, "operator", fmt.Errorf("unsupported operator: %s", r.Operator)
+	}
+
+	converter, ok := op.(CustomOperatorSQLConverter)
+	if !ok {
+		return "", nil, "operator", fmt.Errorf("operator %s does not support sql", r.Operator)
+	}
+
+	clause, args, err := converter.ToSQL(column, r.Value, opt.placeholder)
+	if err != nil {
+		return "", nil, "value", err
+	}
+	return clause, args, "", nil
+}
+
+// customToES generates the elasticsearch query of the rule with a custom operator.
+func (r AtomRule) customToES() (elastic.Query, string, error) {
+	op, exists := customOperator(r.Operator)
+	if !exists {
+		return nil, "operator", fmt.Errorf("unsupported operator: %s", r.Operator)
+	}
+
+	converter, ok := op.(CustomOperatorESConverter)
+	if !ok {
+		return nil, "operator", fmt.Errorf("operator %s does not support elasticsearch", r.Operator)
+	}
+
+	query, err := converter.ToES(r.Field, r.Value)
+	if err != nil {
+		return nil, "value", err
+	}
+	return query, "", nil
+}
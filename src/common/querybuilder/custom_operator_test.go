@@ -0,0 +1,129 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// versionGte is a custom operator matching the semantic versions greater than or equal to the value.
+type versionGte struct{}
+
+func parseVersion(value interface{}) ([3]int, error) {
+	version := [3]int{}
+	str, ok := value.(string)
+	if !ok {
+		return version, fmt.Errorf("version %v is not a string", value)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(str, "v"), ".")
+	if len(parts) != 3 {
+		return version, fmt.Errorf("invalid version %s", str)
+	}
+
+	for idx, part := range parts {
+		num, err := strconv.Atoi(part)
+		if err != nil {
+			return version, fmt.Errorf("invalid version %s", str)
+		}
+		version[idx] = num
+	}
+	return version, nil
+}
+
+func (versionGte) ValidateValue(value interface{}, option *querybuilder.RuleOption) error {
+	_, err := parseVersion(value)
+	return err
+}
+
+func (versionGte) ToMgo(field string, value interface{}) (map[string]interface{}, error) {
+	version, _ := parseVersion(value)
+	return map[string]interface{}{
+		field + ".major": map[string]interface{}{"$gte": version[0]},
+	}, nil
+}
+
+func (versionGte) MatchDoc(values []interface{}, value interface{}) (bool, error) {
+	expected, _ := parseVersion(value)
+	for _, v := range values {
+		version, err := parseVersion(v)
+		if err != nil {
+			continue
+		}
+		for idx := range version {
+			if version[idx] != expected[idx] {
+				if version[idx] > expected[idx] {
+					return true, nil
+				}
+				break
+			}
+			if idx == len(version)-1 {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func TestRegisterOperator(t *testing.T) {
+	require.NoError(t, querybuilder.RegisterOperator("version_gte", versionGte{}))
+
+	assert.Error(t, querybuilder.RegisterOperator("version_gte", versionGte{}), "duplicate operator")
+	assert.Error(t, querybuilder.RegisterOperator(querybuilder.OperatorEqual, versionGte{}), "builtin operator")
+	assert.Error(t, querybuilder.RegisterOperator("", versionGte{}), "empty operator name")
+	assert.Error(t, querybuilder.RegisterOperator("version_lte", nil), "nil operator")
+
+	rule := querybuilder.AtomRule{Field: "agent_version", Operator: "version_gte", Value: "v1.2.3"}
+	key, err := rule.Validate(&querybuilder.RuleOption{})
+	require.NoError(t, err)
+	assert.Empty(t, key)
+
+	filter, key, err := rule.ToMgo()
+	require.NoError(t, err)
+	assert.Empty(t, key)
+	assert.Equal(t, map[string]interface{}{"agent_version.major": map[string]interface{}{"$gte": 1}}, filter)
+
+	for version, expected := range map[string]bool{"1.2.3": true, "1.10.0": true, "2.0.0": true, "1.2.2": false,
+		"0.9.9": false} {
+		matched, err := rule.MatchDoc(map[string]interface{}{"agent_version": version})
+		require.NoError(t, err)
+		assert.Equal(t, expected, matched, version)
+	}
+
+	invalid := querybuilder.AtomRule{Field: "agent_version", Operator: "version_gte", Value: "1.2"}
+	key, err = invalid.Validate(&querybuilder.RuleOption{})
+	assert.Error(t, err)
+	assert.Equal(t, "value", key)
+
+	// the custom operator does not implement the sql and es conversions.
+	_, _, key, err = rule.ToSQL(&querybuilder.SQLOption{})
+	assert.Error(t, err)
+	assert.Equal(t, "operator", key)
+
+	_, key, err = rule.ToES()
+	assert.Error(t, err)
+	assert.Equal(t, "operator", key)
+
+	unknown := querybuilder.AtomRule{Field: "agent_version", Operator: "version_lte", Value: "1.2.3"}
+	key, err = unknown.Validate(&querybuilder.RuleOption{})
+	assert.Error(t, err)
+	assert.Equal(t, "operator", key)
+}
@@ -0,0 +1,501 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseQueryFilterFromText parses the query filter from the text query language, e.g.
+//
+//	bk_host_innerip ~ "10.0.*" and (operator in ["a", "b"] or bk_cloud_id = 0)
+//
+// the rules are combined by the and/or keywords, and is prior to or, the parentheses group the rules, and the not
+// keyword before the parentheses negates the rules in them, e.g. not (a = 1 or b = 2). The supported
+// comparisons of the field are:
+//
+//	= != < <= > >=           compare with the value, compare the date if the value is a string, e.g. "2021-01-02"
+//	~ ~*                     match the regular expression, ~* is case-insensitive
+//	in, not in               match the values in the list, e.g. ["a", "b"]
+//	size = <= >=             match the element count of the array field, e.g. tags size >= 2
+//	= != < <= > >= field     compare with another field if the value is a field, e.g. cpu_used > cpu_limit
+//	contains, begins_with, ends_with and the negative ones prefixed with not
+//	is null, is not null, is empty, is not empty, exists, not exists
+//
+// the values are the double quoted strings, numbers, true and false. The syntax error tells the position of the text.
+func ParseQueryFilterFromText(text string) (*QueryFilter, error) {
+	tokens, err := lexQueryText(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &textParser{text: text, tokens: tokens}
+	rule, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %s, expect and/or", tok)
+	}
+
+	if _, ok := rule.(CombinedRule); !ok {
+		rule = CombinedRule{Condition: ConditionAnd, Rules: []Rule{rule}}
+	}
+
+	filter := &QueryFilter{Rule: rule}
+	if key, err := filter.Validate(&RuleOption{NeedSameSliceElementType: true}); err != nil {
+		return nil, fmt.Errorf("invalid key: %s, err: %v", key, err)
+	}
+
+	if filter.GetDeep() > MaxDeep {
+		return nil, fmt.Errorf("exceed max query condition deepth: %d", MaxDeep)
+	}
+	return filter, nil
+}
+
+// SyntaxError is the syntax error of the text query language
+type SyntaxError struct {
+	// Offset the byte offset of the text where the error occurs
+	Offset int
+	// Near the text near the error
+	Near string
+	Msg  string
+}
+
+// Error returns the error message with the position of the error
+func (e *SyntaxError) Error() string {
+	if e.Near == "" {
+		return fmt.Sprintf("syntax error at offset %d: %s", e.Offset, e.Msg)
+	}
+	return fmt.Sprintf("syntax error at offset %d near %q: %s", e.Offset, e.Near, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type textToken struct {
+	kind   tokenKind
+	text   string
+	offset int
+	// value the parsed value of the string and number tokens
+	value interface{}
+}
+
+// String returns the token text for the syntax error
+func (t textToken) String() string {
+	if t.kind == tokenEOF {
+		return "end of text"
+	}
+	return strconv.Quote(t.text)
+}
+
+// isKeyword checks if the token is the keyword, the keywords are case-insensitive
+func (t textToken) isKeyword(keyword string) bool {
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+// textSymbols the symbols of the text query language, the longer ones are matched first
+var textSymbols = []string{"==", "!=", "<=", ">=", "~*", "=", "<", ">", "~", "(", ")", "[", "]", ","}
+
+// lexQueryText splits the text into tokens
+func lexQueryText(text string) ([]textToken, error) {
+	tokens := make([]textToken, 0)
+	for i := 0; i < len(text); {
+		c := rune(text[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '"':
+			end := i + 1
+			for ; end < len(text) && text[end] != '"'; end++ {
+				if text[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(text) {
+				return nil, &SyntaxError{Offset: i, Near: text[i:], Msg: "unterminated string"}
+			}
+
+			value, err := strconv.Unquote(text[i : end+1])
+			if err != nil {
+				return nil, &SyntaxError{Offset: i, Near: text[i : end+1], Msg: "invalid string"}
+			}
+			tokens = append(tokens, textToken{kind: tokenString, text: text[i : end+1], offset: i, value: value})
+			i = end + 1
+
+		case c == '-' || c == '+' || unicode.IsDigit(c):
+			end := i + 1
+			for end < len(text) && strings.IndexByte("0123456789.eE+-", text[end]) >= 0 {
+				end++
+			}
+
+			var value interface{}
+			if intVal, err := strconv.ParseInt(text[i:end], 10, 64); err == nil {
+				value = intVal
+			} else if floatVal, err := strconv.ParseFloat(text[i:end], 64); err == nil {
+				value = floatVal
+			} else {
+				return nil, &SyntaxError{Offset: i, Near: text[i:end], Msg: "invalid number"}
+			}
+			tokens = append(tokens, textToken{kind: tokenNumber, text: text[i:end], offset: i, value: value})
+			i = end
+
+		case c == '_' || unicode.IsLetter(c):
+			end := i + 1
+			for end < len(text) && isIdentByte(text[end]) {
+				end++
+			}
+			tokens = append(tokens, textToken{kind: tokenIdent, text: text[i:end], offset: i})
+			i = end
+
+		default:
+			matched := false
+			for _, symbol := range textSymbols {
+				if strings.HasPrefix(text[i:], symbol) {
+					tokens = append(tokens, textToken{kind: tokenSymbol, text: symbol, offset: i})
+					i += len(symbol)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &SyntaxError{Offset: i, Near: string(c), Msg: "unexpected character"}
+			}
+		}
+	}
+
+	return append(tokens, textToken{kind: tokenEOF, offset: len(text)}), nil
+}
+
+// isIdentByte checks if the byte is a part of the field, which is the same as the ValidFieldPattern
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+// textParser is a recursive descent parser of the text query language
+type textParser struct {
+	text   string
+	tokens []textToken
+	pos    int
+}
+
+func (p *textParser) peek() textToken {
+	return p.tokens[p.pos]
+}
+
+func (p *textParser) next() textToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *textParser) errorf(tok textToken, format string, args ...interface{}) error {
+	near := tok.text
+	if tok.kind == tokenEOF && len(p.tokens) >= 2 {
+		// the end of the text is reported near the last token, the whitespace only text has no last token
+		near = p.tokens[len(p.tokens)-2].text
+	}
+	return &SyntaxError{Offset: tok.offset, Near: near, Msg: fmt.Sprintf(format, args...)}
+}
+
+// expectSymbol consumes the symbol token, or returns the syntax error
+func (p *textParser) expectSymbol(symbol string) error {
+	tok := p.next()
+	if tok.kind != tokenSymbol || tok.text != symbol {
+		return p.errorf(tok, "unexpected %s, expect %q", tok, symbol)
+	}
+	return nil
+}
+
+// parseOr parses the rules combined by or
+func (p *textParser) parseOr() (Rule, error) {
+	return p.parseCombined(ConditionOr, "or", p.parseAnd)
+}
+
+// parseAnd parses the rules combined by and
+func (p *textParser) parseAnd() (Rule, error) {
+	return p.parseCombined(ConditionAnd, "and", p.parsePrimary)
+}
+
+// parseCombined parses the rules joined by the keyword, the chained rules are combined in one level
+func (p *textParser) parseCombined(condition Condition, keyword string, parse func() (Rule, error)) (Rule, error) {
+	rule, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []Rule{rule}
+	for p.peek().isKeyword(keyword) {
+		p.next()
+		rule, err := parse()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) == 1 {
+		return rules[0], nil
+	}
+	return CombinedRule{Condition: condition, Rules: rules}, nil
+}
+
+// parsePrimary parses the rules in parentheses, the negated rules in parentheses or an atom rule
+func (p *textParser) parsePrimary() (Rule, error) {
+	tok := p.peek()
+	if tok.isKeyword("not") {
+		if lparen := p.tokens[p.pos+1]; lparen.kind == tokenSymbol && lparen.text == "(" {
+			p.next()
+			rule, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return Negate(rule), nil
+		}
+	}
+
+	if tok.kind == tokenSymbol && tok.text == "(" {
+		p.next()
+		rule, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return rule, nil
+	}
+
+	if tok.kind != tokenIdent {
+		return nil, p.errorf(tok, "unexpected %s, expect field or \"(\"", tok)
+	}
+	p.next()
+
+	return p.parseComparison(tok.text)
+}
+
+// textCompareOperators the operators of the compare symbols, the datetime operators are used for the string values
+var textCompareOperators = map[string][2]Operator{
+	"<":  {OperatorLess, OperatorDatetimeLess},
+	"<=": {OperatorLessOrEqual, OperatorDatetimeLessOrEqual},
+	">":  {OperatorGreater, OperatorDatetimeGreater},
+	">=": {OperatorGreaterOrEqual, OperatorDatetimeGreaterOrEqual},
+}
+
+// textFieldCompareOperators the field compare operators of the compare symbols followed by a field
+var textFieldCompareOperators = map[string]Operator{
+	"=":  OperatorEqual,
+	"==": OperatorEqual,
+	"!=": OperatorNotEqual,
+	"<":  OperatorLess,
+	"<=": OperatorLessOrEqual,
+	">":  OperatorGreater,
+	">=": OperatorGreaterOrEqual,
+}
+
+// textStringOperators the operators of the string keywords, and their negative ones prefixed with not
+var textStringOperators = map[string][2]Operator{
+	"contains":    {OperatorContains, OperatorNotContains},
+	"begins_with": {OperatorBeginsWith, OperatorNotBeginsWith},
+	"ends_with":   {OperatorsEndsWith, OperatorNotEndsWith},
+}
+
+// parseComparison parses the comparison of the field
+func (p *textParser) parseComparison(field string) (Rule, error) {
+	tok := p.next()
+
+	if tok.kind == tokenSymbol {
+		if operator, ok := textFieldCompareOperators[tok.text]; ok {
+			if other := p.peek(); other.kind == tokenIdent && !other.isKeyword("true") &&
+				!other.isKeyword("false") && !other.isKeyword("null") {
+				p.next()
+				value := FieldCompareValue{Field: other.text, Operator: operator}
+				return AtomRule{Field: field, Operator: OperatorFieldCompare, Value: value}, nil
+			}
+		}
+
+		switch tok.text {
+		case "=", "==":
+			return p.parseValueRule(field, OperatorEqual)
+		case "!=":
+			return p.parseValueRule(field, OperatorNotEqual)
+		case "~":
+			return p.parseValueRule(field, OperatorRegex)
+		case "~*":
+			return p.parseValueRule(field, OperatorIRegex)
+		}
+
+		if operators, ok := textCompareOperators[tok.text]; ok {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			if _, isString := value.(string); isString {
+				return AtomRule{Field: field, Operator: operators[1], Value: value}, nil
+			}
+			return AtomRule{Field: field, Operator: operators[0], Value: value}, nil
+		}
+	}
+
+	if tok.kind != tokenIdent {
+		return nil, p.errorf(tok, "unexpected %s, expect operator", tok)
+	}
+
+	not := tok.isKeyword("not")
+	if not {
+		tok = p.next()
+	}
+	keyword := strings.ToLower(tok.text)
+
+	if operators, ok := textStringOperators[keyword]; ok {
+		if not {
+			return p.parseValueRule(field, operators[1])
+		}
+		return p.parseValueRule(field, operators[0])
+	}
+
+	switch {
+	case tok.isKeyword("in"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		if not {
+			return AtomRule{Field: field, Operator: OperatorNotIn, Value: values}, nil
+		}
+		return AtomRule{Field: field, Operator: OperatorIn, Value: values}, nil
+
+	case tok.isKeyword("exists"):
+		if not {
+			return AtomRule{Field: field, Operator: OperatorNotExist}, nil
+		}
+		return AtomRule{Field: field, Operator: OperatorExist}, nil
+
+	case tok.isKeyword("is") && !not:
+		return p.parseIs(field)
+
+	case tok.isKeyword("size") && !not:
+		return p.parseSize(field)
+	}
+
+	return nil, p.errorf(tok, "unexpected %s, expect operator", tok)
+}
+
+// parseIs parses the null and empty check after the is keyword
+func (p *textParser) parseIs(field string) (Rule, error) {
+	tok := p.next()
+	not := tok.isKeyword("not")
+	if not {
+		tok = p.next()
+	}
+
+	switch {
+	case tok.isKeyword("null"):
+		if not {
+			return AtomRule{Field: field, Operator: OperatorIsNotNull}, nil
+		}
+		return AtomRule{Field: field, Operator: OperatorIsNull}, nil
+	case tok.isKeyword("empty"):
+		if not {
+			return AtomRule{Field: field, Operator: OperatorIsNotEmpty}, nil
+		}
+		return AtomRule{Field: field, Operator: OperatorIsEmpty}, nil
+	}
+
+	return nil, p.errorf(tok, "unexpected %s, expect null or empty", tok)
+}
+
+// textSizeOperators the size operators of the compare symbols after the size keyword
+var textSizeOperators = map[string]Operator{
+	"=":  OperatorSize,
+	"==": OperatorSize,
+	">=": OperatorSizeGreaterOrEqual,
+	"<=": OperatorSizeLessOrEqual,
+}
+
+// parseSize parses the element count comparison after the size keyword
+func (p *textParser) parseSize(field string) (Rule, error) {
+	tok := p.next()
+	operator, ok := textSizeOperators[tok.text]
+	if tok.kind != tokenSymbol || !ok {
+		return nil, p.errorf(tok, "unexpected %s, expect \"=\", \">=\" or \"<=\"", tok)
+	}
+	return p.parseValueRule(field, operator)
+}
+
+// parseValueRule parses the value of the atom rule with the operator
+func (p *textParser) parseValueRule(field string, operator Operator) (Rule, error) {
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return AtomRule{Field: field, Operator: operator, Value: value}, nil
+}
+
+// parseValue parses a string, number or bool value
+func (p *textParser) parseValue() (interface{}, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == tokenString, tok.kind == tokenNumber:
+		return tok.value, nil
+	case tok.isKeyword("true"):
+		return true, nil
+	case tok.isKeyword("false"):
+		return false, nil
+	}
+	return nil, p.errorf(tok, "unexpected %s, expect value", tok)
+}
+
+// parseList parses the values in brackets
+func (p *textParser) parseList() ([]interface{}, error) {
+	if err := p.expectSymbol("["); err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, 0)
+	if tok := p.peek(); tok.kind == tokenSymbol && tok.text == "]" {
+		p.next()
+		return values, nil
+	}
+
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		tok := p.next()
+		if tok.kind == tokenSymbol && tok.text == "]" {
+			return values, nil
+		}
+		if tok.kind != tokenSymbol || tok.text != "," {
+			return nil, p.errorf(tok, "unexpected %s, expect \",\" or \"]\"", tok)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"testing"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQueryFilterFromText(t *testing.T) {
+	filter, err := querybuilder.ParseQueryFilterFromText(`bk_host_innerip ~ "10.0.*" and ` +
+		`(operator in ["a", "b"] or create_time < "2021-01-02") and bk_cloud_id >= 1 and bk_os_name not contains "x"`)
+	assert.Nil(t, err)
+
+	expected := querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "bk_host_innerip", Operator: querybuilder.OperatorRegex, Value: "10.0.*"},
+			querybuilder.CombinedRule{
+				Condition: querybuilder.ConditionOr,
+				Rules: []querybuilder.Rule{
+					querybuilder.AtomRule{Field: "operator", Operator: querybuilder.OperatorIn,
+						Value: []interface{}{"a", "b"}},
+					querybuilder.AtomRule{Field: "create_time", Operator: querybuilder.OperatorDatetimeLess,
+						Value: "2021-01-02"},
+				},
+			},
+			querybuilder.AtomRule{Field: "bk_cloud_id", Operator: querybuilder.OperatorGreaterOrEqual, Value: int64(1)},
+			querybuilder.AtomRule{Field: "bk_os_name", Operator: querybuilder.OperatorNotContains, Value: "x"},
+		},
+	}
+	assert.Equal(t, expected, filter.Rule)
+
+	filter, err = querybuilder.ParseQueryFilterFromText(`tags size >= 2 or bk_host_outerip SIZE = 0`)
+	assert.Nil(t, err)
+	expected = querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionOr,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "tags", Operator: querybuilder.OperatorSizeGreaterOrEqual, Value: int64(2)},
+			querybuilder.AtomRule{Field: "bk_host_outerip", Operator: querybuilder.OperatorSize, Value: int64(0)},
+		},
+	}
+	assert.Equal(t, expected, filter.Rule)
+
+	filter, err = querybuilder.ParseQueryFilterFromText(`cpu_used > cpu_limit and bk_host_name = "cpu_limit"`)
+	assert.Nil(t, err)
+	expected = querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "cpu_used", Operator: querybuilder.OperatorFieldCompare,
+				Value: querybuilder.FieldCompareValue{Field: "cpu_limit", Operator: querybuilder.OperatorGreater}},
+			querybuilder.AtomRule{Field: "bk_host_name", Operator: querybuilder.OperatorEqual, Value: "cpu_limit"},
+		},
+	}
+	assert.Equal(t, expected, filter.Rule)
+
+	filter, err = querybuilder.ParseQueryFilterFromText(`a is not null AND b NOT EXISTS and c = true and d != -1.5`)
+	assert.Nil(t, err)
+	expected = querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorIsNotNull},
+			querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorNotExist},
+			querybuilder.AtomRule{Field: "c", Operator: querybuilder.OperatorEqual, Value: true},
+			querybuilder.AtomRule{Field: "d", Operator: querybuilder.OperatorNotEqual, Value: -1.5},
+		},
+	}
+	assert.Equal(t, expected, filter.Rule)
+}
+
+func TestParseQueryFilterFromTextSyntaxError(t *testing.T) {
+	testCases := []struct {
+		text   string
+		offset int
+	}{
+		{text: `a = `, offset: 4},
+		{text: `a = "x`, offset: 4},
+		{text: `a in ["x" "y"]`, offset: 10},
+		{text: `(a = 1 or b = 2`, offset: 15},
+		{text: `a = 1 b = 2`, offset: 6},
+		{text: `a @ 1`, offset: 2},
+		{text: `a is 1`, offset: 5},
+		// the whitespace only text ends without any token
+		{text: `   `, offset: 3},
+		{text: ``, offset: 0},
+	}
+
+	for _, testCase := range testCases {
+		_, err := querybuilder.ParseQueryFilterFromText(testCase.text)
+		syntaxErr, ok := err.(*querybuilder.SyntaxError)
+		if assert.True(t, ok, testCase.text) {
+			assert.Equal(t, testCase.offset, syntaxErr.Offset, testCase.text)
+		}
+	}
+
+	// the rules are validated after parsed
+	_, err := querybuilder.ParseQueryFilterFromText(`a in [1, "x"]`)
+	assert.NotNil(t, err)
+	_, err = querybuilder.ParseQueryFilterFromText(`a < "yesterday"`)
+	assert.NotNil(t, err)
+}
+
+func TestParseQueryFilterFromTextPrecedence(t *testing.T) {
+	// and is prior to or
+	filter, err := querybuilder.ParseQueryFilterFromText(`a = 1 or b = 2 and c = 3`)
+	assert.Nil(t, err)
+	expected := querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionOr,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: int64(1)},
+			querybuilder.CombinedRule{
+				Condition: querybuilder.ConditionAnd,
+				Rules: []querybuilder.Rule{
+					querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorEqual, Value: int64(2)},
+					querybuilder.AtomRule{Field: "c", Operator: querybuilder.OperatorEqual, Value: int64(3)},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, filter.Rule)
+}
+
+func TestParseQueryFilterFromTextNot(t *testing.T) {
+	filter, err := querybuilder.ParseQueryFilterFromText(`a = 1 and not (b = 2 or c not in [3])`)
+	assert.Nil(t, err)
+	expected := querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: int64(1)},
+			querybuilder.CombinedRule{
+				Condition: querybuilder.ConditionNot,
+				Rules: []querybuilder.Rule{
+					querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorEqual, Value: int64(2)},
+					querybuilder.AtomRule{Field: "c", Operator: querybuilder.OperatorNotIn,
+						Value: []interface{}{int64(3)}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, filter.Rule)
+
+	// double negation is simplified
+	filter, err = querybuilder.ParseQueryFilterFromText(`not (not (a = 1))`)
+	assert.Nil(t, err)
+	assert.Equal(t, querybuilder.CombinedRule{Condition: querybuilder.ConditionAnd, Rules: []querybuilder.Rule{
+		querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: int64(1)}}}, filter.Rule)
+
+	_, err = querybuilder.ParseQueryFilterFromText(`not (a = 1`)
+	assert.NotNil(t, err)
+}
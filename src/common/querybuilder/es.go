@@ -0,0 +1,188 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// esDateFormat is the elasticsearch date format of the datetime operator values, which is the same as timeLayout.
+const esDateFormat = "yyyy-MM-dd"
+
+var esWildcardEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`)
+
+// ToES generate the elasticsearch query from rule. The rule is converted to match the same documents as ToMgo does
+// as far as elasticsearch can tell, so the negative operators match the documents without the field too, and the
+// string operators match the value literally. Since elasticsearch does not index the null values and the empty
+// arrays, a field is treated as not exist if it is null or an empty array.
+func (r AtomRule) ToES() (query elastic.Query, key string, err error) {
+	if key, err := r.Validate(&RuleOption{NeedSameSliceElementType: true}); err != nil {
+		return nil, key, fmt.Errorf("validate failed, key: %s, err: %s", key, err)
+	}
+
+	switch r.Operator {
+	case OperatorEqual:
+		return elastic.NewTermQuery(r.Field, r.Value), "", nil
+	case OperatorNotEqual:
+		return esMustNot(elastic.NewTermQuery(r.Field, r.Value)), "", nil
+	case OperatorIn:
+		return elastic.NewTermsQuery(r.Field, r.sliceValue()...), "", nil
+	case OperatorNotIn:
+		return esMustNot(elastic.NewTermsQuery(r.Field, r.sliceValue()...)), "", nil
+	case OperatorLess:
+		return elastic.NewRangeQuery(r.Field).Lt(r.Value), "", nil
+	case OperatorLessOrEqual:
+		return elastic.NewRangeQuery(r.Field).Lte(r.Value), "", nil
+	case OperatorGreater:
+		return elastic.NewRangeQuery(r.Field).Gt(r.Value), "", nil
+	case OperatorGreaterOrEqual:
+		return elastic.NewRangeQuery(r.Field).Gte(r.Value), "", nil
+	case OperatorDatetimeLess:
+		return elastic.NewRangeQuery(r.Field).Lt(r.Value).Format(esDateFormat), "", nil
+	case OperatorDatetimeLessOrEqual:
+		return elastic.NewRangeQuery(r.Field).Lte(r.Value).Format(esDateFormat), "", nil
+	case OperatorDatetimeGreater:
+		return elastic.NewRangeQuery(r.Field).Gt(r.Value).Format(esDateFormat), "", nil
+	case OperatorDatetimeGreaterOrEqual:
+		return elastic.NewRangeQuery(r.Field).Gte(r.Value).Format(esDateFormat), "", nil
+	case OperatorWithinDays, OperatorWithinHours, OperatorOlderThan:
+		boundary, err := r.esRelativeTime()
+		if err != nil {
+			return nil, "value", err
+		}
+		if r.Operator == OperatorOlderThan {
+			return elastic.NewRangeQuery(r.Field).Lt(boundary), "", nil
+		}
+		return elastic.NewRangeQuery(r.Field).Gte(boundary), "", nil
+	case OperatorBeginsWith:
+		return elastic.NewPrefixQuery(r.Field, r.Value.(string)), "", nil
+	case OperatorNotBeginsWith:
+		return esMustNot(elastic.NewPrefixQuery(r.Field, r.Value.(string))), "", nil
+	case OperatorContains:
+		// contains is case insensitive as the mongo filter does
+		return elastic.NewWildcardQuery(r.Field, "*"+escapeWildcard(r.Value.(string))+"*").CaseInsensitive(true),
+			"", nil
+	case OperatorNotContains:
+		return esMustNot(elastic.NewWildcardQuery(r.Field, "*"+escapeWildcard(r.Value.(string))+"*")), "", nil
+	case OperatorsEndsWith:
+		return elastic.NewWildcardQuery(r.Field, "*"+escapeWildcard(r.Value.(string))), "", nil
+	case OperatorNotEndsWith:
+		return esMustNot(elastic.NewWildcardQuery(r.Field, "*"+escapeWildcard(r.Value.(string)))), "", nil
+	case OperatorRegex:
+		return elastic.NewRegexpQuery(r.Field, toLuceneRegex(r.Value.(string))), "", nil
+	case OperatorIRegex:
+		return elastic.NewRegexpQuery(r.Field, toLuceneRegex(r.Value.(string))).CaseInsensitive(true), "", nil
+	case OperatorInCIDR:
+		pattern, err := r.cidrLuceneRegex()
+		if err != nil {
+			return nil, "value", err
+		}
+		return elastic.NewRegexpQuery(r.Field, pattern), "", nil
+	case OperatorSize, OperatorSizeGreaterOrEqual, OperatorSizeLessOrEqual:
+		query, err := r.sizeES()
+		if err != nil {
+			return nil, "value", err
+		}
+		return query, "", nil
+	case OperatorFieldCompare:
+		query, err := r.fieldCompareES()
+		if err != nil {
+			return nil, "value", err
+		}
+		return query, "", nil
+	case OperatorIsNotEmpty, OperatorIsNotNull, OperatorExist:
+		return elastic.NewExistsQuery(r.Field), "", nil
+	case OperatorIsEmpty, OperatorIsNull, OperatorNotExist:
+		return esMustNot(elastic.NewExistsQuery(r.Field)), "", nil
+	default:
+		return r.customToES()
+	}
+}
+
+// sliceValue returns the elements of the in/not_in value.
+func (r AtomRule) sliceValue() []interface{} {
+	values := make([]interface{}, 0)
+	if r.Value == nil {
+		return values
+	}
+
+	v := reflect.ValueOf(r.Value)
+	for i := 0; i < v.Len(); i++ {
+		values = append(values, v.Index(i).Interface())
+	}
+	return values
+}
+
+// esMustNot returns the bool query which matches the documents not matching the query.
+func esMustNot(query elastic.Query) elastic.Query {
+	return elastic.NewBoolQuery().MustNot(query)
+}
+
+// escapeWildcard escapes the wildcards in the value so that it is matched literally by the wildcard query.
+func escapeWildcard(value string) string {
+	return esWildcardEscaper.Replace(value)
+}
+
+// toLuceneRegex converts the partial matching regular expression to the lucene regular expression, which always
+// matches the entire value and does not support the anchors, so the anchors are removed and the unanchored sides
+// are padded with .* instead.
+func toLuceneRegex(pattern string) string {
+	if strings.HasPrefix(pattern, "^") {
+		pattern = pattern[1:]
+	} else {
+		pattern = ".*" + pattern
+	}
+
+	if strings.HasSuffix(pattern, "$") && !strings.HasSuffix(pattern, `\$`) {
+		pattern = pattern[:len(pattern)-1]
+	} else {
+		pattern += ".*"
+	}
+	return pattern
+}
+
+// ToES generate the elasticsearch bool query from the combined rules, the queries of the rules are combined as
+// must clauses for AND condition, as should clauses of which at least one should match for OR condition, and as
+// must_not clauses for NOT condition.
+func (r CombinedRule) ToES() (query elastic.Query, key string, err error) {
+	if err := r.Condition.Validate(); err != nil {
+		return nil, "condition", err
+	}
+	if len(r.Rules) == 0 {
+		return nil, "rules", fmt.Errorf("combined rules shouldn't be empty")
+	}
+
+	queries := make([]elastic.Query, 0, len(r.Rules))
+	for idx, rule := range r.Rules {
+		query, key, err := rule.ToES()
+		if err != nil {
+			return nil, fmt.Sprintf("rules[%d].%s", idx, key), err
+		}
+		queries = append(queries, query)
+	}
+
+	switch r.Condition {
+	case ConditionAnd:
+		return elastic.NewBoolQuery().Must(queries...), "", nil
+	case ConditionOr:
+		return elastic.NewBoolQuery().Should(queries...).MinimumNumberShouldMatch(1), "", nil
+	case ConditionNot:
+		return elastic.NewBoolQuery().MustNot(queries...), "", nil
+	default:
+		return nil, "condition", fmt.Errorf("unsupported condition: %s", r.Condition)
+	}
+}
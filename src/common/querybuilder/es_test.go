@@ -0,0 +1,138 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func esSource(t *testing.T, rule querybuilder.Rule) string {
+	query, key, err := rule.ToES()
+	if err != nil {
+		t.Fatalf("convert rule to es query failed, key: %s, err: %v", key, err)
+	}
+
+	source, err := query.Source()
+	if err != nil {
+		t.Fatalf("get es query source failed, err: %v", err)
+	}
+
+	data, err := json.Marshal(source)
+	if err != nil {
+		t.Fatalf("marshal es query source failed, err: %v", err)
+	}
+	return string(data)
+}
+
+func TestAtomRuleToES(t *testing.T) {
+	testCases := []struct {
+		rule   querybuilder.AtomRule
+		source string
+	}{
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 1},
+			source: `{"term":{"a":1}}`,
+		},
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorNotEqual, Value: "x"},
+			source: `{"bool":{"must_not":{"term":{"a":"x"}}}}`,
+		},
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorIn, Value: []int64{1, 2}},
+			source: `{"terms":{"a":[1,2]}}`,
+		},
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorGreaterOrEqual, Value: 3},
+			source: `{"range":{"a":{"from":3,"include_lower":true,"include_upper":true,"to":null}}}`,
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorDatetimeLess,
+				Value: "2022-10-01"},
+			source: `{"range":{"a":{"format":"yyyy-MM-dd","from":null,"include_lower":true,` +
+				`"include_upper":false,"to":"2022-10-01"}}}`,
+		},
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorBeginsWith, Value: "x*"},
+			source: `{"prefix":{"a":"x*"}}`,
+		},
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorContains, Value: "x*?"},
+			source: `{"wildcard":{"a":{"case_insensitive":true,"value":"*x\\*\\?*"}}}`,
+		},
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorNotEndsWith, Value: "x"},
+			source: `{"bool":{"must_not":{"wildcard":{"a":{"value":"*x"}}}}}`,
+		},
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorRegex, Value: "^x[0-9]+"},
+			source: `{"regexp":{"a":{"value":"x[0-9]+.*"}}}`,
+		},
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorIRegex, Value: "x$"},
+			source: `{"regexp":{"a":{"case_insensitive":true,"value":".*x"}}}`,
+		},
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorInCIDR, Value: "192.168.1.0/24"},
+			source: `{"regexp":{"a":{"value":"(.*,)?192\\.168\\.1\\.[0-9]{1,3}(,.*)?"}}}`,
+		},
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorExist},
+			source: `{"exists":{"field":"a"}}`,
+		},
+		{
+			rule:   querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorIsNull},
+			source: `{"bool":{"must_not":{"exists":{"field":"a"}}}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.source, esSource(t, testCase.rule), "operator: %s", testCase.rule.Operator)
+	}
+}
+
+func TestCombinedRuleToES(t *testing.T) {
+	rule := querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionOr,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 1},
+			querybuilder.CombinedRule{
+				Condition: querybuilder.ConditionAnd,
+				Rules: []querybuilder.Rule{
+					querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorNotIn, Value: []string{"x"}},
+					querybuilder.AtomRule{Field: "c", Operator: querybuilder.OperatorLess, Value: 2},
+				},
+			},
+		},
+	}
+
+	expected := `{"bool":{"minimum_should_match":"1","should":[{"term":{"a":1}},{"bool":{"must":[` +
+		`{"bool":{"must_not":{"terms":{"b":["x"]}}}},{"range":{"c":{"from":null,"include_lower":true,` +
+		`"include_upper":false,"to":2}}}]}}]}}`
+	assert.Equal(t, expected, esSource(t, rule))
+
+	not := querybuilder.Negate(querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 1})
+	assert.Equal(t, `{"bool":{"must_not":{"term":{"a":1}}}}`, esSource(t, not))
+
+	invalid := querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd,
+		Rules:     []querybuilder.Rule{querybuilder.AtomRule{Field: "a", Operator: "unknown", Value: 1}},
+	}
+	_, key, err := invalid.ToES()
+	assert.Error(t, err)
+	assert.Equal(t, "rules[0].operator", key)
+}
@@ -0,0 +1,215 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ExplainNode is a node of the explained rule tree, which shows how the rule is converted to the mongo filter and
+// which limits of the rule option apply to it, it is used to debug why a filter returns unexpected results.
+type ExplainNode struct {
+	// Condition the condition of the combined rule, it is empty for the atom rule.
+	Condition Condition   `json:"condition,omitempty"`
+	Field     string      `json:"field,omitempty"`
+	Operator  Operator    `json:"operator,omitempty"`
+	Value     interface{} `json:"value,omitempty"`
+	// Mongo the mongo filter that the rule is converted to, including the filters of its children.
+	Mongo map[string]interface{} `json:"mongo,omitempty"`
+	// Limits the limits of the rule option that apply to the rule, with the value used by the rule.
+	Limits []string `json:"limits,omitempty"`
+	// Error the reason why the rule is rejected by the validation or the conversion.
+	Error    string         `json:"error,omitempty"`
+	Children []*ExplainNode `json:"children,omitempty"`
+}
+
+// Explain returns the explained atom rule.
+func (r AtomRule) Explain(option *RuleOption) *ExplainNode {
+	if option == nil {
+		option = new(RuleOption)
+	}
+
+	node := &ExplainNode{Field: r.Field, Operator: r.Operator, Value: r.Value, Limits: r.explainLimits(option)}
+	if key, err := r.Validate(option); err != nil {
+		node.Error = fmt.Sprintf("%s: %v", key, err)
+		return node
+	}
+
+	mgoFilter, key, err := r.ToMgo()
+	if err != nil {
+		node.Error = fmt.Sprintf("%s: %v", key, err)
+		return node
+	}
+	node.Mongo = mgoFilter
+	return node
+}
+
+// explainLimits returns the limits of the rule option that apply to the atom rule.
+func (r AtomRule) explainLimits(option *RuleOption) []string {
+	limits := make([]string, 0)
+	if operators, exists := option.FieldOperators[r.Field]; exists {
+		limits = append(limits, fmt.Sprintf("FieldOperators: %v", operators))
+	}
+
+	switch r.Operator {
+	case OperatorIn, OperatorNotIn:
+		if option.MaxSliceElementsCount > 0 {
+			limits = append(limits, fmt.Sprintf("MaxSliceElementsCount: %d/%d", explainValueLen(r.Value),
+				option.MaxSliceElementsCount))
+		}
+		if option.NeedSameSliceElementType {
+			limits = append(limits, "NeedSameSliceElementType")
+		}
+	case OperatorWithinDays, OperatorWithinHours, OperatorOlderThan:
+		value, _ := relativeTimeValue(r.Value)
+		limits = append(limits, fmt.Sprintf("MaxRelativeDays: %d/%d", value, r.maxRelativeValue(option)))
+	}
+
+	if option.MaxValueBytes > 0 && r.Operator != OperatorIn && r.Operator != OperatorNotIn {
+		size, _ := valueBytes(r.Value)
+		limits = append(limits, fmt.Sprintf("MaxValueBytes: %d/%d", size, option.MaxValueBytes))
+	}
+	if option.MaxStringLength > 0 {
+		limits = append(limits, fmt.Sprintf("MaxStringLength: %d/%d", maxStringLength(r.Value),
+			option.MaxStringLength))
+	}
+
+	if fieldType, exists := option.RuleFields[r.Field]; exists {
+		limits = append(limits, fmt.Sprintf("RuleFields: %s", fieldType))
+	}
+	return limits
+}
+
+// explainValueLen returns the element count of the array value, returns 0 if it is not an array.
+func explainValueLen(value interface{}) int {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Array && v.Kind() != reflect.Slice {
+		return 0
+	}
+	return v.Len()
+}
+
+// Explain returns the explained combined rule with its explained children, the children are explained even if the
+// combined rule is invalid, so that all the problems of the tree are shown at once.
+func (r CombinedRule) Explain(option *RuleOption) *ExplainNode {
+	if option == nil {
+		option = new(RuleOption)
+	}
+
+	node := &ExplainNode{Condition: r.Condition, Limits: make([]string, 0)}
+	switch r.Condition {
+	case ConditionAnd:
+		if option.MaxConditionAndRulesCount > 0 {
+			node.Limits = append(node.Limits, fmt.Sprintf("MaxConditionAndRulesCount: %d/%d", len(r.Rules),
+				option.MaxConditionAndRulesCount))
+		}
+	case ConditionOr:
+		if option.MaxConditionOrRulesCount > 0 {
+			node.Limits = append(node.Limits, fmt.Sprintf("MaxConditionOrRulesCount: %d/%d", len(r.Rules),
+				option.MaxConditionOrRulesCount))
+		}
+	}
+
+	for _, rule := range r.Rules {
+		node.Children = append(node.Children, rule.Explain(option))
+	}
+
+	if key, err := r.validateCombination(option); err != nil {
+		node.Error = fmt.Sprintf("%s: %v", key, err)
+		return node
+	}
+
+	for _, child := range node.Children {
+		if len(child.Error) > 0 {
+			return node
+		}
+	}
+
+	mgoFilter, key, err := r.ToMgo()
+	if err != nil {
+		node.Error = fmt.Sprintf("%s: %v", key, err)
+		return node
+	}
+	node.Mongo = mgoFilter
+	return node
+}
+
+// Explain returns the explained query filter, returns nil if the query filter is empty. The depth of the filter is
+// limited by MaxDeep when it is converted to the db filter.
+func (qf *QueryFilter) Explain(option *RuleOption) *ExplainNode {
+	if qf.Rule == nil {
+		return nil
+	}
+
+	node := qf.Rule.Explain(option)
+	node.Limits = append(node.Limits, fmt.Sprintf("MaxDeep: %d/%d", qf.Rule.GetDeep(), MaxDeep))
+	if len(node.Error) == 0 && qf.Rule.GetDeep() > MaxDeep {
+		node.Error = fmt.Sprintf("exceed max query condition deepth: %d", MaxDeep)
+	}
+	return node
+}
+
+// String renders the explained rule tree in the human-readable form, each line is a rule with its mongo filter,
+// the limits that apply to it and the error if it is rejected, e.g.
+//
+//	AND => $and [MaxConditionAndRulesCount: 2/20]
+//	├── bk_host_innerip in [1.1.1.1] => {"bk_host_innerip":{"$in":["1.1.1.1"]}}
+//	└── bk_cloud_id equal 0 => {"bk_cloud_id":{"$eq":0}}
+func (n *ExplainNode) String() string {
+	if n == nil {
+		return ""
+	}
+
+	builder := new(strings.Builder)
+	n.render(builder, "", "")
+	return builder.String()
+}
+
+// render writes the node line with the prefix, and the children lines with the prefix of their level.
+func (n *ExplainNode) render(builder *strings.Builder, linePrefix, childPrefix string) {
+	builder.WriteString(linePrefix)
+	if len(n.Condition) > 0 {
+		builder.WriteString(string(n.Condition))
+		if mgoOperator, err := n.Condition.ToMgo(); err == nil {
+			builder.WriteString(" => " + mgoOperator)
+		}
+	} else {
+		builder.WriteString(fmt.Sprintf("%s %s %v", n.Field, n.Operator, n.Value))
+		if n.Mongo != nil {
+			mongo, err := json.Marshal(n.Mongo)
+			if err != nil {
+				mongo = []byte(fmt.Sprintf("%v", n.Mongo))
+			}
+			builder.WriteString(" => " + string(mongo))
+		}
+	}
+
+	if len(n.Limits) > 0 {
+		builder.WriteString(" [" + strings.Join(n.Limits, ", ") + "]")
+	}
+	if len(n.Error) > 0 {
+		builder.WriteString(" ERROR: " + n.Error)
+	}
+	builder.WriteString("\n")
+
+	for idx, child := range n.Children {
+		if idx == len(n.Children)-1 {
+			child.render(builder, childPrefix+"└── ", childPrefix+"    ")
+			continue
+		}
+		child.render(builder, childPrefix+"├── ", childPrefix+"│   ")
+	}
+}
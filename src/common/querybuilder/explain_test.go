@@ -0,0 +1,90 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"testing"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	filter := &querybuilder.QueryFilter{
+		Rule: querybuilder.CombinedRule{
+			Condition: querybuilder.ConditionAnd,
+			Rules: []querybuilder.Rule{
+				querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorIn, Value: []string{"x", "y"}},
+				querybuilder.CombinedRule{
+					Condition: querybuilder.ConditionOr,
+					Rules: []querybuilder.Rule{
+						querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorEqual, Value: 1},
+						querybuilder.AtomRule{Field: "c", Operator: querybuilder.OperatorWithinDays, Value: 3},
+					},
+				},
+			},
+		},
+	}
+	option := &querybuilder.RuleOption{MaxSliceElementsCount: 10, MaxConditionAndRulesCount: 5}
+
+	node := filter.Explain(option)
+	assert.Empty(t, node.Error)
+	assert.Equal(t, querybuilder.ConditionAnd, node.Condition)
+	assert.Equal(t, []string{"MaxConditionAndRulesCount: 2/5", "MaxDeep: 3/3"}, node.Limits)
+	assert.Contains(t, node.Mongo, "$and")
+	assert.Len(t, node.Children, 2)
+
+	in := node.Children[0]
+	assert.Equal(t, []string{"MaxSliceElementsCount: 2/10"}, in.Limits)
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"$in": []string{"x", "y"}}}, in.Mongo)
+
+	or := node.Children[1]
+	assert.Equal(t, querybuilder.ConditionOr, or.Condition)
+	assert.Contains(t, or.Mongo, "$or")
+	assert.Equal(t, []string{"MaxRelativeDays: 3/3650"}, or.Children[1].Limits)
+
+	expected := "AND => $and [MaxConditionAndRulesCount: 2/5, MaxDeep: 3/3]\n" +
+		"├── a in [x y] => {\"a\":{\"$in\":[\"x\",\"y\"]}} [MaxSliceElementsCount: 2/10]\n" +
+		"└── OR => $or\n" +
+		"    ├── b equal 1 => {\"b\":{\"$eq\":1}}\n"
+	assert.Contains(t, node.String(), expected)
+}
+
+func TestExplainInvalid(t *testing.T) {
+	rule := querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionOr,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorIn, Value: []int{1, 2, 3}},
+			querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorRegex, Value: "x"},
+		},
+	}
+	option := &querybuilder.RuleOption{
+		MaxSliceElementsCount:    2,
+		MaxConditionOrRulesCount: 1,
+		FieldOperators:           map[string][]querybuilder.Operator{"b": {querybuilder.OperatorEqual}},
+	}
+
+	node := rule.Explain(option)
+	assert.Contains(t, node.Error, "too many rules of OR condition")
+	assert.Nil(t, node.Mongo)
+
+	// the children are explained even if the parent is invalid
+	assert.Equal(t, []string{"MaxSliceElementsCount: 3/2"}, node.Children[0].Limits)
+	assert.NotEmpty(t, node.Children[0].Error)
+	assert.Equal(t, []string{"FieldOperators: [equal]"}, node.Children[1].Limits)
+	assert.Contains(t, node.Children[1].Error, "operator regex is not allowed on field b")
+	assert.Contains(t, node.String(), "ERROR: ")
+
+	assert.Nil(t, new(querybuilder.QueryFilter).Explain(option))
+}
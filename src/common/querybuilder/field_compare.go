@@ -0,0 +1,244 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"configcenter/src/common"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// FieldCompareValue is the value of the field_compare operator, the rule field is compared with the other field of
+// the same record, e.g. {"field": "cpu_limit", "operator": "greater"} on the field cpu_used matches the records
+// whose cpu_used is greater than cpu_limit. Only the records with both fields set match the comparison.
+type FieldCompareValue struct {
+	// Field the other field that the rule field is compared with.
+	Field string `json:"field"`
+	// Operator the comparison operator, which is one of the equal, not_equal and the numeric compare operators.
+	Operator Operator `json:"operator"`
+}
+
+// fieldCompareMgoOperators the mongo aggregation operators of the field compare operators
+var fieldCompareMgoOperators = map[Operator]string{
+	OperatorEqual:          common.BKDBEQ,
+	OperatorNotEqual:       common.BKDBNE,
+	OperatorLess:           common.BKDBLT,
+	OperatorLessOrEqual:    common.BKDBLTE,
+	OperatorGreater:        common.BKDBGT,
+	OperatorGreaterOrEqual: common.BKDBGTE,
+}
+
+// fieldCompareSymbols the sql comparison symbols of the field compare operators, the es script uses them too except
+// for the equal and not_equal ones
+var fieldCompareSymbols = map[Operator]string{
+	OperatorEqual:          "=",
+	OperatorNotEqual:       "<>",
+	OperatorLess:           "<",
+	OperatorLessOrEqual:    "<=",
+	OperatorGreater:        ">",
+	OperatorGreaterOrEqual: ">=",
+}
+
+// fieldCompareKinds the kinds of the comparable field types, the fields can only be compared with the fields of the
+// same kind, e.g. an int field can be compared with a float field, but not with a string field.
+var fieldCompareKinds = map[string]string{
+	common.FieldTypeInt:        TypeNumeric,
+	common.FieldTypeFloat:      TypeNumeric,
+	common.FieldTypeSingleChar: TypeString,
+	common.FieldTypeLongChar:   TypeString,
+	common.FieldTypeEnum:       TypeString,
+	common.FieldTypeList:       TypeString,
+	common.FieldTypeDate:       common.FieldTypeDate,
+	common.FieldTypeTime:       common.FieldTypeTime,
+	TypeNumeric:                TypeNumeric,
+	TypeString:                 TypeString,
+}
+
+// fieldCompareValue parses the value of the field_compare operator, which can be the FieldCompareValue or the map
+// decoded from json.
+func fieldCompareValue(value interface{}) (FieldCompareValue, error) {
+	var compare FieldCompareValue
+	switch v := normalizeValue(value).(type) {
+	case FieldCompareValue:
+		compare = v
+	case *FieldCompareValue:
+		if v == nil {
+			return FieldCompareValue{}, fmt.Errorf("field compare value is nil")
+		}
+		compare = *v
+	case map[string]interface{}:
+		field, ok := v["field"].(string)
+		if !ok {
+			return FieldCompareValue{}, fmt.Errorf("field compare value field %v is not a string", v["field"])
+		}
+		operator, ok := v["operator"].(string)
+		if !ok {
+			return FieldCompareValue{}, fmt.Errorf("field compare value operator %v is not a string", v["operator"])
+		}
+		if len(v) != 2 {
+			return FieldCompareValue{}, fmt.Errorf("field compare value only has field and operator, value: %v", v)
+		}
+		compare = FieldCompareValue{Field: field, Operator: Operator(operator)}
+	default:
+		return FieldCompareValue{}, fmt.Errorf("unexpected field compare value: %#v", value)
+	}
+
+	if !ValidFieldPattern.MatchString(compare.Field) {
+		return FieldCompareValue{}, fmt.Errorf("invalid compared field: %s", compare.Field)
+	}
+	if _, exists := fieldCompareMgoOperators[compare.Operator]; !exists {
+		return FieldCompareValue{}, fmt.Errorf("unsupported field compare operator: %s", compare.Operator)
+	}
+	return compare, nil
+}
+
+// validateFieldCompareValue validates the value of the field_compare operator, both fields should be in the rule
+// fields with comparable types if the rule fields are set.
+func (r AtomRule) validateFieldCompareValue(option *RuleOption) error {
+	compare, err := fieldCompareValue(r.Value)
+	if err != nil {
+		return err
+	}
+
+	if compare.Field == r.Field {
+		return fmt.Errorf("field %s can not be compared with itself", r.Field)
+	}
+
+	if option.RuleFields == nil {
+		return nil
+	}
+
+	fieldType, exists := option.RuleFields[r.Field]
+	if !exists {
+		return fmt.Errorf("field %s is not found", r.Field)
+	}
+	otherType, exists := option.RuleFields[compare.Field]
+	if !exists {
+		return fmt.Errorf("compared field %s is not found", compare.Field)
+	}
+
+	kind, comparable := fieldCompareKinds[fieldType]
+	if !comparable || kind != fieldCompareKinds[otherType] {
+		return fmt.Errorf("field %s of type %s is not comparable with field %s of type %s", r.Field, fieldType,
+			compare.Field, otherType)
+	}
+	return nil
+}
+
+// fieldCompareMgo generates the $expr comparison of the fields, the fields compared by $expr are the values of the
+// field paths as a whole, so they should not go through the arrays. The null or missing fields are excluded since
+// $expr compares the values of different types by the bson type order.
+func (r AtomRule) fieldCompareMgo() (map[string]interface{}, error) {
+	compare, err := fieldCompareValue(r.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldPath, otherPath := "$"+r.Field, "$"+compare.Field
+	return map[string]interface{}{
+		common.BKDBExpr: map[string]interface{}{
+			common.BKDBAND: []interface{}{
+				map[string]interface{}{common.BKDBGT: []interface{}{fieldPath, nil}},
+				map[string]interface{}{common.BKDBGT: []interface{}{otherPath, nil}},
+				map[string]interface{}{
+					fieldCompareMgoOperators[compare.Operator]: []interface{}{fieldPath, otherPath},
+				},
+			},
+		},
+	}, nil
+}
+
+// fieldCompareES generates the elasticsearch script query of the field comparison, the numbers are compared by their
+// values, and the other values are compared by their natural order, the unmapped or empty fields match nothing.
+func (r AtomRule) fieldCompareES() (elastic.Query, error) {
+	compare, err := fieldCompareValue(r.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol := fieldCompareSymbols[compare.Operator]
+	switch compare.Operator {
+	case OperatorEqual:
+		symbol = "=="
+	case OperatorNotEqual:
+		symbol = "!="
+	}
+
+	source := "if (!doc.containsKey(params.field) || !doc.containsKey(params.other) || " +
+		"doc[params.field].size() == 0 || doc[params.other].size() == 0) { return false; } " +
+		"def a = doc[params.field].value; def b = doc[params.other].value; " +
+		"int c = a instanceof Number && b instanceof Number ? " +
+		"Double.compare(a.doubleValue(), b.doubleValue()) : a.compareTo(b); " +
+		fmt.Sprintf("return c %s 0;", symbol)
+	script := elastic.NewScript(source).Params(map[string]interface{}{"field": r.Field, "other": compare.Field})
+	return elastic.NewScriptQuery(script), nil
+}
+
+// fieldCompareToSQL compares the columns of the fields, the null columns match nothing.
+func (r AtomRule) fieldCompareToSQL(opt *SQLOption, column string) (string, []interface{}, string, error) {
+	compare, err := fieldCompareValue(r.Value)
+	if err != nil {
+		return "", nil, "value", err
+	}
+
+	other, err := opt.column(compare.Field)
+	if err != nil {
+		return "", nil, "value", err
+	}
+	return fmt.Sprintf("%s %s %s", column, fieldCompareSymbols[compare.Operator], other), make([]interface{}, 0), "",
+		nil
+}
+
+// matchFieldCompare checks if the values of the fields in the document satisfy the comparison, the fields should be
+// single values as the $expr comparison does not go through the arrays.
+func (r AtomRule) matchFieldCompare(doc map[string]interface{}, values []interface{}) (bool, error) {
+	compare, err := fieldCompareValue(r.Value)
+	if err != nil {
+		return false, err
+	}
+
+	others := lookupField(doc, strings.Split(compare.Field, "."))
+	if len(values) != 1 || len(others) != 1 {
+		return false, nil
+	}
+
+	value, other := normalizeValue(values[0]), normalizeValue(others[0])
+	if value == nil || other == nil {
+		return false, nil
+	}
+
+	c, ok := compareValue(value, other)
+	if !ok {
+		return false, nil
+	}
+
+	switch compare.Operator {
+	case OperatorEqual:
+		return c == 0, nil
+	case OperatorNotEqual:
+		return c != 0, nil
+	case OperatorLess:
+		return c < 0, nil
+	case OperatorLessOrEqual:
+		return c <= 0, nil
+	case OperatorGreater:
+		return c > 0, nil
+	case OperatorGreaterOrEqual:
+		return c >= 0, nil
+	default:
+		return false, fmt.Errorf("unsupported field compare operator: %s", compare.Operator)
+	}
+}
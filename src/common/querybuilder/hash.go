@@ -0,0 +1,153 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"configcenter/src/common"
+)
+
+// Hash returns the canonical hash of the query filter, the filters that differ only in the order of the sub rules,
+// the order of the in/not_in values, the json key order or the numeric value types have the same hash, so it can be
+// used to deduplicate the saved filters or as the cache key of them.
+func (qf *QueryFilter) Hash() (string, error) {
+	if qf == nil || qf.Rule == nil {
+		return "", fmt.Errorf("query filter is empty")
+	}
+	return RuleHash(qf.Rule)
+}
+
+// RuleHash returns the canonical hash of the rule tree.
+func RuleHash(rule Rule) (string, error) {
+	canonical, err := canonicalRule(rule)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// compiledRuleHash returns the canonical hash of the rule tree and the validate option.
+func compiledRuleHash(rule Rule, option *RuleOption) (string, error) {
+	canonical, err := canonicalRule(rule)
+	if err != nil {
+		return "", err
+	}
+
+	opt, err := json.Marshal(option)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(canonical + "|" + string(opt)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalRule returns the canonical form of the rule tree, the sub rules of the combined rule are sorted and
+// deduplicated since all the conditions are commutative and idempotent, so that the same rules in different orders
+// have the same canonical form.
+func canonicalRule(rule Rule) (string, error) {
+	switch r := rule.(type) {
+	case AtomRule:
+		value, err := canonicalValue(r.Value, isSetOperator(r.Operator))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%q %q %s", r.Field, r.Operator, value), nil
+
+	case CombinedRule:
+		subRules := make([]string, len(r.Rules))
+		for idx, subRule := range r.Rules {
+			canonical, err := canonicalRule(subRule)
+			if err != nil {
+				return "", err
+			}
+			subRules[idx] = canonical
+		}
+		return fmt.Sprintf("%s(%s)", r.Condition, strings.Join(sortUnique(subRules), ",")), nil
+	}
+
+	return "", fmt.Errorf("unsupported rule type %T", rule)
+}
+
+// isSetOperator checks if the order of the values of the operator does not matter, the mongo operators are included
+// so that the conditions stored in the mongo operators can be hashed too.
+func isSetOperator(operator Operator) bool {
+	switch operator {
+	case OperatorIn, OperatorNotIn, common.BKDBIN, common.BKDBNIN:
+		return true
+	}
+	return false
+}
+
+// canonicalValue returns the canonical form of the rule value, the numbers of different types are formatted by
+// their values, and the elements of the slice are sorted and deduplicated if it is a set.
+func canonicalValue(value interface{}, isSet bool) (string, error) {
+	if getType(value) == TypeNumeric {
+		if number, err := int64Value(value); err == nil {
+			return strconv.FormatInt(number, 10), nil
+		}
+
+		number, err := float64Value(value)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(number, 'g', -1, 64), nil
+	}
+
+	v := reflect.ValueOf(value)
+	if value == nil || (v.Kind() != reflect.Array && v.Kind() != reflect.Slice) {
+		// the keys of the map are sorted by json marshal.
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+
+	elements := make([]string, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		element, err := canonicalValue(v.Index(i).Interface(), false)
+		if err != nil {
+			return "", err
+		}
+		elements[i] = element
+	}
+
+	if isSet {
+		elements = sortUnique(elements)
+	}
+	return "[" + strings.Join(elements, ",") + "]", nil
+}
+
+// sortUnique sorts and deduplicates the strings.
+func sortUnique(values []string) []string {
+	sort.Strings(values)
+	result := values[:0]
+	for _, value := range values {
+		if len(result) > 0 && value == result[len(result)-1] {
+			continue
+		}
+		result = append(result, value)
+	}
+	return result
+}
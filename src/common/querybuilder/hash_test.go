@@ -0,0 +1,88 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hashOf(t *testing.T, qf *querybuilder.QueryFilter) string {
+	hash, err := qf.Hash()
+	require.NoError(t, err)
+	return hash
+}
+
+func TestQueryFilterHash(t *testing.T) {
+	name := querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorEqual, Value: "a"}
+	ids := querybuilder.AtomRule{Field: "id", Operator: querybuilder.OperatorIn, Value: []interface{}{1, 2, 3}}
+
+	base := &querybuilder.QueryFilter{Rule: querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd,
+		Rules:     []querybuilder.Rule{name, ids},
+	}}
+
+	// the sub rule order, in values order, numeric types and duplicate rules do not change the hash.
+	same := &querybuilder.QueryFilter{Rule: querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "id", Operator: querybuilder.OperatorIn,
+				Value: []interface{}{3.0, json.Number("1"), int64(2), 2}},
+			name,
+			name,
+		},
+	}}
+	assert.Equal(t, hashOf(t, base), hashOf(t, same))
+
+	// the json decoded filter with different key order has the same hash.
+	decoded := new(querybuilder.QueryFilter)
+	require.NoError(t, json.Unmarshal([]byte(`{"rules":[{"value":"a","operator":"equal","field":"name"},
+		{"operator":"in","field":"id","value":[2,1,3]}],"condition":"AND"}`), decoded))
+	assert.Equal(t, hashOf(t, base), hashOf(t, decoded))
+
+	different := []querybuilder.Rule{
+		querybuilder.CombinedRule{Condition: querybuilder.ConditionOr, Rules: []querybuilder.Rule{name, ids}},
+		querybuilder.CombinedRule{Condition: querybuilder.ConditionAnd, Rules: []querybuilder.Rule{name}},
+		querybuilder.CombinedRule{Condition: querybuilder.ConditionAnd, Rules: []querybuilder.Rule{name,
+			querybuilder.AtomRule{Field: "id", Operator: querybuilder.OperatorIn, Value: []interface{}{1, 2}}}},
+		querybuilder.CombinedRule{Condition: querybuilder.ConditionAnd, Rules: []querybuilder.Rule{ids,
+			querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorEqual, Value: "1"}}},
+	}
+	for _, rule := range different {
+		assert.NotEqual(t, hashOf(t, base), hashOf(t, &querybuilder.QueryFilter{Rule: rule}), "rule: %+v", rule)
+	}
+
+	_, err := (*querybuilder.QueryFilter)(nil).Hash()
+	assert.Error(t, err)
+}
+
+func TestRuleHashValueOrder(t *testing.T) {
+	// the order of the values of the non-set operators matters.
+	a, err := querybuilder.RuleHash(querybuilder.AtomRule{Field: "f", Operator: "custom", Value: []interface{}{1, 2}})
+	require.NoError(t, err)
+	b, err := querybuilder.RuleHash(querybuilder.AtomRule{Field: "f", Operator: "custom", Value: []interface{}{2, 1}})
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+
+	// the mongo set operators are order independent too.
+	a, err = querybuilder.RuleHash(querybuilder.AtomRule{Field: "f", Operator: "$nin", Value: []string{"x", "y"}})
+	require.NoError(t, err)
+	b, err = querybuilder.RuleHash(querybuilder.AtomRule{Field: "f", Operator: "$nin", Value: []string{"y", "x"}})
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+}
@@ -0,0 +1,410 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MatchDoc checks if the document matches the rule in memory, the rule is evaluated with the same semantics as the
+// mongo condition generated by ToMgo, so that the document decoded from the change stream can be filtered without
+// querying mongo. The dotted field is the path of the nested field, the arrays on the path are traversed and the
+// array field matches if any of its elements matches, the numbers of different types are compared by their values.
+func (r AtomRule) MatchDoc(doc map[string]interface{}) (bool, error) {
+	if key, err := r.Validate(&RuleOption{NeedSameSliceElementType: true}); err != nil {
+		return false, fmt.Errorf("validate failed, key: %s, err: %s", key, err)
+	}
+
+	values := lookupField(doc, strings.Split(r.Field, "."))
+
+	switch r.Operator {
+	case OperatorEqual:
+		return matchEqual(values, r.Value), nil
+	case OperatorNotEqual:
+		return !matchEqual(values, r.Value), nil
+	case OperatorIn:
+		return matchIn(values, r.Value), nil
+	case OperatorNotIn:
+		return !matchIn(values, r.Value), nil
+	case OperatorLess:
+		return matchCompare(values, r.Value, func(c int) bool { return c < 0 }), nil
+	case OperatorLessOrEqual:
+		return matchCompare(values, r.Value, func(c int) bool { return c <= 0 }), nil
+	case OperatorGreater:
+		return matchCompare(values, r.Value, func(c int) bool { return c > 0 }), nil
+	case OperatorGreaterOrEqual:
+		return matchCompare(values, r.Value, func(c int) bool { return c >= 0 }), nil
+	case OperatorDatetimeLess:
+		return matchCompare(values, r.Value, func(c int) bool { return c < 0 }), nil
+	case OperatorDatetimeLessOrEqual:
+		return matchCompare(values, r.Value, func(c int) bool { return c <= 0 }), nil
+	case OperatorDatetimeGreater:
+		return matchCompare(values, r.Value, func(c int) bool { return c > 0 }), nil
+	case OperatorDatetimeGreaterOrEqual:
+		return matchCompare(values, r.Value, func(c int) bool { return c >= 0 }), nil
+	case OperatorWithinDays, OperatorWithinHours:
+		boundary, err := r.relativeTime(time.Now())
+		if err != nil {
+			return false, err
+		}
+		return matchCompare(values, boundary, func(c int) bool { return c >= 0 }), nil
+	case OperatorOlderThan:
+		boundary, err := r.relativeTime(time.Now())
+		if err != nil {
+			return false, err
+		}
+		return matchCompare(values, boundary, func(c int) bool { return c < 0 }), nil
+	case OperatorBeginsWith:
+		return matchRegex(values, fmt.Sprintf("^%s", r.Value))
+	case OperatorNotBeginsWith:
+		matched, err := matchRegex(values, fmt.Sprintf("^%s", r.Value))
+		return !matched, err
+	case OperatorContains:
+		return matchRegex(values, fmt.Sprintf("(?i)%s", r.Value))
+	case OperatorNotContains:
+		matched, err := matchRegex(values, fmt.Sprintf("%s", r.Value))
+		return !matched, err
+	case OperatorsEndsWith:
+		return matchRegex(values, fmt.Sprintf("%s$", r.Value))
+	case OperatorNotEndsWith:
+		matched, err := matchRegex(values, fmt.Sprintf("%s$", r.Value))
+		return !matched, err
+	case OperatorRegex:
+		return matchRegex(values, fmt.Sprintf("%s", r.Value))
+	case OperatorIRegex:
+		return matchRegex(values, fmt.Sprintf("(?i)%s", r.Value))
+	case OperatorInCIDR:
+		pattern, err := r.cidrRegex()
+		if err != nil {
+			return false, err
+		}
+		return matchRegex(values, pattern)
+	case OperatorSize, OperatorSizeGreaterOrEqual, OperatorSizeLessOrEqual:
+		return r.matchSize(values)
+	case OperatorFieldCompare:
+		return r.matchFieldCompare(doc, values)
+	case OperatorIsEmpty:
+		return matchEqual(values, make([]interface{}, 0)), nil
+	case OperatorIsNotEmpty:
+		return !matchEqual(values, make([]interface{}, 0)), nil
+	case OperatorIsNull:
+		return matchEqual(values, nil), nil
+	case OperatorIsNotNull:
+		return !matchEqual(values, nil), nil
+	case OperatorExist:
+		return len(values) > 0, nil
+	case OperatorNotExist:
+		return len(values) == 0, nil
+	default:
+		return r.matchCustom(values)
+	}
+}
+
+// MatchDoc checks if the document matches the combined rule in memory.
+func (r CombinedRule) MatchDoc(doc map[string]interface{}) (bool, error) {
+	if err := r.Condition.Validate(); err != nil {
+		return false, err
+	}
+	if len(r.Rules) == 0 {
+		return false, fmt.Errorf("combined rules shouldn't be empty")
+	}
+
+	for idx, rule := range r.Rules {
+		matched, err := rule.MatchDoc(doc)
+		if err != nil {
+			return false, fmt.Errorf("rules[%d]: %v", idx, err)
+		}
+
+		switch r.Condition {
+		case ConditionAnd:
+			if !matched {
+				return false, nil
+			}
+		case ConditionOr:
+			if matched {
+				return true, nil
+			}
+		case ConditionNot:
+			if matched {
+				return false, nil
+			}
+		}
+	}
+
+	return r.Condition != ConditionOr, nil
+}
+
+// lookupField returns the values of the field path in the document, the arrays on the path are traversed like mongo
+// does, the element of the array is also looked up by the index if the path is a number. Nothing is returned if the
+// field does not exist.
+func lookupField(value interface{}, path []string) []interface{} {
+	if len(path) == 0 {
+		return []interface{}{value}
+	}
+
+	switch v := normalizeValue(value).(type) {
+	case map[string]interface{}:
+		child, exists := v[path[0]]
+		if !exists {
+			return nil
+		}
+		return lookupField(child, path[1:])
+
+	case []interface{}:
+		values := make([]interface{}, 0)
+		if index, err := strconv.Atoi(path[0]); err == nil && index >= 0 && index < len(v) {
+			values = append(values, lookupField(v[index], path[1:])...)
+		}
+		for _, elem := range v {
+			if _, ok := normalizeValue(elem).(map[string]interface{}); ok {
+				values = append(values, lookupField(elem, path)...)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
+
+// candidateValues returns the values to be compared with the rule value, the array field is compared both as a
+// whole and by each of its elements.
+func candidateValues(values []interface{}) []interface{} {
+	candidates := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		value = normalizeValue(value)
+		candidates = append(candidates, value)
+		if arr, ok := value.([]interface{}); ok {
+			for _, elem := range arr {
+				candidates = append(candidates, normalizeValue(elem))
+			}
+		}
+	}
+	return candidates
+}
+
+// matchEqual checks if any of the values equals to the target, the nil target also matches the non-exist field.
+func matchEqual(values []interface{}, target interface{}) bool {
+	target = normalizeValue(target)
+	if target == nil && len(values) == 0 {
+		return true
+	}
+
+	for _, candidate := range candidateValues(values) {
+		if equalValue(candidate, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchIn checks if any of the values equals to any of the targets.
+func matchIn(values []interface{}, targets interface{}) bool {
+	arr, ok := normalizeValue(targets).([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, target := range arr {
+		if matchEqual(values, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCompare checks if any of the values of the same type as the target satisfies the comparison.
+func matchCompare(values []interface{}, target interface{}, satisfy func(c int) bool) bool {
+	target = normalizeValue(target)
+	for _, candidate := range candidateValues(values) {
+		if c, ok := compareValue(candidate, target); ok && satisfy(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRegex checks if any of the string values matches the regular expression.
+func matchRegex(values []interface{}, pattern string) (bool, error) {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, fmt.Errorf("invalid regular expression %s, err: %v", pattern, err)
+	}
+
+	for _, candidate := range candidateValues(values) {
+		if str, ok := candidate.(string); ok && regex.MatchString(str) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// normalizeValue converts the bson and json decoded values to the generic types, the arrays are converted to
+// []interface{}, the documents are converted to map[string]interface{}, the dates are converted to time.Time.
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, time.Time, map[string]interface{}, []interface{}:
+		return v
+	case primitive.DateTime:
+		return v.Time()
+	case primitive.A:
+		return []interface{}(v)
+	case primitive.M:
+		return map[string]interface{}(v)
+	case primitive.D:
+		doc := make(map[string]interface{}, len(v))
+		for _, elem := range v {
+			doc[elem.Key] = elem.Value
+		}
+		return doc
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		arr := make([]interface{}, rv.Len())
+		for idx := range arr {
+			arr[idx] = rv.Index(idx).Interface()
+		}
+		return arr
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return value
+		}
+		doc := make(map[string]interface{}, rv.Len())
+		for _, key := range rv.MapKeys() {
+			doc[key.String()] = rv.MapIndex(key).Interface()
+		}
+		return doc
+	case reflect.String:
+		return rv.String()
+	}
+	return value
+}
+
+// numberValue returns the value as int64 if it is an integer, or as float64 if it is a float number.
+func numberValue(value interface{}) (i int64, f float64, isInt bool, ok bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), float64(rv.Int()), true, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, float64(rv.Uint()), false, true
+		}
+		return int64(rv.Uint()), float64(rv.Uint()), true, true
+	case reflect.Float32, reflect.Float64:
+		return 0, rv.Float(), false, true
+	}
+	return 0, 0, false, false
+}
+
+// compareValue compares the values of the same type, the numbers of different types are compared by their values,
+// ok is false if the values are not comparable, which never match the comparison like mongo does.
+func compareValue(a, b interface{}) (c int, ok bool) {
+	ai, af, aInt, aNum := numberValue(a)
+	bi, bf, bInt, bNum := numberValue(b)
+	if aNum && bNum {
+		if aInt && bInt {
+			return compareOrdered(ai < bi, ai > bi), true
+		}
+		if math.IsNaN(af) || math.IsNaN(bf) {
+			return 0, false
+		}
+		return compareOrdered(af < bf, af > bf), true
+	}
+
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), true
+		}
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			return compareOrdered(av.Before(bv), av.After(bv)), true
+		}
+	}
+	return 0, false
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+// equalValue checks if the values are equal, the arrays and the documents are compared by their elements.
+func equalValue(a, b interface{}) bool {
+	a, b = normalizeValue(a), normalizeValue(b)
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	if c, ok := compareValue(a, b); ok {
+		return c == 0
+	}
+
+	switch av := a.(type) {
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for idx := range av {
+			if !equalValue(av[idx], bv[idx]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, exists := bv[key]
+			if !exists || !equalValue(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
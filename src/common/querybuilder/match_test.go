@@ -0,0 +1,171 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"testing"
+	"time"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAtomRuleMatchDoc(t *testing.T) {
+	doc := map[string]interface{}{
+		"id":     int32(10),
+		"name":   "cmdb-web",
+		"score":  9.5,
+		"tags":   primitive.A{"a", "b"},
+		"empty":  []string{},
+		"null":   nil,
+		"ip":     "10.0.0.1,192.168.1.1",
+		"create": primitive.NewDateTimeFromTime(time.Now().Add(-2 * time.Hour)),
+		"date":   "2022-10-01",
+		"owner":  bson.M{"name": "admin", "ids": []int64{1, 2}},
+		"disks":  bson.A{bson.M{"size": int64(100)}, bson.D{{Key: "size", Value: 200.0}}},
+	}
+
+	testCases := []struct {
+		rule    querybuilder.AtomRule
+		matched bool
+	}{
+		// numbers of different types are compared by their values
+		{rule: querybuilder.AtomRule{Field: "id", Operator: querybuilder.OperatorEqual, Value: 10.0}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "id", Operator: querybuilder.OperatorIn, Value: []int{1, 10}}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "id", Operator: querybuilder.OperatorGreater, Value: 9.9}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "score", Operator: querybuilder.OperatorLess, Value: 9}, matched: false},
+		// the values of different types are not comparable
+		{rule: querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorGreater, Value: 1}, matched: false},
+		// the negative operators match the non-exist field
+		{rule: querybuilder.AtomRule{Field: "none", Operator: querybuilder.OperatorNotEqual, Value: 1}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "none", Operator: querybuilder.OperatorNotIn, Value: []int{1}},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorNotEqual, Value: "cmdb-web"},
+			matched: false},
+		// the array field matches if any of its elements matches
+		{rule: querybuilder.AtomRule{Field: "tags", Operator: querybuilder.OperatorEqual, Value: "b"}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "tags", Operator: querybuilder.OperatorNotIn, Value: []string{"a"}},
+			matched: false},
+		{rule: querybuilder.AtomRule{Field: "tags", Operator: querybuilder.OperatorIsEmpty}, matched: false},
+		{rule: querybuilder.AtomRule{Field: "empty", Operator: querybuilder.OperatorIsEmpty}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "none", Operator: querybuilder.OperatorIsNotEmpty}, matched: true},
+		// the nested fields and the documents in the array
+		{rule: querybuilder.AtomRule{Field: "owner.name", Operator: querybuilder.OperatorEqual, Value: "admin"},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "owner.ids", Operator: querybuilder.OperatorEqual, Value: 2},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "disks.size", Operator: querybuilder.OperatorGreaterOrEqual, Value: 200},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "disks.0.size", Operator: querybuilder.OperatorEqual, Value: 100},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "disks.size", Operator: querybuilder.OperatorExist}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "disks.type", Operator: querybuilder.OperatorExist}, matched: false},
+		// null and existence
+		{rule: querybuilder.AtomRule{Field: "null", Operator: querybuilder.OperatorIsNull}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "none", Operator: querybuilder.OperatorIsNull}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "null", Operator: querybuilder.OperatorExist}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "none", Operator: querybuilder.OperatorNotExist}, matched: true},
+		// string operators
+		{rule: querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorBeginsWith, Value: "cmdb"},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorContains, Value: "WEB"},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorNotEndsWith, Value: "web"},
+			matched: false},
+		{rule: querybuilder.AtomRule{Field: "tags", Operator: querybuilder.OperatorRegex, Value: "^b$"},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "ip", Operator: querybuilder.OperatorInCIDR, Value: "192.168.0.0/16"},
+			matched: true},
+		// size operators only match the array fields
+		{rule: querybuilder.AtomRule{Field: "tags", Operator: querybuilder.OperatorSize, Value: 2}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "empty", Operator: querybuilder.OperatorSize, Value: 0}, matched: true},
+		{rule: querybuilder.AtomRule{Field: "tags", Operator: querybuilder.OperatorSizeGreaterOrEqual, Value: 3},
+			matched: false},
+		{rule: querybuilder.AtomRule{Field: "owner.ids", Operator: querybuilder.OperatorSizeLessOrEqual, Value: 2},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorSizeLessOrEqual, Value: 10},
+			matched: false},
+		{rule: querybuilder.AtomRule{Field: "none", Operator: querybuilder.OperatorSize, Value: 0}, matched: false},
+		// field compare operator compares the values of the fields, and the unset fields do not match
+		{rule: querybuilder.AtomRule{Field: "id", Operator: querybuilder.OperatorFieldCompare,
+			Value: querybuilder.FieldCompareValue{Field: "score", Operator: querybuilder.OperatorGreater}},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "owner.name", Operator: querybuilder.OperatorFieldCompare,
+			Value: map[string]interface{}{"field": "name", "operator": "greater_or_equal"}}, matched: false},
+		{rule: querybuilder.AtomRule{Field: "null", Operator: querybuilder.OperatorFieldCompare,
+			Value: querybuilder.FieldCompareValue{Field: "none", Operator: querybuilder.OperatorEqual}},
+			matched: false},
+		// time operators
+		{rule: querybuilder.AtomRule{Field: "date", Operator: querybuilder.OperatorDatetimeLess, Value: "2022-10-02"},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "create", Operator: querybuilder.OperatorWithinHours, Value: 3},
+			matched: true},
+		{rule: querybuilder.AtomRule{Field: "create", Operator: querybuilder.OperatorOlderThan, Value: 1},
+			matched: false},
+	}
+
+	for _, testCase := range testCases {
+		matched, err := testCase.rule.MatchDoc(doc)
+		require.NoError(t, err, "rule: %+v", testCase.rule)
+		assert.Equal(t, testCase.matched, matched, "rule: %+v", testCase.rule)
+	}
+}
+
+func TestCombinedRuleMatchDoc(t *testing.T) {
+	doc := map[string]interface{}{"a": 1, "b": "x"}
+
+	filter := querybuilder.QueryFilter{Rule: querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionOr,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 2},
+			querybuilder.CombinedRule{
+				Condition: querybuilder.ConditionNot,
+				Rules: []querybuilder.Rule{
+					querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorEqual, Value: "y"},
+				},
+			},
+		},
+	}}
+	matched, err := filter.MatchDoc(doc)
+	require.NoError(t, err)
+	assert.True(t, matched)
+
+	filter.Rule = querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 1},
+			querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorIn, Value: []string{"y", "z"}},
+		},
+	}
+	matched, err = filter.MatchDoc(doc)
+	require.NoError(t, err)
+	assert.False(t, matched)
+
+	// the invalid rule is reported
+	filter.Rule = querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorRegex, Value: "("},
+		},
+	}
+	_, err = filter.MatchDoc(doc)
+	assert.Error(t, err)
+
+	matched, err = new(querybuilder.QueryFilter).MatchDoc(doc)
+	require.NoError(t, err)
+	assert.True(t, matched)
+}
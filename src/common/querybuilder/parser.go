@@ -94,6 +94,24 @@ func (qf *QueryFilter) Validate(option *RuleOption) (string, error) {
 	return qf.Rule.Validate(option)
 }
 
+// Estimate returns the cost score of the query filter, the empty query filter costs nothing.
+func (qf *QueryFilter) Estimate(opt *CostOption) float64 {
+	if qf.Rule == nil {
+		return 0
+	}
+
+	return qf.Rule.Estimate(opt)
+}
+
+// MatchDoc checks if the document matches the query filter in memory, the empty query filter matches all documents.
+func (qf *QueryFilter) MatchDoc(doc map[string]interface{}) (bool, error) {
+	if qf.Rule == nil {
+		return true, nil
+	}
+
+	return qf.Rule.MatchDoc(doc)
+}
+
 // MarshalJSON TODO
 func (qf *QueryFilter) MarshalJSON() ([]byte, error) {
 	if qf.Rule != nil {
@@ -0,0 +1,146 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"configcenter/src/common"
+)
+
+// sort orders of the query option.
+const (
+	// SortOrderAsc sorts the field in ascending order, it's the default order.
+	SortOrderAsc = "asc"
+	// SortOrderDesc sorts the field in descending order.
+	SortOrderDesc = "desc"
+)
+
+// QueryOption is the common request format of the query with the filter, it bundles the query filter with the
+// page, the multi-field sort and the returned fields, so that the services do not need to define their own ones.
+type QueryOption struct {
+	// Filter the query filter, all the records are matched if it is not set.
+	Filter *QueryFilter `json:"filter,omitempty"`
+	// Page the page of the records.
+	Page QueryPage `json:"page"`
+	// Sort the records are sorted by the fields in order.
+	Sort []SortField `json:"sort,omitempty"`
+	// Fields the fields of the records to be returned, all the fields are returned if it is not set.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// QueryPage is the page of the query option.
+type QueryPage struct {
+	Start int `json:"start"`
+	Limit int `json:"limit"`
+}
+
+// SortField is the sort field of the query option.
+type SortField struct {
+	Field string `json:"field"`
+	// Order the sort order, asc/desc, SortOrderAsc is used if not set.
+	Order string `json:"order,omitempty"`
+}
+
+// Validate validates the query option, the filter is validated with the rule option, and the page limit is at most
+// common.BKMaxPageSize. It returns the key and error if any one of keys is invalid.
+func (o *QueryOption) Validate(option *RuleOption) (string, error) {
+	if o.Filter != nil {
+		if key, err := o.Filter.Validate(option); err != nil {
+			return fmt.Sprintf("filter.%s", key), err
+		}
+	}
+
+	if o.Page.Start < 0 {
+		return "page.start", errors.New("page start can not be negative")
+	}
+
+	if o.Page.Limit <= 0 {
+		return "page.limit", errors.New("page limit must be set")
+	}
+
+	if o.Page.Limit > common.BKMaxPageSize {
+		return "page.limit", fmt.Errorf("exceed max page size: %d", common.BKMaxPageSize)
+	}
+
+	sortFields := make(map[string]struct{}, len(o.Sort))
+	for _, sort := range o.Sort {
+		if !ValidFieldPattern.MatchString(sort.Field) {
+			return "sort.field", fmt.Errorf("invalid sort field: %s", sort.Field)
+		}
+
+		if _, exists := sortFields[sort.Field]; exists {
+			return "sort.field", fmt.Errorf("duplicate sort field: %s", sort.Field)
+		}
+		sortFields[sort.Field] = struct{}{}
+
+		if sort.Order != "" && sort.Order != SortOrderAsc && sort.Order != SortOrderDesc {
+			return "sort.order", fmt.Errorf("invalid sort order: %s", sort.Order)
+		}
+	}
+
+	for _, field := range o.Fields {
+		if !ValidFieldPattern.MatchString(field) {
+			return "fields", fmt.Errorf("invalid field: %s", field)
+		}
+	}
+
+	return "", nil
+}
+
+// MgoFindOptions is the mongo find options converted from the query option, which can be passed to the dal find
+// directly, e.g. Find(opts.Filter).Fields(opts.Fields...).Sort(opts.Sort).Start(opts.Start).Limit(opts.Limit).
+type MgoFindOptions struct {
+	Filter map[string]interface{}
+	Fields []string
+	// Sort the dal sort string, e.g. "bk_host_id:1,create_time:-1".
+	Sort  string
+	Start uint64
+	Limit uint64
+}
+
+// ToMgoFindOptions validates the query option and converts it to the mongo find options.
+func (o *QueryOption) ToMgoFindOptions(option *RuleOption) (*MgoFindOptions, string, error) {
+	if key, err := o.Validate(option); err != nil {
+		return nil, key, err
+	}
+
+	filter := make(map[string]interface{})
+	if o.Filter != nil && o.Filter.Rule != nil {
+		var key string
+		var err error
+		filter, key, err = o.Filter.ToMgo()
+		if err != nil {
+			return nil, fmt.Sprintf("filter.%s", key), err
+		}
+	}
+
+	sorts := make([]string, len(o.Sort))
+	for idx, sort := range o.Sort {
+		if sort.Order == SortOrderDesc {
+			sorts[idx] = sort.Field + ":-1"
+			continue
+		}
+		sorts[idx] = sort.Field + ":1"
+	}
+
+	return &MgoFindOptions{
+		Filter: filter,
+		Fields: o.Fields,
+		Sort:   strings.Join(sorts, ","),
+		Start:  uint64(o.Page.Start),
+		Limit:  uint64(o.Page.Limit),
+	}, "", nil
+}
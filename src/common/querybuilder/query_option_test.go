@@ -0,0 +1,75 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryOptionToMgoFindOptions(t *testing.T) {
+	raw := `{
+		"filter": {"condition": "AND", "rules": [{"field": "bk_os_type", "operator": "equal", "value": "1"}]},
+		"page": {"start": 10, "limit": 20},
+		"sort": [{"field": "bk_host_id"}, {"field": "create_time", "order": "desc"}],
+		"fields": ["bk_host_id", "bk_host_innerip"]
+	}`
+
+	option := new(querybuilder.QueryOption)
+	require.NoError(t, json.Unmarshal([]byte(raw), option))
+
+	opts, key, err := option.ToMgoFindOptions(&querybuilder.RuleOption{})
+	require.NoError(t, err)
+	assert.Empty(t, key)
+	assert.Equal(t, map[string]interface{}{"$and": []map[string]interface{}{{"bk_os_type": map[string]interface{}{
+		"$eq": "1"}}}}, opts.Filter)
+	assert.Equal(t, []string{"bk_host_id", "bk_host_innerip"}, opts.Fields)
+	assert.Equal(t, "bk_host_id:1,create_time:-1", opts.Sort)
+	assert.Equal(t, uint64(10), opts.Start)
+	assert.Equal(t, uint64(20), opts.Limit)
+
+	// the empty filter matches all the records.
+	noFilter := &querybuilder.QueryOption{Page: querybuilder.QueryPage{Limit: 1}}
+	opts, _, err = noFilter.ToMgoFindOptions(&querybuilder.RuleOption{})
+	require.NoError(t, err)
+	assert.Empty(t, opts.Filter)
+	assert.Empty(t, opts.Sort)
+}
+
+func TestQueryOptionValidate(t *testing.T) {
+	invalids := map[string]querybuilder.QueryOption{
+		"page.limit": {},
+		"page.start": {Page: querybuilder.QueryPage{Start: -1, Limit: 10}},
+		"sort.field": {Page: querybuilder.QueryPage{Limit: 10}, Sort: []querybuilder.SortField{{Field: "id"},
+			{Field: "id", Order: querybuilder.SortOrderDesc}}},
+		"sort.order": {Page: querybuilder.QueryPage{Limit: 10}, Sort: []querybuilder.SortField{{Field: "id",
+			Order: "random"}}},
+		"fields": {Page: querybuilder.QueryPage{Limit: 10}, Fields: []string{"$where"}},
+	}
+
+	for expected, option := range invalids {
+		key, err := option.Validate(&querybuilder.RuleOption{})
+		assert.Error(t, err, expected)
+		assert.Equal(t, expected, key)
+	}
+
+	tooLarge := querybuilder.QueryOption{Page: querybuilder.QueryPage{Limit: 100000}}
+	key, err := tooLarge.Validate(&querybuilder.RuleOption{})
+	assert.Error(t, err)
+	assert.Equal(t, "page.limit", key)
+}
@@ -0,0 +1,86 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultMaxRelativeDays the default max number of days of the relative time operators.
+const DefaultMaxRelativeDays int64 = 3650
+
+// maxRelativeValue returns the max value of the relative time operator, the within_hours operator can be at most 24
+// times of the max relative days.
+func (r AtomRule) maxRelativeValue(option *RuleOption) int64 {
+	maxDays := DefaultMaxRelativeDays
+	if option != nil && option.MaxRelativeDays > 0 {
+		maxDays = option.MaxRelativeDays
+	}
+
+	if r.Operator == OperatorWithinHours {
+		return maxDays * 24
+	}
+	return maxDays
+}
+
+// validateRelativeTimeValue validates the value of the relative time operators, which should be a positive integer
+// no more than the max relative value.
+func (r AtomRule) validateRelativeTimeValue(option *RuleOption) error {
+	value, err := relativeTimeValue(r.Value)
+	if err != nil {
+		return err
+	}
+
+	if maxValue := r.maxRelativeValue(option); value < 1 || value > maxValue {
+		return fmt.Errorf("value %d is out of range [1, %d]", value, maxValue)
+	}
+	return nil
+}
+
+// relativeTimeValue parses the number of days or hours of the relative time operators.
+func relativeTimeValue(value interface{}) (int64, error) {
+	return int64Value(value)
+}
+
+// relativeTime resolves the time boundary of the relative time operators against the given time, the field matches
+// if it is no earlier than the boundary for within_days/within_hours, and if it is earlier for older_than.
+func (r AtomRule) relativeTime(now time.Time) (time.Time, error) {
+	value, err := relativeTimeValue(r.Value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	switch r.Operator {
+	case OperatorWithinDays, OperatorOlderThan:
+		return now.AddDate(0, 0, -int(value)), nil
+	case OperatorWithinHours:
+		return now.Add(-time.Duration(value) * time.Hour), nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported operator: %s", r.Operator)
+	}
+}
+
+// esRelativeTime returns the elasticsearch date math of the time boundary of the relative time operators, so that
+// it is resolved by elasticsearch at query execution.
+func (r AtomRule) esRelativeTime() (string, error) {
+	value, err := relativeTimeValue(r.Value)
+	if err != nil {
+		return "", err
+	}
+
+	if r.Operator == OperatorWithinHours {
+		return fmt.Sprintf("now-%dh", value), nil
+	}
+	return fmt.Sprintf("now-%dd", value), nil
+}
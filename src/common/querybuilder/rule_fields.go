@@ -0,0 +1,182 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"configcenter/src/common"
+)
+
+// ruleFieldKinds the value kinds of the rule field types that are checked against the operators, the rule fields can
+// use the property types or the value kinds, e.g. "disk.capacity": "int" or "disk.capacity": "numeric".
+var ruleFieldKinds = map[string]string{
+	common.FieldTypeInt:         TypeNumeric,
+	common.FieldTypeFloat:       TypeNumeric,
+	common.FieldTypeSingleChar:  TypeString,
+	common.FieldTypeLongChar:    TypeString,
+	common.FieldTypeEnum:        TypeString,
+	common.FieldTypeEnumCascade: TypeString,
+	common.FieldTypeList:        TypeString,
+	common.FieldTypeUser:        TypeString,
+	common.FieldTypeTimeZone:    TypeString,
+	common.FieldTypeBool:        TypeBoolean,
+	common.FieldTypeDate:        common.FieldTypeDate,
+	common.FieldTypeTime:        common.FieldTypeTime,
+	TypeNumeric:                 TypeNumeric,
+	TypeString:                  TypeString,
+	TypeBoolean:                 TypeBoolean,
+}
+
+// operatorFieldKinds the value kinds of the fields that the typed operators can be used on.
+var operatorFieldKinds = map[Operator][]string{
+	OperatorLess:                   {TypeNumeric},
+	OperatorLessOrEqual:            {TypeNumeric},
+	OperatorGreater:                {TypeNumeric},
+	OperatorGreaterOrEqual:         {TypeNumeric},
+	OperatorDatetimeLess:           {common.FieldTypeDate, common.FieldTypeTime},
+	OperatorDatetimeLessOrEqual:    {common.FieldTypeDate, common.FieldTypeTime},
+	OperatorDatetimeGreater:        {common.FieldTypeDate, common.FieldTypeTime},
+	OperatorDatetimeGreaterOrEqual: {common.FieldTypeDate, common.FieldTypeTime},
+	OperatorWithinDays:             {common.FieldTypeDate, common.FieldTypeTime},
+	OperatorWithinHours:            {common.FieldTypeDate, common.FieldTypeTime},
+	OperatorOlderThan:              {common.FieldTypeDate, common.FieldTypeTime},
+	OperatorBeginsWith:             {TypeString},
+	OperatorNotBeginsWith:          {TypeString},
+	OperatorContains:               {TypeString},
+	OperatorNotContains:            {TypeString},
+	OperatorsEndsWith:              {TypeString},
+	OperatorNotEndsWith:            {TypeString},
+	OperatorRegex:                  {TypeString},
+	OperatorIRegex:                 {TypeString},
+}
+
+// objectFieldOperators the operators that can be used on the object field as a whole, the other operators should be
+// used on its sub fields.
+var objectFieldOperators = map[Operator]bool{
+	OperatorExist:              true,
+	OperatorNotExist:           true,
+	OperatorIsNull:             true,
+	OperatorIsNotNull:          true,
+	OperatorIsEmpty:            true,
+	OperatorIsNotEmpty:         true,
+	OperatorSize:               true,
+	OperatorSizeGreaterOrEqual: true,
+	OperatorSizeLessOrEqual:    true,
+}
+
+// isObjectFieldType returns if the rule field type is an object whose sub fields can be declared by the nested paths.
+func isObjectFieldType(fieldType string) bool {
+	return fieldType == common.FieldObject || fieldType == common.FieldTypeTable
+}
+
+// validateRuleField validates the field against the rule fields if they are set. The sub field of an object field
+// must be declared by its nested path if any sub field of the object is declared, e.g. "disk.size" is rejected if
+// "disk.capacity" is declared but "disk.size" is not. The declared field's type must suit the operator. The fields
+// whose parents are not declared are not checked.
+func (r AtomRule) validateRuleField(option *RuleOption) error {
+	if option == nil || option.RuleFields == nil {
+		return nil
+	}
+
+	// check the parents of the nested path from the root, a scalar field can not have sub fields, and the object
+	// field with the declared sub fields has the schema that the sub field must be in.
+	parts := strings.Split(r.Field, ".")
+	for idx := 1; idx < len(parts); idx++ {
+		parent := strings.Join(parts[:idx], ".")
+		parentType, exists := option.RuleFields[parent]
+		if !exists {
+			continue
+		}
+
+		if !isObjectFieldType(parentType) {
+			return fmt.Errorf("field %s of type %s has no sub field %s", parent, parentType, r.Field)
+		}
+
+		if _, exists := option.RuleFields[r.Field]; !exists && hasSubRuleFields(option.RuleFields, parent) {
+			return fmt.Errorf("field %s is not found in the schema of object field %s", r.Field, parent)
+		}
+	}
+
+	fieldType, exists := option.RuleFields[r.Field]
+	if !exists {
+		return nil
+	}
+
+	if isObjectFieldType(fieldType) {
+		if !objectFieldOperators[r.Operator] {
+			return fmt.Errorf("operator %s can not be used on object field %s, filter its sub fields instead",
+				r.Operator, r.Field)
+		}
+		return nil
+	}
+
+	kinds, typed := operatorFieldKinds[r.Operator]
+	kind, known := ruleFieldKinds[fieldType]
+	if !typed || !known {
+		return nil
+	}
+
+	for _, allowed := range kinds {
+		if kind == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("operator %s can not be used on field %s of type %s", r.Operator, r.Field, fieldType)
+}
+
+// hasSubRuleFields returns if any sub field of the object field is declared in the rule fields.
+func hasSubRuleFields(ruleFields map[string]string, field string) bool {
+	prefix := field + "."
+	for ruleField := range ruleFields {
+		if strings.HasPrefix(ruleField, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// FlattenRuleFields flattens the schema of the fields into the rule fields of the nested paths, the value of the
+// schema is the field type, or the schema of the sub object, e.g. {"disk": {"capacity": "int"}} is flattened to
+// {"disk": "object", "disk.capacity": "int"}.
+func FlattenRuleFields(schema map[string]interface{}) (map[string]string, error) {
+	ruleFields := make(map[string]string)
+	if err := flattenRuleFields("", schema, ruleFields); err != nil {
+		return nil, err
+	}
+	return ruleFields, nil
+}
+
+func flattenRuleFields(prefix string, schema map[string]interface{}, ruleFields map[string]string) error {
+	for field, value := range schema {
+		path := prefix + field
+		if !ValidFieldPattern.MatchString(path) || strings.Contains(field, ".") {
+			return fmt.Errorf("invalid field: %s", path)
+		}
+
+		switch v := value.(type) {
+		case string:
+			ruleFields[path] = v
+		case map[string]interface{}:
+			ruleFields[path] = common.FieldObject
+			if err := flattenRuleFields(path+".", v, ruleFields); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("invalid schema of field %s, type: %v", path, reflect.TypeOf(value))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenRuleFields(t *testing.T) {
+	ruleFields, err := querybuilder.FlattenRuleFields(map[string]interface{}{
+		"name": common.FieldTypeSingleChar,
+		"disk": map[string]interface{}{
+			"capacity": querybuilder.TypeNumeric,
+			"mount": map[string]interface{}{
+				"path": common.FieldTypeSingleChar,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"name":            common.FieldTypeSingleChar,
+		"disk":            common.FieldObject,
+		"disk.capacity":   querybuilder.TypeNumeric,
+		"disk.mount":      common.FieldObject,
+		"disk.mount.path": common.FieldTypeSingleChar,
+	}, ruleFields)
+
+	_, err = querybuilder.FlattenRuleFields(map[string]interface{}{"disk": 1})
+	assert.Error(t, err)
+
+	_, err = querybuilder.FlattenRuleFields(map[string]interface{}{"disk": map[string]interface{}{"a.b": "int"}})
+	assert.Error(t, err)
+}
+
+func TestValidateNestedRuleFields(t *testing.T) {
+	option := &querybuilder.RuleOption{RuleFields: map[string]string{
+		"name":          common.FieldTypeSingleChar,
+		"disk":          common.FieldObject,
+		"disk.capacity": querybuilder.TypeNumeric,
+		"disk.mounted":  common.FieldTypeDate,
+		"labels":        common.FieldObject,
+	}}
+
+	validRules := []querybuilder.AtomRule{
+		{Field: "disk.capacity", Operator: querybuilder.OperatorGreater, Value: 100},
+		{Field: "disk.capacity", Operator: querybuilder.OperatorIn, Value: []int{1, 2}},
+		{Field: "disk.mounted", Operator: querybuilder.OperatorWithinDays, Value: 3},
+		{Field: "disk", Operator: querybuilder.OperatorExist, Value: true},
+		{Field: "name", Operator: querybuilder.OperatorContains, Value: "x"},
+		// the object without the declared sub fields has no schema
+		{Field: "labels.env", Operator: querybuilder.OperatorEqual, Value: "prod"},
+		// the fields whose parents are not declared are not checked
+		{Field: "other.field", Operator: querybuilder.OperatorEqual, Value: 1},
+	}
+	for _, rule := range validRules {
+		_, err := rule.Validate(option)
+		assert.NoError(t, err, rule)
+	}
+
+	invalidRules := []querybuilder.AtomRule{
+		// the sub field is not in the schema of disk
+		{Field: "disk.size", Operator: querybuilder.OperatorEqual, Value: 1},
+		// the scalar field has no sub fields
+		{Field: "name.first", Operator: querybuilder.OperatorEqual, Value: "x"},
+		// the operators do not suit the field types
+		{Field: "disk.capacity", Operator: querybuilder.OperatorContains, Value: "1"},
+		{Field: "disk.mounted", Operator: querybuilder.OperatorGreater, Value: 1},
+		{Field: "name", Operator: querybuilder.OperatorLess, Value: 1},
+		// the object field can only be filtered by its sub fields
+		{Field: "disk", Operator: querybuilder.OperatorEqual, Value: "x"},
+	}
+	for _, rule := range invalidRules {
+		key, err := rule.Validate(option)
+		assert.Error(t, err, rule)
+		assert.Equal(t, "field", key, rule)
+	}
+
+	// the rule fields are not checked if they are not set
+	rule := querybuilder.AtomRule{Field: "disk.size", Operator: querybuilder.OperatorEqual, Value: 1}
+	_, err := rule.Validate(&querybuilder.RuleOption{})
+	assert.NoError(t, err)
+}
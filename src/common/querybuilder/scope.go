@@ -0,0 +1,52 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+// WithScope returns a new query filter that matches the records matching both the filter and all the scope rules,
+// e.g. the supplier account and the business constraints appended to the user's filter. The scope rules are ANDed
+// at the top level: they are added to the rules of the root directly if the root is combined by AND, so that the
+// filter does not get deeper, otherwise the root (e.g. an OR) is nested with them in a new AND rule. The original
+// filter is not changed, the nil scope rules are ignored.
+// NOTE: the depth of the scoped filter may exceed MaxDeep, so the user's filter should be validated before scoping.
+func (qf *QueryFilter) WithScope(scopes ...Rule) *QueryFilter {
+	scopeRules := make([]Rule, 0, len(scopes))
+	for _, scope := range scopes {
+		if scope != nil {
+			scopeRules = append(scopeRules, scope)
+		}
+	}
+
+	if qf == nil || qf.Rule == nil {
+		if len(scopeRules) == 0 {
+			return new(QueryFilter)
+		}
+		return &QueryFilter{Rule: CombinedRule{Condition: ConditionAnd, Rules: scopeRules}}
+	}
+
+	if len(scopeRules) == 0 {
+		return &QueryFilter{Rule: qf.Rule}
+	}
+
+	if combined, ok := qf.Rule.(CombinedRule); ok && combined.Condition == ConditionAnd {
+		// copy the rules so that the appended scope rules never share the original's underlying array
+		rules := make([]Rule, 0, len(combined.Rules)+len(scopeRules))
+		rules = append(rules, combined.Rules...)
+		rules = append(rules, scopeRules...)
+		return &QueryFilter{Rule: CombinedRule{Condition: ConditionAnd, Rules: rules}}
+	}
+
+	rules := make([]Rule, 0, len(scopeRules)+1)
+	rules = append(rules, qf.Rule)
+	rules = append(rules, scopeRules...)
+	return &QueryFilter{Rule: CombinedRule{Condition: ConditionAnd, Rules: rules}}
+}
@@ -0,0 +1,71 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"testing"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithScope(t *testing.T) {
+	ownerRule := querybuilder.AtomRule{Field: "bk_supplier_account", Operator: querybuilder.OperatorEqual, Value: "0"}
+	bizRule := querybuilder.AtomRule{Field: "bk_biz_id", Operator: querybuilder.OperatorEqual, Value: 2}
+	nameRule := querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorEqual, Value: "a"}
+	ipRule := querybuilder.AtomRule{Field: "ip", Operator: querybuilder.OperatorEqual, Value: "1.1.1.1"}
+
+	// the scope rules are added to the AND root directly, the original rules are not changed even if their
+	// underlying array has spare capacity
+	rules := make([]querybuilder.Rule, 0, 4)
+	rules = append(rules, nameRule, ipRule)
+	andFilter := &querybuilder.QueryFilter{Rule: querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd, Rules: rules}}
+	scoped := andFilter.WithScope(ownerRule, bizRule)
+	assert.Equal(t, querybuilder.CombinedRule{Condition: querybuilder.ConditionAnd,
+		Rules: []querybuilder.Rule{nameRule, ipRule, ownerRule, bizRule}}, scoped.Rule)
+	assert.Equal(t, 2, scoped.GetDeep())
+	assert.Len(t, andFilter.Rule.(querybuilder.CombinedRule).Rules, 2)
+	andFilter.WithScope(ipRule)
+	assert.Equal(t, ownerRule, scoped.Rule.(querybuilder.CombinedRule).Rules[2])
+
+	// the OR root is nested with the scope rules, so that the scope rules can not be bypassed by the OR
+	orRule := querybuilder.CombinedRule{Condition: querybuilder.ConditionOr, Rules: []querybuilder.Rule{nameRule, ipRule}}
+	orFilter := &querybuilder.QueryFilter{Rule: orRule}
+	scoped = orFilter.WithScope(ownerRule, nil, bizRule)
+	assert.Equal(t, querybuilder.CombinedRule{Condition: querybuilder.ConditionAnd,
+		Rules: []querybuilder.Rule{orRule, ownerRule, bizRule}}, scoped.Rule)
+	assert.Equal(t, orRule, orFilter.Rule)
+
+	matched, err := scoped.MatchDoc(map[string]interface{}{"name": "a", "bk_supplier_account": "0", "bk_biz_id": 3})
+	assert.NoError(t, err)
+	assert.False(t, matched)
+	matched, err = scoped.MatchDoc(map[string]interface{}{"ip": "1.1.1.1", "bk_supplier_account": "0", "bk_biz_id": 2})
+	assert.NoError(t, err)
+	assert.True(t, matched)
+
+	// the empty filter matches the scope rules only
+	var nilFilter *querybuilder.QueryFilter
+	scoped = nilFilter.WithScope(bizRule)
+	assert.Equal(t, querybuilder.CombinedRule{Condition: querybuilder.ConditionAnd,
+		Rules: []querybuilder.Rule{bizRule}}, scoped.Rule)
+	_, err = scoped.Validate(&querybuilder.RuleOption{})
+	assert.NoError(t, err)
+
+	// no scope rule returns a copy of the filter
+	scoped = orFilter.WithScope(nil)
+	assert.Equal(t, orRule, scoped.Rule)
+	assert.False(t, scoped == orFilter)
+	assert.Nil(t, new(querybuilder.QueryFilter).WithScope().Rule)
+}
@@ -0,0 +1,159 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"fmt"
+	"math"
+
+	"configcenter/src/common"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// validateSizeValue validates the value of the size operators, which should be a non-negative integer.
+func validateSizeValue(value interface{}) error {
+	_, err := sizeValue(value)
+	return err
+}
+
+// sizeValue parses the element count of the size operators.
+func sizeValue(value interface{}) (int64, error) {
+	size, err := int64Value(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if size < 0 || size > math.MaxInt32 {
+		return 0, fmt.Errorf("value %d is out of range [0, %d]", size, math.MaxInt32)
+	}
+	return size, nil
+}
+
+// sizeMgo generates the mongo filter of the size operators, the exact size is matched by $size, while the size range
+// is compared by $expr since $size does not accept a range. Only the array fields match the size operators, and the
+// field compared by $expr is the value of the field path as a whole, so it should not go through the arrays.
+func (r AtomRule) sizeMgo() (map[string]interface{}, error) {
+	size, err := sizeValue(r.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var compare string
+	switch r.Operator {
+	case OperatorSize:
+		return map[string]interface{}{r.Field: map[string]interface{}{common.BKDBSize: size}}, nil
+	case OperatorSizeGreaterOrEqual:
+		compare = common.BKDBGTE
+	case OperatorSizeLessOrEqual:
+		compare = common.BKDBLTE
+	default:
+		return nil, fmt.Errorf("unsupported operator: %s", r.Operator)
+	}
+
+	fieldPath := "$" + r.Field
+	return map[string]interface{}{
+		common.BKDBExpr: map[string]interface{}{
+			common.BKDBCond: []interface{}{
+				map[string]interface{}{common.BKDBIsArray: fieldPath},
+				map[string]interface{}{compare: []interface{}{map[string]interface{}{common.BKDBSize: fieldPath}, size}},
+				false,
+			},
+		},
+	}, nil
+}
+
+// sizeES generates the elasticsearch script query of the size operators, which counts the doc values of the field,
+// so the duplicate values of the keyword field are counted once, and the unmapped field matches nothing.
+func (r AtomRule) sizeES() (elastic.Query, error) {
+	size, err := sizeValue(r.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var compare string
+	switch r.Operator {
+	case OperatorSize:
+		compare = "=="
+	case OperatorSizeGreaterOrEqual:
+		compare = ">="
+	case OperatorSizeLessOrEqual:
+		compare = "<="
+	default:
+		return nil, fmt.Errorf("unsupported operator: %s", r.Operator)
+	}
+
+	source := fmt.Sprintf("doc.containsKey(params.field) && doc[params.field].size() %s params.size", compare)
+	script := elastic.NewScript(source).Params(map[string]interface{}{"field": r.Field, "size": size})
+	return elastic.NewScriptQuery(script), nil
+}
+
+// sizeToSQL compares the length of the json array column with the value, the column which is not a json array
+// matches nothing.
+func (r AtomRule) sizeToSQL(opt *SQLOption, column string) (string, []interface{}, string, error) {
+	size, err := sizeValue(r.Value)
+	if err != nil {
+		return "", nil, "value", err
+	}
+
+	var operator string
+	switch r.Operator {
+	case OperatorSize:
+		operator = "="
+	case OperatorSizeGreaterOrEqual:
+		operator = ">="
+	case OperatorSizeLessOrEqual:
+		operator = "<="
+	default:
+		return "", nil, "operator", fmt.Errorf("unsupported operator: %s", r.Operator)
+	}
+
+	length := fmt.Sprintf("CASE WHEN JSON_TYPE(%s) = 'ARRAY' THEN JSON_LENGTH(%s) END", column, column)
+	if opt.Dialect == SQLDialectPostgreSQL {
+		length = fmt.Sprintf("CASE WHEN json_typeof(%s::json) = 'array' THEN json_array_length(%s::json) END",
+			column, column)
+	}
+	return fmt.Sprintf("%s %s %s", length, operator, opt.placeholder()), []interface{}{size}, "", nil
+}
+
+// matchSize checks if any of the values is an array whose size satisfies the size operator.
+func (r AtomRule) matchSize(values []interface{}) (bool, error) {
+	size, err := sizeValue(r.Value)
+	if err != nil {
+		return false, err
+	}
+
+	for _, value := range values {
+		arr, ok := normalizeValue(value).([]interface{})
+		if !ok {
+			continue
+		}
+
+		length := int64(len(arr))
+		switch r.Operator {
+		case OperatorSize:
+			ok = length == size
+		case OperatorSizeGreaterOrEqual:
+			ok = length >= size
+		case OperatorSizeLessOrEqual:
+			ok = length <= size
+		default:
+			return false, fmt.Errorf("unsupported operator: %s", r.Operator)
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,285 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLDialect is the dialect of the sql where clause, which decides the placeholder and identifier quote style.
+type SQLDialect string
+
+const (
+	// SQLDialectMySQL uses ? as the placeholder and quotes the column with backtick.
+	SQLDialectMySQL SQLDialect = "mysql"
+	// SQLDialectPostgreSQL uses $1, $2... as the placeholder and quotes the column with double quote.
+	SQLDialectPostgreSQL SQLDialect = "postgresql"
+)
+
+// sqlLikeEscape is the escape character of the like pattern, which is not a string escape character in any dialect.
+const sqlLikeEscape = "!"
+
+var sqlLikeEscaper = strings.NewReplacer(sqlLikeEscape, sqlLikeEscape+sqlLikeEscape, "%", sqlLikeEscape+"%",
+	"_", sqlLikeEscape+"_")
+
+// SQLOption is the option to generate the sql where clause from the rule.
+type SQLOption struct {
+	// Dialect the sql dialect, mysql is used if not set.
+	Dialect SQLDialect
+
+	// FieldMapper maps the rule field to the quoted column, the field is quoted as the column if not set.
+	FieldMapper func(field string) (column string, err error)
+
+	// argCount is the count of the args generated with the option, the postgresql placeholders are numbered by it,
+	// so that the clauses generated with the same option can be joined in one statement.
+	argCount int
+}
+
+// placeholder returns the placeholder of the next arg.
+func (o *SQLOption) placeholder() string {
+	o.argCount++
+	if o.Dialect == SQLDialectPostgreSQL {
+		return fmt.Sprintf("$%d", o.argCount)
+	}
+	return "?"
+}
+
+// column returns the column of the field.
+func (o *SQLOption) column(field string) (string, error) {
+	if o.FieldMapper != nil {
+		return o.FieldMapper(field)
+	}
+
+	// the field is validated by the ValidFieldPattern, so it contains no quote characters.
+	if o.Dialect == SQLDialectPostgreSQL {
+		return `"` + field + `"`, nil
+	}
+	return "`" + field + "`", nil
+}
+
+func (o *SQLOption) validate() error {
+	switch o.Dialect {
+	case "":
+		o.Dialect = SQLDialectMySQL
+	case SQLDialectMySQL, SQLDialectPostgreSQL:
+	default:
+		return fmt.Errorf("unsupported sql dialect: %s", o.Dialect)
+	}
+	return nil
+}
+
+// ToSQL generate the parameterized sql where clause from rule, the values are returned as the args of the
+// placeholders. The rule is converted to match the same records as ToMgo does, so the negative operators match
+// the null columns too, the string operators match the value literally instead of as a regular expression, the
+// arrays are expected to be stored as json text, and a column is treated as not exist if it is null.
+func (r AtomRule) ToSQL(opt *SQLOption) (where string, args []interface{}, key string, err error) {
+	if opt == nil {
+		opt = new(SQLOption)
+	}
+	if err := opt.validate(); err != nil {
+		return "", nil, "dialect", err
+	}
+
+	if key, err := r.Validate(&RuleOption{NeedSameSliceElementType: true}); err != nil {
+		return "", nil, key, fmt.Errorf("validate failed, key: %s, err: %s", key, err)
+	}
+
+	column, err := opt.column(r.Field)
+	if err != nil {
+		return "", nil, "field", err
+	}
+
+	switch r.Operator {
+	case OperatorEqual:
+		return fmt.Sprintf("%s = %s", column, opt.placeholder()), []interface{}{r.Value}, "", nil
+	case OperatorNotEqual:
+		return fmt.Sprintf("(%s <> %s OR %s IS NULL)", column, opt.placeholder(), column), []interface{}{r.Value},
+			"", nil
+	case OperatorIn, OperatorNotIn:
+		return r.inToSQL(opt, column)
+	case OperatorLess:
+		return fmt.Sprintf("%s < %s", column, opt.placeholder()), []interface{}{r.Value}, "", nil
+	case OperatorLessOrEqual:
+		return fmt.Sprintf("%s <= %s", column, opt.placeholder()), []interface{}{r.Value}, "", nil
+	case OperatorGreater:
+		return fmt.Sprintf("%s > %s", column, opt.placeholder()), []interface{}{r.Value}, "", nil
+	case OperatorGreaterOrEqual:
+		return fmt.Sprintf("%s >= %s", column, opt.placeholder()), []interface{}{r.Value}, "", nil
+	case OperatorDatetimeLess, OperatorDatetimeLessOrEqual, OperatorDatetimeGreater, OperatorDatetimeGreaterOrEqual:
+		return r.datetimeToSQL(opt, column)
+	case OperatorWithinDays, OperatorWithinHours, OperatorOlderThan:
+		boundary, err := r.relativeTime(time.Now())
+		if err != nil {
+			return "", nil, "value", err
+		}
+		if r.Operator == OperatorOlderThan {
+			return fmt.Sprintf("%s < %s", column, opt.placeholder()), []interface{}{boundary}, "", nil
+		}
+		return fmt.Sprintf("%s >= %s", column, opt.placeholder()), []interface{}{boundary}, "", nil
+	case OperatorBeginsWith:
+		return likeToSQL(opt, column, escapeLike(r.Value.(string))+"%", false)
+	case OperatorNotBeginsWith:
+		return likeToSQL(opt, column, escapeLike(r.Value.(string))+"%", true)
+	case OperatorContains:
+		// contains is case insensitive as the mongo filter does
+		return likeToSQL(opt, "LOWER("+column+")", "%"+escapeLike(strings.ToLower(r.Value.(string)))+"%", false)
+	case OperatorNotContains:
+		return likeToSQL(opt, column, "%"+escapeLike(r.Value.(string))+"%", true)
+	case OperatorsEndsWith:
+		return likeToSQL(opt, column, "%"+escapeLike(r.Value.(string)), false)
+	case OperatorNotEndsWith:
+		return likeToSQL(opt, column, "%"+escapeLike(r.Value.(string)), true)
+	case OperatorRegex, OperatorIRegex:
+		return r.regexToSQL(opt, column)
+	case OperatorInCIDR:
+		pattern, err := r.cidrRegex()
+		if err != nil {
+			return "", nil, "value", err
+		}
+		return AtomRule{Field: r.Field, Operator: OperatorRegex, Value: pattern}.regexToSQL(opt, column)
+	case OperatorSize, OperatorSizeGreaterOrEqual, OperatorSizeLessOrEqual:
+		return r.sizeToSQL(opt, column)
+	case OperatorFieldCompare:
+		return r.fieldCompareToSQL(opt, column)
+	case OperatorIsEmpty:
+		return fmt.Sprintf("%s = %s", column, opt.placeholder()), []interface{}{"[]"}, "", nil
+	case OperatorIsNotEmpty:
+		return fmt.Sprintf("(%s <> %s OR %s IS NULL)", column, opt.placeholder(), column), []interface{}{"[]"},
+			"", nil
+	case OperatorIsNull, OperatorNotExist:
+		return fmt.Sprintf("%s IS NULL", column), make([]interface{}, 0), "", nil
+	case OperatorIsNotNull, OperatorExist:
+		return fmt.Sprintf("%s IS NOT NULL", column), make([]interface{}, 0), "", nil
+	default:
+		return r.customToSQL(opt, column)
+	}
+}
+
+// inToSQL expands the in/not_in values to one placeholder for each element, the empty values matches no record
+// for in and all records for not_in.
+func (r AtomRule) inToSQL(opt *SQLOption, column string) (string, []interface{}, string, error) {
+	args := r.sliceValue()
+	if len(args) == 0 {
+		if r.Operator == OperatorIn {
+			return "1 = 0", args, "", nil
+		}
+		return "1 = 1", args, "", nil
+	}
+
+	placeholders := make([]string, len(args))
+	for i := range args {
+		placeholders[i] = opt.placeholder()
+	}
+
+	if r.Operator == OperatorIn {
+		return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", ")), args, "", nil
+	}
+	return fmt.Sprintf("(%s NOT IN (%s) OR %s IS NULL)", column, strings.Join(placeholders, ", "), column), args,
+		"", nil
+}
+
+// datetimeToSQL compares the column with the date of the value, the date is passed as a time.Time arg.
+func (r AtomRule) datetimeToSQL(opt *SQLOption, column string) (string, []interface{}, string, error) {
+	date, err := time.Parse(timeLayout, r.Value.(string))
+	if err != nil {
+		return "", nil, "value", err
+	}
+
+	var operator string
+	switch r.Operator {
+	case OperatorDatetimeLess:
+		operator = "<"
+	case OperatorDatetimeLessOrEqual:
+		operator = "<="
+	case OperatorDatetimeGreater:
+		operator = ">"
+	case OperatorDatetimeGreaterOrEqual:
+		operator = ">="
+	default:
+		return "", nil, "operator", fmt.Errorf("unsupported operator: %s", r.Operator)
+	}
+
+	return fmt.Sprintf("%s %s %s", column, operator, opt.placeholder()), []interface{}{date}, "", nil
+}
+
+// regexToSQL matches the column with the regular expression, the case sensitivity is explicitly specified since the
+// mysql regexp follows the collation of the column.
+func (r AtomRule) regexToSQL(opt *SQLOption, column string) (string, []interface{}, string, error) {
+	insensitive := r.Operator == OperatorIRegex
+	if opt.Dialect == SQLDialectPostgreSQL {
+		operator := "~"
+		if insensitive {
+			operator = "~*"
+		}
+		return fmt.Sprintf("%s %s %s", column, operator, opt.placeholder()), []interface{}{r.Value}, "", nil
+	}
+
+	matchType := "c"
+	if insensitive {
+		matchType = "i"
+	}
+	return fmt.Sprintf("REGEXP_LIKE(%s, %s, '%s')", column, opt.placeholder(), matchType), []interface{}{r.Value},
+		"", nil
+}
+
+// escapeLike escapes the wildcards in the value so that it is matched literally by the like pattern.
+func escapeLike(value string) string {
+	return sqlLikeEscaper.Replace(value)
+}
+
+// likeToSQL generates the like clause of the escaped pattern, the negative clause matches the null column too.
+func likeToSQL(opt *SQLOption, column, pattern string, not bool) (string, []interface{}, string, error) {
+	placeholder := opt.placeholder()
+	if not {
+		return fmt.Sprintf("(%s NOT LIKE %s ESCAPE '%s' OR %s IS NULL)", column, placeholder, sqlLikeEscape, column),
+			[]interface{}{pattern}, "", nil
+	}
+	return fmt.Sprintf("%s LIKE %s ESCAPE '%s'", column, placeholder, sqlLikeEscape), []interface{}{pattern}, "", nil
+}
+
+// ToSQL generate the parameterized sql where clause from the combined rules, the clauses of the rules are joined by
+// the condition and wrapped in parentheses. The NOT condition negates the clauses joined by OR, the unknown result of
+// the null columns is treated as false before the negation so that the null columns are matched as ToMgo does.
+func (r CombinedRule) ToSQL(opt *SQLOption) (where string, args []interface{}, key string, err error) {
+	if opt == nil {
+		opt = new(SQLOption)
+	}
+	if err := opt.validate(); err != nil {
+		return "", nil, "dialect", err
+	}
+
+	if err := r.Condition.Validate(); err != nil {
+		return "", nil, "condition", err
+	}
+	if len(r.Rules) == 0 {
+		return "", nil, "rules", fmt.Errorf("combined rules shouldn't be empty")
+	}
+
+	clauses := make([]string, 0, len(r.Rules))
+	args = make([]interface{}, 0)
+	for idx, rule := range r.Rules {
+		clause, ruleArgs, key, err := rule.ToSQL(opt)
+		if err != nil {
+			return "", nil, fmt.Sprintf("rules[%d].%s", idx, key), err
+		}
+		clauses = append(clauses, clause)
+		args = append(args, ruleArgs...)
+	}
+
+	if r.Condition == ConditionNot {
+		return "NOT COALESCE((" + strings.Join(clauses, " OR ") + "), FALSE)", args, "", nil
+	}
+	return "(" + strings.Join(clauses, " "+string(r.Condition)+" ") + ")", args, "", nil
+}
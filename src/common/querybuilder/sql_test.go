@@ -0,0 +1,164 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"testing"
+	"time"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomRuleToSQL(t *testing.T) {
+	testCases := []struct {
+		rule  querybuilder.AtomRule
+		where string
+		args  []interface{}
+	}{
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 1},
+			where: "`a` = ?",
+			args:  []interface{}{1},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorNotEqual, Value: "x"},
+			where: "(`a` <> ? OR `a` IS NULL)",
+			args:  []interface{}{"x"},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorIn, Value: []int64{1, 2}},
+			where: "`a` IN (?, ?)",
+			args:  []interface{}{int64(1), int64(2)},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorIn, Value: []int64{}},
+			where: "1 = 0",
+			args:  []interface{}{},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorNotIn, Value: []string{"x"}},
+			where: "(`a` NOT IN (?) OR `a` IS NULL)",
+			args:  []interface{}{"x"},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorGreaterOrEqual, Value: 3},
+			where: "`a` >= ?",
+			args:  []interface{}{3},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorDatetimeLess, Value: "2021-01-02"},
+			where: "`a` < ?",
+			args:  []interface{}{time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorBeginsWith, Value: "10%_"},
+			where: "`a` LIKE ? ESCAPE '!'",
+			args:  []interface{}{"10!%!_%"},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorContains, Value: "Ab"},
+			where: "LOWER(`a`) LIKE ? ESCAPE '!'",
+			args:  []interface{}{"%ab%"},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorNotEndsWith, Value: "x!"},
+			where: "(`a` NOT LIKE ? ESCAPE '!' OR `a` IS NULL)",
+			args:  []interface{}{"%x!!"},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorInCIDR, Value: "10.1.0.0/16"},
+			where: "REGEXP_LIKE(`a`, ?, 'c')",
+			args:  []interface{}{`(^|,)10\.1\.[0-9]{1,3}\.[0-9]{1,3}(,|$)`},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorSizeGreaterOrEqual, Value: 2},
+			where: "CASE WHEN JSON_TYPE(`a`) = 'ARRAY' THEN JSON_LENGTH(`a`) END >= ?",
+			args:  []interface{}{int64(2)},
+		},
+		{
+			rule: querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorFieldCompare,
+				Value: querybuilder.FieldCompareValue{Field: "b", Operator: querybuilder.OperatorNotEqual}},
+			where: "`a` <> `b`",
+			args:  []interface{}{},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorIsEmpty},
+			where: "`a` = ?",
+			args:  []interface{}{"[]"},
+		},
+		{
+			rule:  querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorExist},
+			where: "`a` IS NOT NULL",
+			args:  []interface{}{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		where, args, errKey, err := testCase.rule.ToSQL(nil)
+		assert.Nil(t, err, testCase.rule.Operator)
+		assert.Empty(t, errKey)
+		assert.Equal(t, testCase.where, where, testCase.rule.Operator)
+		assert.Equal(t, testCase.args, args, testCase.rule.Operator)
+	}
+}
+
+func TestCombinedRuleToSQL(t *testing.T) {
+	filter, err := querybuilder.NewBuilder().Field("bk_host_innerip").Equal("10.0.0.1").
+		Or(querybuilder.NewBuilder().Field("bk_os_type").Equal("1"),
+			querybuilder.NewBuilder().Field("bk_cloud_id").In([]int64{0, 1})).
+		Build()
+	assert.Nil(t, err)
+
+	opt := &querybuilder.SQLOption{Dialect: querybuilder.SQLDialectPostgreSQL}
+	where, args, errKey, err := filter.ToSQL(opt)
+	assert.Nil(t, err)
+	assert.Empty(t, errKey)
+	assert.Equal(t, `("bk_host_innerip" = $1 AND ("bk_os_type" = $2 OR "bk_cloud_id" IN ($3, $4)))`, where)
+	assert.Equal(t, []interface{}{"10.0.0.1", "1", int64(0), int64(1)}, args)
+
+	// the placeholders continue to be numbered with the same option
+	where, _, _, err = querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorLess, Value: 1}.ToSQL(opt)
+	assert.Nil(t, err)
+	assert.Equal(t, `"a" < $5`, where)
+
+	not := querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionNot,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 1},
+			querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorExist},
+		},
+	}
+	where, args, _, err = not.ToSQL(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "NOT COALESCE((`a` = ? OR `b` IS NOT NULL), FALSE)", where)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestRuleToSQLInvalid(t *testing.T) {
+	rule := querybuilder.CombinedRule{
+		Condition: querybuilder.ConditionAnd,
+		Rules: []querybuilder.Rule{
+			querybuilder.AtomRule{Field: "a", Operator: querybuilder.OperatorEqual, Value: 1},
+			querybuilder.AtomRule{Field: "b", Operator: querybuilder.OperatorIn, Value: 1},
+		},
+	}
+	_, _, errKey, err := rule.ToSQL(nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, "rules[1].value", errKey)
+
+	_, _, errKey, err = rule.ToSQL(&querybuilder.SQLOption{Dialect: "oracle"})
+	assert.NotNil(t, err)
+	assert.Equal(t, "dialect", errKey)
+}
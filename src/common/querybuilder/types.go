@@ -18,6 +18,8 @@ import (
 	"time"
 
 	"configcenter/src/common"
+
+	"github.com/olivere/elastic/v7"
 )
 
 const timeLayout = "2006-01-02"
@@ -27,9 +29,17 @@ type Rule interface {
 	GetDeep() int
 	Validate(option *RuleOption) (string, error)
 	ToMgo() (mgoFilter map[string]interface{}, errKey string, err error)
+	ToSQL(opt *SQLOption) (where string, args []interface{}, errKey string, err error)
+	ToES() (query elastic.Query, errKey string, err error)
+	// Estimate returns the cost score of the rule, the higher the more expensive to query with it.
+	Estimate(opt *CostOption) float64
 	Match(matcher Matcher) bool
 	// MatchAny if any of the rules matches the matcher, return true
 	MatchAny(matcher Matcher) bool
+	// MatchDoc checks if the document matches the rule in memory with the same semantics as ToMgo.
+	MatchDoc(doc map[string]interface{}) (bool, error)
+	// Explain returns the rule tree annotated with the mongo filters and the limits of the option, for debugging.
+	Explain(option *RuleOption) *ExplainNode
 	GetField() []string
 }
 
@@ -39,7 +49,7 @@ type Condition string
 
 // Validate TODO
 func (c Condition) Validate() error {
-	if c == ConditionAnd || c == ConditionOr {
+	if c == ConditionAnd || c == ConditionOr || c == ConditionNot {
 		return nil
 	}
 	return fmt.Errorf("unexpected condition: %s", c)
@@ -52,6 +62,8 @@ func (c Condition) ToMgo() (mgoOperator string, err error) {
 		return common.BKDBOR, nil
 	case ConditionAnd:
 		return common.BKDBAND, nil
+	case ConditionNot:
+		return common.BKDBNOR, nil
 	default:
 		return "", fmt.Errorf("unexpected operator %s", c)
 	}
@@ -62,8 +74,31 @@ var (
 	ConditionAnd = Condition("AND")
 	// ConditionOr TODO
 	ConditionOr = Condition("OR")
+	// ConditionNot matches the records which match none of the rules
+	ConditionNot = Condition("NOT")
 )
 
+// Negate returns the rule which matches the records that the rule does not match, the negation of a NOT combined
+// rule and an OR combined rule are simplified to save a level of the depth.
+func Negate(rule Rule) Rule {
+	combined, ok := rule.(CombinedRule)
+	if !ok {
+		return CombinedRule{Condition: ConditionNot, Rules: []Rule{rule}}
+	}
+
+	switch combined.Condition {
+	case ConditionNot:
+		if len(combined.Rules) == 1 {
+			return combined.Rules[0]
+		}
+		return CombinedRule{Condition: ConditionOr, Rules: combined.Rules}
+	case ConditionOr:
+		return CombinedRule{Condition: ConditionNot, Rules: combined.Rules}
+	default:
+		return CombinedRule{Condition: ConditionNot, Rules: []Rule{rule}}
+	}
+}
+
 // Operator TODO
 // *************** define operator ************************
 type Operator string
@@ -100,6 +135,14 @@ var (
 	// OperatorDatetimeGreaterOrEqual TODO
 	OperatorDatetimeGreaterOrEqual = Operator("datetime_greater_or_equal")
 
+	// OperatorWithinDays matches the time field within the last n days, n is the value
+	// relative time operator, the time is resolved against the server time when the rule is converted
+	OperatorWithinDays = Operator("within_days")
+	// OperatorWithinHours matches the time field within the last n hours, n is the value
+	OperatorWithinHours = Operator("within_hours")
+	// OperatorOlderThan matches the time field older than n days, n is the value
+	OperatorOlderThan = Operator("older_than")
+
 	// OperatorBeginsWith TODO
 	// string operator
 	OperatorBeginsWith = Operator("begins_with")
@@ -114,6 +157,28 @@ var (
 	// OperatorNotEndsWith TODO
 	OperatorNotEndsWith = Operator("not_ends_with")
 
+	// OperatorRegex matches the field with the regular expression
+	// regex operator
+	OperatorRegex = Operator("regex")
+	// OperatorIRegex matches the field with the regular expression case-insensitively
+	OperatorIRegex = Operator("iregex")
+
+	// OperatorInCIDR matches the ip field in the ipv4 cidr, e.g. 10.0.0.0/8
+	// ip operator
+	OperatorInCIDR = Operator("in_cidr")
+
+	// OperatorSize matches the array field with exactly n elements, n is the value
+	// size operators
+	OperatorSize = Operator("size")
+	// OperatorSizeGreaterOrEqual matches the array field with at least n elements, n is the value
+	OperatorSizeGreaterOrEqual = Operator("size_greater_or_equal")
+	// OperatorSizeLessOrEqual matches the array field with at most n elements, n is the value
+	OperatorSizeLessOrEqual = Operator("size_less_or_equal")
+
+	// OperatorFieldCompare compares the field with another field of the same record, the value is a FieldCompareValue
+	// field compare operator
+	OperatorFieldCompare = Operator("field_compare")
+
 	// OperatorIsEmpty TODO
 	// array operator
 	OperatorIsEmpty = Operator("is_empty")
@@ -151,6 +216,10 @@ var SupportOperators = map[Operator]bool{
 	OperatorDatetimeGreater:        true,
 	OperatorDatetimeGreaterOrEqual: true,
 
+	OperatorWithinDays:  true,
+	OperatorWithinHours: true,
+	OperatorOlderThan:   true,
+
 	OperatorBeginsWith:    true,
 	OperatorNotBeginsWith: true,
 	OperatorContains:      true,
@@ -158,6 +227,17 @@ var SupportOperators = map[Operator]bool{
 	OperatorsEndsWith:     true,
 	OperatorNotEndsWith:   true,
 
+	OperatorRegex:  true,
+	OperatorIRegex: true,
+
+	OperatorInCIDR: true,
+
+	OperatorSize:               true,
+	OperatorSizeGreaterOrEqual: true,
+	OperatorSizeLessOrEqual:    true,
+
+	OperatorFieldCompare: true,
+
 	OperatorIsEmpty:    true,
 	OperatorIsNotEmpty: true,
 
@@ -168,12 +248,15 @@ var SupportOperators = map[Operator]bool{
 	OperatorNotExist: true,
 }
 
-// Validate TODO
+// Validate checks if the operator is a builtin operator or a registered custom operator
 func (op Operator) Validate() error {
-	if support, ok := SupportOperators[op]; !support || !ok {
-		return fmt.Errorf("unsupported operator: %s", op)
+	if support, ok := SupportOperators[op]; support && ok {
+		return nil
 	}
-	return nil
+	if _, exists := customOperator(op); exists {
+		return nil
+	}
+	return fmt.Errorf("unsupported operator: %s", op)
 }
 
 // AtomRule TODO
@@ -197,6 +280,15 @@ func (r AtomRule) Validate(option *RuleOption) (string, error) {
 	if err := r.validateField(); err != nil {
 		return "field", err
 	}
+	if err := r.validateFieldOperator(option); err != nil {
+		return "operator", err
+	}
+	if err := r.validateRuleField(option); err != nil {
+		return "field", err
+	}
+	if err := r.validateValueLimit(option); err != nil {
+		return "value", err
+	}
 	if err := r.validateValue(option); err != nil {
 		return "value", err
 	}
@@ -229,6 +321,22 @@ func (r AtomRule) validateField() error {
 	return nil
 }
 
+// validateFieldOperator checks if the operator is in the whitelist of the field, the fields without whitelist can
+// use all the operators.
+func (r AtomRule) validateFieldOperator(option *RuleOption) error {
+	operators, exists := option.FieldOperators[r.Field]
+	if !exists {
+		return nil
+	}
+
+	for _, operator := range operators {
+		if r.Operator == operator {
+			return nil
+		}
+	}
+	return fmt.Errorf("operator %s is not allowed on field %s, allowed operators: %v", r.Operator, r.Field, operators)
+}
+
 func (r AtomRule) validateValue(option *RuleOption) error {
 	switch r.Operator {
 	case OperatorEqual, OperatorNotEqual:
@@ -241,8 +349,18 @@ func (r AtomRule) validateValue(option *RuleOption) error {
 		return validateNumericType(r.Value)
 	case OperatorDatetimeLess, OperatorDatetimeLessOrEqual, OperatorDatetimeGreater, OperatorDatetimeGreaterOrEqual:
 		return validateDatetimeStringType(r.Value)
+	case OperatorWithinDays, OperatorWithinHours, OperatorOlderThan:
+		return r.validateRelativeTimeValue(option)
 	case OperatorBeginsWith, OperatorNotBeginsWith, OperatorContains, OperatorNotContains, OperatorsEndsWith, OperatorNotEndsWith:
 		return validateNotEmptyStringType(r.Value)
+	case OperatorRegex, OperatorIRegex:
+		return validateRegexType(r.Value)
+	case OperatorInCIDR:
+		return validateCIDRType(r.Value)
+	case OperatorSize, OperatorSizeGreaterOrEqual, OperatorSizeLessOrEqual:
+		return validateSizeValue(r.Value)
+	case OperatorFieldCompare:
+		return r.validateFieldCompareValue(option)
 	case OperatorIsEmpty, OperatorIsNotEmpty:
 		return nil
 	case OperatorIsNull, OperatorIsNotNull:
@@ -250,7 +368,7 @@ func (r AtomRule) validateValue(option *RuleOption) error {
 	case OperatorExist, OperatorNotExist:
 		return nil
 	default:
-		return fmt.Errorf("unsupported operator: %s", r.Operator)
+		return r.validateCustomValue(option)
 	}
 }
 
@@ -326,6 +444,22 @@ func (r AtomRule) ToMgo() (mgoFiler map[string]interface{}, key string, err erro
 		filter[r.Field] = map[string]interface{}{
 			common.BKDBGTE: r.Value.(string),
 		}
+	case OperatorWithinDays, OperatorWithinHours:
+		boundary, err := r.relativeTime(time.Now())
+		if err != nil {
+			return nil, "value", err
+		}
+		filter[r.Field] = map[string]interface{}{
+			common.BKDBGTE: boundary,
+		}
+	case OperatorOlderThan:
+		boundary, err := r.relativeTime(time.Now())
+		if err != nil {
+			return nil, "value", err
+		}
+		filter[r.Field] = map[string]interface{}{
+			common.BKDBLT: boundary,
+		}
 	case OperatorBeginsWith:
 		filter[r.Field] = map[string]interface{}{
 			common.BKDBLIKE: fmt.Sprintf("^%s", r.Value),
@@ -351,6 +485,35 @@ func (r AtomRule) ToMgo() (mgoFiler map[string]interface{}, key string, err erro
 		filter[r.Field] = map[string]interface{}{
 			common.BKDBNot: map[string]interface{}{common.BKDBLIKE: fmt.Sprintf("%s$", r.Value)},
 		}
+	case OperatorRegex:
+		filter[r.Field] = map[string]interface{}{
+			common.BKDBLIKE: r.Value,
+		}
+	case OperatorIRegex:
+		filter[r.Field] = map[string]interface{}{
+			common.BKDBLIKE:    r.Value,
+			common.BKDBOPTIONS: "i",
+		}
+	case OperatorInCIDR:
+		pattern, err := r.cidrRegex()
+		if err != nil {
+			return nil, "value", err
+		}
+		filter[r.Field] = map[string]interface{}{
+			common.BKDBLIKE: pattern,
+		}
+	case OperatorSize, OperatorSizeGreaterOrEqual, OperatorSizeLessOrEqual:
+		sizeFilter, err := r.sizeMgo()
+		if err != nil {
+			return nil, "value", err
+		}
+		return sizeFilter, "", nil
+	case OperatorFieldCompare:
+		compareFilter, err := r.fieldCompareMgo()
+		if err != nil {
+			return nil, "value", err
+		}
+		return compareFilter, "", nil
 	case OperatorIsEmpty:
 		// array empty
 		filter[r.Field] = map[string]interface{}{
@@ -378,13 +541,22 @@ func (r AtomRule) ToMgo() (mgoFiler map[string]interface{}, key string, err erro
 			common.BKDBExists: false,
 		}
 	default:
-		return nil, "operator", fmt.Errorf("unsupported operator: %s", r.Operator)
+		customFilter, err := r.customMgo()
+		if err != nil {
+			return nil, "operator", err
+		}
+		return customFilter, "", nil
 	}
 	return filter, "", nil
 }
 
-// GetField get rule field
+// GetField get rule field, the compared field of the field compare rule is returned too
 func (r AtomRule) GetField() []string {
+	if r.Operator == OperatorFieldCompare {
+		if compare, err := fieldCompareValue(r.Value); err == nil {
+			return []string{r.Field, compare.Field}
+		}
+	}
 	return []string{r.Field}
 }
 
@@ -404,6 +576,16 @@ var (
 
 	// DefaultMaxConditionOrRulesCount is default max rules count of one OR combined condition.
 	DefaultMaxConditionOrRulesCount = 20
+
+	// MaxRegexPatternLength is max length of the regular expression of the regex operators.
+	MaxRegexPatternLength = 256
+
+	// DefaultMaxValueBytes is default max bytes of the value of one atom rule.
+	DefaultMaxValueBytes = 64 * 1024
+
+	// DefaultMaxStringLength is default max characters of the string values of one atom rule, which is the length
+	// limit of the long char fields.
+	DefaultMaxStringLength = common.FieldTypeLongLenChar
 )
 
 // RuleOption is combined condition rule validator option.
@@ -414,11 +596,36 @@ type RuleOption struct {
 	// MaxSliceElementsCount max slice(array) value elements count, 0 means no limit.
 	MaxSliceElementsCount int
 
+	// MaxValueBytes max bytes of the value of one atom rule, the non-string values are measured by their json
+	// encoding, 0 means no limit. It applies to all the operators except in/nin, whose value size is already bounded
+	// by MaxSliceElementsCount and MaxStringLength.
+	MaxValueBytes int
+
+	// MaxStringLength max characters of each string in the value of one atom rule, including the elements of the
+	// in/nin values, 0 means no limit.
+	MaxStringLength int
+
 	// MaxConditionOrRulesCount max atom rules count in one OR combined condition, 0 means no limit.
 	MaxConditionOrRulesCount int
 
 	// MaxConditionAndRulesCount max atom rules count in one AND combined condition, 0 means no limit.
 	MaxConditionAndRulesCount int
+
+	// FieldOperators the operators that are allowed on the fields, e.g. the expensive regex/contains queries can be
+	// prevented on the unindexed fields. The fields not in it can use all the operators.
+	FieldOperators map[string][]Operator
+
+	// MaxRelativeDays the max number of days of the relative time operators, within_hours can be at most 24 times
+	// of it, DefaultMaxRelativeDays is used if not set.
+	MaxRelativeDays int64
+
+	// RuleFields the property types of the fields that the rules can use, the field compare rules must compare the
+	// fields in it with the comparable types. The compared fields are not checked if it is not set, e.g. when the
+	// validated rule is converted to the db filter.
+	// The sub fields of the object fields are declared by the nested paths, e.g. "disk": "object" with
+	// "disk.capacity": "int", then the rules on the undeclared sub fields of disk are rejected, FlattenRuleFields
+	// generates them from the nested schema. The operators must suit the types of the declared fields.
+	RuleFields map[string]string
 }
 
 // GetDeep TODO
@@ -435,6 +642,20 @@ func (r CombinedRule) GetDeep() int {
 
 // Validate validates combined rules with the options.
 func (r CombinedRule) Validate(option *RuleOption) (string, error) {
+	if key, err := r.validateCombination(option); err != nil {
+		return key, err
+	}
+
+	for idx, rule := range r.Rules {
+		if key, err := rule.Validate(option); err != nil {
+			return fmt.Sprintf("rules[%d].%s", idx, key), err
+		}
+	}
+	return "", nil
+}
+
+// validateCombination validates the condition and the count of the rules, the rules themselves are not validated.
+func (r CombinedRule) validateCombination(option *RuleOption) (string, error) {
 	if err := r.Condition.Validate(); err != nil {
 		return "condition", err
 	}
@@ -457,12 +678,6 @@ func (r CombinedRule) Validate(option *RuleOption) (string, error) {
 		return "rules", fmt.Errorf("too many rules of AND condition: %d max(%d)",
 			len(r.Rules), option.MaxConditionAndRulesCount)
 	}
-
-	for idx, rule := range r.Rules {
-		if key, err := rule.Validate(option); err != nil {
-			return fmt.Sprintf("rules[%d].%s", idx, key), err
-		}
-	}
 	return "", nil
 }
 
@@ -513,6 +728,13 @@ func (r CombinedRule) Match(matcher Matcher) bool {
 			}
 		}
 		return false
+	case ConditionNot:
+		for _, rule := range r.Rules {
+			if rule.Match(matcher) == true {
+				return false
+			}
+		}
+		return true
 	default:
 		panic(fmt.Sprintf("unexpected condition %s", r.Condition))
 	}
@@ -16,6 +16,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp/syntax"
 	"time"
 
 	"configcenter/src/common/util"
@@ -93,6 +94,52 @@ func validateDatetimeStringType(value interface{}) error {
 	return nil
 }
 
+// validateRegexType validates the regular expression of the regex operators, the pattern is parsed with the perl
+// syntax, and the nested quantifiers like (a+)+ are rejected since they backtrack catastrophically in the database.
+func validateRegexType(value interface{}) error {
+	if err := validateNotEmptyStringType(value); err != nil {
+		return err
+	}
+
+	pattern := value.(string)
+	if len(pattern) > MaxRegexPatternLength {
+		return fmt.Errorf("regular expression too long: %d max(%d)", len(pattern), MaxRegexPatternLength)
+	}
+
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression: %v", err)
+	}
+
+	if hasNestedRepeat(re, false) {
+		return fmt.Errorf("regular expression %s has nested quantifiers", pattern)
+	}
+	return nil
+}
+
+// hasNestedRepeat checks if the regular expression has a repetition inside another one, inRepeat means whether the
+// regular expression is inside a repetition.
+func hasNestedRepeat(re *syntax.Regexp, inRepeat bool) bool {
+	isRepeat := false
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		isRepeat = true
+	case syntax.OpRepeat:
+		isRepeat = re.Max == -1 || re.Max > 1
+	}
+
+	if isRepeat && inRepeat {
+		return true
+	}
+
+	for _, sub := range re.Sub {
+		if hasNestedRepeat(sub, inRepeat || isRepeat) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateSliceOfBasicType(value interface{}, requireSameType bool, maxElementsCount int) error {
 	if value == nil {
 		return nil
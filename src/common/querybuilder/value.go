@@ -0,0 +1,187 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// the typed getters of the rule value, the value is coerced in the same way as it is validated, e.g. the numeric
+// getters accept all the numeric types including json.Number but not the numeric strings, so that the consumers do
+// not need to assert the value types by themselves.
+
+// GetStringValue returns the string value of the rule.
+func (r AtomRule) GetStringValue() (string, error) {
+	if err := validateStringType(r.Value); err != nil {
+		return "", err
+	}
+	return r.Value.(string), nil
+}
+
+// GetBoolValue returns the bool value of the rule.
+func (r AtomRule) GetBoolValue() (bool, error) {
+	if err := validateBoolType(r.Value); err != nil {
+		return false, err
+	}
+	return r.Value.(bool), nil
+}
+
+// GetInt64Value returns the integer value of the rule, the float value is accepted only if it is an integer.
+func (r AtomRule) GetInt64Value() (int64, error) {
+	return int64Value(r.Value)
+}
+
+// GetFloat64Value returns the numeric value of the rule as float64.
+func (r AtomRule) GetFloat64Value() (float64, error) {
+	return float64Value(r.Value)
+}
+
+// GetTimeValue returns the time value of the rule, which is parsed from the datetime string of the datetime
+// operators, or resolved as the time boundary against now for the relative time operators.
+func (r AtomRule) GetTimeValue() (time.Time, error) {
+	switch r.Operator {
+	case OperatorWithinDays, OperatorWithinHours, OperatorOlderThan:
+		return r.relativeTime(time.Now())
+	}
+
+	if err := validateDatetimeStringType(r.Value); err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(timeLayout, r.Value.(string))
+}
+
+// GetSliceValue returns the elements of the slice value of the rule, nil value is returned as an empty slice.
+func (r AtomRule) GetSliceValue() ([]interface{}, error) {
+	if r.Value == nil {
+		return make([]interface{}, 0), nil
+	}
+
+	v := reflect.ValueOf(r.Value)
+	if v.Kind() != reflect.Array && v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("unexpected value type: %s, expect array", v.Kind().String())
+	}
+
+	values := make([]interface{}, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		values[i] = v.Index(i).Interface()
+		if err := validateBasicType(values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// GetStringSlice returns the string slice value of the rule.
+func (r AtomRule) GetStringSlice() ([]string, error) {
+	values, err := r.GetSliceValue()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(values))
+	for i, value := range values {
+		if err := validateStringType(value); err != nil {
+			return nil, fmt.Errorf("element %d is invalid, err: %v", i, err)
+		}
+		result[i] = value.(string)
+	}
+	return result, nil
+}
+
+// GetInt64Slice returns the integer slice value of the rule.
+func (r AtomRule) GetInt64Slice() ([]int64, error) {
+	values, err := r.GetSliceValue()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]int64, len(values))
+	for i, value := range values {
+		if result[i], err = int64Value(value); err != nil {
+			return nil, fmt.Errorf("element %d is invalid, err: %v", i, err)
+		}
+	}
+	return result, nil
+}
+
+// ValueAs decodes the rule value into the pointer of the typed value with the typed getters of the rule, the
+// supported pointers are *string, *bool, *int64, *int, *float64, *time.Time, *[]string, *[]int64 and
+// *[]interface{}. e.g.
+//
+//	ids := make([]int64, 0)
+//	err := querybuilder.ValueAs(rule, &ids)
+func ValueAs(r AtomRule, result interface{}) error {
+	var err error
+	switch v := result.(type) {
+	case *string:
+		*v, err = r.GetStringValue()
+	case *bool:
+		*v, err = r.GetBoolValue()
+	case *int64:
+		*v, err = r.GetInt64Value()
+	case *int:
+		var value int64
+		value, err = r.GetInt64Value()
+		*v = int(value)
+	case *float64:
+		*v, err = r.GetFloat64Value()
+	case *time.Time:
+		*v, err = r.GetTimeValue()
+	case *[]string:
+		*v, err = r.GetStringSlice()
+	case *[]int64:
+		*v, err = r.GetInt64Slice()
+	case *[]interface{}:
+		*v, err = r.GetSliceValue()
+	default:
+		return fmt.Errorf("unsupported result type: %T", result)
+	}
+
+	if err != nil {
+		return fmt.Errorf("field %s value is invalid, err: %v", r.Field, err)
+	}
+	return nil
+}
+
+// int64Value parses the numeric value as an integer, the float value is accepted only if it is an integer.
+func int64Value(value interface{}) (int64, error) {
+	if err := validateNumericType(value); err != nil {
+		return 0, err
+	}
+
+	if number, err := strconv.ParseInt(fmt.Sprint(value), 10, 64); err == nil {
+		return number, nil
+	}
+
+	number, err := float64Value(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if number != math.Trunc(number) || number > math.MaxInt64 || number < math.MinInt64 {
+		return 0, fmt.Errorf("value %v is not an integer", value)
+	}
+	return int64(number), nil
+}
+
+// float64Value parses the numeric value as float64.
+func float64Value(value interface{}) (float64, error) {
+	if err := validateNumericType(value); err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(fmt.Sprint(value), 64)
+}
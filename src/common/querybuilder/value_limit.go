@@ -0,0 +1,92 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+)
+
+// validateValueLimit checks the value against MaxValueBytes and MaxStringLength before the value is validated by its
+// operator, so that the huge values are rejected before they are parsed or sent to the db. The in/nin values are not
+// limited by MaxValueBytes, their size is bounded by MaxSliceElementsCount and MaxStringLength of each element.
+func (r AtomRule) validateValueLimit(option *RuleOption) error {
+	if option.MaxStringLength > 0 {
+		if length := maxStringLength(r.Value); length > option.MaxStringLength {
+			return fmt.Errorf("string value too long: %d max(%d)", length, option.MaxStringLength)
+		}
+	}
+
+	if option.MaxValueBytes > 0 && r.Operator != OperatorIn && r.Operator != OperatorNotIn {
+		size, err := valueBytes(r.Value)
+		if err != nil {
+			return err
+		}
+		if size > option.MaxValueBytes {
+			return fmt.Errorf("value too large: %d bytes max(%d)", size, option.MaxValueBytes)
+		}
+	}
+	return nil
+}
+
+// maxStringLength returns the max character count of the strings in the value, including the ones in the arrays and
+// objects, returns 0 if the value has no string.
+func maxStringLength(value interface{}) int {
+	if value == nil {
+		return 0
+	}
+
+	switch val := value.(type) {
+	case string:
+		return utf8.RuneCountInString(val)
+	case json.Number, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return 0
+	}
+
+	maxLength := 0
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if length := maxStringLength(v.Index(i).Interface()); length > maxLength {
+				maxLength = length
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if length := maxStringLength(iter.Value().Interface()); length > maxLength {
+				maxLength = length
+			}
+		}
+	case reflect.String:
+		return utf8.RuneCountInString(v.String())
+	}
+	return maxLength
+}
+
+// valueBytes returns the byte size of the value, the string is measured by itself to avoid encoding the huge string,
+// and the other values are measured by their json encoding.
+func valueBytes(value interface{}) (int, error) {
+	if str, ok := value.(string); ok {
+		return len(str), nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return 0, fmt.Errorf("encode value failed, err: %v", err)
+	}
+	return len(raw), nil
+}
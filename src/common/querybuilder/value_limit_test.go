@@ -0,0 +1,84 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"strings"
+	"testing"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateValueLimit(t *testing.T) {
+	option := &querybuilder.RuleOption{MaxValueBytes: 32, MaxStringLength: 8, MaxSliceElementsCount: 3}
+
+	validRules := []querybuilder.AtomRule{
+		{Field: "name", Operator: querybuilder.OperatorContains, Value: "12345678"},
+		{Field: "name", Operator: querybuilder.OperatorEqual, Value: "中文字符中文字符"},
+		{Field: "name", Operator: querybuilder.OperatorIn, Value: []interface{}{"12345678", "12345678", "12345678"}},
+		{Field: "count", Operator: querybuilder.OperatorGreater, Value: 100},
+		{Field: "name", Operator: querybuilder.OperatorExist, Value: nil},
+	}
+	for _, rule := range validRules {
+		_, err := rule.Validate(option)
+		assert.NoError(t, err, "rule: %v", rule)
+	}
+
+	invalidRules := []querybuilder.AtomRule{
+		{Field: "name", Operator: querybuilder.OperatorContains, Value: strings.Repeat("a", 5*1024*1024)},
+		{Field: "name", Operator: querybuilder.OperatorEqual, Value: "123456789"},
+		{Field: "name", Operator: querybuilder.OperatorIn, Value: []interface{}{"1", "123456789"}},
+		{Field: "name", Operator: querybuilder.OperatorRegex, Value: "123456789"},
+	}
+	for _, rule := range invalidRules {
+		key, err := rule.Validate(option)
+		assert.Error(t, err, "rule: %v", rule)
+		assert.Equal(t, "value", key)
+	}
+
+	// the in/nin values are only limited by the element count and the length of each element
+	option.MaxStringLength = 0
+	rule := querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorIn,
+		Value: []interface{}{strings.Repeat("a", 20), strings.Repeat("b", 20)}}
+	_, err := rule.Validate(option)
+	assert.NoError(t, err)
+
+	rule.Operator = querybuilder.OperatorEqual
+	rule.Value = strings.Repeat("a", 33)
+	_, err = rule.Validate(option)
+	assert.Error(t, err)
+
+	// no limit by default
+	_, err = rule.Validate(&querybuilder.RuleOption{})
+	assert.NoError(t, err)
+}
+
+func TestExplainValueLimit(t *testing.T) {
+	option := &querybuilder.RuleOption{MaxValueBytes: 32, MaxStringLength: 8}
+
+	rule := querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorContains, Value: "abc"}
+	node := rule.Explain(option)
+	assert.Contains(t, node.Limits, "MaxValueBytes: 3/32")
+	assert.Contains(t, node.Limits, "MaxStringLength: 3/8")
+	assert.Empty(t, node.Error)
+
+	rule = querybuilder.AtomRule{Field: "name", Operator: querybuilder.OperatorIn, Value: []interface{}{"abcdefghi"}}
+	node = rule.Explain(option)
+	for _, limit := range node.Limits {
+		assert.False(t, strings.HasPrefix(limit, "MaxValueBytes"), "limit: %s", limit)
+	}
+	assert.Contains(t, node.Limits, "MaxStringLength: 9/8")
+	assert.NotEmpty(t, node.Error)
+}
@@ -0,0 +1,115 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"configcenter/src/common/querybuilder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtomRuleTypedValue(t *testing.T) {
+	str, err := querybuilder.AtomRule{Value: "abc"}.GetStringValue()
+	require.NoError(t, err)
+	assert.Equal(t, "abc", str)
+
+	_, err = querybuilder.AtomRule{Value: 1}.GetStringValue()
+	assert.Error(t, err)
+
+	b, err := querybuilder.AtomRule{Value: true}.GetBoolValue()
+	require.NoError(t, err)
+	assert.True(t, b)
+
+	// the numeric values of all types are coerced, but the numeric strings are not.
+	for _, value := range []interface{}{int32(7), uint8(7), 7.0, json.Number("7")} {
+		i, err := querybuilder.AtomRule{Value: value}.GetInt64Value()
+		require.NoError(t, err, "value: %#v", value)
+		assert.Equal(t, int64(7), i)
+	}
+
+	for _, value := range []interface{}{"7", 7.5, nil} {
+		_, err := querybuilder.AtomRule{Value: value}.GetInt64Value()
+		assert.Error(t, err, "value: %#v", value)
+	}
+
+	f, err := querybuilder.AtomRule{Value: json.Number("1.5")}.GetFloat64Value()
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, f)
+
+	big, err := querybuilder.AtomRule{Value: int64(1) << 62}.GetInt64Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1)<<62, big)
+}
+
+func TestAtomRuleSliceValue(t *testing.T) {
+	strs, err := querybuilder.AtomRule{Value: []interface{}{"a", "b"}}.GetStringSlice()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, strs)
+
+	_, err = querybuilder.AtomRule{Value: []interface{}{"a", 1}}.GetStringSlice()
+	assert.Error(t, err)
+
+	ids, err := querybuilder.AtomRule{Value: []interface{}{1, int64(2), 3.0}}.GetInt64Slice()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+
+	ids, err = querybuilder.AtomRule{Value: []int{4, 5}}.GetInt64Slice()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 5}, ids)
+
+	values, err := querybuilder.AtomRule{}.GetSliceValue()
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	_, err = querybuilder.AtomRule{Value: "a"}.GetSliceValue()
+	assert.Error(t, err)
+}
+
+func TestAtomRuleTimeValue(t *testing.T) {
+	rule := querybuilder.AtomRule{Operator: querybuilder.OperatorDatetimeLess, Value: "2022-01-02"}
+	tm, err := rule.GetTimeValue()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), tm)
+
+	rule = querybuilder.AtomRule{Operator: querybuilder.OperatorWithinHours, Value: 2}
+	tm, err = rule.GetTimeValue()
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-2*time.Hour), tm, time.Minute)
+
+	rule = querybuilder.AtomRule{Operator: querybuilder.OperatorDatetimeLess, Value: "2022/01/02"}
+	_, err = rule.GetTimeValue()
+	assert.Error(t, err)
+}
+
+func TestValueAs(t *testing.T) {
+	rule := querybuilder.AtomRule{Field: "bk_host_id", Value: []interface{}{1, 2}}
+	ids := make([]int64, 0)
+	require.NoError(t, querybuilder.ValueAs(rule, &ids))
+	assert.Equal(t, []int64{1, 2}, ids)
+
+	var name string
+	assert.Error(t, querybuilder.ValueAs(rule, &name))
+
+	var unsupported map[string]interface{}
+	assert.Error(t, querybuilder.ValueAs(rule, &unsupported))
+
+	rule = querybuilder.AtomRule{Field: "bk_host_id", Value: 3}
+	var id int
+	require.NoError(t, querybuilder.ValueAs(rule, &id))
+	assert.Equal(t, 3, id)
+}
@@ -0,0 +1,161 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package script runs the lightweight transformation hooks written in starlark in a sandbox, the hooks are used to
+// transform the data on the ingest paths, e.g. the cloud sync host fields and the host snapshot fields.
+package script
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"configcenter/src/common/json"
+
+	"go.starlark.net/starlark"
+)
+
+const (
+	// transformFuncName the name of the function that the hook script must define
+	transformFuncName = "transform"
+	// MaxScriptSize the max size of the hook script in bytes
+	MaxScriptSize = 64 * 1024
+)
+
+// Limits is the resource limits of running a hook.
+// starlark has no memory limit, the memory usage is limited by the max execution steps, the timeout and the
+// max result size together.
+type Limits struct {
+	// MaxSteps the max execution steps of a call, it limits the cpu usage.
+	MaxSteps uint64
+	// Timeout the max running time of a call.
+	Timeout time.Duration
+	// MaxResultSize the max size of the json encoded transformed data in bytes.
+	MaxResultSize int
+}
+
+// DefaultLimits is the default resource limits of running a hook
+var DefaultLimits = Limits{
+	MaxSteps:      100000,
+	Timeout:       100 * time.Millisecond,
+	MaxResultSize: 64 * 1024,
+}
+
+// Hook is a compiled transformation hook, the script must define a function named transform, which receives the
+// data as a dict and returns the transformed dict, e.g.
+//
+//	def transform(data):
+//	    data["bk_os_name"] = data.get("bk_os_name", "").lower()
+//	    return data
+//
+// the script can not load modules or access the file system and network, the hook is safe for concurrent use.
+type Hook struct {
+	name      string
+	transform *starlark.Function
+	limits    Limits
+}
+
+// NewHook compile the hook script with the name used in the error messages
+func NewHook(name, source string, limits Limits) (*Hook, error) {
+	if len(source) > MaxScriptSize {
+		return nil, fmt.Errorf("hook %s script size %d exceeds %d", name, len(source), MaxScriptSize)
+	}
+
+	thread := newThread(name, limits)
+	globals, err := starlark.ExecFile(thread, name, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("compile hook %s failed, err: %v", name, err)
+	}
+	globals.Freeze()
+
+	fn, ok := globals[transformFuncName].(*starlark.Function)
+	if !ok {
+		return nil, fmt.Errorf("hook %s does not define function %s", name, transformFuncName)
+	}
+
+	if fn.NumParams() != 1 {
+		return nil, fmt.Errorf("hook %s function %s must have exactly one parameter", name, transformFuncName)
+	}
+
+	return &Hook{name: name, transform: fn, limits: limits}, nil
+}
+
+// NewHookFromFile compile the hook script in the file
+func NewHookFromFile(name, file string, limits Limits) (*Hook, error) {
+	source, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read hook %s script file %s failed, err: %v", name, file, err)
+	}
+
+	return NewHook(name, string(source), limits)
+}
+
+// Name returns the name of the hook
+func (h *Hook) Name() string {
+	return h.name
+}
+
+// Transform call the transform function of the hook with the data, and returns the transformed data.
+// the data is not changed by the hook.
+func (h *Hook) Transform(data map[string]interface{}) (map[string]interface{}, error) {
+	input, err := toStarlark(data)
+	if err != nil {
+		return nil, fmt.Errorf("convert hook %s input failed, err: %v", h.name, err)
+	}
+
+	thread := newThread(h.name, h.limits)
+	if h.limits.Timeout > 0 {
+		timer := time.AfterFunc(h.limits.Timeout, func() {
+			thread.Cancel(fmt.Sprintf("timeout after %s", h.limits.Timeout))
+		})
+		defer timer.Stop()
+	}
+
+	result, err := starlark.Call(thread, h.transform, starlark.Tuple{input}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("run hook %s failed, err: %v", h.name, err)
+	}
+
+	output, err := fromStarlark(result)
+	if err != nil {
+		return nil, fmt.Errorf("convert hook %s output failed, err: %v", h.name, err)
+	}
+
+	outputMap, ok := output.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("hook %s returns %s, not a dict", h.name, result.Type())
+	}
+
+	if h.limits.MaxResultSize > 0 {
+		js, err := json.Marshal(outputMap)
+		if err != nil {
+			return nil, fmt.Errorf("marshal hook %s output failed, err: %v", h.name, err)
+		}
+		if len(js) > h.limits.MaxResultSize {
+			return nil, fmt.Errorf("hook %s output size %d exceeds %d", h.name, len(js), h.limits.MaxResultSize)
+		}
+	}
+
+	return outputMap, nil
+}
+
+// newThread new a starlark thread without the load and print ability, and with the max execution steps limit
+func newThread(name string, limits Limits) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name:  name,
+		Print: func(*starlark.Thread, string) {},
+	}
+	if limits.MaxSteps > 0 {
+		thread.SetMaxExecutionSteps(limits.MaxSteps)
+	}
+	return thread
+}
@@ -0,0 +1,109 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package script
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHookTransform(t *testing.T) {
+	source := `
+def transform(data):
+    data["bk_os_name"] = data.get("bk_os_name", "").lower()
+    data["bk_cpu"] = data["bk_cpu"] * 2
+    data.pop("bk_mac", None)
+    return data
+`
+	hook, err := NewHook("test", source, DefaultLimits)
+	if err != nil {
+		t.Fatalf("new hook failed, err: %v", err)
+	}
+
+	input := map[string]interface{}{"bk_os_name": "Linux", "bk_cpu": 4, "bk_mac": "00:00", "tags": []string{"a"}}
+	output, err := hook.Transform(input)
+	if err != nil {
+		t.Fatalf("transform failed, err: %v", err)
+	}
+
+	want := map[string]interface{}{"bk_os_name": "linux", "bk_cpu": int64(8), "tags": []interface{}{"a"}}
+	if !reflect.DeepEqual(output, want) {
+		t.Errorf("transform got %v, want %v", output, want)
+	}
+
+	if input["bk_os_name"] != "Linux" {
+		t.Errorf("transform changed the input data: %v", input)
+	}
+}
+
+func TestNewHookInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"syntax error", "def transform(data)\n    return data\n"},
+		{"no transform", "def convert(data):\n    return data\n"},
+		{"wrong params", "def transform(data, extra):\n    return data\n"},
+		{"load module", "load('x.star', 'y')\ndef transform(data):\n    return data\n"},
+		{"too large", "# " + strings.Repeat("x", MaxScriptSize)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHook(tt.name, tt.source, DefaultLimits); err == nil {
+				t.Errorf("NewHook() expect error, but got nil")
+			}
+		})
+	}
+}
+
+func TestHookLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		limits Limits
+	}{
+		{
+			name:   "max steps",
+			source: "def transform(data):\n    for i in range(100000000):\n        pass\n    return data\n",
+			limits: Limits{MaxSteps: 1000},
+		},
+		{
+			name:   "timeout",
+			source: "def transform(data):\n    for i in range(100000000):\n        pass\n    return data\n",
+			limits: Limits{Timeout: 10 * time.Millisecond},
+		},
+		{
+			name:   "result size",
+			source: "def transform(data):\n    data['x'] = 'x' * 1024\n    return data\n",
+			limits: Limits{MaxResultSize: 100},
+		},
+		{
+			name:   "not dict",
+			source: "def transform(data):\n    return [data]\n",
+			limits: DefaultLimits,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := NewHook(tt.name, tt.source, tt.limits)
+			if err != nil {
+				t.Fatalf("new hook failed, err: %v", err)
+			}
+			if _, err := hook.Transform(map[string]interface{}{}); err == nil {
+				t.Errorf("Transform() expect error, but got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"configcenter/src/common/mapstr"
+
+	"go.starlark.net/starlark"
+)
+
+// toStarlark convert the go value to the starlark value, values of other types are converted by json.
+func toStarlark(value interface{}) (starlark.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return starlark.None, nil
+	case string:
+		return starlark.String(v), nil
+	case bool:
+		return starlark.Bool(v), nil
+	case int:
+		return starlark.MakeInt(v), nil
+	case int32:
+		return starlark.MakeInt64(int64(v)), nil
+	case int64:
+		return starlark.MakeInt64(v), nil
+	case uint64:
+		return starlark.MakeUint64(v), nil
+	case float32:
+		return starlark.Float(v), nil
+	case float64:
+		return starlark.Float(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return starlark.MakeInt64(i), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return starlark.Float(f), nil
+	case mapstr.MapStr:
+		return toStarlark(map[string]interface{}(v))
+	case map[string]interface{}:
+		dict := starlark.NewDict(len(v))
+		for key, val := range v {
+			item, err := toStarlark(val)
+			if err != nil {
+				return nil, fmt.Errorf("convert field %s failed, err: %v", key, err)
+			}
+			if err := dict.SetKey(starlark.String(key), item); err != nil {
+				return nil, err
+			}
+		}
+		return dict, nil
+	case []string:
+		list := make([]starlark.Value, len(v))
+		for index, val := range v {
+			list[index] = starlark.String(val)
+		}
+		return starlark.NewList(list), nil
+	case []interface{}:
+		list := make([]starlark.Value, len(v))
+		for index, val := range v {
+			item, err := toStarlark(val)
+			if err != nil {
+				return nil, err
+			}
+			list[index] = item
+		}
+		return starlark.NewList(list), nil
+	}
+
+	js, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(js, &generic); err != nil {
+		return nil, err
+	}
+	return toStarlark(generic)
+}
+
+// fromStarlark convert the starlark value to the go value, dict keys must be strings.
+func fromStarlark(value starlark.Value) (interface{}, error) {
+	switch v := value.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.Int:
+		i, ok := v.Int64()
+		if !ok {
+			return nil, fmt.Errorf("int %s out of range", v.String())
+		}
+		return i, nil
+	case starlark.Float:
+		return float64(v), nil
+	case *starlark.Dict:
+		result := make(map[string]interface{}, v.Len())
+		for _, item := range v.Items() {
+			key, ok := item[0].(starlark.String)
+			if !ok {
+				return nil, fmt.Errorf("dict key %s is not a string", item[0].String())
+			}
+			val, err := fromStarlark(item[1])
+			if err != nil {
+				return nil, fmt.Errorf("convert field %s failed, err: %v", string(key), err)
+			}
+			result[string(key)] = val
+		}
+		return result, nil
+	case *starlark.List:
+		result := make([]interface{}, v.Len())
+		for index := 0; index < v.Len(); index++ {
+			val, err := fromStarlark(v.Index(index))
+			if err != nil {
+				return nil, err
+			}
+			result[index] = val
+		}
+		return result, nil
+	case starlark.Tuple:
+		result := make([]interface{}, len(v))
+		for index, item := range v {
+			val, err := fromStarlark(item)
+			if err != nil {
+				return nil, err
+			}
+			result[index] = val
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("unsupported value type %s", value.Type())
+}
@@ -0,0 +1,380 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ssl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"configcenter/src/common/blog"
+)
+
+// ClientAuthMode is how the server authenticates the client certificates of the mutual tls.
+type ClientAuthMode string
+
+const (
+	// ClientAuthStrict the client must present a certificate signed by the ca, this is the default mode.
+	ClientAuthStrict ClientAuthMode = "strict"
+	// ClientAuthPermissive the client certificate is verified only if it is presented, which is used to migrate
+	// the services to the mutual tls one by one.
+	ClientAuthPermissive ClientAuthMode = "permissive"
+	// ClientAuthNone the client certificate is not requested.
+	ClientAuthNone ClientAuthMode = "none"
+)
+
+// Validate validate the client auth mode
+func (m ClientAuthMode) Validate() error {
+	switch m {
+	case ClientAuthStrict, ClientAuthPermissive, ClientAuthNone:
+		return nil
+	}
+	return fmt.Errorf("invalid client auth mode %s", m)
+}
+
+func (m ClientAuthMode) tlsClientAuth() tls.ClientAuthType {
+	switch m {
+	case ClientAuthPermissive:
+		return tls.VerifyClientCertIfGiven
+	case ClientAuthNone:
+		return tls.NoClientCert
+	default:
+		return tls.RequireAndVerifyClientCert
+	}
+}
+
+// DefaultReloadInterval is the default interval to check whether the certificates are rotated.
+const DefaultReloadInterval = time.Minute
+
+// CertBundle is the pem encoded certificates loaded from the cert source.
+type CertBundle struct {
+	CA   []byte
+	Cert []byte
+	Key  []byte
+}
+
+// CertSource is where the certificates are loaded from.
+type CertSource interface {
+	// Key returns the unique key of the source, the sources with the same key share the same reloader.
+	Key() string
+	// Load loads the latest certificates from the source.
+	Load() (*CertBundle, error)
+	// Password returns the password to decrypt the private key.
+	Password() string
+}
+
+// FileSource loads the certificates from the local files.
+type FileSource struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	Passwd   string
+}
+
+// Key returns the unique key of the file source
+func (s *FileSource) Key() string {
+	return "file:" + strings.Join([]string{s.CAFile, s.CertFile, s.KeyFile}, ",")
+}
+
+// Load loads the certificates from the files
+func (s *FileSource) Load() (*CertBundle, error) {
+	bundle := new(CertBundle)
+	var err error
+	if len(s.CAFile) != 0 {
+		if bundle.CA, err = ioutil.ReadFile(s.CAFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if bundle.Cert, err = ioutil.ReadFile(s.CertFile); err != nil {
+		return nil, err
+	}
+
+	if bundle.Key, err = ioutil.ReadFile(s.KeyFile); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// Password returns the password to decrypt the private key
+func (s *FileSource) Password() string {
+	return s.Passwd
+}
+
+// CertServiceConfig is the config of the blueking cert service which issues the certificates of the services.
+type CertServiceConfig struct {
+	// URL the url to get the certificates of the service, start with http:// or https://
+	URL string
+	// Token the token to access the cert service
+	Token string
+	// Timeout the timeout of the request to the cert service
+	Timeout time.Duration
+}
+
+// CertServiceSource loads the certificates from the blueking cert service.
+type CertServiceSource struct {
+	Config CertServiceConfig
+	Passwd string
+}
+
+type certServiceResp struct {
+	Result  bool   `json:"result"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		CA   string `json:"ca"`
+		Cert string `json:"cert"`
+		Key  string `json:"key"`
+	} `json:"data"`
+}
+
+// Key returns the unique key of the cert service source
+func (s *CertServiceSource) Key() string {
+	return "cert_service:" + s.Config.URL
+}
+
+// Load loads the certificates from the cert service
+func (s *CertServiceSource) Load() (*CertBundle, error) {
+	req, err := http.NewRequest(http.MethodGet, s.Config.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if len(s.Config.Token) != 0 {
+		req.Header.Set("Authorization", "Bearer "+s.Config.Token)
+	}
+
+	timeout := s.Config.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	resp, err := (&http.Client{Timeout: timeout}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cert service returns status %d, body: %s", resp.StatusCode, body)
+	}
+
+	result := new(certServiceResp)
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, err
+	}
+
+	if !result.Result {
+		return nil, fmt.Errorf("cert service returns error, code: %d, message: %s", result.Code, result.Message)
+	}
+
+	if len(result.Data.Cert) == 0 || len(result.Data.Key) == 0 {
+		return nil, errors.New("cert service returns empty certificate or key")
+	}
+
+	return &CertBundle{
+		CA:   []byte(result.Data.CA),
+		Cert: []byte(result.Data.Cert),
+		Key:  []byte(result.Data.Key),
+	}, nil
+}
+
+// Password returns the password to decrypt the private key
+func (s *CertServiceSource) Password() string {
+	return s.Passwd
+}
+
+// CertReloader holds the certificates loaded from the cert source and reloads them periodically, so that the
+// rotated certificates take effect on the new connections without restarting the service.
+type CertReloader struct {
+	source   CertSource
+	interval time.Duration
+	// checksum the checksum of the current certificates, which is only used by the reload loop.
+	checksum [sha256.Size]byte
+	// cert is the *tls.Certificate of the service
+	cert atomic.Value
+	// caPool is the *x509.CertPool to verify the peer, it is nil if the ca is not set.
+	caPool atomic.Value
+}
+
+var reloaders = struct {
+	sync.Mutex
+	m map[string]*CertReloader
+}{m: make(map[string]*CertReloader)}
+
+// GetCertReloader returns the reloader of the cert source, the reloader is created and started at the first call,
+// so the server and the clients of the same process share one reloader of the same certificates.
+func GetCertReloader(source CertSource, interval time.Duration) (*CertReloader, error) {
+	reloaders.Lock()
+	defer reloaders.Unlock()
+
+	key := source.Key()
+	if r, exists := reloaders.m[key]; exists {
+		return r, nil
+	}
+
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+
+	r := &CertReloader{source: source, interval: interval}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	go r.run()
+	reloaders.m[key] = r
+	return r, nil
+}
+
+func (r *CertReloader) run() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		changed, err := r.reload()
+		if err != nil {
+			// keep using the current certificates until the new ones are loaded successfully.
+			blog.Errorf("reload certificates from %s failed, err: %v", r.source.Key(), err)
+			continue
+		}
+
+		if changed {
+			blog.Infof("certificates from %s are rotated", r.source.Key())
+		}
+	}
+}
+
+// reload loads the certificates from the source, and replaces the current ones if they are changed.
+func (r *CertReloader) reload() (bool, error) {
+	bundle, err := r.source.Load()
+	if err != nil {
+		return false, err
+	}
+
+	checksum := sha256.Sum256(bytes.Join([][]byte{bundle.CA, bundle.Cert, bundle.Key}, []byte{0}))
+	if checksum == r.checksum {
+		return false, nil
+	}
+
+	cert, err := parseCertificates(bundle.Cert, bundle.Key, r.source.Password())
+	if err != nil {
+		return false, err
+	}
+
+	var caPool *x509.CertPool
+	if len(bundle.CA) != 0 {
+		if caPool, err = parseCa(bundle.CA); err != nil {
+			return false, err
+		}
+	}
+
+	r.cert.Store(cert)
+	r.caPool.Store(caPool)
+	r.checksum = checksum
+	return true, nil
+}
+
+// Certificate returns the current certificate
+func (r *CertReloader) Certificate() *tls.Certificate {
+	return r.cert.Load().(*tls.Certificate)
+}
+
+// CAPool returns the current ca pool, nil if the ca is not set
+func (r *CertReloader) CAPool() *x509.CertPool {
+	return r.caPool.Load().(*x509.CertPool)
+}
+
+// ServerTLSConfig returns the server tls config which always uses the current certificates, the client
+// certificates are authenticated by the mode.
+func (r *CertReloader) ServerTLSConfig(mode ClientAuthMode) (*tls.Config, error) {
+	if len(mode) == 0 {
+		mode = ClientAuthStrict
+	}
+
+	if err := mode.Validate(); err != nil {
+		return nil, err
+	}
+
+	if mode != ClientAuthNone && r.CAPool() == nil {
+		return nil, errors.New("ca is not set, can not verify client certificates")
+	}
+
+	getCertificate := func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return r.Certificate(), nil
+	}
+
+	return &tls.Config{
+		ClientAuth:     mode.tlsClientAuth(),
+		GetCertificate: getCertificate,
+		// the ca pool may be rotated, so the config with the current ca pool is generated for each handshake.
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{
+				ClientAuth:     mode.tlsClientAuth(),
+				ClientCAs:      r.CAPool(),
+				GetCertificate: getCertificate,
+			}, nil
+		},
+	}, nil
+}
+
+// ClientTLSConfig returns the client tls config which always presents the current certificate, and verifies
+// the server certificate with the current ca pool unless insecureSkipVerify is set.
+func (r *CertReloader) ClientTLSConfig(insecureSkipVerify bool) *tls.Config {
+	conf := &tls.Config{
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		},
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if insecureSkipVerify || r.CAPool() == nil {
+		return conf
+	}
+
+	// RootCAs can not be changed after the config is used, so the default verification is skipped and
+	// the server certificate is verified with the current ca pool instead.
+	conf.InsecureSkipVerify = true
+	conf.VerifyConnection = func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return errors.New("server does not present any certificate")
+		}
+
+		opts := x509.VerifyOptions{
+			DNSName:       state.ServerName,
+			Roots:         r.CAPool(),
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range state.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+
+		_, err := state.PeerCertificates[0].Verify(opts)
+		return err
+	}
+	return conf
+}
@@ -0,0 +1,153 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ssl
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// genCert generates a certificate signed by the parent, the certificate is self-signed if parent is nil.
+func genCert(t *testing.T, serial int64, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate,
+	*ecdsa.PrivateKey, []byte, []byte) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "cmdb"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		parent, parentKey = tmpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	source := &FileSource{
+		CAFile:   filepath.Join(dir, "ca.crt"),
+		CertFile: filepath.Join(dir, "server.crt"),
+		KeyFile:  filepath.Join(dir, "server.key"),
+	}
+
+	ca, caKey, caPem, _ := genCert(t, 1, nil, nil)
+	_, _, certPem, keyPem := genCert(t, 2, ca, caKey)
+	write := func(name string, data []byte) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("ca.crt", caPem)
+	write("server.crt", certPem)
+	write("server.key", keyPem)
+
+	r, err := GetCertReloader(source, time.Hour)
+	if err != nil {
+		t.Fatalf("get cert reloader failed, err: %v", err)
+	}
+
+	if same, _ := GetCertReloader(&FileSource{CAFile: source.CAFile, CertFile: source.CertFile,
+		KeyFile: source.KeyFile}, time.Hour); same != r {
+		t.Fatalf("the reloader of the same source is not shared")
+	}
+
+	changed, err := r.reload()
+	if err != nil || changed {
+		t.Fatalf("reload unchanged certificates, changed: %v, err: %v", changed, err)
+	}
+
+	_, _, newCertPem, newKeyPem := genCert(t, 3, ca, caKey)
+	write("server.crt", newCertPem)
+	write("server.key", newKeyPem)
+
+	changed, err = r.reload()
+	if err != nil || !changed {
+		t.Fatalf("reload rotated certificates, changed: %v, err: %v", changed, err)
+	}
+
+	leaf, err := x509.ParseCertificate(r.Certificate().Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaf.SerialNumber.Int64() != 3 {
+		t.Fatalf("certificate is not rotated, serial: %d", leaf.SerialNumber.Int64())
+	}
+
+	// the broken certificates are not loaded, the current ones are kept.
+	write("server.key", []byte("broken"))
+	if _, err := r.reload(); err == nil {
+		t.Fatalf("reload broken certificates should fail")
+	}
+	if r.Certificate().Certificate[0] == nil {
+		t.Fatalf("current certificate is lost")
+	}
+
+	serverConf, err := r.ServerTLSConfig("")
+	if err != nil {
+		t.Fatalf("get server tls config failed, err: %v", err)
+	}
+	conf, err := serverConf.GetConfigForClient(nil)
+	if err != nil || conf.ClientAuth != tls.RequireAndVerifyClientCert || conf.ClientCAs == nil {
+		t.Fatalf("server tls config is not strict, err: %v", err)
+	}
+
+	if _, err := r.ServerTLSConfig("unknown"); err == nil {
+		t.Fatalf("invalid client auth mode should fail")
+	}
+
+	clientConf := r.ClientTLSConfig(false)
+	if clientConf.VerifyConnection == nil {
+		t.Fatalf("client tls config does not verify the server")
+	}
+	if err := clientConf.VerifyConnection(tls.ConnectionState{ServerName: "localhost",
+		PeerCertificates: []*x509.Certificate{leaf}}); err != nil {
+		t.Fatalf("verify server certificate failed, err: %v", err)
+	}
+}
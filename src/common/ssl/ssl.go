@@ -91,6 +91,10 @@ func loadCa(caFile string) (*x509.CertPool, error) {
 		return nil, err
 	}
 
+	return parseCa(ca)
+}
+
+func parseCa(ca []byte) (*x509.CertPool, error) {
 	caPool := x509.NewCertPool()
 	if ok := caPool.AppendCertsFromPEM(ca); ok != true {
 		return nil, fmt.Errorf("append ca cert failed")
@@ -106,6 +110,16 @@ func loadCertificates(certFile, keyFile, passwd string) (*tls.Certificate, error
 		return nil, err
 	}
 
+	// certificate
+	certData, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCertificates(certData, priKey, passwd)
+}
+
+func parseCertificates(certData, priKey []byte, passwd string) (*tls.Certificate, error) {
 	if "" != passwd {
 		priPem, _ := pem.Decode(priKey)
 		if priPem == nil {
@@ -123,12 +137,6 @@ func loadCertificates(certFile, keyFile, passwd string) (*tls.Certificate, error
 		})
 	}
 
-	// certificate
-	certData, err := ioutil.ReadFile(certFile)
-	if err != nil {
-		return nil, err
-	}
-
 	tlsCert, err := tls.X509KeyPair(certData, priKey)
 	if err != nil {
 		return nil, err
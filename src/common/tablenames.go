@@ -78,6 +78,34 @@ const (
 	BKTableNameChartPosition = "cc_ChartPosition"
 	BKTableNameChartData     = "cc_ChartData"
 
+	// BKTableNameReportSubscription the table to store the users' periodic report subscriptions
+	BKTableNameReportSubscription = "cc_ReportSubscription"
+
+	// BKTableNameHostRecyclePolicy the table to store the policies that recycle the hosts automatically
+	BKTableNameHostRecyclePolicy = "cc_HostRecyclePolicy"
+	// BKTableNameHostRecycleReport the table to store the reports of the host recycle policies' runs
+	BKTableNameHostRecycleReport = "cc_HostRecycleReport"
+
+	// BKTableNameBizFieldLayout the table to store the businesses' field layouts of the host and instance forms
+	BKTableNameBizFieldLayout = "cc_BizFieldLayout"
+
+	// BKTableNameBizRoleAssignment the table to store the users assigned to the business roles of the topo nodes
+	BKTableNameBizRoleAssignment = "cc_BizRoleAssignment"
+
+	// BKTableNameAttrGroupDelegation the table to store the roles delegated to edit the attribute groups of the models
+	BKTableNameAttrGroupDelegation = "cc_AttrGroupDelegation"
+
+	// BKTableNameModelAsset the table to store the icons and metadata assets of the models stored in the blob store
+	BKTableNameModelAsset = "cc_ModelAsset"
+
+	// BKTableNameImportWriteRecord the table to store the last import writes of the instance attributes
+	BKTableNameImportWriteRecord = "cc_ImportWriteRecord"
+	// BKTableNameImportConflict the table to store the conflicts of the concurrent imports for review
+	BKTableNameImportConflict = "cc_ImportConflict"
+
+	// BKTableNameExternalID the table to store the ids of the hosts and instances in the external source systems
+	BKTableNameExternalID = "cc_ExternalID"
+
 	// process tables
 	BKTableNameServiceCategory         = "cc_ServiceCategory"
 	BKTableNameServiceTemplate         = "cc_ServiceTemplate"
@@ -86,11 +114,19 @@ const (
 	BKTableNameProcessTemplate         = "cc_ProcessTemplate"
 	BKTableNameProcessInstanceRelation = "cc_ProcessInstanceRelation"
 
+	// BKTableNameSrvInstNameTemplate the table to store the businesses' service instance naming templates
+	BKTableNameSrvInstNameTemplate = "cc_ServiceInstanceNameTemplate"
+
+	// BKTableNameTemplateVariable the table to store the template variables bound on the modules and sets
+	BKTableNameTemplateVariable = "cc_TemplateVariable"
+
 	BKTableNameSetTemplate                = "cc_SetTemplate"
 	BKTableNameSetTemplateAttr            = "cc_SetTemplateAttr"
 	BKTableNameSetServiceTemplateRelation = "cc_SetServiceTemplateRelation"
 	BKTableNameAPITask                    = "cc_APITask"
 	BKTableNameAPITaskSyncHistory         = "cc_APITaskSyncHistory"
+	// BKTableNameAPITaskFile the table to store the file data generated by the sub tasks of the api tasks
+	BKTableNameAPITaskFile = "cc_APITaskFile"
 
 	// rule for host property auto apply
 	BKTableNameHostApplyRule = "cc_HostApplyRule"
@@ -152,9 +188,22 @@ var AllTables = []string{
 	BKTableNameChartConfig,
 	BKTableNameChartPosition,
 	BKTableNameChartData,
+	BKTableNameReportSubscription,
+	BKTableNameHostRecyclePolicy,
+	BKTableNameHostRecycleReport,
+	BKTableNameBizFieldLayout,
+	BKTableNameBizRoleAssignment,
+	BKTableNameAttrGroupDelegation,
+	BKTableNameModelAsset,
+	BKTableNameImportWriteRecord,
+	BKTableNameImportConflict,
+	BKTableNameExternalID,
+	BKTableNameSrvInstNameTemplate,
+	BKTableNameTemplateVariable,
 	BKTableNameHostApplyRule,
 	BKTableNameAPITask,
 	BKTableNameAPITaskSyncHistory,
+	BKTableNameAPITaskFile,
 	BKTableNameCloudSyncTask,
 	BKTableNameCloudAccount,
 	BKTableNameCloudSyncHistory,
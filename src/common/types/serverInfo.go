@@ -30,6 +30,8 @@ const (
 	CC_SERVLANG_BASEPATH    = "/cc/services/language"
 	CC_SERVNOTICE_BASEPATH  = "/cc/services/notice"
 	CC_SERVLIMITER_BASEPATH = "/cc/services/limiter"
+	CC_SERVREADONLY_PATH    = "/cc/services/readonly"
+	CC_SERVFAULTINJECT_PATH = "/cc/services/faultinject"
 
 	CC_DISCOVERY_PREFIX = "cc_"
 )
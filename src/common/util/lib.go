@@ -282,6 +282,10 @@ func CCHeader(header http.Header) http.Header {
 	newHeader.Add(common.BKHTTPRequestAppCode, header.Get(common.BKHTTPRequestAppCode))
 	newHeader.Add(common.BKHTTPRequestRealIP, header.Get(common.BKHTTPRequestRealIP))
 	newHeader.Add(common.BKHTTPReadReference, header.Get(common.BKHTTPReadReference))
+	newHeader.Add(common.BKHTTPImportSource, header.Get(common.BKHTTPImportSource))
+	newHeader.Add(common.BKHTTPImportConflictPolicy, header.Get(common.BKHTTPImportConflictPolicy))
+	newHeader.Add(common.BKHTTPImportDataTime, header.Get(common.BKHTTPImportDataTime))
+	newHeader.Add(common.BKHTTPChangeSetID, header.Get(common.BKHTTPChangeSetID))
 
 	return newHeader
 }
@@ -28,6 +28,8 @@ func ValidPropertyOption(propertyType string, option interface{}, errProxy error
 	switch propertyType {
 	case common.FieldTypeEnum:
 		return ValidFieldTypeEnumOption(option, errProxy)
+	case common.FieldTypeEnumCascade:
+		return ValidFieldTypeEnumCascadeOption(option, errProxy)
 	case common.FieldTypeInt:
 		return ValidFieldTypeIntOption(option, errProxy)
 	case common.FieldTypeList:
@@ -95,6 +97,51 @@ func ValidFieldTypeEnumOption(option interface{}, errProxy errors.DefaultCCError
 	return nil
 }
 
+// ValidFieldTypeEnumCascadeOption validate the cascade enum option, which is like {"parent": "province", "options":
+// {"gd": [{"id": "sz", "name": "深圳", "type": "text"}]}}, the options are the enum options of each parent value.
+func ValidFieldTypeEnumCascadeOption(option interface{}, errProxy errors.DefaultCCErrorIf) error {
+	if nil == option {
+		return errProxy.Errorf(common.CCErrCommParamsLostField, "option")
+	}
+
+	mapOption, ok := option.(map[string]interface{})
+	if !ok {
+		blog.Errorf("option %v not enum cascade option", option)
+		return errProxy.Errorf(common.CCErrCommParamsIsInvalid, "option")
+	}
+
+	parent, ok := mapOption["parent"].(string)
+	if !ok || len(parent) == 0 {
+		blog.Errorf("enum cascade option parent %v is invalid", mapOption["parent"])
+		return errProxy.Errorf(common.CCErrCommParamsNeedSet, "option parent")
+	}
+
+	parentOptions, ok := mapOption["options"].(map[string]interface{})
+	if !ok || len(parentOptions) == 0 {
+		blog.Errorf("enum cascade option options %v is invalid", mapOption["options"])
+		return errProxy.Errorf(common.CCErrCommParamsNeedSet, "option options")
+	}
+
+	if len(parentOptions) > common.AttributeOptionArrayMaxLength {
+		blog.Errorf("option parent values count %d exceeds max length %d", len(parentOptions),
+			common.AttributeOptionArrayMaxLength)
+		return errProxy.Errorf(common.CCErrCommValExceedMaxFailed, "option options", common.AttributeOptionArrayMaxLength)
+	}
+
+	for parentValue, enumOption := range parentOptions {
+		if len(parentValue) == 0 || common.AttributeOptionValueMaxLength < utf8.RuneCountInString(parentValue) {
+			blog.Errorf("enum cascade option parent value %s is invalid", parentValue)
+			return errProxy.Errorf(common.CCErrCommParamsIsInvalid, "option options")
+		}
+
+		if err := ValidFieldTypeEnumOption(enumOption, errProxy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ValidFieldTypeIntOption TODO
 func ValidFieldTypeIntOption(option interface{}, errProxy errors.DefaultCCErrorIf) error {
 	if nil == option {
@@ -0,0 +1,120 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const (
+	// ListChangesDefaultLimit the default number of the events scanned by one changes listing.
+	ListChangesDefaultLimit = 1000
+	// ListChangesMaxLimit the max number of the events scanned by one changes listing.
+	ListChangesMaxLimit = 5000
+)
+
+// ListChangesCursorTypes returns the resources that support listing changes, the events of these resources are
+// identified by the instance id, so that they can be aggregated into the changed ids.
+func ListChangesCursorTypes() []CursorType {
+	return []CursorType{Host, Biz, Set, Module, ObjectBase, Process, MainlineInstance, InstAsst, BizSet}
+}
+
+// ListChangesOptions is the option to list the ids of the resources changed since the token, it is used by the batch
+// consumers to sync the changes periodically without holding a watch connection.
+// the consumer gets the initial token by listing with neither token nor start from, which returns the token of the
+// latest event without any changes, then lists with the returned token until has more is false in each sync.
+type ListChangesOptions struct {
+	Resource CursorType `json:"bk_resource"`
+	// Token the token returned by the previous listing, the changes after it are listed.
+	Token string `json:"bk_token"`
+	// StartFrom unix seconds time to list the changes from, can not be used with Token at the same time.
+	StartFrom int64 `json:"bk_start_from"`
+	// SubResource the sub resource to list the changes of, eg. object ID of the instance resource, list all if not set
+	SubResource string `json:"bk_sub_resource,omitempty"`
+	// Limit the max number of the events scanned, default is ListChangesDefaultLimit.
+	Limit uint64 `json:"limit"`
+}
+
+// Validate validate the list changes options, and set the default limit if it is not set.
+func (o *ListChangesOptions) Validate() error {
+	supported := false
+	for _, typ := range ListChangesCursorTypes() {
+		if typ == o.Resource {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("%s does not support listing changes", o.Resource)
+	}
+
+	if o.StartFrom != 0 && len(o.Token) != 0 {
+		return errors.New("bk_start_from and bk_token can not use at the same time")
+	}
+
+	if len(o.SubResource) > 0 {
+		switch o.Resource {
+		case ObjectBase, MainlineInstance, InstAsst:
+		default:
+			return fmt.Errorf("%s changes cannot have sub resource", o.Resource)
+		}
+	}
+
+	if o.Limit == 0 {
+		o.Limit = ListChangesDefaultLimit
+	}
+
+	if o.Limit > ListChangesMaxLimit {
+		return fmt.Errorf("limit exceeds max limit %d", ListChangesMaxLimit)
+	}
+
+	return nil
+}
+
+// ListChangesResult is the ids of the resources changed since the token, each id appears only once according to its
+// last event, the ids in UpsertedIDs are created or updated, and the ids in DeletedIDs are the tombstones.
+type ListChangesResult struct {
+	// Token the token to list the following changes, it is the same as the requested one if there's no new event.
+	Token string `json:"bk_token"`
+	// HasMore whether there are more changes after the token, the consumer should list again immediately if true.
+	HasMore     bool    `json:"has_more"`
+	UpsertedIDs []int64 `json:"upserted_ids"`
+	DeletedIDs  []int64 `json:"deleted_ids"`
+}
+
+// AggregateChanges aggregates the chain nodes in the order of the events into the ids of the changed resources by
+// the last event of each resource, the nodes without instance id are ignored, the returned ids are sorted.
+func AggregateChanges(nodes []*ChainNode) ([]int64, []int64) {
+	lastEvent := make(map[int64]EventType)
+	for _, node := range nodes {
+		if node.InstanceID <= 0 {
+			continue
+		}
+		lastEvent[node.InstanceID] = node.EventType
+	}
+
+	upserted, deleted := make([]int64, 0), make([]int64, 0)
+	for id, eventType := range lastEvent {
+		if eventType == Delete {
+			deleted = append(deleted, id)
+			continue
+		}
+		upserted = append(upserted, id)
+	}
+
+	sort.Slice(upserted, func(i, j int) bool { return upserted[i] < upserted[j] })
+	sort.Slice(deleted, func(i, j int) bool { return deleted[i] < deleted[j] })
+	return upserted, deleted
+}
@@ -0,0 +1,65 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package watch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAggregateChanges(t *testing.T) {
+	nodes := []*ChainNode{
+		{InstanceID: 3, EventType: Create},
+		{InstanceID: 1, EventType: Update},
+		{InstanceID: 2, EventType: Delete},
+		{InstanceID: 3, EventType: Delete},
+		{InstanceID: 2, EventType: Create},
+		{InstanceID: 0, EventType: Update},
+		{InstanceID: 4, EventType: Delete},
+	}
+
+	upserted, deleted := AggregateChanges(nodes)
+	if !reflect.DeepEqual(upserted, []int64{1, 2}) {
+		t.Errorf("aggregate changes got upserted ids %v, want [1 2]", upserted)
+	}
+	if !reflect.DeepEqual(deleted, []int64{3, 4}) {
+		t.Errorf("aggregate changes got deleted ids %v, want [3 4]", deleted)
+	}
+
+	upserted, deleted = AggregateChanges(nil)
+	if len(upserted) != 0 || len(deleted) != 0 || upserted == nil || deleted == nil {
+		t.Errorf("aggregate changes of no nodes got %v and %v, want empty ids", upserted, deleted)
+	}
+}
+
+func TestListChangesOptionsValidate(t *testing.T) {
+	opts := &ListChangesOptions{Resource: Host}
+	if err := opts.Validate(); err != nil {
+		t.Fatalf("validate list changes options failed, err: %v", err)
+	}
+	if opts.Limit != ListChangesDefaultLimit {
+		t.Errorf("validate list changes options got limit %d, want %d", opts.Limit, ListChangesDefaultLimit)
+	}
+
+	invalid := []*ListChangesOptions{
+		{Resource: ModuleHostRelation},
+		{Resource: Host, Token: "cursor", StartFrom: 1},
+		{Resource: Host, SubResource: "host"},
+		{Resource: ObjectBase, Limit: ListChangesMaxLimit + 1},
+	}
+	for _, opts := range invalid {
+		if err := opts.Validate(); err == nil {
+			t.Errorf("validate list changes options %+v should fail", opts)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/watch"
+)
+
+// NewAPIWatcher returns a watcher of the cmdb cluster by its api server at the address, e.g. http://127.0.0.1:8080,
+// the header is used to call the api server, it must contain the user and the supplier account. The timeout must
+// be longer than the time the watch waits for the events.
+func NewAPIWatcher(addr string, header http.Header, timeout time.Duration) Watcher {
+	header = header.Clone()
+	header.Set("Content-Type", "application/json")
+
+	return &apiWatcher{
+		addr:   strings.TrimSuffix(addr, "/"),
+		header: header,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type apiWatcher struct {
+	addr   string
+	header http.Header
+	client *http.Client
+}
+
+type watchResponse struct {
+	metadata.BaseResp `json:",inline"`
+	Data              *watch.WatchResp `json:"data"`
+}
+
+// Watch watches the events of the resource by the api server
+func (w *apiWatcher) Watch(ctx context.Context, opts *watch.WatchEventOptions) (*watch.WatchResp, error) {
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/v3/event/watch/resource/%s", w.addr, opts.Resource)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = w.header.Clone()
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("watch %s failed, status: %d, body: %s", opts.Resource, resp.StatusCode, data)
+	}
+
+	result := new(watchResponse)
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("decode watch %s response failed, err: %v", opts.Resource, err)
+	}
+	if !result.Result {
+		return nil, fmt.Errorf("watch %s failed, code: %d, message: %s", opts.Resource, result.Code, result.ErrMsg)
+	}
+	return result.Data, nil
+}
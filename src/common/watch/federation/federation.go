@@ -0,0 +1,275 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package federation merges the watch streams of the resources from multiple cmdb clusters, e.g. the regional
+// clusters of a multi-region deployment, into a global stream, so that a central consumer can watch all of them
+// without polling each cluster. Each event is labeled with its source, and the position of the global stream is a
+// cursor composed of the cursors of all the sources.
+package federation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/watch"
+)
+
+// defaultRetryInterval the default interval to watch a source again after its watch failed
+const defaultRetryInterval = 5 * time.Second
+
+var sourceNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-.]+$`)
+
+// Source is a cmdb cluster whose watch stream is merged into the global stream
+type Source struct {
+	// Name the unique name of the source, e.g. the region of the cluster, which labels the events of the source.
+	Name    string
+	Watcher Watcher
+}
+
+// Watcher watches the events of a cmdb cluster
+type Watcher interface {
+	Watch(ctx context.Context, opts *watch.WatchEventOptions) (*watch.WatchResp, error)
+}
+
+// Cursor is the position of the global stream, which is the cursors of the sources by their names, the source
+// without cursor is watched from the start time of the options.
+type Cursor map[string]string
+
+// Encode encodes the cursor to a string to be saved by the consumer
+func (c Cursor) Encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes the cursor encoded by Encode, an empty string is decoded to an empty cursor.
+func DecodeCursor(s string) (Cursor, error) {
+	cursor := make(Cursor)
+	if len(s) == 0 {
+		return cursor, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid federation cursor, err: %v", err)
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid federation cursor, err: %v", err)
+	}
+	return cursor, nil
+}
+
+// Options is the options to watch the global stream, they are used to watch every source.
+type Options struct {
+	Resource   watch.CursorType
+	EventTypes []watch.EventType
+	Fields     []string
+	Filter     watch.WatchEventFilter
+	// StartFrom the unix seconds to watch the sources without cursor from.
+	StartFrom int64
+	// Cursor the position of the global stream to watch from, which is returned with the events previously.
+	Cursor Cursor
+	// RetryInterval the interval to watch a source again after its watch failed, default is 5 seconds.
+	RetryInterval time.Duration
+}
+
+// Event is a watch event of a source
+type Event struct {
+	Source string                  `json:"bk_source"`
+	Event  *watch.WatchEventDetail `json:"bk_event"`
+}
+
+// Batch is the events of a source got by a watch, the events of a batch are in the order of the source's stream.
+type Batch struct {
+	Source string   `json:"bk_source"`
+	Events []*Event `json:"bk_events"`
+	// Cursor the encoded global cursor after the events, the consumer saves it after the events are processed and
+	// watches from it after restarted.
+	Cursor string `json:"bk_cursor"`
+}
+
+// Federation merges the watch streams of the sources into a global stream
+type Federation struct {
+	sources []Source
+	opts    Options
+}
+
+// New creates a federation of the sources
+func New(sources []Source, opts Options) (*Federation, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("federation sources are not set")
+	}
+
+	names := make(map[string]struct{}, len(sources))
+	for _, source := range sources {
+		if !sourceNameRegexp.MatchString(source.Name) {
+			return nil, fmt.Errorf("federation source name %s is invalid", source.Name)
+		}
+		if _, exists := names[source.Name]; exists {
+			return nil, fmt.Errorf("federation source name %s is duplicated", source.Name)
+		}
+		names[source.Name] = struct{}{}
+
+		if source.Watcher == nil {
+			return nil, fmt.Errorf("federation source %s watcher is not set", source.Name)
+		}
+	}
+
+	// the cursors of the sources not in the federation any more are dropped from the global cursor
+	cursor := make(Cursor, len(sources))
+	for name, sourceCursor := range opts.Cursor {
+		if _, exists := names[name]; exists {
+			cursor[name] = sourceCursor
+		}
+	}
+	opts.Cursor = cursor
+
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = defaultRetryInterval
+	}
+
+	return &Federation{sources: sources, opts: opts}, nil
+}
+
+// sourceBatch is the events and the new cursor of a source, the source waits for the done channel before watching
+// again, so that it does not advance if the handler fails.
+type sourceBatch struct {
+	source string
+	cursor string
+	events []*watch.WatchEventDetail
+	done   chan struct{}
+}
+
+// Watch watches the sources concurrently and calls the handler with the events of the sources one batch at a time,
+// the batches of a source are in the order of its stream, while the batches of different sources are interleaved.
+// A source that fails to watch is retried after the retry interval without blocking the other sources. Watch runs
+// until the context is done or the handler returns an error, which is returned.
+func (f *Federation) Watch(ctx context.Context, handler func(batch *Batch) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batches := make(chan *sourceBatch)
+	for _, source := range f.sources {
+		go f.watchSource(ctx, source, f.opts.Cursor[source.Name], batches)
+	}
+
+	cursor := make(Cursor, len(f.opts.Cursor))
+	for name, sourceCursor := range f.opts.Cursor {
+		cursor[name] = sourceCursor
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case batch := <-batches:
+			cursor[batch.source] = batch.cursor
+			if len(batch.events) > 0 {
+				if err := f.handle(cursor, batch, handler); err != nil {
+					return err
+				}
+			}
+			close(batch.done)
+		}
+	}
+}
+
+func (f *Federation) handle(cursor Cursor, batch *sourceBatch, handler func(batch *Batch) error) error {
+	encoded, err := cursor.Encode()
+	if err != nil {
+		return err
+	}
+
+	events := make([]*Event, len(batch.events))
+	for idx, event := range batch.events {
+		events[idx] = &Event{Source: batch.source, Event: event}
+	}
+	return handler(&Batch{Source: batch.source, Events: events, Cursor: encoded})
+}
+
+// watchSource watches the source from its cursor continuously, and sends the events and the new cursor to the
+// batches channel whenever the cursor changes.
+func (f *Federation) watchSource(ctx context.Context, source Source, cursor string, batches chan<- *sourceBatch) {
+	for {
+		opts := &watch.WatchEventOptions{
+			EventTypes: f.opts.EventTypes,
+			Fields:     f.opts.Fields,
+			Resource:   f.opts.Resource,
+			Filter:     f.opts.Filter,
+		}
+		if len(cursor) != 0 {
+			opts.Cursor = cursor
+		} else {
+			opts.StartFrom = f.opts.StartFrom
+		}
+
+		resp, err := source.Watcher.Watch(ctx, opts)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			blog.Errorf("watch federation source %s failed, cursor: %s, err: %v", source.Name, cursor, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(f.opts.RetryInterval):
+			}
+			continue
+		}
+
+		// the cursor is not changed if there's no new event, watch again since the watch has waited for a while
+		newCursor := lastCursor(resp)
+		if len(newCursor) == 0 || newCursor == cursor {
+			continue
+		}
+
+		batch := &sourceBatch{source: source.Name, cursor: newCursor, done: make(chan struct{})}
+		if resp.Watched {
+			batch.events = resp.Events
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case batches <- batch:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-batch.done:
+		}
+		cursor = newCursor
+	}
+}
+
+// lastCursor returns the cursor of the last event in the watch response, which is the cursor to watch the following
+// events from, the NoEventCursor means there's no event yet and is ignored.
+func lastCursor(resp *watch.WatchResp) string {
+	if resp == nil || len(resp.Events) == 0 {
+		return ""
+	}
+
+	cursor := resp.Events[len(resp.Events)-1].Cursor
+	if cursor == watch.NoEventCursor {
+		return ""
+	}
+	return cursor
+}
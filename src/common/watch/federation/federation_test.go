@@ -0,0 +1,193 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package federation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"configcenter/src/common/watch"
+)
+
+// fakeWatcher returns the responses in order, and blocks until the context is done after they are all returned.
+type fakeWatcher struct {
+	lock      sync.Mutex
+	responses []fakeResponse
+	options   []watch.WatchEventOptions
+}
+
+type fakeResponse struct {
+	resp *watch.WatchResp
+	err  error
+}
+
+func (w *fakeWatcher) Watch(ctx context.Context, opts *watch.WatchEventOptions) (*watch.WatchResp, error) {
+	w.lock.Lock()
+	w.options = append(w.options, *opts)
+	if len(w.responses) == 0 {
+		w.lock.Unlock()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	response := w.responses[0]
+	w.responses = w.responses[1:]
+	w.lock.Unlock()
+	return response.resp, response.err
+}
+
+func (w *fakeWatcher) watchedOptions() []watch.WatchEventOptions {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return append([]watch.WatchEventOptions(nil), w.options...)
+}
+
+func watched(cursors ...string) fakeResponse {
+	resp := &watch.WatchResp{Watched: true}
+	for _, cursor := range cursors {
+		resp.Events = append(resp.Events, &watch.WatchEventDetail{Cursor: cursor, Resource: watch.Host,
+			EventType: watch.Update, Detail: watch.JsonString(`{"bk_host_id":1}`)})
+	}
+	return fakeResponse{resp: resp}
+}
+
+func notWatched(cursor string) fakeResponse {
+	return fakeResponse{resp: &watch.WatchResp{Events: []*watch.WatchEventDetail{{Cursor: cursor,
+		Resource: watch.Host}}}}
+}
+
+func TestWatch(t *testing.T) {
+	east := &fakeWatcher{responses: []fakeResponse{watched("e1", "e2"), notWatched("e3"), watched("e4")}}
+	west := &fakeWatcher{responses: []fakeResponse{
+		{err: errors.New("connection refused")},
+		notWatched(watch.NoEventCursor),
+		watched("w1"),
+	}}
+
+	sources := []Source{{Name: "east", Watcher: east}, {Name: "west", Watcher: west}}
+	fed, err := New(sources, Options{Resource: watch.Host, StartFrom: 100, RetryInterval: time.Millisecond,
+		Cursor: Cursor{"east": "e0", "removed": "r1"}})
+	if err != nil {
+		t.Fatalf("new federation failed, err: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := map[string][]string{}
+	var lastCursor string
+	err = fed.Watch(ctx, func(batch *Batch) error {
+		for _, event := range batch.Events {
+			if event.Source != batch.Source {
+				t.Errorf("event source %s is not the batch source %s", event.Source, batch.Source)
+			}
+			received[event.Source] = append(received[event.Source], event.Event.Cursor)
+		}
+		lastCursor = batch.Cursor
+		if len(received["east"]) == 3 && len(received["west"]) == 1 {
+			cancel()
+		}
+		return nil
+	})
+	if err != context.Canceled {
+		t.Fatalf("watch should be stopped by the context, err: %v", err)
+	}
+
+	if got := received["east"]; len(got) != 3 || got[0] != "e1" || got[1] != "e2" || got[2] != "e4" {
+		t.Errorf("unexpected east events: %v", got)
+	}
+	if got := received["west"]; len(got) != 1 || got[0] != "w1" {
+		t.Errorf("unexpected west events: %v", got)
+	}
+
+	cursor, err := DecodeCursor(lastCursor)
+	if err != nil {
+		t.Fatalf("decode cursor failed, err: %v", err)
+	}
+	if len(cursor) != 2 || cursor["east"] != "e4" || cursor["west"] != "w1" {
+		t.Errorf("unexpected global cursor: %v", cursor)
+	}
+
+	eastOpts := east.watchedOptions()
+	if eastOpts[0].Cursor != "e0" || eastOpts[1].Cursor != "e2" || eastOpts[2].Cursor != "e3" {
+		t.Errorf("east is not watched from its cursors: %+v", eastOpts)
+	}
+	westOpts := west.watchedOptions()
+	if westOpts[0].StartFrom != 100 || westOpts[0].Cursor != "" || westOpts[2].StartFrom != 100 {
+		t.Errorf("west without cursor is not watched from the start time: %+v", westOpts)
+	}
+}
+
+func TestWatchHandlerError(t *testing.T) {
+	source := &fakeWatcher{responses: []fakeResponse{watched("c1"), watched("c2")}}
+	fed, err := New([]Source{{Name: "a", Watcher: source}}, Options{Resource: watch.Host})
+	if err != nil {
+		t.Fatalf("new federation failed, err: %v", err)
+	}
+
+	handleErr := errors.New("consumer is down")
+	err = fed.Watch(context.Background(), func(batch *Batch) error {
+		return handleErr
+	})
+	if err != handleErr {
+		t.Fatalf("watch should return the handler error, err: %v", err)
+	}
+
+	// the source does not advance after the handler failed
+	time.Sleep(10 * time.Millisecond)
+	if opts := source.watchedOptions(); len(opts) != 1 {
+		t.Errorf("source should not be watched again after the handler failed: %+v", opts)
+	}
+}
+
+func TestNew(t *testing.T) {
+	watcher := new(fakeWatcher)
+	invalid := [][]Source{
+		nil,
+		{{Name: "", Watcher: watcher}},
+		{{Name: "a b", Watcher: watcher}},
+		{{Name: "a", Watcher: watcher}, {Name: "a", Watcher: watcher}},
+		{{Name: "a"}},
+	}
+	for _, sources := range invalid {
+		if _, err := New(sources, Options{Resource: watch.Host}); err == nil {
+			t.Errorf("invalid sources %+v should fail", sources)
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	cursor := Cursor{"east": "e1", "west": "w1"}
+	encoded, err := cursor.Encode()
+	if err != nil {
+		t.Fatalf("encode cursor failed, err: %v", err)
+	}
+
+	decoded, err := DecodeCursor(encoded)
+	if err != nil {
+		t.Fatalf("decode cursor failed, err: %v", err)
+	}
+	if len(decoded) != 2 || decoded["east"] != "e1" || decoded["west"] != "w1" {
+		t.Errorf("unexpected decoded cursor: %v", decoded)
+	}
+
+	if empty, err := DecodeCursor(""); err != nil || len(empty) != 0 {
+		t.Errorf("empty string should be decoded to empty cursor, cursor: %v, err: %v", empty, err)
+	}
+
+	if _, err := DecodeCursor("not a cursor"); err == nil {
+		t.Errorf("decode invalid cursor should fail")
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+const (
+	// NamedCursorMaxNameLength the max length of the named cursor's name.
+	NamedCursorMaxNameLength = 64
+	// NamedCursorDefaultLeaseSeconds the default lease of the named cursor's owner.
+	NamedCursorDefaultLeaseSeconds = 60
+	// NamedCursorMaxLeaseSeconds the max lease of the named cursor's owner.
+	NamedCursorMaxLeaseSeconds = 3600
+)
+
+var namedCursorNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-.]+$`)
+
+// ValidateNamedCursorName validate the name of the named cursor.
+func ValidateNamedCursorName(name string) error {
+	if len(name) == 0 {
+		return errors.New("named cursor name is not set")
+	}
+
+	if len(name) > NamedCursorMaxNameLength {
+		return fmt.Errorf("named cursor name exceeds max length %d", NamedCursorMaxNameLength)
+	}
+
+	if !namedCursorNameRegexp.MatchString(name) {
+		return fmt.Errorf("named cursor name %s is invalid", name)
+	}
+
+	return nil
+}
+
+// NamedCursor is the cursor stored by the event server on behalf of the consumer, the consumer registers it with a
+// name, watches the events from it and advances it after the events are processed. only one consumer can own the
+// cursor at the same time, the owner keeps its lease by watching or advancing the cursor.
+type NamedCursor struct {
+	Name     string     `json:"bk_name"`
+	Resource CursorType `json:"bk_resource"`
+	// Cursor the cursor that the next watch starts from, the consumer watches from StartFrom if it is empty.
+	Cursor    string `json:"bk_cursor"`
+	StartFrom int64  `json:"bk_start_from"`
+	// Owner the consumer who owns the lease of the cursor, empty if the lease is expired.
+	Owner string `json:"bk_owner"`
+	// LeaseSeconds the remaining seconds of the owner's lease.
+	LeaseSeconds int64 `json:"bk_lease_seconds"`
+	CreateTime   int64 `json:"create_time"`
+	UpdateTime   int64 `json:"update_time"`
+}
+
+// NamedCursorLease is the owner and the lease of the named cursor's operation.
+type NamedCursorLease struct {
+	// Owner the unique identity of the consumer, e.g. the consumer's host and pid.
+	Owner string `json:"bk_owner"`
+	// LeaseSeconds the lease of the owner, default is NamedCursorDefaultLeaseSeconds.
+	LeaseSeconds int64 `json:"bk_lease_seconds"`
+}
+
+// Validate validate the named cursor lease, and set the default lease if it is not set.
+func (l *NamedCursorLease) Validate() error {
+	if len(l.Owner) == 0 {
+		return errors.New("bk_owner is not set")
+	}
+
+	if l.LeaseSeconds == 0 {
+		l.LeaseSeconds = NamedCursorDefaultLeaseSeconds
+	}
+
+	if l.LeaseSeconds < 0 || l.LeaseSeconds > NamedCursorMaxLeaseSeconds {
+		return fmt.Errorf("bk_lease_seconds should be in (0, %d]", NamedCursorMaxLeaseSeconds)
+	}
+
+	return nil
+}
+
+// WatchNamedCursorOptions is the option to watch the events from the named cursor.
+type WatchNamedCursorOptions struct {
+	NamedCursorLease `json:",inline"`
+	// event types you want to care, empty means all.
+	EventTypes []EventType `json:"bk_event_types"`
+	// the fields you only care, if nil, means all.
+	Fields []string         `json:"bk_fields"`
+	Filter WatchEventFilter `json:"bk_filter"`
+	// SchemaVersion the schema version of the events you want, use the current version if not set
+	SchemaVersion SchemaVersion `json:"bk_schema_version,omitempty"`
+}
+
+// AdvanceNamedCursorOption is the option to advance the named cursor to the cursor of the last processed event.
+type AdvanceNamedCursorOption struct {
+	NamedCursorLease `json:",inline"`
+	Cursor           string `json:"bk_cursor"`
+}
+
+// Validate validate the advance named cursor option
+func (o *AdvanceNamedCursorOption) Validate() error {
+	if err := o.NamedCursorLease.Validate(); err != nil {
+		return err
+	}
+
+	if len(o.Cursor) == 0 {
+		return errors.New("bk_cursor is not set")
+	}
+
+	return nil
+}
+
+// SeekNamedCursorOption is the option to seek the named cursor to the cursor or the unix seconds, the cursor is
+// reset to the current time if both of them are not set.
+type SeekNamedCursorOption struct {
+	NamedCursorLease `json:",inline"`
+	Cursor           string `json:"bk_cursor"`
+	StartFrom        int64  `json:"bk_start_from"`
+}
+
+// Validate validate the seek named cursor option
+func (o *SeekNamedCursorOption) Validate() error {
+	if err := o.NamedCursorLease.Validate(); err != nil {
+		return err
+	}
+
+	if o.StartFrom != 0 && len(o.Cursor) != 0 {
+		return errors.New("bk_start_from and bk_cursor can not use at the same time")
+	}
+
+	if o.StartFrom < 0 {
+		return errors.New("bk_start_from is invalid")
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package watch
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SchemaVersion is the schema version of the watch event envelope and detail payloads, the subscribers can request
+// the version they are compatible with, so that the payload changes do not break them when cmdb is upgraded.
+type SchemaVersion string
+
+const (
+	// SchemaV1 is the schema before the versioning is introduced, whose event envelope has no changed fields.
+	SchemaV1 SchemaVersion = "v1"
+	// SchemaV2 is the schema whose event envelope has the changed fields of the update events.
+	SchemaV2 SchemaVersion = "v2"
+	// CurrentSchemaVersion is the schema version of the events generated by the watch, it is used if the subscriber
+	// does not request a version.
+	CurrentSchemaVersion = SchemaV2
+)
+
+// schemaVersions is all the supported schema versions from the newest to the oldest, each version has a converter
+// that converts the event of the previous version in the list to this version.
+var schemaVersions = []SchemaVersion{SchemaV2, SchemaV1}
+
+// Validate validates the schema version, empty means the current version.
+func (v SchemaVersion) Validate() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	for _, version := range schemaVersions {
+		if v == version {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported schema version %s, supported versions: %v", v, schemaVersions)
+}
+
+// DetailConverter converts the event detail of a resource from the newer schema version to an older one.
+type DetailConverter func(detail JsonString) (JsonString, error)
+
+var (
+	detailConverterLock sync.RWMutex
+	// detailConverters is the detail converters of the resources that convert the details to the key version.
+	detailConverters = make(map[SchemaVersion]map[CursorType]DetailConverter)
+)
+
+// RegisterDetailConverter registers the converter of the resource's event detail from the version next to the
+// target version to the target version, the detail of the resource that has no converter is not changed.
+func RegisterDetailConverter(target SchemaVersion, resource CursorType, converter DetailConverter) {
+	detailConverterLock.Lock()
+	defer detailConverterLock.Unlock()
+
+	if detailConverters[target] == nil {
+		detailConverters[target] = make(map[CursorType]DetailConverter)
+	}
+	detailConverters[target][resource] = converter
+}
+
+func getDetailConverter(target SchemaVersion, resource CursorType) DetailConverter {
+	detailConverterLock.RLock()
+	defer detailConverterLock.RUnlock()
+	return detailConverters[target][resource]
+}
+
+// envelopeConverters is the converters of the event envelope to the key version from the version next to it.
+var envelopeConverters = map[SchemaVersion]func(event *WatchEventDetail){
+	SchemaV1: func(event *WatchEventDetail) {
+		event.ChangedFields = nil
+	},
+}
+
+// ConvertSchema converts the watch response of the current schema version to the target version step by step, the
+// events are converted in place.
+func (w *WatchResp) ConvertSchema(target SchemaVersion) error {
+	if len(target) == 0 {
+		target = CurrentSchemaVersion
+	}
+
+	if err := target.Validate(); err != nil {
+		return err
+	}
+
+	w.SchemaVersion = CurrentSchemaVersion
+	if target == CurrentSchemaVersion {
+		return nil
+	}
+
+	// the versions older than the current version are converted to one by one until the target version.
+	started := false
+	for _, version := range schemaVersions {
+		if !started {
+			started = version == CurrentSchemaVersion
+			continue
+		}
+
+		for _, event := range w.Events {
+			if err := event.convertSchema(version); err != nil {
+				return err
+			}
+		}
+
+		if version == target {
+			break
+		}
+	}
+
+	w.SchemaVersion = target
+	if target == SchemaV1 {
+		// the v1 envelope has no schema version.
+		w.SchemaVersion = ""
+	}
+	return nil
+}
+
+// convertSchema converts the event from the version next to the target version to the target version.
+func (w *WatchEventDetail) convertSchema(target SchemaVersion) error {
+	if convert, exists := envelopeConverters[target]; exists {
+		convert(w)
+	}
+
+	detail, ok := w.Detail.(JsonString)
+	if !ok || len(detail) == 0 {
+		return nil
+	}
+
+	convert := getDetailConverter(target, w.Resource)
+	if convert == nil {
+		return nil
+	}
+
+	converted, err := convert(detail)
+	if err != nil {
+		return fmt.Errorf("convert %s event %s detail to schema %s failed, err: %v", w.Resource, w.Cursor, target,
+			err)
+	}
+	w.Detail = converted
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package watch
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newSchemaTestResp() *WatchResp {
+	return &WatchResp{
+		Watched: true,
+		Events: []*WatchEventDetail{{
+			Cursor:        "cursor",
+			Resource:      Host,
+			EventType:     Update,
+			ChangedFields: []string{"bk_host_name"},
+			Detail:        JsonString(`{"bk_host_id":1,"bk_host_name":"a"}`),
+		}},
+	}
+}
+
+func TestConvertSchema(t *testing.T) {
+	resp := newSchemaTestResp()
+	if err := resp.ConvertSchema(""); err != nil {
+		t.Fatalf("convert to current schema failed, err: %v", err)
+	}
+	if resp.SchemaVersion != CurrentSchemaVersion || len(resp.Events[0].ChangedFields) != 1 {
+		t.Errorf("current schema should not be converted, got %+v", resp)
+	}
+
+	resp = newSchemaTestResp()
+	if err := resp.ConvertSchema(SchemaV1); err != nil {
+		t.Fatalf("convert to v1 schema failed, err: %v", err)
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("marshal v1 response failed, err: %v", err)
+	}
+	if strings.Contains(string(data), "bk_changed_fields") || strings.Contains(string(data), "bk_schema_version") {
+		t.Errorf("v1 envelope should not have changed fields and schema version, got %s", data)
+	}
+
+	if err := newSchemaTestResp().ConvertSchema("v0"); err == nil {
+		t.Errorf("unsupported schema version should be rejected")
+	}
+}
+
+func TestDetailConverter(t *testing.T) {
+	RegisterDetailConverter(SchemaV1, Host, func(detail JsonString) (JsonString, error) {
+		return JsonString(strings.Replace(string(detail), "bk_host_name", "host_name", 1)), nil
+	})
+	defer func() {
+		detailConverterLock.Lock()
+		delete(detailConverters[SchemaV1], Host)
+		detailConverterLock.Unlock()
+	}()
+
+	resp := newSchemaTestResp()
+	resp.Events = append(resp.Events, &WatchEventDetail{Cursor: NoEventCursor, Resource: Host})
+	if err := resp.ConvertSchema(SchemaV1); err != nil {
+		t.Fatalf("convert to v1 schema failed, err: %v", err)
+	}
+
+	if detail := resp.Events[0].Detail.(JsonString); detail != `{"bk_host_id":1,"host_name":"a"}` {
+		t.Errorf("detail is not converted, got %s", detail)
+	}
+	if resp.Events[1].Detail != nil {
+		t.Errorf("event without detail should be kept, got %v", resp.Events[1].Detail)
+	}
+
+	resp = newSchemaTestResp()
+	if err := resp.ConvertSchema(SchemaV2); err != nil {
+		t.Fatalf("convert to v2 schema failed, err: %v", err)
+	}
+	if detail := resp.Events[0].Detail.(JsonString); !strings.Contains(string(detail), "bk_host_name") {
+		t.Errorf("detail of current schema should not be converted, got %s", detail)
+	}
+}
+
+func TestValidateSchemaVersion(t *testing.T) {
+	opts := &WatchEventOptions{Resource: Host, Fields: []string{"bk_host_id"}, SchemaVersion: "v3"}
+	if err := opts.Validate(); err == nil {
+		t.Errorf("unsupported schema version should be rejected")
+	}
+
+	opts.SchemaVersion = SchemaV1
+	if err := opts.Validate(); err != nil {
+		t.Errorf("v1 schema version should be valid, err: %v", err)
+	}
+}
@@ -14,6 +14,7 @@ package watch
 
 import (
 	"errors"
+	"strings"
 
 	"configcenter/src/storage/stream/types"
 )
@@ -75,6 +76,37 @@ type ChainNode struct {
 	InstanceID int64 `json:"inst_id,omitempty" bson:"inst_id,omitempty"`
 	// SubResource the sub resource if the watched resource, eg. the object ID of the instance resource
 	SubResource []string `json:"bk_sub_resource,omitempty" bson:"bk_sub_resource,omitempty"`
+	// ChangedFields the top level fields that are updated or removed by the update event
+	ChangedFields []string `json:"bk_changed_fields,omitempty" bson:"bk_changed_fields,omitempty"`
+}
+
+// GetChangedFields get the top level fields that are updated or removed from the event's change description
+func GetChangedFields(desc *types.ChangeDescription) []string {
+	if desc == nil {
+		return nil
+	}
+
+	fields := make([]string, 0, len(desc.UpdatedFields)+len(desc.RemovedFields))
+	fieldMap := make(map[string]struct{}, cap(fields))
+	addField := func(field string) {
+		if idx := strings.Index(field, "."); idx > 0 {
+			field = field[:idx]
+		}
+		if _, exists := fieldMap[field]; exists {
+			return
+		}
+		fieldMap[field] = struct{}{}
+		fields = append(fields, field)
+	}
+
+	for field := range desc.UpdatedFields {
+		addField(field)
+	}
+	for _, field := range desc.RemovedFields {
+		addField(field)
+	}
+
+	return fields
 }
 
 // LastChainNodeData TODO
@@ -0,0 +1,50 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package watch
+
+import (
+	"sort"
+	"testing"
+
+	"configcenter/src/storage/stream/types"
+)
+
+func TestGetChangedFields(t *testing.T) {
+	if fields := GetChangedFields(nil); len(fields) != 0 {
+		t.Errorf("get changed fields of nil description got %v, want empty", fields)
+	}
+
+	desc := &types.ChangeDescription{
+		UpdatedFields: map[string]interface{}{
+			"bk_host_name":      "test",
+			"bk_cpu":            4,
+			"bk_host_innerip.0": "127.0.0.1",
+			"bk_host_innerip.1": "127.0.0.2",
+			"last_time":         "2021-01-01",
+		},
+		RemovedFields: []string{"bk_comment", "bk_cpu"},
+	}
+
+	fields := GetChangedFields(desc)
+	sort.Strings(fields)
+	want := []string{"bk_comment", "bk_cpu", "bk_host_innerip", "bk_host_name", "last_time"}
+	if len(fields) != len(want) {
+		t.Fatalf("get changed fields got %v, want %v", fields, want)
+	}
+	for idx := range want {
+		if fields[idx] != want[idx] {
+			t.Errorf("get changed fields got %v, want %v", fields, want)
+			return
+		}
+	}
+}
@@ -33,12 +33,17 @@ type WatchEventOptions struct {
 	// the resource kind you want to watch
 	Resource CursorType       `json:"bk_resource"`
 	Filter   WatchEventFilter `json:"bk_filter"`
+	// SchemaVersion the schema version of the events you want, use the current version if not set
+	SchemaVersion SchemaVersion `json:"bk_schema_version,omitempty"`
 }
 
 // WatchEventFilter TODO
 type WatchEventFilter struct {
 	// SubResource the sub resource you want to watch, eg. object ID of the instance resource, watch all if not set
 	SubResource string `json:"bk_sub_resource,omitempty"`
+	// ChangedFields the fields you care about in the update events, update events that do not change any of these
+	// fields are suppressed, create and delete events are not filtered, watch all update events if not set
+	ChangedFields []string `json:"bk_changed_fields,omitempty"`
 }
 
 // Validate TODO
@@ -71,6 +76,17 @@ func (w *WatchEventOptions) Validate() error {
 		}
 	}
 
+	if len(w.Filter.ChangedFields) > 0 {
+		switch w.Resource {
+		case HostIdentifier, BizSetRelation:
+			return fmt.Errorf("%s event cannot have changed fields", w.Resource)
+		}
+	}
+
+	if err := w.SchemaVersion.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -79,6 +95,8 @@ type WatchResp struct {
 	// watched events or not
 	Watched bool                `json:"bk_watched"`
 	Events  []*WatchEventDetail `json:"bk_events"`
+	// SchemaVersion the schema version of the events, which is the requested version
+	SchemaVersion SchemaVersion `json:"bk_schema_version,omitempty"`
 }
 
 // WatchEventDetail TODO
@@ -86,15 +104,18 @@ type WatchEventDetail struct {
 	Cursor    string     `json:"bk_cursor"`
 	Resource  CursorType `json:"bk_resource"`
 	EventType EventType  `json:"bk_event_type"`
+	// ChangedFields the fields changed by the update event, empty if it's unknown or not an update event
+	ChangedFields []string `json:"bk_changed_fields,omitempty"`
 	// Default instance is JsonString type
 	Detail DetailInterface `json:"bk_detail"`
 }
 
 type jsonWatchEventDetail struct {
-	Cursor    string          `json:"bk_cursor"`
-	Resource  CursorType      `json:"bk_resource"`
-	EventType EventType       `json:"bk_event_type"`
-	Detail    json.RawMessage `json:"bk_detail"`
+	Cursor        string          `json:"bk_cursor"`
+	Resource      CursorType      `json:"bk_resource"`
+	EventType     EventType       `json:"bk_event_type"`
+	ChangedFields []string        `json:"bk_changed_fields,omitempty"`
+	Detail        json.RawMessage `json:"bk_detail"`
 }
 
 // UnmarshalJSON TODO
@@ -107,6 +128,7 @@ func (w *WatchEventDetail) UnmarshalJSON(data []byte) error {
 	w.Cursor = watchEventDetail.Cursor
 	w.EventType = watchEventDetail.EventType
 	w.Resource = watchEventDetail.Resource
+	w.ChangedFields = watchEventDetail.ChangedFields
 
 	if watchEventDetail.Detail == nil {
 		return nil
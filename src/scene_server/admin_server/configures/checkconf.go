@@ -180,6 +180,16 @@ func (cc *ConfCenter) isDatacollectionConfigOK(v *viper.Viper, fileName string)
 			return err
 		}
 	}
+	if v.IsSet("datacollection.hostsnap.degradedRateLimiter.qps") {
+		if err := cc.isConfigNotIntVal("datacollection.hostsnap.degradedRateLimiter.qps", fileName, v); err != nil {
+			return err
+		}
+	}
+	if v.IsSet("datacollection.hostsnap.degradedRateLimiter.burst") {
+		if err := cc.isConfigNotIntVal("datacollection.hostsnap.degradedRateLimiter.burst", fileName, v); err != nil {
+			return err
+		}
+	}
 	if v.IsSet("datacollection.hostsnap.timeWindow.atTime") {
 		if err := cc.isTimeFormat("datacollection.hostsnap.timeWindow.atTime", fileName, v); err != nil {
 			return err
@@ -27,6 +27,7 @@ import (
 	"configcenter/src/scene_server/admin_server/app"
 	"configcenter/src/scene_server/admin_server/app/options"
 	"configcenter/src/scene_server/admin_server/command"
+	_ "configcenter/src/scene_server/admin_server/upgrader/all"
 )
 
 func main() {
@@ -0,0 +1,103 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"configcenter/src/common"
+	cc "configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/faultinject"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/types"
+	"configcenter/src/common/util"
+	"configcenter/src/common/zkclient"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+// SearchFaultInjectRules search the fault injection rules
+func (s *Service) SearchFaultInjectRules(req *restful.Request, resp *restful.Response) {
+	rHeader := req.Request.Header
+	rid := util.GetHTTPCCRequestID(rHeader)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(rHeader))
+
+	rules := faultinject.Rules{Rules: make([]faultinject.Rule, 0)}
+	data, err := s.Engine.ServiceManageClient().Client().Get(types.CC_SERVFAULTINJECT_PATH)
+	if err != nil && err != zkclient.ErrNoNode {
+		blog.Errorf("get fault injection rules failed, err: %v, rid: %s", err, rid)
+		s.writeSystemError(req, resp, defErr.CCError(common.CCErrCommDBSelectFailed), rid)
+		return
+	}
+
+	if err == nil && len(data) != 0 {
+		if err := json.Unmarshal([]byte(data), &rules); err != nil {
+			blog.Errorf("unmarshal fault injection rules %s failed, err: %v, rid: %s", data, err, rid)
+			s.writeSystemError(req, resp, defErr.CCError(common.CCErrCommJSONUnmarshalFailed), rid)
+			return
+		}
+	}
+
+	if err := resp.WriteEntity(metadata.NewSuccessResp(rules)); err != nil {
+		blog.Errorf("response request url: %s failed, err: %v, rid: %s", req.Request.RequestURI, err, rid)
+	}
+}
+
+// UpdateFaultInjectRules replace all the fault injection rules, which take effect in a few seconds in the services
+// with faultInject.enabled configured. the rules are removed by setting rules to empty.
+func (s *Service) UpdateFaultInjectRules(req *restful.Request, resp *restful.Response) {
+	rHeader := req.Request.Header
+	rid := util.GetHTTPCCRequestID(rHeader)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(rHeader))
+
+	if enabled, _ := cc.Bool("faultInject.enabled"); !enabled {
+		blog.Errorf("fault injection is not enabled, rid: %s", rid)
+		s.writeSystemError(req, resp, defErr.CCError(common.CCErrCommFaultInjectDisabled), rid)
+		return
+	}
+
+	rules := new(faultinject.Rules)
+	if err := json.NewDecoder(req.Request.Body).Decode(rules); err != nil {
+		blog.Errorf("decode fault injection rules failed, err: %v, rid: %s", err, rid)
+		s.writeSystemError(req, resp, defErr.CCError(common.CCErrCommJSONUnmarshalFailed), rid)
+		return
+	}
+
+	if rawErr := rules.Validate(); rawErr.ErrCode != 0 {
+		blog.Errorf("validate fault injection rules %+v failed, err: %v, rid: %s", rules, rawErr, rid)
+		s.writeSystemError(req, resp, rawErr.ToCCError(defErr), rid)
+		return
+	}
+
+	rules.Operator = util.GetUser(rHeader)
+	rules.UpdateTime = time.Now()
+	data, err := json.Marshal(rules)
+	if err != nil {
+		blog.Errorf("marshal fault injection rules %+v failed, err: %v, rid: %s", rules, err, rid)
+		s.writeSystemError(req, resp, defErr.CCError(common.CCErrCommJSONMarshalFailed), rid)
+		return
+	}
+
+	if err := s.Engine.ServiceManageClient().Client().Update(types.CC_SERVFAULTINJECT_PATH, string(data)); err != nil {
+		blog.Errorf("set fault injection rules %s failed, err: %v, rid: %s", data, err, rid)
+		s.writeSystemError(req, resp, defErr.CCError(common.CCErrCommDBUpdateFailed), rid)
+		return
+	}
+
+	blog.Warnf("fault injection rules are set to %s by %s, rid: %s", data, rules.Operator, rid)
+	if err := resp.WriteEntity(metadata.NewSuccessResp(rules)); err != nil {
+		blog.Errorf("response request url: %s failed, err: %v, rid: %s", req.Request.RequestURI, err, rid)
+	}
+}
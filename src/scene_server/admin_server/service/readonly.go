@@ -0,0 +1,102 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/types"
+	"configcenter/src/common/util"
+	"configcenter/src/common/zkclient"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+// SearchReadOnlyMode search the read-only maintenance mode
+func (s *Service) SearchReadOnlyMode(req *restful.Request, resp *restful.Response) {
+	rHeader := req.Request.Header
+	rid := util.GetHTTPCCRequestID(rHeader)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(rHeader))
+
+	mode := metadata.ReadOnlyMode{BizIDs: make([]int64, 0)}
+	data, err := s.Engine.ServiceManageClient().Client().Get(types.CC_SERVREADONLY_PATH)
+	if err != nil && err != zkclient.ErrNoNode {
+		blog.Errorf("get read-only mode failed, err: %v, rid: %s", err, rid)
+		s.writeSystemError(req, resp, defErr.CCError(common.CCErrCommDBSelectFailed), rid)
+		return
+	}
+
+	if err == nil && len(data) != 0 {
+		if err := json.Unmarshal([]byte(data), &mode); err != nil {
+			blog.Errorf("unmarshal read-only mode %s failed, err: %v, rid: %s", data, err, rid)
+			s.writeSystemError(req, resp, defErr.CCError(common.CCErrCommJSONUnmarshalFailed), rid)
+			return
+		}
+	}
+
+	if err := resp.WriteEntity(metadata.NewSuccessResp(mode)); err != nil {
+		blog.Errorf("response request url: %s failed, err: %v, rid: %s", req.Request.RequestURI, err, rid)
+	}
+}
+
+// UpdateReadOnlyMode set the read-only maintenance mode, which takes effect in all the services in a few seconds.
+// the mode is disabled by setting enabled to false and bk_biz_ids to empty.
+func (s *Service) UpdateReadOnlyMode(req *restful.Request, resp *restful.Response) {
+	rHeader := req.Request.Header
+	rid := util.GetHTTPCCRequestID(rHeader)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(rHeader))
+
+	mode := new(metadata.ReadOnlyMode)
+	if err := json.NewDecoder(req.Request.Body).Decode(mode); err != nil {
+		blog.Errorf("decode read-only mode failed, err: %v, rid: %s", err, rid)
+		s.writeSystemError(req, resp, defErr.CCError(common.CCErrCommJSONUnmarshalFailed), rid)
+		return
+	}
+
+	if rawErr := mode.Validate(); rawErr.ErrCode != 0 {
+		blog.Errorf("validate read-only mode %+v failed, err: %v, rid: %s", mode, rawErr, rid)
+		s.writeSystemError(req, resp, rawErr.ToCCError(defErr), rid)
+		return
+	}
+
+	mode.Operator = util.GetUser(rHeader)
+	mode.UpdateTime = time.Now()
+	data, err := json.Marshal(mode)
+	if err != nil {
+		blog.Errorf("marshal read-only mode %+v failed, err: %v, rid: %s", mode, err, rid)
+		s.writeSystemError(req, resp, defErr.CCError(common.CCErrCommJSONMarshalFailed), rid)
+		return
+	}
+
+	if err := s.Engine.ServiceManageClient().Client().Update(types.CC_SERVREADONLY_PATH, string(data)); err != nil {
+		blog.Errorf("set read-only mode %s failed, err: %v, rid: %s", data, err, rid)
+		s.writeSystemError(req, resp, defErr.CCError(common.CCErrCommDBUpdateFailed), rid)
+		return
+	}
+
+	blog.Infof("read-only mode is set to %s by %s, rid: %s", data, mode.Operator, rid)
+	if err := resp.WriteEntity(metadata.NewSuccessResp(mode)); err != nil {
+		blog.Errorf("response request url: %s failed, err: %v, rid: %s", req.Request.RequestURI, err, rid)
+	}
+}
+
+func (s *Service) writeSystemError(req *restful.Request, resp *restful.Response, err error, rid string) {
+	if rErr := resp.WriteError(http.StatusOK, &metadata.RespError{Msg: err}); rErr != nil {
+		blog.Errorf("response request url: %s failed, err: %v, rid: %s", req.Request.RequestURI, rErr, rid)
+	}
+}
@@ -104,6 +104,10 @@ func (s *Service) WebService() *restful.Container {
 
 	api.Route(api.PUT("/update/system_config/platform_setting").To(s.UpdatePlatformSettingConfig))
 	api.Route(api.GET("/find/system_config/platform_setting/{type}").To(s.SearchPlatformSettingConfig))
+	api.Route(api.GET("/find/system/readonly_mode").To(s.SearchReadOnlyMode))
+	api.Route(api.PUT("/update/system/readonly_mode").To(s.UpdateReadOnlyMode))
+	api.Route(api.GET("/find/system/fault_inject_rules").To(s.SearchFaultInjectRules))
+	api.Route(api.PUT("/update/system/fault_inject_rules").To(s.UpdateFaultInjectRules))
 
 	api.Route(api.POST("/migrate/specify/version/{distribution}/{ownerID}").To(s.migrateSpecifyVersion))
 	api.Route(api.POST("/migrate/config/refresh").To(s.refreshConfig))
@@ -10,7 +10,9 @@
  * limitations under the License.
  */
 
-package main
+// Package all imports all the upgraders so that they are registered to the migration, it is imported by the admin
+// server and the processes that run the admin server, e.g. the integration test harness.
+package all
 
 import (
 
@@ -79,4 +81,10 @@ import (
 	_ "configcenter/src/scene_server/admin_server/upgrader/y3.10.202205182148"
 	_ "configcenter/src/scene_server/admin_server/upgrader/y3.10.202206081408"
 	_ "configcenter/src/scene_server/admin_server/upgrader/y3.10.202209231617"
+	_ "configcenter/src/scene_server/admin_server/upgrader/y3.10.202210141500"
+	_ "configcenter/src/scene_server/admin_server/upgrader/y3.10.202210181500"
+	_ "configcenter/src/scene_server/admin_server/upgrader/y3.10.202210201500"
+	_ "configcenter/src/scene_server/admin_server/upgrader/y3.10.202210211500"
+	_ "configcenter/src/scene_server/admin_server/upgrader/y3.10.202210221500"
+	_ "configcenter/src/scene_server/admin_server/upgrader/y3.10.202210251000"
 )
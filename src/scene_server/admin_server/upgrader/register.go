@@ -295,6 +295,21 @@ func DBReady(ctx context.Context, db dal.RDB) (bool, error) {
 	return false, nil
 }
 
+// Versions returns the versions of the registered upgraders in ascending order
+func Versions() []string {
+	registLock.Lock()
+	defer registLock.Unlock()
+
+	versions := make([]string, 0, len(upgraderPool))
+	for _, v := range upgraderPool {
+		versions = append(versions, v.version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return VersionCmp(versions[i], versions[j]) < 0
+	})
+	return versions
+}
+
 // UpgradeSpecifyVersion 强制执行version版本的migrate, 不会修改数据库cc_System表中migrate 版本
 func UpgradeSpecifyVersion(ctx context.Context, db dal.RDB, cache redis.Client, iam *iam.IAM, conf *Config,
 	version string) (err error) {
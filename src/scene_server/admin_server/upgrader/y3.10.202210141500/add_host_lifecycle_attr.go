@@ -0,0 +1,126 @@
+/*
+ * Tencent is pleased to support the open source community by making
+ * 蓝鲸智云 - 配置平台 (BlueKing - Configuration System) available.
+ * Copyright (C) 2017 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package y3_10_202210141500
+
+import (
+	"context"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	comm "configcenter/src/scene_server/admin_server/common"
+	"configcenter/src/scene_server/admin_server/upgrader"
+	"configcenter/src/storage/dal"
+)
+
+// addHostLifecycleStateAttr add host lifecycle state attribute, the states and their transitions are defined by the
+// host lifecycle config of the core service, the existing hosts' states are not set and can be set to any state.
+func addHostLifecycleStateAttr(ctx context.Context, db dal.RDB, conf *upgrader.Config) error {
+	lifecycleAttr := &attribute{
+		OwnerID:       conf.OwnerID,
+		ObjectID:      common.BKInnerObjIDHost,
+		PropertyID:    common.BKHostLifecycleStateField,
+		PropertyName:  "生命周期状态",
+		PropertyGroup: comm.BaseInfo,
+		IsEditable:    true,
+		IsPre:         true,
+		IsRequired:    false,
+		IsReadOnly:    false,
+		IsOnly:        false,
+		IsSystem:      false,
+		IsAPI:         false,
+		PropertyType:  common.FieldTypeSingleChar,
+		Option:        "",
+		Description:   "主机的生命周期状态，如规划中、服务中、维护中、退役中、已退役",
+		Creator:       conf.User,
+	}
+
+	// check if the host lifecycle state attribute exists
+	attrFilter := map[string]interface{}{
+		common.BKObjIDField:      common.BKInnerObjIDHost,
+		common.BKPropertyIDField: lifecycleAttr.PropertyID,
+	}
+
+	cnt, err := db.Table(common.BKTableNameObjAttDes).Find(attrFilter).Count(ctx)
+	if err != nil {
+		blog.Errorf("check if attribute exists failed, filter: %v, err: %v", attrFilter, err)
+		return err
+	}
+
+	if cnt > 0 {
+		return nil
+	}
+
+	// add host lifecycle state attribute, generate its id and property index
+	newAttrID, err := db.NextSequence(ctx, common.BKTableNameObjAttDes)
+	if err != nil {
+		blog.Errorf("get new attributes id failed, err: %v", err)
+		return err
+	}
+
+	attrIdxFilter := map[string]interface{}{
+		common.BKObjIDField: common.BKInnerObjIDHost,
+	}
+	sort := common.BKPropertyIndexField + ":-1"
+	maxIdxAttr := new(attribute)
+	if err := db.Table(common.BKTableNameObjAttDes).Find(attrIdxFilter).Sort(sort).One(ctx, maxIdxAttr); err != nil {
+		blog.Errorf("get max host attribute index failed, filter: %v, err: %v", attrIdxFilter, err)
+		return err
+	}
+
+	lifecycleAttr.ID = int64(newAttrID)
+	lifecycleAttr.PropertyIndex = maxIdxAttr.PropertyIndex + 1
+
+	now := time.Now()
+	lifecycleAttr.CreateTime = now
+	lifecycleAttr.LastTime = now
+
+	if err := db.Table(common.BKTableNameObjAttDes).Insert(ctx, lifecycleAttr); err != nil {
+		blog.Errorf("insert host attribute(%#v) failed, err: %v", lifecycleAttr, err)
+		return err
+	}
+
+	return nil
+}
+
+// attribute definition
+type attribute struct {
+	BizID         int64       `bson:"bk_biz_id"`
+	ID            int64       `bson:"id"`
+	OwnerID       string      `bson:"bk_supplier_account"`
+	ObjectID      string      `bson:"bk_obj_id"`
+	PropertyID    string      `bson:"bk_property_id"`
+	PropertyName  string      `bson:"bk_property_name"`
+	PropertyGroup string      `bson:"bk_property_group"`
+	PropertyIndex int64       `bson:"bk_property_index"`
+	Unit          string      `bson:"unit"`
+	Placeholder   string      `bson:"placeholder"`
+	IsEditable    bool        `bson:"editable"`
+	IsPre         bool        `bson:"ispre"`
+	IsRequired    bool        `bson:"isrequired"`
+	IsReadOnly    bool        `bson:"isreadonly"`
+	IsOnly        bool        `bson:"isonly"`
+	IsSystem      bool        `bson:"bk_issystem"`
+	IsAPI         bool        `bson:"bk_isapi"`
+	PropertyType  string      `bson:"bk_property_type"`
+	Option        interface{} `bson:"option"`
+	Description   string      `bson:"description"`
+	Creator       string      `bson:"creator"`
+	CreateTime    time.Time   `bson:"create_time"`
+	LastTime      time.Time   `bson:"last_time"`
+}
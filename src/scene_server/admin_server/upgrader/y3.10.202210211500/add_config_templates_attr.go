@@ -0,0 +1,139 @@
+/*
+ * Tencent is pleased to support the open source community by making
+ * 蓝鲸智云 - 配置平台 (BlueKing - Configuration System) available.
+ * Copyright (C) 2017 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package y3_10_202210211500
+
+import (
+	"context"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	comm "configcenter/src/scene_server/admin_server/common"
+	"configcenter/src/scene_server/admin_server/upgrader"
+	"configcenter/src/storage/dal"
+)
+
+// addProcConfigTemplatesAttr add the process attribute of the config template references, which are synced from the
+// process template and used by the config distribution workflows.
+func addProcConfigTemplatesAttr(ctx context.Context, db dal.RDB, conf *upgrader.Config) error {
+	attrFilter := map[string]interface{}{
+		common.BKObjIDField:      common.BKInnerObjIDProc,
+		common.BKPropertyIDField: common.BKConfigTemplatesField,
+	}
+
+	cnt, err := db.Table(common.BKTableNameObjAttDes).Find(attrFilter).Count(ctx)
+	if err != nil {
+		blog.Errorf("check if attribute exists failed, filter: %v, err: %v", attrFilter, err)
+		return err
+	}
+
+	if cnt > 0 {
+		return nil
+	}
+
+	option := []metadata.SubAttribute{
+		{
+			PropertyID:    "template_id",
+			PropertyName:  "模板ID",
+			Placeholder:   "配置系统中的配置模板ID",
+			IsEditable:    true,
+			IsRequired:    true,
+			PropertyType:  common.FieldTypeSingleChar,
+			PropertyGroup: common.BKConfigTemplatesField,
+		},
+		{
+			PropertyID:    "version",
+			PropertyName:  "版本",
+			Placeholder:   "配置模板的版本",
+			IsEditable:    true,
+			IsRequired:    true,
+			PropertyType:  common.FieldTypeSingleChar,
+			PropertyGroup: common.BKConfigTemplatesField,
+		},
+	}
+
+	newAttrID, err := db.NextSequence(ctx, common.BKTableNameObjAttDes)
+	if err != nil {
+		blog.Errorf("get new attributes id failed, err: %v", err)
+		return err
+	}
+
+	attrIdxFilter := map[string]interface{}{
+		common.BKObjIDField: common.BKInnerObjIDProc,
+	}
+	sort := common.BKPropertyIndexField + ":-1"
+	maxIdxAttr := new(attribute)
+	if err := db.Table(common.BKTableNameObjAttDes).Find(attrIdxFilter).Sort(sort).One(ctx, maxIdxAttr); err != nil {
+		blog.Errorf("get max process attribute index failed, filter: %v, err: %v", attrIdxFilter, err)
+		return err
+	}
+
+	now := time.Now()
+	attr := &attribute{
+		ID:            int64(newAttrID),
+		OwnerID:       conf.OwnerID,
+		ObjectID:      common.BKInnerObjIDProc,
+		PropertyID:    common.BKConfigTemplatesField,
+		PropertyName:  "配置模板",
+		PropertyGroup: comm.ProcMgrGroupID,
+		PropertyIndex: maxIdxAttr.PropertyIndex + 1,
+		IsEditable:    true,
+		IsPre:         true,
+		PropertyType:  common.FieldTypeTable,
+		Option:        option,
+		Description:   "进程引用的配置系统中的配置模板及版本",
+		Creator:       conf.User,
+		CreateTime:    now,
+		LastTime:      now,
+	}
+
+	if err := db.Table(common.BKTableNameObjAttDes).Insert(ctx, attr); err != nil {
+		blog.Errorf("insert process attribute(%#v) failed, err: %v", attr, err)
+		return err
+	}
+
+	return nil
+}
+
+// attribute definition
+type attribute struct {
+	BizID         int64       `bson:"bk_biz_id"`
+	ID            int64       `bson:"id"`
+	OwnerID       string      `bson:"bk_supplier_account"`
+	ObjectID      string      `bson:"bk_obj_id"`
+	PropertyID    string      `bson:"bk_property_id"`
+	PropertyName  string      `bson:"bk_property_name"`
+	PropertyGroup string      `bson:"bk_property_group"`
+	PropertyIndex int64       `bson:"bk_property_index"`
+	Unit          string      `bson:"unit"`
+	Placeholder   string      `bson:"placeholder"`
+	IsEditable    bool        `bson:"editable"`
+	IsPre         bool        `bson:"ispre"`
+	IsRequired    bool        `bson:"isrequired"`
+	IsReadOnly    bool        `bson:"isreadonly"`
+	IsOnly        bool        `bson:"isonly"`
+	IsSystem      bool        `bson:"bk_issystem"`
+	IsAPI         bool        `bson:"bk_isapi"`
+	PropertyType  string      `bson:"bk_property_type"`
+	Option        interface{} `bson:"option"`
+	Description   string      `bson:"description"`
+	Creator       string      `bson:"creator"`
+	CreateTime    time.Time   `bson:"create_time"`
+	LastTime      time.Time   `bson:"last_time"`
+}
@@ -0,0 +1,153 @@
+/*
+ * Tencent is pleased to support the open source community by making
+ * 蓝鲸智云 - 配置平台 (BlueKing - Configuration System) available.
+ * Copyright (C) 2017 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ * We undertake not to change the open source license (MIT license) applicable
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package y3_10_202210251000
+
+import (
+	"context"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	comm "configcenter/src/scene_server/admin_server/common"
+	"configcenter/src/scene_server/admin_server/upgrader"
+	"configcenter/src/storage/dal"
+)
+
+// addHostProbeAttrs add the host attributes of the latest connectivity probe result, they are not editable since
+// they are only updated by the host probe.
+func addHostProbeAttrs(ctx context.Context, db dal.RDB, conf *upgrader.Config) error {
+	probeAttrs := []*attribute{
+		{
+			PropertyID:   common.BKProbeStatusField,
+			PropertyName: "连通性探测状态",
+			PropertyType: common.FieldTypeEnum,
+			Option: []enumVal{
+				{ID: metadata.HostProbeReachable, Name: "可达", Type: "text"},
+				{ID: metadata.HostProbeUnreachable, Name: "不可达", Type: "text"},
+				{ID: metadata.HostProbeUnknown, Name: "未知", Type: "text"},
+			},
+			Description: "主机最近一次连通性探测(ping、端口或agent状态)的结果",
+		},
+		{
+			PropertyID:   common.BKProbeTimeField,
+			PropertyName: "连通性探测时间",
+			PropertyType: common.FieldTypeTime,
+			Option:       "",
+			Description:  "主机最近一次连通性探测的时间",
+		},
+	}
+
+	for _, attr := range probeAttrs {
+		attr.OwnerID = conf.OwnerID
+		attr.ObjectID = common.BKInnerObjIDHost
+		attr.PropertyGroup = comm.BaseInfo
+		attr.IsEditable = false
+		attr.IsPre = true
+		attr.Creator = conf.User
+
+		if err := addHostAttr(ctx, db, attr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addHostAttr add the host attribute if it does not exist, generate its id and property index
+func addHostAttr(ctx context.Context, db dal.RDB, attr *attribute) error {
+	attrFilter := map[string]interface{}{
+		common.BKObjIDField:      common.BKInnerObjIDHost,
+		common.BKPropertyIDField: attr.PropertyID,
+	}
+
+	cnt, err := db.Table(common.BKTableNameObjAttDes).Find(attrFilter).Count(ctx)
+	if err != nil {
+		blog.Errorf("check if attribute exists failed, filter: %v, err: %v", attrFilter, err)
+		return err
+	}
+
+	if cnt > 0 {
+		return nil
+	}
+
+	newAttrID, err := db.NextSequence(ctx, common.BKTableNameObjAttDes)
+	if err != nil {
+		blog.Errorf("get new attributes id failed, err: %v", err)
+		return err
+	}
+
+	attrIdxFilter := map[string]interface{}{
+		common.BKObjIDField: common.BKInnerObjIDHost,
+	}
+	sort := common.BKPropertyIndexField + ":-1"
+	maxIdxAttr := new(attribute)
+	if err := db.Table(common.BKTableNameObjAttDes).Find(attrIdxFilter).Sort(sort).One(ctx, maxIdxAttr); err != nil {
+		blog.Errorf("get max host attribute index failed, filter: %v, err: %v", attrIdxFilter, err)
+		return err
+	}
+
+	attr.ID = int64(newAttrID)
+	attr.PropertyIndex = maxIdxAttr.PropertyIndex + 1
+
+	now := time.Now()
+	attr.CreateTime = now
+	attr.LastTime = now
+
+	if err := db.Table(common.BKTableNameObjAttDes).Insert(ctx, attr); err != nil {
+		blog.Errorf("insert host attribute(%#v) failed, err: %v", attr, err)
+		return err
+	}
+
+	return nil
+}
+
+// attribute definition
+type attribute struct {
+	BizID         int64       `bson:"bk_biz_id"`
+	ID            int64       `bson:"id"`
+	OwnerID       string      `bson:"bk_supplier_account"`
+	ObjectID      string      `bson:"bk_obj_id"`
+	PropertyID    string      `bson:"bk_property_id"`
+	PropertyName  string      `bson:"bk_property_name"`
+	PropertyGroup string      `bson:"bk_property_group"`
+	PropertyIndex int64       `bson:"bk_property_index"`
+	Unit          string      `bson:"unit"`
+	Placeholder   string      `bson:"placeholder"`
+	IsEditable    bool        `bson:"editable"`
+	IsPre         bool        `bson:"ispre"`
+	IsRequired    bool        `bson:"isrequired"`
+	IsReadOnly    bool        `bson:"isreadonly"`
+	IsOnly        bool        `bson:"isonly"`
+	IsSystem      bool        `bson:"bk_issystem"`
+	IsAPI         bool        `bson:"bk_isapi"`
+	PropertyType  string      `bson:"bk_property_type"`
+	Option        interface{} `bson:"option"`
+	Description   string      `bson:"description"`
+	Creator       string      `bson:"creator"`
+	CreateTime    time.Time   `bson:"create_time"`
+	LastTime      time.Time   `bson:"last_time"`
+}
+
+// enumVal the option of the enum attribute
+type enumVal struct {
+	ID        string `bson:"id"`
+	Name      string `bson:"name"`
+	Type      string `bson:"type"`
+	IsDefault bool   `bson:"is_default"`
+}
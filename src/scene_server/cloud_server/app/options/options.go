@@ -54,4 +54,6 @@ type Config struct {
 	SecretsEnv     string
 	// sync period of cloud sync task, unit is second
 	SyncPeriodMinutes int
+	// TransformScript the file of the hook script that transforms the synced host fields
+	TransformScript string
 }
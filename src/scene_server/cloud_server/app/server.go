@@ -25,10 +25,13 @@ import (
 	cc "configcenter/src/common/backbone/configcenter"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/cryptor"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/script"
 	"configcenter/src/common/types"
 	"configcenter/src/common/util"
 	"configcenter/src/scene_server/cloud_server/app/options"
 	"configcenter/src/scene_server/cloud_server/cloudsync"
+	"configcenter/src/scene_server/cloud_server/cloudvendor"
 	"configcenter/src/scene_server/cloud_server/logics"
 	svc "configcenter/src/scene_server/cloud_server/service"
 	"configcenter/src/thirdparty/secrets"
@@ -102,6 +105,8 @@ func Run(ctx context.Context, cancel context.CancelFunc, op *options.ServerOptio
 	process.Service.Logics = logics.NewLogics(service.Engine, accountCryptor, authorizer)
 
 	process.setSyncPeriod()
+	process.setTransformHook()
+	process.setCallScheduler()
 	syncConf := cloudsync.SyncConf{
 		ZKClient:  service.Engine.ServiceManageClient().Client(),
 		Logics:    process.Service.Logics,
@@ -144,6 +149,7 @@ func (c *CloudServer) onCloudConfigUpdate(previous, current cc.ProcessConfig) {
 	c.Config.SecretsProject, _ = cc.String("cloudServer.cryptor.secretsProject")
 	c.Config.SecretsEnv, _ = cc.String("cloudServer.cryptor.secretsEnv")
 	c.Config.SyncPeriodMinutes, _ = cc.Int("cloudServer.syncTask.syncPeriodMinutes")
+	c.Config.TransformScript, _ = cc.String("cloudServer.syncTask.transformScript")
 }
 
 // getSecretKey get the secret key from bk-secrets service
@@ -194,3 +200,47 @@ func (c *CloudServer) setSyncPeriod() {
 	}
 	blog.Infof("sync period is %d minutes", cloudsync.SyncPeriodMinutes)
 }
+
+// setTransformHook set the hook that transforms the synced host fields if the hook script is configured
+func (c *CloudServer) setTransformHook() {
+	if c.Config.TransformScript == "" {
+		return
+	}
+
+	hook, err := script.NewHookFromFile("cloudsync", c.Config.TransformScript, script.DefaultLimits)
+	if err != nil {
+		blog.Errorf("load cloud sync transform hook failed, skip transforming, err: %v", err)
+		return
+	}
+	cloudsync.TransformHook = hook
+	blog.Infof("cloud sync transform hook is loaded from %s", c.Config.TransformScript)
+}
+
+// rateLimitVendors the config names of the cloud vendors' rate limits
+var rateLimitVendors = map[string]string{
+	"aws":          metadata.AWS,
+	"tencentCloud": metadata.TencentCloud,
+}
+
+// setCallScheduler set the rate limits and circuit break durations of the cloud api calls if they are configured
+func (c *CloudServer) setCallScheduler() {
+	for name, vendorName := range rateLimitVendors {
+		qps, qpsErr := cc.Int64("cloudServer.rateLimiter." + name + ".qps")
+		burst, burstErr := cc.Int64("cloudServer.rateLimiter." + name + ".burst")
+		if qpsErr != nil || burstErr != nil || qps <= 0 || burst <= 0 {
+			blog.Infof("%s rate limiter is not configured or invalid, use the default value", name)
+			continue
+		}
+		cloudvendor.SetRateLimit(vendorName, cloudvendor.RateLimit{QPS: qps, Burst: burst})
+		blog.Infof("%s rate limiter of each account is set, qps: %d, burst: %d", name, qps, burst)
+	}
+
+	if seconds, err := cc.Int("cloudServer.circuitBreaker.breakSeconds"); err == nil && seconds > 0 {
+		cloudvendor.CircuitBreakDuration = time.Duration(seconds) * time.Second
+	}
+	if seconds, err := cc.Int("cloudServer.circuitBreaker.maxBreakSeconds"); err == nil && seconds > 0 {
+		cloudvendor.CircuitBreakMaxDuration = time.Duration(seconds) * time.Second
+	}
+	blog.Infof("cloud api circuit break duration is %s, max duration is %s", cloudvendor.CircuitBreakDuration,
+		cloudvendor.CircuitBreakMaxDuration)
+}
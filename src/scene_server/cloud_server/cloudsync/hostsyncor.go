@@ -305,6 +305,8 @@ func (h *HostSyncor) getDiffHosts(hostResource *metadata.CloudHostResource) (map
 	remoteHostsMap := make(map[string]*metadata.CloudHost)
 	for _, hostRes := range hostResource.HostResource {
 		for _, host := range hostRes.Instances {
+			// 云主机所在地域即其vpc所在地域
+			host.Region = hostRes.Vpc.Region
 			remoteHostsMap[host.InstanceId] = &metadata.CloudHost{
 				Instance:   *host,
 				CloudID:    hostRes.CloudID,
@@ -343,7 +345,8 @@ func (h *HostSyncor) getDiffHosts(hostResource *metadata.CloudHostResource) (map
 				continue
 			}
 			if h.InstanceState != lh.InstanceState || h.PublicIp != lh.PublicIp ||
-				h.PrivateIp != lh.PrivateIp || h.CloudID != lh.CloudID {
+				h.PrivateIp != lh.PrivateIp || h.CloudID != lh.CloudID || h.Region != lh.Region ||
+				h.Zone != lh.Zone {
 				diffHosts["update"] = append(diffHosts["update"], h)
 			}
 		} else {
@@ -566,6 +569,8 @@ func (h *HostSyncor) getLocalHosts(cloudIDs []int64) ([]*metadata.CloudHost, err
 		hostStatus, _ := host.String(common.BKCloudHostStatusField)
 		privateIp, _ := host.String(common.BKHostInnerIPField)
 		publicIp, _ := host.String(common.BKHostOuterIPField)
+		region, _ := host.String(common.BKHostCloudRegionField)
+		zone, _ := host.String(common.BKHostCloudZoneField)
 		cloudID, _ := host.Int64(common.BKCloudIDField)
 		hostID, _ := host.Int64(common.BKHostIDField)
 		result = append(result, &metadata.CloudHost{
@@ -574,6 +579,8 @@ func (h *HostSyncor) getLocalHosts(cloudIDs []int64) ([]*metadata.CloudHost, err
 				InstanceState: hostStatus,
 				PrivateIp:     privateIp,
 				PublicIp:      publicIp,
+				Region:        region,
+				Zone:          zone,
 			},
 			CloudID: cloudID,
 			HostID:  hostID,
@@ -640,7 +647,10 @@ func (h *HostSyncor) addHost(cHost *metadata.CloudHost) (string, error) {
 		common.BKHostOuterIPField:     cHost.PublicIp,
 		common.BKCloudHostStatusField: cHost.InstanceState,
 		common.BKCloudVendor:          cHost.VendorName,
+		common.BKHostCloudRegionField: cHost.Region,
+		common.BKHostCloudZoneField:   cHost.Zone,
 	}
+	host = h.transformHost(host)
 	input := &metadata.CreateModelInstance{
 		Data: host,
 	}
@@ -732,7 +742,10 @@ func (h *HostSyncor) updateHosts(hosts []*metadata.CloudHost) (*metadata.SyncRes
 			common.BKHostInnerIPField:     host.PrivateIp,
 			common.BKHostOuterIPField:     host.PublicIp,
 			common.BKCloudHostStatusField: host.InstanceState,
+			common.BKHostCloudRegionField: host.Region,
+			common.BKHostCloudZoneField:   host.Zone,
 		}
+		updateInfo = h.transformHost(updateInfo)
 
 		// generate audit log.
 		genAuditParam := auditlog.NewGenerateAuditCommonParameter(h.readKit, metadata.AuditUpdate).
@@ -769,6 +782,22 @@ func (h *HostSyncor) updateHosts(hosts []*metadata.CloudHost) (*metadata.SyncRes
 	return syncResult, nil
 }
 
+// transformHost 使用转换钩子转换主机字段，未配置钩子或转换失败时使用原字段
+func (h *HostSyncor) transformHost(host mapstr.MapStr) mapstr.MapStr {
+	if TransformHook == nil {
+		return host
+	}
+
+	transformed, err := TransformHook.Transform(host)
+	if err != nil {
+		blog.Errorf("transform cloud host %v failed, use the original fields, err: %v, rid: %s",
+			host[common.BKCloudInstIDField], err, h.readKit.Rid)
+		return host
+	}
+
+	return transformed
+}
+
 // updateHost 更新云主机
 func (h *HostSyncor) updateHost(cloudInstID string, updateInfo map[string]interface{}) error {
 	input := &metadata.UpdateOption{
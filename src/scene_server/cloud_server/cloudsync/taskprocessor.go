@@ -17,6 +17,7 @@ import (
 
 	"configcenter/src/common/blog"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/script"
 )
 
 const (
@@ -27,6 +28,9 @@ const (
 // SyncPeriodMinutes 同步周期，单位为分钟
 var SyncPeriodMinutes int
 
+// TransformHook 同步主机字段的转换钩子，未配置时为nil
+var TransformHook *script.Hook
+
 // 任务处理器
 type taskProcessor struct {
 	scheduler *taskScheduler
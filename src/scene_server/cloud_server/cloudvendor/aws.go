@@ -19,6 +19,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 )
@@ -31,6 +32,7 @@ type awsClient struct {
 	vendorName string
 	secretID   string
 	secretKey  string
+	caller     *Caller
 }
 
 const (
@@ -60,12 +62,18 @@ var regionIdNameMap = map[string]string{
 	"sa-east-1":      "南美洲（圣保罗）",
 }
 
+// IsThrottled 判断是否为aws的限流错误
+func (c *awsClient) IsThrottled(err error) bool {
+	return request.IsErrorThrottle(err)
+}
+
 // NewVendorClient 创建云厂商客户端
-func (c *awsClient) NewVendorClient(secretID, secretKey string) VendorClient {
+func (c *awsClient) NewVendorClient(secretID, secretKey string, caller *Caller) VendorClient {
 	return &awsClient{
 		vendorName: metadata.AWS,
 		secretID:   secretID,
 		secretKey:  secretKey,
+		caller:     caller,
 	}
 }
 
@@ -79,7 +87,11 @@ func (c *awsClient) GetRegions() ([]*metadata.Region, error) {
 	ec2Svc := ec2.New(sess)
 
 	input := c.newDescribeRegionsInput()
-	resp, err := ec2Svc.DescribeRegions(input)
+	var resp *ec2.DescribeRegionsOutput
+	err = c.caller.Call(func() (err error) {
+		resp, err = ec2Svc.DescribeRegions(input)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +124,11 @@ func (c *awsClient) GetVpcs(region string, opt *ccom.VpcOpt) (*metadata.VpcsInfo
 	input := c.newDescribeVpcsInput(opt)
 	// 在limit小于全部数据量的情况下，获取limit数量的数据，否则获取全部数据
 	for {
-		output, err := ec2Svc.DescribeVpcs(input)
+		var output *ec2.DescribeVpcsOutput
+		err := c.caller.Call(func() (err error) {
+			output, err = ec2Svc.DescribeVpcs(input)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -183,7 +199,11 @@ func (c *awsClient) getInstances(region string, opt *ccom.InstanceOpt) ([]*metad
 	input := c.newDescribeInstancesInput(opt)
 	// 在limit小于全部数据量的情况下，获取limit数量的数据，否则获取全部数据
 	for {
-		output, err := ec2Svc.DescribeInstances(input)
+		var output *ec2.DescribeInstancesOutput
+		err := c.caller.Call(func() (err error) {
+			output, err = ec2Svc.DescribeInstances(input)
+			return err
+		})
 		if err != nil {
 			return nil, false, err
 		}
@@ -211,12 +231,18 @@ func (c *awsClient) getInstances(region string, opt *ccom.InstanceOpt) ([]*metad
 					vpcID = *inst.VpcId
 				}
 
+				zone := ""
+				if inst.Placement != nil && inst.Placement.AvailabilityZone != nil {
+					zone = *inst.Placement.AvailabilityZone
+				}
+
 				instances = append(instances, &metadata.Instance{
 					InstanceId:    *inst.InstanceId,
 					PrivateIp:     privateIP,
 					PublicIp:      publicIP,
 					InstanceState: ccom.CovertInstState(state),
 					VpcId:         vpcID,
+					Zone:          zone,
 				})
 			}
 		}
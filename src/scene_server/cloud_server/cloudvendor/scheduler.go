@@ -0,0 +1,192 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudvendor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"configcenter/src/apimachinery/flowctrl"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+)
+
+// Priority 云厂商接口调用的优先级
+type Priority int
+
+const (
+	// PrioritySync 定时同步任务的调用，优先级低
+	PrioritySync Priority = iota
+	// PriorityInteractive 用户交互请求的调用，如验证账户连通性，优先级高
+	PriorityInteractive
+)
+
+// RateLimit 单个云账户调用云厂商接口的限流配置
+type RateLimit struct {
+	QPS   int64
+	Burst int64
+}
+
+var (
+	// defaultRateLimit 未配置限流的云厂商使用的默认限流配置
+	defaultRateLimit = RateLimit{QPS: 10, Burst: 10}
+
+	// CircuitBreakDuration 云厂商返回限流错误后暂停调用该账户接口的时长，连续被限流时加倍
+	CircuitBreakDuration = 30 * time.Second
+	// CircuitBreakMaxDuration 暂停调用云账户接口的最大时长
+	CircuitBreakMaxDuration = 10 * time.Minute
+)
+
+// callScheduler 云厂商接口调用的调度器，按云账户限流，高优先级的调用优先获得令牌，
+// 云厂商返回限流错误时熔断该账户的调用，避免同时运行的同步任务导致账户被封禁
+var callScheduler = &scheduler{
+	rateLimits: make(map[string]RateLimit),
+	accounts:   make(map[string]*accountScheduler),
+}
+
+type scheduler struct {
+	lock       sync.Mutex
+	rateLimits map[string]RateLimit
+	accounts   map[string]*accountScheduler
+}
+
+// SetRateLimit 设置云厂商的单个云账户的限流配置，仅对之后首次调用的云账户生效
+func SetRateLimit(vendorName string, limit RateLimit) {
+	callScheduler.lock.Lock()
+	defer callScheduler.lock.Unlock()
+	callScheduler.rateLimits[vendorName] = limit
+}
+
+// getAccount 获取云账户的调度器，不存在时创建
+func (s *scheduler) getAccount(vendorName, secretID string) *accountScheduler {
+	key := vendorName + ":" + secretID
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if account, exists := s.accounts[key]; exists {
+		return account
+	}
+
+	limit, exists := s.rateLimits[vendorName]
+	if !exists || limit.QPS <= 0 || limit.Burst <= 0 {
+		limit = defaultRateLimit
+	}
+
+	account := &accountScheduler{
+		limiter: flowctrl.NewRateLimiter(limit.QPS, limit.Burst),
+		queues:  []chan struct{}{make(chan struct{}), make(chan struct{})},
+	}
+	go account.dispatch()
+	s.accounts[key] = account
+	return account
+}
+
+// accountScheduler 单个云账户的调度器
+type accountScheduler struct {
+	limiter flowctrl.RateLimiter
+	// queues 各优先级的等待队列，以优先级为下标
+	queues []chan struct{}
+
+	lock       sync.Mutex
+	breakUntil time.Time
+	breakTimes int
+}
+
+// dispatch 获取令牌后将其分配给优先级最高的等待调用
+func (a *accountScheduler) dispatch() {
+	for {
+		a.limiter.Accept()
+
+		select {
+		case <-a.queues[PriorityInteractive]:
+			continue
+		default:
+		}
+
+		select {
+		case <-a.queues[PriorityInteractive]:
+		case <-a.queues[PrioritySync]:
+		}
+	}
+}
+
+// checkBreak 检查云账户的调用是否被熔断
+func (a *accountScheduler) checkBreak() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if time.Now().Before(a.breakUntil) {
+		return fmt.Errorf("cloud api calls are suspended until %s since the provider throttled the requests",
+			a.breakUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// record 记录调用结果，被限流时熔断云账户的调用，调用成功时恢复熔断时长
+func (a *accountScheduler) record(throttled bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if !throttled {
+		a.breakTimes = 0
+		return
+	}
+
+	duration := CircuitBreakDuration << uint(a.breakTimes)
+	if duration <= 0 || duration > CircuitBreakMaxDuration {
+		duration = CircuitBreakMaxDuration
+	} else {
+		a.breakTimes++
+	}
+	a.breakUntil = time.Now().Add(duration)
+}
+
+// Caller 通过调度器以指定优先级调用云账户的接口
+type Caller struct {
+	account     *accountScheduler
+	priority    Priority
+	isThrottled func(err error) bool
+}
+
+// newCaller 创建云账户的接口调用器
+func newCaller(conf metadata.CloudAccountConf, priority Priority, isThrottled func(err error) bool) *Caller {
+	return &Caller{
+		account:     callScheduler.getAccount(conf.VendorName, conf.SecretID),
+		priority:    priority,
+		isThrottled: isThrottled,
+	}
+}
+
+// Call 等待获得令牌后调用云厂商接口，云账户的调用被熔断时直接返回错误
+func (c *Caller) Call(call func() error) error {
+	if err := c.account.checkBreak(); err != nil {
+		return err
+	}
+
+	c.account.queues[c.priority] <- struct{}{}
+
+	// 等待令牌期间可能被熔断
+	if err := c.account.checkBreak(); err != nil {
+		return err
+	}
+
+	err := call()
+	throttled := err != nil && c.isThrottled(err)
+	if throttled {
+		blog.Errorf("cloud api call is throttled by the provider, suspend the calls of the account, err: %v", err)
+	}
+	c.account.record(throttled)
+	return err
+}
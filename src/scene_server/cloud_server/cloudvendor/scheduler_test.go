@@ -0,0 +1,73 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudvendor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"configcenter/src/common/metadata"
+)
+
+func TestCallerCircuitBreak(t *testing.T) {
+	errThrottled := errors.New("throttled")
+	conf := metadata.CloudAccountConf{VendorName: "test", SecretID: "circuit-break"}
+	caller := newCaller(conf, PrioritySync, func(err error) bool { return err == errThrottled })
+
+	if err := caller.Call(func() error { return nil }); err != nil {
+		t.Fatalf("call failed, err: %v", err)
+	}
+
+	if err := caller.Call(func() error { return errThrottled }); err != errThrottled {
+		t.Fatalf("throttled call returns unexpected err: %v", err)
+	}
+
+	called := false
+	if err := caller.Call(func() error { called = true; return nil }); err == nil || called {
+		t.Fatalf("call is not suspended after throttled, err: %v, called: %v", err, called)
+	}
+
+	interactive := newCaller(conf, PriorityInteractive, caller.isThrottled)
+	if err := interactive.Call(func() error { return nil }); err == nil {
+		t.Fatalf("interactive call of the same account is not suspended")
+	}
+
+	// the calls are resumed after the break duration
+	caller.account.breakUntil = time.Now()
+	if err := caller.Call(func() error { return nil }); err != nil {
+		t.Fatalf("call is not resumed after the break duration, err: %v", err)
+	}
+	if caller.account.breakTimes != 0 {
+		t.Fatalf("break times is not reset after the successful call, break times: %d", caller.account.breakTimes)
+	}
+}
+
+func TestAccountSchedulerBreakDuration(t *testing.T) {
+	account := callScheduler.getAccount("test", "break-duration")
+
+	account.record(true)
+	first := time.Until(account.breakUntil)
+	account.record(true)
+	second := time.Until(account.breakUntil)
+	if second <= first {
+		t.Fatalf("break duration is not increased on consecutive throttles, first: %s, second: %s", first, second)
+	}
+
+	for i := 0; i < 100; i++ {
+		account.record(true)
+	}
+	if time.Until(account.breakUntil) > CircuitBreakMaxDuration {
+		t.Fatalf("break duration exceeds the max duration: %s", time.Until(account.breakUntil))
+	}
+}
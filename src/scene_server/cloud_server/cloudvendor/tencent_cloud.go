@@ -14,12 +14,14 @@ package cloudvendor
 
 import (
 	"fmt"
+	"strings"
 
 	"configcenter/src/common/blog"
 	"configcenter/src/common/metadata"
 	ccom "configcenter/src/scene_server/cloud_server/common"
 
 	tcCommon "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	tcErrors "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/regions"
 	cvm "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cvm/v20170312"
@@ -34,19 +36,33 @@ type tcClient struct {
 	vendorName string
 	secretID   string
 	secretKey  string
+	caller     *Caller
 }
 
 const (
 	tcMinPageSize int64 = 1
 	tcMaxPageSize int64 = 100
+
+	// tcThrottledCode 腾讯云接口限流错误码的前缀
+	tcThrottledCode = "RequestLimitExceeded"
 )
 
+// IsThrottled 判断是否为腾讯云的限流错误
+func (c *tcClient) IsThrottled(err error) bool {
+	sdkErr, ok := err.(*tcErrors.TencentCloudSDKError)
+	if !ok {
+		return false
+	}
+	return strings.HasPrefix(sdkErr.GetCode(), tcThrottledCode)
+}
+
 // NewVendorClient 创建云厂商客户端
-func (c *tcClient) NewVendorClient(secretID, secretKey string) VendorClient {
+func (c *tcClient) NewVendorClient(secretID, secretKey string, caller *Caller) VendorClient {
 	return &tcClient{
 		vendorName: metadata.TencentCloud,
 		secretID:   secretID,
 		secretKey:  secretKey,
+		caller:     caller,
 	}
 }
 
@@ -60,7 +76,11 @@ func (c *tcClient) GetRegions() ([]*metadata.Region, error) {
 	}
 
 	request := c.newDescribeRegionsRequest()
-	resp, err := client.DescribeRegions(request)
+	var resp *cvm.DescribeRegionsResponse
+	err = c.caller.Call(func() (err error) {
+		resp, err = client.DescribeRegions(request)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +115,11 @@ func (c *tcClient) GetVpcs(region string, opt *ccom.VpcOpt) (*metadata.VpcsInfo,
 	request := c.newDescribeVpcsRequest(opt)
 	// 在limit小于全部数据量的情况下，获取limit数量的数据，否则获取全部数据
 	for {
-		resp, err := client.DescribeVpcs(request)
+		var resp *tcVpc.DescribeVpcsResponse
+		err := c.caller.Call(func() (err error) {
+			resp, err = client.DescribeVpcs(request)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -143,7 +167,11 @@ func (c *tcClient) GetInstances(region string, opt *ccom.InstanceOpt) (*metadata
 	request := c.newDescribeInstancesRequest(opt)
 	// 在limit小于全部数据量的情况下，获取limit数量的数据，否则获取全部数据
 	for {
-		resp, err := client.DescribeInstances(request)
+		var resp *cvm.DescribeInstancesResponse
+		err := c.caller.Call(func() (err error) {
+			resp, err = client.DescribeInstances(request)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -172,12 +200,18 @@ func (c *tcClient) GetInstances(region string, opt *ccom.InstanceOpt) (*metadata
 
 			}
 
+			zone := ""
+			if inst.Placement != nil && inst.Placement.Zone != nil {
+				zone = *inst.Placement.Zone
+			}
+
 			instancesInfo.InstanceSet = append(instancesInfo.InstanceSet, &metadata.Instance{
 				InstanceId:    *inst.InstanceId,
 				PrivateIp:     privateIP,
 				PublicIp:      publicIP,
 				InstanceState: ccom.CovertInstState(state),
 				VpcId:         vpcID,
+				Zone:          zone,
 			})
 		}
 
@@ -23,8 +23,10 @@ var vendorClients = make(map[string]VendorClient, 0)
 
 // VendorClient TODO
 type VendorClient interface {
-	// NewVendorClient 创建云厂商客户端
-	NewVendorClient(secretID, secretKey string) VendorClient
+	// NewVendorClient 创建云厂商客户端，caller用于通过调度器调用云厂商接口
+	NewVendorClient(secretID, secretKey string, caller *Caller) VendorClient
+	// IsThrottled 判断云厂商接口的错误是否为限流错误
+	IsThrottled(err error) bool
 	// GetRegions 获取地域列表
 	GetRegions() ([]*metadata.Region, error)
 	// GetVpcs 获取vpc列表
@@ -40,13 +42,18 @@ func Register(vendorName string, client VendorClient) {
 	vendorClients[vendorName] = client
 }
 
-// GetVendorClient 获取云厂商客户端
+// GetVendorClient 获取云厂商客户端，用于用户交互请求
 func GetVendorClient(conf metadata.CloudAccountConf) (VendorClient, error) {
+	return GetVendorClientWithPriority(conf, PriorityInteractive)
+}
+
+// GetVendorClientWithPriority 获取以指定优先级调用云厂商接口的客户端
+func GetVendorClientWithPriority(conf metadata.CloudAccountConf, priority Priority) (VendorClient, error) {
 	var client VendorClient
 	var ok bool
 	if client, ok = vendorClients[conf.VendorName]; !ok {
 		return nil, fmt.Errorf("vendor %s is not supported", conf.VendorName)
 	}
-	cli := client.NewVendorClient(conf.SecretID, conf.SecretKey)
+	cli := client.NewVendorClient(conf.SecretID, conf.SecretKey, newCaller(conf, priority, client.IsThrottled))
 	return cli, nil
 }
@@ -19,6 +19,7 @@ import (
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
 	"configcenter/src/common/util"
 )
 
@@ -48,6 +49,10 @@ func NewHeader() http.Header {
 	header.Add(common.BKHTTPLanguage, "cn")
 	header.Add(common.BKHTTPCCRequestID, util.GenerateRID())
 	header.Add("Content-Type", "application/json")
+	metadata.SetImportContext(header, &metadata.ImportContext{
+		Source: metadata.ImportSourceCloudSync,
+		Policy: metadata.ImportConflictSourcePriority,
+	})
 	return header
 }
 
@@ -232,7 +232,8 @@ func (lgc *Logics) GetVpcHostCnt(kit *rest.Kit, conf metadata.CloudAccountConf,
 
 // GetCloudHostResource 获取需要同步的云主机资源信息
 func (lgc *Logics) GetCloudHostResource(kit *rest.Kit, conf metadata.CloudAccountConf, syncVpcs []metadata.VpcSyncInfo) (*metadata.CloudHostResource, error) {
-	client, err := cloudvendor.GetVendorClient(conf)
+	// 同步任务的调用优先级低于用户交互请求
+	client, err := cloudvendor.GetVendorClientWithPriority(conf, cloudvendor.PrioritySync)
 	if err != nil {
 		blog.Errorf("GetCloudHostResource GetVendorClient failed, AccountID:%d, err:%s, rid:%s", conf.AccountID, err.Error(), kit.Rid)
 		return nil, err
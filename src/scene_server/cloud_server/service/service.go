@@ -67,6 +67,7 @@ func (s *Service) WebService() *restful.Container {
 		return s.Engine.CCErr
 	}
 	api.Filter(rdapi.AllGlobalFilter(getErrFunc))
+	api.Filter(s.Engine.ReadOnlyFilter())
 	api.Produces(restful.MIME_JSON)
 
 	s.initRoute(api)
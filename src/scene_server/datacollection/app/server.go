@@ -129,6 +129,9 @@ type DataCollection struct {
 	// redisCli is cc main cache redis client.
 	redisCli redis.Client
 
+	// redisAvailability is the availability of cc main cache redis, the analyzers degrade while it is unavailable.
+	redisAvailability *redis.Availability
+
 	// snapRedisCli is snap redis client.
 	snapRedisCli redis.Client
 
@@ -332,7 +335,9 @@ func (c *DataCollection) initModules() error {
 		return fmt.Errorf("connect to cc main redis, %+v", err)
 	}
 	c.redisCli = redisCli
-	c.service.SetCache(redisCli)
+	c.redisAvailability = redis.NewAvailability("cc main", redisCli, redis.AvailabilityConfig{})
+	go c.redisAvailability.Run(c.ctx)
+	c.service.SetCache(redisCli, c.redisAvailability)
 	blog.Infof("DataCollection| init modules, connected to cc main redis, %+v", c.config.CCRedis)
 
 	// connect to snap redis.
@@ -482,7 +487,8 @@ func (c *DataCollection) runCollectPorters() {
 	// create and add new porters.
 	if metadata.GseConfigReportMode(c.config.SnapReportMode) == metadata.GseConfigReportModeKafka {
 		topic := c.snapMessageTopic(c.defaultAppID)
-		analyzer := hostsnap.NewHostSnap(c.ctx, c.redisCli, c.db, c.engine, c.authManager)
+		analyzer := hostsnap.NewHostSnap(c.ctx, c.redisCli, c.redisAvailability, c.db, c.engine,
+			c.authManager)
 
 		kafkaPorter := collections.NewKafkaPorter(snapPorterName, c.engine, c.ctx, analyzer, c.snapConsumerGroup,
 			topic, c.registry)
@@ -493,7 +499,8 @@ func (c *DataCollection) runCollectPorters() {
 
 	if c.snapRedisCli != nil {
 		topic := c.snapMessageTopic(c.defaultAppID)
-		analyzer := hostsnap.NewHostSnap(c.ctx, c.redisCli, c.db, c.engine, c.authManager)
+		analyzer := hostsnap.NewHostSnap(c.ctx, c.redisCli, c.redisAvailability, c.db, c.engine,
+			c.authManager)
 
 		porter := collections.NewSimplePorter(snapPorterName, c.engine, c.hash, analyzer, c.snapRedisCli, topic,
 			c.registry)
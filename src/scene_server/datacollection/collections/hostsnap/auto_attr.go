@@ -0,0 +1,175 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hostsnap
+
+import (
+	"strings"
+
+	"configcenter/src/common"
+	cc "configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/json"
+
+	"github.com/tidwall/gjson"
+)
+
+// the overwrite policies of the host attributes derived from the host snapshot
+const (
+	// autoAttrPolicyFillEmpty only set the derived attributes that are empty on the host, so that the values
+	// edited by the user are kept, it's the default policy.
+	autoAttrPolicyFillEmpty = "fillEmpty"
+	// autoAttrPolicyOverwrite always overwrite the host attributes with the derived values.
+	autoAttrPolicyOverwrite = "overwrite"
+	// autoAttrPolicyDisable do not derive the host attributes from the host snapshot.
+	autoAttrPolicyDisable = "disable"
+)
+
+// virtualizationPhysical is the virtualization type of the host that is not a virtualization guest
+const virtualizationPhysical = "physical"
+
+// autoAttrFields the host attributes derived from the host snapshot
+var autoAttrFields = []string{common.BKOSFamilyField, common.BKOSKernelVersionField,
+	common.BKVirtualizationTypeField, common.BKContainerRuntimeField}
+
+// getAutoAttrPolicy get the overwrite policy of the derived host attributes, the default policy is used if the
+// configured one is invalid.
+func getAutoAttrPolicy() string {
+	if !cc.IsExist("datacollection.hostsnap.autoAttrPolicy") {
+		return autoAttrPolicyFillEmpty
+	}
+
+	policy, err := cc.String("datacollection.hostsnap.autoAttrPolicy")
+	if err != nil {
+		blog.Errorf("get datacollection.hostsnap.autoAttrPolicy failed, use the default policy, err: %v", err)
+		return autoAttrPolicyFillEmpty
+	}
+
+	switch policy {
+	case autoAttrPolicyFillEmpty, autoAttrPolicyOverwrite, autoAttrPolicyDisable:
+		return policy
+	case "":
+		return autoAttrPolicyFillEmpty
+	default:
+		blog.Errorf("invalid datacollection.hostsnap.autoAttrPolicy %s, use the default policy", policy)
+		return autoAttrPolicyFillEmpty
+	}
+}
+
+// setAutoAttrs add the host attributes derived from the host snapshot to the setter with the overwrite policy, host
+// is the current host data to check whether the attributes are empty.
+func (h *HostSnap) setAutoAttrs(setter map[string]interface{}, raw string, val *gjson.Result,
+	host string) (map[string]interface{}, string) {
+
+	if h.autoAttrPolicy == autoAttrPolicyDisable {
+		return setter, raw
+	}
+
+	attrs := parseAutoAttrs(val)
+	if len(attrs) == 0 {
+		return setter, raw
+	}
+
+	hostElements := gjson.GetMany(host, autoAttrFields...)
+	rawBuilder := strings.Builder{}
+	rawBuilder.WriteString(strings.TrimSuffix(raw, "}"))
+	for idx, field := range autoAttrFields {
+		value, exists := attrs[field]
+		if !exists {
+			continue
+		}
+
+		if h.autoAttrPolicy == autoAttrPolicyFillEmpty && hostElements[idx].String() != "" {
+			continue
+		}
+
+		js, err := json.Marshal(value)
+		if err != nil {
+			blog.Errorf("marshal host snapshot derived attribute %s value %s failed, err: %v", field, value, err)
+			continue
+		}
+
+		setter[field] = value
+		if rawBuilder.Len() > 1 {
+			rawBuilder.WriteString(",")
+		}
+		rawBuilder.WriteString("\"" + field + "\":")
+		rawBuilder.Write(js)
+	}
+	rawBuilder.WriteByte('}')
+
+	return setter, rawBuilder.String()
+}
+
+// parseAutoAttrs derive the normalized host attributes from the host snapshot, the attributes that can not be
+// derived are not returned.
+func parseAutoAttrs(val *gjson.Result) map[string]string {
+	system := val.Get("data.system.info")
+	if val.Get("data.apiVer").String() == "v1.0" {
+		system = val.Get("data.system")
+	}
+
+	attrs := make(map[string]string)
+
+	// the platform family of windows is the product type, e.g. Server, so the os is used as its family
+	family := strings.ToLower(strings.TrimSpace(system.Get("platformFamily").String()))
+	if strings.ToLower(strings.TrimSpace(system.Get("os").String())) == "windows" {
+		family = "windows"
+	}
+	if family != "" {
+		attrs[common.BKOSFamilyField] = family
+	}
+
+	kernel := strings.TrimSpace(system.Get("kernelVersion").String())
+	if kernel != "" {
+		attrs[common.BKOSKernelVersionField] = kernel
+	}
+
+	// the virtualization role is host for the hypervisor, which is a physical host too.
+	if virtualization := system.Get("virtualizationSystem"); virtualization.Exists() {
+		virtType := strings.ToLower(strings.TrimSpace(virtualization.String()))
+		role := strings.ToLower(strings.TrimSpace(system.Get("virtualizationRole").String()))
+		if virtType == "" || role != "guest" {
+			virtType = virtualizationPhysical
+		}
+		attrs[common.BKVirtualizationTypeField] = virtType
+	}
+
+	// the container runtime is detected only if the network interfaces are reported, empty means there is none.
+	if val.Get("data.net.interface").Exists() {
+		attrs[common.BKContainerRuntimeField] = parseContainerRuntime(val)
+	}
+
+	return attrs
+}
+
+// parseContainerRuntime detect the container runtime by its network bridge and data directory, docker is preferred
+// since it runs on containerd.
+func parseContainerRuntime(val *gjson.Result) string {
+	for _, name := range val.Get("data.net.interface.#.name").Array() {
+		if name.String() == "docker0" {
+			return "docker"
+		}
+	}
+
+	runtime := ""
+	for _, mountPoint := range val.Get("data.disk.partition.#.mountpoint").Array() {
+		switch path := mountPoint.String(); {
+		case strings.HasPrefix(path, "/var/lib/docker"):
+			return "docker"
+		case strings.HasPrefix(path, "/var/lib/containerd"), strings.HasPrefix(path, "/run/containerd"):
+			runtime = "containerd"
+		}
+	}
+
+	return runtime
+}
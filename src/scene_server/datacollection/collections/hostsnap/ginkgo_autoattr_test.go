@@ -0,0 +1,81 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hostsnap
+
+import (
+	"configcenter/src/common"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tidwall/gjson"
+)
+
+const autoAttrJSON = `{
+    "data": {
+        "system": {
+            "info": {
+                "os": "linux",
+                "platformFamily": "RHEL",
+                "kernelVersion": " 3.10.0-1160.el7.x86_64 ",
+                "virtualizationSystem": "kvm",
+                "virtualizationRole": "guest"
+            }
+        },
+        "net": {
+            "interface": [{"name": "eth0"}, {"name": "cni0"}]
+        },
+        "disk": {
+            "partition": [{"mountpoint": "/"}, {"mountpoint": "/run/containerd/io.containerd.runtime.v2.task/k8s.io"}]
+        }
+    }
+}`
+
+var _ = Describe("Hostsnap auto attributes", func() {
+	Context("parse auto attributes", func() {
+		It("", func() {
+			gson := gjson.Parse(autoAttrJSON)
+			attrs := parseAutoAttrs(&gson)
+
+			Expect(attrs).To(Equal(map[string]string{
+				common.BKOSFamilyField:           "rhel",
+				common.BKOSKernelVersionField:    "3.10.0-1160.el7.x86_64",
+				common.BKVirtualizationTypeField: "kvm",
+				common.BKContainerRuntimeField:   "containerd",
+			}))
+		})
+	})
+
+	Context("set auto attributes with policy", func() {
+		It("", func() {
+			gson := gjson.Parse(autoAttrJSON)
+			host := `{"bk_os_family":"centos","bk_os_kernel_version":""}`
+
+			h := &HostSnap{autoAttrPolicy: autoAttrPolicyFillEmpty}
+			setter, raw := h.setAutoAttrs(map[string]interface{}{"bk_cpu": 4}, `{"bk_cpu":4}`, &gson, host)
+			Expect(setter).NotTo(HaveKey(common.BKOSFamilyField))
+			Expect(setter[common.BKOSKernelVersionField]).To(Equal("3.10.0-1160.el7.x86_64"))
+			Expect(gjson.Valid(raw)).To(BeTrue())
+			Expect(gjson.Get(raw, common.BKVirtualizationTypeField).String()).To(Equal("kvm"))
+			Expect(needToUpdate(raw, host)).To(BeTrue())
+
+			h.autoAttrPolicy = autoAttrPolicyOverwrite
+			setter, _ = h.setAutoAttrs(make(map[string]interface{}), "{}", &gson, host)
+			Expect(setter[common.BKOSFamilyField]).To(Equal("rhel"))
+
+			h.autoAttrPolicy = autoAttrPolicyDisable
+			setter, raw = h.setAutoAttrs(make(map[string]interface{}), "{}", &gson, host)
+			Expect(setter).To(BeEmpty())
+			Expect(raw).To(Equal("{}"))
+		})
+	})
+})
@@ -35,6 +35,7 @@ import (
 	"configcenter/src/common/json"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/script"
 	"configcenter/src/common/util"
 	"configcenter/src/storage/dal"
 	"configcenter/src/storage/dal/redis"
@@ -51,14 +52,18 @@ const (
 	defaultRateLimiterQPS = 40
 	// defaultRateLimiterBurst is the default value of rateLimiter burst
 	defaultRateLimiterBurst = 100
+	// defaultDegradedRateLimiterQPS is the default qps of the snapshots analyzed while the redis is unavailable
+	defaultDegradedRateLimiterQPS = 10
+	// defaultDegradedRateLimiterBurst is the default burst of the snapshots analyzed while the redis is unavailable
+	defaultDegradedRateLimiterBurst = 20
 	// redisConsumptionCheckPrefix redis consumption check prefix
 	redisConsumptionCheckPrefix = "consumptionCheck:"
 )
 
 var (
 	// 需要参与变化对比的字段
-	compareFields = []string{"bk_cpu", "bk_cpu_module", "bk_disk", "bk_mem", "bk_os_type", "bk_os_name",
-		"bk_os_version", "bk_host_name", "bk_outer_mac", "bk_mac", "bk_os_bit"}
+	compareFields = append([]string{"bk_cpu", "bk_cpu_module", "bk_disk", "bk_mem", "bk_os_type", "bk_os_name",
+		"bk_os_version", "bk_host_name", "bk_outer_mac", "bk_mac", "bk_os_bit"}, autoAttrFields...)
 	reqireFields = append(compareFields, "bk_host_id", "bk_host_innerip", "bk_host_outerip")
 
 	// notice: 为了对应不同版本和环境差异，再当前版本中设置compareFields中不参加对比的字段
@@ -75,12 +80,21 @@ type HostSnap struct {
 	ctx       context.Context
 	db        dal.RDB
 	window    *Window
+	// transformHook the hook to transform the host snapshot fields before updating the host, nil if not configured
+	transformHook *script.Hook
+	// autoAttrPolicy the overwrite policy of the host attributes derived from the host snapshot
+	autoAttrPolicy string
+	// redisAvailability the availability of the redis, the snapshot is not buffered in the redis and the analysis is
+	// limited by the degradedRateLimit while the redis is unavailable, because the hosts are got from mongodb directly.
+	redisAvailability *redis.Availability
+	degradedRateLimit flowctrl.RateLimiter
 }
 
 // NewHostSnap new hostsnap
-func NewHostSnap(ctx context.Context, redisCli redis.Client, db dal.RDB, engine *backbone.Engine,
-	authManager *extensions.AuthManager) *HostSnap {
+func NewHostSnap(ctx context.Context, redisCli redis.Client, redisAvailability *redis.Availability, db dal.RDB,
+	engine *backbone.Engine, authManager *extensions.AuthManager) *HostSnap {
 	qps, burst := getRateLimiterConfig()
+	degradedQPS, degradedBurst := getDegradedRateLimiterConfig()
 	h := &HostSnap{
 		redisCli:    redisCli,
 		ctx:         ctx,
@@ -91,9 +105,33 @@ func NewHostSnap(ctx context.Context, redisCli redis.Client, db dal.RDB, engine
 		filter:      newFilter(),
 		window:      newWindow(),
 	}
+	h.transformHook = getTransformHook()
+	h.autoAttrPolicy = getAutoAttrPolicy()
+	h.redisAvailability = redisAvailability
+	h.degradedRateLimit = flowctrl.NewRateLimiter(int64(degradedQPS), int64(degradedBurst))
 	return h
 }
 
+// getTransformHook compile the host snapshot transform hook script in the configured file
+func getTransformHook() *script.Hook {
+	if !cc.IsExist("datacollection.hostsnap.transformScript") {
+		return nil
+	}
+
+	file, err := cc.String("datacollection.hostsnap.transformScript")
+	if err != nil || len(file) == 0 {
+		return nil
+	}
+
+	hook, err := script.NewHookFromFile("hostsnap", file, script.DefaultLimits)
+	if err != nil {
+		blog.Errorf("load host snapshot transform hook failed, skip transforming, err: %v", err)
+		return nil
+	}
+	blog.Infof("host snapshot transform hook is loaded from %s", file)
+	return hook
+}
+
 func getRateLimiterConfig() (int, int) {
 	qps, err := cc.Int("datacollection.hostsnap.rateLimiter.qps")
 	if err != nil {
@@ -110,6 +148,18 @@ func getRateLimiterConfig() (int, int) {
 	return qps, burst
 }
 
+// getDegradedRateLimiterConfig returns the qps and burst of the snapshots analyzed while the redis is unavailable
+func getDegradedRateLimiterConfig() (int, int) {
+	qps := getLimitConfig("datacollection.hostsnap.degradedRateLimiter.qps", defaultDegradedRateLimiterQPS, 1)
+	burst := getLimitConfig("datacollection.hostsnap.degradedRateLimiter.burst", defaultDegradedRateLimiterBurst, 1)
+	return qps, burst
+}
+
+// redisAvailable returns whether the redis is available, the redis buffers are skipped if it is not
+func (h *HostSnap) redisAvailable() bool {
+	return h.redisAvailability.Available()
+}
+
 // Hash returns hash value base on message.
 func (h *HostSnap) Hash(cloudid, ip string) (string, error) {
 	if len(cloudid) == 0 {
@@ -162,6 +212,15 @@ func (h *HostSnap) Analyze(msg *string) (bool, error) {
 
 	header, rid := newHeaderWithRid()
 
+	// the hosts are got from mongodb directly while the redis is unavailable, the snapshots exceeding the degraded
+	// limit are dropped, they are collected periodically so the host is updated by the following snapshots.
+	if !h.redisAvailable() && !h.degradedRateLimit.TryAccept() {
+		if blog.V(4) {
+			blog.Infof("redis is unavailable, skip host snapshot due to degraded request limit, rid: %s", rid)
+		}
+		return false, nil
+	}
+
 	val := gjson.Parse(data)
 	cloudID := val.Get("cloudid").Int()
 	ips := getIPS(&val)
@@ -195,6 +254,7 @@ func (h *HostSnap) Analyze(msg *string) (bool, error) {
 	if !val.Get("data.apiVer").Exists() {
 		h.saveHostsnap(header, &val, hostID)
 	}
+	h.saveAgentLastSeen(hostID, rid)
 
 	// window restriction on request when no apiVer information reported
 	if !val.Get("data.apiVer").Exists() && !h.window.canPassWindow() {
@@ -209,7 +269,20 @@ func (h *HostSnap) Analyze(msg *string) (bool, error) {
 		return false, nil
 	}
 
+	// the host snapshot is collected periodically, so the newer collected data wins the conflicts with the other
+	// ingestion paths.
+	importCtx := &metadata.ImportContext{
+		Source: metadata.ImportSourceHostSnapshot,
+		Policy: metadata.ImportConflictNewestWins,
+	}
+	if timestamp := val.Get("data.timestamp").Int(); timestamp > 0 {
+		importCtx.DataTime = time.Unix(timestamp, 0)
+	}
+	metadata.SetImportContext(header, importCtx)
+
 	setter, raw := parseSetter(&val, innerIP, outerIP)
+	setter, raw = h.setAutoAttrs(setter, raw, &val, host)
+	setter, raw = h.transformSetter(setter, raw, hostID, rid)
 	// no need to update
 	if !needToUpdate(raw, host) {
 		return false, nil
@@ -289,12 +362,42 @@ func (h *HostSnap) Analyze(msg *string) (bool, error) {
 	return false, nil
 }
 
+// transformSetter transform the host snapshot fields with the transform hook, the original fields are used if the
+// hook is not configured or failed.
+func (h *HostSnap) transformSetter(setter map[string]interface{}, raw string, hostID int64,
+	rid string) (map[string]interface{}, string) {
+
+	if h.transformHook == nil {
+		return setter, raw
+	}
+
+	transformed, err := h.transformHook.Transform(setter)
+	if err != nil {
+		blog.Errorf("transform host %d snapshot failed, use the original fields, err: %v, rid: %s", hostID, err, rid)
+		return setter, raw
+	}
+
+	js, err := json.Marshal(transformed)
+	if err != nil {
+		blog.Errorf("marshal host %d transformed snapshot failed, use the original fields, err: %v, rid: %s",
+			hostID, err, rid)
+		return setter, raw
+	}
+
+	return transformed, string(js)
+}
+
 // skipMsg verify the timestamp to determine whether the host sequence is correct, if it is old message, skip.
 func (h *HostSnap) skipMsg(val gjson.Result, innerIP, rid string, hostID, cloudID int64) bool {
 	if !val.Get("data.apiVer").Exists() {
 		return false
 	}
 
+	// the consumption order can not be checked without redis, the snapshot is updated anyway
+	if !h.redisAvailable() {
+		return false
+	}
+
 	key := redisConsumptionCheckPrefix + innerIP + ":" + strconv.FormatInt(cloudID, 10)
 	timestamp, err := h.redisCli.Get(context.Background(), key).Result()
 	if err != nil && !redis.IsNilErr(err) {
@@ -843,6 +946,11 @@ func getIPS(val *gjson.Result) []string {
 func (h *HostSnap) saveHostsnap(header http.Header, hostData *gjson.Result, hostID int64) error {
 	rid := util.GetHTTPCCRequestID(header)
 
+	if !h.redisAvailable() {
+		blog.V(4).Infof("redis is unavailable, skip saving host %d snapshot, rid: %s", hostID, rid)
+		return nil
+	}
+
 	snapshot, err := ParseHostSnap(hostData)
 	if err != nil {
 		blog.Errorf("saveHostsnap failed, ParseHostSnap err: %v, hostID:%v, rid:%s", err, hostID, rid)
@@ -858,6 +966,20 @@ func (h *HostSnap) saveHostsnap(header http.Header, hostData *gjson.Result, host
 	return nil
 }
 
+// saveAgentLastSeen record the time that the host's agent reports the snapshot, it is used by the host recycle
+// policies to find out the hosts whose agent is offline for a long time.
+func (h *HostSnap) saveAgentLastSeen(hostID int64, rid string) {
+	if !h.redisAvailable() {
+		return
+	}
+
+	field := strconv.FormatInt(hostID, 10)
+	err := h.redisCli.HSet(context.Background(), common.RedisAgentLastSeenKey, field, time.Now().Unix()).Err()
+	if err != nil {
+		blog.Errorf("save host %d agent last seen time failed, err: %v, rid: %s", hostID, err, rid)
+	}
+}
+
 func newHeaderWithRid() (http.Header, string) {
 	header := http.Header{}
 	header.Add(common.BKHTTPOwnerID, common.BKDefaultOwnerID)
@@ -16,6 +16,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"configcenter/src/common"
 	"configcenter/src/common/backbone"
@@ -45,6 +46,9 @@ type Service struct {
 	disCli  redis.Client
 	netCli  redis.Client
 
+	// cacheAvailability the availability of cc main redis, the health is degraded while it is unavailable
+	cacheAvailability *redis.Availability
+
 	logics *logics.Logics
 }
 
@@ -63,9 +67,10 @@ func (s *Service) SetDB(db dal.RDB) {
 	s.db = db
 }
 
-// SetCache setups cc main redis.
-func (s *Service) SetCache(db redis.Client) {
+// SetCache setups cc main redis and its availability.
+func (s *Service) SetCache(db redis.Client, availability *redis.Availability) {
 	s.cache = db
+	s.cacheAvailability = availability
 }
 
 // SetSnapCli setups snap redis.
@@ -158,8 +163,16 @@ func (s *Service) Healthz(req *restful.Request, resp *restful.Response) {
 	// mongodb health status info.
 	meta.Items = append(meta.Items, metric.NewHealthItem(types.CCFunctionalityMongo, s.db.Ping()))
 
-	// cc main redis health status info.
-	meta.Items = append(meta.Items, metric.NewHealthItem(types.CCFunctionalityRedis, s.cache.Ping(context.Background()).Err()))
+	// cc main redis health status info, the host snapshots are analyzed without the redis buffers while it is
+	// unavailable, so it is degraded.
+	if available, since, err := s.cacheAvailability.Status(); !available {
+		meta.Items = append(meta.Items, metric.NewDegradedHealthItem(types.CCFunctionalityRedis,
+			fmt.Errorf("redis is unavailable since %s, host snapshots are analyzed with reduced throughput, err: %v",
+				since.Format(time.RFC3339), err)))
+	} else {
+		meta.Items = append(meta.Items, metric.NewHealthItem(types.CCFunctionalityRedis,
+			s.cache.Ping(context.Background()).Err()))
+	}
 
 	// snap redis health status info.
 	if s.snapCli != nil {
@@ -182,6 +195,10 @@ func (s *Service) Healthz(req *restful.Request, resp *restful.Response) {
 			meta.Message = "datacolection server is unhealthy"
 			break
 		}
+		if item.Degraded {
+			meta.Degraded = true
+			meta.Message = "datacolection server is degraded"
+		}
 	}
 
 	info := metric.HealthInfo{
@@ -29,6 +29,7 @@ import (
 	"configcenter/src/common/types"
 	"configcenter/src/scene_server/event_server/app/options"
 	svc "configcenter/src/scene_server/event_server/service"
+	"configcenter/src/scene_server/event_server/sync/auditstream"
 	"configcenter/src/scene_server/event_server/sync/hostidentifier"
 	"configcenter/src/storage/dal"
 	"configcenter/src/storage/dal/mongo/local"
@@ -180,6 +181,12 @@ func (es *EventServer) initConfigs() error {
 		return err
 	}
 
+	es.config.AuditStreamConf, err = auditstream.ParseAuditStreamConf()
+	if err != nil {
+		blog.Errorf("parse eventServer audit stream config error, err: %v", err)
+		return err
+	}
+
 	identifierConf, err := hostidentifier.ParseIdentifierConf()
 	if err != nil {
 		blog.Errorf("parse eventServer host identifier config error, err: %v", err)
@@ -261,6 +268,29 @@ func (es *EventServer) Run() error {
 	if err := es.runSyncData(); err != nil {
 		return err
 	}
+
+	if err := es.runAuditStream(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// runAuditStream streams the audit logs to the external sink if it is enabled
+func (es *EventServer) runAuditStream() error {
+	if !es.config.AuditStreamConf.StartUp {
+		return nil
+	}
+
+	sink, err := auditstream.NewSink(es.config.AuditStreamConf)
+	if err != nil {
+		blog.Errorf("new audit stream sink error, err: %v", err)
+		return err
+	}
+
+	stream := auditstream.NewAuditStream(es.ctx, es.engine, es.db, es.redisCli, es.config.AuditStreamConf, sink)
+	go stream.Run()
+
+	blog.Info("run audit stream success!")
 	return nil
 }
 
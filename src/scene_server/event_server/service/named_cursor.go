@@ -0,0 +1,457 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/watch"
+	"configcenter/src/storage/dal/redis"
+)
+
+const (
+	namedCursorKeyPrefix      = common.BKCacheKeyV3Prefix + "event:named_cursor:"
+	namedCursorLeaseKeyPrefix = common.BKCacheKeyV3Prefix + "event:named_cursor_lease:"
+
+	namedCursorField    = "bk_cursor"
+	namedStartFromField = "bk_start_from"
+	namedCreateField    = "create_time"
+	namedUpdateField    = "update_time"
+)
+
+// registerNamedCursorScript acquire the lease of the named cursor if it is not owned by another owner, and create
+// the cursor starting from now if it does not exist. returns 0 if the cursor is owned by another owner.
+// KEYS[1]: lease key, KEYS[2]: cursor key, ARGV[1]: owner, ARGV[2]: lease seconds, ARGV[3]: now unix seconds.
+const registerNamedCursorScript = `
+local owner = redis.call('GET', KEYS[1])
+if owner and owner ~= ARGV[1] then
+	return 0
+end
+
+redis.call('SET', KEYS[1], ARGV[1], 'EX', ARGV[2])
+if redis.call('HSETNX', KEYS[2], 'create_time', ARGV[3]) == 1 then
+	redis.call('HMSET', KEYS[2], 'bk_cursor', '', 'bk_start_from', ARGV[3], 'update_time', ARGV[3])
+end
+return 1
+`
+
+// renewNamedCursorScript renew the lease of the named cursor, returns 0 if the lease is not held by the owner.
+// KEYS[1]: lease key, ARGV[1]: owner, ARGV[2]: lease seconds.
+const renewNamedCursorScript = `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+
+redis.call('EXPIRE', KEYS[1], ARGV[2])
+return 1
+`
+
+// updateNamedCursorScript update the named cursor and renew the owner's lease, the lease must be held by the owner
+// if ARGV[3] is '1', otherwise the free lease is acquired by the owner. returns 0 if the lease can not be held by
+// the owner, returns -1 if the cursor does not exist.
+// KEYS[1]: lease key, KEYS[2]: cursor key, ARGV[1]: owner, ARGV[2]: lease seconds, ARGV[3]: must hold the lease,
+// ARGV[4]: cursor, ARGV[5]: start from, ARGV[6]: now unix seconds.
+const updateNamedCursorScript = `
+local owner = redis.call('GET', KEYS[1])
+if owner ~= ARGV[1] and (owner or ARGV[3] == '1') then
+	return 0
+end
+
+if redis.call('EXISTS', KEYS[2]) == 0 then
+	return -1
+end
+
+redis.call('SET', KEYS[1], ARGV[1], 'EX', ARGV[2])
+redis.call('HMSET', KEYS[2], 'bk_cursor', ARGV[4], 'bk_start_from', ARGV[5], 'update_time', ARGV[6])
+return 1
+`
+
+// releaseNamedCursorScript release the lease of the named cursor held by the owner, and delete the cursor if
+// ARGV[2] is '1', the free cursor can be deleted by anyone. returns 0 if the cursor is owned by another owner.
+// KEYS[1]: lease key, KEYS[2]: cursor key, ARGV[1]: owner, ARGV[2]: whether to delete the cursor.
+const releaseNamedCursorScript = `
+local owner = redis.call('GET', KEYS[1])
+if owner and owner ~= ARGV[1] then
+	return 0
+end
+
+redis.call('DEL', KEYS[1])
+if ARGV[2] == '1' then
+	redis.call('DEL', KEYS[2])
+end
+return 1
+`
+
+// RegisterNamedCursor register the named cursor of the resource by the consumer, the consumer owns the cursor until
+// its lease expires, other consumers can not register the cursor in the meantime.
+func (s *Service) RegisterNamedCursor(ctx *rest.Contexts) {
+	resource, name, err := parseNamedCursorPath(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	lease := new(watch.NamedCursorLease)
+	if err := ctx.DecodeInto(lease); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if err := lease.Validate(); err != nil {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, err.Error()))
+		return
+	}
+
+	keys := namedCursorKeys(resource, name)
+	result, err := s.evalNamedCursorScript(ctx.Kit, registerNamedCursorScript, keys, lease.Owner,
+		lease.LeaseSeconds, time.Now().Unix())
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if result == 0 {
+		ctx.RespAutoError(s.namedCursorOwnedError(ctx.Kit, resource, name))
+		return
+	}
+
+	cursor, err := s.getNamedCursor(ctx.Kit, resource, name)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(cursor)
+}
+
+// FindNamedCursor find the named cursor of the resource with its owner and lease
+func (s *Service) FindNamedCursor(ctx *rest.Contexts) {
+	resource, name, err := parseNamedCursorPath(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	cursor, err := s.getNamedCursor(ctx.Kit, resource, name)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(cursor)
+}
+
+// WatchNamedCursor watch the events from the named cursor by its owner, the owner's lease is renewed. the cursor is
+// not advanced until the owner advances it after the events are processed, so that no event is lost if the owner
+// crashes in the middle.
+func (s *Service) WatchNamedCursor(ctx *rest.Contexts) {
+	resource, name, err := parseNamedCursorPath(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	opts := new(watch.WatchNamedCursorOptions)
+	if err := ctx.DecodeInto(opts); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if err := opts.NamedCursorLease.Validate(); err != nil {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, err.Error()))
+		return
+	}
+
+	keys := namedCursorKeys(resource, name)
+	result, err := s.evalNamedCursorScript(ctx.Kit, renewNamedCursorScript, keys[:1], opts.Owner, opts.LeaseSeconds)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if result == 0 {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrEventNamedCursorLeaseLost, opts.Owner, name))
+		return
+	}
+
+	cursor, err := s.getNamedCursor(ctx.Kit, resource, name)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	watchOpts := &watch.WatchEventOptions{
+		EventTypes:    opts.EventTypes,
+		Fields:        opts.Fields,
+		Cursor:        cursor.Cursor,
+		Resource:      resource,
+		Filter:        opts.Filter,
+		SchemaVersion: opts.SchemaVersion,
+	}
+	if len(cursor.Cursor) == 0 {
+		watchOpts.StartFrom = cursor.StartFrom
+	}
+
+	if err := watchOpts.Validate(); err != nil {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, err.Error()))
+		return
+	}
+
+	resp, err := s.engine.CoreAPI.CacheService().Cache().Event().WatchEvent(ctx.Kit.Ctx, ctx.Kit.Header, watchOpts)
+	if err != nil {
+		blog.Errorf("watch named cursor %s of %s failed, err: %v, rid: %s", name, resource, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespString(resp)
+}
+
+// AdvanceNamedCursor advance the named cursor to the cursor of the last processed event by its owner, the owner's
+// lease is renewed.
+func (s *Service) AdvanceNamedCursor(ctx *rest.Contexts) {
+	resource, name, err := parseNamedCursorPath(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	opt := new(watch.AdvanceNamedCursorOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if err := opt.Validate(); err != nil {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, err.Error()))
+		return
+	}
+
+	keys := namedCursorKeys(resource, name)
+	result, err := s.evalNamedCursorScript(ctx.Kit, updateNamedCursorScript, keys, opt.Owner, opt.LeaseSeconds, "1",
+		opt.Cursor, 0, time.Now().Unix())
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	switch result {
+	case 0:
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrEventNamedCursorLeaseLost, opt.Owner, name))
+		return
+	case -1:
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrEventNamedCursorNotExist, name))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// SeekNamedCursor seek the named cursor to the cursor or the start time, or reset it to the current time if both of
+// them are not set. the cursor can be sought by its owner, or by anyone if it is not owned, who then owns it.
+func (s *Service) SeekNamedCursor(ctx *rest.Contexts) {
+	resource, name, err := parseNamedCursorPath(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	opt := new(watch.SeekNamedCursorOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if err := opt.Validate(); err != nil {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, err.Error()))
+		return
+	}
+
+	now := time.Now().Unix()
+	startFrom := opt.StartFrom
+	if len(opt.Cursor) == 0 && startFrom == 0 {
+		startFrom = now
+	}
+
+	keys := namedCursorKeys(resource, name)
+	result, err := s.evalNamedCursorScript(ctx.Kit, updateNamedCursorScript, keys, opt.Owner, opt.LeaseSeconds, "0",
+		opt.Cursor, startFrom, now)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	switch result {
+	case 0:
+		ctx.RespAutoError(s.namedCursorOwnedError(ctx.Kit, resource, name))
+		return
+	case -1:
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrEventNamedCursorNotExist, name))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// ReleaseNamedCursor release the lease of the named cursor by its owner, so that another consumer can take it over
+// without waiting for the lease to expire.
+func (s *Service) ReleaseNamedCursor(ctx *rest.Contexts) {
+	s.releaseNamedCursor(ctx, false)
+}
+
+// DeleteNamedCursor delete the named cursor by its owner, or by anyone if it is not owned.
+func (s *Service) DeleteNamedCursor(ctx *rest.Contexts) {
+	s.releaseNamedCursor(ctx, true)
+}
+
+func (s *Service) releaseNamedCursor(ctx *rest.Contexts, isDelete bool) {
+	resource, name, err := parseNamedCursorPath(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	lease := new(watch.NamedCursorLease)
+	if err := ctx.DecodeInto(lease); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if err := lease.Validate(); err != nil {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, err.Error()))
+		return
+	}
+
+	deleteFlag := "0"
+	if isDelete {
+		deleteFlag = "1"
+	}
+
+	keys := namedCursorKeys(resource, name)
+	result, err := s.evalNamedCursorScript(ctx.Kit, releaseNamedCursorScript, keys, lease.Owner, deleteFlag)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if result == 0 {
+		ctx.RespAutoError(s.namedCursorOwnedError(ctx.Kit, resource, name))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// parseNamedCursorPath parse the resource and the name of the named cursor from the request path
+func parseNamedCursorPath(ctx *rest.Contexts) (watch.CursorType, string, error) {
+	resource := watch.CursorType(ctx.Request.PathParameter("resource"))
+	valid := false
+	for _, typ := range watch.ListCursorTypes() {
+		if typ == resource {
+			valid = true
+			break
+		}
+	}
+
+	if !valid {
+		blog.Errorf("named cursor resource %s is invalid, rid: %s", resource, ctx.Kit.Rid)
+		return "", "", ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "resource")
+	}
+
+	name := ctx.Request.PathParameter("name")
+	if err := watch.ValidateNamedCursorName(name); err != nil {
+		blog.Errorf("named cursor name is invalid, err: %v, rid: %s", err, ctx.Kit.Rid)
+		return "", "", ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	return resource, name, nil
+}
+
+// namedCursorKeys returns the lease key and the cursor key of the named cursor
+func namedCursorKeys(resource watch.CursorType, name string) []string {
+	suffix := string(resource) + ":" + name
+	return []string{namedCursorLeaseKeyPrefix + suffix, namedCursorKeyPrefix + suffix}
+}
+
+func (s *Service) evalNamedCursorScript(kit *rest.Kit, script string, keys []string, args ...interface{}) (int64,
+	error) {
+
+	result, err := s.cache.Eval(kit.Ctx, script, keys, args...).Result()
+	if err != nil {
+		blog.Errorf("eval named cursor script failed, keys: %v, args: %v, err: %v, rid: %s", keys, args, err,
+			kit.Rid)
+		return 0, kit.CCError.CCError(common.CCErrCommRedisOPErr)
+	}
+
+	count, ok := result.(int64)
+	if !ok {
+		blog.Errorf("named cursor script result %#v is invalid, keys: %v, rid: %s", result, keys, kit.Rid)
+		return 0, kit.CCError.CCError(common.CCErrCommRedisOPErr)
+	}
+
+	return count, nil
+}
+
+// getNamedCursor get the named cursor with its current owner and the remaining lease
+func (s *Service) getNamedCursor(kit *rest.Kit, resource watch.CursorType, name string) (*watch.NamedCursor,
+	error) {
+
+	keys := namedCursorKeys(resource, name)
+	fields, err := s.cache.HGetAll(kit.Ctx, keys[1]).Result()
+	if err != nil {
+		blog.Errorf("get named cursor %s failed, err: %v, rid: %s", keys[1], err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommRedisOPErr)
+	}
+
+	if len(fields) == 0 {
+		return nil, kit.CCError.CCErrorf(common.CCErrEventNamedCursorNotExist, name)
+	}
+
+	cursor := &watch.NamedCursor{Name: name, Resource: resource, Cursor: fields[namedCursorField]}
+	cursor.StartFrom, _ = strconv.ParseInt(fields[namedStartFromField], 10, 64)
+	cursor.CreateTime, _ = strconv.ParseInt(fields[namedCreateField], 10, 64)
+	cursor.UpdateTime, _ = strconv.ParseInt(fields[namedUpdateField], 10, 64)
+
+	owner, err := s.cache.Get(kit.Ctx, keys[0]).Result()
+	if err != nil && !redis.IsNilErr(err) {
+		blog.Errorf("get named cursor lease %s failed, err: %v, rid: %s", keys[0], err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommRedisOPErr)
+	}
+	cursor.Owner = owner
+
+	if len(owner) > 0 {
+		ttl, err := s.cache.TTL(kit.Ctx, keys[0]).Result()
+		if err != nil {
+			blog.Errorf("get named cursor lease %s ttl failed, err: %v, rid: %s", keys[0], err, kit.Rid)
+			return nil, kit.CCError.CCError(common.CCErrCommRedisOPErr)
+		}
+		cursor.LeaseSeconds = int64(ttl / time.Second)
+	}
+
+	return cursor, nil
+}
+
+// namedCursorOwnedError returns the error that the named cursor is owned by another owner
+func (s *Service) namedCursorOwnedError(kit *rest.Kit, resource watch.CursorType, name string) error {
+	owner, err := s.cache.Get(kit.Ctx, namedCursorKeys(resource, name)[0]).Result()
+	if err != nil && !redis.IsNilErr(err) {
+		blog.Errorf("get named cursor %s owner failed, err: %v, rid: %s", name, err, kit.Rid)
+	}
+
+	blog.Errorf("named cursor %s of %s is owned by %s, rid: %s", name, resource, owner, kit.Rid)
+	return kit.CCError.CCErrorf(common.CCErrEventNamedCursorOwned, name, owner)
+}
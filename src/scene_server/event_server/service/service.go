@@ -107,9 +107,27 @@ func (s *Service) initService(web *restful.WebService) {
 	})
 
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/watch/resource/{resource}", Handler: s.WatchEvent})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/watch/changes/resource/{resource}",
+		Handler: s.ListChanges})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/sync/host_identifier", Handler: s.SyncHostIdentifier})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/push/host_identifier", Handler: s.PushHostIdentifier})
 
+	// named cursor apis, the cursor is stored by the event server on behalf of the consumer
+	utility.AddHandler(rest.Action{Verb: http.MethodPost,
+		Path: "/watch/named_cursor/register/resource/{resource}/name/{name}", Handler: s.RegisterNamedCursor})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost,
+		Path: "/watch/named_cursor/find/resource/{resource}/name/{name}", Handler: s.FindNamedCursor})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost,
+		Path: "/watch/named_cursor/events/resource/{resource}/name/{name}", Handler: s.WatchNamedCursor})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut,
+		Path: "/watch/named_cursor/advance/resource/{resource}/name/{name}", Handler: s.AdvanceNamedCursor})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut,
+		Path: "/watch/named_cursor/seek/resource/{resource}/name/{name}", Handler: s.SeekNamedCursor})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost,
+		Path: "/watch/named_cursor/release/resource/{resource}/name/{name}", Handler: s.ReleaseNamedCursor})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete,
+		Path: "/watch/named_cursor/delete/resource/{resource}/name/{name}", Handler: s.DeleteNamedCursor})
+
 	utility.AddToRestfulWebService(web)
 
 }
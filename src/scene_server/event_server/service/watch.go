@@ -42,3 +42,24 @@ func (s *Service) WatchEvent(ctx *rest.Contexts) {
 
 	ctx.RespString(resp)
 }
+
+// ListChanges lists the ids of the resources changed since the token, it does not hold the request like watching, so
+// that the batch consumers can sync the changes periodically.
+func (s *Service) ListChanges(ctx *rest.Contexts) {
+	options := new(watch.ListChangesOptions)
+	if err := ctx.DecodeInto(&options); err != nil {
+		blog.Errorf("list changes, but decode request body failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.Error(common.CCErrCommJSONUnmarshalFailed))
+		return
+	}
+	options.Resource = watch.CursorType(ctx.Request.PathParameter("resource"))
+
+	resp, err := s.engine.CoreAPI.CacheService().Cache().Event().ListChanges(ctx.Kit.Ctx, ctx.Kit.Header, options)
+	if err != nil {
+		blog.Errorf("list changes, but call cache service failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(resp)
+}
@@ -0,0 +1,185 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditstream
+
+import (
+	"errors"
+	"fmt"
+
+	cc "configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/blog"
+	"configcenter/src/storage/dal/kafka"
+)
+
+const (
+	// SinkTypeKafka streams the audit logs to the kafka topic
+	SinkTypeKafka = "kafka"
+	// SinkTypeHTTP streams the audit logs to the https collector
+	SinkTypeHTTP = "http"
+
+	defaultBatchSize          = 200
+	maxBatchSize              = 1000
+	defaultIntervalSeconds    = 3
+	defaultDelaySeconds       = 5
+	defaultMaxRetry           = 5
+	defaultHTTPTimeoutSeconds = 10
+)
+
+// AuditStreamConf audit log stream config
+type AuditStreamConf struct {
+	StartUp bool
+	// SinkType the type of the sink that the audit logs are streamed to, kafka or http
+	SinkType string
+	Kafka    kafka.Config
+	HTTP     *HTTPSinkConf
+	// BatchSize the max number of the audit logs sent to the sink at a time
+	BatchSize int
+	// IntervalSeconds the seconds to wait before next poll when there's no more audit logs
+	IntervalSeconds int
+	// DelaySeconds only the audit logs operated before this seconds are streamed, so that the audit logs whose
+	// transaction commits late are not skipped since they are streamed in the order of id.
+	DelaySeconds int
+	// MaxRetry the max retry times of sending a batch of audit logs, the batch is retried in the next poll if all
+	// the retries are failed, the cursor is not moved until the batch is sent.
+	MaxRetry int
+	// Actions only the audit logs of these actions are streamed, all actions are streamed if it is empty
+	Actions []string
+	// ResourceTypes only the audit logs of these resource types are streamed, all resource types are streamed if it
+	// is empty
+	ResourceTypes []string
+}
+
+// HTTPSinkConf the https collector config
+type HTTPSinkConf struct {
+	URL            string
+	Token          string
+	TimeoutSeconds int
+}
+
+// ParseAuditStreamConf parse audit log stream config
+func ParseAuditStreamConf() (*AuditStreamConf, error) {
+	if !cc.IsExist("eventServer.auditStream.startUp") {
+		return &AuditStreamConf{StartUp: false}, nil
+	}
+
+	startUp, err := cc.Bool("eventServer.auditStream.startUp")
+	if err != nil {
+		blog.Errorf("get eventServer.auditStream.startUp error, err: %v", err)
+		return nil, err
+	}
+
+	if !startUp {
+		blog.Warnf("eventServer.auditStream.startUp is false, will not stream audit logs")
+		return &AuditStreamConf{StartUp: startUp}, nil
+	}
+
+	conf := &AuditStreamConf{
+		StartUp:         startUp,
+		BatchSize:       defaultBatchSize,
+		IntervalSeconds: defaultIntervalSeconds,
+		DelaySeconds:    defaultDelaySeconds,
+		MaxRetry:        defaultMaxRetry,
+	}
+
+	conf.SinkType, err = cc.String("eventServer.auditStream.sink")
+	if err != nil {
+		blog.Errorf("get eventServer.auditStream.sink error, err: %v", err)
+		return nil, err
+	}
+
+	switch conf.SinkType {
+	case SinkTypeKafka:
+		conf.Kafka, err = cc.Kafka("kafka.auditStream")
+		if err != nil {
+			blog.Errorf("get kafka.auditStream config error, err: %v", err)
+			return nil, err
+		}
+		if len(conf.Kafka.Brokers) == 0 || conf.Kafka.Topic == "" {
+			return nil, errors.New("kafka.auditStream brokers and topic must be set")
+		}
+
+	case SinkTypeHTTP:
+		conf.HTTP, err = parseHTTPSinkConf()
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("eventServer.auditStream.sink %s is invalid", conf.SinkType)
+	}
+
+	intFields := map[string]*int{
+		"eventServer.auditStream.batchSize":       &conf.BatchSize,
+		"eventServer.auditStream.intervalSeconds": &conf.IntervalSeconds,
+		"eventServer.auditStream.delaySeconds":    &conf.DelaySeconds,
+		"eventServer.auditStream.maxRetry":        &conf.MaxRetry,
+	}
+	for key, value := range intFields {
+		if !cc.IsExist(key) {
+			continue
+		}
+		if *value, err = cc.Int(key); err != nil {
+			blog.Errorf("get %s error, err: %v", key, err)
+			return nil, err
+		}
+		if *value < 0 {
+			return nil, fmt.Errorf("%s can not be negative", key)
+		}
+	}
+
+	if conf.BatchSize == 0 || conf.BatchSize > maxBatchSize {
+		return nil, fmt.Errorf("eventServer.auditStream.batchSize must be in range [1, %d]", maxBatchSize)
+	}
+
+	if cc.IsExist("eventServer.auditStream.filter.actions") {
+		if conf.Actions, err = cc.StringSlice("eventServer.auditStream.filter.actions"); err != nil {
+			blog.Errorf("get eventServer.auditStream.filter.actions error, err: %v", err)
+			return nil, err
+		}
+	}
+
+	if cc.IsExist("eventServer.auditStream.filter.resourceTypes") {
+		if conf.ResourceTypes, err = cc.StringSlice("eventServer.auditStream.filter.resourceTypes"); err != nil {
+			blog.Errorf("get eventServer.auditStream.filter.resourceTypes error, err: %v", err)
+			return nil, err
+		}
+	}
+
+	return conf, nil
+}
+
+func parseHTTPSinkConf() (*HTTPSinkConf, error) {
+	url, err := cc.String("eventServer.auditStream.http.url")
+	if err != nil || url == "" {
+		blog.Errorf("get eventServer.auditStream.http.url error, err: %v", err)
+		return nil, errors.New("eventServer.auditStream.http.url must be set")
+	}
+
+	conf := &HTTPSinkConf{URL: url, TimeoutSeconds: defaultHTTPTimeoutSeconds}
+
+	if cc.IsExist("eventServer.auditStream.http.token") {
+		if conf.Token, err = cc.String("eventServer.auditStream.http.token"); err != nil {
+			blog.Errorf("get eventServer.auditStream.http.token error, err: %v", err)
+			return nil, err
+		}
+	}
+
+	if cc.IsExist("eventServer.auditStream.http.timeoutSeconds") {
+		if conf.TimeoutSeconds, err = cc.Int("eventServer.auditStream.http.timeoutSeconds"); err != nil {
+			blog.Errorf("get eventServer.auditStream.http.timeoutSeconds error, err: %v", err)
+			return nil, err
+		}
+	}
+
+	return conf, nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"configcenter/src/common/metadata"
+	"configcenter/src/storage/dal/kafka"
+
+	"github.com/Shopify/sarama"
+)
+
+// Sink is the external system that the audit logs are streamed to, e.g. the SIEM.
+type Sink interface {
+	// Send send a batch of audit logs to the sink, the batch is resent if an error is returned.
+	Send(logs []metadata.AuditLog) error
+	Close() error
+}
+
+// NewSink new the sink of the audit log stream by the config
+func NewSink(conf *AuditStreamConf) (Sink, error) {
+	switch conf.SinkType {
+	case SinkTypeKafka:
+		return newKafkaSink(conf.Kafka)
+	case SinkTypeHTTP:
+		return newHTTPSink(conf.HTTP), nil
+	default:
+		return nil, fmt.Errorf("audit stream sink type %s is invalid", conf.SinkType)
+	}
+}
+
+// kafkaSink produces each audit log as a message of the kafka topic, the key of the message is the audit log id.
+type kafkaSink struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+func newKafkaSink(conf kafka.Config) (*kafkaSink, error) {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	if conf.User != "" && conf.Password != "" {
+		config.Net.SASL.Enable = true
+		config.Net.SASL.User = conf.User
+		config.Net.SASL.Password = conf.Password
+		config.Net.SASL.Handshake = true
+		config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return &kafka.XDGSCRAMClient{HashGeneratorFcn: kafka.SHA512}
+		}
+		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+	}
+
+	producer, err := sarama.NewSyncProducer(conf.Brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("create kafka producer failed, err: %v", err)
+	}
+
+	return &kafkaSink{producer: producer, topic: conf.Topic}, nil
+}
+
+// Send produce the audit logs to the kafka topic
+func (k *kafkaSink) Send(logs []metadata.AuditLog) error {
+	messages := make([]*sarama.ProducerMessage, len(logs))
+	for idx := range logs {
+		value, err := json.Marshal(logs[idx])
+		if err != nil {
+			return fmt.Errorf("marshal audit log %d failed, err: %v", logs[idx].ID, err)
+		}
+
+		messages[idx] = &sarama.ProducerMessage{
+			Topic: k.topic,
+			Key:   sarama.StringEncoder(strconv.FormatInt(logs[idx].ID, 10)),
+			Value: sarama.ByteEncoder(value),
+		}
+	}
+
+	return k.producer.SendMessages(messages)
+}
+
+// Close close the kafka producer
+func (k *kafkaSink) Close() error {
+	return k.producer.Close()
+}
+
+// httpSink posts the audit logs as a json array to the https collector.
+type httpSink struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+func newHTTPSink(conf *HTTPSinkConf) *httpSink {
+	return &httpSink{
+		client: &http.Client{Timeout: time.Duration(conf.TimeoutSeconds) * time.Second},
+		url:    conf.URL,
+		token:  conf.Token,
+	}
+}
+
+// Send post the audit logs to the https collector
+func (h *httpSink) Send(logs []metadata.AuditLog) error {
+	body, err := json.Marshal(logs)
+	if err != nil {
+		return fmt.Errorf("marshal audit logs failed, err: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("collector responds status %d, body: %s", resp.StatusCode, respBody)
+	}
+
+	return nil
+}
+
+// Close close the idle connections to the https collector
+func (h *httpSink) Close() error {
+	h.client.CloseIdleConnections()
+	return nil
+}
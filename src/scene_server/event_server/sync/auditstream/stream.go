@@ -0,0 +1,206 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package auditstream streams the audit logs to the external system like the SIEM in near real time, so that the
+// security teams don't need to poll the audit log api.
+package auditstream
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/backbone"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/storage/dal"
+	"configcenter/src/storage/dal/redis"
+)
+
+const (
+	// auditStreamCursor the id of the last streamed audit log in redis
+	auditStreamCursor = "audit_stream:cursor"
+	// notMasterWaitDuration the duration to wait before checking if the event server becomes master again
+	notMasterWaitDuration = 10 * time.Second
+)
+
+// AuditStream streams the audit logs to the sink in the order of id, the cursor is saved in redis so that the
+// streaming resumes from where it stops when the master event server changes.
+type AuditStream struct {
+	ctx      context.Context
+	engine   *backbone.Engine
+	db       dal.RDB
+	redisCli redis.Client
+	conf     *AuditStreamConf
+	sink     Sink
+	cursor   int64
+}
+
+// NewAuditStream new audit log stream
+func NewAuditStream(ctx context.Context, engine *backbone.Engine, db dal.RDB, redisCli redis.Client,
+	conf *AuditStreamConf, sink Sink) *AuditStream {
+
+	return &AuditStream{
+		ctx:      ctx,
+		engine:   engine,
+		db:       db,
+		redisCli: redisCli,
+		conf:     conf,
+		sink:     sink,
+	}
+}
+
+// Run streams the audit logs until the context is done, only the master event server streams the audit logs.
+func (a *AuditStream) Run() {
+	defer func() {
+		if err := a.sink.Close(); err != nil {
+			blog.Errorf("close audit stream sink failed, err: %v", err)
+		}
+	}()
+
+	wasMaster := false
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		default:
+		}
+
+		if !a.engine.Discovery().IsMaster() {
+			wasMaster = false
+			time.Sleep(notMasterWaitDuration)
+			continue
+		}
+
+		rid := util.GenerateRID()
+
+		// the cursor may be moved by the previous master, so it is reloaded when this event server becomes master
+		if !wasMaster {
+			if err := a.loadCursor(rid); err != nil {
+				time.Sleep(time.Duration(a.conf.IntervalSeconds) * time.Second)
+				continue
+			}
+			wasMaster = true
+		}
+
+		count, err := a.streamOnce(rid)
+		if err != nil || count < a.conf.BatchSize {
+			time.Sleep(time.Duration(a.conf.IntervalSeconds) * time.Second)
+		}
+	}
+}
+
+// streamOnce streams a batch of audit logs after the cursor, returns the number of the audit logs read.
+func (a *AuditStream) streamOnce(rid string) (int, error) {
+	filter := map[string]interface{}{
+		common.BKFieldID: map[string]interface{}{common.BKDBGT: a.cursor},
+		common.BKOperationTimeField: map[string]interface{}{
+			common.BKDBLTE: time.Now().Add(-time.Duration(a.conf.DelaySeconds) * time.Second),
+		},
+	}
+
+	logs := make([]metadata.AuditLog, 0)
+	err := a.db.Table(common.BKTableNameAuditLog).Find(filter).Sort(common.BKFieldID).
+		Limit(uint64(a.conf.BatchSize)).All(a.ctx, &logs)
+	if err != nil {
+		blog.Errorf("get audit logs to stream failed, filter: %v, err: %v, rid: %s", filter, err, rid)
+		return 0, err
+	}
+
+	if len(logs) == 0 {
+		return 0, nil
+	}
+
+	matched := make([]metadata.AuditLog, 0, len(logs))
+	for _, log := range logs {
+		if a.isMatched(log) {
+			matched = append(matched, log)
+		}
+	}
+
+	if len(matched) > 0 {
+		if err := a.send(matched, rid); err != nil {
+			return 0, err
+		}
+	}
+
+	a.setCursor(logs[len(logs)-1].ID, rid)
+	return len(logs), nil
+}
+
+// isMatched checks if the audit log matches the action and resource type filters
+func (a *AuditStream) isMatched(log metadata.AuditLog) bool {
+	if len(a.conf.Actions) > 0 && !util.InStrArr(a.conf.Actions, string(log.Action)) {
+		return false
+	}
+
+	if len(a.conf.ResourceTypes) > 0 && !util.InStrArr(a.conf.ResourceTypes, string(log.ResourceType)) {
+		return false
+	}
+
+	return true
+}
+
+// send sends the audit logs to the sink with retry, the retry interval grows with the failed times.
+func (a *AuditStream) send(logs []metadata.AuditLog, rid string) error {
+	var err error
+	for retry := 0; retry <= a.conf.MaxRetry; retry++ {
+		if retry > 0 {
+			time.Sleep(time.Duration(retry) * time.Second)
+		}
+
+		if err = a.sink.Send(logs); err == nil {
+			return nil
+		}
+
+		blog.Errorf("send audit logs [%d, %d] to sink failed, retry: %d, err: %v, rid: %s", logs[0].ID,
+			logs[len(logs)-1].ID, retry, err, rid)
+	}
+
+	return err
+}
+
+// loadCursor loads the cursor from redis, the audit logs are streamed from now on if the cursor is not saved.
+func (a *AuditStream) loadCursor(rid string) error {
+	cursor, err := a.redisCli.Get(a.ctx, auditStreamCursor).Result()
+	if err == nil {
+		if a.cursor, err = strconv.ParseInt(cursor, 10, 64); err == nil {
+			return nil
+		}
+		blog.Errorf("parse audit stream cursor %s failed, stream from now on, err: %v, rid: %s", cursor, err, rid)
+	} else if !redis.IsNilErr(err) {
+		blog.Errorf("get audit stream cursor from redis failed, err: %v, rid: %s", err, rid)
+		return err
+	}
+
+	lastLog := new(metadata.AuditLog)
+	err = a.db.Table(common.BKTableNameAuditLog).Find(nil).Fields(common.BKFieldID).
+		Sort(common.BKFieldID+":-1").One(a.ctx, lastLog)
+	if err != nil && !a.db.IsNotFoundError(err) {
+		blog.Errorf("get the last audit log failed, err: %v, rid: %s", err, rid)
+		return err
+	}
+
+	a.setCursor(lastLog.ID, rid)
+	return nil
+}
+
+// setCursor saves the cursor in memory and redis, the cursor in redis is only used when the master changes, so the
+// streaming goes on even if it fails to be saved.
+func (a *AuditStream) setCursor(cursor int64, rid string) {
+	a.cursor = cursor
+	if err := a.redisCli.Set(a.ctx, auditStreamCursor, cursor, 0).Err(); err != nil {
+		blog.Errorf("set audit stream cursor %d to redis failed, err: %v, rid: %s", cursor, err, rid)
+	}
+}
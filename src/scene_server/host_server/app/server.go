@@ -30,6 +30,7 @@ import (
 	"configcenter/src/scene_server/host_server/logics"
 	hostsvc "configcenter/src/scene_server/host_server/service"
 	"configcenter/src/storage/dal/redis"
+	"configcenter/src/thirdparty/prober"
 
 	"github.com/emicklei/go-restful/v3"
 )
@@ -99,11 +100,23 @@ func Run(ctx context.Context, cancel context.CancelFunc, op *options.ServerOptio
 	}
 	authManager := extensions.NewAuthManager(engine.CoreAPI, iamCli)
 
+	probeConf, err := prober.ParseConfig("hostServer.probe")
+	if err != nil {
+		blog.Errorf("parse host probe config failed, err: %v", err)
+		return fmt.Errorf("parse host probe config failed, err: %v", err)
+	}
+	hostProber, err := prober.NewProber(probeConf, engine.Metric().Registry())
+	if err != nil {
+		blog.Errorf("new host prober failed, err: %v", err)
+		return fmt.Errorf("new host prober failed, err: %v", err)
+	}
+
 	service.AuthManager = authManager
 	service.Engine = engine
 	service.Config = hostSrv.Config
 	service.CacheDB = cacheDB
 	service.Logic = logics.NewLogics(engine, cacheDB, authManager)
+	service.Prober = hostProber
 	hostSrv.Core = engine
 	hostSrv.Service = service
 
@@ -113,6 +126,8 @@ func Run(ctx context.Context, cancel context.CancelFunc, op *options.ServerOptio
 		return err
 	}
 
+	go service.Logic.TimerRecycleHosts(ctx)
+
 	select {
 	case <-ctx.Done():
 	}
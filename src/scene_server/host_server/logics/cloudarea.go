@@ -0,0 +1,284 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/auditlog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// GetCloudAreaUsages get the hosts and the cloud sync tasks bound to the cloud areas
+func (lgc *Logics) GetCloudAreaUsages(kit *rest.Kit, opt *metadata.CloudAreaUsageOption) ([]metadata.CloudAreaUsage,
+	error) {
+
+	countRes, err := lgc.CoreAPI.CoreService().Host().FindCloudAreaHostCount(kit.Ctx, kit.Header,
+		metadata.CloudAreaHostCount{CloudIDs: opt.CloudIDs})
+	if err != nil {
+		blog.Errorf("find cloud area host count failed, cloud ids: %v, err: %v, rid: %s", opt.CloudIDs, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if err := countRes.CCError(); err != nil {
+		blog.Errorf("find cloud area host count failed, cloud ids: %v, err: %v, rid: %s", opt.CloudIDs, err, kit.Rid)
+		return nil, err
+	}
+
+	hostCounts := make(map[int64]int64, len(countRes.Data))
+	for _, elem := range countRes.Data {
+		hostCounts[elem.CloudID] = elem.HostCount
+	}
+
+	syncTaskIDs, err := lgc.getCloudAreaSyncTaskIDs(kit, opt.CloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]metadata.CloudAreaUsage, len(opt.CloudIDs))
+	for index, cloudID := range opt.CloudIDs {
+		usages[index] = metadata.CloudAreaUsage{
+			CloudID:     cloudID,
+			HostCount:   hostCounts[cloudID],
+			Hosts:       make([]mapstr.MapStr, 0),
+			SyncTaskIDs: syncTaskIDs[cloudID],
+		}
+		if usages[index].SyncTaskIDs == nil {
+			usages[index].SyncTaskIDs = make([]int64, 0)
+		}
+
+		if hostCounts[cloudID] == 0 {
+			continue
+		}
+
+		hosts, err := lgc.getCloudAreaHosts(kit, cloudID, opt.HostLimit,
+			[]string{common.BKHostIDField, common.BKHostInnerIPField})
+		if err != nil {
+			return nil, err
+		}
+		usages[index].Hosts = hosts
+	}
+
+	return usages, nil
+}
+
+// CheckCloudAreaDeletable check if the cloud area can be deleted, only the cloud area without hosts and cloud sync
+// tasks can be deleted, its hosts need to be relocated first.
+func (lgc *Logics) CheckCloudAreaDeletable(kit *rest.Kit, cloudID int64) error {
+	if cloudID == common.BKDefaultDirSubArea {
+		blog.Errorf("can not delete the default cloud area, rid: %s", kit.Rid)
+		return kit.CCError.CCError(common.CCErrDeleteDefaultCloudAreaFail)
+	}
+
+	usages, err := lgc.GetCloudAreaUsages(kit, &metadata.CloudAreaUsageOption{CloudIDs: []int64{cloudID},
+		HostLimit: 1})
+	if err != nil {
+		return err
+	}
+
+	usage := usages[0]
+	if usage.HostCount > 0 {
+		blog.Errorf("cloud area %d has %d hosts, can not be deleted, rid: %s", cloudID, usage.HostCount, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrHostCloudAreaHasHosts, cloudID, usage.HostCount)
+	}
+
+	if len(usage.SyncTaskIDs) > 0 {
+		blog.Errorf("cloud area %d is used by sync tasks %v, can not be deleted, rid: %s", cloudID, usage.SyncTaskIDs,
+			kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrHostCloudAreaUsedBySyncTask, cloudID, usage.SyncTaskIDs)
+	}
+
+	return nil
+}
+
+// DeleteCloudArea delete the cloud area and save its audit log, the caller needs to check if it is deletable first.
+func (lgc *Logics) DeleteCloudArea(kit *rest.Kit, cloudID int64) error {
+	audit := auditlog.NewCloudAreaAuditLog(lgc.CoreAPI.CoreService())
+	generateAuditParameter := auditlog.NewGenerateAuditCommonParameter(kit, metadata.AuditDelete)
+	logs, err := audit.GenerateAuditLog(generateAuditParameter, []int64{cloudID})
+	if err != nil {
+		blog.Errorf("generate audit log failed before delete cloud area, err: %v, rid: %s", err, kit.Rid)
+		return err
+	}
+
+	delCond := &metadata.DeleteOption{
+		Condition: mapstr.MapStr{common.BKCloudIDField: cloudID},
+	}
+	_, err = lgc.CoreAPI.CoreService().Instance().DeleteInstance(kit.Ctx, kit.Header, common.BKInnerObjIDPlat, delCond)
+	if err != nil {
+		blog.Errorf("delete cloud area %d failed, err: %v, rid: %s", cloudID, err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrTopoInstDeleteFailed)
+	}
+
+	if err := audit.SaveAuditLog(kit, logs...); err != nil {
+		blog.Errorf("save audit log failed after delete cloud area, err: %v, rid: %s", err, kit.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// RelocateCloudAreaHosts relocate all the hosts of the cloud area to the target cloud area in batches, each batch is
+// relocated in a transaction, so the relocation can be run again to relocate the rest hosts if it fails halfway,
+// e.g. some hosts' inner ips exist in the target cloud area. returns the number of the relocated hosts.
+func (lgc *Logics) RelocateCloudAreaHosts(kit *rest.Kit, cloudID, targetCloudID int64) (int, error) {
+	if cloudID == targetCloudID {
+		return 0, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "target_bk_cloud_id")
+	}
+
+	for _, id := range []int64{cloudID, targetCloudID} {
+		exists, err := lgc.IsPlatExist(kit, mapstr.MapStr{common.BKCloudIDField: id})
+		if err != nil {
+			return 0, err
+		}
+		if !exists {
+			blog.Errorf("cloud area %d is not exist, rid: %s", id, kit.Rid)
+			return 0, kit.CCError.CCError(common.CCErrTopoCloudNotFound)
+		}
+	}
+
+	relocated := 0
+	for {
+		hosts, err := lgc.getCloudAreaHosts(kit, cloudID, common.BKMaxRecordsAtOnce, []string{common.BKHostIDField})
+		if err != nil {
+			return relocated, err
+		}
+
+		if len(hosts) == 0 {
+			return relocated, nil
+		}
+
+		hostIDs := make([]int64, len(hosts))
+		for index, host := range hosts {
+			hostID, err := host.Int64(common.BKHostIDField)
+			if err != nil {
+				blog.Errorf("parse host id failed, host: %#v, err: %v, rid: %s", host, err, kit.Rid)
+				return relocated, kit.CCError.CCErrorf(common.CCErrCommParamsNeedInt, common.BKHostIDField)
+			}
+			hostIDs[index] = hostID
+		}
+
+		option := metadata.UpdateHostCloudAreaFieldOption{HostIDs: hostIDs, CloudID: targetCloudID}
+		txnErr := lgc.CoreAPI.CoreService().Txn().AutoRunTxn(kit.Ctx, kit.Header, func() error {
+			return lgc.CoreAPI.CoreService().Host().UpdateHostCloudAreaField(kit.Ctx, kit.Header, option)
+		})
+		if txnErr != nil {
+			blog.Errorf("relocate cloud area %d hosts to %d failed, err: %v, rid: %s", cloudID, targetCloudID, txnErr,
+				kit.Rid)
+			return relocated, txnErr
+		}
+
+		relocated += len(hostIDs)
+	}
+}
+
+// MergeCloudAreas merge the source cloud areas into the target cloud area, the cloud areas used by the cloud sync tasks
+// can not be merged since the sync tasks would create hosts in them again. each source cloud area is deleted after its
+// hosts are all relocated, so the merge can be run again to merge the rest cloud areas if it fails halfway.
+func (lgc *Logics) MergeCloudAreas(kit *rest.Kit, opt *metadata.MergeCloudAreaOption) (*metadata.MergeCloudAreaResult,
+	error) {
+
+	usages, err := lgc.GetCloudAreaUsages(kit, &metadata.CloudAreaUsageOption{CloudIDs: opt.SourceCloudIDs,
+		HostLimit: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, usage := range usages {
+		if len(usage.SyncTaskIDs) > 0 {
+			blog.Errorf("cloud area %d is used by sync tasks %v, can not be merged, rid: %s", usage.CloudID,
+				usage.SyncTaskIDs, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrHostCloudAreaUsedBySyncTask, usage.CloudID, usage.SyncTaskIDs)
+		}
+	}
+
+	result := &metadata.MergeCloudAreaResult{DeletedCloudIDs: make([]int64, 0)}
+	for _, cloudID := range opt.SourceCloudIDs {
+		relocated, err := lgc.RelocateCloudAreaHosts(kit, cloudID, opt.TargetCloudID)
+		result.RelocatedCount += relocated
+		if err != nil {
+			return result, err
+		}
+
+		if err := lgc.CheckCloudAreaDeletable(kit, cloudID); err != nil {
+			return result, err
+		}
+
+		txnErr := lgc.CoreAPI.CoreService().Txn().AutoRunTxn(kit.Ctx, kit.Header, func() error {
+			return lgc.DeleteCloudArea(kit, cloudID)
+		})
+		if txnErr != nil {
+			return result, txnErr
+		}
+		result.DeletedCloudIDs = append(result.DeletedCloudIDs, cloudID)
+	}
+
+	return result, nil
+}
+
+// getCloudAreaHosts get at most limit hosts of the cloud area sorted by the host id
+func (lgc *Logics) getCloudAreaHosts(kit *rest.Kit, cloudID int64, limit int, fields []string) ([]mapstr.MapStr,
+	error) {
+
+	query := &metadata.QueryCondition{
+		Condition:      mapstr.MapStr{common.BKCloudIDField: cloudID},
+		Fields:         fields,
+		Page:           metadata.BasePage{Limit: limit, Sort: common.BKHostIDField},
+		DisableCounter: true,
+	}
+	result, err := lgc.CoreAPI.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header, common.BKInnerObjIDHost,
+		query)
+	if err != nil {
+		blog.Errorf("get cloud area %d hosts failed, err: %v, rid: %s", cloudID, err, kit.Rid)
+		return nil, err
+	}
+
+	return result.Info, nil
+}
+
+// getCloudAreaSyncTaskIDs get the ids of the cloud sync tasks which sync hosts into the cloud areas
+func (lgc *Logics) getCloudAreaSyncTaskIDs(kit *rest.Kit, cloudIDs []int64) (map[int64][]int64, error) {
+	option := &metadata.SearchCloudOption{
+		Condition: mapstr.MapStr{
+			common.BKCloudSyncVpcs + "." + common.BKCloudIDField: mapstr.MapStr{common.BKDBIN: cloudIDs},
+		},
+		Page:   metadata.BasePage{Limit: common.BKNoLimit},
+		Fields: []string{common.BKCloudSyncTaskID, common.BKCloudSyncVpcs},
+	}
+	result, err := lgc.CoreAPI.CoreService().Cloud().SearchSyncTask(kit.Ctx, kit.Header, option)
+	if err != nil {
+		blog.Errorf("search cloud areas %v sync tasks failed, err: %v, rid: %s", cloudIDs, err, kit.Rid)
+		return nil, err
+	}
+
+	cloudIDMap := make(map[int64]struct{}, len(cloudIDs))
+	for _, cloudID := range cloudIDs {
+		cloudIDMap[cloudID] = struct{}{}
+	}
+
+	taskIDs := make(map[int64][]int64)
+	for _, task := range result.Info {
+		for _, vpc := range task.SyncVpcs {
+			if _, exists := cloudIDMap[vpc.CloudID]; !exists {
+				continue
+			}
+			ids := taskIDs[vpc.CloudID]
+			if len(ids) == 0 || ids[len(ids)-1] != task.TaskID {
+				taskIDs[vpc.CloudID] = append(ids, task.TaskID)
+			}
+		}
+	}
+
+	return taskIDs, nil
+}
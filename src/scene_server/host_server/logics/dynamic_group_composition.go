@@ -0,0 +1,233 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"sort"
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// ValidateDynamicGroupComposition validates that the groups composed by the dynamic group belong to the business
+// and the object of the dynamic group, and the nested compositions have no cycle and do not exceed the max depth.
+func (lgc *Logics) ValidateDynamicGroupComposition(kit *rest.Kit, bizID int64, group *metadata.DynamicGroup) error {
+	return lgc.walkDynamicGroupComposition(kit, bizID, group, make([]string, 0), nil)
+}
+
+// ExecuteDynamicGroup executes the dynamic group, the composed groups are expanded and validated again since they
+// may be changed after the dynamic group is saved, then the members of the composition are filtered by the dynamic
+// group's own conditions.
+func (lgc *Logics) ExecuteDynamicGroup(kit *rest.Kit, bizID int64, group *metadata.DynamicGroup,
+	page metadata.BasePage, fields []string, disableCounter bool) (*metadata.InstDataInfo, error) {
+
+	return lgc.executeDynamicGroup(kit, bizID, group, page, fields, disableCounter, make([]string, 0))
+}
+
+func (lgc *Logics) executeDynamicGroup(kit *rest.Kit, bizID int64, group *metadata.DynamicGroup,
+	page metadata.BasePage, fields []string, disableCounter bool, path []string) (*metadata.InstDataInfo, error) {
+
+	// parse all dynamic group conditions to search condition.
+	searchConditions := make([]metadata.SearchCondition, 0, len(group.Info.Condition)+1)
+	for _, cond := range group.Info.Condition {
+		searchCondition := metadata.SearchCondition{ObjectID: cond.ObjID, Condition: []metadata.ConditionItem{}}
+		for _, item := range cond.Condition {
+			condItem := metadata.ConditionItem{Field: item.Field, Operator: item.Operator, Value: item.Value}
+			searchCondition.Condition = append(searchCondition.Condition, condItem)
+		}
+		searchCondition.TimeCondition = cond.TimeCondition
+		searchConditions = append(searchConditions, searchCondition)
+	}
+
+	if group.Info.Composition != nil {
+		var memberIDs []int64
+		isFirst := true
+		expand := func(child *metadata.DynamicGroup, childPath []string) error {
+			ids, err := lgc.executeDynamicGroupMemberIDs(kit, bizID, child, childPath)
+			if err != nil {
+				return err
+			}
+			memberIDs = combineDynamicGroupMembers(group.Info.Composition.Operator, memberIDs, ids, isFirst)
+			isFirst = false
+			return nil
+		}
+
+		if err := lgc.walkDynamicGroupComposition(kit, bizID, group, path, expand); err != nil {
+			return nil, err
+		}
+
+		if len(memberIDs) == 0 {
+			return &metadata.InstDataInfo{Info: make([]mapstr.MapStr, 0)}, nil
+		}
+
+		// the member condition is merged into the object's condition since each object can have only one condition.
+		memberCond := metadata.ConditionItem{
+			Field:    common.GetInstIDField(group.ObjID),
+			Operator: common.BKDBIN,
+			Value:    memberIDs,
+		}
+		merged := false
+		for index := range searchConditions {
+			if searchConditions[index].ObjectID == group.ObjID {
+				searchConditions[index].Condition = append(searchConditions[index].Condition, memberCond)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			searchConditions = append(searchConditions, metadata.SearchCondition{ObjectID: group.ObjID,
+				Condition: []metadata.ConditionItem{memberCond}})
+		}
+	}
+
+	switch group.ObjID {
+	case common.BKInnerObjIDHost:
+		searchHostCondition := metadata.HostCommonSearch{AppID: bizID, Condition: searchConditions, Page: page}
+		data, err := lgc.ExecuteHostDynamicGroup(kit, &searchHostCondition, fields, disableCounter)
+		if err != nil {
+			return nil, err
+		}
+		return &metadata.InstDataInfo{Count: data.Count, Info: data.Info}, nil
+
+	case common.BKInnerObjIDSet:
+		searchSetCondition := metadata.SetCommonSearch{AppID: bizID, Condition: searchConditions, Page: page}
+		data, err := lgc.ExecuteSetDynamicGroup(kit, &searchSetCondition, fields, disableCounter)
+		if err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	blog.Errorf("unknown dynamic group %s object type %s, rid: %s", group.ID, group.ObjID, kit.Rid)
+	return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKObjIDField)
+}
+
+// executeDynamicGroupMemberIDs executes the composed dynamic group and returns the ids of all its members.
+func (lgc *Logics) executeDynamicGroupMemberIDs(kit *rest.Kit, bizID int64, group *metadata.DynamicGroup,
+	path []string) ([]int64, error) {
+
+	idField := common.GetInstIDField(group.ObjID)
+	page := metadata.BasePage{Limit: common.BKNoLimit}
+	data, err := lgc.executeDynamicGroup(kit, bizID, group, page, []string{idField}, true, path)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(data.Info))
+	for _, info := range data.Info {
+		id, err := util.GetInt64ByInterface(info[idField])
+		if err != nil {
+			blog.Errorf("parse dynamic group %s member id failed, info: %v, err: %v, rid: %s", group.ID, info, err,
+				kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, idField)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// walkDynamicGroupComposition walks through the groups composed by the dynamic group recursively and validates
+// them, the visit func is called with each directly composed group if it is set, otherwise the nested compositions
+// are walked through too. path is the ids of the dynamic groups which compose the dynamic group.
+func (lgc *Logics) walkDynamicGroupComposition(kit *rest.Kit, bizID int64, group *metadata.DynamicGroup,
+	path []string, visit func(child *metadata.DynamicGroup, childPath []string) error) error {
+
+	if group.Info.Composition == nil {
+		return nil
+	}
+
+	if len(path) >= metadata.DynamicGroupCompositionMaxDepth {
+		blog.Errorf("dynamic group composition %v exceeds max depth, rid: %s", path, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrHostDynamicGroupCompositionTooDeep,
+			metadata.DynamicGroupCompositionMaxDepth)
+	}
+
+	// the id of the dynamic group being created is empty, which is kept in the path to count the depth.
+	childPath := make([]string, len(path), len(path)+1)
+	copy(childPath, path)
+	childPath = append(childPath, group.ID)
+
+	for _, id := range group.Info.Composition.GroupIDs {
+		if util.InStrArr(childPath, id) {
+			blog.Errorf("dynamic group %s is referenced by itself, path: %v, rid: %s", id, childPath, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrHostDynamicGroupCompositionCycle, id)
+		}
+
+		result, err := lgc.CoreAPI.CoreService().Host().GetDynamicGroup(kit.Ctx, strconv.FormatInt(bizID, 10), id,
+			kit.Header)
+		if err != nil {
+			blog.Errorf("get composed dynamic group %s failed, err: %v, rid: %s", id, err, kit.Rid)
+			return kit.CCError.CCError(common.CCErrCommHTTPDoRequestFailed)
+		}
+		if err := result.CCError(); err != nil {
+			blog.Errorf("get composed dynamic group %s failed, err: %v, rid: %s", id, err, kit.Rid)
+			return err
+		}
+
+		child := &result.Data
+		if child.ObjID != group.ObjID {
+			blog.Errorf("composed dynamic group %s object %s is not %s, rid: %s", id, child.ObjID, group.ObjID,
+				kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "info.composition.group_ids")
+		}
+
+		if visit != nil {
+			if err := visit(child, childPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := lgc.walkDynamicGroupComposition(kit, bizID, child, childPath, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// combineDynamicGroupMembers combines the members of the composed group into the members of the composition.
+func combineDynamicGroupMembers(operator string, members, ids []int64, isFirst bool) []int64 {
+	if isFirst {
+		return util.IntArrayUnique(ids)
+	}
+
+	idMap := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		idMap[id] = struct{}{}
+	}
+
+	result := make([]int64, 0, len(members)+len(ids))
+	switch operator {
+	case metadata.DynamicGroupCompositionUnion:
+		result = append(result, members...)
+		result = append(result, ids...)
+		result = util.IntArrayUnique(result)
+
+	case metadata.DynamicGroupCompositionIntersection, metadata.DynamicGroupCompositionDifference:
+		keep := operator == metadata.DynamicGroupCompositionIntersection
+		for _, id := range members {
+			if _, exists := idMap[id]; exists == keep {
+				result = append(result, id)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
@@ -0,0 +1,69 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// ValidateDynamicGroupDuplicate validates that the dynamic group has no identical query as the other dynamic groups
+// of the same business and object, the queries are compared by their canonical hashes, so the ones that differ only
+// in the order of the conditions are identical too.
+func (lgc *Logics) ValidateDynamicGroupDuplicate(kit *rest.Kit, bizID int64, group *metadata.DynamicGroup) error {
+	hash, err := group.Info.Hash()
+	if err != nil {
+		blog.Errorf("get dynamic group info hash failed, info: %+v, err: %v, rid: %s", group.Info, err, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "info")
+	}
+
+	cond := map[string]interface{}{
+		common.BKAppIDField: bizID,
+		common.BKObjIDField: group.ObjID,
+	}
+	if len(group.ID) > 0 {
+		cond[common.BKFieldID] = map[string]interface{}{common.BKDBNE: group.ID}
+	}
+
+	query := &metadata.QueryCondition{
+		Condition: cond,
+		Fields:    []string{common.BKFieldID, common.BKFieldName, "info"},
+		Page:      metadata.BasePage{Limit: common.BKNoLimit},
+	}
+	result, err := lgc.CoreAPI.CoreService().Host().SearchDynamicGroup(kit.Ctx, kit.Header, query)
+	if err != nil {
+		blog.Errorf("search dynamic groups failed, cond: %+v, err: %v, rid: %s", cond, err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if err := result.CCError(); err != nil {
+		blog.Errorf("search dynamic groups failed, cond: %+v, err: %v, rid: %s", cond, err, kit.Rid)
+		return err
+	}
+
+	for _, existing := range result.Data.Info {
+		existingHash, err := existing.Info.Hash()
+		if err != nil {
+			// the saved dynamic group that can not be hashed can not be identical to the valid one, skip it.
+			blog.Warnf("get dynamic group %s info hash failed, err: %v, rid: %s", existing.ID, err, kit.Rid)
+			continue
+		}
+
+		if existingHash == hash {
+			blog.Errorf("dynamic group has the same query as %s, rid: %s", existing.ID, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrHostDynamicGroupDuplicateQuery, existing.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"fmt"
+	"sort"
+
+	"configcenter/src/common"
+	"configcenter/src/common/auditlog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// BatchTransferHostModule transfers the hosts in the business to their own target modules with their own policies.
+// the hosts are transferred in chunks and each chunk is transferred in one transaction, so the failure of a chunk
+// does not roll back the other chunks, and the result of each host is returned.
+func (lgc *Logics) BatchTransferHostModule(kit *rest.Kit, opt *metadata.HostModuleBatchTransferOption) (
+	*metadata.HostModuleBatchTransferResult, error) {
+
+	moduleIDs := make([]int64, 0)
+	for _, transfer := range opt.Transfers {
+		moduleIDs = append(moduleIDs, transfer.ModuleIDs...)
+	}
+
+	bizModuleIDs, err := lgc.getBizModuleIDMap(kit, opt.BizID, util.IntArrayUnique(moduleIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]metadata.HostModuleTransferResult, len(opt.Transfers))
+	validIndexes := make([]int, 0, len(opt.Transfers))
+	for idx, transfer := range opt.Transfers {
+		results[idx].HostID = transfer.HostID
+		for _, moduleID := range transfer.ModuleIDs {
+			if _, exists := bizModuleIDs[moduleID]; !exists {
+				blog.Errorf("module %d is not in biz %d, rid: %s", moduleID, opt.BizID, kit.Rid)
+				setHostModuleTransferError(&results[idx], kit.CCError.CCError(common.CCErrTopoModuleIDNotfoundFailed))
+				break
+			}
+		}
+
+		if results[idx].Code == 0 {
+			validIndexes = append(validIndexes, idx)
+		}
+	}
+
+	for start := 0; start < len(validIndexes); start += opt.ChunkSize {
+		end := start + opt.ChunkSize
+		if end > len(validIndexes) {
+			end = len(validIndexes)
+		}
+
+		chunk := validIndexes[start:end]
+		err := lgc.transferHostModuleChunk(kit, opt.BizID, opt.Transfers, chunk)
+		for _, idx := range chunk {
+			if err != nil {
+				setHostModuleTransferError(&results[idx], err)
+				continue
+			}
+			results[idx].Success = true
+		}
+	}
+
+	result := &metadata.HostModuleBatchTransferResult{Results: results}
+	for _, item := range results {
+		if item.Success {
+			result.SuccessCount++
+			continue
+		}
+		result.FailedCount++
+	}
+	return result, nil
+}
+
+// transferHostModuleChunk transfers the hosts of the chunk in one transaction, the hosts with the same target
+// modules and policy are transferred together.
+func (lgc *Logics) transferHostModuleChunk(kit *rest.Kit, bizID int64, transfers []metadata.HostModuleTransfer,
+	chunk []int) error {
+
+	hostIDs := make([]int64, 0, len(chunk))
+	relations := make([]*metadata.HostsModuleRelation, 0)
+	relationMap := make(map[string]*metadata.HostsModuleRelation)
+	for _, idx := range chunk {
+		transfer := transfers[idx]
+		hostIDs = append(hostIDs, transfer.HostID)
+
+		moduleIDs := util.IntArrayUnique(transfer.ModuleIDs)
+		sort.Slice(moduleIDs, func(i, j int) bool { return moduleIDs[i] < moduleIDs[j] })
+		key := fmt.Sprintf("%v:%t:%t", moduleIDs, transfer.IsIncrement, transfer.DisableAutoCreateSvcInst)
+		relation, exists := relationMap[key]
+		if !exists {
+			relation = &metadata.HostsModuleRelation{
+				ApplicationID:            bizID,
+				ModuleID:                 moduleIDs,
+				IsIncrement:              transfer.IsIncrement,
+				DisableAutoCreateSvcInst: transfer.DisableAutoCreateSvcInst,
+			}
+			relationMap[key] = relation
+			relations = append(relations, relation)
+		}
+		relation.HostID = append(relation.HostID, transfer.HostID)
+	}
+
+	audit := auditlog.NewHostModuleLog(lgc.CoreAPI.CoreService(), hostIDs)
+	if err := audit.WithPrevious(kit); err != nil {
+		blog.Errorf("get hosts %v previous module relation failed, err: %v, rid: %s", hostIDs, err, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommResourceInitFailed, "audit server")
+	}
+
+	return lgc.CoreAPI.CoreService().Txn().AutoRunTxn(kit.Ctx, kit.Header, func() error {
+		for _, relation := range relations {
+			_, err := lgc.CoreAPI.CoreService().Host().TransferToNormalModule(kit.Ctx, kit.Header, relation)
+			if err != nil {
+				blog.Errorf("transfer hosts failed, err: %v, input: %#v, rid: %s", err, relation, kit.Rid)
+				return err
+			}
+		}
+
+		if err := audit.SaveAudit(kit); err != nil {
+			blog.Errorf("save hosts %v module relation audit log failed, err: %v, rid: %s", hostIDs, err, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCommHTTPDoRequestFailed, err.Error())
+		}
+		return nil
+	})
+}
+
+// getBizModuleIDMap returns the ids of the modules that belong to the business.
+func (lgc *Logics) getBizModuleIDMap(kit *rest.Kit, bizID int64, moduleIDs []int64) (map[int64]struct{}, error) {
+	query := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{
+			common.BKAppIDField:    bizID,
+			common.BKModuleIDField: mapstr.MapStr{common.BKDBIN: moduleIDs},
+		},
+		Fields:         []string{common.BKModuleIDField},
+		Page:           metadata.BasePage{Limit: common.BKNoLimit},
+		DisableCounter: true,
+	}
+	result, err := lgc.CoreAPI.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header, common.BKInnerObjIDModule,
+		query)
+	if err != nil {
+		blog.Errorf("get biz %d modules %v failed, err: %v, rid: %s", bizID, moduleIDs, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommHTTPDoRequestFailed)
+	}
+
+	moduleIDMap := make(map[int64]struct{}, len(result.Info))
+	for _, module := range result.Info {
+		moduleID, err := module.Int64(common.BKModuleIDField)
+		if err != nil {
+			blog.Errorf("parse module id failed, module: %#v, err: %v, rid: %s", module, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsNeedInt, common.BKModuleIDField)
+		}
+		moduleIDMap[moduleID] = struct{}{}
+	}
+	return moduleIDMap, nil
+}
+
+// setHostModuleTransferError set the host transfer result to failed with the error.
+func setHostModuleTransferError(result *metadata.HostModuleTransferResult, err error) {
+	result.Success = false
+	result.Code = common.CCErrCommHTTPDoRequestFailed
+	if ccErr, ok := err.(errors.CCErrorCoder); ok {
+		result.Code = ccErr.GetCode()
+	}
+	result.Message = err.Error()
+}
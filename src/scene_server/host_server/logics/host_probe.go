@@ -0,0 +1,143 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/thirdparty/prober"
+)
+
+// ProbeHosts probes the connectivity of the hosts in the business by the prober, the latest probe results are saved
+// as the host status fields, so that the hosts can be filtered by them, e.g. to verify the decommissioned hosts.
+func (lgc *Logics) ProbeHosts(kit *rest.Kit, hostProber prober.Prober, bizID int64,
+	opt *metadata.HostProbeOption) ([]metadata.HostProbeResult, error) {
+
+	if hostProber == nil || !hostProber.Support(opt.Method) {
+		blog.Errorf("host probe method %s is not supported, rid: %s", opt.Method, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrHostProbeMethodNotSupported, opt.Method)
+	}
+
+	hostIDs := util.IntArrayUnique(opt.HostIDs)
+	if err := lgc.validateBizHosts(kit, bizID, hostIDs); err != nil {
+		return nil, err
+	}
+
+	cond := metadata.QueryCondition{
+		Fields:    []string{common.BKHostIDField, common.BKCloudIDField, common.BKHostInnerIPField},
+		Condition: mapstr.MapStr{common.BKHostIDField: mapstr.MapStr{common.BKDBIN: hostIDs}},
+		Page:      metadata.BasePage{Limit: common.BKNoLimit},
+	}
+	hosts, ccErr := lgc.SearchHostInfo(kit, cond)
+	if ccErr != nil {
+		return nil, ccErr
+	}
+
+	req := &metadata.HostProbeRequest{
+		Method:  opt.Method,
+		Ports:   opt.Ports,
+		Targets: make([]metadata.HostProbeTarget, 0, len(hosts)),
+	}
+	var err error
+	for _, host := range hosts {
+		target := metadata.HostProbeTarget{InnerIP: util.GetStrByInterface(host[common.BKHostInnerIPField])}
+		if target.HostID, err = util.GetInt64ByInterface(host[common.BKHostIDField]); err != nil {
+			blog.Errorf("parse host id failed, host: %v, err: %v, rid: %s", host, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKHostIDField)
+		}
+		if target.CloudID, err = util.GetInt64ByInterface(host[common.BKCloudIDField]); err != nil {
+			blog.Errorf("parse host cloud id failed, host: %v, err: %v, rid: %s", host, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKCloudIDField)
+		}
+		req.Targets = append(req.Targets, target)
+	}
+
+	results, err := hostProber.Probe(kit.Ctx, kit.Header, req)
+	if err != nil {
+		blog.Errorf("probe hosts failed, method: %s, hosts: %v, err: %v, rid: %s", opt.Method, hostIDs, err, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrHostProbeFailed, err.Error())
+	}
+
+	if err := lgc.saveHostProbeResults(kit, results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// validateBizHosts validates that all the hosts belong to the business.
+func (lgc *Logics) validateBizHosts(kit *rest.Kit, bizID int64, hostIDs []int64) error {
+	relations, err := lgc.GetHostRelations(kit, metadata.HostModuleRelationRequest{
+		ApplicationID: bizID,
+		HostIDArr:     hostIDs,
+		Fields:        []string{common.BKHostIDField},
+	})
+	if err != nil {
+		return err
+	}
+
+	bizHostIDs := make(map[int64]struct{}, len(relations))
+	for _, relation := range relations {
+		bizHostIDs[relation.HostID] = struct{}{}
+	}
+
+	invalidIDs := make([]int64, 0)
+	for _, hostID := range hostIDs {
+		if _, exists := bizHostIDs[hostID]; !exists {
+			invalidIDs = append(invalidIDs, hostID)
+		}
+	}
+
+	if len(invalidIDs) != 0 {
+		blog.Errorf("hosts %v do not belong to biz %d, rid: %s", invalidIDs, bizID, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCoreServiceHostNotBelongBusiness, invalidIDs, bizID)
+	}
+
+	return nil
+}
+
+// saveHostProbeResults saves the probe status and time of the hosts, the hosts with the same status are updated
+// together. the probe fields are not editable, they are updated like the fields collected by the datacollection.
+func (lgc *Logics) saveHostProbeResults(kit *rest.Kit, results []metadata.HostProbeResult) error {
+	statusHostIDs := make(map[string][]int64)
+	for _, result := range results {
+		statusHostIDs[result.Status] = append(statusHostIDs[result.Status], result.HostID)
+	}
+
+	now := time.Now()
+	for status, hostIDs := range statusHostIDs {
+		input := &metadata.UpdateOption{
+			Data: mapstr.MapStr{
+				common.BKProbeStatusField: status,
+				common.BKProbeTimeField:   now,
+			},
+			Condition:  mapstr.MapStr{common.BKHostIDField: mapstr.MapStr{common.BKDBIN: hostIDs}},
+			CanEditAll: true,
+		}
+
+		_, err := lgc.CoreAPI.CoreService().Instance().UpdateInstance(kit.Ctx, kit.Header, common.BKInnerObjIDHost,
+			input)
+		if err != nil {
+			blog.Errorf("save hosts %v probe status %s failed, err: %v, rid: %s", hostIDs, status, err, kit.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
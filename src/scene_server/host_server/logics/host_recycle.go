@@ -0,0 +1,520 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/auditlog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/querybuilder"
+	"configcenter/src/common/util"
+
+	"github.com/robfig/cron"
+)
+
+const (
+	// hostRecycleCheckSpec check the due host recycle policies every minute
+	hostRecycleCheckSpec = "0 * * * * *"
+	// hostRecyclePageSize the page size of the host recycle policies and the host relations
+	hostRecyclePageSize = 200
+	// hostCommentField the host's comment field, which is matched by the opt-out tags
+	hostCommentField = "bk_comment"
+)
+
+// TimerRecycleHosts check and run the due host recycle policies periodically
+func (lgc *Logics) TimerRecycleHosts(ctx context.Context) {
+	c := cron.New()
+	err := c.AddFunc(hostRecycleCheckSpec, func() {
+		// 主服务器执行主机回收策略
+		if !lgc.Engine.ServiceManageInterface.IsMaster() {
+			return
+		}
+		lgc.runDueHostRecyclePolicies()
+	})
+	if err != nil {
+		blog.Errorf("new host recycle cron failed, please contact developer, err: %v", err)
+		return
+	}
+	c.Start()
+
+	select {
+	case <-ctx.Done():
+		c.Stop()
+		return
+	}
+}
+
+func (lgc *Logics) runDueHostRecyclePolicies() {
+	kit := newHostRecycleKit(common.BKSuperOwnerID, common.BKProcInstanceOpUser)
+	now := time.Now()
+
+	query := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{"enabled": true},
+		Page:      metadata.BasePage{Limit: hostRecyclePageSize, Sort: common.BKFieldID},
+	}
+	for {
+		result, err := lgc.CoreAPI.CoreService().Host().SearchHostRecyclePolicy(kit.Ctx, kit.Header, query)
+		if err != nil {
+			blog.Errorf("search host recycle policies failed, err: %v, rid: %s", err, kit.Rid)
+			return
+		}
+
+		for index := range result.Info {
+			policy := &result.Info[index]
+			if !policy.IsDue(now) {
+				continue
+			}
+
+			policyKit := newHostRecycleKit(policy.SupplierAccount, policy.Creator)
+			if _, err := lgc.RunHostRecyclePolicy(policyKit, policy, false); err != nil {
+				// the failed policy is not retried until next schedule time, the matched hosts are found again then
+				blog.Errorf("run host recycle policy %d failed, err: %v, rid: %s", policy.ID, err, policyKit.Rid)
+			}
+
+			data := map[string]interface{}{"last_run_time": now}
+			err := lgc.CoreAPI.CoreService().Host().UpdateHostRecyclePolicy(kit.Ctx, kit.Header, policy.ID, data)
+			if err != nil {
+				blog.Errorf("update host recycle policy %d run time failed, err: %v, rid: %s", policy.ID, err,
+					kit.Rid)
+			}
+		}
+
+		if len(result.Info) < hostRecyclePageSize {
+			return
+		}
+		query.Page.Start += hostRecyclePageSize
+	}
+}
+
+// RunHostRecyclePolicy find the hosts that meet the criteria of the policy, then recycle them or raise a review
+// report according to the policy's action. If dry run, the matched hosts are returned in the report without being
+// recycled, and the report is not saved. The report is not saved either if no host is matched.
+func (lgc *Logics) RunHostRecyclePolicy(kit *rest.Kit, policy *metadata.HostRecyclePolicy, dryRun bool) (
+	*metadata.HostRecycleReport, error) {
+
+	hosts, err := lgc.findHostRecycleCandidates(kit, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &metadata.HostRecycleReport{
+		BizID:        policy.BizID,
+		PolicyID:     policy.ID,
+		PolicyName:   policy.Name,
+		Action:       policy.Action,
+		Hosts:        hosts,
+		MatchedCount: len(hosts),
+	}
+	if len(hosts) > metadata.HostRecycleMaxHostsPerRun {
+		report.Hosts = hosts[:metadata.HostRecycleMaxHostsPerRun]
+		report.Truncated = true
+	}
+
+	if dryRun || len(report.Hosts) == 0 {
+		return report, nil
+	}
+
+	switch policy.Action {
+	case metadata.HostRecycleActionRecycle:
+		report.Status = metadata.HostRecycleReportRecycled
+		if err := lgc.recycleHosts(kit, policy.BizID, report.HostIDs()); err != nil {
+			blog.Errorf("recycle hosts of policy %d failed, err: %v, rid: %s", policy.ID, err, kit.Rid)
+			report.Status = metadata.HostRecycleReportFailed
+			report.Reason = err.Error()
+		}
+	case metadata.HostRecycleActionReview:
+		report.Status = metadata.HostRecycleReportPending
+	}
+
+	result, err := lgc.CoreAPI.CoreService().Host().CreateHostRecycleReport(kit.Ctx, kit.Header, report)
+	if err != nil {
+		blog.Errorf("create host recycle report of policy %d failed, err: %v, rid: %s", policy.ID, err, kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReviewHostRecycleReport approve or reject a pending host recycle report, the hosts in the report are recycled if
+// it is approved, except those that have been moved out of the business since the report is raised.
+func (lgc *Logics) ReviewHostRecycleReport(kit *rest.Kit, report *metadata.HostRecycleReport,
+	opt *metadata.ReviewHostRecycleReportOption) error {
+
+	if report.Status != metadata.HostRecycleReportPending {
+		blog.Errorf("host recycle report %d status %s is not pending, rid: %s", report.ID, report.Status, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrHostRecycleReportNotPending, report.ID)
+	}
+
+	status := metadata.HostRecycleReportRejected
+	reason := opt.Reason
+	if opt.Approve {
+		status = metadata.HostRecycleReportRecycled
+		if err := lgc.recycleReportHosts(kit, report); err != nil {
+			blog.Errorf("recycle hosts of report %d failed, err: %v, rid: %s", report.ID, err, kit.Rid)
+			return err
+		}
+	}
+
+	data := map[string]interface{}{"status": status, "reason": reason, "reviewer": kit.User}
+	if err := lgc.CoreAPI.CoreService().Host().UpdateHostRecycleReport(kit.Ctx, kit.Header, report.ID,
+		data); err != nil {
+		blog.Errorf("update host recycle report %d status failed, err: %v, rid: %s", report.ID, err, kit.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (lgc *Logics) recycleReportHosts(kit *rest.Kit, report *metadata.HostRecycleReport) error {
+	relations, err := lgc.GetHostRelations(kit, metadata.HostModuleRelationRequest{
+		ApplicationID: report.BizID,
+		HostIDArr:     report.HostIDs(),
+		Fields:        []string{common.BKHostIDField},
+	})
+	if err != nil {
+		return err
+	}
+
+	hostIDs := make([]int64, 0, len(relations))
+	for _, relation := range relations {
+		hostIDs = append(hostIDs, relation.HostID)
+	}
+	hostIDs = util.IntArrayUnique(hostIDs)
+	if len(hostIDs) == 0 {
+		return nil
+	}
+
+	return lgc.recycleHosts(kit, report.BizID, hostIDs)
+}
+
+// recycleHosts move the hosts into the recycle module of the business.
+func (lgc *Logics) recycleHosts(kit *rest.Kit, bizID int64, hostIDs []int64) error {
+	moduleFilter := mapstr.MapStr{
+		common.BKAppIDField:   bizID,
+		common.BKDefaultField: common.DefaultRecycleModuleFlag,
+	}
+	moduleID, _, err := lgc.GetResourcePoolModuleID(kit, moduleFilter)
+	if err != nil {
+		blog.Errorf("get biz %d recycle module failed, err: %v, rid: %s", bizID, err, kit.Rid)
+		return err
+	}
+
+	audit := auditlog.NewHostModuleLog(lgc.CoreAPI.CoreService(), hostIDs)
+	if err := audit.WithPrevious(kit); err != nil {
+		blog.Errorf("get hosts %v previous module relations failed, err: %v, rid: %s", hostIDs, err, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommResourceInitFailed, "audit server")
+	}
+
+	return lgc.CoreAPI.CoreService().Txn().AutoRunTxn(kit.Ctx, kit.Header, func() error {
+		transferInput := &metadata.TransferHostToInnerModule{
+			ApplicationID: bizID,
+			HostID:        hostIDs,
+			ModuleID:      moduleID,
+		}
+		if _, err := lgc.CoreAPI.CoreService().Host().TransferToInnerModule(kit.Ctx, kit.Header,
+			transferInput); err != nil {
+			blog.Errorf("transfer hosts to recycle module failed, err: %v, input: %#v, rid: %s", err, transferInput,
+				kit.Rid)
+			return err
+		}
+
+		if err := audit.SaveAudit(kit); err != nil {
+			blog.Errorf("save hosts %v recycle audit log failed, err: %v, rid: %s", hostIDs, err, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCommResourceInitFailed, "audit server")
+		}
+		return nil
+	})
+}
+
+// findHostRecycleCandidates find the hosts of the business that meet all the criteria of the policy, the hosts
+// already in the recycle module and the hosts opted out by the tags are excluded.
+func (lgc *Logics) findHostRecycleCandidates(kit *rest.Kit, policy *metadata.HostRecyclePolicy) (
+	[]metadata.HostRecycleHost, error) {
+
+	if policy.Criteria.BizOutOfService {
+		outOfService, err := lgc.isBizOutOfService(kit, policy.BizID)
+		if err != nil {
+			return nil, err
+		}
+		if !outOfService {
+			return make([]metadata.HostRecycleHost, 0), nil
+		}
+	}
+
+	hostIDs, err := lgc.getBizHostIDsToRecycle(kit, policy.BizID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]metadata.HostRecycleHost, 0)
+	for start := 0; start < len(hostIDs); start += common.BKMaxInstanceLimit {
+		end := start + common.BKMaxInstanceLimit
+		if end > len(hostIDs) {
+			end = len(hostIDs)
+		}
+
+		hosts, err := lgc.matchHostRecycleCriteria(kit, policy, hostIDs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, hosts...)
+	}
+
+	return candidates, nil
+}
+
+func (lgc *Logics) isBizOutOfService(kit *rest.Kit, bizID int64) (bool, error) {
+	biz, err := lgc.GetSingleApp(kit, mapstr.MapStr{common.BKAppIDField: bizID})
+	if err != nil {
+		return false, err
+	}
+	if biz == nil {
+		blog.Errorf("biz %d is not exist, rid: %s", bizID, kit.Rid)
+		return false, kit.CCError.CCError(common.CCErrCommBizNotFoundError)
+	}
+
+	return util.GetStrByInterface(biz[common.BKLifeCycleField]) == common.DefaultAppLifeCycleStopped, nil
+}
+
+// getBizHostIDsToRecycle get the ids of the hosts of the business in the order of the host id, except the hosts
+// that are already in the recycle module.
+func (lgc *Logics) getBizHostIDsToRecycle(kit *rest.Kit, bizID int64) ([]int64, error) {
+	moduleFilter := mapstr.MapStr{
+		common.BKAppIDField:   bizID,
+		common.BKDefaultField: common.DefaultRecycleModuleFlag,
+	}
+	recycleModuleID, _, err := lgc.GetResourcePoolModuleID(kit, moduleFilter)
+	if err != nil {
+		blog.Errorf("get biz %d recycle module failed, err: %v, rid: %s", bizID, err, kit.Rid)
+		return nil, err
+	}
+
+	hostIDs := make([]int64, 0)
+	relReq := metadata.HostModuleRelationRequest{
+		ApplicationID: bizID,
+		Fields:        []string{common.BKHostIDField, common.BKModuleIDField},
+		Page:          metadata.BasePage{Limit: hostRecyclePageSize, Sort: common.BKHostIDField},
+	}
+	for {
+		relations, err := lgc.GetHostRelations(kit, relReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, relation := range relations {
+			// the host in the recycle module belongs to no other modules, so it is skipped by its only relation
+			if relation.ModuleID == recycleModuleID {
+				continue
+			}
+			if len(hostIDs) == 0 || hostIDs[len(hostIDs)-1] != relation.HostID {
+				hostIDs = append(hostIDs, relation.HostID)
+			}
+		}
+
+		if len(relations) < hostRecyclePageSize {
+			return hostIDs, nil
+		}
+		relReq.AfterHostID = relations[len(relations)-1].HostID
+	}
+}
+
+// matchHostRecycleCriteria returns the hosts that are not opted out and meet all the criteria of the policy.
+func (lgc *Logics) matchHostRecycleCriteria(kit *rest.Kit, policy *metadata.HostRecyclePolicy, hostIDs []int64) (
+	[]metadata.HostRecycleHost, error) {
+
+	filter, err := querybuilder.NewBuilder().Field(common.BKHostIDField).In(hostIDs).Build()
+	if err != nil {
+		blog.Errorf("build host filter by ids %v failed, err: %v, rid: %s", hostIDs, err, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKHostIDField)
+	}
+
+	option := &metadata.ListHosts{
+		BizID:              policy.BizID,
+		HostPropertyFilter: filter,
+		Fields:             []string{common.BKHostIDField, common.BKHostInnerIPField, hostCommentField},
+		Page:               metadata.BasePage{Limit: common.BKMaxInstanceLimit, Sort: common.BKHostIDField},
+	}
+	hosts, err := lgc.CoreAPI.CoreService().Host().ListHosts(kit.Ctx, kit.Header, option)
+	if err != nil {
+		blog.Errorf("list hosts %v failed, err: %v, rid: %s", hostIDs, err, kit.Rid)
+		return nil, err
+	}
+
+	candidates := make([]metadata.HostRecycleHost, 0, len(hosts.Info))
+	for _, host := range hosts.Info {
+		if isHostOptedOut(util.GetStrByInterface(host[hostCommentField]), policy.OptOutTags) {
+			continue
+		}
+
+		hostID, err := util.GetInt64ByInterface(host[common.BKHostIDField])
+		if err != nil {
+			blog.Errorf("parse host id failed, host: %v, err: %v, rid: %s", host, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKHostIDField)
+		}
+
+		candidates = append(candidates, metadata.HostRecycleHost{
+			HostID:  hostID,
+			InnerIP: util.GetStrByInterface(host[common.BKHostInnerIPField]),
+		})
+	}
+
+	if len(candidates) != 0 && policy.Criteria.AgentOfflineDays > 0 {
+		if candidates, err = lgc.filterAgentOfflineHosts(kit, candidates,
+			policy.Criteria.AgentOfflineDays); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(candidates) != 0 && policy.Criteria.NoServiceInstance {
+		if candidates, err = lgc.filterNoServiceInstanceHosts(kit, policy.BizID, candidates); err != nil {
+			return nil, err
+		}
+	}
+
+	return candidates, nil
+}
+
+// filterAgentOfflineHosts returns the hosts whose agent has been offline for more than the days. The hosts that
+// have never reported the snapshot since the last seen time is tracked start to be counted from now on, so that
+// they are not recycled at once.
+func (lgc *Logics) filterAgentOfflineHosts(kit *rest.Kit, hosts []metadata.HostRecycleHost, days int) (
+	[]metadata.HostRecycleHost, error) {
+
+	fields := make([]string, len(hosts))
+	for index, host := range hosts {
+		fields[index] = strconv.FormatInt(host.HostID, 10)
+	}
+
+	values, err := lgc.cache.HMGet(kit.Ctx, common.RedisAgentLastSeenKey, fields...).Result()
+	if err != nil {
+		blog.Errorf("get hosts agent last seen time failed, err: %v, rid: %s", err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommRedisOPErr)
+	}
+
+	now := time.Now()
+	unseen := make([]interface{}, 0)
+	result := make([]metadata.HostRecycleHost, 0)
+	for index, value := range values {
+		if value == nil {
+			unseen = append(unseen, fields[index], now.Unix())
+			continue
+		}
+
+		lastSeen, err := util.GetInt64ByInterface(value)
+		if err != nil {
+			blog.Errorf("parse host %s agent last seen time %v failed, err: %v, rid: %s", fields[index], value, err,
+				kit.Rid)
+			continue
+		}
+
+		offlineDays := agentOfflineDays(lastSeen, now)
+		if offlineDays < days {
+			continue
+		}
+
+		host := hosts[index]
+		host.AgentOfflineDays = offlineDays
+		result = append(result, host)
+	}
+
+	if len(unseen) != 0 {
+		if err := lgc.cache.HSet(kit.Ctx, common.RedisAgentLastSeenKey, unseen...).Err(); err != nil {
+			blog.Errorf("init hosts agent last seen time failed, err: %v, rid: %s", err, kit.Rid)
+			return nil, kit.CCError.CCError(common.CCErrCommRedisOPErr)
+		}
+	}
+
+	return result, nil
+}
+
+// filterNoServiceInstanceHosts returns the hosts that have no service instance in the business.
+func (lgc *Logics) filterNoServiceInstanceHosts(kit *rest.Kit, bizID int64, hosts []metadata.HostRecycleHost) (
+	[]metadata.HostRecycleHost, error) {
+
+	hostIDs := make([]int64, len(hosts))
+	for index, host := range hosts {
+		hostIDs[index] = host.HostID
+	}
+
+	option := &metadata.ListServiceInstanceOption{
+		BusinessID: bizID,
+		HostIDs:    hostIDs,
+		Fields:     []string{common.BKHostIDField},
+		Page:       metadata.BasePage{Limit: common.BKNoLimit},
+	}
+	instances, err := lgc.CoreAPI.CoreService().Process().ListServiceInstance(kit.Ctx, kit.Header, option)
+	if err != nil {
+		blog.Errorf("list service instances of hosts %v failed, err: %v, rid: %s", hostIDs, err, kit.Rid)
+		return nil, err
+	}
+
+	hasInstance := make(map[int64]struct{}, len(instances.Info))
+	for _, instance := range instances.Info {
+		hasInstance[instance.HostID] = struct{}{}
+	}
+
+	result := make([]metadata.HostRecycleHost, 0, len(hosts))
+	for _, host := range hosts {
+		if _, exists := hasInstance[host.HostID]; !exists {
+			result = append(result, host)
+		}
+	}
+	return result, nil
+}
+
+// agentOfflineDays returns the whole days from the agent's last seen time to now.
+func agentOfflineDays(lastSeen int64, now time.Time) int {
+	offline := now.Sub(time.Unix(lastSeen, 0))
+	if offline < 0 {
+		return 0
+	}
+	return int(offline / (24 * time.Hour))
+}
+
+// isHostOptedOut check if the host's comment contains any of the opt-out tags.
+func isHostOptedOut(comment string, tags []string) bool {
+	for _, tag := range tags {
+		if strings.Contains(comment, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// newHostRecycleKit new a kit to run the host recycle policy on behalf of the policy's creator
+func newHostRecycleKit(supplierAccount, user string) *rest.Kit {
+	header := make(http.Header)
+	header.Add(common.BKHTTPOwnerID, supplierAccount)
+	header.Add(common.BKHTTPHeaderUser, user)
+	header.Add(common.BKHTTPLanguage, "cn")
+	header.Add(common.BKHTTPCCRequestID, util.GenerateRID())
+	header.Add("Content-Type", "application/json")
+
+	return &rest.Kit{
+		Rid:             util.GetHTTPCCRequestID(header),
+		Header:          header,
+		Ctx:             util.NewContextFromHTTPHeader(header),
+		CCError:         util.GetDefaultCCError(header),
+		User:            user,
+		SupplierAccount: supplierAccount,
+	}
+}
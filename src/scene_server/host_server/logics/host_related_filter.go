@@ -0,0 +1,233 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"fmt"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// RelatedHostScope is the scope of the hosts restricted by the related filters, nil ids means no restriction.
+type RelatedHostScope struct {
+	SetIDs    []int64
+	ModuleIDs []int64
+	HostIDs   []int64
+	// Empty is true if no host can match the related filters.
+	Empty bool
+}
+
+// ParseHostRelatedFilters compiles the host related filters into staged queries, each stage finds the ids of the
+// matched resources which are used as the condition of the next stage, and the scopes of all the filters are
+// intersected. each stage can match at most metadata.HostRelatedFilterMaxMatches resources.
+func (lgc *Logics) ParseHostRelatedFilters(kit *rest.Kit, bizID int64, filters []metadata.HostRelatedFilter) (
+	*RelatedHostScope, errors.CCErrorCoder) {
+
+	scope := new(RelatedHostScope)
+	for idx, filter := range filters {
+		key := fmt.Sprintf("related_filters[%d]", idx)
+
+		cond, errKey, err := filter.Filter.ToMgo()
+		if err != nil {
+			blog.Errorf("%s filter is invalid, key: %s, err: %v, rid: %s", key, errKey, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, key+".filter."+errKey)
+		}
+
+		var ids []int64
+		var ccErr errors.CCErrorCoder
+		switch filter.Resource {
+		case metadata.HostRelatedServiceTemplate:
+			ids, ccErr = lgc.getTemplateRelatedInstIDs(kit, bizID, common.BKTableNameServiceTemplate,
+				common.BKInnerObjIDModule, common.BKServiceTemplateIDField, cond, key)
+			if ccErr == nil {
+				scope.ModuleIDs = intersectRelatedIDs(scope.ModuleIDs, ids)
+				ids = scope.ModuleIDs
+			}
+		case metadata.HostRelatedSetTemplate:
+			ids, ccErr = lgc.getTemplateRelatedInstIDs(kit, bizID, common.BKTableNameSetTemplate,
+				common.BKInnerObjIDSet, common.BKSetTemplateIDField, cond, key)
+			if ccErr == nil {
+				scope.SetIDs = intersectRelatedIDs(scope.SetIDs, ids)
+				ids = scope.SetIDs
+			}
+		case metadata.HostRelatedInstance:
+			ids, ccErr = lgc.getAssociatedHostIDs(kit, filter.ObjectID, cond, key)
+			if ccErr == nil {
+				scope.HostIDs = intersectRelatedIDs(scope.HostIDs, ids)
+				ids = scope.HostIDs
+			}
+		default:
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, key+".resource")
+		}
+
+		if ccErr != nil {
+			return nil, ccErr
+		}
+
+		if len(ids) == 0 {
+			scope.Empty = true
+			return scope, nil
+		}
+	}
+
+	return scope, nil
+}
+
+// getTemplateRelatedInstIDs get the ids of the sets or modules created by the templates matching the condition.
+func (lgc *Logics) getTemplateRelatedInstIDs(kit *rest.Kit, bizID int64, table, objID, templateField string,
+	cond map[string]interface{}, key string) ([]int64, errors.CCErrorCoder) {
+
+	cond[common.BKAppIDField] = bizID
+	option := &metadata.DistinctFieldOption{
+		TableName: table,
+		Field:     common.BKFieldID,
+		Filter:    cond,
+	}
+	rawIDs, ccErr := lgc.CoreAPI.CoreService().Common().GetDistinctField(kit.Ctx, kit.Header, option)
+	if ccErr != nil {
+		blog.Errorf("get %s ids failed, option: %#v, err: %v, rid: %s", table, option, ccErr, kit.Rid)
+		return nil, ccErr
+	}
+
+	if len(rawIDs) > metadata.HostRelatedFilterMaxMatches {
+		blog.Errorf("%s matches %d templates, exceeds limit, rid: %s", key, len(rawIDs), kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommXXExceedLimit, key, metadata.HostRelatedFilterMaxMatches)
+	}
+
+	templateIDs := make([]int64, 0, len(rawIDs))
+	for _, rawID := range rawIDs {
+		templateID, err := util.GetInt64ByInterface(rawID)
+		if err != nil {
+			blog.Errorf("parse %s id %v failed, err: %v, rid: %s", table, rawID, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKFieldID)
+		}
+		templateIDs = append(templateIDs, templateID)
+	}
+
+	if len(templateIDs) == 0 {
+		return make([]int64, 0), nil
+	}
+
+	instCond := mapstr.MapStr{
+		common.BKAppIDField: bizID,
+		templateField:       mapstr.MapStr{common.BKDBIN: templateIDs},
+	}
+	return lgc.getRelatedInstIDs(kit, objID, instCond, key)
+}
+
+// getAssociatedHostIDs get the ids of the hosts associated to the instances of the model matching the condition.
+func (lgc *Logics) getAssociatedHostIDs(kit *rest.Kit, objID string, cond map[string]interface{}, key string) (
+	[]int64, errors.CCErrorCoder) {
+
+	instIDs, ccErr := lgc.getRelatedInstIDs(kit, objID, cond, key)
+	if ccErr != nil {
+		return nil, ccErr
+	}
+
+	if len(instIDs) == 0 {
+		return make([]int64, 0), nil
+	}
+
+	// the association may be from the host to the instance, or from the instance to the host.
+	option := &metadata.InstAsstQueryCondition{
+		ObjID: common.BKInnerObjIDHost,
+		Cond: metadata.QueryCondition{
+			Fields: []string{common.BKObjIDField, common.BKInstIDField, common.BKAsstObjIDField,
+				common.BKAsstInstIDField},
+			Condition: mapstr.MapStr{
+				common.BKDBOR: []mapstr.MapStr{
+					{
+						common.BKObjIDField:      common.BKInnerObjIDHost,
+						common.BKAsstObjIDField:  objID,
+						common.BKAsstInstIDField: mapstr.MapStr{common.BKDBIN: instIDs},
+					},
+					{
+						common.BKObjIDField:     objID,
+						common.BKInstIDField:    mapstr.MapStr{common.BKDBIN: instIDs},
+						common.BKAsstObjIDField: common.BKInnerObjIDHost,
+					},
+				},
+			},
+			Page:           metadata.BasePage{Limit: metadata.HostRelatedFilterMaxMatches + 1},
+			DisableCounter: true,
+		},
+	}
+
+	result, err := lgc.CoreAPI.CoreService().Association().ReadInstAssociation(kit.Ctx, kit.Header, option)
+	if err != nil {
+		blog.Errorf("read host associations failed, option: %#v, err: %v, rid: %s", option, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommHTTPDoRequestFailed)
+	}
+
+	if len(result.Info) > metadata.HostRelatedFilterMaxMatches {
+		blog.Errorf("%s matches too many host associations, rid: %s", key, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommXXExceedLimit, key, metadata.HostRelatedFilterMaxMatches)
+	}
+
+	hostIDs := make([]int64, 0, len(result.Info))
+	for _, asst := range result.Info {
+		if hostID, ok := asst.GetInstID(common.BKInnerObjIDHost); ok {
+			hostIDs = append(hostIDs, hostID)
+		}
+	}
+	return util.IntArrayUnique(hostIDs), nil
+}
+
+// getRelatedInstIDs get the ids of the instances matching the condition, returns error if the instances exceed the
+// max matches of the related filter.
+func (lgc *Logics) getRelatedInstIDs(kit *rest.Kit, objID string, cond mapstr.MapStr, key string) ([]int64,
+	errors.CCErrorCoder) {
+
+	idField := metadata.GetInstIDFieldByObjID(objID)
+	query := &metadata.QueryCondition{
+		Fields:         []string{idField},
+		Condition:      cond,
+		Page:           metadata.BasePage{Limit: metadata.HostRelatedFilterMaxMatches + 1},
+		DisableCounter: true,
+	}
+
+	instances, ccErr := lgc.SearchInstance(kit, objID, query)
+	if ccErr != nil {
+		return nil, ccErr
+	}
+
+	if len(instances) > metadata.HostRelatedFilterMaxMatches {
+		blog.Errorf("%s matches too many %s instances, rid: %s", key, objID, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommXXExceedLimit, key, metadata.HostRelatedFilterMaxMatches)
+	}
+
+	instIDs := make([]int64, 0, len(instances))
+	for _, instance := range instances {
+		instID, err := instance.Int64(idField)
+		if err != nil {
+			blog.Errorf("%s instance %v id is invalid, err: %v, rid: %s", objID, instance, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, idField)
+		}
+		instIDs = append(instIDs, instID)
+	}
+	return instIDs, nil
+}
+
+// intersectRelatedIDs intersects the ids restricted by a related filter with the ids restricted by the former ones.
+func intersectRelatedIDs(scopeIDs, ids []int64) []int64 {
+	if scopeIDs == nil {
+		return ids
+	}
+	return util.IntArrIntersection(scopeIDs, ids)
+}
@@ -0,0 +1,257 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// hostSelectCandidate is a host that can be selected with the failure domains it belongs to.
+type hostSelectCandidate struct {
+	hostID    int64
+	cloudID   int64
+	setIDs    []int64
+	moduleIDs []int64
+}
+
+// SelectHosts selects the hosts matching the filter randomly from the business, a host is skipped if selecting it
+// exceeds any of the spread constraints, so that the selected hosts are spread across the failure domains.
+func (lgc *Logics) SelectHosts(kit *rest.Kit, bizID int64, opt *metadata.HostSelectOption) (
+	*metadata.HostSelectResult, error) {
+
+	candidates, err := lgc.listHostSelectCandidates(kit, bizID, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := opt.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	result := &metadata.HostSelectResult{
+		Candidates: len(candidates),
+		Seed:       seed,
+		Info:       make([]metadata.HostSelectItem, 0),
+	}
+	if len(candidates) == 0 {
+		return result, nil
+	}
+
+	selected := selectSpreadHosts(candidates, opt.Count, opt.Spread, seed)
+	result.Satisfied = len(selected) == opt.Count
+
+	hostIDs := make([]int64, len(selected))
+	for index, candidate := range selected {
+		hostIDs[index] = candidate.hostID
+	}
+
+	fields := make([]string, 0)
+	if len(opt.Fields) != 0 {
+		fields = append(fields, opt.Fields...)
+		if !util.InStrArr(fields, common.BKHostIDField) {
+			fields = append(fields, common.BKHostIDField)
+		}
+	}
+	cond := metadata.QueryCondition{
+		Fields:    fields,
+		Condition: mapstr.MapStr{common.BKHostIDField: mapstr.MapStr{common.BKDBIN: hostIDs}},
+		Page:      metadata.BasePage{Limit: common.BKNoLimit},
+	}
+	hosts, ccErr := lgc.SearchHostInfo(kit, cond)
+	if ccErr != nil {
+		return nil, ccErr
+	}
+
+	hostMap := make(map[int64]mapstr.MapStr, len(hosts))
+	for _, host := range hosts {
+		hostID, err := util.GetInt64ByInterface(host[common.BKHostIDField])
+		if err != nil {
+			blog.Errorf("parse host id failed, host: %v, err: %v, rid: %s", host, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParseDataFailed, common.BKHostIDField)
+		}
+		hostMap[hostID] = host
+	}
+
+	// the selected hosts are returned in the order of the selection, the hosts deleted meanwhile are dropped.
+	for _, candidate := range selected {
+		host, exists := hostMap[candidate.hostID]
+		if !exists {
+			result.Satisfied = false
+			continue
+		}
+		result.Info = append(result.Info, metadata.HostSelectItem{
+			Host:      host,
+			SetIDs:    candidate.setIDs,
+			ModuleIDs: candidate.moduleIDs,
+		})
+	}
+
+	return result, nil
+}
+
+// listHostSelectCandidates lists the hosts matching the filter in the selection scope with their topology.
+func (lgc *Logics) listHostSelectCandidates(kit *rest.Kit, bizID int64, opt *metadata.HostSelectOption) (
+	[]*hostSelectCandidate, error) {
+
+	listOpt := &metadata.ListHosts{
+		BizID:              bizID,
+		SetIDs:             opt.SetIDs,
+		ModuleIDs:          opt.ModuleIDs,
+		HostPropertyFilter: opt.HostPropertyFilter,
+		Fields:             []string{common.BKHostIDField, common.BKCloudIDField},
+		Page:               metadata.BasePage{Limit: common.BKMaxInstanceLimit, Sort: common.BKHostIDField},
+	}
+
+	candidateMap := make(map[int64]*hostSelectCandidate)
+	hostIDs := make([]int64, 0)
+	for {
+		hosts, err := lgc.CoreAPI.CoreService().Host().ListHosts(kit.Ctx, kit.Header, listOpt)
+		if err != nil {
+			blog.Errorf("list hosts failed, option: %#v, err: %v, rid: %s", listOpt, err, kit.Rid)
+			return nil, kit.CCError.CCError(common.CCErrHostGetFail)
+		}
+
+		if hosts.Count > metadata.HostSelectMaxCandidates {
+			blog.Errorf("too many hosts %d to select from, rid: %s", hosts.Count, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommXXExceedLimit, "candidates",
+				metadata.HostSelectMaxCandidates)
+		}
+
+		for _, host := range hosts.Info {
+			hostID, err := util.GetInt64ByInterface(host[common.BKHostIDField])
+			if err != nil {
+				blog.Errorf("parse host id failed, host: %v, err: %v, rid: %s", host, err, kit.Rid)
+				return nil, kit.CCError.CCErrorf(common.CCErrCommParseDataFailed, common.BKHostIDField)
+			}
+			cloudID, err := util.GetInt64ByInterface(host[common.BKCloudIDField])
+			if err != nil {
+				blog.Errorf("parse host cloud id failed, host: %v, err: %v, rid: %s", host, err, kit.Rid)
+				return nil, kit.CCError.CCErrorf(common.CCErrCommParseDataFailed, common.BKCloudIDField)
+			}
+
+			if _, exists := candidateMap[hostID]; exists {
+				continue
+			}
+			candidateMap[hostID] = &hostSelectCandidate{
+				hostID:    hostID,
+				cloudID:   cloudID,
+				setIDs:    make([]int64, 0),
+				moduleIDs: make([]int64, 0),
+			}
+			hostIDs = append(hostIDs, hostID)
+		}
+
+		listOpt.Page.Start += listOpt.Page.Limit
+		if len(hosts.Info) < listOpt.Page.Limit || listOpt.Page.Start >= hosts.Count {
+			break
+		}
+	}
+
+	for start := 0; start < len(hostIDs); start += common.BKMaxInstanceLimit {
+		end := start + common.BKMaxInstanceLimit
+		if end > len(hostIDs) {
+			end = len(hostIDs)
+		}
+
+		relationReq := metadata.HostModuleRelationRequest{
+			ApplicationID: bizID,
+			SetIDArr:      opt.SetIDs,
+			ModuleIDArr:   opt.ModuleIDs,
+			HostIDArr:     hostIDs[start:end],
+			Page:          metadata.BasePage{Limit: common.BKNoLimit},
+			Fields:        []string{common.BKHostIDField, common.BKSetIDField, common.BKModuleIDField},
+		}
+		relations, err := lgc.GetHostRelations(kit, relationReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, relation := range relations {
+			candidate, exists := candidateMap[relation.HostID]
+			if !exists {
+				continue
+			}
+			candidate.moduleIDs = append(candidate.moduleIDs, relation.ModuleID)
+			if !util.ContainsInt64(candidate.setIDs, relation.SetID) {
+				candidate.setIDs = append(candidate.setIDs, relation.SetID)
+			}
+		}
+	}
+
+	candidates := make([]*hostSelectCandidate, 0, len(hostIDs))
+	for _, hostID := range hostIDs {
+		candidates = append(candidates, candidateMap[hostID])
+	}
+	return candidates, nil
+}
+
+// selectSpreadHosts shuffles the candidates with the seed and selects them in turn, a candidate is skipped if any of
+// its modules, sets or cloud area has reached the limit of the spread constraints.
+func selectSpreadHosts(candidates []*hostSelectCandidate, count int, spread metadata.HostSelectSpread,
+	seed int64) []*hostSelectCandidate {
+
+	shuffled := make([]*hostSelectCandidate, len(candidates))
+	copy(shuffled, candidates)
+	sort.Slice(shuffled, func(i, j int) bool { return shuffled[i].hostID < shuffled[j].hostID })
+	random := rand.New(rand.NewSource(seed))
+	random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	moduleCount := make(map[int64]int)
+	setCount := make(map[int64]int)
+	cloudCount := make(map[int64]int)
+	exceeds := func(counter map[int64]int, ids []int64, limit int) bool {
+		if limit <= 0 {
+			return false
+		}
+		for _, id := range ids {
+			if counter[id] >= limit {
+				return true
+			}
+		}
+		return false
+	}
+
+	selected := make([]*hostSelectCandidate, 0, count)
+	for _, candidate := range shuffled {
+		if len(selected) >= count {
+			break
+		}
+
+		if exceeds(moduleCount, candidate.moduleIDs, spread.MaxPerModule) ||
+			exceeds(setCount, candidate.setIDs, spread.MaxPerSet) ||
+			exceeds(cloudCount, []int64{candidate.cloudID}, spread.MaxPerCloudArea) {
+			continue
+		}
+
+		for _, id := range candidate.moduleIDs {
+			moduleCount[id]++
+		}
+		for _, id := range candidate.setIDs {
+			setCount[id]++
+		}
+		cloudCount[candidate.cloudID]++
+		selected = append(selected, candidate)
+	}
+
+	return selected
+}
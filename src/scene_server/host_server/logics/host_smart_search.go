@@ -0,0 +1,273 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/querybuilder"
+	"configcenter/src/common/util"
+)
+
+// ipv4FragmentRegexp matches the ipv4 address or the leading fragment of it, e.g. 10.0.
+var ipv4FragmentRegexp = regexp.MustCompile(`^\d{1,3}\.(\d{1,3}\.?){0,3}$`)
+
+// hostSmartSearchTier is a tier of the host smart search, the hosts matched by the former tier are more relevant.
+// the fields are sorted by their weights, the former field has the higher weight.
+type hostSmartSearchTier struct {
+	source string
+	score  int
+	fields []string
+}
+
+// the base scores of the host smart search tiers, the gap between them is larger than the field weights so that
+// the scores of the tiers never overlap.
+const (
+	hostSmartSearchExactScore    = 100
+	hostSmartSearchPrefixScore   = 60
+	hostSmartSearchFulltextScore = 20
+)
+
+// SmartSearchHost searches the hosts by a single query string, the query is matched exactly, by prefix and by
+// fulltext in turn against the host fields decided by the query type, until the limit is reached.
+func (lgc *Logics) SmartSearchHost(kit *rest.Kit, opt *metadata.HostSmartSearchOption) (
+	*metadata.HostSmartSearchResult, error) {
+
+	queryType, tiers := classifyHostSmartSearchQuery(opt.Query)
+
+	fields := make([]string, 0)
+	if len(opt.Fields) != 0 {
+		fields = append(fields, opt.Fields...)
+		fields = append(fields, common.BKHostIDField)
+		for _, tier := range tiers {
+			fields = append(fields, tier.fields...)
+		}
+		fields = util.StrArrayUnique(fields)
+	}
+
+	hits := make([]metadata.HostSmartSearchHit, 0)
+	hostIDs := make([]int64, 0)
+	for _, tier := range tiers {
+		if len(hits) >= opt.Limit {
+			break
+		}
+
+		hosts, err := lgc.searchHostSmartSearchTier(kit, opt, queryType, tier, fields, hostIDs, opt.Limit-len(hits))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, host := range hosts {
+			hostID, err := util.GetInt64ByInterface(host[common.BKHostIDField])
+			if err != nil {
+				blog.Errorf("parse host id failed, host: %v, err: %v, rid: %s", host, err, kit.Rid)
+				return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKHostIDField)
+			}
+
+			hit := metadata.HostSmartSearchHit{Score: tier.score, Source: tier.source}
+			for index, field := range tier.fields {
+				if matchHostSmartSearchField(tier.source, host[field], opt.Query) {
+					hit.Score += len(tier.fields) - index
+					hit.MatchedField = field
+					break
+				}
+			}
+
+			hit.Host = trimHostSmartSearchFields(host, opt.Fields)
+			hits = append(hits, hit)
+			hostIDs = append(hostIDs, hostID)
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		iID, _ := util.GetInt64ByInterface(hits[i].Host[common.BKHostIDField])
+		jID, _ := util.GetInt64ByInterface(hits[j].Host[common.BKHostIDField])
+		return iID < jID
+	})
+
+	return &metadata.HostSmartSearchResult{QueryType: queryType, Hits: hits}, nil
+}
+
+// searchHostSmartSearchTier searches the hosts matched by the tier, the hosts matched by the former tiers are
+// excluded so that each host is returned only once with its highest score.
+func (lgc *Logics) searchHostSmartSearchTier(kit *rest.Kit, opt *metadata.HostSmartSearchOption, queryType string,
+	tier hostSmartSearchTier, fields []string, excludeIDs []int64, limit int) ([]mapstr.MapStr, error) {
+
+	value := opt.Query
+	if tier.source != metadata.HostSmartSearchExact {
+		// the prefix and fulltext operators are converted to regular expressions, the query is matched literally.
+		value = regexp.QuoteMeta(opt.Query)
+	}
+
+	operator := querybuilder.OperatorEqual
+	switch tier.source {
+	case metadata.HostSmartSearchPrefix:
+		operator = querybuilder.OperatorBeginsWith
+	case metadata.HostSmartSearchFulltext:
+		operator = querybuilder.OperatorContains
+	}
+
+	builder := querybuilder.NewOrBuilder()
+	for _, field := range tier.fields {
+		if field == common.BKHostIDField {
+			hostID, _ := strconv.ParseInt(opt.Query, 10, 64)
+			builder.Field(field).Op(operator, hostID)
+			continue
+		}
+		builder.Field(field).Op(operator, value)
+	}
+
+	if len(excludeIDs) != 0 {
+		builder = querybuilder.NewBuilder().And(builder).Field(common.BKHostIDField).NotIn(excludeIDs)
+	}
+
+	filter, err := builder.Build()
+	if err != nil {
+		blog.Errorf("build %s host filter by query %s failed, err: %v, rid: %s", tier.source, opt.Query, err, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "query")
+	}
+
+	option := &metadata.ListHosts{
+		BizID:              opt.BizID,
+		HostPropertyFilter: filter,
+		Fields:             fields,
+		Page:               metadata.BasePage{Limit: limit, Sort: common.BKHostIDField},
+	}
+	hosts, err := lgc.CoreAPI.CoreService().Host().ListHosts(kit.Ctx, kit.Header, option)
+	if err != nil {
+		blog.Errorf("search %s hosts by query %s failed, err: %v, type: %s, rid: %s", tier.source, opt.Query, err,
+			queryType, kit.Rid)
+		return nil, err
+	}
+
+	result := make([]mapstr.MapStr, 0, len(hosts.Info))
+	for _, host := range hosts.Info {
+		result = append(result, host)
+	}
+	return result, nil
+}
+
+// classifyHostSmartSearchQuery classifies the query and returns the tiers to search the hosts with.
+func classifyHostSmartSearchQuery(query string) (string, []hostSmartSearchTier) {
+	if ip := net.ParseIP(query); ip != nil || ipv4FragmentRegexp.MatchString(query) {
+		ipFields := []string{common.BKHostInnerIPField, common.BKHostOuterIPField}
+		if ip != nil && ip.To4() == nil {
+			ipFields = []string{common.BKHostInnerIPv6Field, common.BKHostOuterIPv6Field}
+		}
+
+		tiers := []hostSmartSearchTier{
+			{source: metadata.HostSmartSearchExact, score: hostSmartSearchExactScore, fields: ipFields},
+		}
+		if ip == nil || ip.To4() != nil {
+			tiers = append(tiers, hostSmartSearchTier{source: metadata.HostSmartSearchPrefix,
+				score: hostSmartSearchPrefixScore, fields: ipFields})
+		}
+		return metadata.HostSmartSearchQueryIP, appendHostSmartSearchFulltextTier(query, tiers,
+			[]string{common.BKHostNameField})
+	}
+
+	textFields := []string{common.BKAssetIDField, common.BKSNField, common.BKHostNameField}
+	exactFields := textFields
+	queryType := metadata.HostSmartSearchQueryText
+	if _, err := strconv.ParseInt(query, 10, 64); err == nil {
+		queryType = metadata.HostSmartSearchQueryNumber
+		exactFields = append([]string{common.BKHostIDField}, textFields...)
+	}
+
+	tiers := []hostSmartSearchTier{
+		{source: metadata.HostSmartSearchExact, score: hostSmartSearchExactScore, fields: exactFields},
+		{source: metadata.HostSmartSearchPrefix, score: hostSmartSearchPrefixScore, fields: textFields},
+	}
+	return queryType, appendHostSmartSearchFulltextTier(query, tiers,
+		append(textFields, "bk_comment"))
+}
+
+// appendHostSmartSearchFulltextTier appends the fulltext tier if the query is long enough to be relevant.
+func appendHostSmartSearchFulltextTier(query string, tiers []hostSmartSearchTier,
+	fields []string) []hostSmartSearchTier {
+
+	if len([]rune(query)) < metadata.HostSmartSearchMinFulltextLength {
+		return tiers
+	}
+
+	return append(tiers, hostSmartSearchTier{source: metadata.HostSmartSearchFulltext,
+		score: hostSmartSearchFulltextScore, fields: fields})
+}
+
+// matchHostSmartSearchField checks whether the host field value is matched by the query in the way of the source,
+// the ip fields may be a comma separated string or an array of multiple ips.
+func matchHostSmartSearchField(source string, value interface{}, query string) bool {
+	values := make([]string, 0)
+	switch val := value.(type) {
+	case nil:
+		return false
+	case string:
+		values = strings.Split(val, ",")
+	case []interface{}:
+		for _, item := range val {
+			values = append(values, util.GetStrByInterface(item))
+		}
+	case []string:
+		values = val
+	default:
+		values = append(values, fmt.Sprint(val))
+	}
+
+	for _, item := range values {
+		switch source {
+		case metadata.HostSmartSearchExact:
+			if item == query {
+				return true
+			}
+		case metadata.HostSmartSearchPrefix:
+			if strings.HasPrefix(item, query) {
+				return true
+			}
+		case metadata.HostSmartSearchFulltext:
+			if strings.Contains(strings.ToLower(item), strings.ToLower(query)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// trimHostSmartSearchFields removes the host fields that are only used to match the query, the host id is kept to
+// identify the host.
+func trimHostSmartSearchFields(host mapstr.MapStr, fields []string) mapstr.MapStr {
+	if len(fields) == 0 {
+		return host
+	}
+
+	result := mapstr.New()
+	result[common.BKHostIDField] = host[common.BKHostIDField]
+	for _, field := range fields {
+		if value, exists := host[field]; exists {
+			result[field] = value
+		}
+	}
+	return result
+}
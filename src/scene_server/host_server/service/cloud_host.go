@@ -30,7 +30,7 @@ import (
 // AddCloudHostToBiz add cloud host to biz idle module
 func (s *Service) AddCloudHostToBiz(ctx *rest.Contexts) {
 	input := new(metadata.AddCloudHostToBizParam)
-	if err := ctx.DecodeInto(input); err != nil {
+	if err := ctx.DecodeStreamInto(input, map[string]int{"host_info": common.BKWriteOpLimit}); err != nil {
 		ctx.RespAutoError(err)
 		return
 	}
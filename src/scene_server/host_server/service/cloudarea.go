@@ -302,71 +302,128 @@ func (s *Service) DeletePlat(ctx *rest.Contexts) {
 		return
 	}
 
-	params := new(meta.QueryInput)
-	params.Fields = common.BKHostIDField
-	params.Condition = map[string]interface{}{
-		common.BKCloudIDField: platID,
+	// only the cloud area without hosts and cloud sync tasks could be deleted
+	if err := s.Logic.CheckCloudAreaDeletable(ctx.Kit, platID); err != nil {
+		ctx.RespAutoError(err)
+		return
 	}
 
-	hostRes, err := s.CoreAPI.CoreService().Host().GetHosts(ctx.Kit.Ctx, ctx.Kit.Header, params)
-	if nil != err {
-		blog.Errorf("DelPlat search host error: %s, input:%+v,rid:%s", err.Error(), platID, ctx.Kit.Rid)
-		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrHostGetFail))
+	// auth: check authorization
+	if err := s.AuthManager.AuthorizeByPlatIDs(ctx.Kit.Ctx, ctx.Kit.Header, authmeta.Delete, platID); err != nil {
+		blog.Errorf("check delete plat authorization failed, plat: %d, err: %v, rid: %s", platID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommAuthorizeFailed))
 		return
 	}
 
-	// only empty plat could be delete
-	if 0 < hostRes.Count {
-		blog.Errorf("DelPlat plat [%d] has host data, can not delete,rid:%s", platID, ctx.Kit.Rid)
-		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrTopoHasHostCheckFailed))
+	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
+		return s.Logic.DeleteCloudArea(ctx.Kit, platID)
+	})
+
+	if txnErr != nil {
+		ctx.RespAutoError(txnErr)
 		return
 	}
 
-	// auth: check authorization
-	if err := s.AuthManager.AuthorizeByPlatIDs(ctx.Kit.Ctx, ctx.Kit.Header, authmeta.Delete, platID); err != nil {
-		blog.Errorf("check delete plat authorization failed, plat: %d, err: %v, rid: %s", platID, err, ctx.Kit.Rid)
-		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommAuthorizeFailed))
+	ctx.RespEntity(nil)
+
+}
+
+// FindCloudAreaUsage find the hosts and the cloud sync tasks bound to the cloud areas, which need to be released
+// before the cloud areas are deleted.
+func (s *Service) FindCloudAreaUsage(ctx *rest.Contexts) {
+	opt := new(metadata.CloudAreaUsageOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
 		return
 	}
 
-	// generate audit log.
-	audit := auditlog.NewCloudAreaAuditLog(s.CoreAPI.CoreService())
-	generateAuditParameter := auditlog.NewGenerateAuditCommonParameter(ctx.Kit, metadata.AuditDelete)
-	logs, err := audit.GenerateAuditLog(generateAuditParameter, []int64{platID})
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	usages, err := s.Logic.GetCloudAreaUsages(ctx.Kit, opt)
 	if err != nil {
-		blog.Errorf("generate audit log failed before delete cloud area, err: %v, rid: %s", err, ctx.Kit.Rid)
 		ctx.RespAutoError(err)
 		return
 	}
 
-	delCond := &meta.DeleteOption{
-		Condition: mapstr.MapStr{common.BKCloudIDField: platID},
+	ctx.RespEntity(usages)
+}
+
+// RelocateCloudAreaHosts relocate all the hosts of the cloud area to the target cloud area.
+func (s *Service) RelocateCloudAreaHosts(ctx *rest.Contexts) {
+	cloudID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKCloudIDField), 10, 64)
+	if err != nil {
+		blog.Errorf("parse cloud id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKCloudIDField))
+		return
 	}
 
-	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
-		_, err := s.CoreAPI.CoreService().Instance().DeleteInstance(ctx.Kit.Ctx, ctx.Kit.Header,
-			common.BKInnerObjIDPlat, delCond)
-		if nil != err {
-			blog.Errorf("DelPlat do error: %v, input:%d,rid:%s", err, platID, ctx.Kit.Rid)
-			return ctx.Kit.CCError.Errorf(common.CCErrTopoInstDeleteFailed)
-		}
+	opt := new(metadata.RelocateCloudAreaHostsOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
 
-		// save audit log.
-		if err := audit.SaveAuditLog(ctx.Kit, logs...); err != nil {
-			blog.Errorf("save audit log failed after delete cloud area, err: %v, rid: %s", err, ctx.Kit.Rid)
-			return err
-		}
+	if opt.TargetCloudID < 0 || opt.TargetCloudID == cloudID {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "target_bk_cloud_id"))
+		return
+	}
 
-		return nil
-	})
+	err = s.AuthManager.AuthorizeByPlatIDs(ctx.Kit.Ctx, ctx.Kit.Header, authmeta.Update, cloudID, opt.TargetCloudID)
+	if err != nil {
+		blog.Errorf("check update plat authorization failed, plats: %d, %d, err: %v, rid: %s", cloudID,
+			opt.TargetCloudID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommAuthorizeFailed))
+		return
+	}
 
-	if txnErr != nil {
-		ctx.RespAutoError(txnErr)
+	relocated, err := s.Logic.RelocateCloudAreaHosts(ctx.Kit, cloudID, opt.TargetCloudID)
+	if err != nil {
+		ctx.RespAutoError(err)
 		return
 	}
 
-	ctx.RespEntity(nil)
+	ctx.RespEntity(metadata.RelocateCloudAreaHostsResult{RelocatedCount: relocated})
+}
+
+// MergeCloudArea merge the source cloud areas into the target cloud area, the source cloud areas are deleted.
+func (s *Service) MergeCloudArea(ctx *rest.Contexts) {
+	opt := new(metadata.MergeCloudAreaOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
 
+	err := s.AuthManager.AuthorizeByPlatIDs(ctx.Kit.Ctx, ctx.Kit.Header, authmeta.Delete, opt.SourceCloudIDs...)
+	if err != nil {
+		blog.Errorf("check delete plat authorization failed, plats: %v, err: %v, rid: %s", opt.SourceCloudIDs, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommAuthorizeFailed))
+		return
+	}
+
+	err = s.AuthManager.AuthorizeByPlatIDs(ctx.Kit.Ctx, ctx.Kit.Header, authmeta.Update, opt.TargetCloudID)
+	if err != nil {
+		blog.Errorf("check update plat authorization failed, plat: %d, err: %v, rid: %s", opt.TargetCloudID, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommAuthorizeFailed))
+		return
+	}
+
+	result, err := s.Logic.MergeCloudAreas(ctx.Kit, opt)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
 }
 
 // UpdatePlat TODO
@@ -24,6 +24,7 @@ import (
 	"configcenter/src/common/http/rest"
 	"configcenter/src/common/json"
 	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/pagelimit"
 	parser "configcenter/src/common/paraparse"
 	"configcenter/src/scene_server/host_server/logics"
 )
@@ -48,6 +49,24 @@ func (s *Service) CreateDynamicGroup(ctx *rest.Contexts) {
 		ctx.RespAutoError(ctx.Kit.CCError.Errorf(common.CCErrCommParamsIsInvalid, err.Error()))
 		return
 	}
+
+	// the new dynamic group has no id yet, so it can not be referenced by the composed groups.
+	newDynamicGroup.ID = ""
+	err := logics.NewLogics(s.Engine, s.CacheDB, s.AuthManager).
+		ValidateDynamicGroupComposition(ctx.Kit, newDynamicGroup.AppID, &newDynamicGroup)
+	if err != nil {
+		blog.Errorf("create dynamic group failed, invalid composition, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	err = logics.NewLogics(s.Engine, s.CacheDB, s.AuthManager).
+		ValidateDynamicGroupDuplicate(ctx.Kit, newDynamicGroup.AppID, &newDynamicGroup)
+	if err != nil {
+		blog.Errorf("create dynamic group failed, duplicate query, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
 	newDynamicGroup.CreateUser = ctx.Kit.User
 	newDynamicGroup.CreateTime = time.Now().UTC()
 	response := &meta.IDResult{}
@@ -186,6 +205,23 @@ func (s *Service) UpdateDynamicGroup(ctx *rest.Contexts) {
 			ctx.RespAutoError(ctx.Kit.CCError.Errorf(common.CCErrCommParamsIsInvalid, err.Error()))
 			return
 		}
+
+		updatedGroup := &meta.DynamicGroup{ID: targetID, AppID: bizIDInt64, ObjID: objectID, Info: *dynamicGroupInfo}
+		err = logics.NewLogics(s.Engine, s.CacheDB, s.AuthManager).
+			ValidateDynamicGroupComposition(ctx.Kit, bizIDInt64, updatedGroup)
+		if err != nil {
+			blog.Errorf("update dynamic group failed, invalid composition, err: %v, rid: %s", err, ctx.Kit.Rid)
+			ctx.RespAutoError(err)
+			return
+		}
+
+		err = logics.NewLogics(s.Engine, s.CacheDB, s.AuthManager).
+			ValidateDynamicGroupDuplicate(ctx.Kit, bizIDInt64, updatedGroup)
+		if err != nil {
+			blog.Errorf("update dynamic group failed, duplicate query, err: %v, rid: %s", err, ctx.Kit.Rid)
+			ctx.RespAutoError(err)
+			return
+		}
 		updates[common.BKObjIDField] = objectID
 		updates["info"] = dynamicGroupInfo
 
@@ -273,6 +309,34 @@ func (s *Service) DeleteDynamicGroup(ctx *rest.Contexts) {
 	}
 	dynamicGroup := result.Data
 
+	// the dynamic group can not be deleted if it's composed by other dynamic groups.
+	refCond := &meta.QueryCondition{
+		Condition: map[string]interface{}{
+			common.BKAppIDField:          dynamicGroup.AppID,
+			"info.composition.group_ids": targetID,
+		},
+		Fields: []string{common.BKFieldName},
+		Page:   meta.BasePage{Limit: 1},
+	}
+	refResult, err := s.CoreAPI.CoreService().Host().SearchDynamicGroup(ctx.Kit.Ctx, ctx.Kit.Header, refCond)
+	if err != nil {
+		blog.Errorf("search dynamic groups composing %s failed, err: %v, rid: %s", targetID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommHTTPDoRequestFailed))
+		return
+	}
+	if err := refResult.CCError(); err != nil {
+		blog.Errorf("search dynamic groups composing %s failed, err: %v, rid: %s", targetID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+	if len(refResult.Data.Info) > 0 {
+		blog.Errorf("dynamic group %s is composed by %s, rid: %s", targetID, refResult.Data.Info[0].Name,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrHostDynamicGroupReferenced,
+			refResult.Data.Info[0].Name))
+		return
+	}
+
 	// delete base on auto run txn with func.
 	autoRunTxnFunc := func() error {
 		result, err := s.CoreAPI.CoreService().Host().DeleteDynamicGroup(ctx.Kit.Ctx, bizID, targetID, ctx.Kit.Header)
@@ -357,16 +421,9 @@ func (s *Service) SearchDynamicGroup(ctx *rest.Contexts) {
 		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommJSONUnmarshalFailed))
 		return
 	}
-	if input.Page.Start < 0 {
-		blog.Errorf("search dynamic groups failed, invalid page start param, input: %+v, rid: %s",
-			input, ctx.Kit.Rid)
-		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "start"))
-		return
-	}
-	if input.Page.IsIllegal() {
-		blog.Errorf("search dynamic groups failed, invalid page limit param, input: %+v, rid: %s",
-			input, ctx.Kit.Rid)
-		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "limit"))
+	if rawErr := pagelimit.Validate("search_dynamic_group", &input.Page); rawErr.ErrCode != 0 {
+		blog.Errorf("search dynamic groups failed, invalid page param, input: %+v, rid: %s", input, ctx.Kit.Rid)
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
 		return
 	}
 
@@ -437,14 +494,9 @@ func (s *Service) ExecuteDynamicGroup(ctx *rest.Contexts) {
 		return
 	}
 
-	if input.Page.Start < 0 {
-		blog.Errorf("execute dynamic group failed, invalid page start param, input: %+v, rid: %s", input, ctx.Kit.Rid)
-		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommJSONUnmarshalFailed))
-		return
-	}
-	if input.Page.IsIllegal() {
-		blog.Errorf("execute dynamic group failed, invalid page limit param, input: %+v, rid: %s", input, ctx.Kit.Rid)
-		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommJSONUnmarshalFailed))
+	if rawErr := pagelimit.Validate("execute_dynamic_group", &input.Page); rawErr.ErrCode != 0 {
+		blog.Errorf("execute dynamic group failed, invalid page param, input: %+v, rid: %s", input, ctx.Kit.Rid)
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
 		return
 	}
 	searchPage := input.Page
@@ -471,68 +523,17 @@ func (s *Service) ExecuteDynamicGroup(ctx *rest.Contexts) {
 	// target dynamic group.
 	targetDynamicGroup := result.Data
 
-	// build search conditions.
-	searchConditions := []meta.SearchCondition{}
-
-	// parse all dynamic group conditions to search condition.
-	for _, cond := range targetDynamicGroup.Info.Condition {
-		searchCondition := meta.SearchCondition{ObjectID: cond.ObjID, Condition: []meta.ConditionItem{}}
-
-		// build condition items.
-		for _, item := range cond.Condition {
-			condItem := meta.ConditionItem{Field: item.Field, Operator: item.Operator, Value: item.Value}
-			searchCondition.Condition = append(searchCondition.Condition, condItem)
-		}
-		searchCondition.TimeCondition = cond.TimeCondition
-		searchConditions = append(searchConditions, searchCondition)
-	}
-
-	// execute dynamic group with target object type.
-	if targetDynamicGroup.ObjID == common.BKInnerObjIDHost {
-		// build host search conditions.
-		searchHostCondition := meta.HostCommonSearch{AppID: bizIDInt64, Condition: searchConditions, Page: searchPage}
-
-		// execute host object dynamic group.
-		data, err := logics.NewLogics(s.Engine, s.CacheDB, s.AuthManager).
-			ExecuteHostDynamicGroup(ctx.Kit, &searchHostCondition, input.Fields, input.DisableCounter)
-		if err != nil {
-			blog.Errorf("execute dynamic group failed, search hosts, err: %+v, bizID: %s, ID: %s, rid: %s",
-				err, bizID, targetID, ctx.Kit.Rid)
-			ctx.RespAutoError(ctx.Kit.CCError.Errorf(common.CCErrGetUserCustomQueryDetailFailed, err.Error()))
-			return
-		}
-
-		ctx.RespEntity(meta.InstDataInfo{
-			Count: data.Count,
-			Info:  data.Info,
-		})
-		return
-
-	} else if targetDynamicGroup.ObjID == common.BKInnerObjIDSet {
-		// build host search conditions.
-		searchSetCondition := meta.SetCommonSearch{AppID: bizIDInt64, Condition: searchConditions, Page: searchPage}
-
-		// execute set object dynamic group.
-		data, err := logics.NewLogics(s.Engine, s.CacheDB, s.AuthManager).
-			ExecuteSetDynamicGroup(ctx.Kit, &searchSetCondition, input.Fields, input.DisableCounter)
-		if err != nil {
-			blog.Errorf("execute dynamic group failed, search sets, err: %+v, bizID: %s, ID: %s, rid: %s",
-				err, bizID, targetID, ctx.Kit.Rid)
-			ctx.RespAutoError(ctx.Kit.CCError.Errorf(common.CCErrGetUserCustomQueryDetailFailed, err.Error()))
-			return
-		}
-
-		ctx.RespEntity(meta.InstDataInfo{
-			Count: data.Count,
-			Info:  data.Info,
-		})
+	// execute dynamic group with target object type, the composed groups are expanded.
+	data, err := logics.NewLogics(s.Engine, s.CacheDB, s.AuthManager).ExecuteDynamicGroup(ctx.Kit, bizIDInt64,
+		&targetDynamicGroup, searchPage, input.Fields, input.DisableCounter)
+	if err != nil {
+		blog.Errorf("execute dynamic group failed, err: %+v, bizID: %s, ID: %s, rid: %s", err, bizID, targetID,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.Errorf(common.CCErrGetUserCustomQueryDetailFailed, err.Error()))
 		return
 	}
 
-	// unknown group object type, should no-reach here.
-	blog.Errorf("execute dynamic group failed, unknown group object type[%s], bizID: %s, ID: %s, rid: %s",
-		targetDynamicGroup.ObjID, bizID, targetID, ctx.Kit.Rid)
-	ctx.RespAutoError(ctx.Kit.CCError.Errorf(common.CCSystemUnknownError))
+	ctx.RespEntity(data)
 }
 
 // changeTimeToMatchLocalZone TODO
@@ -17,12 +17,15 @@ import (
 	"sort"
 	"strconv"
 
+	authmeta "configcenter/src/ac/meta"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/errors"
 	"configcenter/src/common/http/rest"
 	"configcenter/src/common/mapstr"
 	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/pagelimit"
+	"configcenter/src/common/querybuilder"
 	"configcenter/src/common/util"
 )
 
@@ -533,9 +536,13 @@ func (s *Service) listBizHosts(ctx *rest.Contexts, bizID int64, parameter meta.L
 	rid := ctx.Kit.Rid
 	defErr := ctx.Kit.CCError
 
-	if parameter.Page.IsIllegal() {
+	if rawErr := pagelimit.Validate("list_biz_hosts", &parameter.Page); rawErr.ErrCode != 0 {
 		blog.Errorf("ListBizHosts failed, page limit %d illegal, rid:%s", parameter.Page.Limit, ctx.Kit.Rid)
-		return result, defErr.CCErrorf(common.CCErrCommParamsInvalid, "page.limit")
+		return result, rawErr.ToCCError(defErr)
+	}
+
+	if ccErr := s.authorizeSearchExplain(ctx, parameter.Explain); ccErr != nil {
+		return result, ccErr
 	}
 
 	if len(parameter.SetIDs) != 0 && len(parameter.SetCond) != 0 {
@@ -594,13 +601,33 @@ func (s *Service) listBizHosts(ctx *rest.Contexts, bizID int64, parameter meta.L
 		moduleIDList = parameter.ModuleIDs
 	}
 
+	hostPropertyFilter := parameter.HostPropertyFilter
+	if len(parameter.RelatedFilters) != 0 {
+		scope, ccErr := s.Logic.ParseHostRelatedFilters(ctx.Kit, bizID, parameter.RelatedFilters)
+		if ccErr != nil {
+			return nil, ccErr
+		}
+
+		var setMatched, moduleMatched bool
+		setIDList, setMatched = restrictRelatedIDs(setIDList, scope.SetIDs)
+		moduleIDList, moduleMatched = restrictRelatedIDs(moduleIDList, scope.ModuleIDs)
+		if scope.Empty || !setMatched || !moduleMatched {
+			return &meta.ListHostResult{Count: 0, Info: []map[string]interface{}{}}, nil
+		}
+
+		if scope.HostIDs != nil {
+			hostPropertyFilter = addHostIDFilter(hostPropertyFilter, scope.HostIDs)
+		}
+	}
+
 	option := &meta.ListHosts{
 		BizID:              bizID,
 		SetIDs:             setIDList,
 		ModuleIDs:          moduleIDList,
-		HostPropertyFilter: parameter.HostPropertyFilter,
+		HostPropertyFilter: hostPropertyFilter,
 		Fields:             parameter.Fields,
 		Page:               parameter.Page,
+		Explain:            parameter.Explain,
 	}
 	hostResult, err := s.CoreAPI.CoreService().Host().ListHosts(ctx.Kit.Ctx, header, option)
 	if err != nil {
@@ -610,6 +637,29 @@ func (s *Service) listBizHosts(ctx *rest.Contexts, bizID int64, parameter meta.L
 	return hostResult, nil
 }
 
+// restrictRelatedIDs restricts the ids of the host query with the ids of the related filters' scope, the empty ids
+// means no restriction, returns false if no id matches both of them.
+func restrictRelatedIDs(ids, scopeIDs []int64) ([]int64, bool) {
+	if scopeIDs == nil {
+		return ids, true
+	}
+
+	if len(ids) != 0 {
+		scopeIDs = util.IntArrIntersection(ids, scopeIDs)
+	}
+	return scopeIDs, len(scopeIDs) != 0
+}
+
+// addHostIDFilter adds the host id condition to the host property filter as a scope rule, the condition is added to
+// the rules of the AND filter directly so that the filter does not get deeper.
+func addHostIDFilter(filter *querybuilder.QueryFilter, hostIDs []int64) *querybuilder.QueryFilter {
+	return filter.WithScope(querybuilder.AtomRule{
+		Field:    common.BKHostIDField,
+		Operator: querybuilder.OperatorIn,
+		Value:    hostIDs,
+	})
+}
+
 // ListHostsWithNoBiz list host for no biz case merely
 func (s *Service) ListHostsWithNoBiz(ctx *rest.Contexts) {
 	header := ctx.Kit.Header
@@ -630,11 +680,17 @@ func (s *Service) ListHostsWithNoBiz(ctx *rest.Contexts) {
 		return
 	}
 
+	if ccErr := s.authorizeSearchExplain(ctx, parameter.Explain); ccErr != nil {
+		ctx.RespAutoError(ccErr)
+		return
+	}
+
 	parameter.Page.Sort = common.BKHostIDField
 	option := &meta.ListHosts{
 		HostPropertyFilter: parameter.HostPropertyFilter,
 		Fields:             parameter.Fields,
 		Page:               parameter.Page,
+		Explain:            parameter.Explain,
 	}
 
 	ctx.SetReadPreference(common.SecondaryPreferredMode)
@@ -648,6 +704,19 @@ func (s *Service) ListHostsWithNoBiz(ctx *rest.Contexts) {
 
 }
 
+// authorizeSearchExplain only the platform admin can use the explain mode, since it exposes the db details.
+func (s *Service) authorizeSearchExplain(ctx *rest.Contexts, explain bool) errors.CCErrorCoder {
+	if !explain {
+		return nil
+	}
+
+	if err := s.AuthManager.AuthorizeConfigAdmin(ctx.Kit.Ctx, ctx.Kit.Header); err != nil {
+		blog.Errorf("authorize search explain failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		return ctx.Kit.CCError.CCError(common.CCErrCommAuthorizeFailed)
+	}
+	return nil
+}
+
 // ListBizHostsTopo list hosts under business specified by path parameter with their topology information
 func (s *Service) ListBizHostsTopo(ctx *rest.Contexts) {
 	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
@@ -926,6 +995,57 @@ func (s *Service) ListHostDetailAndTopology(ctx *rest.Contexts) {
 	return
 }
 
+// FindHostsByIDs finds the hosts by an id list, the hosts are returned in the order of the requested ids, with the
+// ids that are not found or not authorized to view reported.
+func (s *Service) FindHostsByIDs(ctx *rest.Contexts) {
+	option := new(meta.FindByIDsOption)
+	if err := ctx.DecodeInto(option); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := option.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	if len(option.Fields) > 0 && !util.InStrArr(option.Fields, common.BKHostIDField) {
+		option.Fields = append(option.Fields, common.BKHostIDField)
+	}
+
+	// read data from secondary mongodb nodes
+	ctx.SetReadPreference(common.SecondaryPreferredMode)
+
+	cond := meta.QueryCondition{
+		Fields:    option.Fields,
+		Condition: mapstr.MapStr{common.BKHostIDField: mapstr.MapStr{common.BKDBIN: option.IDs}},
+		Page:      meta.BasePage{Limit: common.BKNoLimit},
+	}
+	hosts, ccErr := s.Logic.SearchHostInfo(ctx.Kit, cond)
+	if ccErr != nil {
+		blog.Errorf("find hosts by ids(%v) failed, err: %v, rid: %s", option.IDs, ccErr, ctx.Kit.Rid)
+		ctx.RespAutoError(ccErr)
+		return
+	}
+
+	noPermission, err := s.AuthManager.ListUnauthorizedHostIDs(ctx.Kit.Ctx, ctx.Kit.Header, authmeta.Find,
+		option.IDs...)
+	if err != nil {
+		blog.Errorf("authorize hosts(%v) failed, err: %v, rid: %s", option.IDs, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommCheckAuthorizeFailed))
+		return
+	}
+
+	result, err := meta.NewFindByIDsResult(option.IDs, common.BKHostIDField, hosts, noPermission)
+	if err != nil {
+		blog.Errorf("arrange hosts by ids failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParseDataFailed, common.BKHostIDField))
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
 // CountTopoNodeHosts TODO
 func (s *Service) CountTopoNodeHosts(ctx *rest.Contexts) {
 
@@ -13,8 +13,6 @@
 package service
 
 import (
-	"encoding/json"
-	"io/ioutil"
 	"strconv"
 	"strings"
 	"sync"
@@ -297,7 +295,7 @@ func (s *Service) GetHostInstanceProperties(ctx *rest.Contexts) {
 // add host to host resource pool
 func (s *Service) AddHost(ctx *rest.Contexts) {
 	hostList := new(meta.HostList)
-	if err := ctx.DecodeInto(&hostList); nil != err {
+	if err := ctx.DecodeStreamInto(hostList, nil); nil != err {
 		ctx.RespAutoError(err)
 		return
 	}
@@ -356,7 +354,7 @@ func (s *Service) AddHost(ctx *rest.Contexts) {
 // add host come from excel to host resource pool
 func (s *Service) AddHostByExcel(ctx *rest.Contexts) {
 	hostList := new(meta.HostList)
-	if err := ctx.DecodeInto(&hostList); nil != err {
+	if err := ctx.DecodeStreamInto(hostList, nil); nil != err {
 		ctx.RespAutoError(err)
 		return
 	}
@@ -411,15 +409,8 @@ func (s *Service) AddHostByExcel(ctx *rest.Contexts) {
 func (s *Service) AddHostToResourcePool(ctx *rest.Contexts) {
 
 	hostList := new(meta.AddHostToResourcePoolHostList)
-	body, err := ioutil.ReadAll(ctx.Request.Request.Body)
-	if err != nil {
-		blog.Errorf("read request body failed, err: %v, rid: %s", err, ctx.Kit.Rid)
-		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommHTTPReadBodyFailed))
-		return
-	}
-	if err := json.Unmarshal(body, hostList); err != nil {
-		blog.Errorf("add host failed with decode body err: %v, body: %s, rid:%s", err, string(body), ctx.Kit.Rid)
-		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommJSONUnmarshalFailed))
+	if err := ctx.DecodeStreamInto(hostList, nil); err != nil {
+		ctx.RespAutoError(err)
 		return
 	}
 	if hostList.HostInfo == nil {
@@ -843,7 +834,7 @@ func (s *Service) UpdateHostPropertyBatch(ctx *rest.Contexts) {
 func (s *Service) NewHostSyncAppTopo(ctx *rest.Contexts) {
 
 	hostList := new(meta.HostSyncList)
-	if err := ctx.DecodeInto(&hostList); nil != err {
+	if err := ctx.DecodeStreamInto(hostList, map[string]int{"host_info": common.BatchHostAddMaxRow}); nil != err {
 		ctx.RespAutoError(err)
 		return
 	}
@@ -0,0 +1,305 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// CreateHostRecyclePolicy create a host recycle policy of the business
+func (s *Service) CreateHostRecyclePolicy(ctx *rest.Contexts) {
+	bizID, err := parseHostRecycleBizID(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	policy := new(metadata.HostRecyclePolicy)
+	if err := ctx.DecodeInto(policy); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	policy.BizID = bizID
+
+	if rawErr := policy.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := s.CoreAPI.CoreService().Host().CreateHostRecyclePolicy(ctx.Kit.Ctx, ctx.Kit.Header, policy)
+	if err != nil {
+		blog.Errorf("create host recycle policy failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// UpdateHostRecyclePolicy update a host recycle policy of the business
+func (s *Service) UpdateHostRecyclePolicy(ctx *rest.Contexts) {
+	policy, err := s.getHostRecyclePolicy(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	data := mapstr.MapStr{}
+	if err := ctx.DecodeInto(&data); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	// the run time is maintained by the host recycle timer
+	data.Remove("last_run_time")
+
+	// validate the policy which is merged with the updated fields
+	if err := data.MarshalJSONInto(policy); err != nil {
+		blog.Errorf("parse host recycle policy update data failed, data: %#v, err: %v, rid: %s", data, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommJSONUnmarshalFailed))
+		return
+	}
+	if rawErr := policy.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	err = s.CoreAPI.CoreService().Host().UpdateHostRecyclePolicy(ctx.Kit.Ctx, ctx.Kit.Header, policy.ID, data)
+	if err != nil {
+		blog.Errorf("update host recycle policy %d failed, err: %v, rid: %s", policy.ID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// DeleteHostRecyclePolicy delete a host recycle policy of the business
+func (s *Service) DeleteHostRecyclePolicy(ctx *rest.Contexts) {
+	policy, err := s.getHostRecyclePolicy(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if err := s.CoreAPI.CoreService().Host().DeleteHostRecyclePolicy(ctx.Kit.Ctx, ctx.Kit.Header,
+		policy.ID); err != nil {
+		blog.Errorf("delete host recycle policy %d failed, err: %v, rid: %s", policy.ID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// ListHostRecyclePolicy list the host recycle policies of the business
+func (s *Service) ListHostRecyclePolicy(ctx *rest.Contexts) {
+	bizID, err := parseHostRecycleBizID(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	opt := new(metadata.ListHostRecyclePolicyOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if err := opt.Page.ValidateLimit(common.BKMaxPageSize); err != nil {
+		blog.Errorf("list host recycle policy page is invalid, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "page"))
+		return
+	}
+
+	query := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{common.BKAppIDField: bizID},
+		Page:      opt.Page,
+	}
+	result, err := s.CoreAPI.CoreService().Host().SearchHostRecyclePolicy(ctx.Kit.Ctx, ctx.Kit.Header, query)
+	if err != nil {
+		blog.Errorf("search host recycle policy failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// RunHostRecyclePolicy run the host recycle policy immediately, or only preview the matched hosts if dry run
+func (s *Service) RunHostRecyclePolicy(ctx *rest.Contexts) {
+	policy, err := s.getHostRecyclePolicy(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	opt := new(metadata.RunHostRecyclePolicyOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	report, err := s.Logic.RunHostRecyclePolicy(ctx.Kit, policy, opt.DryRun)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(report)
+}
+
+// ListHostRecycleReport list the host recycle reports of the business
+func (s *Service) ListHostRecycleReport(ctx *rest.Contexts) {
+	bizID, err := parseHostRecycleBizID(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	opt := new(metadata.ListHostRecycleReportOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if err := opt.Page.ValidateLimit(common.BKMaxPageSize); err != nil {
+		blog.Errorf("list host recycle report page is invalid, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "page"))
+		return
+	}
+
+	cond := mapstr.MapStr{common.BKAppIDField: bizID}
+	if opt.PolicyID > 0 {
+		cond["policy_id"] = opt.PolicyID
+	}
+	if len(opt.Status) > 0 {
+		cond["status"] = opt.Status
+	}
+
+	page := opt.Page
+	if len(page.Sort) == 0 {
+		// the latest reports are listed first
+		page.Sort = "-" + common.BKFieldID
+	}
+
+	query := &metadata.QueryCondition{Condition: cond, Page: page}
+	result, err := s.CoreAPI.CoreService().Host().SearchHostRecycleReport(ctx.Kit.Ctx, ctx.Kit.Header, query)
+	if err != nil {
+		blog.Errorf("search host recycle report failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// ReviewHostRecycleReport approve or reject a pending host recycle report of the business
+func (s *Service) ReviewHostRecycleReport(ctx *rest.Contexts) {
+	bizID, err := parseHostRecycleBizID(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	id, err := parseHostRecycleID(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	opt := new(metadata.ReviewHostRecycleReportOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	query := &metadata.QueryCondition{
+		Condition:      mapstr.MapStr{common.BKFieldID: id, common.BKAppIDField: bizID},
+		DisableCounter: true,
+	}
+	result, err := s.CoreAPI.CoreService().Host().SearchHostRecycleReport(ctx.Kit.Ctx, ctx.Kit.Header, query)
+	if err != nil {
+		blog.Errorf("search host recycle report %d failed, err: %v, rid: %s", id, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if len(result.Info) == 0 {
+		blog.Errorf("host recycle report %d is not exist in biz %d, rid: %s", id, bizID, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommNotFound))
+		return
+	}
+
+	if err := s.Logic.ReviewHostRecycleReport(ctx.Kit, &result.Info[0], opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+func (s *Service) getHostRecyclePolicy(ctx *rest.Contexts) (*metadata.HostRecyclePolicy, error) {
+	bizID, err := parseHostRecycleBizID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := parseHostRecycleID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &metadata.QueryCondition{
+		Condition:      mapstr.MapStr{common.BKFieldID: id, common.BKAppIDField: bizID},
+		DisableCounter: true,
+	}
+	result, err := s.CoreAPI.CoreService().Host().SearchHostRecyclePolicy(ctx.Kit.Ctx, ctx.Kit.Header, query)
+	if err != nil {
+		blog.Errorf("search host recycle policy %d failed, err: %v, rid: %s", id, err, ctx.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Info) == 0 {
+		blog.Errorf("host recycle policy %d is not exist in biz %d, rid: %s", id, bizID, ctx.Kit.Rid)
+		return nil, ctx.Kit.CCError.CCError(common.CCErrCommNotFound)
+	}
+
+	return &result.Info[0], nil
+}
+
+func parseHostRecycleBizID(ctx *rest.Contexts) (int64, error) {
+	bizIDStr := ctx.Request.PathParameter(common.BKAppIDField)
+	bizID, err := strconv.ParseInt(bizIDStr, 10, 64)
+	if err != nil || bizID <= 0 {
+		blog.Errorf("failed to parse the path params bk_biz_id(%s), err: %v, rid: %s", bizIDStr, err, ctx.Kit.Rid)
+		return 0, ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKAppIDField)
+	}
+	return bizID, nil
+}
+
+func parseHostRecycleID(ctx *rest.Contexts) (int64, error) {
+	idStr := ctx.Request.PathParameter(common.BKFieldID)
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		blog.Errorf("failed to parse the path params id(%s), err: %v, rid: %s", idStr, err, ctx.Kit.Rid)
+		return 0, ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKFieldID)
+	}
+	return id, nil
+}
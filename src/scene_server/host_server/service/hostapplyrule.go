@@ -766,7 +766,9 @@ func (s *Service) ExecModuleHostApplyRule(ctx *rest.Contexts) {
 		return
 	}
 
-	// update host operation is not done in a transaction, since the successfully updated hosts need not roll back
+	// update host operation is not done in a transaction, since the successfully updated hosts need not roll back,
+	// the change set of the transaction is kept so that the audit logs of the updated hosts are grouped with it
+	metadata.KeepChangeSet(ctx.Kit.Header)
 	ctx.Kit.Header.Del(common.TransactionIdHeader)
 
 	attributes := make([]metadata.HostAttribute, 0)
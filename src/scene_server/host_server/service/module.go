@@ -78,6 +78,28 @@ func (s *Service) TransferHostModule(ctx *rest.Contexts) {
 	ctx.RespEntity(nil)
 }
 
+// BatchTransferHostModule transfer the hosts in a business to different modules in one call, each host has its own
+// target modules and transfer policy, the result of each host is returned.
+func (s *Service) BatchTransferHostModule(ctx *rest.Contexts) {
+	opt := new(metadata.HostModuleBatchTransferOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := s.Logic.BatchTransferHostModule(ctx.Kit, opt)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	ctx.RespEntity(result)
+}
+
 // MoveHost2IdleModule TODO
 func (s *Service) MoveHost2IdleModule(ctx *rest.Contexts) {
 	s.moveHostToDefaultModule(ctx, common.DefaultResModuleFlag)
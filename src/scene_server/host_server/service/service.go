@@ -30,6 +30,7 @@ import (
 	"configcenter/src/scene_server/host_server/logics"
 	"configcenter/src/storage/dal/redis"
 	"configcenter/src/thirdparty/logplatform/opentelemetry"
+	"configcenter/src/thirdparty/prober"
 
 	"github.com/emicklei/go-restful/v3"
 )
@@ -42,6 +43,8 @@ type Service struct {
 	CacheDB     redis.Client
 	AuthManager *extensions.AuthManager
 	Logic       *logics.Logics
+	// Prober probes the connectivity of the hosts.
+	Prober prober.Prober
 }
 
 // WebService TODO
@@ -56,6 +59,7 @@ func (s *Service) WebService() *restful.Container {
 		return s.CCErr
 	}
 	api.Path("/host/v3").Filter(s.Engine.Metric().RestfulMiddleWare).Filter(rdapi.AllGlobalFilter(getErrFunc)).Produces(restful.MIME_JSON)
+	api.Filter(s.Engine.ReadOnlyFilter())
 
 	// init service actions
 	s.initService(api)
@@ -22,6 +22,7 @@ func (s *Service) initService(web *restful.WebService) {
 	s.initDynamicGroup(web)
 	s.initUsercustom(web)
 	s.initCloudHost(web)
+	s.initHostRecycle(web)
 
 }
 
@@ -39,6 +40,10 @@ func (s *Service) initCloudarea(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/cloudarea/{bk_cloud_id}", Handler: s.DeletePlat})
 	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/updatemany/hosts/cloudarea_field", Handler: s.UpdateHostCloudAreaField})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/cloudarea/hostcount", Handler: s.FindCloudAreaHostCount})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/cloudarea/usage", Handler: s.FindCloudAreaUsage})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/cloudarea/{bk_cloud_id}/relocate",
+		Handler: s.RelocateCloudAreaHosts})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/updatemany/cloudarea/merge", Handler: s.MergeCloudArea})
 
 	utility.AddToRestfulWebService(web)
 
@@ -80,6 +85,10 @@ func (s *Service) initFindhost(web *restful.WebService) {
 		Handler: s.ListBizHosts})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/hosts/list_hosts_without_app",
 		Handler: s.ListHostsWithNoBiz})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/hosts/smart_search",
+		Handler: s.SmartSearchHost})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/update/hosts/probe/biz/{bk_biz_id}",
+		Handler: s.ProbeHosts})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/hosts/app/{bk_biz_id}/list_hosts_topo",
 		Handler: s.ListBizHostsTopo})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/host/count_by_topo_node/bk_biz_id/{bk_biz_id}",
@@ -88,6 +97,10 @@ func (s *Service) initFindhost(web *restful.WebService) {
 		Handler: s.FindHostsByTopo})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/hosts/detail_topo",
 		Handler: s.ListHostDetailAndTopology})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/hosts/select/biz/{bk_biz_id}",
+		Handler: s.SelectHosts})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/hosts/by_ids",
+		Handler: s.FindHostsByIDs})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/hosts/relation/with_topo",
 		Handler: s.GetHostRelationsWithMainlineTopoInstance})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/hosts/service_template",
@@ -206,6 +219,9 @@ func (s *Service) initModule(web *restful.WebService) {
 	})
 
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/hosts/modules", Handler: s.TransferHostModule})
+	// transfer hosts to their own target modules in one call
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/hosts/modules/batch",
+		Handler: s.BatchTransferHostModule})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/hosts/modules/idle", Handler: s.MoveHost2IdleModule})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/hosts/modules/fault", Handler: s.MoveHost2FaultModule})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/hosts/modules/recycle", Handler: s.MoveHost2RecycleModule})
@@ -344,3 +360,27 @@ func (s *Service) initCloudHost(web *restful.WebService) {
 	utility.AddToRestfulWebService(web)
 
 }
+
+func (s *Service) initHostRecycle(web *restful.WebService) {
+	utility := rest.NewRestUtility(rest.Config{
+		ErrorIf:  s.Engine.CCErr,
+		Language: s.Engine.Language,
+	})
+
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/host/recycle_policy/biz/{bk_biz_id}",
+		Handler: s.CreateHostRecyclePolicy})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/host/recycle_policy/{id}/biz/{bk_biz_id}",
+		Handler: s.UpdateHostRecyclePolicy})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/host/recycle_policy/{id}/biz/{bk_biz_id}",
+		Handler: s.DeleteHostRecyclePolicy})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/host/recycle_policy/biz/{bk_biz_id}",
+		Handler: s.ListHostRecyclePolicy})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost,
+		Path: "/update/host/recycle_policy/{id}/run/biz/{bk_biz_id}", Handler: s.RunHostRecyclePolicy})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/host/recycle_report/biz/{bk_biz_id}",
+		Handler: s.ListHostRecycleReport})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut,
+		Path: "/update/host/recycle_report/{id}/review/biz/{bk_biz_id}", Handler: s.ReviewHostRecycleReport})
+
+	utility.AddToRestfulWebService(web)
+}
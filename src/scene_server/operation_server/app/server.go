@@ -23,6 +23,7 @@ import (
 	"configcenter/src/common/auth"
 	"configcenter/src/common/backbone"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/resource/esb"
 	"configcenter/src/common/types"
 	"configcenter/src/scene_server/operation_server/app/options"
 	"configcenter/src/scene_server/operation_server/service"
@@ -81,6 +82,12 @@ func Run(ctx context.Context, cancel context.CancelFunc, op *options.ServerOptio
 	}
 	operationSvr.AuthManager = extensions.NewAuthManager(engine.CoreAPI, iamCli)
 
+	// esb client is used to deliver the subscribed reports
+	if esbConfig, err := esb.ParseEsbConfig(); err == nil {
+		esb.UpdateEsbConfig(*esbConfig)
+	}
+	esb.InitEsbClient(nil)
+
 	operationSvr.Engine = engine
 
 	go operationSvr.InitFunc()
@@ -0,0 +1,274 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"sort"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	cctime "configcenter/src/common/time"
+	"configcenter/src/common/util"
+)
+
+// changeDigestMaxScanLogs the max number of the audit logs scanned to count the top changers
+const changeDigestMaxScanLogs = 10000
+
+var (
+	// changeDigestTopoResources the audit resources of the business's topology counted in the change digest
+	changeDigestTopoResources = []metadata.ResourceType{metadata.MainlineInstanceRes, metadata.SetRes,
+		metadata.ModuleRes}
+	changeDigestTopoActions = []metadata.ActionType{metadata.AuditCreate, metadata.AuditUpdate,
+		metadata.AuditDelete}
+)
+
+// GetChangeDigest summarize the changes in the business during the period from the audit logs and the template sync
+// histories.
+func (lgc *Logics) GetChangeDigest(kit *rest.Kit, opt *metadata.ChangeDigestOption) (*metadata.ChangeDigest, error) {
+	end := time.Now()
+	if opt.EndTime != nil {
+		end = *opt.EndTime
+	}
+
+	digest := &metadata.ChangeDigest{
+		BizID:       opt.BizID,
+		Period:      opt.Period,
+		StartTime:   end.Add(-opt.Period.Duration()),
+		EndTime:     end,
+		TopoChanges: make([]metadata.ChangeDigestTopoChange, 0),
+		TopChangers: make([]metadata.ChangeDigestChanger, 0),
+	}
+
+	var err error
+	digest.HostAdded, err = lgc.countChangeAuditLogs(kit, digest, metadata.HostRes, metadata.AuditCreate,
+		metadata.AuditAssignHost)
+	if err != nil {
+		return nil, err
+	}
+
+	digest.HostRemoved, err = lgc.countChangeAuditLogs(kit, digest, metadata.HostRes, metadata.AuditDelete,
+		metadata.AuditUnassignHost)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resourceType := range changeDigestTopoResources {
+		for _, action := range changeDigestTopoActions {
+			count, err := lgc.countChangeAuditLogs(kit, digest, resourceType, action)
+			if err != nil {
+				return nil, err
+			}
+
+			if count == 0 {
+				continue
+			}
+			digest.TopoChanges = append(digest.TopoChanges, metadata.ChangeDigestTopoChange{
+				ResourceType: resourceType,
+				Action:       action,
+				Count:        count,
+			})
+		}
+	}
+
+	digest.SetTemplateSyncs, err = lgc.countTemplateSyncs(kit, digest, common.BKInnerObjIDSet,
+		common.BKSetTemplateIDField, common.SyncSetTaskFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	digest.ServiceTemplateSyncs, err = lgc.countTemplateSyncs(kit, digest, common.BKInnerObjIDModule,
+		common.BKServiceTemplateIDField, common.SyncModuleTaskFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lgc.countTopChangers(kit, digest); err != nil {
+		return nil, err
+	}
+
+	return digest, nil
+}
+
+// changeAuditLogCond returns the condition of the business's audit logs in the period of the digest
+func changeAuditLogCond(digest *metadata.ChangeDigest) mapstr.MapStr {
+	return mapstr.MapStr{
+		common.BKAppIDField: digest.BizID,
+		common.BKOperationTimeField: mapstr.MapStr{
+			common.BKDBGTE: digest.StartTime.Local().Format(common.TimeTransferModel),
+			common.BKDBLTE: digest.EndTime.Local().Format(common.TimeTransferModel),
+		},
+	}
+}
+
+// countChangeAuditLogs count the business's audit logs of the resource with the actions in the period
+func (lgc *Logics) countChangeAuditLogs(kit *rest.Kit, digest *metadata.ChangeDigest,
+	resourceType metadata.ResourceType, actions ...metadata.ActionType) (int64, error) {
+
+	cond := changeAuditLogCond(digest)
+	cond[common.BKResourceTypeField] = resourceType
+	cond[common.BKActionField] = mapstr.MapStr{common.BKDBIN: actions}
+
+	query := metadata.QueryCondition{
+		Condition: cond,
+		Fields:    []string{common.BKFieldID},
+		Page:      metadata.BasePage{Limit: 1},
+	}
+	result, err := lgc.CoreAPI.CoreService().Audit().SearchAuditLog(kit.Ctx, kit.Header, query)
+	if err != nil {
+		blog.Errorf("count biz %d %s audit logs failed, actions: %v, err: %v, rid: %s", digest.BizID, resourceType,
+			actions, err, kit.Rid)
+		return 0, err
+	}
+
+	return result.Count, nil
+}
+
+// countTemplateSyncs count the sync tasks of the business's instances created from the templates in the period
+func (lgc *Logics) countTemplateSyncs(kit *rest.Kit, digest *metadata.ChangeDigest, objID, templateField,
+	taskType string) (int64, error) {
+
+	idField := common.GetInstIDField(objID)
+	query := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{
+			common.BKAppIDField: digest.BizID,
+			templateField:       mapstr.MapStr{common.BKDBNE: 0},
+		},
+		Fields:         []string{idField},
+		Page:           metadata.BasePage{Limit: common.BKNoLimit},
+		DisableCounter: true,
+	}
+	insts, err := lgc.CoreAPI.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header, objID, query)
+	if err != nil {
+		blog.Errorf("get biz %d %s created from templates failed, err: %v, rid: %s", digest.BizID, objID, err,
+			kit.Rid)
+		return 0, err
+	}
+
+	if len(insts.Info) == 0 {
+		return 0, nil
+	}
+
+	instIDs := make([]int64, 0, len(insts.Info))
+	for _, inst := range insts.Info {
+		instID, err := util.GetInt64ByInterface(inst[idField])
+		if err != nil {
+			blog.Errorf("parse %s id failed, inst: %#v, err: %v, rid: %s", objID, inst, err, kit.Rid)
+			return 0, kit.CCError.CCErrorf(common.CCErrCommParamsNeedInt, idField)
+		}
+		instIDs = append(instIDs, instID)
+	}
+
+	statusQuery := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{
+			common.BKTaskTypeField: taskType,
+			common.BKInstIDField:   mapstr.MapStr{common.BKDBIN: instIDs},
+		},
+		TimeCondition: &metadata.TimeCondition{
+			Operator: "and",
+			Rules: []metadata.TimeConditionItem{{
+				Field: common.CreateTimeField,
+				Start: &cctime.Time{Time: digest.StartTime},
+				End:   &cctime.Time{Time: digest.EndTime},
+			}},
+		},
+		Fields: []string{common.BKTaskIDField},
+		Page:   metadata.BasePage{Limit: 1},
+	}
+	result, err := lgc.CoreAPI.TaskServer().Task().ListSyncStatusHistory(kit.Ctx, kit.Header, statusQuery)
+	if err != nil {
+		blog.Errorf("count biz %d %s sync tasks failed, err: %v, rid: %s", digest.BizID, taskType, err, kit.Rid)
+		return 0, err
+	}
+
+	return result.Count, nil
+}
+
+// countTopChangers count the changes made by each user in the period, at most changeDigestMaxScanLogs audit logs are
+// scanned, the digest is marked as truncated if the business has more changes.
+func (lgc *Logics) countTopChangers(kit *rest.Kit, digest *metadata.ChangeDigest) error {
+	changes := make(map[string]int64)
+	query := metadata.QueryCondition{
+		Condition: changeAuditLogCond(digest),
+		Fields:    []string{common.BKUser},
+		Page:      metadata.BasePage{Limit: common.BKAuditLogPageLimit, Sort: common.BKFieldID},
+	}
+
+	for query.Page.Start < changeDigestMaxScanLogs {
+		result, err := lgc.CoreAPI.CoreService().Audit().SearchAuditLog(kit.Ctx, kit.Header, query)
+		if err != nil {
+			blog.Errorf("search biz %d audit logs failed, err: %v, rid: %s", digest.BizID, err, kit.Rid)
+			return err
+		}
+
+		for _, auditLog := range result.Info {
+			changes[auditLog.User]++
+		}
+
+		if result.Count > changeDigestMaxScanLogs {
+			digest.Truncated = true
+		}
+
+		if len(result.Info) < common.BKAuditLogPageLimit {
+			break
+		}
+		query.Page.Start += common.BKAuditLogPageLimit
+	}
+
+	for user, count := range changes {
+		digest.TopChangers = append(digest.TopChangers, metadata.ChangeDigestChanger{User: user, Count: count})
+	}
+
+	sort.Slice(digest.TopChangers, func(i, j int) bool {
+		if digest.TopChangers[i].Count != digest.TopChangers[j].Count {
+			return digest.TopChangers[i].Count > digest.TopChangers[j].Count
+		}
+		return digest.TopChangers[i].User < digest.TopChangers[j].User
+	})
+
+	if len(digest.TopChangers) > metadata.ChangeDigestTopChangerCount {
+		digest.TopChangers = digest.TopChangers[:metadata.ChangeDigestTopChangerCount]
+	}
+
+	return nil
+}
+
+// getChangeDigestReport get the change digest of the business as the report rows
+func (lgc *Logics) getChangeDigestReport(kit *rest.Kit, sub *metadata.ReportSubscription) (*reportTable, error) {
+	digest, err := lgc.GetChangeDigest(kit, &metadata.ChangeDigestOption{BizID: sub.BizID, Period: sub.DigestPeriod})
+	if err != nil {
+		return nil, err
+	}
+
+	newRow := func(category string, item interface{}, count int64) mapstr.MapStr {
+		return mapstr.MapStr{"category": category, "item": item, "count": count}
+	}
+
+	rows := []mapstr.MapStr{
+		newRow("host", "added", digest.HostAdded),
+		newRow("host", "removed", digest.HostRemoved),
+	}
+	for _, change := range digest.TopoChanges {
+		rows = append(rows, newRow(string(change.ResourceType), change.Action, change.Count))
+	}
+	rows = append(rows, newRow("template_sync", common.SyncSetTaskFlag, digest.SetTemplateSyncs),
+		newRow("template_sync", common.SyncModuleTaskFlag, digest.ServiceTemplateSyncs))
+	for _, changer := range digest.TopChangers {
+		rows = append(rows, newRow("top_changer", changer.User, changer.Count))
+	}
+
+	return &reportTable{profile: newReportProfile(), fields: []string{"category", "item", "count"}, rows: rows}, nil
+}
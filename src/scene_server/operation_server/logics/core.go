@@ -20,6 +20,7 @@ import (
 	"configcenter/src/common/backbone"
 	"configcenter/src/common/errors"
 	"configcenter/src/common/language"
+	"configcenter/src/common/resource/esb"
 	"configcenter/src/common/util"
 	"configcenter/src/thirdparty/esbserver"
 )
@@ -44,6 +45,7 @@ func NewLogics(b *backbone.Engine, header http.Header, authManager *extensions.A
 	lang := util.GetLanguage(header)
 	return &Logics{
 		Engine:      b,
+		esbServ:     esb.EsbClient(),
 		header:      header,
 		rid:         util.GetHTTPCCRequestID(header),
 		ccErr:       b.CCErr.CreateDefaultCCErrorIf(lang),
@@ -0,0 +1,211 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"sort"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// FindSingleDomainModules find the modules of the business whose hosts all reside in a single fault domain of the
+// level, the inner modules such as the idle module are not checked since they are not serving.
+func (lgc *Logics) FindSingleDomainModules(kit *rest.Kit, opt *metadata.FindSingleDomainModuleOption) (
+	*metadata.FindSingleDomainModuleResult, error) {
+
+	placementField, _ := metadata.GetHostPlacementField(opt.Level)
+
+	moduleQuery := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{
+			common.BKAppIDField:   opt.BizID,
+			common.BKDefaultField: common.NormalModuleFlag,
+		},
+		Fields:         []string{common.BKModuleIDField, common.BKModuleNameField, common.BKSetIDField},
+		Page:           metadata.BasePage{Limit: common.BKNoLimit},
+		DisableCounter: true,
+	}
+	modules, err := lgc.CoreAPI.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header, common.BKInnerObjIDModule,
+		moduleQuery)
+	if err != nil {
+		blog.Errorf("get biz %d modules failed, err: %v, rid: %s", opt.BizID, err, kit.Rid)
+		return nil, err
+	}
+
+	moduleMap := make(map[int64]*metadata.SingleDomainModule, len(modules.Info))
+	moduleIDs := make([]int64, 0, len(modules.Info))
+	for _, module := range modules.Info {
+		moduleID, err := module.Int64(common.BKModuleIDField)
+		if err != nil {
+			blog.Errorf("parse module id failed, module: %#v, err: %v, rid: %s", module, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsNeedInt, common.BKModuleIDField)
+		}
+		setID, _ := module.Int64(common.BKSetIDField)
+		moduleName, _ := module.String(common.BKModuleNameField)
+		moduleMap[moduleID] = &metadata.SingleDomainModule{ModuleID: moduleID, ModuleName: moduleName, SetID: setID}
+		moduleIDs = append(moduleIDs, moduleID)
+	}
+
+	moduleHostIDs, hostIDs, err := lgc.getModuleHostIDs(kit, opt.BizID, moduleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	hostDomains, err := lgc.getHostPlacements(kit, hostIDs, placementField)
+	if err != nil {
+		return nil, err
+	}
+
+	singleDomainModules := make([]metadata.SingleDomainModule, 0)
+	for moduleID, ids := range moduleHostIDs {
+		if len(ids) < opt.MinHostCount {
+			continue
+		}
+
+		domain, isSingle := getSingleDomain(ids, hostDomains)
+		if !isSingle {
+			continue
+		}
+
+		module := moduleMap[moduleID]
+		module.HostCount = len(ids)
+		module.Domain = domain
+		singleDomainModules = append(singleDomainModules, *module)
+	}
+
+	sort.Slice(singleDomainModules, func(i, j int) bool {
+		return singleDomainModules[i].ModuleID < singleDomainModules[j].ModuleID
+	})
+
+	result := &metadata.FindSingleDomainModuleResult{
+		Count: len(singleDomainModules),
+		Info:  make([]metadata.SingleDomainModule, 0),
+	}
+	if opt.Page.Start >= len(singleDomainModules) {
+		return result, nil
+	}
+
+	end := opt.Page.Start + opt.Page.Limit
+	if end > len(singleDomainModules) {
+		end = len(singleDomainModules)
+	}
+	result.Info = singleDomainModules[opt.Page.Start:end]
+	return result, nil
+}
+
+// getModuleHostIDs get the host ids of the modules, returns the map of module id to its host ids and all host ids.
+func (lgc *Logics) getModuleHostIDs(kit *rest.Kit, bizID int64, moduleIDs []int64) (map[int64][]int64, []int64,
+	error) {
+
+	moduleHostIDs := make(map[int64][]int64)
+	hostIDs := make([]int64, 0)
+	hostIDMap := make(map[int64]struct{})
+	for start := 0; start < len(moduleIDs); start += common.BKMaxInstanceLimit {
+		end := start + common.BKMaxInstanceLimit
+		if end > len(moduleIDs) {
+			end = len(moduleIDs)
+		}
+
+		relationReq := &metadata.HostModuleRelationRequest{
+			ApplicationID: bizID,
+			ModuleIDArr:   moduleIDs[start:end],
+			Page:          metadata.BasePage{Limit: common.BKNoLimit},
+			Fields:        []string{common.BKHostIDField, common.BKModuleIDField},
+		}
+		relations, err := lgc.CoreAPI.CoreService().Host().GetHostModuleRelation(kit.Ctx, kit.Header, relationReq)
+		if err != nil {
+			blog.Errorf("get biz %d host module relations failed, err: %v, rid: %s", bizID, err, kit.Rid)
+			return nil, nil, err
+		}
+
+		for _, relation := range relations.Info {
+			moduleHostIDs[relation.ModuleID] = append(moduleHostIDs[relation.ModuleID], relation.HostID)
+			if _, exists := hostIDMap[relation.HostID]; !exists {
+				hostIDMap[relation.HostID] = struct{}{}
+				hostIDs = append(hostIDs, relation.HostID)
+			}
+		}
+	}
+
+	return moduleHostIDs, hostIDs, nil
+}
+
+// getHostPlacements get the placements of the hosts, returns the map of host id to its fault domain, the hosts
+// without the placement are not included.
+func (lgc *Logics) getHostPlacements(kit *rest.Kit, hostIDs []int64, placementField string) (map[int64]string,
+	error) {
+
+	hostDomains := make(map[int64]string, len(hostIDs))
+	for start := 0; start < len(hostIDs); start += common.BKMaxInstanceLimit {
+		end := start + common.BKMaxInstanceLimit
+		if end > len(hostIDs) {
+			end = len(hostIDs)
+		}
+
+		hostQuery := &metadata.QueryCondition{
+			Condition:      mapstr.MapStr{common.BKHostIDField: mapstr.MapStr{common.BKDBIN: hostIDs[start:end]}},
+			Fields:         []string{common.BKHostIDField, placementField},
+			Page:           metadata.BasePage{Limit: common.BKNoLimit},
+			DisableCounter: true,
+		}
+		hosts, err := lgc.CoreAPI.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header,
+			common.BKInnerObjIDHost, hostQuery)
+		if err != nil {
+			blog.Errorf("get hosts placement failed, err: %v, rid: %s", err, kit.Rid)
+			return nil, err
+		}
+
+		for _, host := range hosts.Info {
+			hostID, err := host.Int64(common.BKHostIDField)
+			if err != nil {
+				blog.Errorf("parse host id failed, host: %#v, err: %v, rid: %s", host, err, kit.Rid)
+				return nil, kit.CCError.CCErrorf(common.CCErrCommParamsNeedInt, common.BKHostIDField)
+			}
+
+			domain := util.GetStrByInterface(host[placementField])
+			if domain == "" {
+				continue
+			}
+			hostDomains[hostID] = domain
+		}
+	}
+
+	return hostDomains, nil
+}
+
+// getSingleDomain returns the fault domain if all the hosts reside in it, a host without the placement makes the
+// fault domains unknown.
+func getSingleDomain(hostIDs []int64, hostDomains map[int64]string) (string, bool) {
+	domain := ""
+	for _, hostID := range hostIDs {
+		hostDomain, exists := hostDomains[hostID]
+		if !exists {
+			return "", false
+		}
+
+		if domain == "" {
+			domain = hostDomain
+			continue
+		}
+
+		if domain != hostDomain {
+			return "", false
+		}
+	}
+
+	return domain, domain != ""
+}
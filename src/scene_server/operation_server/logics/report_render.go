@@ -0,0 +1,219 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"unicode/utf8"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/rentiansheng/xlsx"
+)
+
+// reportTable is the generated report, its rows are rendered by the export profile of the rows' model.
+type reportTable struct {
+	profile *reportProfile
+	fields  []string
+	rows    []mapstr.MapStr
+}
+
+// reportProfile is the export profile of the model in the business that the report is rendered by, same as the
+// instance export, the columns are titled by the attributes' names and the enum values are rendered by the names.
+type reportProfile struct {
+	// fields the visible fields of the business's field layout in the display order, empty if not set.
+	fields []string
+	// titles the attributes' names, key is the attribute's property id.
+	titles map[string]string
+	// enums the enum attributes' option names, key is the attribute's property id, then the option id.
+	enums map[string]map[string]string
+}
+
+// newReportProfile new an empty export profile, which is used by the statistics report that has no model
+func newReportProfile() *reportProfile {
+	return &reportProfile{titles: make(map[string]string), enums: make(map[string]map[string]string)}
+}
+
+// getReportProfile get the export profile of the model in the business
+func (lgc *Logics) getReportProfile(kit *rest.Kit, bizID int64, objID string) (*reportProfile, error) {
+	profile := newReportProfile()
+
+	cond := mapstr.MapStr{common.BKObjIDField: objID}
+	util.AddModelBizIDCondition(cond, bizID)
+	attrQuery := &metadata.QueryCondition{
+		Condition: cond,
+		Page:      metadata.BasePage{Limit: common.BKNoLimit, Sort: common.BKPropertyIndexField},
+	}
+	attrs, err := lgc.CoreAPI.CoreService().Model().ReadModelAttr(kit.Ctx, kit.Header, objID, attrQuery)
+	if err != nil {
+		blog.Errorf("get object %s attributes failed, err: %v, rid: %s", objID, err, kit.Rid)
+		return nil, err
+	}
+
+	for _, attr := range attrs.Info {
+		profile.titles[attr.PropertyID] = attr.PropertyName
+		if attr.PropertyType != common.FieldTypeEnum {
+			continue
+		}
+
+		options, err := metadata.ParseEnumOption(kit.Ctx, attr.Option)
+		if err != nil {
+			blog.Errorf("parse attribute %s enum option failed, err: %v, rid: %s", attr.PropertyID, err, kit.Rid)
+			continue
+		}
+		names := make(map[string]string, len(options))
+		for _, option := range options {
+			names[option.ID] = option.Name
+		}
+		profile.enums[attr.PropertyID] = names
+	}
+
+	opt := &metadata.BizFieldLayoutOption{BizID: bizID, ObjID: objID}
+	layout, err := lgc.CoreAPI.CoreService().Model().ReadBizFieldLayout(kit.Ctx, kit.Header, opt)
+	if err != nil {
+		blog.Errorf("read biz field layout failed, opt: %#v, err: %v, rid: %s", opt, err, kit.Rid)
+		return nil, err
+	}
+
+	if layout == nil || len(layout.Fields) == 0 {
+		return profile, nil
+	}
+
+	for _, attr := range layout.Apply(attrs.Info) {
+		profile.fields = append(profile.fields, attr.PropertyID)
+	}
+	return profile, nil
+}
+
+// getFields get the fields of the report's columns, the subscription's fields take precedence over the visible
+// fields of the export profile, the default fields are used if neither is set.
+func (p *reportProfile) getFields(sub *metadata.ReportSubscription, defaultFields []string) []string {
+	if len(sub.Fields) > 0 {
+		return sub.Fields
+	}
+
+	if len(p.fields) > 0 {
+		return p.fields
+	}
+
+	return defaultFields
+}
+
+// title get the column title of the field
+func (p *reportProfile) title(field string) string {
+	if title := p.titles[field]; len(title) > 0 {
+		return title
+	}
+	return field
+}
+
+// value render the field's value to text
+func (p *reportProfile) value(field string, value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	if names, exists := p.enums[field]; exists {
+		if name, exists := names[util.GetStrByInterface(value)]; exists {
+			return name
+		}
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// records render the report by the export profile to the text records, the first record is the columns' titles
+func (t *reportTable) records() [][]string {
+	records := make([][]string, 0, len(t.rows)+1)
+
+	titles := make([]string, len(t.fields))
+	for index, field := range t.fields {
+		titles[index] = t.profile.title(field)
+	}
+	records = append(records, titles)
+
+	for _, row := range t.rows {
+		record := make([]string, len(t.fields))
+		for index, field := range t.fields {
+			record[index] = t.profile.value(field, row[field])
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// render render the report to the file content of the format, returns the content and the file extension
+func (t *reportTable) render(format metadata.ReportFormat) ([]byte, string, error) {
+	records := t.records()
+	if format == metadata.ReportFormatExcel {
+		content, err := renderReportExcel(records)
+		return content, "xlsx", err
+	}
+
+	content, err := renderReportCSV(records)
+	return content, "csv", err
+}
+
+// renderReportCSV render the report records to csv
+func renderReportCSV(records [][]string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	writer := csv.NewWriter(buf)
+	if err := writer.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderReportExcel render the report records to an excel file with one sheet
+func renderReportExcel(records [][]string) ([]byte, error) {
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet("report")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		row := sheet.AddRow()
+		for _, value := range record {
+			row.AddCell().SetString(value)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := file.Write(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// truncateReportText truncate the text to the max bytes on the rune boundary, so that the multi-byte characters
+// are not split into invalid text.
+func truncateReportText(text string, maxLength int) string {
+	if len(text) <= maxLength {
+		return text
+	}
+
+	end := maxLength
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end] + "..."
+}
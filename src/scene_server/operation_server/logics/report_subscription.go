@@ -0,0 +1,516 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logics
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/robfig/cron"
+)
+
+const (
+	// reportCheckSpec check the due report subscriptions every minute
+	reportCheckSpec = "0 * * * * *"
+	// reportMaxRows the max rows of a report, the rest rows are dropped
+	reportMaxRows = 10000
+	// reportMsgMaxLength the max length of the report content sent by im, the rest content is truncated
+	reportMsgMaxLength = 4000
+	// reportPageSize the page size of the report subscriptions and the report rows
+	reportPageSize = 200
+)
+
+var (
+	defaultHostReportFields = []string{common.BKHostIDField, common.BKHostInnerIPField, common.BKHostNameField,
+		common.BKCloudIDField}
+	defaultSetReportFields = []string{common.BKSetIDField, common.BKSetNameField}
+	// driftReportFields the columns of the drift report, a row is a host attribute that drifts from the host apply
+	// rules of the host's modules
+	driftReportFields = []string{common.BKHostIDField, common.BKHostInnerIPField, common.BKPropertyIDField,
+		"current_value", "expected_value"}
+)
+
+// TimerSendReport check and send the due report subscriptions periodically
+func (lgc *Logics) TimerSendReport(ctx context.Context) {
+	c := cron.New()
+	err := c.AddFunc(reportCheckSpec, func() {
+		// 主服务器发送订阅报表
+		if !lgc.Engine.ServiceManageInterface.IsMaster() {
+			return
+		}
+		lgc.sendDueReports()
+	})
+	if err != nil {
+		blog.Errorf("new report cron failed, please contact developer, err: %v", err)
+		return
+	}
+	c.Start()
+
+	select {
+	case <-ctx.Done():
+		c.Stop()
+		return
+	}
+}
+
+func (lgc *Logics) sendDueReports() {
+	kit := newReportKit(common.BKSuperOwnerID, common.BKProcInstanceOpUser)
+	now := time.Now()
+
+	query := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{"enabled": true},
+		Page:      metadata.BasePage{Limit: reportPageSize, Sort: common.BKFieldID},
+	}
+	for {
+		result, err := lgc.CoreAPI.CoreService().Operation().SearchReportSubscription(kit.Ctx, kit.Header, query)
+		if err != nil {
+			blog.Errorf("search report subscriptions failed, err: %v, rid: %s", err, kit.Rid)
+			return
+		}
+
+		for index := range result.Info {
+			sub := &result.Info[index]
+			if !sub.IsDue(now) {
+				continue
+			}
+
+			subKit := newReportKit(sub.SupplierAccount, sub.Creator)
+			if err := lgc.SendReport(subKit, sub); err != nil {
+				// the failed report is not retried until next schedule time, so that the receivers are not disturbed
+				blog.Errorf("send report subscription %d failed, err: %v, rid: %s", sub.ID, err, subKit.Rid)
+			}
+
+			data := map[string]interface{}{"last_send_time": now}
+			err := lgc.CoreAPI.CoreService().Operation().UpdateReportSubscription(kit.Ctx, kit.Header, sub.ID, data)
+			if err != nil {
+				blog.Errorf("update report subscription %d send time failed, err: %v, rid: %s", sub.ID, err, kit.Rid)
+			}
+		}
+
+		if len(result.Info) < reportPageSize {
+			return
+		}
+		query.Page.Start += reportPageSize
+	}
+}
+
+// SendReport generate the report of the subscription and deliver it to the receivers
+func (lgc *Logics) SendReport(kit *rest.Kit, sub *metadata.ReportSubscription) error {
+	if lgc.esbServ == nil {
+		blog.Errorf("esb client is not initialized, can not send report %d, rid: %s", sub.ID, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrOperationSendReportFail, "esb is not configured")
+	}
+
+	var table *reportTable
+	var err error
+	switch sub.ReportType {
+	case metadata.ReportTypeDynamicGroup:
+		table, err = lgc.getDynamicGroupReport(kit, sub)
+	case metadata.ReportTypeIdleHost:
+		table, err = lgc.getIdleHostReport(kit, sub)
+	case metadata.ReportTypeChangeDigest:
+		table, err = lgc.getChangeDigestReport(kit, sub)
+	case metadata.ReportTypeDrift:
+		table, err = lgc.getDriftReport(kit, sub)
+	default:
+		return kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "report_type")
+	}
+	if err != nil {
+		return err
+	}
+
+	// the im message is text, only the mail attachment can be an excel file
+	format := metadata.ReportFormatCSV
+	if sub.Channel == metadata.ReportChannelMail && sub.Format == metadata.ReportFormatExcel {
+		format = metadata.ReportFormatExcel
+	}
+	content, ext, err := table.render(format)
+	if err != nil {
+		blog.Errorf("render report %d failed, err: %v, rid: %s", sub.ID, err, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrOperationSendReportFail, err.Error())
+	}
+
+	receivers, err := lgc.getReportReceivers(kit, sub)
+	if err != nil {
+		return err
+	}
+
+	if len(receivers) == 0 {
+		blog.Errorf("report %d has no receivers, receivers: %v, rid: %s", sub.ID, sub.Receivers, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrOperationSendReportFail, "no receivers")
+	}
+
+	title := fmt.Sprintf("[CMDB] %s (%s)", sub.Name, time.Now().Format(common.TimeDayTransferModel))
+	receiver := strings.Join(receivers, ",")
+
+	if sub.Channel == metadata.ReportChannelMail {
+		mail := &metadata.CmsiMail{
+			Receiver: receiver,
+			Title:    title,
+			Content:  fmt.Sprintf("%s: %d", sub.Name, len(table.rows)),
+			Attachments: []metadata.CmsiAttachment{{
+				Filename: fmt.Sprintf("%s.%s", sub.Name, ext),
+				Content:  base64.StdEncoding.EncodeToString(content),
+			}},
+		}
+		if err := lgc.esbServ.CmsiSrv().SendMail(kit.Ctx, kit.Header, mail); err != nil {
+			blog.Errorf("send report %d mail failed, err: %v, rid: %s", sub.ID, err, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrOperationSendReportFail, err.Error())
+		}
+		return nil
+	}
+
+	msg := &metadata.CmsiMsg{
+		MsgType:  sub.Channel,
+		Receiver: receiver,
+		Title:    title,
+		Content:  truncateReportText(string(content), reportMsgMaxLength),
+	}
+	if err := lgc.esbServ.CmsiSrv().SendMsg(kit.Ctx, kit.Header, msg); err != nil {
+		blog.Errorf("send report %d message failed, err: %v, rid: %s", sub.ID, err, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrOperationSendReportFail, err.Error())
+	}
+	return nil
+}
+
+// getReportReceivers get the users that the report is delivered to, the business role receivers like "role:ops" are
+// resolved to the users of the roles in the business.
+func (lgc *Logics) getReportReceivers(kit *rest.Kit, sub *metadata.ReportSubscription) ([]string, error) {
+	receivers := make([]string, 0)
+	roles := make([]metadata.BizRole, 0)
+	for _, receiver := range sub.Receivers {
+		if strings.HasPrefix(receiver, metadata.BizRoleReceiverPrefix) {
+			roles = append(roles, metadata.BizRole(strings.TrimPrefix(receiver, metadata.BizRoleReceiverPrefix)))
+			continue
+		}
+		receivers = append(receivers, receiver)
+	}
+
+	if len(roles) == 0 {
+		return receivers, nil
+	}
+
+	opt := &metadata.ResolveBizRoleReceiverOption{BizID: sub.BizID, Roles: roles}
+	roleReceivers, err := lgc.CoreAPI.CoreService().Operation().ResolveBizRoleReceivers(kit.Ctx, kit.Header, opt)
+	if err != nil {
+		blog.Errorf("resolve report %d role receivers failed, opt: %#v, err: %v, rid: %s", sub.ID, opt, err, kit.Rid)
+		return nil, err
+	}
+
+	for _, roleReceiver := range roleReceivers {
+		receivers = append(receivers, roleReceiver.Users...)
+	}
+
+	return util.StrArrayUnique(receivers), nil
+}
+
+// getDynamicGroupReport get the members of the dynamic group
+func (lgc *Logics) getDynamicGroupReport(kit *rest.Kit, sub *metadata.ReportSubscription) (*reportTable, error) {
+
+	bizID := strconv.FormatInt(sub.BizID, 10)
+	group, err := lgc.CoreAPI.CoreService().Host().GetDynamicGroup(kit.Ctx, bizID, sub.DynamicGroupID, kit.Header)
+	if err != nil {
+		blog.Errorf("get dynamic group %s failed, err: %v, rid: %s", sub.DynamicGroupID, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommHTTPDoRequestFailed)
+	}
+	if err := group.CCError(); err != nil {
+		blog.Errorf("get dynamic group %s failed, err: %v, rid: %s", sub.DynamicGroupID, err, kit.Rid)
+		return nil, err
+	}
+
+	profile, err := lgc.getReportProfile(kit, sub.BizID, group.Data.ObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultFields := defaultHostReportFields
+	if group.Data.ObjID == common.BKInnerObjIDSet {
+		defaultFields = defaultSetReportFields
+	}
+	fields := profile.getFields(sub, defaultFields)
+
+	rows := make([]mapstr.MapStr, 0)
+	for start := 0; start < reportMaxRows; start += reportPageSize {
+		data := map[string]interface{}{
+			"fields": fields,
+			"page":   metadata.BasePage{Start: start, Limit: reportPageSize},
+		}
+		resp, err := lgc.CoreAPI.HostServer().ExecuteDynamicGroup(kit.Ctx, bizID, sub.DynamicGroupID, kit.Header,
+			data)
+		if err != nil {
+			blog.Errorf("execute dynamic group %s failed, err: %v, rid: %s", sub.DynamicGroupID, err, kit.Rid)
+			return nil, kit.CCError.CCError(common.CCErrCommHTTPDoRequestFailed)
+		}
+		if err := resp.CCError(); err != nil {
+			blog.Errorf("execute dynamic group %s failed, err: %v, rid: %s", sub.DynamicGroupID, err, kit.Rid)
+			return nil, err
+		}
+
+		result := new(metadata.InstDataInfo)
+		respData, err := json.Marshal(resp.Data)
+		if err != nil {
+			return nil, kit.CCError.CCError(common.CCErrCommJSONMarshalFailed)
+		}
+		if err := json.Unmarshal(respData, result); err != nil {
+			blog.Errorf("unmarshal dynamic group data failed, err: %v, rid: %s", err, kit.Rid)
+			return nil, kit.CCError.CCError(common.CCErrCommJSONUnmarshalFailed)
+		}
+
+		for _, row := range result.Info {
+			// host search result is grouped by the object, flatten the host's fields
+			if host, exists := row[common.BKInnerObjIDHost]; exists {
+				if hostInfo, ok := host.(map[string]interface{}); ok {
+					row = hostInfo
+				}
+			}
+			rows = append(rows, row)
+		}
+
+		if len(result.Info) < reportPageSize {
+			break
+		}
+	}
+
+	return &reportTable{profile: profile, fields: fields, rows: rows}, nil
+}
+
+// getIdleHostReport get the hosts in the idle module of the business
+func (lgc *Logics) getIdleHostReport(kit *rest.Kit, sub *metadata.ReportSubscription) (*reportTable, error) {
+	moduleQuery := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{
+			common.BKAppIDField:   sub.BizID,
+			common.BKDefaultField: common.DefaultResModuleFlag,
+		},
+		Fields:         []string{common.BKModuleIDField},
+		DisableCounter: true,
+	}
+	modules, err := lgc.CoreAPI.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header, common.BKInnerObjIDModule,
+		moduleQuery)
+	if err != nil {
+		blog.Errorf("get biz %d idle module failed, err: %v, rid: %s", sub.BizID, err, kit.Rid)
+		return nil, err
+	}
+
+	moduleIDs := make([]int64, 0)
+	for _, module := range modules.Info {
+		moduleID, err := module.Int64(common.BKModuleIDField)
+		if err != nil {
+			blog.Errorf("parse module id failed, module: %#v, err: %v, rid: %s", module, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsNeedInt, common.BKModuleIDField)
+		}
+		moduleIDs = append(moduleIDs, moduleID)
+	}
+	if len(moduleIDs) == 0 {
+		blog.Errorf("biz %d has no idle module, rid: %s", sub.BizID, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommNotFound)
+	}
+
+	profile, err := lgc.getReportProfile(kit, sub.BizID, common.BKInnerObjIDHost)
+	if err != nil {
+		return nil, err
+	}
+	fields := profile.getFields(sub, defaultHostReportFields)
+
+	rows := make([]mapstr.MapStr, 0)
+	for start := 0; start < reportMaxRows; start += reportPageSize {
+		option := &metadata.ListHosts{
+			BizID:     sub.BizID,
+			ModuleIDs: moduleIDs,
+			Fields:    fields,
+			Page:      metadata.BasePage{Start: start, Limit: reportPageSize},
+		}
+		hosts, err := lgc.CoreAPI.CoreService().Host().ListHosts(kit.Ctx, kit.Header, option)
+		if err != nil {
+			blog.Errorf("list biz %d idle hosts failed, err: %v, rid: %s", sub.BizID, err, kit.Rid)
+			return nil, err
+		}
+
+		for _, host := range hosts.Info {
+			rows = append(rows, host)
+		}
+
+		if len(hosts.Info) < reportPageSize {
+			break
+		}
+	}
+
+	return &reportTable{profile: profile, fields: fields, rows: rows}, nil
+}
+
+// getDriftReport get the host attributes in the business that drift from the host apply rules of their modules
+func (lgc *Logics) getDriftReport(kit *rest.Kit, sub *metadata.ReportSubscription) (*reportTable, error) {
+	profile, err := lgc.getReportProfile(kit, sub.BizID, common.BKInnerObjIDHost)
+	if err != nil {
+		return nil, err
+	}
+	table := &reportTable{profile: profile, fields: driftReportFields, rows: make([]mapstr.MapStr, 0)}
+
+	// only the rules of the modules that enabled host apply take effect
+	moduleQuery := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{
+			common.BKAppIDField:          sub.BizID,
+			common.HostApplyEnabledField: true,
+		},
+		Fields:         []string{common.BKModuleIDField},
+		Page:           metadata.BasePage{Limit: common.BKNoLimit},
+		DisableCounter: true,
+	}
+	modules, err := lgc.CoreAPI.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header, common.BKInnerObjIDModule,
+		moduleQuery)
+	if err != nil {
+		blog.Errorf("get biz %d host apply enabled modules failed, err: %v, rid: %s", sub.BizID, err, kit.Rid)
+		return nil, err
+	}
+
+	moduleIDs := make([]int64, 0)
+	for _, module := range modules.Info {
+		moduleID, err := module.Int64(common.BKModuleIDField)
+		if err != nil {
+			blog.Errorf("parse module id failed, module: %#v, err: %v, rid: %s", module, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsNeedInt, common.BKModuleIDField)
+		}
+		moduleIDs = append(moduleIDs, moduleID)
+	}
+	if len(moduleIDs) == 0 {
+		return table, nil
+	}
+
+	ruleOpt := metadata.ListHostApplyRuleOption{ModuleIDs: moduleIDs}
+	rules, err := lgc.CoreAPI.CoreService().HostApplyRule().ListHostApplyRule(kit.Ctx, kit.Header, sub.BizID, ruleOpt)
+	if err != nil {
+		blog.Errorf("list biz %d host apply rules failed, err: %v, rid: %s", sub.BizID, err, kit.Rid)
+		return nil, err
+	}
+	if len(rules.Info) == 0 {
+		return table, nil
+	}
+
+	hostModules, err := lgc.getDriftReportHostModules(kit, sub.BizID, moduleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(hostModules) && len(table.rows) < reportMaxRows; start += reportPageSize {
+		end := start + reportPageSize
+		if end > len(hostModules) {
+			end = len(hostModules)
+		}
+
+		planOpt := metadata.HostApplyPlanOption{Rules: rules.Info, HostModules: hostModules[start:end]}
+		plans, err := lgc.CoreAPI.CoreService().HostApplyRule().GenerateApplyPlan(kit.Ctx, kit.Header, sub.BizID,
+			planOpt)
+		if err != nil {
+			blog.Errorf("generate biz %d host apply plan failed, err: %v, rid: %s", sub.BizID, err, kit.Rid)
+			return nil, err
+		}
+
+		for _, plan := range plans.Plans {
+			// the conflict fields are the attributes whose current values differ from the rules
+			for _, conflict := range plan.ConflictFields {
+				expected := make([]interface{}, len(conflict.Rules))
+				for index, rule := range conflict.Rules {
+					expected[index] = rule.PropertyValue
+				}
+
+				row := mapstr.MapStr{
+					common.BKHostIDField:      plan.HostID,
+					common.BKHostInnerIPField: plan.ExpectHost[common.BKHostInnerIPField],
+					common.BKPropertyIDField:  conflict.PropertyID,
+					"current_value":           conflict.PropertyValue,
+					"expected_value":          expected[0],
+				}
+				if len(expected) > 1 {
+					row["expected_value"] = expected
+				}
+				table.rows = append(table.rows, row)
+			}
+		}
+	}
+
+	if len(table.rows) > reportMaxRows {
+		table.rows = table.rows[:reportMaxRows]
+	}
+	return table, nil
+}
+
+// getDriftReportHostModules get the hosts in the modules with their module ids, at most reportMaxRows hosts
+func (lgc *Logics) getDriftReportHostModules(kit *rest.Kit, bizID int64, moduleIDs []int64) (
+	[]metadata.Host2Modules, error) {
+
+	hostModules := make([]metadata.Host2Modules, 0)
+	hostIndexes := make(map[int64]int)
+	for start := 0; len(hostModules) < reportMaxRows; start += reportPageSize {
+		relOpt := &metadata.HostModuleRelationRequest{
+			ApplicationID: bizID,
+			ModuleIDArr:   moduleIDs,
+			Page:          metadata.BasePage{Start: start, Limit: reportPageSize, Sort: common.BKHostIDField},
+			Fields:        []string{common.BKHostIDField, common.BKModuleIDField},
+		}
+		relations, err := lgc.CoreAPI.CoreService().Host().GetHostModuleRelation(kit.Ctx, kit.Header, relOpt)
+		if err != nil {
+			blog.Errorf("get biz %d host module relations failed, err: %v, rid: %s", bizID, err, kit.Rid)
+			return nil, err
+		}
+
+		for _, relation := range relations.Info {
+			index, exists := hostIndexes[relation.HostID]
+			if !exists {
+				index = len(hostModules)
+				hostIndexes[relation.HostID] = index
+				hostModules = append(hostModules, metadata.Host2Modules{HostID: relation.HostID})
+			}
+			hostModules[index].ModuleIDs = append(hostModules[index].ModuleIDs, relation.ModuleID)
+		}
+
+		if len(relations.Info) < reportPageSize {
+			break
+		}
+	}
+
+	if len(hostModules) > reportMaxRows {
+		hostModules = hostModules[:reportMaxRows]
+	}
+	return hostModules, nil
+}
+
+// newReportKit new a kit to generate and deliver the report on behalf of the subscription's creator
+func newReportKit(supplierAccount, user string) *rest.Kit {
+	header := make(http.Header)
+	header.Add(common.BKHTTPOwnerID, supplierAccount)
+	header.Add(common.BKHTTPHeaderUser, user)
+	header.Add(common.BKHTTPLanguage, "cn")
+	header.Add(common.BKHTTPCCRequestID, util.GenerateRID())
+	header.Add("Content-Type", "application/json")
+
+	return &rest.Kit{
+		Rid:             util.GetHTTPCCRequestID(header),
+		Header:          header,
+		Ctx:             util.NewContextFromHTTPHeader(header),
+		CCError:         util.GetDefaultCCError(header),
+		User:            user,
+		SupplierAccount: supplierAccount,
+	}
+}
@@ -0,0 +1,44 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// FindSingleDomainModules find the modules whose hosts all reside in a single fault domain for the resilience audit
+func (o *OperationServer) FindSingleDomainModules(ctx *rest.Contexts) {
+	opt := new(metadata.FindSingleDomainModuleOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	srvData := o.newSrvComm(ctx.Kit.Header)
+	result, err := srvData.lgc.FindSingleDomainModules(ctx.Kit, opt)
+	if err != nil {
+		blog.Errorf("find biz %d single %s modules failed, err: %v, rid: %s", opt.BizID, opt.Level, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
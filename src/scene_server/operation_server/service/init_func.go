@@ -29,4 +29,5 @@ func (o *OperationServer) InitFunc() {
 
 	srvData := o.newSrvComm(header)
 	go srvData.lgc.TimerFreshData(srvData.ctx)
+	go srvData.lgc.TimerSendReport(srvData.ctx)
 }
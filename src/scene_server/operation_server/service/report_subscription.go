@@ -0,0 +1,178 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// CreateReportSubscription create a report subscription
+func (o *OperationServer) CreateReportSubscription(ctx *rest.Contexts) {
+	sub := new(metadata.ReportSubscription)
+	if err := ctx.DecodeInto(sub); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := sub.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := o.CoreAPI.CoreService().Operation().CreateReportSubscription(ctx.Kit.Ctx, ctx.Kit.Header, sub)
+	if err != nil {
+		blog.Errorf("create report subscription failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// UpdateReportSubscription update a report subscription
+func (o *OperationServer) UpdateReportSubscription(ctx *rest.Contexts) {
+	sub, err := o.getReportSubscription(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	data := mapstr.MapStr{}
+	if err := ctx.DecodeInto(&data); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	// the send time is maintained by the report timer
+	data.Remove("last_send_time")
+
+	// validate the subscription which is merged with the updated fields
+	if err := data.MarshalJSONInto(sub); err != nil {
+		blog.Errorf("parse report subscription update data failed, data: %#v, err: %v, rid: %s", data, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommJSONUnmarshalFailed))
+		return
+	}
+	if rawErr := sub.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	err = o.CoreAPI.CoreService().Operation().UpdateReportSubscription(ctx.Kit.Ctx, ctx.Kit.Header, sub.ID, data)
+	if err != nil {
+		blog.Errorf("update report subscription %d failed, err: %v, rid: %s", sub.ID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// DeleteReportSubscription delete a report subscription
+func (o *OperationServer) DeleteReportSubscription(ctx *rest.Contexts) {
+	sub, err := o.getReportSubscription(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if err := o.CoreAPI.CoreService().Operation().DeleteReportSubscription(ctx.Kit.Ctx, ctx.Kit.Header,
+		sub.ID); err != nil {
+		blog.Errorf("delete report subscription %d failed, err: %v, rid: %s", sub.ID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// ListReportSubscription list the report subscriptions of a business
+func (o *OperationServer) ListReportSubscription(ctx *rest.Contexts) {
+	opt := new(metadata.ListReportSubscriptionOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if opt.BizID <= 0 {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKAppIDField))
+		return
+	}
+
+	if err := opt.Page.ValidateLimit(common.BKMaxPageSize); err != nil {
+		blog.Errorf("list report subscription page is invalid, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "page"))
+		return
+	}
+
+	query := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{common.BKAppIDField: opt.BizID},
+		Page:      opt.Page,
+	}
+	result, err := o.CoreAPI.CoreService().Operation().SearchReportSubscription(ctx.Kit.Ctx, ctx.Kit.Header, query)
+	if err != nil {
+		blog.Errorf("search report subscription failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// SendReportSubscription send the report of the subscription immediately
+func (o *OperationServer) SendReportSubscription(ctx *rest.Contexts) {
+	sub, err := o.getReportSubscription(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	srvData := o.newSrvComm(ctx.Kit.Header)
+	if err := srvData.lgc.SendReport(ctx.Kit, sub); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+func (o *OperationServer) getReportSubscription(ctx *rest.Contexts) (*metadata.ReportSubscription, error) {
+	idStr := ctx.Request.PathParameter(common.BKFieldID)
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		blog.Errorf("failed to parse the path params id(%s), err: %v, rid: %s", idStr, err, ctx.Kit.Rid)
+		return nil, ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKFieldID)
+	}
+
+	query := &metadata.QueryCondition{
+		Condition:      mapstr.MapStr{common.BKFieldID: id},
+		DisableCounter: true,
+	}
+	result, err := o.CoreAPI.CoreService().Operation().SearchReportSubscription(ctx.Kit.Ctx, ctx.Kit.Header, query)
+	if err != nil {
+		blog.Errorf("search report subscription %d failed, err: %v, rid: %s", id, err, ctx.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Info) == 0 {
+		blog.Errorf("report subscription %d is not exist, rid: %s", id, ctx.Kit.Rid)
+		return nil, ctx.Kit.CCError.CCError(common.CCErrCommNotFound)
+	}
+
+	return &result.Info[0], nil
+}
@@ -90,6 +90,7 @@ func (o *OperationServer) WebService() *restful.Container {
 
 	api := new(restful.WebService)
 	api.Path("/operation/v3").Filter(o.Engine.Metric().RestfulMiddleWare).Filter(rdapi.AllGlobalFilter(getErrFunc)).Produces(restful.MIME_JSON)
+	api.Filter(o.Engine.ReadOnlyFilter())
 	restful.DefaultRequestContentType(restful.MIME_JSON)
 	restful.DefaultResponseContentType(restful.MIME_JSON)
 
@@ -123,6 +124,21 @@ func (o *OperationServer) newOperationService(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/operation/chart/data", Handler: o.SearchChartData})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/update/operation/chart/position", Handler: o.UpdateChartPosition})
 
+	// report subscription
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/operation/report_subscription", Handler: o.CreateReportSubscription})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/operation/report_subscription/{id}", Handler: o.UpdateReportSubscription})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/operation/report_subscription/{id}", Handler: o.DeleteReportSubscription})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/operation/report_subscription", Handler: o.ListReportSubscription})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/operation/report_subscription/{id}/send", Handler: o.SendReportSubscription})
+
+	// host placement
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/operation/host_placement/single_domain_module",
+		Handler: o.FindSingleDomainModules})
+
+	// change digest
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/operation/change_digest",
+		Handler: o.FindChangeDigest})
+
 	utility.AddToRestfulWebService(web)
 }
 
@@ -232,6 +232,9 @@ func (lgc *Logic) CreateProcessInstances(kit *rest.Kit, processDatas []map[strin
 
 	data := make([]mapstr.MapStr, len(processDatas))
 	for idx := range processDatas {
+		if err := lgc.validateProcessControlData(kit, processDatas[idx]); err != nil {
+			return nil, err
+		}
 		data[idx] = processDatas[idx]
 	}
 
@@ -592,6 +595,25 @@ func (lgc *Logic) DiffWithProcessTemplate(t *metadata.ProcessProperty, i *metada
 		}
 	}
 
+	if metadata.IsAsDefaultValue(t.ConfigTemplates.AsDefaultValue) {
+		var configTemplates metadata.ConfigTemplateRefs
+		if t.ConfigTemplates.Value != nil {
+			configTemplates = *t.ConfigTemplates.Value
+		}
+		if !configTemplates.Equal(i.ConfigTemplates) {
+			if !needDetail {
+				return changes, true, nil
+			}
+			changes = append(changes, metadata.ProcessChangedAttribute{
+				ID:                    attrMap[common.BKConfigTemplatesField].ID,
+				PropertyID:            common.BKConfigTemplatesField,
+				PropertyName:          attrMap[common.BKConfigTemplatesField].PropertyName,
+				PropertyValue:         i.ConfigTemplates,
+				TemplatePropertyValue: t.ConfigTemplates,
+			})
+		}
+	}
+
 	return changes, len(changes) > 0, nil
 }
 
@@ -622,3 +644,36 @@ func (lgc *Logic) GetHostIPMapByID(kit *rest.Kit, hostIDs []int64) (map[int64]ma
 
 	return hostMap, nil
 }
+
+// validateProcessControlData validate the structured control data and the config template references of the process
+// to be created, the other fields are validated by the process model attributes.
+func (lgc *Logic) validateProcessControlData(kit *rest.Kit, processData map[string]interface{}) errors.CCErrorCoder {
+	process := new(metadata.Process)
+	fields := []string{common.BKProcCommandsField, common.BKProcEnvsField, common.BKConfigTemplatesField}
+	for _, field := range fields {
+		value, exists := processData[field]
+		if !exists {
+			continue
+		}
+
+		if err := mapstr.DecodeFromMapStr(process, mapstr.MapStr{field: value}); err != nil {
+			blog.Errorf("decode process %s failed, value: %#v, err: %v, rid: %s", field, value, err, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, field)
+		}
+	}
+
+	if rawErr := process.Commands.Validate(); rawErr.ErrCode != 0 {
+		return rawErr.ToCCError(kit.CCError)
+	}
+
+	if rawErr := process.Envs.Validate(); rawErr.ErrCode != 0 {
+		return rawErr.ToCCError(kit.CCError)
+	}
+
+	if err := process.ConfigTemplates.Validate(); err != nil {
+		blog.Errorf("process config templates %#v are invalid, err: %v, rid: %s", process.ConfigTemplates, err, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKConfigTemplatesField)
+	}
+
+	return nil
+}
@@ -453,8 +453,19 @@ func (ps *ProcServer) updateProcessInstances(ctx *rest.Contexts, input metadata.
 	}
 
 	process2ServiceInstanceMap := make(map[int64]*metadata.ProcessInstanceRelation)
+	templateSrvInstIDs := make([]int64, 0)
 	for i := range relations.Info {
 		process2ServiceInstanceMap[relations.Info[i].ProcessID] = &relations.Info[i]
+		if relations.Info[i].ProcessTemplateID != common.ServiceTemplateIDNotSet {
+			templateSrvInstIDs = append(templateSrvInstIDs, relations.Info[i].ServiceInstanceID)
+		}
+	}
+
+	// the template values are resolved with the template variables bound on the service instance's module
+	srvInstModuleMap, moduleVariables, err := ps.getSrvInstTemplateVariables(ctx.Kit, bizID,
+		util.IntArrayUnique(templateSrvInstIDs))
+	if err != nil {
+		return nil, err
 	}
 
 	hostMap, err := ps.Logic.GetHostIPMapByID(ctx.Kit, hostIDs)
@@ -501,6 +512,17 @@ func (ps *ProcServer) updateProcessInstances(ctx *rest.Contexts, input metadata.
 				blog.Errorf("update process instance failed, process related template not found, relation: %+v, err: %v, rid: %s", relation, err, rid)
 				return nil, err
 			}
+			moduleID := srvInstModuleMap[relation.ServiceInstanceID]
+			resolvedTemplate, missing, resolveErr := processTemplate.ResolveVariables(moduleVariables[moduleID])
+			if resolveErr != nil {
+				blog.Errorf("resolve process template %d failed, err: %v, rid: %s", processTemplate.ID, resolveErr, rid)
+				return nil, errors.New(common.CCErrCommParamsInvalid, resolveErr.Error())
+			}
+			if len(missing) > 0 {
+				return nil, templateVariableNotBoundError(ctx.Kit, common.BKInnerObjIDModule, moduleID, missing)
+			}
+			processTemplate = resolvedTemplate
+
 			var compareErr error
 			processData, compareErr = processTemplate.ExtractInstanceUpdateData(&process, hostMap[relation.HostID])
 			if compareErr != nil {
@@ -658,6 +680,18 @@ func (ps *ProcServer) validateProcessInstance(kit *rest.Kit, process *metadata.P
 			return kit.CCError.CCErrorf(common.CCErrCommParamsNeedSet, common.BKProcBindInfo+"."+common.BKProtocol)
 		}
 	}
+	if rawErr := process.Commands.Validate(); rawErr.ErrCode != 0 {
+		return rawErr.ToCCError(kit.CCError)
+	}
+
+	if rawErr := process.Envs.Validate(); rawErr.ErrCode != 0 {
+		return rawErr.ToCCError(kit.CCError)
+	}
+
+	if err := process.ConfigTemplates.Validate(); err != nil {
+		blog.Errorf("process config templates %#v are invalid, err: %v, rid: %s", process.ConfigTemplates, err, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKConfigTemplatesField)
+	}
 
 	return nil
 }
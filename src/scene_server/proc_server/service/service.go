@@ -55,6 +55,7 @@ func (ps *ProcServer) WebService() *restful.Container {
 	api.Path("/process/v3")
 	api.Filter(ps.Engine.Metric().RestfulMiddleWare)
 	api.Filter(rdapi.AllGlobalFilter(getErrFunc))
+	api.Filter(ps.Engine.ReadOnlyFilter())
 	api.Produces(restful.MIME_JSON)
 	restful.DefaultRequestContentType(restful.MIME_JSON)
 	restful.DefaultResponseContentType(restful.MIME_JSON)
@@ -151,6 +152,35 @@ func (ps *ProcServer) newProcessService(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/proc/service_instance/details", Handler: ps.ListServiceInstancesDetails})
 	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/updatemany/proc/service_instance/biz/{bk_biz_id}", Handler: ps.UpdateServiceInstances})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/deletemany/proc/service_instance", Handler: ps.DeleteServiceInstance})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/proc/service_instance/batch_delete/preview",
+		Handler: ps.PreviewBatchDeleteServiceInstances})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/deletemany/proc/service_instance/batch",
+		Handler: ps.BatchDeleteServiceInstances})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut,
+		Path:    "/updatemany/proc/service_instance/name/render/biz/{bk_biz_id}",
+		Handler: ps.RenderServiceInstanceNames})
+
+	// service instance naming template
+	utility.AddHandler(rest.Action{Verb: http.MethodPut,
+		Path:    "/update/proc/service_instance/name_template/biz/{bk_biz_id}",
+		Handler: ps.SaveSrvInstNameTemplate})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost,
+		Path:    "/find/proc/service_instance/name_template/biz/{bk_biz_id}",
+		Handler: ps.FindSrvInstNameTemplate})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete,
+		Path:    "/delete/proc/service_instance/name_template/biz/{bk_biz_id}",
+		Handler: ps.DeleteSrvInstNameTemplate})
+
+	// template variables of the modules and sets
+	utility.AddHandler(rest.Action{Verb: http.MethodPut,
+		Path:    "/update/proc/template_variable/biz/{bk_biz_id}",
+		Handler: ps.SaveTemplateVariable})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost,
+		Path:    "/findmany/proc/template_variable/biz/{bk_biz_id}",
+		Handler: ps.ListTemplateVariable})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete,
+		Path:    "/delete/proc/template_variable/biz/{bk_biz_id}",
+		Handler: ps.DeleteTemplateVariable})
 
 	utility.AddHandler(rest.Action{Verb: http.MethodPost,
 		Path:    "/find/proc/service_template/general_difference",
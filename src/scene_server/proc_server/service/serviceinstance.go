@@ -618,14 +618,16 @@ func (ps *ProcServer) UpdateServiceInstances(ctx *rest.Contexts) {
 		return
 	}
 
-	// generate audit log before service instance is updated, only allow updating service instance name right now
+	// generate audit log before service instance is updated, only allow updating service instance name and config
+	// template references right now
 	svcInstIDs := make([]int64, len(option.Data))
 	for index, data := range option.Data {
 		svcInstIDs[index] = data.ServiceInstanceID
 	}
 
 	audit := auditlog.NewSvcInstAudit(ps.CoreAPI.CoreService())
-	serviceInstances, err := audit.GetSvcInstByIDs(ctx.Kit, bizID, svcInstIDs, []string{common.BKFieldName})
+	serviceInstances, err := audit.GetSvcInstByIDs(ctx.Kit, bizID, svcInstIDs,
+		[]string{common.BKFieldName, common.BKConfigTemplatesField})
 	if err != nil {
 		ctx.RespAutoError(err)
 		return
@@ -880,6 +882,20 @@ func (ps *ProcServer) DiffServiceTemplateGeneral(ctx *rest.Contexts) {
 		return
 	}
 
+	// 用模块绑定的模板变量替换进程模板中的变量占位符，未绑定的变量保留原样以便在差异中展示
+	variables, cErr := ps.getTemplateVariables(ctx.Kit, option.BizID, common.BKInnerObjIDModule, option.ModuleID)
+	if cErr != nil {
+		ctx.RespAutoError(cErr)
+		return
+	}
+
+	resolvedTemplates, _, err := metadata.ResolveProcessTemplates(processTemplates.Info, variables)
+	if err != nil {
+		blog.Errorf("resolve process templates failed, option: %+v, err: %v, rid: %s", option, err, rid)
+	} else {
+		processTemplates.Info = resolvedTemplates
+	}
+
 	// processTemplates->pTemplateMap
 	pTemplateMap := make(map[int64]*metadata.ProcessTemplate)
 	for idx, pTemplate := range processTemplates.Info {
@@ -893,7 +909,7 @@ func (ps *ProcServer) DiffServiceTemplateGeneral(ctx *rest.Contexts) {
 		return
 	}
 
-	result, cErr := ps.serviceTemplateGeneralDiff(ctx, option, modules[0], pTemplateMap)
+	result, cErr := ps.serviceTemplateGeneralDiff(ctx, option, modules[0], pTemplateMap, variables)
 	if cErr != nil {
 		blog.Errorf("calc service template diff failed, option: %+v, err: %v, rid: %s", option, cErr, rid)
 		ctx.RespAutoError(cErr)
@@ -1665,8 +1681,8 @@ func (ps *ProcServer) handleAddedServiceInsts(hostMap map[int64]map[string]inter
 }
 
 func (ps *ProcServer) serviceTemplateGeneralDiff(ctx *rest.Contexts, option *metadata.ServiceTemplateDiffOption,
-	module mapstr.MapStr, pTemplateMap map[int64]*metadata.ProcessTemplate) (*metadata.ServiceTemplateGeneralDiff,
-	ccErr.CCErrorCoder) {
+	module mapstr.MapStr, pTemplateMap map[int64]*metadata.ProcessTemplate, variables map[string]string) (
+	*metadata.ServiceTemplateGeneralDiff, ccErr.CCErrorCoder) {
 
 	// 获取所有的服务实例
 	serviceInstances, cErr := ps.getServiceInstances(ctx, option, []string{common.BKFieldID, common.BKHostIDField})
@@ -1686,7 +1702,7 @@ func (ps *ProcServer) serviceTemplateGeneralDiff(ctx *rest.Contexts, option *met
 		return nil, cErr
 	}
 
-	attrs, cErr := ps.getAttributesResult(ctx.Kit, option, module)
+	attrs, cErr := ps.getAttributesResult(ctx.Kit, option, module, variables)
 	if cErr != nil {
 		blog.Errorf("get service template or module attributes failed, option: %+v, err: %v, rid: %s", *option,
 			cErr, ctx.Kit.Rid)
@@ -1792,7 +1808,7 @@ func (ps *ProcServer) getModuleAttrIDAndPropertyID(kit *rest.Kit, attrIDs []int6
 
 // getAttributesResult 获取同一属性ID的模板和模块的属性值
 func (ps *ProcServer) getAttributesResult(kit *rest.Kit, option *metadata.ServiceTemplateDiffOption,
-	module mapstr.MapStr) ([]metadata.AttributeFields, ccErr.CCErrorCoder) {
+	module mapstr.MapStr, variables map[string]string) ([]metadata.AttributeFields, ccErr.CCErrorCoder) {
 
 	attrValues := make([]metadata.AttributeFields, 0)
 	// 1、获取指定服务模板的属性ID及属性值
@@ -1822,7 +1838,10 @@ func (ps *ProcServer) getAttributesResult(kit *rest.Kit, option *metadata.Servic
 		}
 	}
 
-	// 4、整理数据
+	// 4、用模块绑定的模板变量替换属性值中的变量占位符，未绑定的变量保留原样
+	srvTemplateAttrValueMap, _ = resolveSrvTemplateAttrValues(srvTemplateAttrValueMap, variables)
+
+	// 5、整理数据
 	for id, attr := range srvTemplateAttrValueMap {
 		attrValues = append(attrValues, metadata.AttributeFields{
 			ID:                    id,
@@ -2118,8 +2137,8 @@ func (ps *ProcServer) syncProcessAndSrvInstToRemove(kit *rest.Kit, svcTempID int
 }
 
 // updateModuleAttributes 通过当前的模板属性值更新对应的模块属性值
-func (ps *ProcServer) updateModuleAttributes(kit *rest.Kit, option metadata.ServiceTemplateDiffOption) (
-	*moduleSimpleInfo, ccErr.CCErrorCoder) {
+func (ps *ProcServer) updateModuleAttributes(kit *rest.Kit, option metadata.ServiceTemplateDiffOption,
+	variables map[string]string) (*moduleSimpleInfo, ccErr.CCErrorCoder) {
 
 	// 1、获取服务模板的属性id与对应的property_value
 	attrIDs, srvTemplateAttrValueMap, cErr := ps.getSrvTemplateAttrIdAndPropertyValue(kit, option.BizID,
@@ -2128,6 +2147,12 @@ func (ps *ProcServer) updateModuleAttributes(kit *rest.Kit, option metadata.Serv
 		return nil, cErr
 	}
 
+	// 用模块绑定的模板变量替换属性值中的变量占位符，所有引用的变量都必须已绑定
+	srvTemplateAttrValueMap, missing := resolveSrvTemplateAttrValues(srvTemplateAttrValueMap, variables)
+	if len(missing) > 0 {
+		return nil, templateVariableNotBoundError(kit, common.BKInnerObjIDModule, option.ModuleID, missing)
+	}
+
 	// 2、根据属性id获取对应的 property_ids
 	propertyIDs, attrIdPropertyMap, cErr := ps.getModuleAttrIDAndPropertyID(kit, attrIDs)
 	if cErr != nil {
@@ -2334,11 +2359,22 @@ func (ps *ProcServer) doSyncServiceInstanceTask(kit *rest.Kit,
 		return cErr
 	}
 
+	// resolve the template variables in the process templates and attributes with the ones bound on the module.
+	variables, cErr := ps.getTemplateVariables(kit, syncOption.BizID, common.BKInnerObjIDModule, syncOption.ModuleID)
+	if cErr != nil {
+		return cErr
+	}
+
+	if cErr = ps.resolveProcessTemplates(kit, syncOption.ModuleID, variables, processRelationInfo.procTemps,
+		processRelationInfo.processTemplateMap); cErr != nil {
+		return cErr
+	}
+
 	// update module service category and attributes.
-	module, cErr := ps.updateModuleAttributes(kit, syncOption)
+	module, cErr := ps.updateModuleAttributes(kit, syncOption, variables)
 	if cErr != nil {
 		blog.Errorf("update module attributes failed, option: %+v, err: %v, rid: %s", syncOption, cErr, kit.Rid)
-		return nil
+		return cErr
 	}
 
 	if err := ps.syncSrvInstToAdd(kit, syncOption, serviceInstanceInfo.hostIDs, serviceInstanceInfo.hostWithSrvInstMap,
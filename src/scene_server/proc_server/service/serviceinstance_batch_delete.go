@@ -0,0 +1,218 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"sort"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// PreviewBatchDeleteServiceInstances returns the service instances, processes and template bindings that will be
+// deleted by the service instance batch deletion without deleting them.
+func (ps *ProcServer) PreviewBatchDeleteServiceInstances(ctx *rest.Contexts) {
+	option := new(metadata.ServiceInstanceBatchDeleteOption)
+	if err := ctx.DecodeInto(option); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := option.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	preview, err := ps.previewBatchDeleteServiceInstances(ctx.Kit, option)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	ctx.RespEntity(preview)
+}
+
+// BatchDeleteServiceInstances deletes the service instances in the business by their ids or by a filter with their
+// processes. The service instances are deleted in chunks and each chunk is deleted in one transaction with its audit
+// logs saved together, so the failure of a chunk does not roll back the other chunks, and the result of each service
+// instance is returned.
+func (ps *ProcServer) BatchDeleteServiceInstances(ctx *rest.Contexts) {
+	option := new(metadata.ServiceInstanceBatchDeleteOption)
+	if err := ctx.DecodeInto(option); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := option.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	preview, err := ps.previewBatchDeleteServiceInstances(ctx.Kit, option)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ids := preview.ServiceInstanceIDs
+	result := &metadata.ServiceInstanceBatchDeleteResult{
+		Preview: *preview,
+		Results: make([]metadata.ServiceInstanceDeleteResult, len(ids)),
+	}
+	for start := 0; start < len(ids); start += option.ChunkSize {
+		end := start + option.ChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		chunk := ids[start:end]
+		txnErr := ps.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
+			return ps.deleteServiceInstance(ctx.Kit, option.BizID, chunk)
+		})
+		if txnErr != nil {
+			blog.Errorf("delete service instances %v failed, err: %v, rid: %s", chunk, txnErr, ctx.Kit.Rid)
+		}
+
+		for idx := start; idx < end; idx++ {
+			result.Results[idx] = newServiceInstanceDeleteResult(ids[idx], txnErr)
+			if txnErr != nil {
+				result.FailedCount++
+				continue
+			}
+			result.SuccessCount++
+		}
+	}
+
+	ctx.RespEntity(result)
+}
+
+// previewBatchDeleteServiceInstances gets the service instances to be deleted by their ids or by the filter, and
+// their processes and template bindings.
+func (ps *ProcServer) previewBatchDeleteServiceInstances(kit *rest.Kit,
+	option *metadata.ServiceInstanceBatchDeleteOption) (*metadata.ServiceInstanceBatchDeletePreview, error) {
+
+	listOption := &metadata.ListServiceInstanceOption{
+		BusinessID: option.BizID,
+		Fields:     []string{common.BKFieldID, common.BKServiceTemplateIDField},
+		Page:       metadata.BasePage{Limit: metadata.ServiceInstanceBatchDeleteMaxCount, Sort: common.BKFieldID},
+	}
+	if len(option.ServiceInstanceIDs) > 0 {
+		listOption.ServiceInstanceIDs = util.IntArrayUnique(option.ServiceInstanceIDs)
+	} else {
+		listOption.ModuleIDs = option.Filter.ModuleIDs
+		listOption.HostIDs = option.Filter.HostIDs
+		listOption.ServiceTemplateID = option.Filter.ServiceTemplateID
+	}
+
+	instances, err := ps.CoreAPI.CoreService().Process().ListServiceInstance(kit.Ctx, kit.Header, listOption)
+	if err != nil {
+		blog.Errorf("list service instances failed, option: %#v, err: %v, rid: %s", listOption, err, kit.Rid)
+		return nil, err
+	}
+
+	if instances.Count > metadata.ServiceInstanceBatchDeleteMaxCount {
+		blog.Errorf("%d service instances matched exceeds limit, option: %#v, rid: %s", instances.Count,
+			listOption, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommXXExceedLimit, "service instances",
+			metadata.ServiceInstanceBatchDeleteMaxCount)
+	}
+
+	// the service instances specified by ids must all exist in the business
+	if len(listOption.ServiceInstanceIDs) > 0 && len(instances.Info) != len(listOption.ServiceInstanceIDs) {
+		blog.Errorf("service instances %v are not all in biz %d, rid: %s", listOption.ServiceInstanceIDs,
+			option.BizID, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "service_instance_ids")
+	}
+
+	preview := &metadata.ServiceInstanceBatchDeletePreview{
+		ServiceInstanceIDs: make([]int64, 0, len(instances.Info)),
+		ProcessIDs:         make([]int64, 0),
+		ServiceTemplates:   make([]metadata.ServiceTemplateDeleteBinding, 0),
+	}
+	if len(instances.Info) == 0 {
+		return preview, nil
+	}
+
+	bindingMap := make(map[int64]*metadata.ServiceTemplateDeleteBinding)
+	instTemplateMap := make(map[int64]int64, len(instances.Info))
+	for _, instance := range instances.Info {
+		preview.ServiceInstanceIDs = append(preview.ServiceInstanceIDs, instance.ID)
+		instTemplateMap[instance.ID] = instance.ServiceTemplateID
+		binding, exists := bindingMap[instance.ServiceTemplateID]
+		if !exists {
+			binding = &metadata.ServiceTemplateDeleteBinding{
+				ServiceTemplateID:  instance.ServiceTemplateID,
+				ProcessTemplateIDs: make([]int64, 0),
+			}
+			bindingMap[instance.ServiceTemplateID] = binding
+		}
+		binding.ServiceInstanceCount++
+	}
+
+	relationOption := &metadata.ListProcessInstanceRelationOption{
+		BusinessID:         option.BizID,
+		ServiceInstanceIDs: preview.ServiceInstanceIDs,
+		Page:               metadata.BasePage{Limit: common.BKNoLimit},
+	}
+	relations, err := ps.CoreAPI.CoreService().Process().ListProcessInstanceRelation(kit.Ctx, kit.Header,
+		relationOption)
+	if err != nil {
+		blog.Errorf("list service instances process relations failed, err: %v, rid: %s", err, kit.Rid)
+		return nil, err
+	}
+
+	for _, relation := range relations.Info {
+		preview.ProcessIDs = append(preview.ProcessIDs, relation.ProcessID)
+		binding := bindingMap[instTemplateMap[relation.ServiceInstanceID]]
+		if binding == nil {
+			continue
+		}
+		binding.ProcessCount++
+		if relation.ProcessTemplateID > 0 {
+			binding.ProcessTemplateIDs = append(binding.ProcessTemplateIDs, relation.ProcessTemplateID)
+		}
+	}
+
+	for _, binding := range bindingMap {
+		binding.ProcessTemplateIDs = util.IntArrayUnique(binding.ProcessTemplateIDs)
+		sort.Slice(binding.ProcessTemplateIDs, func(i, j int) bool {
+			return binding.ProcessTemplateIDs[i] < binding.ProcessTemplateIDs[j]
+		})
+		preview.ServiceTemplates = append(preview.ServiceTemplates, *binding)
+	}
+	sort.Slice(preview.ServiceTemplates, func(i, j int) bool {
+		return preview.ServiceTemplates[i].ServiceTemplateID < preview.ServiceTemplates[j].ServiceTemplateID
+	})
+
+	return preview, nil
+}
+
+// newServiceInstanceDeleteResult returns the deletion result of the service instance with the error of its chunk.
+func newServiceInstanceDeleteResult(id int64, err error) metadata.ServiceInstanceDeleteResult {
+	if err == nil {
+		return metadata.ServiceInstanceDeleteResult{ServiceInstanceID: id, Success: true}
+	}
+
+	result := metadata.ServiceInstanceDeleteResult{
+		ServiceInstanceID: id,
+		Code:              common.CCErrCommHTTPDoRequestFailed,
+		Message:           err.Error(),
+	}
+	if ccErr, ok := err.(errors.CCErrorCoder); ok {
+		result.Code = ccErr.GetCode()
+	}
+	return result
+}
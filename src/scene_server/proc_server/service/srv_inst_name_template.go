@@ -0,0 +1,137 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// SaveSrvInstNameTemplate create or replace the business's service instance naming template, the names of the
+// existing service instances are not changed until they are rendered again.
+func (ps *ProcServer) SaveSrvInstNameTemplate(ctx *rest.Contexts) {
+	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
+	if err != nil {
+		blog.Errorf("parse bk_biz_id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField))
+		return
+	}
+
+	template := new(metadata.SrvInstNameTemplate)
+	if err := ctx.DecodeInto(template); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	template.BizID = bizID
+
+	if rawErr := template.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, ccErr := ps.CoreAPI.CoreService().Process().SaveSrvInstNameTemplate(ctx.Kit.Ctx, ctx.Kit.Header,
+		template)
+	if ccErr != nil {
+		blog.Errorf("save biz %d service instance name template failed, err: %v, rid: %s", bizID, ccErr, ctx.Kit.Rid)
+		ctx.RespAutoError(ccErr)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// FindSrvInstNameTemplate find the business's service instance naming template, returns nil if it is not set.
+func (ps *ProcServer) FindSrvInstNameTemplate(ctx *rest.Contexts) {
+	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
+	if err != nil {
+		blog.Errorf("parse bk_biz_id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField))
+		return
+	}
+
+	opt := &metadata.SrvInstNameTemplateOption{BizID: bizID}
+	template, ccErr := ps.CoreAPI.CoreService().Process().ReadSrvInstNameTemplate(ctx.Kit.Ctx, ctx.Kit.Header, opt)
+	if ccErr != nil {
+		blog.Errorf("find biz %d service instance name template failed, err: %v, rid: %s", bizID, ccErr, ctx.Kit.Rid)
+		ctx.RespAutoError(ccErr)
+		return
+	}
+
+	ctx.RespEntity(template)
+}
+
+// DeleteSrvInstNameTemplate delete the business's service instance naming template, the default naming rule is
+// used afterwards.
+func (ps *ProcServer) DeleteSrvInstNameTemplate(ctx *rest.Contexts) {
+	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
+	if err != nil {
+		blog.Errorf("parse bk_biz_id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField))
+		return
+	}
+
+	opt := &metadata.SrvInstNameTemplateOption{BizID: bizID}
+	if ccErr := ps.CoreAPI.CoreService().Process().DeleteSrvInstNameTemplate(ctx.Kit.Ctx, ctx.Kit.Header,
+		opt); ccErr != nil {
+		blog.Errorf("delete biz %d service instance name template failed, err: %v, rid: %s", bizID, ccErr,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ccErr)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// RenderServiceInstanceNames render the names of the service instances in the business again with the business's
+// current naming template, or with the default naming rule if the business has no naming template.
+func (ps *ProcServer) RenderServiceInstanceNames(ctx *rest.Contexts) {
+	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
+	if err != nil {
+		blog.Errorf("parse bk_biz_id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField))
+		return
+	}
+
+	opt := new(metadata.RenderSrvInstNameOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	opt.BizID = bizID
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	var count uint64
+	txnErr := ps.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
+		var ccErr error
+		count, ccErr = ps.CoreAPI.CoreService().Process().RenderServiceInstanceNames(ctx.Kit.Ctx, ctx.Kit.Header, opt)
+		if ccErr != nil {
+			blog.Errorf("render service instance names failed, opt: %#v, err: %v, rid: %s", opt, ccErr, ctx.Kit.Rid)
+			return ccErr
+		}
+		return nil
+	})
+
+	if txnErr != nil {
+		ctx.RespAutoError(txnErr)
+		return
+	}
+	ctx.RespEntity(metadata.CountResponseContent{Count: count})
+}
@@ -0,0 +1,239 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	ccErr "configcenter/src/common/errors"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// SaveTemplateVariable create or replace the template variables bound on a module or a set, the module or set is
+// not changed until it is synchronized with its template again.
+func (ps *ProcServer) SaveTemplateVariable(ctx *rest.Contexts) {
+	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
+	if err != nil {
+		blog.Errorf("parse bk_biz_id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField))
+		return
+	}
+
+	variable := new(metadata.TemplateVariable)
+	if err := ctx.DecodeInto(variable); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	variable.BizID = bizID
+
+	if rawErr := variable.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, ccErr := ps.CoreAPI.CoreService().Process().SaveTemplateVariable(ctx.Kit.Ctx, ctx.Kit.Header, variable)
+	if ccErr != nil {
+		blog.Errorf("save biz %d template variable failed, variable: %#v, err: %v, rid: %s", bizID, variable, ccErr,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ccErr)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// ListTemplateVariable list the template variables bound on the modules or sets in the business.
+func (ps *ProcServer) ListTemplateVariable(ctx *rest.Contexts) {
+	opt, ok := decodeTemplateVariableOption(ctx)
+	if !ok {
+		return
+	}
+
+	variables, ccErr := ps.CoreAPI.CoreService().Process().ListTemplateVariable(ctx.Kit.Ctx, ctx.Kit.Header, opt)
+	if ccErr != nil {
+		blog.Errorf("list template variables failed, opt: %#v, err: %v, rid: %s", opt, ccErr, ctx.Kit.Rid)
+		ctx.RespAutoError(ccErr)
+		return
+	}
+
+	ctx.RespEntity(variables)
+}
+
+// DeleteTemplateVariable delete the template variables bound on the modules or sets in the business.
+func (ps *ProcServer) DeleteTemplateVariable(ctx *rest.Contexts) {
+	opt, ok := decodeTemplateVariableOption(ctx)
+	if !ok {
+		return
+	}
+
+	if ccErr := ps.CoreAPI.CoreService().Process().DeleteTemplateVariable(ctx.Kit.Ctx, ctx.Kit.Header,
+		opt); ccErr != nil {
+		blog.Errorf("delete template variables failed, opt: %#v, err: %v, rid: %s", opt, ccErr, ctx.Kit.Rid)
+		ctx.RespAutoError(ccErr)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+func decodeTemplateVariableOption(ctx *rest.Contexts) (*metadata.TemplateVariableOption, bool) {
+	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
+	if err != nil {
+		blog.Errorf("parse bk_biz_id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField))
+		return nil, false
+	}
+
+	opt := new(metadata.TemplateVariableOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return nil, false
+	}
+	opt.BizID = bizID
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return nil, false
+	}
+
+	return opt, true
+}
+
+// getTemplateVariables get the template variables bound on the module or set, returns nil if no variable is bound.
+func (ps *ProcServer) getTemplateVariables(kit *rest.Kit, bizID int64, objID string, instID int64) (
+	map[string]string, ccErr.CCErrorCoder) {
+
+	opt := &metadata.TemplateVariableOption{BizID: bizID, ObjID: objID, InstIDs: []int64{instID}}
+	variables, err := ps.CoreAPI.CoreService().Process().ListTemplateVariable(kit.Ctx, kit.Header, opt)
+	if err != nil {
+		blog.Errorf("list template variables failed, opt: %#v, err: %v, rid: %s", opt, err, kit.Rid)
+		return nil, err
+	}
+
+	if len(variables) == 0 {
+		return nil, nil
+	}
+	return variables[0].Variables, nil
+}
+
+// resolveProcessTemplates replaces the template variable placeholders of the process templates with the template
+// variables bound on the module, all the referenced variables must be bound when the module is synchronized.
+func (ps *ProcServer) resolveProcessTemplates(kit *rest.Kit, moduleID int64, variables map[string]string,
+	procTemps *metadata.MultipleProcessTemplate,
+	processTemplateMap map[int64]*metadata.ProcessTemplate) ccErr.CCErrorCoder {
+
+	resolved, missing, err := metadata.ResolveProcessTemplates(procTemps.Info, variables)
+	if err != nil {
+		blog.Errorf("resolve process templates of module %d failed, err: %v, rid: %s", moduleID, err, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	if len(missing) > 0 {
+		return templateVariableNotBoundError(kit, common.BKInnerObjIDModule, moduleID, missing)
+	}
+
+	procTemps.Info = resolved
+	for idx := range procTemps.Info {
+		processTemplateMap[procTemps.Info[idx].ID] = &procTemps.Info[idx]
+	}
+	return nil
+}
+
+// resolveSrvTemplateAttrValues replaces the template variable placeholders in the service template's attribute values
+// with the template variables bound on the module, returns the resolved values and the names of the unbound variables
+func resolveSrvTemplateAttrValues(values map[int64]interface{}, variables map[string]string) (map[int64]interface{},
+	[]string) {
+
+	resolved := make(map[int64]interface{}, len(values))
+	missingMap := make(map[string]struct{})
+	for attrID, value := range values {
+		var missing []string
+		resolved[attrID], missing = metadata.ResolveTemplateVariables(value, variables)
+		for _, name := range missing {
+			missingMap[name] = struct{}{}
+		}
+	}
+
+	missing := make([]string, 0, len(missingMap))
+	for name := range missingMap {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	return resolved, missing
+}
+
+func templateVariableNotBoundError(kit *rest.Kit, objID string, instID int64, missing []string) ccErr.CCErrorCoder {
+	blog.Errorf("template variables %v are not bound on %s %d, rid: %s", missing, objID, instID, kit.Rid)
+	return kit.CCError.CCErrorf(common.CCErrCommTemplateVariableNotBound, strings.Join(missing, ","), objID, instID)
+}
+
+// getSrvInstTemplateVariables get the template variables bound on the modules of the service instances, returns the
+// map of the service instance id to its module id, and the map of the module id to the variables bound on it.
+func (ps *ProcServer) getSrvInstTemplateVariables(kit *rest.Kit, bizID int64, srvInstIDs []int64) (map[int64]int64,
+	map[int64]map[string]string, ccErr.CCErrorCoder) {
+
+	srvInstModuleMap := make(map[int64]int64)
+	moduleVariables := make(map[int64]map[string]string)
+	if len(srvInstIDs) == 0 {
+		return srvInstModuleMap, moduleVariables, nil
+	}
+
+	srvInstOpt := &metadata.ListServiceInstanceOption{
+		BusinessID:         bizID,
+		ServiceInstanceIDs: srvInstIDs,
+		Fields:             []string{common.BKFieldID, common.BKModuleIDField},
+		Page:               metadata.BasePage{Limit: common.BKNoLimit},
+	}
+	srvInsts, err := ps.CoreAPI.CoreService().Process().ListServiceInstance(kit.Ctx, kit.Header, srvInstOpt)
+	if err != nil {
+		blog.Errorf("list service instances failed, opt: %#v, err: %v, rid: %s", srvInstOpt, err, kit.Rid)
+		return nil, nil, err
+	}
+
+	moduleIDs := make([]int64, 0)
+	for _, srvInst := range srvInsts.Info {
+		srvInstModuleMap[srvInst.ID] = srvInst.ModuleID
+		moduleIDs = append(moduleIDs, srvInst.ModuleID)
+	}
+	moduleIDs = util.IntArrayUnique(moduleIDs)
+
+	for start := 0; start < len(moduleIDs); start += metadata.TemplateVariableListMaxCount {
+		end := start + metadata.TemplateVariableListMaxCount
+		if end > len(moduleIDs) {
+			end = len(moduleIDs)
+		}
+
+		opt := &metadata.TemplateVariableOption{
+			BizID:   bizID,
+			ObjID:   common.BKInnerObjIDModule,
+			InstIDs: moduleIDs[start:end],
+		}
+		variables, err := ps.CoreAPI.CoreService().Process().ListTemplateVariable(kit.Ctx, kit.Header, opt)
+		if err != nil {
+			blog.Errorf("list template variables failed, opt: %#v, err: %v, rid: %s", opt, err, kit.Rid)
+			return nil, nil, err
+		}
+
+		for _, variable := range variables {
+			moduleVariables[variable.InstID] = variable.Variables
+		}
+	}
+
+	return srvInstModuleMap, moduleVariables, nil
+}
@@ -14,6 +14,8 @@
 package options
 
 import (
+	"time"
+
 	"github.com/spf13/pflag"
 
 	"configcenter/src/common/core/cc/config"
@@ -43,8 +45,21 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.ServConf.ExConfig, "config", "", "The config path. e.g conf/api.conf")
 }
 
+// DefaultFileRetention the default time that the files generated by the tasks are kept
+const DefaultFileRetention = 72 * time.Hour
+
 // Config TODO
 type Config struct {
 	Redis redis.Config
 	Mongo mongo.Config
+	// FileRetention the time that the files generated by the tasks are kept, they can be downloaded in this period
+	FileRetention time.Duration
+}
+
+// GetFileRetention get the time that the files generated by the tasks are kept
+func (c *Config) GetFileRetention() time.Duration {
+	if c.FileRetention <= 0 {
+		return DefaultFileRetention
+	}
+	return c.FileRetention
 }
@@ -116,6 +116,9 @@ func Run(ctx context.Context, cancel context.CancelFunc, op *options.ServerOptio
 	return nil
 }
 
+// fileRetentionHoursKey the config key of the hours that the files generated by the tasks are kept
+const fileRetentionHoursKey = "taskServer.fileRetentionHours"
+
 // TaskServer TODO
 type TaskServer struct {
 	Core      *backbone.Engine
@@ -133,4 +136,14 @@ func (h *TaskServer) onHostConfigUpdate(previous, current cc.ProcessConfig) {
 	if h.Config == nil {
 		h.Config = new(options.Config)
 	}
+
+	h.Config.FileRetention = options.DefaultFileRetention
+	if cc.IsExist(fileRetentionHoursKey) {
+		hours, err := cc.Int(fileRetentionHoursKey)
+		if err != nil || hours <= 0 {
+			blog.Errorf("task file retention hours config is invalid, use the default one, err: %v", err)
+			return
+		}
+		h.Config.FileRetention = time.Duration(hours) * time.Hour
+	}
 }
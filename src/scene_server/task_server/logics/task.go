@@ -67,12 +67,9 @@ func (lgc *Logics) Create(kit *rest.Kit, input *metadata.CreateTaskRequest) (met
 	dbTask.Status = metadata.APITaskStatusNew
 	dbTask.CreateTime = time.Now()
 	dbTask.LastTime = time.Now()
-	for _, taskItem := range input.Data {
-		dbTask.Detail = append(dbTask.Detail, metadata.APISubTaskDetail{
-			SubTaskID: getStrTaskID("sid"),
-			Data:      taskItem,
-			Status:    metadata.APITaskStatusNew,
-		})
+	dbTask.Detail, err = buildSubTasks(kit, input)
+	if err != nil {
+		return dbTask, err
 	}
 	err = lgc.db.Table(common.BKTableNameAPITask).Insert(kit.Ctx, dbTask)
 	if err != nil {
@@ -98,6 +95,30 @@ func (lgc *Logics) Create(kit *rest.Kit, input *metadata.CreateTaskRequest) (met
 	return dbTask, nil
 }
 
+// buildSubTasks build the sub tasks of the task data, each sub task's checkpoint is set if the checkpoints are set.
+func buildSubTasks(kit *rest.Kit, input *metadata.CreateTaskRequest) ([]metadata.APISubTaskDetail, error) {
+	if len(input.Checkpoints) > 0 && len(input.Checkpoints) != len(input.Data) {
+		blog.Errorf("task checkpoints length %d is not equal to the data length %d, rid: %s", len(input.Checkpoints),
+			len(input.Data), kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "checkpoints")
+	}
+
+	subTasks := make([]metadata.APISubTaskDetail, len(input.Data))
+	for index, taskItem := range input.Data {
+		subTasks[index] = metadata.APISubTaskDetail{
+			SubTaskID: getStrTaskID("sid"),
+			Data:      taskItem,
+			Status:    metadata.APITaskStatusNew,
+		}
+
+		if len(input.Checkpoints) > 0 {
+			checkpoint := input.Checkpoints[index]
+			subTasks[index].Checkpoint = &checkpoint
+		}
+	}
+	return subTasks, nil
+}
+
 // CreateBatch create task batch
 func (lgc *Logics) CreateBatch(kit *rest.Kit, tasks []metadata.CreateTaskRequest) ([]metadata.APITaskDetail,
 	error) {
@@ -143,14 +164,11 @@ func (lgc *Logics) CreateBatch(kit *rest.Kit, tasks []metadata.CreateTaskRequest
 		dbTask.TaskID = getStrTaskID("id")
 		dbTask.TaskType = task.TaskType
 		dbTask.InstID = task.InstID
-		dbTask.Detail = make([]metadata.APISubTaskDetail, 0)
-		for _, taskItem := range task.Data {
-			dbTask.Detail = append(dbTask.Detail, metadata.APISubTaskDetail{
-				SubTaskID: getStrTaskID("sid"),
-				Data:      taskItem,
-				Status:    metadata.APITaskStatusNew,
-			})
+		subTasks, err := buildSubTasks(kit, &task)
+		if err != nil {
+			return nil, err
 		}
+		dbTask.Detail = subTasks
 		dbTasks[index] = dbTask
 
 		taskHistory.TaskID = dbTask.TaskID
@@ -289,6 +307,108 @@ func (lgc *Logics) Detail(kit *rest.Kit, taskID string) (*metadata.APITaskDetail
 	return &rows[0], nil
 }
 
+// Resume resume the failed task from its last checkpoint, the finished sub tasks are not executed again, the other
+// sub tasks are reset to be executed by the task queue.
+func (lgc *Logics) Resume(kit *rest.Kit, taskID string) error {
+	task, err := lgc.Detail(kit, taskID)
+	if err != nil {
+		return err
+	}
+
+	if task == nil {
+		return kit.CCError.CCError(common.CCErrTaskNotFound)
+	}
+
+	if !task.Status.IsFailure() {
+		return kit.CCError.CCErrorf(common.CCErrTaskResumeNotFailed, taskID, task.Status)
+	}
+
+	// check if there is another unfinished task created after the task is failed, forbidden execute duplicate tasks
+	duplicateCond := mapstr.MapStr{
+		common.BKTaskTypeField: task.TaskType,
+		common.BKInstIDField:   task.InstID,
+		common.BKStatusField: map[string]interface{}{
+			common.BKDBIN: []metadata.APITaskStatus{metadata.APITaskStatusNew, metadata.APITaskStatusWaitExecute,
+				metadata.APITaskStatusExecute},
+		},
+	}
+	cnt, err := lgc.db.Table(common.BKTableNameAPITask).Find(duplicateCond).Count(kit.Ctx)
+	if err != nil {
+		blog.Errorf("get duplicate tasks failed, err: %v, cond: %#v, rid: %s", err, duplicateCond, kit.Rid)
+		return kit.CCError.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	if cnt > 0 {
+		return kit.CCError.Errorf(common.CCErrTaskCreateConflict, task.InstID)
+	}
+
+	for index := range task.Detail {
+		if task.Detail[index].Status.IsSuccessful() {
+			continue
+		}
+		task.Detail[index].Status = metadata.APITaskStatusWaitExecute
+		task.Detail[index].Response = nil
+	}
+
+	// only update the task if it is still failed, in case that it is resumed concurrently
+	cond := mapstr.MapStr{common.BKTaskIDField: taskID, common.BKStatusField: metadata.APITAskStatusFail}
+	now := time.Now()
+	data := mapstr.MapStr{
+		"detail":             task.Detail,
+		common.BKStatusField: metadata.APITaskStatusWaitExecute,
+		common.LastTimeField: now,
+	}
+	if err := lgc.db.Table(common.BKTableNameAPITask).Update(kit.Ctx, cond, data); err != nil {
+		blog.Errorf("resume task %s failed, err: %v, rid: %s", taskID, err, kit.Rid)
+		return kit.CCError.Error(common.CCErrCommDBUpdateFailed)
+	}
+
+	historyData := mapstr.MapStr{common.BKStatusField: metadata.APITaskStatusWaitExecute, common.LastTimeField: now}
+	err = lgc.db.Table(common.BKTableNameAPITaskSyncHistory).Update(kit.Ctx, cond, historyData)
+	if err != nil {
+		blog.Errorf("resume task %s sync history failed, err: %v, rid: %s", taskID, err, kit.Rid)
+		return kit.CCError.Error(common.CCErrCommDBUpdateFailed)
+	}
+
+	return nil
+}
+
+// ListTaskFile list the file chunks of the finished task in order, the file is expired if any chunk is removed.
+func (lgc *Logics) ListTaskFile(kit *rest.Kit, taskID string) ([]metadata.APITaskFile, error) {
+	task, err := lgc.Detail(kit, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if task == nil {
+		return nil, kit.CCError.CCError(common.CCErrTaskNotFound)
+	}
+
+	if !task.Status.IsSuccessful() {
+		return nil, kit.CCError.CCErrorf(common.CCErrTaskFileNotReady, taskID, task.Status)
+	}
+
+	// mongodb removes the expired data periodically, so the expire time is checked too
+	cond := mapstr.MapStr{
+		common.BKTaskIDField: taskID,
+		"expire_at":          mapstr.MapStr{common.BKDBGT: time.Now()},
+	}
+	files := make([]metadata.APITaskFile, 0)
+	err = lgc.db.Table(common.BKTableNameAPITaskFile).Find(cond).Sort("chunk_id").All(kit.Ctx, &files)
+	if err != nil {
+		blog.Errorf("list task %s files failed, err: %v, rid: %s", taskID, err, kit.Rid)
+		return nil, kit.CCError.Error(common.CCErrCommDBSelectFailed)
+	}
+
+	if len(files) != len(task.Detail) {
+		blog.Errorf("task %s has %d sub tasks, but %d files are found, rid: %s", taskID, len(task.Detail),
+			len(files), kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrTaskFileExpired, taskID)
+	}
+
+	return files, nil
+}
+
 // DeleteTask delete task
 func (lgc *Logics) DeleteTask(kit *rest.Kit, taskCond *metadata.DeleteOption) error {
 	if len(taskCond.Condition) == 0 {
@@ -42,6 +42,8 @@ type TaskInfo struct {
 	Path    string
 	Retry   int64
 	LockTTL int64
+	// OutputFile whether the task's result is a file made up of the sub tasks' response data
+	OutputFile bool
 }
 
 // TaskQueue TODO
@@ -199,7 +201,7 @@ func (tq *TaskQueue) executePush(ctx context.Context, taskInfo TaskInfo, taskQue
 
 	allSucc := true
 
-	for _, subTask := range taskQueue.Detail {
+	for index, subTask := range taskQueue.Detail {
 
 		if subTask.Status == metadata.APITaskStatusSuccess {
 			continue
@@ -235,12 +237,32 @@ func (tq *TaskQueue) executePush(ctx context.Context, taskInfo TaskInfo, taskQue
 		updateCond := mapstr.MapStr{"task_id": taskQueue.TaskID, "detail.sub_task_id": subTask.SubTaskID}
 		updateData := mapstr.New()
 
+		// the file data is saved separately, so that the task is not too large to be saved
+		if err == nil && resp.Result && taskInfo.OutputFile {
+			if fileErr := tq.saveTaskFile(ctx, taskQueue.TaskID, subTask.SubTaskID, int64(index),
+				resp.Data); fileErr != nil {
+				ccErr := tq.service.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(taskQueue.Header)).
+					CCError(common.CCErrCommDBInsertFailed)
+				resp.Result = false
+				resp.Code = ccErr.GetCode()
+				resp.ErrMsg = ccErr.Error()
+			}
+			resp.Data = nil
+		}
+
 		if err != nil || !resp.Result {
 			allSucc = false
 			updateData.Set("detail.$.status", metadata.APITAskStatusFail)
 			updateData.Set("status", metadata.APITAskStatusFail)
 		} else {
 			updateData.Set("detail.$.status", metadata.APITaskStatusSuccess)
+			if subTask.Checkpoint != nil {
+				checkpoint := *subTask.Checkpoint
+				checkpoint.SubTaskID = subTask.SubTaskID
+				now := time.Now()
+				checkpoint.LastTime = &now
+				updateData.Set("checkpoint", checkpoint)
+			}
 		}
 		updateData.Set("detail.$.response", resp)
 		updateData.Set(common.LastTimeField, time.Now())
@@ -284,6 +306,32 @@ func (tq *TaskQueue) executePush(ctx context.Context, taskInfo TaskInfo, taskQue
 	}
 }
 
+// saveTaskFile save the response data of the sub task as a chunk of the task's file, the chunk is replaced if the
+// sub task is executed again when the task is resumed.
+func (tq *TaskQueue) saveTaskFile(ctx context.Context, taskID, subTaskID string, chunkID int64,
+	data interface{}) error {
+
+	file := metadata.APITaskFile{
+		TaskID:    taskID,
+		SubTaskID: subTaskID,
+		ChunkID:   chunkID,
+		Data:      data,
+		ExpireAt:  time.Now().Add(tq.service.Config.GetFileRetention()),
+	}
+	cond := mapstr.MapStr{common.BKTaskIDField: taskID, "sub_task_id": subTaskID}
+
+	var err error
+	for dbRetry := 0; dbRetry < dbMaxRetry; dbRetry++ {
+		if err = tq.service.DB.Table(common.BKTableNameAPITaskFile).Upsert(ctx, cond, file); err != nil {
+			blog.Errorf("save task file failed, err: %v, task: %s, sub task: %s", err, taskID, subTaskID)
+			time.Sleep(time.Second * 3)
+			continue
+		}
+		return nil
+	}
+	return err
+}
+
 func (tq *TaskQueue) lockTask(ctx context.Context, taskID string, ttl int64) (bool, error) {
 
 	key := fmt.Sprintf("%s:apiTask:%s", common.BKCacheKeyV3Prefix, taskID)
@@ -397,10 +445,11 @@ func (s *Service) initCodeTaskConfig() map[string]TaskInfo {
 
 	for _, codeTaskConfig := range codeTaskConfigArr {
 		ti := TaskInfo{
-			Name:    codeTaskConfig.Name,
-			Retry:   codeTaskConfig.Retry,
-			Path:    codeTaskConfig.Path,
-			LockTTL: codeTaskConfig.LockTTL,
+			Name:       codeTaskConfig.Name,
+			Retry:      codeTaskConfig.Retry,
+			Path:       codeTaskConfig.Path,
+			LockTTL:    codeTaskConfig.LockTTL,
+			OutputFile: codeTaskConfig.OutputFile,
 		}
 		switch codeTaskConfig.SvrType {
 		case types.CC_MODULE_APISERVER:
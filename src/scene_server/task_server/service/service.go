@@ -58,6 +58,7 @@ func (s *Service) WebService() *restful.Container {
 		return s.CCErr
 	}
 	api.Path("/task/v3").Filter(s.Engine.Metric().RestfulMiddleWare).Filter(rdapi.AllGlobalFilter(getErrFunc)).Produces(restful.MIME_JSON)
+	api.Filter(s.Engine.ReadOnlyFilter())
 
 	s.addAPIService(api)
 	container.Add(api)
@@ -89,6 +90,9 @@ func (s *Service) addAPIService(web *restful.WebService) {
 		Handler: s.ListLatestSyncStatus})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/sync_status_history",
 		Handler: s.ListSyncStatusHistory})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/task/resume/id/{task_id}", Handler: s.ResumeTask})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/task/findmany/file/{task_id}",
+		Handler: s.ListTaskFile})
 
 	utility.AddToRestfulWebService(web)
 
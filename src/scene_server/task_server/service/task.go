@@ -104,6 +104,28 @@ func (s *Service) DetailTask(ctx *rest.Contexts) {
 	ctx.RespEntity(map[string]interface{}{"info": taskInfo})
 }
 
+// ResumeTask resume the failed task from its last checkpoint
+func (s *Service) ResumeTask(ctx *rest.Contexts) {
+	err := s.Logics.Resume(ctx.Kit, ctx.Request.PathParameter("task_id"))
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// ListTaskFile list the file chunks generated by the task
+func (s *Service) ListTaskFile(ctx *rest.Contexts) {
+	files, err := s.Logics.ListTaskFile(ctx.Kit, ctx.Request.PathParameter("task_id"))
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(files)
+}
+
 // DeleteTask delete task by condition
 func (s *Service) DeleteTask(ctx *rest.Contexts) {
 
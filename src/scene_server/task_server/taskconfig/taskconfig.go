@@ -31,6 +31,9 @@ type CodeTaskConfig struct {
 	Retry int64
 	// LockTTL the expire time of task lock in minutes
 	LockTTL int64
+	// OutputFile whether the task's result is a file, the response data of each sub task is saved as a chunk of the
+	// file, which can be downloaded until it expires.
+	OutputFile bool
 }
 
 var (
@@ -46,6 +49,11 @@ func init() {
 		"/host/v3/updatemany/module/host_apply_plan/task", 1, 2)
 	AddCodeTaskConfig(common.SyncServiceTemplateHostApplyTaskFlag, types.CC_MODULE_PROC,
 		"/process/v3/updatemany/service_template/host_apply_plan/task", 1, 2)
+	AddCodeTaskConfig(common.ModelDeleteCascadeTaskFlag, types.CC_MODULE_TOPO,
+		"/topo/v3/internal/delete/object/cascade/task", 1, 10)
+	AddCodeTaskConfig(common.InstImportTaskFlag, types.CC_MODULE_TOPO, "/topo/v3/internal/import/instance/task", 1, 10)
+	AddCodeFileTaskConfig(common.InstExportTaskFlag, types.CC_MODULE_TOPO, "/topo/v3/internal/export/instance/task",
+		1, 10)
 }
 
 // AddCodeTaskConfig add task
@@ -60,6 +68,19 @@ func AddCodeTaskConfig(name, srvType, path string, retry, lockTTL int64) {
 	})
 }
 
+// AddCodeFileTaskConfig add task whose result is a file
+func AddCodeFileTaskConfig(name, srvType, path string, retry, lockTTL int64) {
+	blog.Infof("add file task. name: %s, service type: %s, path: %s", name, srvType, path)
+	codeTaskConfigArr = append(codeTaskConfigArr, CodeTaskConfig{
+		Name:       name,
+		SvrType:    srvType,
+		Path:       path,
+		Retry:      retry,
+		LockTTL:    lockTTL,
+		OutputFile: true,
+	})
+}
+
 // GetCodeTaskConfig return code  task config
 func GetCodeTaskConfig() []CodeTaskConfig {
 	return codeTaskConfigArr
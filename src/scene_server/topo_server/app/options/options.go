@@ -17,6 +17,7 @@ import (
 	"configcenter/src/ac/iam"
 	"configcenter/src/common/auth"
 	"configcenter/src/common/core/cc/config"
+	"configcenter/src/common/metadata"
 	"configcenter/src/storage/dal/redis"
 	"configcenter/src/thirdparty/elasticsearch"
 
@@ -36,6 +37,8 @@ type Config struct {
 	Redis     redis.Config
 	ConfigMap map[string]string
 	Es        elasticsearch.EsConfig
+	// ResourceActions the custom actions on the model instances registered by config
+	ResourceActions []metadata.ResourceAction
 }
 
 // NewServerOption TODO
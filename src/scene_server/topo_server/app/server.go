@@ -16,6 +16,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"time"
 
 	"configcenter/src/ac/extensions"
@@ -24,20 +25,30 @@ import (
 	"configcenter/src/common/backbone"
 	cc "configcenter/src/common/backbone/configcenter"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
 	"configcenter/src/common/types"
 	"configcenter/src/scene_server/topo_server/app/options"
 	"configcenter/src/scene_server/topo_server/logics"
 	"configcenter/src/scene_server/topo_server/service"
 	"configcenter/src/storage/driver/redis"
 	"configcenter/src/thirdparty/elasticsearch"
+
+	"gopkg.in/yaml.v2"
 )
 
+// resourceActionFileKey the config key of the file that registers the custom resource actions
+const resourceActionFileKey = "topoServer.resourceAction.file"
+
 // TopoServer the topo server
 type TopoServer struct {
 	Core        *backbone.Engine
 	Config      options.Config
 	Service     *service.Service
 	configReady bool
+	// resourceActionsLoaded is whether the resource actions are loaded successfully once
+	resourceActionsLoaded bool
+	// resourceActionErr is the error of parsing the resource actions before they are loaded successfully once
+	resourceActionErr error
 }
 
 func (t *TopoServer) onTopoConfigUpdate(previous, current cc.ProcessConfig) {
@@ -53,6 +64,55 @@ func (t *TopoServer) onTopoConfigUpdate(previous, current cc.ProcessConfig) {
 	if err != nil {
 		blog.Warnf("parse auth center config failed: %v", err)
 	}
+
+	// keep the previous resource actions if the new ones are invalid, the server fails to start if the first load fails
+	actions, err := parseResourceActions()
+	if err != nil {
+		blog.Errorf("parse resource actions config failed, keep the previous actions, err: %v", err)
+		if !t.resourceActionsLoaded {
+			t.resourceActionErr = err
+		}
+		return
+	}
+	t.Config.ResourceActions = actions
+	t.resourceActionsLoaded = true
+	t.resourceActionErr = nil
+}
+
+// parseResourceActions parse the custom resource actions from the file configured by topoServer.resourceAction.file
+func parseResourceActions() ([]metadata.ResourceAction, error) {
+	if !cc.IsExist(resourceActionFileKey) {
+		return make([]metadata.ResourceAction, 0), nil
+	}
+
+	file, err := cc.String(resourceActionFileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read resource action file %s failed, err: %v", file, err)
+	}
+
+	conf := new(metadata.ResourceActionConfig)
+	if err := yaml.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("unmarshal resource action file %s failed, err: %v", file, err)
+	}
+
+	actionIDs := make(map[string]struct{})
+	for index := range conf.Actions {
+		if err := conf.Actions[index].Validate(); err != nil {
+			return nil, err
+		}
+
+		if _, exists := actionIDs[conf.Actions[index].ID]; exists {
+			return nil, fmt.Errorf("resource action %s is duplicated", conf.Actions[index].ID)
+		}
+		actionIDs[conf.Actions[index].ID] = struct{}{}
+	}
+
+	return conf.Actions, nil
 }
 
 // Run main function
@@ -83,6 +143,10 @@ func Run(ctx context.Context, cancel context.CancelFunc, op *options.ServerOptio
 		return err
 	}
 
+	if server.resourceActionErr != nil {
+		return fmt.Errorf("parse resource actions config failed, err: %v", server.resourceActionErr)
+	}
+
 	server.Config.Redis, err = engine.WithRedis()
 	if err != nil {
 		return err
@@ -102,6 +166,7 @@ func Run(ctx context.Context, cancel context.CancelFunc, op *options.ServerOptio
 			return fmt.Errorf("new es client failed, err: %v", err)
 		}
 		essrv.Client = esClient
+		essrv.Reindexer = elasticsearch.NewReindexer(esClient)
 	}
 
 	iamCli := new(iam.IAM)
@@ -20,6 +20,12 @@ type AssociationOperationInterface interface {
 	// CountInstanceAssociations counts object instance associations num.
 	CountInstanceAssociations(kit *rest.Kit, objID string, input *metadata.CommonCountFilter) (
 		*metadata.CommonCountResult, error)
+	// SearchInstAsstEdges search the instance associations of a model association by page with the endpoint filters
+	SearchInstAsstEdges(kit *rest.Kit, objID string, opt *metadata.InstAsstEdgeSearchOption) (
+		[]metadata.InstAsst, error)
+	// CountInstAsstEdges count the instance associations of a model association with the endpoint filters
+	CountInstAsstEdges(kit *rest.Kit, objID string, filter *metadata.InstAsstEdgeFilter) (
+		*metadata.CommonCountResult, error)
 	// SearchInstAssociationUIList instance association data related to instances, return by pagination
 	SearchInstAssociationUIList(kit *rest.Kit, objID string, query *metadata.QueryCondition) (
 		*metadata.SearchInstAssociationListResult, uint64, error)
@@ -34,6 +40,9 @@ type AssociationOperationInterface interface {
 		*metadata.CreateManyInstAsstResultDetail, error)
 	// DeleteInstAssociation delete association between instances
 	DeleteInstAssociation(kit *rest.Kit, objID string, asstIDList []int64) (uint64, error)
+	// SearchInstGraph search the neighborhood graph of the instance, used by the topology graph of the ui
+	SearchInstGraph(kit *rest.Kit, objID string, instID int64, opt *metadata.InstGraphOption) (*metadata.InstGraph,
+		error)
 	// CheckAssociations returns error if the instances has associations with exist instances, clear dirty associations
 	CheckAssociations(*rest.Kit, string, []int64) error
 
@@ -235,7 +244,7 @@ func (assoc *association) checkInstAsstMapping(kit *rest.Kit, objID string, mapp
 		}
 
 		if instCnt[0] >= 1 {
-			return kit.CCError.Error(common.CCErrorTopoCreateMultipleInstancesForOneToOneAssociation)
+			return kit.CCError.Error(common.CCErrorTopoCreateMultipleInstancesForOneToManyAssociation)
 		}
 
 	default:
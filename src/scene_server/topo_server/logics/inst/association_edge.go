@@ -0,0 +1,188 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inst
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/querybuilder"
+	"configcenter/src/common/util"
+)
+
+// SearchInstAsstEdges search the instance associations of a model association by page, the edges can be filtered
+// by the attributes of their source and target instances.
+func (assoc *association) SearchInstAsstEdges(kit *rest.Kit, objID string, opt *metadata.InstAsstEdgeSearchOption) (
+	[]metadata.InstAsst, error) {
+
+	cond, matched, err := assoc.getInstAsstEdgeCond(kit, objID, &opt.InstAsstEdgeFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	if !matched {
+		return make([]metadata.InstAsst, 0), nil
+	}
+
+	query := &metadata.InstAsstQueryCondition{
+		ObjID: objID,
+		Cond: metadata.QueryCondition{
+			Fields:         opt.Fields,
+			Condition:      cond,
+			Page:           opt.Page,
+			DisableCounter: true,
+		},
+	}
+
+	rsp, err := assoc.clientSet.CoreService().Association().ReadInstAssociation(kit.Ctx, kit.Header, query)
+	if err != nil {
+		blog.Errorf("search instance association edges failed, cond: %#v, err: %v, rid: %s", cond, err, kit.Rid)
+		return nil, err
+	}
+
+	return rsp.Info, nil
+}
+
+// CountInstAsstEdges count the instance associations of a model association, the edges can be filtered by the
+// attributes of their source and target instances.
+func (assoc *association) CountInstAsstEdges(kit *rest.Kit, objID string, filter *metadata.InstAsstEdgeFilter) (
+	*metadata.CommonCountResult, error) {
+
+	cond, matched, err := assoc.getInstAsstEdgeCond(kit, objID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if !matched {
+		return &metadata.CommonCountResult{Count: 0}, nil
+	}
+
+	rsp, err := assoc.clientSet.CoreService().Association().CountInstanceAssociations(kit.Ctx, kit.Header, objID,
+		&metadata.Condition{Condition: cond})
+	if err != nil {
+		blog.Errorf("count instance association edges failed, cond: %#v, err: %v, rid: %s", cond, err, kit.Rid)
+		return nil, err
+	}
+
+	return &metadata.CommonCountResult{Count: rsp.Count}, nil
+}
+
+// getInstAsstEdgeCond get the db condition of the instance association edges, the endpoint filters are converted to
+// the ids of the matched instances. returns false if no instance is matched by the endpoint filters.
+func (assoc *association) getInstAsstEdgeCond(kit *rest.Kit, objID string, filter *metadata.InstAsstEdgeFilter) (
+	mapstr.MapStr, bool, error) {
+
+	asstCond := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{common.AssociationObjAsstIDField: filter.ObjAsstID},
+	}
+	asstRsp, err := assoc.clientSet.CoreService().Association().ReadModelAssociation(kit.Ctx, kit.Header, asstCond)
+	if err != nil {
+		blog.Errorf("get object association %s failed, err: %v, rid: %s", filter.ObjAsstID, err, kit.Rid)
+		return nil, false, err
+	}
+
+	if len(asstRsp.Info) == 0 {
+		blog.Errorf("object association %s is not exist, rid: %s", filter.ObjAsstID, kit.Rid)
+		return nil, false, kit.CCError.CCError(common.CCErrorTopoObjectAssociationNotExist)
+	}
+	asst := asstRsp.Info[0]
+
+	if objID != asst.ObjectID && objID != asst.AsstObjID {
+		blog.Errorf("object %s is not in the association %s, rid: %s", objID, filter.ObjAsstID, kit.Rid)
+		return nil, false, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKObjIDField)
+	}
+
+	cond := mapstr.MapStr{common.AssociationObjAsstIDField: filter.ObjAsstID}
+	if filter.AsstFilter != nil {
+		asstFilter, key, err := filter.AsstFilter.ToMgo()
+		if err != nil {
+			blog.Errorf("parse asst_filter failed, key: %s, err: %v, rid: %s", key, err, kit.Rid)
+			return nil, false, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "asst_filter."+key)
+		}
+		cond = mapstr.MapStr{common.BKDBAND: []mapstr.MapStr{cond, asstFilter}}
+	}
+
+	endpoints := []struct {
+		objID   string
+		idField string
+		filter  *querybuilder.QueryFilter
+		name    string
+	}{
+		{objID: asst.ObjectID, idField: common.BKInstIDField, filter: filter.SrcFilter, name: "src_filter"},
+		{objID: asst.AsstObjID, idField: common.BKAsstInstIDField, filter: filter.DstFilter, name: "dst_filter"},
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.filter == nil {
+			continue
+		}
+
+		ids, err := assoc.findInstAsstEdgeEndpointIDs(kit, endpoint.objID, endpoint.filter, endpoint.name)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if len(ids) == 0 {
+			return nil, false, nil
+		}
+		cond[endpoint.idField] = mapstr.MapStr{common.BKDBIN: ids}
+	}
+
+	return cond, true, nil
+}
+
+// findInstAsstEdgeEndpointIDs find the ids of the instances matched by the endpoint filter, the filter must be
+// narrowed if it matches too many instances to be used as the condition of the edges.
+func (assoc *association) findInstAsstEdgeEndpointIDs(kit *rest.Kit, objID string, filter *querybuilder.QueryFilter,
+	name string) ([]int64, error) {
+
+	instFilter, key, err := filter.ToMgo()
+	if err != nil {
+		blog.Errorf("parse %s failed, key: %s, err: %v, rid: %s", name, key, err, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, name+"."+key)
+	}
+
+	idField := common.GetInstIDField(objID)
+	query := &metadata.QueryCondition{
+		Condition:      instFilter,
+		Fields:         []string{idField},
+		Page:           metadata.BasePage{Limit: metadata.InstAsstEdgeMaxEndpointInsts + 1},
+		DisableCounter: true,
+	}
+
+	rsp, err := assoc.clientSet.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header, objID, query)
+	if err != nil {
+		blog.Errorf("find %s instances by %s failed, err: %v, rid: %s", objID, name, err, kit.Rid)
+		return nil, err
+	}
+
+	if len(rsp.Info) > metadata.InstAsstEdgeMaxEndpointInsts {
+		blog.Errorf("%s matches more than %d %s instances, rid: %s", name, metadata.InstAsstEdgeMaxEndpointInsts,
+			objID, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommXXExceedLimit, name, metadata.InstAsstEdgeMaxEndpointInsts)
+	}
+
+	ids := make([]int64, 0, len(rsp.Info))
+	for _, inst := range rsp.Info {
+		id, err := util.GetInt64ByInterface(inst[idField])
+		if err != nil {
+			blog.Errorf("parse %s instance id failed, inst: %v, err: %v, rid: %s", objID, inst, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, idField)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,506 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inst
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"configcenter/src/apimachinery"
+	"configcenter/src/common"
+	"configcenter/src/common/auditlog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// ChangeBundleOperationInterface change bundle operation methods
+type ChangeBundleOperationInterface interface {
+	// ExecuteChangeBundle execute the bundle of changes in the business as a whole, one audit log is saved for
+	// the bundle whether it succeeds or not.
+	ExecuteChangeBundle(kit *rest.Kit, bizID int64, opt *metadata.ChangeBundleOption) (*metadata.ChangeBundleResult,
+		error)
+	SetProxy(set SetOperationInterface, module ModuleOperationInterface)
+}
+
+// NewChangeBundleOperation create a new change bundle operation instance
+func NewChangeBundleOperation(client apimachinery.ClientSetInterface) ChangeBundleOperationInterface {
+	return &changeBundle{
+		clientSet: client,
+	}
+}
+
+type changeBundle struct {
+	clientSet apimachinery.ClientSetInterface
+	set       SetOperationInterface
+	module    ModuleOperationInterface
+}
+
+// SetProxy 初始化依赖
+func (b *changeBundle) SetProxy(set SetOperationInterface, module ModuleOperationInterface) {
+	b.set = set
+	b.module = module
+}
+
+// changeBundleExecutor executes the changes of a bundle in order, and records the compensation of the executed
+// changes, a new executor is used for each run of the bundle.
+type changeBundleExecutor struct {
+	*changeBundle
+	bizID         int64
+	opt           *metadata.ChangeBundleOption
+	ids           map[string]int64
+	results       []metadata.ChangeBundleChangeResult
+	compensations []changeBundleCompensation
+}
+
+// changeBundleCompensation is the compensation plan of an executed change and the function that executes it.
+type changeBundleCompensation struct {
+	plan metadata.ChangeBundleCompensation
+	run  func(kit *rest.Kit) error
+}
+
+// ExecuteChangeBundle execute the bundle of changes in the business as a whole. in transaction mode, the changes are
+// executed in one transaction. in compensation mode, the executed changes are compensated in the reverse order if
+// any change fails, the compensations that failed are returned in the error so that they can be handled manually.
+func (b *changeBundle) ExecuteChangeBundle(kit *rest.Kit, bizID int64, opt *metadata.ChangeBundleOption) (
+	*metadata.ChangeBundleResult, error) {
+
+	// all the changes of the bundle are grouped into one change set, whether they are done in the transaction or not
+	metadata.KeepChangeSet(kit.Header)
+
+	var executor *changeBundleExecutor
+	var execErr error
+
+	switch opt.Mode {
+	case metadata.ChangeBundleCompensationMode:
+		executor = b.newExecutor(bizID, opt)
+		if execErr = executor.execute(kit); execErr != nil {
+			if err := executor.compensate(kit); err != nil {
+				execErr = err
+			}
+		}
+	default:
+		// use a copied header for the transaction, so that the audit log can be saved after the transaction ends
+		txnKit := *kit
+		txnKit.Header = util.CloneHeader(kit.Header)
+		execErr = b.clientSet.CoreService().Txn().AutoRunTxn(txnKit.Ctx, txnKit.Header, func() error {
+			executor = b.newExecutor(bizID, opt)
+			return executor.execute(&txnKit)
+		})
+	}
+
+	result := &metadata.ChangeBundleResult{
+		Mode:          opt.Mode,
+		Changes:       make([]metadata.ChangeBundleChangeResult, 0),
+		Compensations: make([]metadata.ChangeBundleCompensation, 0),
+	}
+	if executor != nil {
+		result.Changes = executor.results
+		for idx := len(executor.compensations) - 1; idx >= 0; idx-- {
+			result.Compensations = append(result.Compensations, executor.compensations[idx].plan)
+		}
+	}
+
+	audit := auditlog.NewChangeBundleAuditLog(b.clientSet.CoreService())
+	param := auditlog.NewGenerateAuditCommonParameter(kit, metadata.AuditExecute)
+	auditLog := audit.GenerateAuditLog(param, bizID, opt, result, execErr)
+	if err := audit.SaveAuditLog(kit, auditLog); err != nil {
+		blog.Errorf("save change bundle audit log failed, err: %v, rid: %s", err, kit.Rid)
+		if execErr == nil {
+			return nil, err
+		}
+	}
+
+	if execErr != nil {
+		return nil, execErr
+	}
+
+	return result, nil
+}
+
+func (b *changeBundle) newExecutor(bizID int64, opt *metadata.ChangeBundleOption) *changeBundleExecutor {
+	return &changeBundleExecutor{
+		changeBundle:  b,
+		bizID:         bizID,
+		opt:           opt,
+		ids:           make(map[string]int64),
+		results:       make([]metadata.ChangeBundleChangeResult, 0, len(opt.Changes)),
+		compensations: make([]changeBundleCompensation, 0, len(opt.Changes)),
+	}
+}
+
+// execute executes the changes in order, stops at the first failed change.
+func (e *changeBundleExecutor) execute(kit *rest.Kit) error {
+	for index, change := range e.opt.Changes {
+		data, err := change.ResolveRefs(e.ids)
+		if err != nil {
+			blog.Errorf("resolve change %d refs failed, err: %v, rid: %s", index, err, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrTopoChangeBundleExecuteFailed, index, err.Error())
+		}
+
+		var id int64
+		switch change.Type {
+		case metadata.ChangeBundleCreateSet:
+			id, err = e.createSet(kit, index, data)
+		case metadata.ChangeBundleCreateModule:
+			id, err = e.createModule(kit, index, data)
+		case metadata.ChangeBundleTransferHost:
+			err = e.transferHost(kit, index, data)
+		case metadata.ChangeBundleCreateServiceInstance:
+			id, err = e.createServiceInstance(kit, index, data)
+		default:
+			err = kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "type")
+		}
+
+		if err != nil {
+			blog.Errorf("execute change %d of type %s failed, data: %#v, err: %v, rid: %s", index, change.Type, data,
+				err, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrTopoChangeBundleExecuteFailed, index, err.Error())
+		}
+
+		if len(change.Ref) != 0 {
+			e.ids[change.Ref] = id
+		}
+		e.results = append(e.results, metadata.ChangeBundleChangeResult{
+			Index: index,
+			Type:  change.Type,
+			Ref:   change.Ref,
+			ID:    id,
+		})
+	}
+
+	return nil
+}
+
+// compensate executes the compensations of the executed changes in the reverse order, all compensations are tried
+// even if some of them failed, returns the error with the failed changes' indexes if any.
+func (e *changeBundleExecutor) compensate(kit *rest.Kit) error {
+	failed := make([]string, 0)
+	for idx := len(e.compensations) - 1; idx >= 0; idx-- {
+		compensation := &e.compensations[idx]
+		if err := compensation.run(kit); err != nil {
+			blog.Errorf("compensate change %d failed, plan: %#v, err: %v, rid: %s", compensation.plan.ChangeIndex,
+				compensation.plan, err, kit.Rid)
+			compensation.plan.Error = err.Error()
+			failed = append(failed, fmt.Sprintf("%d", compensation.plan.ChangeIndex))
+			continue
+		}
+		compensation.plan.Executed = true
+	}
+
+	if len(failed) > 0 {
+		return kit.CCError.CCErrorf(common.CCErrTopoChangeBundleCompensateFailed, strings.Join(failed, ","))
+	}
+	return nil
+}
+
+func (e *changeBundleExecutor) addCompensation(index int, action string, data mapstr.MapStr,
+	run func(kit *rest.Kit) error) {
+
+	e.compensations = append(e.compensations, changeBundleCompensation{
+		plan: metadata.ChangeBundleCompensation{
+			ChangeIndex: index,
+			Action:      action,
+			Data:        data,
+		},
+		run: run,
+	})
+}
+
+func (e *changeBundleExecutor) createSet(kit *rest.Kit, index int, data mapstr.MapStr) (int64, error) {
+	if !data.Exists(common.BKParentIDField) {
+		data.Set(common.BKParentIDField, e.bizID)
+	}
+
+	set, err := e.set.CreateSet(kit, e.bizID, data)
+	if err != nil {
+		return 0, err
+	}
+
+	setID, err := set.Int64(common.BKSetIDField)
+	if err != nil {
+		blog.Errorf("get created set id failed, set: %#v, err: %v, rid: %s", set, err, kit.Rid)
+		return 0, err
+	}
+
+	e.addCompensation(index, metadata.ChangeBundleDeleteSet, mapstr.MapStr{common.BKSetIDField: setID},
+		func(kit *rest.Kit) error {
+			return e.set.DeleteSet(kit, e.bizID, []int64{setID})
+		})
+	return setID, nil
+}
+
+func (e *changeBundleExecutor) createModule(kit *rest.Kit, index int, data mapstr.MapStr) (int64, error) {
+	setID, err := data.Int64(common.BKSetIDField)
+	if err != nil || setID <= 0 {
+		return 0, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKSetIDField)
+	}
+
+	// the modules of the set initialized by template can only be changed by syncing the set template
+	cond := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{common.BKSetIDField: setID, common.BKAppIDField: e.bizID},
+		Fields:    []string{common.BKSetTemplateIDField},
+		Page:      metadata.BasePage{Limit: 1},
+	}
+	sets, err := e.clientSet.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header, common.BKInnerObjIDSet, cond)
+	if err != nil {
+		blog.Errorf("get set %d failed, err: %v, rid: %s", setID, err, kit.Rid)
+		return 0, err
+	}
+	if len(sets.Info) == 0 {
+		return 0, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKSetIDField)
+	}
+	setTemplateID, _ := sets.Info[0].Int64(common.BKSetTemplateIDField)
+	if setTemplateID > 0 {
+		return 0, kit.CCError.CCError(common.CCErrorTopoForbiddenOperateModuleOnSetInitializedByTemplate)
+	}
+
+	if !data.Exists(common.BKParentIDField) {
+		data.Set(common.BKParentIDField, setID)
+	}
+
+	module, err := e.module.CreateModule(kit, e.bizID, setID, data)
+	if err != nil {
+		return 0, err
+	}
+
+	moduleID, err := module.Int64(common.BKModuleIDField)
+	if err != nil {
+		blog.Errorf("get created module id failed, module: %#v, err: %v, rid: %s", module, err, kit.Rid)
+		return 0, err
+	}
+
+	e.addCompensation(index, metadata.ChangeBundleDeleteModule,
+		mapstr.MapStr{common.BKSetIDField: setID, common.BKModuleIDField: moduleID},
+		func(kit *rest.Kit) error {
+			return e.module.DeleteModule(kit, e.bizID, []int64{setID}, []int64{moduleID})
+		})
+	return moduleID, nil
+}
+
+func (e *changeBundleExecutor) transferHost(kit *rest.Kit, index int, data mapstr.MapStr) error {
+	input := new(metadata.HostsModuleRelation)
+	if err := data.MarshalJSONInto(input); err != nil {
+		blog.Errorf("parse transfer host data failed, data: %#v, err: %v, rid: %s", data, err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+	input.ApplicationID = e.bizID
+
+	if len(input.HostID) == 0 {
+		return kit.CCError.CCErrorf(common.CCErrCommParamsNeedSet, common.BKHostIDField)
+	}
+	if len(input.ModuleID) == 0 {
+		return kit.CCError.CCErrorf(common.CCErrCommParamsNeedSet, common.BKModuleIDField)
+	}
+
+	originals, err := e.getHostOriginalRelations(kit, input.HostID)
+	if err != nil {
+		return err
+	}
+
+	exceptions, err := e.clientSet.CoreService().Host().TransferToNormalModule(kit.Ctx, kit.Header, input)
+	if err != nil {
+		blog.Errorf("transfer hosts failed, input: %#v, exceptions: %#v, err: %v, rid: %s", input, exceptions, err,
+			kit.Rid)
+		return err
+	}
+
+	plans := make([]mapstr.MapStr, len(originals))
+	for idx, original := range originals {
+		plans[idx] = mapstr.MapStr{common.BKHostIDField: original.HostID, common.BKModuleIDField: original.ModuleID}
+	}
+	e.addCompensation(index, metadata.ChangeBundleRestoreHostRelation, mapstr.MapStr{"relations": plans},
+		func(kit *rest.Kit) error {
+			return e.restoreHostRelations(kit, originals)
+		})
+	return nil
+}
+
+// hostOriginalRelation is the hosts that were in the same modules before they are transferred.
+type hostOriginalRelation struct {
+	HostID   []int64
+	ModuleID []int64
+	// inner the original module is the business's inner module, like the idle module
+	inner bool
+}
+
+// getHostOriginalRelations get the modules that the hosts belong to, the hosts are grouped by their modules
+func (e *changeBundleExecutor) getHostOriginalRelations(kit *rest.Kit, hostIDs []int64) ([]hostOriginalRelation,
+	error) {
+
+	relOpt := &metadata.HostModuleRelationRequest{
+		ApplicationID: e.bizID,
+		HostIDArr:     hostIDs,
+		Fields:        []string{common.BKHostIDField, common.BKModuleIDField},
+		Page:          metadata.BasePage{Limit: common.BKNoLimit},
+	}
+	relations, err := e.clientSet.CoreService().Host().GetHostModuleRelation(kit.Ctx, kit.Header, relOpt)
+	if err != nil {
+		blog.Errorf("get host module relations failed, opt: %#v, err: %v, rid: %s", relOpt, err, kit.Rid)
+		return nil, err
+	}
+
+	hostModules := make(map[int64][]int64)
+	moduleIDs := make([]int64, 0)
+	for _, relation := range relations.Info {
+		hostModules[relation.HostID] = append(hostModules[relation.HostID], relation.ModuleID)
+		moduleIDs = append(moduleIDs, relation.ModuleID)
+	}
+
+	for _, hostID := range hostIDs {
+		if _, exists := hostModules[hostID]; !exists {
+			blog.Errorf("host %d is not in biz %d, rid: %s", hostID, e.bizID, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKHostIDField)
+		}
+	}
+
+	cond := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{
+			common.BKModuleIDField: mapstr.MapStr{common.BKDBIN: util.IntArrayUnique(moduleIDs)},
+			common.BKDefaultField:  mapstr.MapStr{common.BKDBNE: common.DefaultFlagDefaultValue},
+		},
+		Fields: []string{common.BKModuleIDField},
+		Page:   metadata.BasePage{Limit: common.BKNoLimit},
+	}
+	innerModules, err := e.clientSet.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header,
+		common.BKInnerObjIDModule, cond)
+	if err != nil {
+		blog.Errorf("get inner modules failed, cond: %#v, err: %v, rid: %s", cond, err, kit.Rid)
+		return nil, err
+	}
+	innerModuleMap := make(map[int64]struct{})
+	for _, module := range innerModules.Info {
+		moduleID, err := module.Int64(common.BKModuleIDField)
+		if err != nil {
+			return nil, err
+		}
+		innerModuleMap[moduleID] = struct{}{}
+	}
+
+	groupMap := make(map[string]*hostOriginalRelation)
+	groupKeys := make([]string, 0)
+	for hostID, modules := range hostModules {
+		sort.Slice(modules, func(i, j int) bool { return modules[i] < modules[j] })
+		key := fmt.Sprint(modules)
+		group, exists := groupMap[key]
+		if !exists {
+			group = &hostOriginalRelation{ModuleID: modules}
+			if _, isInner := innerModuleMap[modules[0]]; isInner && len(modules) == 1 {
+				group.inner = true
+			}
+			groupMap[key] = group
+			groupKeys = append(groupKeys, key)
+		}
+		group.HostID = append(group.HostID, hostID)
+	}
+
+	originals := make([]hostOriginalRelation, len(groupKeys))
+	for idx, key := range groupKeys {
+		originals[idx] = *groupMap[key]
+	}
+	return originals, nil
+}
+
+// restoreHostRelations transfer the hosts back to their original modules
+func (e *changeBundleExecutor) restoreHostRelations(kit *rest.Kit, originals []hostOriginalRelation) error {
+	for _, original := range originals {
+		if original.inner {
+			input := &metadata.TransferHostToInnerModule{
+				ApplicationID: e.bizID,
+				ModuleID:      original.ModuleID[0],
+				HostID:        original.HostID,
+			}
+			if _, err := e.clientSet.CoreService().Host().TransferToInnerModule(kit.Ctx, kit.Header,
+				input); err != nil {
+				blog.Errorf("transfer hosts back to inner module failed, input: %#v, err: %v, rid: %s", input, err,
+					kit.Rid)
+				return err
+			}
+			continue
+		}
+
+		input := &metadata.HostsModuleRelation{
+			ApplicationID:                e.bizID,
+			HostID:                       original.HostID,
+			ModuleID:                     original.ModuleID,
+			DisableAutoCreateSvcInst:     true,
+			DisableTransferHostAutoApply: true,
+		}
+		if _, err := e.clientSet.CoreService().Host().TransferToNormalModule(kit.Ctx, kit.Header, input); err != nil {
+			blog.Errorf("transfer hosts back to modules failed, input: %#v, err: %v, rid: %s", input, err, kit.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *changeBundleExecutor) createServiceInstance(kit *rest.Kit, index int, data mapstr.MapStr) (int64, error) {
+	input := new(metadata.ChangeBundleServiceInstance)
+	if err := data.MarshalJSONInto(input); err != nil {
+		blog.Errorf("parse service instance data failed, data: %#v, err: %v, rid: %s", data, err, kit.Rid)
+		return 0, kit.CCError.CCError(common.CCErrCommJSONUnmarshalFailed)
+	}
+
+	if input.ModuleID <= 0 {
+		return 0, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKModuleIDField)
+	}
+	if input.HostID <= 0 {
+		return 0, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKHostIDField)
+	}
+
+	cond := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{common.BKModuleIDField: input.ModuleID, common.BKAppIDField: e.bizID},
+		Fields:    []string{common.BKServiceTemplateIDField},
+		Page:      metadata.BasePage{Limit: 1},
+	}
+	modules, err := e.clientSet.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header,
+		common.BKInnerObjIDModule, cond)
+	if err != nil {
+		blog.Errorf("get module %d failed, err: %v, rid: %s", input.ModuleID, err, kit.Rid)
+		return 0, err
+	}
+	if len(modules.Info) == 0 {
+		return 0, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKModuleIDField)
+	}
+	serviceTemplateID, _ := modules.Info[0].Int64(common.BKServiceTemplateIDField)
+
+	instance := &metadata.ServiceInstance{
+		BizID:             e.bizID,
+		Name:              input.Name,
+		ServiceTemplateID: serviceTemplateID,
+		HostID:            input.HostID,
+		ModuleID:          input.ModuleID,
+	}
+	created, err := e.clientSet.CoreService().Process().CreateServiceInstance(kit.Ctx, kit.Header, instance)
+	if err != nil {
+		blog.Errorf("create service instance failed, instance: %#v, err: %v, rid: %s", instance, err, kit.Rid)
+		return 0, err
+	}
+
+	e.addCompensation(index, metadata.ChangeBundleDeleteServiceInstance, mapstr.MapStr{common.BKFieldID: created.ID},
+		func(kit *rest.Kit) error {
+			opt := &metadata.CoreDeleteServiceInstanceOption{
+				BizID:              e.bizID,
+				ServiceInstanceIDs: []int64{created.ID},
+			}
+			if err := e.clientSet.CoreService().Process().DeleteServiceInstance(kit.Ctx, kit.Header,
+				opt); err != nil {
+				return err
+			}
+			return nil
+		})
+	return created.ID, nil
+}
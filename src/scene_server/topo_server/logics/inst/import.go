@@ -0,0 +1,337 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inst
+
+import (
+	"fmt"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// importAsstColumn is an association column of the instance import with its model association.
+type importAsstColumn struct {
+	metadata.ImportAssociationColumn
+	// isSrc whether the imported object is the source object of the association
+	isSrc     bool
+	asstObjID string
+}
+
+// importAsstTarget is the associated instances referred by an association column of an imported row.
+type importAsstTarget struct {
+	column  *importAsstColumn
+	instIDs []int64
+}
+
+// getImportAsstColumns get the model associations of the association columns, the imported object must be one
+// side of the association.
+func (c *commonInst) getImportAsstColumns(kit *rest.Kit, objID string,
+	columns []metadata.ImportAssociationColumn) ([]*importAsstColumn, error) {
+
+	if len(columns) == 0 {
+		return make([]*importAsstColumn, 0), nil
+	}
+
+	objAsstIDs := make([]string, len(columns))
+	for index, column := range columns {
+		objAsstIDs[index] = column.ObjAsstID
+	}
+
+	cond := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{common.AssociationObjAsstIDField: mapstr.MapStr{common.BKDBIN: objAsstIDs}},
+	}
+	rsp, err := c.clientSet.CoreService().Association().ReadModelAssociation(kit.Ctx, kit.Header, cond)
+	if err != nil {
+		blog.Errorf("search model associations %v failed, err: %v, rid: %s", objAsstIDs, err, kit.Rid)
+		return nil, err
+	}
+
+	assts := make(map[string]metadata.Association, len(rsp.Info))
+	for _, asst := range rsp.Info {
+		assts[asst.AssociationName] = asst
+	}
+
+	asstColumns := make([]*importAsstColumn, len(columns))
+	for index, column := range columns {
+		asst, exists := assts[column.ObjAsstID]
+		if !exists || (asst.ObjectID != objID && asst.AsstObjID != objID) {
+			blog.Errorf("association %s of column %s is not of object %s, rid: %s", column.ObjAsstID, column.Column,
+				objID, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.AssociationObjAsstIDField)
+		}
+
+		asstColumn := &importAsstColumn{ImportAssociationColumn: column, isSrc: asst.ObjectID == objID}
+		asstColumn.asstObjID = asst.AsstObjID
+		if !asstColumn.isSrc {
+			asstColumn.asstObjID = asst.ObjectID
+		}
+
+		if len(asstColumn.Key) == 0 {
+			asstColumn.Key = metadata.GetInstNameFieldName(asstColumn.asstObjID)
+		}
+		asstColumns[index] = asstColumn
+	}
+
+	return asstColumns, nil
+}
+
+// popImportAsstValues remove the association columns from the imported row, returns the key values of the
+// associated instances of each column. the empty columns are skipped, their associations are not changed.
+func popImportAsstValues(row mapstr.MapStr, columns []*importAsstColumn) map[*importAsstColumn][]string {
+	values := make(map[*importAsstColumn][]string)
+	for _, column := range columns {
+		value, exists := row[column.Column]
+		if !exists {
+			continue
+		}
+		delete(row, column.Column)
+
+		var cell string
+		switch val := value.(type) {
+		case nil:
+			continue
+		case string:
+			cell = val
+		default:
+			cell = util.GetStrByInterface(val)
+		}
+
+		keys := make([]string, 0)
+		for _, key := range strings.Split(cell, ",") {
+			if key = strings.TrimSpace(key); len(key) > 0 {
+				keys = append(keys, key)
+			}
+		}
+
+		if len(keys) > 0 {
+			values[column] = util.StrArrayUnique(keys)
+		}
+	}
+	return values
+}
+
+// getImportAsstTargets get the associated instances referred by the association columns of an imported row, all
+// the referred instances must exist.
+func (c *commonInst) getImportAsstTargets(kit *rest.Kit, values map[*importAsstColumn][]string) (
+	[]importAsstTarget, error) {
+
+	targets := make([]importAsstTarget, 0, len(values))
+	for column, keys := range values {
+		idField := common.GetInstIDField(column.asstObjID)
+		cond := mapstr.MapStr{column.Key: mapstr.MapStr{common.BKDBIN: keys}}
+		if metadata.IsCommon(column.asstObjID) {
+			cond[common.BKObjIDField] = column.asstObjID
+		}
+
+		query := &metadata.QueryCondition{
+			Condition: cond,
+			Fields:    []string{idField, column.Key},
+			Page:      metadata.BasePage{Limit: common.BKNoLimit},
+		}
+		insts, err := c.FindInst(kit, column.asstObjID, query)
+		if err != nil {
+			return nil, err
+		}
+
+		instIDs := make(map[string]int64, len(insts.Info))
+		for _, inst := range insts.Info {
+			instID, err := inst.Int64(idField)
+			if err != nil {
+				blog.Errorf("parse %s inst id failed, inst: %#v, err: %v, rid: %s", column.asstObjID, inst, err,
+					kit.Rid)
+				return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, idField)
+			}
+			instIDs[util.GetStrByInterface(inst[column.Key])] = instID
+		}
+
+		target := importAsstTarget{column: column, instIDs: make([]int64, 0, len(keys))}
+		missing := make([]string, 0)
+		for _, key := range keys {
+			instID, exists := instIDs[key]
+			if !exists {
+				missing = append(missing, key)
+				continue
+			}
+			target.instIDs = append(target.instIDs, instID)
+		}
+
+		if len(missing) > 0 {
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid,
+				fmt.Sprintf("%s: %s", column.Column, strings.Join(missing, ",")))
+		}
+
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// refreshImportInstAsst refresh the instance's associations of each association column to the referred instances,
+// the extra associations are deleted before the missing ones are created, so that the association mapping is kept.
+// returns the number of the created and deleted associations.
+func (c *commonInst) refreshImportInstAsst(kit *rest.Kit, objID string, instID int64,
+	targets []importAsstTarget) (int, int, error) {
+
+	created, deleted := 0, 0
+	for _, target := range targets {
+		instField, asstInstField := common.BKInstIDField, common.BKAsstInstIDField
+		if !target.column.isSrc {
+			instField, asstInstField = common.BKAsstInstIDField, common.BKInstIDField
+		}
+
+		query := &metadata.InstAsstQueryCondition{
+			Cond: metadata.QueryCondition{
+				Condition: mapstr.MapStr{
+					common.AssociationObjAsstIDField: target.column.ObjAsstID,
+					instField:                        instID,
+				},
+				Page: metadata.BasePage{Limit: common.BKNoLimit},
+			},
+			ObjID: objID,
+		}
+		rsp, err := c.clientSet.CoreService().Association().ReadInstAssociation(kit.Ctx, kit.Header, query)
+		if err != nil {
+			blog.Errorf("search inst %d associations %s failed, err: %v, rid: %s", instID, target.column.ObjAsstID,
+				err, kit.Rid)
+			return created, deleted, err
+		}
+
+		expected := make(map[int64]struct{}, len(target.instIDs))
+		for _, id := range target.instIDs {
+			expected[id] = struct{}{}
+		}
+
+		existing := make(map[int64]struct{}, len(rsp.Info))
+		extraIDs := make([]int64, 0)
+		for _, asst := range rsp.Info {
+			asstInstID := asst.AsstInstID
+			if asstInstField == common.BKInstIDField {
+				asstInstID = asst.InstID
+			}
+
+			if _, exists := expected[asstInstID]; !exists {
+				extraIDs = append(extraIDs, asst.ID)
+				continue
+			}
+			existing[asstInstID] = struct{}{}
+		}
+
+		if len(extraIDs) > 0 {
+			if _, err := c.asst.DeleteInstAssociation(kit, objID, extraIDs); err != nil {
+				blog.Errorf("delete inst associations %v failed, err: %v, rid: %s", extraIDs, err, kit.Rid)
+				return created, deleted, err
+			}
+			deleted += len(extraIDs)
+		}
+
+		for _, id := range target.instIDs {
+			if _, exists := existing[id]; exists {
+				continue
+			}
+
+			request := &metadata.CreateAssociationInstRequest{ObjectAsstID: target.column.ObjAsstID, InstID: instID,
+				AsstInstID: id}
+			if !target.column.isSrc {
+				request.InstID, request.AsstInstID = id, instID
+			}
+
+			if _, err := c.asst.CreateInstanceAssociation(kit, request); err != nil {
+				blog.Errorf("create inst association %#v failed, err: %v, rid: %s", request, err, kit.Rid)
+				return created, deleted, err
+			}
+			created++
+		}
+	}
+
+	return created, deleted, nil
+}
+
+// matchImportInst match the existing instance of the imported row by the upsert keys, returns 0 if not matched.
+func (c *commonInst) matchImportInst(kit *rest.Kit, objID string, row mapstr.MapStr, upsertKeys []string) (
+	int64, error) {
+
+	idField := common.GetInstIDField(objID)
+	cond := mapstr.MapStr{}
+	for _, key := range upsertKeys {
+		value, exists := row[key]
+		if !exists || value == nil || value == "" {
+			return 0, kit.CCError.CCErrorf(common.CCErrCommParamsNeedSet, key)
+		}
+		cond[key] = value
+	}
+
+	if metadata.IsCommon(objID) {
+		cond[common.BKObjIDField] = objID
+	}
+
+	query := &metadata.QueryCondition{
+		Condition: cond,
+		Fields:    []string{idField},
+		Page:      metadata.BasePage{Limit: 2},
+	}
+	insts, err := c.FindInst(kit, objID, query)
+	if err != nil {
+		return 0, err
+	}
+
+	switch len(insts.Info) {
+	case 0:
+		return 0, nil
+	case 1:
+		return insts.Info[0].Int64(idField)
+	default:
+		return 0, kit.CCError.CCErrorf(common.CCErrCommDuplicateItem, strings.Join(upsertKeys, ","))
+	}
+}
+
+// popImportExternalID remove the external id column from the imported row, returns the external id of the row in
+// the source of the import, returns nil if the source is not set or the column is empty.
+func popImportExternalID(row mapstr.MapStr, source string) *metadata.ExternalIDRef {
+	value, exists := row[metadata.BKExternalIDField]
+	if !exists {
+		return nil
+	}
+	delete(row, metadata.BKExternalIDField)
+
+	if len(source) == 0 || value == nil {
+		return nil
+	}
+
+	id := strings.TrimSpace(util.GetStrByInterface(value))
+	if len(id) == 0 {
+		return nil
+	}
+	return &metadata.ExternalIDRef{Source: source, ID: id}
+}
+
+// matchImportInstByExternalID returns the id of the instance bound to the external id, returns 0 if it is not bound.
+func (c *commonInst) matchImportInstByExternalID(kit *rest.Kit, objID string, ref metadata.ExternalIDRef) (int64,
+	error) {
+
+	opt := &metadata.SearchExternalIDOption{Source: ref.Source, ExternalIDs: []string{ref.ID}}
+	externalIDs, err := c.clientSet.CoreService().Instance().SearchExternalIDs(kit.Ctx, kit.Header, objID, opt)
+	if err != nil {
+		blog.Errorf("search %s external id %s failed, err: %v, rid: %s", objID, ref.String(), err, kit.Rid)
+		return 0, err
+	}
+
+	if len(externalIDs) == 0 {
+		return 0, nil
+	}
+	return externalIDs[0].InstID, nil
+}
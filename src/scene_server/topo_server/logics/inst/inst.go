@@ -35,6 +35,9 @@ import (
 type InstOperationInterface interface {
 	// CreateInst create instance by object and create message
 	CreateInst(kit *rest.Kit, objID string, data mapstr.MapStr) (mapstr.MapStr, error)
+	// CreateOrUpdateInst create the instance, or update the instance with the same natural key
+	CreateOrUpdateInst(kit *rest.Kit, objID string, opt *metadata.CreateOrUpdateModelInstance) (
+		*metadata.CreateOrUpdateInstResult, error)
 	// CreateManyInstance batch create instance by object and create message
 	CreateManyInstance(kit *rest.Kit, objID string, data []mapstr.MapStr) (*metadata.CreateManyCommInstResultDetail,
 		error)
@@ -61,6 +64,15 @@ type InstOperationInterface interface {
 	FindInstChildTopo(kit *rest.Kit, objID string, instID int64) (int, []*metadata.CommonInstTopo, error)
 	// FindInstTopo find instance all topo which include it's child and parent
 	FindInstTopo(kit *rest.Kit, obj metadata.Object, instID int64) (int, []metadata.CommonInstTopoV2, error)
+	// PreviewMergeInst compare the two instances to be merged, returns the attributes which have conflicting values
+	PreviewMergeInst(kit *rest.Kit, objID string, opt *metadata.MergeInstOption) (*metadata.MergeInstPreview, error)
+	// MergeInst merge the duplicated source instance into the target instance
+	MergeInst(kit *rest.Kit, objID string, opt *metadata.MergeInstOption) error
+	// ExecuteResourceAction execute the custom action on the instance by calling the external system
+	ExecuteResourceAction(kit *rest.Kit, action *metadata.ResourceAction, instID int64, params mapstr.MapStr) (
+		*metadata.ResourceActionResult, error)
+	// FindTopoLimitUsage find the businesses approaching the business topology limits
+	FindTopoLimitUsage(kit *rest.Kit, opt *metadata.TopoLimitUsageOption) (*metadata.TopoLimitUsageResult, error)
 	// SetProxy proxy the interface
 	SetProxy(instAssoc AssociationOperationInterface)
 }
@@ -83,6 +95,10 @@ type BatchResult struct {
 	SuccessCreated []int64  `json:"success_created"`
 	SuccessUpdated []int64  `json:"success_updated"`
 	UpdateErrors   []string `json:"update_error"`
+	// Rows the import result of each row
+	Rows []metadata.ImportRowResult `json:"rows"`
+	// DryRun whether the import is a dry run, nothing is changed if it is
+	DryRun bool `json:"dry_run"`
 }
 
 // ObjectWithInsts a struct include object msg and insts array
@@ -128,11 +144,18 @@ func (c *commonInst) SetProxy(instAssoc AssociationOperationInterface) {
 // CreateInst create instance by object and create message
 func (c *commonInst) CreateInst(kit *rest.Kit, objID string, data mapstr.MapStr) (mapstr.MapStr, error) {
 
-	if err := c.validObject(kit, objID, data); err != nil {
+	isMainline, err := c.validObject(kit, objID, data)
+	if err != nil {
 		blog.Errorf("check object (%s) if is mainline object failed, err: %v, rid: %s", objID, err, kit.Rid)
 		return nil, err
 	}
 
+	if isMainline && objID != common.BKInnerObjIDApp {
+		if err := c.checkTopoChildLimit(kit, objID, data); err != nil {
+			return nil, err
+		}
+	}
+
 	if metadata.IsCommon(objID) {
 		data.Set(common.BKObjIDField, objID)
 	}
@@ -181,6 +204,68 @@ func (c *commonInst) CreateInst(kit *rest.Kit, objID string, data mapstr.MapStr)
 	return inst.Info[0], nil
 }
 
+// CreateOrUpdateInst create the instance, or update the instance with the same natural key which is the keys of
+// the model's unique rule, the audit log is generated by whether the instance is created or updated.
+func (c *commonInst) CreateOrUpdateInst(kit *rest.Kit, objID string, opt *metadata.CreateOrUpdateModelInstance) (
+	*metadata.CreateOrUpdateInstResult, error) {
+
+	if _, err := c.validObject(kit, objID, opt.Data); err != nil {
+		blog.Errorf("check object (%s) if is mainline object failed, err: %v, rid: %s", objID, err, kit.Rid)
+		return nil, err
+	}
+
+	if metadata.IsCommon(objID) {
+		opt.Data.Set(common.BKObjIDField, objID)
+	}
+	opt.Data.Set(common.BkSupplierAccount, kit.SupplierAccount)
+
+	result, ccErr := c.clientSet.CoreService().Instance().CreateOrUpdateInstance(kit.Ctx, kit.Header, objID, opt)
+	if ccErr != nil {
+		blog.Errorf("create or update object %s instance failed, err: %v, rid: %s", objID, ccErr, kit.Rid)
+		return nil, ccErr
+	}
+
+	if !result.Created && !result.Updated {
+		result.PreData = nil
+		return result, nil
+	}
+
+	audit := auditlog.NewInstanceAudit(c.clientSet.CoreService())
+	var auditLog []metadata.AuditLog
+	var err error
+	if result.Created {
+		input := &metadata.QueryCondition{Condition: mapstr.MapStr{metadata.GetInstIDFieldByObjID(objID): result.ID}}
+		inst, err := c.FindInst(kit, objID, input)
+		if err != nil {
+			blog.Errorf("search instance by inst_id(%d) failed, err: %v, rid: %s", result.ID, err, kit.Rid)
+			return nil, err
+		}
+
+		generateAuditParameter := auditlog.NewGenerateAuditCommonParameter(kit, metadata.AuditCreate)
+		auditLog, err = audit.GenerateAuditLog(generateAuditParameter, objID, inst.Info)
+		if err != nil {
+			blog.Errorf("create inst, generate audit log failed, err: %v, rid: %s", err, kit.Rid)
+			return nil, err
+		}
+	} else {
+		generateAuditParameter := auditlog.NewGenerateAuditCommonParameter(kit, metadata.AuditUpdate).
+			WithUpdateFields(opt.Data)
+		auditLog, err = audit.GenerateAuditLog(generateAuditParameter, objID, []mapstr.MapStr{result.PreData})
+		if err != nil {
+			blog.Errorf("update inst, generate audit log failed, err: %v, rid: %s", err, kit.Rid)
+			return nil, err
+		}
+	}
+
+	if err := audit.SaveAuditLog(kit, auditLog...); err != nil {
+		blog.Errorf("create or update inst, save audit log failed, err: %v, rid: %s", err, kit.Rid)
+		return nil, kit.CCError.Error(common.CCErrAuditSaveLogFailed)
+	}
+
+	result.PreData = nil
+	return result, nil
+}
+
 // CreateManyInstance batch create instance by object and create message
 func (c *commonInst) CreateManyInstance(kit *rest.Kit, objID string, data []mapstr.MapStr) (
 	*metadata.CreateManyCommInstResultDetail, error) {
@@ -189,9 +274,11 @@ func (c *commonInst) CreateManyInstance(kit *rest.Kit, objID string, data []maps
 		blog.Errorf("details cannot be empty, rid: %s", kit.Rid)
 		return nil, kit.CCError.CCErrorf(common.CCErrCommInstDataNil, "details")
 	}
-	if len(data) > 200 {
-		blog.Errorf("details cannot more than 200, details number: %s, rid: %s", len(data), kit.Rid)
-		return nil, kit.CCError.CCErrorf(common.CCErrCommXXExceedLimit, "details", 200)
+	if len(data) > metadata.CreateManyCommInstMaxLimit {
+		blog.Errorf("details cannot more than %d, details number: %d, rid: %s", metadata.CreateManyCommInstMaxLimit,
+			len(data), kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommXXExceedLimit, "details",
+			metadata.CreateManyCommInstMaxLimit)
 	}
 
 	params := &metadata.CreateManyModelInstance{Datas: data}
@@ -251,12 +338,27 @@ func (c *commonInst) CreateManyInstance(kit *rest.Kit, objID string, data []maps
 
 // createInstBatch batch create instance by excel
 func (c *commonInst) createInstBatch(kit *rest.Kit, objID string, batchInfo *metadata.InstBatchInfo,
-	idFieldName string) (*BatchResult, []int64, []int64, error){
+	idFieldName string) (*BatchResult, []int64, []int64, error) {
 	updatedInstanceIDs := make([]int64, 0)
 	createdInstanceIDs := make([]int64, 0)
 	colIdxErrMap := map[int]string{}
 	colIdxList := make([]int, 0)
-	results := &BatchResult{}
+	results := &BatchResult{Rows: make([]metadata.ImportRowResult, 0), DryRun: batchInfo.DryRun}
+
+	asstColumns, err := c.getImportAsstColumns(kit, objID, batchInfo.AssociationColumns)
+	if err != nil {
+		return results, nil, nil, err
+	}
+
+	lang := c.language.CreateDefaultCCLanguageIf(util.GetLanguage(kit.Header))
+	addRowErr := func(colIdx int64, err interface{}) {
+		errStr := lang.Languagef("import_row_int_error_str", colIdx, err)
+		colIdxList = append(colIdxList, int(colIdx))
+		colIdxErrMap[int(colIdx)] = errStr
+		results.Rows = append(results.Rows, metadata.ImportRowResult{Line: colIdx, Action: metadata.ImportRowError,
+			Error: errStr})
+	}
+
 	for colIdx, colInput := range batchInfo.BatchInfo {
 		if colInput == nil {
 			// ignore empty excel line
@@ -264,6 +366,14 @@ func (c *commonInst) createInstBatch(kit *rest.Kit, objID string, batchInfo *met
 		}
 
 		delete(colInput, "import_from")
+		asstValues := popImportAsstValues(colInput, asstColumns)
+		externalID := popImportExternalID(colInput, batchInfo.ExternalIDSource)
+		if externalID != nil {
+			if rawErr := externalID.Validate(); rawErr.ErrCode != 0 {
+				addRowErr(colIdx, rawErr.ToCCError(kit.CCError).Error())
+				continue
+			}
+		}
 
 		// 实例id 为空，表示要新建实例
 		// 实例ID已经赋值，更新数据.  (已经赋值, value not equal 0 or nil)
@@ -274,56 +384,105 @@ func (c *commonInst) createInstBatch(kit *rest.Kit, objID string, batchInfo *met
 			exist = false
 		}
 
+		var updateInstID int64
 		// 实例ID字段是否设置值
 		if exist {
-			instID, err := util.GetInt64ByInterface(colInput[idFieldName])
+			updateInstID, err = util.GetInt64ByInterface(colInput[idFieldName])
 			if err != nil {
-				errStr := c.language.CreateDefaultCCLanguageIf(util.GetLanguage(kit.Header)).Languagef(
-					"import_row_int_error_str", colIdx, err.Error())
-				colIdxList = append(colIdxList, int(colIdx))
-				colIdxErrMap[int(colIdx)] = errStr
+				addRowErr(colIdx, err.Error())
 				continue
 			}
+		} else {
+			// 根据外部ID匹配已关联的实例，匹配到则更新该实例
+			if externalID != nil {
+				updateInstID, err = c.matchImportInstByExternalID(kit, objID, *externalID)
+				if err != nil {
+					addRowErr(colIdx, err.Error())
+					continue
+				}
+			}
 
-			filter := mapstr.MapStr{idFieldName: instID}
+			// 根据唯一键匹配已存在的实例，匹配到则更新该实例
+			if updateInstID == 0 && len(batchInfo.UpsertKeys) > 0 {
+				updateInstID, err = c.matchImportInst(kit, objID, colInput, batchInfo.UpsertKeys)
+				if err != nil {
+					addRowErr(colIdx, err.Error())
+					continue
+				}
+			}
+		}
+
+		// the referred instances are checked before the row is imported, so that the row is not imported partly
+		targets, err := c.getImportAsstTargets(kit, asstValues)
+		if err != nil {
+			addRowErr(colIdx, err.Error())
+			continue
+		}
+
+		row := metadata.ImportRowResult{Line: colIdx}
+		if updateInstID > 0 {
+			filter := mapstr.MapStr{idFieldName: updateInstID}
 
 			// to update.
 			if err := c.UpdateInst(kit, filter, colInput, objID); err != nil {
 				blog.Errorf("failed to update the object(%s) inst data (%#v), err: %v, rid: %s", objID, colInput,
 					err, kit.Rid)
-				errStr := c.language.CreateDefaultCCLanguageIf(util.GetLanguage(kit.Header)).Languagef(
-					"import_row_int_error_str", colIdx, err.Error())
-				colIdxList = append(colIdxList, int(colIdx))
-				colIdxErrMap[int(colIdx)] = errStr
+				addRowErr(colIdx, err.Error())
 				continue
 			}
 
-			updatedInstanceIDs = append(updatedInstanceIDs, instID)
-			results.Success = append(results.Success, strconv.FormatInt(colIdx, 10))
-			continue
+			updatedInstanceIDs = append(updatedInstanceIDs, updateInstID)
+			row.Action, row.InstID = metadata.ImportRowUpdate, updateInstID
+		} else {
+			colInput.Set(common.BKObjIDField, objID)
+			// call CoreService.CreateInstance
+			instCond := &metadata.CreateModelInstance{Data: colInput}
+			rsp, err := c.clientSet.CoreService().Instance().CreateInstance(kit.Ctx, kit.Header, objID, instCond)
+			if err != nil {
+				blog.Errorf("failed to create object instance, err: %v, rid: %s", err, kit.Rid)
+				addRowErr(colIdx, err)
+				continue
+			}
+
+			row.Action = metadata.ImportRowCreate
+			if rsp.Created.ID == 0 {
+				blog.Errorf("instances created success, but get id failed, err: %+v, rid: %s", err, kit.Rid)
+				results.Success = append(results.Success, strconv.FormatInt(colIdx, 10))
+				results.Rows = append(results.Rows, row)
+				continue
+			}
+
+			createdInstanceIDs = append(createdInstanceIDs, int64(rsp.Created.ID))
+			row.InstID = int64(rsp.Created.ID)
 		}
 
-		colInput.Set(common.BKObjIDField, objID)
-		// call CoreService.CreateInstance
-		instCond := &metadata.CreateModelInstance{Data: colInput}
-		rsp, err := c.clientSet.CoreService().Instance().CreateInstance(kit.Ctx, kit.Header, objID, instCond)
+		if externalID != nil {
+			bindOpt := &metadata.BindExternalIDOption{
+				InstID:      row.InstID,
+				ExternalIDs: []metadata.ExternalIDRef{*externalID},
+			}
+			err = c.clientSet.CoreService().Instance().BindExternalIDs(kit.Ctx, kit.Header, objID, bindOpt)
+			if err != nil {
+				blog.Errorf("bind external id %s to %s inst %d failed, err: %v, rid: %s", externalID.String(),
+					objID, row.InstID, err, kit.Rid)
+				addRowErr(colIdx, err.Error())
+				continue
+			}
+		}
+
+		row.AssociationCreated, row.AssociationDeleted, err = c.refreshImportInstAsst(kit, objID, row.InstID, targets)
 		if err != nil {
-			blog.Errorf("failed to create object instance, err: %v, rid: %s", err, kit.Rid)
-			errStr := c.language.CreateDefaultCCLanguageIf(util.GetLanguage(kit.Header)).Languagef(
-				"import_row_int_error_str", colIdx, err)
-			colIdxList = append(colIdxList, int(colIdx))
-			colIdxErrMap[int(colIdx)] = errStr
+			addRowErr(colIdx, err.Error())
 			continue
 		}
 
-		results.Success = append(results.Success, strconv.FormatInt(colIdx, 10))
-
-		if rsp.Created.ID == 0 {
-			blog.Errorf("instances created success, but get id failed, err: %+v, rid: %s", err, kit.Rid)
-			continue
+		// the created instances are rolled back in the dry run, their ids are meaningless
+		if batchInfo.DryRun && row.Action == metadata.ImportRowCreate {
+			row.InstID = 0
 		}
 
-		createdInstanceIDs = append(createdInstanceIDs, int64(rsp.Created.ID))
+		results.Success = append(results.Success, strconv.FormatInt(colIdx, 10))
+		results.Rows = append(results.Rows, row)
 	}
 
 	// sort error
@@ -332,6 +491,10 @@ func (c *commonInst) createInstBatch(kit *rest.Kit, objID string, batchInfo *met
 		results.Errors = append(results.Errors, colIdxErrMap[colIdxList[colIdx]])
 	}
 
+	sort.Slice(results.Rows, func(i, j int) bool {
+		return results.Rows[i].Line < results.Rows[j].Line
+	})
+
 	return results, createdInstanceIDs, updatedInstanceIDs, nil
 }
 
@@ -368,6 +531,9 @@ func (c *commonInst) CreateInstBatch(kit *rest.Kit, objID string, batchInfo *met
 	if len(batchInfo.BatchInfo) == 0 {
 		return &BatchResult{}, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "BatchInfo")
 	}
+	if rawErr := batchInfo.Validate(); rawErr.ErrCode != 0 {
+		return &BatchResult{}, rawErr.ToCCError(kit.CCError)
+	}
 
 	// 1. 检查实例与URL参数指定的模型一致
 	for line, inst := range batchInfo.BatchInfo {
@@ -1018,7 +1184,8 @@ func (c *commonInst) validParentInstID(kit *rest.Kit, objID string, instID int64
 	return nil
 }
 
-func (c *commonInst) validObject(kit *rest.Kit, objID string, data mapstr.MapStr) error {
+// validObject validates the object can be created instance with the data, returns whether it is a mainline object
+func (c *commonInst) validObject(kit *rest.Kit, objID string, data mapstr.MapStr) (bool, error) {
 
 	input := &metadata.QueryCondition{
 		Condition: mapstr.MapStr{common.BKObjIDField: objID},
@@ -1027,18 +1194,18 @@ func (c *commonInst) validObject(kit *rest.Kit, objID string, data mapstr.MapStr
 	rsp, err := c.clientSet.CoreService().Model().ReadModel(kit.Ctx, kit.Header, input)
 	if err != nil {
 		blog.Errorf("search object(%s) failed, err: %v, rid: %s", objID, err, kit.Rid)
-		return err
+		return false, err
 	}
 
 	if len(rsp.Info) == 0 {
 		blog.Errorf("search object(%s) failed, object does not exist, rid: %s", objID, kit.Rid)
-		return kit.CCError.CCError(common.CCErrTopoModuleSelectFailed)
+		return false, kit.CCError.CCError(common.CCErrTopoModuleSelectFailed)
 	}
 
 	// 暂停使用的model不允许创建实例
 	if rsp.Info[0].IsPaused {
 		blog.Errorf("object (%s) is paused, rid: %s", objID, kit.Rid)
-		return kit.CCError.CCError(common.CCErrorTopoModelStopped)
+		return false, kit.CCError.CCError(common.CCErrorTopoModelStopped)
 	}
 
 	cond := mapstr.MapStr{
@@ -1049,19 +1216,19 @@ func (c *commonInst) validObject(kit *rest.Kit, objID string, data mapstr.MapStr
 		&metadata.QueryCondition{Condition: cond, DisableCounter: true})
 	if err != nil {
 		blog.Errorf("search object association failed, err: %v, rid: %s", err, kit.Rid)
-		return err
+		return false, err
 	}
 
 	if len(asst.Info) == 0 {
-		return nil
+		return false, nil
 	}
 
 	if err := c.validMainLineParentID(kit, asst.Info[0].AsstObjID, data); err != nil {
 		blog.Errorf("valid mainline object(%s) parentID failed, err: %v, rid: %s", objID, err, kit.Rid)
-		return err
+		return false, err
 	}
 
-	return nil
+	return true, nil
 }
 
 // hasHost get objID and instances map for mainline instances with its children topology, and check if they have hosts
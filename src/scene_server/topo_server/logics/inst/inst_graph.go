@@ -0,0 +1,251 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inst
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// SearchInstGraph search the neighborhood graph of the instance, the nodes are expanded breadth-first from the
+// root instance until the depth is reached, the nodes beyond the max nodes are returned but not expanded.
+func (assoc *association) SearchInstGraph(kit *rest.Kit, objID string, instID int64,
+	opt *metadata.InstGraphOption) (*metadata.InstGraph, error) {
+
+	root := &metadata.InstGraphNode{
+		ID:     metadata.InstGraphNodeID(objID, instID),
+		ObjID:  objID,
+		InstID: instID,
+		Group:  objID,
+	}
+	graph := &metadata.InstGraph{
+		Nodes:  []*metadata.InstGraphNode{root},
+		Edges:  make([]metadata.InstGraphEdge, 0),
+		Groups: make([]metadata.InstGraphGroup, 0),
+		Layout: metadata.InstGraphLayout{Root: root.ID},
+	}
+
+	nodeMap := map[string]*metadata.InstGraphNode{root.ID: root}
+	edgeMap := make(map[int64]struct{})
+	frontier := []*metadata.InstGraphNode{root}
+	for level := 0; level < opt.Depth && len(frontier) > 0; level++ {
+		next := make([]*metadata.InstGraphNode, 0)
+		for _, node := range frontier {
+			if len(graph.Nodes) >= metadata.InstGraphMaxNodes {
+				graph.Layout.Truncated = true
+				break
+			}
+
+			start := 0
+			if node == root {
+				start = opt.NeighborStart
+			}
+
+			assts, count, err := assoc.searchInstGraphNeighbors(kit, node, start, opt)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Expanded = true
+			node.Degree = count
+			if uint64(start+len(assts)) < count {
+				node.NextStart = start + len(assts)
+			}
+
+			for _, asst := range assts {
+				source := metadata.InstGraphNodeID(asst.ObjectID, asst.InstID)
+				target := metadata.InstGraphNodeID(asst.AsstObjectID, asst.AsstInstID)
+
+				neighborObjID, neighborInstID, neighborID := asst.AsstObjectID, asst.AsstInstID, target
+				if target == node.ID {
+					neighborObjID, neighborInstID, neighborID = asst.ObjectID, asst.InstID, source
+				}
+
+				if _, exists := nodeMap[neighborID]; !exists {
+					neighbor := &metadata.InstGraphNode{
+						ID:     neighborID,
+						ObjID:  neighborObjID,
+						InstID: neighborInstID,
+						Group:  neighborObjID,
+						Level:  level + 1,
+					}
+					nodeMap[neighborID] = neighbor
+					graph.Nodes = append(graph.Nodes, neighbor)
+					next = append(next, neighbor)
+					graph.Layout.MaxLevel = neighbor.Level
+				}
+
+				if _, exists := edgeMap[asst.ID]; exists {
+					continue
+				}
+				edgeMap[asst.ID] = struct{}{}
+				graph.Edges = append(graph.Edges, metadata.InstGraphEdge{
+					ID:                asst.ID,
+					Source:            source,
+					Target:            target,
+					ObjectAsstID:      asst.ObjectAsstID,
+					AssociationKindID: asst.AssociationKindID,
+				})
+			}
+		}
+		frontier = next
+	}
+
+	if err := assoc.fillInstGraphNodeNames(kit, graph.Nodes); err != nil {
+		return nil, err
+	}
+
+	if len(root.InstName) == 0 {
+		blog.Errorf("inst graph root %s is not found, rid: %s", root.ID, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommNotFound)
+	}
+
+	groups, err := assoc.getInstGraphGroups(kit, graph.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	graph.Groups = groups
+
+	return graph, nil
+}
+
+// searchInstGraphNeighbors search one page of the associations of the node, returns them with the total count
+func (assoc *association) searchInstGraphNeighbors(kit *rest.Kit, node *metadata.InstGraphNode, start int,
+	opt *metadata.InstGraphOption) ([]metadata.InstAsst, uint64, error) {
+
+	srcCond := mapstr.MapStr{common.BKObjIDField: node.ObjID, common.BKInstIDField: node.InstID}
+	dstCond := mapstr.MapStr{common.BKAsstObjIDField: node.ObjID, common.BKAsstInstIDField: node.InstID}
+	if len(opt.ObjIDs) > 0 {
+		srcCond[common.BKAsstObjIDField] = mapstr.MapStr{common.BKDBIN: opt.ObjIDs}
+		dstCond[common.BKObjIDField] = mapstr.MapStr{common.BKDBIN: opt.ObjIDs}
+	}
+
+	query := &metadata.InstAsstQueryCondition{
+		ObjID: node.ObjID,
+		Cond: metadata.QueryCondition{
+			Condition: mapstr.MapStr{common.BKDBOR: []mapstr.MapStr{srcCond, dstCond}},
+			Page: metadata.BasePage{
+				Start: start,
+				Limit: opt.NeighborLimit,
+				Sort:  common.BKFieldID,
+			},
+		},
+	}
+
+	rsp, err := assoc.clientSet.CoreService().Association().ReadInstAssociation(kit.Ctx, kit.Header, query)
+	if err != nil {
+		blog.Errorf("search inst graph node %s associations failed, err: %v, rid: %s", node.ID, err, kit.Rid)
+		return nil, 0, err
+	}
+
+	return rsp.Info, rsp.Count, nil
+}
+
+// fillInstGraphNodeNames set the instance names of the graph nodes
+func (assoc *association) fillInstGraphNodeNames(kit *rest.Kit, nodes []*metadata.InstGraphNode) error {
+	objNodes := make(map[string]map[int64]*metadata.InstGraphNode)
+	for _, node := range nodes {
+		if _, exists := objNodes[node.ObjID]; !exists {
+			objNodes[node.ObjID] = make(map[int64]*metadata.InstGraphNode)
+		}
+		objNodes[node.ObjID][node.InstID] = node
+	}
+
+	for objID, instNodes := range objNodes {
+		instIDs := make([]int64, 0, len(instNodes))
+		for instID := range instNodes {
+			instIDs = append(instIDs, instID)
+		}
+
+		idField := metadata.GetInstIDFieldByObjID(objID)
+		nameField := metadata.GetInstNameFieldName(objID)
+		cond := mapstr.MapStr{idField: mapstr.MapStr{common.BKDBIN: instIDs}}
+		if metadata.IsCommon(objID) {
+			cond[common.BKObjIDField] = objID
+		}
+		query := &metadata.QueryCondition{
+			Condition:      cond,
+			Fields:         []string{idField, nameField},
+			Page:           metadata.BasePage{Limit: common.BKNoLimit},
+			DisableCounter: true,
+		}
+		insts, err := assoc.clientSet.CoreService().Instance().ReadInstance(kit.Ctx, kit.Header, objID, query)
+		if err != nil {
+			blog.Errorf("search object %s inst graph nodes failed, err: %v, rid: %s", objID, err, kit.Rid)
+			return err
+		}
+
+		for _, inst := range insts.Info {
+			instID, err := inst.Int64(idField)
+			if err != nil {
+				blog.Errorf("parse inst id failed, inst: %#v, err: %v, rid: %s", inst, err, kit.Rid)
+				return kit.CCError.CCErrorf(common.CCErrCommInstFieldConvertFail, objID, idField, "int", err.Error())
+			}
+
+			node, exists := instNodes[instID]
+			if !exists {
+				continue
+			}
+			node.InstName = util.GetStrByInterface(inst[nameField])
+		}
+	}
+
+	return nil
+}
+
+// getInstGraphGroups get the models of the graph nodes as the groups of the graph
+func (assoc *association) getInstGraphGroups(kit *rest.Kit, nodes []*metadata.InstGraphNode) (
+	[]metadata.InstGraphGroup, error) {
+
+	groupCount := make(map[string]int)
+	objIDs := make([]string, 0)
+	for _, node := range nodes {
+		if _, exists := groupCount[node.Group]; !exists {
+			objIDs = append(objIDs, node.Group)
+		}
+		groupCount[node.Group]++
+	}
+
+	query := &metadata.QueryCondition{
+		Condition:      mapstr.MapStr{common.BKObjIDField: mapstr.MapStr{common.BKDBIN: objIDs}},
+		Fields:         []string{common.BKObjIDField, common.BKObjNameField, common.BKObjIconField},
+		Page:           metadata.BasePage{Limit: common.BKNoLimit},
+		DisableCounter: true,
+	}
+	objs, err := assoc.clientSet.CoreService().Model().ReadModel(kit.Ctx, kit.Header, query)
+	if err != nil {
+		blog.Errorf("search inst graph objects %v failed, err: %v, rid: %s", objIDs, err, kit.Rid)
+		return nil, err
+	}
+
+	objMap := make(map[string]metadata.Object)
+	for _, obj := range objs.Info {
+		objMap[obj.ObjectID] = obj
+	}
+
+	groups := make([]metadata.InstGraphGroup, len(objIDs))
+	for index, objID := range objIDs {
+		groups[index] = metadata.InstGraphGroup{
+			ID:      objID,
+			ObjName: objMap[objID].ObjectName,
+			ObjIcon: objMap[objID].ObjIcon,
+			Count:   groupCount[objID],
+		}
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,340 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inst
+
+import (
+	"reflect"
+
+	"configcenter/src/common"
+	"configcenter/src/common/auditlog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// PreviewMergeInst compare the two instances to be merged, returns the attributes which have conflicting values
+func (c *commonInst) PreviewMergeInst(kit *rest.Kit, objID string, opt *metadata.MergeInstOption) (
+	*metadata.MergeInstPreview, error) {
+
+	preview, _, err := c.getMergeInstPreview(kit, objID, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// MergeInst merge the source instance into the target instance, the source instance's attribute values are
+// merged into the target instance with the chosen values of the conflicting attributes, its associations and
+// history are re-pointed to the target instance, then the source instance is deleted and archived.
+// for host, the source host's module relations and service instances are moved to the target host too.
+func (c *commonInst) MergeInst(kit *rest.Kit, objID string, opt *metadata.MergeInstOption) error {
+	preview, assts, err := c.getMergeInstPreview(kit, objID, opt)
+	if err != nil {
+		return err
+	}
+
+	for _, conflict := range preview.Conflicts {
+		if _, exists := opt.Data[conflict.PropertyID]; !exists {
+			blog.Errorf("merge inst %d into %d, attribute %s conflicts, rid: %s", opt.SourceID, opt.TargetID,
+				conflict.PropertyID, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrTopoInstMergeConflict, conflict.PropertyID)
+		}
+	}
+
+	if err := c.repointInstAssociations(kit, objID, opt, assts); err != nil {
+		return err
+	}
+
+	// move the history before the source instance is deleted, so that the deletion stays in the source's history
+	auditOpt := &metadata.RepointAuditLogOption{
+		ResourceType: metadata.GetResourceTypeByObjID(objID, false),
+		ObjID:        objID,
+		SourceID:     opt.SourceID,
+		TargetID:     opt.TargetID,
+	}
+	if err := c.clientSet.CoreService().Audit().RepointAuditLog(kit.Ctx, kit.Header, auditOpt); err != nil {
+		blog.Errorf("repoint inst %d history to %d failed, err: %v, rid: %s", opt.SourceID, opt.TargetID, err,
+			kit.Rid)
+		return err
+	}
+
+	if objID == common.BKInnerObjIDHost {
+		return c.mergeHost(kit, opt, preview.Data)
+	}
+
+	// delete the source instance before updating the target, so that the target can take its unique values
+	if err := c.DeleteInstByInstID(kit, objID, []int64{opt.SourceID}, true); err != nil {
+		blog.Errorf("delete merged inst %d failed, err: %v, rid: %s", opt.SourceID, err, kit.Rid)
+		return err
+	}
+
+	if len(preview.Data) == 0 {
+		return nil
+	}
+
+	cond := mapstr.MapStr{common.GetInstIDField(objID): opt.TargetID}
+	if metadata.IsCommon(objID) {
+		cond[common.BKObjIDField] = objID
+	}
+	if err := c.UpdateInst(kit, cond, preview.Data, objID); err != nil {
+		blog.Errorf("update merge target inst %d failed, err: %v, rid: %s", opt.TargetID, err, kit.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// mergeHost move the source host's module relations and service instances to the target host, delete the source
+// host, then update the merged attribute values to the target host.
+func (c *commonInst) mergeHost(kit *rest.Kit, opt *metadata.MergeInstOption, data mapstr.MapStr) error {
+	audit := auditlog.NewHostAudit(c.clientSet.CoreService())
+	deleteParam := auditlog.NewGenerateAuditCommonParameter(kit, metadata.AuditDelete)
+	deleteLogs, err := audit.GenerateAuditLogByCond(deleteParam, 0, mapstr.MapStr{common.BKHostIDField: opt.SourceID})
+	if err != nil {
+		blog.Errorf("generate merged host %d audit log failed, err: %v, rid: %s", opt.SourceID, err, kit.Rid)
+		return err
+	}
+
+	mergeOpt := &metadata.MergeHostOption{SourceID: opt.SourceID, TargetID: opt.TargetID}
+	if err := c.clientSet.CoreService().Host().MergeHost(kit.Ctx, kit.Header, mergeOpt); err != nil {
+		blog.Errorf("merge host %d into %d failed, err: %v, rid: %s", opt.SourceID, opt.TargetID, err, kit.Rid)
+		return err
+	}
+
+	auditLogs := deleteLogs
+	if len(data) > 0 {
+		cond := mapstr.MapStr{common.BKHostIDField: opt.TargetID}
+		updateParam := auditlog.NewGenerateAuditCommonParameter(kit, metadata.AuditUpdate).WithUpdateFields(data)
+		updateLogs, err := audit.GenerateAuditLogByCond(updateParam, 0, cond)
+		if err != nil {
+			blog.Errorf("generate merge target host %d audit log failed, err: %v, rid: %s", opt.TargetID, err,
+				kit.Rid)
+			return err
+		}
+		auditLogs = append(auditLogs, updateLogs...)
+
+		updateOpt := &metadata.UpdateOption{Condition: cond, Data: data}
+		_, err = c.clientSet.CoreService().Instance().UpdateInstance(kit.Ctx, kit.Header, common.BKInnerObjIDHost,
+			updateOpt)
+		if err != nil {
+			blog.Errorf("update merge target host %d failed, err: %v, rid: %s", opt.TargetID, err, kit.Rid)
+			return err
+		}
+	}
+
+	if err := audit.SaveAuditLog(kit, auditLogs...); err != nil {
+		blog.Errorf("save merge host audit logs failed, err: %v, rid: %s", err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrAuditSaveLogFailed)
+	}
+
+	return nil
+}
+
+// getMergeInstPreview get the merge preview and the source instance's associations
+func (c *commonInst) getMergeInstPreview(kit *rest.Kit, objID string, opt *metadata.MergeInstOption) (
+	*metadata.MergeInstPreview, []metadata.InstAsst, error) {
+
+	instIDField := common.GetInstIDField(objID)
+	cond := mapstr.MapStr{instIDField: mapstr.MapStr{common.BKDBIN: []int64{opt.SourceID, opt.TargetID}}}
+	if metadata.IsCommon(objID) {
+		cond[common.BKObjIDField] = objID
+	}
+	query := &metadata.QueryCondition{
+		Condition:      cond,
+		Page:           metadata.BasePage{Limit: common.BKNoLimit},
+		DisableCounter: true,
+	}
+	insts, err := c.FindInst(kit, objID, query)
+	if err != nil {
+		blog.Errorf("find merge insts failed, cond: %#v, err: %v, rid: %s", cond, err, kit.Rid)
+		return nil, nil, err
+	}
+
+	var source, target mapstr.MapStr
+	for _, inst := range insts.Info {
+		instID, err := inst.Int64(instIDField)
+		if err != nil {
+			blog.Errorf("parse inst id failed, inst: %#v, err: %v, rid: %s", inst, err, kit.Rid)
+			return nil, nil, kit.CCError.CCErrorf(common.CCErrCommParamsNeedInt, instIDField)
+		}
+
+		switch instID {
+		case opt.SourceID:
+			source = inst
+		case opt.TargetID:
+			target = inst
+		}
+	}
+
+	if source == nil || target == nil {
+		blog.Errorf("merge inst %d into %d, but inst not found, rid: %s", opt.SourceID, opt.TargetID, kit.Rid)
+		return nil, nil, kit.CCError.CCError(common.CCErrCommNotFound)
+	}
+
+	attrQuery := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{common.BKObjIDField: objID},
+		Fields:    []string{common.BKPropertyIDField, metadata.AttributeFieldIsEditable},
+		Page:      metadata.BasePage{Limit: common.BKNoLimit},
+	}
+	attrs, err := c.clientSet.CoreService().Model().ReadModelAttr(kit.Ctx, kit.Header, objID, attrQuery)
+	if err != nil {
+		blog.Errorf("find object %s attributes failed, err: %v, rid: %s", objID, err, kit.Rid)
+		return nil, nil, err
+	}
+
+	preview := &metadata.MergeInstPreview{
+		Conflicts: make([]metadata.MergeInstConflict, 0),
+		Data:      mapstr.New(),
+	}
+	for _, attr := range attrs.Info {
+		if !attr.IsEditable || attr.PropertyID == instIDField {
+			continue
+		}
+
+		if value, exists := opt.Data[attr.PropertyID]; exists {
+			preview.Data[attr.PropertyID] = value
+		}
+
+		sourceVal, targetVal := source[attr.PropertyID], target[attr.PropertyID]
+		if isEmptyMergeValue(sourceVal) {
+			continue
+		}
+
+		if isEmptyMergeValue(targetVal) {
+			if _, exists := preview.Data[attr.PropertyID]; !exists {
+				preview.Data[attr.PropertyID] = sourceVal
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(sourceVal, targetVal) {
+			preview.Conflicts = append(preview.Conflicts, metadata.MergeInstConflict{
+				PropertyID:  attr.PropertyID,
+				SourceValue: sourceVal,
+				TargetValue: targetVal,
+			})
+		}
+	}
+
+	asstCond := mapstr.MapStr{common.BKDBOR: []mapstr.MapStr{
+		{common.BKObjIDField: objID, common.BKInstIDField: opt.SourceID},
+		{common.BKAsstObjIDField: objID, common.BKAsstInstIDField: opt.SourceID},
+	}}
+	asstQuery := &metadata.InstAsstQueryCondition{
+		Cond:  metadata.QueryCondition{Condition: asstCond, DisableCounter: true},
+		ObjID: objID,
+	}
+	assts, err := c.clientSet.CoreService().Association().ReadInstAssociation(kit.Ctx, kit.Header, asstQuery)
+	if err != nil {
+		blog.Errorf("find inst %d associations failed, err: %v, rid: %s", opt.SourceID, err, kit.Rid)
+		return nil, nil, err
+	}
+	preview.AssociationCount = len(assts.Info)
+
+	return preview, assts.Info, nil
+}
+
+// repointInstAssociations replace the source instance with the target instance in the source's associations
+func (c *commonInst) repointInstAssociations(kit *rest.Kit, objID string, opt *metadata.MergeInstOption,
+	assts []metadata.InstAsst) error {
+
+	if len(assts) == 0 {
+		return nil
+	}
+
+	asstIDs, requests := buildRepointAsstRequests(objID, opt, assts)
+
+	// delete the source's associations first so that the association mapping check will not be violated
+	if _, err := c.asst.DeleteInstAssociation(kit, objID, asstIDs); err != nil {
+		blog.Errorf("delete inst %d associations failed, err: %v, rid: %s", opt.SourceID, err, kit.Rid)
+		return err
+	}
+
+	for _, request := range requests {
+		result, err := c.asst.CreateManyInstAssociation(kit, request)
+		if err != nil {
+			blog.Errorf("create inst %d associations failed, err: %v, rid: %s", opt.TargetID, err, kit.Rid)
+			return err
+		}
+
+		for index, errMsg := range result.Error {
+			// the target instance already has the association, skip it
+			if errMsg == kit.CCError.CCErrorf(common.CCErrTopoAssociationAlreadyExist, request.ObjectID,
+				request.AsstObjectID).Error() {
+				continue
+			}
+
+			blog.Errorf("create inst association %#v failed, err: %s, rid: %s", request.Details[index], errMsg,
+				kit.Rid)
+			return errors.New(common.CCErrCommParamsInvalid, errMsg)
+		}
+	}
+
+	return nil
+}
+
+// buildRepointAsstRequests returns the ids of the source instance's associations to be deleted, and the requests
+// to create the associations with the source instance replaced by the target instance, grouped by association.
+func buildRepointAsstRequests(objID string, opt *metadata.MergeInstOption, assts []metadata.InstAsst) (
+	[]int64, map[string]*metadata.CreateManyInstAsstRequest) {
+
+	asstIDs := make([]int64, len(assts))
+	requests := make(map[string]*metadata.CreateManyInstAsstRequest)
+	for index, asst := range assts {
+		asstIDs[index] = asst.ID
+
+		if asst.ObjectID == objID && asst.InstID == opt.SourceID {
+			asst.InstID = opt.TargetID
+		}
+		if asst.AsstObjectID == objID && asst.AsstInstID == opt.SourceID {
+			asst.AsstInstID = opt.TargetID
+		}
+
+		// the association between the merged instances is meaningless after merged, drop it
+		if asst.ObjectID == asst.AsstObjectID && asst.InstID == asst.AsstInstID {
+			continue
+		}
+
+		request, exists := requests[asst.ObjectAsstID]
+		if !exists {
+			request = &metadata.CreateManyInstAsstRequest{
+				ObjectID:     asst.ObjectID,
+				AsstObjectID: asst.AsstObjectID,
+				ObjectAsstID: asst.ObjectAsstID,
+			}
+			requests[asst.ObjectAsstID] = request
+		}
+		request.Details = append(request.Details, metadata.InstAsst{InstID: asst.InstID, AsstInstID: asst.AsstInstID})
+	}
+
+	return asstIDs, requests
+}
+
+// isEmptyMergeValue check if the attribute value is empty, which will not be regarded as a conflict
+func isEmptyMergeValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	switch val := value.(type) {
+	case string:
+		return len(val) == 0
+	case []interface{}:
+		return len(val) == 0
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inst
+
+import (
+	"testing"
+
+	"configcenter/src/common/metadata"
+)
+
+func TestBuildRepointAsstRequests(t *testing.T) {
+	opt := &metadata.MergeInstOption{SourceID: 1, TargetID: 2}
+	assts := []metadata.InstAsst{
+		{ID: 10, ObjectID: "host", InstID: 1, AsstObjectID: "switch", AsstInstID: 5,
+			ObjectAsstID: "host_connect_switch"},
+		{ID: 11, ObjectID: "host", InstID: 1, AsstObjectID: "switch", AsstInstID: 6,
+			ObjectAsstID: "host_connect_switch"},
+		{ID: 12, ObjectID: "rack", InstID: 3, AsstObjectID: "host", AsstInstID: 1, ObjectAsstID: "rack_contain_host"},
+		// the association between the merged instances is dropped
+		{ID: 13, ObjectID: "host", InstID: 1, AsstObjectID: "host", AsstInstID: 2, ObjectAsstID: "host_connect_host"},
+	}
+
+	asstIDs, requests := buildRepointAsstRequests("host", opt, assts)
+	if len(asstIDs) != 4 || asstIDs[0] != 10 || asstIDs[3] != 13 {
+		t.Fatalf("unexpected deleted association ids: %v", asstIDs)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expect 2 association requests, got %d", len(requests))
+	}
+
+	switchReq := requests["host_connect_switch"]
+	if switchReq == nil || switchReq.ObjectID != "host" || switchReq.AsstObjectID != "switch" ||
+		len(switchReq.Details) != 2 {
+		t.Fatalf("unexpected host_connect_switch request: %#v", switchReq)
+	}
+	for _, detail := range switchReq.Details {
+		if detail.InstID != 2 {
+			t.Errorf("expect association to be re-pointed to target 2, got %d", detail.InstID)
+		}
+	}
+
+	rackReq := requests["rack_contain_host"]
+	if rackReq == nil || len(rackReq.Details) != 1 || rackReq.Details[0].InstID != 3 ||
+		rackReq.Details[0].AsstInstID != 2 {
+		t.Fatalf("unexpected rack_contain_host request: %#v", rackReq)
+	}
+
+	if _, exists := requests["host_connect_host"]; exists {
+		t.Errorf("association between the merged instances should be dropped")
+	}
+}
+
+func TestIsEmptyMergeValue(t *testing.T) {
+	cases := []struct {
+		value interface{}
+		empty bool
+	}{
+		{value: nil, empty: true},
+		{value: "", empty: true},
+		{value: []interface{}{}, empty: true},
+		{value: "1.1.1.1", empty: false},
+		{value: 0, empty: false},
+		{value: false, empty: false},
+		{value: []interface{}{"a"}, empty: false},
+	}
+
+	for _, c := range cases {
+		if isEmptyMergeValue(c.value) != c.empty {
+			t.Errorf("value %#v, expect empty: %v", c.value, c.empty)
+		}
+	}
+}
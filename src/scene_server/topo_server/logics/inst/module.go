@@ -360,6 +360,12 @@ func (m *module) initModuleWithSvcTemp(kit *rest.Kit, bizID, svcTempID int64, mo
 			blog.Errorf("service template %d attr %d is not exist, rid: %s", svcTempID, tempAttr.AttributeID, kit.Rid)
 			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKSetTemplateIDField)
 		}
+
+		// the module has no bound template variables yet, the attribute referencing them is set when the module is
+		// synchronized with the service template after the variables are bound
+		if _, missing := metadata.ResolveTemplateVariables(tempAttr.PropertyValue, nil); len(missing) > 0 {
+			continue
+		}
 		module[propertyID] = tempAttr.PropertyValue
 	}
 
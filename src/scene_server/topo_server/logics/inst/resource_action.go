@@ -0,0 +1,124 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inst
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/auditlog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/httpclient"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// resourceActionMaxResponseLength the max length of the external system's response that is returned and audited
+const resourceActionMaxResponseLength = 4096
+
+// ExecuteResourceAction execute the custom action on the instance by calling the external system, the execution
+// is audited whether it succeeds or not.
+func (c *commonInst) ExecuteResourceAction(kit *rest.Kit, action *metadata.ResourceAction, instID int64,
+	params mapstr.MapStr) (*metadata.ResourceActionResult, error) {
+
+	instIDField := common.GetInstIDField(action.ObjID)
+	cond := mapstr.MapStr{instIDField: instID}
+	if metadata.IsCommon(action.ObjID) {
+		cond[common.BKObjIDField] = action.ObjID
+	}
+	query := &metadata.QueryCondition{
+		Condition:      cond,
+		Page:           metadata.BasePage{Limit: common.BKNoLimit},
+		DisableCounter: true,
+	}
+	insts, err := c.FindInst(kit, action.ObjID, query)
+	if err != nil {
+		blog.Errorf("find resource action %s inst %d failed, err: %v, rid: %s", action.ID, instID, err, kit.Rid)
+		return nil, err
+	}
+
+	if len(insts.Info) == 0 {
+		blog.Errorf("resource action %s inst %d is not found, rid: %s", action.ID, instID, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommNotFound)
+	}
+	inst := insts.Info[0]
+
+	request := &metadata.ResourceActionRequest{
+		ActionID: action.ID,
+		ObjID:    action.ObjID,
+		InstID:   instID,
+		InstName: util.GetStrByInterface(inst[common.GetInstNameField(action.ObjID)]),
+		Operator: kit.User,
+		Params:   params,
+	}
+	result, execErr := c.callResourceAction(kit, action, request)
+
+	audit := auditlog.NewResourceActionAuditLog(c.clientSet.CoreService())
+	param := auditlog.NewGenerateAuditCommonParameter(kit, metadata.AuditExecute)
+	auditLog := audit.GenerateAuditLog(param, inst, action, params, result)
+	if err := audit.SaveAuditLog(kit, auditLog); err != nil {
+		blog.Errorf("save resource action %s audit log failed, err: %v, rid: %s", action.ID, err, kit.Rid)
+		return nil, err
+	}
+
+	if execErr != nil {
+		return nil, execErr
+	}
+
+	return result, nil
+}
+
+// callResourceAction call the external system to execute the action, non 2xx status code is regarded as failure
+func (c *commonInst) callResourceAction(kit *rest.Kit, action *metadata.ResourceAction,
+	request *metadata.ResourceActionRequest) (*metadata.ResourceActionResult, error) {
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		blog.Errorf("marshal resource action %s request failed, err: %v, rid: %s", action.ID, err, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrTopoResourceActionExecuteFailed, action.ID, err.Error())
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(common.BKHTTPCCRequestID, kit.Rid)
+
+	client := httpclient.NewHttpClient()
+	client.SetTimeOut(time.Duration(action.Timeout) * time.Second)
+	statusCode, body, err := client.RequestEx(action.URL, action.Method, header, data)
+	if err != nil {
+		blog.Errorf("call resource action %s failed, err: %v, rid: %s", action.ID, err, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrTopoResourceActionExecuteFailed, action.ID, err.Error())
+	}
+
+	if len(body) > resourceActionMaxResponseLength {
+		body = body[:resourceActionMaxResponseLength]
+	}
+	result := &metadata.ResourceActionResult{
+		StatusCode: statusCode,
+		Response:   string(body),
+	}
+
+	if statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices {
+		blog.Errorf("call resource action %s failed, status code: %d, response: %s, rid: %s", action.ID, statusCode,
+			result.Response, kit.Rid)
+		return result, kit.CCError.CCErrorf(common.CCErrTopoResourceActionExecuteFailed, action.ID,
+			fmt.Sprintf("status code %d", statusCode))
+	}
+
+	return result, nil
+}
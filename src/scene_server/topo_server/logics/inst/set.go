@@ -242,6 +242,12 @@ func (s *set) initSetWithSetTemplate(kit *rest.Kit, bizID, setTempID int64, set
 			blog.Errorf("set template %d attribute %d is not exist, rid: %s", setTempID, tempAttr.AttributeID, kit.Rid)
 			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKSetTemplateIDField)
 		}
+
+		// the set has no bound template variables yet, the attribute referencing them is set when the set is
+		// synchronized with the set template after the variables are bound
+		if _, missing := metadata.ResolveTemplateVariables(tempAttr.PropertyValue, nil); len(missing) > 0 {
+			continue
+		}
 		set[propertyID] = tempAttr.PropertyValue
 	}
 
@@ -0,0 +1,172 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inst
+
+import (
+	"sort"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// checkTopoChildLimit checks if a new node of the mainline object can be created under its parent node regarding the
+// max topo child nodes in the platform setting, the built-in nodes like the idle set are not limited.
+func (c *commonInst) checkTopoChildLimit(kit *rest.Kit, objID string, data mapstr.MapStr) error {
+	if def, exist := data.Get(common.BKDefaultField); exist {
+		defVal, err := util.GetInt64ByInterface(def)
+		if err == nil && defVal != int64(common.DefaultFlagDefaultValue) {
+			return nil
+		}
+	}
+
+	limits, err := c.getTopoChildLimits(kit)
+	if err != nil {
+		return err
+	}
+
+	limit := limits[objID]
+	if limit <= 0 {
+		return nil
+	}
+
+	bizID, err := metadata.GetBizID(data)
+	if err != nil {
+		blog.Errorf("failed to parse the biz id, err: %v, rid: %s", err, kit.Rid)
+		return kit.CCError.Errorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField)
+	}
+
+	parentID, err := metadata.GetParentID(data)
+	if err != nil {
+		blog.Errorf("failed to parse the parent id, err: %v, rid: %s", err, kit.Rid)
+		return kit.CCError.Errorf(common.CCErrCommParamsIsInvalid, common.BKParentIDField)
+	}
+
+	// the biz id is needed since the parent id may be the business or the custom level node whose ids may be equal
+	cond := &metadata.Condition{
+		Condition: mapstr.MapStr{
+			common.BKAppIDField:    bizID,
+			common.BKParentIDField: parentID,
+			common.BKDefaultField:  mapstr.MapStr{common.BKDBIN: []interface{}{common.DefaultFlagDefaultValue, nil}},
+		},
+	}
+	if metadata.IsCommon(objID) {
+		cond.Condition[common.BKObjIDField] = objID
+	}
+
+	rsp, err := c.clientSet.CoreService().Instance().CountInstances(kit.Ctx, kit.Header, objID, cond)
+	if err != nil {
+		blog.Errorf("count object(%s) inst by the condition(%#v), err: %v, rid: %s", objID, cond, err, kit.Rid)
+		return err
+	}
+
+	if int64(rsp.Count) >= limit {
+		blog.Errorf("the number of %s under node %d is %d, exceeds the limit %d, rid: %s", objID, parentID,
+			rsp.Count, limit, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrTopoChildNodeOverLimit, parentID, objID, limit)
+	}
+
+	return nil
+}
+
+// getTopoChildLimits returns the max topo child nodes in the platform setting, which is keyed by the object id.
+func (c *commonInst) getTopoChildLimits(kit *rest.Kit) (map[string]int64, error) {
+	res, err := c.clientSet.CoreService().System().SearchPlatformSetting(kit.Ctx, kit.Header)
+	if err != nil {
+		blog.Errorf("get platform setting failed, err: %v, rid: %s", err, kit.Rid)
+		return nil, err
+	}
+
+	if err := res.CCError(); err != nil {
+		blog.Errorf("get platform setting failed, err: %v, rid: %s", err, kit.Rid)
+		return nil, err
+	}
+
+	return res.Data.Backend.MaxTopoChildNodes, nil
+}
+
+// FindTopoLimitUsage finds the usage of the business topology limits, the businesses whose child nodes under one
+// parent node reach the ratio of the limit are reported, so that they can be adjusted before the limit is reached.
+func (c *commonInst) FindTopoLimitUsage(kit *rest.Kit, opt *metadata.TopoLimitUsageOption) (
+	*metadata.TopoLimitUsageResult, error) {
+
+	res, err := c.clientSet.CoreService().System().SearchPlatformSetting(kit.Ctx, kit.Header)
+	if err != nil {
+		blog.Errorf("get platform setting failed, err: %v, rid: %s", err, kit.Rid)
+		return nil, err
+	}
+
+	if err := res.CCError(); err != nil {
+		blog.Errorf("get platform setting failed, err: %v, rid: %s", err, kit.Rid)
+		return nil, err
+	}
+
+	topo, err := c.clientSet.CoreService().Mainline().SearchMainlineModelTopo(kit.Ctx, kit.Header, false)
+	if err != nil {
+		blog.Errorf("search mainline model topo failed, err: %v, rid: %s", err, kit.Rid)
+		return nil, err
+	}
+
+	result := &metadata.TopoLimitUsageResult{
+		MaxLevel: res.Data.Backend.MaxBizTopoLevel,
+		Usages:   make([]metadata.TopoChildNodeUsage, 0),
+	}
+	for node := topo; node != nil; {
+		result.Level++
+		if len(node.Children) == 0 {
+			break
+		}
+		node = node.Children[0]
+	}
+
+	for objID, limit := range res.Data.Backend.MaxTopoChildNodes {
+		countOpt := &metadata.CountTopoChildNodesOption{
+			BizIDs: opt.BizIDs,
+			// round up so that the usage exactly at the ratio is reported
+			MinCount: int64(float64(limit)*opt.Ratio + 0.999999),
+		}
+		counts, err := c.clientSet.CoreService().Mainline().CountTopoChildNodes(kit.Ctx, kit.Header, objID, countOpt)
+		if err != nil {
+			blog.Errorf("count %s topo child nodes failed, err: %v, rid: %s", objID, err, kit.Rid)
+			return nil, err
+		}
+
+		for _, count := range counts {
+			result.Usages = append(result.Usages, metadata.TopoChildNodeUsage{
+				BizID:    count.BizID,
+				ObjID:    objID,
+				ParentID: count.ParentID,
+				Count:    count.Count,
+				Limit:    limit,
+			})
+		}
+	}
+
+	sort.Slice(result.Usages, func(i, j int) bool {
+		left, right := result.Usages[i], result.Usages[j]
+		leftRatio := float64(left.Count) / float64(left.Limit)
+		rightRatio := float64(right.Count) / float64(right.Limit)
+		if leftRatio != rightRatio {
+			return leftRatio > rightRatio
+		}
+		if left.BizID != right.BizID {
+			return left.BizID < right.BizID
+		}
+		return left.ObjID < right.ObjID
+	})
+
+	return result, nil
+}
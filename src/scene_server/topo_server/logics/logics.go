@@ -40,6 +40,7 @@ type Logics interface {
 	BusinessOperation() inst.BusinessOperationInterface
 	BusinessSetOperation() inst.BusinessSetOperationInterface
 	SetTemplateOperation() settemplate.SetTemplate
+	ChangeBundleOperation() inst.ChangeBundleOperationInterface
 }
 
 type logics struct {
@@ -58,6 +59,7 @@ type logics struct {
 	business          inst.BusinessOperationInterface
 	businessSet       inst.BusinessSetOperationInterface
 	setTemplate       settemplate.SetTemplate
+	changeBundle      inst.ChangeBundleOperationInterface
 }
 
 // New create a logics manager
@@ -77,6 +79,7 @@ func New(client apimachinery.ClientSetInterface, authManager *extensions.AuthMan
 	groupOperation := model.NewGroupOperation(client)
 	businessOperation := inst.NewBusinessOperation(client, authManager)
 	businessSetOperation := inst.NewBusinessSetOperation(client, authManager)
+	changeBundleOperation := inst.NewChangeBundleOperation(client)
 
 	setTemplate := settemplate.NewSetTemplate(client)
 
@@ -89,6 +92,7 @@ func New(client apimachinery.ClientSetInterface, authManager *extensions.AuthMan
 	attributeOperation.SetProxy(groupOperation, objectOperation)
 	businessOperation.SetProxy(instOperation, moduleOperation, setOperation)
 	businessSetOperation.SetProxy(instOperation)
+	changeBundleOperation.SetProxy(setOperation, moduleOperation)
 
 	return &logics{
 		classification:    classificationOperation,
@@ -106,6 +110,7 @@ func New(client apimachinery.ClientSetInterface, authManager *extensions.AuthMan
 		business:          businessOperation,
 		businessSet:       businessSetOperation,
 		setTemplate:       setTemplate,
+		changeBundle:      changeBundleOperation,
 	}
 }
 
@@ -183,3 +188,8 @@ func (l *logics) BusinessSetOperation() inst.BusinessSetOperationInterface {
 func (l *logics) SetTemplateOperation() settemplate.SetTemplate {
 	return l.setTemplate
 }
+
+// ChangeBundleOperation change bundle operation
+func (l *logics) ChangeBundleOperation() inst.ChangeBundleOperationInterface {
+	return l.changeBundle
+}
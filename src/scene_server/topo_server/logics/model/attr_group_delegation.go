@@ -0,0 +1,123 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// SaveAttrGroupDelegation delegate the edit right of the model's attribute group to the role, the group must be the
+// model's global group, the business custom groups are managed by the business itself.
+func (a *attribute) SaveAttrGroupDelegation(kit *rest.Kit, delegation *metadata.AttrGroupDelegation) (
+	*metadata.AttrGroupDelegation, error) {
+
+	cond := mapstr.MapStr{
+		metadata.GroupFieldGroupID:  delegation.GroupID,
+		metadata.GroupFieldObjectID: delegation.ObjID,
+	}
+	util.AddModelBizIDCondition(cond, 0)
+	grpCond := metadata.QueryCondition{
+		Condition: cond,
+		Fields:    []string{metadata.GroupFieldGroupID},
+		Page:      metadata.BasePage{Limit: 1},
+	}
+
+	grpRsp, err := a.clientSet.CoreService().Model().ReadAttributeGroup(kit.Ctx, kit.Header, delegation.ObjID, grpCond)
+	if err != nil {
+		blog.Errorf("get attribute group failed, cond: %#v, err: %v, rid: %s", grpCond, err, kit.Rid)
+		return nil, err
+	}
+
+	if len(grpRsp.Info) == 0 {
+		blog.Errorf("attribute group %s is not in object %s, rid: %s", delegation.GroupID, delegation.ObjID, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKPropertyGroupIDField)
+	}
+
+	result, err := a.clientSet.CoreService().Model().SaveAttrGroupDelegation(kit.Ctx, kit.Header, delegation)
+	if err != nil {
+		blog.Errorf("save attribute group delegation failed, delegation: %#v, err: %v, rid: %s", delegation, err,
+			kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindAttrGroupDelegation find the attribute group delegations of the model
+func (a *attribute) FindAttrGroupDelegation(kit *rest.Kit, objID string) ([]metadata.AttrGroupDelegation, error) {
+	opt := &metadata.ListAttrGroupDelegationOption{ObjID: objID}
+	delegations, err := a.clientSet.CoreService().Model().ReadAttrGroupDelegation(kit.Ctx, kit.Header, opt)
+	if err != nil {
+		blog.Errorf("read attribute group delegations failed, opt: %#v, err: %v, rid: %s", opt, err, kit.Rid)
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+// DeleteAttrGroupDelegation delete the delegation of the model's attribute group to the role
+func (a *attribute) DeleteAttrGroupDelegation(kit *rest.Kit, key *metadata.AttrGroupDelegationKey) error {
+	if err := a.clientSet.CoreService().Model().DeleteAttrGroupDelegation(kit.Ctx, kit.Header, key); err != nil {
+		blog.Errorf("delete attribute group delegation failed, key: %#v, err: %v, rid: %s", key, err, kit.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// FindAttributeEditSchema find the model's attributes and the business's custom attributes, each attribute is
+// returned with the roles its group is delegated to and whether the current user can edit it.
+func (a *attribute) FindAttributeEditSchema(kit *rest.Kit, bizID int64, objID string) (
+	[]metadata.AttributeEditSchema, error) {
+
+	attrs, err := a.getBizFieldLayoutAttrs(kit, bizID, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	delegations, err := a.FindAttrGroupDelegation(kit, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupRoles := make(map[string][]string)
+	editableGroups := make(map[string]struct{})
+	for _, delegation := range delegations {
+		groupRoles[delegation.GroupID] = append(groupRoles[delegation.GroupID], delegation.Role)
+		if util.InStrArr(delegation.Users, kit.User) {
+			editableGroups[delegation.GroupID] = struct{}{}
+		}
+	}
+
+	schemas := make([]metadata.AttributeEditSchema, len(attrs))
+	for index, attr := range attrs {
+		roles, delegated := groupRoles[attr.PropertyGroup]
+		_, editable := editableGroups[attr.PropertyGroup]
+		if !delegated {
+			roles = make([]string, 0)
+		}
+
+		schemas[index] = metadata.AttributeEditSchema{
+			Attribute:      attr,
+			DelegatedRoles: roles,
+			UserEditable:   attr.IsEditable && (!delegated || editable),
+		}
+	}
+
+	return schemas, nil
+}
@@ -35,10 +35,37 @@ type AttributeOperationInterface interface {
 	CreateObjectAttribute(kit *rest.Kit, data *metadata.Attribute) (*metadata.Attribute, error)
 	DeleteObjectAttribute(kit *rest.Kit, cond mapstr.MapStr, modelBizID int64) error
 	UpdateObjectAttribute(kit *rest.Kit, data mapstr.MapStr, attID int64, modelBizID int64) error
+	// LockObjectAttribute lock or unlock the attributes against the changes in the business scope
+	LockObjectAttribute(kit *rest.Kit, option *metadata.LockAttributeOption) error
+	// SetObjectAttributeOwner set the external source which owns the data of the attributes
+	SetObjectAttributeOwner(kit *rest.Kit, option *metadata.SetAttributeOwnerOption) error
+	// UpdateAttributeLifecycle update the lifecycle of the attribute
+	UpdateAttributeLifecycle(kit *rest.Kit, id int64, lifecycle *metadata.SchemaLifecycle) error
 	// CreateObjectBatch upsert object attributes
 	CreateObjectBatch(kit *rest.Kit, data map[string]metadata.ImportObjectData) (mapstr.MapStr, error)
 	// FindObjectBatch find object to attributes mapping
 	FindObjectBatch(kit *rest.Kit, objIDs []string) (mapstr.MapStr, error)
+	// PreviewDeleteObjectAttribute preview the deletion of the model attribute with the cascade policy
+	PreviewDeleteObjectAttribute(kit *rest.Kit, id int64, modelBizID int64,
+		policy metadata.DeletePolicy) (*metadata.DeletePreview, error)
+	// SaveBizFieldLayout save the business's field layout of the model
+	SaveBizFieldLayout(kit *rest.Kit, layout *metadata.BizFieldLayout) (*metadata.BizFieldLayout, error)
+	// FindBizFieldLayout find the business's field layout of the model with the attributes applied by it
+	FindBizFieldLayout(kit *rest.Kit, bizID int64, objID string) (*metadata.BizFieldLayoutResult, error)
+	// DeleteBizFieldLayout delete the business's field layout of the model
+	DeleteBizFieldLayout(kit *rest.Kit, bizID int64, objID string) error
+	// SaveAttrGroupDelegation delegate the edit right of the model's attribute group to the role
+	SaveAttrGroupDelegation(kit *rest.Kit, delegation *metadata.AttrGroupDelegation) (*metadata.AttrGroupDelegation,
+		error)
+	// FindAttrGroupDelegation find the attribute group delegations of the model
+	FindAttrGroupDelegation(kit *rest.Kit, objID string) ([]metadata.AttrGroupDelegation, error)
+	// DeleteAttrGroupDelegation delete the delegation of the model's attribute group to the role
+	DeleteAttrGroupDelegation(kit *rest.Kit, key *metadata.AttrGroupDelegationKey) error
+	// FindAttributeEditSchema find the model's attributes with the current user's edit right regarding the delegations
+	FindAttributeEditSchema(kit *rest.Kit, bizID int64, objID string) ([]metadata.AttributeEditSchema, error)
+	// FindEnumCascadeOptions find the options of the cascade enum attribute for the value of its parent attribute
+	FindEnumCascadeOptions(kit *rest.Kit, objID string, opt *metadata.EnumCascadeOptionsOption) (
+		*metadata.EnumCascadeOptions, error)
 	SetProxy(grp GroupOperationInterface, obj ObjectOperationInterface)
 }
 
@@ -115,13 +142,17 @@ func (a *attribute) IsValid(kit *rest.Kit, isUpdate bool, data *metadata.Attribu
 			common.AttributePlaceHolderMaxLength)
 	}
 
+	if rawErr := metadata.ValidateAttributeI18n(data.I18n); rawErr.ErrCode != 0 {
+		return rawErr.ToCCError(kit.CCError)
+	}
+
 	return nil
 }
 
 // isPropertyTypeIntEnumListSingleLong check is property type in enum list single long
 func (a *attribute) isPropertyTypeIntEnumListSingleLong(propertyType string) bool {
 	switch propertyType {
-	case common.FieldTypeInt, common.FieldTypeEnum, common.FieldTypeList:
+	case common.FieldTypeInt, common.FieldTypeEnum, common.FieldTypeList, common.FieldTypeEnumCascade:
 		return true
 	case common.FieldTypeSingleChar, common.FieldTypeLongChar:
 		return true
@@ -312,6 +343,12 @@ func (a *attribute) DeleteObjectAttribute(kit *rest.Kit, cond mapstr.MapStr, mod
 		return nil
 	}
 
+	if modelBizID > 0 {
+		if err := checkAttrNotLocked(kit, attrItems.Info); err != nil {
+			return err
+		}
+	}
+
 	auditLogArr := make([]metadata.AuditLog, 0)
 	attrIDMap := make(map[string][]int64, 0)
 	audit := auditlog.NewObjectAttributeAuditLog(a.clientSet.CoreService())
@@ -369,6 +406,24 @@ func (a *attribute) UpdateObjectAttribute(kit *rest.Kit, data mapstr.MapStr, att
 		return err
 	}
 
+	// the locked attributes can only be updated by the platform, not in the business scope
+	if modelBizID > 0 {
+		queryCond := &metadata.QueryCondition{
+			Condition:      mapstr.MapStr{common.BKFieldID: attID},
+			Fields:         []string{common.BKPropertyIDField, metadata.AttributeFieldIsLocked},
+			DisableCounter: true,
+		}
+		attrs, err := a.clientSet.CoreService().Model().ReadModelAttrByCondition(kit.Ctx, kit.Header, queryCond)
+		if err != nil {
+			blog.Errorf("find attribute %d failed, err: %v, rid: %s", attID, err, kit.Rid)
+			return err
+		}
+
+		if err := checkAttrNotLocked(kit, attrs.Info); err != nil {
+			return err
+		}
+	}
+
 	// generate audit log of model attribute.
 	audit := auditlog.NewObjectAttributeAuditLog(a.clientSet.CoreService())
 	generateAuditParameter := auditlog.NewGenerateAuditCommonParameter(kit, metadata.AuditUpdate).WithUpdateFields(data)
@@ -403,6 +458,87 @@ func (a *attribute) UpdateObjectAttribute(kit *rest.Kit, data mapstr.MapStr, att
 	return nil
 }
 
+// LockObjectAttribute lock or unlock the attributes against the changes in the business scope
+func (a *attribute) LockObjectAttribute(kit *rest.Kit, option *metadata.LockAttributeOption) error {
+	if rawErr := option.Validate(); rawErr.ErrCode != 0 {
+		return rawErr.ToCCError(kit.CCError)
+	}
+
+	data := mapstr.MapStr{metadata.AttributeFieldIsLocked: option.Locked}
+	return a.updateAttributesWithAudit(kit, option.IDs, data)
+}
+
+// SetObjectAttributeOwner set the external source which owns the data of the attributes, the instances' owned
+// attributes can only be written by the source, the writes from the other actors are rejected or flagged by policy.
+func (a *attribute) SetObjectAttributeOwner(kit *rest.Kit, option *metadata.SetAttributeOwnerOption) error {
+	if rawErr := option.Validate(); rawErr.ErrCode != 0 {
+		return rawErr.ToCCError(kit.CCError)
+	}
+
+	data := mapstr.MapStr{
+		metadata.AttributeFieldOwnerSource: option.Source,
+		metadata.AttributeFieldOwnerPolicy: option.Policy,
+	}
+	return a.updateAttributesWithAudit(kit, option.IDs, data)
+}
+
+// updateAttributesWithAudit update the attributes by ids with the data, and save the audit logs of the attributes
+func (a *attribute) updateAttributesWithAudit(kit *rest.Kit, ids []int64, data mapstr.MapStr) error {
+	cond := mapstr.MapStr{common.BKFieldID: mapstr.MapStr{common.BKDBIN: ids}}
+	queryCond := &metadata.QueryCondition{
+		Condition:      cond,
+		Page:           metadata.BasePage{Limit: common.BKNoLimit},
+		DisableCounter: true,
+	}
+	attrs, err := a.clientSet.CoreService().Model().ReadModelAttrByCondition(kit.Ctx, kit.Header, queryCond)
+	if err != nil {
+		blog.Errorf("find attributes %v failed, err: %v, rid: %s", ids, err, kit.Rid)
+		return err
+	}
+
+	if len(attrs.Info) != len(util.IntArrayUnique(ids)) {
+		blog.Errorf("some of the attributes %v are not exist, rid: %s", ids, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "ids")
+	}
+
+	audit := auditlog.NewObjectAttributeAuditLog(a.clientSet.CoreService())
+	generateAuditParameter := auditlog.NewGenerateAuditCommonParameter(kit, metadata.AuditUpdate).WithUpdateFields(data)
+	auditLogs := make([]metadata.AuditLog, 0, len(attrs.Info))
+	for index := range attrs.Info {
+		auditLog, err := audit.GenerateAuditLog(generateAuditParameter, attrs.Info[index].ID, &attrs.Info[index])
+		if err != nil {
+			blog.Errorf("generate audit log failed, attribute: %d, err: %v, rid: %s", attrs.Info[index].ID, err,
+				kit.Rid)
+			return err
+		}
+		auditLogs = append(auditLogs, *auditLog)
+	}
+
+	input := metadata.UpdateOption{Condition: cond, Data: data}
+	if _, err := a.clientSet.CoreService().Model().UpdateModelAttrsByCondition(kit.Ctx, kit.Header, &input); err != nil {
+		blog.Errorf("update attributes %v to %v failed, err: %v, rid: %s", ids, data, err, kit.Rid)
+		return err
+	}
+
+	if err := audit.SaveAuditLog(kit, auditLogs...); err != nil {
+		blog.Errorf("update attributes success, but save audit log failed, err: %v, rid: %s", err, kit.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// checkAttrNotLocked check that none of the attributes is locked, which is used for the changes in business scope
+func checkAttrNotLocked(kit *rest.Kit, attrs []metadata.Attribute) error {
+	for _, attr := range attrs {
+		if attr.IsLocked {
+			blog.Errorf("attribute %s is locked, can not be changed in business, rid: %s", attr.PropertyID, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrTopoAttributeLocked, attr.PropertyID)
+		}
+	}
+	return nil
+}
+
 // isMainlineModel check is mainline model by module id
 func (a *attribute) isMainlineModel(kit *rest.Kit, modelID string) (bool, error) {
 	cond := mapstr.MapStr{
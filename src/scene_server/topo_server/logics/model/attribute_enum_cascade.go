@@ -0,0 +1,65 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// FindEnumCascadeOptions find the options of the model's cascade enum attribute when its parent attribute's value is
+// the option's parent value, which is used by the ui to refresh the dependent dropdown when the parent is changed.
+func (a *attribute) FindEnumCascadeOptions(kit *rest.Kit, objID string, opt *metadata.EnumCascadeOptionsOption) (
+	*metadata.EnumCascadeOptions, error) {
+
+	attrCond := &metadata.QueryCondition{
+		Condition: mapstr.MapStr{
+			metadata.AttributeFieldObjectID:   objID,
+			metadata.AttributeFieldPropertyID: opt.PropertyID,
+		},
+		Page: metadata.BasePage{Limit: 1},
+	}
+	attrRsp, err := a.clientSet.CoreService().Model().ReadModelAttr(kit.Ctx, kit.Header, objID, attrCond)
+	if err != nil {
+		blog.Errorf("get object %s attribute %s failed, err: %v, rid: %s", objID, opt.PropertyID, err, kit.Rid)
+		return nil, err
+	}
+
+	if len(attrRsp.Info) == 0 {
+		blog.Errorf("object %s attribute %s is not found, rid: %s", objID, opt.PropertyID, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommNotFound)
+	}
+
+	attr := attrRsp.Info[0]
+	if attr.PropertyType != common.FieldTypeEnumCascade {
+		blog.Errorf("object %s attribute %s type %s is not cascade enum, rid: %s", objID, opt.PropertyID,
+			attr.PropertyType, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKPropertyIDField)
+	}
+
+	option, err := metadata.ParseEnumCascadeOption(attr.Option)
+	if err != nil {
+		blog.Errorf("parse attribute %s enum cascade option failed, err: %v, rid: %s", opt.PropertyID, err, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParseDataFailed)
+	}
+
+	return &metadata.EnumCascadeOptions{
+		PropertyID:  attr.PropertyID,
+		Parent:      option.Parent,
+		ParentValue: opt.ParentValue,
+		Options:     option.GetOptions(opt.ParentValue),
+	}, nil
+}
@@ -0,0 +1,152 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// SaveBizFieldLayout save the business's field layout of the model, the attributes and groups in the layout must
+// belong to the model or the business's custom ones.
+func (a *attribute) SaveBizFieldLayout(kit *rest.Kit, layout *metadata.BizFieldLayout) (*metadata.BizFieldLayout,
+	error) {
+
+	attrs, err := a.getBizFieldLayoutAttrs(kit, layout.BizID, layout.ObjID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attrs) == 0 {
+		blog.Errorf("object %s has no attributes, rid: %s", layout.ObjID, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKObjIDField)
+	}
+
+	attrMap := make(map[string]struct{}, len(attrs))
+	for _, attr := range attrs {
+		attrMap[attr.PropertyID] = struct{}{}
+	}
+
+	groupIDs := make([]string, 0)
+	for _, field := range layout.Fields {
+		if _, exists := attrMap[field.PropertyID]; !exists {
+			blog.Errorf("attribute %s is not in object %s, rid: %s", field.PropertyID, layout.ObjID, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, field.PropertyID)
+		}
+
+		if len(field.PropertyGroup) != 0 {
+			groupIDs = append(groupIDs, field.PropertyGroup)
+		}
+	}
+
+	if len(groupIDs) > 0 {
+		groupIDs = util.StrArrayUnique(groupIDs)
+		cond := mapstr.MapStr{
+			metadata.GroupFieldGroupID:  mapstr.MapStr{common.BKDBIN: groupIDs},
+			metadata.GroupFieldObjectID: layout.ObjID,
+		}
+		util.AddModelBizIDCondition(cond, layout.BizID)
+		grpCond := metadata.QueryCondition{
+			Condition: cond,
+			Fields:    []string{metadata.GroupFieldGroupID},
+			Page:      metadata.BasePage{Limit: common.BKNoLimit},
+		}
+
+		grpRsp, err := a.clientSet.CoreService().Model().ReadAttributeGroup(kit.Ctx, kit.Header, layout.ObjID,
+			grpCond)
+		if err != nil {
+			blog.Errorf("get attribute groups failed, cond: %#v, err: %v, rid: %s", grpCond, err, kit.Rid)
+			return nil, err
+		}
+
+		groupMap := make(map[string]struct{}, len(grpRsp.Info))
+		for _, grp := range grpRsp.Info {
+			groupMap[grp.GroupID] = struct{}{}
+		}
+
+		for _, groupID := range groupIDs {
+			if _, exists := groupMap[groupID]; !exists {
+				blog.Errorf("attribute group %s is not in object %s, rid: %s", groupID, layout.ObjID, kit.Rid)
+				return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, groupID)
+			}
+		}
+	}
+
+	result, err := a.clientSet.CoreService().Model().SaveBizFieldLayout(kit.Ctx, kit.Header, layout)
+	if err != nil {
+		blog.Errorf("save biz field layout failed, layout: %#v, err: %v, rid: %s", layout, err, kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindBizFieldLayout find the business's field layout of the model and the attributes applied by it, the model's
+// attributes are returned if the layout is not set.
+func (a *attribute) FindBizFieldLayout(kit *rest.Kit, bizID int64, objID string) (*metadata.BizFieldLayoutResult,
+	error) {
+
+	opt := &metadata.BizFieldLayoutOption{BizID: bizID, ObjID: objID}
+	layout, err := a.clientSet.CoreService().Model().ReadBizFieldLayout(kit.Ctx, kit.Header, opt)
+	if err != nil {
+		blog.Errorf("read biz field layout failed, opt: %#v, err: %v, rid: %s", opt, err, kit.Rid)
+		return nil, err
+	}
+
+	if layout == nil {
+		layout = &metadata.BizFieldLayout{BizID: bizID, ObjID: objID, Fields: make([]metadata.BizFieldLayoutField, 0)}
+	}
+
+	attrs, err := a.getBizFieldLayoutAttrs(kit, bizID, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metadata.BizFieldLayoutResult{
+		Layout:     *layout,
+		Attributes: layout.Apply(attrs),
+	}, nil
+}
+
+// DeleteBizFieldLayout delete the business's field layout of the model
+func (a *attribute) DeleteBizFieldLayout(kit *rest.Kit, bizID int64, objID string) error {
+	opt := &metadata.BizFieldLayoutOption{BizID: bizID, ObjID: objID}
+	if err := a.clientSet.CoreService().Model().DeleteBizFieldLayout(kit.Ctx, kit.Header, opt); err != nil {
+		blog.Errorf("delete biz field layout failed, opt: %#v, err: %v, rid: %s", opt, err, kit.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// getBizFieldLayoutAttrs get the model's attributes and the business's custom attributes in the model's order
+func (a *attribute) getBizFieldLayoutAttrs(kit *rest.Kit, bizID int64, objID string) ([]metadata.Attribute, error) {
+	cond := mapstr.MapStr{metadata.AttributeFieldObjectID: objID}
+	util.AddModelBizIDCondition(cond, bizID)
+	attrCond := &metadata.QueryCondition{
+		Condition: cond,
+		Page:      metadata.BasePage{Limit: common.BKNoLimit, Sort: metadata.AttributeFieldPropertyIndex},
+	}
+
+	attrRsp, err := a.clientSet.CoreService().Model().ReadModelAttr(kit.Ctx, kit.Header, objID, attrCond)
+	if err != nil {
+		blog.Errorf("get object %s attributes failed, err: %v, rid: %s", objID, err, kit.Rid)
+		return nil, err
+	}
+
+	return attrRsp.Info, nil
+}
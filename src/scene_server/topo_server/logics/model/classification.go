@@ -283,5 +283,12 @@ func (c *classification) isValid(kit *rest.Kit, isUpdate bool, data mapstr.MapSt
 			return nil, err
 		}
 	}
+
+	if data.Exists(common.BKClassificationIconField) {
+		if err := validateIconAssetRef(kit, c.clientSet, cls.ClassificationIcon,
+			common.BKClassificationIconField); err != nil {
+			return nil, err
+		}
+	}
 	return cls, nil
 }
@@ -0,0 +1,153 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// PreviewDeleteObject preview the deletion of the model with the cascade policy
+func (o *object) PreviewDeleteObject(kit *rest.Kit, id int64, policy metadata.DeletePolicy) (*metadata.DeletePreview,
+	error) {
+
+	query := &metadata.QueryCondition{
+		Condition:      mapstr.MapStr{common.BKFieldID: id},
+		Fields:         []string{common.BKFieldID, common.BKObjIDField},
+		DisableCounter: true,
+	}
+	objs, err := o.clientSet.CoreService().Model().ReadModel(kit.Ctx, kit.Header, query)
+	if err != nil {
+		blog.Errorf("find object by id %d failed, err: %v, rid: %s", id, err, kit.Rid)
+		return nil, err
+	}
+
+	if len(objs.Info) == 0 {
+		blog.Errorf("object %d is not exist, rid: %s", id, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrorModelNotFound)
+	}
+	objID := objs.Info[0].ObjectID
+
+	instCount, asstCount, err := o.countDeleteDependence(kit, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &metadata.DeletePreview{
+		Policy:           policy,
+		ObjID:            objID,
+		InstanceCount:    instCount,
+		AssociationCount: asstCount,
+		Async:            policy == metadata.DeletePolicyArchive,
+		Policies:         metadata.ModelDeletePolicies,
+	}
+
+	switch {
+	case common.IsInnerModel(objID):
+		preview.Reason = kit.CCError.Error(common.CCErrTopoForbiddenToDeleteModelFailed).Error()
+	case asstCount != 0:
+		preview.Reason = kit.CCError.Error(common.CCErrorTopoObjectHasAlreadyAssociated).Error()
+	case policy == metadata.DeletePolicyBlock && instCount != 0:
+		preview.Reason = kit.CCError.Errorf(common.CCErrTopoObjectHasSomeInstsForbiddenToDelete, objID).Error()
+	}
+	preview.Blocked = len(preview.Reason) != 0
+
+	return preview, nil
+}
+
+// countDeleteDependence count the instances of the model and the associations related to the model
+func (o *object) countDeleteDependence(kit *rest.Kit, objID string) (int64, int64, error) {
+	cond := mapstr.MapStr{common.BKObjIDField: objID}
+	instRsp, err := o.clientSet.CoreService().Instance().CountInstances(kit.Ctx, kit.Header, objID,
+		&metadata.Condition{Condition: cond})
+	if err != nil {
+		blog.Errorf("failed to count object(%s) insts, err: %v, rid: %s", objID, err, kit.Rid)
+		return 0, 0, err
+	}
+
+	asstCond := []map[string]interface{}{{
+		common.BKDBOR: []mapstr.MapStr{{common.BKObjIDField: objID}, {common.AssociatedObjectIDField: objID}}},
+	}
+	asstCnt, err := o.clientSet.CoreService().Count().GetCountByFilter(kit.Ctx, kit.Header,
+		common.BKTableNameObjAsst, asstCond)
+	if err != nil {
+		blog.Errorf("count object(%s) associations failed, err: %v, rid: %s", objID, err, kit.Rid)
+		return 0, 0, err
+	}
+
+	if len(asstCnt) != 1 {
+		blog.Errorf("count object(%s) associations, but got invalid result: %v, rid: %s", objID, asstCnt, kit.Rid)
+		return 0, 0, kit.CCError.Error(common.CCErrorTopoObjectAssociationNotExist)
+	}
+
+	return int64(instRsp.Count), asstCnt[0], nil
+}
+
+// PreviewDeleteObjectAttribute preview the deletion of the model attribute with the cascade policy
+func (a *attribute) PreviewDeleteObjectAttribute(kit *rest.Kit, id int64, modelBizID int64,
+	policy metadata.DeletePolicy) (*metadata.DeletePreview, error) {
+
+	cond := mapstr.MapStr{metadata.AttributeFieldID: id}
+	util.AddModelBizIDCondition(cond, modelBizID)
+	queryCond := &metadata.QueryCondition{
+		Condition: cond,
+		Fields: []string{common.BKFieldID, common.BKObjIDField, common.BKPropertyIDField,
+			metadata.AttributeFieldIsPre},
+		DisableCounter: true,
+	}
+	attrs, err := a.clientSet.CoreService().Model().ReadModelAttrByCondition(kit.Ctx, kit.Header, queryCond)
+	if err != nil {
+		blog.Errorf("find the attribute by cond(%v) failed, err: %v, rid: %s", cond, err, kit.Rid)
+		return nil, err
+	}
+
+	if len(attrs.Info) == 0 {
+		blog.Errorf("attribute %d is not exist, rid: %s", id, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrTopoObjectPropertyNotFound)
+	}
+	attr := attrs.Info[0]
+
+	// count the instances which has none empty value of the attribute
+	instCond := mapstr.MapStr{attr.PropertyID: mapstr.MapStr{common.BKDBNIN: []interface{}{nil, ""}}}
+	if metadata.IsCommon(attr.ObjectID) {
+		instCond[common.BKObjIDField] = attr.ObjectID
+	}
+	instRsp, err := a.clientSet.CoreService().Instance().CountInstances(kit.Ctx, kit.Header, attr.ObjectID,
+		&metadata.Condition{Condition: instCond})
+	if err != nil {
+		blog.Errorf("count instances with attribute %s data failed, err: %v, rid: %s", attr.PropertyID, err,
+			kit.Rid)
+		return nil, err
+	}
+
+	preview := &metadata.DeletePreview{
+		Policy:        policy,
+		ObjID:         attr.ObjectID,
+		PropertyID:    attr.PropertyID,
+		InstanceCount: int64(instRsp.Count),
+		Async:         policy == metadata.DeletePolicyDropData,
+		Policies:      metadata.AttributeDeletePolicies,
+	}
+
+	if policy == metadata.DeletePolicyBlock && instRsp.Count != 0 {
+		preview.Blocked = true
+		preview.Reason = kit.CCError.Errorf(common.CCErrTopoObjectAttrHasDataForbiddenToDelete,
+			attr.PropertyID).Error()
+	}
+
+	return preview, nil
+}
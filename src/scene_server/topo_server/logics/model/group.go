@@ -89,6 +89,10 @@ func (g *group) CreateObjectGroup(kit *rest.Kit, data *metadata.Group) (*metadat
 		return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKObjIDField)
 	}
 
+	if err := g.validateDisplayRules(kit, data, data.DisplayRules); err != nil {
+		return nil, err
+	}
+
 	// create a new group
 	rsp, err := g.clientSet.CoreService().Model().CreateAttributeGroup(kit.Ctx, kit.Header, data.ObjectID,
 		metadata.CreateModelAttributeGroup{Data: *data})
@@ -209,6 +213,23 @@ func (g *group) UpdateObjectGroup(kit *rest.Kit, cond *metadata.UpdateGroupCondi
 	if cond.Data.Index != nil {
 		input.Data.Set(common.BKPropertyGroupIndexField, cond.Data.Index)
 	}
+	if cond.Data.DisplayRules != nil {
+		groupRsp, err := g.clientSet.CoreService().Model().ReadAttributeGroupByCondition(kit.Ctx, kit.Header,
+			metadata.QueryCondition{Condition: mapstr.MapStr{common.BKFieldID: cond.Condition.ID}})
+		if err != nil {
+			blog.Errorf("get attribute group %d failed, err: %v, rid: %s", cond.Condition.ID, err, kit.Rid)
+			return err
+		}
+		if len(groupRsp.Info) == 0 {
+			blog.Errorf("attribute group %d is not exist, rid: %s", cond.Condition.ID, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKFieldID)
+		}
+
+		if err := g.validateDisplayRules(kit, &groupRsp.Info[0], *cond.Data.DisplayRules); err != nil {
+			return err
+		}
+		input.Data.Set(metadata.GroupFieldDisplayRules, *cond.Data.DisplayRules)
+	}
 
 	// generate audit log of object attribute group.
 	audit := auditlog.NewAttributeGroupAuditLog(g.clientSet.CoreService())
@@ -237,6 +258,59 @@ func (g *group) UpdateObjectGroup(kit *rest.Kit, cond *metadata.UpdateGroupCondi
 	return nil
 }
 
+// validateDisplayRules validate the display rules of the attribute group, the referenced attributes must exist in
+// the group's model and can not belong to the group itself, otherwise the group can never be shown once hidden.
+func (g *group) validateDisplayRules(kit *rest.Kit, grp *metadata.Group, rules metadata.GroupDisplayRules) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	if rawErr := rules.Validate(); rawErr.ErrCode != 0 {
+		blog.Errorf("display rules of attribute group %s are invalid, err: %v, rid: %s", grp.GroupID, rawErr, kit.Rid)
+		return rawErr.ToCCError(kit.CCError)
+	}
+
+	propertyIDs := rules.PropertyIDs()
+	cond := mapstr.MapStr{
+		metadata.AttributeFieldObjectID:   grp.ObjectID,
+		metadata.AttributeFieldPropertyID: mapstr.MapStr{common.BKDBIN: propertyIDs},
+	}
+	util.AddModelBizIDCondition(cond, grp.BizID)
+	attrCond := &metadata.QueryCondition{
+		Condition: cond,
+		Fields:    []string{metadata.AttributeFieldPropertyID, metadata.AttributeFieldPropertyGroup},
+		Page:      metadata.BasePage{Limit: common.BKNoLimit},
+	}
+
+	attrRsp, err := g.clientSet.CoreService().Model().ReadModelAttr(kit.Ctx, kit.Header, grp.ObjectID, attrCond)
+	if err != nil {
+		blog.Errorf("get object %s attributes failed, err: %v, rid: %s", grp.ObjectID, err, kit.Rid)
+		return err
+	}
+
+	attrGroups := make(map[string]string, len(attrRsp.Info))
+	for _, attr := range attrRsp.Info {
+		attrGroups[attr.PropertyID] = attr.PropertyGroup
+	}
+
+	for _, propertyID := range propertyIDs {
+		attrGroup, exists := attrGroups[propertyID]
+		if !exists {
+			blog.Errorf("display rule attribute %s of object %s is not exist, rid: %s", propertyID, grp.ObjectID,
+				kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, metadata.GroupFieldDisplayRules)
+		}
+
+		if attrGroup == grp.GroupID {
+			blog.Errorf("display rule attribute %s belongs to the group %s itself, rid: %s", propertyID, grp.GroupID,
+				kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, metadata.GroupFieldDisplayRules)
+		}
+	}
+
+	return nil
+}
+
 // UpdateObjectAttributeGroup Update Object Attribute group
 func (g *group) UpdateObjectAttributeGroup(kit *rest.Kit, conds []metadata.PropertyGroupObjectAtt,
 	modelBizID int64) error {
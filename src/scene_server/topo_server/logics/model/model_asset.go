@@ -0,0 +1,55 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"strings"
+
+	"configcenter/src/apimachinery"
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// validateIconAssetRef validates the icon of the model or classification, if the icon refers to a model asset, the
+// asset must exist and be an icon. the built-in icons are not validated since they are maintained by the ui.
+func validateIconAssetRef(kit *rest.Kit, clientSet apimachinery.ClientSetInterface, icon, field string) error {
+	if !strings.HasPrefix(icon, metadata.ModelAssetRefPrefix) {
+		return nil
+	}
+
+	id, ok := metadata.ParseModelAssetRef(icon)
+	if !ok {
+		blog.Errorf("%s %s is not a valid model asset reference, rid: %s", field, icon, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, field)
+	}
+
+	opt := &metadata.SearchModelAssetOption{
+		IDs:  []int64{id},
+		Type: metadata.ModelAssetTypeIcon,
+		Page: metadata.BasePage{Limit: 1},
+	}
+	result, err := clientSet.CoreService().Model().ReadModelAsset(kit.Ctx, kit.Header, opt)
+	if err != nil {
+		blog.Errorf("get model asset %d failed, err: %v, rid: %s", id, err, kit.Rid)
+		return err
+	}
+
+	if len(result.Info) == 0 {
+		blog.Errorf("%s refers to model icon asset %d which not exists, rid: %s", field, id, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, field)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"sort"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/json"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// ExportModelBundle export the models with their attributes, unique rules, associations and association kinds as
+// a model bundle, the models referred by the associations but not exported are recorded as the dependencies.
+func (o *object) ExportModelBundle(kit *rest.Kit, opt *metadata.ExportModelBundleOption) (*metadata.ModelBundle,
+	error) {
+
+	info, err := o.SearchObjectsWithTotalInfo(kit, opt.ObjectIDs, opt.ExcludedAsstIDs)
+	if err != nil {
+		blog.Errorf("search objects(%v) with total info failed, err: %v, rid: %s", opt.ObjectIDs, err, kit.Rid)
+		return nil, err
+	}
+
+	bundle := &metadata.ModelBundle{
+		ModelBundleHeader: opt.ModelBundleHeader,
+		CreateTime:        time.Now().Unix(),
+		Objects:           make([]metadata.YamlObject, 0, len(info.Object)),
+		AsstKinds:         make([]metadata.AssociationKind, 0, len(info.Asst)),
+	}
+
+	// the total info is arranged for the yaml export, it is converted to the yaml object that is used to import.
+	for objID, item := range info.Object {
+		obj := metadata.YamlObject{}
+		if err := convertModelBundleData(item, &obj); err != nil {
+			blog.Errorf("convert object %s to bundle failed, err: %v, rid: %s", objID, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParseDataFailed, objID)
+		}
+		bundle.Objects = append(bundle.Objects, obj)
+	}
+	sort.Slice(bundle.Objects, func(i, j int) bool { return bundle.Objects[i].ObjectID < bundle.Objects[j].ObjectID })
+
+	if err := convertModelBundleData(info.Asst, &bundle.AsstKinds); err != nil {
+		blog.Errorf("convert association kinds to bundle failed, err: %v, rid: %s", err, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParseDataFailed, "asst_kinds")
+	}
+
+	bundle.Dependencies = bundle.CollectDependencies()
+	return bundle, nil
+}
+
+func convertModelBundleData(data interface{}, result interface{}) error {
+	js, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(js, result)
+}
+
+// CheckModelBundle check whether the model bundle can be installed, it can not be installed if any of its models
+// already exists, or any of its dependencies does not exist.
+func (o *object) CheckModelBundle(kit *rest.Kit, bundle *metadata.ModelBundle) (*metadata.ModelBundleCheckResult,
+	error) {
+
+	dependencies := bundle.CollectDependencies()
+
+	objIDs := make([]string, 0, len(bundle.Objects)+len(dependencies.ObjectIDs))
+	for _, obj := range bundle.Objects {
+		objIDs = append(objIDs, obj.ObjectID)
+	}
+	objIDs = append(objIDs, dependencies.ObjectIDs...)
+
+	query := &metadata.QueryCondition{
+		Condition:      mapstr.MapStr{common.BKObjIDField: mapstr.MapStr{common.BKDBIN: objIDs}},
+		Fields:         []string{common.BKObjIDField},
+		DisableCounter: true,
+	}
+	objs, err := o.clientSet.CoreService().Model().ReadModel(kit.Ctx, kit.Header, query)
+	if err != nil {
+		blog.Errorf("find objects by ids(%v) failed, err: %v, rid: %s", objIDs, err, kit.Rid)
+		return nil, err
+	}
+
+	existObjIDs := make(map[string]struct{}, len(objs.Info))
+	for _, obj := range objs.Info {
+		existObjIDs[obj.ObjectID] = struct{}{}
+	}
+
+	result := &metadata.ModelBundleCheckResult{
+		ModelBundleHeader:  bundle.ModelBundleHeader,
+		ConflictObjectIDs:  make([]string, 0),
+		MissingObjectIDs:   make([]string, 0),
+		MissingAsstKindIDs: make([]string, 0),
+	}
+	for _, obj := range bundle.Objects {
+		if _, exists := existObjIDs[obj.ObjectID]; exists {
+			result.ConflictObjectIDs = append(result.ConflictObjectIDs, obj.ObjectID)
+		}
+	}
+	for _, objID := range dependencies.ObjectIDs {
+		if _, exists := existObjIDs[objID]; !exists {
+			result.MissingObjectIDs = append(result.MissingObjectIDs, objID)
+		}
+	}
+
+	if len(dependencies.AsstKindIDs) > 0 {
+		kindQuery := &metadata.QueryCondition{
+			Condition: mapstr.MapStr{
+				common.AssociationKindIDField: mapstr.MapStr{common.BKDBIN: dependencies.AsstKindIDs},
+			},
+			Fields:         []string{common.AssociationKindIDField},
+			DisableCounter: true,
+		}
+		kinds, err := o.clientSet.CoreService().Association().ReadAssociationType(kit.Ctx, kit.Header, kindQuery)
+		if err != nil {
+			blog.Errorf("find association kinds(%v) failed, err: %v, rid: %s", dependencies.AsstKindIDs, err,
+				kit.Rid)
+			return nil, err
+		}
+
+		existKindIDs := make(map[string]struct{}, len(kinds.Info))
+		for _, kind := range kinds.Info {
+			existKindIDs[kind.AssociationKindID] = struct{}{}
+		}
+		for _, kindID := range dependencies.AsstKindIDs {
+			if _, exists := existKindIDs[kindID]; !exists {
+				result.MissingAsstKindIDs = append(result.MissingAsstKindIDs, kindID)
+			}
+		}
+	}
+
+	result.Installable = len(result.ConflictObjectIDs) == 0 && len(result.MissingObjectIDs) == 0 &&
+		len(result.MissingAsstKindIDs) == 0
+	return result, nil
+}
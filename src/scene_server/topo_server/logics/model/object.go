@@ -45,6 +45,16 @@ type ObjectOperationInterface interface {
 	CreateObjectByImport(kit *rest.Kit, data []metadata.YamlObject) ([]metadata.Object, error)
 	// SearchObjectsWithTotalInfo search object with it's attribute and association
 	SearchObjectsWithTotalInfo(kit *rest.Kit, ids, excludedAsst []int64) (*metadata.TotalObjectInfo, error)
+	// PreviewDeleteObject preview the deletion of the model with the cascade policy
+	PreviewDeleteObject(kit *rest.Kit, id int64, policy metadata.DeletePolicy) (*metadata.DeletePreview, error)
+	// ExportModelBundle export the models as a model bundle which can be installed into another deployment
+	ExportModelBundle(kit *rest.Kit, opt *metadata.ExportModelBundleOption) (*metadata.ModelBundle, error)
+	// CheckModelBundle check whether the model bundle conflicts with the existing models or lacks dependencies
+	CheckModelBundle(kit *rest.Kit, bundle *metadata.ModelBundle) (*metadata.ModelBundleCheckResult, error)
+	// UpdateObjectLifecycle update the lifecycle of the model
+	UpdateObjectLifecycle(kit *rest.Kit, id int64, lifecycle *metadata.SchemaLifecycle) error
+	// SchemaLifecycleWarnings returns the lifecycle warnings of the model and its attributes
+	SchemaLifecycleWarnings(kit *rest.Kit, objID string, fields []string) ([]string, error)
 }
 
 // NewObjectOperation create a new object operation instance
@@ -489,9 +499,10 @@ func (o *object) UpdateObject(kit *rest.Kit, data mapstr.MapStr, id int64) error
 
 	obj.ID = id
 
-	// remove unchangeable fields.
+	// remove unchangeable fields, the lifecycle can only be changed by the lifecycle api.
 	data.Remove(metadata.ModelFieldObjectID)
 	data.Remove(metadata.ModelFieldID)
+	data.Remove(metadata.SchemaFieldLifecycle)
 
 	if err := o.isClassificationValid(kit, data); err != nil {
 		return err
@@ -561,6 +572,12 @@ func (o *object) isValid(kit *rest.Kit, isUpdate bool, data mapstr.MapStr) (*met
 			fmt.Sprintf("'%s' the built-in object id, please use a new one", obj.ObjectID))
 	}
 
+	if data.Exists(metadata.ModelFieldObjIcon) {
+		if err := validateIconAssetRef(kit, o.clientSet, obj.ObjIcon, metadata.ModelFieldObjIcon); err != nil {
+			return nil, err
+		}
+	}
+
 	obj.OwnerID = kit.SupplierAccount
 	return obj, nil
 }
@@ -0,0 +1,150 @@
+/*
+* Tencent is pleased to support the open source community by making 蓝鲸 available.
+* Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+* Licensed under the MIT License (the "License"); you may not use this file except
+* in compliance with the License. You may obtain a copy of the License at
+* http://opensource.org/licenses/MIT
+* Unless required by applicable law or agreed to in writing, software distributed under
+* the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+* either express or implied. See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/auditlog"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// UpdateObjectLifecycle update the lifecycle of the model, the lifecycle decides whether the model's instances can
+// be written, and the users are warned before the model is retired.
+func (o *object) UpdateObjectLifecycle(kit *rest.Kit, id int64, lifecycle *metadata.SchemaLifecycle) error {
+	if rawErr := lifecycle.Validate(); rawErr.ErrCode != 0 {
+		return rawErr.ToCCError(kit.CCError)
+	}
+
+	data := mapstr.MapStr{metadata.SchemaFieldLifecycle: lifecycle}
+	audit := auditlog.NewObjectAuditLog(o.clientSet.CoreService())
+	generateAuditParameter := auditlog.NewGenerateAuditCommonParameter(kit, metadata.AuditUpdate).
+		WithUpdateFields(data)
+	auditLog, err := audit.GenerateAuditLog(generateAuditParameter, id, nil)
+	if err != nil {
+		blog.Errorf("generate audit log failed before update object %d lifecycle, err: %v, rid: %s", id, err,
+			kit.Rid)
+		return err
+	}
+
+	input := metadata.UpdateOption{
+		Condition: mapstr.MapStr{common.BKFieldID: id},
+		Data:      data,
+	}
+	if _, err := o.clientSet.CoreService().Model().UpdateModel(kit.Ctx, kit.Header, &input); err != nil {
+		blog.Errorf("update object %d lifecycle failed, err: %v, rid: %s", id, err, kit.Rid)
+		return err
+	}
+
+	if err := audit.SaveAuditLog(kit, *auditLog); err != nil {
+		blog.Errorf("update object %d lifecycle success, but save audit log failed, err: %v, rid: %s", id, err,
+			kit.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// SchemaLifecycleWarnings returns the warnings of the model and its attributes that are not active or are scheduled
+// to be retired, only the attributes in the fields are checked if the fields are set.
+func (o *object) SchemaLifecycleWarnings(kit *rest.Kit, objID string, fields []string) ([]string, error) {
+	now := time.Now()
+	warnings := make([]string, 0)
+
+	obj, err := o.FindSingleObject(kit, []string{common.BKObjIDField, metadata.SchemaFieldLifecycle}, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	if warning := obj.Lifecycle.Warning(fmt.Sprintf("model %q", objID), now); len(warning) > 0 {
+		warnings = append(warnings, warning)
+	}
+
+	cond := mapstr.MapStr{
+		common.BKObjIDField:           objID,
+		metadata.SchemaFieldLifecycle: mapstr.MapStr{common.BKDBExists: true},
+	}
+	if len(fields) > 0 {
+		cond[common.BKPropertyIDField] = mapstr.MapStr{common.BKDBIN: fields}
+	}
+	queryCond := &metadata.QueryCondition{
+		Condition:      cond,
+		Fields:         []string{common.BKPropertyIDField, metadata.SchemaFieldLifecycle},
+		Page:           metadata.BasePage{Limit: common.BKNoLimit},
+		DisableCounter: true,
+	}
+	attrs, err := o.clientSet.CoreService().Model().ReadModelAttrByCondition(kit.Ctx, kit.Header, queryCond)
+	if err != nil {
+		blog.Errorf("find object %s attributes lifecycle failed, err: %v, rid: %s", objID, err, kit.Rid)
+		return nil, err
+	}
+
+	for _, attr := range attrs.Info {
+		subject := fmt.Sprintf("attribute %q of model %q", attr.PropertyID, objID)
+		if warning := attr.Lifecycle.Warning(subject, now); len(warning) > 0 {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return warnings, nil
+}
+
+// UpdateAttributeLifecycle update the lifecycle of the attribute, the lifecycle decides whether the attribute's
+// values can be written, and the users are warned before the attribute is retired.
+func (a *attribute) UpdateAttributeLifecycle(kit *rest.Kit, id int64, lifecycle *metadata.SchemaLifecycle) error {
+	if rawErr := lifecycle.Validate(); rawErr.ErrCode != 0 {
+		return rawErr.ToCCError(kit.CCError)
+	}
+
+	cond := mapstr.MapStr{common.BKFieldID: id}
+	queryCond := &metadata.QueryCondition{Condition: cond, DisableCounter: true}
+	attrs, err := a.clientSet.CoreService().Model().ReadModelAttrByCondition(kit.Ctx, kit.Header, queryCond)
+	if err != nil {
+		blog.Errorf("find attribute %d failed, err: %v, rid: %s", id, err, kit.Rid)
+		return err
+	}
+
+	if len(attrs.Info) == 0 {
+		blog.Errorf("attribute %d is not exist, rid: %s", id, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKFieldID)
+	}
+
+	data := mapstr.MapStr{metadata.SchemaFieldLifecycle: lifecycle}
+	audit := auditlog.NewObjectAttributeAuditLog(a.clientSet.CoreService())
+	generateAuditParameter := auditlog.NewGenerateAuditCommonParameter(kit, metadata.AuditUpdate).WithUpdateFields(data)
+	auditLog, err := audit.GenerateAuditLog(generateAuditParameter, id, &attrs.Info[0])
+	if err != nil {
+		blog.Errorf("generate audit log failed before update attribute %d lifecycle, err: %v, rid: %s", id, err,
+			kit.Rid)
+		return err
+	}
+
+	input := metadata.UpdateOption{Condition: cond, Data: data}
+	if _, err := a.clientSet.CoreService().Model().UpdateModelAttrsByCondition(kit.Ctx, kit.Header, &input); err != nil {
+		blog.Errorf("update attribute %d lifecycle failed, err: %v, rid: %s", id, err, kit.Rid)
+		return err
+	}
+
+	if err := audit.SaveAuditLog(kit, *auditLog); err != nil {
+		blog.Errorf("update attribute %d lifecycle success, but save audit log failed, err: %v, rid: %s", id, err,
+			kit.Rid)
+		return err
+	}
+
+	return nil
+}
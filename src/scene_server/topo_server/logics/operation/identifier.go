@@ -13,6 +13,8 @@
 package operation
 
 import (
+	"sort"
+
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -26,6 +28,9 @@ type IdentifierOperationInterface interface {
 	// SearchIdentifier search identifier by ip param
 	SearchIdentifier(kit *rest.Kit, param *metadata.SearchIdentifierParam) (
 		*metadata.SearchHostIdentifierData, error)
+	// SnapshotHostIdentifier get a page of the identifiers of the hosts under the business, sets or modules
+	SnapshotHostIdentifier(kit *rest.Kit, bizID int64, option *metadata.HostIdentifierSnapshotOption) (
+		*metadata.HostIdentifierSnapshotResult, error)
 }
 
 // NewIdentifier create a new identifier operation instance
@@ -111,3 +116,56 @@ func (g *identifier) SearchIdentifier(kit *rest.Kit, param *metadata.SearchIdent
 
 	return rsp, nil
 }
+
+// SnapshotHostIdentifier get a page of the identifiers of the hosts under the business, sets or modules, the hosts
+// after the cursor are found by the host module relations in the order of the host id.
+func (g *identifier) SnapshotHostIdentifier(kit *rest.Kit, bizID int64,
+	option *metadata.HostIdentifierSnapshotOption) (*metadata.HostIdentifierSnapshotResult, error) {
+
+	// a host may have relations with several modules, so one more relation than the limit is fetched to decide
+	// whether there are more hosts, the host ids are distinct after the duplicate ones are removed.
+	relReq := &metadata.HostModuleRelationRequest{
+		ApplicationID: bizID,
+		SetIDArr:      option.SetIDs,
+		ModuleIDArr:   option.ModuleIDs,
+		AfterHostID:   option.Cursor,
+		Page:          metadata.BasePage{Limit: option.Limit + 1, Sort: common.BKHostIDField},
+		Fields:        []string{common.BKHostIDField},
+	}
+	relRsp, err := g.clientSet.CoreService().Host().GetHostModuleRelation(kit.Ctx, kit.Header, relReq)
+	if err != nil {
+		blog.Errorf("get host module relation failed, err: %v, req: %#v, rid: %s", err, relReq, kit.Rid)
+		return nil, err
+	}
+
+	hostIDs := make([]int64, 0, len(relRsp.Info))
+	for _, relation := range relRsp.Info {
+		if len(hostIDs) == 0 || hostIDs[len(hostIDs)-1] != relation.HostID {
+			hostIDs = append(hostIDs, relation.HostID)
+		}
+	}
+
+	result := &metadata.HostIdentifierSnapshotResult{
+		Cursor:  option.Cursor,
+		HasMore: len(relRsp.Info) > option.Limit,
+		Info:    make([]metadata.HostIdentifier, 0),
+	}
+	if len(hostIDs) > option.Limit {
+		hostIDs = hostIDs[:option.Limit]
+	}
+	if len(hostIDs) == 0 {
+		return result, nil
+	}
+
+	queryHostIdentifier := &metadata.SearchHostIdentifierParam{HostIDs: hostIDs}
+	rsp, err := g.clientSet.CoreService().Host().FindIdentifier(kit.Ctx, kit.Header, queryHostIdentifier)
+	if err != nil {
+		blog.Errorf("search identifier failed, err: %v, ids: %v, rid: %s", err, hostIDs, kit.Rid)
+		return nil, err
+	}
+
+	sort.Slice(rsp.Info, func(i, j int) bool { return rsp.Info[i].HostID < rsp.Info[j].HostID })
+	result.Cursor = hostIDs[len(hostIDs)-1]
+	result.Info = rsp.Info
+	return result, nil
+}
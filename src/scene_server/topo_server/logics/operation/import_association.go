@@ -256,6 +256,12 @@ func (ia *importAssociation) importAssociation() {
 			continue
 		}
 
+		if asstInfo.AsstKindID != "" && asstInfo.AsstKindID != asst.AsstKindID {
+			ia.parseImportDataErr[idx] = ia.lang.Languagef("import_association_kind_not_match",
+				asstInfo.ObjectAsstID, asst.AsstKindID, asstInfo.AsstKindID)
+			continue
+		}
+
 		srcInstID, dstInstID, err := ia.getTargetIndexSrcDstInstID(idx, asst, asstInfo)
 		if err != nil {
 			continue
@@ -335,6 +341,10 @@ func (ia *importAssociation) checkExcelAssociationOperate(idx int, srcInstID, ds
 			return false
 		}
 
+		if !ia.checkDstAssociationMapping(idx, dstInstID, asst) {
+			return false
+		}
+
 		ia.addSrcAssociation(idx, asst.AssociationName, srcInstID, dstInstID)
 		return true
 
@@ -734,6 +744,42 @@ func (ia *importAssociation) isExistInstAsst(idx int, cond mapstr.MapStr, dstIns
 	return true, nil
 }
 
+// checkDstAssociationMapping checks that the target instance is not associated by the other source instances if
+// the association mapping is 1:1 or 1:n, the associations added by the previous rows are checked too since they
+// are created before the row is imported.
+func (ia *importAssociation) checkDstAssociationMapping(idx int, dstInstID int64, asst *metadata.Association) bool {
+	if asst.Mapping != metadata.OneToOneMapping && asst.Mapping != metadata.OneToManyMapping {
+		return true
+	}
+
+	queryCond := &metadata.InstAsstQueryCondition{
+		Cond: metadata.QueryCondition{
+			Condition: mapstr.MapStr{
+				common.AssociationObjAsstIDField: asst.AssociationName,
+				common.AssociatedObjectIDField:   asst.AsstObjID,
+				common.BKAsstInstIDField:         dstInstID,
+			},
+			Page: metadata.BasePage{Limit: 1},
+		},
+		ObjID: asst.AsstObjID,
+	}
+	rsp, err := ia.cli.clientSet.CoreService().Association().ReadInstAssociation(ia.kit.Ctx, ia.kit.Header, queryCond)
+	if err != nil {
+		blog.Errorf("read %s instance %d association failed, err: %v, rid: %s", asst.AsstObjID, dstInstID, err,
+			ia.kit.Rid)
+		ia.parseImportDataErr[idx] = err.Error()
+		return false
+	}
+
+	if len(rsp.Info) > 0 {
+		ia.parseImportDataErr[idx] = ia.lang.Languagef("import_association_mapping_conflict", asst.AssociationName,
+			asst.Mapping)
+		return false
+	}
+
+	return true
+}
+
 func (ia *importAssociation) getAssociationObjectInstIDByPrimaryKey(objID, primary string) (int64, error) {
 
 	primaryArr := strings.Split(primary, common.ExcelAsstPrimaryKeySplitChar)
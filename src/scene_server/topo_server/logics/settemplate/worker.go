@@ -3,6 +3,8 @@ package settemplate
 import (
 	"net/http"
 	"reflect"
+	"sort"
+	"strings"
 
 	"configcenter/src/apimachinery"
 	"configcenter/src/common"
@@ -237,6 +239,13 @@ func (bw BackendWorker) syncSetAttributes(kit *rest.Kit, bizID, setTemplateID, s
 	if cErr != nil {
 		return cErr
 	}
+
+	// 用集群绑定的模板变量替换属性值中的变量占位符
+	setTemplateAttrValueMap, cErr = bw.resolveSetTemplateAttrValues(kit, bizID, setID, setTemplateAttrValueMap)
+	if cErr != nil {
+		return cErr
+	}
+
 	// 2、从cc_ObjAttDes 中通过上面的属性id获取对应的 bk_property_id
 	propertyIDs, attrIdPropertyMap, cErr := bw.getSetAttrIDAndPropertyID(kit, attrIDs)
 	if cErr != nil {
@@ -254,3 +263,38 @@ func (bw BackendWorker) syncSetAttributes(kit *rest.Kit, bizID, setTemplateID, s
 	}
 	return nil
 }
+
+// resolveSetTemplateAttrValues 用集群绑定的模板变量替换集群模板属性值中的变量占位符，所有引用的变量都必须已绑定
+func (bw BackendWorker) resolveSetTemplateAttrValues(kit *rest.Kit, bizID, setID int64,
+	setTemplateAttrValueMap map[int64]interface{}) (map[int64]interface{}, errors.CCErrorCoder) {
+
+	opt := &metadata.TemplateVariableOption{BizID: bizID, ObjID: common.BKInnerObjIDSet, InstIDs: []int64{setID}}
+	variables, err := bw.ClientSet.CoreService().Process().ListTemplateVariable(kit.Ctx, kit.Header, opt)
+	if err != nil {
+		blog.Errorf("list template variables failed, opt: %#v, err: %v, rid: %s", opt, err, kit.Rid)
+		return nil, err
+	}
+
+	var setVariables map[string]string
+	if len(variables) > 0 {
+		setVariables = variables[0].Variables
+	}
+
+	resolved := make(map[int64]interface{}, len(setTemplateAttrValueMap))
+	missing := make([]string, 0)
+	for attrID, value := range setTemplateAttrValueMap {
+		var attrMissing []string
+		resolved[attrID], attrMissing = metadata.ResolveTemplateVariables(value, setVariables)
+		missing = append(missing, attrMissing...)
+	}
+
+	if len(missing) > 0 {
+		missing = util.StrArrayUnique(missing)
+		sort.Strings(missing)
+		blog.Errorf("template variables %v are not bound on set %d, rid: %s", missing, setID, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommTemplateVariableNotBound, strings.Join(missing, ","),
+			common.BKInnerObjIDSet, setID)
+	}
+
+	return resolved, nil
+}
@@ -99,6 +99,28 @@ func (s *Service) SearchMainLineObjectTopo(ctx *rest.Contexts) {
 	ctx.RespEntity(resp)
 }
 
+// FindTopoLimitUsage find the usage of the business topology depth and child node limits, the businesses approaching
+// the child node limits are reported so that their topologies can be adjusted in advance
+func (s *Service) FindTopoLimitUsage(ctx *rest.Contexts) {
+	opt := new(metadata.TopoLimitUsageOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := s.Logics.InstOperation().FindTopoLimitUsage(ctx.Kit, opt)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	ctx.RespEntity(result)
+}
+
 // SearchObjectByClassificationID search the object by classification ID
 func (s *Service) SearchObjectByClassificationID(ctx *rest.Contexts) {
 
@@ -551,6 +573,35 @@ func (s *Service) SearchAssociationType(ctx *rest.Contexts) {
 		return
 	}
 
+	if request.WithConstraints {
+		kindIDs := make([]string, len(ret.Info))
+		for idx, kind := range ret.Info {
+			kindIDs[idx] = kind.AssociationKindID
+		}
+
+		assts, err := s.Logics.AssociationOperation().SearchObjectAssocWithAssocKindList(ctx.Kit, kindIDs)
+		if err != nil {
+			ctx.RespAutoError(err)
+			return
+		}
+
+		ret.Constraints = make(map[string][]metadata.AssociationKindConstraint, len(kindIDs))
+		for _, kindID := range kindIDs {
+			ret.Constraints[kindID] = make([]metadata.AssociationKindConstraint, 0)
+		}
+		for _, detail := range assts.Associations {
+			for _, asst := range detail.Associations {
+				ret.Constraints[detail.AssociationKindID] = append(ret.Constraints[detail.AssociationKindID],
+					metadata.AssociationKindConstraint{
+						ObjectAsstID: asst.AssociationName,
+						ObjectID:     asst.ObjectID,
+						AsstObjID:    asst.AsstObjID,
+						Mapping:      asst.Mapping,
+					})
+			}
+		}
+	}
+
 	ctx.RespEntity(ret)
 }
 
@@ -748,6 +799,81 @@ func (s *Service) CountInstanceAssociations(ctx *rest.Contexts) {
 	ctx.RespEntity(result)
 }
 
+// SearchInstAsstEdges searches the instance associations of a model association by page, the edges can be sorted and
+// filtered by the attributes of their source and target instances, which is used for the high-degree instances.
+func (s *Service) SearchInstAsstEdges(ctx *rest.Contexts) {
+	opt := new(metadata.InstAsstEdgeSearchOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+
+	ctx.SetReadPreference(common.SecondaryPreferredMode)
+	result, err := s.Logics.InstAssociationOperation().SearchInstAsstEdges(ctx.Kit, objID, opt)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(mapstr.MapStr{"info": result})
+}
+
+// CountInstAsstEdges counts the instance associations of a model association with the same filters as the search.
+func (s *Service) CountInstAsstEdges(ctx *rest.Contexts) {
+	filter := new(metadata.InstAsstEdgeFilter)
+	if err := ctx.DecodeInto(filter); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := filter.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+
+	ctx.SetReadPreference(common.SecondaryPreferredMode)
+	result, err := s.Logics.InstAssociationOperation().CountInstAsstEdges(ctx.Kit, objID, filter)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// ScanAsstMappingViolation scans the existing instance associations of the model association that violate its
+// mapping, e.g. the ones created before the mapping is enforced.
+func (s *Service) ScanAsstMappingViolation(ctx *rest.Contexts) {
+	opt := new(metadata.ScanAsstMappingViolationOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := s.Engine.CoreAPI.CoreService().Association().ScanAsstMappingViolation(ctx.Kit.Ctx,
+		ctx.Kit.Header, opt)
+	if err != nil {
+		blog.Errorf("scan association %s mapping violation failed, err: %v, rid: %s", opt.ObjectAsstID, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
 // SearchAssociationInst search instance association
 func (s *Service) SearchAssociationInst(ctx *rest.Contexts) {
 	request := &metadata.SearchAssociationInstRequest{}
@@ -0,0 +1,105 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// SaveAttrGroupDelegation delegate the edit right of the model's attribute group to the role, once delegated, the
+// group's attributes of the model's instances can only be changed by the users of the delegated roles
+func (s *Service) SaveAttrGroupDelegation(ctx *rest.Contexts) {
+	delegation := new(metadata.AttrGroupDelegation)
+	if err := ctx.DecodeInto(delegation); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	delegation.ObjID = ctx.Request.PathParameter(common.BKObjIDField)
+	delegation.Role = strings.TrimSpace(delegation.Role)
+
+	if rawErr := delegation.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := s.Logics.AttributeOperation().SaveAttrGroupDelegation(ctx.Kit, delegation)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// FindAttrGroupDelegation find the attribute group delegations of the model
+func (s *Service) FindAttrGroupDelegation(ctx *rest.Contexts) {
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+
+	result, err := s.Logics.AttributeOperation().FindAttrGroupDelegation(ctx.Kit, objID)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// DeleteAttrGroupDelegation delete the delegation of the model's attribute group to the role
+func (s *Service) DeleteAttrGroupDelegation(ctx *rest.Contexts) {
+	key := new(metadata.AttrGroupDelegationKey)
+	if err := ctx.DecodeInto(key); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	key.ObjID = ctx.Request.PathParameter(common.BKObjIDField)
+
+	if rawErr := key.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	if err := s.Logics.AttributeOperation().DeleteAttrGroupDelegation(ctx.Kit, key); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// FindAttributeEditSchema find the model's attributes with the current user's edit right regarding the attribute
+// group delegations, which is used by the ui to disable the fields that the user can not edit in the instance forms
+func (s *Service) FindAttributeEditSchema(ctx *rest.Contexts) {
+	opt := new(metadata.AttributeEditSchemaOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if opt.BizID < 0 {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField))
+		return
+	}
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+
+	result, err := s.Logics.AttributeOperation().FindAttributeEditSchema(ctx.Kit, opt.BizID, objID)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
@@ -0,0 +1,45 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// FindAttributeUsage find the sampled read and write usages of the model's attributes, it is used by the admins to
+// find out the unused attributes before deprecating them.
+func (s *Service) FindAttributeUsage(ctx *rest.Contexts) {
+	opt := new(metadata.SearchAttributeUsageOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+	result, err := s.Engine.CoreAPI.CoreService().Model().ReadAttributeUsage(ctx.Kit.Ctx, ctx.Kit.Header, objID, opt)
+	if err != nil {
+		blog.Errorf("find object %s attribute usage failed, err: %v, rid: %s", objID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
@@ -55,7 +55,8 @@ func (s *Service) SearchAuditList(ctx *rest.Contexts) {
 
 	// the front-end table display fields
 	fields := []string{common.BKFieldID, common.BKUser, common.BKResourceTypeField, common.BKActionField,
-		common.BKOperationTimeField, common.BKAppIDField, common.BKResourceIDField, common.BKResourceNameField}
+		common.BKOperationTimeField, common.BKAppIDField, common.BKResourceIDField, common.BKResourceNameField,
+		common.BKChangeSetIDField}
 
 	cond := mapstr.MapStr{}
 	condition := query.Condition
@@ -122,6 +123,10 @@ func (s *Service) SearchAuditList(ctx *rest.Contexts) {
 		cond[common.BKResourceIDField] = condition.ResourceID
 	}
 
+	if condition.ChangeSetID != "" {
+		cond[common.BKChangeSetIDField] = condition.ChangeSetID
+	}
+
 	if condition.ObjID != "" {
 		switch condition.ResourceType {
 		case metadata.ModelInstanceRes:
@@ -212,6 +217,44 @@ func (s *Service) SearchAuditDetail(ctx *rest.Contexts) {
 	ctx.RespEntity(rsp.Info)
 }
 
+// SearchAuditChangeSet search the audit logs of a change set in the order they are produced, so that all the effects
+// of a single operation like a host transfer or a template sync can be seen as one unit
+func (s *Service) SearchAuditChangeSet(ctx *rest.Contexts) {
+	query := metadata.AuditChangeSetQueryInput{}
+	if err := ctx.DecodeInto(&query); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := query.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	auditQuery := metadata.QueryCondition{
+		Condition: mapstr.MapStr{common.BKChangeSetIDField: query.ChangeSetID},
+		Page: metadata.BasePage{
+			Sort:  common.BKFieldID,
+			Limit: query.Page.Limit,
+			Start: query.Page.Start,
+		},
+	}
+
+	ctx.SetReadPreference(common.SecondaryPreferredMode)
+	rsp, err := s.Engine.CoreAPI.CoreService().Audit().SearchAuditLog(ctx.Kit.Ctx, ctx.Kit.Header, auditQuery)
+	if err != nil {
+		blog.Errorf("search audit change set %s failed, err: %v, rid: %s", query.ChangeSetID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(metadata.AuditChangeSet{
+		ChangeSetID: query.ChangeSetID,
+		Count:       rsp.Count,
+		Info:        rsp.Info,
+	})
+}
+
 func parseOperationTimeCondition(kit *rest.Kit, operationTime metadata.OperationTimeCondition) (map[string]interface{}, error) {
 	timeCond := make(map[string]interface{})
 
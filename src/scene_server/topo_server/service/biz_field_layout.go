@@ -0,0 +1,92 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// SaveBizFieldLayout save the business's field layout of the model, which overrides the visibility, order and group
+// of the attributes in the forms of the business without changing the model
+func (s *Service) SaveBizFieldLayout(ctx *rest.Contexts) {
+	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
+	if err != nil || bizID <= 0 {
+		blog.Errorf("path parameter bk_biz_id invalid, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField))
+		return
+	}
+
+	layout := new(metadata.BizFieldLayout)
+	if err := ctx.DecodeInto(layout); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	layout.BizID = bizID
+	layout.ObjID = ctx.Request.PathParameter(common.BKObjIDField)
+
+	if rawErr := layout.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := s.Logics.AttributeOperation().SaveBizFieldLayout(ctx.Kit, layout)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// FindBizFieldLayout find the business's field layout of the model, and the attributes in the business's display
+// order, which is used by the ui to render the host and instance forms
+func (s *Service) FindBizFieldLayout(ctx *rest.Contexts) {
+	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
+	if err != nil || bizID <= 0 {
+		blog.Errorf("path parameter bk_biz_id invalid, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField))
+		return
+	}
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+
+	result, err := s.Logics.AttributeOperation().FindBizFieldLayout(ctx.Kit, bizID, objID)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// DeleteBizFieldLayout delete the business's field layout of the model, the model's layout is used afterwards
+func (s *Service) DeleteBizFieldLayout(ctx *rest.Contexts) {
+	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
+	if err != nil || bizID <= 0 {
+		blog.Errorf("path parameter bk_biz_id invalid, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField))
+		return
+	}
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+
+	if err := s.Logics.AttributeOperation().DeleteBizFieldLayout(ctx.Kit, bizID, objID); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
@@ -0,0 +1,156 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// SaveBizRoleAssignment assign the users to the business role of a topo node in the business
+func (s *Service) SaveBizRoleAssignment(ctx *rest.Contexts) {
+	bizID, ok := parseBizRoleBizID(ctx)
+	if !ok {
+		return
+	}
+
+	assignment := new(metadata.BizRoleAssignment)
+	if err := ctx.DecodeInto(assignment); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	assignment.BizID = bizID
+
+	if rawErr := assignment.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := s.Engine.CoreAPI.CoreService().Operation().SaveBizRoleAssignment(ctx.Kit.Ctx, ctx.Kit.Header,
+		assignment)
+	if err != nil {
+		blog.Errorf("save biz role assignment failed, data: %#v, err: %v, rid: %s", assignment, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// SearchBizRoleAssignment search the business role assignments of the topo nodes in the business
+func (s *Service) SearchBizRoleAssignment(ctx *rest.Contexts) {
+	bizID, ok := parseBizRoleBizID(ctx)
+	if !ok {
+		return
+	}
+
+	opt := new(metadata.ListBizRoleAssignmentOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	opt.BizID = bizID
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := s.Engine.CoreAPI.CoreService().Operation().SearchBizRoleAssignment(ctx.Kit.Ctx, ctx.Kit.Header,
+		opt)
+	if err != nil {
+		blog.Errorf("search biz role assignment failed, opt: %#v, err: %v, rid: %s", opt, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// DeleteBizRoleAssignment delete the business role assignment of a topo node, the node inherits its parent's
+// assignment afterwards
+func (s *Service) DeleteBizRoleAssignment(ctx *rest.Contexts) {
+	bizID, ok := parseBizRoleBizID(ctx)
+	if !ok {
+		return
+	}
+
+	opt := new(metadata.DeleteBizRoleAssignmentOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	opt.BizID = bizID
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	err := s.Engine.CoreAPI.CoreService().Operation().DeleteBizRoleAssignment(ctx.Kit.Ctx, ctx.Kit.Header, opt)
+	if err != nil {
+		blog.Errorf("delete biz role assignment failed, opt: %#v, err: %v, rid: %s", opt, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// ResolveBizRoleReceivers resolve who should be notified for a module or the business by the business roles, it is
+// used by the notification systems to route the notifications of the business.
+func (s *Service) ResolveBizRoleReceivers(ctx *rest.Contexts) {
+	bizID, ok := parseBizRoleBizID(ctx)
+	if !ok {
+		return
+	}
+
+	opt := new(metadata.ResolveBizRoleReceiverOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	opt.BizID = bizID
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := s.Engine.CoreAPI.CoreService().Operation().ResolveBizRoleReceivers(ctx.Kit.Ctx, ctx.Kit.Header,
+		opt)
+	if err != nil {
+		blog.Errorf("resolve biz role receivers failed, opt: %#v, err: %v, rid: %s", opt, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// parseBizRoleBizID parse the business id in the url, responds the error if it is invalid
+func parseBizRoleBizID(ctx *rest.Contexts) (int64, bool) {
+	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
+	if err != nil || bizID <= 0 {
+		blog.Errorf("parse biz id %s failed, err: %v, rid: %s", ctx.Request.PathParameter(common.BKAppIDField),
+			err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKAppIDField))
+		return 0, false
+	}
+
+	return bizID, true
+}
@@ -0,0 +1,145 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/ac"
+	"configcenter/src/ac/meta"
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// BindExternalIDs bind the ids of the instance in the external source systems to the instance, so that the
+// integrations can look up the instance by the id in their source.
+func (s *Service) BindExternalIDs(ctx *rest.Contexts) {
+	opt := new(metadata.BindExternalIDOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	objID, instID, err := s.parseExternalIDInst(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	opt.InstID = instID
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	if err := s.authorizeExternalIDInst(ctx, objID, instID); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	err = s.Engine.CoreAPI.CoreService().Instance().BindExternalIDs(ctx.Kit.Ctx, ctx.Kit.Header, objID, opt)
+	if err != nil {
+		blog.Errorf("bind external ids to %s instance %d failed, err: %v, rid: %s", objID, instID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// UnbindExternalIDs unbind the external ids of the sources from the instance
+func (s *Service) UnbindExternalIDs(ctx *rest.Contexts) {
+	opt := new(metadata.UnbindExternalIDOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	objID, instID, err := s.parseExternalIDInst(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	opt.InstID = instID
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	if err := s.authorizeExternalIDInst(ctx, objID, instID); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	err = s.Engine.CoreAPI.CoreService().Instance().UnbindExternalIDs(ctx.Kit.Ctx, ctx.Kit.Header, objID, opt)
+	if err != nil {
+		blog.Errorf("unbind external ids from %s instance %d failed, err: %v, rid: %s", objID, instID, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// SearchExternalIDs search the external ids of the instances, or look up the instances by the external ids of a
+// source, the instances that are not bound to any external id are not returned.
+func (s *Service) SearchExternalIDs(ctx *rest.Contexts) {
+	opt := new(metadata.SearchExternalIDOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+	result, err := s.Engine.CoreAPI.CoreService().Instance().SearchExternalIDs(ctx.Kit.Ctx, ctx.Kit.Header, objID, opt)
+	if err != nil {
+		blog.Errorf("search %s external ids failed, err: %v, opt: %#v, rid: %s", objID, err, opt, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+func (s *Service) parseExternalIDInst(ctx *rest.Contexts) (string, int64, error) {
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+	instID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKInstIDField), 10, 64)
+	if err != nil || instID <= 0 {
+		blog.Errorf("parse inst id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		return "", 0, ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKInstIDField)
+	}
+	return objID, instID, nil
+}
+
+// authorizeExternalIDInst the external ids are a part of the instance, binding them requires the update permission
+func (s *Service) authorizeExternalIDInst(ctx *rest.Contexts, objID string, instID int64) error {
+	err := s.AuthManager.AuthorizeByInstanceID(ctx.Kit.Ctx, ctx.Kit.Header, meta.Update, objID, instID)
+	if err == nil {
+		return nil
+	}
+
+	blog.Errorf("authorize update %s instance %d failed, err: %v, rid: %s", objID, instID, err, ctx.Kit.Rid)
+	if err == ac.NoAuthorizeError {
+		return ctx.Kit.CCError.CCError(common.CCErrCommAuthNotHavePermission)
+	}
+	return ctx.Kit.CCError.CCError(common.CCErrCommAuthorizeFailed)
+}
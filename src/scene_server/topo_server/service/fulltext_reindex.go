@@ -0,0 +1,93 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"sort"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+	"configcenter/src/thirdparty/elasticsearch"
+)
+
+// StartFullTextReindex rebuild the fulltext index in background without downtime, and switch the alias to the new
+// index after it catches up with the old one.
+func (s *Service) StartFullTextReindex(ctx *rest.Contexts) {
+	if s.Es.Reindexer == nil {
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrorTopoFullTextClientNotInitialized))
+		return
+	}
+
+	opt := new(metadata.FullTextReindexOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	task, err := s.Es.Reindexer.Start(ctx.Kit.Ctx, opt, ctx.Kit.Rid)
+	if err != nil {
+		blog.Errorf("start reindex %s failed, err: %v, rid: %s", opt.Index, err, ctx.Kit.Rid)
+		if err == elasticsearch.ErrReindexRunning {
+			ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrTopoFullTextReindexRunning, opt.Index))
+			return
+		}
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrTopoFullTextReindexFailed, err.Error()))
+		return
+	}
+
+	ctx.RespEntity(task)
+}
+
+// FindFullTextReindexTasks find the progress of the fulltext reindex tasks, the latest task first.
+func (s *Service) FindFullTextReindexTasks(ctx *rest.Contexts) {
+	if s.Es.Reindexer == nil {
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrorTopoFullTextClientNotInitialized))
+		return
+	}
+
+	tasks := s.Es.Reindexer.List()
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreateTime.After(tasks[j].CreateTime)
+	})
+
+	ctx.RespEntity(tasks)
+}
+
+// CancelFullTextReindex cancel the running fulltext reindex task, the new index is deleted and the search keeps
+// using the old index.
+func (s *Service) CancelFullTextReindex(ctx *rest.Contexts) {
+	if s.Es.Reindexer == nil {
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrorTopoFullTextClientNotInitialized))
+		return
+	}
+
+	taskID := ctx.Request.PathParameter("task_id")
+	if err := s.Es.Reindexer.Cancel(taskID); err != nil {
+		blog.Errorf("cancel reindex task %s failed, err: %v, rid: %s", taskID, err, ctx.Kit.Rid)
+		if err == elasticsearch.ErrReindexTaskNotFound {
+			ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrTopoFullTextReindexTaskNotFound, taskID))
+			return
+		}
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrTopoFullTextReindexFailed, err.Error()))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
@@ -549,38 +549,31 @@ func (s *Service) fullTextMetadata(ctx *rest.Contexts, hits []*elastic.SearchHit
 	return searchResults, nil
 }
 
-// initCommonSearchFilter init common search filter cond
-func initCommonSearchFilter(field string, ids []int64) *metadata.CommonSearchFilter {
-	return &metadata.CommonSearchFilter{
-		Conditions: &querybuilder.QueryFilter{
-			Rule: &querybuilder.AtomRule{
-				Field:    field,
-				Operator: querybuilder.OperatorIn,
-				Value:    ids,
-			},
-		},
-		Page: metadata.BasePage{Start: 0, Limit: common.BKMaxInstanceLimit},
-	}
-}
-
 // fullTextSearchForInstanceCond composition query instance condition.
-func fullTextSearchForInstanceCond(objectID string, ids []int64) *metadata.CommonSearchFilter {
-	input := &metadata.CommonSearchFilter{}
+func fullTextSearchForInstanceCond(objectID string, ids []int64) (*metadata.CommonSearchFilter, error) {
+	field := common.BKInstIDField
 	switch objectID {
 	case common.BKInnerObjIDBizSet:
-		input = initCommonSearchFilter(common.BKBizSetIDField, ids)
+		field = common.BKBizSetIDField
 	case common.BKInnerObjIDApp:
-		input = initCommonSearchFilter(common.BKAppIDField, ids)
+		field = common.BKAppIDField
 	case common.BKInnerObjIDSet:
-		input = initCommonSearchFilter(common.BKSetIDField, ids)
+		field = common.BKSetIDField
 	case common.BKInnerObjIDModule:
-		input = initCommonSearchFilter(common.BKModuleIDField, ids)
+		field = common.BKModuleIDField
 	case common.BKInnerObjIDHost:
-		input = initCommonSearchFilter(common.BKHostIDField, ids)
-	default:
-		input = initCommonSearchFilter(common.BKInstIDField, ids)
+		field = common.BKHostIDField
+	}
+
+	filter, err := querybuilder.NewBuilder().Field(field).In(ids).Build()
+	if err != nil {
+		return nil, err
 	}
-	return input
+
+	return &metadata.CommonSearchFilter{
+		Conditions: filter,
+		Page:       metadata.BasePage{Start: 0, Limit: common.BKMaxInstanceLimit},
+	}, nil
 }
 
 // fullTextSearchForInstance search instance result.
@@ -594,7 +587,6 @@ func (s *Service) fullTextSearchForInstance(ctx *rest.Contexts, instMetadataCond
 	}
 
 	// query metadata instance.
-	input := &metadata.CommonSearchFilter{}
 	var (
 		wg       sync.WaitGroup
 		rwLock   sync.RWMutex
@@ -610,7 +602,14 @@ func (s *Service) fullTextSearchForInstance(ctx *rest.Contexts, instMetadataCond
 				<-pipeline
 			}()
 
-			input = fullTextSearchForInstanceCond(objectID, ids)
+			input, err := fullTextSearchForInstanceCond(objectID, ids)
+			if err != nil {
+				blog.Errorf("build obj instances cond fail, objID: %s, ids: %v, err: %v, rid: %s", objectID, ids, err,
+					ctx.Kit.Rid)
+				firstErr = err
+				return
+			}
+
 			// search object instances.
 			result, err := s.Logics.InstOperation().SearchObjectInstances(ctx.Kit, objectID, input)
 			if err != nil {
@@ -13,6 +13,8 @@
 package service
 
 import (
+	"strconv"
+
 	"configcenter/src/common"
 	"configcenter/src/common/http/rest"
 	"configcenter/src/common/metadata"
@@ -37,3 +39,32 @@ func (s *Service) SearchIdentifier(ctx *rest.Contexts) {
 	}
 	ctx.RespEntity(retVal)
 }
+
+// SnapshotHostIdentifier get the identifiers of all the hosts under the business, sets or modules page by page, so
+// that the configuration distribution tools can bootstrap without querying the identifier of each host.
+func (s *Service) SnapshotHostIdentifier(ctx *rest.Contexts) {
+	bizID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKAppIDField), 10, 64)
+	if err != nil || bizID <= 0 {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField))
+		return
+	}
+
+	option := new(metadata.HostIdentifierSnapshotOption)
+	if err := ctx.DecodeInto(option); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := option.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	ctx.SetReadPreference(common.SecondaryPreferredMode)
+	result, err := s.Logics.IdentifierOperation().SnapshotHostIdentifier(ctx.Kit, bizID, option)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	ctx.RespEntity(result)
+}
@@ -13,15 +13,18 @@
 package service
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 
+	"configcenter/src/ac/meta"
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/condition"
 	"configcenter/src/common/http/rest"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/pagelimit"
 	paraparse "configcenter/src/common/paraparse"
 	"configcenter/src/common/util"
 	"configcenter/src/scene_server/topo_server/logics/inst"
@@ -75,13 +78,15 @@ func (s *Service) CreateInst(ctx *rest.Contexts) {
 		ctx.RespAutoError(txnErr)
 		return
 	}
+	s.setSchemaLifecycleWarnings(ctx, objID, getDataFields(data))
 	ctx.RespEntity(setInst)
 }
 
 // CreateManyInstance TODO
 func (s *Service) CreateManyInstance(ctx *rest.Contexts) {
 	data := new(metadata.CreateManyCommInst)
-	if err := ctx.DecodeInto(&data); err != nil {
+	limits := map[string]int{"details": metadata.CreateManyCommInstMaxLimit}
+	if err := ctx.DecodeStreamInto(data, limits); err != nil {
 		ctx.RespAutoError(err)
 		return
 	}
@@ -115,9 +120,61 @@ func (s *Service) CreateManyInstance(ctx *rest.Contexts) {
 		return
 	}
 
+	s.setSchemaLifecycleWarnings(ctx, objID, getDataFields(data.Details...))
 	ctx.RespEntity(setInst)
 }
 
+// CreateOrUpdateInst create the instance, or update the instance with the same natural key
+func (s *Service) CreateOrUpdateInst(ctx *rest.Contexts) {
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+	opt := new(metadata.CreateOrUpdateModelInstance)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if len(opt.Data) == 0 {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsNeedSet, "data"))
+		return
+	}
+
+	// forbidden create inner model instance with common api
+	if common.IsInnerModel(objID) {
+		blog.Errorf("create or update %s instance with common api forbidden, rid: %s", objID, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommForbiddenOperateInnerModelInstanceWithCommonAPI))
+		return
+	}
+
+	var result *metadata.CreateOrUpdateInstResult
+	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
+		var err error
+		result, err = s.Logics.InstOperation().CreateOrUpdateInst(ctx.Kit, objID, opt)
+		if err != nil {
+			blog.Errorf("create or update %s instance failed, err: %v, rid: %s", objID, err, ctx.Kit.Rid)
+			return err
+		}
+
+		// the create permission is authorized by the api server, the update permission is authorized here since
+		// the instance is unknown until it is searched by the natural key, the update is rolled back if denied.
+		if !result.Created {
+			err = s.AuthManager.AuthorizeByInstanceID(ctx.Kit.Ctx, ctx.Kit.Header, meta.Update, objID,
+				int64(result.ID))
+			if err != nil {
+				blog.Errorf("authorize update %s instance %d failed, err: %v, rid: %s", objID, result.ID, err,
+					ctx.Kit.Rid)
+				return err
+			}
+		}
+		return nil
+	})
+
+	if txnErr != nil {
+		ctx.RespAutoError(txnErr)
+		return
+	}
+	ctx.RespEntity(result)
+}
+
 // CreateInstsByImport batch create insts by excel import
 func (s *Service) CreateInstsByImport(ctx *rest.Contexts) {
 	objID := ctx.Request.PathParameter("bk_obj_id")
@@ -165,6 +222,7 @@ func (s *Service) CreateInstsByImport(ctx *rest.Contexts) {
 	}
 
 	var setInst *inst.BatchResult
+	dryRunDone := false
 	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
 		var err error
 		setInst, err = s.Logics.InstOperation().CreateInstBatch(ctx.Kit, objID, batchInfo)
@@ -172,9 +230,20 @@ func (s *Service) CreateInstsByImport(ctx *rest.Contexts) {
 			blog.Errorf("failed to create new object %s, err: %v, rid: %s", objID, err, ctx.Kit.Rid)
 			return err
 		}
+
+		// the dry run imports the rows to get the result of each row, then rolls back all the changes
+		if batchInfo.DryRun {
+			dryRunDone = true
+			return errors.New("dry run import is rolled back")
+		}
 		return nil
 	})
 
+	if dryRunDone {
+		ctx.RespEntity(setInst)
+		return
+	}
+
 	if txnErr != nil {
 		// 临时方案
 		tmpErr := []string{}
@@ -354,6 +423,12 @@ func (s *Service) UpdateInsts(ctx *rest.Contexts) {
 		ctx.RespAutoError(txnErr)
 		return
 	}
+
+	updateData := make([]mapstr.MapStr, len(data.Update))
+	for idx, item := range data.Update {
+		updateData[idx] = item.InstInfo
+	}
+	s.setSchemaLifecycleWarnings(ctx, objID, getDataFields(updateData...))
 	ctx.RespEntity(nil)
 }
 
@@ -413,6 +488,7 @@ func (s *Service) UpdateInst(ctx *rest.Contexts) {
 		ctx.RespAutoError(txnErr)
 		return
 	}
+	s.setSchemaLifecycleWarnings(ctx, objID, getDataFields(data))
 	ctx.RespEntity(nil)
 }
 
@@ -441,6 +517,7 @@ func (s *Service) SearchInsts(ctx *rest.Contexts) {
 		return
 	}
 
+	s.setSchemaLifecycleWarnings(ctx, objID, queryCond.Fields)
 	ctx.RespEntity(rsp)
 }
 
@@ -480,6 +557,68 @@ func (s *Service) SearchObjectInstances(ctx *rest.Contexts) {
 		return
 	}
 
+	s.setSchemaLifecycleWarnings(ctx, objID, input.Fields)
+	ctx.RespEntity(result)
+}
+
+// FindInstsByIDs finds the object instances by an id list, the instances are returned in the order of the requested
+// ids, with the ids that are not found or not authorized to view reported.
+func (s *Service) FindInstsByIDs(ctx *rest.Contexts) {
+	objID := ctx.Request.PathParameter("bk_obj_id")
+
+	// NOTE: NOT SUPPORT inner model search action in this interface.
+	if common.IsInnerModel(objID) {
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommForbiddenOperateInnerModelInstanceWithCommonAPI))
+		return
+	}
+
+	input := new(metadata.FindByIDsOption)
+	if err := ctx.DecodeInto(input); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := input.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	idField := common.GetInstIDField(objID)
+	if len(input.Fields) > 0 && !util.InStrArr(input.Fields, idField) {
+		input.Fields = append(input.Fields, idField)
+	}
+
+	ctx.SetReadPreference(common.SecondaryPreferredMode)
+
+	cond := &metadata.QueryCondition{
+		Fields:    input.Fields,
+		Condition: mapstr.MapStr{idField: mapstr.MapStr{common.BKDBIN: input.IDs}},
+		Page:      metadata.BasePage{Limit: common.BKNoLimit},
+	}
+	insts, err := s.Logics.InstOperation().FindInst(ctx.Kit, objID, cond)
+	if err != nil {
+		blog.Errorf("find object(%s) instances by ids(%v) failed, err: %v, rid: %s", objID, input.IDs, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	noPermission, err := s.AuthManager.ListUnauthorizedInstanceIDs(ctx.Kit.Ctx, ctx.Kit.Header, meta.Find, objID,
+		input.IDs...)
+	if err != nil {
+		blog.Errorf("authorize object(%s) instances(%v) failed, err: %v, rid: %s", objID, input.IDs, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommCheckAuthorizeFailed))
+		return
+	}
+
+	result, err := metadata.NewFindByIDsResult(input.IDs, idField, insts.Info, noPermission)
+	if err != nil {
+		blog.Errorf("arrange object(%s) instances by ids failed, err: %v, rid: %s", objID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParseDataFailed, idField))
+		return
+	}
+
 	ctx.RespEntity(result)
 }
 
@@ -981,9 +1120,9 @@ func (s *Service) SearchInstAssociationWithOtherObject(ctx *rest.Contexts) {
 		Page:      reqParams.Page,
 	}
 
-	if input.IsIllegal() {
+	if rawErr := pagelimit.Validate("search_inst_association_with_other_object", &input.Page); rawErr.ErrCode != 0 {
 		blog.ErrorJSON("parse page illegal, input:%s,rid:%s", input, ctx.Kit.Rid)
-		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommPageLimitIsExceeded))
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
 		return
 	}
 
@@ -700,6 +700,8 @@ func (s *Service) SearchBusiness(ctx *rest.Contexts) {
 		option := &querybuilder.RuleOption{
 			NeedSameSliceElementType: true,
 			MaxSliceElementsCount:    querybuilder.DefaultMaxSliceElementsCount,
+			MaxValueBytes:            querybuilder.DefaultMaxValueBytes,
+			MaxStringLength:          querybuilder.DefaultMaxStringLength,
 			MaxConditionOrRulesCount: querybuilder.DefaultMaxConditionOrRulesCount,
 		}
 
@@ -475,13 +475,13 @@ func (s *Service) SearchReducedBusinessSetList(ctx *rest.Contexts) {
 	}
 
 	if len(bizSetList) > 0 {
-		query.Conditions = &querybuilder.QueryFilter{
-			Rule: &querybuilder.AtomRule{
-				Field:    common.BKBizSetIDField,
-				Operator: querybuilder.OperatorIn,
-				Value:    bizSetList,
-			},
+		filter, err := querybuilder.NewBuilder().Field(common.BKBizSetIDField).In(bizSetList).Build()
+		if err != nil {
+			blog.Errorf("build biz set filter failed, ids: %v, err: %v, rid: %s", bizSetList, err, ctx.Kit.Rid)
+			ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKBizSetIDField))
+			return
 		}
+		query.Conditions = filter
 	}
 	result, err := s.Logics.InstOperation().SearchObjectInstances(ctx.Kit, common.BKInnerObjIDBizSet, query)
 	if err != nil {
@@ -0,0 +1,93 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// PreviewMergeInst preview the merge of two duplicated instances
+func (s *Service) PreviewMergeInst(ctx *rest.Contexts) {
+	objID, opt, err := s.parseMergeInstParams(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	preview, err := s.Logics.InstOperation().PreviewMergeInst(ctx.Kit, objID, opt)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(preview)
+}
+
+// MergeInst merge the duplicated source instance into the target instance
+func (s *Service) MergeInst(ctx *rest.Contexts) {
+	objID, opt, err := s.parseMergeInstParams(ctx)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
+		if err := s.Logics.InstOperation().MergeInst(ctx.Kit, objID, opt); err != nil {
+			blog.Errorf("merge inst %d into %d failed, objID: %s, err: %v, rid: %s", opt.SourceID, opt.TargetID,
+				objID, err, ctx.Kit.Rid)
+			return err
+		}
+		return nil
+	})
+
+	if txnErr != nil {
+		ctx.RespAutoError(txnErr)
+		return
+	}
+	ctx.RespEntity(nil)
+}
+
+func (s *Service) parseMergeInstParams(ctx *rest.Contexts) (string, *metadata.MergeInstOption, error) {
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+
+	// forbidden merge inner model instance with common api, except for host which is the most duplicated one
+	if objID != common.BKInnerObjIDHost && common.IsInnerModel(objID) {
+		blog.Errorf("merge %s instance with common merge api forbidden, rid: %s", objID, ctx.Kit.Rid)
+		return "", nil, ctx.Kit.CCError.CCError(common.CCErrCommForbiddenOperateInnerModelInstanceWithCommonAPI)
+	}
+
+	// mainline instance has child instances, can not be merged
+	isMainline, err := s.Logics.AssociationOperation().IsMainlineObject(ctx.Kit, objID)
+	if err != nil {
+		blog.Errorf("check whether model %s to be mainline failed, err: %v, rid: %s", objID, err, ctx.Kit.Rid)
+		return "", nil, err
+	}
+	if isMainline {
+		blog.Errorf("merge mainline %s instance is forbidden, rid: %s", objID, ctx.Kit.Rid)
+		return "", nil, ctx.Kit.CCError.CCError(common.CCErrCommForbiddenOperateInnerModelInstanceWithCommonAPI)
+	}
+
+	opt := new(metadata.MergeInstOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		return "", nil, err
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		return "", nil, rawErr.ToCCError(ctx.Kit.CCError)
+	}
+
+	return objID, opt, nil
+}
@@ -23,6 +23,7 @@ import (
 	"configcenter/src/common/http/rest"
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/pagelimit"
 	"configcenter/src/common/querybuilder"
 	"configcenter/src/common/util"
 )
@@ -295,18 +296,12 @@ func (s *Service) ListModulesByServiceTemplateID(ctx *rest.Contexts) {
 
 	// check and set page's limit value
 	if requestBody.Page == nil {
-		requestBody.Page = &metadata.BasePage{
-			Limit: common.BKNoLimit,
-		}
-	} else {
-		if requestBody.Page.Limit == 0 {
-			requestBody.Page.Limit = common.BKDefaultLimit
-		}
-		if requestBody.Page.IsIllegal() {
-			blog.Errorf("page is illegal, page: %+v, rid: %s", requestBody.Page, ctx.Kit.Rid)
-			ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommPageLimitIsExceeded))
-			return
-		}
+		requestBody.Page = new(metadata.BasePage)
+	}
+	if rawErr := pagelimit.Validate("list_modules_by_service_template", requestBody.Page); rawErr.ErrCode != 0 {
+		blog.Errorf("page is illegal, page: %+v, rid: %s", requestBody.Page, ctx.Kit.Rid)
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
 	}
 
 	filter := map[string]interface{}{
@@ -0,0 +1,253 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/scene_server/topo_server/logics/inst"
+)
+
+// CreateInstImportTask create the instance import job, the rows are imported by chunks in background, each chunk is
+// a sub task whose checkpoint is its row range, so that the failed job can be resumed from the failed chunk.
+func (s *Service) CreateInstImportTask(ctx *rest.Contexts) {
+	objID := ctx.Request.PathParameter("bk_obj_id")
+
+	batchInfo := new(metadata.InstBatchInfo)
+	if err := ctx.DecodeInto(batchInfo); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if batchInfo.InputType != common.InputTypeExcel {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "input_type"))
+		return
+	}
+
+	if len(batchInfo.BatchInfo) == 0 {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsNeedSet, "BatchInfo"))
+		return
+	}
+
+	if len(batchInfo.BatchInfo) > common.BKMaxExportLimit {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommXXExceedLimit, "BatchInfo",
+			common.BKMaxExportLimit))
+		return
+	}
+
+	// the dry run import is quick enough to be done by the import api
+	if batchInfo.DryRun {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "dry_run"))
+		return
+	}
+
+	if rawErr := batchInfo.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	model, err := s.getInstTaskModel(ctx.Kit, objID)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	lines := make([]int64, 0, len(batchInfo.BatchInfo))
+	for line := range batchInfo.BatchInfo {
+		lines = append(lines, line)
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i] < lines[j] })
+
+	task := metadata.CreateTaskRequest{TaskType: common.InstImportTaskFlag, InstID: model.ID}
+	for start := 0; start < len(lines); start += metadata.InstTaskChunkSize {
+		end := start + metadata.InstTaskChunkSize
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		data := metadata.InstImportTaskData{ObjID: objID, InstBatchInfo: *batchInfo}
+		data.BatchInfo = make(map[int64]mapstr.MapStr, end-start)
+		for _, line := range lines[start:end] {
+			data.BatchInfo[line] = batchInfo.BatchInfo[line]
+		}
+
+		task.Data = append(task.Data, data)
+		task.Checkpoints = append(task.Checkpoints, metadata.APITaskCheckpoint{
+			ChunkID:  int64(len(task.Checkpoints)),
+			StartRow: lines[start],
+			EndRow:   lines[end-1],
+		})
+	}
+
+	s.createInstTask(ctx, task)
+}
+
+// CreateInstExportTask create the instance export job, the instances are exported by chunks in background, the
+// exported instances are kept as the task's files, which is downloaded as the excel file.
+func (s *Service) CreateInstExportTask(ctx *rest.Contexts) {
+	objID := ctx.Request.PathParameter("bk_obj_id")
+
+	input := new(metadata.InstExportTaskData)
+	if err := ctx.DecodeInto(input); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	input.ObjID = objID
+
+	if rawErr := input.Validate(common.BKMaxExportLimit); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	model, err := s.getInstTaskModel(ctx.Kit, objID)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	task := metadata.CreateTaskRequest{TaskType: common.InstExportTaskFlag, InstID: model.ID}
+	for start := 0; start < len(input.InstIDs); start += metadata.InstTaskChunkSize {
+		end := start + metadata.InstTaskChunkSize
+		if end > len(input.InstIDs) {
+			end = len(input.InstIDs)
+		}
+
+		data := *input
+		data.InstIDs = input.InstIDs[start:end]
+
+		task.Data = append(task.Data, data)
+		task.Checkpoints = append(task.Checkpoints, metadata.APITaskCheckpoint{
+			ChunkID:  int64(len(task.Checkpoints)),
+			StartRow: int64(start),
+			EndRow:   int64(end - 1),
+		})
+	}
+
+	s.createInstTask(ctx, task)
+}
+
+// getInstTaskModel get the model of the instance import or export job, the job is bound to the model so that only
+// one job of the model can be run at the same time.
+func (s *Service) getInstTaskModel(kit *rest.Kit, objID string) (*metadata.Object, error) {
+	// forbidden operate inner model instance with common api
+	if common.IsInnerModel(objID) {
+		blog.Errorf("operate %s instance with common api forbidden, rid: %s", objID, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommForbiddenOperateInnerModelInstanceWithCommonAPI)
+	}
+
+	model, err := s.Logics.ObjectOperation().FindSingleObject(kit, []string{common.BKFieldID}, objID)
+	if err != nil {
+		blog.Errorf("find object %s failed, err: %v, rid: %s", objID, err, kit.Rid)
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (s *Service) createInstTask(ctx *rest.Contexts, task metadata.CreateTaskRequest) {
+	tasks, err := s.Engine.CoreAPI.TaskServer().Task().CreateBatch(ctx.Kit.Ctx, ctx.Kit.Header,
+		[]metadata.CreateTaskRequest{task})
+	if err != nil {
+		blog.Errorf("create %s task failed, model: %d, err: %v, rid: %s", task.TaskType, task.InstID, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if len(tasks) == 0 {
+		blog.Errorf("create %s task returns no task, model: %d, rid: %s", task.TaskType, task.InstID, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommReplyDataFormatError))
+		return
+	}
+
+	ctx.RespEntity(mapstr.MapStr{"task_id": tasks[0].TaskID})
+}
+
+// InstImportTaskHandler import a chunk of the rows of the instance import job, the chunk is imported as a whole,
+// so that the failed chunk changes nothing and can be imported again when the job is resumed.
+func (s *Service) InstImportTaskHandler(ctx *rest.Contexts) {
+	data := new(metadata.InstImportTaskData)
+	if err := ctx.DecodeInto(data); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := data.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	var result *inst.BatchResult
+	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
+		var err error
+		result, err = s.Logics.InstOperation().CreateInstBatch(ctx.Kit, data.ObjID, &data.InstBatchInfo)
+		if err != nil {
+			blog.Errorf("import object %s instances failed, err: %v, rid: %s", data.ObjID, err, ctx.Kit.Rid)
+			return err
+		}
+
+		if len(result.Errors) > 0 {
+			return errors.New(strings.Join(result.Errors, "; "))
+		}
+		return nil
+	})
+
+	if txnErr != nil {
+		ctx.RespEntityWithError(result, txnErr)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// InstExportTaskHandler export a chunk of the instances of the instance export job, the instances are saved as the
+// task's file by the task server.
+func (s *Service) InstExportTaskHandler(ctx *rest.Contexts) {
+	data := new(metadata.InstExportTaskData)
+	if err := ctx.DecodeInto(data); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := data.Validate(metadata.InstTaskChunkSize); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	cond := mapstr.MapStr{common.GetInstIDField(data.ObjID): mapstr.MapStr{common.BKDBIN: data.InstIDs}}
+	if metadata.IsCommon(data.ObjID) {
+		cond[common.BKObjIDField] = data.ObjID
+	}
+
+	query := &metadata.QueryCondition{
+		Condition:      cond,
+		Page:           metadata.BasePage{Limit: common.BKNoLimit},
+		DisableCounter: true,
+	}
+	insts, err := s.Logics.InstOperation().FindInst(ctx.Kit, data.ObjID, query)
+	if err != nil {
+		blog.Errorf("find object %s instances %v failed, err: %v, rid: %s", data.ObjID, data.InstIDs, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(insts.Info)
+}
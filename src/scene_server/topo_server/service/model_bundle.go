@@ -0,0 +1,117 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// ExportModelBundle export the models with their attributes, unique rules, associations and association kinds as a
+// versioned model bundle, which can be installed into another deployment.
+func (s *Service) ExportModelBundle(ctx *rest.Contexts) {
+	opt := new(metadata.ExportModelBundleOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	bundle, err := s.Logics.ObjectOperation().ExportModelBundle(ctx.Kit, opt)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(bundle)
+}
+
+// PreviewInstallModelBundle check whether the model bundle can be installed, and returns the conflicting models and
+// the missing dependencies.
+func (s *Service) PreviewInstallModelBundle(ctx *rest.Contexts) {
+	bundle := new(metadata.ModelBundle)
+	if err := ctx.DecodeInto(bundle); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := bundle.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := s.Logics.ObjectOperation().CheckModelBundle(ctx.Kit, bundle)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// InstallModelBundle install the model bundle, the bundle is rejected if any of its models already exists or any of
+// its dependencies does not exist.
+func (s *Service) InstallModelBundle(ctx *rest.Contexts) {
+	bundle := new(metadata.ModelBundle)
+	if err := ctx.DecodeInto(bundle); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := bundle.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	check, err := s.Logics.ObjectOperation().CheckModelBundle(ctx.Kit, bundle)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if !check.Installable {
+		reasons := make([]string, 0)
+		if len(check.ConflictObjectIDs) > 0 {
+			reasons = append(reasons, "conflict models: "+strings.Join(check.ConflictObjectIDs, ","))
+		}
+		if len(check.MissingObjectIDs) > 0 {
+			reasons = append(reasons, "missing models: "+strings.Join(check.MissingObjectIDs, ","))
+		}
+		if len(check.MissingAsstKindIDs) > 0 {
+			reasons = append(reasons, "missing association kinds: "+strings.Join(check.MissingAsstKindIDs, ","))
+		}
+		blog.Errorf("model bundle %s(%s) is not installable, %s, rid: %s", bundle.Name, bundle.Version,
+			strings.Join(reasons, "; "), ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrTopoModelBundleNotInstallable, bundle.Name,
+			strings.Join(reasons, "; ")))
+		return
+	}
+
+	objects, err := s.createObjectsByImport(ctx, bundle.Objects, bundle.AsstKinds)
+	if err != nil {
+		blog.Errorf("install model bundle %s(%s) failed, err: %v, rid: %s", bundle.Name, bundle.Version, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(&metadata.ModelBundleInstallResult{ModelBundleHeader: bundle.ModelBundleHeader, Objects: objects})
+}
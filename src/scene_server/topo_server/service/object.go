@@ -235,6 +235,38 @@ func (s *Service) UpdateObject(ctx *rest.Contexts) {
 	ctx.RespEntity(nil)
 }
 
+// UpdateObjectLifecycle update the lifecycle of the object
+func (s *Service) UpdateObjectLifecycle(ctx *rest.Contexts) {
+	idStr := ctx.Request.PathParameter(common.BKFieldID)
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		blog.Errorf("failed to parse the path params id(%s), err: %v, rid: %s", idStr, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsNeedInt, common.BKFieldID))
+		return
+	}
+
+	lifecycle := new(metadata.SchemaLifecycle)
+	if err := ctx.DecodeInto(lifecycle); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := lifecycle.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
+		return s.Logics.ObjectOperation().UpdateObjectLifecycle(ctx.Kit, id, lifecycle)
+	})
+
+	if txnErr != nil {
+		ctx.RespAutoError(txnErr)
+		return
+	}
+	ctx.RespEntity(nil)
+}
+
 // DeleteObject delete the object
 func (s *Service) DeleteObject(ctx *rest.Contexts) {
 	idStr := ctx.Request.PathParameter(common.BKFieldID)
@@ -245,7 +277,18 @@ func (s *Service) DeleteObject(ctx *rest.Contexts) {
 		return
 	}
 
+	if err := s.deleteObject(ctx, id); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// deleteObject delete the model by id, and delete its iam view
+func (s *Service) deleteObject(ctx *rest.Contexts, id int64) error {
 	var obj *metadata.Object
+	var err error
 	cond := mapstr.MapStr{common.BKFieldID: id}
 	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
 		obj, err = s.Logics.ObjectOperation().DeleteObject(ctx.Kit, cond, true)
@@ -255,8 +298,7 @@ func (s *Service) DeleteObject(ctx *rest.Contexts) {
 		return nil
 	})
 	if txnErr != nil {
-		ctx.RespAutoError(txnErr)
-		return
+		return txnErr
 	}
 
 	if auth.EnableAuthorize() {
@@ -270,7 +312,7 @@ func (s *Service) DeleteObject(ctx *rest.Contexts) {
 		}
 	}
 
-	ctx.RespEntity(nil)
+	return nil
 }
 
 // GetModelStatistics 用于统计各个模型的实例数(Web页面展示需要)
@@ -344,21 +386,34 @@ func (s *Service) CreateManyObject(ctx *rest.Contexts) {
 		return
 	}
 
-	for _, item := range data.Objects {
+	rsp, err := s.createObjectsByImport(ctx, data.Objects, data.Asst)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(rsp)
+}
+
+// createObjectsByImport create the imported objects with their attributes and associations, and create or update
+// the association kinds.
+func (s *Service) createObjectsByImport(ctx *rest.Contexts, objects []metadata.YamlObject,
+	assts []metadata.AssociationKind) ([]metadata.Object, error) {
+
+	for _, item := range objects {
 		// 创建模型前，先创建表，避免模型创建后，对模型数据查询出现下面的错误，
 		// (SnapshotUnavailable) Unable to read from a snapshot due to pending collection catalog changes;
 		// please retry the operation. Snapshot timestamp is Timestamp(1616747877, 51).
 		// Collection minimum is Timestamp(1616747878, 5)
 		if err := s.createObjectTable(ctx, mapstr.MapStr{common.BKObjIDField: item.ObjectID}); err != nil {
-			ctx.RespAutoError(err)
-			return
+			return nil, err
 		}
 	}
 
 	var rsp []metadata.Object
 	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
 		var err error
-		rsp, err = s.Logics.ObjectOperation().CreateObjectByImport(ctx.Kit, data.Objects)
+		rsp, err = s.Logics.ObjectOperation().CreateObjectByImport(ctx.Kit, objects)
 		if err != nil {
 			return err
 		}
@@ -381,8 +436,8 @@ func (s *Service) CreateManyObject(ctx *rest.Contexts) {
 			}
 		}
 
-		if len(data.Asst) != 0 {
-			if err = s.Logics.AssociationOperation().CreateOrUpdateAssociationType(ctx.Kit, data.Asst); err != nil {
+		if len(assts) != 0 {
+			if err = s.Logics.AssociationOperation().CreateOrUpdateAssociationType(ctx.Kit, assts); err != nil {
 				blog.Errorf("create or update association kind failed, err: %v, rid: %s", err, ctx.Kit.Rid)
 				return err
 			}
@@ -392,11 +447,10 @@ func (s *Service) CreateManyObject(ctx *rest.Contexts) {
 	})
 
 	if txnErr != nil {
-		ctx.RespAutoError(txnErr)
-		return
+		return nil, txnErr
 	}
 
-	ctx.RespEntity(rsp)
+	return rsp, nil
 }
 
 // SearchObjectWithTotalInfo search object with it's attribute and association
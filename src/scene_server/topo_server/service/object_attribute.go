@@ -14,6 +14,7 @@ package service
 
 import (
 	"strconv"
+	"time"
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -32,8 +33,11 @@ func (s *Service) CreateObjectAttribute(ctx *rest.Contexts) {
 		return
 	}
 
-	// do not support add preset attribute by api
+	// do not support add preset attribute by api, the attribute can only be locked by the lock api
 	attr.IsPre = false
+	attr.IsLocked = false
+	attr.OwnerSource = ""
+	attr.OwnerPolicy = ""
 	isBizCustomField := false
 	// adapt input path param with bk_biz_id
 	if bizIDStr := ctx.Request.PathParameter(common.BKAppIDField); bizIDStr != "" {
@@ -121,8 +125,15 @@ func (s *Service) SearchObjectAttribute(ctx *rest.Contexts) {
 		ctx.RespAutoError(err)
 		return
 	}
+	now := time.Now()
+	language := util.GetLanguage(ctx.Kit.Header)
 	attrInfos := make([]*metadata.ObjAttDes, 0)
 	for _, attr := range resp.Info {
+		if isAttributeHidden(&attr, now) {
+			continue
+		}
+		attr.Localize(ctx.Kit.Ctx, language)
+
 		attrInfo := &metadata.ObjAttDes{
 			Attribute: attr,
 		}
@@ -174,9 +185,14 @@ func (s *Service) UpdateObjectAttribute(ctx *rest.Contexts) {
 	data.Remove(metadata.BKMetadata)
 	data.Remove(common.BKAppIDField)
 
-	// UpdateObjectAttribute should not update bk_property_index、bk_property_group
+	// UpdateObjectAttribute should not update bk_property_index、bk_property_group、bk_islocked、bk_lifecycle and the
+	// owner of the attribute
 	data.Remove(common.BKPropertyIndexField)
 	data.Remove(common.BKPropertyGroupField)
+	data.Remove(metadata.AttributeFieldIsLocked)
+	data.Remove(metadata.AttributeFieldOwnerSource)
+	data.Remove(metadata.AttributeFieldOwnerPolicy)
+	data.Remove(metadata.SchemaFieldLifecycle)
 
 	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
 		err := s.Logics.AttributeOperation().UpdateObjectAttribute(ctx.Kit, data, id, bizID)
@@ -193,6 +209,78 @@ func (s *Service) UpdateObjectAttribute(ctx *rest.Contexts) {
 	ctx.RespEntity(nil)
 }
 
+// LockObjectAttribute lock or unlock the object attributes, the locked attributes can not be updated or deleted in
+// the business scope, and can not be configured in the templates, so that the shared models are not broken.
+func (s *Service) LockObjectAttribute(ctx *rest.Contexts) {
+	option := new(metadata.LockAttributeOption)
+	if err := ctx.DecodeInto(option); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
+		return s.Logics.AttributeOperation().LockObjectAttribute(ctx.Kit, option)
+	})
+
+	if txnErr != nil {
+		ctx.RespAutoError(txnErr)
+		return
+	}
+	ctx.RespEntity(nil)
+}
+
+// SetObjectAttributeOwner set the external source, e.g. the cloud sync or the monitoring agent, which owns the data
+// of the object attributes, so that the manual edits and the automated data do not clobber each other.
+func (s *Service) SetObjectAttributeOwner(ctx *rest.Contexts) {
+	option := new(metadata.SetAttributeOwnerOption)
+	if err := ctx.DecodeInto(option); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
+		return s.Logics.AttributeOperation().SetObjectAttributeOwner(ctx.Kit, option)
+	})
+
+	if txnErr != nil {
+		ctx.RespAutoError(txnErr)
+		return
+	}
+	ctx.RespEntity(nil)
+}
+
+// UpdateObjectAttributeLifecycle update the lifecycle of the object attribute
+func (s *Service) UpdateObjectAttributeLifecycle(ctx *rest.Contexts) {
+	id, err := strconv.ParseInt(ctx.Request.PathParameter("id"), 10, 64)
+	if err != nil {
+		blog.Errorf("failed to parse the path params id: %s, err: %v, rid: %s", ctx.Request.PathParameter("id"),
+			err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "id"))
+		return
+	}
+
+	lifecycle := new(metadata.SchemaLifecycle)
+	if err := ctx.DecodeInto(lifecycle); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := lifecycle.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(ctx.Kit.Ctx, ctx.Kit.Header, func() error {
+		return s.Logics.AttributeOperation().UpdateAttributeLifecycle(ctx.Kit, id, lifecycle)
+	})
+
+	if txnErr != nil {
+		ctx.RespAutoError(txnErr)
+		return
+	}
+	ctx.RespEntity(nil)
+}
+
 // DeleteObjectAttribute delete the object attribute
 func (s *Service) DeleteObjectAttribute(ctx *rest.Contexts) {
 
@@ -206,6 +294,21 @@ func (s *Service) DeleteObjectAttribute(ctx *rest.Contexts) {
 		return
 	}
 
+	modelType := new(ModelType)
+	if err := ctx.DecodeInto(modelType); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if err := s.deleteObjectAttribute(ctx, id, modelType.BizID); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	ctx.RespEntity(nil)
+}
+
+// deleteObjectAttribute delete the model attribute by id, and delete the related host apply rules
+func (s *Service) deleteObjectAttribute(ctx *rest.Contexts, id int64, modelBizID int64) error {
 	listRuleOption := metadata.ListHostApplyRuleOption{
 		ModuleIDs: []int64{id},
 		Page: metadata.BasePage{
@@ -217,25 +320,17 @@ func (s *Service) DeleteObjectAttribute(ctx *rest.Contexts) {
 	if err != nil {
 		blog.Errorf("get host apply rule failed, listRuleOption: %+v, err: %+v, rid: %s", listRuleOption, err,
 			ctx.Kit.Rid)
-		ctx.RespAutoError(err)
-		return
+		return err
 	}
 	ruleIDs := make([]int64, 0)
 	for _, item := range ruleResult.Info {
 		ruleIDs = append(ruleIDs, item.ID)
 	}
 
-	modelType := new(ModelType)
-	if err := ctx.DecodeInto(modelType); err != nil {
-		ctx.RespAutoError(err)
-		return
-	}
-
 	cond := mapstr.MapStr{metadata.AttributeFieldID: id}
-	if err = s.Logics.AttributeOperation().DeleteObjectAttribute(ctx.Kit, cond, modelType.BizID); err != nil {
+	if err := s.Logics.AttributeOperation().DeleteObjectAttribute(ctx.Kit, cond, modelBizID); err != nil {
 		blog.Errorf("delete object attribute failed, params: %+v, err: %+v, rid: %s", cond, err, ctx.Kit.Rid)
-		ctx.RespAutoError(err)
-		return
+		return err
 	}
 
 	if len(ruleIDs) > 0 {
@@ -246,11 +341,10 @@ func (s *Service) DeleteObjectAttribute(ctx *rest.Contexts) {
 			deleteRuleOption); err != nil {
 			blog.Errorf("delete host apply rule failed, params: %+v, err: %+v, rid: %s", deleteRuleOption, err,
 				ctx.Kit.Rid)
-			ctx.RespAutoError(err)
-			return
+			return err
 		}
 	}
-	ctx.RespEntity(nil)
+	return nil
 }
 
 // UpdateObjectAttributeIndex update object attribute index
@@ -339,8 +433,15 @@ func (s *Service) ListHostModelAttribute(ctx *rest.Contexts) {
 		return
 	}
 
+	now := time.Now()
+	language := util.GetLanguage(ctx.Kit.Header)
 	hostAttributes := make([]metadata.HostObjAttDes, 0)
 	for _, item := range result.Info {
+		if isAttributeHidden(&item, now) {
+			continue
+		}
+		item.Localize(ctx.Kit.Ctx, language)
+
 		hostApplyEnabled := metadata.CheckAllowHostApplyOnField(&item)
 		hostAttribute := metadata.HostObjAttDes{
 			ObjAttDes: metadata.ObjAttDes{
@@ -394,3 +495,27 @@ func (s *Service) getPropertyGroupName(ctx *rest.Contexts, attrs []metadata.Attr
 
 	return grpMap, nil
 }
+
+// FindEnumCascadeOptions find the options of the cascade enum attribute for the value of its parent attribute, which is
+// used by the ui to render the dependent dropdowns.
+func (s *Service) FindEnumCascadeOptions(ctx *rest.Contexts) {
+	opt := new(metadata.EnumCascadeOptionsOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+
+	result, err := s.Logics.AttributeOperation().FindEnumCascadeOptions(ctx.Kit, objID, opt)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
@@ -0,0 +1,272 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// deleteCascadeInstBatchSize the count of instances deleted in one batch when archiving a model's instances
+const deleteCascadeInstBatchSize = 200
+
+// PreviewDeleteObject preview the deletion of the model with the cascade policy
+func (s *Service) PreviewDeleteObject(ctx *rest.Contexts) {
+	id, opt, err := s.parseDeleteWithPolicyParams(ctx, metadata.ModelDeletePolicies)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	preview, err := s.Logics.ObjectOperation().PreviewDeleteObject(ctx.Kit, id, opt.Policy)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(preview)
+}
+
+// DeleteObjectWithPolicy delete the model with the cascade policy
+func (s *Service) DeleteObjectWithPolicy(ctx *rest.Contexts) {
+	id, opt, err := s.parseDeleteWithPolicyParams(ctx, metadata.ModelDeletePolicies)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	preview, err := s.Logics.ObjectOperation().PreviewDeleteObject(ctx.Kit, id, opt.Policy)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if preview.Blocked {
+		blog.Errorf("delete object %d is blocked by policy %s, reason: %s, rid: %s", id, opt.Policy, preview.Reason,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrTopoDeleteBlockedByPolicy, opt.Policy, preview.Reason))
+		return
+	}
+
+	result := &metadata.DeleteWithPolicyResult{Policy: opt.Policy}
+
+	// the model has no instances, delete it directly
+	if opt.Policy == metadata.DeletePolicyBlock || preview.InstanceCount == 0 {
+		if err := s.deleteObject(ctx, id); err != nil {
+			ctx.RespAutoError(err)
+			return
+		}
+		ctx.RespEntity(result)
+		return
+	}
+
+	task := metadata.ModelDeleteCascadeTask{
+		Policy:  opt.Policy,
+		ObjID:   preview.ObjID,
+		ModelID: id,
+		BizID:   opt.BizID,
+	}
+	result.TaskID, err = s.createModelDeleteCascadeTask(ctx.Kit, id, task)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// PreviewDeleteObjectAttribute preview the deletion of the model attribute with the cascade policy
+func (s *Service) PreviewDeleteObjectAttribute(ctx *rest.Contexts) {
+	id, opt, err := s.parseDeleteWithPolicyParams(ctx, metadata.AttributeDeletePolicies)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	preview, err := s.Logics.AttributeOperation().PreviewDeleteObjectAttribute(ctx.Kit, id, opt.BizID, opt.Policy)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(preview)
+}
+
+// DeleteObjectAttributeWithPolicy delete the model attribute with the cascade policy
+func (s *Service) DeleteObjectAttributeWithPolicy(ctx *rest.Contexts) {
+	id, opt, err := s.parseDeleteWithPolicyParams(ctx, metadata.AttributeDeletePolicies)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	preview, err := s.Logics.AttributeOperation().PreviewDeleteObjectAttribute(ctx.Kit, id, opt.BizID, opt.Policy)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if preview.Blocked {
+		blog.Errorf("delete attribute %d is blocked by policy %s, reason: %s, rid: %s", id, opt.Policy,
+			preview.Reason, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrTopoDeleteBlockedByPolicy, opt.Policy, preview.Reason))
+		return
+	}
+
+	result := &metadata.DeleteWithPolicyResult{Policy: opt.Policy}
+
+	// no instance has data of the attribute, delete it directly
+	if opt.Policy == metadata.DeletePolicyBlock || preview.InstanceCount == 0 {
+		if err := s.deleteObjectAttribute(ctx, id, opt.BizID); err != nil {
+			ctx.RespAutoError(err)
+			return
+		}
+		ctx.RespEntity(result)
+		return
+	}
+
+	task := metadata.ModelDeleteCascadeTask{
+		Policy: opt.Policy,
+		ObjID:  preview.ObjID,
+		AttrID: id,
+		BizID:  opt.BizID,
+	}
+	result.TaskID, err = s.createModelDeleteCascadeTask(ctx.Kit, id, task)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+func (s *Service) parseDeleteWithPolicyParams(ctx *rest.Contexts, policies []metadata.DeletePolicy) (int64,
+	*metadata.DeleteWithPolicyOption, error) {
+
+	idStr := ctx.Request.PathParameter(common.BKFieldID)
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		blog.Errorf("failed to parse the path params id(%s), err: %v, rid: %s", idStr, err, ctx.Kit.Rid)
+		return 0, nil, ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKFieldID)
+	}
+
+	opt := new(metadata.DeleteWithPolicyOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		return 0, nil, err
+	}
+
+	if rawErr := opt.Validate(policies); rawErr.ErrCode != 0 {
+		return 0, nil, rawErr.ToCCError(ctx.Kit.CCError)
+	}
+
+	return id, opt, nil
+}
+
+func (s *Service) createModelDeleteCascadeTask(kit *rest.Kit, id int64, task metadata.ModelDeleteCascadeTask) (
+	string, error) {
+
+	taskRes, err := s.Engine.CoreAPI.TaskServer().Task().Create(kit.Ctx, kit.Header,
+		common.ModelDeleteCascadeTaskFlag, id, []interface{}{task})
+	if err != nil {
+		blog.Errorf("create model delete cascade task failed, task: %#v, err: %v, rid: %s", task, err, kit.Rid)
+		return "", err
+	}
+
+	return taskRes.TaskID, nil
+}
+
+// ModelDeleteCascadeTaskHandler delete the model's instances or the attribute's data in background, then delete
+// the model or the attribute
+func (s *Service) ModelDeleteCascadeTaskHandler(ctx *rest.Contexts) {
+	task := new(metadata.ModelDeleteCascadeTask)
+	if err := ctx.DecodeInto(task); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	switch task.Policy {
+	case metadata.DeletePolicyArchive:
+		if err := s.deleteAllObjectInstances(ctx.Kit, task.ObjID); err != nil {
+			ctx.RespAutoError(err)
+			return
+		}
+
+		if err := s.deleteObject(ctx, task.ModelID); err != nil {
+			blog.Errorf("delete object %d failed, err: %v, rid: %s", task.ModelID, err, ctx.Kit.Rid)
+			ctx.RespAutoError(err)
+			return
+		}
+	case metadata.DeletePolicyDropData:
+		// attribute data in the instances is cleaned when the attribute is deleted
+		if err := s.deleteObjectAttribute(ctx, task.AttrID, task.BizID); err != nil {
+			ctx.RespAutoError(err)
+			return
+		}
+	default:
+		blog.Errorf("model delete cascade task policy %s is invalid, rid: %s", task.Policy, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "policy"))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// deleteAllObjectInstances delete all the instances of the model in batches, the deleted instances are archived
+func (s *Service) deleteAllObjectInstances(kit *rest.Kit, objID string) error {
+	instIDField := common.GetInstIDField(objID)
+	cond := mapstr.MapStr{}
+	if metadata.IsCommon(objID) {
+		cond[common.BKObjIDField] = objID
+	}
+	query := &metadata.QueryCondition{
+		Condition:      cond,
+		Fields:         []string{instIDField},
+		Page:           metadata.BasePage{Limit: deleteCascadeInstBatchSize},
+		DisableCounter: true,
+	}
+
+	for {
+		insts, err := s.Logics.InstOperation().FindInst(kit, objID, query)
+		if err != nil {
+			blog.Errorf("find object %s instances failed, err: %v, rid: %s", objID, err, kit.Rid)
+			return err
+		}
+
+		if len(insts.Info) == 0 {
+			return nil
+		}
+
+		instIDs := make([]int64, 0, len(insts.Info))
+		for _, inst := range insts.Info {
+			instID, err := inst.Int64(instIDField)
+			if err != nil {
+				blog.Errorf("parse instance id failed, inst: %#v, err: %v, rid: %s", inst, err, kit.Rid)
+				return kit.CCError.CCErrorf(common.CCErrCommParamsNeedInt, instIDField)
+			}
+			instIDs = append(instIDs, instID)
+		}
+
+		txnErr := s.Engine.CoreAPI.CoreService().Txn().AutoRunTxn(kit.Ctx, kit.Header, func() error {
+			return s.Logics.InstOperation().DeleteInstByInstID(kit, objID, instIDs, true)
+		})
+		if txnErr != nil {
+			blog.Errorf("delete object %s instances %v failed, err: %v, rid: %s", objID, instIDs, txnErr, kit.Rid)
+			return txnErr
+		}
+	}
+}
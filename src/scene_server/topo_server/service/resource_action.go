@@ -0,0 +1,96 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+
+	"configcenter/src/ac"
+	"configcenter/src/ac/meta"
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+)
+
+// ListResourceActions list the custom actions registered on the resource's model
+func (s *Service) ListResourceActions(ctx *rest.Contexts) {
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+
+	actions := make([]metadata.ResourceAction, 0)
+	for _, action := range s.Config.ResourceActions {
+		if action.ObjID == objID {
+			actions = append(actions, action)
+		}
+	}
+
+	ctx.RespEntityWithCount(int64(len(actions)), actions)
+}
+
+// ExecuteResourceAction execute the custom action on the resource, the permission of the resource required by the
+// action is checked before calling the external system.
+func (s *Service) ExecuteResourceAction(ctx *rest.Contexts) {
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+	instID, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKInstIDField), 10, 64)
+	if err != nil || instID <= 0 {
+		blog.Errorf("path parameter bk_inst_id invalid, object: %s, err: %v, rid: %s", objID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKInstIDField))
+		return
+	}
+
+	actionID := ctx.Request.PathParameter("action_id")
+	var action *metadata.ResourceAction
+	for index := range s.Config.ResourceActions {
+		if s.Config.ResourceActions[index].ID == actionID && s.Config.ResourceActions[index].ObjID == objID {
+			action = &s.Config.ResourceActions[index]
+			break
+		}
+	}
+	if action == nil {
+		blog.Errorf("resource action %s of object %s is not registered, rid: %s", actionID, objID, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "action_id"))
+		return
+	}
+
+	opt := new(metadata.ExecuteResourceActionOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := action.ValidateParams(opt.Params); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	err = s.AuthManager.AuthorizeByInstanceID(ctx.Kit.Ctx, ctx.Kit.Header, meta.Action(action.Permission), objID,
+		instID)
+	if err != nil {
+		blog.Errorf("authorize resource action %s on inst %d failed, err: %v, rid: %s", actionID, instID, err,
+			ctx.Kit.Rid)
+		if err == ac.NoAuthorizeError {
+			ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommAuthNotHavePermission))
+			return
+		}
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommAuthorizeFailed))
+		return
+	}
+
+	result, err := s.Logics.InstOperation().ExecuteResourceAction(ctx.Kit, action, instID, opt.Params)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
@@ -0,0 +1,55 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"time"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+)
+
+// setSchemaLifecycleWarnings warns the caller in the response header if the model or the used attributes are
+// deprecated or scheduled to be retired, the failure of the warning does not affect the request.
+func (s *Service) setSchemaLifecycleWarnings(ctx *rest.Contexts, objID string, fields []string) {
+	warnings, err := s.Logics.ObjectOperation().SchemaLifecycleWarnings(ctx.Kit, objID, fields)
+	if err != nil {
+		blog.Warnf("get object %s lifecycle warnings failed, err: %v, rid: %s", objID, err, ctx.Kit.Rid)
+		return
+	}
+	ctx.SetWarnings(warnings)
+}
+
+// getDataFields returns the fields of the instance data used to check the attributes' lifecycle.
+func getDataFields(data ...mapstr.MapStr) []string {
+	fieldMap := make(map[string]struct{})
+	fields := make([]string, 0)
+	for _, item := range data {
+		for field := range item {
+			if _, exists := fieldMap[field]; exists {
+				continue
+			}
+			fieldMap[field] = struct{}{}
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// isAttributeHidden returns if the attribute is hidden by its lifecycle, the hidden attributes are not returned to
+// the callers any more.
+func isAttributeHidden(attr *metadata.Attribute, now time.Time) bool {
+	return attr.Lifecycle.EffectiveState(now) == metadata.SchemaStateHidden
+}
@@ -31,13 +31,25 @@ func (s *Service) initBusinessObject(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/object", Handler: s.CreateObject})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/object", Handler: s.SearchObject})
 	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/object/{id}", Handler: s.UpdateObject})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/object/{id}/lifecycle",
+		Handler: s.UpdateObjectLifecycle})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/object/{id}", Handler: s.DeleteObject})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/object/{id}/delete/preview",
+		Handler: s.PreviewDeleteObject})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/object/{id}/cascade",
+		Handler: s.DeleteObjectWithPolicy})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/objecttopology", Handler: s.SearchObjectTopo})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/object/model", Handler: s.SearchModel})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/createmany/object/by_import",
 		Handler: s.CreateManyObject})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/object/total/info",
 		Handler: s.SearchObjectWithTotalInfo})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/object/bundle/export",
+		Handler: s.ExportModelBundle})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/object/bundle/install/preview",
+		Handler: s.PreviewInstallModelBundle})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/createmany/object/by_bundle",
+		Handler: s.InstallModelBundle})
 
 	utility.AddToRestfulWebService(web)
 }
@@ -54,6 +66,13 @@ func (s *Service) initBusinessClassification(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/objectclassification/{id}", Handler: s.UpdateClassification})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/objectclassification/{id}", Handler: s.DeleteClassification})
 
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/model/asset", Handler: s.CreateModelAsset})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/model/asset/{id}/version",
+		Handler: s.AddModelAssetVersion})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/model/asset", Handler: s.SearchModelAsset})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/model/asset/{id}",
+		Handler: s.DeleteModelAsset})
+
 	utility.AddToRestfulWebService(web)
 }
 
@@ -69,7 +88,35 @@ func (s *Service) initBusinessObjectAttribute(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/objectattr/host", Handler: s.ListHostModelAttribute})
 	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/objectattr/{id}", Handler: s.UpdateObjectAttribute})
 	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/objectattr/biz/{bk_biz_id}/id/{id}", Handler: s.UpdateObjectAttribute})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/objectattr/lock",
+		Handler: s.LockObjectAttribute})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/objectattr/owner",
+		Handler: s.SetObjectAttributeOwner})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/objectattr/{id}/lifecycle",
+		Handler: s.UpdateObjectAttributeLifecycle})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/objectattr/{id}", Handler: s.DeleteObjectAttribute})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/objectattr/{id}/delete/preview",
+		Handler: s.PreviewDeleteObjectAttribute})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/objectattr/{id}/cascade",
+		Handler: s.DeleteObjectAttributeWithPolicy})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/objectattr/biz/{bk_biz_id}/layout/{bk_obj_id}",
+		Handler: s.SaveBizFieldLayout})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/objectattr/biz/{bk_biz_id}/layout/{bk_obj_id}",
+		Handler: s.FindBizFieldLayout})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete,
+		Path: "/delete/objectattr/biz/{bk_biz_id}/layout/{bk_obj_id}", Handler: s.DeleteBizFieldLayout})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut,
+		Path: "/update/objectattr/group/delegation/object/{bk_obj_id}", Handler: s.SaveAttrGroupDelegation})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost,
+		Path: "/findmany/objectattr/group/delegation/object/{bk_obj_id}", Handler: s.FindAttrGroupDelegation})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete,
+		Path: "/delete/objectattr/group/delegation/object/{bk_obj_id}", Handler: s.DeleteAttrGroupDelegation})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/objectattr/edit_schema/object/{bk_obj_id}",
+		Handler: s.FindAttributeEditSchema})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/objectattr/enum_cascade/object/{bk_obj_id}",
+		Handler: s.FindEnumCascadeOptions})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/objectattr/usage/object/{bk_obj_id}",
+		Handler: s.FindAttributeUsage})
 
 	utility.AddToRestfulWebService(web)
 }
@@ -130,6 +177,7 @@ func (s *Service) initBusinessAssociation(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/topomodelmainline", Handler: s.CreateMainLineObject})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/topomodelmainline/object/{bk_obj_id}", Handler: s.DeleteMainLineObject})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/topomodelmainline", Handler: s.SearchMainLineObjectTopo})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/topo/limit/usage", Handler: s.FindTopoLimitUsage})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/topoinst/biz/{bk_biz_id}", Handler: s.SearchBusinessTopo})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/topoinst_with_statistics/biz/{bk_biz_id}", Handler: s.SearchBusinessTopoWithStatistics})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/topoinstnode/host_serviceinst_count/{bk_biz_id}",
@@ -146,6 +194,8 @@ func (s *Service) initBusinessAssociation(web *restful.WebService) {
 
 	// object association methods
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/objectassociation", Handler: s.SearchObjectAssociation})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/objectassociation/mapping_violation",
+		Handler: s.ScanAsstMappingViolation})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/objectassociation", Handler: s.CreateObjectAssociation})
 	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/objectassociation/{id}", Handler: s.UpdateObjectAssociation})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/objectassociation/{id}", Handler: s.DeleteObjectAssociation})
@@ -159,6 +209,10 @@ func (s *Service) initBusinessAssociation(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/instassociation/batch", Handler: s.DeleteAssociationInstBatch})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/search/instance_associations/object/{bk_obj_id}", Handler: s.SearchInstanceAssociations})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/count/instance_associations/object/{bk_obj_id}", Handler: s.CountInstanceAssociations})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/instassociation/edge/object/{bk_obj_id}",
+		Handler: s.SearchInstAsstEdges})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/count/instassociation/edge/object/{bk_obj_id}",
+		Handler: s.CountInstAsstEdges})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/instassociation/model",
 		Handler: s.SearchModuleAssociation})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/instassociation/object/{bk_obj_id}/inst/detail",
@@ -167,6 +221,8 @@ func (s *Service) initBusinessAssociation(web *restful.WebService) {
 	// topo search methods
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/instassociation/object/{bk_obj_id}", Handler: s.SearchInstByAssociation})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/instassttopo/object/{bk_obj_id}/inst/{inst_id}", Handler: s.SearchInstTopo})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost,
+		Path: "/find/instassttopo/graph/object/{bk_obj_id}/inst/{inst_id}", Handler: s.SearchInstGraph})
 
 	// ATTENTION: the following methods is not recommended
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/insttopo/object/{bk_obj_id}/inst/{inst_id}", Handler: s.SearchInstChildTopo})
@@ -190,8 +246,14 @@ func (s *Service) initBusinessInst(web *restful.WebService) {
 		Handler: s.CreateInst})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/createmany/instance/object/{bk_obj_id}",
 		Handler: s.CreateManyInstance})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create_or_update/instance/object/{bk_obj_id}",
+		Handler: s.CreateOrUpdateInst})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/instance/object/{bk_obj_id}/by_import",
 		Handler: s.CreateInstsByImport})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/instance/object/{bk_obj_id}/by_import/task",
+		Handler: s.CreateInstImportTask})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/instance/object/{bk_obj_id}/export/task",
+		Handler: s.CreateInstExportTask})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/instance/object/{bk_obj_id}/inst/{inst_id}",
 		Handler: s.DeleteInst})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/deletemany/instance/object/{bk_obj_id}",
@@ -200,6 +262,10 @@ func (s *Service) initBusinessInst(web *restful.WebService) {
 		Handler: s.UpdateInst})
 	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/updatemany/instance/object/{bk_obj_id}",
 		Handler: s.UpdateInsts})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/instance/object/{bk_obj_id}/merge/preview",
+		Handler: s.PreviewMergeInst})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/instance/object/{bk_obj_id}/merge",
+		Handler: s.MergeInst})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/instance/object/{bk_obj_id}",
 		Handler: s.SearchInstAndAssociationDetail})
 	utility.AddHandler(rest.Action{
@@ -217,6 +283,16 @@ func (s *Service) initBusinessInst(web *restful.WebService) {
 		Handler: s.SearchObjectInstances})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/count/instances/object/{bk_obj_id}",
 		Handler: s.CountObjectInstances})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/instance/object/{bk_obj_id}/by_ids",
+		Handler: s.FindInstsByIDs})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/instance/object/{bk_obj_id}/import_conflict",
+		Handler: s.FindInstImportConflict})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut,
+		Path: "/update/external_id/object/{bk_obj_id}/inst/{bk_inst_id}", Handler: s.BindExternalIDs})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete,
+		Path: "/delete/external_id/object/{bk_obj_id}/inst/{bk_inst_id}", Handler: s.UnbindExternalIDs})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/external_id/object/{bk_obj_id}",
+		Handler: s.SearchExternalIDs})
 
 	utility.AddToRestfulWebService(web)
 }
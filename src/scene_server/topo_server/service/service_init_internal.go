@@ -32,6 +32,12 @@ func (s *Service) initInternalTask(web *restful.WebService) {
 
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/internal/sync/module/task",
 		Handler: s.SyncModuleTaskHandler})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/internal/delete/object/cascade/task",
+		Handler: s.ModelDeleteCascadeTaskHandler})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/internal/import/instance/task",
+		Handler: s.InstImportTaskHandler})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/internal/export/instance/task",
+		Handler: s.InstExportTaskHandler})
 
 	utility.AddToRestfulWebService(web)
 }
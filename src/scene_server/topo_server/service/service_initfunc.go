@@ -46,6 +46,8 @@ func (s *Service) initAuditLog(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/audit_list", Handler: s.SearchAuditList})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/audit", Handler: s.SearchAuditDetail})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/inst_audit", Handler: s.SearchInstAudit})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/audit/change_set",
+		Handler: s.SearchAuditChangeSet})
 
 	utility.AddToRestfulWebService(web)
 }
@@ -235,6 +237,8 @@ func (s *Service) initIdentifier(web *restful.WebService) {
 
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/identifier/{obj_type}/search",
 		Handler: s.SearchIdentifier})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/identifier/host/snapshot/biz/{bk_biz_id}",
+		Handler: s.SnapshotHostIdentifier})
 
 	utility.AddToRestfulWebService(web)
 }
@@ -247,6 +251,12 @@ func (s *Service) initFullTextSearch(web *restful.WebService) {
 	})
 
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/full_text", Handler: s.FullTextSearch})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/full_text/reindex",
+		Handler: s.StartFullTextReindex})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/full_text/reindex",
+		Handler: s.FindFullTextReindexTasks})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/update/full_text/reindex/{task_id}/cancel",
+		Handler: s.CancelFullTextReindex})
 
 	utility.AddToRestfulWebService(web)
 }
@@ -270,6 +280,53 @@ func (s *Service) initResourceDirectory(web *restful.WebService) {
 	utility.AddToRestfulWebService(web)
 }
 
+// initResourceAction the custom actions on the resources registered by config
+func (s *Service) initResourceAction(web *restful.WebService) {
+	utility := rest.NewRestUtility(rest.Config{
+		ErrorIf:  s.Engine.CCErr,
+		Language: s.Engine.Language,
+	})
+
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/resource/{bk_obj_id}/{bk_inst_id}/actions",
+		Handler: s.ListResourceActions})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost,
+		Path: "/update/resource/{bk_obj_id}/{bk_inst_id}/actions/{action_id}", Handler: s.ExecuteResourceAction})
+
+	utility.AddToRestfulWebService(web)
+}
+
+// initChangeBundle the bundles of related changes in a business that are executed as a whole
+func (s *Service) initChangeBundle(web *restful.WebService) {
+	utility := rest.NewRestUtility(rest.Config{
+		ErrorIf:  s.Engine.CCErr,
+		Language: s.Engine.Language,
+	})
+
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/update/topo/change_bundle/biz/{bk_biz_id}",
+		Handler: s.ExecuteChangeBundle})
+
+	utility.AddToRestfulWebService(web)
+}
+
+// initBizRole the business roles that are used to route the notifications of the business
+func (s *Service) initBizRole(web *restful.WebService) {
+	utility := rest.NewRestUtility(rest.Config{
+		ErrorIf:  s.Engine.CCErr,
+		Language: s.Engine.Language,
+	})
+
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/topo/biz_role/biz/{bk_biz_id}",
+		Handler: s.SaveBizRoleAssignment})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/topo/biz_role/biz/{bk_biz_id}",
+		Handler: s.SearchBizRoleAssignment})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/topo/biz_role/biz/{bk_biz_id}",
+		Handler: s.DeleteBizRoleAssignment})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/topo/biz_role/receivers/biz/{bk_biz_id}",
+		Handler: s.ResolveBizRoleReceivers})
+
+	utility.AddToRestfulWebService(web)
+}
+
 func (s *Service) initService(web *restful.WebService) {
 	s.initAssociation(web)
 	s.initAuditLog(web)
@@ -297,4 +354,7 @@ func (s *Service) initService(web *restful.WebService) {
 	s.initInternalTask(web)
 
 	s.initResourceDirectory(web)
+	s.initResourceAction(web)
+	s.initChangeBundle(web)
+	s.initBizRole(web)
 }
@@ -0,0 +1,38 @@
+import com.tencent.bk.cmdb.client.ApiClient;
+import com.tencent.bk.cmdb.client.model.Resource;
+import com.tencent.bk.cmdb.client.model.WatchEventFilter;
+import com.tencent.bk.cmdb.client.model.WatchNamedCursorOptions;
+import com.tencent.bk.cmdb.watch.NamedCursorWatcher;
+
+import java.net.URI;
+import java.util.Arrays;
+
+/**
+ * Watches the host events by the named cursor, run it on several machines and only one of them consumes the events
+ * at the same time, the others take over the cursor when it stops.
+ *
+ * <pre>java WatchHostExample http://127.0.0.1:8080/api/v3 admin</pre>
+ */
+public class WatchHostExample {
+
+    public static void main(String[] args) throws Exception {
+        String user = args[1];
+        String supplierAccount = args.length > 2 ? args[2] : "0";
+
+        ApiClient apiClient = new ApiClient();
+        apiClient.updateBaseUri(URI.create(args[0]).toString());
+        apiClient.setRequestInterceptor(builder -> builder
+            .header("BK_User", user)
+            .header("HTTP_BLUEKING_SUPPLIER_ID", supplierAccount));
+
+        WatchNamedCursorOptions options = new WatchNamedCursorOptions()
+            .bkFields(Arrays.asList("bk_host_id", "bk_host_innerip", "bk_host_name"))
+            .bkFilter(new WatchEventFilter().bkChangedFields(Arrays.asList("bk_host_innerip", "bk_host_name")));
+        NamedCursorWatcher watcher = new NamedCursorWatcher(apiClient, Resource.HOST, "example_host_consumer",
+            options);
+        Runtime.getRuntime().addShutdownHook(new Thread(watcher::stop));
+
+        watcher.run(events -> events.forEach(event ->
+            System.out.println(event.getBkEventType() + " " + event.getBkChangedFields() + " " + event.getBkDetail())));
+    }
+}
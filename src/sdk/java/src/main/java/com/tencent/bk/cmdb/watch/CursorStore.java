@@ -0,0 +1,16 @@
+package com.tencent.bk.cmdb.watch;
+
+/**
+ * The store of the cursor of the last processed event, so that the watcher resumes from it after restarting.
+ */
+public interface CursorStore {
+
+    /**
+     * @return the saved cursor, or null if no cursor is saved.
+     */
+    String load() throws Exception;
+
+    void save(String cursor) throws Exception;
+
+    void clear() throws Exception;
+}
@@ -0,0 +1,14 @@
+package com.tencent.bk.cmdb.watch;
+
+import com.tencent.bk.cmdb.client.model.WatchEventDetail;
+
+import java.util.List;
+
+/**
+ * Handles the watched events, the cursor is saved only if the handler returns without exception.
+ */
+@FunctionalInterface
+public interface EventHandler {
+
+    void handle(List<WatchEventDetail> events) throws Exception;
+}
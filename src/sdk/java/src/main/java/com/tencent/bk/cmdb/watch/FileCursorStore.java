@@ -0,0 +1,42 @@
+package com.tencent.bk.cmdb.watch;
+
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.NoSuchFileException;
+import java.nio.file.Path;
+import java.nio.file.StandardCopyOption;
+
+/**
+ * Keeps the cursor in a local file, the file is replaced atomically on saving.
+ */
+public class FileCursorStore implements CursorStore {
+
+    private final Path path;
+
+    public FileCursorStore(Path path) {
+        this.path = path.toAbsolutePath();
+    }
+
+    @Override
+    public String load() throws IOException {
+        try {
+            String cursor = new String(Files.readAllBytes(path), StandardCharsets.UTF_8).trim();
+            return cursor.isEmpty() ? null : cursor;
+        } catch (NoSuchFileException e) {
+            return null;
+        }
+    }
+
+    @Override
+    public void save(String cursor) throws IOException {
+        Path tmp = Files.createTempFile(path.getParent(), path.getFileName().toString(), ".tmp");
+        Files.write(tmp, cursor.getBytes(StandardCharsets.UTF_8));
+        Files.move(tmp, path, StandardCopyOption.REPLACE_EXISTING, StandardCopyOption.ATOMIC_MOVE);
+    }
+
+    @Override
+    public void clear() throws IOException {
+        Files.deleteIfExists(path);
+    }
+}
@@ -0,0 +1,24 @@
+package com.tencent.bk.cmdb.watch;
+
+/**
+ * Keeps the cursor in memory, the watcher starts from now after restarting.
+ */
+public class MemoryCursorStore implements CursorStore {
+
+    private volatile String cursor;
+
+    @Override
+    public String load() {
+        return cursor;
+    }
+
+    @Override
+    public void save(String cursor) {
+        this.cursor = cursor;
+    }
+
+    @Override
+    public void clear() {
+        this.cursor = null;
+    }
+}
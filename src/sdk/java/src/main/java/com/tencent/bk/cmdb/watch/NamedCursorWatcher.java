@@ -0,0 +1,177 @@
+package com.tencent.bk.cmdb.watch;
+
+import com.tencent.bk.cmdb.client.ApiClient;
+import com.tencent.bk.cmdb.client.api.NamedCursorApi;
+import com.tencent.bk.cmdb.client.model.AdvanceNamedCursorOption;
+import com.tencent.bk.cmdb.client.model.BaseResponse;
+import com.tencent.bk.cmdb.client.model.NamedCursorLease;
+import com.tencent.bk.cmdb.client.model.NamedCursorResponse;
+import com.tencent.bk.cmdb.client.model.Resource;
+import com.tencent.bk.cmdb.client.model.SeekNamedCursorOption;
+import com.tencent.bk.cmdb.client.model.WatchEventDetail;
+import com.tencent.bk.cmdb.client.model.WatchNamedCursorOptions;
+import com.tencent.bk.cmdb.client.model.WatchResponse;
+
+import java.lang.management.ManagementFactory;
+import java.util.List;
+import java.util.logging.Level;
+import java.util.logging.Logger;
+
+/**
+ * Watches the events of the resource by the named cursor stored in the event server. The watcher registers the
+ * cursor to take its lease, then watches the events, calls the handler and advances the cursor. If the cursor is
+ * owned by another consumer, the watcher waits until the lease expires, so that the consumers of the same name can
+ * be deployed as active and standby.
+ */
+public class NamedCursorWatcher {
+
+    private static final Logger LOGGER = Logger.getLogger(NamedCursorWatcher.class.getName());
+
+    private final NamedCursorApi api;
+    private final Resource resource;
+    private final String name;
+    private final WatchNamedCursorOptions options;
+    private long intervalMillis = 1000L;
+    private volatile boolean owned;
+    private volatile boolean running;
+
+    /**
+     * @param options the event types, fields and filter of the watch, the owner defaults to the host and the pid of
+     *                the process and the lease defaults to 60 seconds.
+     */
+    public NamedCursorWatcher(ApiClient apiClient, Resource resource, String name, WatchNamedCursorOptions options) {
+        this.api = new NamedCursorApi(apiClient);
+        this.resource = resource;
+        this.name = name;
+        this.options = options;
+        if (options.getBkOwner() == null || options.getBkOwner().isEmpty()) {
+            options.bkOwner(ManagementFactory.getRuntimeMXBean().getName());
+        }
+        if (options.getBkLeaseSeconds() == null) {
+            options.bkLeaseSeconds(60L);
+        }
+    }
+
+    public NamedCursorWatcher interval(long intervalMillis) {
+        this.intervalMillis = intervalMillis;
+        return this;
+    }
+
+    private NamedCursorLease lease() {
+        return new NamedCursorLease().bkOwner(options.getBkOwner()).bkLeaseSeconds(options.getBkLeaseSeconds());
+    }
+
+    /**
+     * Takes the lease of the named cursor.
+     *
+     * @return false if the cursor is owned by another consumer.
+     */
+    public boolean register() throws Exception {
+        NamedCursorResponse resp = api.registerNamedCursor(resource, name, lease());
+        try {
+            WatchException.check(resp.getResult(), resp.getBkErrorCode(), resp.getBkErrorMsg(), resp.getRequestId());
+        } catch (WatchException e) {
+            if (e.getCode() == WatchException.ERR_NAMED_CURSOR_OWNED) {
+                return false;
+            }
+            throw e;
+        }
+        LOGGER.info("named cursor " + name + " of " + resource + " is owned by " + options.getBkOwner());
+        owned = true;
+        return true;
+    }
+
+    /**
+     * Seeks the named cursor to the cursor or the unix seconds, or to now if both of them are null.
+     */
+    public void seek(String cursor, Long startFrom) throws Exception {
+        SeekNamedCursorOption seek = new SeekNamedCursorOption().bkOwner(options.getBkOwner())
+            .bkLeaseSeconds(options.getBkLeaseSeconds()).bkCursor(cursor).bkStartFrom(startFrom);
+        NamedCursorResponse resp = api.seekNamedCursor(resource, name, seek);
+        WatchException.check(resp.getResult(), resp.getBkErrorCode(), resp.getBkErrorMsg(), resp.getRequestId());
+    }
+
+    /**
+     * Watches the events once and advances the cursor after handling them, the lease is renewed by the watch.
+     *
+     * @return the number of the handled events.
+     */
+    public int watchOnce(EventHandler handler) throws Exception {
+        WatchResponse resp = api.watchNamedCursor(resource, name, options);
+        try {
+            WatchException.check(resp.getResult(), resp.getBkErrorCode(), resp.getBkErrorMsg(), resp.getRequestId());
+        } catch (WatchException e) {
+            if (e.getCode() == WatchException.ERR_NAMED_CURSOR_LEASE_LOST) {
+                owned = false;
+                return 0;
+            }
+            if (e.getCode() == WatchException.ERR_EVENT_CHAIN_NODE_NOT_EXIST) {
+                LOGGER.log(Level.WARNING, "named cursor " + name + " of " + resource + " is expired, seek to now", e);
+                seek(null, null);
+                return 0;
+            }
+            throw e;
+        }
+
+        List<WatchEventDetail> events = Watcher.watchedEvents(resp.getData());
+        if (!events.isEmpty()) {
+            handler.handle(events);
+        }
+        String last = Watcher.lastCursor(resp.getData());
+        if (last != null) {
+            AdvanceNamedCursorOption advance = new AdvanceNamedCursorOption().bkOwner(options.getBkOwner())
+                .bkLeaseSeconds(options.getBkLeaseSeconds()).bkCursor(last);
+            NamedCursorResponse advanced = api.advanceNamedCursor(resource, name, advance);
+            WatchException.check(advanced.getResult(), advanced.getBkErrorCode(), advanced.getBkErrorMsg(),
+                advanced.getRequestId());
+        }
+        return events.size();
+    }
+
+    /**
+     * Watches the events until stop is called, the lease is released on stopping.
+     */
+    public void run(EventHandler handler) throws InterruptedException {
+        running = true;
+        try {
+            while (running) {
+                try {
+                    if (!owned && !register()) {
+                        Thread.sleep(intervalMillis);
+                        continue;
+                    }
+                    if (watchOnce(handler) == 0) {
+                        Thread.sleep(intervalMillis);
+                    }
+                } catch (InterruptedException e) {
+                    throw e;
+                } catch (Exception e) {
+                    LOGGER.log(Level.SEVERE, "watch named cursor " + name + " of " + resource + " failed", e);
+                    Thread.sleep(intervalMillis);
+                }
+            }
+        } finally {
+            release();
+        }
+    }
+
+    /**
+     * Releases the lease so that the standby consumers take over the cursor immediately.
+     */
+    public void release() {
+        if (!owned) {
+            return;
+        }
+        owned = false;
+        try {
+            BaseResponse resp = api.releaseNamedCursor(resource, name, lease());
+            WatchException.check(resp.getResult(), resp.getBkErrorCode(), resp.getBkErrorMsg(), resp.getRequestId());
+        } catch (Exception e) {
+            LOGGER.log(Level.WARNING, "release named cursor " + name + " of " + resource + " failed", e);
+        }
+    }
+
+    public void stop() {
+        running = false;
+    }
+}
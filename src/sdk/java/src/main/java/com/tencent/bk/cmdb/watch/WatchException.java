@@ -0,0 +1,37 @@
+package com.tencent.bk.cmdb.watch;
+
+/**
+ * The api returns a failed result.
+ */
+public class WatchException extends Exception {
+
+    /** the cursor of the watch is expired or does not exist, watch from now */
+    public static final int ERR_EVENT_CHAIN_NODE_NOT_EXIST = 1103007;
+    /** the named cursor is owned by another consumer */
+    public static final int ERR_NAMED_CURSOR_OWNED = 1103012;
+    /** the consumer does not hold the lease of the named cursor */
+    public static final int ERR_NAMED_CURSOR_LEASE_LOST = 1103013;
+
+    private final int code;
+    private final String requestId;
+
+    public WatchException(Integer code, String message, String requestId) {
+        super(String.format("code: %s, message: %s, rid: %s", code, message, requestId));
+        this.code = code == null ? 0 : code;
+        this.requestId = requestId;
+    }
+
+    public int getCode() {
+        return code;
+    }
+
+    public String getRequestId() {
+        return requestId;
+    }
+
+    static void check(Boolean result, Integer code, String message, String requestId) throws WatchException {
+        if (!Boolean.TRUE.equals(result)) {
+            throw new WatchException(code, message, requestId);
+        }
+    }
+}
@@ -0,0 +1,125 @@
+package com.tencent.bk.cmdb.watch;
+
+import com.tencent.bk.cmdb.client.ApiClient;
+import com.tencent.bk.cmdb.client.api.WatchApi;
+import com.tencent.bk.cmdb.client.model.Resource;
+import com.tencent.bk.cmdb.client.model.WatchEventDetail;
+import com.tencent.bk.cmdb.client.model.WatchEventOptions;
+import com.tencent.bk.cmdb.client.model.WatchResponse;
+import com.tencent.bk.cmdb.client.model.WatchResult;
+
+import java.util.Collections;
+import java.util.List;
+import java.util.logging.Level;
+import java.util.logging.Logger;
+
+/**
+ * Watches the events of the resource from the cursor in the store, and saves the cursor of the last event after the
+ * handler processes them, the events are delivered at least once. If the saved cursor is expired, the store is
+ * cleared and the watch starts from now.
+ */
+public class Watcher {
+
+    private static final Logger LOGGER = Logger.getLogger(Watcher.class.getName());
+
+    private final WatchApi api;
+    private final Resource resource;
+    private final WatchEventOptions options;
+    private final CursorStore store;
+    private long intervalMillis = 1000L;
+    private volatile boolean running;
+
+    /**
+     * @param options the event types, fields and filter of the watch, the cursor and the start time are managed by
+     *                the watcher, the start time is only used if no cursor is saved.
+     */
+    public Watcher(ApiClient apiClient, Resource resource, WatchEventOptions options, CursorStore store) {
+        this.api = new WatchApi(apiClient);
+        this.resource = resource;
+        this.options = options.bkResource(resource);
+        this.store = store == null ? new MemoryCursorStore() : store;
+    }
+
+    public Watcher interval(long intervalMillis) {
+        this.intervalMillis = intervalMillis;
+        return this;
+    }
+
+    /**
+     * Watches the events once and saves the cursor after handling them.
+     *
+     * @return the number of the handled events.
+     */
+    public int watchOnce(EventHandler handler) throws Exception {
+        String cursor = store.load();
+        options.bkCursor(cursor);
+        if (cursor != null) {
+            options.bkStartFrom(null);
+        }
+
+        WatchResponse resp = api.watchResource(resource, options);
+        try {
+            WatchException.check(resp.getResult(), resp.getBkErrorCode(), resp.getBkErrorMsg(), resp.getRequestId());
+        } catch (WatchException e) {
+            if (e.getCode() != WatchException.ERR_EVENT_CHAIN_NODE_NOT_EXIST) {
+                throw e;
+            }
+            LOGGER.log(Level.WARNING, "cursor of " + resource + " is expired, watch from now", e);
+            store.clear();
+            options.bkStartFrom(null);
+            return 0;
+        }
+
+        WatchResult result = resp.getData();
+        List<WatchEventDetail> events = watchedEvents(result);
+        if (!events.isEmpty()) {
+            handler.handle(events);
+        }
+        String last = lastCursor(result);
+        if (last != null) {
+            store.save(last);
+        }
+        return events.size();
+    }
+
+    /**
+     * Watches the events until stop is called, the errors are logged and retried after the interval.
+     */
+    public void run(EventHandler handler) throws InterruptedException {
+        running = true;
+        while (running) {
+            try {
+                if (watchOnce(handler) == 0) {
+                    Thread.sleep(intervalMillis);
+                }
+            } catch (InterruptedException e) {
+                throw e;
+            } catch (Exception e) {
+                LOGGER.log(Level.SEVERE, "watch " + resource + " failed, retry later", e);
+                Thread.sleep(intervalMillis);
+            }
+        }
+    }
+
+    public void stop() {
+        running = false;
+    }
+
+    /**
+     * If no event is watched, the only event carries the latest cursor, it should be saved but not handled.
+     */
+    static List<WatchEventDetail> watchedEvents(WatchResult result) {
+        if (result == null || !Boolean.TRUE.equals(result.getBkWatched()) || result.getBkEvents() == null) {
+            return Collections.emptyList();
+        }
+        return result.getBkEvents();
+    }
+
+    static String lastCursor(WatchResult result) {
+        if (result == null || result.getBkEvents() == null || result.getBkEvents().isEmpty()) {
+            return null;
+        }
+        List<WatchEventDetail> events = result.getBkEvents();
+        return events.get(events.size() - 1).getBkCursor();
+    }
+}
@@ -0,0 +1,16 @@
+# -*- coding: utf-8 -*-
+"""
+the cursor management helpers of the cmdb watch apis, built on the generated bkcmdb_client package.
+"""
+
+from bkcmdb_watch.store import CursorStore, FileCursorStore, MemoryCursorStore
+from bkcmdb_watch.watcher import NamedCursorWatcher, WatchError, Watcher
+
+__all__ = [
+    "CursorStore",
+    "FileCursorStore",
+    "MemoryCursorStore",
+    "NamedCursorWatcher",
+    "WatchError",
+    "Watcher",
+]
@@ -0,0 +1,64 @@
+# -*- coding: utf-8 -*-
+"""
+the stores that persist the cursor of the last processed event, so that the watcher resumes from it after restarting.
+"""
+
+import os
+import tempfile
+
+
+class CursorStore(object):
+    """the store of the cursor, load returns None if no cursor is saved."""
+
+    def load(self):
+        raise NotImplementedError
+
+    def save(self, cursor):
+        raise NotImplementedError
+
+    def clear(self):
+        raise NotImplementedError
+
+
+class MemoryCursorStore(CursorStore):
+    """keeps the cursor in memory, the watcher starts from now after restarting."""
+
+    def __init__(self, cursor=None):
+        self._cursor = cursor
+
+    def load(self):
+        return self._cursor
+
+    def save(self, cursor):
+        self._cursor = cursor
+
+    def clear(self):
+        self._cursor = None
+
+
+class FileCursorStore(CursorStore):
+    """keeps the cursor in a local file, the file is replaced atomically on saving."""
+
+    def __init__(self, path):
+        self._path = path
+
+    def load(self):
+        try:
+            with open(self._path) as f:
+                cursor = f.read().strip()
+        except IOError:
+            return None
+        return cursor or None
+
+    def save(self, cursor):
+        directory = os.path.dirname(os.path.abspath(self._path))
+        fd, tmp = tempfile.mkstemp(dir=directory)
+        with os.fdopen(fd, "w") as f:
+            f.write(cursor)
+        os.rename(tmp, self._path)
+
+    def clear(self):
+        try:
+            os.remove(self._path)
+        except OSError:
+            pass
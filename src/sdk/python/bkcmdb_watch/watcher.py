@@ -0,0 +1,241 @@
+# -*- coding: utf-8 -*-
+"""
+the watchers of the cmdb resource events.
+
+Watcher keeps the cursor in the consumer's CursorStore, NamedCursorWatcher keeps the cursor in the event server by
+the named cursor apis, so that the consumers of the same name share the cursor and only the lease owner consumes it.
+in both of them, the cursor is saved only after the handler processes the events successfully, the events are
+delivered at least once.
+"""
+
+import logging
+import os
+import socket
+import time
+
+import bkcmdb_client
+
+from bkcmdb_watch.store import MemoryCursorStore
+
+logger = logging.getLogger(__name__)
+
+# the cursor of the watch is expired or does not exist, watch from now
+ERR_EVENT_CHAIN_NODE_NOT_EXIST = 1103007
+# the named cursor is owned by another consumer
+ERR_NAMED_CURSOR_OWNED = 1103012
+# the consumer does not hold the lease of the named cursor
+ERR_NAMED_CURSOR_LEASE_LOST = 1103013
+
+# the resources that must be watched with the fields
+RESOURCES_NEED_FIELDS = ("host", "biz", "set", "module")
+
+
+class WatchError(Exception):
+    """the api returns a failed result."""
+
+    def __init__(self, code, message, request_id=None):
+        super(WatchError, self).__init__("code: %s, message: %s, rid: %s" % (code, message, request_id))
+        self.code = code
+        self.message = message
+        self.request_id = request_id
+
+
+def _check(resp):
+    if not resp.result:
+        raise WatchError(resp.bk_error_code, resp.bk_error_msg, getattr(resp, "request_id", None))
+    return resp.data
+
+
+def _watched_events(result):
+    # if no event is watched, the only event carries the latest cursor, it should be saved but not handled
+    if not result.bk_watched:
+        return [], (result.bk_events[0].bk_cursor if result.bk_events else None)
+    events = result.bk_events or []
+    return events, (events[-1].bk_cursor if events else None)
+
+
+def default_owner():
+    """the default owner of the named cursor lease, which is unique for each consumer process."""
+    return "%s:%d" % (socket.gethostname(), os.getpid())
+
+
+class Watcher(object):
+    """
+    watch the events of the resource from the cursor in the store, and save the cursor of the last event after
+    the handler processes them. if the saved cursor is expired, the store is cleared and the watch starts from now.
+    """
+
+    def __init__(self, api_client, resource, fields=None, event_types=None, sub_resource=None,
+                 changed_fields=None, store=None, start_from=None, interval=1):
+        if resource in RESOURCES_NEED_FIELDS and not fields:
+            raise ValueError("bk_fields must be set to watch %s" % resource)
+        self._api = bkcmdb_client.WatchApi(api_client)
+        self._resource = resource
+        self._fields = fields
+        self._event_types = event_types
+        self._filter = {"bk_sub_resource": sub_resource, "bk_changed_fields": changed_fields}
+        self._store = store or MemoryCursorStore()
+        self._start_from = start_from
+        self._interval = interval
+        self._running = False
+
+    def watch_once(self, handler):
+        """watch the events once, calls handler with the events, returns the number of the handled events."""
+        options = {
+            "bk_resource": self._resource,
+            "bk_fields": self._fields,
+            "bk_event_types": self._event_types,
+            "bk_filter": {k: v for k, v in self._filter.items() if v},
+        }
+        cursor = self._store.load()
+        if cursor:
+            options["bk_cursor"] = cursor
+        elif self._start_from:
+            options["bk_start_from"] = self._start_from
+
+        try:
+            result = _check(self._api.watch_resource(
+                self._resource, bkcmdb_client.WatchEventOptions.from_dict(options)))
+        except WatchError as err:
+            if err.code != ERR_EVENT_CHAIN_NODE_NOT_EXIST:
+                raise
+            logger.warning("cursor %s of %s is expired, watch from now, err: %s", cursor, self._resource, err)
+            self._store.clear()
+            self._start_from = None
+            return 0
+
+        events, last = _watched_events(result)
+        if events:
+            handler(events)
+        if last:
+            self._store.save(last)
+        return len(events)
+
+    def run(self, handler):
+        """watch the events until stop is called, the errors are logged and retried after the interval."""
+        self._running = True
+        while self._running:
+            try:
+                if self.watch_once(handler) == 0:
+                    time.sleep(self._interval)
+            except Exception:
+                logger.exception("watch %s failed, retry later", self._resource)
+                time.sleep(self._interval)
+
+    def stop(self):
+        self._running = False
+
+
+class NamedCursorWatcher(object):
+    """
+    watch the events of the resource by the named cursor stored in the event server.
+    the watcher registers the cursor to take its lease, then watches the events, calls the handler and advances the
+    cursor, which also renews the lease. if the cursor is owned by another consumer, the watcher waits until the
+    lease expires, so that the consumers of the same name can be deployed as active and standby.
+    """
+
+    def __init__(self, api_client, resource, name, fields=None, event_types=None, sub_resource=None,
+                 changed_fields=None, owner=None, lease_seconds=60, interval=1):
+        if resource in RESOURCES_NEED_FIELDS and not fields:
+            raise ValueError("bk_fields must be set to watch %s" % resource)
+        self._api = bkcmdb_client.NamedCursorApi(api_client)
+        self._resource = resource
+        self._name = name
+        self._fields = fields
+        self._event_types = event_types
+        self._filter = {"bk_sub_resource": sub_resource, "bk_changed_fields": changed_fields}
+        self._owner = owner or default_owner()
+        self._lease_seconds = lease_seconds
+        self._interval = interval
+        self._owned = False
+        self._running = False
+
+    def _lease(self):
+        return {"bk_owner": self._owner, "bk_lease_seconds": self._lease_seconds}
+
+    def register(self):
+        """take the lease of the named cursor, returns False if it is owned by another consumer."""
+        try:
+            cursor = _check(self._api.register_named_cursor(
+                self._resource, self._name, bkcmdb_client.NamedCursorLease.from_dict(self._lease())))
+        except WatchError as err:
+            if err.code != ERR_NAMED_CURSOR_OWNED:
+                raise
+            return False
+        logger.info("named cursor %s of %s is owned by %s, cursor: %s", self._name, self._resource, self._owner,
+                    cursor.bk_cursor)
+        self._owned = True
+        return True
+
+    def seek(self, cursor=None, start_from=None):
+        """seek the named cursor to the cursor or the unix seconds, or to now if both of them are not set."""
+        options = self._lease()
+        if cursor:
+            options["bk_cursor"] = cursor
+        if start_from:
+            options["bk_start_from"] = start_from
+        return _check(self._api.seek_named_cursor(
+            self._resource, self._name, bkcmdb_client.SeekNamedCursorOption.from_dict(options)))
+
+    def watch_once(self, handler):
+        """watch the events once and advance the cursor after handling them, returns the number of handled events."""
+        options = self._lease()
+        options.update({
+            "bk_fields": self._fields,
+            "bk_event_types": self._event_types,
+            "bk_filter": {k: v for k, v in self._filter.items() if v},
+        })
+        try:
+            result = _check(self._api.watch_named_cursor(
+                self._resource, self._name, bkcmdb_client.WatchNamedCursorOptions.from_dict(options)))
+        except WatchError as err:
+            if err.code == ERR_NAMED_CURSOR_LEASE_LOST:
+                self._owned = False
+                return 0
+            if err.code == ERR_EVENT_CHAIN_NODE_NOT_EXIST:
+                logger.warning("named cursor %s of %s is expired, seek to now, err: %s", self._name,
+                               self._resource, err)
+                self.seek()
+                return 0
+            raise
+
+        events, last = _watched_events(result)
+        if events:
+            handler(events)
+        if last:
+            advance = self._lease()
+            advance["bk_cursor"] = last
+            _check(self._api.advance_named_cursor(
+                self._resource, self._name, bkcmdb_client.AdvanceNamedCursorOption.from_dict(advance)))
+        return len(events)
+
+    def run(self, handler):
+        """watch the events until stop is called, the lease is released on stopping."""
+        self._running = True
+        try:
+            while self._running:
+                try:
+                    if not self._owned and not self.register():
+                        time.sleep(self._interval)
+                        continue
+                    if self.watch_once(handler) == 0:
+                        time.sleep(self._interval)
+                except Exception:
+                    logger.exception("watch named cursor %s of %s failed, retry later", self._name, self._resource)
+                    time.sleep(self._interval)
+        finally:
+            self.release()
+
+    def release(self):
+        """release the lease so that the standby consumers take over the cursor immediately."""
+        if not self._owned:
+            return
+        self._owned = False
+        try:
+            _check(self._api.release_named_cursor(
+                self._resource, self._name, bkcmdb_client.NamedCursorLease.from_dict(self._lease())))
+        except Exception:
+            logger.exception("release named cursor %s of %s failed", self._name, self._resource)
+
+    def stop(self):
+        self._running = False
@@ -0,0 +1,50 @@
+# -*- coding: utf-8 -*-
+"""
+watch the set events by the named cursor, run it on several machines and only one of them consumes the events at
+the same time, the others take over the cursor when it stops.
+
+    python named_cursor_set.py http://127.0.0.1:8080/api/v3 admin
+"""
+
+import logging
+import sys
+
+import bkcmdb_client
+from bkcmdb_watch import NamedCursorWatcher
+
+
+def handle(events):
+    for event in events:
+        print(event.bk_event_type, event.bk_detail)
+
+
+def sync_all(api_client):
+    # the full data is synchronized by the read apis before watching, if the consumer starts for the first time
+    api = bkcmdb_client.ReadApi(api_client)
+    resp = api.search_business("0", bkcmdb_client.SearchBusinessOptions.from_dict(
+        {"fields": ["bk_biz_id", "bk_biz_name"], "page": {"start": 0, "limit": 200}}))
+    for biz in resp.data.info:
+        sets = api.search_set("0", biz["bk_biz_id"], bkcmdb_client.QueryCondition.from_dict(
+            {"fields": ["bk_set_id", "bk_set_name"], "page": {"start": 0, "limit": 200}}))
+        print(biz["bk_biz_name"], [s["bk_set_name"] for s in sets.data.info])
+
+
+def main(host, user, supplier_account="0"):
+    conf = bkcmdb_client.Configuration(host=host)
+    conf.api_key["user"] = user
+    conf.api_key["supplierAccount"] = supplier_account
+    api_client = bkcmdb_client.ApiClient(conf)
+
+    watcher = NamedCursorWatcher(api_client, "set", "example_set_consumer",
+                                 fields=["bk_set_id", "bk_set_name", "bk_biz_id"])
+    if watcher.register():
+        sync_all(api_client)
+    try:
+        watcher.run(handle)
+    except KeyboardInterrupt:
+        watcher.stop()
+
+
+if __name__ == "__main__":
+    logging.basicConfig(level=logging.INFO)
+    main(*sys.argv[1:])
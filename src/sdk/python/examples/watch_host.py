@@ -0,0 +1,37 @@
+# -*- coding: utf-8 -*-
+"""
+watch the host events and resume from the cursor saved in a local file after restarting.
+
+    python watch_host.py http://127.0.0.1:8080/api/v3 admin
+"""
+
+import logging
+import sys
+
+import bkcmdb_client
+from bkcmdb_watch import FileCursorStore, Watcher
+
+
+def handle(events):
+    for event in events:
+        print(event.bk_event_type, event.bk_changed_fields, event.bk_detail)
+
+
+def main(host, user, supplier_account="0"):
+    conf = bkcmdb_client.Configuration(host=host)
+    conf.api_key["user"] = user
+    conf.api_key["supplierAccount"] = supplier_account
+
+    watcher = Watcher(bkcmdb_client.ApiClient(conf), "host",
+                      fields=["bk_host_id", "bk_host_innerip", "bk_host_name"],
+                      changed_fields=["bk_host_innerip", "bk_host_name"],
+                      store=FileCursorStore("./host.cursor"))
+    try:
+        watcher.run(handle)
+    except KeyboardInterrupt:
+        watcher.stop()
+
+
+if __name__ == "__main__":
+    logging.basicConfig(level=logging.INFO)
+    main(*sys.argv[1:])
@@ -35,6 +35,10 @@ type Client struct {
 // GetHostWithID get host with host id.
 // fields allows you can specify which fields you need only.
 func (c *Client) GetHostWithID(ctx context.Context, opt *metadata.SearchHostWithIDOption) (string, error) {
+	if degraded() {
+		return getDegradedHostWithID(ctx, opt)
+	}
+
 	rid := ctx.Value(common.ContextRequestIDField)
 	needRefresh := false
 	data, err := redis.Client().Get(context.Background(), hostKey.HostDetailKey(opt.HostID)).Result()
@@ -49,6 +53,12 @@ func (c *Client) GetHostWithID(ctx context.Context, opt *metadata.SearchHostWith
 	}
 
 	if !needRefresh {
+		data, err = tools.DecompressValue(data)
+		if err != nil {
+			blog.Errorf("get host: %d from redis failed, err: %v, rid: %s", opt.HostID, err, rid)
+			return "", err
+		}
+
 		// already get the data, use cache
 		if len(opt.Fields) == 0 {
 			return data, nil
@@ -88,6 +98,10 @@ func (c *Client) ListHostWithHostIDs(ctx context.Context, opt *metadata.ListWith
 		return nil, errors.New("host id array is empty")
 	}
 
+	if degraded() {
+		return listDegradedHostWithHostIDs(ctx, opt)
+	}
+
 	keys := make([]string, len(opt.IDs))
 	for i, id := range opt.IDs {
 		keys[i] = hostKey.HostDetailKey(id)
@@ -112,6 +126,12 @@ func (c *Client) ListHostWithHostIDs(ctx context.Context, opt *metadata.ListWith
 			return nil, errors.New("invalid host detail type, not string")
 		}
 
+		detail, err = tools.DecompressValue(detail)
+		if err != nil {
+			blog.Errorf("list host with ids, but got invalid host detail, err: %v, rid: %s", err, rid)
+			return nil, err
+		}
+
 		if len(opt.Fields) != 0 {
 			list = append(list, *json.CutJsonDataWithFields(&detail, opt.Fields))
 		} else {
@@ -152,6 +172,10 @@ func (c *Client) GetHostWithInnerIP(ctx context.Context, opt *metadata.SearchHos
 		return "", errors.New("invalid ip address with multiple ip")
 	}
 
+	if degraded() {
+		return getDegradedHostWithInnerIP(ctx, opt)
+	}
+
 	detail, err := c.getHostDetailWithIP(opt.InnerIP, opt.CloudID)
 	if err != nil {
 		blog.Errorf("get host with inner ip: %s failed, err：%v, rid: %s", opt.InnerIP, err, rid)
@@ -192,6 +216,13 @@ func (c *Client) ListHostsWithPage(ctx context.Context, opt *metadata.ListHostWi
 		return 0, nil, errors.New("page size is over limit")
 	}
 
+	if degraded() {
+		if err := acceptDegraded(rid); err != nil {
+			return 0, nil, err
+		}
+		return c.getHostsWithPage(ctx, opt)
+	}
+
 	cnt, idList, details, err := c.getPagedHostDetailList(opt.Page)
 	if err != nil {
 		if err != keyNotExistError {
@@ -0,0 +1,114 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"context"
+	"errors"
+
+	"configcenter/src/apimachinery/flowctrl"
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/json"
+	"configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/redis"
+)
+
+const (
+	// degradedQPS the max qps of the host requests served from mongodb directly while the redis is unavailable.
+	degradedQPS = 200
+	// degradedBurst the burst of the host requests served from mongodb directly while the redis is unavailable.
+	degradedBurst = 400
+)
+
+var (
+	// degradedLimiter limits the host requests served from mongodb directly while the redis is unavailable, so that
+	// the mongodb is not overwhelmed by the requests that used to be served by the cache.
+	degradedLimiter = flowctrl.NewRateLimiter(degradedQPS, degradedBurst)
+
+	errDegradedLimit = errors.New("redis is unavailable, too many requests to get hosts from db")
+)
+
+// degraded returns whether the host cache is degraded, the hosts are got from mongodb directly with reduced
+// throughput and the cache is not refreshed if it is.
+func degraded() bool {
+	return !redis.Availability().Available()
+}
+
+// acceptDegraded checks whether the degraded request can be served from mongodb.
+func acceptDegraded(rid interface{}) error {
+	if !degradedLimiter.TryAccept() {
+		blog.Errorf("redis is unavailable and the degraded host requests exceed the limit, rid: %v", rid)
+		return errDegradedLimit
+	}
+	return nil
+}
+
+func cutHostFields(detail string, fields []string) string {
+	if len(fields) == 0 {
+		return detail
+	}
+	return *json.CutJsonDataWithFields(&detail, fields)
+}
+
+// getDegradedHostWithID gets the host with id from mongodb directly while the redis is unavailable.
+func getDegradedHostWithID(ctx context.Context, opt *metadata.SearchHostWithIDOption) (string, error) {
+	rid := ctx.Value(common.ContextRequestIDField)
+	if err := acceptDegraded(rid); err != nil {
+		return "", err
+	}
+
+	_, _, detail, err := getHostDetailsFromMongoWithHostID(opt.HostID)
+	if err != nil {
+		blog.Errorf("redis is unavailable, get host %d from mongo failed, err: %v, rid: %v", opt.HostID, err, rid)
+		return "", err
+	}
+	return cutHostFields(string(detail), opt.Fields), nil
+}
+
+// listDegradedHostWithHostIDs lists the hosts with ids from mongodb directly while the redis is unavailable.
+func listDegradedHostWithHostIDs(ctx context.Context, opt *metadata.ListWithIDOption) ([]string, error) {
+	rid := ctx.Value(common.ContextRequestIDField)
+	if err := acceptDegraded(rid); err != nil {
+		return nil, err
+	}
+
+	hosts, err := listHostDetailsFromMongoWithHostID(opt.IDs)
+	if err != nil {
+		blog.Errorf("redis is unavailable, list hosts %v from mongo failed, err: %v, rid: %v", opt.IDs, err, rid)
+		return nil, err
+	}
+
+	list := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		list = append(list, cutHostFields(host.detail, opt.Fields))
+	}
+	return list, nil
+}
+
+// getDegradedHostWithInnerIP gets the host with inner ip and cloud id from mongodb directly while the redis is
+// unavailable.
+func getDegradedHostWithInnerIP(ctx context.Context, opt *metadata.SearchHostWithInnerIPOption) (string, error) {
+	rid := ctx.Value(common.ContextRequestIDField)
+	if err := acceptDegraded(rid); err != nil {
+		return "", err
+	}
+
+	_, detail, err := getHostDetailsFromMongoWithIP(opt.InnerIP, opt.CloudID)
+	if err != nil {
+		blog.Errorf("redis is unavailable, get host with inner ip %s, cloud id %d from mongo failed, err: %v, "+
+			"rid: %v", opt.InnerIP, opt.CloudID, err, rid)
+		return "", err
+	}
+	return cutHostFields(string(detail), opt.Fields), nil
+}
@@ -26,6 +26,7 @@ import (
 	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/util"
+	"configcenter/src/source_controller/cacheservice/cache/tools"
 	"configcenter/src/storage/driver/mongodb"
 	"configcenter/src/storage/driver/redis"
 	"configcenter/src/storage/reflector"
@@ -190,8 +191,8 @@ func refreshHostDetailCache(hostID int64, ips string, cloudID int64, hostDetail
 		pipeline.Set(hostKey.IPCloudIDKey(ip, cloudID), hostID, ttl)
 	}
 
-	// update host details
-	pipeline.Set(hostKey.HostDetailKey(hostID), hostDetail, ttl)
+	// update host details, the large host detail is compressed to reduce the redis memory.
+	pipeline.Set(hostKey.HostDetailKey(hostID), tools.CompressValue("host", hostDetail), ttl)
 
 	// add host id to id list.
 	pipeline.ZAddNX(hostKey.HostIDListKey(), &rawRedis.Z{
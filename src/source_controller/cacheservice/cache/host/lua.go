@@ -21,6 +21,7 @@ import (
 
 	"configcenter/src/common/blog"
 	"configcenter/src/common/metadata"
+	"configcenter/src/source_controller/cacheservice/cache/tools"
 	"configcenter/src/storage/driver/redis"
 )
 
@@ -71,7 +72,11 @@ func (c *Client) getHostDetailWithIP(innerIP string, cloudID int64) (*string, er
 		// host detail not exist
 	default:
 		// we have find the data, return directly.
-		return &resp, nil
+		detail, err := tools.DecompressValue(resp)
+		if err != nil {
+			return nil, err
+		}
+		return &detail, nil
 	}
 
 	// now, we need to refresh the cache.
@@ -216,7 +221,11 @@ func (c *Client) getPagedHostDetailList(page metadata.BasePage) (int64, []int64,
 			if !ok {
 				return 0, nil, nil, fmt.Errorf("invalid host detail: %v", idStr)
 			}
-			detailList[idx] = detail
+
+			detailList[idx], err = tools.DecompressValue(detail)
+			if err != nil {
+				return 0, nil, nil, err
+			}
 		}
 
 		return total, idList, detailList, nil
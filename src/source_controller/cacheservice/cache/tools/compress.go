@@ -0,0 +1,117 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metrics"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultCompressThresholdKB is the default size threshold in KB of the cache value to be compressed.
+const defaultCompressThresholdKB = 4
+
+// zstdMagic is the magic number of the zstd frame, the compressed value is distinguished from the json value by it,
+// so that the values cached before the compression is enabled can still be read.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+var (
+	compressOnce      sync.Once
+	compressThreshold int
+	encoder           *zstd.Encoder
+	decoder           *zstd.Decoder
+	compressMetrics   *compressionMetrics
+)
+
+type compressionMetrics struct {
+	ratio *prometheus.HistogramVec
+	bytes *prometheus.CounterVec
+}
+
+func initCompression() {
+	compressOnce.Do(func() {
+		thresholdKB, err := configcenter.Int("cacheService.compressThresholdKB")
+		if err != nil {
+			blog.Warnf("get cache compress threshold failed, err: %v, use default value %d", err,
+				defaultCompressThresholdKB)
+			thresholdKB = defaultCompressThresholdKB
+		}
+		compressThreshold = thresholdKB * 1024
+
+		// the encoder and decoder are safe for concurrent use with EncodeAll and DecodeAll.
+		encoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+		decoder, _ = zstd.NewReader(nil)
+
+		compressMetrics = &compressionMetrics{
+			ratio: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: metrics.Namespace,
+				Subsystem: "cache",
+				Name:      "compression_ratio",
+				Help:      "the ratio of the compressed size to the original size of the cache value",
+				Buckets:   []float64{0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.8, 1},
+			}, []string{"resource"}),
+			bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: metrics.Namespace,
+				Subsystem: "cache",
+				Name:      "compression_bytes_total",
+				Help:      "the total bytes of the compressed cache values, the stage is original or compressed",
+			}, []string{"resource", "stage"}),
+		}
+		metrics.Register().MustRegister(compressMetrics.ratio, compressMetrics.bytes)
+	})
+}
+
+// CompressValue compresses the cache value of the resource with zstd if its size reaches the threshold, which is
+// configured by cacheService.compressThresholdKB, 0 means the compression is disabled. The value smaller than the
+// threshold is returned as it is.
+func CompressValue(resource string, value []byte) []byte {
+	initCompression()
+
+	if compressThreshold <= 0 || len(value) < compressThreshold {
+		return value
+	}
+
+	compressed := encoder.EncodeAll(value, make([]byte, 0, len(value)/4))
+	compressMetrics.ratio.WithLabelValues(resource).Observe(float64(len(compressed)) / float64(len(value)))
+	compressMetrics.bytes.WithLabelValues(resource, "original").Add(float64(len(value)))
+	compressMetrics.bytes.WithLabelValues(resource, "compressed").Add(float64(len(compressed)))
+	return compressed
+}
+
+// DecompressValue decompresses the cache value if it is compressed by CompressValue, otherwise it is returned as
+// it is.
+func DecompressValue(value string) (string, error) {
+	if !IsCompressed(value) {
+		return value, nil
+	}
+
+	initCompression()
+
+	data, err := decoder.DecodeAll([]byte(value), nil)
+	if err != nil {
+		return "", fmt.Errorf("decompress cache value failed, err: %v", err)
+	}
+	return string(data), nil
+}
+
+// IsCompressed checks if the cache value is compressed, the json value never starts with the zstd magic number.
+func IsCompressed(value string) bool {
+	return len(value) >= len(zstdMagic) && bytes.Equal([]byte(value[:len(zstdMagic)]), zstdMagic)
+}
@@ -0,0 +1,40 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressValue(t *testing.T) {
+	small := []byte(`{"bk_host_id":1}`)
+	assert.Equal(t, small, CompressValue("host", small))
+
+	large := []byte(`{"bk_host_id":1,"bk_host_name":"` + strings.Repeat("a", 10*1024) + `"}`)
+	compressed := CompressValue("host", large)
+	assert.Less(t, len(compressed), len(large))
+	assert.True(t, IsCompressed(string(compressed)))
+
+	value, err := DecompressValue(string(compressed))
+	require.NoError(t, err)
+	assert.Equal(t, string(large), value)
+
+	// the value which is not compressed is returned as it is
+	value, err = DecompressValue(string(small))
+	require.NoError(t, err)
+	assert.Equal(t, string(small), value)
+}
@@ -21,6 +21,7 @@ import (
 	"configcenter/src/common/blog"
 	"configcenter/src/common/json"
 	"configcenter/src/common/mapstr"
+	"configcenter/src/source_controller/cacheservice/cache/tools"
 	"configcenter/src/storage/dal"
 	"configcenter/src/storage/dal/redis"
 	"configcenter/src/storage/driver/mongodb"
@@ -138,7 +139,8 @@ func (c *cacheKey) updateTopology(ctx context.Context, topo *BizBriefTopology) e
 		return fmt.Errorf("marshal topology failed, err: %v", err)
 	}
 
-	return c.rds.Set(ctx, c.bizTopologyKey(topo.Biz.ID), string(js), c.ttl).Err()
+	// the topology of the big biz is large, which is compressed to reduce the redis memory.
+	return c.rds.Set(ctx, c.bizTopologyKey(topo.Biz.ID), tools.CompressValue("brief_topology", js), c.ttl).Err()
 }
 
 // getTopology get biz Topology from cache
@@ -153,5 +155,10 @@ func (c *cacheKey) getTopology(ctx context.Context, biz int64) (*string, error)
 		return nil, fmt.Errorf("get cache from redis failed, err: %v", err)
 	}
 
+	dat, err = tools.DecompressValue(dat)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dat, nil
 }
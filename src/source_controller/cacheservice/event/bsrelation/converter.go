@@ -14,7 +14,6 @@ package bsrelation
 
 import (
 	"context"
-	"reflect"
 	"sync"
 	"time"
 
@@ -96,9 +95,9 @@ func (b *bizSetRelation) getNeedCareBizFields(ctx context.Context) (map[string]s
 
 // rearrangeBizSetEvents TODO
 // biz set events rearrange policy:
-// 1. If update event's updated fields do not contain "bk_scope" field, we will drop this event.
-// 2. Aggregate multiple same biz set's events to one event, so that we can decrease the amount of biz set relation
-//    events. Because we only care about which biz set's relation is changed, one event is enough for us.
+//  1. If update event's updated fields do not contain "bk_scope" field, we will drop this event.
+//  2. Aggregate multiple same biz set's events to one event, so that we can decrease the amount of biz set relation
+//     events. Because we only care about which biz set's relation is changed, one event is enough for us.
 func (b *bizSetRelation) rearrangeBizSetEvents(es []*types.Event, rid string) ([]*types.Event, error) {
 
 	// get last biz set event type, in order to rearrange biz set events later, policy:
@@ -235,12 +234,12 @@ type delArchive struct {
 
 // rearrangeBizEvents TODO
 // biz events rearrange policy:
-// 1. Biz event is redirected to its related biz sets' events by traversing all biz sets and checking if the "bk_scope"
-//    field matches the biz's attribute.
-// 2. Create and delete event's related biz set is judged by whether its scope contains the biz.
-// 3. Update event's related biz set is judged by whether its scope contains the updated fields of the event. Since
-//    we can't get the previous value of the updated fields, we can't get the exact biz sets it was in before.
-// 4. Aggregate multiple biz events with the same biz set to one event.
+//  1. Biz event is redirected to its related biz sets' events by traversing all biz sets and checking if the "bk_scope"
+//     field matches the biz's attribute.
+//  2. Create and delete event's related biz set is judged by whether its scope contains the biz.
+//  3. Update event's related biz set is judged by whether its scope contains the updated fields of the event. Since
+//     we can't get the previous value of the updated fields, we can't get the exact biz sets it was in before.
+//  4. Aggregate multiple biz events with the same biz set to one event.
 func (b *bizSetRelation) rearrangeBizEvents(es []*types.Event, rid string) ([]*types.Event, error) {
 
 	// get delete event oids from delete events, and get deleted biz detail by oids to find matching biz sets.
@@ -503,7 +502,13 @@ func (b *bizSetRelation) getRelatedBizSets(params convertBizEventParams, bizSets
 				case querybuilder.OperatorEqual:
 					return matchEqualOper(r.Value, bizVal, propertyType, params.rid)
 				case querybuilder.OperatorIn:
-					return matchInOper(r.Value, bizVal, propertyType, params.rid)
+					ruleValues, err := r.GetSliceValue()
+					if err != nil {
+						blog.Errorf("biz set(%+v) filter rule value is invalid, err: %v, rid: %s", bizSet, err,
+							params.rid)
+						return false
+					}
+					return matchInOper(ruleValues, bizVal, propertyType, params.rid)
 				default:
 					blog.Errorf("biz set(%+v) filter rule contains invalid operator, rid: %s", bizSet, params.rid)
 					return false
@@ -569,21 +574,10 @@ func matchEqualOper(ruleVal, bizVal interface{}, propertyType string, rid string
 }
 
 // matchInOper check if biz set scope filter rule with in operator matches biz value
-func matchInOper(ruleVal, bizVal interface{}, propertyType string, rid string) bool {
-	switch reflect.TypeOf(ruleVal).Kind() {
-	case reflect.Array:
-	case reflect.Slice:
-	default:
-		blog.Errorf("rule value(%+v) type is invalid, rid: %s", ruleVal, rid)
-		return false
-	}
-
-	ruleValArr := reflect.ValueOf(ruleVal)
-	ruleValLen := ruleValArr.Len()
-	for i := 0; i < ruleValLen; i++ {
+func matchInOper(ruleValues []interface{}, bizVal interface{}, propertyType string, rid string) bool {
+	for _, ruleVal := range ruleValues {
 		// check if any of the rule value matches biz value
-		matched := matchEqualOper(ruleValArr.Index(i).Interface(), bizVal, propertyType, rid)
-		if matched {
+		if matchEqualOper(ruleVal, bizVal, propertyType, rid) {
 			return true
 		}
 	}
@@ -222,7 +222,7 @@ func (b *bizSetRelation) getBizSetRelationDetail(e *types.Event, rid string) (st
 	}
 
 	// parse biz condition from biz set scope filter, get biz ids using it to gen relation detail
-	bizSetBizCond, _, rawErr := bizSet.Scope.Filter.ToMgo()
+	bizSetBizCond, _, rawErr := event.BizSetScopeCond(bizSet.Scope.Filter)
 	if rawErr != nil {
 		blog.Errorf("parse biz set scope(%#v) failed, err: %v, rid: %s", bizSet.Scope, rawErr, rid)
 		return "", rawErr
@@ -175,6 +175,10 @@ func parseInstAsstEvent(key event.Key, e *types.Event, oidDetailMap map[oidCollK
 	asstObjID := gjson.GetBytes(e.DocBytes, common.BKAsstObjIDField).String()
 	chainNode.SubResource = []string{objID, asstObjID}
 
+	if chainNode.EventType == watch.Update {
+		chainNode.ChangedFields = watch.GetChangedFields(e.ChangeDesc)
+	}
+
 	detail := types.EventDetail{
 		Detail:        types.JsonString(e.DocBytes),
 		UpdatedFields: e.ChangeDesc.UpdatedFields,
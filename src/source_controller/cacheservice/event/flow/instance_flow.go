@@ -171,6 +171,26 @@ func (f *InstanceFlow) doBatch(es []*types.Event) (retry bool) {
 		f.metrics.CollectCycleDuration(time.Since(start))
 	}()
 
+	lastTokenData := map[string]interface{}{
+		common.BKTokenField:       es[eventLen-1].Token.Data,
+		common.BKStartAtTimeField: es[eventLen-1].ClusterTime,
+	}
+
+	es, err := event.FilterSuppressedEvents(es, rid)
+	if err != nil {
+		return true
+	}
+
+	eventLen = len(es)
+	if eventLen == 0 {
+		if err := f.tokenHandler.setLastWatchToken(context.Background(), lastTokenData); err != nil {
+			f.metrics.CollectMongoError()
+			return false
+		}
+		hasError = false
+		return false
+	}
+
 	oidDetailMap, retry, err := f.getDeleteEventDetails(es, f.ccDB, f.metrics)
 	if err != nil {
 		blog.Errorf("get deleted event details failed, err: %v, rid: %s", err, rid)
@@ -232,11 +252,6 @@ func (f *InstanceFlow) doBatch(es []*types.Event) (retry bool) {
 		}
 	}
 
-	lastTokenData := map[string]interface{}{
-		common.BKTokenField:       es[eventLen-1].Token.Data,
-		common.BKStartAtTimeField: es[eventLen-1].ClusterTime,
-	}
-
 	// if all events are invalid, set last token to the last events' token, do not need to retry for the invalid ones
 	if len(chainNodesMap) == 0 {
 		if err := f.tokenHandler.setLastWatchToken(context.Background(), lastTokenData); err != nil {
@@ -158,6 +158,27 @@ func (f *MixEventFlow) doBatch(es []*types.Event) (retry bool) {
 		f.metrics.CollectCycleDuration(time.Since(start))
 	}()
 
+	// last event in original events is used to generate
+	lastEvent := es[len(es)-1]
+	lastTokenData := mapstr.MapStr{
+		common.BKTokenField:       lastEvent.Token.Data,
+		common.BKStartAtTimeField: lastEvent.ClusterTime,
+	}
+
+	es, err := event.FilterSuppressedEvents(es, rid)
+	if err != nil {
+		return true
+	}
+
+	if len(es) == 0 {
+		if err := f.tokenHandler.setLastWatchToken(context.Background(), lastTokenData); err != nil {
+			f.metrics.CollectMongoError()
+			return false
+		}
+		hasError = false
+		return false
+	}
+
 	// rearranging mix events
 	events, err := f.rearrangeEvents(rid, es)
 	if err != nil {
@@ -176,13 +197,6 @@ func (f *MixEventFlow) doBatch(es []*types.Event) (retry bool) {
 	// release the lock when the job is done or failed.
 	defer f.releaseLock(rid)
 
-	// last event in original events is used to generate
-	lastEvent := es[len(es)-1]
-	lastTokenData := mapstr.MapStr{
-		common.BKTokenField:       lastEvent.Token.Data,
-		common.BKStartAtTimeField: lastEvent.ClusterTime,
-	}
-
 	// handle the rearranged events
 	retry, err = f.handleEvents(events, lastTokenData, rid)
 	if err != nil {
@@ -13,12 +13,20 @@
 package event
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"configcenter/src/common"
+	"configcenter/src/common/blog"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/metrics"
+	"configcenter/src/common/querybuilder"
 	"configcenter/src/common/watch"
+	"configcenter/src/storage/dal/mongo/local"
+	"configcenter/src/storage/driver/redis"
+	"configcenter/src/storage/stream/types"
 )
 
 // GetResourceKeyWithCursorType TODO
@@ -71,6 +79,48 @@ func IsConflictError(err error) bool {
 	return false
 }
 
+// FilterSuppressedEvents filters out the events made by the transactions that are marked to suppress the watch
+// events, e.g. the transactions of the repair or migration jobs, returns the events to be handled.
+func FilterSuppressedEvents(es []*types.Event, rid string) ([]*types.Event, error) {
+	sessionIDs := make([]string, 0)
+	sessionMap := make(map[string]struct{})
+	for _, e := range es {
+		if len(e.SessionID) == 0 {
+			continue
+		}
+		if _, exists := sessionMap[e.SessionID]; exists {
+			continue
+		}
+		sessionMap[e.SessionID] = struct{}{}
+		sessionIDs = append(sessionIDs, e.SessionID)
+	}
+
+	if len(sessionIDs) == 0 {
+		return es, nil
+	}
+
+	suppressed, err := local.ListSuppressEventSessions(context.Background(), redis.Client(), sessionIDs)
+	if err != nil {
+		blog.Errorf("list suppress event sessions failed, sessions: %v, err: %v, rid: %s", sessionIDs, err, rid)
+		return nil, err
+	}
+
+	if len(suppressed) == 0 {
+		return es, nil
+	}
+
+	events := make([]*types.Event, 0, len(es))
+	for _, e := range es {
+		if _, exists := suppressed[e.SessionID]; exists {
+			continue
+		}
+		events = append(events, e)
+	}
+
+	blog.Infof("skip %d events of the suppress event transactions, rid: %s", len(es)-len(events), rid)
+	return events, nil
+}
+
 // HostArchive TODO
 type HostArchive struct {
 	Oid    string              `bson:"oid"`
@@ -82,3 +132,23 @@ const ObjInstTablePrefixRegex = "^" + common.BKObjectInstShardingTablePrefix
 
 // InstAsstTablePrefixRegex TODO
 const InstAsstTablePrefixRegex = "^" + common.BKObjectInstAsstShardingTablePrefix
+
+var (
+	bizSetScopeCache     *querybuilder.CompiledCache
+	bizSetScopeCacheOnce sync.Once
+)
+
+// BizSetScopeCond returns the mongo condition of the biz set scope filter, the compiled filter is cached since the
+// scope filter is converted repeatedly to generate the biz set relation events.
+func BizSetScopeCond(filter *querybuilder.QueryFilter) (map[string]interface{}, string, error) {
+	bizSetScopeCacheOnce.Do(func() {
+		bizSetScopeCache = querybuilder.NewCompiledCache(querybuilder.CompiledCacheOption{Name: "biz_set_scope"},
+			metrics.Register())
+	})
+
+	compiled, key, err := bizSetScopeCache.Compile(filter, nil)
+	if err != nil {
+		return nil, key, err
+	}
+	return compiled.MgoFilter()
+}
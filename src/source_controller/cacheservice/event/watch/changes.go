@@ -0,0 +1,157 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package watch
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/watch"
+	"configcenter/src/source_controller/cacheservice/event"
+)
+
+// ListChanges lists the ids of the resources changed since the token or the start from time without holding the
+// request, the events are scanned in chain order up to the limit and aggregated by the instance id. if neither token
+// nor start from is set, the token of the latest event is returned as the start point of the following listings.
+func (c *Client) ListChanges(kit *rest.Kit, key event.Key, opts *watch.ListChangesOptions) (
+	*watch.ListChangesResult, error) {
+
+	var token string
+	var nodes []*watch.ChainNode
+	var err error
+	switch {
+	case len(opts.Token) != 0:
+		token, nodes, err = c.listChangedNodesWithToken(kit, key, opts)
+	case opts.StartFrom != 0:
+		token, nodes, err = c.listChangedNodesWithStartFrom(kit, key, opts)
+	default:
+		token, err = c.getLatestCursor(kit, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &watch.ListChangesResult{Token: token, HasMore: uint64(len(nodes)) >= opts.Limit}
+	if len(nodes) > 0 {
+		// the token is moved to the last scanned node even if it does not hit the sub resource, so that the
+		// unmatched nodes are not scanned again in the next listing.
+		result.Token = nodes[len(nodes)-1].Cursor
+	}
+
+	hitNodes := make([]*watch.ChainNode, 0, len(nodes))
+	for _, node := range nodes {
+		if c.isNodeHitSubResource(node, opts.SubResource) {
+			hitNodes = append(hitNodes, node)
+		}
+	}
+	result.UpsertedIDs, result.DeletedIDs = watch.AggregateChanges(hitNodes)
+	return result, nil
+}
+
+// listChangedNodesWithToken list the chain nodes after the token, the token is returned as it is if no node is found.
+func (c *Client) listChangedNodesWithToken(kit *rest.Kit, key event.Key, opts *watch.ListChangesOptions) (string,
+	[]*watch.ChainNode, error) {
+
+	searchOpt := &searchFollowingChainNodesOption{
+		startCursor: opts.Token,
+		limit:       opts.Limit,
+		key:         key,
+	}
+	exists, nodes, _, err := c.searchFollowingEventChainNodes(kit, searchOpt)
+	if err != nil {
+		blog.Errorf("search nodes after token %s failed, err: %v, rid: %s", opts.Token, err, kit.Rid)
+		return "", nil, err
+	}
+
+	// the token is expired, the consumer needs to do a full sync and list with a new token.
+	if !exists && opts.Token != watch.NoEventCursor {
+		return "", nil, kit.CCError.CCError(common.CCErrEventChainNodeNotExist)
+	}
+
+	// the head node is included when starting from the beginning, which may exceed the limit.
+	if uint64(len(nodes)) > opts.Limit {
+		nodes = nodes[:opts.Limit]
+	}
+	return opts.Token, nodes, nil
+}
+
+// listChangedNodesWithStartFrom list the chain nodes whose cluster time is after the start from time, the token of
+// the latest event is returned if no node is found.
+func (c *Client) listChangedNodesWithStartFrom(kit *rest.Kit, key event.Key, opts *watch.ListChangesOptions) (
+	string, []*watch.ChainNode, error) {
+
+	diff := time.Now().Unix() - opts.StartFrom
+	if diff < 0 || diff > key.TTLSeconds() {
+		return "", nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "bk_start_from")
+	}
+
+	filter := map[string]interface{}{
+		common.BKClusterTimeField: map[string]interface{}{
+			common.BKDBGT: metadata.Time{Time: time.Unix(opts.StartFrom, 0).Local()},
+		},
+	}
+
+	// filters out the previous version where sub resource is string type // TODO remove this
+	if key.Collection() == common.BKTableNameBaseInst ||
+		key.Collection() == common.BKTableNameMainlineInstance {
+		filter[common.BKSubResourceField] = map[string]interface{}{common.BKDBType: "array"}
+	}
+
+	node := new(watch.ChainNode)
+	err := c.watchDB.Table(key.ChainCollection()).Find(filter).Sort(common.BKFieldID).One(kit.Ctx, node)
+	if err != nil {
+		if !c.watchDB.IsNotFoundError(err) {
+			blog.ErrorJSON("get chain node from mongo failed, err: %s, filter: %s, rid: %s", err, filter, kit.Rid)
+			return "", nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+		}
+
+		token, err := c.getLatestCursor(kit, key)
+		return token, nil, err
+	}
+
+	// the first node is after the start time, so it is included in the changed nodes.
+	nodes := []*watch.ChainNode{node}
+	if opts.Limit <= 1 {
+		return node.Cursor, nodes, nil
+	}
+
+	searchOpt := &searchFollowingChainNodesOption{
+		id:    node.ID,
+		limit: opts.Limit - 1,
+		key:   key,
+	}
+	following, err := c.searchFollowingEventChainNodesByID(kit, searchOpt)
+	if err != nil {
+		blog.Errorf("search nodes after node %d failed, err: %v, rid: %s", node.ID, err, kit.Rid)
+		return "", nil, err
+	}
+
+	return node.Cursor, append(nodes, following...), nil
+}
+
+// getLatestCursor get the cursor of the latest event, returns no event cursor if there's no event.
+func (c *Client) getLatestCursor(kit *rest.Kit, key event.Key) (string, error) {
+	node, exists, err := c.getLatestEvent(kit, key)
+	if err != nil {
+		blog.Errorf("get latest event failed, err: %v, rid: %s", err, kit.Rid)
+		return "", err
+	}
+
+	if !exists {
+		return watch.NoEventCursor, nil
+	}
+	return node.Cursor, nil
+}
@@ -295,18 +295,20 @@ func (c *Client) searchFollowingEventChainNodes(kit *rest.Kit, opts *searchFollo
 		}
 
 		idOpts := &searchFollowingChainNodesOption{
-			id:          node.ID,
-			limit:       opts.limit,
-			types:       opts.types,
-			key:         opts.key,
-			subResource: opts.subResource,
+			id:            node.ID,
+			limit:         opts.limit,
+			types:         opts.types,
+			key:           opts.key,
+			subResource:   opts.subResource,
+			changedFields: opts.changedFields,
 		}
 		nodes, err := c.searchFollowingEventChainNodesByID(kit, idOpts)
 		if err != nil {
 			return false, nil, 0, err
 		}
 
-		if c.isNodeHitEventType(node, opts.types) && c.isNodeHitSubResource(node, opts.subResource) {
+		if c.isNodeHitEventType(node, opts.types) && c.isNodeHitSubResource(node, opts.subResource) &&
+			c.isNodeHitChangedFields(node, opts.changedFields) {
 			return true, append([]*watch.ChainNode{node}, nodes...), node.ID, nil
 		}
 		return true, nodes, node.ID, nil
@@ -402,6 +404,15 @@ func (c *Client) searchFollowingEventChainNodesByID(kit *rest.Kit, opt *searchFo
 		}
 	}
 
+	if len(opt.changedFields) > 0 {
+		// only filter the update events with changed fields, the others are always hit
+		filter[common.BKDBOR] = []map[string]interface{}{
+			{common.BKEventTypeField: map[string]interface{}{common.BKDBNE: watch.Update}},
+			{common.BKChangedFieldsField: map[string]interface{}{common.BKDBExists: false}},
+			{common.BKChangedFieldsField: map[string]interface{}{common.BKDBIN: opt.changedFields}},
+		}
+	}
+
 	nodes := make([]*watch.ChainNode, 0)
 	if err := c.watchDB.Table(opt.key.ChainCollection()).Find(filter).Sort(common.BKFieldID).Limit(opt.limit).
 		All(kit.Ctx, &nodes); err != nil {
@@ -669,10 +680,11 @@ type mapStrWithOid struct {
 }
 
 type searchFollowingChainNodesOption struct {
-	id          uint64
-	startCursor string
-	limit       uint64
-	types       []watch.EventType
-	key         event.Key
-	subResource string
+	id            uint64
+	startCursor   string
+	limit         uint64
+	types         []watch.EventType
+	key           event.Key
+	subResource   string
+	changedFields []string
 }
@@ -81,7 +81,9 @@ func (c *Client) WatchWithStartFrom(kit *rest.Kit, key event.Key, opts *watch.Wa
 
 	// start from is ahead of the latest's event time, watch from now.
 	if int64(tailNode.ClusterTime.Sec) <= opts.StartFrom {
-		if !c.isNodeHitEventType(tailNode, opts.EventTypes) || !c.isNodeHitSubResource(tailNode, opts.Filter.SubResource) {
+		if !c.isNodeHitEventType(tailNode, opts.EventTypes) ||
+			!c.isNodeHitSubResource(tailNode, opts.Filter.SubResource) ||
+			!c.isNodeHitChangedFields(tailNode, opts.Filter.ChangedFields) {
 			// not matched, set to no event cursor with empty detail
 			return []*watch.WatchEventDetail{{
 				Cursor:    watch.NoEventCursor,
@@ -102,9 +104,10 @@ func (c *Client) WatchWithStartFrom(kit *rest.Kit, key event.Key, opts *watch.Wa
 		}
 
 		event := &watch.WatchEventDetail{
-			Cursor:    tailNode.Cursor,
-			Resource:  opts.Resource,
-			EventType: tailNode.EventType,
+			Cursor:        tailNode.Cursor,
+			Resource:      opts.Resource,
+			EventType:     tailNode.EventType,
+			ChangedFields: tailNode.ChangedFields,
 		}
 
 		if detail == nil {
@@ -154,11 +157,12 @@ func (c *Client) WatchWithStartFrom(kit *rest.Kit, key event.Key, opts *watch.Wa
 	}
 
 	searchOpt := &searchFollowingChainNodesOption{
-		id:          node.ID,
-		limit:       eventStep,
-		types:       opts.EventTypes,
-		key:         key,
-		subResource: opts.Filter.SubResource,
+		id:            node.ID,
+		limit:         eventStep,
+		types:         opts.EventTypes,
+		key:           key,
+		subResource:   opts.Filter.SubResource,
+		changedFields: opts.Filter.ChangedFields,
 	}
 	nodes, err := c.searchFollowingEventChainNodesByID(kit, searchOpt)
 	if err != nil {
@@ -167,7 +171,8 @@ func (c *Client) WatchWithStartFrom(kit *rest.Kit, key event.Key, opts *watch.Wa
 	}
 
 	// since the first node is after the start time, we need to include it in the nodes after the start time
-	if c.isNodeHitEventType(node, opts.EventTypes) && c.isNodeHitSubResource(node, opts.Filter.SubResource) {
+	if c.isNodeHitEventType(node, opts.EventTypes) && c.isNodeHitSubResource(node, opts.Filter.SubResource) &&
+		c.isNodeHitChangedFields(node, opts.Filter.ChangedFields) {
 		nodes = append([]*watch.ChainNode{node}, nodes...)
 	}
 
@@ -220,10 +225,11 @@ func (c *Client) getEventDetailsWithNodes(kit *rest.Kit, opts *watch.WatchEventO
 			jsonStr := types.GetEventDetail(&detail)
 			detail = *json.CutJsonDataWithFields(jsonStr, opts.Fields)
 			resp[idx] = &watch.WatchEventDetail{
-				Cursor:    hitNodes[idx].Cursor,
-				Resource:  opts.Resource,
-				EventType: hitNodes[idx].EventType,
-				Detail:    watch.JsonString(detail),
+				Cursor:        hitNodes[idx].Cursor,
+				Resource:      opts.Resource,
+				EventType:     hitNodes[idx].EventType,
+				ChangedFields: hitNodes[idx].ChangedFields,
+				Detail:        watch.JsonString(detail),
 			}
 		}
 		return resp, nil
@@ -259,10 +265,11 @@ func (c *Client) getEventDetailsWithNodes(kit *rest.Kit, opts *watch.WatchEventO
 		}
 
 		resp[idx] = &watch.WatchEventDetail{
-			Cursor:    hitNodes[idx].Cursor,
-			Resource:  opts.Resource,
-			EventType: hitNodes[idx].EventType,
-			Detail:    watch.JsonString(detail),
+			Cursor:        hitNodes[idx].Cursor,
+			Resource:      opts.Resource,
+			EventType:     hitNodes[idx].EventType,
+			ChangedFields: hitNodes[idx].ChangedFields,
+			Detail:        watch.JsonString(detail),
 		}
 	}
 	return resp, nil
@@ -476,7 +483,7 @@ func (c *Client) getBizSetRelationEventDetailFromMongo(kit *rest.Kit, bizSetIDs
 			continue
 		}
 
-		bizSetBizCond, errKey, rawErr := bizSet.Scope.Filter.ToMgo()
+		bizSetBizCond, errKey, rawErr := event.BizSetScopeCond(bizSet.Scope.Filter)
 		if rawErr != nil {
 			blog.Errorf("parse biz set scope(%#v) failed, err: %v, rid: %s", bizSet.Scope, rawErr, kit.Rid)
 			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, errKey)
@@ -550,7 +557,8 @@ func (c *Client) WatchFromNow(kit *rest.Kit, key event.Key, opts *watch.WatchEve
 		}, nil
 	}
 
-	if !c.isNodeHitEventType(node, opts.EventTypes) || !c.isNodeHitSubResource(node, opts.Filter.SubResource) {
+	if !c.isNodeHitEventType(node, opts.EventTypes) || !c.isNodeHitSubResource(node, opts.Filter.SubResource) ||
+		!c.isNodeHitChangedFields(node, opts.Filter.ChangedFields) {
 		// not matched, set to no event cursor with empty detail
 		return &watch.WatchEventDetail{
 			Cursor:    watch.NoEventCursor,
@@ -571,9 +579,10 @@ func (c *Client) WatchFromNow(kit *rest.Kit, key event.Key, opts *watch.WatchEve
 	}
 
 	e := &watch.WatchEventDetail{
-		Cursor:    node.Cursor,
-		Resource:  opts.Resource,
-		EventType: node.EventType,
+		Cursor:        node.Cursor,
+		Resource:      opts.Resource,
+		EventType:     node.EventType,
+		ChangedFields: node.ChangedFields,
 	}
 
 	if detail == nil {
@@ -606,11 +615,12 @@ func (c *Client) WatchWithCursor(kit *rest.Kit, key event.Key, opts *watch.Watch
 	start := time.Now().Unix()
 
 	searchOpt := &searchFollowingChainNodesOption{
-		startCursor: opts.Cursor,
-		limit:       eventStep,
-		types:       opts.EventTypes,
-		key:         key,
-		subResource: opts.Filter.SubResource,
+		startCursor:   opts.Cursor,
+		limit:         eventStep,
+		types:         opts.EventTypes,
+		key:           key,
+		subResource:   opts.Filter.SubResource,
+		changedFields: opts.Filter.ChangedFields,
 	}
 	exists, nodes, nodeID, err := c.searchFollowingEventChainNodes(kit, searchOpt)
 	if err != nil {
@@ -711,3 +721,20 @@ func (c *Client) isNodeHitSubResource(node *watch.ChainNode, subResource string)
 
 	return false
 }
+
+// isNodeHitChangedFields check if node hit the changed fields, not specifying changed fields means matching all.
+// only update events are filtered, and the update events that have no changed fields recorded are always hit,
+// because their changes are unknown.
+func (c *Client) isNodeHitChangedFields(node *watch.ChainNode, changedFields []string) bool {
+	if len(changedFields) == 0 || node.EventType != watch.Update || len(node.ChangedFields) == 0 {
+		return true
+	}
+
+	for _, field := range node.ChangedFields {
+		if util.InStrArr(changedFields, field) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -300,7 +300,7 @@ func (s *cacheService) WatchEvent(ctx *rest.Contexts) {
 		}
 
 		// if not events is hit, then we return user's cursor, so that they can watch with this cursor again.
-		ctx.RespEntity(s.generateWatchEventResp(options.Cursor, options.Resource, events))
+		s.respWatchEvents(ctx, options.Cursor, options, events)
 		return
 	}
 
@@ -314,7 +314,7 @@ func (s *cacheService) WatchEvent(ctx *rest.Contexts) {
 			return
 		}
 
-		ctx.RespEntity(s.generateWatchEventResp("", options.Resource, events))
+		s.respWatchEvents(ctx, "", options, events)
 		return
 	}
 
@@ -326,7 +326,57 @@ func (s *cacheService) WatchEvent(ctx *rest.Contexts) {
 		return
 	}
 
-	ctx.RespEntity(s.generateWatchEventResp("", options.Resource, []*watch.WatchEventDetail{events}))
+	s.respWatchEvents(ctx, "", options, []*watch.WatchEventDetail{events})
+}
+
+// ListChanges lists the ids of the resources changed since the token without holding the request
+func (s *cacheService) ListChanges(ctx *rest.Contexts) {
+	options := new(watch.ListChangesOptions)
+	if err := ctx.DecodeInto(&options); err != nil {
+		blog.Errorf("list changes, but decode request body failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if err := options.Validate(); err != nil {
+		blog.Errorf("list changes, but got invalid request options, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, err.Error()))
+		return
+	}
+
+	key, err := event.GetResourceKeyWithCursorType(options.Resource)
+	if err != nil {
+		blog.Errorf("list changes, but get resource key with cursor type failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	// read all data from db in case secondary node's latency causes data inconsistency
+	util.SetDBReadPreference(ctx.Kit.Ctx, common.PrimaryMode)
+
+	result, err := s.cacheSet.Event.ListChanges(ctx.Kit, key, options)
+	if err != nil {
+		blog.Errorf("list changes failed, options: %+v, err: %v, rid: %s", options, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(result)
+}
+
+// respWatchEvents responds the watched events in the schema version requested by the user.
+func (s *cacheService) respWatchEvents(ctx *rest.Contexts, startCursor string, options *watch.WatchEventOptions,
+	events []*watch.WatchEventDetail) {
+
+	result := s.generateWatchEventResp(startCursor, options.Resource, events)
+	if err := result.ConvertSchema(options.SchemaVersion); err != nil {
+		blog.Errorf("convert watch events to schema %s failed, err: %v, rid: %s", options.SchemaVersion, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommJSONMarshalFailed))
+		return
+	}
+
+	ctx.RespEntity(result)
 }
 
 func (s *cacheService) generateWatchEventResp(startCursor string, rsc watch.CursorType,
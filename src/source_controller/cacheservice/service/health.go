@@ -14,6 +14,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"configcenter/src/common"
 	"configcenter/src/common/metadata"
@@ -48,11 +50,15 @@ func (s *cacheService) Healthz(req *restful.Request, resp *restful.Response) {
 	}
 	meta.Items = append(meta.Items, mongoItem)
 
-	// redis status
+	// redis status, the host cache falls back to mongodb while the redis is unavailable, so it is degraded
 	redisItem := metric.HealthItem{IsHealthy: true, Name: types.CCFunctionalityRedis}
 	if redis.Client() == nil {
 		redisItem.IsHealthy = false
 		redisItem.Message = "not connected"
+	} else if available, since, err := redis.Availability().Status(); !available {
+		redisItem = metric.NewDegradedHealthItem(types.CCFunctionalityRedis,
+			fmt.Errorf("redis is unavailable since %s, host cache is served from mongodb, err: %v",
+				since.Format(time.RFC3339), err))
 	} else if err := redis.Client().Ping(context.Background()).Err(); err != nil {
 		redisItem.IsHealthy = false
 		redisItem.Message = err.Error()
@@ -65,6 +71,10 @@ func (s *cacheService) Healthz(req *restful.Request, resp *restful.Response) {
 			meta.Message = "cacheservice is unhealthy"
 			break
 		}
+		if item.Degraded {
+			meta.Degraded = true
+			meta.Message = "cacheservice is degraded"
+		}
 	}
 
 	info := metric.HealthInfo{
@@ -95,6 +95,11 @@ func (s *cacheService) initCache(web *restful.WebService) {
 		Path:    "/watch/cache/event",
 		Handler: s.WatchEvent,
 	})
+	utility.AddHandler(rest.Action{
+		Verb:    http.MethodPost,
+		Path:    "/watch/cache/changes",
+		Handler: s.ListChanges,
+	})
 
 	utility.AddToRestfulWebService(web)
 }
@@ -14,7 +14,10 @@
 package options
 
 import (
+	"time"
+
 	"configcenter/src/common/core/cc/config"
+	"configcenter/src/common/metadata"
 	"configcenter/src/storage/dal/mongo"
 	"configcenter/src/storage/dal/redis"
 
@@ -28,8 +31,18 @@ type ServerOption struct {
 
 // Config export
 type Config struct {
-	Mongo mongo.Config
-	Redis redis.Config
+	Mongo         mongo.Config
+	Redis         redis.Config
+	HostLifecycle *metadata.HostLifecycleConfig
+	AuditDetail   *metadata.AuditDetailConfig
+	// InstNameCollation the policy to compare the instance names in the uniqueness checks
+	InstNameCollation *metadata.InstNameCollation
+	// TxnMaxLifetime the transactions that live longer than it are force aborted by the watchdog
+	TxnMaxLifetime time.Duration
+	// AttributeUsage the config of the sampled attribute usage tracking
+	AttributeUsage *metadata.AttributeUsageConfig
+	// ImportConflict the config of the conflict resolution of the concurrent imports
+	ImportConflict *metadata.ImportConflictConfig
 }
 
 // NewServerOption create a ServerOption object
@@ -15,6 +15,9 @@ package app
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
 	"time"
 
 	"configcenter/src/common"
@@ -22,11 +25,48 @@ import (
 	cc "configcenter/src/common/backbone/configcenter"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
 	"configcenter/src/common/types"
 	"configcenter/src/source_controller/coreservice/app/options"
+	"configcenter/src/source_controller/coreservice/core/attrusage"
+	"configcenter/src/source_controller/coreservice/core/importconflict"
 	coresvr "configcenter/src/source_controller/coreservice/service"
 	"configcenter/src/storage/driver/mongodb"
 	"configcenter/src/storage/driver/redis"
+
+	"gopkg.in/yaml.v2"
+)
+
+// hostLifecycleFileKey the config key of the file that defines the host lifecycle state machine
+const hostLifecycleFileKey = "coreService.hostLifecycle.file"
+
+const (
+	// txnMaxLifetimeKey the config key of the max lifetime seconds of the transactions
+	txnMaxLifetimeKey = "coreService.transaction.maxLifetimeSeconds"
+	// defaultTxnMaxLifetime the default max lifetime of the transactions, which is much longer than the default
+	// transaction timeout, so that only the stuck transactions are aborted.
+	defaultTxnMaxLifetime = 10 * time.Minute
+	// txnWatchdogInterval the interval of the watchdog to check the transactions that exceed the max lifetime
+	txnWatchdogInterval = 30 * time.Second
+)
+
+// auditDetailFileKey the config key of the file that defines the audit detail levels of the resources
+const auditDetailFileKey = "coreService.auditDetail.file"
+
+// the config keys of the instance name collation policy used by the instance name uniqueness checks
+const (
+	instNameCaseInsensitiveKey  = "coreService.instNameCollation.caseInsensitive"
+	instNameTrimSpaceKey        = "coreService.instNameCollation.trimSpace"
+	instNameWidthInsensitiveKey = "coreService.instNameCollation.widthInsensitive"
+)
+
+// attrUsageSamplePercentKey the config key of the percent of the requests sampled to track the attribute usages
+const attrUsageSamplePercentKey = "coreService.attributeUsage.samplePercent"
+
+// the config keys of the conflict resolution of the concurrent imports
+const (
+	importConflictWindowKey   = "coreService.importConflict.windowSeconds"
+	importConflictPriorityKey = "coreService.importConflict.sourcePriority"
 )
 
 // CoreServer the core server
@@ -43,6 +83,200 @@ func (t *CoreServer) onCoreServiceConfigUpdate(previous, current cc.ProcessConfi
 
 	blog.V(3).Infof("the new cfg:%#v the origin cfg:%#v", t.Config, string(current.ConfigData))
 
+	hostLifecycle, err := parseHostLifecycle()
+	if err != nil {
+		blog.Errorf("parse host lifecycle config failed, use the default one, err: %v", err)
+		hostLifecycle = metadata.DefaultHostLifecycleConfig()
+	}
+	t.Config.HostLifecycle = hostLifecycle
+
+	auditDetail, err := parseAuditDetail()
+	if err != nil {
+		blog.Errorf("parse audit detail config failed, use the default one, err: %v", err)
+		auditDetail = metadata.DefaultAuditDetailConfig()
+	}
+	t.Config.AuditDetail = auditDetail
+
+	t.Config.InstNameCollation = &metadata.InstNameCollation{
+		CaseInsensitive:  parseBoolConfig(instNameCaseInsensitiveKey),
+		TrimSpace:        parseBoolConfig(instNameTrimSpaceKey),
+		WidthInsensitive: parseBoolConfig(instNameWidthInsensitiveKey),
+	}
+
+	t.Config.AttributeUsage = &metadata.AttributeUsageConfig{
+		SamplePercent: metadata.AttributeUsageDefaultSamplePercent,
+	}
+	if cc.IsExist(attrUsageSamplePercentKey) {
+		percent, err := cc.Int(attrUsageSamplePercentKey)
+		if err != nil || percent < 0 || percent > 100 {
+			blog.Errorf("config %s is invalid, use the default value %d, err: %v", attrUsageSamplePercentKey,
+				metadata.AttributeUsageDefaultSamplePercent, err)
+		} else {
+			t.Config.AttributeUsage.SamplePercent = percent
+		}
+	}
+
+	t.Config.ImportConflict = parseImportConflict()
+
+	t.Config.TxnMaxLifetime = defaultTxnMaxLifetime
+	if cc.IsExist(txnMaxLifetimeKey) {
+		seconds, err := cc.Int(txnMaxLifetimeKey)
+		if err != nil || seconds <= 0 {
+			blog.Errorf("config %s is invalid, use the default value %s, err: %v", txnMaxLifetimeKey,
+				defaultTxnMaxLifetime, err)
+		} else {
+			t.Config.TxnMaxLifetime = time.Duration(seconds) * time.Second
+		}
+	}
+}
+
+// watchTransactions force aborts the transactions that exceed the max lifetime periodically, only the master core
+// service does it to avoid aborting the same transaction concurrently.
+func (t *CoreServer) watchTransactions(ctx context.Context) {
+	ticker := time.NewTicker(txnWatchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !t.Core.ServiceManageInterface.IsMaster() {
+			continue
+		}
+
+		if err := mongodb.Client().AbortExpiredTransactions(ctx, t.Config.TxnMaxLifetime); err != nil {
+			blog.Errorf("abort expired transactions failed, err: %v", err)
+		}
+	}
+}
+
+// parseBoolConfig parse the bool config, returns false if it is not configured or invalid.
+func parseBoolConfig(key string) bool {
+	if !cc.IsExist(key) {
+		return false
+	}
+
+	val, err := cc.Bool(key)
+	if err != nil {
+		blog.Errorf("config %s is invalid, use the default value false, err: %v", key, err)
+		return false
+	}
+	return val
+}
+
+// parseImportConflict parse the conflict resolution config of the concurrent imports, the source priorities are
+// configured like "cloud_sync:10", the invalid ones are ignored.
+func parseImportConflict() *metadata.ImportConflictConfig {
+	conf := &metadata.ImportConflictConfig{
+		Window:         metadata.ImportConflictDefaultWindow,
+		SourcePriority: make(map[string]int),
+	}
+
+	if cc.IsExist(importConflictWindowKey) {
+		seconds, err := cc.Int(importConflictWindowKey)
+		if err != nil || seconds <= 0 {
+			blog.Errorf("config %s is invalid, use the default value %s, err: %v", importConflictWindowKey,
+				metadata.ImportConflictDefaultWindow, err)
+		} else {
+			conf.Window = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if !cc.IsExist(importConflictPriorityKey) {
+		return conf
+	}
+
+	priorities, err := cc.StringSlice(importConflictPriorityKey)
+	if err != nil {
+		blog.Errorf("config %s is invalid, err: %v", importConflictPriorityKey, err)
+		return conf
+	}
+
+	for _, item := range priorities {
+		index := strings.LastIndex(item, ":")
+		if index <= 0 {
+			blog.Errorf("config %s item %s is invalid, skip it", importConflictPriorityKey, item)
+			continue
+		}
+
+		priority, err := strconv.Atoi(item[index+1:])
+		if err != nil {
+			blog.Errorf("config %s item %s is invalid, skip it, err: %v", importConflictPriorityKey, item, err)
+			continue
+		}
+		conf.SourcePriority[item[:index]] = priority
+	}
+
+	return conf
+}
+
+// parseHostLifecycle parse the host lifecycle state machine from the file configured by coreService.hostLifecycle.file,
+// returns the default one if it is not configured.
+func parseHostLifecycle() (*metadata.HostLifecycleConfig, error) {
+	if !cc.IsExist(hostLifecycleFileKey) {
+		return metadata.DefaultHostLifecycleConfig(), nil
+	}
+
+	file, err := cc.String(hostLifecycleFileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(file) == 0 {
+		return metadata.DefaultHostLifecycleConfig(), nil
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read host lifecycle file %s failed, err: %v", file, err)
+	}
+
+	conf := new(metadata.HostLifecycleConfig)
+	if err := yaml.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("unmarshal host lifecycle file %s failed, err: %v", file, err)
+	}
+
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+// parseAuditDetail parse the audit detail levels from the file configured by coreService.auditDetail.file, returns
+// the default one which keeps the full details if it is not configured.
+func parseAuditDetail() (*metadata.AuditDetailConfig, error) {
+	if !cc.IsExist(auditDetailFileKey) {
+		return metadata.DefaultAuditDetailConfig(), nil
+	}
+
+	file, err := cc.String(auditDetailFileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(file) == 0 {
+		return metadata.DefaultAuditDetailConfig(), nil
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read audit detail file %s failed, err: %v", file, err)
+	}
+
+	conf := new(metadata.AuditDetailConfig)
+	if err := yaml.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("unmarshal audit detail file %s failed, err: %v", file, err)
+	}
+
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	return conf, nil
 }
 
 // Run main function
@@ -96,6 +330,10 @@ func Run(ctx context.Context, cancel context.CancelFunc, op *options.ServerOptio
 		return err
 	}
 
+	go coreSvr.watchTransactions(ctx)
+	attrusage.Init(ctx, coreSvr.Config.AttributeUsage)
+	importconflict.Init(coreSvr.Config.ImportConflict)
+
 	err = backbone.StartServer(ctx, cancel, engine, coreService.WebService(), true)
 	if err != nil {
 		return err
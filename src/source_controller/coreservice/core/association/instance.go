@@ -90,8 +90,8 @@ func (m *associationInstance) checkAssociationMapping(kit *rest.Kit, objAsstID s
 		return kit.CCError.CCError(common.CCErrTopoGotMultipleAssociationInstance)
 	}
 
-	asstMapping, ok := asst.Info[0].Get("mapping")
-	if !ok {
+	asstMapping, err := asst.Info[0].String("mapping")
+	if err != nil {
 		return kit.CCError.CCError(common.CCErrorTopoAssociationDoNotExist)
 	}
 
@@ -104,18 +104,33 @@ func (m *associationInstance) checkAssociationMapping(kit *rest.Kit, objAsstID s
 		return err
 	}
 
-	switch asstMapping {
-	case string(metadata.OneToOneMapping):
-		instCount, err := m.countInstanceAssociation(kit, objectID, mapstr.MapStr{
-			common.AssociationObjAsstIDField: objAsstID,
+	association := metadata.Association{
+		AssociationName: objAsstID,
+		ObjectID:        objectID,
+		AsstObjID:       asstObjectID,
+		Mapping:         metadata.AssociationMapping(asstMapping),
+	}
+	return m.checkInstAsstMapping(kit, association, instID, asstInstID)
+}
+
+// checkInstAsstMapping check if the instance association between the instances violates the association mapping,
+// the source instance of 1:1 association and the destination instance of 1:1 and 1:n association can not be
+// associated with more than one instance.
+func (m *associationInstance) checkInstAsstMapping(kit *rest.Kit, asst metadata.Association, instID int64,
+	asstInstID int64) error {
+
+	switch asst.Mapping {
+	case metadata.OneToOneMapping:
+		instCount, err := m.countInstanceAssociation(kit, asst.ObjectID, mapstr.MapStr{
+			common.AssociationObjAsstIDField: asst.AssociationName,
 			common.BKInstIDField:             instID,
 		})
 		if err != nil {
 			return err
 		}
 
-		asstInstCount, err := m.countInstanceAssociation(kit, asstObjectID, mapstr.MapStr{
-			common.AssociationObjAsstIDField: objAsstID,
+		asstInstCount, err := m.countInstanceAssociation(kit, asst.AsstObjID, mapstr.MapStr{
+			common.AssociationObjAsstIDField: asst.AssociationName,
 			common.BKAsstInstIDField:         asstInstID,
 		})
 		if err != nil {
@@ -125,9 +140,9 @@ func (m *associationInstance) checkAssociationMapping(kit *rest.Kit, objAsstID s
 		if instCount > 0 || asstInstCount > 0 {
 			return kit.CCError.CCError(common.CCErrorTopoCreateMultipleInstancesForOneToOneAssociation)
 		}
-	case string(metadata.OneToManyMapping):
-		asstInstCount, err := m.countInstanceAssociation(kit, asstObjectID, mapstr.MapStr{
-			common.AssociationObjAsstIDField: objAsstID,
+	case metadata.OneToManyMapping:
+		asstInstCount, err := m.countInstanceAssociation(kit, asst.AsstObjID, mapstr.MapStr{
+			common.AssociationObjAsstIDField: asst.AssociationName,
 			common.BKAsstInstIDField:         asstInstID,
 		})
 		if err != nil {
@@ -308,6 +323,13 @@ func (m *associationInstance) CreateOneInstanceAssociation(kit *rest.Kit, inputP
 			}
 		}()
 
+		if err := m.checkInstAsstMapping(kit, assoItems[0], inputParam.Data.InstID,
+			inputParam.Data.AsstInstID); err != nil {
+			blog.Errorf("instance association %#v violates the mapping, err: %v, rid: %s", inputParam.Data, err,
+				kit.Rid)
+			return nil, err
+		}
+
 		id, err := m.save(kit, inputParam.Data)
 		if err != nil {
 			blog.Errorf("create one to one instance association failed, err: %v, rid: %s", err, kit.Rid)
@@ -335,6 +357,13 @@ func (m *associationInstance) CreateOneInstanceAssociation(kit *rest.Kit, inputP
 			}
 		}()
 
+		if err := m.checkInstAsstMapping(kit, assoItems[0], inputParam.Data.InstID,
+			inputParam.Data.AsstInstID); err != nil {
+			blog.Errorf("instance association %#v violates the mapping, err: %v, rid: %s", inputParam.Data, err,
+				kit.Rid)
+			return nil, err
+		}
+
 		id, err := m.save(kit, inputParam.Data)
 		if err != nil {
 			blog.Errorf("create one to one instance association failed, err: %v, rid: %s", err, kit.Rid)
@@ -504,6 +533,110 @@ func (m *associationInstance) CountInstanceAssociations(kit *rest.Kit,
 	return result, nil
 }
 
+// ScanAsstMappingViolation scans the existing instance associations of the model association that violate the
+// association mapping, the source side violations are returned before the destination side ones.
+func (m *associationInstance) ScanAsstMappingViolation(kit *rest.Kit, opt *metadata.ScanAsstMappingViolationOption) (
+	*metadata.AsstMappingViolationResult, error) {
+
+	cond := mongo.NewCondition()
+	cond.Element(&mongo.Eq{Key: common.AssociationObjAsstIDField, Val: opt.ObjectAsstID})
+	cond.Element(&mongo.Eq{Key: common.BKOwnerIDField, Val: kit.SupplierAccount})
+	assts, err := m.search(kit, cond)
+	if err != nil {
+		blog.Errorf("search association %s failed, err: %v, rid: %s", opt.ObjectAsstID, err, kit.Rid)
+		return nil, err
+	}
+
+	if len(assts) == 0 {
+		blog.Errorf("association %s does not exist, rid: %s", opt.ObjectAsstID, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrorTopoAssociationDoNotExist)
+	}
+
+	asst := assts[0]
+	result := &metadata.AsstMappingViolationResult{
+		ObjectAsstID: asst.AssociationName,
+		Mapping:      asst.Mapping,
+		Violations:   make([]metadata.AsstMappingViolation, 0),
+	}
+
+	var sides []string
+	switch asst.Mapping {
+	case metadata.OneToOneMapping:
+		sides = []string{metadata.AsstMappingViolationSrc, metadata.AsstMappingViolationDest}
+	case metadata.OneToManyMapping:
+		sides = []string{metadata.AsstMappingViolationDest}
+	default:
+		return result, nil
+	}
+
+	for _, side := range sides {
+		limit := opt.Limit - len(result.Violations)
+		if limit <= 0 {
+			result.HasMore = true
+			break
+		}
+
+		violations, hasMore, err := m.aggregateAsstMappingViolation(kit, asst, side, limit)
+		if err != nil {
+			return nil, err
+		}
+		result.Violations = append(result.Violations, violations...)
+		result.HasMore = hasMore
+	}
+
+	return result, nil
+}
+
+// aggregateAsstMappingViolation aggregates the instances on the side of the association which are associated with
+// more than one instance, returns whether there are more violations than the limit.
+func (m *associationInstance) aggregateAsstMappingViolation(kit *rest.Kit, asst metadata.Association, side string,
+	limit int) ([]metadata.AsstMappingViolation, bool, error) {
+
+	instIDField := common.BKInstIDField
+	if side == metadata.AsstMappingViolationDest {
+		instIDField = common.BKAsstInstIDField
+	}
+
+	filter := map[string]interface{}{
+		common.AssociationObjAsstIDField: asst.AssociationName,
+		common.BKOwnerIDField:            kit.SupplierAccount,
+	}
+	pipeline := []map[string]interface{}{
+		{common.BKDBMatch: filter},
+		{common.BKDBGroup: map[string]interface{}{
+			"_id":      "$" + instIDField,
+			"asst_ids": map[string]interface{}{common.BKDBPush: "$" + common.BKFieldID},
+			"count":    map[string]interface{}{common.BKDBSum: 1},
+		}},
+		{common.BKDBMatch: map[string]interface{}{"count": map[string]interface{}{common.BKDBGT: 1}}},
+		{"$sort": map[string]interface{}{"_id": 1}},
+		// get one more violation to know if there are more violations
+		{"$limit": limit + 1},
+	}
+
+	groups := make([]struct {
+		InstID  int64   `bson:"_id"`
+		AsstIDs []int64 `bson:"asst_ids"`
+	}, 0)
+	tableName := common.GetObjectInstAsstTableName(asst.ObjectID, kit.SupplierAccount)
+	if err := mongodb.Client().Table(tableName).AggregateAll(kit.Ctx, pipeline, &groups); err != nil {
+		blog.Errorf("aggregate %s association %s mapping violation failed, err: %v, rid: %s", side,
+			asst.AssociationName, err, kit.Rid)
+		return nil, false, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	hasMore := len(groups) > limit
+	if hasMore {
+		groups = groups[:limit]
+	}
+
+	violations := make([]metadata.AsstMappingViolation, len(groups))
+	for idx, group := range groups {
+		violations[idx] = metadata.AsstMappingViolation{Side: side, InstID: group.InstID, AsstIDs: group.AsstIDs}
+	}
+	return violations, hasMore, nil
+}
+
 // DeleteInstanceAssociation TODO
 func (m *associationInstance) DeleteInstanceAssociation(kit *rest.Kit, objID string, inputParam metadata.DeleteOption) (
 	*metadata.DeletedCount, error) {
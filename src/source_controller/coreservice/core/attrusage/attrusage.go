@@ -0,0 +1,305 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package attrusage tracks the sampled read and write usages of the model attributes, so that the model owners can
+// find out the unused attributes. the usages are aggregated in memory and flushed to redis periodically, so that the
+// tracking does not slow down the instance operations.
+package attrusage
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/redis"
+)
+
+const (
+	// readKind the attribute is read by the query fields or the query condition
+	readKind = "read"
+	// writeKind the attribute is written by the created or updated data
+	writeKind = "write"
+)
+
+const (
+	// flushInterval the interval to flush the aggregated usages to redis
+	flushInterval = 30 * time.Second
+	// recordQueueSize the size of the queue of the sampled records, the records are dropped when it is full
+	recordQueueSize = 10000
+	// countFieldSuffix the suffix of the usage kind in the redis hash field that stores the estimated count of the
+	// attribute usage, the full field is like "read_count:bk_host_name"
+	countFieldSuffix = "_count:"
+	// lastTimeFieldPrefix the prefix of the redis hash field that stores the last used unix time of the attribute,
+	// the full field is like "last_read:bk_host_name"
+	lastTimeFieldPrefix = "last_"
+)
+
+var (
+	tracker     *usageTracker
+	trackerOnce sync.Once
+)
+
+// Init starts to track the attribute usages with the config, the tracking is disabled if the sample percent is 0.
+func Init(ctx context.Context, conf *metadata.AttributeUsageConfig) {
+	if conf == nil || conf.SamplePercent <= 0 {
+		blog.Infof("attribute usage tracking is disabled")
+		return
+	}
+
+	trackerOnce.Do(func() {
+		percent := conf.SamplePercent
+		if percent > 100 {
+			percent = 100
+		}
+
+		tracker = &usageTracker{
+			samplePercent: percent,
+			queue:         make(chan usageRecord, recordQueueSize),
+		}
+		go tracker.run(ctx)
+	})
+}
+
+// SamplePercent returns the percent of the requests sampled to track the attribute usages, 0 means not tracked.
+func SamplePercent() int {
+	if tracker == nil {
+		return 0
+	}
+	return tracker.samplePercent
+}
+
+// RecordRead records the attributes read by a query, both the returned fields and the fields in the query
+// condition are read.
+func RecordRead(kit *rest.Kit, objID string, fields []string, cond map[string]interface{}) {
+	if tracker == nil || !tracker.sample() {
+		return
+	}
+
+	readFields := make([]string, 0, len(fields))
+	readFields = append(readFields, fields...)
+	readFields = appendConditionFields(readFields, cond)
+	tracker.add(kit, objID, readKind, readFields)
+}
+
+// RecordWrite records the attributes written by the created or updated data of the instances.
+func RecordWrite(kit *rest.Kit, objID string, data ...mapstr.MapStr) {
+	if tracker == nil || !tracker.sample() {
+		return
+	}
+
+	fieldMap := make(map[string]struct{})
+	for _, item := range data {
+		for field := range item {
+			fieldMap[field] = struct{}{}
+		}
+	}
+
+	fields := make([]string, 0, len(fieldMap))
+	for field := range fieldMap {
+		fields = append(fields, field)
+	}
+	tracker.add(kit, objID, writeKind, fields)
+}
+
+// appendConditionFields appends the fields in the mongo condition, the fields in the nested logical operators like
+// $and and $or are appended too.
+func appendConditionFields(fields []string, cond map[string]interface{}) []string {
+	for key, val := range cond {
+		if !strings.HasPrefix(key, "$") {
+			fields = append(fields, key)
+			continue
+		}
+
+		switch subConds := val.(type) {
+		case []map[string]interface{}:
+			for _, subCond := range subConds {
+				fields = appendConditionFields(fields, subCond)
+			}
+		case []mapstr.MapStr:
+			for _, subCond := range subConds {
+				fields = appendConditionFields(fields, subCond)
+			}
+		case []interface{}:
+			for _, subCond := range subConds {
+				switch c := subCond.(type) {
+				case map[string]interface{}:
+					fields = appendConditionFields(fields, c)
+				case mapstr.MapStr:
+					fields = appendConditionFields(fields, c)
+				}
+			}
+		}
+	}
+	return fields
+}
+
+// GetUsages returns the tracked usages of the model's attributes, keyed by the attribute's property id.
+func GetUsages(ctx context.Context, supplierAccount, objID string) (map[string]*metadata.AttributeUsage, error) {
+	values, err := redis.Client().HGetAll(ctx, usageKey(supplierAccount, objID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make(map[string]*metadata.AttributeUsage)
+	for field, value := range values {
+		sepIdx := strings.Index(field, ":")
+		if sepIdx <= 0 {
+			continue
+		}
+		name, propertyID := field[:sepIdx+1], field[sepIdx+1:]
+
+		num, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			blog.Errorf("parse attribute usage field %s value %s failed, err: %v", field, value, err)
+			continue
+		}
+
+		usage, exists := usages[propertyID]
+		if !exists {
+			usage = &metadata.AttributeUsage{PropertyID: propertyID}
+			usages[propertyID] = usage
+		}
+
+		lastTime := time.Unix(num, 0)
+		switch name {
+		case readKind + countFieldSuffix:
+			usage.ReadCount = num
+		case writeKind + countFieldSuffix:
+			usage.WriteCount = num
+		case lastTimeFieldPrefix + readKind + ":":
+			usage.LastReadTime = &lastTime
+		case lastTimeFieldPrefix + writeKind + ":":
+			usage.LastWriteTime = &lastTime
+		}
+	}
+
+	return usages, nil
+}
+
+func usageKey(supplierAccount, objID string) string {
+	return common.AttributeUsageKeyPrefix + supplierAccount + ":" + objID
+}
+
+// usageRecord is a sampled request that uses the attributes of the model
+type usageRecord struct {
+	supplierAccount string
+	objID           string
+	kind            string
+	fields          []string
+	time            int64
+}
+
+// usageStatKey is the key of the aggregated usage of an attribute
+type usageStatKey struct {
+	kind  string
+	field string
+}
+
+// usageStat is the aggregated usage of an attribute between two flushes
+type usageStat struct {
+	count    int64
+	lastTime int64
+}
+
+type usageTracker struct {
+	samplePercent int
+	queue         chan usageRecord
+}
+
+func (t *usageTracker) sample() bool {
+	return t.samplePercent >= 100 || rand.Intn(100) < t.samplePercent
+}
+
+func (t *usageTracker) add(kit *rest.Kit, objID, kind string, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+
+	record := usageRecord{
+		supplierAccount: kit.SupplierAccount,
+		objID:           objID,
+		kind:            kind,
+		fields:          fields,
+		time:            time.Now().Unix(),
+	}
+
+	select {
+	case t.queue <- record:
+	default:
+		blog.V(4).Infof("attribute usage queue is full, drop the record of object %s, rid: %s", objID, kit.Rid)
+	}
+}
+
+func (t *usageTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	// stats is the aggregated usages, keyed by the redis key of the model
+	stats := make(map[string]map[usageStatKey]*usageStat)
+	for {
+		select {
+		case <-ctx.Done():
+			t.flush(stats)
+			return
+		case record := <-t.queue:
+			key := usageKey(record.supplierAccount, record.objID)
+			if _, exists := stats[key]; !exists {
+				stats[key] = make(map[usageStatKey]*usageStat)
+			}
+
+			for _, field := range record.fields {
+				statKey := usageStatKey{kind: record.kind, field: field}
+				stat, exists := stats[key][statKey]
+				if !exists {
+					stat = new(usageStat)
+					stats[key][statKey] = stat
+				}
+				stat.count++
+				if record.time > stat.lastTime {
+					stat.lastTime = record.time
+				}
+			}
+		case <-ticker.C:
+			t.flush(stats)
+			stats = make(map[string]map[usageStatKey]*usageStat)
+		}
+	}
+}
+
+// flush increases the estimated counts and sets the last used times of the aggregated usages in redis, the
+// estimated count is the sampled count divided by the sample percent.
+func (t *usageTracker) flush(stats map[string]map[usageStatKey]*usageStat) {
+	if len(stats) == 0 {
+		return
+	}
+
+	pipe := redis.Client().Pipeline()
+	for key, keyStats := range stats {
+		for statKey, stat := range keyStats {
+			count := stat.count * 100 / int64(t.samplePercent)
+			pipe.HIncrBy(key, statKey.kind+countFieldSuffix+statKey.field, count)
+			pipe.HSet(key, lastTimeFieldPrefix+statKey.kind+":"+statKey.field, stat.lastTime)
+		}
+	}
+
+	if _, err := pipe.Exec(); err != nil {
+		blog.Errorf("flush attribute usages to redis failed, err: %v", err)
+	}
+}
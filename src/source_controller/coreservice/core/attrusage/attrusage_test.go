@@ -0,0 +1,36 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package attrusage
+
+import (
+	"sort"
+	"testing"
+
+	"configcenter/src/common/mapstr"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendConditionFields(t *testing.T) {
+	cond := map[string]interface{}{
+		"bk_host_innerip": "127.0.0.1",
+		"$and": []interface{}{
+			map[string]interface{}{"bk_os_type": "1"},
+			mapstr.MapStr{"$or": []map[string]interface{}{{"bk_cpu": 1}, {"bk_mem": map[string]interface{}{"$gt": 1}}}},
+		},
+	}
+
+	fields := appendConditionFields([]string{"bk_host_id"}, cond)
+	sort.Strings(fields)
+	require.Equal(t, []string{"bk_cpu", "bk_host_id", "bk_host_innerip", "bk_mem", "bk_os_type"}, fields)
+}
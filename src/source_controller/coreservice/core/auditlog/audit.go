@@ -13,6 +13,7 @@
 package auditlog
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -30,16 +31,61 @@ import (
 var _ core.AuditOperation = (*auditManager)(nil)
 
 type auditManager struct {
+	cfg       *metadata.AuditDetailConfig
+	diffQueue chan diffTask
+	writer    *auditWriter
 }
 
 // New create a new instance manager instance
-func New() core.AuditOperation {
-	return &auditManager{}
+func New(cfg *metadata.AuditDetailConfig) core.AuditOperation {
+	if cfg == nil {
+		cfg = metadata.DefaultAuditDetailConfig()
+	}
+
+	m := &auditManager{
+		cfg:       cfg,
+		diffQueue: make(chan diffTask, diffQueueSize),
+	}
+	go m.runDiffWorker()
+
+	if cfg.AsyncWrite.Enabled {
+		m.writer = newAuditWriter(cfg.AsyncWrite, insertAuditLogs, m.addDiffTasks)
+	}
+	return m
+}
+
+// insertAuditLogs saves the audit logs into db.
+func insertAuditLogs(ctx context.Context, logs []metadata.AuditLog) error {
+	return mongodb.Client().Table(common.BKTableNameAuditLog).Insert(ctx, logs)
+}
+
+// addDiffTasks adds the saved audit logs whose changed fields need to be computed to the diff queue.
+func (m *auditManager) addDiffTasks(entries []writeEntry) {
+	for _, entry := range entries {
+		if entry.diff {
+			m.addDiffTask(diffTask{id: entry.log.ID, rid: entry.rid})
+		}
+	}
+}
+
+// isSyncWrite returns if the audit log is saved synchronously, the audit log saved in a transaction must be saved
+// in it so that it is rolled back with the operation, and the audit logs of the sync actions are critical.
+func (m *auditManager) isSyncWrite(kit *rest.Kit, log *metadata.AuditLog) bool {
+	if m.writer == nil {
+		return true
+	}
+
+	if kit.Ctx.Value(common.TransactionIdHeader) != nil {
+		return true
+	}
+
+	return m.cfg.AsyncWrite.IsSyncAction(log.Action)
 }
 
 // CreateAuditLog TODO
 func (m *auditManager) CreateAuditLog(kit *rest.Kit, logs ...metadata.AuditLog) error {
-	logRows := make([]metadata.AuditLog, 0)
+	syncEntries := make([]writeEntry, 0)
+	asyncEntries := make([]writeEntry, 0)
 
 	ids, err := mongodb.Client().NextSequences(kit.Ctx, common.BKTableNameAuditLog, len(logs))
 	if err != nil {
@@ -67,16 +113,41 @@ func (m *auditManager) CreateAuditLog(kit *rest.Kit, logs ...metadata.AuditLog)
 		if rid := kit.Rid; len(rid) > 0 {
 			log.RequestID = kit.Rid
 		}
+		if changeSetID := metadata.GetChangeSetID(kit.Header); len(changeSetID) > 0 {
+			log.ChangeSetID = changeSetID
+		}
 		log.OperationTime = metadata.Now()
 		log.ID = int64(ids[index])
 
-		logRows = append(logRows, log)
+		diff := m.processDetail(&log, kit.Rid)
+		entry := writeEntry{log: log, rid: kit.Rid, diff: diff}
+		if m.isSyncWrite(kit, &log) {
+			syncEntries = append(syncEntries, entry)
+		} else {
+			asyncEntries = append(asyncEntries, entry)
+		}
 	}
 
-	if len(logRows) == 0 {
-		return nil
+	if len(syncEntries) > 0 {
+		logRows := make([]metadata.AuditLog, len(syncEntries))
+		for index := range syncEntries {
+			logRows[index] = syncEntries[index].log
+		}
+
+		if err := insertAuditLogs(kit.Ctx, logRows); err != nil {
+			return err
+		}
+
+		if m.writer != nil {
+			m.writer.metrics.written.WithLabelValues(writeModeSync).Add(float64(len(logRows)))
+		}
+		m.addDiffTasks(syncEntries)
+	}
+
+	if len(asyncEntries) > 0 {
+		return m.writer.write(kit.Ctx, kit.Rid, asyncEntries)
 	}
-	return mongodb.Client().Table(common.BKTableNameAuditLog).Insert(kit.Ctx, logRows)
+	return nil
 }
 
 // SearchAuditLog TODO
@@ -126,5 +197,38 @@ func (m *auditManager) SearchAuditLog(kit *rest.Kit, param metadata.QueryConditi
 		return nil, 0, kit.CCError.CCError(common.CCErrAuditSelectFailed)
 	}
 
+	for _, row := range rows {
+		getter, ok := row.OperationDetail.(metadata.BasicOpDetailGetter)
+		if !ok {
+			continue
+		}
+
+		if err := getter.GetBasicOpDetail().Decompress(); err != nil {
+			blog.Errorf("decompress audit log %d details failed, err: %v, rid: %s", row.ID, err, kit.Rid)
+			return nil, 0, kit.CCError.CCError(common.CCErrAuditSelectFailed)
+		}
+	}
+
 	return rows, cnt, nil
 }
+
+// RepointAuditLog move the audit logs of the source resource to the target resource of the same type
+func (m *auditManager) RepointAuditLog(kit *rest.Kit, opt *metadata.RepointAuditLogOption) error {
+	cond := map[string]interface{}{
+		common.BKResourceTypeField: opt.ResourceType,
+		common.BKResourceIDField:   opt.SourceID,
+	}
+	if opt.ResourceType == metadata.ModelInstanceRes {
+		cond[common.BKOperationDetailField+"."+common.BKObjIDField] = opt.ObjID
+	}
+	cond = util.SetQueryOwner(cond, kit.SupplierAccount)
+
+	data := map[string]interface{}{common.BKResourceIDField: opt.TargetID}
+	if err := mongodb.Client().Table(common.BKTableNameAuditLog).Update(kit.Ctx, cond, data); err != nil {
+		blog.Errorf("repoint audit logs failed, err: %v, cond: %#v, target: %d, rid: %s", err, cond, opt.TargetID,
+			kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommDBUpdateFailed)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditlog
+
+import (
+	"context"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+const (
+	// diffQueueSize the max number of the audit logs waiting for their changed fields to be computed, the changed
+	// fields are computed synchronously when the queue is full.
+	diffQueueSize = 1000
+	// diffMaxRetry the max retry times of the audit log that is not found, it may be saved in a transaction that is
+	// not committed yet.
+	diffMaxRetry = 3
+)
+
+// diffTask is the audit log whose changed fields are computed asynchronously.
+type diffTask struct {
+	id    int64
+	rid   string
+	retry int
+}
+
+// processDetail processes the details of the audit log by the detail level of its resource, returns true if the
+// changed fields of the details need to be computed asynchronously after the audit log is saved.
+func (m *auditManager) processDetail(log *metadata.AuditLog, rid string) bool {
+	getter, ok := log.OperationDetail.(metadata.BasicOpDetailGetter)
+	if !ok {
+		return false
+	}
+
+	detail := getter.GetBasicOpDetail()
+	level := m.cfg.GetLevel(log.ResourceType)
+	switch level {
+	case metadata.AuditDetailMetadataOnly:
+		detail.Details = nil
+		detail.DetailLevel = level
+		return false
+
+	case metadata.AuditDetailChangedFields:
+		if detail.Details == nil {
+			return false
+		}
+
+		fieldCount := len(detail.Details.PreData) + len(detail.Details.CurData)
+		if m.cfg.AsyncDiffFieldCount > 0 && fieldCount > m.cfg.AsyncDiffFieldCount {
+			// the full details are saved first, and replaced by the changed fields later.
+			return true
+		}
+
+		detail.Details.KeepChangedFields()
+		detail.DetailLevel = level
+	}
+
+	if err := detail.Compress(m.cfg.CompressSize); err != nil {
+		// the uncompressed details are saved instead, which is still correct.
+		blog.Errorf("compress audit log details failed, err: %v, rid: %s", err, rid)
+	}
+	return false
+}
+
+// addDiffTask adds the audit log to the diff queue, or computes its changed fields synchronously if the queue is full.
+func (m *auditManager) addDiffTask(task diffTask) {
+	select {
+	case m.diffQueue <- task:
+	default:
+		m.diff(task)
+	}
+}
+
+// runDiffWorker computes the changed fields of the audit logs in the diff queue, so that the large documents do not
+// slow down the bulk operations that save their audit logs.
+func (m *auditManager) runDiffWorker() {
+	for task := range m.diffQueue {
+		m.diff(task)
+	}
+}
+
+// diff computes the changed fields of the saved audit log and replaces its full details with them.
+func (m *auditManager) diff(task diffTask) {
+	ctx := context.WithValue(context.Background(), common.ContextRequestIDField, task.rid)
+	filter := mapstr.MapStr{common.BKFieldID: task.id}
+
+	logs := make([]metadata.AuditLog, 0)
+	if err := mongodb.Client().Table(common.BKTableNameAuditLog).Find(filter).All(ctx, &logs); err != nil {
+		blog.Errorf("get audit log %d to compute changed fields failed, err: %v, rid: %s", task.id, err, task.rid)
+		return
+	}
+
+	if len(logs) == 0 {
+		if task.retry >= diffMaxRetry {
+			blog.Errorf("audit log %d to compute changed fields is not found, rid: %s", task.id, task.rid)
+			return
+		}
+
+		task.retry++
+		time.AfterFunc(time.Second, func() { m.addDiffTask(task) })
+		return
+	}
+
+	getter, ok := logs[0].OperationDetail.(metadata.BasicOpDetailGetter)
+	if !ok {
+		return
+	}
+
+	detail := getter.GetBasicOpDetail()
+	if detail.DetailLevel == metadata.AuditDetailChangedFields || detail.Details == nil {
+		return
+	}
+
+	detail.Details.KeepChangedFields()
+	detail.DetailLevel = metadata.AuditDetailChangedFields
+	if err := detail.Compress(m.cfg.CompressSize); err != nil {
+		blog.Errorf("compress audit log %d details failed, err: %v, rid: %s", task.id, err, task.rid)
+	}
+
+	doc := mapstr.MapStr{common.BKOperationDetailField: logs[0].OperationDetail}
+	if err := mongodb.Client().Table(common.BKTableNameAuditLog).Update(ctx, filter, doc); err != nil {
+		blog.Errorf("update audit log %d changed fields failed, err: %v, rid: %s", task.id, err, task.rid)
+		return
+	}
+}
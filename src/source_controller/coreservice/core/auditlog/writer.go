@@ -0,0 +1,223 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditlog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/metrics"
+	"configcenter/src/common/types"
+	"configcenter/src/thirdparty/monitor"
+	"configcenter/src/thirdparty/monitor/meta"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// writeMaxRetry the max retry times of saving a batch of the audit logs asynchronously, the audit logs are
+	// dropped and alarmed after that.
+	writeMaxRetry = 3
+	// writeRetryInterval the interval between the retries of saving a batch of the audit logs.
+	writeRetryInterval = 500 * time.Millisecond
+)
+
+// the modes that the audit logs are saved in.
+const (
+	writeModeSync     = "sync"
+	writeModeAsync    = "async"
+	writeModeFallback = "fallback"
+)
+
+var (
+	writeMetricsOnce sync.Once
+	writeMetrics     *auditWriteMetrics
+)
+
+type auditWriteMetrics struct {
+	written    *prometheus.CounterVec
+	dropped    prometheus.Counter
+	queueSize  prometheus.Gauge
+	batchSizes prometheus.Histogram
+}
+
+func getWriteMetrics() *auditWriteMetrics {
+	writeMetricsOnce.Do(func() {
+		writeMetrics = &auditWriteMetrics{
+			written: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: metrics.Namespace,
+				Subsystem: "audit",
+				Name:      "log_written_total",
+				Help:      "the total number of the saved audit logs, the mode is sync, async or fallback",
+			}, []string{"mode"}),
+			dropped: prometheus.NewCounter(prometheus.CounterOpts{
+				Namespace: metrics.Namespace,
+				Subsystem: "audit",
+				Name:      "log_dropped_total",
+				Help:      "the total number of the audit logs dropped since they can not be saved asynchronously",
+			}),
+			queueSize: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: metrics.Namespace,
+				Subsystem: "audit",
+				Name:      "log_queue_size",
+				Help:      "the number of the audit logs waiting to be saved asynchronously",
+			}),
+			batchSizes: prometheus.NewHistogram(prometheus.HistogramOpts{
+				Namespace: metrics.Namespace,
+				Subsystem: "audit",
+				Name:      "log_batch_size",
+				Help:      "the number of the audit logs saved asynchronously in one batch",
+				Buckets:   []float64{1, 5, 10, 20, 50, 100, 200, 500, 1000},
+			}),
+		}
+		metrics.Register().MustRegister(writeMetrics.written, writeMetrics.dropped, writeMetrics.queueSize,
+			writeMetrics.batchSizes)
+	})
+	return writeMetrics
+}
+
+// writeEntry is the audit log waiting to be saved asynchronously.
+type writeEntry struct {
+	log  metadata.AuditLog
+	rid  string
+	diff bool
+}
+
+// insertFunc saves a batch of the audit logs.
+type insertFunc func(ctx context.Context, logs []metadata.AuditLog) error
+
+// auditWriter saves the audit logs asynchronously in batches, so that saving them does not slow down the bulk
+// operations. The audit logs are never dropped silently, they are saved synchronously when the queue is full, and
+// the ones that still can not be saved after retries are counted and alarmed.
+type auditWriter struct {
+	cfg     metadata.AuditAsyncWriteConfig
+	queue   chan writeEntry
+	insert  insertFunc
+	onSaved func(entries []writeEntry)
+	metrics *auditWriteMetrics
+}
+
+// newAuditWriter creates the audit writer and starts its flush worker, onSaved is called with the audit logs saved
+// asynchronously.
+func newAuditWriter(cfg metadata.AuditAsyncWriteConfig, insert insertFunc,
+	onSaved func(entries []writeEntry)) *auditWriter {
+
+	w := &auditWriter{
+		cfg:     cfg,
+		queue:   make(chan writeEntry, cfg.QueueSize),
+		insert:  insert,
+		onSaved: onSaved,
+		metrics: getWriteMetrics(),
+	}
+	go w.run()
+	return w
+}
+
+// write saves the audit logs asynchronously, the audit logs that can not be queued since the queue is full are
+// saved synchronously instead.
+func (w *auditWriter) write(ctx context.Context, rid string, entries []writeEntry) error {
+	for index, entry := range entries {
+		select {
+		case w.queue <- entry:
+			w.metrics.queueSize.Inc()
+			continue
+		default:
+		}
+
+		fallback := entries[index:]
+		blog.Warnf("audit log queue is full, save %d audit logs synchronously, rid: %s", len(fallback), rid)
+
+		logs := make([]metadata.AuditLog, len(fallback))
+		for i := range fallback {
+			logs[i] = fallback[i].log
+		}
+		if err := w.insert(ctx, logs); err != nil {
+			return err
+		}
+		w.metrics.written.WithLabelValues(writeModeFallback).Add(float64(len(logs)))
+		w.onSaved(fallback)
+		return nil
+	}
+	return nil
+}
+
+// run collects the queued audit logs into batches, and saves a batch when it reaches the batch size or the flush
+// interval is reached.
+func (w *auditWriter) run() {
+	ticker := time.NewTicker(time.Duration(w.cfg.FlushIntervalMS) * time.Millisecond)
+	defer ticker.Stop()
+
+	batch := make([]writeEntry, 0, w.cfg.BatchSize)
+	for {
+		select {
+		case entry := <-w.queue:
+			w.metrics.queueSize.Dec()
+			batch = append(batch, entry)
+			if len(batch) < w.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		w.flush(batch)
+		batch = make([]writeEntry, 0, w.cfg.BatchSize)
+	}
+}
+
+// flush saves a batch of the audit logs with retries, the batch is dropped and alarmed if it still fails.
+func (w *auditWriter) flush(batch []writeEntry) {
+	rid := batch[0].rid
+	ctx := context.WithValue(context.Background(), common.ContextRequestIDField, rid)
+
+	logs := make([]metadata.AuditLog, len(batch))
+	for index := range batch {
+		logs[index] = batch[index].log
+	}
+	w.metrics.batchSizes.Observe(float64(len(logs)))
+
+	var err error
+	for retry := 0; retry < writeMaxRetry; retry++ {
+		if err = w.insert(ctx, logs); err == nil {
+			w.metrics.written.WithLabelValues(writeModeAsync).Add(float64(len(logs)))
+			w.onSaved(batch)
+			return
+		}
+
+		blog.Errorf("save %d audit logs asynchronously failed, retry: %d, err: %v, rid: %s", len(logs), retry, err,
+			rid)
+		time.Sleep(writeRetryInterval)
+	}
+
+	ids := make([]int64, len(logs))
+	for index := range logs {
+		ids[index] = logs[index].ID
+	}
+	w.metrics.dropped.Add(float64(len(logs)))
+	blog.Errorf("drop %d audit logs that can not be saved, ids: %v, err: %v, rid: %s", len(logs), ids, err, rid)
+
+	monitor.Collect(&meta.Alarm{
+		RequestID: rid,
+		Type:      meta.MongoFatalError,
+		Detail:    fmt.Sprintf("drop %d audit logs that can not be saved, ids: %v, err: %v", len(logs), ids, err),
+		Module:    types.CC_MODULE_CORESERVICE,
+		Dimension: map[string]string{"audit_log_dropped": "yes"},
+	})
+}
@@ -0,0 +1,141 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditlog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"configcenter/src/common/metadata"
+)
+
+type fakeInserter struct {
+	lock    sync.Mutex
+	batches [][]metadata.AuditLog
+	err     error
+}
+
+func (f *fakeInserter) insert(_ context.Context, logs []metadata.AuditLog) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.batches = append(f.batches, logs)
+	return f.err
+}
+
+func (f *fakeInserter) batchCount() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return len(f.batches)
+}
+
+func newTestEntries(count int) []writeEntry {
+	entries := make([]writeEntry, count)
+	for index := range entries {
+		entries[index] = writeEntry{log: metadata.AuditLog{ID: int64(index + 1)}, rid: "rid"}
+	}
+	return entries
+}
+
+func TestAuditWriterBatch(t *testing.T) {
+	inserter := new(fakeInserter)
+	saved := make(chan []writeEntry, 10)
+	cfg := metadata.AuditAsyncWriteConfig{QueueSize: 10, BatchSize: 3, FlushIntervalMS: 50}
+	w := newAuditWriter(cfg, inserter.insert, func(entries []writeEntry) { saved <- entries })
+
+	if err := w.write(context.Background(), "rid", newTestEntries(4)); err != nil {
+		t.Fatalf("write audit logs failed, err: %v", err)
+	}
+
+	// the first 3 logs reach the batch size, and the last one is saved when the flush interval is reached.
+	for _, expected := range []int{3, 1} {
+		select {
+		case entries := <-saved:
+			if len(entries) != expected {
+				t.Fatalf("expect batch size %d, got %d", expected, len(entries))
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("audit logs are not saved")
+		}
+	}
+}
+
+func TestAuditWriterQueueFull(t *testing.T) {
+	inserter := new(fakeInserter)
+	w := &auditWriter{
+		cfg:     metadata.AuditAsyncWriteConfig{QueueSize: 2, BatchSize: 10, FlushIntervalMS: 50},
+		queue:   make(chan writeEntry, 2),
+		insert:  inserter.insert,
+		metrics: getWriteMetrics(),
+	}
+	var fallback []writeEntry
+	w.onSaved = func(entries []writeEntry) { fallback = entries }
+
+	// the worker is not started, so the logs exceeding the queue size are saved synchronously.
+	if err := w.write(context.Background(), "rid", newTestEntries(5)); err != nil {
+		t.Fatalf("write audit logs failed, err: %v", err)
+	}
+
+	if len(w.queue) != 2 {
+		t.Fatalf("expect 2 queued audit logs, got %d", len(w.queue))
+	}
+	if len(inserter.batches) != 1 || len(inserter.batches[0]) != 3 || len(fallback) != 3 {
+		t.Fatalf("expect 3 audit logs saved synchronously, got %v", inserter.batches)
+	}
+	if fallback[0].log.ID != 3 {
+		t.Fatalf("expect the fallback audit logs begin with id 3, got %d", fallback[0].log.ID)
+	}
+
+	inserter.err = errors.New("insert failed")
+	if err := w.write(context.Background(), "rid", newTestEntries(1)); err == nil {
+		t.Fatalf("expect the error of saving audit logs synchronously is returned")
+	}
+}
+
+func TestAuditWriterDrop(t *testing.T) {
+	inserter := &fakeInserter{err: errors.New("insert failed")}
+	w := &auditWriter{
+		insert:  inserter.insert,
+		onSaved: func(entries []writeEntry) { t.Fatalf("dropped audit logs should not be saved") },
+		metrics: getWriteMetrics(),
+	}
+
+	w.flush(newTestEntries(2))
+	if inserter.batchCount() != writeMaxRetry {
+		t.Fatalf("expect %d retries, got %d", writeMaxRetry, inserter.batchCount())
+	}
+}
+
+func TestAuditAsyncWriteConfig(t *testing.T) {
+	cfg := metadata.AuditAsyncWriteConfig{}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validate audit async write config failed, err: %v", err)
+	}
+
+	if cfg.QueueSize != metadata.AuditAsyncWriteDefaultQueueSize ||
+		cfg.BatchSize != metadata.AuditAsyncWriteDefaultBatchSize ||
+		cfg.FlushIntervalMS != metadata.AuditAsyncWriteDefaultFlushIntervalMS {
+		t.Fatalf("default values are not set, cfg: %+v", cfg)
+	}
+
+	if !cfg.IsSyncAction(metadata.AuditDelete) || cfg.IsSyncAction(metadata.AuditCreate) {
+		t.Fatalf("expect only delete is the default sync action, got %v", cfg.SyncActions)
+	}
+
+	cfg = metadata.AuditAsyncWriteConfig{BatchSize: -1}
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expect negative batch size is invalid")
+	}
+}
@@ -112,12 +112,18 @@ type InstanceOperation interface {
 	CreateManyModelInstance(kit *rest.Kit, objID string, inputParam metadata.CreateManyModelInstance) (
 		*metadata.CreateManyDataResult, error)
 	UpdateModelInstance(kit *rest.Kit, objID string, inputParam metadata.UpdateOption) (*metadata.UpdatedCount, error)
+	CreateOrUpdateModelInstance(kit *rest.Kit, objID string, inputParam metadata.CreateOrUpdateModelInstance) (
+		*metadata.CreateOrUpdateInstResult, error)
 	SearchModelInstance(kit *rest.Kit, objID string, inputParam metadata.QueryCondition) (*metadata.QueryResult, error)
 	CountModelInstances(kit *rest.Kit, objID string, input *metadata.Condition) (
 		*metadata.CommonCountResult, error)
 	DeleteModelInstance(kit *rest.Kit, objID string, inputParam metadata.DeleteOption) (*metadata.DeletedCount, error)
 	CascadeDeleteModelInstance(kit *rest.Kit, objID string, inputParam metadata.DeleteOption) (*metadata.DeletedCount,
 		error)
+	BindExternalIDs(kit *rest.Kit, objID string, opt *metadata.BindExternalIDOption) error
+	UnbindExternalIDs(kit *rest.Kit, objID string, opt *metadata.UnbindExternalIDOption) error
+	SearchExternalIDs(kit *rest.Kit, objID string, opt *metadata.SearchExternalIDOption) ([]metadata.ExternalID,
+		error)
 }
 
 // AssociationKind association kind methods
@@ -160,6 +166,8 @@ type InstanceAssociation interface {
 	CountInstanceAssociations(kit *rest.Kit, objID string, input *metadata.Condition) (
 		*metadata.CommonCountResult, error)
 	DeleteInstanceAssociation(kit *rest.Kit, objID string, param metadata.DeleteOption) (*metadata.DeletedCount, error)
+	ScanAsstMappingViolation(kit *rest.Kit, opt *metadata.ScanAsstMappingViolationOption) (
+		*metadata.AsstMappingViolationResult, error)
 }
 
 // DataSynchronizeOperation manager data synchronize interface
@@ -206,6 +214,9 @@ type HostOperation interface {
 		error)
 
 	TransferResourceDirectory(kit *rest.Kit, input *metadata.TransferHostResourceDirectory) errors.CCErrorCoder
+
+	// MergeHost move the source host's relations to the target host and delete the source host
+	MergeHost(kit *rest.Kit, input *metadata.MergeHostOption) error
 }
 
 // AssociationOperation association methods
@@ -219,6 +230,7 @@ type AssociationOperation interface {
 type AuditOperation interface {
 	CreateAuditLog(kit *rest.Kit, logs ...metadata.AuditLog) error
 	SearchAuditLog(kit *rest.Kit, param metadata.QueryCondition) ([]metadata.AuditLog, uint64, error)
+	RepointAuditLog(kit *rest.Kit, opt *metadata.RepointAuditLogOption) error
 }
 
 // StatisticOperation TODO
@@ -309,6 +321,7 @@ type ProcessOperation interface {
 	ConstructServiceInstanceName(kit *rest.Kit, instanceID int64, host map[string]interface{},
 		process *metadata.Process) (string, errors.CCErrorCoder)
 	ReconstructServiceInstanceName(kit *rest.Kit, instanceID int64) errors.CCErrorCoder
+	RenderServiceInstanceNames(kit *rest.Kit, option *metadata.RenderSrvInstNameOption) (int, errors.CCErrorCoder)
 
 	// CreateProcessInstanceRelation TODO
 	// process instance relation
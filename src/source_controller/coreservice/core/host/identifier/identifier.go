@@ -187,8 +187,11 @@ func (i *Identifier) findHostServiceInst(kit *rest.Kit, hostIDs []int64) error {
 
 	// 服务实例与模块的关系
 	serviceInstModuleRelation := make(map[int64][]int64, 0)
+	// 服务实例的配置模板引用
+	serviceInstConfigTemplates := make(map[int64]metadata.ConfigTemplateRefs)
 	for _, serviceInstInfo := range serviceInstInfos {
 		serviceInstModuleRelation[serviceInstInfo.ID] = append(serviceInstModuleRelation[serviceInstInfo.ID], serviceInstInfo.ModuleID)
+		serviceInstConfigTemplates[serviceInstInfo.ID] = serviceInstInfo.ConfigTemplates
 	}
 
 	procInfos := make([]metadata.HostIdentProcess, 0)
@@ -227,6 +230,7 @@ func (i *Identifier) findHostServiceInst(kit *rest.Kit, hostIDs []int64) error {
 	// 主机和进程之间的关系,生成主机与进程的关系
 	for _, relation := range relations {
 		if procInfo, ok := procs[relation.ProcessID]; ok {
+			procInfo.SvcInstConfigTemplates = serviceInstConfigTemplates[relation.ServiceInstanceID]
 			i.hostProcRelation[relation.HostID] = append(i.hostProcRelation[relation.HostID], procInfo)
 		}
 	}
@@ -13,11 +13,19 @@
 package host
 
 import (
+	"configcenter/src/common"
 	"configcenter/src/common/http/rest"
 	"configcenter/src/common/metadata"
+	"configcenter/src/source_controller/coreservice/core/attrusage"
 )
 
 // ListHosts TODO
 func (hm *hostManager) ListHosts(kit *rest.Kit, input metadata.ListHosts) (*metadata.ListHostResult, error) {
+	readFields := input.Fields
+	if input.HostPropertyFilter != nil && input.HostPropertyFilter.Rule != nil {
+		readFields = append(readFields[:len(readFields):len(readFields)], input.HostPropertyFilter.GetField()...)
+	}
+	attrusage.RecordRead(kit, common.BKInnerObjIDHost, readFields, nil)
+
 	return hm.hostSearcher.ListHosts(kit.Ctx, input)
 }
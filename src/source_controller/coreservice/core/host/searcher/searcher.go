@@ -16,6 +16,7 @@ package searcher
 import (
 	"context"
 	"strings"
+	"time"
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -47,6 +48,12 @@ func (s *Searcher) ListHosts(ctx context.Context, option metadata.ListHosts) (se
 		return nil, err
 	}
 
+	var explain *metadata.SearchExplain
+	if option.Explain {
+		explain = &metadata.SearchExplain{Collection: common.BKTableNameBaseHost, Sort: option.Page.Sort,
+			Timings: make([]metadata.SearchExplainTiming, 0)}
+	}
+
 	relationFilter := map[string]interface{}{}
 	if option.BizID != 0 {
 		relationFilter[common.BKAppIDField] = option.BizID
@@ -69,15 +76,18 @@ func (s *Searcher) ListHosts(ctx context.Context, option metadata.ListHosts) (se
 	if len(relationFilter) > 0 {
 		needHostIDFilter = true
 		var err error
+		distinctStart := time.Now()
 		hostIDs, err = mongodb.Client().Table(common.BKTableNameModuleHostConfig).Distinct(ctx, common.BKHostIDField, relationFilter)
+		explain.AddTiming("distinct_relation_host_ids", distinctStart)
 		if err != nil {
 			blog.Errorf("ListHosts failed, db select failed, filter: %+v, err: %+v, rid: %s", relationFilter, err, rid)
 			return nil, err
 		}
 		if len(hostIDs) == 0 {
 			return &metadata.ListHostResult{
-				Count: 0,
-				Info:  []map[string]interface{}{},
+				Count:   0,
+				Info:    []map[string]interface{}{},
+				Explain: explain,
 			}, nil
 		}
 
@@ -107,7 +117,8 @@ func (s *Searcher) ListHosts(ctx context.Context, option metadata.ListHosts) (se
 		finalFilter[common.BKDBAND] = filters
 	}
 
-	if needHostIDFilter && len(filters) == 1 && option.BizID != 0 {
+	// the explain mode always searches the db with the final filter to explain it
+	if needHostIDFilter && len(filters) == 1 && option.BizID != 0 && !option.Explain {
 		sort := strings.TrimLeft(option.Page.Sort, "+-")
 		if len(option.Page.Sort) == 0 || sort == common.BKHostIDField || strings.Contains(sort, ",") == false &&
 			strings.HasPrefix(sort, common.BKHostIDField+":") {
@@ -120,7 +131,7 @@ func (s *Searcher) ListHosts(ctx context.Context, option metadata.ListHosts) (se
 
 	}
 
-	if len(filters) == 0 {
+	if len(filters) == 0 && !option.Explain {
 		// return info use cache
 		// fix: has question when multi-supplier
 		sort := strings.TrimLeft(option.Page.Sort, "+-")
@@ -137,7 +148,9 @@ func (s *Searcher) ListHosts(ctx context.Context, option metadata.ListHosts) (se
 		}
 	}
 
+	countStart := time.Now()
 	total, err := mongodb.Client().Table(common.BKTableNameBaseHost).Find(finalFilter).Count(ctx)
+	explain.AddTiming("count_hosts", countStart)
 	if err != nil {
 		blog.Errorf("ListHosts failed, db select failed, filter: %+v, err: %+v, rid: %s", finalFilter, err, rid)
 		return nil, err
@@ -155,14 +168,29 @@ func (s *Searcher) ListHosts(ctx context.Context, option metadata.ListHosts) (se
 	}
 
 	hosts := make([]metadata.HostMapStr, 0)
+	findStart := time.Now()
 	if err := query.All(ctx, &hosts); err != nil {
 		blog.Errorf("ListHosts failed, db select hosts failed, filter: %+v, err: %+v, rid: %s", finalFilter, err, rid)
 		return nil, err
 	}
+	explain.AddTiming("find_hosts", findStart)
 	searchResult.Info = make([]map[string]interface{}, len(hosts))
 	for index, host := range hosts {
 		searchResult.Info[index] = host
 	}
+
+	if explain != nil {
+		explain.Filter = finalFilter
+		if len(explain.Sort) == 0 {
+			explain.Sort = common.BKHostIDField
+		}
+		explain.Plan, err = query.Explain(ctx)
+		if err != nil {
+			blog.Errorf("explain list hosts failed, filter: %+v, err: %v, rid: %s", finalFilter, err, rid)
+			return nil, err
+		}
+		searchResult.Explain = explain
+	}
 	return searchResult, nil
 }
 
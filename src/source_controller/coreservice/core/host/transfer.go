@@ -62,3 +62,8 @@ func (hm *hostManager) GetDistinctHostIDsByTopoRelation(kit *rest.Kit, input *me
 func (hm *hostManager) TransferResourceDirectory(kit *rest.Kit, input *metadata.TransferHostResourceDirectory) errors.CCErrorCoder {
 	return hm.hostTransfer.TransferResourceDirectory(kit, input)
 }
+
+// MergeHost move the source host's relations to the target host and delete the source host
+func (hm *hostManager) MergeHost(kit *rest.Kit, input *metadata.MergeHostOption) error {
+	return hm.hostTransfer.MergeHost(kit, input)
+}
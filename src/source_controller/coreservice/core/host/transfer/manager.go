@@ -349,6 +349,12 @@ func (manager *TransferManager) GetHostModuleRelation(kit *rest.Kit, input *meta
 		return nil, nil
 	}
 	cond = util.SetQueryOwner(moduleHostCond.ToMapStr(), kit.SupplierAccount)
+	if input.AfterHostID > 0 {
+		// the host id may be used by HostIDArr, so the condition is combined with and
+		cond = mapstr.MapStr{common.BKDBAND: []mapstr.MapStr{
+			cond, {common.BKHostIDField: mapstr.MapStr{common.BKDBGT: input.AfterHostID}},
+		}}
+	}
 
 	cnt, err := mongodb.Client().Table(common.BKTableNameModuleHostConfig).Find(cond).Fields(input.Fields...).Count(kit.Ctx)
 	if err != nil {
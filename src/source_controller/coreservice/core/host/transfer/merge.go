@@ -0,0 +1,122 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transfer
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// MergeHost merge the duplicated source host into the target host of the same business, the target host is added
+// to the source host's normal modules, the source host's service instances and processes are re-pointed to the
+// target host, then the source host is deleted.
+func (manager *TransferManager) MergeHost(kit *rest.Kit, input *metadata.MergeHostOption) error {
+	hostIDs := []int64{input.SourceID, input.TargetID}
+	relationCond := util.SetQueryOwner(map[string]interface{}{
+		common.BKHostIDField: map[string]interface{}{common.BKDBIN: hostIDs},
+	}, kit.SupplierAccount)
+
+	relations := make([]metadata.ModuleHost, 0)
+	err := mongodb.Client().Table(common.BKTableNameModuleHostConfig).Find(relationCond).All(kit.Ctx, &relations)
+	if err != nil {
+		blog.Errorf("get merge host relations failed, err: %v, host ids: %v, rid: %s", err, hostIDs, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	hostBizMap := make(map[int64]int64)
+	sourceModuleIDs := make([]int64, 0)
+	for _, relation := range relations {
+		hostBizMap[relation.HostID] = relation.AppID
+		if relation.HostID == input.SourceID {
+			sourceModuleIDs = append(sourceModuleIDs, relation.ModuleID)
+		}
+	}
+
+	bizID, exists := hostBizMap[input.SourceID]
+	if !exists || hostBizMap[input.TargetID] != bizID {
+		blog.Errorf("merge host %d into %d, but they are not in the same biz, relations: %#v, rid: %s",
+			input.SourceID, input.TargetID, relations, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCoreServiceMergeHostNotInSameBiz, input.SourceID, input.TargetID)
+	}
+
+	// re-point the source host's service instances and process relations to the target host, they are bound to
+	// the source host's modules, which the target host will be added to.
+	hostCond := map[string]interface{}{common.BKHostIDField: input.SourceID}
+	hostData := map[string]interface{}{common.BKHostIDField: input.TargetID}
+	for _, table := range []string{common.BKTableNameServiceInstance, common.BKTableNameProcessInstanceRelation} {
+		if err := mongodb.Client().Table(table).Update(kit.Ctx, hostCond, hostData); err != nil {
+			blog.Errorf("re-point %s of host %d to %d failed, err: %v, rid: %s", table, input.SourceID,
+				input.TargetID, err, kit.Rid)
+			return kit.CCError.CCError(common.CCErrCommDBUpdateFailed)
+		}
+	}
+
+	normalModuleIDs, err := manager.getNormalModuleIDs(kit, sourceModuleIDs)
+	if err != nil {
+		return err
+	}
+
+	// the source host in idle or fault module has no relations to be moved, the target host keeps its modules.
+	if len(normalModuleIDs) > 0 {
+		transferOpt := &metadata.HostsModuleRelation{
+			ApplicationID:                bizID,
+			HostID:                       []int64{input.TargetID},
+			ModuleID:                     normalModuleIDs,
+			IsIncrement:                  true,
+			DisableAutoCreateSvcInst:     true,
+			DisableTransferHostAutoApply: true,
+		}
+		if err := manager.TransferToNormalModule(kit, transferOpt); err != nil {
+			blog.Errorf("transfer merge target host %d to modules %v failed, err: %v, rid: %s", input.TargetID,
+				normalModuleIDs, err, kit.Rid)
+			return err
+		}
+	}
+
+	deleteOpt := &metadata.DeleteHostRequest{ApplicationID: bizID, HostIDArr: []int64{input.SourceID}}
+	if err := manager.DeleteFromSystem(kit, deleteOpt); err != nil {
+		blog.Errorf("delete merged host %d failed, err: %v, rid: %s", input.SourceID, err, kit.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// getNormalModuleIDs get the ids of the modules which are not the idle, fault or recycle module
+func (manager *TransferManager) getNormalModuleIDs(kit *rest.Kit, moduleIDs []int64) ([]int64, error) {
+	if len(moduleIDs) == 0 {
+		return make([]int64, 0), nil
+	}
+
+	moduleCond := map[string]interface{}{
+		common.BKModuleIDField: map[string]interface{}{common.BKDBIN: moduleIDs},
+		common.BKDefaultField:  common.DefaultFlagDefaultValue,
+	}
+	modules := make([]metadata.ModuleInst, 0)
+	err := mongodb.Client().Table(common.BKTableNameBaseModule).Find(moduleCond).Fields(common.BKModuleIDField).
+		All(kit.Ctx, &modules)
+	if err != nil {
+		blog.Errorf("get normal modules failed, err: %v, module ids: %v, rid: %s", err, moduleIDs, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	normalModuleIDs := make([]int64, len(modules))
+	for index, module := range modules {
+		normalModuleIDs[index] = module.ModuleID
+	}
+	return normalModuleIDs, nil
+}
@@ -162,6 +162,17 @@ func (t *genericTransfer) DeleteHosts(kit *rest.Kit, hostIDs []int64) error {
 		return kit.CCError.CCErrorf(common.CCErrCommDBDeleteFailed)
 	}
 
+	// remove the external ids of the hosts
+	externalIDCond := map[string]interface{}{
+		common.BKObjIDField:   common.BKInnerObjIDHost,
+		common.BKInstIDField:  map[string]interface{}{common.BKDBIN: hostIDs},
+		common.BKOwnerIDField: kit.SupplierAccount,
+	}
+	if err := mongodb.Client().Table(common.BKTableNameExternalID).Delete(kit.Ctx, externalIDCond); err != nil {
+		blog.Errorf("delete host external ids failed, err: %v, host ID: %+v, rid: %s", err, hostIDs, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommDBDeleteFailed)
+	}
+
 	return nil
 }
 
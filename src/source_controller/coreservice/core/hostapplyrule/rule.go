@@ -548,8 +548,8 @@ func match(ctx context.Context, rules map[string]metadata.HostApplyRule, attribu
 			return false
 		}
 
-		strValue, ok := r.Value.(string)
-		if !ok {
+		strValue, err := r.GetStringValue()
+		if err != nil {
 			return false
 		}
 		if r.Operator == querybuilder.OperatorContains {
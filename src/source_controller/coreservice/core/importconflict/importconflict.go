@@ -0,0 +1,228 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package importconflict resolves the conflicts when two ingestion paths, e.g. the cloud sync and the host snapshot,
+// update the same instance attributes within a short window. the last import writes of the attributes are recorded,
+// and the later import's writes of the attributes are applied or dropped by its conflict policy, the conflicts are
+// recorded for review.
+package importconflict
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+const fieldsPrefix = "fields."
+
+var (
+	confLock sync.RWMutex
+	conf     = &metadata.ImportConflictConfig{Window: metadata.ImportConflictDefaultWindow}
+)
+
+// Init set the config of the conflict resolution, the default window is used if the window is not set.
+func Init(config *metadata.ImportConflictConfig) {
+	if config == nil {
+		return
+	}
+
+	newConf := &metadata.ImportConflictConfig{Window: config.Window, SourcePriority: config.SourcePriority}
+	if newConf.Window <= 0 {
+		newConf.Window = metadata.ImportConflictDefaultWindow
+	}
+
+	confLock.Lock()
+	conf = newConf
+	confLock.Unlock()
+}
+
+func getConfig() *metadata.ImportConflictConfig {
+	confLock.RLock()
+	defer confLock.RUnlock()
+	return conf
+}
+
+// Resolve resolves the conflicts of the import's update data of the instance, returns the data to be applied, the
+// attributes that lose the conflicts are removed from it. only the attributes that are changed and are written by
+// the other ingestion paths within the window are conflicted.
+func Resolve(kit *rest.Kit, ctx *metadata.ImportContext, objID string, instID int64, data,
+	origin mapstr.MapStr) (mapstr.MapStr, error) {
+
+	record, err := getWriteRecord(kit, objID, instID)
+	if err != nil {
+		return nil, err
+	}
+
+	if record == nil || len(record.Fields) == 0 {
+		return data, nil
+	}
+
+	config := getConfig()
+	now := time.Now()
+	resolved := data.Clone()
+	conflicts := make([]metadata.ImportConflict, 0)
+	for key, val := range data {
+		write, exists := record.Fields[key]
+		if !exists || write.Source == ctx.Source || now.Sub(write.WriteTime) > config.Window {
+			continue
+		}
+
+		if oldVal, exists := origin[key]; exists && fmt.Sprint(oldVal) == fmt.Sprint(val) {
+			continue
+		}
+
+		applied := isImportWin(config, ctx, &write)
+		if !applied {
+			delete(resolved, key)
+		}
+
+		conflicts = append(conflicts, metadata.ImportConflict{
+			ObjectID:         objID,
+			InstID:           instID,
+			PropertyID:       key,
+			Policy:           ctx.Policy,
+			Source:           ctx.Source,
+			DataTime:         ctx.DataTime,
+			Value:            val,
+			ConflictSource:   write.Source,
+			ConflictDataTime: write.DataTime,
+			Applied:          applied,
+			Operator:         kit.User,
+			OwnerID:          kit.SupplierAccount,
+			CreateTime:       now,
+		})
+	}
+
+	if err := saveConflicts(kit, conflicts); err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// isImportWin check if the import wins the conflict with the last write of the other ingestion path.
+func isImportWin(config *metadata.ImportConflictConfig, ctx *metadata.ImportContext,
+	write *metadata.ImportFieldWrite) bool {
+
+	switch ctx.Policy {
+	case metadata.ImportConflictSourcePriority:
+		return config.SourcePriority[ctx.Source] >= config.SourcePriority[write.Source]
+	case metadata.ImportConflictNewestWins:
+		return !ctx.DataTime.Before(write.DataTime)
+	default:
+		// field merge keeps the attributes written by the other ingestion path
+		return false
+	}
+}
+
+// RecordWrites records the import's writes of the instance attributes, which are used to find out the conflicts of
+// the later imports.
+func RecordWrites(kit *rest.Kit, ctx *metadata.ImportContext, objID string, instID int64, data mapstr.MapStr) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	doc := mapstr.MapStr{
+		common.BKObjIDField:      objID,
+		common.BKInstIDField:     instID,
+		common.BkSupplierAccount: kit.SupplierAccount,
+	}
+	for key := range data {
+		doc[fieldsPrefix+key] = metadata.ImportFieldWrite{Source: ctx.Source, DataTime: ctx.DataTime, WriteTime: now}
+	}
+
+	filter := mapstr.MapStr{common.BKObjIDField: objID, common.BKInstIDField: instID}
+	if err := mongodb.Client().Table(common.BKTableNameImportWriteRecord).Upsert(kit.Ctx, filter, doc); err != nil {
+		blog.Errorf("save %s instance %d import write record failed, err: %v, rid: %s", objID, instID, err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommDBUpdateFailed)
+	}
+
+	return nil
+}
+
+// Search searches the recorded conflicts of the concurrent imports, the newest conflicts are returned first.
+func Search(kit *rest.Kit, opt *metadata.SearchImportConflictOption) (*metadata.ImportConflictResult, error) {
+	filter := mapstr.MapStr{common.BKObjIDField: opt.ObjectID}
+	if len(opt.InstIDs) > 0 {
+		filter[common.BKInstIDField] = mapstr.MapStr{common.BKDBIN: opt.InstIDs}
+	}
+	if len(opt.Source) > 0 {
+		filter[common.BKDBOR] = []mapstr.MapStr{{"source": opt.Source}, {"conflict_source": opt.Source}}
+	}
+
+	table := mongodb.Client().Table(common.BKTableNameImportConflict)
+	count, err := table.Find(filter).Count(kit.Ctx)
+	if err != nil {
+		blog.Errorf("count import conflicts failed, filter: %v, err: %v, rid: %s", filter, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	conflicts := make([]metadata.ImportConflict, 0)
+	err = table.Find(filter).Sort("-"+common.BKFieldID).Start(uint64(opt.Page.Start)).
+		Limit(uint64(opt.Page.Limit)).All(kit.Ctx, &conflicts)
+	if err != nil {
+		blog.Errorf("search import conflicts failed, filter: %v, err: %v, rid: %s", filter, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	return &metadata.ImportConflictResult{Count: int64(count), Info: conflicts}, nil
+}
+
+func getWriteRecord(kit *rest.Kit, objID string, instID int64) (*metadata.ImportWriteRecord, error) {
+	filter := mapstr.MapStr{common.BKObjIDField: objID, common.BKInstIDField: instID}
+	records := make([]metadata.ImportWriteRecord, 0)
+	if err := mongodb.Client().Table(common.BKTableNameImportWriteRecord).Find(filter).Limit(1).All(kit.Ctx,
+		&records); err != nil {
+		blog.Errorf("get %s instance %d import write record failed, err: %v, rid: %s", objID, instID, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return &records[0], nil
+}
+
+func saveConflicts(kit *rest.Kit, conflicts []metadata.ImportConflict) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	ids, err := mongodb.Client().NextSequences(kit.Ctx, common.BKTableNameImportConflict, len(conflicts))
+	if err != nil {
+		blog.Errorf("generate import conflict ids failed, err: %v, rid: %s", err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommGenerateRecordIDFailed)
+	}
+
+	for index := range conflicts {
+		conflicts[index].ID = int64(ids[index])
+		blog.Warnf("import %s conflicts with %s on %s instance %d attribute %s, policy: %s, applied: %v, rid: %s",
+			conflicts[index].Source, conflicts[index].ConflictSource, conflicts[index].ObjectID,
+			conflicts[index].InstID, conflicts[index].PropertyID, conflicts[index].Policy, conflicts[index].Applied,
+			kit.Rid)
+	}
+
+	if err := mongodb.Client().Table(common.BKTableNameImportConflict).Insert(kit.Ctx, conflicts); err != nil {
+		blog.Errorf("save import conflicts failed, err: %v, rid: %s", err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommDBInsertFailed)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package importconflict
+
+import (
+	"testing"
+	"time"
+
+	"configcenter/src/common/metadata"
+)
+
+func TestIsImportWin(t *testing.T) {
+	config := &metadata.ImportConflictConfig{
+		Window:         time.Minute,
+		SourcePriority: map[string]int{metadata.ImportSourceCloudSync: 10, metadata.ImportSourceHostSnapshot: 5},
+	}
+	now := time.Now()
+	write := &metadata.ImportFieldWrite{Source: metadata.ImportSourceCloudSync, DataTime: now, WriteTime: now}
+
+	cases := []struct {
+		source   string
+		policy   string
+		dataTime time.Time
+		expected bool
+	}{
+		{metadata.ImportSourceHostSnapshot, metadata.ImportConflictSourcePriority, now, false},
+		{metadata.ImportSourceExcel, metadata.ImportConflictSourcePriority, now, false},
+		{metadata.ImportSourceHostSnapshot, metadata.ImportConflictNewestWins, now.Add(time.Second), true},
+		{metadata.ImportSourceHostSnapshot, metadata.ImportConflictNewestWins, now.Add(-time.Second), false},
+		{metadata.ImportSourceHostSnapshot, metadata.ImportConflictFieldMerge, now.Add(time.Second), false},
+	}
+
+	for index, c := range cases {
+		ctx := &metadata.ImportContext{Source: c.source, Policy: c.policy, DataTime: c.dataTime}
+		if win := isImportWin(config, ctx, write); win != c.expected {
+			t.Errorf("case %d: expected %v, got %v", index, c.expected, win)
+		}
+	}
+
+	// the later import wins if the priorities are the same
+	highWrite := &metadata.ImportFieldWrite{Source: metadata.ImportSourceHostSnapshot, DataTime: now, WriteTime: now}
+	ctx := &metadata.ImportContext{Source: metadata.ImportSourceCloudSync, Policy: metadata.ImportConflictSourcePriority}
+	if !isImportWin(config, ctx, highWrite) {
+		t.Errorf("the import of the higher priority source should win")
+	}
+	config.SourcePriority[metadata.ImportSourceHostSnapshot] = 10
+	if !isImportWin(config, ctx, highWrite) {
+		t.Errorf("the later import should win if the priorities are the same")
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package instances
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// BindExternalIDs binds the external ids to the instance, the instance's existing external id of the same source is
+// replaced. An external id can be bound to only one instance of the model in its source, the uniqueness is also
+// guaranteed by the unique index of the table when the external id is bound concurrently.
+func (m *instanceManager) BindExternalIDs(kit *rest.Kit, objID string, opt *metadata.BindExternalIDOption) error {
+	if _, err := m.getInstDataByID(kit, objID, opt.InstID); err != nil {
+		blog.Errorf("get %s instance %d failed, err: %v, rid: %s", objID, opt.InstID, err, kit.Rid)
+		if mongodb.Client().IsNotFoundError(err) {
+			return kit.CCError.CCError(common.CCErrCommNotFound)
+		}
+		return kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	for _, ref := range opt.ExternalIDs {
+		bound, err := m.getInstIDByExternalID(kit, objID, ref)
+		if err != nil {
+			return err
+		}
+
+		if bound == opt.InstID {
+			continue
+		}
+
+		if bound != 0 {
+			blog.Errorf("external id %s is bound to %s instance %d, rid: %s", ref.String(), objID, bound, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCoreServiceExternalIDConflict, ref.Source, ref.ID, bound)
+		}
+
+		// replace the instance's external id of the same source
+		cond := externalIDCond(kit, objID, mapstr.MapStr{common.BKInstIDField: opt.InstID, "source": ref.Source})
+		if err := mongodb.Client().Table(common.BKTableNameExternalID).Delete(kit.Ctx, cond); err != nil {
+			blog.Errorf("delete external id failed, err: %v, cond: %#v, rid: %s", err, cond, kit.Rid)
+			return kit.CCError.CCError(common.CCErrCommDBDeleteFailed)
+		}
+
+		now := time.Now()
+		externalID := metadata.ExternalID{
+			ObjID:           objID,
+			InstID:          opt.InstID,
+			Source:          ref.Source,
+			ID:              ref.ID,
+			SupplierAccount: kit.SupplierAccount,
+			Creator:         kit.User,
+			CreateTime:      now,
+			LastTime:        now,
+		}
+		if err := mongodb.Client().Table(common.BKTableNameExternalID).Insert(kit.Ctx, externalID); err != nil {
+			blog.Errorf("insert external id failed, err: %v, data: %#v, rid: %s", err, externalID, kit.Rid)
+			if !mongodb.Client().IsDuplicatedError(err) {
+				return kit.CCError.CCError(common.CCErrCommDBInsertFailed)
+			}
+
+			// the external id or the instance's source is bound concurrently
+			if bound, err := m.getInstIDByExternalID(kit, objID, ref); err == nil && bound != 0 {
+				return kit.CCError.CCErrorf(common.CCErrCoreServiceExternalIDConflict, ref.Source, ref.ID, bound)
+			}
+			return kit.CCError.CCErrorf(common.CCErrCommDuplicateItem, ref.Source)
+		}
+	}
+
+	return nil
+}
+
+// UnbindExternalIDs unbinds the external ids of the sources from the instance.
+func (m *instanceManager) UnbindExternalIDs(kit *rest.Kit, objID string, opt *metadata.UnbindExternalIDOption) error {
+	cond := externalIDCond(kit, objID, mapstr.MapStr{
+		common.BKInstIDField: opt.InstID,
+		"source":             mapstr.MapStr{common.BKDBIN: opt.Sources},
+	})
+	if err := mongodb.Client().Table(common.BKTableNameExternalID).Delete(kit.Ctx, cond); err != nil {
+		blog.Errorf("delete external ids failed, err: %v, cond: %#v, rid: %s", err, cond, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommDBDeleteFailed)
+	}
+	return nil
+}
+
+// SearchExternalIDs searches the external ids of the instances, or looks up the instances by the external ids.
+func (m *instanceManager) SearchExternalIDs(kit *rest.Kit, objID string, opt *metadata.SearchExternalIDOption) (
+	[]metadata.ExternalID, error) {
+
+	filter := mapstr.MapStr{}
+	if len(opt.InstIDs) > 0 {
+		filter[common.BKInstIDField] = mapstr.MapStr{common.BKDBIN: opt.InstIDs}
+	}
+	if len(opt.Source) > 0 {
+		filter["source"] = opt.Source
+	}
+	if len(opt.ExternalIDs) > 0 {
+		filter["external_id"] = mapstr.MapStr{common.BKDBIN: opt.ExternalIDs}
+	}
+
+	cond := externalIDCond(kit, objID, filter)
+	externalIDs := make([]metadata.ExternalID, 0)
+	if err := mongodb.Client().Table(common.BKTableNameExternalID).Find(cond).All(kit.Ctx, &externalIDs); err != nil {
+		blog.Errorf("search external ids failed, err: %v, cond: %#v, rid: %s", err, cond, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+	return externalIDs, nil
+}
+
+// getInstIDByExternalID returns the id of the instance bound to the external id, returns 0 if it is not bound.
+func (m *instanceManager) getInstIDByExternalID(kit *rest.Kit, objID string, ref metadata.ExternalIDRef) (int64,
+	error) {
+
+	cond := externalIDCond(kit, objID, mapstr.MapStr{"source": ref.Source, "external_id": ref.ID})
+	externalIDs := make([]metadata.ExternalID, 0)
+	err := mongodb.Client().Table(common.BKTableNameExternalID).Find(cond).Fields(common.BKInstIDField).
+		All(kit.Ctx, &externalIDs)
+	if err != nil {
+		blog.Errorf("get external id failed, err: %v, cond: %#v, rid: %s", err, cond, kit.Rid)
+		return 0, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	if len(externalIDs) == 0 {
+		return 0, nil
+	}
+	return externalIDs[0].InstID, nil
+}
+
+// deleteExternalIDs deletes the external ids of the deleted instances.
+func (m *instanceManager) deleteExternalIDs(kit *rest.Kit, objID string, instIDs []int64) error {
+	if len(instIDs) == 0 {
+		return nil
+	}
+
+	cond := externalIDCond(kit, objID, mapstr.MapStr{common.BKInstIDField: mapstr.MapStr{common.BKDBIN: instIDs}})
+	if err := mongodb.Client().Table(common.BKTableNameExternalID).Delete(kit.Ctx, cond); err != nil {
+		blog.Errorf("delete external ids of %s instances %v failed, err: %v, rid: %s", objID, instIDs, err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommDBDeleteFailed)
+	}
+	return nil
+}
+
+func externalIDCond(kit *rest.Kit, objID string, cond mapstr.MapStr) mapstr.MapStr {
+	cond[common.BKObjIDField] = objID
+	cond[common.BKOwnerIDField] = kit.SupplierAccount
+	return cond
+}
@@ -26,6 +26,8 @@ import (
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/util"
 	"configcenter/src/source_controller/coreservice/core"
+	"configcenter/src/source_controller/coreservice/core/attrusage"
+	"configcenter/src/source_controller/coreservice/core/importconflict"
 	"configcenter/src/storage/driver/mongodb"
 	"configcenter/src/storage/driver/mongodb/instancemapping"
 	"configcenter/src/thirdparty/hooks"
@@ -34,17 +36,28 @@ import (
 var _ core.InstanceOperation = (*instanceManager)(nil)
 
 type instanceManager struct {
-	dependent OperationDependences
-	language  language.CCLanguageIf
-	clientSet apimachinery.ClientSetInterface
+	dependent     OperationDependences
+	language      language.CCLanguageIf
+	clientSet     apimachinery.ClientSetInterface
+	hostLifecycle *metadata.HostLifecycleConfig
+	// nameCollation the policy to compare the instance names in the uniqueness checks
+	nameCollation *metadata.InstNameCollation
 }
 
 // New create a new instance manager instance
-func New(dependent OperationDependences, language language.CCLanguageIf, clientSet apimachinery.ClientSetInterface) core.InstanceOperation {
+func New(dependent OperationDependences, language language.CCLanguageIf, clientSet apimachinery.ClientSetInterface,
+	hostLifecycle *metadata.HostLifecycleConfig, nameCollation *metadata.InstNameCollation) core.InstanceOperation {
+
+	if hostLifecycle == nil {
+		hostLifecycle = metadata.DefaultHostLifecycleConfig()
+	}
+
 	return &instanceManager{
-		dependent: dependent,
-		language:  language,
-		clientSet: clientSet,
+		dependent:     dependent,
+		language:      language,
+		clientSet:     clientSet,
+		hostLifecycle: hostLifecycle,
+		nameCollation: nameCollation,
 	}
 }
 
@@ -59,6 +72,10 @@ func (m *instanceManager) instCnt(kit *rest.Kit, objID string, cond mapstr.MapSt
 func (m *instanceManager) CreateModelInstance(kit *rest.Kit, objID string, inputParam metadata.CreateModelInstance) (*metadata.CreateOneDataResult, error) {
 	rid := util.ExtractRequestIDFromContext(kit.Ctx)
 
+	if err := m.validModelWritable(kit, objID); err != nil {
+		return nil, err
+	}
+
 	inputParam.Data.Set(common.BKOwnerIDField, kit.SupplierAccount)
 	bizID, err := m.getBizIDFromInstance(kit, objID, inputParam.Data, common.ValidCreate, 0)
 	if err != nil {
@@ -83,6 +100,7 @@ func (m *instanceManager) CreateModelInstance(kit *rest.Kit, objID string, input
 			err, objID, inputParam.Data, kit.Rid)
 		return nil, err
 	}
+	attrusage.RecordWrite(kit, objID, inputParam.Data)
 
 	return &metadata.CreateOneDataResult{Created: metadata.CreatedDataResult{ID: id}}, err
 }
@@ -96,6 +114,10 @@ func (m *instanceManager) CreateManyModelInstance(kit *rest.Kit, objID string,
 		return dataResult, nil
 	}
 
+	if err := m.validModelWritable(kit, objID); err != nil {
+		return nil, err
+	}
+
 	instValidators, err := m.getValidatorsFromInstances(kit, objID, inputParam.Datas, common.ValidCreate)
 	if err != nil {
 		blog.Errorf("get inst(%#v) validators failed, err: %v, obj: %s, rid:%s", err, objID, inputParam.Datas, kit.Rid)
@@ -167,6 +189,7 @@ func (m *instanceManager) CreateManyModelInstance(kit *rest.Kit, objID string,
 			OriginIndex: int64(index),
 		})
 	}
+	attrusage.RecordWrite(kit, objID, inputParam.Datas...)
 
 	return dataResult, nil
 }
@@ -175,6 +198,10 @@ func (m *instanceManager) CreateManyModelInstance(kit *rest.Kit, objID string,
 func (m *instanceManager) UpdateModelInstance(kit *rest.Kit, objID string, inputParam metadata.UpdateOption) (
 	*metadata.UpdatedCount, error) {
 
+	if err := m.validModelWritable(kit, objID); err != nil {
+		return nil, err
+	}
+
 	inputParam.Condition = util.SetModOwner(inputParam.Condition, kit.SupplierAccount)
 	origins, _, err := m.getInsts(kit, objID, inputParam.Condition)
 	if err != nil {
@@ -234,12 +261,26 @@ func (m *instanceManager) UpdateModelInstance(kit *rest.Kit, objID string, input
 		}
 	}
 
+	importCtx, rawErr := metadata.GetImportContext(kit.Header)
+	if rawErr.ErrCode != 0 {
+		return nil, rawErr.ToCCError(kit.CCError)
+	}
+
+	if importCtx != nil {
+		if err := m.updateImportInstances(kit, objID, importCtx, inputParam.Data, origins); err != nil {
+			return nil, err
+		}
+		attrusage.RecordWrite(kit, objID, inputParam.Data)
+		return &metadata.UpdatedCount{Count: uint64(len(origins))}, nil
+	}
+
 	err = m.update(kit, objID, inputParam.Data, inputParam.Condition)
 	if err != nil {
 		blog.Errorf("update objID(%s) inst failed, err: %v, condition: %#v, data: %#v rid: %s", objID, err,
 			inputParam.Condition, inputParam.Data, kit.Rid)
 		return nil, err
 	}
+	attrusage.RecordWrite(kit, objID, inputParam.Data)
 
 	if objID == common.BKInnerObjIDHost {
 		if err := m.updateHostProcessBindIP(kit, inputParam.Data, origins); err != nil {
@@ -250,6 +291,49 @@ func (m *instanceManager) UpdateModelInstance(kit *rest.Kit, objID string, input
 	return &metadata.UpdatedCount{Count: uint64(len(origins))}, nil
 }
 
+// updateImportInstances update the instances by an import, the conflicts with the other ingestion paths are resolved
+// for each instance by the import's conflict policy, so the instances are updated one by one with the resolved data.
+func (m *instanceManager) updateImportInstances(kit *rest.Kit, objID string, importCtx *metadata.ImportContext,
+	data mapstr.MapStr, origins []mapstr.MapStr) error {
+
+	instIDField := common.GetInstIDField(objID)
+	for _, origin := range origins {
+		instID, err := util.GetInt64ByInterface(origin[instIDField])
+		if err != nil {
+			blog.Errorf("parse inst id failed, err: %v, objID: %s, data: %#v, rid: %s", err, objID, origin, kit.Rid)
+			return err
+		}
+
+		resolved, err := importconflict.Resolve(kit, importCtx, objID, instID, data, origin)
+		if err != nil {
+			return err
+		}
+
+		if len(resolved) == 0 {
+			continue
+		}
+
+		cond := util.SetModOwner(mapstr.MapStr{instIDField: instID}, kit.SupplierAccount)
+		if err := m.update(kit, objID, resolved, cond); err != nil {
+			blog.Errorf("update objID(%s) inst %d failed, err: %v, data: %#v rid: %s", objID, instID, err, resolved,
+				kit.Rid)
+			return err
+		}
+
+		if err := importconflict.RecordWrites(kit, importCtx, objID, instID, resolved); err != nil {
+			return err
+		}
+
+		if objID == common.BKInnerObjIDHost {
+			if err := m.updateHostProcessBindIP(kit, resolved, []mapstr.MapStr{origin}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // updateHostProcessBindIP if hosts' ips are updated, update processes which binds the changed ip
 func (m *instanceManager) updateHostProcessBindIP(kit *rest.Kit, updateData mapstr.MapStr, origins []mapstr.MapStr) error {
 	innerIP, innerIPExist := updateData[common.BKHostInnerIPField]
@@ -407,6 +491,7 @@ func (m *instanceManager) updateProcessBindIP(kit *rest.Kit, data map[string]int
 // SearchModelInstance TODO
 func (m *instanceManager) SearchModelInstance(kit *rest.Kit, objID string, inputParam metadata.QueryCondition) (*metadata.QueryResult, error) {
 	blog.V(9).Infof("search instance with parameter: %+v, rid: %s", inputParam, kit.Rid)
+	attrusage.RecordRead(kit, objID, inputParam.Fields, inputParam.Condition)
 
 	tableName := common.GetInstTableName(objID, kit.SupplierAccount)
 	if common.IsObjectInstShardingTable(tableName) {
@@ -509,9 +594,14 @@ func (m *instanceManager) CountModelInstances(kit *rest.Kit,
 // DeleteModelInstance TODO
 func (m *instanceManager) DeleteModelInstance(kit *rest.Kit, objID string, inputParam metadata.DeleteOption) (*metadata.DeletedCount, error) {
 	instIDs := []int64{}
+	deletedIDs := make([]int64, 0)
 	tableName := common.GetInstTableName(objID, kit.SupplierAccount)
 	instIDFieldName := common.GetInstIDField(objID)
 
+	if err := m.validModelWritable(kit, objID); err != nil {
+		return nil, err
+	}
+
 	inputParam.Condition.Set(common.BKOwnerIDField, kit.SupplierAccount)
 	inputParam.Condition = util.SetModOwner(inputParam.Condition, kit.SupplierAccount)
 
@@ -525,6 +615,7 @@ func (m *instanceManager) DeleteModelInstance(kit *rest.Kit, objID string, input
 		if nil != err {
 			return nil, err
 		}
+		deletedIDs = append(deletedIDs, instID)
 		if metadata.IsCommon(objID) {
 			instIDs = append(instIDs, instID)
 		}
@@ -554,15 +645,28 @@ func (m *instanceManager) DeleteModelInstance(kit *rest.Kit, objID string, input
 		}
 	}
 
+	if err := m.deleteExternalIDs(kit, objID, deletedIDs); err != nil {
+		return nil, err
+	}
+
+	if err := m.deleteTemplateVariables(kit, objID, deletedIDs); err != nil {
+		return nil, err
+	}
+
 	return &metadata.DeletedCount{Count: uint64(len(origins))}, nil
 }
 
 // CascadeDeleteModelInstance TODO
 func (m *instanceManager) CascadeDeleteModelInstance(kit *rest.Kit, objID string, inputParam metadata.DeleteOption) (*metadata.DeletedCount, error) {
 	instIDs := []int64{}
+	deletedIDs := make([]int64, 0)
 	tableName := common.GetInstTableName(objID, kit.SupplierAccount)
 	instIDFieldName := common.GetInstIDField(objID)
 
+	if err := m.validModelWritable(kit, objID); err != nil {
+		return nil, err
+	}
+
 	origins, _, err := m.getInsts(kit, objID, inputParam.Condition)
 	if nil != err {
 		blog.Errorf("cascade delete model instance get inst error:%v, rid: %s", err, kit.Rid)
@@ -574,6 +678,7 @@ func (m *instanceManager) CascadeDeleteModelInstance(kit *rest.Kit, objID string
 		if nil != err {
 			return &metadata.DeletedCount{}, err
 		}
+		deletedIDs = append(deletedIDs, instID)
 		if metadata.IsCommon(objID) {
 			instIDs = append(instIDs, instID)
 		}
@@ -600,5 +705,13 @@ func (m *instanceManager) CascadeDeleteModelInstance(kit *rest.Kit, objID string
 		}
 	}
 
+	if err := m.deleteExternalIDs(kit, objID, deletedIDs); err != nil {
+		return nil, err
+	}
+
+	if err := m.deleteTemplateVariables(kit, objID, deletedIDs); err != nil {
+		return nil, err
+	}
+
 	return &metadata.DeletedCount{Count: uint64(len(origins))}, nil
 }
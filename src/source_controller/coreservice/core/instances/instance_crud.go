@@ -28,6 +28,66 @@ import (
 )
 
 func (m *instanceManager) save(kit *rest.Kit, objID string, inputParam mapstr.MapStr) (uint64, error) {
+	inputParam, id, err := m.buildNewInstance(kit, objID, inputParam)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := m.saveInstanceMapping(kit, objID, id); err != nil {
+		return 0, err
+	}
+
+	// save object instance.
+	instTableName := common.GetInstTableName(objID, kit.SupplierAccount)
+	err = mongodb.Client().Table(instTableName).Insert(kit.Ctx, inputParam)
+	if err != nil {
+		blog.ErrorJSON("save instance error. err: %s, objID: %s, instance: %s, rid: %s",
+			err.Error(), objID, inputParam, kit.Rid)
+		if mongodb.Client().IsDuplicatedError(err) {
+			return id, kit.CCError.CCErrorf(common.CCErrCommDuplicateItem, mongodb.GetDuplicateKey(err))
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// saveIfAbsent saves the instance only if no instance matches the condition, the condition must hit the unique index
+// so that the instance is not created concurrently. returns the id of the new instance and whether it is saved.
+func (m *instanceManager) saveIfAbsent(kit *rest.Kit, objID string, inputParam, cond mapstr.MapStr) (uint64, bool,
+	error) {
+
+	inputParam, id, err := m.buildNewInstance(kit, objID, inputParam)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if !util.IsInnerObject(objID) {
+		cond.Set(common.BKObjIDField, objID)
+	}
+
+	instTableName := common.GetInstTableName(objID, kit.SupplierAccount)
+	inserted, err := mongodb.Client().Table(instTableName).InsertIfAbsent(kit.Ctx, cond, inputParam)
+	if err != nil {
+		blog.Errorf("save %s instance if absent failed, err: %v, cond: %#v, rid: %s", objID, err, cond, kit.Rid)
+		return 0, false, err
+	}
+
+	if !inserted {
+		return 0, false, nil
+	}
+
+	if err := m.saveInstanceMapping(kit, objID, id); err != nil {
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
+
+// buildNewInstance generates the id of the new instance, and sets the fields that are set on creation.
+func (m *instanceManager) buildNewInstance(kit *rest.Kit, objID string, inputParam mapstr.MapStr) (mapstr.MapStr,
+	uint64, error) {
+
 	if objID == common.BKInnerObjIDHost {
 		inputParam = metadata.ConvertHostSpecialStringToArray(inputParam)
 	}
@@ -35,12 +95,11 @@ func (m *instanceManager) save(kit *rest.Kit, objID string, inputParam mapstr.Ma
 	instTableName := common.GetInstTableName(objID, kit.SupplierAccount)
 	id, err := mongodb.Client().NextSequence(kit.Ctx, instTableName)
 	if err != nil {
-		return 0, err
+		return nil, 0, err
 	}
 
 	// build new object instance data.
-	instIDFieldName := common.GetInstIDField(objID)
-	inputParam[instIDFieldName] = id
+	inputParam[common.GetInstIDField(objID)] = id
 	if !util.IsInnerObject(objID) {
 		inputParam[common.BKObjIDField] = objID
 	}
@@ -49,31 +108,22 @@ func (m *instanceManager) save(kit *rest.Kit, objID string, inputParam mapstr.Ma
 	inputParam.Set(common.CreateTimeField, ts)
 	inputParam.Set(common.LastTimeField, ts)
 
-	// build and save new object mapping data for inner object instance.
-	if metadata.IsCommon(objID) {
-		mapping := make(mapstr.MapStr, 0)
-		mapping[instIDFieldName] = id
-		mapping[common.BKObjIDField] = objID
-		mapping[common.BkSupplierAccount] = kit.SupplierAccount
+	return inputParam, id, nil
+}
 
-		// save instance object type mapping.
-		if err := instancemapping.Create(kit.Ctx, mapping); err != nil {
-			return 0, err
-		}
+// saveInstanceMapping saves the object mapping data for the instance of the common object.
+func (m *instanceManager) saveInstanceMapping(kit *rest.Kit, objID string, id uint64) error {
+	if !metadata.IsCommon(objID) {
+		return nil
 	}
 
-	// save object instance.
-	err = mongodb.Client().Table(instTableName).Insert(kit.Ctx, inputParam)
-	if err != nil {
-		blog.ErrorJSON("save instance error. err: %s, objID: %s, instance: %s, rid: %s",
-			err.Error(), objID, inputParam, kit.Rid)
-		if mongodb.Client().IsDuplicatedError(err) {
-			return id, kit.CCError.CCErrorf(common.CCErrCommDuplicateItem, mongodb.GetDuplicateKey(err))
-		}
-		return 0, err
-	}
+	mapping := make(mapstr.MapStr, 0)
+	mapping[common.GetInstIDField(objID)] = id
+	mapping[common.BKObjIDField] = objID
+	mapping[common.BkSupplierAccount] = kit.SupplierAccount
 
-	return id, nil
+	// save instance object type mapping.
+	return instancemapping.Create(kit.Ctx, mapping)
 }
 
 func (m *instanceManager) update(kit *rest.Kit, objID string, data mapstr.MapStr, cond mapstr.MapStr) errors.CCError {
@@ -0,0 +1,275 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package instances
+
+import (
+	"reflect"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core/attrusage"
+)
+
+// CreateOrUpdateModelInstance creates the instance, or updates the instance with the same natural key if it exists.
+// The instance is created by an atomic upsert on the natural key which hits the unique index, so if the instance is
+// created by another request after it is searched, the upsert matches it and it is updated instead. In a transaction
+// the concurrent creation causes the write conflict, and the transaction is retried by the caller.
+func (m *instanceManager) CreateOrUpdateModelInstance(kit *rest.Kit, objID string,
+	inputParam metadata.CreateOrUpdateModelInstance) (*metadata.CreateOrUpdateInstResult, error) {
+
+	if len(inputParam.Data) == 0 {
+		blog.Errorf("create or update %s instance, but data is empty, rid: %s", objID, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParamsNeedSet, "data")
+	}
+
+	inputParam.Data.Set(common.BKOwnerIDField, kit.SupplierAccount)
+	bizID, err := m.getBizIDFromInstance(kit, objID, inputParam.Data, common.ValidCreate, 0)
+	if err != nil {
+		blog.Errorf("get biz id from instance failed, err: %v, objID: %s, data: %#v, rid: %s", err, objID,
+			inputParam.Data, kit.Rid)
+		return nil, err
+	}
+
+	validator, err := m.newValidator(kit, objID, bizID)
+	if err != nil {
+		blog.Errorf("new validator failed, err: %v, objID: %s, rid: %s", err, objID, kit.Rid)
+		return nil, err
+	}
+
+	if inputParam.ExternalID != nil {
+		if rawErr := inputParam.ExternalID.Validate(); rawErr.ErrCode != 0 {
+			return nil, rawErr.ToCCError(kit.CCError)
+		}
+
+		return m.createOrUpdateByExternalID(kit, objID, validator, inputParam)
+	}
+
+	return m.createOrUpdateByNaturalKey(kit, objID, validator, inputParam)
+}
+
+// createOrUpdateByNaturalKey creates the instance, or updates the instance with the same natural key if it exists.
+func (m *instanceManager) createOrUpdateByNaturalKey(kit *rest.Kit, objID string, validator *validator,
+	inputParam metadata.CreateOrUpdateModelInstance) (*metadata.CreateOrUpdateInstResult, error) {
+
+	keyCond, keys, err := m.getNaturalKeyCond(kit, validator, inputParam)
+	if err != nil {
+		return nil, err
+	}
+
+	origin, err := m.getInstByNaturalKey(kit, objID, keyCond, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if origin != nil {
+		return m.updateByNaturalKey(kit, objID, inputParam.Data.Clone(), origin)
+	}
+
+	if err := m.validModelWritable(kit, objID); err != nil {
+		return nil, err
+	}
+
+	data := inputParam.Data.Clone()
+	if err := m.validCreateInstanceData(kit, objID, data, validator); err != nil {
+		blog.Errorf("valid create %s instance data failed, err: %v, data: %#v, rid: %s", objID, err, data, kit.Rid)
+		return nil, err
+	}
+
+	id, saved, err := m.saveIfAbsent(kit, objID, data, keyCond.Clone())
+	if err != nil {
+		return nil, err
+	}
+
+	if saved {
+		attrusage.RecordWrite(kit, objID, data)
+		return &metadata.CreateOrUpdateInstResult{ID: id, Created: true}, nil
+	}
+
+	// the instance is created by another request after it is searched, update it instead.
+	blog.Warnf("%s instance with natural key %#v is created concurrently, rid: %s", objID, keyCond, kit.Rid)
+	origin, err = m.getInstByNaturalKey(kit, objID, keyCond, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if origin == nil {
+		blog.Errorf("%s instance with natural key %#v is not found after upsert, rid: %s", objID, keyCond, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommNotFound)
+	}
+
+	return m.updateByNaturalKey(kit, objID, inputParam.Data.Clone(), origin)
+}
+
+// getInstByNaturalKey returns the instance matched by the natural key, returns nil if it does not exist.
+func (m *instanceManager) getInstByNaturalKey(kit *rest.Kit, objID string, keyCond mapstr.MapStr, keys []string) (
+	mapstr.MapStr, error) {
+
+	origins, _, err := m.getInsts(kit, objID, keyCond.Clone())
+	if err != nil {
+		blog.Errorf("get %s instance by natural key failed, err: %v, cond: %#v, rid: %s", objID, err, keyCond, kit.Rid)
+		return nil, err
+	}
+
+	if len(origins) > 1 {
+		blog.Errorf("natural key %#v matches %d %s instances, rid: %s", keyCond, len(origins), objID, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommDuplicateItem, strings.Join(keys, ","))
+	}
+
+	if len(origins) == 0 {
+		return nil, nil
+	}
+
+	return origins[0], nil
+}
+
+// createOrUpdateByExternalID updates the instance bound to the external id if it exists, otherwise it falls back to
+// the natural key, and creates the instance if no unique rule has all keys set in the data. The created or updated
+// instance is then bound to the external id, so that the following requests match it by the external id directly.
+func (m *instanceManager) createOrUpdateByExternalID(kit *rest.Kit, objID string, validator *validator,
+	inputParam metadata.CreateOrUpdateModelInstance) (*metadata.CreateOrUpdateInstResult, error) {
+
+	ref := *inputParam.ExternalID
+	boundID, err := m.getInstIDByExternalID(kit, objID, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *metadata.CreateOrUpdateInstResult
+	if boundID != 0 {
+		origin, err := m.getInstDataByID(kit, objID, boundID)
+		if err != nil {
+			blog.Errorf("get %s instance %d bound to external id %s failed, err: %v, rid: %s", objID, boundID,
+				ref.String(), err, kit.Rid)
+			return nil, err
+		}
+		return m.updateByNaturalKey(kit, objID, inputParam.Data.Clone(), origin)
+	}
+
+	_, _, err = m.getNaturalKeyCond(kit, validator, inputParam)
+	switch {
+	case err == nil:
+		result, err = m.createOrUpdateByNaturalKey(kit, objID, validator, inputParam)
+	case inputParam.UniqueID == 0:
+		// the instance can only be matched by the external id, which is not bound yet
+		var created *metadata.CreateOneDataResult
+		created, err = m.CreateModelInstance(kit, objID, metadata.CreateModelInstance{Data: inputParam.Data.Clone()})
+		if err == nil {
+			result = &metadata.CreateOrUpdateInstResult{ID: created.Created.ID, Created: true}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bindOpt := &metadata.BindExternalIDOption{InstID: int64(result.ID), ExternalIDs: []metadata.ExternalIDRef{ref}}
+	if err := m.BindExternalIDs(kit, objID, bindOpt); err != nil {
+		blog.Errorf("bind external id %s to %s instance %d failed, err: %v, rid: %s", ref.String(), objID,
+			result.ID, err, kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getNaturalKeyCond returns the condition to search the instance by the keys of the unique rule, and the keys.
+func (m *instanceManager) getNaturalKeyCond(kit *rest.Kit, validator *validator,
+	inputParam metadata.CreateOrUpdateModelInstance) (mapstr.MapStr, []string, error) {
+
+	uniqueOpts, err := validator.getValidUniqueOptions(kit, inputParam.Data, m)
+	if err != nil {
+		blog.Errorf("get unique options failed, err: %v, data: %#v, rid: %s", err, inputParam.Data, kit.Rid)
+		return nil, nil, err
+	}
+
+	// the unique options are generated in the order of the unique rules of the validator.
+	for index, opt := range uniqueOpts {
+		unique := validator.uniqueAttrs[index]
+		if inputParam.UniqueID != 0 && unique.ID != inputParam.UniqueID {
+			continue
+		}
+
+		missingKeys := make([]string, 0)
+		for _, key := range opt.UniqueKeys {
+			if val, exists := inputParam.Data[key]; !exists || val == nil || val == "" {
+				missingKeys = append(missingKeys, key)
+			}
+		}
+
+		if len(missingKeys) == 0 && len(opt.UniqueKeys) > 0 {
+			return opt.Condition.ToMapStr(), opt.UniqueKeys, nil
+		}
+
+		if inputParam.UniqueID != 0 {
+			blog.Errorf("unique %d keys %v are not set, rid: %s", unique.ID, missingKeys, kit.Rid)
+			return nil, nil, kit.CCError.CCErrorf(common.CCErrCommParamsNeedSet, strings.Join(missingKeys, ","))
+		}
+	}
+
+	if inputParam.UniqueID != 0 {
+		blog.Errorf("unique %d is not found in %s, rid: %s", inputParam.UniqueID, validator.objID, kit.Rid)
+		return nil, nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, "bk_unique_id")
+	}
+
+	blog.Errorf("no unique rule of %s has all keys set in data %#v, rid: %s", validator.objID, inputParam.Data,
+		kit.Rid)
+	return nil, nil, kit.CCError.CCErrorf(common.CCErrCommParamsNeedSet, "unique keys")
+}
+
+// updateByNaturalKey updates the existing instance, the instance is updated only if its values are changed.
+func (m *instanceManager) updateByNaturalKey(kit *rest.Kit, objID string, data, origin mapstr.MapStr) (
+	*metadata.CreateOrUpdateInstResult, error) {
+
+	instIDField := common.GetInstIDField(objID)
+	instID, err := util.GetInt64ByInterface(origin[instIDField])
+	if err != nil {
+		blog.Errorf("parse inst id failed, err: %v, objID: %s, inst: %#v, rid: %s", err, objID, origin, kit.Rid)
+		return nil, err
+	}
+
+	data.Remove(instIDField)
+	data.Remove(common.BKOwnerIDField)
+	data.Remove(common.BKObjIDField)
+	result := &metadata.CreateOrUpdateInstResult{ID: uint64(instID), PreData: origin}
+
+	updateOpt := metadata.UpdateOption{
+		Data:      data,
+		Condition: mapstr.MapStr{instIDField: instID},
+	}
+	if _, err := m.UpdateModelInstance(kit, objID, updateOpt); err != nil {
+		blog.Errorf("update %s instance %d failed, err: %v, data: %#v, rid: %s", objID, instID, err, data, kit.Rid)
+		return nil, err
+	}
+
+	current, err := m.getInstDataByID(kit, objID, instID)
+	if err != nil {
+		blog.Errorf("get %s instance %d failed, err: %v, rid: %s", objID, instID, err, kit.Rid)
+		return nil, err
+	}
+
+	for field, value := range current {
+		if field == common.LastTimeField {
+			continue
+		}
+		if !reflect.DeepEqual(origin[field], value) {
+			result.Updated = true
+			break
+		}
+	}
+
+	return result, nil
+}
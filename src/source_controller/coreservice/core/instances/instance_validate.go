@@ -14,6 +14,7 @@ package instances
 
 import (
 	stderr "errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -152,12 +153,20 @@ func (m *instanceManager) validCreateInstanceData(kit *rest.Kit, objID string, i
 			return valid.errIf.Errorf(common.CCErrCommParamsNeedSet, key)
 		}
 	}
+
+	if err := valid.validAttrsWritable(kit, instanceData, true); err != nil {
+		return err
+	}
 	FillLostedFieldValue(kit.Ctx, instanceData, valid.propertySlice)
 
 	if err := m.validCloudID(kit, objID, instanceData); err != nil {
 		return err
 	}
 
+	if err := m.validCreateHostLifecycle(kit, objID, instanceData); err != nil {
+		return err
+	}
+
 	isMainline, err := m.isMainlineObject(kit, objID)
 	if err != nil {
 		return err
@@ -205,6 +214,10 @@ func (m *instanceManager) validCreateInstanceData(kit *rest.Kit, objID string, i
 		}
 	}
 
+	if err := m.validEnumCascadeAttrs(kit, instanceData, instanceData, valid); err != nil {
+		return err
+	}
+
 	skip, err := hooks.IsSkipValidateHook(kit, objID, instanceData)
 	if err != nil {
 		blog.Errorf("check is skip validate %s hook failed, err: %v, rid: %s", objID, err, kit.Rid)
@@ -230,7 +243,7 @@ func (m *instanceManager) validCreateInstanceData(kit *rest.Kit, objID string, i
 		}
 	}
 
-	return nil
+	return m.validInstNameCollation(kit, objID, instanceData, valid, 0)
 }
 
 func (m *instanceManager) validateModuleCreate(kit *rest.Kit, instanceData mapstr.MapStr, valid *validator) error {
@@ -277,6 +290,10 @@ func (m *instanceManager) validateModuleCreate(kit *rest.Kit, instanceData mapst
 
 func (m *instanceManager) validUpdateInstanceData(kit *rest.Kit, objID string, updateData mapstr.MapStr,
 	instanceData mapstr.MapStr, valid *validator, instID int64, canEditAll, isMainline bool) error {
+	if err := valid.validAttrsWritable(kit, updateData, false); err != nil {
+		return err
+	}
+
 	if err := m.validCloudID(kit, objID, updateData); err != nil {
 		return err
 	}
@@ -326,6 +343,25 @@ func (m *instanceManager) validUpdateInstanceData(kit *rest.Kit, objID string, u
 		}
 	}
 
+	updatedInst := make(mapstr.MapStr, len(instanceData)+len(updateData))
+	updatedInst.Merge(instanceData)
+	updatedInst.Merge(updateData)
+	if err := m.validEnumCascadeAttrs(kit, updateData, updatedInst, valid); err != nil {
+		return err
+	}
+
+	if err := m.validUpdateHostLifecycle(kit, objID, updateData, instanceData); err != nil {
+		return err
+	}
+
+	if err := m.validUpdateDelegatedAttrs(kit, updateData, instanceData, valid); err != nil {
+		return err
+	}
+
+	if err := m.validUpdateOwnedAttrs(kit, objID, updateData, instanceData, valid); err != nil {
+		return err
+	}
+
 	if err := m.changeStringToTime(updateData, valid.propertySlice); err != nil {
 		blog.Errorf("there is an error in converting the time type string to the time type, err: %s, rid: %s", err, kit.Rid)
 		return err
@@ -341,7 +377,18 @@ func (m *instanceManager) validUpdateInstanceData(kit *rest.Kit, objID string, u
 		return nil
 	}
 
-	return nil
+	if _, exists := updateData[metadata.GetInstNameFieldName(objID)]; !exists {
+		return nil
+	}
+
+	updatedData := make(mapstr.MapStr, len(instanceData)+len(updateData))
+	for key, val := range instanceData {
+		updatedData[key] = val
+	}
+	for key, val := range updateData {
+		updatedData[key] = val
+	}
+	return m.validInstNameCollation(kit, objID, updatedData, valid, instID)
 }
 
 func (m *instanceManager) isMainlineObject(kit *rest.Kit, objID string) (bool, error) {
@@ -414,6 +461,200 @@ func (m *instanceManager) validCloudID(kit *rest.Kit, objID string, instanceData
 	return nil
 }
 
+// validCreateHostLifecycle set the created host's lifecycle state to the initial state if it is not set,
+// otherwise the state must be defined in the host lifecycle.
+func (m *instanceManager) validCreateHostLifecycle(kit *rest.Kit, objID string, instanceData mapstr.MapStr) error {
+	if objID != common.BKInnerObjIDHost {
+		return nil
+	}
+
+	state, err := getHostLifecycleState(instanceData)
+	if err != nil {
+		blog.Errorf("host lifecycle state %#v is invalid, rid: %s", instanceData[common.BKHostLifecycleStateField],
+			kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKHostLifecycleStateField)
+	}
+
+	if len(state) == 0 {
+		instanceData[common.BKHostLifecycleStateField] = m.hostLifecycle.InitialState()
+		return nil
+	}
+
+	if rawErr := m.hostLifecycle.ValidateState(state); rawErr.ErrCode != 0 {
+		blog.Errorf("host lifecycle state %s is not defined, rid: %s", state, kit.Rid)
+		return rawErr.ToCCError(kit.CCError)
+	}
+
+	return nil
+}
+
+// validEnumCascadeAttrs validate the cascade enum attributes whose values or parents' values are changed, the value
+// must be one of the options of the parent's value in the instance data after the change.
+func (m *instanceManager) validEnumCascadeAttrs(kit *rest.Kit, changedData, instanceData mapstr.MapStr,
+	valid *validator) error {
+
+	for _, property := range valid.propertySlice {
+		if property.PropertyType != common.FieldTypeEnumCascade {
+			continue
+		}
+
+		option, err := metadata.ParseEnumCascadeOption(property.Option)
+		if err != nil {
+			blog.Errorf("parse attribute %s enum cascade option failed, err: %v, rid: %s", property.PropertyID, err,
+				kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, property.PropertyID)
+		}
+
+		if !changedData.Exists(property.PropertyID) && !changedData.Exists(option.Parent) {
+			continue
+		}
+
+		if rawErr := property.ValidateEnumCascade(kit.Ctx, instanceData); rawErr.ErrCode != 0 {
+			blog.Errorf("attribute %s value %v is invalid with its parent %s value %v, rid: %s", property.PropertyID,
+				instanceData[property.PropertyID], option.Parent, instanceData[option.Parent], kit.Rid)
+			return rawErr.ToCCError(kit.CCError)
+		}
+	}
+
+	return nil
+}
+
+// validUpdateHostLifecycle checks if the host's lifecycle state change is allowed by the host lifecycle, and the
+// updated host has all the attributes that the transition requires.
+func (m *instanceManager) validUpdateHostLifecycle(kit *rest.Kit, objID string, updateData,
+	instanceData mapstr.MapStr) error {
+
+	if objID != common.BKInnerObjIDHost || !updateData.Exists(common.BKHostLifecycleStateField) {
+		return nil
+	}
+
+	to, err := getHostLifecycleState(updateData)
+	if err != nil || len(to) == 0 {
+		blog.Errorf("host lifecycle state %#v is invalid, rid: %s", updateData[common.BKHostLifecycleStateField],
+			kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKHostLifecycleStateField)
+	}
+
+	from, err := getHostLifecycleState(instanceData)
+	if err != nil {
+		blog.Errorf("host lifecycle state %#v is invalid, rid: %s", instanceData[common.BKHostLifecycleStateField],
+			kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKHostLifecycleStateField)
+	}
+
+	host := make(mapstr.MapStr, len(instanceData)+len(updateData))
+	host.Merge(instanceData)
+	host.Merge(updateData)
+
+	if rawErr := m.hostLifecycle.ValidateTransition(from, to, host); rawErr.ErrCode != 0 {
+		blog.Errorf("host %v lifecycle state can not change from %s to %s, rid: %s",
+			instanceData[common.BKHostIDField], from, to, kit.Rid)
+		return rawErr.ToCCError(kit.CCError)
+	}
+
+	return nil
+}
+
+// validUpdateOwnedAttrs validate that the changed attributes owned by the external sources are only changed by the
+// sources, the writes from the other actors are rejected or only flagged in the log according to the owner policy.
+func (m *instanceManager) validUpdateOwnedAttrs(kit *rest.Kit, objID string, updateData, instanceData mapstr.MapStr,
+	valid *validator) error {
+
+	if kit.User == common.CCSystemOperatorUserName {
+		return nil
+	}
+
+	appCode := kit.Header.Get(common.BKHTTPRequestAppCode)
+	for key, val := range updateData {
+		property, exists := valid.properties[key]
+		if !exists || property.IsOwnedBy(appCode, kit.User) {
+			continue
+		}
+
+		if oldVal, exists := instanceData[key]; exists && fmt.Sprint(oldVal) == fmt.Sprint(val) {
+			continue
+		}
+
+		if property.OwnerPolicy == metadata.AttributeOwnerPolicyFlag {
+			blog.Warnf("%s attribute %s owned by %s is changed by user %s app %s, instance: %v, value: %v, rid: %s",
+				objID, key, property.OwnerSource, kit.User, appCode, instanceData[common.GetInstIDField(objID)],
+				val, kit.Rid)
+			continue
+		}
+
+		blog.Errorf("user %s app %s can not change %s attribute %s owned by %s, rid: %s", kit.User, appCode, objID,
+			key, property.OwnerSource, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCoreServiceAttrOwnedBySource, key, property.OwnerSource)
+	}
+
+	return nil
+}
+
+// validUpdateDelegatedAttrs validate that the changed attributes whose groups are delegated to some roles are only
+// changed by the users of these roles, the unchanged attributes are skipped since the whole form is updated by ui.
+func (m *instanceManager) validUpdateDelegatedAttrs(kit *rest.Kit, updateData, instanceData mapstr.MapStr,
+	valid *validator) error {
+
+	if kit.User == common.CCSystemOperatorUserName || len(valid.groupDelegations) == 0 {
+		return nil
+	}
+
+	groupAttrs := make(map[string][]string)
+	for key, val := range updateData {
+		property, exists := valid.properties[key]
+		if !exists || len(valid.groupDelegations[property.PropertyGroup]) == 0 {
+			continue
+		}
+
+		if oldVal, exists := instanceData[key]; exists && fmt.Sprint(oldVal) == fmt.Sprint(val) {
+			continue
+		}
+		groupAttrs[property.PropertyGroup] = append(groupAttrs[property.PropertyGroup], key)
+	}
+
+	if len(groupAttrs) == 0 {
+		return nil
+	}
+
+	for groupID, attrs := range groupAttrs {
+		roles := make([]string, 0)
+		editable := false
+		for _, delegation := range valid.groupDelegations[groupID] {
+			roles = append(roles, delegation.Role)
+			if util.InStrArr(delegation.Users, kit.User) {
+				editable = true
+				break
+			}
+		}
+
+		if editable {
+			continue
+		}
+
+		blog.Errorf("user %s can not edit attributes %v of the group %s delegated to %v, rid: %s", kit.User, attrs,
+			groupID, roles, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCoreServiceAttrGroupDelegated, strings.Join(attrs, ","),
+			strings.Join(roles, ","))
+	}
+
+	return nil
+}
+
+// getHostLifecycleState returns the host's lifecycle state, returns empty string if it is not set.
+func getHostLifecycleState(host mapstr.MapStr) (string, error) {
+	value, exists := host[common.BKHostLifecycleStateField]
+	if !exists || value == nil {
+		return "", nil
+	}
+
+	state, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("host lifecycle state %#v is not a string", value)
+	}
+
+	return strings.TrimSpace(state), nil
+}
+
 func (m *instanceManager) changeStringToTime(valData mapstr.MapStr, properties []metadata.Attribute) error {
 	for _, field := range properties {
 		if field.PropertyType != common.FieldTypeTime {
@@ -0,0 +1,81 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package instances
+
+import (
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// validInstNameCollation checks that no other instance has a name equal to the instance's name under the name
+// collation policy, in the scope of each unique rule that contains the name field. the exactly duplicated names are
+// rejected by the unique indexes, so nothing is checked if the policy is exact. instID is the id of the updated
+// instance which is excluded from the check, 0 for the created instance.
+func (m *instanceManager) validInstNameCollation(kit *rest.Kit, objID string, data mapstr.MapStr, valid *validator,
+	instID int64) error {
+
+	if m.nameCollation.IsExact() {
+		return nil
+	}
+
+	nameField := metadata.GetInstNameFieldName(objID)
+	name, ok := data[nameField].(string)
+	if !ok || len(name) == 0 {
+		return nil
+	}
+
+	uniqueOpts, err := valid.getValidUniqueOptions(kit, data, m)
+	if err != nil {
+		blog.Errorf("get unique options failed, err: %v, data: %#v, rid: %s", err, data, kit.Rid)
+		return err
+	}
+
+	for _, opt := range uniqueOpts {
+		if !util.InStrArr(opt.UniqueKeys, nameField) {
+			continue
+		}
+
+		cond := opt.Condition.ToMapStr()
+		cond[nameField] = mapstr.MapStr{common.BKDBLIKE: m.nameCollation.MatchPattern(name)}
+		if instID > 0 {
+			cond[common.GetInstIDField(objID)] = mapstr.MapStr{common.BKDBNE: instID}
+		}
+		cond = util.SetQueryOwner(cond, kit.SupplierAccount)
+
+		cnt, exists, err := m.instCnt(kit, objID, cond)
+		if err != nil {
+			blog.Errorf("count instances with collated name failed, err: %v, cond: %#v, rid: %s", err, cond, kit.Rid)
+			return kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+		}
+
+		if exists {
+			blog.Errorf("instance name %s duplicates with %d instances under the name collation policy %+v, "+
+				"unique keys: %v, rid: %s", name, cnt, *m.nameCollation, opt.UniqueKeys, kit.Rid)
+			lang := valid.language.CreateDefaultCCLanguageIf(util.GetLanguage(kit.Header))
+			propertyNames := make([]string, len(opt.UniqueKeys))
+			for idx, key := range opt.UniqueKeys {
+				propertyNames[idx] = util.FirstNotEmptyString(lang.Language(objID+"_property_"+key),
+					valid.properties[key].PropertyName, key)
+			}
+			return valid.errIf.Errorf(common.CCErrCommDuplicateItem, strings.Join(propertyNames, ","))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.,
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the ",License",); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an ",AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package instances
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// validModelWritable validates that the instances of the model can be written in the model's lifecycle state, the
+// instances of the read only or hidden models can only be read.
+func (m *instanceManager) validModelWritable(kit *rest.Kit, objID string) error {
+	cond := util.SetQueryOwner(mapstr.MapStr{common.BKObjIDField: objID}, kit.SupplierAccount)
+	models := make([]metadata.Object, 0)
+	err := mongodb.Client().Table(common.BKTableNameObjDes).Find(cond).
+		Fields(common.BKObjIDField, metadata.SchemaFieldLifecycle).All(kit.Ctx, &models)
+	if err != nil {
+		blog.Errorf("get model %s lifecycle failed, err: %v, rid: %s", objID, err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	if len(models) == 0 {
+		return nil
+	}
+
+	state := models[0].Lifecycle.EffectiveState(time.Now())
+	if !metadata.IsSchemaWritable(state) {
+		blog.Errorf("model %s is %s, can not write its instances, rid: %s", objID, state, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCoreServiceSchemaNotWritable, objID, state)
+	}
+	return nil
+}
+
+// validAttrsWritable validates that the attributes in the data can be written in their lifecycle states. when the
+// instance is created, the attributes with empty values are not regarded as written, since the upper services may
+// fill the empty values of all the attributes.
+func (valid *validator) validAttrsWritable(kit *rest.Kit, data mapstr.MapStr, isCreate bool) error {
+	now := time.Now()
+	for key, val := range data {
+		attr, exists := valid.properties[key]
+		if !exists || attr.Lifecycle == nil {
+			continue
+		}
+
+		if isCreate && (val == nil || val == "") {
+			continue
+		}
+
+		state := attr.Lifecycle.EffectiveState(now)
+		if !metadata.IsSchemaWritable(state) {
+			blog.Errorf("attribute %s of %s is %s, can not be written, rid: %s", key, valid.objID, state, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCoreServiceSchemaNotWritable, key, state)
+		}
+	}
+	return nil
+}
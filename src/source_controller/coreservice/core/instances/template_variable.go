@@ -0,0 +1,39 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package instances
+
+import (
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// deleteTemplateVariables deletes the template variables bound on the deleted modules or sets.
+func (m *instanceManager) deleteTemplateVariables(kit *rest.Kit, objID string, instIDs []int64) error {
+	if len(instIDs) == 0 || (objID != common.BKInnerObjIDModule && objID != common.BKInnerObjIDSet) {
+		return nil
+	}
+
+	cond := mapstr.MapStr{
+		common.BKObjIDField:  objID,
+		common.BKInstIDField: mapstr.MapStr{common.BKDBIN: instIDs},
+	}
+	if err := mongodb.Client().Table(common.BKTableNameTemplateVariable).Delete(kit.Ctx, cond); err != nil {
+		blog.Errorf("delete template variables of %s instances %v failed, err: %v, rid: %s", objID, instIDs, err,
+			kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommDBDeleteFailed)
+	}
+	return nil
+}
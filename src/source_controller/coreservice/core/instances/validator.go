@@ -18,7 +18,9 @@ import (
 	"configcenter/src/common/errors"
 	"configcenter/src/common/http/rest"
 	"configcenter/src/common/language"
+	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/mongodb"
 )
 
 type validator struct {
@@ -29,9 +31,11 @@ type validator struct {
 	require       map[string]bool
 	requireFields []string
 	uniqueAttrs   []metadata.ObjectUnique
-	dependent     OperationDependences
-	objID         string
-	language      language.CCLanguageIf
+	// groupDelegations is the mapping of the attribute group id to its delegations, empty if no group is delegated
+	groupDelegations map[string][]metadata.AttrGroupDelegation
+	dependent        OperationDependences
+	objID            string
+	language         language.CCLanguageIf
 }
 
 // NewValidator TODO
@@ -75,6 +79,11 @@ func NewValidator(kit *rest.Kit, dependent OperationDependences, objID string, b
 	}
 	valid.uniqueAttrs = uniqueAttrs
 
+	valid.groupDelegations, err = getGroupDelegations(kit, objID)
+	if err != nil {
+		return nil, err
+	}
+
 	return valid, nil
 }
 
@@ -98,6 +107,11 @@ func NewValidators(kit *rest.Kit, dependent OperationDependences, objID string,
 		return nil, err
 	}
 
+	groupDelegations, err := getGroupDelegations(kit, objID)
+	if err != nil {
+		return nil, err
+	}
+
 	attrMap := make(map[int64][]metadata.Attribute)
 	for _, attr := range attributes {
 		attrMap[attr.BizID] = append(attrMap[attr.BizID], attr)
@@ -110,16 +124,17 @@ func NewValidators(kit *rest.Kit, dependent OperationDependences, objID string,
 		}
 
 		validator := &validator{
-			properties:    make(map[string]metadata.Attribute),
-			idToProperty:  make(map[int64]metadata.Attribute),
-			propertySlice: make([]metadata.Attribute, 0),
-			require:       make(map[string]bool),
-			requireFields: make([]string, 0),
-			uniqueAttrs:   uniqueAttrs,
-			objID:         objID,
-			errIf:         kit.CCError,
-			dependent:     dependent,
-			language:      language,
+			properties:       make(map[string]metadata.Attribute),
+			idToProperty:     make(map[int64]metadata.Attribute),
+			propertySlice:    make([]metadata.Attribute, 0),
+			require:          make(map[string]bool),
+			requireFields:    make([]string, 0),
+			uniqueAttrs:      uniqueAttrs,
+			groupDelegations: groupDelegations,
+			objID:            objID,
+			errIf:            kit.CCError,
+			dependent:        dependent,
+			language:         language,
 		}
 
 		// the instances in biz has both biz attributes and global attributes that has no biz id
@@ -142,3 +157,20 @@ func NewValidators(kit *rest.Kit, dependent OperationDependences, objID string,
 
 	return bizValidatorMap, nil
 }
+
+// getGroupDelegations returns the mapping of the attribute group id to its delegations of the object.
+func getGroupDelegations(kit *rest.Kit, objID string) (map[string][]metadata.AttrGroupDelegation, error) {
+	filter := mapstr.MapStr{common.BKObjIDField: objID}
+	delegations := make([]metadata.AttrGroupDelegation, 0)
+	if err := mongodb.Client().Table(common.BKTableNameAttrGroupDelegation).Find(filter).All(kit.Ctx,
+		&delegations); err != nil {
+		blog.Errorf("search attribute group delegations failed, filter: %v, err: %v, rid: %s", filter, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	groupDelegations := make(map[string][]metadata.AttrGroupDelegation)
+	for _, delegation := range delegations {
+		groupDelegations[delegation.GroupID] = append(groupDelegations[delegation.GroupID], delegation)
+	}
+	return groupDelegations, nil
+}
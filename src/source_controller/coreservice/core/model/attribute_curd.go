@@ -219,7 +219,8 @@ func (m *modelAttribute) checkAttributeValidity(kit *rest.Kit, attribute metadat
 	if attribute.PropertyType != "" {
 		switch attribute.PropertyType {
 		case common.FieldTypeSingleChar, common.FieldTypeLongChar, common.FieldTypeInt, common.FieldTypeFloat, common.FieldTypeEnum,
-			common.FieldTypeDate, common.FieldTypeTime, common.FieldTypeUser, common.FieldTypeOrganization, common.FieldTypeTimeZone, common.FieldTypeBool, common.FieldTypeList:
+			common.FieldTypeDate, common.FieldTypeTime, common.FieldTypeUser, common.FieldTypeOrganization, common.FieldTypeTimeZone, common.FieldTypeBool, common.FieldTypeList,
+			common.FieldTypeEnumCascade:
 		default:
 			return kit.CCError.Errorf(common.CCErrCommParamsIsInvalid, metadata.AttributeFieldPropertyType)
 		}
@@ -299,6 +300,10 @@ func (m *modelAttribute) delete(kit *rest.Kit, cond universalsql.Condition) (cnt
 		objIDArrMap[attr.ObjectID] = append(objIDArrMap[attr.ObjectID], attr.ID)
 	}
 
+	if err := m.checkEnumCascadeParentReferred(kit, resultAttrs); err != nil {
+		return 0, err
+	}
+
 	if err := m.cleanAttributeFieldInInstances(kit.Ctx, kit.SupplierAccount, resultAttrs); err != nil {
 		blog.Errorf("delete object attributes with cond: %v, but delete these attribute in instance failed, "+
 			"err: %v, rid: %s", condMap, err, kit.Rid)
@@ -658,6 +663,12 @@ func (m *modelAttribute) saveCheck(kit *rest.Kit, attribute metadata.Attribute)
 		return err
 	}
 
+	if attribute.PropertyType == common.FieldTypeEnumCascade {
+		if err := m.checkEnumCascadeParent(kit, attribute.ObjectID, attribute.PropertyID, attribute.Option); err != nil {
+			return err
+		}
+	}
+
 	// check name duplicate
 	if err := m.checkUnique(kit, true, attribute.ObjectID, attribute.PropertyID, attribute.PropertyName, attribute.BizID); err != nil {
 		blog.ErrorJSON("save attribute check unique err:%s, input:%s, rid:%s", err.Error(), attribute, kit.Rid)
@@ -689,7 +700,7 @@ func (m *modelAttribute) checkUpdate(kit *rest.Kit, data mapstr.MapStr, cond uni
 		}
 	}
 
-	// 预定义字段，只能更新分组、分组内排序、名称、单位、提示语和option
+	// 预定义字段，只能更新分组、分组内排序、名称、单位、提示语、option、是否锁定、数据来源、生命周期和多语言名称
 	if hasIsPreProperty {
 		_ = data.ForEach(func(key string, val interface{}) error {
 			if key != metadata.AttributeFieldPropertyGroup &&
@@ -697,7 +708,12 @@ func (m *modelAttribute) checkUpdate(kit *rest.Kit, data mapstr.MapStr, cond uni
 				key != metadata.AttributeFieldPropertyName &&
 				key != metadata.AttributeFieldUnit &&
 				key != metadata.AttributeFieldPlaceHolder &&
-				key != metadata.AttributeFieldOption {
+				key != metadata.AttributeFieldOption &&
+				key != metadata.AttributeFieldIsLocked &&
+				key != metadata.AttributeFieldOwnerSource &&
+				key != metadata.AttributeFieldOwnerPolicy &&
+				key != metadata.SchemaFieldLifecycle &&
+				key != metadata.AttributeFieldI18n {
 				data.Remove(key)
 			}
 			return nil
@@ -716,6 +732,14 @@ func (m *modelAttribute) checkUpdate(kit *rest.Kit, data mapstr.MapStr, cond uni
 			blog.ErrorJSON("valid property option failed, err: %s, data: %s, rid:%s", err, data, kit.Ctx)
 			return err
 		}
+		if propertyType == common.FieldTypeEnumCascade {
+			for _, dbAttribute := range dbAttributeArr {
+				err := m.checkEnumCascadeParent(kit, dbAttribute.ObjectID, dbAttribute.PropertyID, option)
+				if err != nil {
+					return err
+				}
+			}
+		}
 	}
 
 	// 删除不可更新字段， 避免由于传入数据，修改字段
@@ -42,3 +42,108 @@ func (m *modelAttribute) isExists(kit *rest.Kit, objID, propertyID string, model
 	}
 	return oneAttribute, !mongodb.Client().IsNotFoundError(err), nil
 }
+
+// checkEnumCascadeParent check that the cascade enum attribute's parent is an enum or cascade enum attribute of the
+// same model, and the cascade chain does not go back to the attribute itself.
+func (m *modelAttribute) checkEnumCascadeParent(kit *rest.Kit, objID, propertyID string, option interface{}) error {
+	cascadeOption, err := metadata.ParseEnumCascadeOption(option)
+	if err != nil {
+		blog.Errorf("parse enum cascade option failed, option: %#v, err: %v, rid: %s", option, err, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, metadata.AttributeFieldOption)
+	}
+
+	attrs := make([]metadata.Attribute, 0)
+	filter := map[string]interface{}{
+		common.BKObjIDField: objID,
+		metadata.AttributeFieldPropertyType: map[string]interface{}{
+			common.BKDBIN: []string{common.FieldTypeEnum, common.FieldTypeEnumCascade},
+		},
+	}
+	filter = util.SetQueryOwner(filter, kit.SupplierAccount)
+	if err := mongodb.Client().Table(common.BKTableNameObjAttDes).Find(filter).All(kit.Ctx, &attrs); err != nil {
+		blog.Errorf("find enum attributes failed, filter: %#v, err: %v, rid: %s", filter, err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	attrMap := make(map[string]metadata.Attribute, len(attrs))
+	for _, attr := range attrs {
+		attrMap[attr.PropertyID] = attr
+	}
+
+	visited := map[string]struct{}{propertyID: {}}
+	for parent := cascadeOption.Parent; ; {
+		if _, exists := visited[parent]; exists {
+			blog.Errorf("enum cascade attribute %s parent %s forms a cycle, rid: %s", propertyID, parent, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "option parent")
+		}
+		visited[parent] = struct{}{}
+
+		parentAttr, exists := attrMap[parent]
+		if !exists {
+			blog.Errorf("enum cascade attribute %s parent %s is not an enum attribute of %s, rid: %s", propertyID,
+				parent, objID, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "option parent")
+		}
+
+		if parentAttr.PropertyType == common.FieldTypeEnum {
+			return nil
+		}
+
+		parentOption, err := metadata.ParseEnumCascadeOption(parentAttr.Option)
+		if err != nil {
+			blog.Errorf("parse attribute %s enum cascade option failed, err: %v, rid: %s", parent, err, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "option parent")
+		}
+		parent = parentOption.Parent
+	}
+}
+
+// checkEnumCascadeParentReferred check that the attributes to be deleted are not the parents of the remaining cascade
+// enum attributes, the cascade enum attributes can not be edited without their parents.
+func (m *modelAttribute) checkEnumCascadeParentReferred(kit *rest.Kit, attrs []metadata.Attribute) error {
+	objPropertyIDs := make(map[string]map[string]struct{})
+	for _, attr := range attrs {
+		if _, exists := objPropertyIDs[attr.ObjectID]; !exists {
+			objPropertyIDs[attr.ObjectID] = make(map[string]struct{})
+		}
+		objPropertyIDs[attr.ObjectID][attr.PropertyID] = struct{}{}
+	}
+
+	objIDs := make([]string, 0, len(objPropertyIDs))
+	for objID := range objPropertyIDs {
+		objIDs = append(objIDs, objID)
+	}
+
+	cascadeAttrs := make([]metadata.Attribute, 0)
+	filter := map[string]interface{}{
+		common.BKObjIDField:                 map[string]interface{}{common.BKDBIN: objIDs},
+		metadata.AttributeFieldPropertyType: common.FieldTypeEnumCascade,
+	}
+	filter = util.SetQueryOwner(filter, kit.SupplierAccount)
+	if err := mongodb.Client().Table(common.BKTableNameObjAttDes).Find(filter).All(kit.Ctx, &cascadeAttrs); err != nil {
+		blog.Errorf("find enum cascade attributes failed, filter: %#v, err: %v, rid: %s", filter, err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	for _, attr := range cascadeAttrs {
+		propertyIDs := objPropertyIDs[attr.ObjectID]
+		if _, deleted := propertyIDs[attr.PropertyID]; deleted {
+			continue
+		}
+
+		option, err := metadata.ParseEnumCascadeOption(attr.Option)
+		if err != nil {
+			blog.Warnf("parse attribute %s enum cascade option failed, err: %v, rid: %s", attr.PropertyID, err, kit.Rid)
+			continue
+		}
+
+		if _, deleted := propertyIDs[option.Parent]; deleted {
+			blog.Errorf("attribute %s is the parent of enum cascade attribute %s, rid: %s", option.Parent,
+				attr.PropertyID, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCoreServiceEnumCascadeParentReferred, option.Parent,
+				attr.PropertyID)
+		}
+	}
+
+	return nil
+}
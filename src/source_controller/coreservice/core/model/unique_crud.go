@@ -422,7 +422,7 @@ func getBasicDataType(propertyType string) (interface{}, error) {
 		return "", nil
 	case common.FieldTypeInt:
 		return 0, nil
-	case common.FieldTypeEnum:
+	case common.FieldTypeEnum, common.FieldTypeEnumCascade:
 		return "", nil
 	case common.FieldTypeDate:
 		return "", nil
@@ -146,6 +146,12 @@ func (p *processOperation) CreateServiceInstance(kit *rest.Kit, instance *metada
 			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKServiceTemplateIDField)
 		}
 
+		listProcTplResult.Info, ccErr = p.resolveModuleProcessTemplates(kit, module.BizID, module.ModuleID,
+			listProcTplResult.Info)
+		if ccErr != nil {
+			return nil, ccErr
+		}
+
 		// get host data for bind IP if needed
 		host := metadata.HostMapStr{}
 		filter := map[string]interface{}{common.BKHostIDField: instance.HostID}
@@ -578,10 +584,12 @@ func (p *processOperation) DeleteServiceInstance(kit *rest.Kit, serviceInstanceI
 	return nil
 }
 
-// generateServiceInstanceName get service instance's name, format: `IP + first process name + first process port`
+// getServiceInstanceNameSource get the source data of the service instance's name, including the instance, its
+// host's inner ip and its first process, the process is nil if the instance has no process.
 // 可能应用场景：1. 查询服务实例时组装名称；2. 更新进程信息时根据组装名称直接更新到 `name` 字段
 // issue: https://github.com/Tencent/bk-cmdb/issues/2485
-func (p *processOperation) generateServiceInstanceName(kit *rest.Kit, instanceID int64) (string, errors.CCErrorCoder) {
+func (p *processOperation) getServiceInstanceNameSource(kit *rest.Kit, instanceID int64) (*metadata.ServiceInstance,
+	string, *metadata.Process, errors.CCErrorCoder) {
 
 	// get instance
 	instance := metadata.ServiceInstance{}
@@ -591,9 +599,9 @@ func (p *processOperation) generateServiceInstanceName(kit *rest.Kit, instanceID
 	if err := mongodb.Client().Table(common.BKTableNameServiceInstance).Find(instanceFilter).One(kit.Ctx, &instance); err != nil {
 		blog.Errorf("GetServiceInstanceName failed, mongodb failed, table: %s, filter: %+v, err: %+v, rid: %s", common.BKTableNameServiceInstance, instanceFilter, err, kit.Rid)
 		if mongodb.Client().IsNotFoundError(err) {
-			return "", kit.CCError.CCErrorf(common.CCErrCommNotFound)
+			return nil, "", nil, kit.CCError.CCErrorf(common.CCErrCommNotFound)
 		}
-		return "", kit.CCError.CCErrorf(common.CCErrCommDBSelectFailed)
+		return nil, "", nil, kit.CCError.CCErrorf(common.CCErrCommDBSelectFailed)
 	}
 
 	// get host inner ip
@@ -605,11 +613,11 @@ func (p *processOperation) generateServiceInstanceName(kit *rest.Kit, instanceID
 	if err := mongodb.Client().Table(common.BKTableNameBaseHost).Find(hostFilter).One(kit.Ctx, &host); err != nil {
 		blog.Errorf("GetServiceInstanceName failed, mongodb failed, table: %s, filter: %+v, err: %+v, rid: %s", common.BKTableNameBaseHost, hostFilter, err, kit.Rid)
 		if mongodb.Client().IsNotFoundError(err) {
-			return "", kit.CCError.CCErrorf(common.CCErrCommNotFound)
+			return nil, "", nil, kit.CCError.CCErrorf(common.CCErrCommNotFound)
 		}
-		return "", kit.CCError.CCErrorf(common.CCErrCommDBSelectFailed)
+		return nil, "", nil, kit.CCError.CCErrorf(common.CCErrCommDBSelectFailed)
 	}
-	instanceName := host[common.BKHostInnerIPField].(string)
+	hostIP := util.GetStrByInterface(host[common.BKHostInnerIPField])
 
 	// get first process instance relation
 	relation := metadata.ProcessInstanceRelation{}
@@ -621,74 +629,56 @@ func (p *processOperation) generateServiceInstanceName(kit *rest.Kit, instanceID
 		// relation not found means no process in service instance, service instance's name will only contains ip in that case
 		if !mongodb.Client().IsNotFoundError(err) {
 			blog.Errorf("GetServiceInstanceName failed, mongodb failed, table: %s, filter: %+v, err: %+v, rid: %s", common.BKTableNameProcessInstanceRelation, relationFilter, err, kit.Rid)
-			return "", kit.CCError.CCErrorf(common.CCErrCommDBSelectFailed)
+			return nil, "", nil, kit.CCError.CCErrorf(common.CCErrCommDBSelectFailed)
 		}
 	}
 
-	if relation.ProcessID != 0 {
-		// get process instance
-		process := metadata.Process{}
-		processFilter := map[string]interface{}{
-			common.BKProcIDField: relation.ProcessID,
-		}
-		if err := mongodb.Client().Table(common.BKTableNameBaseProcess).Find(processFilter).One(kit.Ctx, &process); err != nil {
-			blog.Errorf("GetServiceInstanceName failed, mongodb failed, table: %s, filter: %+v, err: %+v, rid: %s", common.BKTableNameBaseProcess, processFilter, err, kit.Rid)
-			if mongodb.Client().IsNotFoundError(err) {
-				return "", kit.CCError.CCErrorf(common.CCErrCommNotFound)
-			}
-			return "", kit.CCError.CCErrorf(common.CCErrCommDBSelectFailed)
-		}
+	if relation.ProcessID == 0 {
+		return &instance, hostIP, nil, nil
+	}
 
-		if process.ProcessName != nil && len(*process.ProcessName) > 0 {
-			instanceName += fmt.Sprintf("_%s", *process.ProcessName)
-		}
-		for _, bindInfo := range process.BindInfo {
-			if bindInfo.Std != nil && bindInfo.Std.Port != nil {
-				instanceName += fmt.Sprintf("_%s", *bindInfo.Std.Port)
-				break
-			}
+	// get process instance
+	process := metadata.Process{}
+	processFilter := map[string]interface{}{
+		common.BKProcIDField: relation.ProcessID,
+	}
+	if err := mongodb.Client().Table(common.BKTableNameBaseProcess).Find(processFilter).One(kit.Ctx, &process); err != nil {
+		blog.Errorf("GetServiceInstanceName failed, mongodb failed, table: %s, filter: %+v, err: %+v, rid: %s", common.BKTableNameBaseProcess, processFilter, err, kit.Rid)
+		if mongodb.Client().IsNotFoundError(err) {
+			return nil, "", nil, kit.CCError.CCErrorf(common.CCErrCommNotFound)
 		}
-
+		return nil, "", nil, kit.CCError.CCErrorf(common.CCErrCommDBSelectFailed)
 	}
-	return instanceName, nil
+
+	return &instance, hostIP, &process, nil
 }
 
 // ConstructServiceInstanceName construct service instance name
-// if no service instance name is defined, use the following rule to construct service name:
+// if the business has a service instance naming template, the name is rendered by the template, otherwise use the
+// following rule to construct service name:
 // hostInnerIP(if exist) + firstProcessName(if exist) + firstProcessPort(if exist)
 func (p *processOperation) ConstructServiceInstanceName(kit *rest.Kit, instanceID int64, host map[string]interface{},
 	process *metadata.Process) (string, errors.CCErrorCoder) {
 
-	serviceInstanceName := util.GetStrByInterface(host[common.BKHostInnerIPField])
-
-	if process != nil {
-		if process.ProcessName != nil && len(*process.ProcessName) > 0 {
-			serviceInstanceName += fmt.Sprintf("_%s", *process.ProcessName)
-		}
-		for _, bindInfo := range process.BindInfo {
-			if bindInfo.Std != nil && bindInfo.Std.Port != nil {
-				serviceInstanceName += fmt.Sprintf("_%s", *bindInfo.Std.Port)
-				break
-			}
-		}
-	}
-
-	if err := p.updateServiceInstanceName(kit, instanceID, serviceInstanceName); err != nil {
+	instance, err := p.GetServiceInstance(kit, instanceID)
+	if err != nil {
 		return "", err
 	}
 
-	return serviceInstanceName, nil
+	hostIP := util.GetStrByInterface(host[common.BKHostInnerIPField])
+	return p.saveServiceInstanceName(kit, instance, hostIP, process)
 }
 
 // ReconstructServiceInstanceName do reconstruct service instance name after process name or process port changed
 func (p *processOperation) ReconstructServiceInstanceName(kit *rest.Kit, instanceID int64) errors.CCErrorCoder {
-	name, err := p.generateServiceInstanceName(kit, instanceID)
+	instance, hostIP, process, err := p.getServiceInstanceNameSource(kit, instanceID)
 	if err != nil {
 		blog.Errorf("ReconstructServiceInstanceName failed, generate instance name failed, err: %s, rid: %s", err.Error(), kit.Rid)
 		return err
 	}
 
-	return p.updateServiceInstanceName(kit, instanceID, name)
+	_, err = p.saveServiceInstanceName(kit, instance, hostIP, process)
+	return err
 }
 
 func (p *processOperation) updateServiceInstanceName(kit *rest.Kit, instanceID int64, serviceInstanceName string) errors.CCErrorCoder {
@@ -860,6 +850,12 @@ func (p *processOperation) AutoCreateServiceInstanceModuleHost(kit *rest.Kit, ho
 			continue
 		}
 
+		processTemplates, ccErr = p.resolveModuleProcessTemplates(kit, module.BizID, module.ModuleID,
+			processTemplates)
+		if ccErr != nil {
+			return ccErr
+		}
+
 		for _, host := range hosts {
 			hostID, err := util.GetInt64ByInterface(host[common.BKHostIDField])
 			if nil != err {
@@ -0,0 +1,183 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"fmt"
+	"sync"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// srvInstNameLock makes sure that the service instance names rendered by the naming templates are not duplicated by
+// the concurrent creations, e.g. the service instances created in batch.
+var srvInstNameLock sync.Mutex
+
+// saveServiceInstanceName builds the service instance's name and saves it, the name is rendered by the business's
+// naming template if it is set, otherwise the name is `host ip + first process name + first process port`.
+func (p *processOperation) saveServiceInstanceName(kit *rest.Kit, instance *metadata.ServiceInstance, hostIP string,
+	process *metadata.Process) (string, errors.CCErrorCoder) {
+
+	values := metadata.SrvInstNameValues{HostIP: hostIP}
+	if process != nil {
+		if process.ProcessName != nil {
+			values.ProcessName = *process.ProcessName
+		}
+		for _, bindInfo := range process.BindInfo {
+			if bindInfo.Std != nil && bindInfo.Std.Port != nil {
+				values.Port = *bindInfo.Std.Port
+				break
+			}
+		}
+	}
+
+	template, err := p.getSrvInstNameTemplate(kit, instance.BizID)
+	if err != nil {
+		return "", err
+	}
+
+	if template == nil {
+		name := values.HostIP
+		if len(values.ProcessName) > 0 {
+			name += fmt.Sprintf("_%s", values.ProcessName)
+		}
+		if len(values.Port) > 0 {
+			name += fmt.Sprintf("_%s", values.Port)
+		}
+
+		if err := p.updateServiceInstanceName(kit, instance.ID, name); err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+
+	srvInstNameLock.Lock()
+	defer srvInstNameLock.Unlock()
+
+	name, err := p.renderServiceInstanceName(kit, template, instance, values)
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.updateServiceInstanceName(kit, instance.ID, name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// getSrvInstNameTemplate get the business's service instance naming template, returns nil if it is not set.
+func (p *processOperation) getSrvInstNameTemplate(kit *rest.Kit, bizID int64) (*metadata.SrvInstNameTemplate,
+	errors.CCErrorCoder) {
+
+	filter := mapstr.MapStr{common.BKAppIDField: bizID}
+	template := new(metadata.SrvInstNameTemplate)
+	err := mongodb.Client().Table(common.BKTableNameSrvInstNameTemplate).Find(filter).One(kit.Ctx, template)
+	if err != nil {
+		if mongodb.Client().IsNotFoundError(err) {
+			return nil, nil
+		}
+		blog.Errorf("get biz %d service instance name template failed, err: %v, rid: %s", bizID, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	return template, nil
+}
+
+// renderServiceInstanceName renders the service instance's name by the naming template, the index is the smallest
+// positive number that makes the name unique in the module. the duplicated name is not allowed if the template has
+// no index placeholder.
+func (p *processOperation) renderServiceInstanceName(kit *rest.Kit, template *metadata.SrvInstNameTemplate,
+	instance *metadata.ServiceInstance, values metadata.SrvInstNameValues) (string, errors.CCErrorCoder) {
+
+	module := make(mapstr.MapStr)
+	moduleFilter := mapstr.MapStr{common.BKModuleIDField: instance.ModuleID}
+	err := mongodb.Client().Table(common.BKTableNameBaseModule).Find(moduleFilter).Fields(common.BKModuleNameField).
+		One(kit.Ctx, &module)
+	if err != nil {
+		blog.Errorf("get module %d failed, err: %v, rid: %s", instance.ModuleID, err, kit.Rid)
+		return "", kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+	values.ModuleName = util.GetStrByInterface(module[common.BKModuleNameField])
+
+	// get the names used by the other service instances in the module to find the unique name.
+	instances := make([]metadata.ServiceInstance, 0)
+	instanceFilter := mapstr.MapStr{
+		common.BKModuleIDField: instance.ModuleID,
+		common.BKFieldID:       mapstr.MapStr{common.BKDBNE: instance.ID},
+	}
+	err = mongodb.Client().Table(common.BKTableNameServiceInstance).Find(instanceFilter).Fields(common.BKFieldName).
+		All(kit.Ctx, &instances)
+	if err != nil {
+		blog.Errorf("get module %d service instance names failed, err: %v, rid: %s", instance.ModuleID, err, kit.Rid)
+		return "", kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	usedNames := make(map[string]struct{}, len(instances))
+	for _, inst := range instances {
+		usedNames[inst.Name] = struct{}{}
+	}
+
+	name := template.Render(values, 1)
+	if !template.HasIndex() {
+		if _, exists := usedNames[name]; exists {
+			blog.Errorf("service instance name %s is duplicated in module %d, rid: %s", name, instance.ModuleID,
+				kit.Rid)
+			return "", kit.CCError.CCErrorf(common.CCErrCoreServiceSrvInstNameDuplicated, name)
+		}
+		return name, nil
+	}
+
+	for index := 1; index <= metadata.SrvInstNameMaxIndex; index++ {
+		name = template.Render(values, index)
+		if _, exists := usedNames[name]; !exists {
+			return name, nil
+		}
+	}
+
+	blog.Errorf("no unique service instance name is found in module %d, rid: %s", instance.ModuleID, kit.Rid)
+	return "", kit.CCError.CCErrorf(common.CCErrCoreServiceSrvInstNameDuplicated, name)
+}
+
+// RenderServiceInstanceNames renders the names of the service instances in the business again, with the business's
+// current naming template, or with the default rule if the business has no naming template. the service instances
+// are rendered in the order of their ids so that the indexes are stable.
+func (p *processOperation) RenderServiceInstanceNames(kit *rest.Kit, option *metadata.RenderSrvInstNameOption) (
+	int, errors.CCErrorCoder) {
+
+	instances := make([]metadata.ServiceInstance, 0)
+	filter := mapstr.MapStr{
+		common.BKAppIDField: option.BizID,
+		common.BKFieldID:    mapstr.MapStr{common.BKDBIN: option.ServiceInstanceIDs},
+	}
+	err := mongodb.Client().Table(common.BKTableNameServiceInstance).Find(filter).Fields(common.BKFieldID).
+		Sort(common.BKFieldID).All(kit.Ctx, &instances)
+	if err != nil {
+		blog.Errorf("get service instances failed, filter: %v, err: %v, rid: %s", filter, err, kit.Rid)
+		return 0, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	for _, instance := range instances {
+		if err := p.ReconstructServiceInstanceName(kit, instance.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(instances), nil
+}
@@ -0,0 +1,57 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// resolveModuleProcessTemplates replaces the template variable placeholders of the process templates with the
+// template variables bound on the module, all the referenced variables must be bound on the module.
+func (p *processOperation) resolveModuleProcessTemplates(kit *rest.Kit, bizID, moduleID int64,
+	templates []metadata.ProcessTemplate) ([]metadata.ProcessTemplate, errors.CCErrorCoder) {
+
+	filter := mapstr.MapStr{
+		common.BKAppIDField:  bizID,
+		common.BKObjIDField:  common.BKInnerObjIDModule,
+		common.BKInstIDField: moduleID,
+	}
+	variable := new(metadata.TemplateVariable)
+	err := mongodb.Client().Table(common.BKTableNameTemplateVariable).Find(filter).One(kit.Ctx, variable)
+	if err != nil && !mongodb.Client().IsNotFoundError(err) {
+		blog.Errorf("get template variables failed, filter: %v, err: %v, rid: %s", filter, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	resolved, missing, err := metadata.ResolveProcessTemplates(templates, variable.Variables)
+	if err != nil {
+		blog.Errorf("resolve process templates of module %d failed, err: %v, rid: %s", moduleID, err, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, err.Error())
+	}
+
+	if len(missing) > 0 {
+		blog.Errorf("template variables %v are not bound on module %d, rid: %s", missing, moduleID, kit.Rid)
+		return nil, kit.CCError.CCErrorf(common.CCErrCommTemplateVariableNotBound, strings.Join(missing, ","),
+			common.BKInnerObjIDModule, moduleID)
+	}
+
+	return resolved, nil
+}
@@ -102,6 +102,12 @@ func (p *setTemplateOperation) validateSetTemplateAttrs(kit *rest.Kit, bizID int
 			return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "attributes")
 		}
 
+		// the locked attributes are managed by the platform, the templates can not override them
+		if attribute.IsLocked {
+			blog.Errorf("set attribute %s is locked, rid: %s", attribute.PropertyID, kit.Rid)
+			return kit.CCError.CCErrorf(common.CCErrCoreServiceTemplateAttrLocked, attribute.PropertyID)
+		}
+
 		rawError := attribute.Validate(kit.Ctx, attr.PropertyValue, common.BKPropertyValueField)
 		if rawError.ErrCode != 0 {
 			ccErr := rawError.ToCCError(kit.CCError)
@@ -315,6 +315,27 @@ func (s *coreService) CountInstanceAssociations(ctx *rest.Contexts) {
 	ctx.RespEntity(result)
 }
 
+// ScanAsstMappingViolation scans the instance associations that violate the association mapping.
+func (s *coreService) ScanAsstMappingViolation(ctx *rest.Contexts) {
+	opt := new(metadata.ScanAsstMappingViolationOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	result, err := s.core.AssociationOperation().ScanAsstMappingViolation(ctx.Kit, opt)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	ctx.RespEntity(result)
+}
+
 // DeleteInstanceAssociation TODO
 func (s *coreService) DeleteInstanceAssociation(ctx *rest.Contexts) {
 	inputData := metadata.InstAsstDeleteOption{}
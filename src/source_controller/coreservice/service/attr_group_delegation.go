@@ -0,0 +1,137 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strings"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// SaveAttrGroupDelegation creates or replaces the users of the role that the attribute group is delegated to.
+func (s *coreService) SaveAttrGroupDelegation(ctx *rest.Contexts) {
+	delegation := new(meta.AttrGroupDelegation)
+	if err := ctx.DecodeInto(delegation); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	delegation.Role = strings.TrimSpace(delegation.Role)
+	if rawErr := delegation.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	groupFilter := mapstr.MapStr{
+		common.BKObjIDField:           delegation.ObjID,
+		common.BKPropertyGroupIDField: delegation.GroupID,
+	}
+	count, err := mongodb.Client().Table(common.BKTableNamePropertyGroup).Find(groupFilter).Count(ctx.Kit.Ctx)
+	if err != nil {
+		blog.Errorf("count attribute group failed, filter: %v, err: %v, rid: %s", groupFilter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	if count == 0 {
+		blog.Errorf("attribute group %s of object %s not exists, rid: %s", delegation.GroupID, delegation.ObjID,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKPropertyGroupIDField))
+		return
+	}
+
+	delegation.Users = util.StrArrayUnique(delegation.Users)
+	delegation.Modifier = ctx.Kit.User
+	delegation.LastTime = time.Now().UTC()
+	delegation.SupplierAccount = ctx.Kit.SupplierAccount
+
+	filter := mapstr.MapStr{
+		common.BKObjIDField:           delegation.ObjID,
+		common.BKPropertyGroupIDField: delegation.GroupID,
+		"role":                        delegation.Role,
+	}
+	if err := mongodb.Client().Table(common.BKTableNameAttrGroupDelegation).Upsert(ctx.Kit.Ctx, filter,
+		delegation); err != nil {
+		blog.Errorf("save attribute group delegation %#v failed, err: %v, rid: %s", delegation, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBUpdateFailed))
+		return
+	}
+
+	ctx.RespEntity(delegation)
+}
+
+// SearchAttrGroupDelegation returns the attribute group delegations of the model.
+func (s *coreService) SearchAttrGroupDelegation(ctx *rest.Contexts) {
+	opt := new(meta.ListAttrGroupDelegationOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	filter := mapstr.MapStr{common.BKObjIDField: opt.ObjID}
+	if len(opt.GroupIDs) != 0 {
+		filter[common.BKPropertyGroupIDField] = mapstr.MapStr{common.BKDBIN: opt.GroupIDs}
+	}
+
+	delegations := make([]meta.AttrGroupDelegation, 0)
+	if err := mongodb.Client().Table(common.BKTableNameAttrGroupDelegation).Find(filter).All(ctx.Kit.Ctx,
+		&delegations); err != nil {
+		blog.Errorf("search attribute group delegation failed, filter: %v, err: %v, rid: %s", filter, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	ctx.RespEntity(delegations)
+}
+
+// DeleteAttrGroupDelegation deletes the delegation of the attribute group to the role, the group's attributes are
+// editable by all the users who have the instance's edit permission if none of its delegations is left.
+func (s *coreService) DeleteAttrGroupDelegation(ctx *rest.Contexts) {
+	opt := new(meta.AttrGroupDelegationKey)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	filter := mapstr.MapStr{
+		common.BKObjIDField:           opt.ObjID,
+		common.BKPropertyGroupIDField: opt.GroupID,
+		"role":                        opt.Role,
+	}
+	if err := mongodb.Client().Table(common.BKTableNameAttrGroupDelegation).Delete(ctx.Kit.Ctx, filter); err != nil {
+		blog.Errorf("delete attribute group delegation failed, filter: %v, err: %v, rid: %s", filter, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBDeleteFailed))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
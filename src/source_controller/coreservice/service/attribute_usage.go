@@ -0,0 +1,91 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/source_controller/coreservice/core/attrusage"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// SearchAttributeUsage returns the sampled read and write usages of the model's attributes, the attributes that are
+// never used are returned too with zero usages, so that the model owners can find out the unused attributes.
+func (s *coreService) SearchAttributeUsage(ctx *rest.Contexts) {
+	opt := new(meta.SearchAttributeUsageOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+	attrFilter := util.SetQueryOwner(mapstr.MapStr{common.BKObjIDField: objID}, ctx.Kit.SupplierAccount)
+	attrs := make([]meta.Attribute, 0)
+	err := mongodb.Client().Table(common.BKTableNameObjAttDes).Find(attrFilter).
+		Fields(common.BKPropertyIDField, common.BKPropertyNameField, common.BKIsPre).
+		Sort(common.BKPropertyIndexField).All(ctx.Kit.Ctx, &attrs)
+	if err != nil {
+		blog.Errorf("search attributes failed, filter: %v, err: %v, rid: %s", attrFilter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	if len(attrs) == 0 {
+		blog.Errorf("object %s has no attributes, rid: %s", objID, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKObjIDField))
+		return
+	}
+
+	usages, err := attrusage.GetUsages(ctx.Kit.Ctx, ctx.Kit.SupplierAccount, objID)
+	if err != nil {
+		blog.Errorf("get object %s attribute usages failed, err: %v, rid: %s", objID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommRedisOPErr))
+		return
+	}
+
+	unusedSince := time.Now().AddDate(0, 0, -opt.UnusedDays)
+	result := &meta.AttributeUsageResult{
+		ObjectID:      objID,
+		SamplePercent: attrusage.SamplePercent(),
+		Attributes:    make([]meta.AttributeUsage, 0),
+	}
+	for _, attr := range attrs {
+		usage := meta.AttributeUsage{PropertyID: attr.PropertyID}
+		if tracked, exists := usages[attr.PropertyID]; exists {
+			usage = *tracked
+		}
+		usage.PropertyName = attr.PropertyName
+		usage.IsPre = attr.IsPre
+
+		if opt.UnusedDays > 0 {
+			lastUsed := usage.LastUsedTime()
+			if lastUsed != nil && lastUsed.After(unusedSince) {
+				continue
+			}
+		}
+		result.Attributes = append(result.Attributes, usage)
+	}
+
+	ctx.RespEntity(result)
+}
@@ -55,6 +55,28 @@ func (s *coreService) SearchAuditLog(ctx *rest.Contexts) {
 	ctx.RespEntityWithCount(int64(count), auditLogs)
 }
 
+// RepointAuditLog move the audit logs of a resource to another resource of the same type
+func (s *coreService) RepointAuditLog(ctx *rest.Contexts) {
+	opt := new(metadata.RepointAuditLogOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	if err := s.core.AuditOperation().RepointAuditLog(ctx.Kit, opt); err != nil {
+		blog.Errorf("repoint audit log failed, opt: %#v, err: %v, rid: %s", opt, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
 // CreateAuditLogDependence is a dependence for host to create service instance audit logs for transfer operation
 func (s *coreService) CreateAuditLogDependence(kit *rest.Kit, logs ...metadata.AuditLog) error {
 	return s.core.AuditOperation().CreateAuditLog(kit, logs...)
@@ -0,0 +1,95 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	meta "configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// SaveBizFieldLayout creates or replaces the business's field layout of the model, the attributes in the layout
+// should be validated by the caller.
+func (s *coreService) SaveBizFieldLayout(ctx *rest.Contexts) {
+	layout := new(meta.BizFieldLayout)
+	if err := ctx.DecodeInto(layout); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := layout.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	layout.Modifier = ctx.Kit.User
+	layout.LastTime = time.Now().UTC()
+	layout.SupplierAccount = ctx.Kit.SupplierAccount
+
+	filter := mapstr.MapStr{common.BKAppIDField: layout.BizID, common.BKObjIDField: layout.ObjID}
+	if err := mongodb.Client().Table(common.BKTableNameBizFieldLayout).Upsert(ctx.Kit.Ctx, filter,
+		layout); err != nil {
+		blog.Errorf("save biz field layout %#v failed, err: %v, rid: %s", layout, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBUpdateFailed))
+		return
+	}
+
+	ctx.RespEntity(layout)
+}
+
+// SearchBizFieldLayout returns the business's field layout of the model, returns nil if it is not set.
+func (s *coreService) SearchBizFieldLayout(ctx *rest.Contexts) {
+	opt := new(meta.BizFieldLayoutOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	filter := mapstr.MapStr{common.BKAppIDField: opt.BizID, common.BKObjIDField: opt.ObjID}
+	layout := new(meta.BizFieldLayout)
+	err := mongodb.Client().Table(common.BKTableNameBizFieldLayout).Find(filter).One(ctx.Kit.Ctx, layout)
+	if err != nil {
+		if mongodb.Client().IsNotFoundError(err) {
+			ctx.RespEntity(nil)
+			return
+		}
+		blog.Errorf("search biz field layout failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	ctx.RespEntity(layout)
+}
+
+// DeleteBizFieldLayout deletes the business's field layout of the model, the model's layout is used afterwards.
+func (s *coreService) DeleteBizFieldLayout(ctx *rest.Contexts) {
+	opt := new(meta.BizFieldLayoutOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	filter := mapstr.MapStr{common.BKAppIDField: opt.BizID, common.BKObjIDField: opt.ObjID}
+	if err := mongodb.Client().Table(common.BKTableNameBizFieldLayout).Delete(ctx.Kit.Ctx, filter); err != nil {
+		blog.Errorf("delete biz field layout failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBDeleteFailed))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
@@ -0,0 +1,288 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// SaveBizRoleAssignment creates or replaces the users assigned to the business role of the topo node.
+func (s *coreService) SaveBizRoleAssignment(ctx *rest.Contexts) {
+	assignment := new(meta.BizRoleAssignment)
+	if err := ctx.DecodeInto(assignment); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := assignment.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	if err := s.checkBizRoleNode(ctx.Kit, assignment.BizRoleNode()); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	assignment.Users = util.StrArrayUnique(assignment.Users)
+	assignment.Modifier = ctx.Kit.User
+	assignment.LastTime = time.Now().UTC()
+	assignment.SupplierAccount = ctx.Kit.SupplierAccount
+
+	filter := mapstr.MapStr{
+		common.BKAppIDField:  assignment.BizID,
+		common.BKObjIDField:  assignment.ObjID,
+		common.BKInstIDField: assignment.InstID,
+		"role":               assignment.Role,
+	}
+	if err := mongodb.Client().Table(common.BKTableNameBizRoleAssignment).Upsert(ctx.Kit.Ctx, filter,
+		assignment); err != nil {
+		blog.Errorf("save biz role assignment %#v failed, err: %v, rid: %s", assignment, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBUpdateFailed))
+		return
+	}
+
+	ctx.RespEntity(assignment)
+}
+
+// checkBizRoleNode checks if the topo node exists in the business.
+func (s *coreService) checkBizRoleNode(kit *rest.Kit, node *meta.BizRoleNode) errors.CCErrorCoder {
+	filter := mapstr.MapStr{
+		common.BKAppIDField:               node.BizID,
+		common.GetInstIDField(node.ObjID): node.InstID,
+	}
+	table := common.GetInstTableName(node.ObjID, kit.SupplierAccount)
+	count, err := mongodb.Client().Table(table).Find(filter).Count(kit.Ctx)
+	if err != nil {
+		blog.Errorf("count %s instances failed, filter: %v, err: %v, rid: %s", node.ObjID, filter, err, kit.Rid)
+		return kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	if count == 0 {
+		blog.Errorf("%s instance %d is not in biz %d, rid: %s", node.ObjID, node.InstID, node.BizID, kit.Rid)
+		return kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKInstIDField)
+	}
+
+	return nil
+}
+
+// SearchBizRoleAssignment returns the business role assignments of the business.
+func (s *coreService) SearchBizRoleAssignment(ctx *rest.Contexts) {
+	opt := new(meta.ListBizRoleAssignmentOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	filter := mapstr.MapStr{common.BKAppIDField: opt.BizID}
+	if len(opt.ObjID) != 0 {
+		filter[common.BKObjIDField] = opt.ObjID
+	}
+	if len(opt.InstIDs) != 0 {
+		filter[common.BKInstIDField] = mapstr.MapStr{common.BKDBIN: opt.InstIDs}
+	}
+	if len(opt.Roles) != 0 {
+		filter["role"] = mapstr.MapStr{common.BKDBIN: opt.Roles}
+	}
+
+	assignments := make([]meta.BizRoleAssignment, 0)
+	if err := mongodb.Client().Table(common.BKTableNameBizRoleAssignment).Find(filter).All(ctx.Kit.Ctx,
+		&assignments); err != nil {
+		blog.Errorf("search biz role assignment failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	ctx.RespEntity(assignments)
+}
+
+// DeleteBizRoleAssignment deletes the assignment of the business role of the topo node, the node inherits its
+// parent's assignment afterwards.
+func (s *coreService) DeleteBizRoleAssignment(ctx *rest.Contexts) {
+	opt := new(meta.DeleteBizRoleAssignmentOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	filter := mapstr.MapStr{
+		common.BKAppIDField:  opt.BizID,
+		common.BKObjIDField:  opt.ObjID,
+		common.BKInstIDField: opt.InstID,
+		"role":               opt.Role,
+	}
+	if err := mongodb.Client().Table(common.BKTableNameBizRoleAssignment).Delete(ctx.Kit.Ctx, filter); err != nil {
+		blog.Errorf("delete biz role assignment failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBDeleteFailed))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// bizRoleNodeData is a topo node with its data in the chain from the module to the business
+type bizRoleNodeData struct {
+	objID  string
+	instID int64
+	data   mapstr.MapStr
+}
+
+// ResolveBizRoleReceivers returns the users of the business roles that should be notified for the module or the
+// business. for each role, the first assigned node in the chain from the module to its set and business is used,
+// then the default attributes of the nodes in the chain are used if the role is not assigned to any of them.
+func (s *coreService) ResolveBizRoleReceivers(ctx *rest.Contexts) {
+	opt := new(meta.ResolveBizRoleReceiverOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	chain, err := s.getBizRoleNodeChain(ctx.Kit, opt.BizID, opt.ModuleID)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	nodeCond := make([]mapstr.MapStr, len(chain))
+	for index, node := range chain {
+		nodeCond[index] = mapstr.MapStr{common.BKObjIDField: node.objID, common.BKInstIDField: node.instID}
+	}
+	filter := mapstr.MapStr{
+		common.BKAppIDField: opt.BizID,
+		"role":              mapstr.MapStr{common.BKDBIN: opt.Roles},
+		common.BKDBOR:       nodeCond,
+	}
+
+	assignments := make([]meta.BizRoleAssignment, 0)
+	if err := mongodb.Client().Table(common.BKTableNameBizRoleAssignment).Find(filter).All(ctx.Kit.Ctx,
+		&assignments); err != nil {
+		blog.Errorf("search biz role assignment failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	assignmentMap := make(map[string][]string)
+	for _, assignment := range assignments {
+		assignmentMap[bizRoleAssignmentKey(assignment.ObjID, assignment.InstID, assignment.Role)] = assignment.Users
+	}
+
+	receivers := make([]meta.BizRoleReceiver, 0, len(opt.Roles))
+	for _, role := range opt.Roles {
+		receivers = append(receivers, resolveBizRoleReceiver(role, chain, assignmentMap))
+	}
+
+	ctx.RespEntity(receivers)
+}
+
+func resolveBizRoleReceiver(role meta.BizRole, chain []bizRoleNodeData,
+	assignmentMap map[string][]string) meta.BizRoleReceiver {
+
+	for _, node := range chain {
+		users := assignmentMap[bizRoleAssignmentKey(node.objID, node.instID, role)]
+		if len(users) != 0 {
+			return meta.BizRoleReceiver{Role: role, Users: users, ObjID: node.objID, InstID: node.instID}
+		}
+	}
+
+	for _, node := range chain {
+		users := make([]string, 0)
+		for _, attr := range meta.BizRoleDefaultAttributes[role][node.objID] {
+			for _, user := range strings.Split(util.GetStrByInterface(node.data[attr]), ",") {
+				if user = strings.TrimSpace(user); len(user) != 0 {
+					users = append(users, user)
+				}
+			}
+		}
+
+		if len(users) != 0 {
+			return meta.BizRoleReceiver{Role: role, Users: util.StrArrayUnique(users), ObjID: node.objID,
+				InstID: node.instID, FromAttribute: true}
+		}
+	}
+
+	return meta.BizRoleReceiver{Role: role, Users: make([]string, 0)}
+}
+
+func bizRoleAssignmentKey(objID string, instID int64, role meta.BizRole) string {
+	return objID + ":" + strconv.FormatInt(instID, 10) + ":" + string(role)
+}
+
+// getBizRoleNodeChain returns the topo nodes from the module to its set and business, only the business is
+// returned if the module is not set.
+func (s *coreService) getBizRoleNodeChain(kit *rest.Kit, bizID, moduleID int64) ([]bizRoleNodeData,
+	errors.CCErrorCoder) {
+
+	chain := make([]bizRoleNodeData, 0)
+	if moduleID > 0 {
+		module := make(mapstr.MapStr)
+		filter := mapstr.MapStr{common.BKAppIDField: bizID, common.BKModuleIDField: moduleID}
+		err := mongodb.Client().Table(common.BKTableNameBaseModule).Find(filter).One(kit.Ctx, &module)
+		if err != nil {
+			if mongodb.Client().IsNotFoundError(err) {
+				blog.Errorf("module %d is not in biz %d, rid: %s", moduleID, bizID, kit.Rid)
+				return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKModuleIDField)
+			}
+			blog.Errorf("get module failed, filter: %v, err: %v, rid: %s", filter, err, kit.Rid)
+			return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+		}
+
+		setID, err := module.Int64(common.BKSetIDField)
+		if err != nil {
+			blog.Errorf("parse module %d set id failed, err: %v, rid: %s", moduleID, err, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKSetIDField)
+		}
+
+		chain = append(chain,
+			bizRoleNodeData{objID: common.BKInnerObjIDModule, instID: moduleID, data: module},
+			bizRoleNodeData{objID: common.BKInnerObjIDSet, instID: setID})
+	}
+
+	biz := make(mapstr.MapStr)
+	filter := mapstr.MapStr{common.BKAppIDField: bizID}
+	if err := mongodb.Client().Table(common.BKTableNameBaseApp).Find(filter).One(kit.Ctx, &biz); err != nil {
+		if mongodb.Client().IsNotFoundError(err) {
+			blog.Errorf("biz %d is not exist, rid: %s", bizID, kit.Rid)
+			return nil, kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKAppIDField)
+		}
+		blog.Errorf("get biz failed, filter: %v, err: %v, rid: %s", filter, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	return append(chain, bizRoleNodeData{objID: common.BKInnerObjIDApp, instID: bizID, data: biz}), nil
+}
@@ -122,6 +122,27 @@ func (s *coreService) DeleteHostFromSystem(ctx *rest.Contexts) {
 	ctx.RespEntity(nil)
 }
 
+// MergeHost move the source host's relations to the target host and delete the source host
+func (s *coreService) MergeHost(ctx *rest.Contexts) {
+	opt := new(metadata.MergeHostOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	if err := s.core.HostOperation().MergeHost(ctx.Kit, opt); err != nil {
+		blog.Errorf("merge host %d into %d failed, err: %v, rid: %s", opt.SourceID, opt.TargetID, err, ctx.Kit.Rid)
+		ctx.RespAutoError(err)
+		return
+	}
+	ctx.RespEntity(nil)
+}
+
 // HostIdentifier TODO
 func (s *coreService) HostIdentifier(ctx *rest.Contexts) {
 	inputData := &metadata.SearchHostIdentifierParam{}
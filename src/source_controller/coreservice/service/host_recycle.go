@@ -0,0 +1,285 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// CreateHostRecyclePolicy creates a new host recycle policy.
+func (s *coreService) CreateHostRecyclePolicy(ctx *rest.Contexts) {
+	policy := new(meta.HostRecyclePolicy)
+	if err := ctx.DecodeInto(policy); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := policy.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	filter := mapstr.MapStr{common.BKAppIDField: policy.BizID, common.BKFieldName: policy.Name}
+	count, err := mongodb.Client().Table(common.BKTableNameHostRecyclePolicy).Find(filter).Count(ctx.Kit.Ctx)
+	if err != nil {
+		blog.Errorf("count host recycle policy failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+	if count != 0 {
+		blog.Errorf("host recycle policy %s already exist, rid: %s", policy.Name, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommDuplicateItem, common.BKFieldName))
+		return
+	}
+
+	id, err := mongodb.Client().NextSequence(ctx.Kit.Ctx, common.BKTableNameHostRecyclePolicy)
+	if err != nil {
+		blog.Errorf("generate host recycle policy id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommGenerateRecordIDFailed))
+		return
+	}
+
+	now := time.Now().UTC()
+	policy.ID = int64(id)
+	policy.Creator = ctx.Kit.User
+	policy.Modifier = ctx.Kit.User
+	policy.CreateTime = now
+	policy.LastTime = now
+	policy.LastRunTime = nil
+	policy.SupplierAccount = ctx.Kit.SupplierAccount
+
+	if err := mongodb.Client().Table(common.BKTableNameHostRecyclePolicy).Insert(ctx.Kit.Ctx, policy); err != nil {
+		blog.Errorf("create host recycle policy %#v failed, err: %v, rid: %s", policy, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBInsertFailed))
+		return
+	}
+
+	ctx.RespEntity(policy)
+}
+
+// UpdateHostRecyclePolicy updates the host recycle policy, the data should be validated by the caller.
+func (s *coreService) UpdateHostRecyclePolicy(ctx *rest.Contexts) {
+	id, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKFieldID), 10, 64)
+	if err != nil {
+		blog.Errorf("parse host recycle policy id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKFieldID))
+		return
+	}
+
+	data := make(mapstr.MapStr)
+	if err := ctx.DecodeInto(&data); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	// remove the fields that can not be updated
+	data.Remove(common.BKFieldID)
+	data.Remove(common.BKAppIDField)
+	data.Remove(common.CreatorField)
+	data.Remove(common.CreateTimeField)
+	data.Remove(common.BkSupplierAccount)
+
+	// the run time is transferred as string, convert it back to time so that it can be decoded
+	if lastRunTime, exists := data["last_run_time"]; exists {
+		runTime, err := time.Parse(time.RFC3339Nano, util.GetStrByInterface(lastRunTime))
+		if err != nil {
+			blog.Errorf("parse host recycle policy run time failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+			ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "last_run_time"))
+			return
+		}
+		data["last_run_time"] = runTime.UTC()
+	}
+
+	data[common.ModifierField] = ctx.Kit.User
+	data[common.LastTimeField] = time.Now().UTC()
+
+	filter := mapstr.MapStr{common.BKFieldID: id}
+	if err := mongodb.Client().Table(common.BKTableNameHostRecyclePolicy).Update(ctx.Kit.Ctx, filter,
+		data); err != nil {
+		blog.Errorf("update host recycle policy %d failed, data: %v, err: %v, rid: %s", id, data, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBUpdateFailed))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// DeleteHostRecyclePolicy deletes the host recycle policy, the reports of the policy are kept for audit.
+func (s *coreService) DeleteHostRecyclePolicy(ctx *rest.Contexts) {
+	id, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKFieldID), 10, 64)
+	if err != nil {
+		blog.Errorf("parse host recycle policy id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKFieldID))
+		return
+	}
+
+	filter := mapstr.MapStr{common.BKFieldID: id}
+	if err := mongodb.Client().Table(common.BKTableNameHostRecyclePolicy).Delete(ctx.Kit.Ctx, filter); err != nil {
+		blog.Errorf("delete host recycle policy %d failed, err: %v, rid: %s", id, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBDeleteFailed))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// SearchHostRecyclePolicy returns the host recycle policies with the conditions.
+func (s *coreService) SearchHostRecyclePolicy(ctx *rest.Contexts) {
+	input := new(meta.QueryCondition)
+	if err := ctx.DecodeInto(input); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	result := meta.MultipleHostRecyclePolicy{Info: make([]meta.HostRecyclePolicy, 0)}
+	count, err := s.searchHostRecycleTable(ctx.Kit, common.BKTableNameHostRecyclePolicy, input, &result.Info)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	result.Count = count
+
+	ctx.RespEntity(result)
+}
+
+// CreateHostRecycleReport creates a new host recycle report.
+func (s *coreService) CreateHostRecycleReport(ctx *rest.Contexts) {
+	report := new(meta.HostRecycleReport)
+	if err := ctx.DecodeInto(report); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	id, err := mongodb.Client().NextSequence(ctx.Kit.Ctx, common.BKTableNameHostRecycleReport)
+	if err != nil {
+		blog.Errorf("generate host recycle report id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommGenerateRecordIDFailed))
+		return
+	}
+
+	now := time.Now().UTC()
+	report.ID = int64(id)
+	report.Creator = ctx.Kit.User
+	report.Modifier = ctx.Kit.User
+	report.CreateTime = now
+	report.LastTime = now
+	report.SupplierAccount = ctx.Kit.SupplierAccount
+	if report.Hosts == nil {
+		report.Hosts = make([]meta.HostRecycleHost, 0)
+	}
+
+	if err := mongodb.Client().Table(common.BKTableNameHostRecycleReport).Insert(ctx.Kit.Ctx, report); err != nil {
+		blog.Errorf("create host recycle report %#v failed, err: %v, rid: %s", report, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBInsertFailed))
+		return
+	}
+
+	ctx.RespEntity(report)
+}
+
+// UpdateHostRecycleReport updates the status of the host recycle report.
+func (s *coreService) UpdateHostRecycleReport(ctx *rest.Contexts) {
+	id, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKFieldID), 10, 64)
+	if err != nil {
+		blog.Errorf("parse host recycle report id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKFieldID))
+		return
+	}
+
+	data := make(mapstr.MapStr)
+	if err := ctx.DecodeInto(&data); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	// only the review result of the report can be updated, the matched hosts are kept as they were
+	updateData := make(mapstr.MapStr)
+	for _, field := range []string{"status", "reason", "reviewer"} {
+		if value, exists := data[field]; exists {
+			updateData[field] = value
+		}
+	}
+	updateData[common.ModifierField] = ctx.Kit.User
+	updateData[common.LastTimeField] = time.Now().UTC()
+
+	filter := mapstr.MapStr{common.BKFieldID: id}
+	if err := mongodb.Client().Table(common.BKTableNameHostRecycleReport).Update(ctx.Kit.Ctx, filter,
+		updateData); err != nil {
+		blog.Errorf("update host recycle report %d failed, data: %v, err: %v, rid: %s", id, updateData, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBUpdateFailed))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// SearchHostRecycleReport returns the host recycle reports with the conditions.
+func (s *coreService) SearchHostRecycleReport(ctx *rest.Contexts) {
+	input := new(meta.QueryCondition)
+	if err := ctx.DecodeInto(input); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	result := meta.MultipleHostRecycleReport{Info: make([]meta.HostRecycleReport, 0)}
+	count, err := s.searchHostRecycleTable(ctx.Kit, common.BKTableNameHostRecycleReport, input, &result.Info)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	result.Count = count
+
+	ctx.RespEntity(result)
+}
+
+func (s *coreService) searchHostRecycleTable(kit *rest.Kit, table string, input *meta.QueryCondition,
+	result interface{}) (int64, error) {
+
+	condition := input.Condition
+	if condition == nil {
+		condition = make(mapstr.MapStr)
+	}
+
+	sort := input.Page.Sort
+	if len(sort) == 0 {
+		sort = common.BKFieldID
+	}
+
+	var count uint64
+	if !input.DisableCounter {
+		var err error
+		count, err = mongodb.Client().Table(table).Find(condition).Count(kit.Ctx)
+		if err != nil {
+			blog.Errorf("count %s failed, cond: %v, err: %v, rid: %s", table, condition, err, kit.Rid)
+			return 0, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+		}
+	}
+
+	if err := mongodb.Client().Table(table).Find(condition).Fields(input.Fields...).Sort(sort).
+		Start(uint64(input.Page.Start)).Limit(uint64(input.Page.Limit)).All(kit.Ctx, result); err != nil {
+		blog.Errorf("search %s failed, cond: %v, err: %v, rid: %s", table, condition, err, kit.Rid)
+		return 0, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+
+	return int64(count), nil
+}
@@ -51,6 +51,18 @@ func (s *coreService) UpdateModelInstances(ctx *rest.Contexts) {
 	ctx.RespEntityWithError(s.core.InstanceOperation().UpdateModelInstance(ctx.Kit, ctx.Request.PathParameter("bk_obj_id"), inputData))
 }
 
+// CreateOrUpdateModelInstance create the instance, or update the instance with the same natural key
+func (s *coreService) CreateOrUpdateModelInstance(ctx *rest.Contexts) {
+	inputData := metadata.CreateOrUpdateModelInstance{}
+	if err := ctx.DecodeInto(&inputData); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntityWithError(s.core.InstanceOperation().CreateOrUpdateModelInstance(ctx.Kit,
+		ctx.Request.PathParameter(common.BKObjIDField), inputData))
+}
+
 // SearchModelInstances TODO
 func (s *coreService) SearchModelInstances(ctx *rest.Contexts) {
 	inputData := metadata.QueryCondition{}
@@ -133,3 +145,62 @@ func (s *coreService) GetInstanceObjectMapping(ctx *rest.Contexts) {
 	}
 	ctx.RespEntityWithError(instancemapping.GetInstanceObjectMapping(inputData.IDs))
 }
+
+// BindExternalIDs bind the external ids to the instance
+func (s *coreService) BindExternalIDs(ctx *rest.Contexts) {
+	opt := new(metadata.BindExternalIDOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+	if err := s.core.InstanceOperation().BindExternalIDs(ctx.Kit, objID, opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	ctx.RespEntity(nil)
+}
+
+// UnbindExternalIDs unbind the external ids of the sources from the instance
+func (s *coreService) UnbindExternalIDs(ctx *rest.Contexts) {
+	opt := new(metadata.UnbindExternalIDOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+	if err := s.core.InstanceOperation().UnbindExternalIDs(ctx.Kit, objID, opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	ctx.RespEntity(nil)
+}
+
+// SearchExternalIDs search the external ids of the instances, or look up the instances by the external ids
+func (s *coreService) SearchExternalIDs(ctx *rest.Contexts) {
+	opt := new(metadata.SearchExternalIDOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	ctx.RespEntityWithError(s.core.InstanceOperation().SearchExternalIDs(ctx.Kit,
+		ctx.Request.PathParameter(common.BKObjIDField), opt))
+}
@@ -18,6 +18,9 @@ import (
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/mongodb"
 )
 
 // SearchMainlineModelTopo TODO
@@ -69,3 +72,62 @@ func (s *coreService) SearchMainlineInstanceTopo(ctx *rest.Contexts) {
 	}
 	ctx.RespEntity(result)
 }
+
+// CountTopoChildNodes counts the child nodes of the mainline object under each parent node, and returns the parent
+// node having the most child nodes of each business, which is used to report the businesses approaching the limits.
+func (s *coreService) CountTopoChildNodes(ctx *rest.Contexts) {
+	objID := ctx.Request.PathParameter(common.BKObjIDField)
+	if len(objID) == 0 || objID == common.BKInnerObjIDApp {
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsInvalid, common.BKObjIDField))
+		return
+	}
+
+	opt := new(metadata.CountTopoChildNodesOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	// the built-in idle set and its modules are not counted since they are not created by the users
+	filter := mapstr.MapStr{common.BKDefaultField: mapstr.MapStr{common.BKDBIN: []interface{}{0, nil}}}
+	if len(opt.BizIDs) != 0 {
+		filter[common.BKAppIDField] = mapstr.MapStr{common.BKDBIN: opt.BizIDs}
+	}
+	if metadata.IsCommon(objID) {
+		filter[common.BKObjIDField] = objID
+	}
+
+	pipeline := []mapstr.MapStr{
+		{common.BKDBMatch: filter},
+		{common.BKDBGroup: mapstr.MapStr{
+			"_id": mapstr.MapStr{
+				common.BKAppIDField:    "$" + common.BKAppIDField,
+				common.BKParentIDField: "$" + common.BKParentIDField,
+			},
+			"count": mapstr.MapStr{common.BKDBSum: 1},
+		}},
+		{common.BKDBMatch: mapstr.MapStr{"count": mapstr.MapStr{common.BKDBGTE: opt.MinCount}}},
+		{common.BKDBSort: mapstr.MapStr{"count": -1}},
+		{common.BKDBGroup: mapstr.MapStr{
+			"_id":                  "$_id." + common.BKAppIDField,
+			common.BKParentIDField: mapstr.MapStr{"$first": "$_id." + common.BKParentIDField},
+			"count":                mapstr.MapStr{"$first": "$count"},
+		}},
+		{common.BKDBProject: mapstr.MapStr{
+			"_id":                  0,
+			common.BKAppIDField:    "$_id",
+			common.BKParentIDField: 1,
+			"count":                1,
+		}},
+	}
+
+	result := make([]metadata.TopoChildNodeCount, 0)
+	table := common.GetInstTableName(objID, ctx.Kit.SupplierAccount)
+	if err := mongodb.Client().Table(table).AggregateAll(ctx.Kit.Ctx, pipeline, &result); err != nil {
+		blog.Errorf("count %s topo child nodes failed, opt: %#v, err: %v, rid: %s", objID, opt, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	ctx.RespEntity(result)
+}
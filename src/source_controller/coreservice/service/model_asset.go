@@ -0,0 +1,298 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	meta "configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// CreateModelAsset creates a model asset with its first version, the content should be put into the blob store by
+// the caller before the asset is created.
+func (s *coreService) CreateModelAsset(ctx *rest.Contexts) {
+	opt := new(meta.CreateModelAssetOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	filter := mapstr.MapStr{"type": opt.Type, common.BKFieldName: opt.Name}
+	count, err := mongodb.Client().Table(common.BKTableNameModelAsset).Find(filter).Count(ctx.Kit.Ctx)
+	if err != nil {
+		blog.Errorf("count model asset failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+	if count != 0 {
+		blog.Errorf("model asset %s of type %s already exist, rid: %s", opt.Name, opt.Type, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommDuplicateItem, common.BKFieldName))
+		return
+	}
+
+	id, err := mongodb.Client().NextSequence(ctx.Kit.Ctx, common.BKTableNameModelAsset)
+	if err != nil {
+		blog.Errorf("generate model asset id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommGenerateRecordIDFailed))
+		return
+	}
+
+	now := time.Now().UTC()
+	asset := &meta.ModelAsset{
+		ID:      int64(id),
+		Name:    opt.Name,
+		Type:    opt.Type,
+		Version: 1,
+		Versions: []meta.ModelAssetVersion{{
+			Version:           1,
+			ModelAssetContent: opt.Content,
+			Creator:           ctx.Kit.User,
+			CreateTime:        now,
+		}},
+		Creator:         ctx.Kit.User,
+		CreateTime:      now,
+		Modifier:        ctx.Kit.User,
+		LastTime:        now,
+		SupplierAccount: ctx.Kit.SupplierAccount,
+	}
+
+	if err := mongodb.Client().Table(common.BKTableNameModelAsset).Insert(ctx.Kit.Ctx, asset); err != nil {
+		blog.Errorf("create model asset %#v failed, err: %v, rid: %s", asset, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBInsertFailed))
+		return
+	}
+
+	ctx.RespEntity(asset)
+}
+
+// AddModelAssetVersion adds a new version to the model asset which becomes the latest one, the oldest versions
+// exceeding the max versions are removed and their blob store keys are returned for the caller to clean up.
+func (s *coreService) AddModelAssetVersion(ctx *rest.Contexts) {
+	id, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKFieldID), 10, 64)
+	if err != nil {
+		blog.Errorf("parse model asset id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKFieldID))
+		return
+	}
+
+	opt := new(meta.AddModelAssetVersionOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	asset, err := s.getModelAsset(ctx.Kit, id)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Content.Validate(asset.Type); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	now := time.Now().UTC()
+	asset.Version++
+	asset.Versions = append(asset.Versions, meta.ModelAssetVersion{
+		Version:           asset.Version,
+		ModelAssetContent: opt.Content,
+		Creator:           ctx.Kit.User,
+		CreateTime:        now,
+	})
+
+	removedKeys := make([]string, 0)
+	if len(asset.Versions) > meta.ModelAssetMaxVersions {
+		removed := asset.Versions[:len(asset.Versions)-meta.ModelAssetMaxVersions]
+		for _, version := range removed {
+			removedKeys = append(removedKeys, version.Key)
+		}
+		asset.Versions = asset.Versions[len(removed):]
+	}
+	asset.Modifier = ctx.Kit.User
+	asset.LastTime = now
+
+	// the previous version is used as the condition so that the concurrent updates do not overwrite each other
+	filter := mapstr.MapStr{common.BKFieldID: id, "version": asset.Version - 1}
+	data := mapstr.MapStr{
+		"version":            asset.Version,
+		"versions":           asset.Versions,
+		common.ModifierField: asset.Modifier,
+		common.LastTimeField: asset.LastTime,
+	}
+	count, err := mongodb.Client().Table(common.BKTableNameModelAsset).UpdateMany(ctx.Kit.Ctx, filter, data)
+	if err != nil {
+		blog.Errorf("add model asset %d version failed, err: %v, rid: %s", id, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBUpdateFailed))
+		return
+	}
+	if count == 0 {
+		blog.Errorf("model asset %d is updated concurrently, rid: %s", id, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "version"))
+		return
+	}
+
+	ctx.RespEntity(&meta.AddModelAssetVersionResult{Asset: asset, RemovedKeys: removedKeys})
+}
+
+// SearchModelAsset searches the model assets, the reference counts of the assets are counted by the models and
+// classifications using them as the icon.
+func (s *coreService) SearchModelAsset(ctx *rest.Contexts) {
+	opt := new(meta.SearchModelAssetOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	filter := mapstr.MapStr{}
+	if len(opt.IDs) != 0 {
+		filter[common.BKFieldID] = mapstr.MapStr{common.BKDBIN: opt.IDs}
+	}
+	if len(opt.Type) != 0 {
+		filter["type"] = opt.Type
+	}
+	if len(opt.Name) != 0 {
+		filter[common.BKFieldName] = mapstr.MapStr{common.BKDBLIKE: regexp.QuoteMeta(opt.Name), common.BKDBOPTIONS: "i"}
+	}
+
+	count, err := mongodb.Client().Table(common.BKTableNameModelAsset).Find(filter).Count(ctx.Kit.Ctx)
+	if err != nil {
+		blog.Errorf("count model asset failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	assets := make([]meta.ModelAsset, 0)
+	if err := mongodb.Client().Table(common.BKTableNameModelAsset).Find(filter).Sort(opt.Page.Sort).
+		Start(uint64(opt.Page.Start)).Limit(uint64(opt.Page.Limit)).All(ctx.Kit.Ctx, &assets); err != nil {
+		blog.Errorf("search model asset failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	refs := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		refs = append(refs, meta.ModelAssetRef(asset.ID))
+	}
+	refCounts, err := s.countModelAssetRefs(ctx.Kit, refs)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	for index := range assets {
+		assets[index].RefCount = refCounts[meta.ModelAssetRef(assets[index].ID)]
+	}
+
+	ctx.RespEntity(&meta.SearchModelAssetResult{Count: int64(count), Info: assets})
+}
+
+// DeleteModelAsset deletes the model asset which is not referred by any model or classification, the deleted asset
+// is returned for the caller to delete the contents of its versions from the blob store.
+func (s *coreService) DeleteModelAsset(ctx *rest.Contexts) {
+	id, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKFieldID), 10, 64)
+	if err != nil {
+		blog.Errorf("parse model asset id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKFieldID))
+		return
+	}
+
+	asset, err := s.getModelAsset(ctx.Kit, id)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ref := meta.ModelAssetRef(id)
+	refCounts, err := s.countModelAssetRefs(ctx.Kit, []string{ref})
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+	if refCounts[ref] > 0 {
+		blog.Errorf("model asset %d is referred by %d models or classifications, rid: %s", id, refCounts[ref],
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCoreServiceModelAssetReferred, asset.Name,
+			refCounts[ref]))
+		return
+	}
+
+	filter := mapstr.MapStr{common.BKFieldID: id}
+	if err := mongodb.Client().Table(common.BKTableNameModelAsset).Delete(ctx.Kit.Ctx, filter); err != nil {
+		blog.Errorf("delete model asset %d failed, err: %v, rid: %s", id, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBDeleteFailed))
+		return
+	}
+
+	ctx.RespEntity(asset)
+}
+
+func (s *coreService) getModelAsset(kit *rest.Kit, id int64) (*meta.ModelAsset, error) {
+	asset := new(meta.ModelAsset)
+	filter := mapstr.MapStr{common.BKFieldID: id}
+	if err := mongodb.Client().Table(common.BKTableNameModelAsset).Find(filter).One(kit.Ctx, asset); err != nil {
+		if mongodb.Client().IsNotFoundError(err) {
+			blog.Errorf("model asset %d not exists, rid: %s", id, kit.Rid)
+			return nil, kit.CCError.CCError(common.CCErrCommNotFound)
+		}
+		blog.Errorf("get model asset %d failed, err: %v, rid: %s", id, err, kit.Rid)
+		return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+	}
+	return asset, nil
+}
+
+// countModelAssetRefs counts the models and classifications using the model assets as the icon, returns the map of
+// the asset reference to its count.
+func (s *coreService) countModelAssetRefs(kit *rest.Kit, refs []string) (map[string]int64, error) {
+	refCounts := make(map[string]int64, len(refs))
+	if len(refs) == 0 {
+		return refCounts, nil
+	}
+
+	iconFields := map[string]string{
+		common.BKTableNameObjDes:            common.BKObjIconField,
+		common.BKTableNameObjClassification: common.BKClassificationIconField,
+	}
+	for table, field := range iconFields {
+		filter := mapstr.MapStr{field: mapstr.MapStr{common.BKDBIN: refs}}
+		icons := make([]mapstr.MapStr, 0)
+		if err := mongodb.Client().Table(table).Find(filter).Fields(field).All(kit.Ctx, &icons); err != nil {
+			blog.Errorf("find model asset references in %s failed, filter: %v, err: %v, rid: %s", table, filter, err,
+				kit.Rid)
+			return nil, kit.CCError.CCError(common.CCErrCommDBSelectFailed)
+		}
+
+		for _, icon := range icons {
+			ref, _ := icon[field].(string)
+			refCounts[ref]++
+		}
+	}
+
+	return refCounts, nil
+}
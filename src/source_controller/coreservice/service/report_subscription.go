@@ -0,0 +1,185 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"strconv"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	meta "configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// CreateReportSubscription creates a new report subscription.
+func (s *coreService) CreateReportSubscription(ctx *rest.Contexts) {
+	subscription := new(meta.ReportSubscription)
+	if err := ctx.DecodeInto(subscription); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := subscription.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	filter := mapstr.MapStr{common.BKAppIDField: subscription.BizID, common.BKFieldName: subscription.Name}
+	count, err := mongodb.Client().Table(common.BKTableNameReportSubscription).Find(filter).Count(ctx.Kit.Ctx)
+	if err != nil {
+		blog.Errorf("count report subscription failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+	if count != 0 {
+		blog.Errorf("report subscription %s already exist, rid: %s", subscription.Name, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommDuplicateItem, common.BKFieldName))
+		return
+	}
+
+	id, err := mongodb.Client().NextSequence(ctx.Kit.Ctx, common.BKTableNameReportSubscription)
+	if err != nil {
+		blog.Errorf("generate report subscription id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommGenerateRecordIDFailed))
+		return
+	}
+
+	now := time.Now().UTC()
+	subscription.ID = int64(id)
+	subscription.Creator = ctx.Kit.User
+	subscription.Modifier = ctx.Kit.User
+	subscription.CreateTime = now
+	subscription.LastTime = now
+	subscription.LastSendTime = nil
+	subscription.SupplierAccount = ctx.Kit.SupplierAccount
+
+	if err := mongodb.Client().Table(common.BKTableNameReportSubscription).Insert(ctx.Kit.Ctx,
+		subscription); err != nil {
+		blog.Errorf("create report subscription %#v failed, err: %v, rid: %s", subscription, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBInsertFailed))
+		return
+	}
+
+	ctx.RespEntity(subscription)
+}
+
+// UpdateReportSubscription updates the report subscription, the data should be validated by the caller.
+func (s *coreService) UpdateReportSubscription(ctx *rest.Contexts) {
+	id, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKFieldID), 10, 64)
+	if err != nil {
+		blog.Errorf("parse report subscription id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKFieldID))
+		return
+	}
+
+	data := make(mapstr.MapStr)
+	if err := ctx.DecodeInto(&data); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	// remove the fields that can not be updated
+	data.Remove(common.BKFieldID)
+	data.Remove(common.BKAppIDField)
+	data.Remove(common.CreatorField)
+	data.Remove(common.CreateTimeField)
+	data.Remove(common.BkSupplierAccount)
+
+	// the send time is transferred as string, convert it back to time so that it can be decoded
+	if lastSendTime, exists := data["last_send_time"]; exists {
+		sendTime, err := time.Parse(time.RFC3339Nano, util.GetStrByInterface(lastSendTime))
+		if err != nil {
+			blog.Errorf("parse report subscription send time failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+			ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, "last_send_time"))
+			return
+		}
+		data["last_send_time"] = sendTime.UTC()
+	}
+
+	data[common.ModifierField] = ctx.Kit.User
+	data[common.LastTimeField] = time.Now().UTC()
+
+	filter := mapstr.MapStr{common.BKFieldID: id}
+	if err := mongodb.Client().Table(common.BKTableNameReportSubscription).Update(ctx.Kit.Ctx, filter,
+		data); err != nil {
+		blog.Errorf("update report subscription %d failed, data: %v, err: %v, rid: %s", id, data, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBUpdateFailed))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// DeleteReportSubscription deletes the report subscription.
+func (s *coreService) DeleteReportSubscription(ctx *rest.Contexts) {
+	id, err := strconv.ParseInt(ctx.Request.PathParameter(common.BKFieldID), 10, 64)
+	if err != nil {
+		blog.Errorf("parse report subscription id failed, err: %v, rid: %s", err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKFieldID))
+		return
+	}
+
+	filter := mapstr.MapStr{common.BKFieldID: id}
+	if err := mongodb.Client().Table(common.BKTableNameReportSubscription).Delete(ctx.Kit.Ctx, filter); err != nil {
+		blog.Errorf("delete report subscription %d failed, err: %v, rid: %s", id, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBDeleteFailed))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// SearchReportSubscription returns the report subscriptions with the conditions.
+func (s *coreService) SearchReportSubscription(ctx *rest.Contexts) {
+	input := new(meta.QueryCondition)
+	if err := ctx.DecodeInto(input); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	condition := input.Condition
+	if condition == nil {
+		condition = make(mapstr.MapStr)
+	}
+
+	sort := input.Page.Sort
+	if len(sort) == 0 {
+		sort = common.BKFieldID
+	}
+
+	result := meta.MultipleReportSubscription{Info: make([]meta.ReportSubscription, 0)}
+	if !input.DisableCounter {
+		count, err := mongodb.Client().Table(common.BKTableNameReportSubscription).Find(condition).
+			Count(ctx.Kit.Ctx)
+		if err != nil {
+			blog.Errorf("count report subscriptions failed, cond: %v, err: %v, rid: %s", condition, err, ctx.Kit.Rid)
+			ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+			return
+		}
+		result.Count = int64(count)
+	}
+
+	if err := mongodb.Client().Table(common.BKTableNameReportSubscription).Find(condition).Fields(input.Fields...).
+		Sort(sort).Start(uint64(input.Page.Start)).Limit(uint64(input.Page.Limit)).
+		All(ctx.Kit.Ctx, &result.Info); err != nil {
+		blog.Errorf("search report subscriptions failed, cond: %v, err: %v, rid: %s", condition, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	ctx.RespEntity(result)
+}
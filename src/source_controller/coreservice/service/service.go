@@ -104,7 +104,7 @@ func (s *coreService) SetConfig(cfg options.Config, engine *backbone.Engine, err
 	s.rds = cache */
 
 	// connect the remote mongodb
-	instance := instances.New(s, lang, engine.CoreAPI)
+	instance := instances.New(s, lang, engine.CoreAPI, cfg.HostLifecycle, cfg.InstNameCollation)
 	hostApplyRuleCore := hostapplyrule.New(instance)
 	s.core = core.New(
 		model.New(s, lang),
@@ -113,7 +113,7 @@ func (s *coreService) SetConfig(cfg options.Config, engine *backbone.Engine, err
 		datasynchronize.New(s),
 		mainline.New(lang),
 		host.New(s, hostApplyRuleCore),
-		auditlog.New(),
+		auditlog.New(cfg.AuditDetail),
 		process.New(s),
 		label.New(),
 		settemplate.New(),
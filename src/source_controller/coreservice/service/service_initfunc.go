@@ -72,6 +72,34 @@ func (s *coreService) initModel(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/model/{bk_obj_id}/attributes", Handler: s.DeleteModelAttribute})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/model/{bk_obj_id}/attributes", Handler: s.SearchModelAttributes})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/model/attributes", Handler: s.SearchModelAttributesByCondition})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/model/{bk_obj_id}/attributes/usage",
+		Handler: s.SearchAttributeUsage})
+
+	// init business field layout methods
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/model/biz_field_layout",
+		Handler: s.SaveBizFieldLayout})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/model/biz_field_layout",
+		Handler: s.SearchBizFieldLayout})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/model/biz_field_layout",
+		Handler: s.DeleteBizFieldLayout})
+
+	// init attribute group delegation methods
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/model/group/delegation",
+		Handler: s.SaveAttrGroupDelegation})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/model/group/delegation",
+		Handler: s.SearchAttrGroupDelegation})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/model/group/delegation",
+		Handler: s.DeleteAttrGroupDelegation})
+
+	// init model asset methods
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/model/asset",
+		Handler: s.CreateModelAsset})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/model/asset/{id}/version",
+		Handler: s.AddModelAssetVersion})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/model/asset",
+		Handler: s.SearchModelAsset})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/model/asset/{id}",
+		Handler: s.DeleteModelAsset})
 
 	utility.AddToRestfulWebService(web)
 }
@@ -99,11 +127,21 @@ func (s *coreService) initModelInstances(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/model/{bk_obj_id}/instance", Handler: s.CreateOneModelInstance})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/createmany/model/{bk_obj_id}/instance", Handler: s.CreateManyModelInstances})
 	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/model/{bk_obj_id}/instance", Handler: s.UpdateModelInstances})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create_or_update/model/{bk_obj_id}/instance",
+		Handler: s.CreateOrUpdateModelInstance})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/model/{bk_obj_id}/instances", Handler: s.SearchModelInstances})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/count/model/{bk_obj_id}/instances", Handler: s.CountModelInstances})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/model/{bk_obj_id}/instance", Handler: s.DeleteModelInstances})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/model/{bk_obj_id}/instance/cascade", Handler: s.CascadeDeleteModelInstances})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/get/instance/object/mapping", Handler: s.GetInstanceObjectMapping})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/instance/import/conflict",
+		Handler: s.SearchImportConflict})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/external_id/object/{bk_obj_id}",
+		Handler: s.BindExternalIDs})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/external_id/object/{bk_obj_id}",
+		Handler: s.UnbindExternalIDs})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/external_id/object/{bk_obj_id}",
+		Handler: s.SearchExternalIDs})
 
 	utility.AddToRestfulWebService(web)
 }
@@ -154,6 +192,8 @@ func (s *coreService) initInstanceAssociation(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/instanceassociation", Handler: s.SearchInstanceAssociation})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/count/instanceassociation/model/{bk_obj_id}", Handler: s.CountInstanceAssociations})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/instanceassociation", Handler: s.DeleteInstanceAssociation})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/instanceassociation/mapping_violation",
+		Handler: s.ScanAsstMappingViolation})
 
 	utility.AddToRestfulWebService(web)
 }
@@ -167,6 +207,8 @@ func (s *coreService) initMainline(web *restful.WebService) {
 	// add handler for model topo and business topo
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/mainline/model", Handler: s.SearchMainlineModelTopo})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/mainline/instance/{bk_biz_id}", Handler: s.SearchMainlineInstanceTopo})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/mainline/child_count/{bk_obj_id}",
+		Handler: s.CountTopoChildNodes})
 
 	utility.AddToRestfulWebService(web)
 }
@@ -182,6 +224,7 @@ func (s *coreService) host(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/set/module/host/relation/cross/business", Handler: s.TransferHostToAnotherBusiness})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/host", Handler: s.DeleteHostFromSystem})
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/host/host_module_relations", Handler: s.RemoveFromModule})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/host/merge", Handler: s.MergeHost})
 
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/module/host/relation", Handler: s.GetHostModuleRelation})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/host/indentifier", Handler: s.HostIdentifier})
@@ -241,6 +284,22 @@ func (s *coreService) host(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/distinct/host_id/topology/relation", Handler: s.GetDistinctHostIDsByTopoRelation})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/host/transfer/resource/directory", Handler: s.TransferHostResourceDirectory})
 
+	// host recycle policy handlers.
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/host_recycle_policy",
+		Handler: s.CreateHostRecyclePolicy})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/host_recycle_policy/{id}",
+		Handler: s.UpdateHostRecyclePolicy})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/host_recycle_policy/{id}",
+		Handler: s.DeleteHostRecyclePolicy})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/host_recycle_policy",
+		Handler: s.SearchHostRecyclePolicy})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/host_recycle_report",
+		Handler: s.CreateHostRecycleReport})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/host_recycle_report/{id}",
+		Handler: s.UpdateHostRecycleReport})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/host_recycle_report",
+		Handler: s.SearchHostRecycleReport})
+
 	utility.AddToRestfulWebService(web)
 }
 
@@ -252,6 +311,7 @@ func (s *coreService) audit(web *restful.WebService) {
 
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/auditlog", Handler: s.CreateAuditLog})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/read/auditlog", Handler: s.SearchAuditLog})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/auditlog/repoint", Handler: s.RepointAuditLog})
 
 	utility.AddToRestfulWebService(web)
 }
@@ -274,6 +334,23 @@ func (s *coreService) initOperation(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/operation/timer/chart/data", Handler: s.SearchTimerChartData})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/start/operation/chart/timer", Handler: s.TimerFreshData})
 
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/create/report_subscription",
+		Handler: s.CreateReportSubscription})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/report_subscription/{id}",
+		Handler: s.UpdateReportSubscription})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/report_subscription/{id}",
+		Handler: s.DeleteReportSubscription})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/report_subscription",
+		Handler: s.SearchReportSubscription})
+
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/biz_role", Handler: s.SaveBizRoleAssignment})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/biz_role",
+		Handler: s.SearchBizRoleAssignment})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/biz_role",
+		Handler: s.DeleteBizRoleAssignment})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/biz_role/receivers",
+		Handler: s.ResolveBizRoleReceivers})
+
 	utility.AddToRestfulWebService(web)
 }
 
@@ -56,6 +56,20 @@ func (s *coreService) initProcess(web *restful.WebService) {
 	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/process/service_instance", Handler: s.DeleteServiceInstance})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/update/process/service_instance_name", Handler: s.ConstructServiceInstanceName})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/update/process/service_instance_name/{service_instance_id}", Handler: s.ReconstructServiceInstanceName})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/updatemany/process/service_instance_name/render",
+		Handler: s.RenderServiceInstanceNames})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/process/service_instance_name_template",
+		Handler: s.SaveSrvInstNameTemplate})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/find/process/service_instance_name_template",
+		Handler: s.SearchSrvInstNameTemplate})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/process/service_instance_name_template",
+		Handler: s.DeleteSrvInstNameTemplate})
+	utility.AddHandler(rest.Action{Verb: http.MethodPut, Path: "/update/process/template_variable",
+		Handler: s.SaveTemplateVariable})
+	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/process/template_variable",
+		Handler: s.ListTemplateVariable})
+	utility.AddHandler(rest.Action{Verb: http.MethodDelete, Path: "/delete/process/template_variable",
+		Handler: s.DeleteTemplateVariable})
 	utility.AddHandler(rest.Action{Verb: http.MethodPost, Path: "/findmany/process/service_instance/details", Handler: s.ListServiceInstanceDetail})
 
 	// process template
@@ -0,0 +1,120 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// SaveSrvInstNameTemplate creates or replaces the business's service instance naming template, the names of the
+// existing service instances are not changed until they are rendered again.
+func (s *coreService) SaveSrvInstNameTemplate(ctx *rest.Contexts) {
+	template := new(metadata.SrvInstNameTemplate)
+	if err := ctx.DecodeInto(template); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := template.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	template.Modifier = ctx.Kit.User
+	template.LastTime = time.Now().UTC()
+	template.SupplierAccount = ctx.Kit.SupplierAccount
+
+	filter := mapstr.MapStr{common.BKAppIDField: template.BizID}
+	if err := mongodb.Client().Table(common.BKTableNameSrvInstNameTemplate).Upsert(ctx.Kit.Ctx, filter,
+		template); err != nil {
+		blog.Errorf("save service instance name template %#v failed, err: %v, rid: %s", template, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBUpdateFailed))
+		return
+	}
+
+	ctx.RespEntity(template)
+}
+
+// SearchSrvInstNameTemplate returns the business's service instance naming template, returns nil if it is not set.
+func (s *coreService) SearchSrvInstNameTemplate(ctx *rest.Contexts) {
+	opt := new(metadata.SrvInstNameTemplateOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	filter := mapstr.MapStr{common.BKAppIDField: opt.BizID}
+	template := new(metadata.SrvInstNameTemplate)
+	err := mongodb.Client().Table(common.BKTableNameSrvInstNameTemplate).Find(filter).One(ctx.Kit.Ctx, template)
+	if err != nil {
+		if mongodb.Client().IsNotFoundError(err) {
+			ctx.RespEntity(nil)
+			return
+		}
+		blog.Errorf("search service instance name template failed, filter: %v, err: %v, rid: %s", filter, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	ctx.RespEntity(template)
+}
+
+// DeleteSrvInstNameTemplate deletes the business's service instance naming template, the default naming rule is
+// used afterwards.
+func (s *coreService) DeleteSrvInstNameTemplate(ctx *rest.Contexts) {
+	opt := new(metadata.SrvInstNameTemplateOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	filter := mapstr.MapStr{common.BKAppIDField: opt.BizID}
+	if err := mongodb.Client().Table(common.BKTableNameSrvInstNameTemplate).Delete(ctx.Kit.Ctx, filter); err != nil {
+		blog.Errorf("delete service instance name template failed, filter: %v, err: %v, rid: %s", filter, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBDeleteFailed))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+// RenderServiceInstanceNames renders the names of the service instances again with the business's naming template
+func (s *coreService) RenderServiceInstanceNames(ctx *rest.Contexts) {
+	opt := new(metadata.RenderSrvInstNameOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	count, err := s.core.ProcessOperation().RenderServiceInstanceNames(ctx.Kit, opt)
+	if err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	ctx.RespEntity(metadata.CountResponseContent{Count: uint64(count)})
+}
@@ -0,0 +1,136 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/http/rest"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/storage/driver/mongodb"
+)
+
+// SaveTemplateVariable creates or replaces the template variables bound on a module or a set, the module or set is
+// not changed until it is synchronized with its template again.
+func (s *coreService) SaveTemplateVariable(ctx *rest.Contexts) {
+	variable := new(metadata.TemplateVariable)
+	if err := ctx.DecodeInto(variable); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := variable.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	instIDField := common.GetInstIDField(variable.ObjID)
+	instFilter := mapstr.MapStr{
+		common.BKAppIDField: variable.BizID,
+		instIDField:         variable.InstID,
+	}
+	count, err := mongodb.Client().Table(common.GetInstTableName(variable.ObjID, ctx.Kit.SupplierAccount)).
+		Find(instFilter).Count(ctx.Kit.Ctx)
+	if err != nil {
+		blog.Errorf("count %s instance failed, filter: %v, err: %v, rid: %s", variable.ObjID, instFilter, err,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	if count == 0 {
+		blog.Errorf("%s instance %d not exists in biz %d, rid: %s", variable.ObjID, variable.InstID, variable.BizID,
+			ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKInstIDField))
+		return
+	}
+
+	variable.Modifier = ctx.Kit.User
+	variable.LastTime = time.Now().UTC()
+	variable.SupplierAccount = ctx.Kit.SupplierAccount
+
+	filter := mapstr.MapStr{
+		common.BKAppIDField:  variable.BizID,
+		common.BKObjIDField:  variable.ObjID,
+		common.BKInstIDField: variable.InstID,
+	}
+	if err := mongodb.Client().Table(common.BKTableNameTemplateVariable).Upsert(ctx.Kit.Ctx, filter,
+		variable); err != nil {
+		blog.Errorf("save template variable %#v failed, err: %v, rid: %s", variable, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBUpdateFailed))
+		return
+	}
+
+	ctx.RespEntity(variable)
+}
+
+// ListTemplateVariable returns the template variables bound on the modules or sets, the instances without bound
+// variables are not returned.
+func (s *coreService) ListTemplateVariable(ctx *rest.Contexts) {
+	opt := new(metadata.TemplateVariableOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	filter := templateVariableFilter(opt)
+	variables := make([]metadata.TemplateVariable, 0)
+	if err := mongodb.Client().Table(common.BKTableNameTemplateVariable).Find(filter).All(ctx.Kit.Ctx,
+		&variables); err != nil {
+		blog.Errorf("list template variables failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBSelectFailed))
+		return
+	}
+
+	ctx.RespEntity(variables)
+}
+
+// DeleteTemplateVariable deletes the template variables bound on the modules or sets, the templates referencing
+// these variables can not be synchronized to them until the variables are bound again.
+func (s *coreService) DeleteTemplateVariable(ctx *rest.Contexts) {
+	opt := new(metadata.TemplateVariableOption)
+	if err := ctx.DecodeInto(opt); err != nil {
+		ctx.RespAutoError(err)
+		return
+	}
+
+	if rawErr := opt.Validate(); rawErr.ErrCode != 0 {
+		ctx.RespAutoError(rawErr.ToCCError(ctx.Kit.CCError))
+		return
+	}
+
+	filter := templateVariableFilter(opt)
+	if err := mongodb.Client().Table(common.BKTableNameTemplateVariable).Delete(ctx.Kit.Ctx, filter); err != nil {
+		blog.Errorf("delete template variables failed, filter: %v, err: %v, rid: %s", filter, err, ctx.Kit.Rid)
+		ctx.RespAutoError(ctx.Kit.CCError.CCError(common.CCErrCommDBDeleteFailed))
+		return
+	}
+
+	ctx.RespEntity(nil)
+}
+
+func templateVariableFilter(opt *metadata.TemplateVariableOption) mapstr.MapStr {
+	return mapstr.MapStr{
+		common.BKAppIDField:  opt.BizID,
+		common.BKObjIDField:  opt.ObjID,
+		common.BKInstIDField: mapstr.MapStr{common.BKDBIN: opt.InstIDs},
+	}
+}
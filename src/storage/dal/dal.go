@@ -15,6 +15,7 @@ package dal
 
 import (
 	"context"
+	"time"
 
 	"configcenter/src/common/metadata"
 	"configcenter/src/storage/dal/redis"
@@ -60,6 +61,8 @@ type DB interface {
 	CommitTransaction(context.Context, *metadata.TxnCapable) error
 	// AbortTransaction 取消事务
 	AbortTransaction(context.Context, *metadata.TxnCapable) (bool, error)
+	// AbortExpiredTransactions 强制取消超过最大存活时间仍未结束的事务
+	AbortExpiredTransactions(ctx context.Context, lifetime time.Duration) error
 
 	// InitTxnManager TxnID management of initial transaction
 	InitTxnManager(r redis.Client) error
@@ -52,6 +52,31 @@ func initMongoMetric() {
 			Buckets:   []float64{0.02, 0.04, 0.06, 0.08, 0.1, 0.3, 0.5, 0.7, 1, 5, 10, 20, 30, 60},
 		}, []string{"collection", "operation"})
 		metrics.Register().MustRegister(mtc.operDuration)
+
+		mtc.txnCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "mongo",
+			Name:      "transaction_total_count",
+			Help:      "the total count of the finished mongodb transactions",
+		}, []string{"result"})
+		metrics.Register().MustRegister(mtc.txnCount)
+
+		mtc.txnDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "mongo",
+			Name:      "transaction_duration_seconds",
+			Help:      "the cost second duration from the first operation to the end of one mongodb transaction",
+			Buckets:   []float64{0.05, 0.1, 0.3, 0.5, 1, 3, 5, 10, 20, 30, 60, 120, 300, 600},
+		}, []string{"result"})
+		metrics.Register().MustRegister(mtc.txnDuration)
+
+		mtc.txnAbortCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "mongo",
+			Name:      "transaction_abort_count",
+			Help:      "the total count of the aborted mongodb transactions with the abort reason",
+		}, []string{"reason"})
+		metrics.Register().MustRegister(mtc.txnAbortCount)
 	})
 }
 
@@ -71,6 +96,28 @@ const (
 	indexDropOper   oper = "drop_index"
 )
 
+// txnResult is the result of the finished transaction.
+type txnResult string
+
+const (
+	txnCommitted    txnResult = "commit"
+	txnCommitFailed txnResult = "commit_failed"
+	txnAborted      txnResult = "abort"
+	txnAbortFailed  txnResult = "abort_failed"
+)
+
+// txnAbortReason is the reason why the transaction is aborted.
+type txnAbortReason string
+
+const (
+	// txnAbortByError the transaction is aborted because of the failure of the operation in it.
+	txnAbortByError txnAbortReason = "error"
+	// txnAbortByWriteConflict the transaction is aborted because it conflicts with another one.
+	txnAbortByWriteConflict txnAbortReason = "write_conflict"
+	// txnAbortByLifetimeExceeded the transaction is force aborted by the watchdog because it lives too long.
+	txnAbortByLifetimeExceeded txnAbortReason = "lifetime_exceeded"
+)
+
 type mongoMetric struct {
 	// record the total operation countOper with mongodb
 	totalOperCount *prometheus.CounterVec
@@ -78,6 +125,12 @@ type mongoMetric struct {
 	totalErrorCount *prometheus.CounterVec
 	// record the operate duration with mongodb
 	operDuration *prometheus.HistogramVec
+	// record the total finished transaction count with mongodb
+	txnCount *prometheus.CounterVec
+	// record the transaction duration with mongodb
+	txnDuration *prometheus.HistogramVec
+	// record the total aborted transaction count with mongodb by the abort reason
+	txnAbortCount *prometheus.CounterVec
 }
 
 func (m *mongoMetric) collectOperCount(collection string, operation oper) {
@@ -112,3 +165,22 @@ func (m *mongoMetric) collectOperDuration(collection string, operation oper, dur
 		"operation":  string(operation),
 	}).Observe(duration.Seconds())
 }
+
+func (m *mongoMetric) collectTxnResult(result txnResult, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.txnCount.With(prometheus.Labels{"result": string(result)}).Inc()
+	if duration > 0 {
+		m.txnDuration.With(prometheus.Labels{"result": string(result)}).Observe(duration.Seconds())
+	}
+}
+
+func (m *mongoMetric) collectTxnAbortReason(reason txnAbortReason) {
+	if m == nil {
+		return
+	}
+
+	m.txnAbortCount.With(prometheus.Labels{"reason": string(reason)}).Inc()
+}
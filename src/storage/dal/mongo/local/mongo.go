@@ -23,6 +23,7 @@ import (
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
+	"configcenter/src/common/faultinject"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/util"
 	"configcenter/src/storage/dal"
@@ -314,6 +315,9 @@ var hostSpecialFieldMap = map[string]bool{
 // All 查询多个
 func (f *Find) All(ctx context.Context, result interface{}) error {
 	mtc.collectOperCount(f.collName, findOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, f.collName+"."+string(findOper)); err != nil {
+		return err
+	}
 
 	rid := ctx.Value(common.ContextRequestIDField)
 	start := time.Now()
@@ -347,6 +351,9 @@ func (f *Find) All(ctx context.Context, result interface{}) error {
 // List 查询多个数据， 当分页中start值为零的时候返回满足条件总行数
 func (f *Find) List(ctx context.Context, result interface{}) (int64, error) {
 	mtc.collectOperCount(f.collName, findOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, f.collName+"."+string(findOper)); err != nil {
+		return 0, err
+	}
 
 	rid := ctx.Value(common.ContextRequestIDField)
 	start := time.Now()
@@ -390,6 +397,9 @@ func (f *Find) List(ctx context.Context, result interface{}) (int64, error) {
 // One 查询一个
 func (f *Find) One(ctx context.Context, result interface{}) error {
 	mtc.collectOperCount(f.collName, findOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, f.collName+"."+string(findOper)); err != nil {
+		return err
+	}
 
 	start := time.Now()
 	rid := ctx.Value(common.ContextRequestIDField)
@@ -426,9 +436,93 @@ func (f *Find) One(ctx context.Context, result interface{}) error {
 
 }
 
+// explainOutput is the output of the explain command with the executionStats verbosity
+type explainOutput struct {
+	QueryPlanner   bson.M `bson:"queryPlanner"`
+	ExecutionStats struct {
+		ExecutionTimeMillis int64 `bson:"executionTimeMillis"`
+		NReturned           int64 `bson:"nReturned"`
+		TotalKeysExamined   int64 `bson:"totalKeysExamined"`
+		TotalDocsExamined   int64 `bson:"totalDocsExamined"`
+	} `bson:"executionStats"`
+}
+
+// Explain 获取查询的执行计划及执行统计(非事务)
+// explain can not be run in a transaction, and the query is executed by the explain command to collect the stats.
+func (f *Find) Explain(ctx context.Context) (*types.ExplainResult, error) {
+	if f.filter == nil {
+		f.filter = bson.M{}
+	}
+
+	findCmd := bson.D{{"find", f.collName}, {"filter", f.filter}}
+	if len(f.projection) > 0 {
+		findCmd = append(findCmd, bson.E{"projection", f.projection})
+	}
+	if len(f.sort) > 0 {
+		findCmd = append(findCmd, bson.E{"sort", f.sort})
+	}
+	if f.start > 0 {
+		findCmd = append(findCmd, bson.E{"skip", f.start})
+	}
+	if f.limit > 0 {
+		findCmd = append(findCmd, bson.E{"limit", f.limit})
+	}
+
+	cmdOpt := options.RunCmd()
+	if collOpt := getCollectionOption(ctx); collOpt != nil && collOpt.ReadPreference != nil {
+		cmdOpt.SetReadPreference(collOpt.ReadPreference)
+	}
+
+	cmd := bson.D{{"explain", findCmd}, {"verbosity", "executionStats"}}
+	output := new(explainOutput)
+	if err := f.dbc.Database(f.dbname).RunCommand(ctx, cmd, cmdOpt).Decode(output); err != nil {
+		return nil, err
+	}
+
+	result := &types.ExplainResult{
+		Indexes:             make([]string, 0),
+		WinningPlan:         output.QueryPlanner["winningPlan"],
+		ExecutionTimeMillis: output.ExecutionStats.ExecutionTimeMillis,
+		TotalKeysExamined:   output.ExecutionStats.TotalKeysExamined,
+		TotalDocsExamined:   output.ExecutionStats.TotalDocsExamined,
+		Returned:            output.ExecutionStats.NReturned,
+	}
+	// the index scan stages may be nested in the plan, e.g. under the shards' plans of a sharded cluster
+	collectPlanIndexes(output.QueryPlanner["winningPlan"], &result.Indexes)
+	result.Indexes = util.StrArrayUnique(result.Indexes)
+
+	return result, nil
+}
+
+// collectPlanIndexes collect the index names of the index scan stages in the query plan recursively
+func collectPlanIndexes(plan interface{}, indexes *[]string) {
+	switch value := plan.(type) {
+	case bson.M:
+		if indexName, ok := value["indexName"].(string); ok {
+			*indexes = append(*indexes, indexName)
+		}
+		for _, child := range value {
+			collectPlanIndexes(child, indexes)
+		}
+	case bson.D:
+		collectPlanIndexes(value.Map(), indexes)
+	case bson.A:
+		for _, child := range value {
+			collectPlanIndexes(child, indexes)
+		}
+	case []interface{}:
+		for _, child := range value {
+			collectPlanIndexes(child, indexes)
+		}
+	}
+}
+
 // Count 统计数量(非事务)
 func (f *Find) Count(ctx context.Context) (uint64, error) {
 	mtc.collectOperCount(f.collName, countOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, f.collName+"."+string(countOper)); err != nil {
+		return 0, err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -472,6 +566,9 @@ func (f *Find) Count(ctx context.Context) (uint64, error) {
 // Insert 插入数据, docs 可以为 单个数据 或者 多个数据
 func (c *Collection) Insert(ctx context.Context, docs interface{}) error {
 	mtc.collectOperCount(c.collName, insertOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(insertOper)); err != nil {
+		return err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -494,6 +591,9 @@ func (c *Collection) Insert(ctx context.Context, docs interface{}) error {
 // Update 更新数据
 func (c *Collection) Update(ctx context.Context, filter types.Filter, doc interface{}) error {
 	mtc.collectOperCount(c.collName, updateOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(updateOper)); err != nil {
+		return err
+	}
 	start := time.Now()
 	defer func() {
 		mtc.collectOperDuration(c.collName, updateOper, time.Since(start))
@@ -518,6 +618,9 @@ func (c *Collection) Update(ctx context.Context, filter types.Filter, doc interf
 // Update 更新数据, 返回修改成功的条数
 func (c *Collection) UpdateMany(ctx context.Context, filter types.Filter, doc interface{}) (uint64, error) {
 	mtc.collectOperCount(c.collName, updateOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(updateOper)); err != nil {
+		return 0, err
+	}
 	start := time.Now()
 	defer func() {
 		mtc.collectOperDuration(c.collName, updateOper, time.Since(start))
@@ -545,6 +648,9 @@ func (c *Collection) UpdateMany(ctx context.Context, filter types.Filter, doc in
 // 注意：该接口非原子操作，可能存在插入多条相同数据的风险。
 func (c *Collection) Upsert(ctx context.Context, filter types.Filter, doc interface{}) error {
 	mtc.collectOperCount(c.collName, upsertOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(upsertOper)); err != nil {
+		return err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -568,9 +674,43 @@ func (c *Collection) Upsert(ctx context.Context, filter types.Filter, doc interf
 
 }
 
+// InsertIfAbsent 数据不存在时插入数据，存在时不做修改，返回是否插入了数据。
+// 注意：filter需要命中唯一索引才能保证并发时不会插入多条相同数据。
+func (c *Collection) InsertIfAbsent(ctx context.Context, filter types.Filter, doc interface{}) (bool, error) {
+	mtc.collectOperCount(c.collName, upsertOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(upsertOper)); err != nil {
+		return false, err
+	}
+
+	start := time.Now()
+	defer func() {
+		mtc.collectOperDuration(c.collName, upsertOper, time.Since(start))
+	}()
+
+	doUpsert := true
+	upsertOpt := &options.UpdateOptions{
+		Upsert: &doUpsert,
+	}
+	data := bson.M{"$setOnInsert": doc}
+	inserted := false
+	err := c.tm.AutoRunWithTxn(ctx, c.dbc, func(ctx context.Context) error {
+		ret, err := c.dbc.Database(c.dbname).Collection(c.collName).UpdateOne(ctx, filter, data, upsertOpt)
+		if err != nil {
+			mtc.collectErrorCount(c.collName, upsertOper)
+			return err
+		}
+		inserted = ret.UpsertedCount > 0
+		return nil
+	})
+	return inserted, err
+}
+
 // UpdateMultiModel 根据不同的操作符去更新数据
 func (c *Collection) UpdateMultiModel(ctx context.Context, filter types.Filter, updateModel ...types.ModeUpdate) error {
 	mtc.collectOperCount(c.collName, updateOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(updateOper)); err != nil {
+		return err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -606,6 +746,9 @@ func (c *Collection) Delete(ctx context.Context, filter types.Filter) error {
 // Delete 删除数据， 返回删除的行数
 func (c *Collection) DeleteMany(ctx context.Context, filter types.Filter) (uint64, error) {
 	mtc.collectOperCount(c.collName, deleteOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(deleteOper)); err != nil {
+		return 0, err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -822,6 +965,9 @@ func (c *Mongo) RenameTable(ctx context.Context, prevName, currName string) erro
 // CreateIndex 创建索引
 func (c *Collection) CreateIndex(ctx context.Context, index types.Index) error {
 	mtc.collectOperCount(c.collName, indexCreateOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(indexCreateOper)); err != nil {
+		return err
+	}
 
 	createIndexOpt := &options.IndexOptions{
 		Background:              &index.Background,
@@ -870,6 +1016,9 @@ func (c *Collection) CreateIndex(ctx context.Context, index types.Index) error {
 // DropIndex remove index by name
 func (c *Collection) DropIndex(ctx context.Context, indexName string) error {
 	mtc.collectOperCount(c.collName, indexDropOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(indexDropOper)); err != nil {
+		return err
+	}
 	indexView := c.dbc.Database(c.dbname).Collection(c.collName).Indexes()
 	_, err := indexView.DropOne(ctx, indexName)
 	if err != nil {
@@ -903,6 +1052,9 @@ func (c *Collection) Indexes(ctx context.Context) ([]types.Index, error) {
 // AddColumn add a new column for the collection
 func (c *Collection) AddColumn(ctx context.Context, column string, value interface{}) error {
 	mtc.collectOperCount(c.collName, columnOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(columnOper)); err != nil {
+		return err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -924,6 +1076,9 @@ func (c *Collection) AddColumn(ctx context.Context, column string, value interfa
 // RenameColumn rename a column for the collection
 func (c *Collection) RenameColumn(ctx context.Context, filter types.Filter, oldName, newColumn string) error {
 	mtc.collectOperCount(c.collName, columnOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(columnOper)); err != nil {
+		return err
+	}
 	if filter == nil {
 		filter = dtype.Document{}
 	}
@@ -948,6 +1103,9 @@ func (c *Collection) RenameColumn(ctx context.Context, filter types.Filter, oldN
 // DropColumn remove a column by the name
 func (c *Collection) DropColumn(ctx context.Context, field string) error {
 	mtc.collectOperCount(c.collName, columnOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(columnOper)); err != nil {
+		return err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -969,6 +1127,9 @@ func (c *Collection) DropColumn(ctx context.Context, field string) error {
 // DropColumns remove many columns by the name
 func (c *Collection) DropColumns(ctx context.Context, filter types.Filter, fields []string) error {
 	mtc.collectOperCount(c.collName, columnOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(columnOper)); err != nil {
+		return err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -995,6 +1156,9 @@ func (c *Collection) DropColumns(ctx context.Context, filter types.Filter, field
 // DropDocsColumn remove a column by the name for doc use filter
 func (c *Collection) DropDocsColumn(ctx context.Context, field string, filter types.Filter) error {
 	mtc.collectOperCount(c.collName, columnOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(columnOper)); err != nil {
+		return err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -1023,6 +1187,9 @@ func (c *Collection) AggregateAll(ctx context.Context, pipeline interface{}, res
 	opts ...*types.AggregateOpts) error {
 
 	mtc.collectOperCount(c.collName, aggregateOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(aggregateOper)); err != nil {
+		return err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -1056,6 +1223,9 @@ func (c *Collection) AggregateAll(ctx context.Context, pipeline interface{}, res
 // AggregateOne aggregate one operation
 func (c *Collection) AggregateOne(ctx context.Context, pipeline interface{}, result interface{}) error {
 	mtc.collectOperCount(c.collName, aggregateOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(aggregateOper)); err != nil {
+		return err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -1085,6 +1255,9 @@ func (c *Collection) AggregateOne(ctx context.Context, pipeline interface{}, res
 // filter query that specifies the documents from which to retrieve the distinct values.
 func (c *Collection) Distinct(ctx context.Context, field string, filter types.Filter) ([]interface{}, error) {
 	mtc.collectOperCount(c.collName, distinctOper)
+	if err := faultinject.Inject(ctx, faultinject.Mongo, c.collName+"."+string(distinctOper)); err != nil {
+		return nil, err
+	}
 
 	start := time.Now()
 	defer func() {
@@ -15,6 +15,7 @@ package local
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
@@ -42,7 +43,7 @@ func (c *Mongo) CommitTransaction(ctx context.Context, cap *metadata.TxnCapable)
 		return nil
 	}
 
-	reloadSession, err := c.tm.PrepareTransaction(cap, c.dbc)
+	reloadSession, err := c.tm.PrepareTransaction(ctx, cap, c.dbc)
 	if err != nil {
 		blog.Errorf("commit transaction, but prepare transaction failed, err: %v, rid: %v", err, rid)
 		return err
@@ -57,8 +58,10 @@ func (c *Mongo) CommitTransaction(ctx context.Context, cap *metadata.TxnCapable)
 	// we commit the transaction with the session id
 	err = reloadSession.CommitTransaction(ctx)
 	if err != nil {
+		c.finishTxn(cap.SessionID, txnCommitFailed, rid)
 		return fmt.Errorf("commit transaction: %s failed, err: %v, rid: %v", cap.SessionID, err, rid)
 	}
+	c.finishTxn(cap.SessionID, txnCommitted, rid)
 
 	err = c.tm.RemoveSessionKey(cap.SessionID)
 	if err != nil {
@@ -72,8 +75,15 @@ func (c *Mongo) CommitTransaction(ctx context.Context, cap *metadata.TxnCapable)
 
 // AbortTransaction 取消事务
 func (c *Mongo) AbortTransaction(ctx context.Context, cap *metadata.TxnCapable) (bool, error) {
+	return c.abortTransaction(ctx, cap, "")
+}
+
+// abortTransaction aborts the transaction, the abort reason is decided by the transaction error if it is not set.
+func (c *Mongo) abortTransaction(ctx context.Context, cap *metadata.TxnCapable, reason txnAbortReason) (bool,
+	error) {
+
 	rid := ctx.Value(common.ContextRequestIDField)
-	reloadSession, err := c.tm.PrepareTransaction(cap, c.dbc)
+	reloadSession, err := c.tm.PrepareTransaction(ctx, cap, c.dbc)
 	if err != nil {
 		blog.Errorf("abort transaction, but prepare transaction failed, err: %v, rid: %v", err, rid)
 		return false, err
@@ -88,8 +98,10 @@ func (c *Mongo) AbortTransaction(ctx context.Context, cap *metadata.TxnCapable)
 	// we abort the transaction with the session id
 	err = reloadSession.AbortTransaction(ctx)
 	if err != nil {
+		c.finishTxn(cap.SessionID, txnAbortFailed, rid)
 		return false, fmt.Errorf("abort transaction: %s failed, err: %v, rid: %v", cap.SessionID, err, rid)
 	}
+	c.finishTxn(cap.SessionID, txnAborted, rid)
 
 	err = c.tm.RemoveSessionKey(cap.SessionID)
 	if err != nil {
@@ -98,12 +110,65 @@ func (c *Mongo) AbortTransaction(ctx context.Context, cap *metadata.TxnCapable)
 		// do not return.
 	}
 
+	if len(reason) != 0 {
+		mtc.collectTxnAbortReason(reason)
+		return false, nil
+	}
+
 	errorType := c.tm.GetTxnError(sessionKey(cap.SessionID))
 	switch errorType {
 	// retry when the transaction error type is write conflict, which means the transaction conflicts with another one
 	case WriteConflictType:
+		mtc.collectTxnAbortReason(txnAbortByWriteConflict)
 		return true, nil
 	}
 
+	mtc.collectTxnAbortReason(txnAbortByError)
 	return false, nil
 }
+
+// finishTxn removes the finished transaction from the active transactions and records its result.
+func (c *Mongo) finishTxn(sessionID string, result txnResult, rid interface{}) {
+	duration, err := c.tm.RemoveActiveTxn(sessionID)
+	if err != nil {
+		// the active transaction is force aborted by the watchdog later, it's ok if we not delete it.
+		blog.Errorf("remove active transaction %s failed, err: %v, rid: %v", sessionID, err, rid)
+	}
+	mtc.collectTxnResult(result, duration)
+}
+
+// AbortExpiredTransactions force aborts the transactions that are not committed or aborted after the lifetime, which
+// may be stuck and hold the locks of the documents they operated.
+func (c *Mongo) AbortExpiredTransactions(ctx context.Context, lifetime time.Duration) error {
+	actives, err := c.tm.ListActiveTxn()
+	if err != nil {
+		return fmt.Errorf("list active transactions failed, err: %v", err)
+	}
+
+	now := time.Now()
+	for sessionID, active := range actives {
+		startTime := time.Unix(0, active.StartTime*int64(time.Millisecond))
+		if now.Sub(startTime) < lifetime {
+			continue
+		}
+
+		// abort the transaction with the request id that starts it, so that it can be traced.
+		txnCtx := context.WithValue(ctx, common.ContextRequestIDField, active.Rid)
+		blog.Warnf("transaction %s started at %s exceeds max lifetime %s, force abort it, rid: %s", sessionID,
+			startTime.Format(time.RFC3339), lifetime, active.Rid)
+
+		cap := &metadata.TxnCapable{Timeout: common.TransactionDefaultTimeout, SessionID: sessionID}
+		if _, err := c.abortTransaction(txnCtx, cap, txnAbortByLifetimeExceeded); err != nil {
+			// the transaction may be already expired in mongodb, remove it so that it is not aborted again.
+			blog.Errorf("force abort transaction %s failed, err: %v, rid: %s", sessionID, err, active.Rid)
+			if _, err := c.tm.RemoveActiveTxn(sessionID); err != nil {
+				blog.Errorf("remove active transaction %s failed, err: %v, rid: %s", sessionID, err, active.Rid)
+			}
+			if err := c.tm.RemoveSessionKey(sessionID); err != nil {
+				blog.Errorf("remove transaction %s key failed, err: %v, rid: %s", sessionID, err, active.Rid)
+			}
+		}
+	}
+
+	return nil
+}
@@ -15,6 +15,7 @@ package local
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -25,6 +26,7 @@ import (
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
 	"configcenter/src/storage/dal/redis"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -34,8 +36,25 @@ import (
 const (
 	transactionNumberRedisKeyNamespace = common.BKCacheKeyV3Prefix + "transaction:number:"
 	transactionErrorRedisKeyNamespace  = common.BKCacheKeyV3Prefix + "transaction:error:"
+	// transactionActiveRedisKey is the hash of the transactions that are not committed or aborted yet, the field is
+	// the session id, and the value is the activeTxn.
+	transactionActiveRedisKey = common.BKCacheKeyV3Prefix + "transaction:active"
+	// transactionSuppressEventRedisKeyNamespace is the key namespace of the sessions whose transactions are marked
+	// to suppress the watch events.
+	transactionSuppressEventRedisKeyNamespace = common.BKCacheKeyV3Prefix + "transaction:suppress_event:"
+	// transactionSuppressEventTTL is how long the suppress event mark is kept, the events of the transaction are
+	// generated after it is committed, so the mark needs to outlive the transaction until the events are handled.
+	transactionSuppressEventTTL = 24 * time.Hour
 )
 
+// activeTxn is the transaction that is not committed or aborted yet.
+type activeTxn struct {
+	// StartTime the unix milliseconds of the transaction's first operation.
+	StartTime int64 `json:"start_time"`
+	// Rid the request id of the request that starts the transaction.
+	Rid string `json:"rid"`
+}
+
 type sessionKey string
 
 func (s sessionKey) genKey() string {
@@ -46,6 +65,10 @@ func (s sessionKey) genErrKey() string {
 	return transactionErrorRedisKeyNamespace + string(s)
 }
 
+func (s sessionKey) genSuppressEventKey() string {
+	return transactionSuppressEventRedisKeyNamespace + string(s)
+}
+
 // TxnErrorType the error type of the transaction, some error type needs to do special operations like retry
 type TxnErrorType string
 
@@ -78,8 +101,9 @@ func (t *TxnManager) GetTxnNumber(sessionID string) (int64, error) {
 	return strconv.ParseInt(v, 10, 64)
 }
 
-// GenTxnNumber generate the transaction number from redis.
-func (t *TxnManager) GenTxnNumber(sessionID string, ttl time.Duration) (int64, error) {
+// GenTxnNumber generate the transaction number from redis, and records the transaction as active if it is the
+// first operation of the transaction.
+func (t *TxnManager) GenTxnNumber(sessionID, rid string, ttl time.Duration) (int64, error) {
 	// return txnNumber with 1 directly, when our mongodb client option's RetryWrite
 	// is set to false.
 	key := sessionKey(sessionID).genKey()
@@ -93,7 +117,12 @@ func (t *TxnManager) GenTxnNumber(sessionID string, ttl time.Duration) (int64, e
 	// be executed in a same session.
 	pip.SetNX(key, 0, ttl).Result()
 	incrBy := pip.IncrBy(key, 1)
-	_, err := pip.Exec()
+	active, err := json.Marshal(activeTxn{StartTime: time.Now().UnixNano() / int64(time.Millisecond), Rid: rid})
+	if err != nil {
+		return 0, err
+	}
+	pip.HSetNX(transactionActiveRedisKey, sessionID, string(active))
+	_, err = pip.Exec()
 	if err != nil {
 		return 0, err
 	}
@@ -103,12 +132,89 @@ func (t *TxnManager) GenTxnNumber(sessionID string, ttl time.Duration) (int64, e
 	return num, nil
 }
 
+// MarkSuppressEvent marks the transaction of the session to suppress the watch events.
+func (t *TxnManager) MarkSuppressEvent(sessionID string) error {
+	key := sessionKey(sessionID).genSuppressEventKey()
+	return t.cache.Set(context.Background(), key, 1, transactionSuppressEventTTL).Err()
+}
+
+// ListSuppressEventSessions returns the sessions among the given sessions whose transactions are marked to suppress
+// the watch events.
+func ListSuppressEventSessions(ctx context.Context, cache redis.Client, sessionIDs []string) (map[string]struct{},
+	error) {
+
+	suppressed := make(map[string]struct{})
+	if len(sessionIDs) == 0 {
+		return suppressed, nil
+	}
+
+	keys := make([]string, len(sessionIDs))
+	for idx, sessionID := range sessionIDs {
+		keys[idx] = sessionKey(sessionID).genSuppressEventKey()
+	}
+
+	values, err := cache.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for idx, value := range values {
+		if value != nil {
+			suppressed[sessionIDs[idx]] = struct{}{}
+		}
+	}
+	return suppressed, nil
+}
+
 // RemoveSessionKey remove transaction session key
 func (t *TxnManager) RemoveSessionKey(sessionID string) error {
 	key := sessionKey(sessionID).genKey()
 	return t.cache.Del(context.Background(), key).Err()
 }
 
+// RemoveActiveTxn remove the transaction from the active transactions, returns how long the transaction lives, which
+// is 0 if the transaction is not found.
+func (t *TxnManager) RemoveActiveTxn(sessionID string) (time.Duration, error) {
+	ctx := context.Background()
+	value, err := t.cache.HGet(ctx, transactionActiveRedisKey, sessionID).Result()
+	if err != nil {
+		if redis.IsNilErr(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if err := t.cache.HDel(ctx, transactionActiveRedisKey, sessionID).Err(); err != nil {
+		return 0, err
+	}
+
+	active := new(activeTxn)
+	if err := json.Unmarshal([]byte(value), active); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(time.Now().UnixNano()/int64(time.Millisecond)-active.StartTime) * time.Millisecond, nil
+}
+
+// ListActiveTxn list the transactions that are not committed or aborted yet, the key is the session id.
+func (t *TxnManager) ListActiveTxn() (map[string]activeTxn, error) {
+	values, err := t.cache.HGetAll(context.Background(), transactionActiveRedisKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]activeTxn, len(values))
+	for sessionID, value := range values {
+		active := activeTxn{}
+		if err := json.Unmarshal([]byte(value), &active); err != nil {
+			blog.Errorf("unmarshal active transaction %s failed, value: %s, err: %v", sessionID, value, err)
+			continue
+		}
+		result[sessionID] = active
+	}
+	return result, nil
+}
+
 // ReloadSession is used to reset a created session's session id
 func (t *TxnManager) ReloadSession(sess mongo.Session, info *SessionInfo) (mongo.Session, error) {
 	err := CmdbReloadSession(sess, info)
@@ -129,7 +235,8 @@ func (t *TxnManager) PrepareCommit(cli *mongo.Client) (mongo.Session, error) {
 }
 
 // PrepareTransaction prepare transaction
-func (t *TxnManager) PrepareTransaction(cap *metadata.TxnCapable, cli *mongo.Client) (mongo.Session, error) {
+func (t *TxnManager) PrepareTransaction(ctx context.Context, cap *metadata.TxnCapable, cli *mongo.Client) (
+	mongo.Session, error) {
 	// create a session client.
 	sess, err := cli.StartSession()
 	if err != nil {
@@ -142,11 +249,18 @@ func (t *TxnManager) PrepareTransaction(cap *metadata.TxnCapable, cli *mongo.Cli
 		return nil, fmt.Errorf("start transaction %s failed: %v", cap.SessionID, err)
 	}
 
-	txnNumber, err := t.GenTxnNumber(cap.SessionID, cap.Timeout)
+	txnNumber, err := t.GenTxnNumber(cap.SessionID, util.ExtractRequestIDFromContext(ctx), cap.Timeout)
 	if err != nil {
 		return nil, fmt.Errorf("generate txn number failed, err: %v", err)
 	}
 
+	// mark the transaction at its first operation, before any change of it is made.
+	if cap.SuppressEvent && txnNumber == 1 {
+		if err := t.MarkSuppressEvent(cap.SessionID); err != nil {
+			return nil, fmt.Errorf("mark transaction %s to suppress event failed, err: %v", cap.SessionID, err)
+		}
+	}
+
 	// reset the session info with the session id.
 	info := &SessionInfo{
 		TxnNubmer: txnNumber,
@@ -176,7 +290,7 @@ func (t *TxnManager) GetTxnContext(ctx context.Context, cli *mongo.Client) (cont
 		return ctx, nil, false, nil
 	}
 
-	session, err := t.PrepareTransaction(cap, cli)
+	session, err := t.PrepareTransaction(ctx, cap, cli)
 	if err != nil {
 		return ctx, nil, true, err
 	}
@@ -218,10 +332,13 @@ func parseTxnInfoFromCtx(txnCtx context.Context) (*metadata.TxnCapable, bool, er
 		return nil, false, fmt.Errorf("invalid transaction timeout value, parse %v failed, err: %v", ttl, err)
 	}
 
+	suppressEvent, _ := txnCtx.Value(common.TransactionSuppressEventHeader).(string)
+
 	cap := &metadata.TxnCapable{
 		// timeout is not
-		Timeout:   time.Duration(timeout),
-		SessionID: txnID,
+		Timeout:       time.Duration(timeout),
+		SessionID:     txnID,
+		SuppressEvent: suppressEvent == "true",
 	}
 	return cap, true, nil
 }
@@ -238,7 +355,7 @@ func (t *TxnManager) AutoRunWithTxn(ctx context.Context, cli *mongo.Client, cmd
 		return cmd(ctx)
 	}
 
-	session, err := t.PrepareTransaction(cap, cli)
+	session, err := t.PrepareTransaction(ctx, cap, cli)
 	if err != nil {
 		return err
 	}
@@ -308,7 +425,9 @@ func GenTxnCableAndSetHeader(header http.Header, opts ...metadata.TxnOption) (*m
 		return nil, fmt.Errorf("generate session id failed, err: %v", err)
 	}
 	var timeout time.Duration
+	suppressEvent := false
 	if len(opts) != 0 {
+		suppressEvent = opts[0].SuppressEvent
 		if opts[0].Timeout < 30*time.Second {
 			timeout = common.TransactionDefaultTimeout
 		} else {
@@ -321,10 +440,16 @@ func GenTxnCableAndSetHeader(header http.Header, opts ...metadata.TxnOption) (*m
 
 	header.Set(common.TransactionIdHeader, sessionID)
 	header.Set(common.TransactionTimeoutHeader, strconv.FormatInt(int64(timeout), 10))
+	if suppressEvent {
+		header.Set(common.TransactionSuppressEventHeader, "true")
+	} else {
+		header.Del(common.TransactionSuppressEventHeader)
+	}
 
 	cap := metadata.TxnCapable{
-		Timeout:   timeout,
-		SessionID: sessionID,
+		Timeout:       timeout,
+		SessionID:     sessionID,
+		SuppressEvent: suppressEvent,
 	}
 	return &cap, nil
 }
@@ -0,0 +1,154 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"configcenter/src/common/blog"
+)
+
+const (
+	// DefaultProbeInterval the default interval of probing the redis.
+	DefaultProbeInterval = 3 * time.Second
+	// DefaultProbeTimeout the default timeout of one probe.
+	DefaultProbeTimeout = time.Second
+	// DefaultProbeFailureThreshold the default number of consecutive failed probes to mark the redis unavailable.
+	DefaultProbeFailureThreshold = 3
+)
+
+// AvailabilityConfig is the config of probing the redis availability.
+type AvailabilityConfig struct {
+	// ProbeInterval the interval of probing the redis.
+	ProbeInterval time.Duration
+	// ProbeTimeout the timeout of one probe.
+	ProbeTimeout time.Duration
+	// FailureThreshold the number of consecutive failed probes to mark the redis unavailable, the redis is marked
+	// available again once a probe succeeds.
+	FailureThreshold int
+}
+
+// Availability tracks whether the redis is available by probing it periodically. The features that depend on the
+// redis (e.g. the caches and the buffers) check it to fall back to the degraded paths while the redis is down,
+// instead of failing or waiting for the timeout of every request.
+type Availability struct {
+	name  string
+	probe func(ctx context.Context) error
+	conf  AvailabilityConfig
+
+	lock      sync.RWMutex
+	available bool
+	failures  int
+	lastErr   error
+	since     time.Time
+}
+
+// NewAvailability new the availability of the redis client, the redis is regarded as available until the probes
+// fail, the probes starts after Run is called.
+func NewAvailability(name string, client Client, conf AvailabilityConfig) *Availability {
+	return newAvailability(name, func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}, conf)
+}
+
+func newAvailability(name string, probe func(ctx context.Context) error, conf AvailabilityConfig) *Availability {
+	if conf.ProbeInterval <= 0 {
+		conf.ProbeInterval = DefaultProbeInterval
+	}
+	if conf.ProbeTimeout <= 0 {
+		conf.ProbeTimeout = DefaultProbeTimeout
+	}
+	if conf.FailureThreshold <= 0 {
+		conf.FailureThreshold = DefaultProbeFailureThreshold
+	}
+
+	return &Availability{
+		name:      name,
+		probe:     probe,
+		conf:      conf,
+		available: true,
+		since:     time.Now(),
+	}
+}
+
+// Run probes the redis until the context is done.
+func (a *Availability) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.conf.ProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		a.probeOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *Availability) probeOnce(ctx context.Context) {
+	probeCtx, cancel := context.WithTimeout(ctx, a.conf.ProbeTimeout)
+	err := a.probe(probeCtx)
+	cancel()
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err == nil {
+		a.failures = 0
+		a.lastErr = nil
+		if !a.available {
+			blog.Infof("redis %s is available again after %s, recover from the degraded mode", a.name,
+				time.Since(a.since))
+			a.available = true
+			a.since = time.Now()
+		}
+		return
+	}
+
+	a.failures++
+	a.lastErr = err
+	if a.available && a.failures >= a.conf.FailureThreshold {
+		blog.Errorf("redis %s is unavailable after %d failed probes, switch to the degraded mode, err: %v", a.name,
+			a.failures, err)
+		a.available = false
+		a.since = time.Now()
+	}
+}
+
+// Available returns whether the redis is available, a nil availability means the redis is not tracked, which is
+// regarded as available.
+func (a *Availability) Available() bool {
+	if a == nil {
+		return true
+	}
+
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.available
+}
+
+// Status returns whether the redis is available, since when the redis is in the current status, and the error of
+// the last failed probe, a nil availability is regarded as available.
+func (a *Availability) Status() (bool, time.Time, error) {
+	if a == nil {
+		return true, time.Time{}, nil
+	}
+
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.available, a.since, a.lastErr
+}
@@ -0,0 +1,63 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvailability(t *testing.T) {
+	var probeErr error
+	a := newAvailability("test", func(ctx context.Context) error {
+		return probeErr
+	}, AvailabilityConfig{FailureThreshold: 2})
+	assert.True(t, a.Available())
+
+	// the redis is marked unavailable after the consecutive failures reach the threshold
+	probeErr = errors.New("connection refused")
+	a.probeOnce(context.Background())
+	assert.True(t, a.Available())
+	a.probeOnce(context.Background())
+	assert.False(t, a.Available())
+	available, since, err := a.Status()
+	assert.False(t, available)
+	assert.False(t, since.IsZero())
+	assert.Equal(t, probeErr, err)
+
+	// one successful probe recovers the redis
+	probeErr = nil
+	a.probeOnce(context.Background())
+	assert.True(t, a.Available())
+	_, _, err = a.Status()
+	assert.NoError(t, err)
+
+	// the failures that are not consecutive do not mark the redis unavailable
+	probeErr = errors.New("i/o timeout")
+	a.probeOnce(context.Background())
+	probeErr = nil
+	a.probeOnce(context.Background())
+	probeErr = errors.New("i/o timeout")
+	a.probeOnce(context.Background())
+	assert.True(t, a.Available())
+
+	// the untracked redis is regarded as available
+	var untracked *Availability
+	assert.True(t, untracked.Available())
+	available, _, err = untracked.Status()
+	assert.True(t, available)
+	assert.NoError(t, err)
+}
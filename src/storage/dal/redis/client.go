@@ -33,15 +33,19 @@ type client struct {
 
 // NewClient returns a client to the Redis Server specified by Options
 func NewClient(opt *redis.Options) Client {
+	cli := redis.NewClient(opt)
+	cli.AddHook(faultInjectHook{})
 	return &client{
-		cli: redis.NewClient(opt),
+		cli: cli,
 	}
 }
 
 // NewFailoverClient returns a Redis client that uses Redis Sentinel for automatic failover
 func NewFailoverClient(failoverOpt *redis.FailoverOptions) Client {
+	cli := redis.NewFailoverClient(failoverOpt)
+	cli.AddHook(faultInjectHook{})
 	return &client{
-		cli: redis.NewFailoverClient(failoverOpt),
+		cli: cli,
 	}
 }
 
@@ -0,0 +1,57 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"configcenter/src/common/faultinject"
+
+	"github.com/go-redis/redis/v7"
+)
+
+// faultInjectHook injects the faults into the redis commands by the fault injection rules
+type faultInjectHook struct{}
+
+// BeforeProcess injects the fault before the command is processed, the command fails with the injected error.
+func (faultInjectHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, faultinject.Inject(ctx, faultinject.Redis, cmdResource(cmd))
+}
+
+// AfterProcess TODO
+func (faultInjectHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	return nil
+}
+
+// BeforeProcessPipeline injects the fault before the pipeline is processed by its first command.
+func (faultInjectHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	if len(cmds) == 0 {
+		return ctx, nil
+	}
+	return ctx, faultinject.Inject(ctx, faultinject.Redis, cmdResource(cmds[0]))
+}
+
+// AfterProcessPipeline TODO
+func (faultInjectHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	return nil
+}
+
+// cmdResource returns the fault injection resource of the command, which is "<command>:<key>".
+func cmdResource(cmd redis.Cmder) string {
+	args := cmd.Args()
+	if len(args) < 2 {
+		return cmd.Name()
+	}
+	return fmt.Sprintf("%s:%v", cmd.Name(), args[1])
+}
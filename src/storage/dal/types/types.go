@@ -49,6 +49,8 @@ type Table interface {
 	// Upsert TODO
 	// update or insert data
 	Upsert(ctx context.Context, filter Filter, doc interface{}) error
+	// InsertIfAbsent atomically inserts the doc if no data matches the filter, returns whether the doc is inserted.
+	InsertIfAbsent(ctx context.Context, filter Filter, doc interface{}) (bool, error)
 	// UpdateMultiModel  data based on operators.
 	UpdateMultiModel(ctx context.Context, filter Filter, updateModel ...ModeUpdate) error
 
@@ -103,10 +105,24 @@ type Find interface {
 	Count(ctx context.Context) (uint64, error)
 	// List 查询多个, start 等于0的时候，返回满足条件的行数
 	List(ctx context.Context, result interface{}) (int64, error)
+	// Explain 获取查询的执行计划及执行统计(非事务)
+	Explain(ctx context.Context) (*ExplainResult, error)
 
 	Option(opts ...*FindOpts)
 }
 
+// ExplainResult is the execution plan of a find operation chosen by the mongodb query planner, with its execution
+// stats.
+type ExplainResult struct {
+	// Indexes the names of the indexes used by the winning plan, it is empty if the collection is scanned.
+	Indexes             []string    `json:"indexes"`
+	WinningPlan         interface{} `json:"winning_plan"`
+	ExecutionTimeMillis int64       `json:"execution_time_millis"`
+	TotalKeysExamined   int64       `json:"total_keys_examined"`
+	TotalDocsExamined   int64       `json:"total_docs_examined"`
+	Returned            int64       `json:"returned"`
+}
+
 // ModeUpdate  根据不同的操作符去更新数据
 type ModeUpdate struct {
 	Op  string
@@ -43,6 +43,9 @@ var (
 
 	// IsNilErr TODO
 	IsNilErr = redis.IsNilErr
+
+	availability     *redis.Availability
+	availabilityLock sync.Mutex
 )
 
 // Client  get default error
@@ -52,6 +55,25 @@ func Client() redis.Client {
 	return defaultClient
 }
 
+// Availability returns the availability of the default redis, the probes are started on the first call so that only
+// the services that have the degraded paths probe the redis. It returns nil if the default redis is not initialized.
+func Availability() *redis.Availability {
+	availabilityLock.Lock()
+	defer availabilityLock.Unlock()
+
+	if availability != nil {
+		return availability
+	}
+
+	client := Client()
+	if client == nil {
+		return nil
+	}
+	availability = redis.NewAvailability(defaultPrefix, client, redis.AvailabilityConfig{})
+	go availability.Run(context.Background())
+	return availability
+}
+
 // ClientInstance  获取指定的redis
 func ClientInstance(prefix string) redis.Client {
 	lock.RLock()
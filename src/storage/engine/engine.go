@@ -0,0 +1,138 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package engine defines the storage engine abstraction above the dal, which decouples the data access from the
+// mongodb specific filters and transactions, so that the data can be stored in the alternative backends. The
+// filters are expressed by the querybuilder rules, and each engine converts them to its own query language.
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"configcenter/src/common/querybuilder"
+)
+
+// Errors defines
+var (
+	// ErrTxnNotSupported is returned by the engine which does not support the local transaction.
+	ErrTxnNotSupported = errors.New("transaction is not supported by the storage engine")
+	// ErrNotRegistered is returned when the storage engine is not registered, which may not be compiled in.
+	ErrNotRegistered = errors.New("storage engine is not registered")
+)
+
+// Engine is the storage engine interface, which manages the tables and the transactions.
+type Engine interface {
+	// Name returns the name of the storage engine.
+	Name() string
+	// Table returns the table operation interface of the table.
+	Table(name string) Table
+	// HasTable checks if the table exists.
+	HasTable(ctx context.Context, name string) (bool, error)
+	// CreateTable creates the table.
+	CreateTable(ctx context.Context, name string) error
+	// DropTable drops the table.
+	DropTable(ctx context.Context, name string) error
+	// Txn runs the fn in a transaction, the table operations with the ctx passed to fn are committed if fn returns
+	// nil and rolled back otherwise. ErrTxnNotSupported is returned if the engine does not support the transaction.
+	Txn(ctx context.Context, fn func(ctx context.Context) error) error
+	// Close closes the storage engine.
+	Close() error
+}
+
+// Table is the table operation interface, the nil filter matches all the documents in the table.
+type Table interface {
+	// Insert inserts the docs, docs can be a single document or a slice of documents.
+	Insert(ctx context.Context, docs interface{}) error
+	// Find finds the documents matched by the filter and decodes them into the result, which is a slice pointer.
+	Find(ctx context.Context, filter *querybuilder.QueryFilter, opt *FindOption, result interface{}) error
+	// Count counts the documents matched by the filter.
+	Count(ctx context.Context, filter *querybuilder.QueryFilter) (uint64, error)
+	// Update sets the fields of the doc to the documents matched by the filter, returns the number of the
+	// documents that were modified.
+	Update(ctx context.Context, filter *querybuilder.QueryFilter, doc interface{}) (uint64, error)
+	// Delete deletes the documents matched by the filter, returns the number of the documents that were deleted.
+	Delete(ctx context.Context, filter *querybuilder.QueryFilter) (uint64, error)
+}
+
+// FindOption is the option of the find operation.
+type FindOption struct {
+	// Fields the fields to be returned, all the fields are returned if not set.
+	Fields []string
+	// Sort the sort fields in the same format as the dal, e.g. "bk_host_id,-create_time" or "create_time:-1".
+	Sort  string
+	Start uint64
+	Limit uint64
+}
+
+// SortField is a parsed sort field of the find option.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses the sort of the find option to the sort fields.
+func ParseSort(sort string) []SortField {
+	if len(strings.TrimSpace(sort)) == 0 {
+		return nil
+	}
+
+	fields := make([]SortField, 0)
+	for _, item := range strings.Split(sort, ",") {
+		pair := strings.Split(strings.TrimSpace(item), ":")
+		field := strings.TrimLeft(pair[0], "+-")
+		if len(field) == 0 {
+			continue
+		}
+
+		desc := strings.HasPrefix(pair[0], "-")
+		if len(pair) == 2 {
+			desc = strings.TrimSpace(pair[1]) == "-1"
+		}
+		fields = append(fields, SortField{Field: field, Desc: desc})
+	}
+	return fields
+}
+
+// Factory creates the storage engine with the data source name.
+type Factory func(dsn string) (Engine, error)
+
+var (
+	factoryLock sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register registers the storage engine factory, it is called in the init func of the engine implementation.
+func Register(name string, factory Factory) {
+	factoryLock.Lock()
+	defer factoryLock.Unlock()
+
+	if _, exists := factories[name]; exists {
+		panic(fmt.Sprintf("storage engine %s is registered twice", name))
+	}
+	factories[name] = factory
+}
+
+// New creates the registered storage engine by the name.
+func New(name, dsn string) (Engine, error) {
+	factoryLock.RLock()
+	factory, exists := factories[name]
+	factoryLock.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrNotRegistered, name)
+	}
+	return factory(dsn)
+}
@@ -0,0 +1,38 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"testing"
+
+	"configcenter/src/storage/engine"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	assert.Nil(t, engine.ParseSort(" "))
+
+	expected := []engine.SortField{
+		{Field: "bk_host_id"},
+		{Field: "create_time", Desc: true},
+		{Field: "last_time", Desc: true},
+		{Field: "bk_host_name"},
+	}
+	assert.Equal(t, expected, engine.ParseSort("bk_host_id, -create_time,last_time:-1,+bk_host_name:1"))
+}
+
+func TestNewNotRegistered(t *testing.T) {
+	_, err := engine.New("unknown", "")
+	assert.ErrorIs(t, err, engine.ErrNotRegistered)
+}
@@ -0,0 +1,137 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"configcenter/src/common/querybuilder"
+	"configcenter/src/storage/dal"
+	"configcenter/src/storage/dal/types"
+)
+
+// MongoEngineName is the name of the mongodb storage engine.
+const MongoEngineName = "mongodb"
+
+// NewMongo creates the mongodb storage engine on the dal, the filters are converted to the mongodb filters.
+func NewMongo(db dal.DB) Engine {
+	return &mongoEngine{db: db}
+}
+
+type mongoEngine struct {
+	db dal.DB
+}
+
+// Name returns the name of the storage engine.
+func (e *mongoEngine) Name() string {
+	return MongoEngineName
+}
+
+// Table returns the table operation interface of the table.
+func (e *mongoEngine) Table(name string) Table {
+	return &mongoTable{table: e.db.Table(name)}
+}
+
+// HasTable checks if the table exists.
+func (e *mongoEngine) HasTable(ctx context.Context, name string) (bool, error) {
+	return e.db.HasTable(ctx, name)
+}
+
+// CreateTable creates the table.
+func (e *mongoEngine) CreateTable(ctx context.Context, name string) error {
+	return e.db.CreateTable(ctx, name)
+}
+
+// DropTable drops the table.
+func (e *mongoEngine) DropTable(ctx context.Context, name string) error {
+	return e.db.DropTable(ctx, name)
+}
+
+// Txn is not supported by the mongodb engine, since the mongodb transaction is a distributed transaction across
+// the services, which is started with the transaction header and joined by the dal operations with the ctx.
+func (e *mongoEngine) Txn(ctx context.Context, fn func(ctx context.Context) error) error {
+	return ErrTxnNotSupported
+}
+
+// Close closes the storage engine.
+func (e *mongoEngine) Close() error {
+	return e.db.Close()
+}
+
+type mongoTable struct {
+	table types.Table
+}
+
+// Insert inserts the docs.
+func (t *mongoTable) Insert(ctx context.Context, docs interface{}) error {
+	return t.table.Insert(ctx, docs)
+}
+
+// Find finds the documents matched by the filter.
+func (t *mongoTable) Find(ctx context.Context, filter *querybuilder.QueryFilter, opt *FindOption,
+	result interface{}) error {
+
+	cond, err := toMongoFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	find := t.table.Find(cond)
+	if opt != nil {
+		find = find.Fields(opt.Fields...).Sort(opt.Sort).Start(opt.Start).Limit(opt.Limit)
+	}
+	return find.All(ctx, result)
+}
+
+// Count counts the documents matched by the filter.
+func (t *mongoTable) Count(ctx context.Context, filter *querybuilder.QueryFilter) (uint64, error) {
+	cond, err := toMongoFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+	return t.table.Find(cond).Count(ctx)
+}
+
+// Update sets the fields of the doc to the documents matched by the filter.
+func (t *mongoTable) Update(ctx context.Context, filter *querybuilder.QueryFilter, doc interface{}) (uint64,
+	error) {
+
+	cond, err := toMongoFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+	return t.table.UpdateMany(ctx, cond, doc)
+}
+
+// Delete deletes the documents matched by the filter.
+func (t *mongoTable) Delete(ctx context.Context, filter *querybuilder.QueryFilter) (uint64, error) {
+	cond, err := toMongoFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+	return t.table.DeleteMany(ctx, cond)
+}
+
+// toMongoFilter converts the query filter to the mongodb filter, the nil filter matches all the documents.
+func toMongoFilter(filter *querybuilder.QueryFilter) (map[string]interface{}, error) {
+	if filter == nil || filter.Rule == nil {
+		return make(map[string]interface{}), nil
+	}
+
+	cond, key, err := filter.ToMgo()
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %s: %v", key, err)
+	}
+	return cond, nil
+}
@@ -0,0 +1,393 @@
+//go:build postgres
+// +build postgres
+
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package postgres is the reference postgresql implementation of the storage engine, each table stores the documents
+// in a jsonb column. It is compiled with the postgres build tag, and the database/sql driver named "postgres" (e.g.
+// github.com/lib/pq) must be imported by the binary which enables it.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"configcenter/src/common/querybuilder"
+	"configcenter/src/storage/engine"
+)
+
+// EngineName is the name of the postgresql storage engine.
+const EngineName = "postgresql"
+
+// driverName is the name of the database/sql driver.
+const driverName = "postgres"
+
+func init() {
+	engine.Register(EngineName, New)
+}
+
+// New creates the postgresql storage engine with the data source name.
+func New(dsn string) (engine.Engine, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &pgEngine{db: db}, nil
+}
+
+type pgEngine struct {
+	db *sql.DB
+}
+
+// executor is implemented by both the sql.DB and the sql.Tx.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txnKey struct{}
+
+// executor returns the transaction in the ctx if the operation is in the transaction.
+func (e *pgEngine) executor(ctx context.Context) executor {
+	if tx, ok := ctx.Value(txnKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return e.db
+}
+
+// Name returns the name of the storage engine.
+func (e *pgEngine) Name() string {
+	return EngineName
+}
+
+// Table returns the table operation interface of the table.
+func (e *pgEngine) Table(name string) engine.Table {
+	return &pgTable{engine: e, name: name}
+}
+
+// HasTable checks if the table exists.
+func (e *pgEngine) HasTable(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	err := e.executor(ctx).QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", quoteIdent(name)).Scan(&exists)
+	return exists, err
+}
+
+// CreateTable creates the table with a jsonb column to store the documents.
+func (e *pgEngine) CreateTable(ctx context.Context, name string) error {
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id BIGSERIAL PRIMARY KEY, data JSONB NOT NULL)",
+		quoteIdent(name))
+	_, err := e.executor(ctx).ExecContext(ctx, query)
+	return err
+}
+
+// DropTable drops the table.
+func (e *pgEngine) DropTable(ctx context.Context, name string) error {
+	_, err := e.executor(ctx).ExecContext(ctx, "DROP TABLE IF EXISTS "+quoteIdent(name))
+	return err
+}
+
+// Txn runs the fn in a local transaction, the nested Txn joins the outer transaction.
+func (e *pgEngine) Txn(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txnKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txnKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%v, rollback failed: %v", err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// Close closes the storage engine.
+func (e *pgEngine) Close() error {
+	return e.db.Close()
+}
+
+type pgTable struct {
+	engine *pgEngine
+	name   string
+}
+
+// Insert inserts the docs, each document is stored as a row.
+func (t *pgTable) Insert(ctx context.Context, docs interface{}) error {
+	rows := toSlice(docs)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(rows))
+	args := make([]interface{}, len(rows))
+	for idx, row := range rows {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		placeholders[idx] = fmt.Sprintf("($%d::jsonb)", idx+1)
+		args[idx] = string(data)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (data) VALUES %s", quoteIdent(t.name), strings.Join(placeholders, ","))
+	_, err := t.engine.executor(ctx).ExecContext(ctx, query, args...)
+	return err
+}
+
+// Find finds the documents matched by the filter, the rows are decoded into the result by json.
+func (t *pgTable) Find(ctx context.Context, filter *querybuilder.QueryFilter, opt *engine.FindOption,
+	result interface{}) error {
+
+	if opt == nil {
+		opt = new(engine.FindOption)
+	}
+
+	selection := "data"
+	if len(opt.Fields) > 0 {
+		pairs := make([]string, 0, len(opt.Fields))
+		for _, field := range opt.Fields {
+			if !querybuilder.ValidFieldPattern.MatchString(field) {
+				return fmt.Errorf("invalid field %s", field)
+			}
+			pairs = append(pairs, fmt.Sprintf("'%s', %s", field, jsonPath(field)))
+		}
+		selection = fmt.Sprintf("jsonb_strip_nulls(jsonb_build_object(%s))", strings.Join(pairs, ", "))
+	}
+
+	where, args, err := toWhere(filter)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s%s", selection, quoteIdent(t.name), where)
+	if sorts := engine.ParseSort(opt.Sort); len(sorts) > 0 {
+		orders := make([]string, 0, len(sorts))
+		for _, sort := range sorts {
+			if !querybuilder.ValidFieldPattern.MatchString(sort.Field) {
+				return fmt.Errorf("invalid sort field %s", sort.Field)
+			}
+			order := jsonPath(sort.Field)
+			if sort.Desc {
+				order += " DESC"
+			}
+			orders = append(orders, order)
+		}
+		query += " ORDER BY " + strings.Join(orders, ", ")
+	}
+	if opt.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opt.Limit)
+	}
+	if opt.Start > 0 {
+		query += fmt.Sprintf(" OFFSET %d", opt.Start)
+	}
+
+	rows, err := t.engine.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	docs := make([]json.RawMessage, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		docs = append(docs, data)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(docs)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// Count counts the documents matched by the filter.
+func (t *pgTable) Count(ctx context.Context, filter *querybuilder.QueryFilter) (uint64, error) {
+	where, args, err := toWhere(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count uint64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", quoteIdent(t.name), where)
+	err = t.engine.executor(ctx).QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+// Update merges the fields of the doc into the documents matched by the filter.
+func (t *pgTable) Update(ctx context.Context, filter *querybuilder.QueryFilter, doc interface{}) (uint64, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args, err := toWhere(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	// the doc is the last arg, so that it is numbered after the placeholders of the where clause.
+	args = append(args, string(data))
+	query := fmt.Sprintf("UPDATE %s SET data = data || $%d::jsonb%s", quoteIdent(t.name), len(args), where)
+	return affected(t.engine.executor(ctx).ExecContext(ctx, query, args...))
+}
+
+// Delete deletes the documents matched by the filter.
+func (t *pgTable) Delete(ctx context.Context, filter *querybuilder.QueryFilter) (uint64, error) {
+	where, args, err := toWhere(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s%s", quoteIdent(t.name), where)
+	return affected(t.engine.executor(ctx).ExecContext(ctx, query, args...))
+}
+
+// toWhere converts the query filter to the where clause with the leading space, the nil filter has no where clause.
+func toWhere(filter *querybuilder.QueryFilter) (string, []interface{}, error) {
+	if filter == nil || filter.Rule == nil {
+		return "", nil, nil
+	}
+
+	casts := make(map[string]string)
+	collectCasts(filter.Rule, casts)
+
+	opt := &querybuilder.SQLOption{
+		Dialect: querybuilder.SQLDialectPostgreSQL,
+		FieldMapper: func(field string) (string, error) {
+			return fmt.Sprintf("(%s)%s", jsonTextPath(field), casts[field]), nil
+		},
+	}
+
+	where, args, key, err := filter.ToSQL(opt)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid filter %s: %v", key, err)
+	}
+	return " WHERE " + where, args, nil
+}
+
+// collectCasts collects the type casts of the fields compared with the numeric or boolean values, since the
+// jsonb field is extracted as text, which must be cast to be compared with the values of the other types. The
+// field compared with the values of different types or used by the other operators is compared as text.
+func collectCasts(rule querybuilder.Rule, casts map[string]string) {
+	switch r := rule.(type) {
+	case querybuilder.CombinedRule:
+		for _, sub := range r.Rules {
+			collectCasts(sub, casts)
+		}
+	case querybuilder.AtomRule:
+		switch r.Operator {
+		case querybuilder.OperatorEqual, querybuilder.OperatorNotEqual, querybuilder.OperatorIn,
+			querybuilder.OperatorNotIn, querybuilder.OperatorLess, querybuilder.OperatorLessOrEqual,
+			querybuilder.OperatorGreater, querybuilder.OperatorGreaterOrEqual:
+		default:
+			casts[r.Field] = ""
+			return
+		}
+
+		cast := valueCast(r.Value)
+		if prev, exists := casts[r.Field]; exists && prev != cast {
+			cast = ""
+		}
+		casts[r.Field] = cast
+	}
+}
+
+// valueCast returns the type cast of the value, the elements of the slice value decide the cast.
+func valueCast(value interface{}) string {
+	if n, ok := value.(json.Number); ok {
+		if _, err := n.Float64(); err == nil {
+			return "::numeric"
+		}
+		return ""
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return "::numeric"
+	case reflect.Bool:
+		return "::boolean"
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			return ""
+		}
+		cast := valueCast(v.Index(0).Interface())
+		for idx := 1; idx < v.Len(); idx++ {
+			if valueCast(v.Index(idx).Interface()) != cast {
+				return ""
+			}
+		}
+		return cast
+	}
+	return ""
+}
+
+// jsonPath returns the jsonb expression of the field, the dotted field is the path of the nested field. The field is
+// validated by the ValidFieldPattern, so it contains no quote characters.
+func jsonPath(field string) string {
+	return fmt.Sprintf("data#>'{%s}'", strings.ReplaceAll(field, ".", ","))
+}
+
+// jsonTextPath returns the text expression of the field.
+func jsonTextPath(field string) string {
+	return fmt.Sprintf("data#>>'{%s}'", strings.ReplaceAll(field, ".", ","))
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func toSlice(docs interface{}) []interface{} {
+	v := reflect.ValueOf(docs)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []interface{}{docs}
+	}
+
+	rows := make([]interface{}, v.Len())
+	for idx := range rows {
+		rows[idx] = v.Index(idx).Interface()
+	}
+	return rows
+}
+
+func affected(result sql.Result, err error) (uint64, error) {
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(count), nil
+}
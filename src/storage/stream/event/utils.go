@@ -48,7 +48,8 @@ func newEventStruct(typ reflect.Type) reflect.Value {
 
 const fullDocPrefix = "fullDocument."
 
-var eventFields = []string{"_id", "operationType", "clusterTime", "ns", "documentKey", "updateDescription"}
+var eventFields = []string{"_id", "operationType", "clusterTime", "ns", "documentKey", "updateDescription", "lsid",
+	"txnNumber"}
 
 func generateOptions(opts *types.Options) (mongo.Pipeline, *options.ChangeStreamOptions) {
 
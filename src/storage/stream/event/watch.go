@@ -14,6 +14,7 @@ package event
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"reflect"
 	"strings"
@@ -247,6 +248,11 @@ func (e *Event) loopWatch(ctx context.Context,
 			currentToken.Data = base.Token.Data
 			byt, _ := json.Marshal(newStruct.Field(1).Addr().Interface())
 
+			sessionID := ""
+			if base.LsID != nil {
+				sessionID = base64.StdEncoding.EncodeToString(base.LsID.ID.Data)
+			}
+
 			eventChan <- &types.Event{
 				Oid:           base.DocumentKey.ID.Hex(),
 				OperationType: base.OperationType,
@@ -262,6 +268,7 @@ func (e *Event) loopWatch(ctx context.Context,
 					UpdatedFields: base.UpdateDesc.UpdatedFields,
 					RemovedFields: base.UpdateDesc.RemovedFields,
 				},
+				SessionID: sessionID,
 			}
 		}
 
@@ -292,6 +292,10 @@ type Event struct {
 
 	// changed fields details in this event, describes which fields is updated or removed.
 	ChangeDesc *ChangeDescription
+
+	// SessionID the base64 encoded id of the session that makes this change, empty if the change is not made in a
+	// session. it is the cmdb transaction's session id if the change is made in a transaction.
+	SessionID string
 }
 
 // ChangeDescription TODO
@@ -328,6 +332,15 @@ type EventStream struct {
 	Namespace     Namespace           `bson:"ns"`
 	DocumentKey   Key                 `bson:"documentKey"`
 	UpdateDesc    UpdateDescription   `bson:"updateDescription"`
+	// LsID the session of the change, only exists if the change is made in a session.
+	LsID *LsID `bson:"lsid,omitempty"`
+	// TxnNumber the transaction number of the change, only exists if the change is made in a transaction.
+	TxnNumber int64 `bson:"txnNumber,omitempty"`
+}
+
+// LsID is the logical session id of a change stream event.
+type LsID struct {
+	ID primitive.Binary `bson:"id"`
 }
 
 // Key TODO
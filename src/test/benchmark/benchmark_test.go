@@ -0,0 +1,182 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+	"configcenter/src/test/run"
+)
+
+// fakeAPIServer responds the api calls of the dataset generation and the scenarios with the generated ids
+type fakeAPIServer struct {
+	lock   sync.Mutex
+	nextID int64
+	calls  map[string]int
+}
+
+func (s *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	path := r.URL.Path
+	s.calls[path]++
+	s.nextID++
+
+	var data interface{}
+	switch {
+	case path == "/api/v3/find/topomodelmainline":
+		data = []map[string]interface{}{{common.BKObjIDField: common.BKInnerObjIDApp}}
+	case strings.HasPrefix(path, "/api/v3/biz/"):
+		data = map[string]interface{}{common.BKAppIDField: s.nextID}
+	case strings.HasPrefix(path, "/api/v3/create/instance/object/"):
+		data = map[string]interface{}{common.BKInstIDField: s.nextID}
+	case strings.HasPrefix(path, "/api/v3/set/"):
+		data = map[string]interface{}{common.BKSetIDField: s.nextID}
+	case strings.HasPrefix(path, "/api/v3/module/"):
+		data = map[string]interface{}{common.BKModuleIDField: s.nextID}
+	case path == "/api/v3/hosts/add/resource":
+		hosts := new(metadata.AddHostToResourcePoolHostList)
+		if err := json.NewDecoder(r.Body).Decode(hosts); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		result := new(metadata.AddHostToResourcePoolResult)
+		for index := range hosts.HostInfo {
+			s.nextID++
+			result.Success = append(result.Success,
+				metadata.AddOneHostToResourcePoolResult{Index: index, HostID: s.nextID})
+		}
+		data = result
+	}
+
+	json.NewEncoder(w).Encode(metadata.Response{BaseResp: metadata.SuccessBaseResp, Data: data})
+}
+
+func TestGenerateAndRun(t *testing.T) {
+	server := &fakeAPIServer{calls: make(map[string]int)}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	client := NewClient(httpServer.URL, "admin", common.BKDefaultOwnerID, time.Second)
+	opt := DatasetOption{Prefix: "bench", Businesses: 2, TopoLevels: 2, SetsPerBiz: 2, ModulesPerSet: 3,
+		HostsPerBiz: addHostBatchSize + 10}
+	dataset, err := GenerateDataset(ctx, client, opt)
+	if err != nil {
+		t.Fatalf("generate dataset failed, err: %v", err)
+	}
+
+	if len(dataset.Businesses) != opt.Businesses {
+		t.Fatalf("expect %d businesses, got %d", opt.Businesses, len(dataset.Businesses))
+	}
+	ips := make(map[string]struct{})
+	for _, biz := range dataset.Businesses {
+		if len(biz.ModuleIDs) != opt.SetsPerBiz*opt.ModulesPerSet {
+			t.Errorf("business %d expects %d modules, got %d", biz.ID, opt.SetsPerBiz*opt.ModulesPerSet,
+				len(biz.ModuleIDs))
+		}
+		if len(biz.HostIDs) != opt.HostsPerBiz || len(biz.HostIPs) != opt.HostsPerBiz {
+			t.Errorf("business %d expects %d hosts, got %d", biz.ID, opt.HostsPerBiz, len(biz.HostIDs))
+		}
+		for _, ip := range biz.HostIPs {
+			ips[ip] = struct{}{}
+		}
+	}
+	if len(ips) != opt.Businesses*opt.HostsPerBiz {
+		t.Errorf("expect %d distinct host ips, got %d", opt.Businesses*opt.HostsPerBiz, len(ips))
+	}
+	if calls := server.calls["/api/v3/create/topomodelmainline"]; calls != opt.TopoLevels {
+		t.Errorf("expect %d mainline levels created, got %d", opt.TopoLevels, calls)
+	}
+	if calls := server.calls["/api/v3/create/instance/object/bench_level2"]; calls != opt.Businesses {
+		t.Errorf("expect %d bottom level instances created, got %d", opt.Businesses, calls)
+	}
+
+	report, err := Run(ctx, client, dataset, RunOption{Concurrent: 2, TotalRequest: 20})
+	if err != nil {
+		t.Fatalf("run benchmark failed, err: %v", err)
+	}
+	if len(report.Results) != len(ScenarioNames()) {
+		t.Fatalf("expect %d scenario results, got %d", len(ScenarioNames()), len(report.Results))
+	}
+	for _, result := range report.Results {
+		if result.Metrics.SucceedRequest != 20 {
+			t.Errorf("scenario %s expects 20 succeed requests, got %+v", result.Scenario, result.Metrics)
+		}
+	}
+	if regressions := Compare(report, report, 10); len(regressions) != 0 {
+		t.Errorf("report compared with itself has regressions: %v", regressions)
+	}
+
+	if _, err := Run(ctx, client, dataset, RunOption{Scenarios: []string{"unknown"}, Concurrent: 1,
+		TotalRequest: 1}); err == nil {
+		t.Errorf("run unknown scenario should fail")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Report{Results: []ScenarioResult{
+		{Scenario: ScenarioHostSearch, Metrics: run.Metrics{QPS: 100, AverageDuration: 10, Percent95Duration: 20}},
+		{Scenario: ScenarioAudit, Metrics: run.Metrics{QPS: 100, AverageDuration: 10, Percent95Duration: 20}},
+	}}
+	current := &Report{Results: []ScenarioResult{
+		{Scenario: ScenarioHostSearch, Metrics: run.Metrics{QPS: 80, AverageDuration: 10.5, Percent95Duration: 30}},
+		{Scenario: ScenarioAudit, Metrics: run.Metrics{QPS: 105, AverageDuration: 9, Percent95Duration: 21,
+			FailedRequest: 1}},
+		{Scenario: ScenarioWatch, Metrics: run.Metrics{QPS: 1, AverageDuration: 1000, Percent95Duration: 1000}},
+	}}
+
+	expects := map[string]bool{
+		ScenarioHostSearch + ".qps":               true,
+		ScenarioHostSearch + ".p95_ms":            true,
+		ScenarioAudit + "." + failedRequestMetric: true,
+	}
+	regressions := Compare(baseline, current, 10)
+	if len(regressions) != len(expects) {
+		t.Fatalf("expect %d regressions, got %v", len(expects), regressions)
+	}
+	for _, regression := range regressions {
+		if !expects[regression.Scenario+"."+regression.Metric] {
+			t.Errorf("unexpected regression %s", regression)
+		}
+	}
+}
+
+func TestDatasetOptionValidate(t *testing.T) {
+	valid := DatasetOption{Prefix: "bench", Businesses: 1, SetsPerBiz: 1, ModulesPerSet: 1, HostsPerBiz: 1}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid option failed, err: %v", err)
+	}
+
+	invalid := []DatasetOption{
+		{Businesses: 1, SetsPerBiz: 1, ModulesPerSet: 1, HostsPerBiz: 1},
+		{Prefix: "bench", SetsPerBiz: 1, ModulesPerSet: 1, HostsPerBiz: 1},
+		{Prefix: "bench", Businesses: 1, TopoLevels: -1, SetsPerBiz: 1, ModulesPerSet: 1, HostsPerBiz: 1},
+		{Prefix: "bench", Businesses: 1 << 12, SetsPerBiz: 1, ModulesPerSet: 1, HostsPerBiz: 1 << 12},
+	}
+	for _, opt := range invalid {
+		if err := opt.Validate(); err == nil {
+			t.Errorf("invalid option %+v passed", opt)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package benchmark generates the synthetic datasets and measures the latency and throughput of the core data paths,
+// e.g. host search, host transfer, resource watch and audit search, through the api server like the external callers.
+// it is used by the cmdb_benchmark tool to catch the performance regressions before release.
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+)
+
+// Client issues the api calls to the api server
+type Client interface {
+	// Do issues the api call, the body is encoded to json if it is not nil, and the response is decoded into the
+	// result if it is not nil.
+	Do(ctx context.Context, method, path string, body, result interface{}) error
+}
+
+// NewClient returns a client of the api server at the address, e.g. http://127.0.0.1:8080, the requests are issued
+// by the user of the supplier account.
+func NewClient(addr, user, supplierAccount string, timeout time.Duration) Client {
+	header := make(http.Header)
+	header.Set(common.BKHTTPOwnerID, supplierAccount)
+	header.Set(common.BKHTTPHeaderUser, user)
+	header.Set(common.BKHTTPLanguage, "en")
+	header.Set("Content-Type", "application/json")
+
+	return &httpClient{
+		addr:   strings.TrimSuffix(addr, "/"),
+		header: header,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type httpClient struct {
+	addr   string
+	header http.Header
+	client *http.Client
+}
+
+// Do issues the api call to the api server
+func (c *httpClient) Do(ctx context.Context, method, path string, body, result interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header = c.header.Clone()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respData, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s failed, status: %d, body: %s", method, path, resp.StatusCode, respData)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(respData, result)
+}
+
+// call issues the api call and decodes the data of the cmdb response into the result, the error of the response is
+// returned if the call is not successful.
+func call(ctx context.Context, client Client, method, path string, body, result interface{}) error {
+	resp := &metadata.Response{Data: result}
+	if err := client.Do(ctx, method, path, body, resp); err != nil {
+		return err
+	}
+	if err := resp.CCError(); err != nil {
+		return fmt.Errorf("%s %s failed, err: %v", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+)
+
+// addHostBatchSize the max number of the hosts added to the resource pool in a request
+const addHostBatchSize = 200
+
+// DatasetOption is the option to generate the synthetic dataset
+type DatasetOption struct {
+	// Prefix the prefix of the names of the generated resources, which distinguishes the datasets
+	Prefix string `json:"prefix"`
+	// Businesses the number of the businesses
+	Businesses int `json:"businesses"`
+	// TopoLevels the number of the custom mainline levels between the business and the set, the levels are shared by
+	// all the businesses since the mainline model is global.
+	TopoLevels    int `json:"topo_levels"`
+	SetsPerBiz    int `json:"sets_per_biz"`
+	ModulesPerSet int `json:"modules_per_set"`
+	// HostsPerBiz the number of the hosts of each business, they are spread over the modules of the business.
+	HostsPerBiz int `json:"hosts_per_biz"`
+}
+
+// Validate validates the dataset option
+func (o *DatasetOption) Validate() error {
+	if o.Prefix == "" {
+		return fmt.Errorf("prefix is not set")
+	}
+	if o.Businesses <= 0 || o.SetsPerBiz <= 0 || o.ModulesPerSet <= 0 || o.HostsPerBiz <= 0 {
+		return fmt.Errorf("businesses, sets per biz, modules per set and hosts per biz must be positive")
+	}
+	if o.TopoLevels < 0 {
+		return fmt.Errorf("topo levels can not be negative")
+	}
+	// the ips of the hosts are generated in 10.0.0.0/8
+	if o.Businesses*o.HostsPerBiz > 1<<24-2 {
+		return fmt.Errorf("the total number of the hosts exceeds %d", 1<<24-2)
+	}
+	return nil
+}
+
+// Dataset is the generated dataset, it is saved to a file by the generate command and loaded by the run command.
+type Dataset struct {
+	Option     DatasetOption `json:"option"`
+	CreateTime time.Time     `json:"create_time"`
+	Businesses []Business    `json:"businesses"`
+}
+
+// Business is a generated business with its topology and hosts
+type Business struct {
+	ID        int64    `json:"bk_biz_id"`
+	ModuleIDs []int64  `json:"bk_module_ids"`
+	HostIDs   []int64  `json:"bk_host_ids"`
+	HostIPs   []string `json:"bk_host_innerips"`
+}
+
+// LoadDataset loads the dataset from the file saved by Save
+func LoadDataset(file string) (*Dataset, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset := new(Dataset)
+	if err := json.Unmarshal(data, dataset); err != nil {
+		return nil, fmt.Errorf("parse dataset file %s failed, err: %v", file, err)
+	}
+	if len(dataset.Businesses) == 0 {
+		return nil, fmt.Errorf("dataset file %s has no business", file)
+	}
+	return dataset, nil
+}
+
+// Save saves the dataset to the file
+func (d *Dataset) Save(file string) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+// GenerateDataset creates the businesses, the custom mainline levels, the sets, the modules and the hosts by the api
+// server, and the hosts of each business are transferred to its modules evenly.
+func GenerateDataset(ctx context.Context, client Client, opt DatasetOption) (*Dataset, error) {
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	dataset := &Dataset{Option: opt, CreateTime: time.Now(), Businesses: make([]Business, 0, opt.Businesses)}
+	levels, err := createTopoLevels(ctx, client, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	for index := 0; index < opt.Businesses; index++ {
+		biz, err := generateBusiness(ctx, client, opt, levels, index)
+		if err != nil {
+			return nil, err
+		}
+		dataset.Businesses = append(dataset.Businesses, *biz)
+		blog.Infof("generated business %d/%d, id: %d", index+1, opt.Businesses, biz.ID)
+	}
+
+	return dataset, nil
+}
+
+// createTopoLevels creates the custom mainline levels if they do not exist, and returns their object ids from the top
+// to the bottom.
+func createTopoLevels(ctx context.Context, client Client, opt DatasetOption) ([]string, error) {
+	topo := make([]map[string]interface{}, 0)
+	if err := call(ctx, client, http.MethodPost, "/api/v3/find/topomodelmainline", nil, &topo); err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]struct{}, len(topo))
+	for _, obj := range topo {
+		existing[util.GetStrByInterface(obj[common.BKObjIDField])] = struct{}{}
+	}
+
+	levels := make([]string, opt.TopoLevels)
+	parent := common.BKInnerObjIDApp
+	for index := range levels {
+		objID := fmt.Sprintf("%s_level%d", opt.Prefix, index+1)
+		levels[index] = objID
+		if _, exists := existing[objID]; !exists {
+			option := map[string]interface{}{
+				common.BKClassificationIDField: "bk_biz_topo",
+				common.BKObjIDField:            objID,
+				common.BKObjNameField:          objID,
+				common.BKObjIconField:          "icon-cc-default",
+				common.BKAsstObjIDField:        parent,
+				common.BKOwnerIDField:          common.BKDefaultOwnerID,
+			}
+			if err := call(ctx, client, http.MethodPost, "/api/v3/create/topomodelmainline", option, nil); err != nil {
+				return nil, err
+			}
+		}
+		parent = objID
+	}
+
+	return levels, nil
+}
+
+func generateBusiness(ctx context.Context, client Client, opt DatasetOption, levels []string, index int) (*Business,
+	error) {
+
+	bizName := fmt.Sprintf("%s_biz_%d", opt.Prefix, index)
+	bizOption := map[string]interface{}{
+		common.BKAppNameField: bizName,
+		"bk_biz_maintainer":   "admin",
+		"life_cycle":          "2",
+		"language":            "1",
+		"time_zone":           "Asia/Shanghai",
+	}
+	bizData := make(map[string]interface{})
+	path := "/api/v3/biz/" + common.BKDefaultOwnerID
+	if err := call(ctx, client, http.MethodPost, path, bizOption, &bizData); err != nil {
+		return nil, err
+	}
+	bizID, err := util.GetInt64ByInterface(bizData[common.BKAppIDField])
+	if err != nil {
+		return nil, err
+	}
+	biz := &Business{ID: bizID}
+
+	// the sets are created under the bottom custom level, the chain of the custom levels is created once per business.
+	parentID := bizID
+	for _, objID := range levels {
+		option := map[string]interface{}{
+			common.BKInstNameField: fmt.Sprintf("%s_%d", objID, index),
+			common.BKInstParentStr: parentID,
+			common.BKAppIDField:    bizID,
+		}
+		inst := make(map[string]interface{})
+		path := "/api/v3/create/instance/object/" + objID
+		if err := call(ctx, client, http.MethodPost, path, option, &inst); err != nil {
+			return nil, err
+		}
+		if parentID, err = util.GetInt64ByInterface(inst[common.BKInstIDField]); err != nil {
+			return nil, err
+		}
+	}
+
+	for setIndex := 0; setIndex < opt.SetsPerBiz; setIndex++ {
+		setOption := map[string]interface{}{
+			common.BKSetNameField:  fmt.Sprintf("set_%d", setIndex),
+			common.BKInstParentStr: parentID,
+			common.BKAppIDField:    bizID,
+		}
+		set := make(map[string]interface{})
+		if err := call(ctx, client, http.MethodPost, fmt.Sprintf("/api/v3/set/%d", bizID), setOption,
+			&set); err != nil {
+			return nil, err
+		}
+		setID, err := util.GetInt64ByInterface(set[common.BKSetIDField])
+		if err != nil {
+			return nil, err
+		}
+
+		for moduleIndex := 0; moduleIndex < opt.ModulesPerSet; moduleIndex++ {
+			moduleOption := map[string]interface{}{
+				common.BKModuleNameField:        fmt.Sprintf("module_%d", moduleIndex),
+				common.BKInstParentStr:          setID,
+				common.BKServiceCategoryIDField: 2,
+				common.BKServiceTemplateIDField: 0,
+			}
+			module := make(map[string]interface{})
+			if err := call(ctx, client, http.MethodPost, fmt.Sprintf("/api/v3/module/%d/%d", bizID, setID),
+				moduleOption, &module); err != nil {
+				return nil, err
+			}
+			moduleID, err := util.GetInt64ByInterface(module[common.BKModuleIDField])
+			if err != nil {
+				return nil, err
+			}
+			biz.ModuleIDs = append(biz.ModuleIDs, moduleID)
+		}
+	}
+
+	if err := generateHosts(ctx, client, opt, biz, index); err != nil {
+		return nil, err
+	}
+	return biz, nil
+}
+
+// generateHosts adds the hosts to the resource pool in batches, assigns them to the business and transfers them to
+// the modules of the business evenly.
+func generateHosts(ctx context.Context, client Client, opt DatasetOption, biz *Business, bizIndex int) error {
+	for start := 0; start < opt.HostsPerBiz; start += addHostBatchSize {
+		end := start + addHostBatchSize
+		if end > opt.HostsPerBiz {
+			end = opt.HostsPerBiz
+		}
+
+		hosts := metadata.AddHostToResourcePoolHostList{HostInfo: make([]map[string]interface{}, 0, end-start)}
+		ips := make([]string, 0, end-start)
+		for index := start; index < end; index++ {
+			ip := hostIP(bizIndex*opt.HostsPerBiz + index)
+			ips = append(ips, ip)
+			hosts.HostInfo = append(hosts.HostInfo, map[string]interface{}{
+				common.BKHostInnerIPField: ip,
+				common.BKCloudIDField:     common.BKDefaultDirSubArea,
+			})
+		}
+
+		result := new(metadata.AddHostToResourcePoolResult)
+		if err := call(ctx, client, http.MethodPost, "/api/v3/hosts/add/resource", hosts, result); err != nil {
+			return err
+		}
+		if len(result.Error) != 0 {
+			return fmt.Errorf("add host %s failed, err: %s", ips[result.Error[0].Index], result.Error[0].ErrorMsg)
+		}
+
+		hostIDs := make([]int64, len(ips))
+		for _, success := range result.Success {
+			hostIDs[success.Index] = success.HostID
+		}
+
+		assign := metadata.DefaultModuleHostConfigParams{ApplicationID: biz.ID, HostIDs: hostIDs}
+		if err := call(ctx, client, http.MethodPost, "/api/v3/hosts/modules/resource/idle", assign, nil); err != nil {
+			return err
+		}
+
+		for index, hostID := range hostIDs {
+			moduleID := biz.ModuleIDs[(start+index)%len(biz.ModuleIDs)]
+			if err := transferHost(ctx, client, biz.ID, hostID, moduleID); err != nil {
+				return err
+			}
+		}
+
+		biz.HostIDs = append(biz.HostIDs, hostIDs...)
+		biz.HostIPs = append(biz.HostIPs, ips...)
+	}
+
+	return nil
+}
+
+// hostIP returns the ip of the host with the sequence number in 10.0.0.0/8
+func hostIP(seq int) string {
+	seq++
+	return fmt.Sprintf("10.%d.%d.%d", seq>>16&0xff, seq>>8&0xff, seq&0xff)
+}
+
+func transferHost(ctx context.Context, client Client, bizID, hostID, moduleID int64) error {
+	option := metadata.HostsModuleRelation{
+		ApplicationID: bizID,
+		HostID:        []int64{hostID},
+		ModuleID:      []int64{moduleID},
+		IsIncrement:   false,
+	}
+	return call(ctx, client, http.MethodPost, "/api/v3/hosts/modules", option, nil)
+}
@@ -0,0 +1,201 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/test/run"
+)
+
+// RunOption is the option to run the benchmark scenarios
+type RunOption struct {
+	// Scenarios the names of the scenarios to run, run all the scenarios if not set
+	Scenarios []string `json:"scenarios"`
+	// Concurrent the number of the concurrent requests
+	Concurrent int `json:"concurrent"`
+	// SustainSeconds the seconds each scenario lasts
+	SustainSeconds float64 `json:"sustain_seconds"`
+	// TotalRequest the number of the requests of each scenario, it has higher priority than SustainSeconds
+	TotalRequest int64 `json:"total_request"`
+}
+
+// Report is the benchmark report of the scenarios
+type Report struct {
+	StartTime time.Time        `json:"start_time"`
+	Option    RunOption        `json:"option"`
+	Dataset   DatasetOption    `json:"dataset"`
+	Results   []ScenarioResult `json:"results"`
+}
+
+// ScenarioResult is the metrics of a scenario
+type ScenarioResult struct {
+	Scenario string      `json:"scenario"`
+	Metrics  run.Metrics `json:"metrics"`
+}
+
+// Run runs the scenarios one by one against the dataset and reports their metrics, the scenarios are not run in
+// parallel since the load test options of the run package are global.
+func Run(ctx context.Context, client Client, dataset *Dataset, opt RunOption) (*Report, error) {
+	if opt.Concurrent <= 0 {
+		return nil, fmt.Errorf("concurrent must be positive")
+	}
+	if opt.TotalRequest <= 0 && opt.SustainSeconds <= 0 {
+		return nil, fmt.Errorf("either total request or sustain seconds must be set")
+	}
+
+	names := opt.Scenarios
+	if len(names) == 0 {
+		names = ScenarioNames()
+	}
+	toRun := make([]Scenario, len(names))
+	for index, name := range names {
+		scenario, err := GetScenario(name)
+		if err != nil {
+			return nil, err
+		}
+		toRun[index] = scenario
+	}
+
+	run.Concurrent = opt.Concurrent
+	run.SustainSeconds = opt.SustainSeconds
+	run.TotalRequest = opt.TotalRequest
+
+	report := &Report{StartTime: time.Now(), Option: opt, Dataset: dataset.Option}
+	for _, scenario := range toRun {
+		blog.Infof("start to run scenario %s", scenario.Name)
+		request := scenario.Request
+		metrics := run.FireLoadTest(func() error {
+			err := request(ctx, client, dataset)
+			if err != nil {
+				blog.Errorf("scenario %s request failed, err: %v", scenario.Name, err)
+			}
+			return err
+		})
+		report.Results = append(report.Results, ScenarioResult{Scenario: scenario.Name, Metrics: metrics})
+	}
+
+	return report, nil
+}
+
+// LoadReport loads the report from the file saved by Save
+func LoadReport(file string) (*Report, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	report := new(Report)
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("parse report file %s failed, err: %v", file, err)
+	}
+	return report, nil
+}
+
+// Save saves the report to the file in json
+func (r *Report) Save(file string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+// Format formats the report to be read in the terminal
+func (r *Report) Format() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Benchmark started at %s, dataset: %d businesses * %d hosts\n\n",
+		r.StartTime.Format(time.RFC3339), r.Dataset.Businesses, r.Dataset.HostsPerBiz))
+	for _, result := range r.Results {
+		builder.WriteString(run.SetBlue(fmt.Sprintf("Scenario %s\n", result.Scenario)))
+		builder.WriteString(result.Metrics.Format())
+	}
+	return builder.String()
+}
+
+// failedRequestMetric the metric name of the failed requests, which is a regression whenever it is not zero
+const failedRequestMetric = "failed_request"
+
+// Regression is a metric of a scenario that gets worse than the baseline beyond the threshold
+type Regression struct {
+	Scenario string  `json:"scenario"`
+	Metric   string  `json:"metric"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	// ChangePercent how much the metric gets worse in percent
+	ChangePercent float64 `json:"change_percent"`
+}
+
+// String returns the description of the regression
+func (r Regression) String() string {
+	if r.Metric == failedRequestMetric {
+		return fmt.Sprintf("scenario %s has %.0f failed requests", r.Scenario, r.Current)
+	}
+	return fmt.Sprintf("scenario %s %s regressed by %.1f%%: %.2f -> %.2f", r.Scenario, r.Metric, r.ChangePercent,
+		r.Baseline, r.Current)
+}
+
+// Compare compares the report with the baseline report, and returns the metrics that get worse by more than the
+// threshold percent. The average and p95 latencies are regressed if they increase, the qps is regressed if it
+// decreases, and any failed request is a regression. Scenarios that are not in the baseline are ignored.
+func Compare(baseline, current *Report, thresholdPercent float64) []Regression {
+	baseMetrics := make(map[string]run.Metrics, len(baseline.Results))
+	for _, result := range baseline.Results {
+		baseMetrics[result.Scenario] = result.Metrics
+	}
+
+	regressions := make([]Regression, 0)
+	for _, result := range current.Results {
+		base, exists := baseMetrics[result.Scenario]
+		if !exists {
+			continue
+		}
+		cur := result.Metrics
+
+		if cur.FailedRequest > 0 {
+			regressions = append(regressions, Regression{Scenario: result.Scenario, Metric: failedRequestMetric,
+				Baseline: float64(base.FailedRequest), Current: float64(cur.FailedRequest)})
+		}
+
+		compares := []struct {
+			metric         string
+			base, cur      float64
+			higherIsBetter bool
+		}{
+			{metric: "avg_ms", base: base.AverageDuration, cur: cur.AverageDuration},
+			{metric: "p95_ms", base: base.Percent95Duration, cur: cur.Percent95Duration},
+			{metric: "qps", base: base.QPS, cur: cur.QPS, higherIsBetter: true},
+		}
+		for _, c := range compares {
+			if c.base <= 0 {
+				continue
+			}
+			change := (c.cur - c.base) / c.base * 100
+			if c.higherIsBetter {
+				change = -change
+			}
+			if change > thresholdPercent {
+				regressions = append(regressions, Regression{Scenario: result.Scenario, Metric: c.metric,
+					Baseline: c.base, Current: c.cur, ChangePercent: change})
+			}
+		}
+	}
+
+	return regressions
+}
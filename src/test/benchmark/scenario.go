@@ -0,0 +1,144 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/querybuilder"
+	"configcenter/src/common/watch"
+)
+
+// the names of the benchmark scenarios
+const (
+	ScenarioHostSearch   = "host_search"
+	ScenarioHostTransfer = "host_transfer"
+	ScenarioWatch        = "watch"
+	ScenarioAudit        = "audit"
+)
+
+// searchPageLimit the page limit of the search scenarios, which is the common page size of the web pages
+const searchPageLimit = 20
+
+// Scenario is a core data path to be measured
+type Scenario struct {
+	Name string
+	// Request sends one request of the scenario with the data randomly picked from the dataset
+	Request func(ctx context.Context, client Client, dataset *Dataset) error
+}
+
+var scenarios = []Scenario{
+	{Name: ScenarioHostSearch, Request: searchHost},
+	{Name: ScenarioHostTransfer, Request: transferRandomHost},
+	{Name: ScenarioWatch, Request: watchHost},
+	{Name: ScenarioAudit, Request: searchAudit},
+}
+
+// ScenarioNames returns the names of all the scenarios
+func ScenarioNames() []string {
+	names := make([]string, len(scenarios))
+	for index, scenario := range scenarios {
+		names[index] = scenario.Name
+	}
+	return names
+}
+
+// GetScenario returns the scenario by its name
+func GetScenario(name string) (Scenario, error) {
+	for _, scenario := range scenarios {
+		if scenario.Name == name {
+			return scenario, nil
+		}
+	}
+	return Scenario{}, fmt.Errorf("scenario %s is not supported, supported scenarios: %v", name, ScenarioNames())
+}
+
+func randomBusiness(dataset *Dataset) *Business {
+	return &dataset.Businesses[rand.Intn(len(dataset.Businesses))]
+}
+
+// searchHost searches the hosts in a random module of a random business, and searches a random host by its ip
+// without the business alternately, which are the most common ways to search the hosts.
+func searchHost(ctx context.Context, client Client, dataset *Dataset) error {
+	biz := randomBusiness(dataset)
+	page := metadata.BasePage{Limit: searchPageLimit}
+	fields := []string{common.BKHostIDField, common.BKHostInnerIPField, common.BKCloudIDField}
+
+	if rand.Intn(2) == 0 {
+		option := metadata.ListHostsParameter{
+			ModuleIDs: []int64{biz.ModuleIDs[rand.Intn(len(biz.ModuleIDs))]},
+			Fields:    fields,
+			Page:      page,
+		}
+		path := fmt.Sprintf("/api/v3/hosts/app/%d/list_hosts", biz.ID)
+		return call(ctx, client, http.MethodPost, path, option, nil)
+	}
+
+	option := metadata.ListHostsWithNoBizParameter{
+		HostPropertyFilter: &querybuilder.QueryFilter{
+			Rule: querybuilder.CombinedRule{
+				Condition: querybuilder.ConditionAnd,
+				Rules: []querybuilder.Rule{
+					querybuilder.AtomRule{
+						Field:    common.BKHostInnerIPField,
+						Operator: querybuilder.OperatorEqual,
+						Value:    biz.HostIPs[rand.Intn(len(biz.HostIPs))],
+					},
+				},
+			},
+		},
+		Fields: fields,
+		Page:   page,
+	}
+	return call(ctx, client, http.MethodPost, "/api/v3/hosts/list_hosts_without_app", option, nil)
+}
+
+// transferRandomHost transfers a random host to a random module of its business
+func transferRandomHost(ctx context.Context, client Client, dataset *Dataset) error {
+	biz := randomBusiness(dataset)
+	hostID := biz.HostIDs[rand.Intn(len(biz.HostIDs))]
+	moduleID := biz.ModuleIDs[rand.Intn(len(biz.ModuleIDs))]
+	return transferHost(ctx, client, biz.ID, hostID, moduleID)
+}
+
+// watchHost watches the host events from the time the dataset is created, so that the events of the generated hosts
+// are always returned without waiting for the new events.
+func watchHost(ctx context.Context, client Client, dataset *Dataset) error {
+	option := watch.WatchEventOptions{
+		Fields:    []string{common.BKHostIDField, common.BKHostInnerIPField},
+		StartFrom: dataset.CreateTime.Unix(),
+		Resource:  watch.Host,
+	}
+	return call(ctx, client, http.MethodPost, "/api/v3/event/watch/resource/"+string(watch.Host), option, nil)
+}
+
+// searchAudit searches the audit logs of a random business since the dataset is created
+func searchAudit(ctx context.Context, client Client, dataset *Dataset) error {
+	option := metadata.AuditQueryInput{
+		Condition: metadata.AuditQueryCondition{
+			BizID: randomBusiness(dataset).ID,
+			OperationTime: metadata.OperationTimeCondition{
+				Start: dataset.CreateTime.Format(common.TimeTransferModel),
+				End:   time.Now().Format(common.TimeTransferModel),
+			},
+		},
+		Page: metadata.BasePage{Limit: searchPageLimit},
+	}
+	return call(ctx, client, http.MethodPost, "/api/v3/findmany/audit_list", option, nil)
+}
@@ -0,0 +1,115 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package harness
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// placeholderRegexp matches the placeholders of the config templates, e.g. __BK_CMDB_ZK_ADDR__
+var placeholderRegexp = regexp.MustCompile(`__BK_[A-Z0-9_]+__`)
+
+// repoRoot returns the root directory of the repository
+func repoRoot() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..")
+}
+
+// renderConfig renders the config templates in docs/support-file/config/templates into the directory, the template
+// file name is the relative path with "#" as the separator, e.g. server#conf#common.yaml is rendered to
+// <dir>/cmdb/server/conf/common.yaml. the errors and language resources are linked to <dir>/cmdb. it returns the
+// path of the migrate config that the admin server starts with.
+func renderConfig(dir string, deps *Dependencies, overrides map[string]string) (string, error) {
+	home := filepath.Join(dir, "cmdb")
+	mongoHost, mongoPort := splitAddr(deps.MongoAddr)
+	redisHost, redisPort := splitAddr(deps.RedisAddr)
+	values := map[string]string{
+		"__BK_HOME__":                           dir,
+		"__BK_CMDB_ZK_ADDR__":                   deps.ZkAddr,
+		"__BK_CMDB_MONGODB_HOST__":              mongoHost,
+		"__BK_CMDB_MONGODB_PORT__":              mongoPort,
+		"__BK_CMDB_MONGODB_USERNAME__":          deps.MongoUser,
+		"__BK_CMDB_MONGODB_PASSWORD__":          deps.MongoPassword,
+		"__BK_CMDB_EVENTS_MONGODB_HOST__":       mongoHost,
+		"__BK_CMDB_EVENTS_MONGODB_PORT__":       mongoPort,
+		"__BK_CMDB_EVENTS_MONGODB_USERNAME__":   deps.MongoUser,
+		"__BK_CMDB_EVENTS_MONGODB_PASSWORD__":   deps.MongoPassword,
+		"__BK_CMDB_EVENTS_MONGODB_DATABASE__":   "cmdb",
+		"__BK_CMDB_REDIS_SENTINEL_HOST__":       redisHost,
+		"__BK_CMDB_REDIS_SENTINEL_PORT__":       redisPort,
+		"__BK_CMDB_REDIS_PASSWORD__":            deps.RedisPassword,
+		"__BK_HTTP_SCHEMA__":                    "http",
+		"__BK_CMDB_CLOUD_SYNC_PERIOD_MINUTES__": "5",
+	}
+	for key, value := range overrides {
+		values[key] = value
+	}
+
+	templateDir := filepath.Join(repoRoot(), "docs", "support-file", "config", "templates")
+	files, err := ioutil.ReadDir(templateDir)
+	if err != nil {
+		return "", fmt.Errorf("read config templates failed, err: %v", err)
+	}
+
+	for _, file := range files {
+		content, err := ioutil.ReadFile(filepath.Join(templateDir, file.Name()))
+		if err != nil {
+			return "", fmt.Errorf("read config template %s failed, err: %v", file.Name(), err)
+		}
+
+		rendered := placeholderRegexp.ReplaceAllStringFunc(string(content), func(placeholder string) string {
+			if value, exists := values[placeholder]; exists {
+				return value
+			}
+			// the addresses of the third party systems that are not set, e.g. gse and kafka, are not called by the
+			// tests, they are rendered to the local address to keep the configs valid, and the others to empty.
+			switch {
+			case strings.HasSuffix(placeholder, "_HOST__"):
+				return "127.0.0.1"
+			case strings.HasSuffix(placeholder, "_PORT__"):
+				return "0"
+			}
+			return ""
+		})
+
+		target := filepath.Join(home, filepath.Join(strings.Split(file.Name(), "#")...))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return "", err
+		}
+		if err := ioutil.WriteFile(target, []byte(rendered), 0644); err != nil {
+			return "", fmt.Errorf("write config %s failed, err: %v", target, err)
+		}
+	}
+
+	for _, res := range []string{"errors", "language"} {
+		if err := os.Symlink(filepath.Join(repoRoot(), "resources", res), filepath.Join(home, res)); err != nil {
+			return "", fmt.Errorf("link %s resources failed, err: %v", res, err)
+		}
+	}
+
+	return filepath.Join(home, "server", "conf", "migrate.yaml"), nil
+}
+
+func splitAddr(addr string) (string, string) {
+	index := strings.LastIndex(addr, ":")
+	if index < 0 {
+		return addr, ""
+	}
+	return addr[:index], addr[index+1:]
+}
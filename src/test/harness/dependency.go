@@ -0,0 +1,240 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package harness
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the environment variables to use the existing dependencies instead of starting them by docker
+const (
+	// MongoAddrEnv the address of the mongodb replica set named rs0, e.g. 127.0.0.1:27017, the user and password are
+	// set by MongoUserEnv and MongoPasswordEnv.
+	MongoAddrEnv     = "CC_TEST_MONGO_ADDR"
+	MongoUserEnv     = "CC_TEST_MONGO_USER"
+	MongoPasswordEnv = "CC_TEST_MONGO_PASSWORD"
+	// RedisAddrEnv the address of the redis, e.g. 127.0.0.1:6379, the password is set by RedisPasswordEnv.
+	RedisAddrEnv     = "CC_TEST_REDIS_ADDR"
+	RedisPasswordEnv = "CC_TEST_REDIS_PASSWORD"
+	// ZkAddrEnv the address of the zookeeper, e.g. 127.0.0.1:2181
+	ZkAddrEnv = "CC_TEST_ZK_ADDR"
+)
+
+// the docker images of the dependencies
+const (
+	mongoImage = "mongo:4.2"
+	redisImage = "redis:6.2"
+	zkImage    = "zookeeper:3.6"
+)
+
+// the default user and password of the dependencies started by docker
+const (
+	defaultUser     = "cc"
+	defaultPassword = "cc"
+)
+
+// Dependencies is the mongodb, redis and zookeeper that the services depend on
+type Dependencies struct {
+	MongoAddr     string
+	MongoUser     string
+	MongoPassword string
+	RedisAddr     string
+	RedisPassword string
+	ZkAddr        string
+
+	// containers the ids of the docker containers started by the harness, they are removed when the harness stops.
+	containers []string
+}
+
+// DockerAvailable returns whether the docker command is available to start the dependencies
+func DockerAvailable() bool {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return false
+	}
+	return exec.Command("docker", "info").Run() == nil
+}
+
+// DependenciesConfigured returns whether all the dependencies are set by the environment variables
+func DependenciesConfigured() bool {
+	return os.Getenv(MongoAddrEnv) != "" && os.Getenv(RedisAddrEnv) != "" && os.Getenv(ZkAddrEnv) != ""
+}
+
+// StartDependencies starts the dependencies that are not set by the environment variables in docker
+func StartDependencies(ctx context.Context) (*Dependencies, error) {
+	deps := &Dependencies{
+		MongoAddr:     os.Getenv(MongoAddrEnv),
+		MongoUser:     os.Getenv(MongoUserEnv),
+		MongoPassword: os.Getenv(MongoPasswordEnv),
+		RedisAddr:     os.Getenv(RedisAddrEnv),
+		RedisPassword: os.Getenv(RedisPasswordEnv),
+		ZkAddr:        os.Getenv(ZkAddrEnv),
+	}
+
+	if deps.MongoAddr == "" {
+		if err := deps.startMongo(ctx); err != nil {
+			deps.Stop()
+			return nil, err
+		}
+	}
+
+	if deps.RedisAddr == "" {
+		if err := deps.startRedis(ctx); err != nil {
+			deps.Stop()
+			return nil, err
+		}
+	}
+
+	if deps.ZkAddr == "" {
+		if err := deps.startZk(ctx); err != nil {
+			deps.Stop()
+			return nil, err
+		}
+	}
+
+	return deps, nil
+}
+
+// startMongo starts a single node replica set since the transactions are required, the port in the container is the
+// same as the host port so that the replica set member address is reachable from the host.
+func (d *Dependencies) startMongo(ctx context.Context) error {
+	port, err := freePort()
+	if err != nil {
+		return err
+	}
+
+	portStr := strconv.Itoa(port)
+	id, err := d.runContainer(ctx, "-p", portStr+":"+portStr, mongoImage, "--replSet", "rs0", "--bind_ip_all",
+		"--port", portStr)
+	if err != nil {
+		return fmt.Errorf("start mongodb failed, err: %v", err)
+	}
+
+	initRs := fmt.Sprintf(`rs.initiate({_id: "rs0", members: [{_id: 0, host: "127.0.0.1:%d"}]})`, port)
+	createUser := fmt.Sprintf(`db.getSiblingDB("cmdb").createUser({user: "%s", pwd: "%s", roles: ["root"]})`,
+		defaultUser, defaultPassword)
+	waitPrimary := `while (!db.isMaster().ismaster) { sleep(100) }`
+	for _, script := range []string{initRs, waitPrimary, createUser} {
+		err := retry(ctx, func() error {
+			return exec.CommandContext(ctx, "docker", "exec", id, "mongo", "--quiet", "--port", portStr, "--eval",
+				script).Run()
+		})
+		if err != nil {
+			return fmt.Errorf("init mongodb replica set failed, script: %s, err: %v", script, err)
+		}
+	}
+
+	d.MongoAddr = "127.0.0.1:" + portStr
+	d.MongoUser = defaultUser
+	d.MongoPassword = defaultPassword
+	return nil
+}
+
+func (d *Dependencies) startRedis(ctx context.Context) error {
+	port, err := freePort()
+	if err != nil {
+		return err
+	}
+
+	if _, err := d.runContainer(ctx, "-p", fmt.Sprintf("%d:6379", port), redisImage, "--requirepass",
+		defaultPassword); err != nil {
+		return fmt.Errorf("start redis failed, err: %v", err)
+	}
+
+	d.RedisAddr = fmt.Sprintf("127.0.0.1:%d", port)
+	d.RedisPassword = defaultPassword
+	return waitTCP(ctx, d.RedisAddr)
+}
+
+func (d *Dependencies) startZk(ctx context.Context) error {
+	port, err := freePort()
+	if err != nil {
+		return err
+	}
+
+	if _, err := d.runContainer(ctx, "-p", fmt.Sprintf("%d:2181", port), zkImage); err != nil {
+		return fmt.Errorf("start zookeeper failed, err: %v", err)
+	}
+
+	d.ZkAddr = fmt.Sprintf("127.0.0.1:%d", port)
+	return waitTCP(ctx, d.ZkAddr)
+}
+
+// runContainer runs a docker container in background and returns its id
+func (d *Dependencies) runContainer(ctx context.Context, args ...string) (string, error) {
+	args = append([]string{"run", "-d", "--rm"}, args...)
+	out, err := exec.CommandContext(ctx, "docker", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("docker %s failed, output: %s, err: %v", strings.Join(args, " "), out, err)
+	}
+
+	id := strings.TrimSpace(string(out))
+	d.containers = append(d.containers, id)
+	return id, nil
+}
+
+// Stop removes the docker containers started by the harness
+func (d *Dependencies) Stop() {
+	for _, id := range d.containers {
+		if out, err := exec.Command("docker", "rm", "-f", id).CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "remove container %s failed, output: %s, err: %v\n", id, out, err)
+		}
+	}
+	d.containers = nil
+}
+
+// freePort returns a free tcp port of the local host
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// waitTCP waits until the address can be connected
+func waitTCP(ctx context.Context, addr string) error {
+	return retry(ctx, func() error {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
+
+// retry runs the function until it succeeds or the context is done
+func retry(ctx context.Context, do func() error) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		err := do()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%v, last err: %v", ctx.Err(), err)
+		case <-ticker.C:
+		}
+	}
+}
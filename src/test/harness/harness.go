@@ -0,0 +1,330 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package harness spins up the cmdb services against the dockerized mongodb, redis and zookeeper for the end-to-end
+// tests. the services are run by the test binary itself, each in a child process since the identification, config
+// center and metrics registry of a service are process wide, so that the tests need no built binaries. a test package
+// uses it like this:
+//
+//	func TestMain(m *testing.M) {
+//		harness.Main(m)
+//	}
+//
+//	func TestTransfer(t *testing.T) {
+//		h, err := harness.Start(context.Background(), harness.Options{Services: []string{types.CC_MODULE_HOST}})
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//		defer h.Stop()
+//		...
+//	}
+//
+// the dependencies are started by docker unless they are set by the CC_TEST_* environment variables, see
+// StartDependencies.
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"configcenter/src/apimachinery"
+	"configcenter/src/apimachinery/discovery"
+	"configcenter/src/apimachinery/util"
+	"configcenter/src/common"
+	"configcenter/src/common/backbone/service_mange/zk"
+	"configcenter/src/common/core/cc/config"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/types"
+)
+
+const (
+	// defaultStartTimeout the default timeout to start the dependencies and the services
+	defaultStartTimeout = 5 * time.Minute
+	// zkSessionTimeout the session timeout of the zookeeper client used to discover the services
+	zkSessionTimeout = 40 * time.Second
+)
+
+// Options is the options to start the harness
+type Options struct {
+	// Services the scene servers to start besides the admin server, core service and api server that are always
+	// started, e.g. topo, host, proc.
+	Services []string
+	// ConfigValues overrides the values of the config template placeholders, e.g. __BK_CMDB_API_HOST__
+	ConfigValues map[string]string
+	// StartTimeout the timeout to start the dependencies and the services, default is 5 minutes.
+	StartTimeout time.Duration
+	// KeepDir keeps the config and log directory after the harness stops for debugging.
+	KeepDir bool
+}
+
+// Harness is the running services and their dependencies
+type Harness struct {
+	Deps *Dependencies
+	// Dir the directory of the configs and the logs of the services
+	Dir string
+
+	opts      Options
+	zkClient  *zk.ZkClient
+	clientSet apimachinery.ClientSetInterface
+	// addrs the addresses of the started services
+	addrs map[string]string
+	procs []*process
+}
+
+// process is a service running in a child process
+type process struct {
+	name string
+	cmd  *exec.Cmd
+	log  *os.File
+	// done is closed when the process exits
+	done chan struct{}
+}
+
+// Main runs the service if the test binary is started by the harness, otherwise runs the tests. it is called by the
+// TestMain of the test packages that use the harness.
+func Main(m *testing.M) {
+	RunServiceIfRequested()
+	os.Exit(m.Run())
+}
+
+// Start starts the dependencies and the services, and migrates the database. the harness must be stopped after use.
+func Start(ctx context.Context, opts Options) (*Harness, error) {
+	if opts.StartTimeout == 0 {
+		opts.StartTimeout = defaultStartTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.StartTimeout)
+	defer cancel()
+
+	h := &Harness{opts: opts, addrs: make(map[string]string)}
+	if err := h.start(ctx); err != nil {
+		h.Stop()
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *Harness) start(ctx context.Context) error {
+	var err error
+	h.Deps, err = StartDependencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	h.Dir, err = ioutil.TempDir("", "cmdb-harness-")
+	if err != nil {
+		return err
+	}
+
+	migrateConf, err := renderConfig(h.Dir, h.Deps, h.opts.ConfigValues)
+	if err != nil {
+		return err
+	}
+
+	h.zkClient = zk.NewZkClient(h.Deps.ZkAddr, zkSessionTimeout)
+	if err := h.zkClient.Start(); err != nil {
+		return fmt.Errorf("connect zookeeper %s failed, err: %v", h.Deps.ZkAddr, err)
+	}
+
+	disc, err := discovery.NewServiceDiscovery(h.zkClient)
+	if err != nil {
+		return fmt.Errorf("new service discovery failed, err: %v", err)
+	}
+
+	h.clientSet, err = apimachinery.NewApiMachinery(&util.APIMachineryConfig{QPS: 2000, Burst: 1000}, disc)
+	if err != nil {
+		return fmt.Errorf("new api machinery failed, err: %v", err)
+	}
+
+	// the admin server pushes the configs to zookeeper and initializes the database, which the others depend on.
+	if err := h.startServices(ctx, migrateConf, types.CC_MODULE_MIGRATE); err != nil {
+		return err
+	}
+	if err := h.migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := h.startServices(ctx, "", types.CC_MODULE_CORESERVICE); err != nil {
+		return err
+	}
+
+	services := append([]string{types.CC_MODULE_APISERVER}, h.opts.Services...)
+	return h.startServices(ctx, "", services...)
+}
+
+// startServices starts the services concurrently and waits until they are all ready
+func (h *Harness) startServices(ctx context.Context, exConfig string, names ...string) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(names))
+	for index, name := range names {
+		if _, exists := runners[name]; !exists {
+			return fmt.Errorf("service %s is not supported", name)
+		}
+
+		port, err := freePort()
+		if err != nil {
+			return err
+		}
+
+		conf := config.CCAPIConfig{
+			AddrPort:    "127.0.0.1:" + strconv.Itoa(port),
+			RegDiscover: h.Deps.ZkAddr,
+			ExConfig:    exConfig,
+		}
+		proc, err := h.startProcess(name, conf)
+		if err != nil {
+			return err
+		}
+		h.addrs[name] = conf.AddrPort
+
+		wg.Add(1)
+		go func(index int, proc *process, addr string) {
+			defer wg.Done()
+			errs[index] = h.waitReady(ctx, proc, addr)
+		}(index, proc, conf.AddrPort)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startProcess runs the service by the test binary in a child process, its output is written to <dir>/logs.
+func (h *Harness) startProcess(name string, conf config.CCAPIConfig) (*process, error) {
+	args, err := json.Marshal(serviceArgs{Name: name, Conf: conf})
+	if err != nil {
+		return nil, err
+	}
+
+	logDir := filepath.Join(h.Dir, "logs")
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return nil, err
+	}
+	log, err := os.Create(filepath.Join(logDir, name+".log"))
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), serviceEnv+"="+string(args))
+	cmd.Stdout = log
+	cmd.Stderr = log
+	if err := cmd.Start(); err != nil {
+		log.Close()
+		return nil, fmt.Errorf("start service %s failed, err: %v", name, err)
+	}
+
+	proc := &process{name: name, cmd: cmd, log: log, done: make(chan struct{})}
+	go func() {
+		_ = cmd.Wait()
+		close(proc.done)
+	}()
+	h.procs = append(h.procs, proc)
+	return proc, nil
+}
+
+// waitReady waits until the service is registered to zookeeper and listening
+func (h *Harness) waitReady(ctx context.Context, proc *process, addr string) error {
+	path := fmt.Sprintf("%s/%s", types.CC_SERV_BASEPATH, proc.name)
+	err := retry(ctx, func() error {
+		select {
+		case <-proc.done:
+			return fmt.Errorf("service %s exited, see %s", proc.name, proc.log.Name())
+		default:
+		}
+
+		children, err := h.zkClient.Client().GetChildren(path)
+		if err != nil {
+			return err
+		}
+		if len(children) == 0 {
+			return fmt.Errorf("service %s is not registered", proc.name)
+		}
+		return waitTCP(ctx, addr)
+	})
+	if err != nil {
+		return fmt.Errorf("wait service %s ready failed, err: %v", proc.name, err)
+	}
+	return nil
+}
+
+// migrate initializes the database by the admin server
+func (h *Harness) migrate(ctx context.Context) error {
+	var resp *metadata.Response
+	err := retry(ctx, func() error {
+		var err error
+		resp, err = h.clientSet.AdminServer().Migrate(ctx, common.BKDefaultOwnerID, "community", h.Header())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("migrate failed, err: %v", err)
+	}
+	if err := resp.CCError(); err != nil {
+		return fmt.Errorf("migrate failed, err: %v", err)
+	}
+	return nil
+}
+
+// ClientSet returns the client set to call the services
+func (h *Harness) ClientSet() apimachinery.ClientSetInterface {
+	return h.clientSet
+}
+
+// Addr returns the address of the service, it is empty if the service is not started.
+func (h *Harness) Addr(service string) string {
+	return h.addrs[service]
+}
+
+// Header returns the header of the requests issued by the admin user of the default supplier account
+func (h *Harness) Header() http.Header {
+	header := make(http.Header)
+	header.Set(common.BKHTTPOwnerID, common.BKDefaultOwnerID)
+	header.Set(common.BKHTTPHeaderUser, "admin")
+	header.Set(common.BKHTTPLanguage, "en")
+	header.Set("Content-Type", "application/json")
+	return header
+}
+
+// Stop stops the services and the dependencies started by the harness
+func (h *Harness) Stop() {
+	for _, proc := range h.procs {
+		_ = proc.cmd.Process.Kill()
+		<-proc.done
+		proc.log.Close()
+	}
+	h.procs = nil
+
+	if h.zkClient != nil {
+		_ = h.zkClient.Stop()
+	}
+
+	if h.Deps != nil {
+		h.Deps.Stop()
+	}
+
+	if h.Dir != "" && !h.opts.KeepDir {
+		_ = os.RemoveAll(h.Dir)
+	}
+}
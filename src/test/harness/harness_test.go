@@ -0,0 +1,129 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/types"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestMain(m *testing.M) {
+	Main(m)
+}
+
+func TestRenderConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cmdb-harness-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	deps := &Dependencies{MongoAddr: "127.0.0.1:27017", MongoUser: "cc", MongoPassword: "cc",
+		RedisAddr: "127.0.0.1:6379", RedisPassword: "cc", ZkAddr: "127.0.0.1:2181"}
+	migrateConf, err := renderConfig(dir, deps, nil)
+	if err != nil {
+		t.Fatalf("render config failed, err: %v", err)
+	}
+
+	confDir := filepath.Dir(migrateConf)
+	for _, name := range []string{"migrate.yaml", "common.yaml", "mongodb.yaml", "redis.yaml"} {
+		content, err := ioutil.ReadFile(filepath.Join(confDir, name))
+		if err != nil {
+			t.Fatalf("read %s failed, err: %v", name, err)
+		}
+		if placeholderRegexp.Match(content) {
+			t.Errorf("%s has placeholders not rendered", name)
+		}
+		if err := yaml.Unmarshal(content, new(map[string]interface{})); err != nil {
+			t.Errorf("%s is not valid yaml, err: %v", name, err)
+		}
+	}
+
+	mongoConf, _ := ioutil.ReadFile(filepath.Join(confDir, "mongodb.yaml"))
+	if !strings.Contains(string(mongoConf), "host: 127.0.0.1\n  port: 27017") {
+		t.Errorf("mongodb address is not rendered, config: %s", mongoConf)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "cmdb", "errors")); err != nil {
+		t.Errorf("errors resources are not linked, err: %v", err)
+	}
+}
+
+// TestTransferHost is an example of the end-to-end test, it is skipped if neither docker nor the dependencies are
+// available.
+func TestTransferHost(t *testing.T) {
+	if testing.Short() || (!DependenciesConfigured() && !DockerAvailable()) {
+		t.Skip("the dependencies are not available")
+	}
+
+	ctx := context.Background()
+	h, err := Start(ctx, Options{Services: []string{types.CC_MODULE_TOPO, types.CC_MODULE_HOST}})
+	if err != nil {
+		t.Fatalf("start harness failed, err: %v", err)
+	}
+	defer h.Stop()
+
+	bizID, err := h.CreateBusiness(ctx, "harness_biz")
+	if err != nil {
+		t.Fatalf("create business failed, err: %v", err)
+	}
+	setID, err := h.CreateSet(ctx, bizID, "harness_set")
+	if err != nil {
+		t.Fatalf("create set failed, err: %v", err)
+	}
+	moduleID, err := h.CreateModule(ctx, bizID, setID, "harness_module")
+	if err != nil {
+		t.Fatalf("create module failed, err: %v", err)
+	}
+
+	hostIDs, err := h.AddHostsToResourcePool(ctx, "127.0.0.1", "127.0.0.2")
+	if err != nil {
+		t.Fatalf("add hosts failed, err: %v", err)
+	}
+	if err := h.AssignHostsToBusiness(ctx, bizID, hostIDs); err != nil {
+		t.Fatalf("assign hosts to business failed, err: %v", err)
+	}
+	if err := h.TransferHostsToModules(ctx, bizID, hostIDs, []int64{moduleID}); err != nil {
+		t.Fatalf("transfer hosts failed, err: %v", err)
+	}
+
+	option := metadata.ListHostsParameter{
+		ModuleIDs: []int64{moduleID},
+		Fields:    []string{common.BKHostIDField},
+		Page:      metadata.BasePage{Limit: 10},
+	}
+	result := new(metadata.ListHostResult)
+	resp := &metadata.Response{Data: result}
+	path := fmt.Sprintf("/api/v3/hosts/app/%d/list_hosts", bizID)
+	if err := h.Do(ctx, http.MethodPost, path, option, resp); err != nil {
+		t.Fatalf("list hosts failed, err: %v", err)
+	}
+	if err := resp.CCError(); err != nil {
+		t.Fatalf("list hosts failed, err: %v", err)
+	}
+	if result.Count != len(hostIDs) {
+		t.Errorf("expect %d hosts in the module, but got %d", len(hostIDs), result.Count)
+	}
+}
@@ -0,0 +1,197 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package harness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"configcenter/src/common"
+	"configcenter/src/common/mapstr"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/types"
+	"configcenter/src/common/util"
+)
+
+// Do issues the api call to the api server like the external callers, e.g. Do(ctx, http.MethodPost,
+// "/api/v3/findmany/hosts/search/resource", body, result). the body is encoded to json if it is not nil, and the
+// response is decoded into the result if it is not nil.
+func (h *Harness) Do(ctx context.Context, method, path string, body, result interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, "http://"+h.Addr(types.CC_MODULE_APISERVER)+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header = h.Header()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s failed, status: %d, body: %s", method, path, resp.StatusCode, data)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// CreateBusiness creates a business maintained by the admin user and returns its id
+func (h *Harness) CreateBusiness(ctx context.Context, name string) (int64, error) {
+	data := map[string]interface{}{
+		common.BKAppNameField: name,
+		"bk_biz_maintainer":   "admin",
+		"life_cycle":          "2",
+		"language":            "1",
+		"time_zone":           "Asia/Shanghai",
+	}
+	resp, err := h.clientSet.ApiServer().CreateBiz(ctx, common.BKDefaultOwnerID, h.Header(), data)
+	if err != nil {
+		return 0, err
+	}
+	if err := resp.CCError(); err != nil {
+		return 0, err
+	}
+	return util.GetInt64ByInterface(resp.Data[common.BKAppIDField])
+}
+
+// CreateSet creates a set under the business and returns its id
+func (h *Harness) CreateSet(ctx context.Context, bizID int64, name string) (int64, error) {
+	data := mapstr.MapStr{
+		common.BKSetNameField:  name,
+		common.BKInstParentStr: bizID,
+		common.BKAppIDField:    bizID,
+	}
+	set, err := h.clientSet.TopoServer().Instance().CreateSet(ctx, bizID, h.Header(), data)
+	if err != nil {
+		return 0, err
+	}
+	return util.GetInt64ByInterface(set[common.BKSetIDField])
+}
+
+// CreateModule creates a module without service template under the set and returns its id
+func (h *Harness) CreateModule(ctx context.Context, bizID, setID int64, name string) (int64, error) {
+	data := map[string]interface{}{
+		common.BKModuleNameField:        name,
+		common.BKInstParentStr:          setID,
+		common.BKServiceCategoryIDField: 2,
+		common.BKServiceTemplateIDField: 0,
+	}
+	module, err := h.clientSet.TopoServer().Instance().CreateModule(ctx, bizID, setID, h.Header(), data)
+	if err != nil {
+		return 0, err
+	}
+	return util.GetInt64ByInterface(module[common.BKModuleIDField])
+}
+
+// CreateModel creates a model in the uncategorized classification
+func (h *Harness) CreateModel(ctx context.Context, objID, name string) error {
+	obj := metadata.Object{
+		ObjCls:     metadata.ClassificationUncategorizedID,
+		ObjIcon:    "icon-cc-default",
+		ObjectID:   objID,
+		ObjectName: name,
+		OwnerID:    common.BKDefaultOwnerID,
+		Creator:    "admin",
+	}
+	resp, err := h.clientSet.TopoServer().Object().CreateObject(ctx, h.Header(), obj)
+	if err != nil {
+		return err
+	}
+	return resp.CCError()
+}
+
+// AddHostsToResourcePool adds the hosts with the inner ips in the default cloud area to the resource pool, and returns
+// the ids of the hosts in the order of the ips. the host related helpers require the host server to be started.
+func (h *Harness) AddHostsToResourcePool(ctx context.Context, ips ...string) ([]int64, error) {
+	hosts := metadata.AddHostToResourcePoolHostList{HostInfo: make([]map[string]interface{}, len(ips))}
+	for index, ip := range ips {
+		hosts.HostInfo[index] = map[string]interface{}{
+			common.BKHostInnerIPField: ip,
+			common.BKCloudIDField:     common.BKDefaultDirSubArea,
+		}
+	}
+
+	resp, err := h.clientSet.HostServer().AddHostToResourcePool(ctx, h.Header(), hosts)
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.CCError(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		return nil, err
+	}
+	result := new(metadata.AddHostToResourcePoolResult)
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	if len(result.Error) != 0 {
+		return nil, fmt.Errorf("add host %s failed, err: %s", ips[result.Error[0].Index], result.Error[0].ErrorMsg)
+	}
+
+	hostIDs := make([]int64, len(ips))
+	for _, success := range result.Success {
+		hostIDs[success.Index] = success.HostID
+	}
+	return hostIDs, nil
+}
+
+// AssignHostsToBusiness assigns the hosts in the resource pool to the idle module of the business
+func (h *Harness) AssignHostsToBusiness(ctx context.Context, bizID int64, hostIDs []int64) error {
+	option := &metadata.DefaultModuleHostConfigParams{ApplicationID: bizID, HostIDs: hostIDs}
+	resp, err := h.clientSet.HostServer().AssignHostToApp(ctx, h.Header(), option)
+	if err != nil {
+		return err
+	}
+	return resp.CCError()
+}
+
+// TransferHostsToModules transfers the hosts of the business to the modules, which replaces their current modules.
+func (h *Harness) TransferHostsToModules(ctx context.Context, bizID int64, hostIDs, moduleIDs []int64) error {
+	option := map[string]interface{}{
+		common.BKAppIDField:    bizID,
+		common.BKHostIDField:   hostIDs,
+		common.BKModuleIDField: moduleIDs,
+		"is_increment":         false,
+	}
+	resp, err := h.clientSet.HostServer().TransferHostModule(ctx, h.Header(), option)
+	if err != nil {
+		return err
+	}
+	return resp.CCError()
+}
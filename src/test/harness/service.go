@@ -0,0 +1,139 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"configcenter/src/apiserver/app"
+	apioptions "configcenter/src/apiserver/app/options"
+	"configcenter/src/common"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/core/cc/config"
+	"configcenter/src/common/types"
+	adminapp "configcenter/src/scene_server/admin_server/app"
+	adminoptions "configcenter/src/scene_server/admin_server/app/options"
+	_ "configcenter/src/scene_server/admin_server/upgrader/all"
+	hostapp "configcenter/src/scene_server/host_server/app"
+	hostoptions "configcenter/src/scene_server/host_server/app/options"
+	operationapp "configcenter/src/scene_server/operation_server/app"
+	operationoptions "configcenter/src/scene_server/operation_server/app/options"
+	procapp "configcenter/src/scene_server/proc_server/app"
+	procoptions "configcenter/src/scene_server/proc_server/app/options"
+	taskapp "configcenter/src/scene_server/task_server/app"
+	taskoptions "configcenter/src/scene_server/task_server/app/options"
+	topoapp "configcenter/src/scene_server/topo_server/app"
+	topooptions "configcenter/src/scene_server/topo_server/app/options"
+	cacheapp "configcenter/src/source_controller/cacheservice/app"
+	cacheoptions "configcenter/src/source_controller/cacheservice/app/options"
+	coreapp "configcenter/src/source_controller/coreservice/app"
+	coreoptions "configcenter/src/source_controller/coreservice/app/options"
+)
+
+// serviceEnv is the environment variable that tells the test binary to run the service instead of the tests, its
+// value is the json of serviceArgs.
+const serviceEnv = "CC_TEST_HARNESS_SERVICE"
+
+// serviceArgs is the arguments to run a service in the child process
+type serviceArgs struct {
+	Name string             `json:"name"`
+	Conf config.CCAPIConfig `json:"conf"`
+}
+
+type runner func(ctx context.Context, cancel context.CancelFunc, conf *config.CCAPIConfig) error
+
+// runners are the services that can be run by the harness, the admin server is named migrate as it is registered.
+var runners = map[string]runner{
+	types.CC_MODULE_MIGRATE: func(ctx context.Context, cancel context.CancelFunc, conf *config.CCAPIConfig) error {
+		op := adminoptions.NewServerOption()
+		op.ServConf = conf
+		return adminapp.Run(ctx, cancel, op)
+	},
+	types.CC_MODULE_CORESERVICE: func(ctx context.Context, cancel context.CancelFunc, conf *config.CCAPIConfig) error {
+		op := coreoptions.NewServerOption()
+		op.ServConf = conf
+		return coreapp.Run(ctx, cancel, op)
+	},
+	types.CC_MODULE_CACHESERVICE: func(ctx context.Context, cancel context.CancelFunc, conf *config.CCAPIConfig) error {
+		op := cacheoptions.NewServerOption()
+		op.ServConf = conf
+		return cacheapp.Run(ctx, cancel, op)
+	},
+	types.CC_MODULE_APISERVER: func(ctx context.Context, cancel context.CancelFunc, conf *config.CCAPIConfig) error {
+		op := apioptions.NewServerOption()
+		op.ServConf = conf
+		return app.Run(ctx, cancel, op)
+	},
+	types.CC_MODULE_TOPO: func(ctx context.Context, cancel context.CancelFunc, conf *config.CCAPIConfig) error {
+		op := topooptions.NewServerOption()
+		op.ServConf = conf
+		return topoapp.Run(ctx, cancel, op)
+	},
+	types.CC_MODULE_HOST: func(ctx context.Context, cancel context.CancelFunc, conf *config.CCAPIConfig) error {
+		op := hostoptions.NewServerOption()
+		op.ServConf = conf
+		return hostapp.Run(ctx, cancel, op)
+	},
+	types.CC_MODULE_PROC: func(ctx context.Context, cancel context.CancelFunc, conf *config.CCAPIConfig) error {
+		op := procoptions.NewServerOption()
+		op.ServConf = conf
+		return procapp.Run(ctx, cancel, op)
+	},
+	types.CC_MODULE_TASK: func(ctx context.Context, cancel context.CancelFunc, conf *config.CCAPIConfig) error {
+		op := taskoptions.NewServerOption()
+		op.ServConf = conf
+		return taskapp.Run(ctx, cancel, op)
+	},
+	types.CC_MODULE_OPERATION: func(ctx context.Context, cancel context.CancelFunc, conf *config.CCAPIConfig) error {
+		op := operationoptions.NewServerOption()
+		op.ServConf = conf
+		return operationapp.Run(ctx, cancel, op)
+	},
+}
+
+// RunServiceIfRequested runs the service and exits if the test binary is started by the harness to run a service,
+// it must be called at the beginning of TestMain, see Main.
+func RunServiceIfRequested() {
+	value := os.Getenv(serviceEnv)
+	if value == "" {
+		return
+	}
+
+	args := new(serviceArgs)
+	if err := json.Unmarshal([]byte(value), args); err != nil {
+		fmt.Fprintf(os.Stderr, "parse service args %s failed, err: %v\n", value, err)
+		os.Exit(1)
+	}
+
+	run, exists := runners[args.Name]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "service %s is not supported\n", args.Name)
+		os.Exit(1)
+	}
+
+	common.SetIdentification(args.Name)
+	blog.InitLogs()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := run(ctx, cancel, &args.Conf); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		blog.Errorf("process stopped by %v", err)
+		blog.CloseLogs()
+		os.Exit(1)
+	}
+	blog.CloseLogs()
+	os.Exit(0)
+}
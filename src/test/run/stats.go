@@ -103,6 +103,7 @@ type Metrics struct {
 	AverageDuration   float64
 	Percent85Duration float64
 	Percent95Duration float64
+	Percent99Duration float64
 
 	TotalRequest    int64
 	SucceedRequest  int64
@@ -127,6 +128,7 @@ func (m *Metrics) Format() string {
 	f += fmt.Sprintf("  Avg:     %sms\n", SetGreen(fmt.Sprintf("%.1f", m.AverageDuration)))
 	f += fmt.Sprintf("  P(85):   %sms\n", SetGreen(fmt.Sprintf("%.1f", m.Percent85Duration)))
 	f += fmt.Sprintf("  P(95):   %sms\n", SetGreen(fmt.Sprintf("%.1f", m.Percent95Duration)))
+	f += fmt.Sprintf("  P(99):   %sms\n", SetGreen(fmt.Sprintf("%.1f", m.Percent99Duration)))
 
 	return f + "\n"
 }
@@ -150,7 +152,7 @@ func (s *Statistic) CalculateMetrics() Metrics {
 	} else {
 		// The median of an even number of values is the average of the middle two.
 		if (s.TotalSucceed & 0x01) == 0 {
-			m.MedianDuration = (s.Values[s.TotalSucceed/2-1] + s.Values[s.TotalSucceed/2]) / 2 / float64(time.Millisecond)
+			m.MedianDuration = (s.Values[s.TotalSucceed/2-1] + s.Values[s.TotalSucceed/2]) / 2
 		} else {
 			m.MedianDuration = s.Values[s.TotalSucceed/2]
 		}
@@ -158,6 +160,7 @@ func (s *Statistic) CalculateMetrics() Metrics {
 	}
 	m.Percent85Duration = s.percent(0.85)
 	m.Percent95Duration = s.percent(0.95)
+	m.Percent99Duration = s.percent(0.99)
 
 	m.TotalRequest = s.TotalRequest
 	m.SucceedRequest = s.TotalSucceed
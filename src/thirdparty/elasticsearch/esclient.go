@@ -18,6 +18,8 @@ import (
 // EsSrv TODO
 type EsSrv struct {
 	Client *elastic.Client
+	// Reindexer rebuilds the indexes without downtime, it's nil if the client is not initialized.
+	Reindexer *Reindexer
 }
 
 // NewEsClient TODO
@@ -0,0 +1,491 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+
+	"github.com/olivere/elastic/v7"
+)
+
+var (
+	// ErrReindexRunning the index is being rebuilt by another reindex task.
+	ErrReindexRunning = errors.New("index is being reindexed")
+
+	// ErrReindexTaskNotFound the reindex task is not found.
+	ErrReindexTaskNotFound = errors.New("reindex task not found")
+)
+
+const (
+	// reindexPollInterval the interval to poll the progress of the elastic reindex task.
+	reindexPollInterval = 2 * time.Second
+
+	// reindexMaxCatchUpRounds the max catch up rounds until the document counts of the two indexes are the same.
+	reindexMaxCatchUpRounds = 5
+
+	// reindexScrollSize the number of the document ids compared in a batch when removing the deleted documents.
+	reindexScrollSize = 1000
+)
+
+// Reindexer rebuilds the elastic indexes without downtime. It copies a snapshot of the source index to a new index,
+// replays the documents changed by the monstache sync stream during the snapshot, verifies the document counts,
+// then atomically switches the alias to the new index, so the fulltext search is always available.
+// NOTE: the tasks are kept in memory, the progress can only be got from the server which starts the task.
+type Reindexer struct {
+	client *elastic.Client
+	lock   sync.RWMutex
+	// tasks reindex tasks, task id -> task.
+	tasks map[string]*reindexTask
+}
+
+type reindexTask struct {
+	info   metadata.FullTextReindexTask
+	cancel context.CancelFunc
+	// switched whether the alias has been switched to the new index, the task can not be canceled after it.
+	switched bool
+}
+
+// NewReindexer new an elastic reindexer.
+func NewReindexer(client *elastic.Client) *Reindexer {
+	return &Reindexer{
+		client: client,
+		tasks:  make(map[string]*reindexTask),
+	}
+}
+
+// Start create the new index and start the reindex task in background.
+func (r *Reindexer) Start(ctx context.Context, opt *metadata.FullTextReindexOption, rid string) (
+	*metadata.FullTextReindexTask, error) {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, task := range r.tasks {
+		if task.info.Index == opt.Index && task.info.Status == metadata.FullTextReindexStatusRunning {
+			return nil, ErrReindexRunning
+		}
+	}
+
+	aliases, err := r.client.Aliases().Index(opt.Index).Do(ctx)
+	if err != nil {
+		blog.Errorf("get elastic alias %s failed, err: %v, rid: %s", opt.Index, err, rid)
+		return nil, err
+	}
+
+	indexes := aliases.IndicesByAlias(opt.Index)
+	if len(indexes) != 1 {
+		return nil, fmt.Errorf("alias %s points to %d indexes, must be exactly one", opt.Index, len(indexes))
+	}
+	source := indexes[0]
+	target := fmt.Sprintf("%s_%s", opt.Index, opt.Version)
+
+	exist, err := r.client.IndexExists(target).Do(ctx)
+	if err != nil {
+		blog.Errorf("check elastic index %s existence failed, err: %v, rid: %s", target, err, rid)
+		return nil, err
+	}
+	if exist {
+		return nil, fmt.Errorf("index %s already exists", target)
+	}
+
+	body, err := r.targetIndexBody(ctx, source, opt.Metadata)
+	if err != nil {
+		blog.Errorf("get elastic index %s metadata failed, err: %v, rid: %s", source, err, rid)
+		return nil, err
+	}
+
+	if _, err := r.client.CreateIndex(target).BodyJson(body).Do(ctx); err != nil {
+		blog.Errorf("create elastic index %s failed, err: %v, rid: %s", target, err, rid)
+		return nil, err
+	}
+
+	now := time.Now()
+	taskCtx, cancel := context.WithCancel(context.Background())
+	task := &reindexTask{
+		info: metadata.FullTextReindexTask{
+			TaskID:      target,
+			Index:       opt.Index,
+			SourceIndex: source,
+			TargetIndex: target,
+			Status:      metadata.FullTextReindexStatusRunning,
+			Stage:       metadata.FullTextReindexStageSnapshot,
+			CreateTime:  now,
+			LastTime:    now,
+		},
+		cancel: cancel,
+	}
+	r.tasks[target] = task
+
+	go r.run(taskCtx, task, rid)
+
+	info := task.info
+	return &info, nil
+}
+
+// targetIndexBody returns the settings and mappings of the new index, copy the ones of the source index if the
+// metadata is not specified.
+func (r *Reindexer) targetIndexBody(ctx context.Context, source string, meta *metadata.ESIndexMetadata) (
+	interface{}, error) {
+
+	if meta != nil {
+		return meta, nil
+	}
+
+	mappings, err := r.client.GetMapping().Index(source).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := r.client.IndexGetSettings(source).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceMapping, ok := mappings[source].(map[string]interface{})
+	if !ok || settings[source] == nil {
+		return nil, fmt.Errorf("metadata of index %s not found", source)
+	}
+
+	indexSettings, _ := settings[source].Settings["index"].(map[string]interface{})
+	return map[string]interface{}{
+		"settings": map[string]interface{}{
+			"number_of_shards":   indexSettings["number_of_shards"],
+			"number_of_replicas": indexSettings["number_of_replicas"],
+		},
+		"mappings": sourceMapping["mappings"],
+	}, nil
+}
+
+// Progress returns the reindex task by its id.
+func (r *Reindexer) Progress(taskID string) (*metadata.FullTextReindexTask, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	task, exist := r.tasks[taskID]
+	if !exist {
+		return nil, ErrReindexTaskNotFound
+	}
+
+	info := task.info
+	return &info, nil
+}
+
+// List returns all the reindex tasks.
+func (r *Reindexer) List() []metadata.FullTextReindexTask {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	tasks := make([]metadata.FullTextReindexTask, 0, len(r.tasks))
+	for _, task := range r.tasks {
+		tasks = append(tasks, task.info)
+	}
+	return tasks
+}
+
+// Cancel cancel the running reindex task, the new index is deleted and the alias is kept unchanged.
+func (r *Reindexer) Cancel(taskID string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	task, exist := r.tasks[taskID]
+	if !exist || task.info.Status != metadata.FullTextReindexStatusRunning {
+		return ErrReindexTaskNotFound
+	}
+
+	if task.switched {
+		return fmt.Errorf("alias %s has been switched to index %s, can not be canceled", task.info.Index,
+			task.info.TargetIndex)
+	}
+
+	task.cancel()
+	return nil
+}
+
+// update updates the reindex task info with the handler under the lock.
+func (r *Reindexer) update(task *reindexTask, handler func(info *metadata.FullTextReindexTask)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	handler(&task.info)
+	task.info.LastTime = time.Now()
+}
+
+func (r *Reindexer) run(ctx context.Context, task *reindexTask, rid string) {
+	info := task.info
+	blog.Infof("start reindex %s from %s to %s, rid: %s", info.Index, info.SourceIndex, info.TargetIndex, rid)
+
+	err := r.rebuild(ctx, task, rid)
+	if err == nil {
+		r.update(task, func(info *metadata.FullTextReindexTask) {
+			info.Status = metadata.FullTextReindexStatusSucceed
+		})
+		blog.Infof("reindex %s to %s succeed, rid: %s", info.Index, info.TargetIndex, rid)
+		return
+	}
+
+	r.lock.RLock()
+	switched := task.switched
+	r.lock.RUnlock()
+
+	// the new index is in use after the alias is switched, it can not be deleted.
+	if !switched {
+		if _, delErr := r.client.DeleteIndex(info.TargetIndex).Do(context.Background()); delErr != nil {
+			blog.Errorf("delete elastic index %s failed, err: %v, rid: %s", info.TargetIndex, delErr, rid)
+		}
+	}
+
+	status := metadata.FullTextReindexStatusFailed
+	if ctx.Err() == context.Canceled {
+		status = metadata.FullTextReindexStatusCanceled
+	}
+	r.update(task, func(info *metadata.FullTextReindexTask) {
+		info.Status = status
+		info.Message = err.Error()
+	})
+	blog.Errorf("reindex %s to %s %s, err: %v, rid: %s", info.Index, info.TargetIndex, status, err, rid)
+}
+
+// rebuild executes the stages of the reindex task.
+func (r *Reindexer) rebuild(ctx context.Context, task *reindexTask, rid string) error {
+	source, target := task.info.SourceIndex, task.info.TargetIndex
+
+	// copy the snapshot, the external version keeps the versions of the source documents, so that the documents
+	// changed after the snapshot could be replayed by the later copies.
+	if err := r.copy(ctx, task, metadata.FullTextReindexStageSnapshot, rid); err != nil {
+		return err
+	}
+
+	// the monstache still writes the changes to the source index by the alias, replay them until the new index
+	// catches up with the source index.
+	matched := false
+	for round := 1; round <= reindexMaxCatchUpRounds; round++ {
+		r.update(task, func(info *metadata.FullTextReindexTask) {
+			info.CatchUpRounds = round
+		})
+
+		if err := r.copy(ctx, task, metadata.FullTextReindexStageCatchUp, rid); err != nil {
+			return err
+		}
+
+		if err := r.removeDeleted(ctx, source, target); err != nil {
+			return fmt.Errorf("remove deleted documents failed, %v", err)
+		}
+
+		var err error
+		matched, err = r.verify(ctx, task)
+		if err != nil {
+			return err
+		}
+
+		if matched {
+			break
+		}
+	}
+
+	if !matched {
+		return fmt.Errorf("document counts of %s and %s are still different after %d catch up rounds", source,
+			target, reindexMaxCatchUpRounds)
+	}
+
+	r.update(task, func(info *metadata.FullTextReindexTask) {
+		info.Stage = metadata.FullTextReindexStageSwitch
+	})
+
+	// remove and add the alias in one request, the elastic executes them atomically. it's not interrupted by the
+	// cancellation, otherwise the switched new index may be deleted.
+	_, err := r.client.Alias().Action(
+		elastic.NewAliasRemoveAction(task.info.Index).Index(source),
+		elastic.NewAliasAddAction(task.info.Index).Index(target),
+	).Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("switch alias failed, %v", err)
+	}
+
+	r.lock.Lock()
+	task.switched = true
+	r.lock.Unlock()
+
+	// replay the documents written to the source index between the last catch up and the switch. the source index
+	// is not written anymore, and the newer documents in the new index are skipped as version conflicts.
+	return r.copy(context.Background(), task, metadata.FullTextReindexStageFinish, rid)
+}
+
+// copy copies the documents from the source index to the new index by the elastic reindex task, and polls its
+// progress until it's done.
+func (r *Reindexer) copy(ctx context.Context, task *reindexTask, stage, rid string) error {
+	r.update(task, func(info *metadata.FullTextReindexTask) {
+		info.Stage = stage
+		info.Total = 0
+		info.Copied = 0
+	})
+
+	started, err := r.client.Reindex().
+		Source(elastic.NewReindexSource().Index(task.info.SourceIndex)).
+		Destination(elastic.NewReindexDestination().Index(task.info.TargetIndex).VersionType("external")).
+		ProceedOnVersionConflict().
+		DoAsync(ctx)
+	if err != nil {
+		return fmt.Errorf("start %s reindex failed, %v", stage, err)
+	}
+	blog.V(4).Infof("reindex %s stage %s started, es task: %s, rid: %s", task.info.Index, stage, started.TaskId, rid)
+
+	ticker := time.NewTicker(reindexPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if _, err := r.client.TasksCancel().TaskId(started.TaskId).Do(context.Background()); err != nil {
+				blog.Errorf("cancel es task %s failed, err: %v, rid: %s", started.TaskId, err, rid)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		resp, err := r.client.TasksGetTask().TaskId(started.TaskId).Do(ctx)
+		if err != nil {
+			blog.Errorf("get es task %s failed, err: %v, rid: %s", started.TaskId, err, rid)
+			continue
+		}
+
+		if resp.Task != nil {
+			status := parseReindexStatus(resp.Task.Status)
+			r.update(task, func(info *metadata.FullTextReindexTask) {
+				info.Total = status.Total
+				info.Copied = status.Created + status.Updated + status.VersionConflicts
+			})
+		}
+
+		if !resp.Completed {
+			continue
+		}
+
+		if resp.Error != nil {
+			return fmt.Errorf("%s reindex failed, %s: %s", stage, resp.Error.Type, resp.Error.Reason)
+		}
+		return nil
+	}
+}
+
+// reindexStatus is the status of the elastic reindex task.
+type reindexStatus struct {
+	Total            int64 `json:"total"`
+	Created          int64 `json:"created"`
+	Updated          int64 `json:"updated"`
+	VersionConflicts int64 `json:"version_conflicts"`
+}
+
+func parseReindexStatus(raw interface{}) reindexStatus {
+	status := reindexStatus{}
+	js, err := json.Marshal(raw)
+	if err != nil {
+		return status
+	}
+	_ = json.Unmarshal(js, &status)
+	return status
+}
+
+// removeDeleted removes the documents in the new index which are deleted from the source index during the snapshot,
+// since the reindex only copies the existing documents.
+func (r *Reindexer) removeDeleted(ctx context.Context, source, target string) error {
+	if _, err := r.client.Refresh(source, target).Do(ctx); err != nil {
+		return err
+	}
+
+	scroll := r.client.Scroll(target).Size(reindexScrollSize).FetchSource(false)
+	defer scroll.Clear(context.Background())
+
+	for {
+		result, err := scroll.Do(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, len(result.Hits.Hits))
+		for _, hit := range result.Hits.Hits {
+			ids = append(ids, hit.Id)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		existing, err := r.client.Search(source).Query(elastic.NewIdsQuery().Ids(ids...)).FetchSource(false).
+			Size(len(ids)).Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		existIDs := make(map[string]struct{}, len(existing.Hits.Hits))
+		for _, hit := range existing.Hits.Hits {
+			existIDs[hit.Id] = struct{}{}
+		}
+
+		bulk := r.client.Bulk().Index(target)
+		for _, id := range ids {
+			if _, exist := existIDs[id]; !exist {
+				bulk.Add(elastic.NewBulkDeleteRequest().Id(id))
+			}
+		}
+
+		if bulk.NumberOfActions() == 0 {
+			continue
+		}
+
+		resp, err := bulk.Do(ctx)
+		if err != nil {
+			return err
+		}
+		if resp.Errors {
+			return fmt.Errorf("delete documents from %s failed", target)
+		}
+	}
+}
+
+// verify compares the document counts of the source index and the new index.
+func (r *Reindexer) verify(ctx context.Context, task *reindexTask) (bool, error) {
+	r.update(task, func(info *metadata.FullTextReindexTask) {
+		info.Stage = metadata.FullTextReindexStageVerify
+	})
+
+	if _, err := r.client.Refresh(task.info.SourceIndex, task.info.TargetIndex).Do(ctx); err != nil {
+		return false, err
+	}
+
+	sourceCount, err := r.client.Count(task.info.SourceIndex).Do(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	targetCount, err := r.client.Count(task.info.TargetIndex).Do(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	r.update(task, func(info *metadata.FullTextReindexTask) {
+		info.SourceCount = sourceCount
+		info.TargetCount = targetCount
+	})
+	return sourceCount == targetCount, nil
+}
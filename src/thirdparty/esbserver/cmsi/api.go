@@ -0,0 +1,71 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmsi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"configcenter/src/common/metadata"
+	"configcenter/src/thirdparty/esbserver/esbutil"
+)
+
+// SendMail send mail with attachments to the receivers
+func (c *cmsi) SendMail(ctx context.Context, header http.Header, mail *metadata.CmsiMail) error {
+	resp := new(metadata.EsbBaseResponse)
+	params := &esbCmsiMailParams{
+		EsbCommParams: esbutil.GetEsbRequestParams(c.config.GetConfig(), header),
+		CmsiMail:      mail,
+	}
+
+	err := c.client.Post().
+		SubResourcef("/v2/cmsi/send_mail/").
+		WithContext(ctx).
+		WithHeaders(header).
+		Body(params).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+	if !resp.Result || resp.Code != 0 {
+		return fmt.Errorf("code: %d, message: %s", resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// SendMsg send message to the receivers by the message type, such as weixin, rtx
+func (c *cmsi) SendMsg(ctx context.Context, header http.Header, msg *metadata.CmsiMsg) error {
+	resp := new(metadata.EsbBaseResponse)
+	params := &esbCmsiMsgParams{
+		EsbCommParams: esbutil.GetEsbRequestParams(c.config.GetConfig(), header),
+		CmsiMsg:       msg,
+	}
+
+	err := c.client.Post().
+		SubResourcef("/v2/cmsi/send_msg/").
+		WithContext(ctx).
+		WithHeaders(header).
+		Body(params).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+	if !resp.Result || resp.Code != 0 {
+		return fmt.Errorf("code: %d, message: %s", resp.Code, resp.Message)
+	}
+
+	return nil
+}
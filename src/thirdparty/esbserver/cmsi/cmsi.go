@@ -0,0 +1,53 @@
+// Package cmsi is the client of the blueking message notification component
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmsi
+
+import (
+	"context"
+	"net/http"
+
+	"configcenter/src/apimachinery/rest"
+	"configcenter/src/common/metadata"
+	"configcenter/src/thirdparty/esbserver/esbutil"
+)
+
+// CmsiClientInterface is the client of the blueking message notification component
+type CmsiClientInterface interface {
+	// SendMail send mail with attachments to the receivers
+	SendMail(ctx context.Context, header http.Header, mail *metadata.CmsiMail) error
+	// SendMsg send message to the receivers by the message type, such as weixin, rtx
+	SendMsg(ctx context.Context, header http.Header, msg *metadata.CmsiMsg) error
+}
+
+// NewCmsiClientInterface new a cmsi client
+func NewCmsiClientInterface(client rest.ClientInterface, config *esbutil.EsbConfigSrv) CmsiClientInterface {
+	return &cmsi{
+		client: client,
+		config: config,
+	}
+}
+
+type cmsi struct {
+	config *esbutil.EsbConfigSrv
+	client rest.ClientInterface
+}
+
+type esbCmsiMailParams struct {
+	*esbutil.EsbCommParams `json:",inline"`
+	*metadata.CmsiMail     `json:",inline"`
+}
+
+type esbCmsiMsgParams struct {
+	*esbutil.EsbCommParams `json:",inline"`
+	*metadata.CmsiMsg      `json:",inline"`
+}
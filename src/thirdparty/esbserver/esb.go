@@ -19,6 +19,7 @@ import (
 	"configcenter/src/apimachinery/flowctrl"
 	"configcenter/src/apimachinery/rest"
 	"configcenter/src/apimachinery/util"
+	"configcenter/src/thirdparty/esbserver/cmsi"
 	"configcenter/src/thirdparty/esbserver/esbutil"
 	"configcenter/src/thirdparty/esbserver/gse"
 	"configcenter/src/thirdparty/esbserver/iam"
@@ -34,6 +35,7 @@ type EsbClientInterface interface {
 	User() user.UserClientInterface
 	NodemanSrv() nodeman.NodeManClientInterface
 	IamSrv() iam.IamClientInterface
+	CmsiSrv() cmsi.CmsiClientInterface
 }
 
 type esbsrv struct {
@@ -42,6 +44,7 @@ type esbsrv struct {
 	userSrv    user.UserClientInterface
 	nodemanSrv nodeman.NodeManClientInterface
 	iamSrv     iam.IamClientInterface
+	cmsiSrv    cmsi.CmsiClientInterface
 	sync.RWMutex
 	esbConfig *esbutil.EsbConfigSrv
 	c         *util.Capability
@@ -115,6 +118,20 @@ func (e *esbsrv) IamSrv() iam.IamClientInterface {
 	return srv
 }
 
+// CmsiSrv returns the message notification client
+func (e *esbsrv) CmsiSrv() cmsi.CmsiClientInterface {
+	e.RLock()
+	srv := e.cmsiSrv
+	e.RUnlock()
+	if nil == srv {
+		e.Lock()
+		e.cmsiSrv = cmsi.NewCmsiClientInterface(e.client, e.esbConfig)
+		srv = e.cmsiSrv
+		e.Unlock()
+	}
+	return srv
+}
+
 // GetEsbConfigSrv TODO
 func (e *esbsrv) GetEsbConfigSrv() *esbutil.EsbConfigSrv {
 	return e.esbConfig
@@ -0,0 +1,150 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prober
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"configcenter/src/apimachinery/flowctrl"
+	"configcenter/src/apimachinery/rest"
+	apiutil "configcenter/src/apimachinery/util"
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ExternalConfig the config of the external prober, which probes the hosts by ping or connecting to the tcp ports.
+type ExternalConfig struct {
+	// Addrs the addresses of the external prober, start with http:// or https://
+	Addrs []string
+	// Path the url path of the probe api of the external prober, e.g. /api/v1/probe
+	Path string
+	// Token as a header param for sending the probe request to the external prober
+	Token string
+	TLS   *apiutil.TLSClientConfig
+}
+
+// Validate validate the external prober config fields
+func (c *ExternalConfig) Validate() error {
+	if len(c.Addrs) == 0 {
+		return errors.New("external prober addrs can't be empty")
+	}
+
+	if c.Path == "" {
+		return errors.New("external prober path can't be empty")
+	}
+
+	return nil
+}
+
+// externalProber sends the probe request to the external prober, the prober responds in the format of cmdb api.
+type externalProber struct {
+	client      rest.ClientInterface
+	config      ExternalConfig
+	basicHeader http.Header
+}
+
+func newExternalProber(config ExternalConfig, reg prometheus.Registerer) (*externalProber, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := apiutil.NewClient(config.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &apiutil.Capability{
+		Client:     client,
+		Discover:   &probeDiscovery{servers: config.Addrs},
+		Throttle:   flowctrl.NewRateLimiter(100, 100),
+		MetricOpts: apiutil.MetricOption{Register: reg},
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("Accept", "application/json")
+	if config.Token != "" {
+		header.Set(common.BKHTTPProbeToken, config.Token)
+	}
+
+	return &externalProber{
+		client:      rest.NewRESTClient(c, "/"),
+		config:      config,
+		basicHeader: header,
+	}, nil
+}
+
+// Support the external prober supports the ping and the port probe.
+func (e *externalProber) Support(method string) bool {
+	return method == metadata.HostProbeMethodPing || method == metadata.HostProbeMethodPort
+}
+
+// Probe sends the probe request to the external prober.
+func (e *externalProber) Probe(ctx context.Context, h http.Header, req *metadata.HostProbeRequest) (
+	[]metadata.HostProbeResult, error) {
+
+	header := util.CloneHeader(h)
+	util.CopyHeader(e.basicHeader, header)
+
+	resp := new(metadata.HostProbeResponse)
+	err := e.client.Post().
+		WithContext(ctx).
+		Body(req).
+		SubResourcef(e.config.Path).
+		WithHeaders(header).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Result {
+		return nil, errors.New(resp.ErrMsg)
+	}
+
+	return resp.Data, nil
+}
+
+// probeDiscovery returns the addresses of the external prober in turn.
+type probeDiscovery struct {
+	servers []string
+	index   int
+	sync.Mutex
+}
+
+// GetServers returns the servers starting from the next one.
+func (d *probeDiscovery) GetServers() ([]string, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	num := len(d.servers)
+	if num == 0 {
+		return []string{}, errors.New("there is no external prober can be used")
+	}
+
+	d.index = (d.index + 1) % num
+	servers := make([]string, 0, num)
+	servers = append(servers, d.servers[d.index:]...)
+	return append(servers, d.servers[:d.index]...), nil
+}
+
+// GetServersChan the servers of the external prober are static.
+func (d *probeDiscovery) GetServersChan() chan []string {
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prober
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+	"configcenter/src/thirdparty/gse/client"
+	getstatus "configcenter/src/thirdparty/gse/get_agent_state_forsyncdata"
+
+	"github.com/tidwall/gjson"
+)
+
+// agentAliveStatus the bk_agent_alive value of the agent which is on
+const agentAliveStatus = 1
+
+// gseAgentProber probes the hosts by the gse agent status, a host is reachable if the agent of any of its inner ips
+// is alive.
+type gseAgentProber struct {
+	client *client.GseApiServerClient
+}
+
+// Support the gse agent prober supports the agent probe.
+func (g *gseAgentProber) Support(method string) bool {
+	return method == metadata.HostProbeMethodAgent
+}
+
+// Probe gets the agent status of the targets from the gse api server.
+func (g *gseAgentProber) Probe(ctx context.Context, _ http.Header, req *metadata.HostProbeRequest) (
+	[]metadata.HostProbeResult, error) {
+
+	statusReq := &getstatus.AgentStatusRequest{Hosts: make([]*getstatus.CacheIPInfo, 0)}
+	for _, target := range req.Targets {
+		cloudID := strconv.FormatInt(target.CloudID, 10)
+		for _, ip := range splitInnerIP(target.InnerIP) {
+			statusReq.Hosts = append(statusReq.Hosts, &getstatus.CacheIPInfo{GseCompositeID: cloudID, IP: ip})
+		}
+	}
+
+	results := make([]metadata.HostProbeResult, 0, len(req.Targets))
+	if len(statusReq.Hosts) == 0 {
+		return results, nil
+	}
+
+	resp, err := g.client.GetAgentStatus(ctx, statusReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.BkErrorCode != common.CCSuccess {
+		return nil, fmt.Errorf("get agent status failed, code: %d, msg: %s", resp.BkErrorCode, resp.BkErrorMsg)
+	}
+
+	for _, target := range req.Targets {
+		ips := splitInnerIP(target.InnerIP)
+		if len(ips) == 0 {
+			continue
+		}
+
+		result := metadata.HostProbeResult{HostID: target.HostID, Status: metadata.HostProbeUnreachable}
+		cloudID := strconv.FormatInt(target.CloudID, 10)
+		for _, ip := range ips {
+			if gjson.Get(resp.Result_[cloudID+":"+ip], "bk_agent_alive").Int() == agentAliveStatus {
+				result.Status = metadata.HostProbeReachable
+				result.Message = "agent of " + ip + " is alive"
+				break
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// splitInnerIP splits the inner ip field of the host into the ips
+func splitInnerIP(innerIP string) []string {
+	ips := make([]string, 0)
+	for _, ip := range strings.Split(innerIP, ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
@@ -0,0 +1,194 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package prober probes the connectivity of the hosts through the external prober or the gse agent status.
+package prober
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"configcenter/src/apimachinery/util"
+	cc "configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/metadata"
+	"configcenter/src/thirdparty/gse/client"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultProbeTimeout the default timeout of a probe request.
+const defaultProbeTimeout = 30 * time.Second
+
+// ErrMethodNotSupported the probe method is not enabled by any prober.
+var ErrMethodNotSupported = errors.New("probe method is not supported")
+
+// Prober probes the connectivity of the hosts.
+type Prober interface {
+	// Support returns if the probe method is supported.
+	Support(method string) bool
+	// Probe probes the targets by the method.
+	Probe(ctx context.Context, h http.Header, req *metadata.HostProbeRequest) ([]metadata.HostProbeResult, error)
+}
+
+// Config the config of the host prober
+type Config struct {
+	// Timeout the timeout of a probe request
+	Timeout time.Duration
+	// External the config of the external prober, it is disabled if its addrs is empty
+	External ExternalConfig
+	// Gse the config to connect to the gse api server, the agent probe is disabled if it is nil
+	Gse *client.GseConnConfig
+}
+
+// ParseConfig parse the host prober config with the config prefix, e.g. hostServer.probe
+func ParseConfig(prefix string) (*Config, error) {
+	conf := &Config{Timeout: defaultProbeTimeout}
+
+	if cc.IsExist(prefix + ".timeoutSeconds") {
+		seconds, err := cc.Int(prefix + ".timeoutSeconds")
+		if err != nil {
+			blog.Errorf("get host probe timeoutSeconds config failed, err: %v", err)
+			return nil, err
+		}
+		if seconds > 0 {
+			conf.Timeout = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if cc.IsExist(prefix + ".external.addrs") {
+		addrs, err := cc.StringSlice(prefix + ".external.addrs")
+		if err != nil {
+			blog.Errorf("get host probe external addrs config failed, err: %v", err)
+			return nil, err
+		}
+		conf.External.Addrs = addrs
+		conf.External.Path, _ = cc.String(prefix + ".external.path")
+		conf.External.Token, _ = cc.String(prefix + ".external.token")
+
+		tlsConf, err := util.NewTLSClientConfigFromConfig(prefix + ".external.tls")
+		if err != nil {
+			blog.Errorf("get host probe external tls config failed, err: %v", err)
+			return nil, err
+		}
+		conf.External.TLS = &tlsConf
+	}
+
+	gseAgent, _ := cc.Bool(prefix + ".gseAgent")
+	if gseAgent {
+		gseConf, err := client.NewGseConnConfig("gse.apiServer")
+		if err != nil {
+			blog.Errorf("get gse apiServer config for host probe failed, err: %v", err)
+			return nil, err
+		}
+		conf.Gse = gseConf
+	}
+
+	return conf, nil
+}
+
+// NewProber new a host prober, which dispatches the probe request to the backend prober supporting the method.
+func NewProber(conf *Config, reg prometheus.Registerer) (Prober, error) {
+	p := &prober{timeout: conf.Timeout, backends: make([]Prober, 0)}
+	if p.timeout <= 0 {
+		p.timeout = defaultProbeTimeout
+	}
+
+	if len(conf.External.Addrs) != 0 {
+		external, err := newExternalProber(conf.External, reg)
+		if err != nil {
+			return nil, err
+		}
+		p.backends = append(p.backends, external)
+	}
+
+	if conf.Gse != nil {
+		gseClient, err := client.NewGseApiServerClient(conf.Gse.Endpoints, conf.Gse.TLSConf)
+		if err != nil {
+			return nil, err
+		}
+		p.backends = append(p.backends, &gseAgentProber{client: gseClient})
+	}
+
+	return p, nil
+}
+
+// prober dispatches the probe request to the backends, and normalizes the results of them.
+type prober struct {
+	timeout  time.Duration
+	backends []Prober
+}
+
+// Support returns if any backend supports the probe method.
+func (p *prober) Support(method string) bool {
+	return p.backend(method) != nil
+}
+
+// Probe probes the targets by the backend supporting the method, each target has exactly one result, the targets
+// that are not returned by the backend are regarded as unknown.
+func (p *prober) Probe(ctx context.Context, h http.Header, req *metadata.HostProbeRequest) (
+	[]metadata.HostProbeResult, error) {
+
+	backend := p.backend(req.Method)
+	if backend == nil {
+		return nil, ErrMethodNotSupported
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	results, err := backend.Probe(ctx, h, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizeResults(req.Targets, results), nil
+}
+
+func (p *prober) backend(method string) Prober {
+	for _, backend := range p.backends {
+		if backend.Support(method) {
+			return backend
+		}
+	}
+	return nil
+}
+
+// normalizeResults keeps the results of the targets in the order of the targets, the invalid status and the missing
+// results are set to unknown.
+func normalizeResults(targets []metadata.HostProbeTarget,
+	results []metadata.HostProbeResult) []metadata.HostProbeResult {
+
+	resultMap := make(map[int64]metadata.HostProbeResult, len(results))
+	for _, result := range results {
+		resultMap[result.HostID] = result
+	}
+
+	normalized := make([]metadata.HostProbeResult, len(targets))
+	for index, target := range targets {
+		result, exists := resultMap[target.HostID]
+		if !exists {
+			result = metadata.HostProbeResult{HostID: target.HostID, Message: "no probe result"}
+		}
+
+		switch result.Status {
+		case metadata.HostProbeReachable, metadata.HostProbeUnreachable:
+		default:
+			result.Status = metadata.HostProbeUnknown
+		}
+		normalized[index] = result
+	}
+
+	return normalized
+}
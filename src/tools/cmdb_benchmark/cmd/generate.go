@@ -0,0 +1,73 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"configcenter/src/test/benchmark"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(NewGenerateCommand())
+}
+
+type generateConf struct {
+	option benchmark.DatasetOption
+	output string
+}
+
+// NewGenerateCommand returns the command to generate the synthetic dataset
+func NewGenerateCommand() *cobra.Command {
+	conf := new(generateConf)
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "generate the synthetic dataset of businesses, topologies and hosts to run the benchmark against",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenerate(conf)
+		},
+	}
+
+	conf.addFlags(cmd)
+
+	return cmd
+}
+
+func (c *generateConf) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&c.option.Prefix, "prefix", "bench",
+		"the prefix of the names of the generated businesses and mainline levels")
+	cmd.Flags().IntVar(&c.option.Businesses, "businesses", 10, "the number of the businesses")
+	cmd.Flags().IntVar(&c.option.TopoLevels, "topo-levels", 0,
+		"the number of the custom mainline levels between the business and the set")
+	cmd.Flags().IntVar(&c.option.SetsPerBiz, "sets-per-biz", 10, "the number of the sets of each business")
+	cmd.Flags().IntVar(&c.option.ModulesPerSet, "modules-per-set", 5, "the number of the modules of each set")
+	cmd.Flags().IntVar(&c.option.HostsPerBiz, "hosts-per-biz", 1000, "the number of the hosts of each business")
+	cmd.Flags().StringVar(&c.output, "output", "dataset.json", "the file to save the generated dataset")
+}
+
+func runGenerate(c *generateConf) error {
+	dataset, err := benchmark.GenerateDataset(context.Background(), apiConf.newClient(), c.option)
+	if err != nil {
+		return err
+	}
+
+	if err := dataset.Save(c.output); err != nil {
+		return err
+	}
+	fmt.Printf("dataset of %d businesses is saved to %s\n", len(dataset.Businesses), c.output)
+	return nil
+}
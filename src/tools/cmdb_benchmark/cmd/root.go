@@ -0,0 +1,61 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cmd is the commands of the cmdb benchmark tool
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/test/benchmark"
+
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:          os.Args[0],
+	SilenceUsage: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+// apiConfig is the config to call the api server, which is shared by all the commands
+type apiConfig struct {
+	addr            string
+	user            string
+	supplierAccount string
+	timeout         time.Duration
+}
+
+var apiConf = new(apiConfig)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&apiConf.addr, "addr", "http://127.0.0.1:8080",
+		"the address of the api server")
+	rootCmd.PersistentFlags().StringVar(&apiConf.user, "user", "admin", "the user to call the api server")
+	rootCmd.PersistentFlags().StringVar(&apiConf.supplierAccount, "supplier-account", common.BKDefaultOwnerID,
+		"the supplier account to call the api server")
+	rootCmd.PersistentFlags().DurationVar(&apiConf.timeout, "timeout", 30*time.Second,
+		"the timeout of each api call")
+}
+
+func (c *apiConfig) newClient() benchmark.Client {
+	return benchmark.NewClient(c.addr, c.user, c.supplierAccount, c.timeout)
+}
+
+// GetRootCmd returns the root command of the cmdb benchmark tool
+func GetRootCmd() *cobra.Command {
+	return rootCmd
+}
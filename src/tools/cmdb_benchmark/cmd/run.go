@@ -0,0 +1,113 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"configcenter/src/test/benchmark"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(NewRunCommand())
+}
+
+type runConf struct {
+	dataset   string
+	scenarios string
+	option    benchmark.RunOption
+	output    string
+	baseline  string
+	threshold float64
+}
+
+// NewRunCommand returns the command to run the benchmark scenarios
+func NewRunCommand() *cobra.Command {
+	conf := new(runConf)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "run the benchmark scenarios against the generated dataset and report the metrics",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBenchmark(conf)
+		},
+	}
+
+	conf.addFlags(cmd)
+
+	return cmd
+}
+
+func (c *runConf) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&c.dataset, "dataset", "dataset.json", "the dataset file saved by the generate command")
+	cmd.Flags().StringVar(&c.scenarios, "scenarios", "", fmt.Sprintf("the scenarios to run, separated by comma, "+
+		"run all if not set, supported scenarios: %s", strings.Join(benchmark.ScenarioNames(), ",")))
+	cmd.Flags().IntVar(&c.option.Concurrent, "concurrent", 10, "the number of the concurrent requests")
+	cmd.Flags().Float64Var(&c.option.SustainSeconds, "sustain-seconds", 60, "the seconds each scenario lasts")
+	cmd.Flags().Int64Var(&c.option.TotalRequest, "total-request", 0,
+		"the number of the requests of each scenario, it has higher priority than sustain-seconds")
+	cmd.Flags().StringVar(&c.output, "output", "", "the file to save the report in json")
+	cmd.Flags().StringVar(&c.baseline, "baseline", "",
+		"the baseline report file to compare with, exit with error if any scenario regresses")
+	cmd.Flags().Float64Var(&c.threshold, "threshold", 10,
+		"the percent a metric can get worse than the baseline before it is a regression")
+}
+
+func runBenchmark(c *runConf) error {
+	dataset, err := benchmark.LoadDataset(c.dataset)
+	if err != nil {
+		return err
+	}
+
+	if c.scenarios != "" {
+		c.option.Scenarios = strings.Split(c.scenarios, ",")
+	}
+
+	// load the baseline before running so that a wrong baseline file does not waste the benchmark
+	var baseline *benchmark.Report
+	if c.baseline != "" {
+		if baseline, err = benchmark.LoadReport(c.baseline); err != nil {
+			return err
+		}
+	}
+
+	report, err := benchmark.Run(context.Background(), apiConf.newClient(), dataset, c.option)
+	if err != nil {
+		return err
+	}
+	fmt.Print(report.Format())
+
+	if c.output != "" {
+		if err := report.Save(c.output); err != nil {
+			return err
+		}
+	}
+
+	if baseline == nil {
+		return nil
+	}
+
+	regressions := benchmark.Compare(baseline, report, c.threshold)
+	if len(regressions) == 0 {
+		fmt.Printf("no regression compared with the baseline %s\n", c.baseline)
+		return nil
+	}
+	for _, regression := range regressions {
+		fmt.Println(regression)
+	}
+	return fmt.Errorf("%d regressions compared with the baseline %s", len(regressions), c.baseline)
+}
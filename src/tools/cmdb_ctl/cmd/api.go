@@ -0,0 +1,123 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+
+	"github.com/spf13/cobra"
+)
+
+// apiConf is the config to call the api server, which is shared by the commands operating the resources by the api
+type apiConf struct {
+	addr            string
+	user            string
+	supplierAccount string
+	output          string
+}
+
+func (c *apiConf) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&c.addr, "api-addr", os.Getenv("CMDB_API_ADDR"), "the address of the api "+
+		"server, eg. http://127.0.0.1:8080, corresponding environment variable is CMDB_API_ADDR")
+	cmd.PersistentFlags().StringVar(&c.user, "user", "cmdb_tool", "the user to call the api server")
+	cmd.PersistentFlags().StringVar(&c.supplierAccount, "supplier-account", common.BKDefaultOwnerID,
+		"the supplier account to call the api server")
+	cmd.PersistentFlags().StringVarP(&c.output, "output", "o", outputTable, "the output format, can be: table, "+
+		"wide, json, yaml")
+}
+
+// request sends the request to the api server and returns the response body, path is the full path of the url
+func (c *apiConf) request(method, path string, body interface{}) ([]byte, error) {
+	if c.addr == "" {
+		return nil, fmt.Errorf("api-addr must set via flag or environment variable")
+	}
+
+	var reader io.Reader
+	if body != nil {
+		js, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(js)
+	}
+
+	url := strings.TrimSuffix(c.addr, "/") + path
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	rid := util.GenerateRID()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(common.BKHTTPHeaderUser, c.user)
+	req.Header.Set(common.BKHTTPOwnerID, c.supplierAccount)
+	req.Header.Set(common.BKHTTPCCRequestID, rid)
+
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed, rid: %s, err: %v", method, url, rid, err)
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response of %s %s failed, rid: %s, err: %v", method, url, rid, err)
+	}
+
+	if resp.StatusCode != http.StatusOK && len(content) == 0 {
+		return nil, fmt.Errorf("%s %s failed, rid: %s, status: %s", method, url, rid, resp.Status)
+	}
+	return content, nil
+}
+
+// call calls the api of the api server, the data of the response is decoded into result if it's not nil, the json
+// numbers are kept as json.Number if the result is an interface.
+func (c *apiConf) call(method, path string, body interface{}, result interface{}) error {
+	content, err := c.request(method, "/api/v3"+path, body)
+	if err != nil {
+		return err
+	}
+
+	resp := new(struct {
+		metadata.BaseResp `json:",inline"`
+		Data              json.RawMessage `json:"data"`
+	})
+	if err := json.Unmarshal(content, resp); err != nil {
+		return fmt.Errorf("decode response %s failed, err: %v", content, err)
+	}
+
+	if !resp.Result {
+		return fmt.Errorf("%s %s failed, code: %d, message: %s", method, path, resp.Code, resp.ErrMsg)
+	}
+
+	if result == nil || len(resp.Data) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(resp.Data))
+	decoder.UseNumber()
+	return decoder.Decode(result)
+}
@@ -0,0 +1,356 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/querybuilder"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(NewInstanceCommand())
+}
+
+// instDefaultColumns the default table columns of the instances
+var instDefaultColumns = []string{common.BKInstIDField, common.BKInstNameField, common.CreateTimeField,
+	common.LastTimeField}
+
+// instImportIgnoreFields the fields generated by the server, which are ignored when importing the instances
+var instImportIgnoreFields = []string{common.BKInstIDField, common.BKObjIDField, common.BKOwnerIDField,
+	common.CreateTimeField, common.LastTimeField}
+
+type instConf struct {
+	apiConf
+	objID  string
+	instID int64
+	filter string
+	fields []string
+	start  int
+	limit  int
+	data   string
+	file   string
+}
+
+// NewInstanceCommand new tool command for operating the object instances through the api server
+func NewInstanceCommand() *cobra.Command {
+	conf := new(instConf)
+
+	cmd := &cobra.Command{
+		Use:   "inst",
+		Short: "object instance operations through the api server",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+	conf.apiConf.addFlags(cmd)
+	cmd.PersistentFlags().StringVar(&conf.objID, "obj", "", "the object id of the instances")
+
+	getCmd := &cobra.Command{
+		Use:   "get",
+		Short: "get the instances, use with flag --id or --filter",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGetInst(conf)
+		},
+	}
+	getCmd.Flags().Int64Var(&conf.instID, "id", 0, "the instance id")
+	getCmd.Flags().StringVar(&conf.filter, "filter", "", `the filter of the instances in the query language, `+
+		`eg. bk_inst_name ~ "^db" and bk_inst_id > 10`)
+	getCmd.Flags().StringSliceVar(&conf.fields, "fields", nil, "the instance fields to return")
+	getCmd.Flags().IntVar(&conf.start, "start", 0, "the start index of the instances")
+	getCmd.Flags().IntVar(&conf.limit, "limit", 20, "the number of the instances to return, max 500")
+	cmd.AddCommand(getCmd)
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "create an instance, use with flag --data or --file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCreateInst(conf)
+		},
+	}
+	createCmd.Flags().StringVar(&conf.data, "data", "", "the instance data in json")
+	createCmd.Flags().StringVar(&conf.file, "file", "", "the file of the instance data in json")
+	cmd.AddCommand(createCmd)
+
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "update an instance, use with flag --id and --data or --file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUpdateInst(conf)
+		},
+	}
+	updateCmd.Flags().Int64Var(&conf.instID, "id", 0, "the instance id")
+	updateCmd.Flags().StringVar(&conf.data, "data", "", "the instance data to update in json")
+	updateCmd.Flags().StringVar(&conf.file, "file", "", "the file of the instance data to update in json")
+	cmd.AddCommand(updateCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "delete an instance, use with flag --id",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDeleteInst(conf)
+		},
+	}
+	deleteCmd.Flags().Int64Var(&conf.instID, "id", 0, "the instance id")
+	cmd.AddCommand(deleteCmd)
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "export all the matched instances to a json file, use with flag --file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExportInst(conf)
+		},
+	}
+	exportCmd.Flags().StringVar(&conf.filter, "filter", "", "the filter of the instances in the query language")
+	exportCmd.Flags().StringSliceVar(&conf.fields, "fields", nil, "the instance fields to export")
+	exportCmd.Flags().StringVar(&conf.file, "file", "", "the file to export to, print to stdout if not set")
+	cmd.AddCommand(exportCmd)
+
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "import the instances from a json file exported by the export command, use with flag --file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImportInst(conf)
+		},
+	}
+	importCmd.Flags().StringVar(&conf.file, "file", "", "the json file of the instances array")
+	cmd.AddCommand(importCmd)
+
+	return cmd
+}
+
+func (c *instConf) validate(needID bool) error {
+	if c.objID == "" {
+		return fmt.Errorf("obj must be set")
+	}
+	if needID && c.instID <= 0 {
+		return fmt.Errorf("id must be set")
+	}
+	return nil
+}
+
+// searchFilter returns the search filter of the instances with the id and the filter flags
+func (c *instConf) searchFilter(page metadata.BasePage) (*metadata.CommonSearchFilter, error) {
+	builder := querybuilder.NewBuilder()
+	if c.instID > 0 {
+		builder.Field(common.BKInstIDField).Equal(c.instID)
+	}
+
+	if c.filter != "" {
+		filter, err := querybuilder.ParseQueryFilterFromText(c.filter)
+		if err != nil {
+			return nil, err
+		}
+		builder.Rule(filter.Rule)
+	}
+
+	searchFilter := &metadata.CommonSearchFilter{Fields: c.fields, Page: page}
+	if c.instID > 0 || c.filter != "" {
+		conditions, err := builder.Build()
+		if err != nil {
+			return nil, err
+		}
+		searchFilter.Conditions = conditions
+	}
+	return searchFilter, nil
+}
+
+// searchInst searches a page of the instances
+func (c *instConf) searchInst(page metadata.BasePage) ([]map[string]interface{}, error) {
+	filter, err := c.searchFilter(page)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(struct {
+		Info []map[string]interface{} `json:"info"`
+	})
+	if err := c.call(http.MethodPost, "/search/instances/object/"+c.objID, filter, result); err != nil {
+		return nil, err
+	}
+	return result.Info, nil
+}
+
+// readData reads the instance data from the data flag or the file
+func (c *instConf) readData(data interface{}) error {
+	content := []byte(c.data)
+	if c.file != "" {
+		var err error
+		if content, err = ioutil.ReadFile(c.file); err != nil {
+			return err
+		}
+	}
+
+	if len(content) == 0 {
+		return fmt.Errorf("data or file must be set")
+	}
+	return json.Unmarshal(content, data)
+}
+
+func runGetInst(c *instConf) error {
+	if err := c.validate(false); err != nil {
+		return err
+	}
+
+	page := metadata.BasePage{Start: c.start, Limit: c.limit, Sort: common.BKInstIDField}
+	items, err := c.searchInst(page)
+	if err != nil {
+		return err
+	}
+
+	if c.instID > 0 && len(items) == 0 {
+		return fmt.Errorf("%s instance %d not found", c.objID, c.instID)
+	}
+	return printItems(c.output, instDefaultColumns, items)
+}
+
+func runCreateInst(c *instConf) error {
+	if err := c.validate(false); err != nil {
+		return err
+	}
+
+	data := make(map[string]interface{})
+	if err := c.readData(&data); err != nil {
+		return err
+	}
+
+	inst := make(map[string]interface{})
+	if err := c.call(http.MethodPost, "/create/instance/object/"+c.objID, data, &inst); err != nil {
+		return err
+	}
+	return printItems(c.output, instDefaultColumns, []map[string]interface{}{inst})
+}
+
+func runUpdateInst(c *instConf) error {
+	if err := c.validate(true); err != nil {
+		return err
+	}
+
+	data := make(map[string]interface{})
+	if err := c.readData(&data); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/update/instance/object/%s/inst/%d", c.objID, c.instID)
+	if err := c.call(http.MethodPut, path, data, nil); err != nil {
+		return err
+	}
+	fmt.Printf("%s instance %d updated\n", c.objID, c.instID)
+	return nil
+}
+
+func runDeleteInst(c *instConf) error {
+	if err := c.validate(true); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/delete/instance/object/%s/inst/%d", c.objID, c.instID)
+	if err := c.call(http.MethodDelete, path, nil, nil); err != nil {
+		return err
+	}
+	fmt.Printf("%s instance %d deleted\n", c.objID, c.instID)
+	return nil
+}
+
+func runExportInst(c *instConf) error {
+	if err := c.validate(false); err != nil {
+		return err
+	}
+
+	all := make([]map[string]interface{}, 0)
+	page := metadata.BasePage{Limit: common.BKMaxInstanceLimit, Sort: common.BKInstIDField}
+	for {
+		items, err := c.searchInst(page)
+		if err != nil {
+			return err
+		}
+		all = append(all, items...)
+
+		if len(items) < page.Limit {
+			break
+		}
+		page.Start += page.Limit
+	}
+
+	js, err := json.MarshalIndent(all, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	if c.file == "" {
+		fmt.Println(string(js))
+		return nil
+	}
+
+	if err := ioutil.WriteFile(c.file, js, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d %s instances are exported to %s\n", len(all), c.objID, c.file)
+	return nil
+}
+
+func runImportInst(c *instConf) error {
+	if err := c.validate(false); err != nil {
+		return err
+	}
+	if c.file == "" {
+		return fmt.Errorf("file must be set")
+	}
+
+	items := make([]map[string]interface{}, 0)
+	if err := c.readData(&items); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		for _, field := range instImportIgnoreFields {
+			delete(item, field)
+		}
+	}
+
+	created, failed := 0, 0
+	for start := 0; start < len(items); start += metadata.CreateManyCommInstMaxLimit {
+		end := start + metadata.CreateManyCommInstMaxLimit
+		if end > len(items) {
+			end = len(items)
+		}
+
+		input := map[string]interface{}{"details": items[start:end]}
+		result := metadata.NewManyCommInstResultDetail()
+		if err := c.call(http.MethodPost, "/createmany/instance/object/"+c.objID, input, result); err != nil {
+			return fmt.Errorf("import instances [%d, %d) failed, err: %v", start, end, err)
+		}
+
+		created += len(result.SuccessCreated)
+		failed += len(result.Error)
+		for index, msg := range result.Error {
+			fmt.Fprintf(os.Stderr, "import instance #%s failed: %s\n", strconv.FormatInt(int64(start)+index, 10),
+				msg)
+		}
+	}
+
+	fmt.Printf("%d %s instances are imported, %d failed\n", created, c.objID, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d instances failed to import", failed)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ghodss/yaml"
+)
+
+// the output formats of the commands operating the resources, which are the same as kubectl
+const (
+	// outputTable prints the items as a table with the default columns
+	outputTable = "table"
+	// outputWide prints the items as a table with all the fields
+	outputWide = "wide"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// printItems prints the items in the output format, the table columns are the default columns, and the wide table
+// columns are all the fields of the items.
+func printItems(output string, columns []string, items []map[string]interface{}) error {
+	switch output {
+	case outputJSON, outputYAML:
+		return printObject(output, items)
+	case outputWide:
+		columns = itemFields(items)
+	case outputTable, "":
+		if len(columns) == 0 {
+			columns = itemFields(items)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", output)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("No resources found.")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 3, ' ', 0)
+	header := make([]string, len(columns))
+	for idx, column := range columns {
+		header[idx] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(writer, strings.Join(header, "\t"))
+
+	for _, item := range items {
+		values := make([]string, len(columns))
+		for idx, column := range columns {
+			values[idx] = formatValue(item[column])
+		}
+		fmt.Fprintln(writer, strings.Join(values, "\t"))
+	}
+	return writer.Flush()
+}
+
+// printObject prints the object in json or yaml format
+func printObject(output string, object interface{}) error {
+	js, err := json.MarshalIndent(object, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	if output == outputYAML {
+		if js, err = yaml.JSONToYAML(js); err != nil {
+			return err
+		}
+		fmt.Print(string(js))
+		return nil
+	}
+
+	fmt.Println(string(js))
+	return nil
+}
+
+// itemFields returns the sorted fields of all the items
+func itemFields(items []map[string]interface{}) []string {
+	fieldMap := make(map[string]struct{})
+	for _, item := range items {
+		for field := range item {
+			fieldMap[field] = struct{}{}
+		}
+	}
+
+	fields := make([]string, 0, len(fieldMap))
+	for field := range fieldMap {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// formatValue formats the value of the table cell, the maps and arrays are formatted as json
+func formatValue(value interface{}) string {
+	switch val := value.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		return val
+	case json.Number, bool:
+		return fmt.Sprintf("%v", val)
+	default:
+		js, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(js)
+	}
+}
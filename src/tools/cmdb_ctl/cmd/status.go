@@ -0,0 +1,213 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"configcenter/src/common"
+	"configcenter/src/common/metric"
+	"configcenter/src/scene_server/admin_server/upgrader"
+	// import all the upgraders to get the latest migration version
+	_ "configcenter/src/scene_server/admin_server/upgrader/all"
+	"configcenter/src/tools/cmdb_ctl/app/config"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(NewHealthCommand())
+	rootCmd.AddCommand(NewMigrateStatusCommand())
+	rootCmd.AddCommand(NewCacheCommand())
+}
+
+// NewHealthCommand new tool command for diagnosing the health of the cmdb services through the api server
+func NewHealthCommand() *cobra.Command {
+	conf := new(apiConf)
+
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "diagnose the health of the cmdb services, exit with error if any of them is unhealthy",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHealth(conf)
+		},
+	}
+	conf.addFlags(cmd)
+
+	return cmd
+}
+
+func runHealth(c *apiConf) error {
+	content, err := c.request(http.MethodGet, "/healthz", nil)
+	if err != nil {
+		return err
+	}
+
+	resp := new(metric.HealthResponse)
+	if err := json.Unmarshal(content, resp); err != nil {
+		return fmt.Errorf("decode health response %s failed, err: %v", content, err)
+	}
+
+	if c.output == outputJSON || c.output == outputYAML {
+		if err := printObject(c.output, resp.Data); err != nil {
+			return err
+		}
+	} else {
+		items := make([]map[string]interface{}, 0, len(resp.Data.Items))
+		for _, item := range resp.Data.Items {
+			items = append(items, map[string]interface{}{
+				"name":    item.Name,
+				"healthy": item.IsHealthy,
+				"message": item.Message,
+			})
+		}
+		if err := printItems(c.output, []string{"name", "healthy", "message"}, items); err != nil {
+			return err
+		}
+	}
+
+	if !resp.Data.IsHealthy {
+		return fmt.Errorf("%s is unhealthy: %s", resp.Data.Module, resp.Data.Message)
+	}
+	return nil
+}
+
+// NewMigrateStatusCommand new tool command for showing the migration status of the db
+func NewMigrateStatusCommand() *cobra.Command {
+	output := new(string)
+
+	cmd := &cobra.Command{
+		Use:   "migrate-status",
+		Short: "show the migration version of the db and the pending migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrateStatus(*output)
+		},
+	}
+	cmd.Flags().StringVarP(output, "output", "o", outputTable, "the output format, can be: table, json, yaml")
+
+	return cmd
+}
+
+// migrateStatus is the migration status of the db
+type migrateStatus struct {
+	CurrentVersion string   `json:"current_version"`
+	InitVersion    string   `json:"init_version"`
+	LatestVersion  string   `json:"latest_version"`
+	Pending        []string `json:"pending"`
+}
+
+func runMigrateStatus(output string) error {
+	service, err := config.NewMongoService(config.Conf.MongoURI, config.Conf.MongoRsName)
+	if err != nil {
+		return err
+	}
+
+	version := new(upgrader.Version)
+	cond := map[string]interface{}{"type": upgrader.SystemTypeVersion}
+	err = service.DbProxy.Table(common.BKTableNameSystem).Find(cond).One(context.Background(), version)
+	if err != nil && !service.DbProxy.IsNotFoundError(err) {
+		return fmt.Errorf("get migration version failed, err: %v", err)
+	}
+
+	status := &migrateStatus{
+		CurrentVersion: version.CurrentVersion,
+		InitVersion:    version.InitVersion,
+		Pending:        make([]string, 0),
+	}
+	for _, v := range upgrader.Versions() {
+		status.LatestVersion = v
+		if upgrader.VersionCmp(v, version.CurrentVersion) > 0 {
+			status.Pending = append(status.Pending, v)
+		}
+	}
+
+	if output == outputJSON || output == outputYAML {
+		return printObject(output, status)
+	}
+
+	fmt.Printf("current version: %s\n", status.CurrentVersion)
+	fmt.Printf("init version:    %s\n", status.InitVersion)
+	fmt.Printf("latest version:  %s\n", status.LatestVersion)
+	if len(status.Pending) == 0 {
+		fmt.Println(WithGreenColor("the db is migrated to the latest version"))
+		return nil
+	}
+	fmt.Print(WithRedColor(fmt.Sprintf("%d migrations are pending: %s", len(status.Pending),
+		strings.Join(status.Pending, ", "))))
+	return nil
+}
+
+// cacheDetailKeyPatterns the patterns of the detail cache keys of the resources, the cache service reloads the
+// details from the db when they are not found in the cache.
+var cacheDetailKeyPatterns = map[string]string{
+	common.BKInnerObjIDHost:   common.BKCacheKeyV3Prefix + "host:detail:*",
+	common.BKInnerObjIDApp:    common.BKCacheKeyV3Prefix + "biz:" + common.BKInnerObjIDApp + "_detail:*",
+	common.BKInnerObjIDSet:    common.BKCacheKeyV3Prefix + "biz:" + common.BKInnerObjIDSet + "_detail:*",
+	common.BKInnerObjIDModule: common.BKCacheKeyV3Prefix + "biz:" + common.BKInnerObjIDModule + "_detail:*",
+}
+
+// NewCacheCommand new tool command for operating the cache of the cache service
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "cache operations",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	resources := new([]string)
+	rebuildCmd := &cobra.Command{
+		Use:   "rebuild",
+		Short: "rebuild the detail cache of the resources, which are reloaded from the db when they are accessed",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCacheRebuild(*resources)
+		},
+	}
+	rebuildCmd.Flags().StringSliceVar(resources, "rsc", []string{"all"}, "the resources to rebuild the cache, can "+
+		"be: host, biz, set, module, all")
+	cmd.AddCommand(rebuildCmd)
+
+	return cmd
+}
+
+func runCacheRebuild(resources []string) error {
+	patterns := make([]string, 0)
+	for _, resource := range resources {
+		if resource == "all" {
+			for _, rsc := range []string{common.BKInnerObjIDHost, common.BKInnerObjIDApp, common.BKInnerObjIDSet,
+				common.BKInnerObjIDModule} {
+				patterns = append(patterns, cacheDetailKeyPatterns[rsc])
+			}
+			continue
+		}
+
+		pattern, exists := cacheDetailKeyPatterns[resource]
+		if !exists {
+			return fmt.Errorf("unsupported resource: %s", resource)
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	for _, pattern := range patterns {
+		fmt.Print(WithBlueColor("rebuild cache " + pattern))
+		if err := runRedisScanDel(&redisOperation{match: pattern}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -21,10 +22,12 @@ import (
 	"strings"
 	"time"
 
+	"configcenter/src/common"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/types"
 	"configcenter/src/common/util"
 	"configcenter/src/common/watch"
+	"configcenter/src/common/watch/federation"
 	"configcenter/src/tools/cmdb_ctl/app/config"
 
 	"github.com/spf13/cobra"
@@ -42,6 +45,8 @@ type watchConf struct {
 	fields      []string
 	filter      string
 	subresource string
+	sources     string
+	user        string
 }
 
 func (w *watchConf) addFlags(cmd *cobra.Command) {
@@ -56,6 +61,10 @@ func (w *watchConf) addFlags(cmd *cobra.Command) {
 		"':' , multiple kv is separated with ';', like k1:v1;k2:v2")
 	cmd.PersistentFlags().StringVar(&w.subresource, "sub-rsc", "", "the sub resource to watch, can be the object ID "+
 		"of object_instance or mainline_instance resource")
+	cmd.PersistentFlags().StringVar(&w.sources, "sources", "", "the api servers of the clusters to federate, the "+
+		"name and the address of a cluster is separated with '=', multiple clusters are separated with ',', like "+
+		"east=http://127.0.0.1:8080,west=http://127.0.0.2:8080")
+	cmd.PersistentFlags().StringVar(&w.user, "user", "cmdb_tool", "the user to call the api servers")
 }
 
 // NewWatchCommand TODO
@@ -86,6 +95,14 @@ func NewWatchCommand() *cobra.Command {
 		},
 	})
 
+	cmd.AddCommand(&cobra.Command{
+		Use:   "federate",
+		Short: "watch events of multiple clusters as a global stream, the cursor is the global cursor",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFederateWatch(conf)
+		},
+	})
+
 	conf.addFlags(cmd)
 	return cmd
 }
@@ -276,6 +293,53 @@ func runStartFromWatch(c *watchConf) error {
 	return nil
 }
 
+func runFederateWatch(c *watchConf) error {
+	cursor, err := federation.DecodeCursor(c.cursor)
+	if err != nil {
+		return err
+	}
+
+	header := make(http.Header)
+	header.Add(common.BKHTTPOwnerID, common.BKDefaultOwnerID)
+	header.Add(common.BKHTTPHeaderUser, c.user)
+
+	sources := make([]federation.Source, 0)
+	for _, source := range strings.Split(c.sources, ",") {
+		array := strings.SplitN(source, "=", 2)
+		if len(array) != 2 {
+			return fmt.Errorf("invalid source %s, the name and the address must be separated with '='", source)
+		}
+		sources = append(sources, federation.Source{
+			Name:    array[0],
+			Watcher: federation.NewAPIWatcher(array[1], header, 2*time.Minute),
+		})
+	}
+
+	if c.startFrom < 0 {
+		c.startFrom = time.Now().Unix() + c.startFrom
+	}
+
+	fed, err := federation.New(sources, federation.Options{
+		Resource:  watch.CursorType(c.resource),
+		Fields:    c.fields,
+		Filter:    watch.WatchEventFilter{SubResource: c.subresource},
+		StartFrom: c.startFrom,
+		Cursor:    cursor,
+	})
+	if err != nil {
+		return err
+	}
+
+	return fed.Watch(context.Background(), func(batch *federation.Batch) error {
+		js, err := json.MarshalIndent(batch, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n>>>watched %d events from %s -> : \n %s \n", len(batch.Events), batch.Source, string(js))
+		return nil
+	})
+}
+
 // WatchResp TODO
 type WatchResp struct {
 	// watched events or not
@@ -16,16 +16,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"configcenter/src/common"
 	"configcenter/src/common/backbone"
 	cc "configcenter/src/common/backbone/configcenter"
+	"configcenter/src/common/blobstore"
 	"configcenter/src/common/resource/esb"
 	"configcenter/src/common/types"
 	"configcenter/src/storage/dal/redis"
 	"configcenter/src/web_server/app/options"
+	webCommon "configcenter/src/web_server/common"
 	"configcenter/src/web_server/logics"
 	websvc "configcenter/src/web_server/service"
 )
@@ -97,6 +100,15 @@ func Run(ctx context.Context, cancel context.CancelFunc, op *options.ServerOptio
 		return err
 	}
 
+	blobConf, err := parseBlobStoreConf()
+	if err != nil {
+		return fmt.Errorf("parse blob store config failed, err: %v", err)
+	}
+	service.BlobStore, err = blobstore.New(blobConf)
+	if err != nil {
+		return fmt.Errorf("new blob store failed, err: %v", err)
+	}
+
 	service.Engine = engine
 	service.CacheCli = cacheCli
 	service.Logics = &logics.Logics{Engine: engine}
@@ -117,6 +129,18 @@ func Run(ctx context.Context, cancel context.CancelFunc, op *options.ServerOptio
 	return nil
 }
 
+// parseBlobStoreConf parse the blob store config, the files are stored in the local resource directory as before
+// if the blob store is not configured.
+func parseBlobStoreConf() (*blobstore.Config, error) {
+	if !cc.IsExist("blobStore") {
+		return &blobstore.Config{
+			Type:  blobstore.TypeLocal,
+			Local: blobstore.LocalConfig{Path: filepath.Join(webCommon.ResourcePath, "blob")},
+		}, nil
+	}
+	return blobstore.ParseConfig("blobStore")
+}
+
 func (w *WebServer) onServerConfigUpdate(previous, current cc.ProcessConfig) {
 	domainUrl, _ := cc.String("webServer.site.domainUrl")
 	w.Config.Site.DomainUrl = domainUrl + "/"
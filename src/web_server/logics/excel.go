@@ -375,6 +375,11 @@ func (lgc *Logics) BuildAssociationExcelFromData(ctx context.Context, objID stri
 
 	productExcelAssociationHeader(ctx, sheet, defLang, len(instAsst), asstList)
 
+	asstKindMap := make(map[string]string)
+	for _, asst := range asstList {
+		asstKindMap[asst.AssociationName] = asst.AsstKindID
+	}
+
 	rowIndex := common.HostAddMethodExcelAssociationIndexOffset
 
 	for _, inst := range instAsst {
@@ -389,6 +394,7 @@ func (lgc *Logics) BuildAssociationExcelFromData(ctx context.Context, objID stri
 		// TODO: 注意源和目标顺序
 		sheet.Cell(rowIndex, 3).SetString(buildExcelPrimaryKey(srcInst))
 		sheet.Cell(rowIndex, 4).SetString(buildExcelPrimaryKey(dstInst))
+		sheet.Cell(rowIndex, associationAsstKindIndex).SetString(asstKindMap[inst.ObjectAsstID])
 		style := sheet.Cell(rowIndex, 3).GetStyle()
 		style.Alignment.WrapText = true
 		style = sheet.Cell(rowIndex, 4).GetStyle()
@@ -603,7 +609,7 @@ func GetAssociationExcelData(sheet *xlsx.Sheet, firstRow int, defLang lang.Defau
 		row := sheet.Rows[index]
 
 		// 获取单元格内容，使用for循环防止直接获取对应单元格数据导致数组越界
-		var asstObjID, op, srcInst, dstInst string
+		var asstObjID, op, srcInst, dstInst, asstKindID string
 		for index, item := range row.Cells {
 			switch index {
 			case associationAsstObjIDIndex:
@@ -614,6 +620,8 @@ func GetAssociationExcelData(sheet *xlsx.Sheet, firstRow int, defLang lang.Defau
 				srcInst = item.String()
 			case associationDstInstIndex:
 				dstInst = item.String()
+			case associationAsstKindIndex:
+				asstKindID = strings.TrimSpace(item.String())
 			}
 		}
 
@@ -632,12 +640,60 @@ func GetAssociationExcelData(sheet *xlsx.Sheet, firstRow int, defLang lang.Defau
 			Operate:      getAssociationExcelOperateFlag(op),
 			SrcPrimary:   srcInst,
 			DstPrimary:   dstInst,
+			AsstKindID:   asstKindID,
 		}
 	}
 
 	return asstInfoArr, errMsg
 }
 
+// BuildAssociationErrorReport build the error report of the association sheet import, which keeps the header rows
+// and the failed rows of the sheet with their error messages, so that the failed rows can be fixed and imported again.
+func BuildAssociationErrorReport(sheet *xlsx.Sheet, errMsg []metadata.RowMsgData,
+	defLang lang.DefaultCCLanguageIf) (*xlsx.File, error) {
+
+	file := xlsx.NewFile()
+	reportSheet, err := file.AddSheet(sheet.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	rowMsg := make(map[int][]string)
+	for _, msg := range errMsg {
+		rowMsg[msg.Row] = append(rowMsg[msg.Row], msg.Msg)
+	}
+
+	reportRow := 0
+	copyRow := func(row *xlsx.Row) {
+		for index, cell := range row.Cells {
+			if index >= associationErrorIndex {
+				break
+			}
+			reportSheet.Cell(reportRow, index).SetString(cell.String())
+		}
+	}
+
+	for index := 0; index < common.HostAddMethodExcelAssociationIndexOffset && index < len(sheet.Rows); index++ {
+		copyRow(sheet.Rows[index])
+		reportRow++
+	}
+	reportSheet.Cell(0, associationErrorIndex).SetString(defLang.Language("excel_association_error"))
+	reportSheet.Col(associationErrorIndex).Width = 60
+
+	for index := common.HostAddMethodExcelAssociationIndexOffset; index < len(sheet.Rows); index++ {
+		msg, exists := rowMsg[index]
+		if !exists {
+			continue
+		}
+
+		copyRow(sheet.Rows[index])
+		reportSheet.Cell(reportRow, associationErrorIndex).SetString(strings.Join(msg, "; "))
+		reportRow++
+	}
+
+	return file, nil
+}
+
 // StatisticsAssociation TODO
 func StatisticsAssociation(sheet *xlsx.Sheet, firstRow int) ([]string, map[string]metadata.ObjectAsstIDStatisticsInfo) {
 
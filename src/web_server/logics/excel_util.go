@@ -642,6 +642,11 @@ func productExcelAssociationHeader(ctx context.Context, sheet *xlsx.Sheet, defLa
 	style.Alignment.WrapText = true
 	cellDstID.SetStyle(style)
 
+	sheet.Col(associationAsstKindIndex).Width = 20
+	cellKindID := sheet.Cell(0, associationAsstKindIndex)
+	cellKindID.SetString(defLang.Language("excel_association_kind"))
+	cellKindID.SetStyle(getHeaderFirstRowCellStyle(false))
+
 	cell := sheet.Cell(1, associationAsstObjIDIndex)
 	cell.SetString(defLang.Language("excel_example_association"))
 	cell.SetStyle(backStyle)
@@ -654,6 +659,9 @@ func productExcelAssociationHeader(ctx context.Context, sheet *xlsx.Sheet, defLa
 	cell = sheet.Cell(1, associationDstInstIndex)
 	cell.SetString(defLang.Language("excel_example_association_dst_inst"))
 	cell.SetStyle(backStyle)
+	cell = sheet.Cell(1, associationAsstKindIndex)
+	cell.SetString(defLang.Language("excel_example_association_kind"))
+	cell.SetStyle(backStyle)
 }
 
 const (
@@ -661,6 +669,9 @@ const (
 	associationAsstObjIDIndex = 1
 	associationSrcInstIndex   = 3
 	associationDstInstIndex   = 4
+	associationAsstKindIndex  = 5
+	// associationErrorIndex is the column of the error message in the association error report
+	associationErrorIndex = 6
 
 	associationOPAdd = "add"
 	// associationOPUpdate = "update"
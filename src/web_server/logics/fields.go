@@ -127,6 +127,60 @@ func (lgc *Logics) GetObjFieldIDs(objID string, filterFields []string, customFie
 	return ret, nil
 }
 
+// GetBizFieldLayoutFields get the visible fields of the business's field layout of the model, they are exported
+// by default when the export fields are not specified. returns nil if the business has no hidden fields.
+func (lgc *Logics) GetBizFieldLayoutFields(ctx context.Context, header http.Header, bizID int64, objID string) (
+	[]string, error) {
+
+	if bizID <= 0 {
+		return nil, nil
+	}
+
+	rid := util.GetHTTPCCRequestID(header)
+	opt := &metadata.BizFieldLayoutOption{BizID: bizID, ObjID: objID}
+	layout, err := lgc.CoreAPI.CoreService().Model().ReadBizFieldLayout(ctx, header, opt)
+	if err != nil {
+		blog.Errorf("read biz field layout failed, opt: %#v, err: %v, rid: %s", opt, err, rid)
+		return nil, err
+	}
+
+	if layout == nil {
+		return nil, nil
+	}
+
+	hasHidden := false
+	for _, field := range layout.Fields {
+		if field.Hidden {
+			hasHidden = true
+			break
+		}
+	}
+	if !hasHidden {
+		return nil, nil
+	}
+
+	cond := mapstr.MapStr{common.BKObjIDField: objID}
+	util.AddModelBizIDCondition(cond, bizID)
+	attrCond := &metadata.QueryCondition{
+		Condition: cond,
+		Fields:    []string{common.BKPropertyIDField, common.BKPropertyIndexField},
+		Page:      metadata.BasePage{Limit: common.BKNoLimit, Sort: common.BKPropertyIndexField},
+	}
+	attrRsp, err := lgc.CoreAPI.CoreService().Model().ReadModelAttr(ctx, header, objID, attrCond)
+	if err != nil {
+		blog.Errorf("get object %s attributes failed, err: %v, rid: %s", objID, err, rid)
+		return nil, err
+	}
+
+	attrs := layout.Apply(attrRsp.Info)
+	fields := make([]string, len(attrs))
+	for index, attr := range attrs {
+		fields[index] = attr.PropertyID
+	}
+
+	return fields, nil
+}
+
 func (lgc *Logics) getObjectGroup(objID string, header http.Header, modelBizID int64) ([]PropertyGroup, error) {
 	rid := util.GetHTTPCCRequestID(header)
 	ownerID := util.GetOwnerID(header)
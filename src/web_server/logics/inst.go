@@ -100,7 +100,7 @@ func (lgc *Logics) GetInstData(objID string, instIDArr []int64, header http.Head
 // ImportInsts import host info
 func (lgc *Logics) ImportInsts(ctx context.Context, f *xlsx.File, objID string, header http.Header,
 	defLang lang.DefaultCCLanguageIf, modelBizID int64, opType int64,
-	asstObjectUniqueIDMap map[string]int64, objectUniqueID int64) (
+	asstObjectUniqueIDMap map[string]int64, objectUniqueID int64, importOpt *InstImportOption) (
 	resultData mapstr.MapStr, errCode int, err error) {
 
 	rid := util.GetHTTPCCRequestID(header)
@@ -117,13 +117,27 @@ func (lgc *Logics) ImportInsts(ctx context.Context, f *xlsx.File, objID string,
 		return mapstr.MapStr{"association": info}, 0, nil
 	}
 
-	return lgc.importInsts(ctx, f, objID, header, defLang, modelBizID, asstObjectUniqueIDMap, objectUniqueID)
+	return lgc.importInsts(ctx, f, objID, header, defLang, modelBizID, asstObjectUniqueIDMap, objectUniqueID,
+		importOpt)
+}
+
+// InstImportOption is the option of the instance import besides the excel data.
+type InstImportOption struct {
+	// UpsertKeys the attributes used to match the existing instances of the rows without instance id
+	UpsertKeys []string
+	// AssociationColumns the columns that refresh the instances' associations by the referred instances' keys
+	AssociationColumns []metadata.ImportAssociationColumn
+	// DryRun only reports the import result of each row, the association sheet is not imported either
+	DryRun bool
+	// AsTask imports the rows by a background job that can be resumed, returns the job's task id, the association
+	// sheet is not imported either, the association columns are used instead
+	AsTask bool
 }
 
 // importInsts import insts info
 func (lgc *Logics) importInsts(ctx context.Context, f *xlsx.File, objID string, header http.Header,
-	defLang lang.DefaultCCLanguageIf, modelBizID int64, asstObjectUniqueIDMap map[string]int64, objectUniqueID int64) (
-	resultData mapstr.MapStr, errCode int, err error) {
+	defLang lang.DefaultCCLanguageIf, modelBizID int64, asstObjectUniqueIDMap map[string]int64, objectUniqueID int64,
+	importOpt *InstImportOption) (resultData mapstr.MapStr, errCode int, err error) {
 
 	rid := util.GetHTTPCCRequestID(header)
 	defErr := lgc.CCErr.CreateDefaultCCErrorIf(util.GetLanguage(header))
@@ -149,6 +163,21 @@ func (lgc *Logics) importInsts(ctx context.Context, f *xlsx.File, objID string,
 		params["input_type"] = common.InputTypeExcel
 		params["BatchInfo"] = insts
 		params[common.BKAppIDField] = modelBizID
+		if importOpt != nil {
+			params["upsert_keys"] = importOpt.UpsertKeys
+			params["association_columns"] = importOpt.AssociationColumns
+			params["dry_run"] = importOpt.DryRun
+		}
+		if importOpt != nil && importOpt.AsTask {
+			taskID, err := lgc.CoreAPI.ApiServer().CreateInstImportTask(ctx, header, objID, params)
+			if err != nil {
+				blog.Errorf("create %s inst import task failed, err: %v, rid: %s", objID, err, rid)
+				return nil, common.CCErrorUnknownOrUnrecognizedError, err
+			}
+			resultData.Set(common.BKTaskIDField, taskID)
+			return resultData, 0, nil
+		}
+
 		result, resultErr = lgc.CoreAPI.ApiServer().AddInstByImport(context.Background(), header,
 			util.GetOwnerID(header), objID, params)
 		if resultErr != nil {
@@ -158,6 +187,10 @@ func (lgc *Logics) importInsts(ctx context.Context, f *xlsx.File, objID string,
 		resultData.Merge(result.Data)
 	}
 
+	if importOpt != nil && importOpt.DryRun {
+		return
+	}
+
 	for _, sheet := range f.Sheets {
 		if sheet.Name != "association" {
 			continue
@@ -0,0 +1,72 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"configcenter/src/common/blobstore"
+	"configcenter/src/common/blog"
+	"configcenter/src/web_server/logics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportInstTaskFileKey returns the blob store key of the excel file of the instance export job
+func exportInstTaskFileKey(taskID string) string {
+	return fmt.Sprintf("export/inst/%s.xlsx", taskID)
+}
+
+// saveExportFile saves the built export file to the blob store, so that it can be downloaded again from any web
+// server instance without being built again. the failure is only logged since the file is still sent to the user.
+func (s *Service) saveExportFile(ctx context.Context, key, fileName, rid string) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		blog.Errorf("open export file %s failed, err: %v, rid: %s", fileName, err, rid)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		blog.Errorf("stat export file %s failed, err: %v, rid: %s", fileName, err, rid)
+		return
+	}
+
+	if err := s.BlobStore.Put(ctx, key, file, info.Size()); err != nil {
+		blog.Errorf("save export file %s to blob store as %s failed, err: %v, rid: %s", fileName, key, err, rid)
+	}
+}
+
+// serveExportFile sends the export file saved in the blob store to the user, returns false if the file is not saved.
+func (s *Service) serveExportFile(c *gin.Context, key, name, rid string) bool {
+	reader, err := s.BlobStore.Get(c.Request.Context(), key)
+	if err != nil {
+		if err != blobstore.ErrNotFound {
+			blog.Errorf("get export file %s from blob store failed, err: %v, rid: %s", key, err, rid)
+		}
+		return false
+	}
+	defer reader.Close()
+
+	logics.AddDownExcelHttpHeader(c, name)
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		blog.Errorf("send export file %s failed, err: %v, rid: %s", key, err, rid)
+	}
+	return true
+}
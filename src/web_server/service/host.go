@@ -71,6 +71,8 @@ type excelImportUpdateHostInput struct {
 	// 用来限定当前操作对象导出数据的时候，需要使用的唯一校验关系，
 	// 自关联的时候，规定左边对象使用到的唯一索引
 	ObjectUniqueID int64 `json:"object_unique_id"`
+	// ConflictPolicy 与其他导入来源（如云同步、主机快照）在短时间内更新相同主机字段时的冲突处理策略，默认为source_priority
+	ConflictPolicy string `json:"conflict_policy"`
 }
 
 // ImportHost import host
@@ -181,6 +183,16 @@ func (s *Service) ExportHost(c *gin.Context) {
 
 	objID := common.BKInnerObjIDHost
 	filterFields := logics.GetFilterFields(objID)
+	if len(input.CustomFields) == 0 {
+		input.CustomFields, err = s.Logics.GetBizFieldLayoutFields(ctx, header, appID, objID)
+		if err != nil {
+			blog.Errorf("get biz %d field layout failed, err: %v, rid: %s", appID, err, rid)
+			reply := getReturnStr(common.CCErrCommExcelTemplateFailed, defErr.Errorf(
+				common.CCErrCommExcelTemplateFailed, objID).Error(), nil)
+			_, _ = c.Writer.Write([]byte(reply))
+			return
+		}
+	}
 	customFields := logics.GetCustomFields(filterFields, input.CustomFields)
 	// customLen+5为生成主机数据的起始列索引, 5=字段说明1列+业务拓扑，业务名，集群，模块4列
 	fields, err := s.Logics.GetObjFieldIDs(objID, filterFields, customFields, c.Request.Header, appID, len(objectName)+5)
@@ -441,6 +453,18 @@ func (s *Service) UpdateHosts(c *gin.Context) {
 		return
 	}
 
+	if inputJSON.ConflictPolicy == "" {
+		inputJSON.ConflictPolicy = metadata.ImportConflictSourcePriority
+	}
+	if !metadata.IsValidImportConflictPolicy(inputJSON.ConflictPolicy) {
+		blog.Errorf("excel import update hosts failed, conflict policy %s is invalid, rid: %s",
+			inputJSON.ConflictPolicy, rid)
+		msg := getReturnStr(common.CCErrCommParamsInvalid,
+			defErr.CCErrorf(common.CCErrCommParamsInvalid, "conflict_policy").Error(), nil)
+		c.String(http.StatusOK, msg)
+		return
+	}
+
 	file, err := c.FormFile("file")
 	if nil != err {
 		blog.Errorf("excel import update hosts failed, get file from form data failed, err: %+v, rid: %s", err, rid)
@@ -485,6 +509,10 @@ func (s *Service) UpdateHosts(c *gin.Context) {
 		c.String(http.StatusOK, string(msg))
 		return
 	}
+	metadata.SetImportContext(c.Request.Header, &metadata.ImportContext{
+		Source: metadata.ImportSourceExcel,
+		Policy: inputJSON.ConflictPolicy,
+	})
 	result := s.Logics.UpdateHosts(ctx, f, c.Request.Header, defLang, inputJSON.BizID, inputJSON.OpType,
 		inputJSON.AssociationCond, inputJSON.ObjectUniqueID)
 
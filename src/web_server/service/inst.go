@@ -13,6 +13,7 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -23,6 +24,8 @@ import (
 
 	"configcenter/src/common"
 	"configcenter/src/common/blog"
+	lang "configcenter/src/common/language"
+	"configcenter/src/common/mapstr"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/util"
 	webCommon "configcenter/src/web_server/common"
@@ -30,6 +33,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/rentiansheng/xlsx"
+	"github.com/rs/xid"
 )
 
 type excelExportInstInput struct {
@@ -56,6 +60,14 @@ type excelImportInstInput struct {
 	// 用来限定当前操作对象导出数据的时候，需要使用的唯一校验关系，
 	// 自关联的时候，规定左边对象使用到的唯一索引
 	ObjectUniqueID int64 `json:"object_unique_id"`
+	// UpsertKeys 用来匹配没有实例id的行对应的已存在实例，匹配到则更新该实例
+	UpsertKeys []string `json:"upsert_keys"`
+	// AssociationColumns 用来通过关联实例的唯一键刷新实例关联关系的列
+	AssociationColumns []metadata.ImportAssociationColumn `json:"association_columns"`
+	// DryRun 只返回每一行的导入结果，不实际导入
+	DryRun bool `json:"dry_run"`
+	// AsTask 以可断点续传的后台任务导入，返回任务id
+	AsTask bool `json:"as_task"`
 }
 
 // ImportInst import inst
@@ -122,7 +134,20 @@ func (s *Service) ImportInst(c *gin.Context) {
 	}
 
 	data, errCode, err := s.Logics.ImportInsts(context.Background(), f, objID, c.Request.Header, defLang,
-		inputJSON.BizID, inputJSON.OpType, inputJSON.AssociationCond, inputJSON.ObjectUniqueID)
+		inputJSON.BizID, inputJSON.OpType, inputJSON.AssociationCond, inputJSON.ObjectUniqueID,
+		&logics.InstImportOption{
+			UpsertKeys:         inputJSON.UpsertKeys,
+			AssociationColumns: inputJSON.AssociationColumns,
+			DryRun:             inputJSON.DryRun,
+			AsTask:             inputJSON.AsTask,
+		})
+
+	// the failed association rows are saved as a report that can be downloaded, fixed and imported again
+	if asstErr, ok := data["asst_error"].([]metadata.RowMsgData); ok && len(asstErr) > 0 {
+		if reportID := s.saveAssociationErrorReport(c, f, asstErr, defLang, rid); reportID != "" {
+			data.Set("asst_error_report", reportID)
+		}
+	}
 
 	if err != nil {
 		msg := getReturnStr(errCode, err.Error(), data)
@@ -137,11 +162,8 @@ func (s *Service) ImportInst(c *gin.Context) {
 func (s *Service) ExportInst(c *gin.Context) {
 
 	rid := util.GetHTTPCCRequestID(c.Request.Header)
-	ctx := util.NewContextFromGinContext(c)
 	webCommon.SetProxyHeader(c)
-	language := webCommon.GetLanguageByHTTPRequest(c)
-	defLang := s.Language.CreateDefaultCCLanguageIf(language)
-	defErr := s.CCErr.CreateDefaultCCErrorIf(language)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(webCommon.GetLanguageByHTTPRequest(c))
 	pheader := c.Request.Header
 
 	input := &excelExportInstInput{}
@@ -163,8 +185,6 @@ func (s *Service) ExportInst(c *gin.Context) {
 	// ownerID := c.Param(common.BKOwnerIDField)
 	objID := c.Param(common.BKObjIDField)
 
-	modelBizID := input.AppID
-
 	instInfo, err := s.Logics.GetInstData(objID, input.InstIDArr, pheader)
 	if err != nil {
 		msg := getReturnStr(common.CCErrWebGetObjectFail, defErr.Errorf(common.CCErrWebGetObjectFail,
@@ -174,6 +194,32 @@ func (s *Service) ExportInst(c *gin.Context) {
 		return
 	}
 
+	s.writeInstExcel(c, objID, input, instInfo, "")
+}
+
+// writeInstExcel build the excel file of the exported instances and write it to the response, the file is also
+// saved to the blob store as the storeKey if it is set.
+func (s *Service) writeInstExcel(c *gin.Context, objID string, input *excelExportInstInput,
+	instInfo []mapstr.MapStr, storeKey string) {
+
+	rid := util.GetHTTPCCRequestID(c.Request.Header)
+	ctx := util.NewContextFromGinContext(c)
+	language := webCommon.GetLanguageByHTTPRequest(c)
+	defLang := s.Language.CreateDefaultCCLanguageIf(language)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(language)
+	pheader := c.Request.Header
+	modelBizID := input.AppID
+
+	var err error
+	if len(input.CustomFields) == 0 {
+		input.CustomFields, err = s.Logics.GetBizFieldLayoutFields(ctx, pheader, modelBizID, objID)
+		if err != nil {
+			blog.Errorf("get biz %d field layout failed, err: %v, rid: %s", modelBizID, err, rid)
+			_, _ = c.Writer.Write([]byte(getReturnStr(common.CCErrCommExcelTemplateFailed, defErr.Errorf(
+				common.CCErrCommExcelTemplateFailed, objID).Error(), nil)))
+			return
+		}
+	}
 	customFields := logics.GetCustomFields(nil, input.CustomFields)
 	fields, err := s.Logics.GetObjFieldIDs(objID, nil, customFields, pheader, modelBizID,
 		common.HostAddMethodExcelDefaultIndex)
@@ -224,9 +270,183 @@ func (s *Service) ExportInst(c *gin.Context) {
 			common.CCErrCommExcelTemplateFailed, err.Error()).Error(), nil)))
 		return
 	}
+	if len(storeKey) != 0 {
+		s.saveExportFile(ctx, storeKey, dirFileName, rid)
+	}
 	logics.AddDownExcelHttpHeader(c, fmt.Sprintf("bk_cmdb_export_inst_%s.xlsx", objID))
 	c.File(dirFileName)
 	if err := os.Remove(dirFileName); err != nil {
 		blog.Errorf("remove file %s failed, err: %+v, rid: %s", dirFileName, err, rid)
 	}
 }
+
+// CreateExportInstTask create the instance export job, the excel file is downloaded after the job is finished
+func (s *Service) CreateExportInstTask(c *gin.Context) {
+	rid := util.GetHTTPCCRequestID(c.Request.Header)
+	webCommon.SetProxyHeader(c)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(webCommon.GetLanguageByHTTPRequest(c))
+	objID := c.Param(common.BKObjIDField)
+
+	input := &excelExportInstInput{}
+	if err := c.BindJSON(input); err != nil {
+		blog.Errorf("unmarshal input failed, err: %v, rid: %s", err, rid)
+		c.String(http.StatusOK, getReturnStr(common.CCErrCommJSONUnmarshalFailed,
+			defErr.CCError(common.CCErrCommJSONUnmarshalFailed).Error(), nil))
+		return
+	}
+
+	taskInput := &metadata.InstExportTaskData{
+		ObjID:           objID,
+		BizID:           input.AppID,
+		InstIDs:         input.InstIDArr,
+		CustomFields:    input.CustomFields,
+		AssociationCond: input.AssociationCond,
+		ObjectUniqueID:  input.ObjectUniqueID,
+	}
+	taskID, err := s.CoreAPI.ApiServer().CreateInstExportTask(c.Request.Context(), c.Request.Header, objID,
+		taskInput)
+	if err != nil {
+		blog.Errorf("create %s inst export task failed, err: %v, rid: %s", objID, err, rid)
+		c.String(http.StatusOK, getReturnStr(common.CCErrorUnknownOrUnrecognizedError, err.Error(), nil))
+		return
+	}
+
+	c.String(http.StatusOK, getReturnStr(0, "", mapstr.MapStr{common.BKTaskIDField: taskID}))
+}
+
+// DownloadExportInstTaskFile download the excel file of the finished instance export job, the file is built from
+// the exported instances that are kept for the configured retention period.
+func (s *Service) DownloadExportInstTaskFile(c *gin.Context) {
+	rid := util.GetHTTPCCRequestID(c.Request.Header)
+	webCommon.SetProxyHeader(c)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(webCommon.GetLanguageByHTTPRequest(c))
+	objID := c.Param(common.BKObjIDField)
+	taskID := c.Param(common.BKTaskIDField)
+	ctx := c.Request.Context()
+
+	task, err := s.CoreAPI.ApiServer().GetTaskDetail(ctx, c.Request.Header, taskID)
+	if err != nil {
+		blog.Errorf("get task %s detail failed, err: %v, rid: %s", taskID, err, rid)
+		c.String(http.StatusOK, getReturnStr(common.CCErrorUnknownOrUnrecognizedError, err.Error(), nil))
+		return
+	}
+
+	// only the user who creates the export job can download its file
+	if task.TaskType != common.InstExportTaskFlag || task.User != util.GetUser(c.Request.Header) ||
+		len(task.Detail) == 0 {
+		blog.Errorf("task %s is not the inst export task of the user, rid: %s", taskID, rid)
+		c.String(http.StatusOK, getReturnStr(common.CCErrTaskNotFound,
+			defErr.CCError(common.CCErrTaskNotFound).Error(), nil))
+		return
+	}
+
+	taskInput := new(metadata.InstExportTaskData)
+	if err := decodeTaskData(task.Detail[0].Data, taskInput); err != nil {
+		blog.Errorf("decode task %s data failed, err: %v, rid: %s", taskID, err, rid)
+		c.String(http.StatusOK, getReturnStr(common.CCErrCommJSONUnmarshalFailed,
+			defErr.CCError(common.CCErrCommJSONUnmarshalFailed).Error(), nil))
+		return
+	}
+
+	if taskInput.ObjID != objID {
+		c.String(http.StatusOK, getReturnStr(common.CCErrTaskNotFound,
+			defErr.CCError(common.CCErrTaskNotFound).Error(), nil))
+		return
+	}
+
+	// the excel file is built only once, and then downloaded from the blob store until it expires
+	storeKey := exportInstTaskFileKey(taskID)
+	if s.serveExportFile(c, storeKey, fmt.Sprintf("bk_cmdb_export_inst_%s.xlsx", objID), rid) {
+		return
+	}
+
+	files, err := s.CoreAPI.ApiServer().ListTaskFile(ctx, c.Request.Header, taskID)
+	if err != nil {
+		blog.Errorf("list task %s files failed, err: %v, rid: %s", taskID, err, rid)
+		c.String(http.StatusOK, getReturnStr(common.CCErrorUnknownOrUnrecognizedError, err.Error(), nil))
+		return
+	}
+
+	instInfo := make([]mapstr.MapStr, 0)
+	for _, file := range files {
+		insts := make([]mapstr.MapStr, 0)
+		if err := decodeTaskData(file.Data, &insts); err != nil {
+			blog.Errorf("decode task %s file %d failed, err: %v, rid: %s", taskID, file.ChunkID, err, rid)
+			c.String(http.StatusOK, getReturnStr(common.CCErrCommJSONUnmarshalFailed,
+				defErr.CCError(common.CCErrCommJSONUnmarshalFailed).Error(), nil))
+			return
+		}
+		instInfo = append(instInfo, insts...)
+	}
+
+	input := &excelExportInstInput{
+		CustomFields:    taskInput.CustomFields,
+		AppID:           taskInput.BizID,
+		AssociationCond: taskInput.AssociationCond,
+		ObjectUniqueID:  taskInput.ObjectUniqueID,
+	}
+	s.writeInstExcel(c, objID, input, instInfo, storeKey)
+}
+
+// DownloadAssociationErrorReport download the error report of the association import, only the user who imports
+// the associations can download it.
+func (s *Service) DownloadAssociationErrorReport(c *gin.Context) {
+	rid := util.GetHTTPCCRequestID(c.Request.Header)
+	webCommon.SetProxyHeader(c)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(webCommon.GetLanguageByHTTPRequest(c))
+	objID := c.Param(common.BKObjIDField)
+
+	key := associationErrorReportKey(util.GetUser(c.Request.Header), c.Param("report_id"))
+	if s.serveExportFile(c, key, fmt.Sprintf("bk_cmdb_import_association_error_%s.xlsx", objID), rid) {
+		return
+	}
+
+	c.String(http.StatusOK, getReturnStr(common.CCErrCommNotFound, defErr.CCError(common.CCErrCommNotFound).Error(),
+		nil))
+}
+
+// saveAssociationErrorReport saves the error report of the association sheet to the blob store, returns the report
+// id, or empty if the report is not saved, the failure is only logged since the errors are still returned.
+func (s *Service) saveAssociationErrorReport(c *gin.Context, f *xlsx.File, errMsg []metadata.RowMsgData,
+	defLang lang.DefaultCCLanguageIf, rid string) string {
+
+	sheet, exists := f.Sheet["association"]
+	if !exists {
+		return ""
+	}
+
+	report, err := logics.BuildAssociationErrorReport(sheet, errMsg, defLang)
+	if err != nil {
+		blog.Errorf("build association error report failed, err: %v, rid: %s", err, rid)
+		return ""
+	}
+
+	buf := new(bytes.Buffer)
+	if err := report.Write(buf); err != nil {
+		blog.Errorf("write association error report failed, err: %v, rid: %s", err, rid)
+		return ""
+	}
+
+	reportID := xid.New().String()
+	key := associationErrorReportKey(util.GetUser(c.Request.Header), reportID)
+	if err := s.BlobStore.Put(c.Request.Context(), key, buf, int64(buf.Len())); err != nil {
+		blog.Errorf("save association error report %s failed, err: %v, rid: %s", key, err, rid)
+		return ""
+	}
+	return reportID
+}
+
+// associationErrorReportKey returns the blob store key of the association error report, the user is a part of the
+// key so that the report can only be downloaded by the user who imports the associations.
+func associationErrorReportKey(user, reportID string) string {
+	return fmt.Sprintf("import/association/%s/%s.xlsx", user, reportID)
+}
+
+// decodeTaskData decode the task's data or file which is decoded as the generic json value
+func decodeTaskData(data interface{}, result interface{}) error {
+	js, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(js, result)
+}
@@ -0,0 +1,258 @@
+/*
+ * Tencent is pleased to support the open source community by making 蓝鲸 available.
+ * Copyright (C) 2017-2018 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+
+	"configcenter/src/common"
+	"configcenter/src/common/blobstore"
+	"configcenter/src/common/blog"
+	"configcenter/src/common/errors"
+	"configcenter/src/common/metadata"
+	"configcenter/src/common/util"
+	webCommon "configcenter/src/web_server/common"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/xid"
+)
+
+// UploadModelAsset uploads the content of a new model asset to the blob store and registers the asset, the form
+// contains the file and the name and type of the asset.
+func (s *Service) UploadModelAsset(c *gin.Context) {
+	rid := util.GetHTTPCCRequestID(c.Request.Header)
+	webCommon.SetProxyHeader(c)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(webCommon.GetLanguageByHTTPRequest(c))
+	ctx := c.Request.Context()
+
+	opt := &metadata.CreateModelAssetOption{Name: c.PostForm(common.BKFieldName), Type: c.PostForm("type")}
+	content, err := s.putModelAssetContent(c, opt.Type, defErr, rid)
+	if err != nil {
+		respModelAssetError(c, err)
+		return
+	}
+	opt.Content = *content
+
+	asset, err := s.CoreAPI.ApiServer().CreateModelAsset(ctx, c.Request.Header, opt)
+	if err != nil {
+		blog.Errorf("create model asset %s failed, err: %v, rid: %s", opt.Name, err, rid)
+		s.deleteModelAssetContents(ctx, []string{content.Key}, rid)
+		respModelAssetError(c, err)
+		return
+	}
+
+	c.String(http.StatusOK, getReturnStr(0, "", asset))
+}
+
+// UploadModelAssetVersion uploads the content of a new version of the model asset, the contents of the oldest
+// versions exceeding the max versions are deleted from the blob store.
+func (s *Service) UploadModelAssetVersion(c *gin.Context) {
+	rid := util.GetHTTPCCRequestID(c.Request.Header)
+	webCommon.SetProxyHeader(c)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(webCommon.GetLanguageByHTTPRequest(c))
+	ctx := c.Request.Context()
+
+	asset, err := s.getModelAsset(c, defErr, rid)
+	if err != nil {
+		respModelAssetError(c, err)
+		return
+	}
+
+	content, err := s.putModelAssetContent(c, asset.Type, defErr, rid)
+	if err != nil {
+		respModelAssetError(c, err)
+		return
+	}
+
+	opt := &metadata.AddModelAssetVersionOption{Content: *content}
+	result, err := s.CoreAPI.ApiServer().AddModelAssetVersion(ctx, c.Request.Header, asset.ID, opt)
+	if err != nil {
+		blog.Errorf("add model asset %d version failed, err: %v, rid: %s", asset.ID, err, rid)
+		s.deleteModelAssetContents(ctx, []string{content.Key}, rid)
+		respModelAssetError(c, err)
+		return
+	}
+
+	s.deleteModelAssetContents(ctx, result.RemovedKeys, rid)
+	c.String(http.StatusOK, getReturnStr(0, "", result.Asset))
+}
+
+// DownloadModelAsset sends the content of the model asset to the user, the latest version is sent if the version is
+// not specified in the query, so that the models referring to the asset by "asset:<id>" show the latest icon.
+func (s *Service) DownloadModelAsset(c *gin.Context) {
+	rid := util.GetHTTPCCRequestID(c.Request.Header)
+	webCommon.SetProxyHeader(c)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(webCommon.GetLanguageByHTTPRequest(c))
+
+	asset, err := s.getModelAsset(c, defErr, rid)
+	if err != nil {
+		respModelAssetError(c, err)
+		return
+	}
+
+	var version int64
+	if versionStr := c.Query("version"); len(versionStr) > 0 {
+		version, err = strconv.ParseInt(versionStr, 10, 64)
+		if err != nil {
+			respModelAssetError(c, defErr.CCErrorf(common.CCErrCommParamsIsInvalid, "version"))
+			return
+		}
+	}
+
+	assetVersion, exists := asset.GetVersion(version)
+	if !exists {
+		respModelAssetError(c, defErr.CCError(common.CCErrCommNotFound))
+		return
+	}
+
+	reader, err := s.BlobStore.Get(c.Request.Context(), assetVersion.Key)
+	if err != nil {
+		blog.Errorf("get model asset %d content %s failed, err: %v, rid: %s", asset.ID, assetVersion.Key, err, rid)
+		if err == blobstore.ErrNotFound {
+			respModelAssetError(c, defErr.CCError(common.CCErrCommNotFound))
+			return
+		}
+		respModelAssetError(c, err)
+		return
+	}
+	defer reader.Close()
+
+	// the svg icons may contain scripts, forbid them to be run in the context of the site
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("ETag", strconv.Quote(assetVersion.SHA256))
+	c.DataFromReader(http.StatusOK, assetVersion.Size, assetVersion.ContentType, reader, nil)
+}
+
+// DeleteModelAsset deletes the model asset which is not referred by any model or classification, and the contents of
+// all its versions in the blob store.
+func (s *Service) DeleteModelAsset(c *gin.Context) {
+	rid := util.GetHTTPCCRequestID(c.Request.Header)
+	webCommon.SetProxyHeader(c)
+	defErr := s.CCErr.CreateDefaultCCErrorIf(webCommon.GetLanguageByHTTPRequest(c))
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseInt(c.Param(common.BKFieldID), 10, 64)
+	if err != nil {
+		respModelAssetError(c, defErr.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKFieldID))
+		return
+	}
+
+	asset, err := s.CoreAPI.ApiServer().DeleteModelAsset(ctx, c.Request.Header, id)
+	if err != nil {
+		blog.Errorf("delete model asset %d failed, err: %v, rid: %s", id, err, rid)
+		respModelAssetError(c, err)
+		return
+	}
+
+	s.deleteModelAssetContents(ctx, asset.BlobKeys(), rid)
+	c.String(http.StatusOK, getReturnStr(0, "", nil))
+}
+
+// putModelAssetContent puts the uploaded file into the blob store as the content of the model asset of the type.
+func (s *Service) putModelAssetContent(c *gin.Context, assetType string, defErr errors.DefaultCCErrorIf,
+	rid string) (*metadata.ModelAssetContent, error) {
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return nil, defErr.CCError(common.CCErrWebFileNoFound)
+	}
+
+	if file.Size > metadata.ModelAssetMaxSize {
+		return nil, defErr.CCErrorf(common.CCErrCommXXExceedLimit, "file", metadata.ModelAssetMaxSize)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		blog.Errorf("open uploaded model asset file failed, err: %v, rid: %s", err, rid)
+		return nil, defErr.Errorf(common.CCErrWebFileSaveFail, err.Error())
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, metadata.ModelAssetMaxSize+1))
+	if err != nil {
+		blog.Errorf("read uploaded model asset file failed, err: %v, rid: %s", err, rid)
+		return nil, defErr.Errorf(common.CCErrWebFileSaveFail, err.Error())
+	}
+
+	contentType := file.Header.Get("Content-Type")
+	if len(contentType) == 0 {
+		contentType = http.DetectContentType(data)
+	}
+
+	sum := sha256.Sum256(data)
+	content := &metadata.ModelAssetContent{
+		Key:         metadata.ModelAssetBlobKey(assetType, xid.New().String()),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}
+
+	// validate the content before it is put so that the invalid content is not left in the blob store
+	if rawErr := content.Validate(assetType); rawErr.ErrCode != 0 {
+		return nil, rawErr.ToCCError(defErr)
+	}
+
+	if err := s.BlobStore.Put(c.Request.Context(), content.Key, bytes.NewReader(data), content.Size); err != nil {
+		blog.Errorf("put model asset content %s failed, err: %v, rid: %s", content.Key, err, rid)
+		return nil, defErr.Errorf(common.CCErrWebFileSaveFail, err.Error())
+	}
+
+	return content, nil
+}
+
+// getModelAsset gets the model asset whose id is in the url.
+func (s *Service) getModelAsset(c *gin.Context, defErr errors.DefaultCCErrorIf, rid string) (*metadata.ModelAsset,
+	error) {
+
+	id, err := strconv.ParseInt(c.Param(common.BKFieldID), 10, 64)
+	if err != nil {
+		return nil, defErr.CCErrorf(common.CCErrCommParamsIsInvalid, common.BKFieldID)
+	}
+
+	opt := &metadata.SearchModelAssetOption{IDs: []int64{id}, Page: metadata.BasePage{Limit: 1}}
+	result, err := s.CoreAPI.ApiServer().SearchModelAsset(c.Request.Context(), c.Request.Header, opt)
+	if err != nil {
+		blog.Errorf("get model asset %d failed, err: %v, rid: %s", id, err, rid)
+		return nil, err
+	}
+
+	if len(result.Info) == 0 {
+		return nil, defErr.CCError(common.CCErrCommNotFound)
+	}
+	return &result.Info[0], nil
+}
+
+// deleteModelAssetContents deletes the contents of the model asset from the blob store, the failure is only logged
+// since the asset has been changed, the left contents are orphans which do not affect the assets.
+func (s *Service) deleteModelAssetContents(ctx context.Context, keys []string, rid string) {
+	for _, key := range keys {
+		if err := s.BlobStore.Delete(ctx, key); err != nil && err != blobstore.ErrNotFound {
+			blog.Errorf("delete model asset content %s failed, err: %v, rid: %s", key, err, rid)
+		}
+	}
+}
+
+func respModelAssetError(c *gin.Context, err error) {
+	code := common.CCErrorUnknownOrUnrecognizedError
+	if ccErr, ok := err.(errors.CCErrorCoder); ok {
+		code = ccErr.GetCode()
+	}
+	c.String(http.StatusOK, getReturnStr(code, err.Error(), nil))
+}
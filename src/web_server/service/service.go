@@ -21,6 +21,7 @@ import (
 
 	"configcenter/src/common"
 	"configcenter/src/common/backbone"
+	"configcenter/src/common/blobstore"
 	"configcenter/src/common/blog"
 	"configcenter/src/common/metadata"
 	"configcenter/src/common/metric"
@@ -44,6 +45,9 @@ type Service struct {
 	*logics.Logics
 	Config  *options.Config
 	Session redis.RedisStore
+
+	// BlobStore stores the generated files that are shared by the web server instances, e.g. the export job files
+	BlobStore blobstore.BlobStore
 }
 
 // WebService TODO
@@ -88,6 +92,9 @@ func (s *Service) WebService() *gin.Engine {
 	ws.POST("/importtemplate/:bk_obj_id", s.BuildDownLoadExcelTemplate)
 	ws.POST("/insts/object/:bk_obj_id/import", s.ImportInst)
 	ws.POST("/insts/object/:bk_obj_id/export", s.ExportInst)
+	ws.POST("/insts/object/:bk_obj_id/export/task", s.CreateExportInstTask)
+	ws.GET("/insts/object/:bk_obj_id/export/task/:task_id/file", s.DownloadExportInstTaskFile)
+	ws.GET("/insts/object/:bk_obj_id/import/association/error_report/:report_id", s.DownloadAssociationErrorReport)
 	ws.POST("/logout", s.LogOutUser)
 	ws.GET("/login", s.Login)
 	ws.POST("/login", s.LoginUser)
@@ -96,6 +103,10 @@ func (s *Service) WebService() *gin.Engine {
 	ws.POST("/object/exportmany", s.BatchExportObject)
 	ws.POST("/object/importmany/analysis", s.BatchImportObjectAnalysis)
 	ws.POST("/object/importmany", s.BatchImportObject)
+	ws.POST("/model/asset", s.UploadModelAsset)
+	ws.POST("/model/asset/:id/version", s.UploadModelAssetVersion)
+	ws.GET("/model/asset/:id/content", s.DownloadModelAsset)
+	ws.DELETE("/model/asset/:id", s.DeleteModelAsset)
 	ws.GET("/user/list", s.GetUserList)
 	// suggest move to  Organization
 	ws.GET("/user/department", s.GetDepartment)